@@ -49,6 +49,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&UserMembershipIndexList{},
 		&UserPreferences{},
 		&UserPreferencesList{},
+		&PersonalAccessToken{},
+		&PersonalAccessTokenList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
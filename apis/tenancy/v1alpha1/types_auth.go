@@ -45,4 +45,10 @@ type AuthCode struct {
 	RedirectURL  string `json:"redirectURL"` // CLI localhost callback URL
 	SessionID    string `json:"sid"`
 	CodeVerifier string `json:"cv"` // PKCE code verifier (RFC 7636)
+	// StepUp marks a re-authentication requested with max_age, so the
+	// callback can check the IdP actually honoured it.
+	StepUp bool `json:"su,omitempty"`
+	// IDP names the identity provider the login started at, so the
+	// callback exchanges the code with the same one. Empty is the primary.
+	IDP string `json:"idp,omitempty"`
 }
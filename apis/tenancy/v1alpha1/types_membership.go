@@ -40,6 +40,10 @@ const (
 	// workspace and (with scope=workspace) edit objects in it. Members
 	// cannot manage Memberships.
 	MembershipRoleMember = "member"
+
+	// MembershipSourceDirectory marks a UserMembershipIndex row granted by
+	// the directory sync from an Organization group binding.
+	MembershipSourceDirectory = "directory"
 )
 
 // +genclient
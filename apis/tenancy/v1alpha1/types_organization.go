@@ -33,6 +33,16 @@ const (
 	// to Org admins.
 	CatalogEntryCreationAdmin = "admin"
 
+	// WorkspaceRoleViewer lets a group binding's members read the
+	// Workspace's objects, Secrets excepted.
+	WorkspaceRoleViewer = "viewer"
+	// WorkspaceRoleEditor lets a group binding's members create, change
+	// and delete the Workspace's objects, but not its RBAC or APIBindings.
+	WorkspaceRoleEditor = "editor"
+	// WorkspaceRoleAdmin makes a group binding's members cluster-admin of
+	// the Workspace.
+	WorkspaceRoleAdmin = "admin"
+
 	// OrganizationConditionReady is set True once both the Organization CR
 	// and the corresponding kcp workspace are in place. False with a reason
 	// during initial provisioning or while a soft-delete cascade is in
@@ -173,6 +183,62 @@ type OrganizationSpec struct {
 	// +optional
 	// +kubebuilder:validation:Minimum=0
 	WorkspaceQuota int32 `json:"workspaceQuota,omitempty"`
+
+	// GroupBindings grant directory groups a role in this Organization and
+	// its child Workspaces. A User's groups come from the groups claim of
+	// their last OIDC login; the hub's directory sync (--directory-sync)
+	// turns matching bindings into Memberships and revokes the ones it
+	// granted when the User leaves the group. Ignored on personal Orgs.
+	// See docs/organizations.md §Directory sync.
+	//
+	// +optional
+	// +listType=atomic
+	GroupBindings []OrganizationGroupBinding `json:"groupBindings,omitempty"`
+
+	// InheritedProviders names providers the directory sync enables in
+	// every child Workspace of this Organization, including Workspaces
+	// created later, accepting their declared permission claims on the
+	// Workspace's behalf. Removing a name stops new Workspaces from
+	// inheriting it; existing Workspaces keep their binding until it is
+	// disabled there.
+	//
+	// +optional
+	// +listType=set
+	InheritedProviders []string `json:"inheritedProviders,omitempty"`
+}
+
+// OrganizationGroupBinding maps one directory group to a role.
+type OrganizationGroupBinding struct {
+	// Group is the group name as it appears in the OIDC groups claim,
+	// without the "kedge:" prefix kcp adds for RBAC.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Group string `json:"group"`
+
+	// Role granted to the group's members. See MembershipRole* constants.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=admin;member
+	Role string `json:"role"`
+
+	// WorkspaceRole is the access the group's members get inside the child
+	// Workspaces the binding covers, enforced by kcp RBAC: viewer reads,
+	// editor also writes, admin is cluster-admin. Role still decides what
+	// they may do through the hub's own API. See WorkspaceRole* constants.
+	//
+	// +optional
+	// +kubebuilder:validation:Enum=viewer;editor;admin
+	// +kubebuilder:default=admin
+	WorkspaceRole string `json:"workspaceRole,omitempty"`
+
+	// Workspaces restricts the grant to these child Workspace UUIDs (the
+	// Org's teams). Empty grants an org-scope Membership and access to
+	// every child Workspace, including ones created later.
+	//
+	// +optional
+	// +listType=set
+	Workspaces []string `json:"workspaces,omitempty"`
 }
 
 // OrganizationStatus defines the observed state of an Organization.
@@ -0,0 +1,132 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PersonalAccessTokenScope is one permission a PersonalAccessToken grants on
+// top of its owner's own RBAC.
+//
+// +kubebuilder:validation:Enum=read;write;admin
+type PersonalAccessTokenScope string
+
+const (
+	// PersonalAccessTokenScopeRead allows get, list and watch.
+	PersonalAccessTokenScopeRead PersonalAccessTokenScope = "read"
+	// PersonalAccessTokenScopeWrite allows reads and creating, updating and
+	// deleting resources, except deleting edges.
+	PersonalAccessTokenScopeWrite PersonalAccessTokenScope = "write"
+	// PersonalAccessTokenScopeAdmin allows everything the owner may do,
+	// including deleting edges.
+	PersonalAccessTokenScopeAdmin PersonalAccessTokenScope = "admin"
+)
+
+// PersonalAccessTokenUserLabel carries the owning User's metadata.name, so a
+// user's tokens can be listed by label.
+const PersonalAccessTokenUserLabel = "tenants.kedge.faros.sh/user"
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=pat
+// +kubebuilder:printcolumn:name="User",type="string",JSONPath=".spec.user"
+// +kubebuilder:printcolumn:name="Scopes",type="string",JSONPath=".spec.scopes"
+// +kubebuilder:printcolumn:name="Expires",type="date",JSONPath=".spec.expirationTimestamp"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PersonalAccessToken is a long-lived bearer token a User issues for
+// non-interactive clients such as CI pipelines. The hub proxy verifies it
+// alongside OIDC and static tokens and forwards the request to kcp as the
+// owning User, restricted to the token's scopes and, optionally, to one
+// workspace. metadata.name is the token's public ID and is embedded in the
+// token itself; only a SHA-256 hash of the secret is stored. Tokens live in
+// root:kedge:system:tenants with the User CRs and are issued and revoked
+// through the hub REST surface (/api/users/me/tokens).
+type PersonalAccessToken struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              PersonalAccessTokenSpec   `json:"spec,omitempty"`
+	Status            PersonalAccessTokenStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PersonalAccessTokenList is a list of PersonalAccessToken resources.
+type PersonalAccessTokenList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PersonalAccessToken `json:"items"`
+}
+
+// PersonalAccessTokenSpec defines a PersonalAccessToken.
+type PersonalAccessTokenSpec struct {
+	// User is the metadata.name of the owning User. Requests made with the
+	// token run with this User's identity.
+	//
+	// +kubebuilder:validation:Required
+	User string `json:"user"`
+
+	// Description is a free-form note, e.g. the pipeline using the token.
+	//
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Scopes are what the token may be used for.
+	//
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	Scopes []PersonalAccessTokenScope `json:"scopes"`
+
+	// Cluster, when set, restricts the token to one workspace: the kcp
+	// logical cluster ID and the edges mounted under it.
+	//
+	// +optional
+	Cluster string `json:"cluster,omitempty"`
+
+	// TokenHash is the hex SHA-256 of the full token.
+	//
+	// +kubebuilder:validation:Required
+	TokenHash string `json:"tokenHash"`
+
+	// ExpirationTimestamp is when the token stops being accepted. Unset
+	// means it does not expire.
+	//
+	// +optional
+	ExpirationTimestamp *metav1.Time `json:"expirationTimestamp,omitempty"`
+}
+
+// PersonalAccessTokenStatus is the observed state of a PersonalAccessToken.
+type PersonalAccessTokenStatus struct {
+	// LastUsedTime is when the token last authenticated a request, updated
+	// at most once a minute.
+	//
+	// +optional
+	LastUsedTime *metav1.Time `json:"lastUsedTime,omitempty"`
+}
+
+// HasScope reports whether the token grants scope.
+func (s *PersonalAccessTokenSpec) HasScope(scope PersonalAccessTokenScope) bool {
+	for _, have := range s.Scopes {
+		if have == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -113,4 +113,11 @@ type UserStatus struct {
 	//
 	// +optional
 	DeletionRequestedAt *metav1.Time `json:"deletionRequestedAt,omitempty"`
+
+	// Groups are the directory groups from the groups claim of the User's
+	// last OIDC login. Recorded by the hub's auth handler when directory
+	// sync is enabled and matched against Organization.spec.groupBindings.
+	//
+	// +optional
+	Groups []string `json:"groups,omitempty"`
 }
@@ -129,4 +129,14 @@ type MembershipIndexEntry struct {
 	//
 	// +optional
 	SoftDeletedAt *metav1.Time `json:"softDeletedAt,omitempty"`
+
+	// Source records how the Membership was granted: empty for an explicit
+	// grant (bootstrap or the REST API), "directory" for one the directory
+	// sync derived from Organization.spec.groupBindings. The directory sync
+	// only changes or revokes rows it granted; an explicit grant of the
+	// same (Org, Workspace) takes the row over.
+	//
+	// +optional
+	// +kubebuilder:validation:Enum=directory
+	Source string `json:"source,omitempty"`
 }
@@ -245,7 +245,7 @@ func (in *Organization) DeepCopyInto(out *Organization) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -267,6 +267,26 @@ func (in *Organization) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrganizationGroupBinding) DeepCopyInto(out *OrganizationGroupBinding) {
+	*out = *in
+	if in.Workspaces != nil {
+		in, out := &in.Workspaces, &out.Workspaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationGroupBinding.
+func (in *OrganizationGroupBinding) DeepCopy() *OrganizationGroupBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(OrganizationGroupBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OrganizationList) DeepCopyInto(out *OrganizationList) {
 	*out = *in
@@ -302,6 +322,18 @@ func (in *OrganizationList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OrganizationSpec) DeepCopyInto(out *OrganizationSpec) {
 	*out = *in
+	if in.GroupBindings != nil {
+		in, out := &in.GroupBindings, &out.GroupBindings
+		*out = make([]OrganizationGroupBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InheritedProviders != nil {
+		in, out := &in.InheritedProviders, &out.InheritedProviders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationSpec.
@@ -340,6 +372,108 @@ func (in *OrganizationStatus) DeepCopy() *OrganizationStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersonalAccessToken) DeepCopyInto(out *PersonalAccessToken) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersonalAccessToken.
+func (in *PersonalAccessToken) DeepCopy() *PersonalAccessToken {
+	if in == nil {
+		return nil
+	}
+	out := new(PersonalAccessToken)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PersonalAccessToken) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersonalAccessTokenList) DeepCopyInto(out *PersonalAccessTokenList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PersonalAccessToken, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersonalAccessTokenList.
+func (in *PersonalAccessTokenList) DeepCopy() *PersonalAccessTokenList {
+	if in == nil {
+		return nil
+	}
+	out := new(PersonalAccessTokenList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PersonalAccessTokenList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersonalAccessTokenSpec) DeepCopyInto(out *PersonalAccessTokenSpec) {
+	*out = *in
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]PersonalAccessTokenScope, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExpirationTimestamp != nil {
+		in, out := &in.ExpirationTimestamp, &out.ExpirationTimestamp
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersonalAccessTokenSpec.
+func (in *PersonalAccessTokenSpec) DeepCopy() *PersonalAccessTokenSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PersonalAccessTokenSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersonalAccessTokenStatus) DeepCopyInto(out *PersonalAccessTokenStatus) {
+	*out = *in
+	if in.LastUsedTime != nil {
+		in, out := &in.LastUsedTime, &out.LastUsedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersonalAccessTokenStatus.
+func (in *PersonalAccessTokenStatus) DeepCopy() *PersonalAccessTokenStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PersonalAccessTokenStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *User) DeepCopyInto(out *User) {
 	*out = *in
@@ -631,6 +765,11 @@ func (in *UserStatus) DeepCopyInto(out *UserStatus) {
 		in, out := &in.DeletionRequestedAt, &out.DeletionRequestedAt
 		*out = (*in).DeepCopy()
 	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserStatus.
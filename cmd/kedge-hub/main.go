@@ -30,6 +30,7 @@ import (
 
 	"github.com/faroshq/faros-kedge/pkg/hub"
 	"github.com/faroshq/faros-kedge/pkg/hub/providers"
+	"github.com/faroshq/faros-kedge/pkg/server/auth"
 	// First-party provider registrations. Each package's init() calls
 	// providers.RegisterBuiltin, so the catalog controller can find them
 	// without a central data list. Adding a new builtin = new blank import
@@ -59,11 +60,33 @@ func main() {
 	cmd.Flags().StringVar(&opts.ListenAddr, "listen-addr", opts.ListenAddr, "Address to listen on")
 	cmd.Flags().StringVar(&opts.Kubeconfig, "kubeconfig", "", "Kubeconfig for hub cluster")
 	cmd.Flags().StringVar(&opts.ExternalKCPKubeconfig, "external-kcp-kubeconfig", "", "Kubeconfig for external kcp (empty for embedded)")
-	cmd.Flags().StringVar(&opts.IDPIssuerURL, "idp-issuer-url", "", "OIDC identity provider issuer URL")
-	cmd.Flags().StringVar(&opts.IDPClientID, "idp-client-id", "kedge", "OIDC identity provider client ID")
-	cmd.Flags().StringVar(&opts.IDPCAFile, "idp-ca-file", "", "PEM-encoded CA bundle for verifying the IdP's TLS cert (required for self-signed/private CAs)")
+	cmd.Flags().StringArrayVar(&opts.IDPIssuerURLs, "idp-issuer-url", nil, "OIDC identity provider issuer URL. Repeat to trust several identity providers, tried in order; the first is the primary.")
+	cmd.Flags().StringArrayVar(&opts.IDPClientIDs, "idp-client-id", opts.IDPClientIDs, "OIDC identity provider client ID: once for every --idp-issuer-url, or once per issuer")
+	cmd.Flags().StringArrayVar(&opts.IDPNames, "idp-name", nil, "Name of the --idp-issuer-url at the same position, for kedge login --idp. Required for every issuer after the first, whose users and groups it prefixes (kedge:<name>:); the first defaults to \"default\".")
+	cmd.Flags().StringVar(&opts.IDPConfigFile, "idp-config", "", "YAML file listing further OIDC identity providers (identityProviders: name, issuerURL, clientID, caFile, groupsClaim), tried after --idp-issuer-url")
+	cmd.Flags().StringVar(&opts.IDPCAFile, "idp-ca-file", "", "PEM-encoded CA bundle for verifying the TLS certs of the --idp-issuer-url IdPs (required for self-signed/private CAs)")
+	cmd.Flags().StringVar(&opts.IDPGroupsClaim, "idp-groups-claim", opts.IDPGroupsClaim, "ID token claim holding the user's IdP groups, for kcp RBAC and --directory-sync")
+	cmd.Flags().BoolVar(&opts.DirectorySync, "directory-sync", false, "Grant organization and workspace memberships, and workspace RBAC roles, from the IdP groups claim per each organization's spec.groupBindings")
+	cmd.Flags().DurationVar(&opts.StepUpMaxAge, "step-up-max-age", 0, "Require an OIDC sign-in no older than this (or a second factor, see --step-up-amr) for interactive SSH and edge deletion, e.g. 15m. 0 disables step-up.")
+	cmd.Flags().StringSliceVar(&opts.StepUpAMR, "step-up-amr", auth.DefaultStepUpAMR, "ID token amr values accepted as a second factor for step-up regardless of sign-in age")
+	cmd.Flags().IntVar(&opts.AuthLockoutThreshold, "auth-lockout-threshold", opts.AuthLockoutThreshold, "Failed authentication attempts from one client IP, or with one static-token prefix, within 5m before further attempts are refused with 429. 0 disables the lockout; it is always off with --dev-mode.")
+	cmd.Flags().DurationVar(&opts.AuthLockoutDuration, "auth-lockout-duration", opts.AuthLockoutDuration, "How long --auth-lockout-threshold failures lock a client IP or token prefix out; doubled for each repeat lockout, up to 1h")
+	cmd.Flags().StringSliceVar(&opts.AuthTrustedProxies, "auth-trusted-proxies", nil, "CIDRs of load balancers whose X-Forwarded-For header names the client IP for the authentication lockout (can be specified multiple times). Without them the connection's peer address is used")
+	cmd.Flags().Float64Var(&opts.ProxyRequestLimits.UserQPS, "proxy-user-qps", opts.ProxyRequestLimits.UserQPS, "Requests per second the kcp API proxy forwards for each user (or ServiceAccount token) before answering 429. 0 disables the limit.")
+	cmd.Flags().IntVar(&opts.ProxyRequestLimits.UserBurst, "proxy-user-burst", opts.ProxyRequestLimits.UserBurst, "Requests a user may send at once above --proxy-user-qps")
+	cmd.Flags().Float64Var(&opts.ProxyRequestLimits.WorkspaceQPS, "proxy-workspace-qps", opts.ProxyRequestLimits.WorkspaceQPS, "Requests per second the kcp API proxy forwards to each workspace, its edges included, across its users, before answering 429. 0 disables the limit.")
+	cmd.Flags().IntVar(&opts.ProxyRequestLimits.WorkspaceBurst, "proxy-workspace-burst", opts.ProxyRequestLimits.WorkspaceBurst, "Requests a workspace may receive at once above --proxy-workspace-qps")
 	cmd.Flags().StringVar(&opts.ServingCertFile, "serving-cert-file", "", "TLS certificate file for HTTPS serving")
 	cmd.Flags().StringVar(&opts.ServingKeyFile, "serving-key-file", "", "TLS key file for HTTPS serving")
+	cmd.Flags().StringVar(&opts.ReadOnlyListenAddr, "read-only-listen-addr", "", "Address for a second listener serving only kcp API reads (GET/HEAD, including watches), e.g. \":9444\". Empty disables it.")
+	cmd.Flags().DurationVar(&opts.ReadOnlyCacheTTL, "read-only-cache-ttl", 0, "Cache responses on the read-only listener for this long where resourceVersion semantics allow (resourceVersion=0 or resourceVersionMatch=Exact). 0 disables the cache.")
+	cmd.Flags().StringVar(&opts.MirrorURL, "mirror-url", "", "Base URL of a canary hub or kcp to mirror a share of read-only kcp API requests to (shadow traffic); responses are compared and logged, never returned. Empty disables mirroring.")
+	cmd.Flags().Float64Var(&opts.MirrorPercent, "mirror-percent", 1, "Percentage (0-100] of eligible reads mirrored to --mirror-url.")
+	cmd.Flags().BoolVar(&opts.MirrorInsecureSkipTLSVerify, "mirror-insecure-skip-tls-verify", false, "Skip verification of the --mirror-url serving certificate.")
+	cmd.Flags().StringSliceVar(&opts.AuditSinks, "audit-sink", nil, "Record an audit event for every kcp API and provider backend request, edge cluster access included, to this sink: stdout, file:<path> or webhook:<url>. Repeat for several sinks. Empty disables audit logging.")
+	cmd.Flags().IntVar(&opts.ControllerShards, "controller-shards", opts.ControllerShards, "Divide the tenant workspaces reconciled by the hub controllers among this many hub replicas, each claiming one shard through a Lease in root:kedge:system:controllers. Replicas beyond it wait as standbys. Needs an external kcp when > 1.")
+	cmd.Flags().BoolVar(&opts.ControllerLeaderElection, "controller-leader-election", opts.ControllerLeaderElection, "Elect one hub replica to run the controllers through a Lease in root:kedge:system:controllers, the others waiting as standbys, so kedge-hub can run several replicas behind a load balancer. Implied by --controller-shards > 1. Needs an external kcp.")
+	cmd.Flags().DurationVar(&opts.ShutdownGracePeriod, "shutdown-grace-period", opts.ShutdownGracePeriod, "On SIGTERM, how long the hub waits for in-flight requests and sessions (kubectl exec, ssh) to finish after refusing new agent tunnels and closing the ones it holds, so agents reconnect through another replica")
 	cmd.Flags().StringVar(&opts.HubExternalURL, "hub-external-url", opts.HubExternalURL, "External URL of this hub (for kubeconfig generation)")
 	cmd.Flags().StringVar(&opts.HubInternalURL, "hub-internal-url", "", "Internal URL for kcp mount resolution (default: derived from listen-addr; avoids CDN loops)")
 	cmd.Flags().StringVar(&opts.ProviderInternalURL, "provider-internal-url", "", "Server URL baked into the minted provider kubeconfig (default: --hub-external-url). Override for in-cluster provider pods, e.g. https://host.docker.internal:9443.")
@@ -73,6 +96,11 @@ func main() {
 	cmd.Flags().StringSliceVar(&opts.Providers, "providers", providers.BuiltinNames(),
 		"First-party providers to enable as CatalogEntries (comma-separated or repeat). "+
 			"Defaults to all known builtins. Dependencies are enforced — e.g. mcp requires server-edges.")
+	cmd.Flags().StringSliceVar(&opts.BootstrapManifests, "bootstrap-manifests", nil,
+		"Directories, files or http(s) URLs of YAML manifests to apply at startup into the workspace each object names "+
+			"with the kedge.faros.sh/workspace annotation (comma-separated or repeat). Re-applied every --bootstrap-manifests-interval.")
+	cmd.Flags().DurationVar(&opts.BootstrapManifestsInterval, "bootstrap-manifests-interval", opts.BootstrapManifestsInterval,
+		"How often --bootstrap-manifests are re-read and re-applied, reverting drift")
 
 	cmd.Flags().StringVar(&opts.GraphQLAddr, "graphql-addr", opts.GraphQLAddr, "Address of an external GraphQL gateway to proxy /graphql/* requests to (empty to disable)")
 	cmd.Flags().BoolVar(&opts.EmbeddedGraphQL, "embedded-graphql", opts.EmbeddedGraphQL, "Run GraphQL listener+gateway in-process (requires embedded or external kcp; overrides --graphql-addr)")
@@ -83,6 +111,9 @@ func main() {
 
 	cmd.Flags().StringVar(&opts.PortalDevURL, "portal-dev-url", "", "Reverse-proxy /ui/* to this URL (e.g. http://localhost:3000 for Vite dev server); takes precedence over embedded portal dist")
 	cmd.Flags().StringSliceVar(&opts.PortalFrameSources, "portal-frame-source", nil, "Additional CSP frame-src source expressions allowed by the portal, e.g. https://*.preview.example.com")
+	cmd.Flags().StringVar(&opts.DebugAddr, "debug-addr", "", "Bind address for the debug HTTP server exposing /metrics and /debug/pprof/* (e.g. \"127.0.0.1:6061\"). Empty disables the server.")
+	cmd.Flags().DurationVar(&opts.SearchIndexTTL, "search-index-ttl", opts.SearchIndexTTL, "How long the hub keeps a workspace's search index (/services/search) after its last search")
+	cmd.Flags().BoolVar(&opts.APIExplorer, "api-explorer", opts.APIExplorer, "Serve the interactive API explorer at /explorer (the OpenAPI document requires sign-in)")
 
 	// Embedded kcp flags
 	cmd.Flags().BoolVar(&opts.EmbeddedKCP, "embedded-kcp", opts.EmbeddedKCP, "Enable embedded kcp server (runs kcp in-process)")
@@ -18,16 +18,16 @@ limitations under the License.
 package main
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/faroshq/faros-kedge/pkg/cli/cmd"
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
 )
 
 func main() {
 	rootCmd := cmd.NewRootCommand()
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		ui.PrintError(os.Stderr, err)
 		os.Exit(1)
 	}
 }
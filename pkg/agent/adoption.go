@@ -0,0 +1,100 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+	pkgversion "github.com/faroshq/faros-kedge/pkg/version"
+)
+
+// AdoptionPollInterval is how often a waiting agent re-reads its
+// EdgeAdoptionRequest.
+const AdoptionPollInterval = 10 * time.Second
+
+// requestAdoption files an EdgeAdoptionRequest for this agent's edge and
+// blocks until an admin decides on it. Approval (`kedge edge approve`)
+// creates the edge, so returning nil means registration is complete; a denial
+// is returned as an error. An existing request is reused, so restarting a
+// waiting agent does not file a second one.
+func (a *Agent) requestAdoption(ctx context.Context, client *kedgeclient.Client, edgeType string) error {
+	logger := klog.FromContext(ctx)
+	res := client.Dynamic().Resource(kedgeclient.EdgeAdoptionRequestGVR)
+
+	labels := map[string]interface{}{}
+	for k, v := range a.opts.Labels {
+		labels[k] = v
+	}
+	hostname, _ := os.Hostname()
+	req := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": kedgeclient.EdgeAdoptionRequestGVR.GroupVersion().String(),
+		"kind":       "EdgeAdoptionRequest",
+		"metadata": map[string]interface{}{
+			"name": a.opts.EdgeName,
+		},
+		"spec": map[string]interface{}{
+			"type":         edgeType,
+			"labels":       labels,
+			"hostname":     hostname,
+			"agentVersion": pkgversion.Get(),
+		},
+	}}
+	if _, err := res.Create(ctx, req, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("edge %q does not exist and this agent may not create it; filing an adoption request also failed: %w", a.opts.EdgeName, err)
+	}
+	logger.Info("Edge does not exist and this agent may not create it; waiting for an admin to approve the adoption request",
+		"edgeName", a.opts.EdgeName, "approveWith", "kedge edge approve "+a.opts.EdgeName)
+
+	var denied error
+	err := wait.PollUntilContextCancel(ctx, AdoptionPollInterval, true, func(ctx context.Context) (bool, error) {
+		cur, err := res.Get(ctx, a.opts.EdgeName, metav1.GetOptions{})
+		if err != nil {
+			// Keep waiting through transient errors; the decision is what
+			// ends the wait.
+			logger.V(4).Info("Reading adoption request failed", "err", err)
+			return false, nil
+		}
+		phase, _, _ := unstructured.NestedString(cur.Object, "status", "phase")
+		switch phase {
+		case "Approved":
+			return true, nil
+		case "Denied":
+			message, _, _ := unstructured.NestedString(cur.Object, "status", "message")
+			denied = fmt.Errorf("adoption of edge %q was denied: %s (delete the EdgeAdoptionRequest to ask again)", a.opts.EdgeName, message)
+			return true, nil
+		}
+		return false, nil
+	})
+	if err != nil {
+		return fmt.Errorf("waiting for adoption of edge %q: %w", a.opts.EdgeName, err)
+	}
+	if denied != nil {
+		return denied
+	}
+	logger.Info("Adoption request approved; edge created", "edgeName", a.opts.EdgeName)
+	return nil
+}
@@ -28,11 +28,15 @@ import (
 	"encoding/json"
 	"encoding/pem"
 	"fmt"
+	"maps"
+	"net"
 	"net/http"
 	"net/http/pprof"
+	"net/url"
 	"os"
 	"os/user"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -54,7 +58,14 @@ import (
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
+	"github.com/faroshq/provider-sdk/revdial"
+
+	"github.com/faroshq/faros-kedge/pkg/agent/clock"
+	"github.com/faroshq/faros-kedge/pkg/agent/health"
+	"github.com/faroshq/faros-kedge/pkg/agent/metrics"
 	agentReconciler "github.com/faroshq/faros-kedge/pkg/agent/reconciler"
+	"github.com/faroshq/faros-kedge/pkg/agent/registrycache"
+	"github.com/faroshq/faros-kedge/pkg/agent/sshserver"
 	agentStatus "github.com/faroshq/faros-kedge/pkg/agent/status"
 	"github.com/faroshq/faros-kedge/pkg/agent/tunnel"
 	"github.com/faroshq/faros-kedge/pkg/apiurl"
@@ -131,7 +142,7 @@ func LoadAgentKubeconfig(edgeName string) (string, error) {
 // errors) return nil because they don't prove the token is invalid — the hub
 // may be temporarily unreachable or the RBAC may not permit the probe call.
 // When insecureSkipTLS is true, TLS certificate verification is disabled.
-func ValidateAgentKubeconfig(kubeconfigPath string, insecureSkipTLS bool) error {
+func ValidateAgentKubeconfig(kubeconfigPath string, insecureSkipTLS bool, proxyURL string) error {
 	rules := &clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfigPath}
 	cfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{}).ClientConfig()
 	if err != nil {
@@ -140,6 +151,10 @@ func ValidateAgentKubeconfig(kubeconfigPath string, insecureSkipTLS bool) error
 	if insecureSkipTLS {
 		cfg.Insecure = true
 	}
+	// An invalid proxy URL is New's to report; the check goes direct.
+	if proxy, err := tunnel.ProxyFunc(proxyURL); err == nil {
+		cfg.Proxy = proxy
+	}
 	// Use a short timeout so we don't block startup for too long.
 	cfg.Timeout = 10 * time.Second
 	dynClient, err := dynamic.NewForConfig(cfg)
@@ -260,6 +275,67 @@ func resolveType(raw string) (AgentType, error) {
 	}
 }
 
+// EmbeddedSSHMode controls when a server-type agent serves SSH itself instead
+// of proxying to the host sshd.
+type EmbeddedSSHMode string
+
+const (
+	// EmbeddedSSHOff always proxies to the host sshd on SSHProxyPort.
+	EmbeddedSSHOff EmbeddedSSHMode = "off"
+	// EmbeddedSSHFallback starts the embedded server only when nothing answers
+	// on SSHProxyPort at startup (containers, minimal OS images).
+	EmbeddedSSHFallback EmbeddedSSHMode = "fallback"
+	// EmbeddedSSHAlways ignores the host sshd and always uses the embedded server.
+	EmbeddedSSHAlways EmbeddedSSHMode = "always"
+)
+
+// AdoptionPolicy controls what an agent does when its edge does not exist and
+// it may not create it.
+type AdoptionPolicy string
+
+const (
+	// AdoptionRequest files an EdgeAdoptionRequest and waits for an admin to
+	// approve it with `kedge edge approve`.
+	AdoptionRequest AdoptionPolicy = "request"
+	// AdoptionNever fails registration, as agents did before adoption.
+	AdoptionNever AdoptionPolicy = "never"
+)
+
+// RegistrationMode controls whether the agent creates and updates its edge.
+type RegistrationMode string
+
+const (
+	// RegistrationAgent has the agent create its edge, or request its
+	// adoption, and keep its labels and location up to date.
+	RegistrationAgent RegistrationMode = "agent"
+	// RegistrationExternal has the agent use an edge an admin provisioned
+	// without ever writing it: it patches only the edge's status, and a
+	// server-type agent hands its SSH credentials to the hub over the
+	// tunnel. Its hub credentials need no write access to edges.
+	RegistrationExternal RegistrationMode = "external"
+)
+
+// LabelPolicy controls how the agent reconciles its labels with its edge's,
+// which the hub's users may have changed since the agent last applied them.
+// The agent records the labels it applied in the edge's
+// lastAppliedLabelsAnnotation, and tells the hub's changes from its own by it.
+type LabelPolicy string
+
+const (
+	// LabelPolicyMerge three-way merges: the agent applies the labels it
+	// added, changed or dropped since it last applied them, while a label
+	// changed or removed on the hub stays so until the agent's value for it
+	// changes.
+	LabelPolicyMerge LabelPolicy = "merge"
+	// LabelPolicyReplace makes the agent's labels authoritative: all are set
+	// again on every registration, and those it dropped are removed.
+	LabelPolicyReplace LabelPolicy = "replace"
+	// LabelPolicyHubWins only adds labels the edge does not have and the
+	// agent never applied: the hub's changes always stand, and the agent
+	// never changes or removes a label.
+	LabelPolicyHubWins LabelPolicy = "hub-wins"
+)
+
 // Options holds configuration for the agent.
 type Options struct {
 	HubURL        string
@@ -270,13 +346,21 @@ type Options struct {
 	EdgeName      string
 	Kubeconfig    string
 	Context       string
-	Labels        map[string]string
+	// Edges maps edge names to the Kubeconfig context each serves, to run
+	// several edges from one process. Set instead of EdgeName and Context;
+	// PerEdge expands it into one Options per edge.
+	Edges  map[string]string
+	Labels map[string]string
 	// Type controls whether the agent registers as a Kubernetes edge or a
 	// Server edge. Defaults to AgentTypeKubernetes.
 	Type AgentType
 	// InsecureSkipTLSVerify disables TLS certificate verification for the hub
 	// connection. Should only be used in development/testing; never in production.
 	InsecureSkipTLSVerify bool
+	// ProxyURL is the HTTP(S) or SOCKS5 proxy every connection to the hub
+	// goes through, the tunnel included. Empty follows HTTP_PROXY,
+	// HTTPS_PROXY and NO_PROXY. See tunnel.ProxyFunc.
+	ProxyURL string
 	// SSHProxyPort is the local port of the SSH daemon the agent proxies to.
 	// Defaults to 22; override in tests to avoid conflicts with the host sshd.
 	SSHProxyPort int
@@ -286,8 +370,22 @@ type Options struct {
 	// SSHPassword is the SSH password for password-based authentication.
 	// Prefer SSHPrivateKeyPath for better security.
 	SSHPassword string
+	// SSHPasswordFile is a file holding the SSH password, used instead of
+	// SSHPassword. The agent re-reads it when it changes.
+	SSHPasswordFile string
 	// SSHPrivateKeyPath is the path to an SSH private key file for key-based auth.
 	SSHPrivateKeyPath string
+	// SSHCredentialsResync is how often a server-type agent that manages its
+	// edge's SSH credentials re-reads them and re-syncs the hub Secret, on
+	// top of watching the key and password files. Zero disables the
+	// periodic re-sync.
+	SSHCredentialsResync time.Duration
+	// EmbeddedSSH selects whether server-type edges fall back to the agent's
+	// embedded SSH server (see pkg/agent/sshserver). Defaults to EmbeddedSSHOff.
+	EmbeddedSSH EmbeddedSSHMode
+	// EmbeddedSSHExecOnly restricts the embedded SSH server to exec requests,
+	// rejecting interactive shells.
+	EmbeddedSSHExecOnly bool
 	// Cluster is the kcp logical cluster path (e.g., "root:kedge:user-default").
 	// If not set, it's extracted from the SA token (for kubeconfig-based auth)
 	// or defaults to "default" (for static token auth).
@@ -297,27 +395,142 @@ type Options struct {
 	// registration is skipped (the edge was already registered).
 	UsingSavedKubeconfig bool
 	// DebugAddr, if non-empty, is the bind address for the agent's debug
-	// HTTP server. It exposes /healthz and the standard /debug/pprof/*
-	// endpoints. Use "127.0.0.1:6060" for local-only access; bind to a
+	// HTTP server. It exposes /healthz, Prometheus /metrics and the standard
+	// /debug/pprof/* endpoints. Use "127.0.0.1:6060" for local-only access; bind to a
 	// non-loopback address only when port-forwarding is not an option.
 	DebugAddr string
+	// HealthProbeAddr, if non-empty, is the bind address of the /healthz and
+	// /readyz endpoints for orchestrators (see ServeProbes). One server covers
+	// all edges of the process; the agent command starts it.
+	HealthProbeAddr string
+	// LivenessTimeout is how long the tunnel may stay down, or a subsystem
+	// fail fatally, before /healthz fails. Zero keeps /healthz passing.
+	LivenessTimeout time.Duration
+	// StatusMirrorNamespaces limits which edge namespaces the placement status
+	// mirror watches for Deployments, StatefulSets and Jobs. Empty mirrors
+	// placement-managed objects in every namespace.
+	StatusMirrorNamespaces []string
+	// ForwardEvents mirrors the edge cluster's Events about placement-managed
+	// objects, and the Pods under them, onto their Placements in the tenant
+	// workspace (see status.EventForwarder). It covers the namespaces
+	// StatusMirrorNamespaces names. Kubernetes type only.
+	ForwardEvents bool
+	// GitOps hands placements whose Workload names a Git source off to a
+	// GitOps controller on the edge (Flux or Argo CD) instead of applying
+	// them. Empty applies every placement directly.
+	GitOps agentReconciler.GitOpsTool
+	// GitOpsNamespace is where the GitOps objects are created. Empty uses
+	// the tool's default namespace.
+	GitOpsNamespace string
+	// ApplyConcurrency is how many objects of a placement's bundle the agent
+	// applies at once within each ordering phase. Zero uses
+	// reconciler.DefaultApplyConcurrency.
+	ApplyConcurrency int
+	// PlacementBundle, if set, runs the agent offline: it applies the
+	// Placements in this signed bundle file instead of connecting to the
+	// hub (see offline.go). Kubernetes type only.
+	PlacementBundle string
+	// PlacementBundlePublicKey is the Ed25519 public key (PEM) the bundle
+	// must be signed with.
+	PlacementBundlePublicKey string
+	// EndToEndTLS makes a kubernetes-type agent terminate TLS for API traffic
+	// itself, so the hub only relays ciphertext; plaintext /k8s access is
+	// refused. See tunnel.EndToEndTLS.
+	EndToEndTLS bool
+	// EndToEndTLSCertFile and EndToEndTLSKeyFile are the serving certificate
+	// for EndToEndTLS. Both empty uses a self-signed certificate kept next to
+	// the agent's SSH host key.
+	EndToEndTLSCertFile string
+	EndToEndTLSKeyFile  string
+	// CertificateJoin makes the agent request a client certificate from the
+	// hub's agent CA and authenticate its tunnel with it instead of a bearer
+	// token. See tunnel.CertificateJoin.
+	CertificateJoin bool
+	// Adoption selects what happens when the edge does not exist and the
+	// agent may not create it. Defaults to AdoptionRequest.
+	Adoption AdoptionPolicy
+	// Registration selects whether the agent creates and updates its edge.
+	// Defaults to RegistrationAgent.
+	Registration RegistrationMode
+	// LabelPolicy selects how Labels are reconciled with the edge's labels.
+	// Defaults to LabelPolicyMerge.
+	LabelPolicy LabelPolicy
+	// Location fills the edge's spec.location when it has none.
+	Location Location
+	// TunnelKeepalive tunes how quickly a dead hub tunnel is detected. The
+	// zero value keeps revdial's defaults.
+	TunnelKeepalive revdial.Keepalive
+	// TunnelReconnect spaces out the agent's attempts to reopen a lost hub
+	// tunnel. Zero fields use tunnel.DefaultReconnect.
+	TunnelReconnect tunnel.Reconnect
+	// ReadCacheTTL, if non-zero, has the agent serve GET and LIST requests
+	// for pods, nodes and namespaces proxied from the hub from informers,
+	// kept for ReadCacheTTL after their last read. Kubernetes type only.
+	ReadCacheTTL time.Duration
+	// K8sProxyScope restricts the namespaces, verbs and resources of the
+	// downstream cluster the hub may reach through the agent, instead of
+	// everything the agent's credentials allow. Kubernetes type only.
+	K8sProxyScope tunnel.ProxyScope
+	// TunnelBandwidth caps the bytes per second the agent proxies over the
+	// hub tunnel, for edges on metered links. Zero rates are unlimited.
+	TunnelBandwidth tunnel.Bandwidth
+	// ClockSkewThreshold is how far the edge's clock may be from the hub's
+	// before the edge's ClockSynchronized condition turns False and the
+	// agent warns. Zero uses clock.DefaultThreshold.
+	ClockSkewThreshold time.Duration
+	// LogLevel is the klog verbosity. It can be changed at runtime through
+	// Reload.
+	LogLevel int
+	// HeartbeatInterval is how often the agent heartbeats the edge's status.
+	// Zero uses status.HeartbeatInterval.
+	HeartbeatInterval time.Duration
+	// DownstreamProxy reaches the downstream API server through a SOCKS5
+	// proxy or SSH bastion (kubernetes type only).
+	DownstreamProxy DownstreamProxy
+	// ShutdownGracePeriod is how long the agent, on shutdown, waits for
+	// in-flight proxied requests and sessions to finish after marking the
+	// edge Draining.
+	ShutdownGracePeriod time.Duration
+	// HostTelemetryPaths are the filesystems whose usage a server-type agent
+	// reports, with the host's load, memory and temperatures, in the edge's
+	// status. Empty disables host telemetry.
+	HostTelemetryPaths []string
+	// DiskPressureThreshold is the filesystem usage, in percent, at which the
+	// edge's DiskPressure condition turns True. Zero leaves the condition out.
+	DiskPressureThreshold int
+	// DryRun makes New leave the host alone, for Agent.DryRun: no SSH key is
+	// generated and authorized_keys is not touched.
+	DryRun bool
 }
 
+// DefaultShutdownGracePeriod leaves room for the final status update within
+// the default 30s termination grace period of a Kubernetes pod.
+const DefaultShutdownGracePeriod = 20 * time.Second
+
 // NewOptions returns default agent options.
 func NewOptions() *Options {
 	return &Options{
-		Labels:       make(map[string]string),
-		Type:         AgentTypeKubernetes,
-		SSHProxyPort: 22,
+		Labels:               make(map[string]string),
+		Type:                 AgentTypeKubernetes,
+		SSHProxyPort:         22,
+		SSHCredentialsResync: DefaultSSHCredentialsResync,
+		LivenessTimeout:      DefaultLivenessTimeout,
+		EmbeddedSSH:          EmbeddedSSHOff,
+		Adoption:             AdoptionRequest,
+		Registration:         RegistrationAgent,
+		LabelPolicy:          LabelPolicyMerge,
 	}
 }
 
 // Agent is the kedge agent that connects an edge to the hub.
 type Agent struct {
-	opts             *Options
-	agentType        AgentType
-	hubConfig        *rest.Config
-	hubTLSConfig     *tls.Config
+	opts         *Options
+	agentType    AgentType
+	hubConfig    *rest.Config
+	hubTLSConfig *tls.Config
+	// hubProxy selects the proxy for connections to the hub, from
+	// Options.ProxyURL.
+	hubProxy         func(*http.Request) (*url.URL, error)
 	downstreamConfig *rest.Config // nil in server mode
 
 	// tunnelToken holds the bearer token used by the proxy tunnel goroutine on
@@ -331,6 +544,28 @@ type Agent struct {
 	// cleared on the first successful auth — leaving the agent in an endless
 	// "websocket: bad handshake" loop until manually restarted.
 	tunnelToken atomic.Pointer[string]
+
+	// health collects the failures of the tunnel, reconcilers and reporters;
+	// the edge status reporter surfaces the most severe one as the edge's
+	// AgentHealthy condition.
+	health *health.Tracker
+
+	// clock measures the skew versus the hub from hub responses. Hub TLS
+	// verification uses the corrected time, and the edge status reporter
+	// surfaces the skew as the edge's ClockSynchronized condition.
+	clock *clock.Skew
+
+	// traffic limits the bandwidth of the tunnel and counts the bytes it
+	// proxies; the edge status reporter publishes the totals.
+	traffic *tunnel.TrafficMeter
+
+	// reloads carries the latest settings passed to Reload to the goroutine
+	// applying them.
+	reloads chan Reloadable
+
+	// sshServer is the embedded SSH server, nil when the host sshd is used.
+	// Rotated SSH credentials are handed to it.
+	sshServer *sshserver.Server
 }
 
 // setTunnelToken stores t as the token used for tunnel (re)connects.
@@ -347,6 +582,30 @@ func (a *Agent) currentTunnelToken() string {
 	return ""
 }
 
+// tunnelTokenSource returns the tunnel's token source. With CertificateJoin
+// it is a certificate assertion once the hub has issued the agent a
+// certificate: the first request is made here, so an auto-approving hub
+// sees the certificate from the first connect, and the certificate is
+// renewed in the background.
+func (a *Agent) tunnelTokenSource(ctx context.Context, tunnelURL, cluster string) (func() string, error) {
+	if !a.opts.CertificateJoin {
+		return a.currentTunnelToken, nil
+	}
+	dir, err := agentKeyDir(a.opts.EdgeName)
+	if err != nil {
+		return nil, err
+	}
+	join, err := tunnel.NewCertificateJoin(tunnelURL, string(a.agentType), cluster, a.opts.EdgeName, dir, a.hubTLSConfig, a.hubProxy)
+	if err != nil {
+		return nil, fmt.Errorf("loading the agent certificate: %w", err)
+	}
+	if err := join.Ensure(ctx, a.currentTunnelToken); err != nil {
+		klog.FromContext(ctx).Info("No agent certificate yet; connecting with the token until one is issued", "reason", err.Error())
+	}
+	go join.Run(ctx, a.currentTunnelToken)
+	return join.Token(a.currentTunnelToken), nil
+}
+
 // extractTokenFromKubeconfigB64 decodes a base64-encoded kubeconfig (as
 // delivered by the hub in the X-Kedge-Agent-Kubeconfig header) and returns the
 // bearer token of its current context's AuthInfo.
@@ -389,6 +648,78 @@ func New(opts *Options) (*Agent, error) {
 		return nil, err
 	}
 
+	switch opts.EmbeddedSSH {
+	case "":
+		opts.EmbeddedSSH = EmbeddedSSHOff
+	case EmbeddedSSHOff, EmbeddedSSHFallback, EmbeddedSSHAlways:
+	default:
+		return nil, fmt.Errorf("invalid embedded SSH mode %q: must be %q, %q or %q",
+			opts.EmbeddedSSH, EmbeddedSSHOff, EmbeddedSSHFallback, EmbeddedSSHAlways)
+	}
+
+	switch opts.GitOps {
+	case agentReconciler.GitOpsNone, agentReconciler.GitOpsFlux, agentReconciler.GitOpsArgoCD:
+	default:
+		return nil, fmt.Errorf("invalid GitOps tool %q: must be %q or %q",
+			opts.GitOps, agentReconciler.GitOpsFlux, agentReconciler.GitOpsArgoCD)
+	}
+	if opts.ApplyConcurrency < 0 {
+		return nil, fmt.Errorf("invalid apply concurrency %d: must not be negative", opts.ApplyConcurrency)
+	}
+
+	switch opts.Adoption {
+	case "":
+		opts.Adoption = AdoptionRequest
+	case AdoptionRequest, AdoptionNever:
+	default:
+		return nil, fmt.Errorf("invalid adoption policy %q: must be %q or %q",
+			opts.Adoption, AdoptionRequest, AdoptionNever)
+	}
+
+	switch opts.Registration {
+	case "":
+		opts.Registration = RegistrationAgent
+	case RegistrationAgent, RegistrationExternal:
+	default:
+		return nil, fmt.Errorf("invalid registration mode %q: must be %q or %q",
+			opts.Registration, RegistrationAgent, RegistrationExternal)
+	}
+
+	switch opts.LabelPolicy {
+	case "":
+		opts.LabelPolicy = LabelPolicyMerge
+	case LabelPolicyMerge, LabelPolicyReplace, LabelPolicyHubWins:
+	default:
+		return nil, fmt.Errorf("invalid label policy %q: must be %q, %q or %q",
+			opts.LabelPolicy, LabelPolicyMerge, LabelPolicyReplace, LabelPolicyHubWins)
+	}
+
+	if _, err := opts.Location.Spec(); err != nil {
+		return nil, fmt.Errorf("invalid location: %w", err)
+	}
+	if opts.DiskPressureThreshold < 0 || opts.DiskPressureThreshold > 100 {
+		return nil, fmt.Errorf("invalid disk pressure threshold %d: must be a percentage", opts.DiskPressureThreshold)
+	}
+	if err := opts.TunnelReconnect.Validate(); err != nil {
+		return nil, err
+	}
+	if err := opts.K8sProxyScope.Validate(); err != nil {
+		return nil, err
+	}
+	if err := opts.TunnelBandwidth.Validate(); err != nil {
+		return nil, err
+	}
+	if opts.SSHPasswordFile != "" {
+		if opts.SSHPassword != "" {
+			return nil, fmt.Errorf("SSH password and SSH password file are mutually exclusive")
+		}
+		password, err := readSSHPasswordFile(opts.SSHPasswordFile)
+		if err != nil {
+			return nil, err
+		}
+		opts.SSHPassword = password
+	}
+
 	// Auto-discover or auto-generate an SSH private key for server-type edges
 	// when no credentials were provided. This makes `kedge agent join --type
 	// server` work out of the box: the agent generates a keypair, installs the
@@ -408,7 +739,7 @@ func New(opts *Options) (*Agent, error) {
 				}
 			}
 		}
-		if opts.SSHPrivateKeyPath == "" {
+		if opts.SSHPrivateKeyPath == "" && !opts.DryRun {
 			generated, err := ensureGeneratedAgentKey(opts.EdgeName)
 			if err != nil {
 				klog.Warningf("Failed to auto-generate SSH key: %v; SSH authentication will fail", err)
@@ -421,15 +752,23 @@ func New(opts *Options) (*Agent, error) {
 
 	// Ensure the public key for the selected private key is in authorized_keys
 	// so the hub can authenticate when it SSHes back into this agent.
-	if agentType == AgentTypeServer && opts.SSHPrivateKeyPath != "" {
+	if agentType == AgentTypeServer && opts.SSHPrivateKeyPath != "" && !opts.DryRun {
 		if err := ensureAuthorizedKey(opts.SSHPrivateKeyPath); err != nil {
 			klog.Warningf("Failed to ensure public key in authorized_keys: %v", err)
 		}
 	}
 
-	// Build hub config.
+	// Build hub config. An offline agent has none: its placements come
+	// from the bundle.
 	var hubConfig *rest.Config
-	if opts.HubKubeconfig != "" {
+	if opts.PlacementBundle != "" {
+		if agentType != AgentTypeKubernetes {
+			return nil, fmt.Errorf("placement bundles are supported for the %s type only", AgentTypeKubernetes)
+		}
+		if opts.PlacementBundlePublicKey == "" {
+			return nil, fmt.Errorf("a placement bundle needs the public key it is signed with")
+		}
+	} else if opts.HubKubeconfig != "" {
 		rules := &clientcmd.ClientConfigLoadingRules{ExplicitPath: opts.HubKubeconfig}
 		overrides := &clientcmd.ConfigOverrides{}
 		if opts.HubContext != "" {
@@ -461,9 +800,25 @@ func New(opts *Options) (*Agent, error) {
 		return nil, fmt.Errorf("hub URL or hub kubeconfig is required")
 	}
 
-	hubTLSConfig, err := rest.TLSConfigFor(hubConfig)
+	hubProxy, err := tunnel.ProxyFunc(opts.ProxyURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build hub TLS config: %w", err)
+		return nil, err
+	}
+
+	skew := clock.NewSkew()
+	var hubTLSConfig *tls.Config
+	if hubConfig != nil {
+		hubConfig.Proxy = hubProxy
+		hubConfig.Wrap(skew.WrapTransport)
+		hubTLSConfig, err = rest.TLSConfigFor(hubConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build hub TLS config: %w", err)
+		}
+		if hubTLSConfig != nil {
+			// Verify the hub's certificate against hub time, so a drifted edge
+			// clock does not see it as not yet valid or expired.
+			hubTLSConfig.Time = skew.Now
+		}
 	}
 
 	a := &Agent{
@@ -471,6 +826,11 @@ func New(opts *Options) (*Agent, error) {
 		agentType:    agentType,
 		hubConfig:    hubConfig,
 		hubTLSConfig: hubTLSConfig,
+		hubProxy:     hubProxy,
+		health:       health.NewTracker(),
+		clock:        skew,
+		traffic:      tunnel.NewTrafficMeter(opts.EdgeName, opts.TunnelBandwidth),
+		reloads:      make(chan Reloadable, 1),
 	}
 
 	// In server mode there is no downstream Kubernetes cluster to connect to.
@@ -487,6 +847,9 @@ func New(opts *Options) (*Agent, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to build downstream config: %w", err)
 		}
+		if err := opts.DownstreamProxy.apply(a.downstreamConfig); err != nil {
+			return nil, fmt.Errorf("invalid downstream proxy: %w", err)
+		}
 	}
 
 	return a, nil
@@ -501,10 +864,17 @@ func (a *Agent) Run(ctx context.Context) error {
 		"labels", a.opts.Labels,
 	)
 
+	if err := SetLogLevel(a.opts.LogLevel); err != nil {
+		return err
+	}
 	if a.opts.DebugAddr != "" {
 		go runDebugServer(ctx, logger, a.opts.DebugAddr)
 	}
 
+	if a.opts.PlacementBundle != "" {
+		return a.runOfflineMode(ctx, logger)
+	}
+
 	hubDynamic, err := dynamic.NewForConfig(a.hubConfig)
 	if err != nil {
 		return fmt.Errorf("creating hub dynamic client: %w", err)
@@ -517,8 +887,8 @@ func (a *Agent) Run(ctx context.Context) error {
 	return a.runKubernetesMode(ctx, logger, hubClient)
 }
 
-// runDebugServer starts an HTTP server exposing /healthz and the standard
-// net/http/pprof endpoints (/debug/pprof/, /goroutine, /heap, /profile, ...).
+// runDebugServer starts an HTTP server exposing /healthz, /metrics and the
+// standard net/http/pprof endpoints (/debug/pprof/, /goroutine, /heap, /profile, ...).
 // Goroutine dumps from this server are the primary way to diagnose tunnel
 // reconnect-loop hangs, since the agent has no other introspection surface.
 func runDebugServer(ctx context.Context, logger klog.Logger, addr string) {
@@ -527,6 +897,7 @@ func runDebugServer(ctx context.Context, logger klog.Logger, addr string) {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("ok"))
 	})
+	mux.Handle("/metrics", metrics.Handler())
 	mux.HandleFunc("/debug/pprof/", pprof.Index)
 	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
 	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
@@ -543,7 +914,7 @@ func runDebugServer(ctx context.Context, logger klog.Logger, addr string) {
 		_ = server.Shutdown(context.Background())
 	}()
 
-	logger.Info("Starting debug HTTP server (pprof + healthz)", "addr", addr)
+	logger.Info("Starting debug HTTP server (pprof, metrics + healthz)", "addr", addr)
 	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 		logger.Error(err, "debug HTTP server exited", "addr", addr)
 	}
@@ -567,6 +938,11 @@ func (a *Agent) runKubernetesMode(ctx context.Context, logger klog.Logger, hubCl
 	} else if a.opts.UsingSavedKubeconfig {
 		logger.Info("Using saved kubeconfig: skipping edge registration (already registered)",
 			"edgeName", a.opts.EdgeName)
+	} else if a.opts.Registration == RegistrationExternal {
+		if err := a.verifyEdge(ctx, hubClient); err != nil {
+			return err
+		}
+		logger.Info("External registration: using the admin-provisioned edge", "edgeName", a.opts.EdgeName)
 	} else {
 		if err := a.registerEdge(ctx, hubClient); err != nil {
 			return fmt.Errorf("registering edge: %w", err)
@@ -626,8 +1002,30 @@ func (a *Agent) runKubernetesMode(ctx context.Context, logger klog.Logger, hubCl
 		}
 		deliverOnce.Do(func() { close(agentKubeconfigDelivered) })
 	}
+	var e2eTLS *tunnel.EndToEndTLS
+	if a.opts.EndToEndTLS {
+		var err error
+		stateDir, _ := agentKeyDir(a.opts.EdgeName)
+		if e2eTLS, err = tunnel.NewEndToEndTLS(a.opts.EndToEndTLSCertFile, a.opts.EndToEndTLSKeyFile, stateDir); err != nil {
+			return fmt.Errorf("setting up end-to-end TLS: %w", err)
+		}
+		logger.Info("End-to-end TLS enabled; plaintext k8s access through the hub is refused; verify clients with kedge edge tls-proxy --fingerprint", "fingerprint", e2eTLS.Fingerprint)
+	}
 	a.setTunnelToken(a.hubConfig.BearerToken)
-	go tunnel.StartProxyTunnel(ctx, tunnelURL, a.currentTunnelToken, a.opts.EdgeName, string(a.agentType), a.downstreamConfig, a.hubTLSConfig, tunnelState, a.opts.SSHProxyPort, clusterName, onAgentToken, nil)
+	tunnelToken, err := a.tunnelTokenSource(ctx, tunnelURL, clusterName)
+	if err != nil {
+		return err
+	}
+	// shutdown tracks the tunnel and the status reporter, which drain and
+	// report the edge Draining when ctx is cancelled; Run returns once both
+	// are done.
+	var shutdown sync.WaitGroup
+	defer shutdown.Wait()
+	shutdown.Add(1)
+	go func() {
+		defer shutdown.Done()
+		tunnel.StartProxyTunnel(ctx, tunnelURL, tunnelToken, a.opts.EdgeName, string(a.agentType), a.downstreamConfig, e2eTLS, a.opts.K8sProxyScope, a.hubTLSConfig, a.hubProxy, tunnelState, a.opts.SSHProxyPort, clusterName, onAgentToken, nil, a.health, a.opts.TunnelKeepalive, a.opts.TunnelReconnect, a.traffic, a.opts.ReadCacheTTL, a.shutdownGracePeriod())
+	}()
 
 	// Out-of-cluster join-token mode: the in-memory hubClient was built from
 	// the bootstrap join token, which is not a valid kcp credential. Wait for
@@ -664,6 +1062,17 @@ func (a *Agent) runKubernetesMode(ctx context.Context, logger klog.Logger, hubCl
 	} else if wr, werr := agentReconciler.NewWorkloadReconciler(a.opts.EdgeName, hubDyn, a.downstreamConfig); werr != nil {
 		logger.Error(werr, "workload plane disabled: cannot build workload reconciler")
 	} else {
+		a.compareOfflineState(ctx, logger, hubDyn)
+		wr.SetHealth(a.health)
+		wr.SetApplyConcurrency(a.opts.ApplyConcurrency)
+		if err := wr.SetGitOps(a.opts.GitOps, a.opts.GitOpsNamespace); err != nil {
+			logger.Error(err, "GitOps handoff disabled; placements are applied directly")
+		}
+		if mf, merr := agentReconciler.NewManifestFetcher(a.hubConfig, clusterName); merr != nil {
+			logger.Error(merr, "placements referencing stored manifests will fail: cannot build manifest fetcher")
+		} else {
+			wr.SetManifestFetcher(mf)
+		}
 		go func() {
 			if err := wr.Run(ctx); err != nil {
 				logger.Error(err, "workload reconciler failed")
@@ -672,15 +1081,47 @@ func (a *Agent) runKubernetesMode(ctx context.Context, logger klog.Logger, hubCl
 
 		factory := informers.NewSharedInformerFactory(downstream, 10*time.Minute)
 		pr := agentStatus.NewPlacementReporter(hubDyn, factory)
+		pr.SetHealth(a.health)
 		factory.Start(ctx.Done())
 		go func() {
 			if err := pr.Run(ctx, 2); err != nil {
 				logger.Error(err, "placement status reporter failed")
 			}
 		}()
+
+		if downstreamDyn, merr := dynamic.NewForConfig(a.downstreamConfig); merr != nil {
+			logger.Error(merr, "placement status mirror disabled: cannot build downstream dynamic client")
+		} else {
+			sm := agentStatus.NewStatusMirror(hubDyn, downstreamDyn, a.opts.StatusMirrorNamespaces)
+			sm.SetHealth(a.health)
+			go func() {
+				if err := sm.Run(ctx, 2); err != nil {
+					logger.Error(err, "placement status mirror failed")
+				}
+			}()
+
+			if a.opts.ForwardEvents {
+				ef := agentStatus.NewEventForwarder(a.opts.EdgeName, hubDyn, downstream, downstreamDyn, a.opts.StatusMirrorNamespaces)
+				go func() {
+					if err := ef.Run(ctx, 2); err != nil {
+						logger.Error(err, "event forwarder failed")
+					}
+				}()
+			}
+		}
+
+		rc := registrycache.NewManager(a.opts.EdgeName, hubDyn, downstream)
+		go func() {
+			if err := rc.Run(ctx); err != nil {
+				logger.Error(err, "registry cache manager failed")
+			}
+		}()
 		logger.Info("Workload plane started (Workload/Placement)")
 	}
 
+	// Surface an edge-cluster credential that stopped working.
+	go a.watchDownstreamAuth(ctx)
+
 	// In-cluster join-token mode is the only path where the agent does not yet
 	// hold a valid kcp credential when reaching this point (it will os.Exit on
 	// kubeconfig delivery and the next pod restart picks up the saved one).
@@ -694,8 +1135,28 @@ func (a *Agent) runKubernetesMode(ctx context.Context, logger klog.Logger, hubCl
 			}
 		}()
 	} else {
+		go a.runReloads(ctx, hubClient)
+
 		reporter := agentStatus.NewEdgeReporter(a.opts.EdgeName, kedgeclient.EdgeGVRForType(string(a.agentType)), hubClient, tunnelState, a.opts.SSHProxyPort)
+		reporter.SetHealth(a.health)
+		reporter.SetClock(a.clock, a.clockSkewThreshold())
+		reporter.SetHeartbeatInterval(a.opts.HeartbeatInterval)
+		reporter.SetShutdownGracePeriod(a.shutdownGracePeriod())
+		reporter.SetTunnelTraffic(a.traffic)
+		if location, _ := a.opts.Location.Spec(); location != nil && a.opts.Registration != RegistrationExternal {
+			reporter.SetLocation(location)
+		}
+		if e2eTLS != nil {
+			reporter.SetEndToEndTLS(e2eTLS.CertificatePEM, e2eTLS.Fingerprint)
+		}
+		if downstream, derr := kubernetes.NewForConfig(a.downstreamConfig); derr != nil {
+			logger.Error(derr, "cluster inventory reporting disabled: cannot build downstream client")
+		} else {
+			reporter.SetInventory(downstream)
+		}
+		shutdown.Add(1)
 		go func() {
+			defer shutdown.Done()
 			if err := reporter.Run(ctx); err != nil {
 				logger.Error(err, "Edge status reporter failed")
 			}
@@ -704,10 +1165,28 @@ func (a *Agent) runKubernetesMode(ctx context.Context, logger klog.Logger, hubCl
 
 	logger.Info("Agent started successfully (kubernetes mode)")
 	<-ctx.Done()
-	logger.Info("Agent shutting down")
+	logger.Info("Agent shutting down; draining sessions", "grace", a.shutdownGracePeriod())
 	return nil
 }
 
+// shutdownGracePeriod returns the configured shutdown grace period, or the
+// default.
+func (a *Agent) shutdownGracePeriod() time.Duration {
+	if a.opts.ShutdownGracePeriod > 0 {
+		return a.opts.ShutdownGracePeriod
+	}
+	return DefaultShutdownGracePeriod
+}
+
+// clockSkewThreshold returns the configured clock skew threshold, or the
+// default.
+func (a *Agent) clockSkewThreshold() time.Duration {
+	if a.opts.ClockSkewThreshold > 0 {
+		return a.opts.ClockSkewThreshold
+	}
+	return clock.DefaultThreshold
+}
+
 // refreshHubClientFromSavedKubeconfig loads the SA kubeconfig that the tunnel
 // token-exchange callback just saved to disk, builds a fresh rest.Config from
 // it, updates a.hubConfig in place, and returns a kedge client backed by the
@@ -730,6 +1209,8 @@ func (a *Agent) refreshHubClientFromSavedKubeconfig() (*kedgeclient.Client, erro
 		newCfg.CAData = nil
 		newCfg.CAFile = ""
 	}
+	newCfg.Proxy = a.hubProxy
+	newCfg.Wrap(a.clock.WrapTransport)
 	dynClient, err := dynamic.NewForConfig(newCfg)
 	if err != nil {
 		return nil, fmt.Errorf("creating dynamic client from saved kubeconfig: %w", err)
@@ -749,6 +1230,11 @@ func (a *Agent) runServerMode(ctx context.Context, logger klog.Logger, hubClient
 	} else if a.opts.UsingSavedKubeconfig {
 		logger.Info("Using saved kubeconfig: skipping edge registration (already registered)",
 			"edgeName", a.opts.EdgeName)
+	} else if a.opts.Registration == RegistrationExternal {
+		if err := a.verifyEdge(ctx, hubClient); err != nil {
+			return err
+		}
+		logger.Info("External registration: using the admin-provisioned edge", "edgeName", a.opts.EdgeName)
 	} else {
 		if err := a.registerEdge(ctx, hubClient); err != nil {
 			return fmt.Errorf("registering edge: %w", err)
@@ -756,15 +1242,23 @@ func (a *Agent) runServerMode(ctx context.Context, logger klog.Logger, hubClient
 		logger.Info("Edge registered", "type", "server")
 	}
 
+	// Start the embedded SSH server before anything reads SSHProxyPort: the
+	// credential headers and the edge reporter probe it for the host key, and
+	// the tunnel proxies /ssh to it.
+	if err := a.startEmbeddedSSH(ctx, logger); err != nil {
+		return fmt.Errorf("starting embedded SSH server: %w", err)
+	}
+
 	// Set up SSH credentials if provided.
-	// In join-token mode the token is not a valid kcp credential, so skip
+	// In join-token mode the token is not a valid kcp credential, and with
+	// external registration the agent may not write Secrets, so skip
 	// credential setup — the hub manages SSH credentials server-side.
-	if a.opts.Token == "" {
+	if !a.hubStoresSSHCredentials() {
 		if err := a.setupSSHCredentials(ctx, logger, hubClient); err != nil {
 			return fmt.Errorf("setting up SSH credentials: %w", err)
 		}
 	} else {
-		logger.Info("Join-token mode: skipping SSH credential setup (hub manages credentials)")
+		logger.Info("Skipping SSH credential setup (hub manages credentials)")
 	}
 
 	// Determine the cluster name: explicit flag > kubeconfig Host URL > SA token.
@@ -819,15 +1313,26 @@ func (a *Agent) runServerMode(ctx context.Context, logger klog.Logger, hubClient
 
 	// In join-token mode, pass SSH credentials as WebSocket headers so the hub
 	// can store them server-side (the agent's join token is not a valid kcp
-	// credential for creating secrets).
+	// credential for creating secrets, nor may an externally registered
+	// agent create them).
 	var sshHeaders http.Header
-	if a.opts.Token != "" {
+	if a.hubStoresSSHCredentials() {
 		sshHeaders = a.buildSSHHeaders()
 	}
 
 	// downstreamConfig is nil in server mode; the tunnel only serves /ssh.
 	a.setTunnelToken(a.hubConfig.BearerToken)
-	go tunnel.StartProxyTunnel(ctx, tunnelURL, a.currentTunnelToken, a.opts.EdgeName, string(a.agentType), nil, a.hubTLSConfig, tunnelState, a.opts.SSHProxyPort, serverClusterName, serverOnAgentToken, sshHeaders)
+	tunnelToken, err := a.tunnelTokenSource(ctx, tunnelURL, serverClusterName)
+	if err != nil {
+		return err
+	}
+	var shutdown sync.WaitGroup
+	defer shutdown.Wait()
+	shutdown.Add(1)
+	go func() {
+		defer shutdown.Done()
+		tunnel.StartProxyTunnel(ctx, tunnelURL, tunnelToken, a.opts.EdgeName, string(a.agentType), nil, nil, tunnel.ProxyScope{}, a.hubTLSConfig, a.hubProxy, tunnelState, a.opts.SSHProxyPort, serverClusterName, serverOnAgentToken, sshHeaders, a.health, a.opts.TunnelKeepalive, a.opts.TunnelReconnect, a.traffic, a.opts.ReadCacheTTL, a.shutdownGracePeriod())
+	}()
 
 	// Out-of-cluster join-token mode: wait for the SA kubeconfig before
 	// starting the edge_reporter, otherwise its patch calls would all return
@@ -859,8 +1364,23 @@ func (a *Agent) runServerMode(ctx context.Context, logger klog.Logger, hubClient
 			}
 		}()
 	} else {
+		go a.runReloads(ctx, hubClient)
+
 		reporter := agentStatus.NewEdgeReporter(a.opts.EdgeName, kedgeclient.EdgeGVRForType(string(a.agentType)), hubClient, tunnelState, a.opts.SSHProxyPort)
+		reporter.SetHealth(a.health)
+		reporter.SetClock(a.clock, a.clockSkewThreshold())
+		reporter.SetHeartbeatInterval(a.opts.HeartbeatInterval)
+		reporter.SetShutdownGracePeriod(a.shutdownGracePeriod())
+		reporter.SetTunnelTraffic(a.traffic)
+		if len(a.opts.HostTelemetryPaths) > 0 {
+			reporter.SetHostTelemetry(a.opts.HostTelemetryPaths, a.opts.DiskPressureThreshold)
+		}
+		if location, _ := a.opts.Location.Spec(); location != nil && a.opts.Registration != RegistrationExternal {
+			reporter.SetLocation(location)
+		}
+		shutdown.Add(1)
 		go func() {
+			defer shutdown.Done()
 			if err := reporter.Run(ctx); err != nil {
 				logger.Error(err, "Edge status reporter failed")
 			}
@@ -869,7 +1389,7 @@ func (a *Agent) runServerMode(ctx context.Context, logger klog.Logger, hubClient
 
 	logger.Info("Agent started successfully (server mode)")
 	<-ctx.Done()
-	logger.Info("Agent shutting down")
+	logger.Info("Agent shutting down; draining sessions", "grace", a.shutdownGracePeriod())
 	return nil
 }
 
@@ -1012,19 +1532,77 @@ const (
 	sshCredentialsNamespace = "kedge-system"
 )
 
+// sshUser is the login name the hub uses for this edge: --ssh-user, else the
+// current OS user, else root.
+func (a *Agent) sshUser() string {
+	if a.opts.SSHUser != "" {
+		return a.opts.SSHUser
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return "root"
+}
+
+// startEmbeddedSSH starts the agent's embedded SSH server according to
+// opts.EmbeddedSSH and points SSHProxyPort at it. In fallback mode it does
+// nothing when the host sshd already answers on SSHProxyPort. The server
+// accepts the same credentials the hub holds for the edge: the agent's SSH
+// password, its own key pair and ~/.ssh/authorized_keys.
+func (a *Agent) startEmbeddedSSH(ctx context.Context, logger klog.Logger) error {
+	switch a.opts.EmbeddedSSH {
+	case EmbeddedSSHAlways:
+	case EmbeddedSSHFallback:
+		addr := net.JoinHostPort("localhost", strconv.Itoa(a.opts.SSHProxyPort))
+		if conn, err := net.DialTimeout("tcp", addr, 2*time.Second); err == nil {
+			conn.Close() //nolint:errcheck
+			logger.Info("Host SSH daemon reachable; embedded SSH server not needed", "addr", addr)
+			return nil
+		}
+		logger.Info("No host SSH daemon reachable; starting embedded SSH server", "addr", addr)
+	default:
+		return nil
+	}
+
+	sshOpts := sshserver.Options{
+		User:     a.sshUser(),
+		Password: a.opts.SSHPassword,
+		ExecOnly: a.opts.EmbeddedSSHExecOnly,
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		sshOpts.AuthorizedKeysFile = filepath.Join(home, ".ssh", "authorized_keys")
+	}
+	// Trust the agent's own key directly too: it is what the hub was given,
+	// and writing authorized_keys may have failed on a read-only home.
+	if a.opts.SSHPrivateKeyPath != "" {
+		if data, err := os.ReadFile(a.opts.SSHPrivateKeyPath); err == nil {
+			if signer, err := gossh.ParsePrivateKey(data); err == nil {
+				sshOpts.AuthorizedKeys = append(sshOpts.AuthorizedKeys, signer.PublicKey())
+			}
+		}
+	}
+	if dir, err := agentKeyDir(a.opts.EdgeName); err == nil {
+		sshOpts.HostKeyPath = filepath.Join(dir, "ssh_host_ed25519_key")
+	}
+
+	srv, err := sshserver.New(sshOpts)
+	if err != nil {
+		return err
+	}
+	port, err := srv.Listen(ctx, "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	a.opts.SSHProxyPort = port
+	a.sshServer = srv
+	return nil
+}
+
 // buildSSHHeaders returns HTTP headers carrying SSH credentials for the hub
 // to store server-side during join-token registration.
 func (a *Agent) buildSSHHeaders() http.Header {
 	h := http.Header{}
-	sshUser := a.opts.SSHUser
-	if sshUser == "" {
-		if u, err := user.Current(); err == nil {
-			sshUser = u.Username
-		} else {
-			sshUser = "root"
-		}
-	}
-	h.Set("X-Kedge-SSH-User", sshUser)
+	h.Set("X-Kedge-SSH-User", a.sshUser())
 	if a.opts.SSHPassword != "" {
 		h.Set("X-Kedge-SSH-Password", base64.StdEncoding.EncodeToString([]byte(a.opts.SSHPassword)))
 	}
@@ -1051,16 +1629,7 @@ func (a *Agent) buildSSHHeaders() http.Header {
 
 // setupSSHCredentials creates a Secret with SSH credentials and updates the Edge status.
 func (a *Agent) setupSSHCredentials(ctx context.Context, logger klog.Logger, hubClient *kedgeclient.Client) error {
-	// Determine SSH username.
-	sshUser := a.opts.SSHUser
-	if sshUser == "" {
-		// Default to current user.
-		if u, err := user.Current(); err == nil {
-			sshUser = u.Username
-		} else {
-			sshUser = "root"
-		}
-	}
+	sshUser := a.sshUser()
 
 	// Check if we have any credentials to set up.
 	hasPassword := a.opts.SSHPassword != ""
@@ -1072,71 +1641,20 @@ func (a *Agent) setupSSHCredentials(ctx context.Context, logger klog.Logger, hub
 		return nil
 	}
 
-	secretName := a.opts.EdgeName + "-ssh-credentials"
-	secretData := make(map[string][]byte)
-
+	secretName := a.sshCredentialsSecretName()
+	secretData, err := a.sshCredentialsData()
+	if err != nil {
+		return err
+	}
 	if hasPassword {
-		secretData["password"] = []byte(a.opts.SSHPassword)
 		logger.Info("Using SSH password authentication", "user", sshUser)
 	}
-
 	if hasPrivateKey {
-		keyData, err := os.ReadFile(a.opts.SSHPrivateKeyPath)
-		if err != nil {
-			return fmt.Errorf("reading SSH private key from %s: %w", a.opts.SSHPrivateKeyPath, err)
-		}
-		secretData["privateKey"] = keyData
 		logger.Info("Using SSH private key authentication", "user", sshUser, "keyPath", a.opts.SSHPrivateKeyPath)
 	}
 
-	// Create or update the Secret via the hub's dynamic client.
-	// We use the kubernetes clientset for core resources.
-	hubK8s, err := kubernetes.NewForConfig(a.hubConfig)
-	if err != nil {
-		return fmt.Errorf("creating hub kubernetes client: %w", err)
-	}
-
-	// Ensure namespace exists.
-	_, err = hubK8s.CoreV1().Namespaces().Get(ctx, sshCredentialsNamespace, metav1.GetOptions{})
-	if apierrors.IsNotFound(err) {
-		_, err = hubK8s.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{
-			ObjectMeta: metav1.ObjectMeta{Name: sshCredentialsNamespace},
-		}, metav1.CreateOptions{})
-		if err != nil && !apierrors.IsAlreadyExists(err) {
-			return fmt.Errorf("creating namespace %s: %w", sshCredentialsNamespace, err)
-		}
-	} else if err != nil {
-		return fmt.Errorf("checking namespace %s: %w", sshCredentialsNamespace, err)
-	}
-
-	// Create or update the secret.
-	secret := &corev1.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      secretName,
-			Namespace: sshCredentialsNamespace,
-			Labels: map[string]string{
-				"kedge.faros.sh/edge": a.opts.EdgeName,
-			},
-		},
-		Type: corev1.SecretTypeOpaque,
-		Data: secretData,
-	}
-
-	_, err = hubK8s.CoreV1().Secrets(sshCredentialsNamespace).Get(ctx, secretName, metav1.GetOptions{})
-	if apierrors.IsNotFound(err) {
-		_, err = hubK8s.CoreV1().Secrets(sshCredentialsNamespace).Create(ctx, secret, metav1.CreateOptions{})
-		if err != nil {
-			return fmt.Errorf("creating SSH credentials secret: %w", err)
-		}
-		logger.Info("Created SSH credentials secret", "secret", sshCredentialsNamespace+"/"+secretName)
-	} else if err != nil {
-		return fmt.Errorf("checking SSH credentials secret: %w", err)
-	} else {
-		_, err = hubK8s.CoreV1().Secrets(sshCredentialsNamespace).Update(ctx, secret, metav1.UpdateOptions{})
-		if err != nil {
-			return fmt.Errorf("updating SSH credentials secret: %w", err)
-		}
-		logger.Info("Updated SSH credentials secret", "secret", sshCredentialsNamespace+"/"+secretName)
+	if _, err := a.syncSSHCredentialsSecret(ctx, logger, secretData); err != nil {
+		return err
 	}
 
 	// Update Edge status with SSH credentials reference. The Edge type now lives
@@ -1189,6 +1707,114 @@ func (a *Agent) setupSSHCredentials(ctx context.Context, logger klog.Logger, hub
 	return nil
 }
 
+// sshCredentialsSecretName is the hub Secret holding the edge's SSH
+// credentials.
+func (a *Agent) sshCredentialsSecretName() string {
+	return a.opts.EdgeName + "-ssh-credentials"
+}
+
+// sshCredentialsData reads the SSH password and private key into the data of
+// the edge's SSH credentials Secret.
+func (a *Agent) sshCredentialsData() (map[string][]byte, error) {
+	data := make(map[string][]byte)
+	if a.opts.SSHPassword != "" {
+		data["password"] = []byte(a.opts.SSHPassword)
+	}
+	if a.opts.SSHPrivateKeyPath != "" {
+		keyData, err := os.ReadFile(a.opts.SSHPrivateKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading SSH private key from %s: %w", a.opts.SSHPrivateKeyPath, err)
+		}
+		data["privateKey"] = keyData
+	}
+	return data, nil
+}
+
+// syncSSHCredentialsSecret creates or updates the edge's SSH credentials
+// Secret on the hub so that it holds exactly data; credentials it held
+// before are dropped. It reports whether the Secret was written.
+func (a *Agent) syncSSHCredentialsSecret(ctx context.Context, logger klog.Logger, data map[string][]byte) (bool, error) {
+	secretName := a.sshCredentialsSecretName()
+
+	// Create or update the Secret via the hub's dynamic client.
+	// We use the kubernetes clientset for core resources.
+	hubK8s, err := kubernetes.NewForConfig(a.hubConfig)
+	if err != nil {
+		return false, fmt.Errorf("creating hub kubernetes client: %w", err)
+	}
+
+	// Ensure namespace exists.
+	_, err = hubK8s.CoreV1().Namespaces().Get(ctx, sshCredentialsNamespace, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = hubK8s.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: sshCredentialsNamespace},
+		}, metav1.CreateOptions{})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			return false, fmt.Errorf("creating namespace %s: %w", sshCredentialsNamespace, err)
+		}
+	} else if err != nil {
+		return false, fmt.Errorf("checking namespace %s: %w", sshCredentialsNamespace, err)
+	}
+
+	// Create or update the secret.
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: sshCredentialsNamespace,
+			Labels: map[string]string{
+				"kedge.faros.sh/edge": a.opts.EdgeName,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+
+	existing, err := hubK8s.CoreV1().Secrets(sshCredentialsNamespace).Get(ctx, secretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = hubK8s.CoreV1().Secrets(sshCredentialsNamespace).Create(ctx, secret, metav1.CreateOptions{})
+		if err != nil {
+			return false, fmt.Errorf("creating SSH credentials secret: %w", err)
+		}
+		logger.Info("Created SSH credentials secret", "secret", sshCredentialsNamespace+"/"+secretName)
+		return true, nil
+	} else if err != nil {
+		return false, fmt.Errorf("checking SSH credentials secret: %w", err)
+	}
+	if maps.EqualFunc(existing.Data, data, bytes.Equal) && existing.Labels["kedge.faros.sh/edge"] == a.opts.EdgeName {
+		return false, nil
+	}
+	_, err = hubK8s.CoreV1().Secrets(sshCredentialsNamespace).Update(ctx, secret, metav1.UpdateOptions{})
+	if err != nil {
+		return false, fmt.Errorf("updating SSH credentials secret: %w", err)
+	}
+	logger.Info("Updated SSH credentials secret", "secret", sshCredentialsNamespace+"/"+secretName)
+	return true, nil
+}
+
+// verifyEdge checks that the admin-provisioned edge of an agent with
+// external registration exists. The agent never creates it.
+func (a *Agent) verifyEdge(ctx context.Context, client *kedgeclient.Client) error {
+	gvr := kedgeclient.EdgeGVRForType(string(a.agentType))
+	if _, err := client.Dynamic().Resource(gvr).Get(ctx, a.opts.EdgeName, metav1.GetOptions{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("edge %q does not exist: with external registration an admin must create it (kedge edge create %s --registration-mode=external)", a.opts.EdgeName, a.opts.EdgeName)
+		}
+		return fmt.Errorf("getting edge %q: %w", a.opts.EdgeName, err)
+	}
+	if len(a.opts.Labels) > 0 {
+		klog.FromContext(ctx).Info("External registration: ignoring --labels; the edge's labels are managed by its admin", "edgeName", a.opts.EdgeName)
+	}
+	return nil
+}
+
+// hubStoresSSHCredentials reports whether the hub, rather than the agent,
+// stores a server edge's SSH credentials, from the tunnel's headers: the
+// agent's join token cannot write them, and an externally registered agent
+// may not.
+func (a *Agent) hubStoresSSHCredentials() bool {
+	return a.opts.Token != "" || a.opts.Registration == RegistrationExternal
+}
+
 // registerEdge ensures an Edge resource exists on the hub with the correct type.
 // The Edge type lives in the edges-connectivity provider (group
 // edges.kedge.faros.sh); the agent addresses it dynamically (unstructured).
@@ -1205,36 +1831,33 @@ func (a *Agent) registerEdge(ctx context.Context, client *kedgeclient.Client) er
 	existing, err := res.Get(ctx, a.opts.EdgeName, metav1.GetOptions{})
 	if err != nil {
 		logger.Info("Creating Edge", "name", a.opts.EdgeName, "type", edgeType)
-		labels := map[string]interface{}{}
-		for k, v := range a.opts.Labels {
-			labels[k] = v
-		}
 		edge := &unstructured.Unstructured{Object: map[string]interface{}{
 			"apiVersion": kedgeclient.KubernetesClusterGVR.GroupVersion().String(),
 			"kind":       "Edge",
 			"metadata": map[string]interface{}{
-				"name":   a.opts.EdgeName,
-				"labels": labels,
+				"name": a.opts.EdgeName,
 			},
 			"spec": map[string]interface{}{
 				"type": edgeType,
 			},
 		}}
+		if err := a.applyEdgeLabels(edge, a.opts.Labels); err != nil {
+			return fmt.Errorf("setting edge labels: %w", err)
+		}
+		if location, _ := a.opts.Location.Spec(); location != nil {
+			edge.Object["spec"].(map[string]interface{})["location"] = location
+		}
 		if _, err := res.Create(ctx, edge, metav1.CreateOptions{}); err != nil {
+			if apierrors.IsForbidden(err) && a.opts.Adoption == AdoptionRequest {
+				return a.requestAdoption(ctx, client, edgeType)
+			}
 			return fmt.Errorf("creating edge: %w", err)
 		}
 		return nil
 	}
 
-	logger.Info("Updating Edge", "name", a.opts.EdgeName, "type", edgeType)
-	labels, _, _ := unstructured.NestedStringMap(existing.Object, "metadata", "labels")
-	if labels == nil {
-		labels = map[string]string{}
-	}
-	for k, v := range a.opts.Labels {
-		labels[k] = v
-	}
-	if err := unstructured.SetNestedStringMap(existing.Object, labels, "metadata", "labels"); err != nil {
+	logger.Info("Updating Edge", "name", a.opts.EdgeName, "type", edgeType, "labelPolicy", a.opts.LabelPolicy)
+	if err := a.applyEdgeLabels(existing, a.opts.Labels); err != nil {
 		return fmt.Errorf("setting edge labels: %w", err)
 	}
 	// Keep spec.type in sync.
@@ -0,0 +1,147 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bundle reads and writes placement bundles: an edge's Placements,
+// with the Workloads and edge object they refer to, exported from the hub
+// and signed, so an agent with no route to the hub can apply them from
+// local disk.
+//
+// A bundle file is JSON: the bundle itself as the payload, and an Ed25519
+// signature over the payload bytes. Keys are PEM files as written by
+//
+//	openssl genpkey -algorithm ed25519 -out bundle-key.pem
+//	openssl pkey -in bundle-key.pem -pubout -out bundle-key.pub.pem
+package bundle
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Kind identifies a bundle file.
+const Kind = "PlacementBundle"
+
+// Bundle is what a bundle file carries.
+type Bundle struct {
+	// Edge is the edge the bundle was exported for; agents refuse bundles
+	// for other edges.
+	Edge string `json:"edge"`
+	// Created is when the bundle was exported.
+	Created time.Time `json:"created"`
+	// Objects are the hub objects the agent reads: the edge, its
+	// Placements, and the Workloads they reference.
+	Objects []unstructured.Unstructured `json:"objects"`
+}
+
+// file is the on-disk form of a signed bundle.
+type file struct {
+	Kind      string `json:"kind"`
+	Payload   []byte `json:"payload"`
+	Signature []byte `json:"signature"`
+}
+
+// Sign encodes b as a bundle file signed with key.
+func Sign(b *Bundle, key ed25519.PrivateKey) ([]byte, error) {
+	payload, err := json.Marshal(b)
+	if err != nil {
+		return nil, fmt.Errorf("encoding bundle: %w", err)
+	}
+	return json.MarshalIndent(file{
+		Kind:      Kind,
+		Payload:   payload,
+		Signature: ed25519.Sign(key, payload),
+	}, "", "  ")
+}
+
+// Open verifies a bundle file against key and decodes it. It also returns
+// the digest of the signed payload, which identifies the bundle.
+func Open(data []byte, key ed25519.PublicKey) (*Bundle, string, error) {
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, "", fmt.Errorf("decoding bundle file: %w", err)
+	}
+	if f.Kind != Kind {
+		return nil, "", fmt.Errorf("not a placement bundle (kind %q)", f.Kind)
+	}
+	if !ed25519.Verify(key, f.Payload, f.Signature) {
+		return nil, "", errors.New("bundle signature does not verify against the bundle public key")
+	}
+	var b Bundle
+	if err := json.Unmarshal(f.Payload, &b); err != nil {
+		return nil, "", fmt.Errorf("decoding bundle: %w", err)
+	}
+	if b.Edge == "" {
+		return nil, "", errors.New("bundle names no edge")
+	}
+	sum := sha256.Sum256(f.Payload)
+	return &b, "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// ReadPrivateKey reads a PEM-encoded PKCS #8 Ed25519 private key.
+func ReadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing private key %s: %w", path, err)
+	}
+	ed, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key %s is %T, not Ed25519", path, key)
+	}
+	return ed, nil
+}
+
+// ReadPublicKey reads a PEM-encoded PKIX Ed25519 public key.
+func ReadPublicKey(path string) (ed25519.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing public key %s: %w", path, err)
+	}
+	ed, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key %s is %T, not Ed25519", path, key)
+	}
+	return ed, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s holds no PEM block", path)
+	}
+	return block, nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestSignOpen(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &Bundle{
+		Edge:    "store-a",
+		Created: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Objects: []unstructured.Unstructured{{Object: map[string]interface{}{
+			"apiVersion": "edges.kedge.faros.sh/v1alpha1",
+			"kind":       "Placement",
+			"metadata":   map[string]interface{}{"name": "web-store-a", "namespace": "default"},
+		}}},
+	}
+	data, err := Sign(b, priv)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	got, digest, err := Open(data, pub)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if got.Edge != b.Edge || !got.Created.Equal(b.Created) || len(got.Objects) != 1 || got.Objects[0].GetName() != "web-store-a" {
+		t.Errorf("Open = %+v, want %+v", got, b)
+	}
+	if _, again, _ := Open(data, pub); again != digest || digest == "" {
+		t.Errorf("digest %q is not stable (%q)", digest, again)
+	}
+
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+	if _, _, err := Open(data, otherPub); err == nil {
+		t.Error("bundle opened with the wrong key")
+	}
+	tampered := bytes.Replace(data, []byte(`"payload": "`), []byte(`"payload": "AA`), 1)
+	if _, _, err := Open(tampered, pub); err == nil {
+		t.Error("tampered bundle opened")
+	}
+}
+
+func TestReadKeys(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	privDER, _ := x509.MarshalPKCS8PrivateKey(priv)
+	pubDER, _ := x509.MarshalPKIXPublicKey(pub)
+	privPath, pubPath := filepath.Join(dir, "key.pem"), filepath.Join(dir, "key.pub.pem")
+	if err := os.WriteFile(privPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pubPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	gotPriv, err := ReadPrivateKey(privPath)
+	if err != nil || !gotPriv.Equal(priv) {
+		t.Errorf("ReadPrivateKey = %v, %v", gotPriv, err)
+	}
+	gotPub, err := ReadPublicKey(pubPath)
+	if err != nil || !gotPub.Equal(pub) {
+		t.Errorf("ReadPublicKey = %v, %v", gotPub, err)
+	}
+	if _, err := ReadPublicKey(privPath); err == nil {
+		t.Error("private key read as a public key")
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clock measures how far the edge's clock is from the hub's, from
+// the Date headers of hub responses, so the agent can report the skew and
+// verify the hub's certificate against hub time rather than a drifted local
+// clock.
+package clock
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ConditionClockSynchronized is the edge condition the agent reports its
+// clock in: True while the skew versus the hub is within the threshold and
+// the kernel considers the clock synchronized.
+const ConditionClockSynchronized = "ClockSynchronized"
+
+// Reasons for the ClockSynchronized condition.
+const (
+	// ReasonInSync: the skew is within the threshold.
+	ReasonInSync = "InSync"
+	// ReasonSkewed: the skew exceeds the threshold.
+	ReasonSkewed = "ClockSkewed"
+	// ReasonNTPUnsynchronized: the skew is within the threshold but the
+	// kernel reports the clock as not synchronized, so it will drift.
+	ReasonNTPUnsynchronized = "NTPUnsynchronized"
+)
+
+const (
+	// DefaultThreshold is the skew beyond which the agent warns.
+	DefaultThreshold = 30 * time.Second
+
+	// MaxCorrection bounds the skew Now corrects for. A clock further off
+	// than this is broken rather than drifted; it is reported, not masked.
+	MaxCorrection = 10 * time.Minute
+
+	// maxRoundTrip drops samples from slow responses: the hub's Date could
+	// have been stamped anywhere within the round trip.
+	maxRoundTrip = 2 * time.Second
+
+	// samples is how many recent measurements the estimate is the median of.
+	// Date headers have one-second resolution, so single samples jitter.
+	samples = 5
+)
+
+// Skew estimates the offset of the hub's clock from the local one. The zero
+// value is not usable; use NewSkew. A nil *Skew reports no offset and Now is
+// the local time.
+type Skew struct {
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+	now     func() time.Time
+}
+
+// NewSkew returns a Skew with no measurements yet.
+func NewSkew() *Skew {
+	return &Skew{now: time.Now}
+}
+
+// Observe records one hub response: sent and received bracket the request
+// on the local clock, date is the response's Date header. Responses without
+// a parseable Date, or too slow to be precise, are ignored.
+func (s *Skew) Observe(sent, received time.Time, date string) {
+	if s == nil || date == "" {
+		return
+	}
+	hub, err := http.ParseTime(date)
+	if err != nil {
+		return
+	}
+	rtt := received.Sub(sent)
+	if rtt < 0 || rtt > maxRoundTrip {
+		return
+	}
+	// Date is truncated to the second: its midpoint is the best guess.
+	hub = hub.Add(500 * time.Millisecond)
+	offset := hub.Sub(sent.Add(rtt / 2))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.samples) < samples {
+		s.samples = append(s.samples, offset)
+		return
+	}
+	s.samples[s.next] = offset
+	s.next = (s.next + 1) % samples
+}
+
+// Offset returns hub time minus local time: positive when the edge's clock
+// is behind. ok is false before the first measurement.
+func (s *Skew) Offset() (offset time.Duration, ok bool) {
+	if s == nil {
+		return 0, false
+	}
+	s.mu.Lock()
+	sorted := append([]time.Duration(nil), s.samples...)
+	s.mu.Unlock()
+	if len(sorted) == 0 {
+		return 0, false
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted[len(sorted)/2], true
+}
+
+// Now returns the local time corrected by the measured offset, or the local
+// time when there is no measurement or the offset exceeds MaxCorrection.
+// Suitable as tls.Config.Time for connections to the hub.
+func (s *Skew) Now() time.Time {
+	if s == nil {
+		return time.Now()
+	}
+	now := s.now()
+	offset, ok := s.Offset()
+	if !ok || offset > MaxCorrection || offset < -MaxCorrection {
+		return now
+	}
+	return now.Add(offset)
+}
+
+// WrapTransport measures the skew from every response rt returns. Use as a
+// rest.Config WrapTransport for hub clients.
+func (s *Skew) WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return &skewTransport{rt: rt, skew: s}
+}
+
+type skewTransport struct {
+	rt   http.RoundTripper
+	skew *Skew
+}
+
+func (t *skewTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	sent := t.skew.now()
+	resp, err := t.rt.RoundTrip(req)
+	if err == nil {
+		t.skew.Observe(sent, t.skew.now(), resp.Header.Get("Date"))
+	}
+	return resp, err
+}
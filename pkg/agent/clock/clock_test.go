@@ -0,0 +1,88 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clock
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSkewOffset(t *testing.T) {
+	local := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	s := NewSkew()
+	s.now = func() time.Time { return local }
+
+	if _, ok := s.Offset(); ok {
+		t.Error("offset before any sample")
+	}
+	if got := s.Now(); !got.Equal(local) {
+		t.Errorf("Now without samples = %v, want local time", got)
+	}
+
+	// The hub is 42s ahead; the Date header truncates to the second.
+	hubDate := func(d time.Duration) string { return local.Add(d).Format(http.TimeFormat) }
+	s.Observe(local, local.Add(100*time.Millisecond), hubDate(42*time.Second))
+	s.Observe(local, local.Add(100*time.Millisecond), hubDate(43*time.Second))
+	s.Observe(local, local.Add(100*time.Millisecond), hubDate(42*time.Second))
+	// Slow or undated responses are ignored.
+	s.Observe(local, local.Add(5*time.Second), hubDate(time.Hour))
+	s.Observe(local, local.Add(time.Millisecond), "")
+
+	offset, ok := s.Offset()
+	if !ok || offset.Round(time.Second) != 42*time.Second {
+		t.Errorf("offset = %v, %v; want ~42s", offset, ok)
+	}
+	if got := s.Now().Sub(local).Round(time.Second); got != 42*time.Second {
+		t.Errorf("Now corrects by %v, want 42s", got)
+	}
+
+	// Beyond MaxCorrection the clock is reported, not corrected for.
+	for range samples {
+		s.Observe(local, local, hubDate(-time.Hour))
+	}
+	if offset, _ := s.Offset(); offset > -MaxCorrection {
+		t.Errorf("offset = %v, want about -1h", offset)
+	}
+	if got := s.Now(); !got.Equal(local) {
+		t.Errorf("Now = %v, want uncorrected local time", got)
+	}
+
+	var nilSkew *Skew
+	if _, ok := nilSkew.Offset(); ok {
+		t.Error("nil Skew has an offset")
+	}
+}
+
+func TestSkewWrapTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", time.Now().Add(-2*time.Minute).UTC().Format(http.TimeFormat))
+	}))
+	defer srv.Close()
+
+	s := NewSkew()
+	client := &http.Client{Transport: s.WrapTransport(http.DefaultTransport)}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if offset, ok := s.Offset(); !ok || offset.Round(time.Second) > -119*time.Second || offset < -121*time.Second {
+		t.Errorf("offset = %v, %v; want about -2m", offset, ok)
+	}
+}
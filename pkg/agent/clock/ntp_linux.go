@@ -0,0 +1,31 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clock
+
+import "golang.org/x/sys/unix"
+
+// NTPSynchronized reports whether the kernel considers the clock
+// synchronized, as set by whichever NTP daemon disciplines it (chronyd,
+// ntpd, systemd-timesyncd). known is false when the kernel cannot be asked.
+func NTPSynchronized() (synced, known bool) {
+	var tx unix.Timex
+	state, err := unix.Adjtimex(&tx)
+	if err != nil {
+		return false, false
+	}
+	return state != unix.TIME_ERROR && tx.Status&unix.STA_UNSYNC == 0, true
+}
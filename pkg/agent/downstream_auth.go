@@ -0,0 +1,118 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+
+	// Registers the oidc auth provider for downstream kubeconfigs; exec
+	// credential plugins need no registration.
+	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
+
+	"github.com/faroshq/faros-kedge/pkg/agent/health"
+)
+
+// downstreamAuthSubsystem names the check of the agent's credential for the
+// edge cluster in its health tracker.
+const downstreamAuthSubsystem = "downstream-auth"
+
+// downstreamAuthInterval is how often the credential is checked.
+const downstreamAuthInterval = time.Minute
+
+// watchDownstreamAuth checks the agent's credential for the edge cluster
+// until ctx is done, so an expired token its exec plugin or OIDC provider
+// cannot refresh shows in the edge's AgentHealthy condition as
+// DownstreamUnauthorized rather than as failing placements.
+func (a *Agent) watchDownstreamAuth(ctx context.Context) {
+	logger := klog.FromContext(ctx).WithValues("subsystem", downstreamAuthSubsystem)
+	ticker := time.NewTicker(downstreamAuthInterval)
+	defer ticker.Stop()
+	for {
+		err := checkDownstreamAuth(ctx, a.downstreamConfig)
+		var authErr *health.DownstreamAuthError
+		switch {
+		case err == nil:
+			a.health.Observe(downstreamAuthSubsystem, nil)
+		case errors.As(err, &authErr):
+			logger.Error(err, "edge cluster rejected the agent's credential")
+			a.health.Observe(downstreamAuthSubsystem, err)
+		default:
+			// The API server did not answer; that says nothing about the
+			// credential.
+			logger.V(2).Info("Skipping downstream credential check", "err", err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkDownstreamAuth asks the edge cluster's API server for its version with
+// the agent's credential. It returns a *health.DownstreamAuthError when the
+// server answers 401 or no credential could be obtained (a failing exec
+// plugin or OIDC refresh), and the plain error when the server did not answer.
+func checkDownstreamAuth(ctx context.Context, config *rest.Config) error {
+	cfg := rest.CopyConfig(config)
+	// Wrappers added here run below the credential ones, so a request that
+	// never reaches this one failed to get a credential.
+	var reached atomic.Bool
+	cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			reached.Store(true)
+			return rt.RoundTrip(r)
+		})
+	})
+	httpClient, err := rest.HTTPClientFor(cfg)
+	if err != nil {
+		return &health.DownstreamAuthError{Err: err}
+	}
+	defer httpClient.CloseIdleConnections()
+	client, err := kubernetes.NewForConfigAndClient(cfg, httpClient)
+	if err != nil {
+		return &health.DownstreamAuthError{Err: err}
+	}
+	_, err = client.Discovery().RESTClient().Get().AbsPath("/version").DoRaw(ctx)
+	switch {
+	case err == nil:
+		return nil
+	case ctx.Err() != nil:
+		return err
+	case !reached.Load():
+		return &health.DownstreamAuthError{Err: fmt.Errorf("getting credentials: %w", err)}
+	case apierrors.IsUnauthorized(err):
+		return &health.DownstreamAuthError{Err: err}
+	default:
+		return err
+	}
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
@@ -0,0 +1,76 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/faroshq/faros-kedge/pkg/agent/health"
+)
+
+func TestCheckDownstreamAuth(t *testing.T) {
+	apiserver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer good" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Unauthorized","code":401}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"gitVersion":"v1.33.0"}`))
+	}))
+	defer apiserver.Close()
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	failingPlugin := filepath.Join(t.TempDir(), "credential-plugin")
+	if err := os.WriteFile(failingPlugin, []byte("#!/bin/sh\necho 'token expired, run gcloud auth login' >&2\nexit 1\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name     string
+		config   *rest.Config
+		wantAuth bool
+		wantErr  bool
+	}{
+		{"accepted", &rest.Config{Host: apiserver.URL, BearerToken: "good"}, false, false},
+		{"rejected", &rest.Config{Host: apiserver.URL, BearerToken: "expired"}, true, true},
+		{"plugin fails", &rest.Config{Host: apiserver.URL, ExecProvider: &clientcmdapi.ExecConfig{
+			APIVersion:      "client.authentication.k8s.io/v1",
+			Command:         failingPlugin,
+			InteractiveMode: clientcmdapi.NeverExecInteractiveMode,
+		}}, true, true},
+		{"unreachable", &rest.Config{Host: closed.URL, BearerToken: "good"}, false, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkDownstreamAuth(context.Background(), tt.config)
+			var authErr *health.DownstreamAuthError
+			if (err != nil) != tt.wantErr || errors.As(err, &authErr) != tt.wantAuth {
+				t.Fatalf("checkDownstreamAuth = %v, want error %t, auth error %t", err, tt.wantErr, tt.wantAuth)
+			}
+		})
+	}
+}
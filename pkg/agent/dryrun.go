@@ -0,0 +1,263 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/faroshq/faros-kedge/pkg/agent/bundle"
+	"github.com/faroshq/faros-kedge/pkg/apiurl"
+	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+)
+
+// dryRunTimeout bounds each network call of a dry run.
+const dryRunTimeout = 10 * time.Second
+
+// CheckStatus is the outcome of a dry-run check.
+type CheckStatus string
+
+const (
+	// CheckOK means the check passed.
+	CheckOK CheckStatus = "ok"
+	// CheckWarning means the agent would run, but not as configured might
+	// suggest, e.g. it would request adoption instead of creating its edge.
+	CheckWarning CheckStatus = "warning"
+	// CheckFailed means the agent would not run.
+	CheckFailed CheckStatus = "failed"
+	// CheckSkipped means the check does not apply or could not be made.
+	CheckSkipped CheckStatus = "skipped"
+)
+
+// Check is the result of one dry-run check.
+type Check struct {
+	Name    string      `json:"name"`
+	Status  CheckStatus `json:"status"`
+	Message string      `json:"message"`
+}
+
+// ChecksFailed reports whether any of checks failed.
+func ChecksFailed(checks []Check) bool {
+	for _, c := range checks {
+		if c.Status == CheckFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// DryRun checks what the agent needs to run without starting it: the
+// downstream cluster or SSH daemon, the hub credentials, the edge and the
+// path to the hub's tunnel endpoint. It changes nothing: the edge is not
+// created, and the tunnel is not opened, so a join token is not exchanged.
+// Options were already validated by New, which must have been called with
+// Options.DryRun set.
+func (a *Agent) DryRun(ctx context.Context) []Check {
+	checks := []Check{{Name: "config", Status: CheckOK, Message: fmt.Sprintf("%s edge %q", a.agentType, a.opts.EdgeName)}}
+	if a.agentType == AgentTypeKubernetes {
+		checks = append(checks, a.checkDownstream())
+	} else {
+		checks = append(checks, a.checkSSH(ctx))
+	}
+	if a.opts.PlacementBundle != "" {
+		return append(checks, a.checkBundle(ctx))
+	}
+	checks = append(checks, a.checkHub(ctx)...)
+	return append(checks, a.checkTunnel(ctx))
+}
+
+// checkDownstream asks the target cluster for its version.
+func (a *Agent) checkDownstream() Check {
+	c := Check{Name: "downstream"}
+	config := rest.CopyConfig(a.downstreamConfig)
+	config.Timeout = dryRunTimeout
+	cs, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		c.Status, c.Message = CheckFailed, err.Error()
+		return c
+	}
+	v, err := cs.Discovery().ServerVersion()
+	if err != nil {
+		c.Status, c.Message = CheckFailed, fmt.Sprintf("%s: %v", a.downstreamConfig.Host, err)
+		return c
+	}
+	c.Status, c.Message = CheckOK, fmt.Sprintf("%s: Kubernetes %s", a.downstreamConfig.Host, v.GitVersion)
+	return c
+}
+
+// checkSSH dials the SSH daemon a server edge proxies to.
+func (a *Agent) checkSSH(ctx context.Context) Check {
+	c := Check{Name: "ssh"}
+	if a.opts.EmbeddedSSH == EmbeddedSSHAlways {
+		c.Status, c.Message = CheckOK, "embedded SSH server"
+		return c
+	}
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(a.opts.SSHProxyPort))
+	ctx, cancel := context.WithTimeout(ctx, dryRunTimeout)
+	defer cancel()
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	switch {
+	case err == nil:
+		_ = conn.Close()
+		c.Status, c.Message = CheckOK, "sshd at "+addr
+		if a.opts.SSHPrivateKeyPath == "" && a.opts.SSHPassword == "" {
+			c.Message += "; no SSH key found, the agent would generate one"
+		}
+	case a.opts.EmbeddedSSH == EmbeddedSSHFallback:
+		c.Status, c.Message = CheckWarning, fmt.Sprintf("no sshd at %s; the embedded SSH server would serve", addr)
+	default:
+		c.Status, c.Message = CheckFailed, fmt.Sprintf("no sshd at %s: %v", addr, err)
+	}
+	return c
+}
+
+// checkBundle opens the placement bundle an offline agent would apply.
+func (a *Agent) checkBundle(ctx context.Context) Check {
+	c := Check{Name: "bundle"}
+	key, err := bundle.ReadPublicKey(a.opts.PlacementBundlePublicKey)
+	if err != nil {
+		c.Status, c.Message = CheckFailed, fmt.Sprintf("reading public key: %v", err)
+		return c
+	}
+	src := newBundleSource(a.opts.PlacementBundle, a.opts.EdgeName, key)
+	if _, err := src.load(ctx); err != nil {
+		c.Status, c.Message = CheckFailed, fmt.Sprintf("%s: %v", a.opts.PlacementBundle, err)
+		return c
+	}
+	c.Status, c.Message = CheckOK, fmt.Sprintf("%s, exported %s", a.opts.PlacementBundle, src.exported.Format(time.RFC3339))
+	return c
+}
+
+// checkHub authenticates to the hub by reading the edge, and tells whether
+// the agent would find, create or request its edge.
+func (a *Agent) checkHub(ctx context.Context) []Check {
+	auth := Check{Name: "hub-auth"}
+	edge := Check{Name: "edge"}
+	if a.opts.Token != "" {
+		auth.Status, auth.Message = CheckSkipped, "join token; the hub checks it when the tunnel first connects"
+		edge.Status, edge.Message = CheckSkipped, "join-token edges are provisioned by an admin"
+		return []Check{auth, edge}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, dryRunTimeout)
+	defer cancel()
+	hubDynamic, err := dynamic.NewForConfig(a.hubConfig)
+	if err != nil {
+		auth.Status, auth.Message = CheckFailed, err.Error()
+		edge.Status, edge.Message = CheckSkipped, "no hub client"
+		return []Check{auth, edge}
+	}
+	gvr := kedgeclient.EdgeGVRForType(string(a.agentType))
+	_, err = hubDynamic.Resource(gvr).Get(ctx, a.opts.EdgeName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsUnauthorized(err):
+		auth.Status, auth.Message = CheckFailed, "the hub rejected the credentials"
+		edge.Status, edge.Message = CheckSkipped, "not authenticated"
+		return []Check{auth, edge}
+	case err != nil && !apierrors.IsNotFound(err) && !apierrors.IsForbidden(err):
+		auth.Status, auth.Message = CheckFailed, fmt.Sprintf("%s: %v", a.hubConfig.Host, err)
+		edge.Status, edge.Message = CheckSkipped, "hub unreachable"
+		return []Check{auth, edge}
+	}
+	auth.Status, auth.Message = CheckOK, a.hubConfig.Host
+
+	switch {
+	case err == nil:
+		edge.Status, edge.Message = CheckOK, fmt.Sprintf("%s %q exists", gvr.Resource, a.opts.EdgeName)
+	case apierrors.IsForbidden(err):
+		edge.Status, edge.Message = CheckFailed, fmt.Sprintf("may not read %s %q", gvr.Resource, a.opts.EdgeName)
+	case a.opts.Registration == RegistrationExternal || a.opts.UsingSavedKubeconfig:
+		edge.Status, edge.Message = CheckFailed, fmt.Sprintf("%s %q does not exist and the agent does not create it", gvr.Resource, a.opts.EdgeName)
+	default:
+		edge = a.checkEdgeCreate(ctx, gvr.Group, gvr.Resource)
+	}
+	return []Check{auth, edge}
+}
+
+// checkEdgeCreate tells whether the agent may create its missing edge.
+func (a *Agent) checkEdgeCreate(ctx context.Context, group, resource string) Check {
+	c := Check{Name: "edge"}
+	cs, err := kubernetes.NewForConfig(a.hubConfig)
+	if err != nil {
+		c.Status, c.Message = CheckFailed, err.Error()
+		return c
+	}
+	review, err := cs.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{Verb: "create", Group: group, Resource: resource, Name: a.opts.EdgeName},
+		},
+	}, metav1.CreateOptions{})
+	switch {
+	case err != nil:
+		c.Status, c.Message = CheckFailed, fmt.Sprintf("%s %q does not exist; checking create access: %v", resource, a.opts.EdgeName, err)
+	case review.Status.Allowed:
+		c.Status, c.Message = CheckOK, fmt.Sprintf("%s %q does not exist; the agent would create it", resource, a.opts.EdgeName)
+	case a.opts.Adoption == AdoptionRequest:
+		c.Status, c.Message = CheckWarning, fmt.Sprintf("%s %q does not exist and may not be created; the agent would request adoption (kedge edge approve)", resource, a.opts.EdgeName)
+	default:
+		c.Status, c.Message = CheckFailed, fmt.Sprintf("%s %q does not exist and may not be created", resource, a.opts.EdgeName)
+	}
+	return c
+}
+
+// checkTunnel reaches the hub's health endpoint at the tunnel URL, through
+// the proxy and with the TLS settings of the tunnel, without opening it.
+func (a *Agent) checkTunnel(ctx context.Context) Check {
+	c := Check{Name: "tunnel"}
+	tunnelURL := a.opts.TunnelURL
+	if tunnelURL == "" {
+		tunnelURL, _ = apiurl.SplitBaseAndCluster(a.hubConfig.Host)
+	}
+	// The tunnel dials ws(s):// for http(s):// and accepts either.
+	if strings.HasPrefix(tunnelURL, "ws") {
+		tunnelURL = "http" + strings.TrimPrefix(tunnelURL, "ws")
+	}
+	target := strings.TrimSuffix(tunnelURL, "/") + "/healthz"
+	client := &http.Client{
+		Timeout:   dryRunTimeout,
+		Transport: &http.Transport{Proxy: a.hubProxy, TLSClientConfig: a.hubTLSConfig},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		c.Status, c.Message = CheckFailed, err.Error()
+		return c
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		c.Status, c.Message = CheckFailed, err.Error()
+		return c
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		c.Status, c.Message = CheckFailed, fmt.Sprintf("%s answered %s", target, resp.Status)
+		return c
+	}
+	c.Status, c.Message = CheckOK, tunnelURL
+	return c
+}
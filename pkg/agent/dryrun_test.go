@@ -0,0 +1,104 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestDryRunHubChecks(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		edgeStatus int
+		allowed    bool
+		adoption   AdoptionPolicy
+		want       map[string]CheckStatus
+	}{
+		{"unauthorized", http.StatusUnauthorized, false, AdoptionRequest,
+			map[string]CheckStatus{"hub-auth": CheckFailed, "edge": CheckSkipped, "tunnel": CheckOK}},
+		{"edge exists", http.StatusOK, false, AdoptionRequest,
+			map[string]CheckStatus{"hub-auth": CheckOK, "edge": CheckOK, "tunnel": CheckOK}},
+		{"edge created", http.StatusNotFound, true, AdoptionRequest,
+			map[string]CheckStatus{"hub-auth": CheckOK, "edge": CheckOK, "tunnel": CheckOK}},
+		{"adoption requested", http.StatusNotFound, false, AdoptionRequest,
+			map[string]CheckStatus{"hub-auth": CheckOK, "edge": CheckWarning, "tunnel": CheckOK}},
+		{"edge refused", http.StatusNotFound, false, AdoptionNever,
+			map[string]CheckStatus{"hub-auth": CheckOK, "edge": CheckFailed, "tunnel": CheckOK}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			hub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case r.URL.Path == "/healthz":
+					_, _ = w.Write([]byte("ok"))
+				case strings.HasSuffix(r.URL.Path, "/selfsubjectaccessreviews"):
+					var review map[string]interface{}
+					_ = json.NewDecoder(r.Body).Decode(&review)
+					review["status"] = map[string]interface{}{"allowed": tc.allowed}
+					_ = json.NewEncoder(w).Encode(review)
+				case strings.HasSuffix(r.URL.Path, "/kubernetesclusters/store-a"):
+					if tc.edgeStatus == http.StatusOK {
+						_, _ = w.Write([]byte(`{"apiVersion":"edges.kedge.faros.sh/v1alpha1","kind":"KubernetesCluster","metadata":{"name":"store-a"}}`))
+						return
+					}
+					w.WriteHeader(tc.edgeStatus)
+					_ = json.NewEncoder(w).Encode(map[string]interface{}{
+						"apiVersion": "v1", "kind": "Status", "status": "Failure", "code": tc.edgeStatus,
+						"reason": map[int]string{http.StatusUnauthorized: "Unauthorized", http.StatusNotFound: "NotFound"}[tc.edgeStatus],
+					})
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer hub.Close()
+
+			a := &Agent{
+				opts:      &Options{EdgeName: "store-a", Adoption: tc.adoption, Registration: RegistrationAgent},
+				agentType: AgentTypeKubernetes,
+				hubConfig: &rest.Config{Host: hub.URL + "/clusters/abc"},
+			}
+			checks := append(a.checkHub(context.Background()), a.checkTunnel(context.Background()))
+			got := map[string]CheckStatus{}
+			for _, c := range checks {
+				got[c.Name] = c.Status
+			}
+			for name, want := range tc.want {
+				if got[name] != want {
+					t.Errorf("%s = %s, want %s (%+v)", name, got[name], want, checks)
+				}
+			}
+			if ChecksFailed(checks) != (tc.want["hub-auth"] == CheckFailed || tc.want["edge"] == CheckFailed) {
+				t.Errorf("ChecksFailed = %v for %+v", ChecksFailed(checks), checks)
+			}
+		})
+	}
+
+	// A join token is not a hub credential and is not spent on a dry run.
+	a := &Agent{opts: &Options{EdgeName: "store-a", Token: "join"}, agentType: AgentTypeKubernetes}
+	for _, c := range a.checkHub(context.Background()) {
+		if c.Status != CheckSkipped {
+			t.Errorf("join token: %s = %s, want skipped", c.Name, c.Status)
+		}
+	}
+}
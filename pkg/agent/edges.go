@@ -0,0 +1,67 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// PerEdge splits o into the options of each edge the process serves: o
+// itself when Edges is empty, otherwise one copy per entry of Edges, in
+// name order, with EdgeName and Context set from it. Only the first copy
+// keeps DebugAddr, so one debug server covers the process.
+//
+// Every edge connects with its own credentials, found by edge name the way
+// a single-edge agent finds them, so a bootstrap Token, which belongs to one
+// edge, cannot be shared.
+func (o *Options) PerEdge() ([]*Options, error) {
+	if len(o.Edges) == 0 {
+		return []*Options{o}, nil
+	}
+	if o.EdgeName != "" || o.Context != "" {
+		return nil, fmt.Errorf("edges cannot be combined with an edge name or context")
+	}
+	if o.Type == AgentTypeServer {
+		return nil, fmt.Errorf("multiple edges are supported for the %s type only", AgentTypeKubernetes)
+	}
+	if o.PlacementBundle != "" && len(o.Edges) > 1 {
+		return nil, fmt.Errorf("a placement bundle is for a single edge")
+	}
+	if o.Token != "" && len(o.Edges) > 1 {
+		return nil, fmt.Errorf("a bootstrap token registers a single edge; join each edge first, then run them together")
+	}
+
+	out := make([]*Options, 0, len(o.Edges))
+	for i, name := range slices.Sorted(maps.Keys(o.Edges)) {
+		if name == "" {
+			return nil, fmt.Errorf("edge with context %q has no name", o.Edges[name])
+		}
+		edge := *o
+		edge.Edges = nil
+		edge.EdgeName = name
+		edge.Context = o.Edges[name]
+		edge.Labels = maps.Clone(o.Labels)
+		edge.StatusMirrorNamespaces = slices.Clone(o.StatusMirrorNamespaces)
+		if i > 0 {
+			edge.DebugAddr = ""
+		}
+		out = append(out, &edge)
+	}
+	return out, nil
+}
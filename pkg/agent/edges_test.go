@@ -0,0 +1,71 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import "testing"
+
+func TestPerEdge(t *testing.T) {
+	opts := NewOptions()
+	opts.HubURL = "https://hub.example.com"
+	opts.DebugAddr = "127.0.0.1:6060"
+	opts.Labels["site"] = "berlin"
+	opts.Edges = map[string]string{"store-b": "kind-b", "store-a": "kind-a"}
+
+	edges, err := opts.PerEdge()
+	if err != nil {
+		t.Fatalf("PerEdge: %v", err)
+	}
+	if len(edges) != 2 {
+		t.Fatalf("got %d edges, want 2", len(edges))
+	}
+	for i, want := range []struct{ name, context, debugAddr string }{
+		{"store-a", "kind-a", "127.0.0.1:6060"},
+		{"store-b", "kind-b", ""},
+	} {
+		e := edges[i]
+		if e.EdgeName != want.name || e.Context != want.context || e.DebugAddr != want.debugAddr {
+			t.Errorf("edge %d = %s/%s/%q, want %s/%s/%q", i, e.EdgeName, e.Context, e.DebugAddr, want.name, want.context, want.debugAddr)
+		}
+		if e.HubURL != opts.HubURL || e.Edges != nil {
+			t.Errorf("edge %d did not inherit the shared options: %+v", i, e)
+		}
+	}
+	edges[0].Labels["site"] = "munich"
+	if edges[1].Labels["site"] != "berlin" || opts.Labels["site"] != "berlin" {
+		t.Error("edges share one labels map")
+	}
+
+	single := NewOptions()
+	single.EdgeName = "store-a"
+	if edges, err := single.PerEdge(); err != nil || len(edges) != 1 || edges[0] != single {
+		t.Errorf("PerEdge without Edges = %v, %v; want the options themselves", edges, err)
+	}
+
+	for name, mutate := range map[string]func(*Options){
+		"edge name":    func(o *Options) { o.EdgeName = "x" },
+		"context":      func(o *Options) { o.Context = "x" },
+		"server type":  func(o *Options) { o.Type = AgentTypeServer },
+		"shared token": func(o *Options) { o.Token = "join" },
+	} {
+		bad := NewOptions()
+		bad.Edges = map[string]string{"store-a": "kind-a", "store-b": "kind-b"}
+		mutate(bad)
+		if _, err := bad.PerEdge(); err == nil {
+			t.Errorf("%s: PerEdge succeeded, want an error", name)
+		}
+	}
+}
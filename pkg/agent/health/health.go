@@ -0,0 +1,292 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package health classifies the errors the agent's subsystems (tunnel,
+// reconcilers, status reporters) run into as transient or fatal, and keeps
+// the current one per subsystem so the edge status reporter can surface the
+// most severe in the edge's AgentHealthy condition.
+package health
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// ConditionAgentHealthy is the edge condition the agent reports its health
+// in: True when no subsystem is failing, otherwise False with the most
+// severe failure's reason.
+const ConditionAgentHealthy = "AgentHealthy"
+
+// ReasonAsExpected is the AgentHealthy reason while nothing is failing.
+const ReasonAsExpected = "AsExpected"
+
+// Reasons for a failing subsystem. Transient ones clear on their own once
+// the hub or network recovers; fatal ones need an operator.
+const (
+	// ReasonHubUnreachable: the hub could not be reached (transient).
+	ReasonHubUnreachable = "HubUnreachable"
+	// ReasonRateLimited: the hub answered 429 (transient).
+	ReasonRateLimited = "RateLimited"
+	// ReasonHubError: the hub answered with a 5xx or timed out (transient).
+	ReasonHubError = "HubError"
+	// ReasonError: any other error (transient; retried).
+	ReasonError = "Error"
+	// ReasonUnauthorized: the agent's credential was rejected (fatal).
+	ReasonUnauthorized = "Unauthorized"
+	// ReasonForbidden: RBAC denies the agent an operation it needs (fatal).
+	ReasonForbidden = "Forbidden"
+	// ReasonTLSVerificationFailed: the hub's certificate is not trusted (fatal).
+	ReasonTLSVerificationFailed = "TLSVerificationFailed"
+	// ReasonUnsupportedVersion: the hub refused the agent's version as
+	// outside its skew policy (fatal).
+	ReasonUnsupportedVersion = "UnsupportedVersion"
+	// ReasonDownstreamUnauthorized: the edge cluster's API server rejected
+	// the agent's credential, or its credential plugin failed (fatal).
+	ReasonDownstreamUnauthorized = "DownstreamUnauthorized"
+)
+
+// Severity orders failures; the reporter surfaces the highest.
+type Severity int
+
+const (
+	// SeverityTransient failures are retried and expected to clear.
+	SeverityTransient Severity = iota + 1
+	// SeverityFatal failures repeat until an operator fixes credentials,
+	// RBAC, trust or the agent version.
+	SeverityFatal
+)
+
+func (s Severity) String() string {
+	if s == SeverityFatal {
+		return "fatal"
+	}
+	return "transient"
+}
+
+// HTTPStatusError carries the status of a non-API HTTP response, such as a
+// refused WebSocket upgrade, so Classify can read it.
+type HTTPStatusError struct {
+	Code int
+	Err  error
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("%v (HTTP %d)", e.Err, e.Code)
+}
+
+func (e *HTTPStatusError) Unwrap() error { return e.Err }
+
+// DownstreamAuthError marks a failure to authenticate to the edge cluster's
+// own API server, as opposed to the hub.
+type DownstreamAuthError struct {
+	Err error
+}
+
+func (e *DownstreamAuthError) Error() string {
+	return fmt.Sprintf("authenticating to the edge cluster: %v", e.Err)
+}
+
+func (e *DownstreamAuthError) Unwrap() error { return e.Err }
+
+// Classify returns err's severity and reason code.
+func Classify(err error) (Severity, string) {
+	var downstreamErr *DownstreamAuthError
+	if errors.As(err, &downstreamErr) {
+		return SeverityFatal, ReasonDownstreamUnauthorized
+	}
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return classifyCode(statusErr.Code)
+	}
+	var apiStatus apierrors.APIStatus
+	if errors.As(err, &apiStatus) {
+		return classifyCode(int(apiStatus.Status().Code))
+	}
+
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var invalid x509.CertificateInvalidError
+	if errors.As(err, &unknownAuthority) || errors.As(err, &hostname) || errors.As(err, &invalid) {
+		return SeverityFatal, ReasonTLSVerificationFailed
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return SeverityTransient, ReasonHubUnreachable
+	}
+	return SeverityTransient, ReasonError
+}
+
+func classifyCode(code int) (Severity, string) {
+	switch {
+	case code == http.StatusUnauthorized:
+		return SeverityFatal, ReasonUnauthorized
+	case code == http.StatusForbidden:
+		return SeverityFatal, ReasonForbidden
+	case code == http.StatusUpgradeRequired:
+		return SeverityFatal, ReasonUnsupportedVersion
+	case code == http.StatusTooManyRequests:
+		return SeverityTransient, ReasonRateLimited
+	case code >= 500:
+		return SeverityTransient, ReasonHubError
+	default:
+		return SeverityTransient, ReasonError
+	}
+}
+
+// Problem is the current failure of one subsystem.
+type Problem struct {
+	Subsystem string
+	Severity  Severity
+	Reason    string
+	Message   string
+	// Since is when the subsystem started failing with this reason.
+	Since time.Time
+}
+
+// Tracker holds the current Problem of each subsystem. A nil *Tracker
+// ignores every call, so subsystems need not check whether one is wired.
+type Tracker struct {
+	mu       sync.Mutex
+	problems map[string]Problem
+	now      func() time.Time
+
+	// tunnelUp is whether the tunnel to the hub is connected, since
+	// tunnelSince; tunnelEver whether it ever was.
+	tunnelUp    bool
+	tunnelEver  bool
+	tunnelSince time.Time
+}
+
+// NewTracker returns an empty Tracker.
+func NewTracker() *Tracker {
+	return &Tracker{problems: map[string]Problem{}, now: time.Now, tunnelSince: time.Now()}
+}
+
+// Observe records the outcome of subsystem's latest attempt: a nil err
+// clears its problem, any other err replaces it. Cancellation is shutdown,
+// not a failure, and is ignored.
+func (t *Tracker) Observe(subsystem string, err error) {
+	if t == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err == nil {
+		delete(t.problems, subsystem)
+		return
+	}
+	severity, reason := Classify(err)
+	since := t.now()
+	if prev, ok := t.problems[subsystem]; ok && prev.Reason == reason {
+		since = prev.Since
+	}
+	t.problems[subsystem] = Problem{
+		Subsystem: subsystem,
+		Severity:  severity,
+		Reason:    reason,
+		Message:   err.Error(),
+		Since:     since,
+	}
+}
+
+// Worst returns the most severe current problem: fatal before transient,
+// then the longest-standing, then by subsystem name. ok is false when
+// nothing is failing.
+func (t *Tracker) Worst() (p Problem, ok bool) {
+	if t == nil {
+		return Problem{}, false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	problems := make([]Problem, 0, len(t.problems))
+	for _, p := range t.problems {
+		problems = append(problems, p)
+	}
+	if len(problems) == 0 {
+		return Problem{}, false
+	}
+	sort.Slice(problems, func(i, j int) bool {
+		a, b := problems[i], problems[j]
+		if a.Severity != b.Severity {
+			return a.Severity > b.Severity
+		}
+		if !a.Since.Equal(b.Since) {
+			return a.Since.Before(b.Since)
+		}
+		return a.Subsystem < b.Subsystem
+	})
+	return problems[0], true
+}
+
+// SetTunnelConnected records whether the tunnel to the hub is connected.
+func (t *Tracker) SetTunnelConnected(connected bool) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if connected != t.tunnelUp {
+		t.tunnelUp, t.tunnelSince = connected, t.now()
+	}
+	t.tunnelEver = t.tunnelEver || connected
+}
+
+// Ready reports why the agent cannot serve the hub yet: its tunnel is not
+// connected (when needTunnel) or a subsystem is failing fatally. Transient
+// problems do not make it unready.
+func (t *Tracker) Ready(needTunnel bool) error {
+	if t == nil {
+		return nil
+	}
+	if p, ok := t.Worst(); ok && p.Severity == SeverityFatal {
+		return fmt.Errorf("%s: %s: %s", p.Subsystem, p.Reason, p.Message)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if needTunnel && !t.tunnelUp {
+		return errors.New("tunnel to the hub not connected")
+	}
+	return nil
+}
+
+// Live reports why the agent looks stuck: a subsystem has failed fatally
+// for longer than timeout, or its tunnel (when needTunnel) has been down for
+// longer than timeout after having connected. A tunnel that never connected
+// is left to Ready, so an agent waiting to be adopted is not restarted. A
+// zero timeout disables both checks.
+func (t *Tracker) Live(needTunnel bool, timeout time.Duration) error {
+	if t == nil || timeout <= 0 {
+		return nil
+	}
+	if p, ok := t.Worst(); ok && p.Severity == SeverityFatal && t.now().Sub(p.Since) > timeout {
+		return fmt.Errorf("%s failing since %s: %s: %s", p.Subsystem, p.Since.Format(time.RFC3339), p.Reason, p.Message)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if needTunnel && t.tunnelEver && !t.tunnelUp && t.now().Sub(t.tunnelSince) > timeout {
+		return fmt.Errorf("tunnel to the hub down since %s", t.tunnelSince.Format(time.RFC3339))
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestClassify(t *testing.T) {
+	placements := schema.GroupResource{Group: "edges.kedge.faros.sh", Resource: "placements"}
+	tests := []struct {
+		name     string
+		err      error
+		severity Severity
+		reason   string
+	}{
+		{"unauthorized", apierrors.NewUnauthorized("token expired"), SeverityFatal, ReasonUnauthorized},
+		{"forbidden wrapped", fmt.Errorf("updating placement status: %w", apierrors.NewForbidden(placements, "web", errors.New("rbac"))), SeverityFatal, ReasonForbidden},
+		{"throttled", apierrors.NewTooManyRequests("slow down", 1), SeverityTransient, ReasonRateLimited},
+		{"server error", apierrors.NewInternalError(errors.New("etcd")), SeverityTransient, ReasonHubError},
+		{"conflict", apierrors.NewConflict(placements, "web", errors.New("stale")), SeverityTransient, ReasonError},
+		{"upgrade refused", &HTTPStatusError{Code: 401, Err: errors.New("bad handshake")}, SeverityFatal, ReasonUnauthorized},
+		{"version refused", &HTTPStatusError{Code: 426, Err: errors.New("bad handshake: unsupported agent version")}, SeverityFatal, ReasonUnsupportedVersion},
+		{"upgrade throttled", fmt.Errorf("dial: %w", &HTTPStatusError{Code: 429, Err: errors.New("bad handshake")}), SeverityTransient, ReasonRateLimited},
+		{"connection refused", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, SeverityTransient, ReasonHubUnreachable},
+		{"untrusted hub", fmt.Errorf("dial: %w", &tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}), SeverityFatal, ReasonTLSVerificationFailed},
+		{"edge cluster credential", &DownstreamAuthError{Err: apierrors.NewUnauthorized("token expired")}, SeverityFatal, ReasonDownstreamUnauthorized},
+		{"other", errors.New("decoding manifest"), SeverityTransient, ReasonError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			severity, reason := Classify(tt.err)
+			if severity != tt.severity || reason != tt.reason {
+				t.Fatalf("Classify = %s/%s, want %s/%s", severity, reason, tt.severity, tt.reason)
+			}
+		})
+	}
+}
+
+func TestTrackerWorst(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	tr := NewTracker()
+	tr.now = func() time.Time { return now }
+
+	if _, ok := tr.Worst(); ok {
+		t.Fatal("empty tracker reports a problem")
+	}
+
+	tr.Observe("tunnel", &net.OpError{Op: "dial", Err: errors.New("connection refused")})
+	now = now.Add(time.Minute)
+	tr.Observe("workloads/default/web", apierrors.NewInternalError(errors.New("etcd")))
+	if p, _ := tr.Worst(); p.Subsystem != "tunnel" {
+		t.Fatalf("worst = %s, want the longest-standing transient problem", p.Subsystem)
+	}
+
+	// A repeat of the same reason keeps its start time.
+	now = now.Add(time.Minute)
+	tr.Observe("tunnel", &net.OpError{Op: "dial", Err: errors.New("i/o timeout")})
+	if p, _ := tr.Worst(); p.Subsystem != "tunnel" || !p.Since.Equal(now.Add(-2*time.Minute)) {
+		t.Fatalf("worst = %s since %s", p.Subsystem, p.Since)
+	}
+
+	tr.Observe("heartbeat", apierrors.NewForbidden(schema.GroupResource{Resource: "kubernetesclusters"}, "edge", errors.New("rbac")))
+	p, ok := tr.Worst()
+	if !ok || p.Subsystem != "heartbeat" || p.Severity != SeverityFatal || p.Reason != ReasonForbidden {
+		t.Fatalf("worst = %+v, want the fatal heartbeat problem", p)
+	}
+
+	tr.Observe("heartbeat", nil)
+	tr.Observe("tunnel", context.Canceled)
+	tr.Observe("tunnel", nil)
+	if p, _ := tr.Worst(); p.Subsystem != "workloads/default/web" {
+		t.Fatalf("worst = %s after clearing, want the remaining problem", p.Subsystem)
+	}
+	tr.Observe("workloads/default/web", nil)
+	if _, ok := tr.Worst(); ok {
+		t.Fatal("problems remain after every subsystem recovered")
+	}
+
+	var nilTracker *Tracker
+	nilTracker.Observe("tunnel", errors.New("ignored"))
+	if _, ok := nilTracker.Worst(); ok {
+		t.Fatal("nil tracker reports a problem")
+	}
+}
+
+func TestTrackerProbes(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	tr := NewTracker()
+	tr.now = func() time.Time { return now }
+	const timeout = 10 * time.Minute
+
+	if err := tr.Ready(true); err == nil {
+		t.Error("ready before the tunnel connected")
+	}
+	if err := tr.Ready(false); err != nil {
+		t.Errorf("ready without a tunnel: %v", err)
+	}
+	now = now.Add(time.Hour)
+	if err := tr.Live(true, timeout); err != nil {
+		t.Errorf("not live while waiting for a first connection: %v", err)
+	}
+
+	tr.SetTunnelConnected(true)
+	tr.Observe("tunnel", &net.OpError{Op: "dial", Err: errors.New("connection refused")})
+	if err := tr.Ready(true); err != nil {
+		t.Errorf("transient problem made the agent unready: %v", err)
+	}
+
+	tr.SetTunnelConnected(false)
+	now = now.Add(timeout)
+	if err := tr.Live(true, timeout); err != nil {
+		t.Errorf("not live within the timeout: %v", err)
+	}
+	now = now.Add(time.Second)
+	if err := tr.Live(true, timeout); err == nil {
+		t.Error("live with the tunnel down past the timeout")
+	}
+	if err := tr.Live(true, 0); err != nil {
+		t.Errorf("zero timeout: %v", err)
+	}
+	tr.SetTunnelConnected(true)
+
+	tr.Observe("heartbeat", apierrors.NewUnauthorized("expired"))
+	if err := tr.Ready(true); err == nil || !strings.Contains(err.Error(), ReasonUnauthorized) {
+		t.Errorf("ready with a fatal problem: %v", err)
+	}
+	if err := tr.Live(true, timeout); err != nil {
+		t.Errorf("fatal problem killed the agent at once: %v", err)
+	}
+	now = now.Add(timeout + time.Second)
+	if err := tr.Live(true, timeout); err == nil {
+		t.Error("live with a fatal problem past the timeout")
+	}
+}
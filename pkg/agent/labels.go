@@ -0,0 +1,96 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"encoding/json"
+	"maps"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// lastAppliedLabelsAnnotation holds, as a JSON object, the labels the agent
+// last applied to its edge: the base of the three-way label merge.
+const lastAppliedLabelsAnnotation = "kedge.faros.sh/last-applied-labels"
+
+// applyEdgeLabels reconciles edge's labels with labels under a.opts.LabelPolicy
+// and records labels as the last applied. An edge without the annotation, or
+// with one that does not parse, is treated as never labelled by the agent.
+func (a *Agent) applyEdgeLabels(edge *unstructured.Unstructured, labels map[string]string) error {
+	annotations := edge.GetAnnotations()
+	var lastApplied map[string]string
+	if raw := annotations[lastAppliedLabelsAnnotation]; raw != "" {
+		if err := json.Unmarshal([]byte(raw), &lastApplied); err != nil {
+			lastApplied = nil
+		}
+	}
+	edge.SetLabels(reconcileLabels(a.opts.LabelPolicy, edge.GetLabels(), labels, lastApplied))
+
+	data, err := json.Marshal(labels)
+	if err != nil {
+		return err
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[lastAppliedLabelsAnnotation] = string(data)
+	edge.SetAnnotations(annotations)
+	return nil
+}
+
+// reconcileLabels returns the edge's labels after applying desired, the
+// agent's labels, to current, the edge's, under policy. lastApplied is what
+// the agent applied before; labels outside it and desired are the hub's and
+// are always kept.
+func reconcileLabels(policy LabelPolicy, current, desired, lastApplied map[string]string) map[string]string {
+	out := maps.Clone(current)
+	if out == nil {
+		out = map[string]string{}
+	}
+	switch policy {
+	case LabelPolicyReplace:
+		for k := range lastApplied {
+			if _, ok := desired[k]; !ok {
+				delete(out, k)
+			}
+		}
+		maps.Copy(out, desired)
+	case LabelPolicyHubWins:
+		for k, v := range desired {
+			_, applied := lastApplied[k]
+			if _, ok := current[k]; !ok && !applied {
+				out[k] = v
+			}
+		}
+	default:
+		// A label dropped by the agent is removed unless the hub has since
+		// changed it; a label the agent still has with the value it applied
+		// is left as the hub has it, removed or not.
+		for k, v := range lastApplied {
+			if _, ok := desired[k]; !ok && current[k] == v {
+				delete(out, k)
+			}
+		}
+		for k, v := range desired {
+			if last, ok := lastApplied[k]; ok && last == v {
+				continue
+			}
+			out[k] = v
+		}
+	}
+	return out
+}
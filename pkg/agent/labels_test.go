@@ -0,0 +1,90 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"maps"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestReconcileLabels(t *testing.T) {
+	// The agent applied site=berlin, tier=edge and gpu=yes. Since then the
+	// hub removed tier, changed gpu and added owner; the agent dropped gpu
+	// and tier, and now has site=munich and rack=r1.
+	current := map[string]string{"site": "berlin", "gpu": "no", "owner": "ops"}
+	lastApplied := map[string]string{"site": "berlin", "tier": "edge", "gpu": "yes"}
+	desired := map[string]string{"site": "munich", "rack": "r1"}
+
+	for _, tc := range []struct {
+		policy LabelPolicy
+		want   map[string]string
+	}{
+		// The hub's change to gpu stands though the agent dropped it.
+		{LabelPolicyMerge, map[string]string{"site": "munich", "rack": "r1", "gpu": "no", "owner": "ops"}},
+		{LabelPolicyReplace, map[string]string{"site": "munich", "rack": "r1", "owner": "ops"}},
+		{LabelPolicyHubWins, map[string]string{"site": "berlin", "rack": "r1", "gpu": "no", "owner": "ops"}},
+	} {
+		if got := reconcileLabels(tc.policy, current, desired, lastApplied); !maps.Equal(got, tc.want) {
+			t.Errorf("%s: labels = %v, want %v", tc.policy, got, tc.want)
+		}
+	}
+}
+
+func TestReconcileLabelsKeepsHubRemovals(t *testing.T) {
+	// The hub removed zone, which the agent still applies unchanged.
+	current := map[string]string{"site": "berlin"}
+	lastApplied := map[string]string{"site": "berlin", "zone": "a"}
+	desired := map[string]string{"site": "berlin", "zone": "a"}
+
+	for policy, wantZone := range map[LabelPolicy]bool{
+		LabelPolicyMerge:   false,
+		LabelPolicyReplace: true,
+		LabelPolicyHubWins: false,
+	} {
+		if _, ok := reconcileLabels(policy, current, desired, lastApplied)["zone"]; ok != wantZone {
+			t.Errorf("%s: zone restored = %v, want %v", policy, ok, wantZone)
+		}
+	}
+
+	// Without a record of what was applied, e.g. for an edge an older agent
+	// labelled, every label the agent has is applied.
+	if got := reconcileLabels(LabelPolicyMerge, current, desired, nil); !maps.Equal(got, desired) {
+		t.Errorf("merge without last applied = %v, want %v", got, desired)
+	}
+}
+
+func TestApplyEdgeLabels(t *testing.T) {
+	a := &Agent{opts: &Options{LabelPolicy: LabelPolicyMerge}}
+	edge := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	if err := a.applyEdgeLabels(edge, map[string]string{"site": "berlin", "zone": "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := edge.GetAnnotations()[lastAppliedLabelsAnnotation]; got != `{"site":"berlin","zone":"a"}` {
+		t.Errorf("last applied = %s", got)
+	}
+
+	// Removed on the hub, zone is not restored on the next registration.
+	edge.SetLabels(map[string]string{"site": "berlin"})
+	if err := a.applyEdgeLabels(edge, map[string]string{"site": "berlin", "zone": "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := edge.GetLabels(); !maps.Equal(got, map[string]string{"site": "berlin"}) {
+		t.Errorf("labels = %v, want the hub's removal kept", got)
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Location is where the agent's edge sits, as given on the command line. The
+// agent fills the edge's spec.location with it only while that is empty, so a
+// location set when the edge was created always wins.
+type Location struct {
+	// Coordinates is "<latitude>,<longitude>" in decimal degrees.
+	Coordinates string
+	// Address is a free-form postal address or site name.
+	Address string
+	// Region is a coarse grouping such as "eu-west".
+	Region string
+}
+
+// Spec returns l as the edge's spec.location, or nil when l is empty.
+func (l Location) Spec() (map[string]interface{}, error) {
+	spec := map[string]interface{}{}
+	if l.Coordinates != "" {
+		lat, lon, err := ParseCoordinates(l.Coordinates)
+		if err != nil {
+			return nil, err
+		}
+		spec["latitude"] = lat
+		spec["longitude"] = lon
+	}
+	if l.Address != "" {
+		spec["address"] = l.Address
+	}
+	if l.Region != "" {
+		spec["region"] = l.Region
+	}
+	if len(spec) == 0 {
+		return nil, nil
+	}
+	return spec, nil
+}
+
+// ParseCoordinates parses "<latitude>,<longitude>" in decimal degrees.
+func ParseCoordinates(s string) (lat, lon float64, err error) {
+	latStr, lonStr, ok := strings.Cut(s, ",")
+	if !ok {
+		return 0, 0, fmt.Errorf("coordinates %q must be \"<latitude>,<longitude>\"", s)
+	}
+	if lat, err = strconv.ParseFloat(strings.TrimSpace(latStr), 64); err != nil || lat < -90 || lat > 90 {
+		return 0, 0, fmt.Errorf("latitude %q must be a number between -90 and 90", latStr)
+	}
+	if lon, err = strconv.ParseFloat(strings.TrimSpace(lonStr), 64); err != nil || lon < -180 || lon > 180 {
+		return 0, 0, fmt.Errorf("longitude %q must be a number between -180 and 180", lonStr)
+	}
+	return lat, lon, nil
+}
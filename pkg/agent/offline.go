@@ -0,0 +1,367 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/faroshq/faros-kedge/pkg/agent/bundle"
+	agentReconciler "github.com/faroshq/faros-kedge/pkg/agent/reconciler"
+	agentStatus "github.com/faroshq/faros-kedge/pkg/agent/status"
+	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+)
+
+// bundlePollInterval is how often an offline agent checks its placement
+// bundle for a replacement and records what it applied.
+const bundlePollInterval = 30 * time.Second
+
+// offlineStateFile, in the agent's directory, records what an offline agent
+// applied, for the agent to compare with the hub once it is back online.
+const offlineStateFile = "offline-state.json"
+
+// bundleGVRs are the hub resources a placement bundle may carry, with the
+// list kinds the in-memory hub serves them under.
+var bundleGVRs = map[schema.GroupVersionResource]string{
+	kedgeclient.KubernetesClusterGVR: "KubernetesClusterList",
+	kedgeclient.PlacementGVR:         "PlacementList",
+	kedgeclient.WorkloadGVR:          "WorkloadList",
+}
+
+// offlineState is the content of offlineStateFile.
+type offlineState struct {
+	// Bundle is the digest of the bundle last loaded.
+	Bundle     string             `json:"bundle"`
+	Exported   time.Time          `json:"exported"`
+	Updated    time.Time          `json:"updated"`
+	Placements []offlinePlacement `json:"placements"`
+}
+
+// offlinePlacement is one Placement of the bundle and how far the agent got
+// applying it.
+type offlinePlacement struct {
+	Namespace          string    `json:"namespace"`
+	Name               string    `json:"name"`
+	UID                types.UID `json:"uid"`
+	Generation         int64     `json:"generation"`
+	ObservedGeneration int64     `json:"observedGeneration"`
+	Phase              string    `json:"phase,omitempty"`
+}
+
+// bundleSource serves the objects of a placement bundle file through an
+// in-memory hub, so the workload reconciler and status reporters run
+// unchanged: they read Placements and write their status as they would on
+// the hub.
+type bundleSource struct {
+	path     string
+	edgeName string
+	key      ed25519.PublicKey
+	hub      dynamic.Interface
+
+	// digest and exported identify the bundle last loaded.
+	digest   string
+	exported time.Time
+}
+
+func newBundleSource(path, edgeName string, key ed25519.PublicKey) *bundleSource {
+	return &bundleSource{
+		path:     path,
+		edgeName: edgeName,
+		key:      key,
+		hub:      dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), bundleGVRs),
+	}
+}
+
+// load reads the bundle file and, when it differs from the bundle loaded
+// last, makes the in-memory hub hold its objects. It reports whether it did.
+// A bundle that fails verification leaves the previous one in place.
+func (s *bundleSource) load(ctx context.Context) (bool, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return false, err
+	}
+	b, digest, err := bundle.Open(data, s.key)
+	if err != nil {
+		return false, err
+	}
+	if digest == s.digest {
+		return false, nil
+	}
+	if b.Edge != s.edgeName {
+		return false, fmt.Errorf("bundle is for edge %q, not %q", b.Edge, s.edgeName)
+	}
+
+	want := make(map[schema.GroupVersionResource]map[string]*unstructured.Unstructured, len(bundleGVRs))
+	for gvr := range bundleGVRs {
+		want[gvr] = map[string]*unstructured.Unstructured{}
+	}
+	for i := range b.Objects {
+		obj := &b.Objects[i]
+		gvr, err := s.resourceFor(obj)
+		if err != nil {
+			return false, err
+		}
+		want[gvr][obj.GetNamespace()+"/"+obj.GetName()] = obj
+	}
+	for gvr, objs := range want {
+		if err := s.sync(ctx, gvr, objs); err != nil {
+			return false, err
+		}
+	}
+	s.digest, s.exported = digest, b.Created
+	return true, nil
+}
+
+// resourceFor returns the resource of a bundle object, refusing objects the
+// agent would not read from the hub.
+func (s *bundleSource) resourceFor(obj *unstructured.Unstructured) (schema.GroupVersionResource, error) {
+	gvk := obj.GroupVersionKind()
+	if gvk.Group == kedgeclient.PlacementGVR.Group {
+		switch gvk.Kind {
+		case "KubernetesCluster":
+			if obj.GetName() == s.edgeName {
+				return kedgeclient.KubernetesClusterGVR, nil
+			}
+		case "Placement":
+			if edge, _, _ := unstructured.NestedString(obj.Object, "spec", "edgeName"); edge == s.edgeName {
+				return kedgeclient.PlacementGVR, nil
+			}
+		case "Workload":
+			return kedgeclient.WorkloadGVR, nil
+		}
+	}
+	return schema.GroupVersionResource{}, fmt.Errorf("bundle holds %s %s/%s, which is not for edge %q",
+		gvk.Kind, obj.GetNamespace(), obj.GetName(), s.edgeName)
+}
+
+// sync makes the in-memory hub's gvr objects those in want. Objects already
+// held keep the status the agent wrote, unless they were recreated on the
+// hub since.
+func (s *bundleSource) sync(ctx context.Context, gvr schema.GroupVersionResource, want map[string]*unstructured.Unstructured) error {
+	list, err := s.hub.Resource(gvr).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	have := make(map[string]*unstructured.Unstructured, len(list.Items))
+	for i := range list.Items {
+		obj := &list.Items[i]
+		key := obj.GetNamespace() + "/" + obj.GetName()
+		if _, ok := want[key]; !ok {
+			if err := s.hub.Resource(gvr).Namespace(obj.GetNamespace()).Delete(ctx, obj.GetName(), metav1.DeleteOptions{}); err != nil {
+				return err
+			}
+			continue
+		}
+		have[key] = obj
+	}
+	for key, obj := range want {
+		// The hub's resource versions mean nothing to the in-memory one.
+		obj.SetResourceVersion("")
+		ri := s.hub.Resource(gvr).Namespace(obj.GetNamespace())
+		old, ok := have[key]
+		if !ok {
+			if _, err := ri.Create(ctx, obj, metav1.CreateOptions{}); err != nil {
+				return err
+			}
+			continue
+		}
+		if old.GetUID() == obj.GetUID() {
+			if status, found := old.Object["status"]; found {
+				obj.Object["status"] = status
+			}
+		}
+		if _, err := ri.Update(ctx, obj, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeState records the bundle's Placements and what the agent applied
+// of them in path.
+func (s *bundleSource) writeState(ctx context.Context, path string) error {
+	list, err := s.hub.Resource(kedgeclient.PlacementGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	state := offlineState{Bundle: s.digest, Exported: s.exported, Updated: time.Now().UTC()}
+	for _, p := range list.Items {
+		observed, _, _ := unstructured.NestedInt64(p.Object, "status", "observedGeneration")
+		phase, _, _ := unstructured.NestedString(p.Object, "status", "phase")
+		state.Placements = append(state.Placements, offlinePlacement{
+			Namespace:          p.GetNamespace(),
+			Name:               p.GetName(),
+			UID:                p.GetUID(),
+			Generation:         p.GetGeneration(),
+			ObservedGeneration: observed,
+			Phase:              phase,
+		})
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// offlineStatePath returns where the agent for edgeName keeps its offline
+// state.
+func offlineStatePath(edgeName string) (string, error) {
+	dir, err := agentKeyDir(edgeName)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, offlineStateFile), nil
+}
+
+// runOfflineMode runs a kubernetes-type agent with no hub: it applies the
+// Placements of the signed bundle in Options.PlacementBundle exactly as it
+// would hub-delivered ones, picks up a replaced bundle file, and records
+// what it applied in the agent's offline state for when the hub is
+// reachable again (see compareOfflineState).
+func (a *Agent) runOfflineMode(ctx context.Context, logger klog.Logger) error {
+	key, err := bundle.ReadPublicKey(a.opts.PlacementBundlePublicKey)
+	if err != nil {
+		return fmt.Errorf("reading placement bundle public key: %w", err)
+	}
+	statePath, err := offlineStatePath(a.opts.EdgeName)
+	if err != nil {
+		return fmt.Errorf("resolving offline state path: %w", err)
+	}
+	src := newBundleSource(a.opts.PlacementBundle, a.opts.EdgeName, key)
+	if _, err := src.load(ctx); err != nil {
+		return fmt.Errorf("loading placement bundle %s: %w", a.opts.PlacementBundle, err)
+	}
+	logger.Info("Running offline from a placement bundle",
+		"bundle", a.opts.PlacementBundle, "digest", src.digest, "exported", src.exported)
+
+	downstream, err := kubernetes.NewForConfig(a.downstreamConfig)
+	if err != nil {
+		return fmt.Errorf("building downstream client: %w", err)
+	}
+	wr, err := agentReconciler.NewWorkloadReconciler(a.opts.EdgeName, src.hub, a.downstreamConfig)
+	if err != nil {
+		return fmt.Errorf("building workload reconciler: %w", err)
+	}
+	wr.SetHealth(a.health)
+	wr.SetApplyConcurrency(a.opts.ApplyConcurrency)
+	if err := wr.SetGitOps(a.opts.GitOps, a.opts.GitOpsNamespace); err != nil {
+		logger.Error(err, "GitOps handoff disabled; placements are applied directly")
+	}
+	go func() {
+		if err := wr.Run(ctx); err != nil {
+			logger.Error(err, "workload reconciler failed")
+		}
+	}()
+
+	factory := informers.NewSharedInformerFactory(downstream, 10*time.Minute)
+	pr := agentStatus.NewPlacementReporter(src.hub, factory)
+	pr.SetHealth(a.health)
+	factory.Start(ctx.Done())
+	go func() {
+		if err := pr.Run(ctx, 2); err != nil {
+			logger.Error(err, "placement status reporter failed")
+		}
+	}()
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if changed, err := src.load(ctx); err != nil {
+			logger.Error(err, "Ignoring placement bundle; keeping the one loaded", "digest", src.digest)
+		} else if changed {
+			logger.Info("Loaded replaced placement bundle", "digest", src.digest, "exported", src.exported)
+		}
+		if err := src.writeState(ctx, statePath); err != nil {
+			logger.Error(err, "Recording offline state failed", "path", statePath)
+		}
+	}, bundlePollInterval)
+
+	if err := src.writeState(context.WithoutCancel(ctx), statePath); err != nil {
+		logger.Error(err, "Recording offline state failed", "path", statePath)
+	}
+	return nil
+}
+
+// compareOfflineState runs once the agent reaches the hub: it logs how the
+// Placements an earlier offline run applied differ from the hub's, then
+// drops the offline state. The workload reconciler brings the edge to the
+// hub's Placements; objects of Placements the hub no longer has are removed
+// by its orphan sweep.
+func (a *Agent) compareOfflineState(ctx context.Context, logger klog.Logger, hub dynamic.Interface) {
+	path, err := offlineStatePath(a.opts.EdgeName)
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Error(err, "Reading offline state failed", "path", path)
+		}
+		return
+	}
+	var state offlineState
+	if err := json.Unmarshal(data, &state); err != nil {
+		logger.Error(err, "Ignoring unreadable offline state", "path", path)
+		_ = os.Remove(path)
+		return
+	}
+
+	logger.Info("Reconciling placements applied offline against the hub",
+		"bundle", state.Bundle, "exported", state.Exported, "placements", len(state.Placements))
+	for _, p := range state.Placements {
+		pl := logger.WithValues("placement", p.Namespace+"/"+p.Name, "offlineGeneration", p.ObservedGeneration)
+		hp, err := hub.Resource(kedgeclient.PlacementGVR).Namespace(p.Namespace).Get(ctx, p.Name, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			pl.Info("Placement applied offline was deleted on the hub; its objects will be pruned")
+		case err != nil:
+			// The reconciler reads the hub on its own; this is reporting only.
+			pl.Error(err, "Comparing offline placement with the hub failed")
+		case hp.GetUID() != p.UID:
+			pl.Info("Placement applied offline was replaced on the hub; applying the hub's")
+		case hp.GetGeneration() != p.ObservedGeneration:
+			pl.Info("Hub has another revision of a placement applied offline; applying it", "hubGeneration", hp.GetGeneration())
+		}
+	}
+	if err := os.Remove(path); err != nil {
+		logger.Error(err, "Removing offline state failed", "path", path)
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/faroshq/faros-kedge/pkg/agent/bundle"
+	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+)
+
+func bundlePlacement(name, edge string, uid types.UID, generation int64) unstructured.Unstructured {
+	p := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "edges.kedge.faros.sh/v1alpha1",
+		"kind":       "Placement",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "default", "uid": string(uid)},
+		"spec":       map[string]interface{}{"edgeName": edge},
+	}}
+	p.SetGeneration(generation)
+	return p
+}
+
+// TestBundleSource checks that a bundle's objects land in the in-memory hub,
+// that a replaced bundle keeps the status the agent wrote and drops removed
+// placements, and that foreign or unsigned bundles are refused.
+func TestBundleSource(t *testing.T) {
+	ctx := context.Background()
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	path := filepath.Join(t.TempDir(), "bundle.json")
+	write := func(b *bundle.Bundle, key ed25519.PrivateKey) {
+		t.Helper()
+		data, err := bundle.Sign(b, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(&bundle.Bundle{Edge: "store-a", Created: time.Now(), Objects: []unstructured.Unstructured{
+		bundlePlacement("web", "store-a", "uid-web", 1),
+		bundlePlacement("db", "store-a", "uid-db", 1),
+	}}, priv)
+	src := newBundleSource(path, "store-a", pub)
+	if changed, err := src.load(ctx); err != nil || !changed {
+		t.Fatalf("load = %v, %v; want true, nil", changed, err)
+	}
+	if changed, err := src.load(ctx); err != nil || changed {
+		t.Fatalf("reload of the same bundle = %v, %v; want false, nil", changed, err)
+	}
+
+	// The reconciler records what it applied.
+	placements := src.hub.Resource(kedgeclient.PlacementGVR).Namespace("default")
+	if _, err := placements.Patch(ctx, "web", types.MergePatchType, []byte(`{"status":{"observedGeneration":1}}`), metav1.PatchOptions{}, "status"); err != nil {
+		t.Fatal(err)
+	}
+
+	write(&bundle.Bundle{Edge: "store-a", Created: time.Now(), Objects: []unstructured.Unstructured{
+		bundlePlacement("web", "store-a", "uid-web", 2),
+	}}, priv)
+	if changed, err := src.load(ctx); err != nil || !changed {
+		t.Fatalf("load of the replaced bundle = %v, %v; want true, nil", changed, err)
+	}
+	web, err := placements.Get(ctx, "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if observed, _, _ := unstructured.NestedInt64(web.Object, "status", "observedGeneration"); web.GetGeneration() != 2 || observed != 1 {
+		t.Errorf("web: generation %d, observedGeneration %d; want 2, 1", web.GetGeneration(), observed)
+	}
+	if _, err := placements.Get(ctx, "db", metav1.GetOptions{}); err == nil {
+		t.Error("placement dropped from the bundle is still served")
+	}
+
+	statePath := filepath.Join(t.TempDir(), "agents", "store-a", offlineStateFile)
+	if err := src.writeState(ctx, statePath); err != nil {
+		t.Fatalf("writeState: %v", err)
+	}
+	var state offlineState
+	data, _ := os.ReadFile(statePath)
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatal(err)
+	}
+	if state.Bundle != src.digest || len(state.Placements) != 1 || state.Placements[0].UID != "uid-web" || state.Placements[0].ObservedGeneration != 1 {
+		t.Errorf("offline state = %+v", state)
+	}
+
+	for name, b := range map[string]*bundle.Bundle{
+		"other edge":           {Edge: "store-b", Objects: []unstructured.Unstructured{bundlePlacement("web", "store-b", "u", 1)}},
+		"other edge placement": {Edge: "store-a", Objects: []unstructured.Unstructured{bundlePlacement("web", "store-b", "u", 1)}},
+	} {
+		write(b, priv)
+		if _, err := src.load(ctx); err == nil {
+			t.Errorf("%s: bundle loaded", name)
+		}
+	}
+	_, otherKey, _ := ed25519.GenerateKey(nil)
+	write(&bundle.Bundle{Edge: "store-a"}, otherKey)
+	if _, err := src.load(ctx); err == nil {
+		t.Error("bundle signed with another key loaded")
+	}
+	if _, err := placements.Get(ctx, "web", metav1.GetOptions{}); err != nil {
+		t.Errorf("refused bundle replaced the loaded one: %v", err)
+	}
+}
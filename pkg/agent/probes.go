@@ -0,0 +1,96 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// DefaultLivenessTimeout is how long the tunnel may stay down, or a
+// subsystem fail fatally, before /healthz fails by default.
+const DefaultLivenessTimeout = 15 * time.Minute
+
+// ServeProbes serves /healthz and /readyz for orchestrators on addr until
+// ctx is done, covering every agent of the process:
+//
+//   - /readyz fails while an agent's tunnel to the hub is not connected or a
+//     subsystem fails fatally (bad credentials, RBAC, TLS trust).
+//   - /healthz fails once an agent's tunnel has been down, or a subsystem
+//     has failed fatally, for longer than its LivenessTimeout, so a restart
+//     picks up replaced credentials or clears a wedged connection.
+//
+// Failing probes answer 503 with one line per failing edge.
+func ServeProbes(ctx context.Context, addr string, agents ...*Agent) error {
+	logger := klog.FromContext(ctx)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", probeHandler(agents, (*Agent).live))
+	mux.HandleFunc("/readyz", probeHandler(agents, (*Agent).ready))
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		_ = server.Shutdown(context.Background())
+	}()
+
+	logger.Info("Starting health probe server (healthz + readyz)", "addr", addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("health probe server on %s: %w", addr, err)
+	}
+	return nil
+}
+
+func probeHandler(agents []*Agent, check func(*Agent) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		var failures []string
+		for _, a := range agents {
+			if err := check(a); err != nil {
+				failures = append(failures, fmt.Sprintf("edge %s: %v", a.opts.EdgeName, err))
+			}
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if len(failures) > 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(strings.Join(failures, "\n") + "\n"))
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}
+}
+
+// needsTunnel is false for offline agents, which never connect to the hub.
+func (a *Agent) needsTunnel() bool {
+	return a.opts.PlacementBundle == ""
+}
+
+func (a *Agent) ready() error {
+	return a.health.Ready(a.needsTunnel())
+}
+
+func (a *Agent) live() error {
+	return a.health.Live(a.needsTunnel(), a.opts.LivenessTimeout)
+}
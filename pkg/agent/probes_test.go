@@ -0,0 +1,47 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/faroshq/faros-kedge/pkg/agent/health"
+)
+
+func TestReadyzProbe(t *testing.T) {
+	connected := &Agent{opts: &Options{EdgeName: "store-a"}, health: health.NewTracker()}
+	connected.health.SetTunnelConnected(true)
+	offline := &Agent{opts: &Options{EdgeName: "store-b", PlacementBundle: "bundle.json"}, health: health.NewTracker()}
+	waiting := &Agent{opts: &Options{EdgeName: "store-c"}, health: health.NewTracker()}
+
+	probe := func(agents ...*Agent) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		probeHandler(agents, (*Agent).ready)(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		return rec
+	}
+
+	if rec := probe(connected, offline); rec.Code != http.StatusOK {
+		t.Errorf("connected and offline agents: status %d: %s", rec.Code, rec.Body)
+	}
+	rec := probe(connected, waiting)
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), "edge store-c:") || strings.Contains(rec.Body.String(), "store-a") {
+		t.Errorf("agent without a tunnel: status %d: %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,386 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+)
+
+// conditionWithinBudget is the Placement condition the agent sets while the
+// edge has a spec.workloadBudget: True when the Placement fits and was
+// applied, False (reason BudgetExceeded) when it was refused.
+const conditionWithinBudget = "WithinBudget"
+
+// budgetResources are the resources a workload budget can cap, in the order
+// they are reported.
+var budgetResources = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}
+
+// workloadBudget mirrors KubernetesCluster.spec.workloadBudget.
+type workloadBudget struct {
+	CPU    *resource.Quantity `json:"cpu,omitempty"`
+	Memory *resource.Quantity `json:"memory,omitempty"`
+}
+
+// limits returns the budget as a ResourceList holding only the capped
+// resources.
+func (b *workloadBudget) limits() corev1.ResourceList {
+	out := corev1.ResourceList{}
+	if b.CPU != nil {
+		out[corev1.ResourceCPU] = *b.CPU
+	}
+	if b.Memory != nil {
+		out[corev1.ResourceMemory] = *b.Memory
+	}
+	return out
+}
+
+// budgetEntry is one Placement's claim on the budget.
+type budgetEntry struct {
+	key      string
+	created  metav1.Time
+	requests corev1.ResourceList
+}
+
+// admitPlacements walks entries oldest first (ties broken by key) and admits
+// each one that still fits in budget next to the ones admitted before it.
+// Ordering by age keeps running placements admitted when a newer one would
+// overflow the budget. It returns whether target was admitted and what was
+// left of the budget when its turn came.
+func admitPlacements(budget corev1.ResourceList, entries []budgetEntry, target string) (bool, corev1.ResourceList) {
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].created.Equal(&entries[j].created) {
+			return entries[i].created.Before(&entries[j].created)
+		}
+		return entries[i].key < entries[j].key
+	})
+	used := corev1.ResourceList{}
+	for _, e := range entries {
+		fits := true
+		for name, limit := range budget {
+			total := used[name].DeepCopy()
+			total.Add(e.requests[name])
+			if total.Cmp(limit) > 0 {
+				fits = false
+				break
+			}
+		}
+		if e.key == target {
+			return fits, remaining(budget, used)
+		}
+		if fits {
+			addRequests(used, e.requests, 1)
+		}
+	}
+	return true, remaining(budget, used)
+}
+
+// remaining returns budget minus used, floored at zero.
+func remaining(budget, used corev1.ResourceList) corev1.ResourceList {
+	out := corev1.ResourceList{}
+	for name, limit := range budget {
+		left := limit.DeepCopy()
+		left.Sub(used[name])
+		if left.Sign() < 0 {
+			left = resource.Quantity{Format: limit.Format}
+		}
+		out[name] = left
+	}
+	return out
+}
+
+// enforceBudget checks placement against the edge's spec.workloadBudget and
+// records the outcome in the Placement's WithinBudget condition. It returns
+// false when the placement must not be applied. Without a budget every
+// placement fits and a leftover condition is removed.
+func (r *WorkloadReconciler) enforceBudget(ctx context.Context, pu *unstructured.Unstructured, placement *placementView) (bool, error) {
+	budget, err := r.edgeBudget(ctx)
+	if err != nil {
+		return false, err
+	}
+	if budget == nil || len(budget.limits()) == 0 {
+		if meta.FindStatusCondition(placement.Status.Conditions, conditionWithinBudget) == nil {
+			return true, nil
+		}
+		conditions := append([]metav1.Condition(nil), placement.Status.Conditions...)
+		meta.RemoveStatusCondition(&conditions, conditionWithinBudget)
+		return true, r.patchPlacementStatus(ctx, placement, conditions, "")
+	}
+
+	requests, err := r.placementRequests(ctx, placement)
+	if err != nil {
+		return false, err
+	}
+	entries := []budgetEntry{{key: pu.GetNamespace() + "/" + pu.GetName(), created: placement.CreationTimestamp, requests: requests}}
+	for _, other := range r.edgePlacements(placement.UID) {
+		otherRequests, err := r.placementRequests(ctx, other)
+		if err != nil {
+			// An undecodable placement fails to apply anyway; it holds no
+			// share of the budget.
+			klog.FromContext(ctx).V(2).Info("Ignoring placement in budget", "placement", other.Name, "err", err)
+			continue
+		}
+		entries = append(entries, budgetEntry{key: other.Namespace + "/" + other.Name, created: other.CreationTimestamp, requests: otherRequests})
+	}
+	limits := budget.limits()
+	fits, left := admitPlacements(limits, entries, entries[0].key)
+
+	cond := metav1.Condition{
+		Type:               conditionWithinBudget,
+		Status:             metav1.ConditionTrue,
+		Reason:             "WithinBudget",
+		Message:            fmt.Sprintf("Requests %s of the edge's workload budget %s.", formatResources(requests), formatResources(limits)),
+		ObservedGeneration: placement.Generation,
+	}
+	phase := ""
+	if !fits {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "BudgetExceeded"
+		cond.Message = fmt.Sprintf("Requests %s but only %s of the edge's workload budget %s is left; not applied.",
+			formatResources(requests), formatResources(left), formatResources(limits))
+		phase = "Failed"
+		klog.FromContext(ctx).Info("Refusing placement over the edge's workload budget",
+			"placement", placement.Name, "requests", formatResources(requests), "left", formatResources(left))
+	} else if prev := meta.FindStatusCondition(placement.Status.Conditions, conditionWithinBudget); prev != nil && prev.Status == metav1.ConditionFalse {
+		// Admitted after being refused: the status reporter takes the phase
+		// from here once the workload runs.
+		phase = "Pending"
+	}
+
+	conditions := append([]metav1.Condition(nil), placement.Status.Conditions...)
+	if !meta.SetStatusCondition(&conditions, cond) && phase == "" {
+		return fits, nil
+	}
+	return fits, r.patchPlacementStatus(ctx, placement, conditions, phase)
+}
+
+// edgeBudget reads spec.workloadBudget from the agent's own edge.
+func (r *WorkloadReconciler) edgeBudget(ctx context.Context) (*workloadBudget, error) {
+	edge, err := r.hubDynamic.Resource(kedgeclient.KubernetesClusterGVR).Get(ctx, r.edgeName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("getting edge %q: %w", r.edgeName, err)
+	}
+	raw, found, _ := unstructured.NestedMap(edge.Object, "spec", "workloadBudget")
+	if !found {
+		return nil, nil
+	}
+	var b workloadBudget
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &b); err != nil {
+		return nil, fmt.Errorf("decoding workloadBudget of edge %q: %w", r.edgeName, err)
+	}
+	return &b, nil
+}
+
+// edgePlacements returns this edge's live Placements from the informer
+// cache, except the one with UID skip.
+func (r *WorkloadReconciler) edgePlacements(skip types.UID) []*placementView {
+	if r.placements == nil {
+		return nil
+	}
+	var out []*placementView
+	for _, obj := range r.placements.List() {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok || u.GetUID() == skip || u.GetDeletionTimestamp() != nil {
+			continue
+		}
+		var p placementView
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &p); err != nil {
+			continue
+		}
+		if p.Spec.EdgeName == r.edgeName {
+			out = append(out, &p)
+		}
+	}
+	return out
+}
+
+// enqueueRefusedPlacements requeues the placements the budget refused, so
+// capacity freed by a deleted placement is handed out again.
+func (r *WorkloadReconciler) enqueueRefusedPlacements() {
+	for _, p := range r.edgePlacements("") {
+		if meta.IsStatusConditionFalse(p.Status.Conditions, conditionWithinBudget) {
+			r.queue.Add(p.Namespace + "/" + p.Name)
+		}
+	}
+}
+
+// patchPlacementStatus writes conditions, and phase when non-empty, to the
+// Placement's status. The conditions list is replaced whole.
+func (r *WorkloadReconciler) patchPlacementStatus(ctx context.Context, placement *placementView, conditions []metav1.Condition, phase string) error {
+	status := map[string]interface{}{"conditions": conditions}
+	if phase != "" {
+		status["phase"] = phase
+	}
+	patch, err := json.Marshal(map[string]interface{}{"status": status})
+	if err != nil {
+		return fmt.Errorf("marshaling placement status patch: %w", err)
+	}
+	if _, err := r.hubDynamic.Resource(placementGVR).Namespace(placement.Namespace).Patch(
+		ctx, placement.Name, types.MergePatchType, patch, metav1.PatchOptions{}, "status",
+	); err != nil {
+		return fmt.Errorf("updating status of placement %s: %w", placement.Name, err)
+	}
+	// Later patches in the same reconcile start from what was written here.
+	placement.Status.Conditions = conditions
+	return nil
+}
+
+// placementRequests returns the CPU and memory a Placement asks of the edge:
+// its rendered bundle's (inline or stored), or for legacy placements, the
+// synthesized Deployment's.
+func (r *WorkloadReconciler) placementRequests(ctx context.Context, placement *placementView) (corev1.ResourceList, error) {
+	if err := r.resolveManifests(ctx, placement); err != nil {
+		return nil, err
+	}
+	if len(placement.Spec.Manifests) > 0 {
+		return manifestRequests(placement.Spec.Manifests)
+	}
+	ref := placement.Spec.WorkloadRef
+	vu, err := r.hubDynamic.Resource(workloadGVR).Namespace(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("getting Workload %s/%s: %w", ref.Namespace, ref.Name, err)
+	}
+	var vw workloadView
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(vu.Object, &vw); err != nil {
+		return nil, fmt.Errorf("decoding Workload %s/%s: %w", ref.Namespace, ref.Name, err)
+	}
+	deployment, err := convertToDeployment(&vw, placement)
+	if err != nil {
+		return nil, err
+	}
+	total := corev1.ResourceList{}
+	addRequests(total, podRequests(&deployment.Spec.Template.Spec), int64(*deployment.Spec.Replicas))
+	return total, nil
+}
+
+// manifestRequests sums the pod requests of the workload objects in a
+// rendered bundle, times their replica count. A DaemonSet counts once: the
+// agent cannot tell how many nodes it will land on.
+func manifestRequests(manifests []runtime.RawExtension) (corev1.ResourceList, error) {
+	total := corev1.ResourceList{}
+	for i, raw := range manifests {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(raw.Raw); err != nil {
+			return nil, fmt.Errorf("decoding manifest[%d]: %w", i, err)
+		}
+		var templatePath []string
+		var replicasPath []string
+		switch gk := obj.GroupVersionKind().GroupKind(); gk.String() {
+		case "Deployment.apps", "StatefulSet.apps", "ReplicaSet.apps":
+			templatePath, replicasPath = []string{"spec", "template", "spec"}, []string{"spec", "replicas"}
+		case "DaemonSet.apps":
+			templatePath = []string{"spec", "template", "spec"}
+		case "Job.batch":
+			templatePath, replicasPath = []string{"spec", "template", "spec"}, []string{"spec", "parallelism"}
+		case "CronJob.batch":
+			templatePath = []string{"spec", "jobTemplate", "spec", "template", "spec"}
+			replicasPath = []string{"spec", "jobTemplate", "spec", "parallelism"}
+		case "Pod":
+			templatePath = []string{"spec"}
+		default:
+			continue
+		}
+		rawSpec, found, err := unstructured.NestedMap(obj.Object, templatePath...)
+		if err != nil || !found {
+			continue
+		}
+		var spec corev1.PodSpec
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawSpec, &spec); err != nil {
+			return nil, fmt.Errorf("decoding pod spec of %s %q: %w", obj.GetKind(), obj.GetName(), err)
+		}
+		replicas := int64(1)
+		if replicasPath != nil {
+			if n, found, _ := unstructured.NestedInt64(obj.Object, replicasPath...); found {
+				replicas = n
+			}
+		}
+		addRequests(total, podRequests(&spec), replicas)
+	}
+	return total, nil
+}
+
+// podRequests returns a pod's effective CPU and memory requests the way the
+// scheduler sees them: the larger of the app containers' sum and the biggest
+// init container, plus pod overhead. A container without a request is
+// counted at its limit, which is what the API server defaults the request to.
+func podRequests(spec *corev1.PodSpec) corev1.ResourceList {
+	containerRequest := func(c *corev1.Container, name corev1.ResourceName) resource.Quantity {
+		if q, ok := c.Resources.Requests[name]; ok {
+			return q
+		}
+		return c.Resources.Limits[name]
+	}
+	out := corev1.ResourceList{}
+	for _, name := range budgetResources {
+		var sum resource.Quantity
+		for i := range spec.Containers {
+			sum.Add(containerRequest(&spec.Containers[i], name))
+		}
+		for i := range spec.InitContainers {
+			if q := containerRequest(&spec.InitContainers[i], name); q.Cmp(sum) > 0 {
+				sum = q
+			}
+		}
+		if q, ok := spec.Overhead[name]; ok {
+			sum.Add(q)
+		}
+		if !sum.IsZero() {
+			out[name] = sum
+		}
+	}
+	return out
+}
+
+// addRequests adds n times add to total.
+func addRequests(total, add corev1.ResourceList, n int64) {
+	for name, q := range add {
+		for i := int64(0); i < n; i++ {
+			sum := total[name]
+			sum.Add(q)
+			total[name] = sum
+		}
+	}
+}
+
+// formatResources renders the budget resources present in list as "cpu=2,
+// memory=4Gi".
+func formatResources(list corev1.ResourceList) string {
+	parts := make([]string, 0, len(budgetResources))
+	for _, name := range budgetResources {
+		if q, ok := list[name]; ok {
+			parts = append(parts, string(name)+"="+q.String())
+		}
+	}
+	if len(parts) == 0 {
+		return "nothing"
+	}
+	return strings.Join(parts, ", ")
+}
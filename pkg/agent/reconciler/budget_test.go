@@ -0,0 +1,98 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestManifestRequests(t *testing.T) {
+	manifests := []runtime.RawExtension{
+		{Raw: []byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web"},"spec":{"replicas":3,"template":{"spec":{"containers":[
+			{"name":"app","resources":{"requests":{"cpu":"250m","memory":"128Mi"}}},
+			{"name":"sidecar","resources":{"limits":{"cpu":"100m","memory":"64Mi"}}}]}}}}`)},
+		{Raw: []byte(`{"apiVersion":"batch/v1","kind":"Job","metadata":{"name":"migrate"},"spec":{"template":{"spec":{
+			"initContainers":[{"name":"init","resources":{"requests":{"memory":"1Gi"}}}],
+			"containers":[{"name":"job","resources":{"requests":{"cpu":"1","memory":"256Mi"}}}]}}}}`)},
+		{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cfg"},"data":{"a":"b"}}`)},
+	}
+	got, err := manifestRequests(manifests)
+	if err != nil {
+		t.Fatalf("manifestRequests: %v", err)
+	}
+	// web: 3 × (250m+100m, 128Mi+64Mi); migrate: 1 × (1, max(256Mi, 1Gi)).
+	for name, want := range map[corev1.ResourceName]string{
+		corev1.ResourceCPU:    "2050m",
+		corev1.ResourceMemory: "1600Mi",
+	} {
+		q := got[name]
+		if q.Cmp(resource.MustParse(want)) != 0 {
+			t.Errorf("%s = %s, want %s", name, q.String(), want)
+		}
+	}
+}
+
+func TestAdmitPlacements(t *testing.T) {
+	budget := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}
+	at := func(minutes int) metav1.Time {
+		return metav1.NewTime(time.Date(2026, 10, 16, 12, minutes, 0, 0, time.UTC))
+	}
+	cpu := func(q string) corev1.ResourceList {
+		return corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(q)}
+	}
+	entries := func() []budgetEntry {
+		return []budgetEntry{
+			{key: "ns/newest", created: at(3), requests: cpu("500m")},
+			{key: "ns/oldest", created: at(1), requests: cpu("1500m")},
+			{key: "ns/middle", created: at(2), requests: cpu("1")},
+		}
+	}
+
+	tests := []struct {
+		target   string
+		wantFits bool
+		wantLeft string
+	}{
+		// The oldest placement always gets its share first.
+		{"ns/oldest", true, "2"},
+		// middle would overflow next to oldest and is refused...
+		{"ns/middle", false, "500m"},
+		// ...which leaves room for the newest.
+		{"ns/newest", true, "500m"},
+	}
+	for _, tt := range tests {
+		fits, left := admitPlacements(budget, entries(), tt.target)
+		if fits != tt.wantFits {
+			t.Errorf("%s: fits = %v, want %v", tt.target, fits, tt.wantFits)
+		}
+		if q := left[corev1.ResourceCPU]; q.Cmp(resource.MustParse(tt.wantLeft)) != 0 {
+			t.Errorf("%s: left = %s, want %s", tt.target, q.String(), tt.wantLeft)
+		}
+	}
+
+	// Resources the budget does not cap are not limited.
+	unlimited := []budgetEntry{{key: "ns/mem", created: at(1), requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Gi")}}}
+	if fits, _ := admitPlacements(budget, unlimited, "ns/mem"); !fits {
+		t.Error("memory request refused by a CPU-only budget")
+	}
+}
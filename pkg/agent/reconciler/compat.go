@@ -0,0 +1,161 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+)
+
+// conditionCompatible is the Placement condition the agent sets when the edge
+// cluster's Kubernetes version needed attention: True (reason
+// APIVersionFallback) when objects were applied under an older or newer
+// equivalent API version, False (reason UnsupportedAPI) when some had no API
+// on the edge and were skipped, False (reason Forbidden) when the edge
+// cluster refused some, through the agent's RBAC or admission. It is absent when every object applied as
+// rendered.
+const conditionCompatible = "Compatible"
+
+// apiVersionFallbacks lists, per kind, the API versions that carry the same
+// schema, newest first. Field devices often run Kubernetes 1.21–1.24, which
+// predate some GA versions bundles are rendered with (autoscaling/v2 is 1.23+)
+// or have since dropped betas; an object is applied under the first version
+// of its kind the edge cluster serves. Versions whose schema differs (e.g.
+// autoscaling/v2beta1) are deliberately not listed.
+var apiVersionFallbacks = map[schema.GroupKind][]string{
+	{Group: "batch", Kind: "CronJob"}:                       {"v1", "v1beta1"},
+	{Group: "policy", Kind: "PodDisruptionBudget"}:          {"v1", "v1beta1"},
+	{Group: "autoscaling", Kind: "HorizontalPodAutoscaler"}: {"v2", "v2beta2"},
+	{Group: "discovery.k8s.io", Kind: "EndpointSlice"}:      {"v1", "v1beta1"},
+	{Group: "storage.k8s.io", Kind: "CSIStorageCapacity"}:   {"v1", "v1beta1"},
+}
+
+// bundleCompat collects what applying a bundle took to fit the edge cluster.
+type bundleCompat struct {
+	// fallbacks are objects applied under another API version.
+	fallbacks []string
+	// unsupported are objects skipped for lack of any API on the edge.
+	unsupported []string
+	// forbidden are objects the edge cluster refused with 403, from the
+	// agent's RBAC or from admission (ResourceQuota, PodSecurity), with the
+	// server's reason.
+	forbidden []string
+}
+
+// compatMapping returns the REST mapping for obj on the edge cluster. When
+// the cluster does not serve obj's API version it tries the equivalent
+// versions in apiVersionFallbacks and rewrites obj's apiVersion to the one
+// served. ok is false when no version is served; the object should then be
+// skipped and recorded in compat.
+func (r *WorkloadReconciler) compatMapping(obj *unstructured.Unstructured, compat *bundleCompat) (mapping *meta.RESTMapping, ok bool, err error) {
+	gvk := obj.GroupVersionKind()
+	mapping, err = r.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err == nil {
+		return mapping, true, nil
+	}
+	if !meta.IsNoMatchError(err) {
+		return nil, false, fmt.Errorf("no REST mapping for %s: %w", gvk, err)
+	}
+
+	for _, version := range apiVersionFallbacks[gvk.GroupKind()] {
+		if version == gvk.Version {
+			continue
+		}
+		mapping, err = r.mapper.RESTMapping(gvk.GroupKind(), version)
+		if meta.IsNoMatchError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("no REST mapping for %s: %w", gvk, err)
+		}
+		fallback := schema.GroupVersion{Group: gvk.Group, Version: version}
+		obj.SetAPIVersion(fallback.String())
+		compat.fallbacks = append(compat.fallbacks, fmt.Sprintf("%s %q as %s", gvk.Kind, obj.GetName(), fallback))
+		return mapping, true, nil
+	}
+
+	compat.unsupported = append(compat.unsupported, fmt.Sprintf("%s %q (%s)", gvk.Kind, obj.GetName(), gvk.GroupVersion()))
+	return nil, false, nil
+}
+
+// forbiddenMessage is the API server's reason for a 403, which tells an RBAC
+// denial from an exceeded quota or a PodSecurity violation.
+func forbiddenMessage(err error) string {
+	var status apierrors.APIStatus
+	if errors.As(err, &status) && status.Status().Message != "" {
+		return status.Status().Message
+	}
+	return err.Error()
+}
+
+// recordCompat reflects compat in the Placement's Compatible condition.
+func (r *WorkloadReconciler) recordCompat(ctx context.Context, placement *placementView, compat *bundleCompat) error {
+	conditions := append([]metav1.Condition(nil), placement.Status.Conditions...)
+	if len(compat.fallbacks) == 0 && len(compat.unsupported) == 0 && len(compat.forbidden) == 0 {
+		if !meta.RemoveStatusCondition(&conditions, conditionCompatible) {
+			return nil
+		}
+		return r.patchPlacementStatus(ctx, placement, conditions, "")
+	}
+
+	cond := metav1.Condition{
+		Type:               conditionCompatible,
+		Status:             metav1.ConditionTrue,
+		Reason:             "APIVersionFallback",
+		Message:            "Applied " + strings.Join(compat.fallbacks, ", ") + " for the edge cluster's Kubernetes version.",
+		ObservedGeneration: placement.Generation,
+	}
+	if len(compat.unsupported) > 0 {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "UnsupportedAPI"
+		cond.Message = "The edge cluster serves no API for " + strings.Join(compat.unsupported, ", ") + "; skipped."
+		if len(compat.fallbacks) > 0 {
+			cond.Message += " Applied " + strings.Join(compat.fallbacks, ", ") + "."
+		}
+		klog.FromContext(ctx).Info("Skipped objects the edge cluster has no API for",
+			"placement", placement.Name, "objects", compat.unsupported)
+	}
+	if len(compat.forbidden) > 0 {
+		forbidden := "The edge cluster refused " + strings.Join(compat.forbidden, "; ") +
+			". Copies already on the edge are left in place."
+		if cond.Status == metav1.ConditionFalse {
+			cond.Message += " " + forbidden
+		} else {
+			cond.Status = metav1.ConditionFalse
+			cond.Reason = "Forbidden"
+			cond.Message = forbidden
+			if len(compat.fallbacks) > 0 {
+				cond.Message += " Applied " + strings.Join(compat.fallbacks, ", ") + "."
+			}
+		}
+		klog.FromContext(ctx).Info("Skipped objects the agent may not apply",
+			"placement", placement.Name, "objects", compat.forbidden)
+	}
+	if !meta.SetStatusCondition(&conditions, cond) {
+		return nil
+	}
+	return r.patchPlacementStatus(ctx, placement, conditions, "")
+}
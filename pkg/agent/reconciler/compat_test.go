@@ -0,0 +1,218 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+// oldClusterMapper serves what a Kubernetes 1.20 cluster does for the kinds
+// under test: apps/v1 Deployments and batch/v1beta1 CronJobs, no PDB API.
+func oldClusterMapper() meta.RESTMapper {
+	m := meta.NewDefaultRESTMapper(nil)
+	m.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	m.Add(schema.GroupVersionKind{Group: "batch", Version: "v1beta1", Kind: "CronJob"}, meta.RESTScopeNamespace)
+	return m
+}
+
+func manifest(apiVersion, kind, name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": name},
+	}}
+}
+
+func TestCompatMapping(t *testing.T) {
+	r := &WorkloadReconciler{mapper: oldClusterMapper()}
+	compat := &bundleCompat{}
+
+	dep := manifest("apps/v1", "Deployment", "web")
+	if m, ok, err := r.compatMapping(dep, compat); err != nil || !ok || m.Resource.Resource != "deployments" {
+		t.Fatalf("Deployment: mapping %v, ok %v, err %v", m, ok, err)
+	}
+
+	cron := manifest("batch/v1", "CronJob", "backup")
+	m, ok, err := r.compatMapping(cron, compat)
+	if err != nil || !ok {
+		t.Fatalf("CronJob: ok %v, err %v", ok, err)
+	}
+	if m.Resource.Version != "v1beta1" || cron.GetAPIVersion() != "batch/v1beta1" {
+		t.Errorf("CronJob mapped to %s, apiVersion %s; want batch/v1beta1", m.Resource, cron.GetAPIVersion())
+	}
+
+	pdb := manifest("policy/v1", "PodDisruptionBudget", "web")
+	if _, ok, err := r.compatMapping(pdb, compat); err != nil || ok {
+		t.Fatalf("PDB: ok %v, err %v; want skipped", ok, err)
+	}
+
+	if len(compat.fallbacks) != 1 || !strings.Contains(compat.fallbacks[0], `CronJob "backup" as batch/v1beta1`) {
+		t.Errorf("fallbacks = %v", compat.fallbacks)
+	}
+	if len(compat.unsupported) != 1 || !strings.Contains(compat.unsupported[0], `PodDisruptionBudget "web" (policy/v1)`) {
+		t.Errorf("unsupported = %v", compat.unsupported)
+	}
+}
+
+func TestRecordCompat(t *testing.T) {
+	pu := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "edges.kedge.faros.sh/v1alpha1",
+		"kind":       "Placement",
+		"metadata":   map[string]interface{}{"name": "web-edge-1", "namespace": "default", "generation": int64(2)},
+	}}
+	hub := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{placementGVR: "PlacementList"}, pu)
+	r := &WorkloadReconciler{hubDynamic: hub}
+	placement := &placementView{ObjectMeta: metav1.ObjectMeta{Name: "web-edge-1", Namespace: "default", Generation: 2}}
+	placement.Status.Conditions = []metav1.Condition{{Type: conditionWithinBudget, Status: metav1.ConditionTrue, Reason: "WithinBudget"}}
+
+	compat := &bundleCompat{
+		fallbacks:   []string{`CronJob "backup" as batch/v1beta1`},
+		unsupported: []string{`PodDisruptionBudget "web" (policy/v1)`},
+	}
+	if err := r.recordCompat(context.Background(), placement, compat); err != nil {
+		t.Fatal(err)
+	}
+	got, err := hub.Resource(placementGVR).Namespace("default").Get(context.Background(), "web-edge-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	conds, _, _ := unstructured.NestedSlice(got.Object, "status", "conditions")
+	if len(conds) != 2 {
+		t.Fatalf("conditions = %v, want WithinBudget kept and Compatible added", conds)
+	}
+	c := conds[1].(map[string]interface{})
+	if c["type"] != conditionCompatible || c["status"] != "False" || c["reason"] != "UnsupportedAPI" ||
+		!strings.Contains(c["message"].(string), "policy/v1") || !strings.Contains(c["message"].(string), "batch/v1beta1") {
+		t.Errorf("Compatible condition = %v", c)
+	}
+
+	// Once the bundle applies as rendered the condition goes away.
+	hub.ClearActions()
+	if err := r.recordCompat(context.Background(), placement, &bundleCompat{}); err != nil {
+		t.Fatal(err)
+	}
+	if meta.FindStatusCondition(placement.Status.Conditions, conditionCompatible) != nil {
+		t.Error("Compatible condition not removed")
+	}
+	// Nothing to change → no write.
+	hub.ClearActions()
+	if err := r.recordCompat(context.Background(), placement, &bundleCompat{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(hub.Actions()) != 0 {
+		t.Errorf("unexpected writes: %v", hub.Actions())
+	}
+}
+
+func TestApplyBundleSkipsForbidden(t *testing.T) {
+	m := meta.NewDefaultRESTMapper(nil)
+	m.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	m.Add(schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}, meta.RESTScopeNamespace)
+
+	listKinds := map[schema.GroupVersionResource]string{}
+	for _, gvr := range prunableResources {
+		listKinds[gvr] = "List"
+	}
+	downstream := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+	var applied []string
+	downstream.PrependReactor("patch", "*", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		patch := action.(clienttesting.PatchAction)
+		if action.GetResource().Resource == "ingresses" {
+			return true, nil, apierrors.NewForbidden(action.GetResource().GroupResource(), patch.GetName(), nil)
+		}
+		applied = append(applied, action.GetResource().Resource+"/"+patch.GetName())
+		return true, &unstructured.Unstructured{}, nil
+	})
+
+	r := &WorkloadReconciler{mapper: m, downstreamDyn: downstream}
+	placement := &placementView{ObjectMeta: metav1.ObjectMeta{Name: "web-edge-1", Namespace: "default"}}
+	for _, obj := range []*unstructured.Unstructured{
+		manifest("apps/v1", "Deployment", "web"),
+		manifest("networking.k8s.io/v1", "Ingress", "web"),
+	} {
+		raw, err := obj.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		placement.Spec.Manifests = append(placement.Spec.Manifests, runtime.RawExtension{Raw: raw})
+	}
+
+	compat, err := r.applyBundle(context.Background(), placement)
+	if err != nil {
+		t.Fatalf("applyBundle: %v", err)
+	}
+	if len(applied) != 1 || applied[0] != "deployments/web" {
+		t.Errorf("applied = %v, want the Deployment only", applied)
+	}
+	if len(compat.forbidden) != 1 || !strings.Contains(compat.forbidden[0], `Ingress "web" (ingresses.networking.k8s.io)`) {
+		t.Errorf("forbidden = %v", compat.forbidden)
+	}
+}
+
+func TestApplyBundleKeepsRefusedObjects(t *testing.T) {
+	m := meta.NewDefaultRESTMapper(nil)
+	m.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+
+	listKinds := map[schema.GroupVersionResource]string{}
+	for _, gvr := range prunableResources {
+		listKinds[gvr] = "List"
+	}
+	running := manifest("apps/v1", "Deployment", "web")
+	running.SetNamespace(targetNamespace)
+	running.SetLabels(map[string]string{labelPlacement: "web-edge-1"})
+	downstream := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, running)
+	// An update a ResourceQuota refuses comes back as 403 like an RBAC
+	// denial; the running Deployment must survive it.
+	downstream.PrependReactor("patch", "deployments", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(action.GetResource().GroupResource(), "web",
+			errors.New("exceeded quota: compute, requested: limits.cpu=4, used: limits.cpu=2, limited: limits.cpu=4"))
+	})
+
+	r := &WorkloadReconciler{mapper: m, downstreamDyn: downstream}
+	placement := &placementView{ObjectMeta: metav1.ObjectMeta{Name: "web-edge-1", Namespace: "default"}}
+	raw, err := manifest("apps/v1", "Deployment", "web").MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	placement.Spec.Manifests = []runtime.RawExtension{{Raw: raw}}
+
+	compat, err := r.applyBundle(context.Background(), placement)
+	if err != nil {
+		t.Fatalf("applyBundle: %v", err)
+	}
+	for _, a := range downstream.Actions() {
+		if a.GetVerb() == "delete" {
+			t.Errorf("refused Deployment was pruned: %v", a)
+		}
+	}
+	if len(compat.forbidden) != 1 || !strings.Contains(compat.forbidden[0], "exceeded quota") {
+		t.Errorf("forbidden = %v, want the quota message", compat.forbidden)
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	"github.com/faroshq/faros-kedge/pkg/agent/metrics"
+)
+
+// gcInterval is how often the agent sweeps the edge for orphaned objects.
+const gcInterval = 10 * time.Minute
+
+// orphansDeleted counts objects the orphan sweep deleted, by resource.
+var orphansDeleted = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "kedge_agent",
+	Name:      "orphan_gc_deleted_total",
+	Help:      "Objects deleted from the edge cluster because the Placement that applied them no longer exists.",
+}, []string{"resource"})
+
+func init() {
+	metrics.Registry.MustRegister(orphansDeleted)
+}
+
+// collectOrphans deletes placement-managed objects whose Placement is gone.
+//
+// Placement deletions are normally handled by prune when the informer sees
+// them. An agent that was disconnected or restarted while a Placement was
+// deleted never does, and the objects it applied would run forever; this
+// periodic sweep catches them. Like prune it covers the namespaced
+// prunableResources, plus the GitOps tool's objects when placements are
+// handed off, here in every namespace. An object is only deleted
+// when the hub confirms its Placement does not exist or has moved to
+// another edge; any error reading the hub skips the object.
+func (r *WorkloadReconciler) collectOrphans(ctx context.Context) {
+	logger := klog.FromContext(ctx).WithName("orphan-gc")
+	gone := map[types.NamespacedName]bool{}
+
+	for _, gvr := range append(r.gitOpsResources(), prunableResources...) {
+		list, err := r.downstreamDyn.Resource(gvr).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{LabelSelector: labelPlacement})
+		if err != nil {
+			if !apierrors.IsNotFound(err) && !apierrors.IsForbidden(err) && !apierrors.IsMethodNotSupported(err) {
+				logger.Error(err, "Listing placement-managed objects failed", "resource", gvr.Resource)
+			}
+			continue
+		}
+		for i := range list.Items {
+			item := &list.Items[i]
+			if edge := item.GetLabels()[labelEdge]; edge != "" && edge != r.edgeName {
+				continue
+			}
+			ann := item.GetAnnotations()
+			key := types.NamespacedName{Namespace: ann[annPlacementNamespace], Name: ann[annPlacementName]}
+			if key.Namespace == "" || key.Name == "" {
+				// Without the annotations the owning Placement is unknown.
+				continue
+			}
+			orphaned, checked := gone[key]
+			if !checked {
+				if orphaned, err = r.placementGone(ctx, key); err != nil {
+					logger.Error(err, "Checking placement failed", "placement", key)
+					continue
+				}
+				gone[key] = orphaned
+			}
+			if !orphaned {
+				continue
+			}
+			propagation := metav1.DeletePropagationBackground
+			err := r.downstreamDyn.Resource(gvr).Namespace(item.GetNamespace()).Delete(ctx, item.GetName(), metav1.DeleteOptions{PropagationPolicy: &propagation})
+			if err != nil && !apierrors.IsNotFound(err) {
+				logger.Error(err, "Deleting orphaned object failed", "resource", gvr.Resource, "namespace", item.GetNamespace(), "name", item.GetName())
+				continue
+			}
+			orphansDeleted.WithLabelValues(gvr.Resource).Inc()
+			logger.Info("Deleted orphaned object", "resource", gvr.Resource, "namespace", item.GetNamespace(), "name", item.GetName(), "placement", key)
+		}
+	}
+}
+
+// placementGone reports whether the Placement key no longer places onto this
+// edge: it does not exist, or names another edge. The informer's store is
+// trusted when it has the Placement; otherwise the hub is asked, since the
+// store may lag.
+func (r *WorkloadReconciler) placementGone(ctx context.Context, key types.NamespacedName) (bool, error) {
+	if r.placements != nil {
+		if _, exists, err := r.placements.GetByKey(key.String()); err == nil && exists {
+			return false, nil
+		}
+	}
+	pu, err := r.hubDynamic.Resource(placementGVR).Namespace(key.Namespace).Get(ctx, key.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	var placement placementView
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(pu.Object, &placement); err != nil {
+		return false, fmt.Errorf("decoding placement %s: %w", key, err)
+	}
+	return placement.Spec.EdgeName != r.edgeName, nil
+}
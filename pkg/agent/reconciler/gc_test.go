@@ -0,0 +1,114 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func managedObject(kind, namespace, name, edge, placementNamespace, placement string) *unstructured.Unstructured {
+	labels := map[string]interface{}{labelPlacement: placement}
+	if edge != "" {
+		labels[labelEdge] = edge
+	}
+	metadata := map[string]interface{}{"name": name, "namespace": namespace, "labels": labels}
+	if placementNamespace != "" {
+		metadata["annotations"] = map[string]interface{}{
+			annPlacementName:      placement,
+			annPlacementNamespace: placementNamespace,
+		}
+	}
+	apiVersion := "v1"
+	if kind == "Deployment" {
+		apiVersion = "apps/v1"
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion, "kind": kind, "metadata": metadata,
+	}}
+}
+
+func placementObject(namespace, name, edge string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": edgesGroup + "/" + edgesVersion,
+		"kind":       "Placement",
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		"spec":       map[string]interface{}{"edgeName": edge},
+	}}
+}
+
+func TestCollectOrphans(t *testing.T) {
+	listKinds := map[schema.GroupVersionResource]string{}
+	for _, gvr := range prunableResources {
+		listKinds[gvr] = "List"
+	}
+	downstream := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds,
+		// Placement deleted while the agent was away.
+		managedObject("Deployment", "default", "gone", "edge-1", "tenant", "web-edge-1"),
+		managedObject("ConfigMap", "apps", "gone-config", "edge-1", "tenant", "web-edge-1"),
+		// Placement in the informer store.
+		managedObject("Deployment", "default", "cached", "edge-1", "tenant", "api-edge-1"),
+		// Placement only on the hub (store lagging).
+		managedObject("Deployment", "default", "live", "edge-1", "tenant", "db-edge-1"),
+		// Placement rescheduled to another edge.
+		managedObject("Deployment", "default", "moved", "edge-1", "tenant", "cache-edge-1"),
+		// Another edge's object, and one whose placement is unknown.
+		managedObject("Deployment", "default", "foreign", "edge-2", "tenant", "other"),
+		managedObject("Deployment", "default", "unannotated", "edge-1", "", "legacy"),
+	)
+	hub := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{placementGVR: "PlacementList"},
+		placementObject("tenant", "db-edge-1", "edge-1"),
+		placementObject("tenant", "cache-edge-1", "edge-2"),
+	)
+	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	if err := store.Add(placementObject("tenant", "api-edge-1", "edge-1")); err != nil {
+		t.Fatal(err)
+	}
+	r := &WorkloadReconciler{edgeName: "edge-1", hubDynamic: hub, downstreamDyn: downstream, placements: store}
+
+	before := testutil.ToFloat64(orphansDeleted.WithLabelValues("deployments"))
+	r.collectOrphans(context.Background())
+
+	var remaining []string
+	for _, gvr := range []schema.GroupVersionResource{prunableResources[0], {Version: "v1", Resource: "configmaps"}} {
+		list, err := downstream.Resource(gvr).Namespace(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, item := range list.Items {
+			remaining = append(remaining, item.GetName())
+		}
+	}
+	sort.Strings(remaining)
+	if got, want := strings.Join(remaining, " "), "cached foreign live unannotated"; got != want {
+		t.Errorf("remaining objects = %s, want %s", got, want)
+	}
+	if got := testutil.ToFloat64(orphansDeleted.WithLabelValues("deployments")) - before; got != 2 {
+		t.Errorf("deployments deleted counter += %v, want 2", got)
+	}
+}
@@ -0,0 +1,291 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+)
+
+// GitOpsTool selects the GitOps controller on the edge that placements are
+// handed off to instead of being applied by the agent.
+type GitOpsTool string
+
+const (
+	// GitOpsNone applies every placement directly (the default).
+	GitOpsNone GitOpsTool = ""
+	// GitOpsFlux hands placements off as a Flux GitRepository and
+	// Kustomization.
+	GitOpsFlux GitOpsTool = "flux"
+	// GitOpsArgoCD hands placements off as an Argo CD Application.
+	GitOpsArgoCD GitOpsTool = "argocd"
+)
+
+// DefaultNamespace is where the tool's objects go when no namespace is
+// configured: the namespace Flux and Argo CD are installed into by default.
+func (t GitOpsTool) DefaultNamespace() string {
+	switch t {
+	case GitOpsFlux:
+		return "flux-system"
+	case GitOpsArgoCD:
+		return "argocd"
+	}
+	return ""
+}
+
+// Workload annotations naming the Git source a handed-off placement is
+// synced from. A Workload without annotGitOpsRepo is applied directly even
+// when a GitOps tool is configured: simple, template and helm workloads are
+// rendered by the provider and have no source the tool could pull.
+const (
+	annotGitOpsRepo     = edgesGroup + "/gitops-repo"
+	annotGitOpsPath     = edgesGroup + "/gitops-path"
+	annotGitOpsRevision = edgesGroup + "/gitops-revision"
+
+	defaultGitOpsRevision = "main"
+)
+
+// gitOpsSyncInterval is how often the GitOps tool re-syncs a handed-off
+// placement.
+const gitOpsSyncInterval = "5m"
+
+// argoCDResourcesFinalizer makes deleting an Application delete what it
+// synced, as deleting a Flux Kustomization with prune does.
+const argoCDResourcesFinalizer = "resources-finalizer.argocd.argoproj.io"
+
+var (
+	gitRepositoryGVR = schema.GroupVersionResource{Group: "source.toolkit.fluxcd.io", Version: "v1", Resource: "gitrepositories"}
+	kustomizationGVR = schema.GroupVersionResource{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations"}
+	applicationGVR   = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"}
+
+	gitOpsKinds = map[string]schema.GroupVersionResource{
+		"GitRepository": gitRepositoryGVR,
+		"Kustomization": kustomizationGVR,
+		"Application":   applicationGVR,
+	}
+)
+
+var commitSHA = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// gitOpsSource is where a handed-off placement is synced from.
+type gitOpsSource struct {
+	repo     string
+	path     string
+	revision string
+}
+
+// SetGitOps has the reconciler hand placements whose Workload names a Git
+// source off to tool, creating its objects in namespace (empty: the tool's
+// default), instead of applying them itself. kedge still decides which
+// edges run the workload; the tool owns the apply. Call before Run.
+func (r *WorkloadReconciler) SetGitOps(tool GitOpsTool, namespace string) error {
+	switch tool {
+	case GitOpsNone, GitOpsFlux, GitOpsArgoCD:
+	default:
+		return fmt.Errorf("unknown GitOps tool %q: must be %q or %q", tool, GitOpsFlux, GitOpsArgoCD)
+	}
+	if namespace == "" {
+		namespace = tool.DefaultNamespace()
+	}
+	r.gitOpsTool = tool
+	r.gitOpsNamespace = namespace
+	return nil
+}
+
+// gitOpsResources are the kinds the configured tool's handoff creates.
+func (r *WorkloadReconciler) gitOpsResources() []schema.GroupVersionResource {
+	switch r.gitOpsTool {
+	case GitOpsFlux:
+		return []schema.GroupVersionResource{kustomizationGVR, gitRepositoryGVR}
+	case GitOpsArgoCD:
+		return []schema.GroupVersionResource{applicationGVR}
+	}
+	return nil
+}
+
+// workloadGitOpsSource returns the Git source the placement's Workload
+// names, and false when it names none or no GitOps tool is configured.
+func (r *WorkloadReconciler) workloadGitOpsSource(ctx context.Context, placement *placementView) (gitOpsSource, bool, error) {
+	if r.gitOpsTool == GitOpsNone {
+		return gitOpsSource{}, false, nil
+	}
+	ref := placement.Spec.WorkloadRef
+	ns := ref.Namespace
+	if ns == "" {
+		ns = placement.Namespace
+	}
+	wu, err := r.hubDynamic.Resource(workloadGVR).Namespace(ns).Get(ctx, ref.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		// The Workload is being deleted; its Placement follows.
+		return gitOpsSource{}, false, nil
+	}
+	if err != nil {
+		return gitOpsSource{}, false, fmt.Errorf("getting Workload %s/%s: %w", ns, ref.Name, err)
+	}
+	ann := wu.GetAnnotations()
+	src := gitOpsSource{repo: ann[annotGitOpsRepo], path: ann[annotGitOpsPath], revision: ann[annotGitOpsRevision]}
+	if src.repo == "" {
+		return gitOpsSource{}, false, nil
+	}
+	if src.path == "" {
+		src.path = "."
+	}
+	if src.revision == "" {
+		src.revision = defaultGitOpsRevision
+	}
+	return src, true, nil
+}
+
+// handOff creates or updates the configured tool's objects for placement.
+// Objects the agent applied directly before the handoff are left in place
+// for the tool to take over.
+func (r *WorkloadReconciler) handOff(ctx context.Context, placement *placementView, src gitOpsSource) error {
+	var objs []*unstructured.Unstructured
+	switch r.gitOpsTool {
+	case GitOpsFlux:
+		objs = fluxObjects(placement, src, r.gitOpsNamespace)
+	case GitOpsArgoCD:
+		objs = []*unstructured.Unstructured{argoCDApplication(placement, src, r.gitOpsNamespace)}
+	}
+	for _, obj := range objs {
+		r.stampPlacementMeta(obj, placement)
+		gvr := gitOpsKinds[obj.GetKind()]
+		if _, err := r.downstreamDyn.Resource(gvr).Namespace(r.gitOpsNamespace).Apply(
+			ctx, obj.GetName(), obj, metav1.ApplyOptions{FieldManager: fieldManager, Force: true},
+		); err != nil {
+			return fmt.Errorf("applying %s %s/%s: %w", gvr.Resource, r.gitOpsNamespace, obj.GetName(), err)
+		}
+	}
+	klog.FromContext(ctx).V(4).Info("Handed placement off", "placement", placement.Name, "tool", r.gitOpsTool, "repo", src.repo)
+	return nil
+}
+
+// pruneGitOps deletes the tool objects created for placementName, which
+// lets the tool garbage-collect what it synced. No-op without a tool.
+func (r *WorkloadReconciler) pruneGitOps(ctx context.Context, placementName string) error {
+	sel := labelPlacement + "=" + placementName
+	for _, gvr := range r.gitOpsResources() {
+		ri := r.downstreamDyn.Resource(gvr).Namespace(r.gitOpsNamespace)
+		list, err := ri.List(ctx, metav1.ListOptions{LabelSelector: sel})
+		if err != nil {
+			if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) {
+				continue
+			}
+			return fmt.Errorf("listing %s for prune: %w", gvr.Resource, err)
+		}
+		for i := range list.Items {
+			name := list.Items[i].GetName()
+			if err := ri.Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				return fmt.Errorf("pruning %s %q: %w", gvr.Resource, name, err)
+			}
+			klog.FromContext(ctx).Info("Pruned GitOps object", "resource", gvr.Resource, "name", name, "placement", placementName)
+		}
+	}
+	return nil
+}
+
+// gitOpsObjectName names the tool objects for a placement.
+func gitOpsObjectName(placement *placementView) string {
+	return "kedge-" + placement.Name
+}
+
+// fluxObjects returns the GitRepository and Kustomization for a handoff.
+// The Kustomization stamps the placement labels on everything it applies,
+// so the placement status reporter sees the resulting Deployments.
+func fluxObjects(placement *placementView, src gitOpsSource, namespace string) []*unstructured.Unstructured {
+	name := gitOpsObjectName(placement)
+	ref := map[string]interface{}{}
+	switch {
+	case commitSHA.MatchString(src.revision):
+		ref["commit"] = src.revision
+	case strings.HasPrefix(src.revision, "refs/"):
+		ref["name"] = src.revision
+	default:
+		ref["branch"] = src.revision
+	}
+	repo := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": gitRepositoryGVR.GroupVersion().String(),
+		"kind":       "GitRepository",
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		"spec": map[string]interface{}{
+			"url":      src.repo,
+			"ref":      ref,
+			"interval": gitOpsSyncInterval,
+		},
+	}}
+	ks := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": kustomizationGVR.GroupVersion().String(),
+		"kind":       "Kustomization",
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		"spec": map[string]interface{}{
+			"interval":  gitOpsSyncInterval,
+			"path":      src.path,
+			"prune":     true,
+			"sourceRef": map[string]interface{}{"kind": "GitRepository", "name": name},
+			"commonMetadata": map[string]interface{}{
+				"labels": map[string]interface{}{
+					labelPlacement: placement.Name,
+					labelWorkload:  placement.Spec.WorkloadRef.Name,
+					labelEdge:      placement.Spec.EdgeName,
+				},
+				"annotations": map[string]interface{}{
+					annPlacementName:      placement.Name,
+					annPlacementNamespace: placement.Namespace,
+					annPlacementUID:       string(placement.UID),
+				},
+			},
+		},
+	}}
+	return []*unstructured.Unstructured{repo, ks}
+}
+
+// argoCDApplication returns the Application for a handoff, synced
+// automatically into the edge cluster itself.
+func argoCDApplication(placement *placementView, src gitOpsSource, namespace string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": applicationGVR.GroupVersion().String(),
+		"kind":       "Application",
+		"metadata": map[string]interface{}{
+			"name":       gitOpsObjectName(placement),
+			"namespace":  namespace,
+			"finalizers": []interface{}{argoCDResourcesFinalizer},
+		},
+		"spec": map[string]interface{}{
+			"project": "default",
+			"source": map[string]interface{}{
+				"repoURL":        src.repo,
+				"path":           src.path,
+				"targetRevision": src.revision,
+			},
+			"destination": map[string]interface{}{
+				"server":    "https://kubernetes.default.svc",
+				"namespace": targetNamespace,
+			},
+			"syncPolicy": map[string]interface{}{
+				"automated": map[string]interface{}{"prune": true, "selfHeal": true},
+			},
+		},
+	}}
+}
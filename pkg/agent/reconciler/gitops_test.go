@@ -0,0 +1,164 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func gitOpsPlacement() *placementView {
+	p := &placementView{ObjectMeta: metav1.ObjectMeta{Name: "web-edge-1", Namespace: "tenant", UID: "uid-1"}}
+	p.Spec.EdgeName = "edge-1"
+	p.Spec.WorkloadRef = corev1.ObjectReference{Name: "web"}
+	return p
+}
+
+func TestWorkloadGitOpsSource(t *testing.T) {
+	workload := func(name string, annotations map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": edgesGroup + "/" + edgesVersion,
+			"kind":       "Workload",
+			"metadata":   map[string]interface{}{"name": name, "namespace": "tenant", "annotations": annotations},
+		}}
+	}
+	hub := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{workloadGVR: "WorkloadList"},
+		workload("web", map[string]interface{}{annotGitOpsRepo: "https://git.example.com/apps"}),
+		workload("plain", nil),
+	)
+	r := &WorkloadReconciler{hubDynamic: hub}
+	p := gitOpsPlacement()
+
+	if _, ok, err := r.workloadGitOpsSource(context.Background(), p); err != nil || ok {
+		t.Errorf("without a tool: handoff = %v, %v; want false", ok, err)
+	}
+	if err := r.SetGitOps(GitOpsFlux, ""); err != nil {
+		t.Fatal(err)
+	}
+	if r.gitOpsNamespace != "flux-system" {
+		t.Errorf("namespace = %q, want flux-system", r.gitOpsNamespace)
+	}
+	src, ok, err := r.workloadGitOpsSource(context.Background(), p)
+	if err != nil || !ok {
+		t.Fatalf("handoff = %v, %v; want true", ok, err)
+	}
+	if src != (gitOpsSource{repo: "https://git.example.com/apps", path: ".", revision: "main"}) {
+		t.Errorf("source = %+v", src)
+	}
+
+	for _, name := range []string{"plain", "missing"} {
+		p.Spec.WorkloadRef.Name = name
+		if _, ok, err := r.workloadGitOpsSource(context.Background(), p); err != nil || ok {
+			t.Errorf("%s: handoff = %v, %v; want false", name, ok, err)
+		}
+	}
+
+	if err := r.SetGitOps("jenkins", ""); err == nil {
+		t.Error("SetGitOps accepted an unknown tool")
+	}
+}
+
+func TestFluxObjects(t *testing.T) {
+	p := gitOpsPlacement()
+	for _, tc := range []struct {
+		revision, field string
+	}{
+		{"main", "branch"},
+		{"refs/tags/v1.2.0", "name"},
+		{"0123456789abcdef0123456789abcdef01234567", "commit"},
+	} {
+		objs := fluxObjects(p, gitOpsSource{repo: "https://git.example.com/apps", path: "./pos", revision: tc.revision}, "flux-system")
+		if len(objs) != 2 || objs[0].GetKind() != "GitRepository" || objs[1].GetKind() != "Kustomization" {
+			t.Fatalf("objects = %v", objs)
+		}
+		if got, _, _ := unstructured.NestedString(objs[0].Object, "spec", "ref", tc.field); got != tc.revision {
+			t.Errorf("%s: ref.%s = %q", tc.revision, tc.field, got)
+		}
+	}
+
+	ks := fluxObjects(p, gitOpsSource{repo: "r", path: "./pos", revision: "main"}, "flux-system")[1]
+	if ks.GetName() != "kedge-web-edge-1" || ks.GetNamespace() != "flux-system" {
+		t.Errorf("kustomization = %s/%s", ks.GetNamespace(), ks.GetName())
+	}
+	if got, _, _ := unstructured.NestedString(ks.Object, "spec", "sourceRef", "name"); got != "kedge-web-edge-1" {
+		t.Errorf("sourceRef.name = %q", got)
+	}
+	labels, _, _ := unstructured.NestedStringMap(ks.Object, "spec", "commonMetadata", "labels")
+	if labels[labelPlacement] != "web-edge-1" || labels[labelEdge] != "edge-1" {
+		t.Errorf("commonMetadata.labels = %v", labels)
+	}
+	annotations, _, _ := unstructured.NestedStringMap(ks.Object, "spec", "commonMetadata", "annotations")
+	if annotations[annPlacementNamespace] != "tenant" {
+		t.Errorf("commonMetadata.annotations = %v", annotations)
+	}
+}
+
+func TestArgoCDApplication(t *testing.T) {
+	app := argoCDApplication(gitOpsPlacement(), gitOpsSource{repo: "https://git.example.com/apps", path: "pos", revision: "v1"}, "argocd")
+	if app.GetName() != "kedge-web-edge-1" || app.GetNamespace() != "argocd" {
+		t.Errorf("application = %s/%s", app.GetNamespace(), app.GetName())
+	}
+	if got := app.GetFinalizers(); len(got) != 1 || got[0] != argoCDResourcesFinalizer {
+		t.Errorf("finalizers = %v", got)
+	}
+	for path, want := range map[string]string{
+		"repoURL": "https://git.example.com/apps", "path": "pos", "targetRevision": "v1",
+	} {
+		if got, _, _ := unstructured.NestedString(app.Object, "spec", "source", path); got != want {
+			t.Errorf("source.%s = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestPruneGitOps(t *testing.T) {
+	app := func(name, placement string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": applicationGVR.GroupVersion().String(),
+			"kind":       "Application",
+			"metadata": map[string]interface{}{
+				"name": name, "namespace": "argocd",
+				"labels": map[string]interface{}{labelPlacement: placement},
+			},
+		}}
+	}
+	downstream := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{applicationGVR: "ApplicationList"},
+		app("kedge-web-edge-1", "web-edge-1"), app("kedge-api-edge-1", "api-edge-1"))
+	r := &WorkloadReconciler{downstreamDyn: downstream}
+	if err := r.SetGitOps(GitOpsArgoCD, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.pruneGitOps(context.Background(), "web-edge-1"); err != nil {
+		t.Fatal(err)
+	}
+	list, err := downstream.Resource(applicationGVR).Namespace("argocd").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 1 || list.Items[0].GetName() != "kedge-api-edge-1" {
+		t.Errorf("remaining applications = %v", list.Items)
+	}
+}
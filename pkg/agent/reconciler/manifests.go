@@ -0,0 +1,204 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+
+	"github.com/faroshq/faros-kedge/pkg/agent/health"
+	"github.com/faroshq/faros-kedge/pkg/apiurl"
+)
+
+// maxCachedManifestBytes bounds the bundles a ManifestFetcher keeps.
+const maxCachedManifestBytes = 32 << 20
+
+// manifestFetchTimeout bounds one bundle download.
+const manifestFetchTimeout = time.Minute
+
+// manifestsRef mirrors a Placement's spec.manifestsRef: the digest of a
+// bundle held in the edges provider's manifest store.
+type manifestsRef struct {
+	Digest string `json:"digest"`
+	Size   int64  `json:"size"`
+	Count  int32  `json:"count"`
+}
+
+// ManifestFetcher downloads the manifest bundles Placements reference by
+// digest from the edges provider's manifest store, through the hub, and
+// keeps them by digest: an edge running the same workload for several
+// placements downloads it once, and re-reconciles download nothing.
+type ManifestFetcher struct {
+	client  *http.Client
+	hubBase string
+	cluster string
+
+	mu     sync.Mutex
+	cache  map[string]cachedBundle
+	order  []string
+	cached int64
+}
+
+type cachedBundle struct {
+	manifests []runtime.RawExtension
+	size      int64
+}
+
+// NewManifestFetcher returns a fetcher authenticating to the hub with
+// hubConfig, for Placements in the tenant workspace cluster.
+func NewManifestFetcher(hubConfig *rest.Config, cluster string) (*ManifestFetcher, error) {
+	client, err := rest.HTTPClientFor(hubConfig)
+	if err != nil {
+		return nil, fmt.Errorf("building manifest fetch client: %w", err)
+	}
+	if client.Timeout == 0 {
+		client.Timeout = manifestFetchTimeout
+	}
+	hubBase, _ := apiurl.SplitBaseAndCluster(hubConfig.Host)
+	return &ManifestFetcher{
+		client:  client,
+		hubBase: hubBase,
+		cluster: cluster,
+		cache:   map[string]cachedBundle{},
+	}, nil
+}
+
+// Fetch returns the bundle ref points at for the Placement namespace/name.
+// The download is verified against the digest, so neither the hub nor the
+// provider can substitute a different bundle.
+func (f *ManifestFetcher) Fetch(ctx context.Context, namespace, name string, ref manifestsRef) ([]runtime.RawExtension, error) {
+	f.mu.Lock()
+	bundle, ok := f.cache[ref.Digest]
+	f.mu.Unlock()
+	if ok {
+		return bundle.manifests, nil
+	}
+
+	url := apiurl.ProviderManifestsURL(f.hubBase, f.cluster, namespace, name, ref.Digest)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching manifest bundle %s: %w", ref.Digest, err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode != http.StatusOK {
+		return nil, &health.HTTPStatusError{
+			Code: resp.StatusCode,
+			Err:  fmt.Errorf("fetching manifest bundle %s: %s", ref.Digest, resp.Status),
+		}
+	}
+	// The bundle's size is known; anything longer is not it.
+	data, err := io.ReadAll(io.LimitReader(resp.Body, ref.Size+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest bundle %s: %w", ref.Digest, err)
+	}
+	sum := sha256.Sum256(data)
+	if got := "sha256:" + hex.EncodeToString(sum[:]); got != ref.Digest {
+		return nil, fmt.Errorf("manifest bundle %s failed verification: content digest is %s", ref.Digest, got)
+	}
+	var objs []json.RawMessage
+	if err := json.Unmarshal(data, &objs); err != nil {
+		return nil, fmt.Errorf("decoding manifest bundle %s: %w", ref.Digest, err)
+	}
+	manifests := make([]runtime.RawExtension, 0, len(objs))
+	for _, obj := range objs {
+		manifests = append(manifests, runtime.RawExtension{Raw: obj})
+	}
+
+	f.mu.Lock()
+	f.add(ref.Digest, manifests, int64(len(data)))
+	f.mu.Unlock()
+	return manifests, nil
+}
+
+// Inline replaces the spec.manifestsRef of Placement p with the bundle it
+// references, so p carries its manifests itself, as it must in an offline
+// placement bundle. Placements without a manifestsRef are left alone.
+func (f *ManifestFetcher) Inline(ctx context.Context, p *unstructured.Unstructured) error {
+	raw, found, _ := unstructured.NestedMap(p.Object, "spec", "manifestsRef")
+	if !found {
+		return nil
+	}
+	var ref manifestsRef
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &ref); err != nil {
+		return fmt.Errorf("decoding manifestsRef of placement %s/%s: %w", p.GetNamespace(), p.GetName(), err)
+	}
+	manifests, err := f.Fetch(ctx, p.GetNamespace(), p.GetName(), ref)
+	if err != nil {
+		return err
+	}
+	objs := make([]interface{}, 0, len(manifests))
+	for _, m := range manifests {
+		var obj map[string]interface{}
+		if err := json.Unmarshal(m.Raw, &obj); err != nil {
+			return fmt.Errorf("decoding manifest of placement %s/%s: %w", p.GetNamespace(), p.GetName(), err)
+		}
+		objs = append(objs, obj)
+	}
+	unstructured.RemoveNestedField(p.Object, "spec", "manifestsRef")
+	return unstructured.SetNestedSlice(p.Object, objs, "spec", "manifests")
+}
+
+// add caches a bundle, evicting the oldest ones to stay within
+// maxCachedManifestBytes. Callers hold f.mu.
+func (f *ManifestFetcher) add(digest string, manifests []runtime.RawExtension, size int64) {
+	if _, ok := f.cache[digest]; ok {
+		return
+	}
+	f.cache[digest] = cachedBundle{manifests: manifests, size: size}
+	f.order = append(f.order, digest)
+	f.cached += size
+	for f.cached > maxCachedManifestBytes && len(f.order) > 1 {
+		oldest := f.order[0]
+		f.order = f.order[1:]
+		f.cached -= f.cache[oldest].size
+		delete(f.cache, oldest)
+	}
+}
+
+// resolveManifests fills in spec.manifests of a Placement that references a
+// stored bundle, so the rest of the reconciler treats both alike.
+func (r *WorkloadReconciler) resolveManifests(ctx context.Context, placement *placementView) error {
+	ref := placement.Spec.ManifestsRef
+	if ref == nil || len(placement.Spec.Manifests) > 0 {
+		return nil
+	}
+	if r.manifests == nil {
+		return fmt.Errorf("placement %s/%s references stored manifests %s, but no manifest fetcher is configured",
+			placement.Namespace, placement.Name, ref.Digest)
+	}
+	manifests, err := r.manifests.Fetch(ctx, placement.Namespace, placement.Name, *ref)
+	if err != nil {
+		return err
+	}
+	placement.Spec.Manifests = manifests
+	return nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+)
+
+func TestManifestFetcher(t *testing.T) {
+	bundle := []byte(`[{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"web"}}]`)
+	sum := sha256.Sum256(bundle)
+	ref := manifestsRef{Digest: "sha256:" + hex.EncodeToString(sum[:]), Size: int64(len(bundle)), Count: 1}
+
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		switch r.URL.Path {
+		case "/services/providers/edges/manifests/tenant/default/web-edge/" + ref.Digest:
+			if r.Header.Get("Authorization") != "Bearer agent-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write(bundle)
+		default:
+			_, _ = w.Write([]byte(`[{"kind":"Tampered"}]`))
+		}
+	}))
+	defer srv.Close()
+
+	f, err := NewManifestFetcher(&rest.Config{Host: srv.URL + "/clusters/tenant", BearerToken: "agent-token"}, "tenant")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		manifests, err := f.Fetch(ctx, "default", "web-edge", ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(manifests) != 1 || string(manifests[0].Raw) != `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"web"}}` {
+			t.Fatalf("manifests = %s", manifests)
+		}
+	}
+	if hits != 1 {
+		t.Fatalf("bundle downloaded %d times, want once", hits)
+	}
+
+	// A response that does not match the digest is refused.
+	if _, err := f.Fetch(ctx, "default", "other", ref); err != nil {
+		t.Fatalf("cached bundle refetched: %v", err)
+	}
+	forged := manifestsRef{Digest: "sha256:" + hex.EncodeToString(make([]byte, 32)), Size: 64, Count: 1}
+	if _, err := f.Fetch(ctx, "default", "web-edge", forged); err == nil {
+		t.Fatal("bundle not matching its digest accepted")
+	}
+
+	// Inline swaps the reference for the manifests it names.
+	p := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "web-edge", "namespace": "default"},
+		"spec": map[string]interface{}{"manifestsRef": map[string]interface{}{
+			"digest": ref.Digest, "size": ref.Size, "count": int64(ref.Count),
+		}},
+	}}
+	if err := f.Inline(ctx, p); err != nil {
+		t.Fatalf("Inline: %v", err)
+	}
+	manifests, _, _ := unstructured.NestedSlice(p.Object, "spec", "manifests")
+	if _, hasRef := p.Object["spec"].(map[string]interface{})["manifestsRef"]; hasRef || len(manifests) != 1 {
+		t.Errorf("inlined spec = %v", p.Object["spec"])
+	}
+}
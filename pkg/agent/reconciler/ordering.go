@@ -0,0 +1,235 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+)
+
+// DefaultApplyConcurrency is how many objects of one apply phase the agent
+// applies at once.
+const DefaultApplyConcurrency = 4
+
+// crdEstablishedTimeout bounds the wait for a bundle's CustomResourceDefinitions
+// to be served before its custom resources are applied. A CRD that takes
+// longer fails the reconcile, which is retried.
+const crdEstablishedTimeout = 30 * time.Second
+
+var crdGVR = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// applyPhases orders the kinds of a bundle so that nothing is applied before
+// what it depends on: namespaces and CRDs first, then the configuration,
+// storage and RBAC workloads reference, then Services, then the workloads.
+// Kinds not listed — Ingresses, webhooks, custom resources — go in a last
+// phase. Each phase finishes before the next starts; within one, objects are
+// applied concurrently.
+var applyPhases = [][]schema.GroupKind{
+	{
+		{Kind: "Namespace"},
+		{Group: crdGVR.Group, Kind: "CustomResourceDefinition"},
+	},
+	{
+		{Group: "scheduling.k8s.io", Kind: "PriorityClass"},
+		{Group: "storage.k8s.io", Kind: "StorageClass"},
+		{Kind: "ResourceQuota"},
+		{Kind: "LimitRange"},
+		{Group: "networking.k8s.io", Kind: "NetworkPolicy"},
+		{Group: "policy", Kind: "PodDisruptionBudget"},
+		{Kind: "ServiceAccount"},
+		{Kind: "Secret"},
+		{Kind: "ConfigMap"},
+		{Kind: "PersistentVolume"},
+		{Kind: "PersistentVolumeClaim"},
+		{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"},
+		{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"},
+		{Group: "rbac.authorization.k8s.io", Kind: "Role"},
+		{Group: "rbac.authorization.k8s.io", Kind: "RoleBinding"},
+	},
+	{
+		{Kind: "Service"},
+	},
+	{
+		{Kind: "Pod"},
+		{Group: "apps", Kind: "ReplicaSet"},
+		{Group: "apps", Kind: "Deployment"},
+		{Group: "apps", Kind: "StatefulSet"},
+		{Group: "apps", Kind: "DaemonSet"},
+		{Group: "batch", Kind: "Job"},
+		{Group: "batch", Kind: "CronJob"},
+		{Group: "autoscaling", Kind: "HorizontalPodAutoscaler"},
+	},
+}
+
+// SetApplyConcurrency has the reconciler apply up to n objects of a bundle's
+// phase at once. n <= 0 uses DefaultApplyConcurrency. Call before Run.
+func (r *WorkloadReconciler) SetApplyConcurrency(n int) {
+	r.applyConcurrency = n
+}
+
+// applyPhase returns the index in applyPhases of gk's phase.
+func applyPhase(gk schema.GroupKind) int {
+	for i, kinds := range applyPhases {
+		for _, k := range kinds {
+			if k == gk {
+				return i
+			}
+		}
+	}
+	return len(applyPhases)
+}
+
+// orderBundle splits objs into their apply phases, keeping the bundle's order
+// within each. Empty phases are dropped.
+func orderBundle(objs []*unstructured.Unstructured) [][]*unstructured.Unstructured {
+	phases := make([][]*unstructured.Unstructured, len(applyPhases)+1)
+	for _, obj := range objs {
+		i := applyPhase(obj.GroupVersionKind().GroupKind())
+		phases[i] = append(phases[i], obj)
+	}
+	ordered := phases[:0]
+	for _, phase := range phases {
+		if len(phase) > 0 {
+			ordered = append(ordered, phase)
+		}
+	}
+	return ordered
+}
+
+// applyObjects server-side applies one phase of a placement's bundle, up to
+// r.applyConcurrency objects at once, recording the applied objects in keep
+// and the skipped ones in compat. It returns the names of the
+// CustomResourceDefinitions it applied.
+func (r *WorkloadReconciler) applyObjects(ctx context.Context, placement *placementView, objs []*unstructured.Unstructured, keep map[appliedRef]bool, compat *bundleCompat) ([]string, error) {
+	logger := klog.FromContext(ctx).WithValues("placement", placement.Name)
+	limit := r.applyConcurrency
+	if limit <= 0 {
+		limit = DefaultApplyConcurrency
+	}
+
+	var (
+		mu   sync.Mutex
+		crds []string
+	)
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(limit)
+	for _, obj := range objs {
+		// Mapping runs here rather than in the goroutines: it appends to
+		// compat and rewrites obj's apiVersion.
+		mapping, ok, err := r.compatMapping(obj, compat)
+		if err != nil {
+			_ = g.Wait()
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		gvk := obj.GroupVersionKind()
+
+		var ri dynamic.ResourceInterface
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			ns := obj.GetNamespace()
+			if ns == "" {
+				ns = targetNamespace
+				obj.SetNamespace(ns)
+			}
+			ri = r.downstreamDyn.Resource(mapping.Resource).Namespace(ns)
+		} else {
+			ri = r.downstreamDyn.Resource(mapping.Resource)
+		}
+		r.stampPlacementMeta(obj, placement)
+
+		g.Go(func() error {
+			if _, err := ri.Apply(gctx, obj.GetName(), obj, metav1.ApplyOptions{FieldManager: fieldManager, Force: true}); err != nil {
+				// A kind outside the agent's RBAC fails every retry; name it
+				// on the Placement rather than failing the rest of the bundle.
+				// Admission (ResourceQuota, PodSecurity) refuses with 403 too,
+				// so the object stays in keep: a refused update must not
+				// prune the copy already running on the edge.
+				if apierrors.IsForbidden(err) {
+					mu.Lock()
+					keep[appliedRef{gvr: mapping.Resource, name: obj.GetName()}] = true
+					compat.forbidden = append(compat.forbidden, fmt.Sprintf("%s %q (%s): %s",
+						gvk.Kind, obj.GetName(), mapping.Resource.GroupResource(), forbiddenMessage(err)))
+					mu.Unlock()
+					return nil
+				}
+				return fmt.Errorf("applying %s %q: %w", mapping.Resource.Resource, obj.GetName(), err)
+			}
+			mu.Lock()
+			keep[appliedRef{gvr: mapping.Resource, name: obj.GetName()}] = true
+			if mapping.Resource.GroupResource() == crdGVR.GroupResource() {
+				crds = append(crds, obj.GetName())
+			}
+			mu.Unlock()
+			logger.V(4).Info("Applied object", "kind", gvk.Kind, "apiVersion", obj.GetAPIVersion(), "name", obj.GetName())
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return crds, nil
+}
+
+// waitForCRDs waits until the named CustomResourceDefinitions are
+// Established, then resets the REST mapper so the custom resources of the
+// following phases map to their new APIs.
+func (r *WorkloadReconciler) waitForCRDs(ctx context.Context, names []string) error {
+	for _, name := range names {
+		if err := wait.PollUntilContextTimeout(ctx, 500*time.Millisecond, crdEstablishedTimeout, true, func(ctx context.Context) (bool, error) {
+			crd, err := r.downstreamDyn.Resource(crdGVR).Get(ctx, name, metav1.GetOptions{})
+			switch {
+			case apierrors.IsForbidden(err):
+				return false, err
+			case err != nil:
+				return false, nil
+			}
+			return crdEstablished(crd), nil
+		}); err != nil {
+			return fmt.Errorf("waiting for CustomResourceDefinition %q to be established: %w", name, err)
+		}
+	}
+	if m, ok := r.mapper.(meta.ResettableRESTMapper); ok {
+		m.Reset()
+	}
+	return nil
+}
+
+// crdEstablished reports whether crd's Established condition is True.
+func crdEstablished(crd *unstructured.Unstructured) bool {
+	conds, _, _ := unstructured.NestedSlice(crd.Object, "status", "conditions")
+	for _, c := range conds {
+		cond, ok := c.(map[string]interface{})
+		if ok && cond["type"] == "Established" && cond["status"] == string(metav1.ConditionTrue) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+var widgetGVK = schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+
+// crdMapper serves what an edge cluster does before and after a bundle's
+// Widget CRD is established: discovery only lists Widgets once reset.
+type crdMapper struct {
+	*meta.DefaultRESTMapper
+	resets int
+}
+
+func (m *crdMapper) Reset() {
+	m.resets++
+	m.Add(widgetGVK, meta.RESTScopeNamespace)
+}
+
+func TestOrderBundle(t *testing.T) {
+	bundle := []*unstructured.Unstructured{
+		manifest("networking.k8s.io/v1", "Ingress", "web"),
+		manifest("apps/v1", "Deployment", "web"),
+		manifest("example.com/v1", "Widget", "w"),
+		manifest("v1", "Service", "web"),
+		manifest("v1", "ConfigMap", "web"),
+		manifest("apiextensions.k8s.io/v1", "CustomResourceDefinition", "widgets.example.com"),
+		manifest("v1", "Namespace", "shop"),
+		manifest("v1", "ConfigMap", "extra"),
+	}
+	var got [][]string
+	for _, phase := range orderBundle(bundle) {
+		var names []string
+		for _, obj := range phase {
+			names = append(names, obj.GetKind()+"/"+obj.GetName())
+		}
+		got = append(got, names)
+	}
+	want := [][]string{
+		{"CustomResourceDefinition/widgets.example.com", "Namespace/shop"},
+		{"ConfigMap/web", "ConfigMap/extra"},
+		{"Service/web"},
+		{"Deployment/web"},
+		{"Ingress/web", "Widget/w"},
+	}
+	if !slices.EqualFunc(got, want, slices.Equal[[]string]) {
+		t.Errorf("phases = %v, want %v", got, want)
+	}
+}
+
+func TestApplyBundleOrdersPhases(t *testing.T) {
+	m := &crdMapper{DefaultRESTMapper: meta.NewDefaultRESTMapper(nil)}
+	m.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+	m.Add(schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}, meta.RESTScopeRoot)
+	m.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	m.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+
+	listKinds := map[schema.GroupVersionResource]string{}
+	for _, gvr := range prunableResources {
+		listKinds[gvr] = "List"
+	}
+	downstream := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+	var applied []string
+	downstream.PrependReactor("patch", "*", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		applied = append(applied, action.GetResource().Resource+"/"+action.(clienttesting.PatchAction).GetName())
+		return true, &unstructured.Unstructured{}, nil
+	})
+	crdGets := 0
+	downstream.PrependReactor("get", "customresourcedefinitions", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		crdGets++
+		crd := manifest("apiextensions.k8s.io/v1", "CustomResourceDefinition", "widgets.example.com")
+		if crdGets > 1 {
+			_ = unstructured.SetNestedSlice(crd.Object, []interface{}{
+				map[string]interface{}{"type": "Established", "status": "True"},
+			}, "status", "conditions")
+		}
+		return true, crd, nil
+	})
+
+	r := &WorkloadReconciler{mapper: m, downstreamDyn: downstream, applyConcurrency: 2}
+	placement := &placementView{ObjectMeta: metav1.ObjectMeta{Name: "web-edge-1", Namespace: "default"}}
+	for _, obj := range []*unstructured.Unstructured{
+		manifest("example.com/v1", "Widget", "w"),
+		manifest("apps/v1", "Deployment", "web"),
+		manifest("v1", "ConfigMap", "web"),
+		manifest("apiextensions.k8s.io/v1", "CustomResourceDefinition", "widgets.example.com"),
+		manifest("v1", "Namespace", "shop"),
+	} {
+		raw, err := obj.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		placement.Spec.Manifests = append(placement.Spec.Manifests, runtime.RawExtension{Raw: raw})
+	}
+
+	compat, err := r.applyBundle(context.Background(), placement)
+	if err != nil {
+		t.Fatalf("applyBundle: %v", err)
+	}
+	if len(compat.unsupported) != 0 {
+		t.Errorf("unsupported = %v, want the Widget applied once its CRD is established", compat.unsupported)
+	}
+	if len(applied) != 5 {
+		t.Fatalf("applied = %v, want all 5 objects", applied)
+	}
+	// Namespace and CRD share the first phase, in either order.
+	first := applied[:2]
+	slices.Sort(first)
+	if !slices.Equal(first, []string{"customresourcedefinitions/widgets.example.com", "namespaces/shop"}) ||
+		!slices.Equal(applied[2:], []string{"configmaps/web", "deployments/web", "widgets/w"}) {
+		t.Errorf("applied = %v, want namespace and CRD, then configmap, deployment, widget", applied)
+	}
+	if crdGets < 2 || m.resets != 1 {
+		t.Errorf("CRD polled %d times, mapper reset %d times; want it waited for once", crdGets, m.resets)
+	}
+}
@@ -0,0 +1,284 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+)
+
+// Namespace quotas keep fleet-wide resource policy on the hub: the
+// namespaceQuota of the edge and of each placed Workload is written as a
+// ResourceQuota and a LimitRange into the edge namespaces the Placements'
+// objects land in. Edge policy is named edgeQuotaName and shared by every
+// placement using the namespace; Workload policy is named after the Workload
+// and labeled with its Placement, which removes it when it goes away.
+const (
+	// labelQuota marks an object the agent materialized from a
+	// namespaceQuota, with the quota's scope: quotaScopeEdge or
+	// quotaScopeWorkload.
+	labelQuota         = edgesGroup + "/quota"
+	quotaScopeEdge     = "edge"
+	quotaScopeWorkload = "workload"
+
+	edgeQuotaName       = "kedge-edge"
+	workloadQuotaPrefix = "kedge-workload-"
+)
+
+var (
+	resourceQuotaGVR = schema.GroupVersionResource{Version: "v1", Resource: "resourcequotas"}
+	limitRangeGVR    = schema.GroupVersionResource{Version: "v1", Resource: "limitranges"}
+
+	quotaResources = []schema.GroupVersionResource{resourceQuotaGVR, limitRangeGVR}
+)
+
+// namespaceQuota mirrors the edges provider's NamespaceQuota.
+type namespaceQuota struct {
+	ResourceQuota *corev1.ResourceQuotaSpec `json:"resourceQuota,omitempty"`
+	LimitRange    *corev1.LimitRangeSpec    `json:"limitRange,omitempty"`
+}
+
+// wants reports whether q asks for an object of gvr.
+func (q *namespaceQuota) wants(gvr schema.GroupVersionResource) bool {
+	if q == nil {
+		return false
+	}
+	if gvr == resourceQuotaGVR {
+		return q.ResourceQuota != nil
+	}
+	return q.LimitRange != nil
+}
+
+// placementQuotas are the namespace quotas that apply to one placement.
+type placementQuotas struct {
+	edge     *namespaceQuota
+	workload *namespaceQuota
+}
+
+// namespaceQuotas reads the namespaceQuota of the agent's edge and of the
+// placement's Workload.
+func (r *WorkloadReconciler) namespaceQuotas(ctx context.Context, placement *placementView) (*placementQuotas, error) {
+	edge, err := r.hubDynamic.Resource(kedgeclient.KubernetesClusterGVR).Get(ctx, r.edgeName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("getting edge %q: %w", r.edgeName, err)
+	}
+	q := &placementQuotas{}
+	if q.edge, err = decodeNamespaceQuota(edge); err != nil {
+		return nil, fmt.Errorf("decoding namespaceQuota of edge %q: %w", r.edgeName, err)
+	}
+
+	ref := placement.Spec.WorkloadRef
+	ns := ref.Namespace
+	if ns == "" {
+		ns = placement.Namespace
+	}
+	wu, err := r.hubDynamic.Resource(workloadGVR).Namespace(ns).Get(ctx, ref.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		// The Workload is being deleted; its Placement follows.
+		return q, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting Workload %s/%s: %w", ns, ref.Name, err)
+	}
+	if q.workload, err = decodeNamespaceQuota(wu); err != nil {
+		return nil, fmt.Errorf("decoding namespaceQuota of Workload %s/%s: %w", ns, ref.Name, err)
+	}
+	return q, nil
+}
+
+// decodeNamespaceQuota returns the spec.namespaceQuota of obj, or nil.
+func decodeNamespaceQuota(obj *unstructured.Unstructured) (*namespaceQuota, error) {
+	raw, found, _ := unstructured.NestedMap(obj.Object, "spec", "namespaceQuota")
+	if !found {
+		return nil, nil
+	}
+	var q namespaceQuota
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &q); err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+// quotaNamespaces returns the namespaces placement's objects land in: those
+// its bundle names, its Namespaces, and targetNamespace for the objects that
+// name none.
+func quotaNamespaces(placement *placementView) []string {
+	set := map[string]bool{}
+	for _, raw := range placement.Spec.Manifests {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(raw.Raw); err != nil {
+			continue
+		}
+		switch {
+		case obj.GetKind() == "Namespace" && obj.GroupVersionKind().Group == "":
+			set[obj.GetName()] = true
+		case obj.GetNamespace() != "":
+			set[obj.GetNamespace()] = true
+		default:
+			set[targetNamespace] = true
+		}
+	}
+	if len(set) == 0 {
+		// A legacy placement's Deployment.
+		set[targetNamespace] = true
+	}
+	out := make([]string, 0, len(set))
+	for ns := range set {
+		out = append(out, ns)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// applyNamespaceQuotas writes quotas into the namespaces of placement and
+// removes the objects of quotas, or parts of them, that were unset. Edge
+// policy stays in namespaces placement no longer uses, as other placements
+// may. Namespaces that do not exist yet are skipped: the reconciler applies
+// quotas before a bundle, so its pods are admitted under them, and again
+// after it, for the namespaces the bundle creates.
+func (r *WorkloadReconciler) applyNamespaceQuotas(ctx context.Context, placement *placementView, quotas *placementQuotas) error {
+	namespaces := quotaNamespaces(placement)
+
+	edgeLabels := map[string]string{labelEdge: r.edgeName, labelQuota: quotaScopeEdge}
+	for _, gvr := range quotaResources {
+		if quotas.edge.wants(gvr) {
+			continue
+		}
+		if err := r.pruneQuotas(ctx, gvr, edgeLabels, nil); err != nil {
+			return err
+		}
+	}
+	for _, ns := range namespaces {
+		if _, err := r.applyQuota(ctx, quotas.edge, ns, edgeQuotaName, edgeLabels); err != nil {
+			return err
+		}
+	}
+
+	workloadLabels := map[string]string{
+		labelEdge:      r.edgeName,
+		labelQuota:     quotaScopeWorkload,
+		labelPlacement: placement.Name,
+		labelWorkload:  placement.Spec.WorkloadRef.Name,
+	}
+	keep := map[string]bool{}
+	for _, ns := range namespaces {
+		applied, err := r.applyQuota(ctx, quotas.workload, ns, workloadQuotaPrefix+placement.Spec.WorkloadRef.Name, workloadLabels)
+		if err != nil {
+			return err
+		}
+		for _, ref := range applied {
+			keep[ref] = true
+		}
+	}
+	for _, gvr := range quotaResources {
+		if err := r.pruneQuotas(ctx, gvr, workloadLabels, keep); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyQuota server-side applies the objects q asks for as name in
+// namespace, and returns them as "<resource>/<namespace>/<name>".
+func (r *WorkloadReconciler) applyQuota(ctx context.Context, q *namespaceQuota, namespace, name string, labels map[string]string) ([]string, error) {
+	if q == nil {
+		return nil, nil
+	}
+	meta := metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels}
+	var objs []runtime.Object
+	if q.ResourceQuota != nil {
+		objs = append(objs, &corev1.ResourceQuota{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ResourceQuota"},
+			ObjectMeta: meta,
+			Spec:       *q.ResourceQuota,
+		})
+	}
+	if q.LimitRange != nil {
+		objs = append(objs, &corev1.LimitRange{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "LimitRange"},
+			ObjectMeta: meta,
+			Spec:       *q.LimitRange,
+		})
+	}
+
+	var applied []string
+	for _, obj := range objs {
+		raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, fmt.Errorf("converting %T %q: %w", obj, name, err)
+		}
+		u := &unstructured.Unstructured{Object: raw}
+		unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+		unstructured.RemoveNestedField(u.Object, "status")
+		gvr := resourceQuotaGVR
+		if u.GetKind() == "LimitRange" {
+			gvr = limitRangeGVR
+		}
+		if _, err := r.downstreamDyn.Resource(gvr).Namespace(namespace).Apply(ctx, name, u, metav1.ApplyOptions{FieldManager: fieldManager, Force: true}); err != nil {
+			if apierrors.IsNotFound(err) {
+				klog.FromContext(ctx).V(4).Info("Namespace not there yet, skipping quota", "namespace", namespace, "kind", u.GetKind(), "name", name)
+				continue
+			}
+			return nil, fmt.Errorf("applying %s %s/%s: %w", gvr.Resource, namespace, name, err)
+		}
+		applied = append(applied, gvr.Resource+"/"+namespace+"/"+name)
+	}
+	return applied, nil
+}
+
+// pruneQuotas deletes the objects of gvr, in every namespace, carrying
+// labels and not in keep. keep nil deletes them all.
+func (r *WorkloadReconciler) pruneQuotas(ctx context.Context, gvr schema.GroupVersionResource, labels map[string]string, keep map[string]bool) error {
+	list, err := r.downstreamDyn.Resource(gvr).List(ctx, metav1.ListOptions{LabelSelector: metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: labels})})
+	if err != nil {
+		return fmt.Errorf("listing %s for prune: %w", gvr.Resource, err)
+	}
+	for i := range list.Items {
+		item := &list.Items[i]
+		if keep[gvr.Resource+"/"+item.GetNamespace()+"/"+item.GetName()] {
+			continue
+		}
+		if err := r.downstreamDyn.Resource(gvr).Namespace(item.GetNamespace()).Delete(ctx, item.GetName(), metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("pruning %s %s/%s: %w", gvr.Resource, item.GetNamespace(), item.GetName(), err)
+		}
+		klog.FromContext(ctx).Info("Pruned quota", "resource", gvr.Resource, "namespace", item.GetNamespace(), "name", item.GetName())
+	}
+	return nil
+}
+
+// pruneWorkloadQuotas deletes the Workload policy materialized for a
+// deleted placement.
+func (r *WorkloadReconciler) pruneWorkloadQuotas(ctx context.Context, placementName string) error {
+	labels := map[string]string{labelEdge: r.edgeName, labelQuota: quotaScopeWorkload, labelPlacement: placementName}
+	for _, gvr := range quotaResources {
+		if err := r.pruneQuotas(ctx, gvr, labels, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+// quotaObject returns a downstream object the agent materialized from a
+// namespaceQuota.
+func quotaObject(kind, namespace, name string, labels map[string]string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("v1")
+	u.SetKind(kind)
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	u.SetLabels(labels)
+	return u
+}
+
+func TestApplyNamespaceQuotas(t *testing.T) {
+	edge := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "edges.kedge.faros.sh/v1alpha1",
+		"kind":       "KubernetesCluster",
+		"metadata":   map[string]interface{}{"name": "edge-1"},
+		"spec": map[string]interface{}{"namespaceQuota": map[string]interface{}{
+			"resourceQuota": map[string]interface{}{"hard": map[string]interface{}{"requests.cpu": "8"}},
+		}},
+	}}
+	workload := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "edges.kedge.faros.sh/v1alpha1",
+		"kind":       "Workload",
+		"metadata":   map[string]interface{}{"name": "web", "namespace": "default"},
+		"spec": map[string]interface{}{"namespaceQuota": map[string]interface{}{
+			"limitRange": map[string]interface{}{"limits": []interface{}{map[string]interface{}{
+				"type":           "Container",
+				"defaultRequest": map[string]interface{}{"cpu": "100m"},
+			}}},
+		}},
+	}}
+	hub := fake.NewSimpleDynamicClient(runtime.NewScheme(), edge, workload)
+
+	edgeLabels := map[string]string{labelEdge: "edge-1", labelQuota: quotaScopeEdge}
+	workloadLabels := map[string]string{labelEdge: "edge-1", labelQuota: quotaScopeWorkload, labelPlacement: "web-edge-1", labelWorkload: "web"}
+	downstream := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{resourceQuotaGVR: "ResourceQuotaList", limitRangeGVR: "LimitRangeList"},
+		// The edge no longer asks for a LimitRange; the Workload's quota
+		// was applied to a namespace its bundle no longer uses.
+		quotaObject("LimitRange", "default", edgeQuotaName, edgeLabels),
+		quotaObject("LimitRange", "old", workloadQuotaPrefix+"web", workloadLabels),
+		quotaObject("LimitRange", "default", workloadQuotaPrefix+"api", map[string]string{
+			labelEdge: "edge-1", labelQuota: quotaScopeWorkload, labelPlacement: "api-edge-1", labelWorkload: "api",
+		}),
+	)
+	var applied []string
+	downstream.PrependReactor("patch", "*", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		patch := action.(clienttesting.PatchAction)
+		if patch.GetNamespace() == "monitoring" {
+			return true, nil, apierrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, "monitoring")
+		}
+		applied = append(applied, patch.GetResource().Resource+"/"+patch.GetNamespace()+"/"+patch.GetName())
+		return true, &unstructured.Unstructured{}, nil
+	})
+
+	placement := &placementView{ObjectMeta: metav1.ObjectMeta{Name: "web-edge-1", Namespace: "default"}}
+	placement.Spec.EdgeName = "edge-1"
+	placement.Spec.WorkloadRef.Name = "web"
+	placement.Spec.Manifests = []runtime.RawExtension{
+		{Raw: []byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web"}}`)},
+		{Raw: []byte(`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"monitoring"}}`)},
+		{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cfg","namespace":"monitoring"}}`)},
+	}
+	if got := strings.Join(quotaNamespaces(placement), ","); got != "default,monitoring" {
+		t.Fatalf("quotaNamespaces = %s", got)
+	}
+
+	r := &WorkloadReconciler{edgeName: "edge-1", hubDynamic: hub, downstreamDyn: downstream}
+	ctx := context.Background()
+	quotas, err := r.namespaceQuotas(ctx, placement)
+	if err != nil {
+		t.Fatalf("namespaceQuotas: %v", err)
+	}
+	if err := r.applyNamespaceQuotas(ctx, placement, quotas); err != nil {
+		t.Fatalf("applyNamespaceQuotas: %v", err)
+	}
+
+	// The monitoring namespace does not exist yet and is skipped.
+	sort.Strings(applied)
+	if got := strings.Join(applied, ","); got != "limitranges/default/kedge-workload-web,resourcequotas/default/kedge-edge" {
+		t.Errorf("applied = %s", got)
+	}
+	var deleted []string
+	for _, a := range downstream.Actions() {
+		if a.GetVerb() == "delete" {
+			deleted = append(deleted, a.GetResource().Resource+"/"+a.GetNamespace()+"/"+a.(clienttesting.DeleteAction).GetName())
+		}
+	}
+	sort.Strings(deleted)
+	if got := strings.Join(deleted, ","); got != "limitranges/default/kedge-edge,limitranges/old/kedge-workload-web" {
+		t.Errorf("deleted = %s", got)
+	}
+
+	// A deleted placement takes its Workload's quota along.
+	if err := r.pruneWorkloadQuotas(ctx, "api-edge-1"); err != nil {
+		t.Fatalf("pruneWorkloadQuotas: %v", err)
+	}
+	if _, err := downstream.Resource(limitRangeGVR).Namespace("default").Get(ctx, workloadQuotaPrefix+"api", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("quota of deleted placement: err = %v, want NotFound", err)
+	}
+}
@@ -28,6 +28,7 @@ package reconciler
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -38,18 +39,22 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/discovery"
 	memcache "k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/dynamic/dynamicinformer"
-	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/csaupgrade"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
+
+	"github.com/faroshq/faros-kedge/pkg/agent/health"
 )
 
 const controllerName = "workload-reconciler"
@@ -57,6 +62,11 @@ const controllerName = "workload-reconciler"
 // fieldManager identifies the agent's server-side-apply writes on the edge.
 const fieldManager = "kedge-agent"
 
+// legacyFieldManager owns the fields of Deployments that agents created and
+// updated before they applied them: the API server names a manager after
+// the client's binary.
+const legacyFieldManager = "kedge"
+
 // resyncPeriod for the Placement informer.
 const resyncPeriod = 10 * time.Minute
 
@@ -80,6 +90,8 @@ const (
 var (
 	placementGVR = schema.GroupVersionResource{Group: edgesGroup, Version: edgesVersion, Resource: "placements"}
 	workloadGVR  = schema.GroupVersionResource{Group: edgesGroup, Version: edgesVersion, Resource: "workloads"}
+
+	deploymentGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
 )
 
 // prunableResources are the namespaced kinds the agent will garbage-collect when
@@ -88,7 +100,7 @@ var (
 // Cluster-scoped objects (e.g. a chart's ClusterRoleBinding) are not pruned in
 // v1 — see docs/edges-marketplace.md.
 var prunableResources = []schema.GroupVersionResource{
-	{Group: "apps", Version: "v1", Resource: "deployments"},
+	deploymentGVR,
 	{Group: "apps", Version: "v1", Resource: "statefulsets"},
 	{Group: "apps", Version: "v1", Resource: "daemonsets"},
 	{Group: "", Version: "v1", Resource: "services"},
@@ -110,7 +122,13 @@ type placementView struct {
 		EdgeName    string                 `json:"edgeName"`
 		Replicas    *int32                 `json:"replicas,omitempty"`
 		Manifests   []runtime.RawExtension `json:"manifests,omitempty"`
+		// ManifestsRef replaces Manifests when the bundle is held in the
+		// provider's manifest store; resolveManifests fills Manifests in.
+		ManifestsRef *manifestsRef `json:"manifestsRef,omitempty"`
 	} `json:"spec,omitempty"`
+	Status struct {
+		Conditions []metav1.Condition `json:"conditions,omitempty"`
+	} `json:"status,omitempty"`
 }
 
 // workloadView is the subset of a Workload the agent reads.
@@ -138,22 +156,37 @@ type simpleWorkload struct {
 // server-side apply; otherwise it falls back to synthesizing a Deployment from
 // the referenced Workload (legacy placements).
 type WorkloadReconciler struct {
-	edgeName         string
-	hubDynamic       dynamic.Interface
-	downstreamClient kubernetes.Interface
-	downstreamDyn    dynamic.Interface
-	mapper           meta.RESTMapper
-	queue            workqueue.TypedRateLimitingInterface[string]
+	edgeName      string
+	hubDynamic    dynamic.Interface
+	downstreamDyn dynamic.Interface
+	mapper        meta.RESTMapper
+	queue         workqueue.TypedRateLimitingInterface[string]
+
+	// placements is the Placement informer's store, read to share the edge's
+	// workload budget among its placements (budget.go). Set by Run.
+	placements cache.Store
+
+	// health, if set, records each placement's reconcile failures.
+	health *health.Tracker
+
+	// manifests, if set, fetches the bundles Placements reference from the
+	// provider's manifest store.
+	manifests *ManifestFetcher
+
+	// gitOpsTool, if set, is handed the placements whose Workload names a
+	// Git source; its objects go into gitOpsNamespace (gitops.go).
+	gitOpsTool      GitOpsTool
+	gitOpsNamespace string
+
+	// applyConcurrency bounds the objects of a bundle applied at once
+	// (ordering.go). Zero uses DefaultApplyConcurrency.
+	applyConcurrency int
 }
 
 // NewWorkloadReconciler creates a workload reconciler. hubDynamic is a dynamic
 // client scoped to the edge's tenant workspace; downstreamConfig targets the
 // edge's local cluster.
 func NewWorkloadReconciler(edgeName string, hubDynamic dynamic.Interface, downstreamConfig *rest.Config) (*WorkloadReconciler, error) {
-	downstreamClient, err := kubernetes.NewForConfig(downstreamConfig)
-	if err != nil {
-		return nil, fmt.Errorf("building downstream client: %w", err)
-	}
 	downstreamDyn, err := dynamic.NewForConfig(downstreamConfig)
 	if err != nil {
 		return nil, fmt.Errorf("building downstream dynamic client: %w", err)
@@ -163,11 +196,10 @@ func NewWorkloadReconciler(edgeName string, hubDynamic dynamic.Interface, downst
 		return nil, fmt.Errorf("building downstream discovery client: %w", err)
 	}
 	return &WorkloadReconciler{
-		edgeName:         edgeName,
-		hubDynamic:       hubDynamic,
-		downstreamClient: downstreamClient,
-		downstreamDyn:    downstreamDyn,
-		mapper:           restmapper.NewDeferredDiscoveryRESTMapper(memcache.NewMemCacheClient(dc)),
+		edgeName:      edgeName,
+		hubDynamic:    hubDynamic,
+		downstreamDyn: downstreamDyn,
+		mapper:        restmapper.NewDeferredDiscoveryRESTMapper(memcache.NewMemCacheClient(dc)),
 		queue: workqueue.NewTypedRateLimitingQueueWithConfig(
 			workqueue.DefaultTypedControllerRateLimiter[string](),
 			workqueue.TypedRateLimitingQueueConfig[string]{Name: controllerName},
@@ -175,6 +207,19 @@ func NewWorkloadReconciler(edgeName string, hubDynamic dynamic.Interface, downst
 	}, nil
 }
 
+// SetHealth has the reconciler record each placement's reconcile failures in
+// tracker, under "workloads/<namespace>/<name>". Call before Run.
+func (r *WorkloadReconciler) SetHealth(tracker *health.Tracker) {
+	r.health = tracker
+}
+
+// SetManifestFetcher has the reconciler apply Placements whose bundle is
+// held in the provider's manifest store (spec.manifestsRef), fetching it
+// with f. Without one such placements fail to reconcile. Call before Run.
+func (r *WorkloadReconciler) SetManifestFetcher(f *ManifestFetcher) {
+	r.manifests = f
+}
+
 // Run starts the workload reconciler.
 func (r *WorkloadReconciler) Run(ctx context.Context) error {
 	defer utilruntime.HandleCrash()
@@ -183,7 +228,12 @@ func (r *WorkloadReconciler) Run(ctx context.Context) error {
 	logger := klog.FromContext(ctx).WithName(controllerName)
 	logger.Info("Starting workload reconciler", "edgeName", r.edgeName)
 
-	// Placement informer filtered to this edge.
+	// Placement informer filtered to this edge. Placements are consumed by
+	// watch, not polled: the reflector asks for bookmarks, resumes a dropped
+	// watch through the hub proxy from the last resourceVersion it saw, and
+	// re-lists only when the hub answers 410 Gone, backing off while the
+	// tunnel is down. The resync replays the cache locally without calling
+	// the hub.
 	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(
 		r.hubDynamic, resyncPeriod, metav1.NamespaceAll,
 		func(opts *metav1.ListOptions) {
@@ -191,6 +241,7 @@ func (r *WorkloadReconciler) Run(ctx context.Context) error {
 		},
 	)
 	placementInformer := factory.ForResource(placementGVR).Informer()
+	r.placements = placementInformer.GetStore()
 
 	if _, err := placementInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    func(obj interface{}) { r.enqueue(obj) },
@@ -206,6 +257,7 @@ func (r *WorkloadReconciler) Run(ctx context.Context) error {
 	for i := 0; i < 2; i++ {
 		go wait.UntilWithContext(ctx, r.worker, time.Second)
 	}
+	go wait.UntilWithContext(ctx, r.collectOrphans, gcInterval)
 
 	<-ctx.Done()
 	logger.Info("Shutting down workload reconciler")
@@ -233,7 +285,9 @@ func (r *WorkloadReconciler) processNextWorkItem(ctx context.Context) bool {
 	}
 	defer r.queue.Done(key)
 
-	if err := r.reconcile(ctx, key); err != nil {
+	err := r.reconcile(ctx, key)
+	r.health.Observe("workloads/"+key, err)
+	if err != nil {
 		utilruntime.HandleError(fmt.Errorf("reconciling %q: %w", key, err))
 		r.queue.AddRateLimited(key)
 		return true
@@ -254,7 +308,17 @@ func (r *WorkloadReconciler) reconcile(ctx context.Context, key string) error {
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			logger.Info("Placement deleted, pruning local objects")
-			return r.prune(ctx, name, nil)
+			if err := r.prune(ctx, name, nil); err != nil {
+				return err
+			}
+			if err := r.pruneGitOps(ctx, name); err != nil {
+				return err
+			}
+			if err := r.pruneWorkloadQuotas(ctx, name); err != nil {
+				return err
+			}
+			r.enqueueRefusedPlacements()
+			return nil
 		}
 		return err
 	}
@@ -268,9 +332,58 @@ func (r *WorkloadReconciler) reconcile(ctx context.Context, key string) error {
 		return nil
 	}
 
+	// Handoff: a GitOps tool on the edge syncs the Workload's Git source.
+	// Budgets are not enforced; what the repository holds is unknown here.
+	src, handOff, err := r.workloadGitOpsSource(ctx, &placement)
+	if err != nil {
+		return err
+	}
+	if handOff {
+		if err := r.handOff(ctx, &placement, src); err != nil {
+			return err
+		}
+		return r.recordApplied(ctx, pu, &placement)
+	}
+	// Taking a placement back from the tool lets it garbage-collect what it
+	// synced; the apply below then restores the workload.
+	if err := r.pruneGitOps(ctx, name); err != nil {
+		return err
+	}
+
+	if err := r.resolveManifests(ctx, &placement); err != nil {
+		return err
+	}
+
+	// Refuse placements that would overrun the edge's workload budget; what
+	// was applied for an earlier revision keeps running.
+	if fits, err := r.enforceBudget(ctx, pu, &placement); err != nil || !fits {
+		return err
+	}
+
+	// Materialize the edge's and the Workload's namespace quotas before the
+	// workload, so its pods are admitted under them (quota.go).
+	quotas, err := r.namespaceQuotas(ctx, &placement)
+	if err != nil {
+		return err
+	}
+	if err := r.applyNamespaceQuotas(ctx, &placement, quotas); err != nil {
+		return err
+	}
+
 	// Preferred path: apply the provider-rendered manifest bundle.
 	if len(placement.Spec.Manifests) > 0 {
-		return r.applyBundle(ctx, &placement)
+		compat, err := r.applyBundle(ctx, &placement)
+		if err != nil {
+			return err
+		}
+		// Again for the namespaces the bundle created.
+		if err := r.applyNamespaceQuotas(ctx, &placement, quotas); err != nil {
+			return err
+		}
+		if err := r.recordCompat(ctx, &placement, compat); err != nil {
+			return err
+		}
+		return r.recordApplied(ctx, pu, &placement)
 	}
 
 	// Legacy fallback: no bundle (placement predates provider-side rendering) —
@@ -290,21 +403,71 @@ func (r *WorkloadReconciler) reconcile(ctx context.Context, key string) error {
 		return fmt.Errorf("converting to deployment: %w", err)
 	}
 
-	existing, err := r.downstreamClient.AppsV1().Deployments(deployment.Namespace).Get(ctx, deployment.Name, metav1.GetOptions{})
-	if apierrors.IsNotFound(err) {
-		logger.Info("Creating local deployment", "name", deployment.Name)
-		_, err = r.downstreamClient.AppsV1().Deployments(deployment.Namespace).Create(ctx, deployment, metav1.CreateOptions{})
-		return err
-	} else if err != nil {
+	if err := r.applyDeployment(ctx, deployment); err != nil {
 		return err
 	}
+	// The Deployment is all a legacy placement owns: objects of an earlier
+	// bundle, or the Deployment of a renamed Workload, are pruned.
+	keep := map[appliedRef]bool{{gvr: deploymentGVR, name: deployment.Name}: true}
+	return r.prune(ctx, placement.Name, keep)
+}
+
+// applyDeployment server-side applies a legacy placement's Deployment as the
+// agent's field manager, so fields dropped from the Workload are removed on
+// the edge while fields other managers own (an HPA's replicas, say) are kept.
+// Deployments written by agents that still created and updated them are
+// first handed over to the apply manager; otherwise the fields those writes
+// own would never be removed.
+func (r *WorkloadReconciler) applyDeployment(ctx context.Context, deployment *appsv1.Deployment) error {
+	ri := r.downstreamDyn.Resource(deploymentGVR).Namespace(deployment.Namespace)
+	existing, err := ri.Get(ctx, deployment.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return fmt.Errorf("getting deployment %q: %w", deployment.Name, err)
+	default:
+		patch, err := csaupgrade.UpgradeManagedFieldsPatch(existing, sets.New(legacyFieldManager), fieldManager)
+		if err != nil {
+			return fmt.Errorf("upgrading managed fields of deployment %q: %w", deployment.Name, err)
+		}
+		if patch != nil {
+			if _, err := ri.Patch(ctx, deployment.Name, types.JSONPatchType, patch, metav1.PatchOptions{}); err != nil {
+				return fmt.Errorf("upgrading managed fields of deployment %q: %w", deployment.Name, err)
+			}
+		}
+	}
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(deployment)
+	if err != nil {
+		return fmt.Errorf("converting deployment %q: %w", deployment.Name, err)
+	}
+	u := &unstructured.Unstructured{Object: obj}
+	u.SetAPIVersion("apps/v1")
+	u.SetKind("Deployment")
+	unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(u.Object, "status")
+	if _, err := ri.Apply(ctx, deployment.Name, u, metav1.ApplyOptions{FieldManager: fieldManager, Force: true}); err != nil {
+		return fmt.Errorf("applying deployment %q: %w", deployment.Name, err)
+	}
+	klog.FromContext(ctx).V(4).Info("Applied local deployment", "name", deployment.Name)
+	return nil
+}
 
-	logger.V(4).Info("Updating local deployment", "name", deployment.Name)
-	existing.Spec = deployment.Spec
-	existing.Labels = deployment.Labels
-	existing.Annotations = deployment.Annotations
-	_, err = r.downstreamClient.AppsV1().Deployments(deployment.Namespace).Update(ctx, existing, metav1.UpdateOptions{})
-	return err
+// recordApplied stamps status.observedGeneration with the Placement generation
+// whose bundle was just applied, so hub users can tell which revision the edge
+// is running. It is a no-op when the status already reports that generation.
+func (r *WorkloadReconciler) recordApplied(ctx context.Context, pu *unstructured.Unstructured, placement *placementView) error {
+	observed, _, _ := unstructured.NestedInt64(pu.Object, "status", "observedGeneration")
+	if observed == placement.Generation {
+		return nil
+	}
+	patch := []byte(fmt.Sprintf(`{"status":{"observedGeneration":%d}}`, placement.Generation))
+	if _, err := r.hubDynamic.Resource(placementGVR).Namespace(placement.Namespace).Patch(
+		ctx, placement.Name, types.MergePatchType, patch, metav1.PatchOptions{}, "status",
+	); err != nil {
+		return fmt.Errorf("recording applied generation on placement %s: %w", placement.Name, err)
+	}
+	return nil
 }
 
 // appliedRef identifies one applied object for prune bookkeeping.
@@ -315,43 +478,40 @@ type appliedRef struct {
 
 // applyBundle applies each rendered object with server-side apply, stamps the
 // placement/workload labels the status reporter + prune rely on, then prunes any
-// previously-applied object that is no longer in the bundle.
-func (r *WorkloadReconciler) applyBundle(ctx context.Context, placement *placementView) error {
-	logger := klog.FromContext(ctx).WithValues("placement", placement.Name)
-	keep := make(map[appliedRef]bool, len(placement.Spec.Manifests))
-
+// previously-applied object that is no longer in the bundle. Objects are
+// applied in dependency order, phase by phase (ordering.go), waiting for the
+// bundle's CRDs to be established before its custom resources. Objects are
+// fitted to the edge cluster's API versions (compat.go); what that took, and
+// the objects the agent's RBAC refused, is returned for the Compatible
+// condition.
+func (r *WorkloadReconciler) applyBundle(ctx context.Context, placement *placementView) (*bundleCompat, error) {
+	objs := make([]*unstructured.Unstructured, 0, len(placement.Spec.Manifests))
 	for i, raw := range placement.Spec.Manifests {
 		obj := &unstructured.Unstructured{}
 		if err := obj.UnmarshalJSON(raw.Raw); err != nil {
-			return fmt.Errorf("decoding manifest[%d] of placement %s: %w", i, placement.Name, err)
+			return nil, fmt.Errorf("decoding manifest[%d] of placement %s: %w", i, placement.Name, err)
 		}
-		gvk := obj.GroupVersionKind()
-		mapping, err := r.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		objs = append(objs, obj)
+	}
+
+	keep := make(map[appliedRef]bool, len(objs))
+	compat := &bundleCompat{}
+	for _, phase := range orderBundle(objs) {
+		crds, err := r.applyObjects(ctx, placement, phase, keep, compat)
 		if err != nil {
-			return fmt.Errorf("no REST mapping for %s: %w", gvk, err)
+			return nil, err
 		}
-
-		var ri dynamic.ResourceInterface
-		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
-			ns := obj.GetNamespace()
-			if ns == "" {
-				ns = targetNamespace
-				obj.SetNamespace(ns)
+		if len(crds) > 0 {
+			if err := r.waitForCRDs(ctx, crds); err != nil {
+				return nil, err
 			}
-			ri = r.downstreamDyn.Resource(mapping.Resource).Namespace(ns)
-		} else {
-			ri = r.downstreamDyn.Resource(mapping.Resource)
-		}
-
-		r.stampPlacementMeta(obj, placement)
-		if _, err := ri.Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{FieldManager: fieldManager, Force: true}); err != nil {
-			return fmt.Errorf("applying %s %q: %w", mapping.Resource.Resource, obj.GetName(), err)
 		}
-		keep[appliedRef{gvr: mapping.Resource, name: obj.GetName()}] = true
-		logger.V(4).Info("Applied object", "kind", gvk.Kind, "name", obj.GetName())
 	}
+	// Concurrent applies record refusals in any order; keep the condition
+	// message stable.
+	sort.Strings(compat.forbidden)
 
-	return r.prune(ctx, placement.Name, keep)
+	return compat, r.prune(ctx, placement.Name, keep)
 }
 
 // prune deletes objects labeled for this placement that are not in keep. keep
@@ -429,11 +589,12 @@ func convertToDeployment(vw *workloadView, placement *placementView) (*appsv1.De
 			Labels: map[string]string{
 				labelWorkload:  vw.Name,
 				labelPlacement: placement.Name,
+				labelEdge:      placement.Spec.EdgeName,
 			},
 			Annotations: map[string]string{
-				edgesGroup + "/placement-name":      placement.Name,
-				edgesGroup + "/placement-namespace": placement.Namespace,
-				edgesGroup + "/placement-uid":       string(placement.UID),
+				annPlacementName:      placement.Name,
+				annPlacementNamespace: placement.Namespace,
+				annPlacementUID:       string(placement.UID),
 			},
 		},
 		Spec: appsv1.DeploymentSpec{
@@ -0,0 +1,170 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestRecordApplied(t *testing.T) {
+	tests := []struct {
+		name       string
+		observed   int64 // status.observedGeneration on the hub; 0 = unset
+		generation int64
+		onHub      bool
+		wantPatch  bool
+		wantErr    bool
+	}{
+		{name: "first apply", generation: 1, onHub: true, wantPatch: true},
+		{name: "new generation", observed: 2, generation: 3, onHub: true, wantPatch: true},
+		{name: "already recorded", observed: 3, generation: 3, onHub: true},
+		{name: "placement gone", generation: 1, wantPatch: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pu := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "edges.kedge.faros.sh/v1alpha1",
+				"kind":       "Placement",
+				"metadata": map[string]interface{}{
+					"name":       "web-edge-1",
+					"namespace":  "default",
+					"generation": tt.generation,
+				},
+			}}
+			if tt.observed != 0 {
+				if err := unstructured.SetNestedField(pu.Object, tt.observed, "status", "observedGeneration"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var objs []runtime.Object
+			if tt.onHub {
+				objs = append(objs, pu.DeepCopy())
+			}
+			hub := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+				map[schema.GroupVersionResource]string{placementGVR: "PlacementList"}, objs...)
+			r := &WorkloadReconciler{hubDynamic: hub}
+			placement := &placementView{ObjectMeta: metav1.ObjectMeta{Name: "web-edge-1", Namespace: "default", Generation: tt.generation}}
+
+			err := r.recordApplied(context.Background(), pu, placement)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("recordApplied() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			patches := 0
+			for _, a := range hub.Actions() {
+				if a.GetVerb() == "patch" {
+					patches++
+					if a.GetSubresource() != "status" {
+						t.Errorf("patched subresource %q, want status", a.GetSubresource())
+					}
+				}
+			}
+			if got := patches > 0; got != tt.wantPatch {
+				t.Fatalf("patched = %v, want %v", got, tt.wantPatch)
+			}
+			if !tt.onHub || !tt.wantPatch {
+				return
+			}
+			got, err := hub.Resource(placementGVR).Namespace("default").Get(context.Background(), "web-edge-1", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if g, _, _ := unstructured.NestedInt64(got.Object, "status", "observedGeneration"); g != tt.generation {
+				t.Errorf("status.observedGeneration = %d, want %d", g, tt.generation)
+			}
+		})
+	}
+}
+
+func TestApplyDeploymentPrunesRemoved(t *testing.T) {
+	placement := &placementView{ObjectMeta: metav1.ObjectMeta{Name: "web-edge-1", Namespace: "default"}}
+	placement.Spec.EdgeName = "edge-1"
+	vw := &workloadView{ObjectMeta: metav1.ObjectMeta{Name: "web"}}
+	vw.Spec.Simple = &simpleWorkload{Image: "nginx"}
+	deployment, err := convertToDeployment(vw, placement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An earlier agent updated "web"; "old-web" and the ConfigMap were
+	// applied for an earlier revision of the placement.
+	existing := managedObject("Deployment", "default", "web", "edge-1", "default", "web-edge-1")
+	existing.SetManagedFields([]metav1.ManagedFieldsEntry{{
+		Manager:    legacyFieldManager,
+		Operation:  metav1.ManagedFieldsOperationUpdate,
+		APIVersion: "apps/v1",
+		FieldsType: "FieldsV1",
+		FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:replicas":{}}}`)},
+	}})
+	listKinds := map[schema.GroupVersionResource]string{}
+	for _, gvr := range prunableResources {
+		listKinds[gvr] = "List"
+	}
+	downstream := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds,
+		existing,
+		managedObject("Deployment", "default", "old-web", "edge-1", "default", "web-edge-1"),
+		managedObject("ConfigMap", "default", "web-config", "edge-1", "default", "web-edge-1"),
+		managedObject("ConfigMap", "default", "other", "edge-1", "default", "other-edge-1"),
+	)
+	var patches []types.PatchType
+	downstream.PrependReactor("patch", "deployments", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		patch := action.(clienttesting.PatchAction)
+		patches = append(patches, patch.GetPatchType())
+		if patch.GetPatchType() == types.ApplyPatchType {
+			var applied map[string]interface{}
+			if err := json.Unmarshal(patch.GetPatch(), &applied); err != nil {
+				t.Errorf("decoding apply patch: %v", err)
+			}
+			if _, ok := applied["status"]; ok {
+				t.Error("apply patch carries status")
+			}
+		}
+		return true, &unstructured.Unstructured{}, nil
+	})
+
+	r := &WorkloadReconciler{edgeName: "edge-1", downstreamDyn: downstream}
+	if err := r.applyDeployment(context.Background(), deployment); err != nil {
+		t.Fatalf("applyDeployment: %v", err)
+	}
+	if len(patches) != 2 || patches[0] != types.JSONPatchType || patches[1] != types.ApplyPatchType {
+		t.Errorf("patches = %v, want the managed fields upgrade, then the apply", patches)
+	}
+	keep := map[appliedRef]bool{{gvr: deploymentGVR, name: "web"}: true}
+	if err := r.prune(context.Background(), placement.Name, keep); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+
+	var deleted []string
+	for _, a := range downstream.Actions() {
+		if a.GetVerb() == "delete" {
+			deleted = append(deleted, a.GetResource().Resource+"/"+a.(clienttesting.DeleteAction).GetName())
+		}
+	}
+	if got := strings.Join(deleted, ","); got != "deployments/old-web,configmaps/web-config" {
+		t.Errorf("deleted = %s", got)
+	}
+}
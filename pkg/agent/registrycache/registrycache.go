@@ -0,0 +1,490 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package registrycache runs the optional edge-local pull-through image cache
+// configured by a KubernetesCluster's spec.registryCache.
+//
+// Sites with many nodes behind a thin uplink otherwise download every image
+// once per node. The agent deploys a single distribution registry in proxy
+// mode, reports its in-cluster endpoint so nodes can be pointed at it as a
+// mirror, and mirrors the cache's hit/miss counters into
+// status.registryCache.
+//
+// Like the workload reconciler, the KubernetesCluster type is read as
+// unstructured so the agent needs no import of the edges provider module, and
+// it is consumed by watch: the cache is synced when the edge's
+// spec.registryCache or status.registryCache changes, and polled only while
+// enabled, to scrape its counters.
+package registrycache
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	appsv1ac "k8s.io/client-go/applyconfigurations/apps/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	metav1ac "k8s.io/client-go/applyconfigurations/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+)
+
+const (
+	// Namespace is where the cache workload runs on the edge.
+	Namespace = "kedge-system"
+	// Name is the name of the cache Deployment, Service and PVC.
+	Name = "kedge-registry-cache"
+
+	// DefaultImage is a distribution release with proxy mode and the
+	// Prometheus debug endpoint.
+	DefaultImage = "registry:2.8.3"
+	// DefaultUpstream is used when spec.registryCache.upstream is empty.
+	DefaultUpstream = "https://registry-1.docker.io"
+
+	// SyncInterval is how often an enabled cache is re-synced and its
+	// counters scraped. A disabled cache is synced on edge changes only.
+	SyncInterval = 30 * time.Second
+	// resyncPeriod replays the edge informer's cache; it does not call the
+	// hub.
+	resyncPeriod = 10 * time.Minute
+
+	registryPort = 5000
+	debugPort    = 5001
+
+	fieldManager = "kedge-agent"
+)
+
+// spec mirrors the fields of KubernetesCluster.spec.registryCache the agent
+// acts on.
+type spec struct {
+	Enabled     bool   `json:"enabled"`
+	Upstream    string `json:"upstream,omitempty"`
+	Image       string `json:"image,omitempty"`
+	StorageSize string `json:"storageSize,omitempty"`
+}
+
+// counters are the cache's cumulative blob counters.
+type counters struct {
+	Requests, Hits, Misses int64
+}
+
+// Manager converges the edge's registry cache on the edge's spec.
+type Manager struct {
+	edgeName   string
+	hubDynamic dynamic.Interface
+	downstream kubernetes.Interface
+	// edges is the edge informer's store, holding this edge only.
+	edges cache.Store
+	queue workqueue.TypedRateLimitingInterface[string]
+}
+
+// NewManager creates a Manager. hubDynamic is scoped to the edge's tenant
+// workspace; downstream targets the edge cluster.
+func NewManager(edgeName string, hubDynamic dynamic.Interface, downstream kubernetes.Interface) *Manager {
+	return &Manager{
+		edgeName:   edgeName,
+		hubDynamic: hubDynamic,
+		downstream: downstream,
+		queue: workqueue.NewTypedRateLimitingQueueWithConfig(
+			workqueue.DefaultTypedControllerRateLimiter[string](),
+			workqueue.TypedRateLimitingQueueConfig[string]{Name: "registry-cache"},
+		),
+	}
+}
+
+// Run watches the edge and syncs the cache when its registry cache spec or
+// status changes, and every SyncInterval while the cache is enabled. It
+// blocks until ctx is cancelled.
+func (m *Manager) Run(ctx context.Context) error {
+	defer utilruntime.HandleCrash()
+	defer m.queue.ShutDown()
+
+	logger := klog.FromContext(ctx).WithName("registry-cache")
+	logger.Info("Starting registry cache manager", "edgeName", m.edgeName)
+	ctx = klog.NewContext(ctx, logger)
+
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(
+		m.hubDynamic, resyncPeriod, metav1.NamespaceAll,
+		func(opts *metav1.ListOptions) {
+			opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", m.edgeName).String()
+		},
+	)
+	informer := factory.ForResource(kedgeclient.KubernetesClusterGVR).Informer()
+	m.edges = informer.GetStore()
+	// The edge's status is written on every heartbeat; only changes to the
+	// registry cache's own fields warrant a sync.
+	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(interface{}) { m.queue.Add(m.edgeName) },
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if registryCacheChanged(oldObj, newObj) {
+				m.queue.Add(m.edgeName)
+			}
+		},
+	}); err != nil {
+		return fmt.Errorf("adding event handler: %w", err)
+	}
+
+	factory.Start(ctx.Done())
+	factory.WaitForCacheSync(ctx.Done())
+
+	go wait.UntilWithContext(ctx, m.worker, time.Second)
+
+	<-ctx.Done()
+	logger.Info("Shutting down registry cache manager")
+	return nil
+}
+
+func (m *Manager) worker(ctx context.Context) {
+	for m.processNextWorkItem(ctx) {
+	}
+}
+
+func (m *Manager) processNextWorkItem(ctx context.Context) bool {
+	key, quit := m.queue.Get()
+	if quit {
+		return false
+	}
+	defer m.queue.Done(key)
+
+	if err := m.sync(ctx); err != nil {
+		utilruntime.HandleError(fmt.Errorf("syncing registry cache: %w", err))
+		m.queue.AddRateLimited(key)
+		return true
+	}
+	m.queue.Forget(key)
+	if m.enabled() {
+		m.queue.AddAfter(key, SyncInterval)
+	}
+	return true
+}
+
+// enabled reports whether the watched edge has the cache enabled.
+func (m *Manager) enabled() bool {
+	obj, exists, err := m.edges.GetByKey(m.edgeName)
+	if err != nil || !exists {
+		return false
+	}
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return false
+	}
+	enabled, _, _ := unstructured.NestedBool(u.Object, "spec", "registryCache", "enabled")
+	return enabled
+}
+
+// registryCacheChanged reports whether spec.registryCache or
+// status.registryCache differ between two versions of the edge.
+func registryCacheChanged(oldObj, newObj interface{}) bool {
+	o, ok1 := oldObj.(*unstructured.Unstructured)
+	n, ok2 := newObj.(*unstructured.Unstructured)
+	if !ok1 || !ok2 {
+		return true
+	}
+	for _, field := range [][]string{{"spec", "registryCache"}, {"status", "registryCache"}} {
+		ov, _, _ := unstructured.NestedFieldNoCopy(o.Object, field...)
+		nv, _, _ := unstructured.NestedFieldNoCopy(n.Object, field...)
+		if !reflect.DeepEqual(ov, nv) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Manager) sync(ctx context.Context) error {
+	edge, err := m.hubDynamic.Resource(kedgeclient.KubernetesClusterGVR).Get(ctx, m.edgeName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("getting edge %q: %w", m.edgeName, err)
+	}
+
+	var s spec
+	raw, found, _ := unstructured.NestedMap(edge.Object, "spec", "registryCache")
+	if found {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &s); err != nil {
+			return fmt.Errorf("decoding spec.registryCache: %w", err)
+		}
+	}
+
+	prev, reported, _ := unstructured.NestedMap(edge.Object, "status", "registryCache")
+
+	if !s.Enabled {
+		// Most edges never enable the cache; only touch the downstream
+		// cluster when it was enabled before or left resources behind.
+		if !reported {
+			provisioned, err := m.provisioned(ctx)
+			if err != nil || !provisioned {
+				return err
+			}
+		}
+		if err := m.teardown(ctx); err != nil {
+			return err
+		}
+		if reported {
+			return m.patchStatus(ctx, nil)
+		}
+		return nil
+	}
+
+	if err := m.apply(ctx, s); err != nil {
+		return err
+	}
+
+	status := map[string]interface{}{"ready": false}
+	if svc, err := m.downstream.CoreV1().Services(Namespace).Get(ctx, Name, metav1.GetOptions{}); err == nil && svc.Spec.ClusterIP != "" {
+		status["endpoint"] = fmt.Sprintf("http://%s:%d", svc.Spec.ClusterIP, registryPort)
+	}
+	if dep, err := m.downstream.AppsV1().Deployments(Namespace).Get(ctx, Name, metav1.GetOptions{}); err == nil && dep.Status.AvailableReplicas > 0 {
+		status["ready"] = true
+		// Counters are only scraped from a running cache; a failed scrape
+		// leaves the previously reported values in place.
+		if c, err := m.scrape(ctx); err != nil {
+			klog.FromContext(ctx).V(4).Info("Scraping registry cache metrics failed", "err", err)
+		} else {
+			status["requests"] = c.Requests
+			status["hits"] = c.Hits
+			status["misses"] = c.Misses
+		}
+	}
+	if reported && !statusChanged(prev, status) {
+		return nil
+	}
+	if _, ok := status["requests"]; ok {
+		status["lastScrapeTime"] = metav1.Now()
+	}
+	return m.patchStatus(ctx, status)
+}
+
+// statusChanged reports whether next differs from the reported status in any
+// field it sets. lastScrapeTime is ignored so an idle cache does not cause a
+// hub write every SyncInterval; it records when the counters last moved.
+func statusChanged(prev, next map[string]interface{}) bool {
+	for k, v := range next {
+		if k == "lastScrapeTime" {
+			continue
+		}
+		if !reflect.DeepEqual(prev[k], v) {
+			return true
+		}
+	}
+	return false
+}
+
+// provisioned reports whether any of the cache's downstream resources exist.
+// Only a successful lookup counts; errors other than NotFound are returned so
+// the sync is retried instead of tearing down on a guess.
+func (m *Manager) provisioned(ctx context.Context) (bool, error) {
+	for _, get := range []func() error{
+		func() error {
+			_, err := m.downstream.AppsV1().Deployments(Namespace).Get(ctx, Name, metav1.GetOptions{})
+			return err
+		},
+		func() error {
+			_, err := m.downstream.CoreV1().Services(Namespace).Get(ctx, Name, metav1.GetOptions{})
+			return err
+		},
+		func() error {
+			_, err := m.downstream.CoreV1().PersistentVolumeClaims(Namespace).Get(ctx, Name, metav1.GetOptions{})
+			return err
+		},
+	} {
+		switch err := get(); {
+		case err == nil:
+			return true, nil
+		case !apierrors.IsNotFound(err):
+			return false, fmt.Errorf("looking up registry cache resources: %w", err)
+		}
+	}
+	return false, nil
+}
+
+// apply server-side applies the cache Service, optional PVC and Deployment.
+func (m *Manager) apply(ctx context.Context, s spec) error {
+	image := s.Image
+	if image == "" {
+		image = DefaultImage
+	}
+	upstream := s.Upstream
+	if upstream == "" {
+		upstream = DefaultUpstream
+	}
+	labels := map[string]string{"app.kubernetes.io/name": Name, "app.kubernetes.io/managed-by": fieldManager}
+	opts := metav1.ApplyOptions{FieldManager: fieldManager, Force: true}
+
+	if _, err := m.downstream.CoreV1().Namespaces().Apply(ctx, corev1ac.Namespace(Namespace), opts); err != nil {
+		return fmt.Errorf("applying namespace %s: %w", Namespace, err)
+	}
+
+	svc := corev1ac.Service(Name, Namespace).
+		WithLabels(labels).
+		WithSpec(corev1ac.ServiceSpec().
+			WithSelector(map[string]string{"app.kubernetes.io/name": Name}).
+			WithPorts(
+				corev1ac.ServicePort().WithName("registry").WithPort(registryPort).WithTargetPort(intstr.FromInt32(registryPort)),
+				corev1ac.ServicePort().WithName("debug").WithPort(debugPort).WithTargetPort(intstr.FromInt32(debugPort)),
+			))
+	if _, err := m.downstream.CoreV1().Services(Namespace).Apply(ctx, svc, opts); err != nil {
+		return fmt.Errorf("applying registry cache service: %w", err)
+	}
+
+	volume := corev1ac.Volume().WithName("cache").WithEmptyDir(corev1ac.EmptyDirVolumeSource())
+	if s.StorageSize != "" {
+		size, err := resource.ParseQuantity(s.StorageSize)
+		if err != nil {
+			return fmt.Errorf("parsing spec.registryCache.storageSize %q: %w", s.StorageSize, err)
+		}
+		pvc := corev1ac.PersistentVolumeClaim(Name, Namespace).
+			WithLabels(labels).
+			WithSpec(corev1ac.PersistentVolumeClaimSpec().
+				WithAccessModes(corev1.ReadWriteOnce).
+				WithResources(corev1ac.VolumeResourceRequirements().
+					WithRequests(corev1.ResourceList{corev1.ResourceStorage: size})))
+		if _, err := m.downstream.CoreV1().PersistentVolumeClaims(Namespace).Apply(ctx, pvc, opts); err != nil {
+			return fmt.Errorf("applying registry cache volume claim: %w", err)
+		}
+		volume = corev1ac.Volume().WithName("cache").
+			WithPersistentVolumeClaim(corev1ac.PersistentVolumeClaimVolumeSource().WithClaimName(Name))
+	}
+
+	container := corev1ac.Container().
+		WithName("registry").
+		WithImage(image).
+		WithEnv(
+			corev1ac.EnvVar().WithName("REGISTRY_PROXY_REMOTEURL").WithValue(upstream),
+			corev1ac.EnvVar().WithName("REGISTRY_HTTP_ADDR").WithValue(fmt.Sprintf(":%d", registryPort)),
+			corev1ac.EnvVar().WithName("REGISTRY_HTTP_DEBUG_ADDR").WithValue(fmt.Sprintf(":%d", debugPort)),
+			corev1ac.EnvVar().WithName("REGISTRY_HTTP_DEBUG_PROMETHEUS_ENABLED").WithValue("true"),
+			corev1ac.EnvVar().WithName("REGISTRY_HTTP_DEBUG_PROMETHEUS_PATH").WithValue("/metrics"),
+		).
+		WithPorts(
+			corev1ac.ContainerPort().WithName("registry").WithContainerPort(registryPort),
+			corev1ac.ContainerPort().WithName("debug").WithContainerPort(debugPort),
+		).
+		WithVolumeMounts(corev1ac.VolumeMount().WithName("cache").WithMountPath("/var/lib/registry")).
+		WithReadinessProbe(corev1ac.Probe().
+			WithHTTPGet(corev1ac.HTTPGetAction().WithPath("/").WithPort(intstr.FromInt32(registryPort))))
+
+	dep := appsv1ac.Deployment(Name, Namespace).
+		WithLabels(labels).
+		WithSpec(appsv1ac.DeploymentSpec().
+			WithReplicas(1).
+			WithSelector(metav1ac.LabelSelector().WithMatchLabels(map[string]string{"app.kubernetes.io/name": Name})).
+			WithTemplate(corev1ac.PodTemplateSpec().
+				WithLabels(labels).
+				WithSpec(corev1ac.PodSpec().
+					WithContainers(container).
+					WithVolumes(volume))))
+	if _, err := m.downstream.AppsV1().Deployments(Namespace).Apply(ctx, dep, opts); err != nil {
+		return fmt.Errorf("applying registry cache deployment: %w", err)
+	}
+	return nil
+}
+
+// teardown removes the cache workload, including its volume claim.
+func (m *Manager) teardown(ctx context.Context) error {
+	if err := m.downstream.AppsV1().Deployments(Namespace).Delete(ctx, Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("deleting registry cache deployment: %w", err)
+	}
+	if err := m.downstream.CoreV1().Services(Namespace).Delete(ctx, Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("deleting registry cache service: %w", err)
+	}
+	if err := m.downstream.CoreV1().PersistentVolumeClaims(Namespace).Delete(ctx, Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("deleting registry cache volume claim: %w", err)
+	}
+	return nil
+}
+
+// scrape reads the cache's Prometheus endpoint through the API server's
+// service proxy, so it works whether or not the agent runs in-cluster.
+func (m *Manager) scrape(ctx context.Context) (counters, error) {
+	body, err := m.downstream.CoreV1().Services(Namespace).
+		ProxyGet("http", Name, strconv.Itoa(debugPort), "/metrics", nil).
+		DoRaw(ctx)
+	if err != nil {
+		return counters{}, err
+	}
+	return parseCounters(body), nil
+}
+
+// parseCounters extracts distribution's registry_storage_cache_total series
+// (labelled type="Request"|"Hit"|"Miss") from a Prometheus text exposition.
+func parseCounters(body []byte) counters {
+	var c counters
+	sc := bufio.NewScanner(bytes.NewReader(body))
+	for sc.Scan() {
+		line := sc.Text()
+		if !strings.HasPrefix(line, "registry_storage_cache_total{") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		v, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			continue
+		}
+		switch {
+		case strings.Contains(fields[0], `type="Request"`):
+			c.Requests = int64(v)
+		case strings.Contains(fields[0], `type="Hit"`):
+			c.Hits = int64(v)
+		case strings.Contains(fields[0], `type="Miss"`):
+			c.Misses = int64(v)
+		}
+	}
+	return c
+}
+
+// patchStatus merge-patches status.registryCache; nil clears it.
+func (m *Manager) patchStatus(ctx context.Context, status map[string]interface{}) error {
+	var value interface{}
+	if status != nil {
+		value = status
+	}
+	patchBytes, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{"registryCache": value},
+	})
+	if err != nil {
+		return fmt.Errorf("marshaling registry cache status patch: %w", err)
+	}
+	if _, err := m.hubDynamic.Resource(kedgeclient.KubernetesClusterGVR).Patch(ctx, m.edgeName,
+		types.MergePatchType, patchBytes, metav1.PatchOptions{}, "status"); err != nil {
+		return fmt.Errorf("updating registry cache status: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registrycache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+)
+
+func TestParseCounters(t *testing.T) {
+	body := []byte(`# HELP registry_storage_cache_total The number of cache request received
+# TYPE registry_storage_cache_total counter
+registry_storage_cache_total{type="Hit"} 42
+registry_storage_cache_total{type="Miss"} 8
+registry_storage_cache_total{type="Request"} 50
+registry_http_requests_total{code="200",method="get"} 99
+`)
+	got := parseCounters(body)
+	want := counters{Requests: 50, Hits: 42, Misses: 8}
+	if got != want {
+		t.Errorf("parseCounters() = %+v, want %+v", got, want)
+	}
+
+	if got := parseCounters([]byte("garbage\n")); got != (counters{}) {
+		t.Errorf("parseCounters(garbage) = %+v, want zero", got)
+	}
+}
+
+// newTestManager builds a Manager over a fake hub holding one
+// KubernetesCluster with the given spec.registryCache, and a fake edge.
+func newTestManager(t *testing.T, registryCache map[string]interface{}) (*Manager, *dynamicfake.FakeDynamicClient, *kubefake.Clientset) {
+	t.Helper()
+	edge := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "edges.kedge.faros.sh/v1alpha1",
+		"kind":       "KubernetesCluster",
+		"metadata":   map[string]interface{}{"name": "edge-1"},
+		"spec":       map[string]interface{}{},
+	}}
+	if registryCache != nil {
+		edge.Object["spec"] = map[string]interface{}{"registryCache": registryCache}
+	}
+	hub := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		kedgeclient.KubernetesClusterGVR: "KubernetesClusterList",
+	}, edge)
+	downstream := kubefake.NewClientset()
+	return NewManager("edge-1", hub, downstream), hub, downstream
+}
+
+// setEnabled flips spec.registryCache.enabled on the fake hub's edge.
+func setEnabled(t *testing.T, hub *dynamicfake.FakeDynamicClient, enabled bool) {
+	t.Helper()
+	ctx := context.Background()
+	edges := hub.Resource(kedgeclient.KubernetesClusterGVR)
+	edge, err := edges.Get(ctx, "edge-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := unstructured.SetNestedField(edge.Object, enabled, "spec", "registryCache", "enabled"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := edges.Update(ctx, edge, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// countActions returns how many recorded actions have the given verb.
+func countActions(actions []clienttesting.Action, verb string) int {
+	n := 0
+	for _, a := range actions {
+		if a.GetVerb() == verb {
+			n++
+		}
+	}
+	return n
+}
+
+func TestSyncEnabled(t *testing.T) {
+	ctx := context.Background()
+	m, hub, downstream := newTestManager(t, map[string]interface{}{"enabled": true})
+
+	if err := m.sync(ctx); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if _, err := downstream.AppsV1().Deployments(Namespace).Get(ctx, Name, metav1.GetOptions{}); err != nil {
+		t.Fatalf("cache deployment not applied: %v", err)
+	}
+	edge, err := hub.Resource(kedgeclient.KubernetesClusterGVR).Get(ctx, "edge-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ready, found, _ := unstructured.NestedBool(edge.Object, "status", "registryCache", "ready"); !found || ready {
+		t.Errorf("status.registryCache.ready = %v (found %v), want false", ready, found)
+	}
+
+	// Nothing changed: the next tick must not write to the hub again.
+	hub.ClearActions()
+	if err := m.sync(ctx); err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	if n := countActions(hub.Actions(), "patch"); n != 0 {
+		t.Errorf("unchanged status patched %d times, want 0", n)
+	}
+}
+
+func TestSyncDisabled(t *testing.T) {
+	for name, registryCache := range map[string]map[string]interface{}{
+		"unset":    nil,
+		"disabled": {"enabled": false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			m, hub, downstream := newTestManager(t, registryCache)
+			if err := m.sync(context.Background()); err != nil {
+				t.Fatalf("sync: %v", err)
+			}
+			if n := countActions(downstream.Actions(), "delete"); n != 0 {
+				t.Errorf("never-enabled cache issued %d deletes, want 0", n)
+			}
+			if n := countActions(hub.Actions(), "patch"); n != 0 {
+				t.Errorf("never-enabled cache patched status %d times, want 0", n)
+			}
+		})
+	}
+}
+
+func TestSyncEnabledToDisabled(t *testing.T) {
+	ctx := context.Background()
+	m, hub, downstream := newTestManager(t, map[string]interface{}{"enabled": true, "storageSize": "1Gi"})
+	if err := m.sync(ctx); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+
+	setEnabled(t, hub, false)
+	if err := m.sync(ctx); err != nil {
+		t.Fatalf("sync after disable: %v", err)
+	}
+	if _, err := downstream.AppsV1().Deployments(Namespace).Get(ctx, Name, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("cache deployment still present: %v", err)
+	}
+	if _, err := downstream.CoreV1().PersistentVolumeClaims(Namespace).Get(ctx, Name, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("cache volume claim still present: %v", err)
+	}
+	edge, err := hub.Resource(kedgeclient.KubernetesClusterGVR).Get(ctx, "edge-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(edge.Object, "status", "registryCache"); found {
+		t.Error("status.registryCache not cleared after disabling")
+	}
+
+	// Once torn down and cleared, later ticks leave the edge alone.
+	downstream.ClearActions()
+	hub.ClearActions()
+	if err := m.sync(ctx); err != nil {
+		t.Fatalf("sync after teardown: %v", err)
+	}
+	if n := countActions(downstream.Actions(), "delete"); n != 0 {
+		t.Errorf("torn-down cache issued %d deletes, want 0", n)
+	}
+	if n := countActions(hub.Actions(), "patch"); n != 0 {
+		t.Errorf("cleared status patched %d times, want 0", n)
+	}
+}
+
+// TestSyncDisabledLookupError checks that a failed lookup of the downstream
+// resources is retried, not taken for a provisioned cache to tear down.
+func TestSyncDisabledLookupError(t *testing.T) {
+	m, _, downstream := newTestManager(t, map[string]interface{}{"enabled": false})
+	downstream.PrependReactor("get", "deployments", func(clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("connection refused")
+	})
+	if err := m.sync(context.Background()); err == nil {
+		t.Error("sync succeeded despite the lookup error")
+	}
+	if n := countActions(downstream.Actions(), "delete"); n != 0 {
+		t.Errorf("failed lookup issued %d deletes, want 0", n)
+	}
+}
+
+func TestRunFollowsEdge(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m, hub, downstream := newTestManager(t, map[string]interface{}{"enabled": true})
+	go func() { _ = m.Run(ctx) }()
+
+	deployed := func(want bool) wait.ConditionWithContextFunc {
+		return func(ctx context.Context) (bool, error) {
+			_, err := downstream.AppsV1().Deployments(Namespace).Get(ctx, Name, metav1.GetOptions{})
+			return (err == nil) == want, nil
+		}
+	}
+	if err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true, deployed(true)); err != nil {
+		t.Fatalf("cache not deployed for an enabled edge: %v", err)
+	}
+	setEnabled(t, hub, false)
+	if err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true, deployed(false)); err != nil {
+		t.Fatalf("cache not torn down after disabling: %v", err)
+	}
+}
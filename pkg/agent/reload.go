@@ -0,0 +1,173 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"maps"
+	"strconv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+)
+
+// Reloadable are the options a running agent applies without restarting.
+type Reloadable struct {
+	// Labels replace the labels the agent applies to its edge, reconciled
+	// with the edge's under Options.LabelPolicy.
+	Labels   map[string]string
+	LogLevel int
+	// SSHUser, SSHPassword and SSHPrivateKeyPath are stored on the hub again
+	// for server-type edges whose credentials the agent manages.
+	SSHUser           string
+	SSHPassword       string
+	SSHPrivateKeyPath string
+}
+
+// Reload hands r to the running agent. It does not block: when an earlier
+// Reload is still pending, r replaces it. Settings reloaded before the agent
+// has connected are applied once it has.
+func (a *Agent) Reload(r Reloadable) {
+	for {
+		select {
+		case a.reloads <- r:
+			return
+		default:
+		}
+		select {
+		case <-a.reloads:
+		default:
+		}
+	}
+}
+
+// SetLogLevel sets the klog verbosity.
+func SetLogLevel(level int) error {
+	fs := flag.NewFlagSet("klog", flag.ContinueOnError)
+	klog.InitFlags(fs)
+	if err := fs.Set("v", strconv.Itoa(level)); err != nil {
+		return fmt.Errorf("setting log level %d: %w", level, err)
+	}
+	return nil
+}
+
+// runReloads applies the settings passed to Reload until ctx is done. On
+// server-type edges whose SSH credentials the agent manages, it also
+// re-syncs them when the key or password file changes and periodically.
+func (a *Agent) runReloads(ctx context.Context, hubClient *kedgeclient.Client) {
+	sshLogger := klog.FromContext(ctx).WithName(sshCredentialsSubsystem)
+	rotation := a.newSSHRotation(sshLogger)
+	defer rotation.stop()
+
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case r := <-a.reloads:
+			a.applyReload(ctx, hubClient, rotation, r)
+		case <-rotation.events():
+			debounce = time.After(sshCredentialsDebounce)
+		case err := <-rotation.errors():
+			sshLogger.Error(err, "Watching SSH credential files")
+		case <-debounce:
+			debounce = nil
+			a.resyncSSHCredentials(ctx, sshLogger, rotation)
+		case <-rotation.resync():
+			a.resyncSSHCredentials(ctx, sshLogger, rotation)
+		}
+	}
+}
+
+func (a *Agent) applyReload(ctx context.Context, hubClient *kedgeclient.Client, rotation *sshRotation, r Reloadable) {
+	logger := klog.FromContext(ctx).WithName("reload")
+
+	if r.LogLevel != a.opts.LogLevel {
+		if err := SetLogLevel(r.LogLevel); err != nil {
+			logger.Error(err, "Changing log level failed")
+		} else {
+			logger.Info("Log level changed", "from", a.opts.LogLevel, "to", r.LogLevel)
+			a.opts.LogLevel = r.LogLevel
+		}
+	}
+
+	switch {
+	case maps.Equal(r.Labels, a.opts.Labels):
+	case a.opts.Registration == RegistrationExternal:
+		logger.Info("Labels changed; the edge's labels are managed by its admin, so they are not applied")
+		a.opts.Labels = r.Labels
+	default:
+		if err := a.updateEdgeLabels(ctx, hubClient, r.Labels); err != nil {
+			logger.Error(err, "Updating edge labels failed")
+		} else {
+			logger.Info("Edge labels updated", "labels", r.Labels)
+			a.opts.Labels = r.Labels
+		}
+	}
+
+	// The password comes from the password file when there is one; the
+	// rotation re-reads it.
+	if a.opts.SSHPasswordFile != "" {
+		r.SSHPassword = a.opts.SSHPassword
+	}
+	if r.SSHUser != a.opts.SSHUser || r.SSHPassword != a.opts.SSHPassword || r.SSHPrivateKeyPath != a.opts.SSHPrivateKeyPath {
+		a.opts.SSHUser, a.opts.SSHPassword, a.opts.SSHPrivateKeyPath = r.SSHUser, r.SSHPassword, r.SSHPrivateKeyPath
+		switch {
+		case a.agentType != AgentTypeServer:
+		case a.opts.Token != "":
+			logger.Info("SSH settings changed; the hub manages this edge's credentials, so they take effect when it is re-joined")
+		case a.hubStoresSSHCredentials():
+			logger.Info("SSH settings changed; the hub stores this edge's credentials from the tunnel, so they take effect when the agent restarts")
+		default:
+			rotation.watchFiles(logger, a.opts.SSHPrivateKeyPath)
+			err := a.setupSSHCredentials(ctx, logger, hubClient)
+			if err == nil {
+				err = a.applyLocalSSHCredentials(logger, rotation)
+			}
+			a.health.Observe(sshCredentialsSubsystem, err)
+			if err != nil {
+				logger.Error(err, "Updating SSH credentials failed")
+			} else {
+				logger.Info("SSH credentials updated")
+			}
+		}
+	}
+}
+
+// updateEdgeLabels reconciles the edge's labels with labels under the
+// agent's label policy (see applyEdgeLabels), retrying on conflicts with
+// concurrent writes.
+func (a *Agent) updateEdgeLabels(ctx context.Context, hubClient *kedgeclient.Client, labels map[string]string) error {
+	res := hubClient.Dynamic().Resource(kedgeclient.EdgeGVRForType(string(a.agentType)))
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		edge, err := res.Get(ctx, a.opts.EdgeName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if err := a.applyEdgeLabels(edge, labels); err != nil {
+			return err
+		}
+		_, err = res.Update(ctx, edge, metav1.UpdateOptions{})
+		return err
+	})
+}
@@ -0,0 +1,33 @@
+//go:build !windows
+
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import "context"
+
+// Run reports false: outside Windows a service manager runs the agent as a
+// plain process that stops on SIGTERM.
+func Run(string, func(ctx context.Context) error) (bool, error) {
+	return false, nil
+}
+
+// restrict is a no-op: the 0600/0700 modes WriteSecret sets already limit
+// access to the owner.
+func restrict(string) error {
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package service installs the agent as an operating-system service on
+// server-type edges: a systemd unit on Linux and a service registered with
+// the Service Control Manager on Windows. It also places the agent's
+// credentials where only the service account can read them.
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ConfigFile and LogFile are the names of the agent's --config file and, on
+// Windows where a service has no console, its log in the service directory.
+const (
+	ConfigFile = "config.yaml"
+	LogFile    = "agent.log"
+)
+
+// Config describes the service to install.
+type Config struct {
+	// Name is the service (systemd unit) name, e.g. "kedge-agent-store-17".
+	Name string
+	// Description is shown by systemctl status or the Services console.
+	Description string
+	// Executable is the absolute path of the kedge binary.
+	Executable string
+	// Args are the arguments the service runs Executable with.
+	Args []string
+}
+
+func (c Config) validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("service name is required")
+	}
+	if !filepath.IsAbs(c.Executable) {
+		return fmt.Errorf("service executable %q is not an absolute path", c.Executable)
+	}
+	return nil
+}
+
+// WriteSecret writes data to path readable by the service account and
+// administrators only, creating the directory with the same restriction.
+// An existing file is replaced.
+func WriteSecret(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("creating %s: %w", dir, err)
+	}
+	if err := restrict(dir); err != nil {
+		return fmt.Errorf("restricting access to %s: %w", dir, err)
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o600); err != nil {
+		return err
+	}
+	if err := restrict(tmp.Name()); err != nil {
+		return fmt.Errorf("restricting access to %s: %w", path, err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,108 @@
+//go:build linux
+
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// systemdUnitDir is where Install writes units.
+var systemdUnitDir = "/etc/systemd/system"
+
+// DefaultDir is the directory holding the configuration and credentials of
+// the service of edge.
+func DefaultDir(edge string) string {
+	return filepath.Join("/etc/kedge", edge)
+}
+
+// Install writes a systemd unit for cfg, then enables and starts it. It
+// requires root.
+func Install(cfg Config) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	unitPath := filepath.Join(systemdUnitDir, cfg.Name+".service")
+	if err := os.WriteFile(unitPath, []byte(systemdUnit(cfg)), 0o644); err != nil {
+		return fmt.Errorf("writing unit file %s: %w (are you running as root?)", unitPath, err)
+	}
+	return systemctl(
+		[]string{"daemon-reload"},
+		[]string{"enable", cfg.Name + ".service"},
+		[]string{"restart", cfg.Name + ".service"},
+	)
+}
+
+// Uninstall stops and disables the service name and removes its unit.
+func Uninstall(name string) error {
+	unit := name + ".service"
+	// A unit that is not running or not enabled is fine.
+	_ = systemctl([]string{"stop", unit})
+	_ = systemctl([]string{"disable", unit})
+	unitPath := filepath.Join(systemdUnitDir, unit)
+	if err := os.Remove(unitPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing %s: %w", unitPath, err)
+	}
+	return systemctl([]string{"daemon-reload"})
+}
+
+// StatusCommands returns the commands that show the state and the logs of
+// the service name keeping its files in dir.
+func StatusCommands(name, _ string) (status, logs string) {
+	return "systemctl status " + name, "journalctl -u " + name + " -f"
+}
+
+func systemctl(commands ...[]string) error {
+	for _, args := range commands {
+		out, err := exec.Command("systemctl", args...).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("running systemctl %s: %w\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+	return nil
+}
+
+// systemdUnit renders the unit of cfg. The agent keeps its saved hub
+// credentials under $HOME/.kedge, so HOME is pinned to root's.
+func systemdUnit(cfg Config) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[Unit]\nDescription=%s\nAfter=network-online.target\nWants=network-online.target\n\n", cfg.Description)
+	b.WriteString("[Service]\nType=simple\nExecStart=")
+	b.WriteString(systemdQuote(cfg.Executable))
+	for _, arg := range cfg.Args {
+		b.WriteString(" ")
+		b.WriteString(systemdQuote(arg))
+	}
+	b.WriteString("\nRestart=always\nRestartSec=10\nEnvironment=HOME=/root\n\n[Install]\nWantedBy=multi-user.target\n")
+	return b.String()
+}
+
+// systemdQuote quotes arg for an ExecStart line: specifiers and variable
+// references are escaped, and arguments with spaces or quotes are
+// double-quoted.
+func systemdQuote(arg string) string {
+	arg = strings.NewReplacer("%", "%%", "$", "$$").Replace(arg)
+	if arg != "" && !strings.ContainsAny(arg, " \t\"'\\;") {
+		return arg
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(arg) + `"`
+}
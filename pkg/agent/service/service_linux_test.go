@@ -0,0 +1,87 @@
+//go:build linux
+
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSystemdUnit(t *testing.T) {
+	unit := systemdUnit(Config{
+		Name:        "kedge-agent-store-17",
+		Description: "Kedge Agent - store-17",
+		Executable:  "/usr/local/bin/kedge",
+		Args:        []string{"agent", "run", "--config", "/etc/kedge/store 17/config.yaml", "--labels", "cost=100%"},
+	})
+	want := `ExecStart=/usr/local/bin/kedge agent run --config "/etc/kedge/store 17/config.yaml" --labels cost=100%%` + "\n"
+	if !strings.Contains(unit, want) {
+		t.Errorf("unit has no line %q:\n%s", want, unit)
+	}
+	for _, line := range []string{"Description=Kedge Agent - store-17\n", "Restart=always\n", "WantedBy=multi-user.target\n"} {
+		if !strings.Contains(unit, line) {
+			t.Errorf("unit has no line %q", line)
+		}
+	}
+}
+
+func TestSystemdQuote(t *testing.T) {
+	for arg, want := range map[string]string{
+		"plain":    "plain",
+		"":         `""`,
+		"a b":      `"a b"`,
+		`say "hi"`: `"say \"hi\""`,
+		"$HOME":    "$$HOME",
+		`C:\x`:     `"C:\\x"`,
+		"50%":      "50%%",
+		"a;b":      `"a;b"`,
+	} {
+		if got := systemdQuote(arg); got != want {
+			t.Errorf("systemdQuote(%q) = %s, want %s", arg, got, want)
+		}
+	}
+}
+
+func TestWriteSecret(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store-17", "config.yaml")
+	for _, data := range []string{"token: one\n", "token: two\n"} {
+		if err := WriteSecret(path, []byte(data)); err != nil {
+			t.Fatalf("WriteSecret: %v", err)
+		}
+		got, err := os.ReadFile(path)
+		if err != nil || string(got) != data {
+			t.Errorf("file = %q, %v; want %q", got, err, data)
+		}
+	}
+	for p, mode := range map[string]os.FileMode{path: 0o600, filepath.Dir(path): 0o700} {
+		fi, err := os.Stat(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi.Mode().Perm() != mode {
+			t.Errorf("%s mode = %o, want %o", p, fi.Mode().Perm(), mode)
+		}
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("directory holds %d entries, want only the secret", len(entries))
+	}
+}
@@ -0,0 +1,46 @@
+//go:build !linux && !windows
+
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+)
+
+// DefaultDir is the directory holding the configuration and credentials of
+// the service of edge.
+func DefaultDir(edge string) string {
+	return filepath.Join("/etc/kedge", edge)
+}
+
+// Install is only supported on Linux (systemd) and Windows.
+func Install(Config) error {
+	return fmt.Errorf("installing the agent as a service is not supported on %s; run \"kedge agent run\" under your service manager", runtime.GOOS)
+}
+
+// Uninstall is only supported on Linux (systemd) and Windows.
+func Uninstall(string) error {
+	return fmt.Errorf("services are not supported on %s", runtime.GOOS)
+}
+
+// StatusCommands returns no commands: there is no service to inspect.
+func StatusCommands(string, string) (status, logs string) {
+	return "", ""
+}
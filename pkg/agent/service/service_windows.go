@@ -0,0 +1,201 @@
+//go:build windows
+
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+	"k8s.io/klog/v2"
+)
+
+// secretSDDL grants full control to LocalSystem, which the service runs as,
+// and to Administrators, and blocks inherited entries.
+const secretSDDL = "D:P(A;OICI;FA;;;SY)(A;OICI;FA;;;BA)"
+
+// DefaultDir is the directory holding the configuration and credentials of
+// the service of edge.
+func DefaultDir(edge string) string {
+	base := os.Getenv("ProgramData")
+	if base == "" {
+		base = `C:\ProgramData`
+	}
+	return filepath.Join(base, "kedge", edge)
+}
+
+// Install registers cfg with the Service Control Manager as an automatic
+// service running as LocalSystem, restarted when it exits, and starts it. It
+// requires an elevated prompt.
+func Install(cfg Config) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("connecting to the service manager: %w (are you running as Administrator?)", err)
+	}
+	defer m.Disconnect() //nolint:errcheck
+
+	s, err := m.OpenService(cfg.Name)
+	if err == nil {
+		_ = s.Close()
+		return fmt.Errorf("service %s is already installed; run \"kedge agent uninstall-service\" first", cfg.Name)
+	}
+	s, err = m.CreateService(cfg.Name, cfg.Executable, mgr.Config{
+		DisplayName:      cfg.Name,
+		Description:      cfg.Description,
+		StartType:        mgr.StartAutomatic,
+		DelayedAutoStart: true,
+	}, cfg.Args...)
+	if err != nil {
+		return fmt.Errorf("creating service %s: %w", cfg.Name, err)
+	}
+	defer s.Close() //nolint:errcheck
+
+	restart := mgr.RecoveryAction{Type: mgr.ServiceRestart, Delay: 10 * time.Second}
+	if err := s.SetRecoveryActions([]mgr.RecoveryAction{restart, restart, restart}, 24*60*60); err != nil {
+		return fmt.Errorf("setting restart policy of %s: %w", cfg.Name, err)
+	}
+	// The agent exits non-zero on a fatal error rather than crashing; restart
+	// on that too, as systemd's Restart=always does.
+	if err := s.SetRecoveryActionsOnNonCrashFailures(true); err != nil {
+		return fmt.Errorf("setting restart policy of %s: %w", cfg.Name, err)
+	}
+	if err := s.Start(); err != nil {
+		return fmt.Errorf("starting service %s: %w", cfg.Name, err)
+	}
+	return nil
+}
+
+// Uninstall stops the service name and removes it.
+func Uninstall(name string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("connecting to the service manager: %w (are you running as Administrator?)", err)
+	}
+	defer m.Disconnect() //nolint:errcheck
+
+	s, err := m.OpenService(name)
+	if err != nil {
+		return fmt.Errorf("opening service %s: %w", name, err)
+	}
+	defer s.Close() //nolint:errcheck
+	if status, err := s.Control(svc.Stop); err == nil {
+		for deadline := time.Now().Add(30 * time.Second); status.State != svc.Stopped && time.Now().Before(deadline); {
+			time.Sleep(500 * time.Millisecond)
+			if status, err = s.Query(); err != nil {
+				break
+			}
+		}
+	}
+	if err := s.Delete(); err != nil {
+		return fmt.Errorf("deleting service %s: %w", name, err)
+	}
+	return nil
+}
+
+// StatusCommands returns the commands that show the state and the logs of
+// the service name keeping its files in dir.
+func StatusCommands(name, dir string) (status, logs string) {
+	return "sc.exe query " + name, "Get-Content -Wait " + filepath.Join(dir, LogFile)
+}
+
+// Run runs fn as the Windows service the process was started as, cancelling
+// its context when the Service Control Manager stops the service. A service
+// has no console, so logs are appended to LogFile in dir. It reports false,
+// without running fn, when the process is not a service.
+func Run(dir string, fn func(ctx context.Context) error) (bool, error) {
+	isService, err := svc.IsWindowsService()
+	if err != nil || !isService {
+		return false, err
+	}
+	logs, err := os.OpenFile(filepath.Join(dir, LogFile), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return true, fmt.Errorf("opening service log: %w", err)
+	}
+	defer logs.Close() //nolint:errcheck
+	klog.LogToStderr(false)
+	klog.SetOutput(logs)
+	defer klog.Flush()
+
+	h := &handler{run: fn}
+	// The name is ignored for services that run in their own process.
+	if err := svc.Run("", h); err != nil {
+		return true, err
+	}
+	return true, h.err
+}
+
+// handler adapts an agent run to the service control protocol.
+type handler struct {
+	run func(ctx context.Context) error
+	err error
+}
+
+func (h *handler) Execute(_ []string, requests <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+	const accepted = svc.AcceptStop | svc.AcceptShutdown
+	status <- svc.Status{State: svc.StartPending}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- h.run(ctx) }()
+	status <- svc.Status{State: svc.Running, Accepts: accepted}
+
+	for {
+		select {
+		case h.err = <-done:
+			if h.err != nil && !errors.Is(h.err, context.Canceled) {
+				// A service-specific exit code makes the SCM apply the
+				// recovery actions.
+				return true, 1
+			}
+			return false, 0
+		case r := <-requests:
+			switch r.Cmd {
+			case svc.Interrogate:
+				status <- r.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				status <- svc.Status{State: svc.StopPending}
+				cancel()
+			}
+		}
+	}
+}
+
+// restrict limits access to path to LocalSystem and Administrators.
+func restrict(path string) error {
+	sd, err := windows.SecurityDescriptorFromString(secretSDDL)
+	if err != nil {
+		return err
+	}
+	dacl, _, err := sd.DACL()
+	if err != nil {
+		return err
+	}
+	return windows.SetNamedSecurityInfo(path, windows.SE_FILE_OBJECT,
+		windows.DACL_SECURITY_INFORMATION|windows.PROTECTED_DACL_SECURITY_INFORMATION, nil, nil, dacl, nil)
+}
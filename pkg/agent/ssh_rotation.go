@@ -0,0 +1,314 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	gossh "golang.org/x/crypto/ssh"
+	"k8s.io/klog/v2"
+)
+
+// DefaultSSHCredentialsResync is how often a server-type agent re-syncs its
+// SSH credentials with the hub by default.
+const DefaultSSHCredentialsResync = 5 * time.Minute
+
+// sshCredentialsDebounce coalesces the burst of events a key rotation or a
+// Secret volume update produces into one re-sync.
+const sshCredentialsDebounce = 500 * time.Millisecond
+
+// sshCredentialsSubsystem names SSH credential rotation in the agent's
+// health.
+const sshCredentialsSubsystem = "ssh-credentials"
+
+// sshRotation tracks the SSH private key and password file of a server-type
+// agent that stores its credentials on the hub, so that rotated credentials
+// replace the old ones on the hub and on the edge without a restart. It is
+// owned by the goroutine running runReloads.
+type sshRotation struct {
+	// watcher watches the directories of the credential files rather than
+	// the files, so files replaced by rotation tools and editors and Secret
+	// volume updates are seen too. Nil when watching is unavailable; the
+	// periodic re-sync still applies.
+	watcher *fsnotify.Watcher
+	dirs    map[string]bool
+	ticker  *time.Ticker
+	// key is the public half of the private key last authorized on the
+	// edge, nil when there is none.
+	key gossh.PublicKey
+}
+
+// newSSHRotation starts tracking the agent's SSH credentials. It returns nil
+// when the agent does not manage them: kubernetes-type edges, and agents
+// whose credentials the hub keeps (hubStoresSSHCredentials).
+func (a *Agent) newSSHRotation(logger klog.Logger) *sshRotation {
+	if a.agentType != AgentTypeServer || a.hubStoresSSHCredentials() {
+		return nil
+	}
+	r := &sshRotation{dirs: map[string]bool{}}
+	if a.opts.SSHPrivateKeyPath != "" {
+		key, err := readSSHPublicKey(a.opts.SSHPrivateKeyPath)
+		if err != nil {
+			logger.Error(err, "Reading SSH private key failed")
+		}
+		r.key = key
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		logger.Error(err, "Changed SSH credential files will only be picked up by the periodic re-sync")
+	} else {
+		r.watcher = watcher
+		r.watchFiles(logger, a.opts.SSHPrivateKeyPath, a.opts.SSHPasswordFile)
+	}
+	if a.opts.SSHCredentialsResync > 0 {
+		r.ticker = time.NewTicker(a.opts.SSHCredentialsResync)
+	}
+	return r
+}
+
+// watchFiles adds the directories of paths to the watch. Empty paths are
+// skipped.
+func (r *sshRotation) watchFiles(logger klog.Logger, paths ...string) {
+	if r == nil || r.watcher == nil {
+		return
+	}
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		dir := filepath.Dir(path)
+		if r.dirs[dir] {
+			continue
+		}
+		if err := r.watcher.Add(dir); err != nil {
+			logger.Error(err, "Changes to SSH credential file will only be picked up by the periodic re-sync", "path", path)
+			continue
+		}
+		r.dirs[dir] = true
+	}
+}
+
+// events and errors are the watch's channels, nil when there is no watch.
+func (r *sshRotation) events() <-chan fsnotify.Event {
+	if r == nil || r.watcher == nil {
+		return nil
+	}
+	return r.watcher.Events
+}
+
+func (r *sshRotation) errors() <-chan error {
+	if r == nil || r.watcher == nil {
+		return nil
+	}
+	return r.watcher.Errors
+}
+
+// resync ticks at the periodic re-sync interval, nil when it is disabled.
+func (r *sshRotation) resync() <-chan time.Time {
+	if r == nil || r.ticker == nil {
+		return nil
+	}
+	return r.ticker.C
+}
+
+func (r *sshRotation) stop() {
+	if r == nil {
+		return
+	}
+	if r.watcher != nil {
+		r.watcher.Close() //nolint:errcheck
+	}
+	if r.ticker != nil {
+		r.ticker.Stop()
+	}
+}
+
+// resyncSSHCredentials re-reads the SSH password file and private key and
+// brings the hub Secret and the edge in line with them. The hub Secret is
+// written first, so the hub holds the new credentials before the old ones
+// stop working; then the old key is removed from authorized_keys and the
+// embedded SSH server accepts only the new credentials. The outcome is
+// reported to the agent's health.
+func (a *Agent) resyncSSHCredentials(ctx context.Context, logger klog.Logger, r *sshRotation) {
+	err := a.syncSSHCredentials(ctx, logger, r)
+	a.health.Observe(sshCredentialsSubsystem, err)
+	if err != nil {
+		logger.Error(err, "Re-syncing SSH credentials failed")
+	}
+}
+
+func (a *Agent) syncSSHCredentials(ctx context.Context, logger klog.Logger, r *sshRotation) error {
+	if a.opts.SSHPasswordFile != "" {
+		password, err := readSSHPasswordFile(a.opts.SSHPasswordFile)
+		if err != nil {
+			return err
+		}
+		if password != a.opts.SSHPassword {
+			logger.Info("SSH password file changed", "path", a.opts.SSHPasswordFile)
+			a.opts.SSHPassword = password
+		}
+	}
+
+	data, err := a.sshCredentialsData()
+	if err != nil {
+		return err
+	}
+	if len(data) > 0 {
+		changed, err := a.syncSSHCredentialsSecret(ctx, logger, data)
+		if err != nil {
+			return err
+		}
+		if changed {
+			logger.Info("SSH credentials rotated on the hub; the previous ones are revoked")
+		}
+	}
+	return a.applyLocalSSHCredentials(logger, r)
+}
+
+// applyLocalSSHCredentials authorizes the current private key on the edge in
+// place of the one authorized before, and hands the current credentials to
+// the embedded SSH server.
+func (a *Agent) applyLocalSSHCredentials(logger klog.Logger, r *sshRotation) error {
+	if r == nil {
+		return nil
+	}
+	var key gossh.PublicKey
+	if a.opts.SSHPrivateKeyPath != "" {
+		var err error
+		if key, err = readSSHPublicKey(a.opts.SSHPrivateKeyPath); err != nil {
+			return err
+		}
+	}
+	if !sameSSHKey(key, r.key) {
+		if err := replaceAuthorizedKey(r.key, key); err != nil {
+			return err
+		}
+		logger.Info("SSH key rotated; the previous key is no longer authorized", "keyPath", a.opts.SSHPrivateKeyPath)
+		r.key = key
+	}
+	if a.sshServer != nil {
+		var keys []gossh.PublicKey
+		if key != nil {
+			keys = []gossh.PublicKey{key}
+		}
+		a.sshServer.SetCredentials(a.opts.SSHPassword, keys)
+	}
+	return nil
+}
+
+// readSSHPasswordFile reads an SSH password file, without the trailing line
+// break editors and "echo" add.
+func readSSHPasswordFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading SSH password file: %w", err)
+	}
+	password := strings.TrimRight(string(data), "\r\n")
+	if password == "" {
+		return "", fmt.Errorf("SSH password file %s is empty", path)
+	}
+	return password, nil
+}
+
+// readSSHPublicKey returns the public half of the private key at path.
+func readSSHPublicKey(path string) (gossh.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading SSH private key from %s: %w", path, err)
+	}
+	signer, err := gossh.ParsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing SSH private key %s: %w", path, err)
+	}
+	return signer.PublicKey(), nil
+}
+
+func sameSSHKey(a, b gossh.PublicKey) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return bytes.Equal(a.Marshal(), b.Marshal())
+}
+
+// replaceAuthorizedKey removes old from ~/.ssh/authorized_keys and adds
+// replacement, either of which may be nil. The file is replaced atomically.
+func replaceAuthorizedKey(old, replacement gossh.PublicKey) error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("getting home directory: %w", err)
+	}
+	sshDir := filepath.Join(home, ".ssh")
+	if err := os.MkdirAll(sshDir, 0700); err != nil {
+		return fmt.Errorf("creating %s: %w", sshDir, err)
+	}
+	authKeysPath := filepath.Join(sshDir, "authorized_keys")
+
+	existing, err := os.ReadFile(authKeysPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading %s: %w", authKeysPath, err)
+	}
+	var out bytes.Buffer
+	present := false
+	scanner := bufio.NewScanner(bytes.NewReader(existing))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if key, _, _, _, err := gossh.ParseAuthorizedKey([]byte(line)); err == nil {
+			if old != nil && sameSSHKey(key, old) && !sameSSHKey(key, replacement) {
+				continue // revoked
+			}
+			if sameSSHKey(key, replacement) {
+				present = true
+			}
+		}
+		out.WriteString(line)
+		out.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading %s: %w", authKeysPath, err)
+	}
+	if replacement != nil && !present {
+		out.Write(gossh.MarshalAuthorizedKey(replacement))
+	}
+	if bytes.Equal(out.Bytes(), existing) {
+		return nil
+	}
+
+	tmp, err := os.CreateTemp(sshDir, ".authorized_keys-*")
+	if err != nil {
+		return fmt.Errorf("writing %s: %w", authKeysPath, err)
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+	if _, err := tmp.Write(out.Bytes()); err != nil {
+		tmp.Close() //nolint:errcheck
+		return fmt.Errorf("writing %s: %w", authKeysPath, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing %s: %w", authKeysPath, err)
+	}
+	if err := os.Rename(tmp.Name(), authKeysPath); err != nil {
+		return fmt.Errorf("replacing %s: %w", authKeysPath, err)
+	}
+	return nil
+}
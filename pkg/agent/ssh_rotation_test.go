@@ -0,0 +1,135 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gossh "golang.org/x/crypto/ssh"
+	"k8s.io/klog/v2"
+
+	"github.com/faroshq/faros-kedge/pkg/agent/health"
+)
+
+// writeSSHKey writes a new ed25519 private key to path and returns its
+// public half.
+func writeSSHKey(t *testing.T, path string) gossh.PublicKey {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := gossh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatal(err)
+	}
+	key, err := gossh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func authorizedKeys(t *testing.T, home string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(home, ".ssh", "authorized_keys"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// TestApplyLocalSSHCredentials checks that a rotated key replaces the old
+// one in authorized_keys and leaves the other entries alone.
+func TestApplyLocalSSHCredentials(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+
+	oldKey := writeSSHKey(t, keyPath)
+	other := writeSSHKey(t, filepath.Join(t.TempDir(), "other"))
+	if err := os.MkdirAll(filepath.Join(home, ".ssh"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	initial := "# keep me\n" + string(gossh.MarshalAuthorizedKey(other)) + string(gossh.MarshalAuthorizedKey(oldKey))
+	if err := os.WriteFile(filepath.Join(home, ".ssh", "authorized_keys"), []byte(initial), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	a := &Agent{opts: &Options{SSHPrivateKeyPath: keyPath}, agentType: AgentTypeServer, health: health.NewTracker()}
+	r := a.newSSHRotation(klog.Background())
+	defer r.stop()
+
+	// Nothing changed: the file is left as it is.
+	if err := a.applyLocalSSHCredentials(klog.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+	if got := authorizedKeys(t, home); got != initial {
+		t.Errorf("authorized_keys rewritten without a rotation:\n%s", got)
+	}
+
+	newKey := writeSSHKey(t, keyPath)
+	if err := a.applyLocalSSHCredentials(klog.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+	got := authorizedKeys(t, home)
+	want := "# keep me\n" + string(gossh.MarshalAuthorizedKey(other)) + string(gossh.MarshalAuthorizedKey(newKey))
+	if got != want {
+		t.Errorf("authorized_keys after rotation:\n%s\nwant:\n%s", got, want)
+	}
+	if !sameSSHKey(r.key, newKey) {
+		t.Error("rotated key not recorded")
+	}
+}
+
+func TestReadSSHPasswordFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(path, []byte("s3cret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := readSSHPasswordFile(path); err != nil || got != "s3cret" {
+		t.Errorf("readSSHPasswordFile = %q, %v; want s3cret", got, err)
+	}
+	if err := os.WriteFile(path, []byte("\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readSSHPasswordFile(path); err == nil || !strings.Contains(err.Error(), "empty") {
+		t.Errorf("empty password file: err = %v", err)
+	}
+}
+
+func TestNewSSHRotationScope(t *testing.T) {
+	for _, a := range []*Agent{
+		{opts: &Options{}, agentType: AgentTypeKubernetes},
+		{opts: &Options{Token: "join-token"}, agentType: AgentTypeServer},
+		{opts: &Options{Registration: RegistrationExternal}, agentType: AgentTypeServer},
+	} {
+		if r := a.newSSHRotation(klog.Background()); r != nil {
+			r.stop()
+			t.Errorf("rotation started for %s agent with token %q, registration %q", a.agentType, a.opts.Token, a.opts.Registration)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+//go:build linux
+
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sshserver
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// ptySupported reports whether pty-req can be honoured on this platform.
+const ptySupported = true
+
+// openPTY allocates a pseudo-terminal sized cols x rows and returns its
+// master side and the terminal the session's process is attached to.
+func openPTY(cols, rows uint32) (master, tty *os.File, err error) {
+	master, err = os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening /dev/ptmx: %w", err)
+	}
+	fd := int(master.Fd())
+	if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+		master.Close() //nolint:errcheck
+		return nil, nil, fmt.Errorf("unlocking pty: %w", err)
+	}
+	n, err := unix.IoctlGetInt(fd, unix.TIOCGPTN)
+	if err != nil {
+		master.Close() //nolint:errcheck
+		return nil, nil, fmt.Errorf("getting pty number: %w", err)
+	}
+	tty, err = os.OpenFile("/dev/pts/"+strconv.Itoa(n), os.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		master.Close() //nolint:errcheck
+		return nil, nil, fmt.Errorf("opening pty: %w", err)
+	}
+	if err := resizePTY(master, cols, rows); err != nil {
+		master.Close() //nolint:errcheck
+		tty.Close()    //nolint:errcheck
+		return nil, nil, err
+	}
+	return master, tty, nil
+}
+
+// resizePTY applies a window-change to the terminal behind master. A zero
+// dimension leaves the kernel default in place.
+func resizePTY(master *os.File, cols, rows uint32) error {
+	if cols == 0 || rows == 0 {
+		return nil
+	}
+	// SyscallConn rather than Fd: it is safe alongside the relay's reads
+	// and fails cleanly once the session closed master.
+	rc, err := master.SyscallConn()
+	if err != nil {
+		return fmt.Errorf("resizing pty: %w", err)
+	}
+	ws := &unix.Winsize{Col: uint16(cols), Row: uint16(rows)}
+	var ioctlErr error
+	if err := rc.Control(func(fd uintptr) {
+		ioctlErr = unix.IoctlSetWinsize(int(fd), unix.TIOCSWINSZ, ws)
+	}); err != nil {
+		return fmt.Errorf("resizing pty: %w", err)
+	}
+	if ioctlErr != nil {
+		return fmt.Errorf("resizing pty: %w", ioctlErr)
+	}
+	return nil
+}
+
+// attachPTY makes tty the controlling terminal and stdio of cmd.
+func attachPTY(cmd *exec.Cmd, tty *os.File) {
+	cmd.Stdin = tty
+	cmd.Stdout = tty
+	cmd.Stderr = tty
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true, Ctty: 0}
+}
@@ -0,0 +1,37 @@
+//go:build !linux
+
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sshserver
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// ptySupported is false: the embedded server only allocates PTYs on Linux,
+// so pty-req is refused elsewhere.
+const ptySupported = false
+
+func openPTY(uint32, uint32) (*os.File, *os.File, error) {
+	return nil, nil, errors.New("pty allocation is not supported on this platform")
+}
+
+func resizePTY(*os.File, uint32, uint32) error { return nil }
+
+func attachPTY(*exec.Cmd, *os.File) {}
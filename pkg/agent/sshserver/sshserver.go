@@ -0,0 +1,494 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sshserver is the agent's embedded SSH server. It stands in for the
+// host sshd on server-type edges that have none (containers, minimal OS
+// images) so the hub's ssh subresource keeps working.
+//
+// It is deliberately small: one login user (the agent's SSH user), the same
+// credentials the hub already holds for the edge (authorized keys and/or the
+// agent's SSH password), exec requests run through /bin/sh -c, and — unless
+// ExecOnly is set — an interactive shell, on a PTY when the client asks for
+// one (Linux only; pty-req is refused elsewhere). Commands run as the agent's
+// own OS user; there is no privilege switching, port forwarding or SFTP. It only ever listens on loopback: the agent's /ssh tunnel handler is
+// its only client.
+package sshserver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	gossh "golang.org/x/crypto/ssh"
+	"k8s.io/klog/v2"
+)
+
+// Options configures the embedded SSH server.
+type Options struct {
+	// User is the only login name accepted.
+	User string
+	// Password, when non-empty, enables password authentication.
+	Password string
+	// AuthorizedKeysFile is re-read on every public-key attempt, so keys the
+	// agent appends later are honoured without a restart. Missing is fine.
+	AuthorizedKeysFile string
+	// AuthorizedKeys are accepted in addition to AuthorizedKeysFile.
+	AuthorizedKeys []gossh.PublicKey
+	// HostKeyPath is where the ed25519 host key is kept; it is generated on
+	// first use so the key the hub pins survives agent restarts. Empty uses an
+	// ephemeral key.
+	HostKeyPath string
+	// ExecOnly rejects interactive shell requests; only exec is served.
+	ExecOnly bool
+	// Shell runs exec commands (Shell -c <cmd>) and interactive sessions.
+	// Defaults to /bin/sh.
+	Shell string
+}
+
+// Server is the embedded SSH server.
+type Server struct {
+	opts   Options
+	config *gossh.ServerConfig
+	logger klog.Logger
+
+	// mu guards opts.Password and opts.AuthorizedKeys, which SetCredentials
+	// replaces while the server runs.
+	mu sync.RWMutex
+}
+
+// New builds a Server. At least one credential (a password or an authorized
+// key source) is required.
+func New(opts Options) (*Server, error) {
+	if opts.User == "" {
+		return nil, errors.New("sshserver: user is required")
+	}
+	if opts.Password == "" && opts.AuthorizedKeysFile == "" && len(opts.AuthorizedKeys) == 0 {
+		return nil, errors.New("sshserver: a password or authorized keys are required")
+	}
+	if opts.Shell == "" {
+		opts.Shell = "/bin/sh"
+	}
+
+	hostKey, err := loadOrCreateHostKey(opts.HostKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{opts: opts, logger: klog.Background().WithName("embedded-sshd")}
+	s.config = &gossh.ServerConfig{
+		PasswordCallback:  s.checkPassword,
+		PublicKeyCallback: s.checkPublicKey,
+		ServerVersion:     "SSH-2.0-kedge-agent",
+	}
+	s.config.AddHostKey(hostKey)
+	return s, nil
+}
+
+// Listen binds addr (use "127.0.0.1:0" for an ephemeral port) and serves until
+// ctx is cancelled. It returns the bound port once listening.
+func (s *Server) Listen(ctx context.Context, addr string) (int, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return 0, fmt.Errorf("sshserver: listening on %s: %w", addr, err)
+	}
+	go func() {
+		<-ctx.Done()
+		ln.Close() //nolint:errcheck
+	}()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return // listener closed
+			}
+			go s.handleConn(ctx, conn)
+		}
+	}()
+	port := ln.Addr().(*net.TCPAddr).Port
+	s.logger.Info("Embedded SSH server listening", "addr", ln.Addr().String(), "user", s.opts.User, "execOnly", s.opts.ExecOnly)
+	return port, nil
+}
+
+// SetCredentials replaces the password and the AuthorizedKeys the server
+// accepts, so rotated credentials take effect without a restart and the
+// replaced ones stop working. An empty password disables password
+// authentication.
+func (s *Server) SetCredentials(password string, keys []gossh.PublicKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.opts.Password = password
+	s.opts.AuthorizedKeys = keys
+}
+
+func (s *Server) checkPassword(conn gossh.ConnMetadata, password []byte) (*gossh.Permissions, error) {
+	s.mu.RLock()
+	want := s.opts.Password
+	s.mu.RUnlock()
+	if want != "" && conn.User() == s.opts.User && subtle.ConstantTimeCompare(password, []byte(want)) == 1 {
+		return nil, nil
+	}
+	return nil, fmt.Errorf("password rejected for %q", conn.User())
+}
+
+func (s *Server) checkPublicKey(conn gossh.ConnMetadata, key gossh.PublicKey) (*gossh.Permissions, error) {
+	if conn.User() != s.opts.User {
+		return nil, fmt.Errorf("unknown user %q", conn.User())
+	}
+	s.mu.RLock()
+	keys := s.opts.AuthorizedKeys
+	s.mu.RUnlock()
+	want := key.Marshal()
+	for _, k := range keys {
+		if bytes.Equal(k.Marshal(), want) {
+			return nil, nil
+		}
+	}
+	if s.opts.AuthorizedKeysFile != "" {
+		for _, k := range readAuthorizedKeys(s.opts.AuthorizedKeysFile) {
+			if bytes.Equal(k.Marshal(), want) {
+				return nil, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("public key rejected for %q", conn.User())
+}
+
+func (s *Server) handleConn(ctx context.Context, c net.Conn) {
+	serverConn, chans, reqs, err := gossh.NewServerConn(c, s.config)
+	if err != nil {
+		s.logger.V(4).Info("SSH handshake failed", "err", err)
+		return
+	}
+	defer serverConn.Close() //nolint:errcheck
+	s.logger.V(2).Info("SSH session opened", "user", serverConn.User())
+
+	go gossh.DiscardRequests(reqs)
+	for newChan := range chans {
+		if newChan.ChannelType() != "session" {
+			newChan.Reject(gossh.UnknownChannelType, "only session channels are supported") //nolint:errcheck
+			continue
+		}
+		ch, requests, err := newChan.Accept()
+		if err != nil {
+			return
+		}
+		go s.handleSession(ctx, ch, requests, serverConn.User())
+	}
+}
+
+// ptyRequest is the payload of a pty-req (RFC 4254 section 6.2).
+type ptyRequest struct {
+	Term          string
+	Columns, Rows uint32
+	Width, Height uint32
+	Modes         string
+}
+
+// windowChange is the payload of a window-change (RFC 4254 section 6.7).
+type windowChange struct {
+	Columns, Rows uint32
+	Width, Height uint32
+}
+
+// handleSession serves one session channel. It keeps reading requests after
+// the command starts — clients send window-change (and may send others) for
+// the whole session, and an unread request channel stalls the connection.
+func (s *Server) handleSession(ctx context.Context, ch gossh.Channel, requests <-chan *gossh.Request, user string) {
+	defer ch.Close() //nolint:errcheck
+
+	var (
+		env    []string
+		pty    *ptyRequest
+		master *os.File      // the PTY of a running session, if it has one
+		done   chan struct{} // closed once the session's command exited; nil until started
+	)
+	for {
+		var req *gossh.Request
+		select {
+		case <-done:
+			return
+		case r, ok := <-requests:
+			if !ok {
+				if done != nil {
+					<-done
+				}
+				return
+			}
+			req = r
+		}
+
+		switch req.Type {
+		case "env":
+			var kv struct{ Name, Value string }
+			if done != nil || gossh.Unmarshal(req.Payload, &kv) != nil {
+				reply(req, false)
+				continue
+			}
+			env = append(env, kv.Name+"="+kv.Value)
+			reply(req, true)
+		case "pty-req":
+			var p ptyRequest
+			if s.opts.ExecOnly || !ptySupported || done != nil || gossh.Unmarshal(req.Payload, &p) != nil {
+				reply(req, false)
+				continue
+			}
+			pty = &p
+			reply(req, true)
+		case "window-change":
+			var wc windowChange
+			if gossh.Unmarshal(req.Payload, &wc) != nil || pty == nil {
+				reply(req, false)
+				continue
+			}
+			pty.Columns, pty.Rows = wc.Columns, wc.Rows
+			if master != nil {
+				if err := resizePTY(master, wc.Columns, wc.Rows); err != nil {
+					s.logger.V(4).Info("SSH window change failed", "err", err)
+				}
+			}
+			reply(req, true)
+		case "exec":
+			var payload struct{ Command string }
+			if done != nil || gossh.Unmarshal(req.Payload, &payload) != nil {
+				reply(req, false)
+				continue
+			}
+			reply(req, true)
+			master, done = s.start(ctx, ch, exec.CommandContext(ctx, s.opts.Shell, "-c", payload.Command), user, env, pty)
+		case "shell":
+			if done != nil {
+				reply(req, false)
+				continue
+			}
+			if s.opts.ExecOnly {
+				reply(req, false)
+				fmt.Fprintln(ch.Stderr(), "interactive shells are disabled on this edge; run a command instead") //nolint:errcheck
+				sendExitStatus(ch, 1)
+				return
+			}
+			reply(req, true)
+			master, done = s.start(ctx, ch, exec.CommandContext(ctx, s.opts.Shell, "-i"), user, env, pty)
+		default:
+			reply(req, false)
+		}
+	}
+}
+
+// start runs cmd for the session in the background, on a fresh PTY when the
+// client requested one. It returns that PTY's master (nil without one) and a
+// channel closed once the command exited and its status was sent.
+func (s *Server) start(ctx context.Context, ch gossh.Channel, cmd *exec.Cmd, user string, env []string, pty *ptyRequest) (*os.File, chan struct{}) {
+	done := make(chan struct{})
+	cmd.Env = append(sessionEnv(user), env...)
+	if home, err := os.UserHomeDir(); err == nil {
+		cmd.Dir = home
+	}
+
+	if pty == nil {
+		go func() {
+			defer close(done)
+			s.run(ctx, ch, cmd, user)
+		}()
+		return nil, done
+	}
+
+	master, tty, err := openPTY(pty.Columns, pty.Rows)
+	if err != nil {
+		s.logger.Error(err, "SSH session PTY allocation failed", "user", user)
+		fmt.Fprintln(ch.Stderr(), "failed to allocate a terminal") //nolint:errcheck
+		sendExitStatus(ch, 1)
+		close(done)
+		return nil, done
+	}
+	term := pty.Term
+	if term == "" {
+		term = "xterm"
+	}
+	cmd.Env = append(cmd.Env, "TERM="+term)
+	go func() {
+		defer close(done)
+		s.runPTY(ctx, ch, cmd, user, master, tty)
+	}()
+	return master, done
+}
+
+// run executes cmd wired to ch and reports its exit status.
+func (s *Server) run(ctx context.Context, ch gossh.Channel, cmd *exec.Cmd, user string) {
+	cmd.Stdout = ch
+	cmd.Stderr = ch.Stderr()
+	// StdinPipe rather than cmd.Stdin = ch so Wait does not block on the
+	// channel once the process has exited.
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		sendExitStatus(ch, 1)
+		return
+	}
+	go func() {
+		io.Copy(stdin, ch) //nolint:errcheck
+		stdin.Close()      //nolint:errcheck
+	}()
+
+	sendExitStatus(ch, s.exitCode(ctx, cmd.Run(), user))
+}
+
+// ptyDrainTimeout bounds how long output is still relayed after the session's
+// command exits; a background job that kept the terminal open would otherwise
+// hold the session forever.
+const ptyDrainTimeout = time.Second
+
+// runPTY executes cmd on tty, relays the terminal through master to ch and
+// reports the exit status.
+func (s *Server) runPTY(ctx context.Context, ch gossh.Channel, cmd *exec.Cmd, user string, master, tty *os.File) {
+	defer master.Close() //nolint:errcheck
+
+	attachPTY(cmd, tty)
+	err := cmd.Start()
+	tty.Close() //nolint:errcheck
+	if err != nil {
+		s.logger.Error(err, "SSH session command failed", "user", user)
+		sendExitStatus(ch, 1)
+		return
+	}
+
+	go io.Copy(master, ch) //nolint:errcheck
+	output := make(chan struct{})
+	go func() {
+		// Reads end with EIO once every holder of the terminal is gone.
+		io.Copy(ch, master) //nolint:errcheck
+		close(output)
+	}()
+
+	code := s.exitCode(ctx, cmd.Wait(), user)
+	select {
+	case <-output:
+	case <-time.After(ptyDrainTimeout):
+	}
+	sendExitStatus(ch, code)
+}
+
+// exitCode maps a command's Run/Wait error to the exit status reported to the
+// client, logging failures that are not the command's own exit.
+func (s *Server) exitCode(ctx context.Context, err error, user string) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() >= 0 {
+		return exitErr.ExitCode()
+	}
+	if ctx.Err() == nil {
+		s.logger.Error(err, "SSH session command failed", "user", user)
+	}
+	return 1
+}
+
+// sessionEnv is the agent's environment with USER/LOGNAME set to the login
+// user, so scripts see the identity the hub connected as.
+func sessionEnv(user string) []string {
+	env := make([]string, 0, len(os.Environ())+2)
+	for _, e := range os.Environ() {
+		if strings.HasPrefix(e, "USER=") || strings.HasPrefix(e, "LOGNAME=") {
+			continue
+		}
+		env = append(env, e)
+	}
+	return append(env, "USER="+user, "LOGNAME="+user)
+}
+
+func reply(req *gossh.Request, ok bool) {
+	if req.WantReply {
+		req.Reply(ok, nil) //nolint:errcheck
+	}
+}
+
+func sendExitStatus(ch gossh.Channel, code int) {
+	ch.SendRequest("exit-status", false, gossh.Marshal(struct{ Code uint32 }{uint32(code)})) //nolint:errcheck
+}
+
+// readAuthorizedKeys parses an authorized_keys file, skipping blank lines,
+// comments and entries it cannot parse. A missing file yields no keys.
+func readAuthorizedKeys(path string) []gossh.PublicKey {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var keys []gossh.PublicKey
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if k, _, _, _, err := gossh.ParseAuthorizedKey([]byte(line)); err == nil {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// loadOrCreateHostKey reads the host key at path, generating and persisting an
+// ed25519 key when it does not exist yet. An empty path yields an ephemeral key.
+func loadOrCreateHostKey(path string) (gossh.Signer, error) {
+	if path != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			signer, err := gossh.ParsePrivateKey(data)
+			if err != nil {
+				return nil, fmt.Errorf("sshserver: parsing host key %s: %w", path, err)
+			}
+			return signer, nil
+		} else if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("sshserver: reading host key %s: %w", path, err)
+		}
+	}
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("sshserver: generating host key: %w", err)
+	}
+	signer, err := gossh.NewSignerFromKey(priv)
+	if err != nil {
+		return nil, fmt.Errorf("sshserver: creating host key signer: %w", err)
+	}
+	if path == "" {
+		return signer, nil
+	}
+
+	block, err := gossh.MarshalPrivateKey(priv, "kedge-agent-host")
+	if err != nil {
+		return nil, fmt.Errorf("sshserver: marshaling host key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("sshserver: creating %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+		return nil, fmt.Errorf("sshserver: writing host key %s: %w", path, err)
+	}
+	return signer, nil
+}
@@ -0,0 +1,346 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sshserver
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	gossh "golang.org/x/crypto/ssh"
+)
+
+func TestEmbeddedServer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, clientKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := gossh.NewSignerFromKey(clientKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	authKeys := filepath.Join(dir, "authorized_keys")
+	if err := os.WriteFile(authKeys, gossh.MarshalAuthorizedKey(signer.PublicKey()), 0600); err != nil {
+		t.Fatal(err)
+	}
+	hostKeyPath := filepath.Join(dir, "host_key")
+
+	srv, err := New(Options{
+		User:               "edge",
+		Password:           "s3cret",
+		AuthorizedKeysFile: authKeys,
+		HostKeyPath:        hostKeyPath,
+		ExecOnly:           true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, err := srv.Listen(ctx, "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+
+	dial := func(user string, auth gossh.AuthMethod) (*gossh.Client, error) {
+		return gossh.Dial("tcp", addr, &gossh.ClientConfig{
+			User:            user,
+			Auth:            []gossh.AuthMethod{auth},
+			HostKeyCallback: gossh.InsecureIgnoreHostKey(), //nolint:gosec
+		})
+	}
+
+	t.Run("exec with authorized key", func(t *testing.T) {
+		client, err := dial("edge", gossh.PublicKeys(signer))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer client.Close() //nolint:errcheck
+		sess, err := client.NewSession()
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := sess.Output("echo hello $USER")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.TrimSpace(string(out)); got != "hello edge" {
+			t.Fatalf("output = %q, want %q", got, "hello edge")
+		}
+	})
+
+	t.Run("exit status propagates", func(t *testing.T) {
+		client, err := dial("edge", gossh.Password("s3cret"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer client.Close() //nolint:errcheck
+		sess, err := client.NewSession()
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = sess.Run("exit 3")
+		exitErr, ok := err.(*gossh.ExitError)
+		if !ok || exitErr.ExitStatus() != 3 {
+			t.Fatalf("Run() error = %v, want exit status 3", err)
+		}
+	})
+
+	t.Run("shell rejected when exec-only", func(t *testing.T) {
+		client, err := dial("edge", gossh.PublicKeys(signer))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer client.Close() //nolint:errcheck
+		sess, err := client.NewSession()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sess.Shell(); err == nil {
+			t.Fatal("Shell() succeeded on an exec-only server")
+		}
+	})
+
+	t.Run("wrong user rejected", func(t *testing.T) {
+		if _, err := dial("root", gossh.PublicKeys(signer)); err == nil {
+			t.Fatal("expected authentication as another user to fail")
+		}
+	})
+
+	t.Run("wrong password rejected", func(t *testing.T) {
+		if _, err := dial("edge", gossh.Password("nope")); err == nil {
+			t.Fatal("expected a wrong password to fail")
+		}
+	})
+
+	// The host key is persisted so the key the hub pins stays stable.
+	first, err := loadOrCreateHostKey(hostKeyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := loadOrCreateHostKey(hostKeyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(first.PublicKey().Marshal()) != string(second.PublicKey().Marshal()) {
+		t.Fatal("host key changed between loads")
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for the SSH session's output goroutine.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf strings.Builder
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// waitFor polls out until it contains want.
+func waitFor(t *testing.T, out *syncBuffer, want string) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for !strings.Contains(out.String(), want) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %q; output so far:\n%s", want, out.String())
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// newInteractiveClient starts a server that allows shells and returns a
+// client authenticated with its password.
+func newInteractiveClient(t *testing.T) *gossh.Client {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	srv, err := New(Options{User: "edge", Password: "s3cret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, err := srv.Listen(ctx, "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := gossh.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", port), &gossh.ClientConfig{
+		User:            "edge",
+		Auth:            []gossh.AuthMethod{gossh.Password("s3cret")},
+		HostKeyCallback: gossh.InsecureIgnoreHostKey(), //nolint:gosec
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() }) //nolint:errcheck
+	return client
+}
+
+func TestEmbeddedServerPTY(t *testing.T) {
+	if !ptySupported {
+		t.Skip("PTY allocation is not supported on this platform")
+	}
+	sess, err := newInteractiveClient(t).NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdin, err := sess.StdinPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &syncBuffer{}
+	sess.Stdout = out
+	sess.Stderr = out
+	if err := sess.RequestPty("xterm", 24, 80, gossh.TerminalModes{gossh.ECHO: 0}); err != nil {
+		t.Fatalf("RequestPty() = %v", err)
+	}
+	if err := sess.Shell(); err != nil {
+		t.Fatal(err)
+	}
+
+	fmt.Fprintln(stdin, `echo "size=$(stty size) term=$TERM"`) //nolint:errcheck
+	waitFor(t, out, "size=24 80 term=xterm")
+
+	// window-change is still serviced while the shell runs. It travels
+	// apart from stdin, so ask until the shell sees the new size.
+	if err := sess.WindowChange(40, 100); err != nil {
+		t.Fatalf("WindowChange() = %v", err)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for !strings.Contains(out.String(), "resized=40 100") {
+		if time.Now().After(deadline) {
+			t.Fatalf("window change not applied; output so far:\n%s", out.String())
+		}
+		fmt.Fprintln(stdin, `echo "resized=$(stty size)"`) //nolint:errcheck
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	fmt.Fprintln(stdin, "exit 7") //nolint:errcheck
+	err = sess.Wait()
+	exitErr, ok := err.(*gossh.ExitError)
+	if !ok || exitErr.ExitStatus() != 7 {
+		t.Fatalf("Wait() error = %v, want exit status 7", err)
+	}
+}
+
+// TestEmbeddedServerServicesRequestsDuringExec checks that a request sent
+// while a command runs is answered rather than stalling the connection.
+func TestEmbeddedServerServicesRequestsDuringExec(t *testing.T) {
+	sess, err := newInteractiveClient(t).NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &syncBuffer{}
+	sess.Stdout = out
+	if err := sess.Start("sleep 1; echo done"); err != nil {
+		t.Fatal(err)
+	}
+
+	replied := make(chan bool, 1)
+	go func() {
+		ok, _ := sess.SendRequest("env", true, gossh.Marshal(struct{ Name, Value string }{"LATE", "1"}))
+		replied <- ok
+	}()
+	select {
+	case ok := <-replied:
+		if ok {
+			t.Error("env after exec was accepted")
+		}
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("request sent during exec was not answered")
+	}
+
+	if err := sess.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(out.String()); got != "done" {
+		t.Errorf("output = %q, want done", got)
+	}
+}
+
+// TestEmbeddedServerSetCredentials checks that replaced credentials stop
+// working and their replacements work without a restart.
+func TestEmbeddedServerSetCredentials(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	newSigner := func() gossh.Signer {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signer, err := gossh.NewSignerFromKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signer
+	}
+	oldKey, newKey := newSigner(), newSigner()
+
+	srv, err := New(Options{User: "edge", Password: "old", AuthorizedKeys: []gossh.PublicKey{oldKey.PublicKey()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, err := srv.Listen(ctx, "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	login := func(auth gossh.AuthMethod) bool {
+		client, err := gossh.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", port), &gossh.ClientConfig{
+			User:            "edge",
+			Auth:            []gossh.AuthMethod{auth},
+			HostKeyCallback: gossh.InsecureIgnoreHostKey(), //nolint:gosec
+		})
+		if err != nil {
+			return false
+		}
+		client.Close() //nolint:errcheck
+		return true
+	}
+
+	if !login(gossh.Password("old")) || !login(gossh.PublicKeys(oldKey)) {
+		t.Fatal("initial credentials rejected")
+	}
+	srv.SetCredentials("new", []gossh.PublicKey{newKey.PublicKey()})
+	if login(gossh.Password("old")) || login(gossh.PublicKeys(oldKey)) {
+		t.Error("replaced credentials still accepted")
+	}
+	if !login(gossh.Password("new")) || !login(gossh.PublicKeys(newKey)) {
+		t.Error("rotated credentials rejected")
+	}
+	srv.SetCredentials("", []gossh.PublicKey{newKey.PublicKey()})
+	if login(gossh.Password("")) || login(gossh.Password("new")) {
+		t.Error("password accepted after it was removed")
+	}
+}
@@ -26,12 +26,18 @@ import (
 	"time"
 
 	gossh "golang.org/x/crypto/ssh"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
+	"github.com/faroshq/faros-kedge/pkg/agent/clock"
+	"github.com/faroshq/faros-kedge/pkg/agent/health"
 	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
 	pkgversion "github.com/faroshq/faros-kedge/pkg/version"
 )
@@ -78,8 +84,22 @@ func DialAndFetchSSHHostKey(port int, logger klog.Logger) string {
 }
 
 const (
-	// HeartbeatInterval is how often the agent sends heartbeats to the hub.
+	// HeartbeatInterval is how often the agent sends heartbeats to the hub
+	// unless SetHeartbeatInterval changes it.
 	HeartbeatInterval = 30 * time.Second
+
+	// heartbeatSubsystem names the heartbeat in the agent's health tracker.
+	heartbeatSubsystem = "heartbeat"
+
+	// conditionDraining is the edge condition the agent sets, with reason
+	// reasonAgentShutdown, while it shuts down. The provider owns the
+	// condition otherwise (cordon and deletion drains).
+	conditionDraining   = "Draining"
+	reasonAgentShutdown = "AgentShutdown"
+
+	// shutdownWriteTimeout bounds each status write made after the agent
+	// was told to stop.
+	shutdownWriteTimeout = 5 * time.Second
 )
 
 // EdgeReporter sends heartbeats for an Edge resource.
@@ -93,6 +113,46 @@ type EdgeReporter struct {
 	// sshProxyPort is the local port of the SSH daemon the agent proxies to.
 	// Zero means SSH host key reporting is disabled (non-server-mode edges).
 	sshProxyPort int
+	// endToEndTLS is the agent's end-to-end TLS serving certificate, reported
+	// as status.endToEndTLS; nil when the agent does not offer it.
+	endToEndTLS map[string]interface{}
+	// location is written to spec.location if the edge has none; cleared once
+	// the edge has a location.
+	location map[string]interface{}
+	// health is the agent's error tracker; its most severe problem becomes
+	// the AgentHealthy condition.
+	health *health.Tracker
+	// clock measures the skew versus the hub for the ClockSynchronized
+	// condition, which turns False beyond clockThreshold.
+	clock          *clock.Skew
+	clockThreshold time.Duration
+	// downstream is the edge cluster whose inventory is reported as
+	// status.inventory, every InventoryInterval; nil disables it.
+	downstream    kubernetes.Interface
+	inventoryTime time.Time
+	// traffic accounts for the tunnel's traffic, reported as
+	// status.tunnelTraffic; nil disables it.
+	traffic TrafficCounter
+	// hostPaths are the filesystems whose usage is reported, with the
+	// server's load, memory and temperatures, as status.hostTelemetry on
+	// every heartbeat; nil disables it. diskPressure is the DiskPressure
+	// condition from the last collection, at diskPressureThreshold percent.
+	hostPaths             []string
+	diskPressureThreshold int
+	diskPressure          *metav1.Condition
+	// lastConditions are the agent-owned conditions last written, so
+	// unchanged ones are not rewritten every heartbeat.
+	lastConditions map[string]metav1.Condition
+	// interval is the heartbeat interval, reported as
+	// status.heartbeatIntervalSeconds so the hub can tell a stale heartbeat
+	// from a slow one.
+	interval time.Duration
+	// shutdownGrace is how long Run, once its context is done, waits for
+	// the tunnel to drain before the final heartbeat.
+	shutdownGrace time.Duration
+	// draining is set while the agent shuts down; the Draining condition
+	// is then reported.
+	draining bool
 }
 
 // NewEdgeReporter creates a new EdgeReporter.
@@ -107,15 +167,94 @@ func NewEdgeReporter(edgeName string, gvr schema.GroupVersionResource, hubClient
 		hubClient:    hubClient,
 		tunnelState:  tunnelState,
 		sshProxyPort: sshProxyPort,
+		interval:     HeartbeatInterval,
+	}
+}
+
+// SetHeartbeatInterval sets how often the reporter heartbeats. The hub marks
+// the edge Disconnected after three missed heartbeats, so a longer interval
+// saves traffic on metered links at the cost of slower detection. Call before
+// Run.
+func (r *EdgeReporter) SetHeartbeatInterval(interval time.Duration) {
+	if interval > 0 {
+		r.interval = interval
 	}
 }
 
+// SetShutdownGracePeriod sets how long Run, on shutdown, waits for the
+// tunnel's sessions to drain before it reports the edge disconnected. Call
+// before Run.
+func (r *EdgeReporter) SetShutdownGracePeriod(grace time.Duration) {
+	r.shutdownGrace = grace
+}
+
+// SetEndToEndTLS reports the agent's end-to-end TLS serving certificate
+// (PEM) and its fingerprint with every heartbeat. Call before Run.
+func (r *EdgeReporter) SetEndToEndTLS(certificatePEM, fingerprint string) {
+	r.endToEndTLS = map[string]interface{}{
+		"certificate": certificatePEM,
+		"fingerprint": fingerprint,
+	}
+}
+
+// SetLocation has the reporter fill the edge's spec.location with location
+// unless the edge already has one. Call before Run.
+func (r *EdgeReporter) SetLocation(location map[string]interface{}) {
+	r.location = location
+}
+
+// SetHealth has the reporter publish tracker's most severe problem as the
+// edge's AgentHealthy condition. Call before Run.
+func (r *EdgeReporter) SetHealth(tracker *health.Tracker) {
+	r.health = tracker
+}
+
+// SetClock has the reporter publish the clock skew skew measures, and the
+// kernel's NTP state, as the edge's ClockSynchronized condition, warning
+// when the skew exceeds threshold. Call before Run.
+func (r *EdgeReporter) SetClock(skew *clock.Skew, threshold time.Duration) {
+	r.clock = skew
+	r.clockThreshold = threshold
+}
+
+// SetInventory has the reporter publish the node count, capacity, version,
+// provider and CNI of the downstream cluster as the edge's status.inventory.
+// Call before Run.
+func (r *EdgeReporter) SetInventory(downstream kubernetes.Interface) {
+	r.downstream = downstream
+}
+
+// TrafficCounter reports the traffic proxied over the agent's tunnel and
+// its bandwidth limits in bytes per second, zero when unlimited.
+type TrafficCounter interface {
+	Totals() (sent, received, sessions int64)
+	Limits() (upload, download int64)
+}
+
+// SetTunnelTraffic has the reporter publish traffic's totals and limits as
+// the edge's status.tunnelTraffic. Call before Run.
+func (r *EdgeReporter) SetTunnelTraffic(traffic TrafficCounter) {
+	r.traffic = traffic
+}
+
+// SetHostTelemetry has the reporter publish the server's load, memory,
+// temperatures and the usage of the filesystems at paths as the edge's
+// status.hostTelemetry, and set the DiskPressure condition while one of them
+// is at least diskPressureThreshold percent full; zero leaves the condition
+// out. Call before Run.
+func (r *EdgeReporter) SetHostTelemetry(paths []string, diskPressureThreshold int) {
+	r.hostPaths = paths
+	r.diskPressureThreshold = diskPressureThreshold
+}
+
 // Run starts the edge heartbeat reporter and blocks until ctx is cancelled.
+// It then marks the edge Draining, waits for the tunnel to drain, and sends a
+// final heartbeat reporting the edge disconnected before it returns.
 func (r *EdgeReporter) Run(ctx context.Context) error {
 	logger := klog.FromContext(ctx).WithName("edge-status-reporter")
 	logger.Info("Starting edge status reporter", "edgeName", r.edgeName)
 
-	ticker := time.NewTicker(HeartbeatInterval)
+	ticker := time.NewTicker(r.interval)
 	defer ticker.Stop()
 
 	// First heartbeat immediately.
@@ -124,6 +263,7 @@ func (r *EdgeReporter) Run(ctx context.Context) error {
 	for {
 		select {
 		case <-ctx.Done():
+			r.shutdown(ctx, logger)
 			return nil
 		case connected, ok := <-r.tunnelState:
 			if ok {
@@ -136,7 +276,43 @@ func (r *EdgeReporter) Run(ctx context.Context) error {
 	}
 }
 
+// shutdown reports the edge Draining, waits up to the shutdown grace period
+// for the tunnel to report itself closed, and flushes a final heartbeat.
+// ctx is already done, so each write gets its own deadline.
+func (r *EdgeReporter) shutdown(ctx context.Context, logger klog.Logger) {
+	writeCtx := func() (context.Context, context.CancelFunc) {
+		return context.WithTimeout(context.WithoutCancel(ctx), shutdownWriteTimeout)
+	}
+
+	r.draining = true
+	drainCtx, cancel := writeCtx()
+	r.reportConditions(drainCtx, logger)
+	cancel()
+
+	wait := time.NewTimer(r.shutdownGrace + shutdownWriteTimeout)
+	defer wait.Stop()
+	for r.tunnelConnected && r.tunnelState != nil {
+		select {
+		case connected, ok := <-r.tunnelState:
+			r.tunnelConnected = ok && connected
+		case <-wait.C:
+			logger.Info("Tunnel did not drain in time; reporting the edge disconnected", "edge", r.edgeName)
+			r.tunnelConnected = false
+		}
+	}
+
+	r.tunnelConnected = false
+	finalCtx, cancel := writeCtx()
+	defer cancel()
+	r.sendHeartbeat(finalCtx, logger)
+	logger.Info("Final edge status sent", "edge", r.edgeName)
+}
+
 func (r *EdgeReporter) sendHeartbeat(ctx context.Context, logger klog.Logger) {
+	if r.location != nil {
+		r.reportLocation(ctx, logger)
+	}
+
 	// The hub may set Hostname/WorkspaceURL; we only patch the fields we own.
 	// "Ready" mirrors the provider's EdgePhaseReady; the Edge type now lives in
 	// the edges-connectivity provider so we build the patch as a plain map and
@@ -146,6 +322,8 @@ func (r *EdgeReporter) sendHeartbeat(ctx context.Context, logger klog.Logger) {
 		"connected":         r.tunnelConnected,
 		"agentVersion":      pkgversion.Get(),
 		"lastHeartbeatTime": metav1.Now(),
+		// Rounded up: the hub only needs it to size its staleness window.
+		"heartbeatIntervalSeconds": int64((r.interval + time.Second - 1) / time.Second),
 	}
 
 	// Report the sshd host public key so the hub can verify the agent's identity.
@@ -157,6 +335,49 @@ func (r *EdgeReporter) sendHeartbeat(ctx context.Context, logger klog.Logger) {
 		}
 	}
 
+	// Always send the end-to-end TLS state of a KubernetesCluster, so an agent
+	// restarted without it clears the certificate (JSON merge patch null).
+	if r.gvr == kedgeclient.KubernetesClusterGVR {
+		statusPatch["endToEndTLS"] = r.endToEndTLS
+	}
+
+	if r.traffic != nil {
+		sent, received, sessions := r.traffic.Totals()
+		upload, download := r.traffic.Limits()
+		// A null clears a limit an earlier run of the agent reported.
+		limit := func(v int64) interface{} {
+			if v == 0 {
+				return nil
+			}
+			return v
+		}
+		statusPatch["tunnelTraffic"] = map[string]interface{}{
+			"bytesSent":                   sent,
+			"bytesReceived":               received,
+			"sessions":                    sessions,
+			"uploadLimitBytesPerSecond":   limit(upload),
+			"downloadLimitBytesPerSecond": limit(download),
+		}
+	}
+
+	// The inventory rides along with a heartbeat every InventoryInterval;
+	// the merge patch leaves the last one in place in between.
+	inventoryDue := r.downstream != nil && time.Since(r.inventoryTime) >= InventoryInterval
+	if inventoryDue {
+		inventory, err := collectInventory(ctx, r.downstream, time.Now())
+		r.health.Observe(inventorySubsystem, err)
+		if err != nil {
+			logger.Error(err, "failed to collect cluster inventory", "edge", r.edgeName)
+			inventoryDue = false
+		} else {
+			statusPatch["inventory"] = inventory
+		}
+	}
+
+	if r.hostPaths != nil {
+		r.reportHostTelemetry(logger, statusPatch)
+	}
+
 	patch := map[string]interface{}{
 		"status": statusPatch,
 	}
@@ -170,11 +391,223 @@ func (r *EdgeReporter) sendHeartbeat(ctx context.Context, logger klog.Logger) {
 	_, err = r.hubClient.Dynamic().Resource(r.gvr).Patch(ctx, r.edgeName,
 		types.MergePatchType, patchBytes,
 		metav1.PatchOptions{}, "status")
+	r.health.Observe(heartbeatSubsystem, err)
 	if err != nil {
 		logger.Error(err, "failed to update edge status", "edge", r.edgeName)
 		return
 	}
+	if inventoryDue {
+		r.inventoryTime = time.Now()
+	}
+	if r.health != nil || r.clock != nil || r.diskPressure != nil {
+		r.reportConditions(ctx, logger)
+	}
 
 	logger.V(4).Info("Edge heartbeat sent", "edge", r.edgeName,
 		"phase", "Ready", "connected", r.tunnelConnected)
 }
+
+// reportHostTelemetry adds the server's telemetry to statusPatch and
+// updates the DiskPressure condition. Collection failures are reported to
+// the agent's health and leave the last telemetry in place.
+func (r *EdgeReporter) reportHostTelemetry(logger klog.Logger, statusPatch map[string]interface{}) {
+	host, err := collectHostTelemetry(r.hostPaths)
+	r.health.Observe(hostTelemetrySubsystem, err)
+	if err != nil {
+		logger.Error(err, "failed to collect host telemetry", "edge", r.edgeName)
+		return
+	}
+	if host == nil {
+		return
+	}
+	statusPatch["hostTelemetry"] = host.patch(time.Now())
+	if r.diskPressureThreshold > 0 {
+		cond := diskPressureCondition(host.filesystems, r.diskPressureThreshold)
+		if cond.Status == metav1.ConditionTrue && (r.diskPressure == nil || r.diskPressure.Message != cond.Message) {
+			logger.Error(nil, "Filesystem almost full", "edge", r.edgeName, "detail", cond.Message)
+		}
+		r.diskPressure = &cond
+	}
+}
+
+// reportLocation writes r.location into the edge's spec.location if that is
+// empty. A location set when the edge was created, or edited since, is left
+// alone. Failures are retried with the next heartbeat.
+func (r *EdgeReporter) reportLocation(ctx context.Context, logger klog.Logger) {
+	edge, err := r.hubClient.Dynamic().Resource(r.gvr).Get(ctx, r.edgeName, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(err, "failed to get edge to report its location", "edge", r.edgeName)
+		return
+	}
+	if _, found, _ := unstructured.NestedMap(edge.Object, "spec", "location"); found {
+		logger.V(2).Info("Edge already has a location; not overriding it", "edge", r.edgeName)
+		r.location = nil
+		return
+	}
+
+	patchBytes, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{"location": r.location},
+	})
+	if err != nil {
+		logger.Error(err, "failed to marshal edge location patch")
+		return
+	}
+	if _, err := r.hubClient.Dynamic().Resource(r.gvr).Patch(ctx, r.edgeName,
+		types.MergePatchType, patchBytes, metav1.PatchOptions{}); err != nil {
+		logger.Error(err, "failed to set edge location", "edge", r.edgeName)
+		return
+	}
+	logger.Info("Edge location set", "edge", r.edgeName)
+	r.location = nil
+}
+
+// healthCondition renders the tracker's most severe problem as the
+// AgentHealthy condition.
+func healthCondition(tracker *health.Tracker) metav1.Condition {
+	p, failing := tracker.Worst()
+	if !failing {
+		return metav1.Condition{
+			Type:    health.ConditionAgentHealthy,
+			Status:  metav1.ConditionTrue,
+			Reason:  health.ReasonAsExpected,
+			Message: "No agent subsystem is failing.",
+		}
+	}
+	action := "retrying"
+	if p.Severity == health.SeverityFatal {
+		action = "needs operator action"
+	}
+	return metav1.Condition{
+		Type:   health.ConditionAgentHealthy,
+		Status: metav1.ConditionFalse,
+		Reason: p.Reason,
+		Message: fmt.Sprintf("%s: %s (%s, %s since %s)", p.Subsystem, p.Message,
+			p.Severity, action, p.Since.UTC().Format(time.RFC3339)),
+	}
+}
+
+// clockCondition renders the measured offset of the hub's clock from the
+// agent's, and the kernel's NTP state, as the ClockSynchronized condition.
+func clockCondition(offset time.Duration, ntpSynced, ntpKnown bool, threshold time.Duration) metav1.Condition {
+	skew := offset.Abs().Round(time.Second)
+	var where string
+	switch {
+	case skew == 0:
+		where = "within a second of the hub"
+	case offset > 0:
+		where = skew.String() + " behind the hub"
+	default:
+		where = skew.String() + " ahead of the hub"
+	}
+
+	cond := metav1.Condition{Type: clock.ConditionClockSynchronized}
+	switch {
+	case offset.Abs() > threshold:
+		cond.Status, cond.Reason = metav1.ConditionFalse, clock.ReasonSkewed
+		cond.Message = fmt.Sprintf("Agent clock is %s, beyond the %s threshold; fix time synchronization on the edge.", where, threshold)
+		if offset.Abs() > clock.MaxCorrection {
+			cond.Message += " Too far off for the agent to correct hub TLS verification for."
+		}
+	case ntpKnown && !ntpSynced:
+		cond.Status, cond.Reason = metav1.ConditionFalse, clock.ReasonNTPUnsynchronized
+		cond.Message = fmt.Sprintf("Agent clock is %s, but the kernel reports it as not synchronized by NTP, so it will drift.", where)
+	default:
+		cond.Status, cond.Reason = metav1.ConditionTrue, clock.ReasonInSync
+		cond.Message = fmt.Sprintf("Agent clock is %s.", where)
+	}
+	return cond
+}
+
+// reportConditions writes the agent-owned conditions, AgentHealthy,
+// ClockSynchronized and DiskPressure, and Draining while the agent shuts
+// down, when they changed since the last write. A Draining condition left by
+// the agent's previous shutdown is removed. The provider owns the edge's other
+// conditions, and a merge patch replaces the whole list, so they are merged
+// into the current list and written with the edge's resourceVersion; a
+// conflicting write is retried with the next heartbeat.
+func (r *EdgeReporter) reportConditions(ctx context.Context, logger klog.Logger) {
+	var conds []metav1.Condition
+	if r.health != nil {
+		conds = append(conds, healthCondition(r.health))
+	}
+	if offset, ok := r.clock.Offset(); ok {
+		synced, known := clock.NTPSynchronized()
+		cond := clockCondition(offset, synced, known, r.clockThreshold)
+		if last, ok := r.lastConditions[cond.Type]; cond.Reason == clock.ReasonSkewed && (!ok || last.Reason != cond.Reason) {
+			logger.Error(nil, "Agent clock is skewed versus the hub; tokens and TLS may be rejected until it is fixed",
+				"edge", r.edgeName, "offset", offset.Round(time.Second).String(), "threshold", r.clockThreshold.String())
+		}
+		conds = append(conds, cond)
+	}
+	if r.diskPressure != nil {
+		conds = append(conds, *r.diskPressure)
+	}
+	if r.draining {
+		conds = append(conds, metav1.Condition{
+			Type:    conditionDraining,
+			Status:  metav1.ConditionTrue,
+			Reason:  reasonAgentShutdown,
+			Message: "Agent is shutting down; open sessions are finishing and new ones are refused.",
+		})
+	}
+
+	changed := false
+	for _, cond := range conds {
+		last, ok := r.lastConditions[cond.Type]
+		if !ok || last.Status != cond.Status || last.Reason != cond.Reason || last.Message != cond.Message {
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+
+	res := r.hubClient.Dynamic().Resource(r.gvr)
+	edge, err := res.Get(ctx, r.edgeName, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(err, "failed to get edge to report agent conditions", "edge", r.edgeName)
+		return
+	}
+	var conditions []metav1.Condition
+	if raw, found, _ := unstructured.NestedSlice(edge.Object, "status", "conditions"); found {
+		for _, item := range raw {
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			var c metav1.Condition
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, &c); err == nil {
+				conditions = append(conditions, c)
+			}
+		}
+	}
+	if c := meta.FindStatusCondition(conditions, conditionDraining); !r.draining && c != nil && c.Reason == reasonAgentShutdown {
+		meta.RemoveStatusCondition(&conditions, conditionDraining)
+	}
+	for i := range conds {
+		conds[i].ObservedGeneration = edge.GetGeneration()
+		meta.SetStatusCondition(&conditions, conds[i])
+	}
+
+	patchBytes, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"resourceVersion": edge.GetResourceVersion()},
+		"status":   map[string]interface{}{"conditions": conditions},
+	})
+	if err != nil {
+		logger.Error(err, "failed to marshal agent conditions patch")
+		return
+	}
+	if _, err := res.Patch(ctx, r.edgeName, types.MergePatchType, patchBytes,
+		metav1.PatchOptions{}, "status"); err != nil {
+		logger.Error(err, "failed to report agent conditions", "edge", r.edgeName)
+		return
+	}
+	if r.lastConditions == nil {
+		r.lastConditions = map[string]metav1.Condition{}
+	}
+	for _, cond := range conds {
+		logger.V(2).Info("Agent condition reported", "edge", r.edgeName,
+			"type", cond.Type, "status", cond.Status, "reason", cond.Reason)
+		r.lastConditions[cond.Type] = cond
+	}
+}
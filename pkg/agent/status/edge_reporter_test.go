@@ -0,0 +1,161 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/klog/v2"
+
+	"github.com/faroshq/faros-kedge/pkg/agent/clock"
+	"github.com/faroshq/faros-kedge/pkg/agent/health"
+	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+)
+
+func TestClockCondition(t *testing.T) {
+	tests := []struct {
+		name       string
+		offset     time.Duration
+		synced     bool
+		known      bool
+		wantStatus metav1.ConditionStatus
+		wantReason string
+		wantMsg    string
+	}{
+		{"in sync", 200 * time.Millisecond, true, true, metav1.ConditionTrue, clock.ReasonInSync, "within a second of the hub"},
+		{"ntp unknown", 3 * time.Second, false, false, metav1.ConditionTrue, clock.ReasonInSync, "3s behind the hub"},
+		{"ntp unsynchronized", -3 * time.Second, false, true, metav1.ConditionFalse, clock.ReasonNTPUnsynchronized, "3s ahead of the hub"},
+		{"skewed", 45 * time.Second, true, true, metav1.ConditionFalse, clock.ReasonSkewed, "45s behind the hub, beyond the 30s threshold"},
+		{"beyond correction", -time.Hour, true, true, metav1.ConditionFalse, clock.ReasonSkewed, "Too far off"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := clockCondition(tt.offset, tt.synced, tt.known, 30*time.Second)
+			if c.Type != clock.ConditionClockSynchronized || c.Status != tt.wantStatus || c.Reason != tt.wantReason {
+				t.Errorf("condition = %s/%s/%s", c.Type, c.Status, c.Reason)
+			}
+			if !strings.Contains(c.Message, tt.wantMsg) {
+				t.Errorf("message = %q, want it to contain %q", c.Message, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestEdgeReporterShutdown(t *testing.T) {
+	gvr := kedgeclient.KubernetesClusterGVR
+	edge := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": gvr.GroupVersion().String(),
+		"kind":       "KubernetesCluster",
+		"metadata":   map[string]interface{}{"name": "site-a"},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{map[string]interface{}{
+				"type": "Ready", "status": "True", "reason": "Connected",
+				"lastTransitionTime": "2026-01-01T00:00:00Z",
+			}},
+		},
+	}}
+	hub := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{gvr: "KubernetesClusterList"}, edge)
+
+	tunnelState := make(chan bool, 1)
+	r := NewEdgeReporter("site-a", gvr, kedgeclient.NewFromDynamic(hub), tunnelState, 0)
+	r.SetHealth(health.NewTracker())
+	r.SetShutdownGracePeriod(time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	tunnelState <- true
+	go func() {
+		_ = r.Run(ctx)
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	get := func() *unstructured.Unstructured {
+		t.Helper()
+		got, err := hub.Resource(gvr).Get(context.Background(), "site-a", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for draining(get()) == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("edge not marked Draining after shutdown began")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	select {
+	case <-done:
+		t.Fatal("Run returned before the tunnel drained")
+	default:
+	}
+
+	tunnelState <- false
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after the tunnel drained")
+	}
+	got := get()
+	if c := draining(got); c == nil || c.Reason != reasonAgentShutdown {
+		t.Errorf("Draining condition = %v, want reason %s", c, reasonAgentShutdown)
+	}
+	if connected, _, _ := unstructured.NestedBool(got.Object, "status", "connected"); connected {
+		t.Error("final heartbeat left status.connected true")
+	}
+
+	// The next run lifts the agent's drain and keeps the provider's conditions.
+	r = NewEdgeReporter("site-a", gvr, kedgeclient.NewFromDynamic(hub), nil, 0)
+	r.SetHealth(health.NewTracker())
+	ctx, cancel = context.WithCancel(context.Background())
+	r.sendHeartbeat(ctx, klog.Background())
+	cancel()
+	got = get()
+	if c := draining(got); c != nil {
+		t.Errorf("Draining condition = %v after restart, want it removed", c)
+	}
+	if conditions(got)["Ready"] == nil {
+		t.Error("provider-owned Ready condition lost")
+	}
+}
+
+func conditions(u *unstructured.Unstructured) map[string]*metav1.Condition {
+	raw, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	out := map[string]*metav1.Condition{}
+	for _, item := range raw {
+		var c metav1.Condition
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.(map[string]interface{}), &c); err == nil {
+			out[c.Type] = &c
+		}
+	}
+	return out
+}
+
+func draining(u *unstructured.Unstructured) *metav1.Condition {
+	return conditions(u)[conditionDraining]
+}
@@ -0,0 +1,411 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const (
+	eventForwarderName = "event-forwarder"
+
+	// WorkloadLabel carries the Workload a placement-managed object, and a
+	// forwarded event, belongs to.
+	WorkloadLabel = edgesGroup + "/workload"
+	// EdgeLabel carries the edge a forwarded event was recorded on.
+	EdgeLabel = edgesGroup + "/edge"
+	// SourceObjectAnnotation names the edge object a forwarded event is
+	// about, as kind/namespace/name.
+	SourceObjectAnnotation = edgesGroup + "/source-object"
+
+	placementUIDAnnotation = edgesGroup + "/placement-uid"
+
+	// eventOwnerDepth bounds the owner-reference walk from an event's object
+	// to the placement-managed object above it (Pod, ReplicaSet, Deployment).
+	eventOwnerDepth = 4
+	// eventOwnerCacheSize bounds the remembered object-to-placement lookups;
+	// the cache starts over when it fills up.
+	eventOwnerCacheSize = 4096
+	// eventMaxRetries is how often a failed forward is retried before the
+	// event is dropped; a recurrence queues it again.
+	eventMaxRetries = 5
+)
+
+var eventGVR = schema.GroupVersionResource{Version: "v1", Resource: "events"}
+
+// eventOwnerKinds are the kinds the forwarder follows owner references
+// through, by apiVersion/kind. Events about other kinds are not forwarded.
+var eventOwnerKinds = map[string]schema.GroupVersionResource{
+	"v1/Pod":                       {Version: "v1", Resource: "pods"},
+	"v1/Service":                   {Version: "v1", Resource: "services"},
+	"v1/PersistentVolumeClaim":     {Version: "v1", Resource: "persistentvolumeclaims"},
+	"apps/v1/ReplicaSet":           {Group: "apps", Version: "v1", Resource: "replicasets"},
+	"apps/v1/Deployment":           {Group: "apps", Version: "v1", Resource: "deployments"},
+	"apps/v1/StatefulSet":          {Group: "apps", Version: "v1", Resource: "statefulsets"},
+	"apps/v1/DaemonSet":            {Group: "apps", Version: "v1", Resource: "daemonsets"},
+	"batch/v1/Job":                 {Group: "batch", Version: "v1", Resource: "jobs"},
+	"batch/v1/CronJob":             {Group: "batch", Version: "v1", Resource: "cronjobs"},
+	"networking.k8s.io/v1/Ingress": {Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"},
+}
+
+// placementRef is the Placement an edge object belongs to. The zero value
+// means the object is not placement-managed.
+type placementRef struct {
+	namespace string
+	name      string
+	uid       string
+	workload  string
+}
+
+// EventForwarder watches the Kubernetes Events of the edge cluster and
+// mirrors those about placement-managed objects, and the Pods and
+// ReplicaSets under them, into the tenant workspace as Events on the owning
+// Placement, so hub users can see why a pod is crash-looping without
+// proxying to the edge. Forwarding can be restricted to a set of namespaces.
+type EventForwarder struct {
+	edgeName   string
+	hubDynamic dynamic.Interface
+	downstream dynamic.Interface
+	factories  []informers.SharedInformerFactory
+	listers    map[string]corelisters.EventLister
+	synced     []cache.InformerSynced
+	queue      workqueue.TypedRateLimitingInterface[string]
+
+	mu     sync.Mutex
+	owners map[types.UID]placementRef
+}
+
+// NewEventForwarder creates an EventForwarder for edgeName. hubDynamic is
+// scoped to the edge's tenant workspace; downstreamClient and
+// downstreamDynamic target the edge cluster. namespaces limits which edge
+// namespaces are forwarded; empty forwards all of them.
+func NewEventForwarder(edgeName string, hubDynamic dynamic.Interface, downstreamClient kubernetes.Interface, downstreamDynamic dynamic.Interface, namespaces []string) *EventForwarder {
+	f := &EventForwarder{
+		edgeName:   edgeName,
+		hubDynamic: hubDynamic,
+		downstream: downstreamDynamic,
+		listers:    map[string]corelisters.EventLister{},
+		owners:     map[types.UID]placementRef{},
+		queue: workqueue.NewTypedRateLimitingQueueWithConfig(
+			workqueue.DefaultTypedControllerRateLimiter[string](),
+			workqueue.TypedRateLimitingQueueConfig[string]{Name: eventForwarderName},
+		),
+	}
+
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+	for _, ns := range namespaces {
+		// No resync: an event changes whenever it recurs, which is when it
+		// needs forwarding again.
+		factory := informers.NewSharedInformerFactoryWithOptions(downstreamClient, 0, informers.WithNamespace(ns))
+		informer := factory.Core().V1().Events()
+		if _, err := informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    f.enqueue,
+			UpdateFunc: func(_, newObj interface{}) { f.enqueue(newObj) },
+		}); err != nil {
+			panic(fmt.Sprintf("failed to add event handler: %v", err))
+		}
+		f.listers[ns] = informer.Lister()
+		f.synced = append(f.synced, informer.Informer().HasSynced)
+		f.factories = append(f.factories, factory)
+	}
+	return f
+}
+
+func (f *EventForwarder) enqueue(obj interface{}) {
+	event, ok := obj.(*corev1.Event)
+	if !ok {
+		utilruntime.HandleError(fmt.Errorf("unexpected object type: %T", obj))
+		return
+	}
+	if _, ok := eventOwnerKinds[event.InvolvedObject.APIVersion+"/"+event.InvolvedObject.Kind]; !ok {
+		return
+	}
+	key, err := cache.MetaNamespaceKeyFunc(event)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	f.queue.Add(key)
+}
+
+// Run starts the informers and workers and blocks until ctx is cancelled.
+func (f *EventForwarder) Run(ctx context.Context, workers int) error {
+	defer utilruntime.HandleCrash()
+	defer f.queue.ShutDown()
+
+	logger := klog.FromContext(ctx).WithName(eventForwarderName)
+	logger.Info("Starting event forwarder")
+
+	for _, factory := range f.factories {
+		factory.Start(ctx.Done())
+	}
+	if !cache.WaitForCacheSync(ctx.Done(), f.synced...) {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, f.worker, time.Second)
+	}
+
+	<-ctx.Done()
+	logger.Info("Shutting down event forwarder")
+	return nil
+}
+
+func (f *EventForwarder) worker(ctx context.Context) {
+	for f.processNextWorkItem(ctx) {
+	}
+}
+
+func (f *EventForwarder) processNextWorkItem(ctx context.Context) bool {
+	key, quit := f.queue.Get()
+	if quit {
+		return false
+	}
+	defer f.queue.Done(key)
+
+	err := f.reconcile(ctx, key)
+	if err == nil {
+		f.queue.Forget(key)
+		return true
+	}
+	if f.queue.NumRequeues(key) < eventMaxRetries {
+		utilruntime.HandleError(fmt.Errorf("forwarding event %q: %w", key, err))
+		f.queue.AddRateLimited(key)
+		return true
+	}
+	klog.FromContext(ctx).V(2).Info("Dropping event after repeated forwarding failures", "event", key, "err", err)
+	f.queue.Forget(key)
+	return true
+}
+
+func (f *EventForwarder) reconcile(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return nil
+	}
+	event, err := f.lister(namespace).Get(name)
+	if err != nil {
+		// Expired or deleted on the edge; what was forwarded stays.
+		return nil
+	}
+
+	ref, err := f.placementFor(ctx, event.InvolvedObject)
+	if err != nil {
+		return err
+	}
+	if ref.name == "" {
+		return nil
+	}
+	return f.forward(ctx, event, ref)
+}
+
+// lister returns the lister covering namespace.
+func (f *EventForwarder) lister(namespace string) corelisters.EventNamespaceLister {
+	if l, ok := f.listers[namespace]; ok {
+		return l.Events(namespace)
+	}
+	return f.listers[metav1.NamespaceAll].Events(namespace)
+}
+
+// placementFor walks owner references up from obj until it reaches an object
+// stamped with a Placement. Lookups are remembered by UID.
+func (f *EventForwarder) placementFor(ctx context.Context, obj corev1.ObjectReference) (placementRef, error) {
+	namespace := obj.Namespace
+	apiVersion, kind, name, uid := obj.APIVersion, obj.Kind, obj.Name, obj.UID
+	var visited []types.UID
+	for depth := 0; depth < eventOwnerDepth; depth++ {
+		if ref, ok := f.cachedOwner(uid); ok {
+			f.rememberOwners(visited, ref)
+			return ref, nil
+		}
+		gvr, ok := eventOwnerKinds[apiVersion+"/"+kind]
+		if !ok {
+			break
+		}
+		u, err := f.downstream.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			// Gone already, like a crash-looping pod's predecessor: nothing to
+			// attribute it to, and nothing worth remembering.
+			return placementRef{}, nil
+		}
+		if err != nil {
+			return placementRef{}, fmt.Errorf("getting %s %s/%s: %w", kind, namespace, name, err)
+		}
+		visited = append(visited, u.GetUID())
+		if ref := placementRefFor(u); ref.name != "" {
+			f.rememberOwners(visited, ref)
+			return ref, nil
+		}
+		owner := metav1.GetControllerOfNoCopy(u)
+		if owner == nil {
+			break
+		}
+		apiVersion, kind, name, uid = owner.APIVersion, owner.Kind, owner.Name, owner.UID
+	}
+	f.rememberOwners(visited, placementRef{})
+	return placementRef{}, nil
+}
+
+// placementRefFor reads the Placement u was stamped with, if any.
+func placementRefFor(u *unstructured.Unstructured) placementRef {
+	name := u.GetLabels()[PlacementLabel]
+	if name == "" {
+		return placementRef{}
+	}
+	ns := u.GetAnnotations()[placementNamespaceAnnotation]
+	if ns == "" {
+		ns = "default"
+	}
+	return placementRef{
+		namespace: ns,
+		name:      name,
+		uid:       u.GetAnnotations()[placementUIDAnnotation],
+		workload:  u.GetLabels()[WorkloadLabel],
+	}
+}
+
+func (f *EventForwarder) cachedOwner(uid types.UID) (placementRef, bool) {
+	if uid == "" {
+		return placementRef{}, false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ref, ok := f.owners[uid]
+	return ref, ok
+}
+
+func (f *EventForwarder) rememberOwners(uids []types.UID, ref placementRef) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.owners)+len(uids) > eventOwnerCacheSize {
+		f.owners = map[types.UID]placementRef{}
+	}
+	for _, uid := range uids {
+		f.owners[uid] = ref
+	}
+}
+
+// forward creates or refreshes the hub Event mirroring event on the
+// Placement ref. The hub Event is named after the edge Event's UID, so a
+// recurrence updates it rather than adding another.
+func (f *EventForwarder) forward(ctx context.Context, event *corev1.Event, ref placementRef) error {
+	hubEvent := f.hubEvent(event, ref)
+	client := f.hubDynamic.Resource(eventGVR).Namespace(ref.namespace)
+	_, err := client.Create(ctx, hubEvent, metav1.CreateOptions{})
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("creating hub event: %w", err)
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"message":       hubEvent.Object["message"],
+		"count":         hubEvent.Object["count"],
+		"lastTimestamp": hubEvent.Object["lastTimestamp"],
+	})
+	if err != nil {
+		return fmt.Errorf("marshaling hub event patch: %w", err)
+	}
+	if _, err := client.Patch(ctx, hubEvent.GetName(), types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("updating hub event: %w", err)
+	}
+	return nil
+}
+
+// hubEvent builds the tenant-workspace Event mirroring event on ref.
+func (f *EventForwarder) hubEvent(event *corev1.Event, ref placementRef) *unstructured.Unstructured {
+	obj := event.InvolvedObject
+	first, last := eventTimes(event)
+	count := event.Count
+	if count == 0 && event.Series != nil {
+		count = event.Series.Count
+	}
+	labels := map[string]interface{}{EdgeLabel: f.edgeName}
+	if ref.workload != "" {
+		labels[WorkloadLabel] = ref.workload
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Event",
+		"metadata": map[string]interface{}{
+			"name":      ref.name + "." + string(event.UID),
+			"namespace": ref.namespace,
+			"labels":    labels,
+			"annotations": map[string]interface{}{
+				SourceObjectAnnotation: obj.Kind + "/" + obj.Namespace + "/" + obj.Name,
+			},
+		},
+		"involvedObject": map[string]interface{}{
+			"apiVersion": placementGVR.GroupVersion().String(),
+			"kind":       "Placement",
+			"namespace":  ref.namespace,
+			"name":       ref.name,
+			"uid":        ref.uid,
+		},
+		"type":           event.Type,
+		"reason":         event.Reason,
+		"message":        strings.ToLower(obj.Kind) + "/" + obj.Name + ": " + event.Message,
+		"source":         map[string]interface{}{"component": "kedge-agent", "host": f.edgeName},
+		"firstTimestamp": first.UTC().Format(time.RFC3339),
+		"lastTimestamp":  last.UTC().Format(time.RFC3339),
+		"count":          int64(max(count, 1)),
+	}}
+}
+
+// eventTimes returns when event first and last happened, for either event
+// API shape.
+func eventTimes(event *corev1.Event) (first, last time.Time) {
+	first, last = event.FirstTimestamp.Time, event.LastTimestamp.Time
+	if first.IsZero() {
+		first = event.EventTime.Time
+	}
+	if event.Series != nil && !event.Series.LastObservedTime.IsZero() {
+		last = event.Series.LastObservedTime.Time
+	}
+	if first.IsZero() {
+		first = event.CreationTimestamp.Time
+	}
+	if last.IsZero() {
+		last = first
+	}
+	return first, last
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+// edgeObject is an edge cluster object with a UID, optionally controlled by
+// owner.
+func edgeObject(apiVersion, kind, name, uid string, owner *unstructured.Unstructured) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"namespace": "apps", "name": name, "uid": uid},
+	}}
+	if owner != nil {
+		controller := true
+		u.SetOwnerReferences([]metav1.OwnerReference{{
+			APIVersion: owner.GetAPIVersion(), Kind: owner.GetKind(),
+			Name: owner.GetName(), UID: owner.GetUID(), Controller: &controller,
+		}})
+	}
+	return u
+}
+
+func edgeEvent(name string, obj *unstructured.Unstructured, count int32, last time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: name, UID: types.UID("uid-" + name)},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: obj.GetAPIVersion(), Kind: obj.GetKind(),
+			Namespace: obj.GetNamespace(), Name: obj.GetName(), UID: obj.GetUID(),
+		},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "BackOff",
+		Message:        "Back-off restarting failed container",
+		Count:          count,
+		FirstTimestamp: metav1.NewTime(last.Add(-time.Minute)),
+		LastTimestamp:  metav1.NewTime(last),
+	}
+}
+
+// newTestForwarder builds an EventForwarder over fake hub and edge clients,
+// with events and objects on the edge, and waits for its informers to sync.
+func newTestForwarder(t *testing.T, events []runtime.Object, objs ...runtime.Object) (*EventForwarder, *fake.FakeDynamicClient) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	hub := fake.NewSimpleDynamicClientWithCustomListKinds(scheme, map[schema.GroupVersionResource]string{
+		eventGVR: "EventList",
+	})
+	listKinds := map[schema.GroupVersionResource]string{}
+	for _, gvr := range eventOwnerKinds {
+		listKinds[gvr] = "List"
+	}
+	edge := fake.NewSimpleDynamicClientWithCustomListKinds(scheme, listKinds, objs...)
+
+	f := NewEventForwarder("store-7", hub, kubefake.NewClientset(events...), edge, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	for _, factory := range f.factories {
+		factory.Start(ctx.Done())
+	}
+	if !cache.WaitForCacheSync(ctx.Done(), f.synced...) {
+		t.Fatal("informers did not sync")
+	}
+	return f, hub
+}
+
+func TestEventForwarderForwardsPodEventToPlacement(t *testing.T) {
+	deploy := edgeObject("apps/v1", "Deployment", "web", "d1", nil)
+	deploy.SetLabels(map[string]string{PlacementLabel: "web-store-7", WorkloadLabel: "web"})
+	deploy.SetAnnotations(map[string]string{placementNamespaceAnnotation: "tenant", placementUIDAnnotation: "p1"})
+	rs := edgeObject("apps/v1", "ReplicaSet", "web-7d9f", "rs1", deploy)
+	pod := edgeObject("v1", "Pod", "web-7d9f-x2k", "pod1", rs)
+	now := time.Now().Truncate(time.Second)
+	event := edgeEvent("web-7d9f-x2k.1", pod, 3, now)
+
+	f, hub := newTestForwarder(t, []runtime.Object{event}, deploy, rs, pod)
+	if err := f.reconcile(context.Background(), "apps/web-7d9f-x2k.1"); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+
+	got, err := hub.Resource(eventGVR).Namespace("tenant").Get(context.Background(), "web-store-7.uid-web-7d9f-x2k.1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("getting forwarded event: %v", err)
+	}
+	for field, want := range map[string]string{
+		"involvedObject.kind": "Placement",
+		"involvedObject.name": "web-store-7",
+		"involvedObject.uid":  "p1",
+		"reason":              "BackOff",
+		"type":                "Warning",
+		"message":             "pod/web-7d9f-x2k: Back-off restarting failed container",
+		"lastTimestamp":       now.UTC().Format(time.RFC3339),
+	} {
+		if v, _, _ := unstructured.NestedString(got.Object, strings.Split(field, ".")...); v != want {
+			t.Errorf("%s = %q, want %q", field, v, want)
+		}
+	}
+	if l := got.GetLabels(); l[WorkloadLabel] != "web" || l[EdgeLabel] != "store-7" {
+		t.Errorf("labels = %v, want workload web and edge store-7", l)
+	}
+	if c, _, _ := unstructured.NestedInt64(got.Object, "count"); c != 3 {
+		t.Errorf("count = %d, want 3", c)
+	}
+
+	// A recurrence refreshes the forwarded event.
+	recurred := edgeEvent("web-7d9f-x2k.1", pod, 4, now.Add(time.Minute))
+	ref, _ := f.placementFor(context.Background(), recurred.InvolvedObject)
+	if err := f.forward(context.Background(), recurred, ref); err != nil {
+		t.Fatalf("forwarding recurrence: %v", err)
+	}
+	got, _ = hub.Resource(eventGVR).Namespace("tenant").Get(context.Background(), "web-store-7.uid-web-7d9f-x2k.1", metav1.GetOptions{})
+	if c, _, _ := unstructured.NestedInt64(got.Object, "count"); c != 4 {
+		t.Errorf("count after recurrence = %d, want 4", c)
+	}
+}
+
+func TestEventForwarderSkipsUnmanagedObjects(t *testing.T) {
+	pod := edgeObject("v1", "Pod", "sidecar", "pod2", nil)
+	event := edgeEvent("sidecar.1", pod, 1, time.Now())
+
+	f, hub := newTestForwarder(t, []runtime.Object{event}, pod)
+	if err := f.reconcile(context.Background(), "apps/sidecar.1"); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	list, err := hub.Resource(eventGVR).Namespace("").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 0 {
+		t.Errorf("forwarded %d events about an unmanaged pod, want none", len(list.Items))
+	}
+	if ref, ok := f.cachedOwner("pod2"); !ok || ref.name != "" {
+		t.Errorf("unmanaged pod lookup not remembered: %+v, %v", ref, ok)
+	}
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultDiskPressureThreshold is the filesystem usage, in percent, at
+	// which the edge's DiskPressure condition turns True by default.
+	DefaultDiskPressureThreshold = 90
+
+	// hostTelemetrySubsystem names host telemetry collection in the agent's
+	// health tracker.
+	hostTelemetrySubsystem = "host-telemetry"
+
+	// conditionDiskPressure is the agent-owned edge condition that is True
+	// while a watched filesystem is fuller than the threshold.
+	conditionDiskPressure   = "DiskPressure"
+	reasonFilesystemFull    = "FilesystemAlmostFull"
+	reasonFilesystemsNormal = "FilesystemsHaveSpace"
+)
+
+// hostTelemetry is a snapshot of a server's resource usage, reported as the
+// LinuxServer's status.hostTelemetry.
+type hostTelemetry struct {
+	cpus                   int
+	loadAverage            string
+	memTotal, memAvailable int64
+	filesystems            []filesystemUsage
+	temperatures           []temperatureReading
+}
+
+// filesystemUsage is the usage of the filesystem mounted at path, in bytes.
+type filesystemUsage struct {
+	path                  string
+	size, free, available int64
+}
+
+// usedPercent is the share of the space unprivileged users can use that is
+// used, rounded up, as df computes it: the blocks reserved for root count
+// as neither used nor available.
+func (f filesystemUsage) usedPercent() int64 {
+	used := f.size - f.free
+	usable := used + f.available
+	if usable <= 0 {
+		return 0
+	}
+	return (used*100 + usable - 1) / usable
+}
+
+// temperatureReading is one thermal zone's temperature.
+type temperatureReading struct {
+	sensor       string
+	milliCelsius int64
+}
+
+// patch renders the telemetry as the status.hostTelemetry patch.
+func (h *hostTelemetry) patch(now time.Time) map[string]interface{} {
+	out := map[string]interface{}{
+		"cpus":           int64(h.cpus),
+		"lastUpdateTime": metav1.NewTime(now),
+	}
+	if h.loadAverage != "" {
+		out["loadAverage"] = h.loadAverage
+	}
+	if h.memTotal > 0 {
+		out["memoryTotal"] = resource.NewQuantity(h.memTotal, resource.BinarySI).String()
+		out["memoryAvailable"] = resource.NewQuantity(h.memAvailable, resource.BinarySI).String()
+	}
+	// Always sent, so a filesystem or sensor that went away is dropped
+	// rather than kept by the merge patch.
+	filesystems := make([]interface{}, 0, len(h.filesystems))
+	for _, f := range h.filesystems {
+		filesystems = append(filesystems, map[string]interface{}{
+			"path":        f.path,
+			"size":        resource.NewQuantity(f.size, resource.BinarySI).String(),
+			"available":   resource.NewQuantity(f.available, resource.BinarySI).String(),
+			"usedPercent": f.usedPercent(),
+		})
+	}
+	out["filesystems"] = filesystems
+	temperatures := make([]interface{}, 0, len(h.temperatures))
+	for _, t := range h.temperatures {
+		temperatures = append(temperatures, map[string]interface{}{
+			"sensor":  t.sensor,
+			"celsius": t.milliCelsius / 1000,
+		})
+	}
+	out["temperatures"] = temperatures
+	return out
+}
+
+// diskPressureCondition reports whether any filesystem is at least
+// threshold percent full.
+func diskPressureCondition(filesystems []filesystemUsage, threshold int) metav1.Condition {
+	var full []string
+	for _, f := range filesystems {
+		if f.usedPercent() >= int64(threshold) {
+			full = append(full, fmt.Sprintf("%s is %d%% full (%s available)", f.path, f.usedPercent(),
+				resource.NewQuantity(f.available, resource.BinarySI).String()))
+		}
+	}
+	if len(full) == 0 {
+		return metav1.Condition{
+			Type:    conditionDiskPressure,
+			Status:  metav1.ConditionFalse,
+			Reason:  reasonFilesystemsNormal,
+			Message: fmt.Sprintf("Every watched filesystem is below %d%% full.", threshold),
+		}
+	}
+	return metav1.Condition{
+		Type:    conditionDiskPressure,
+		Status:  metav1.ConditionTrue,
+		Reason:  reasonFilesystemFull,
+		Message: fmt.Sprintf("%s; the threshold is %d%%.", strings.Join(full, ", "), threshold),
+	}
+}
+
+// parseLoadAverage returns the 1, 5 and 15 minute load averages of a
+// /proc/loadavg.
+func parseLoadAverage(data []byte) (string, error) {
+	fields := strings.Fields(string(data))
+	if len(fields) < 3 {
+		return "", fmt.Errorf("unexpected loadavg %q", strings.TrimSpace(string(data)))
+	}
+	return strings.Join(fields[:3], " "), nil
+}
+
+// parseMemInfo returns MemTotal and MemAvailable of a /proc/meminfo, in
+// bytes.
+func parseMemInfo(data []byte) (total, available int64, err error) {
+	found := 0
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok || (key != "MemTotal" && key != "MemAvailable") {
+			continue
+		}
+		kib, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimSpace(value), " kB"), 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("parsing meminfo %s: %w", key, err)
+		}
+		if key == "MemTotal" {
+			total = kib * 1024
+		} else {
+			available = kib * 1024
+		}
+		found++
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, 0, err
+	}
+	if found < 2 {
+		return 0, 0, fmt.Errorf("meminfo lacks MemTotal or MemAvailable")
+	}
+	return total, available, nil
+}
+
+// readTemperatures reads the thermal zones of a /sys/class/thermal tree.
+// Zones that cannot be read, or report no temperature, are skipped.
+func readTemperatures(thermal fs.FS) []temperatureReading {
+	zones, _ := fs.Glob(thermal, "thermal_zone*")
+	var readings []temperatureReading
+	for _, zone := range zones {
+		raw, err := fs.ReadFile(thermal, zone+"/temp")
+		if err != nil {
+			continue
+		}
+		milli, err := strconv.ParseInt(strings.TrimSpace(string(raw)), 10, 64)
+		if err != nil || milli <= 0 {
+			continue
+		}
+		sensor := zone
+		if name, err := fs.ReadFile(thermal, zone+"/type"); err == nil && len(bytes.TrimSpace(name)) > 0 {
+			sensor = string(bytes.TrimSpace(name))
+		}
+		readings = append(readings, temperatureReading{sensor: sensor, milliCelsius: milli})
+	}
+	sort.SliceStable(readings, func(i, j int) bool { return readings[i].sensor < readings[j].sensor })
+	return readings
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	"golang.org/x/sys/unix"
+)
+
+// collectHostTelemetry reads the load, memory, thermal zones and the usage
+// of the filesystems at paths from procfs, sysfs and statfs.
+func collectHostTelemetry(paths []string) (*hostTelemetry, error) {
+	h := &hostTelemetry{cpus: runtime.NumCPU()}
+
+	data, err := os.ReadFile("/proc/loadavg")
+	if err != nil {
+		return nil, fmt.Errorf("reading load average: %w", err)
+	}
+	if h.loadAverage, err = parseLoadAverage(data); err != nil {
+		return nil, err
+	}
+	if data, err = os.ReadFile("/proc/meminfo"); err != nil {
+		return nil, fmt.Errorf("reading memory usage: %w", err)
+	}
+	if h.memTotal, h.memAvailable, err = parseMemInfo(data); err != nil {
+		return nil, err
+	}
+
+	for _, path := range paths {
+		var st unix.Statfs_t
+		if err := unix.Statfs(path, &st); err != nil {
+			return nil, fmt.Errorf("reading usage of filesystem at %s: %w", path, err)
+		}
+		bsize := int64(st.Bsize)
+		h.filesystems = append(h.filesystems, filesystemUsage{
+			path:      path,
+			size:      int64(st.Blocks) * bsize,
+			free:      int64(st.Bfree) * bsize,
+			available: int64(st.Bavail) * bsize,
+		})
+	}
+
+	h.temperatures = readTemperatures(os.DirFS("/sys/class/thermal"))
+	return h, nil
+}
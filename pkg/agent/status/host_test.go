@@ -0,0 +1,105 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseHostFiles(t *testing.T) {
+	load, err := parseLoadAverage([]byte("0.52 0.41 0.30 2/512 12345\n"))
+	if err != nil || load != "0.52 0.41 0.30" {
+		t.Errorf("parseLoadAverage = %q, %v", load, err)
+	}
+	if _, err := parseLoadAverage([]byte("")); err == nil {
+		t.Error("empty loadavg parsed")
+	}
+
+	meminfo := "MemTotal:        8049012 kB\nMemFree:          512000 kB\nMemAvailable:    4024506 kB\n"
+	total, available, err := parseMemInfo([]byte(meminfo))
+	if err != nil || total != 8049012*1024 || available != 4024506*1024 {
+		t.Errorf("parseMemInfo = %d, %d, %v", total, available, err)
+	}
+	if _, _, err := parseMemInfo([]byte("MemTotal: 1 kB\n")); err == nil {
+		t.Error("meminfo without MemAvailable parsed")
+	}
+}
+
+func TestReadTemperatures(t *testing.T) {
+	thermal := fstest.MapFS{
+		"thermal_zone0/type": {Data: []byte("x86_pkg_temp\n")},
+		"thermal_zone0/temp": {Data: []byte("54000\n")},
+		"thermal_zone1/temp": {Data: []byte("41500\n")},
+		// A zone without a sensor reads an error or zero; it is skipped.
+		"thermal_zone2/type":   {Data: []byte("acpitz\n")},
+		"thermal_zone2/temp":   {Data: []byte("0\n")},
+		"cooling_device0/type": {Data: []byte("Processor\n")},
+	}
+	got := readTemperatures(thermal)
+	if len(got) != 2 || got[0].sensor != "thermal_zone1" || got[1].sensor != "x86_pkg_temp" || got[1].milliCelsius != 54000 {
+		t.Errorf("readTemperatures = %+v", got)
+	}
+}
+
+func TestHostTelemetryPatch(t *testing.T) {
+	h := &hostTelemetry{
+		cpus:         4,
+		loadAverage:  "0.52 0.41 0.30",
+		memTotal:     8 << 30,
+		memAvailable: 2 << 30,
+		filesystems:  []filesystemUsage{{path: "/", size: 100 << 30, free: 10 << 30, available: 5 << 30}},
+		temperatures: []temperatureReading{{sensor: "cpu-thermal", milliCelsius: 61999}},
+	}
+	p := h.patch(time.Unix(0, 0))
+	if p["memoryTotal"] != "8Gi" || p["memoryAvailable"] != "2Gi" || p["cpus"] != int64(4) {
+		t.Errorf("patch = %v", p)
+	}
+	fs := p["filesystems"].([]interface{})[0].(map[string]interface{})
+	// 90Gi used of the 95Gi usable: 94.7%, rounded up.
+	if fs["usedPercent"] != int64(95) || fs["available"] != "5Gi" {
+		t.Errorf("filesystem = %v", fs)
+	}
+	if temp := p["temperatures"].([]interface{})[0].(map[string]interface{}); temp["celsius"] != int64(61) {
+		t.Errorf("temperature = %v", temp)
+	}
+
+	// No filesystems or sensors still clears the lists.
+	empty := (&hostTelemetry{cpus: 1}).patch(time.Unix(0, 0))
+	if l, ok := empty["temperatures"].([]interface{}); !ok || len(l) != 0 {
+		t.Errorf("temperatures = %v", empty["temperatures"])
+	}
+}
+
+func TestDiskPressureCondition(t *testing.T) {
+	filesystems := []filesystemUsage{
+		{path: "/", size: 100, free: 50, available: 50},
+		{path: "/var/lib", size: 100, free: 5, available: 5},
+	}
+	cond := diskPressureCondition(filesystems, 90)
+	if cond.Status != metav1.ConditionTrue || cond.Reason != reasonFilesystemFull ||
+		!strings.Contains(cond.Message, "/var/lib is 95% full") || strings.Contains(cond.Message, "/ is") {
+		t.Errorf("condition = %+v", cond)
+	}
+	if cond := diskPressureCondition(filesystems[:1], 90); cond.Status != metav1.ConditionFalse {
+		t.Errorf("condition = %+v", cond)
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// InventoryInterval is how often the agent re-collects the cluster
+	// inventory. Node counts and capacity change rarely, so it is sent with
+	// a heartbeat every few minutes rather than with every one.
+	InventoryInterval = 5 * time.Minute
+
+	// inventorySubsystem names inventory collection in the agent's health
+	// tracker.
+	inventorySubsystem = "inventory"
+)
+
+// cniDaemonSets maps DaemonSet name prefixes to the CNI plugin they run.
+// Canal precedes calico and flannel, whose components it bundles.
+var cniDaemonSets = []struct{ prefix, cni string }{
+	{"cilium", "cilium"},
+	{"canal", "canal"},
+	{"calico-node", "calico"},
+	{"kube-flannel", "flannel"},
+	{"flannel", "flannel"},
+	{"weave-net", "weave"},
+	{"kindnet", "kindnet"},
+	{"kube-router", "kube-router"},
+	{"antrea-agent", "antrea"},
+	{"ovnkube-node", "ovn-kubernetes"},
+	{"aws-node", "aws-vpc-cni"},
+	{"azure-cni", "azure-cni"},
+}
+
+// versionProviders maps markers in the API server's version string to the
+// distribution that sets them, for clusters whose nodes carry no providerID.
+var versionProviders = []struct{ marker, provider string }{
+	{"+k3s", "k3s"},
+	{"+rke2", "rke2"},
+	{"-eks-", "eks"},
+	{"-gke.", "gke"},
+}
+
+// collectInventory reads the downstream cluster's nodes, version and
+// DaemonSets and summarizes them as the status.inventory patch.
+func collectInventory(ctx context.Context, downstream kubernetes.Interface, now time.Time) (map[string]interface{}, error) {
+	nodes, err := downstream.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("listing nodes: %w", err)
+	}
+	version, err := downstream.Discovery().ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("reading server version: %w", err)
+	}
+	daemonSets, err := downstream.AppsV1().DaemonSets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("listing daemonsets: %w", err)
+	}
+	return summarizeInventory(nodes.Items, version.GitVersion, daemonSets.Items, now), nil
+}
+
+// summarizeInventory renders nodes, the API server's git version and the
+// cluster's DaemonSets as the status.inventory patch.
+func summarizeInventory(nodes []corev1.Node, gitVersion string, daemonSets []appsv1.DaemonSet, now time.Time) map[string]interface{} {
+	var ready int64
+	cpu, memory := resource.Quantity{}, resource.Quantity{}
+	provider := ""
+	for _, node := range nodes {
+		for _, cond := range node.Status.Conditions {
+			if cond.Type == corev1.NodeReady && cond.Status == corev1.ConditionTrue {
+				ready++
+			}
+		}
+		if q, ok := node.Status.Capacity[corev1.ResourceCPU]; ok {
+			cpu.Add(q)
+		}
+		if q, ok := node.Status.Capacity[corev1.ResourceMemory]; ok {
+			memory.Add(q)
+		}
+		if provider == "" {
+			provider, _, _ = strings.Cut(node.Spec.ProviderID, "://")
+		}
+	}
+	if provider == "" {
+		for _, vp := range versionProviders {
+			if strings.Contains(gitVersion, vp.marker) {
+				provider = vp.provider
+				break
+			}
+		}
+	}
+
+	inventory := map[string]interface{}{
+		"nodes":          int64(len(nodes)),
+		"readyNodes":     ready,
+		"cpu":            cpu.String(),
+		"memory":         memory.String(),
+		"lastUpdateTime": metav1.NewTime(now),
+	}
+	if gitVersion != "" {
+		inventory["kubernetesVersion"] = gitVersion
+	}
+	if provider != "" {
+		inventory["provider"] = provider
+	}
+	if cni := detectCNI(daemonSets); cni != "" {
+		inventory["cni"] = cni
+	}
+	return inventory
+}
+
+// detectCNI names the network plugin whose DaemonSet runs in the cluster, or
+// returns "" when none is recognized.
+func detectCNI(daemonSets []appsv1.DaemonSet) string {
+	for _, known := range cniDaemonSets {
+		for _, ds := range daemonSets {
+			if strings.HasPrefix(ds.Name, known.prefix) {
+				return known.cni
+			}
+		}
+	}
+	return ""
+}
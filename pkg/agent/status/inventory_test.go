@@ -0,0 +1,114 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func inventoryNode(name, providerID, cpu, memory string, ready bool) *corev1.Node {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       corev1.NodeSpec{ProviderID: providerID},
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			},
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+		},
+	}
+}
+
+func TestCollectInventory(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		inventoryNode("a", "aws:///eu-west-1a/i-0abc", "4", "16Gi", true),
+		inventoryNode("b", "aws:///eu-west-1b/i-0def", "2500m", "8Gi", false),
+		&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "kube-proxy", Namespace: "kube-system"}},
+		&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "cilium", Namespace: "kube-system"}},
+	)
+	client.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.31.2-eks-7f9249a"}
+
+	inv, err := collectInventory(context.Background(), client, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"nodes":             int64(2),
+		"readyNodes":        int64(1),
+		"cpu":               "6500m",
+		"memory":            "24Gi",
+		"kubernetesVersion": "v1.31.2-eks-7f9249a",
+		"provider":          "aws",
+		"cni":               "cilium",
+	}
+	for k, v := range want {
+		if inv[k] != v {
+			t.Errorf("inventory[%q] = %v, want %v", k, inv[k], v)
+		}
+	}
+}
+
+func TestSummarizeInventoryProviderFromVersion(t *testing.T) {
+	nodes := []corev1.Node{*inventoryNode("a", "", "2", "4Gi", true)}
+	inv := summarizeInventory(nodes, "v1.30.4+k3s1", nil, time.Now())
+	if inv["provider"] != "k3s" {
+		t.Errorf("provider = %v, want k3s", inv["provider"])
+	}
+	if _, ok := inv["cni"]; ok {
+		t.Errorf("cni = %v, want it unset with no known DaemonSet", inv["cni"])
+	}
+}
+
+func TestDetectCNI(t *testing.T) {
+	ds := func(names ...string) []appsv1.DaemonSet {
+		var out []appsv1.DaemonSet
+		for _, n := range names {
+			out = append(out, appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: n}})
+		}
+		return out
+	}
+	tests := []struct {
+		daemonSets []appsv1.DaemonSet
+		want       string
+	}{
+		{ds("kube-proxy", "calico-node"), "calico"},
+		{ds("canal", "calico-node"), "canal"},
+		{ds("kube-flannel-ds"), "flannel"},
+		{ds("kindnet"), "kindnet"},
+		{ds("kube-proxy"), ""},
+	}
+	for _, tt := range tests {
+		if got := detectCNI(tt.daemonSets); got != tt.want {
+			t.Errorf("detectCNI(%v) = %q, want %q", tt.daemonSets, got, tt.want)
+		}
+	}
+}
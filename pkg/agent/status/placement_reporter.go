@@ -34,6 +34,8 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
+
+	"github.com/faroshq/faros-kedge/pkg/agent/health"
 )
 
 const (
@@ -57,6 +59,7 @@ type PlacementReporter struct {
 	deploymentLister appslisters.DeploymentLister
 	deploymentSynced cache.InformerSynced
 	queue            workqueue.TypedRateLimitingInterface[string]
+	health           *health.Tracker
 }
 
 // NewPlacementReporter creates a PlacementReporter. hubDynamic is scoped to the
@@ -116,6 +119,12 @@ func (r *PlacementReporter) enqueueDeployment(obj interface{}) {
 	r.queue.Add(key)
 }
 
+// SetHealth has the reporter record each Deployment's status-patch failures
+// in tracker, under "placement-status/<namespace>/<name>". Call before Run.
+func (r *PlacementReporter) SetHealth(tracker *health.Tracker) {
+	r.health = tracker
+}
+
 // Run starts the placement status reporter.
 func (r *PlacementReporter) Run(ctx context.Context, workers int) error {
 	defer utilruntime.HandleCrash()
@@ -149,7 +158,9 @@ func (r *PlacementReporter) processNextWorkItem(ctx context.Context) bool {
 	}
 	defer r.queue.Done(key)
 
-	if err := r.reconcile(ctx, key); err != nil {
+	err := r.reconcile(ctx, key)
+	r.health.Observe("placement-status/"+key, err)
+	if err != nil {
 		utilruntime.HandleError(fmt.Errorf("reconciling %q: %w", key, err))
 		r.queue.AddRateLimited(key)
 		return true
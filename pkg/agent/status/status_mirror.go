@@ -0,0 +1,256 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"github.com/faroshq/faros-kedge/pkg/agent/health"
+)
+
+const statusMirrorName = "placement-status-mirror"
+
+// mirroredResources are the kinds whose status subtree is copied back into the
+// owning Placement's status.resources. They carry the replica / completion
+// counts hub users care about; everything else a bundle applies (Services,
+// ConfigMaps, ...) has no meaningful status to mirror.
+var mirroredResources = []schema.GroupVersionResource{
+	{Group: "apps", Version: "v1", Resource: "deployments"},
+	{Group: "apps", Version: "v1", Resource: "statefulsets"},
+	{Group: "batch", Version: "v1", Resource: "jobs"},
+}
+
+// StatusMirror watches the placement-labeled Deployments, StatefulSets and Jobs
+// on the edge and copies their .status into the owning Placement's
+// status.resources, so hub users see readiness without proxying to the edge.
+// Mirroring can be restricted to a set of namespaces; objects elsewhere are
+// neither watched nor reported.
+type StatusMirror struct {
+	hubDynamic dynamic.Interface
+	factories  []dynamicinformer.DynamicSharedInformerFactory
+	listers    []cache.GenericLister
+	synced     []cache.InformerSynced
+	queue      workqueue.TypedRateLimitingInterface[string]
+	health     *health.Tracker
+}
+
+// NewStatusMirror creates a StatusMirror. hubDynamic is scoped to the edge's
+// tenant workspace; downstreamDynamic targets the edge cluster. namespaces
+// limits which edge namespaces are mirrored; empty mirrors all of them.
+func NewStatusMirror(hubDynamic, downstreamDynamic dynamic.Interface, namespaces []string) *StatusMirror {
+	m := &StatusMirror{
+		hubDynamic: hubDynamic,
+		queue: workqueue.NewTypedRateLimitingQueueWithConfig(
+			workqueue.DefaultTypedControllerRateLimiter[string](),
+			workqueue.TypedRateLimitingQueueConfig[string]{Name: statusMirrorName},
+		),
+	}
+
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+	for _, ns := range namespaces {
+		factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(
+			downstreamDynamic, 10*time.Minute, ns,
+			func(opts *metav1.ListOptions) { opts.LabelSelector = PlacementLabel },
+		)
+		for _, gvr := range mirroredResources {
+			informer := factory.ForResource(gvr)
+			if _, err := informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+				AddFunc:    m.enqueue,
+				UpdateFunc: func(_, newObj interface{}) { m.enqueue(newObj) },
+				DeleteFunc: m.enqueue,
+			}); err != nil {
+				panic(fmt.Sprintf("failed to add %s event handler: %v", gvr.Resource, err))
+			}
+			m.listers = append(m.listers, informer.Lister())
+			m.synced = append(m.synced, informer.Informer().HasSynced)
+		}
+		m.factories = append(m.factories, factory)
+	}
+	return m
+}
+
+// enqueue queues the owning Placement ("namespace/name") of a mirrored object.
+func (m *StatusMirror) enqueue(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		utilruntime.HandleError(fmt.Errorf("unexpected object type: %T", obj))
+		return
+	}
+	if key := placementKeyFor(u); key != "" {
+		m.queue.Add(key)
+	}
+}
+
+// placementKeyFor returns the "namespace/name" key of the Placement that owns
+// u, or "" if u is not placement-managed.
+func placementKeyFor(u *unstructured.Unstructured) string {
+	name := u.GetLabels()[PlacementLabel]
+	if name == "" {
+		return ""
+	}
+	ns := u.GetAnnotations()[placementNamespaceAnnotation]
+	if ns == "" {
+		ns = "default"
+	}
+	return ns + "/" + name
+}
+
+// SetHealth has the mirror record each placement's mirroring failures in
+// tracker, under "status-mirror/<namespace>/<name>". Call before Run.
+func (m *StatusMirror) SetHealth(tracker *health.Tracker) {
+	m.health = tracker
+}
+
+// Run starts the informers and workers and blocks until ctx is cancelled.
+func (m *StatusMirror) Run(ctx context.Context, workers int) error {
+	defer utilruntime.HandleCrash()
+	defer m.queue.ShutDown()
+
+	logger := klog.FromContext(ctx).WithName(statusMirrorName)
+	logger.Info("Starting placement status mirror")
+
+	for _, f := range m.factories {
+		f.Start(ctx.Done())
+	}
+	if !cache.WaitForCacheSync(ctx.Done(), m.synced...) {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, m.worker, time.Second)
+	}
+
+	<-ctx.Done()
+	logger.Info("Shutting down placement status mirror")
+	return nil
+}
+
+func (m *StatusMirror) worker(ctx context.Context) {
+	for m.processNextWorkItem(ctx) {
+	}
+}
+
+func (m *StatusMirror) processNextWorkItem(ctx context.Context) bool {
+	key, quit := m.queue.Get()
+	if quit {
+		return false
+	}
+	defer m.queue.Done(key)
+
+	err := m.reconcile(ctx, key)
+	m.health.Observe("status-mirror/"+key, err)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("mirroring status for placement %q: %w", key, err))
+		m.queue.AddRateLimited(key)
+		return true
+	}
+	m.queue.Forget(key)
+	return true
+}
+
+func (m *StatusMirror) reconcile(ctx context.Context, key string) error {
+	placementNamespace, placementName, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return nil
+	}
+
+	resources, err := m.collect(key, placementName)
+	if err != nil {
+		return err
+	}
+
+	// A merge patch replaces the whole list, so objects that disappeared from
+	// the edge drop out of status.resources on the next reconcile.
+	patchBytes, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{"resources": resources},
+	})
+	if err != nil {
+		return fmt.Errorf("marshaling placement status patch: %w", err)
+	}
+	if _, err := m.hubDynamic.Resource(placementGVR).Namespace(placementNamespace).Patch(
+		ctx, placementName, types.MergePatchType, patchBytes, metav1.PatchOptions{}, "status",
+	); err != nil {
+		// The Placement was deleted; its objects go away with it and there is
+		// nothing left to report to.
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("updating placement status: %w", err)
+	}
+
+	klog.FromContext(ctx).V(4).Info("Mirrored placement resource status",
+		"placement", key, "resources", len(resources))
+	return nil
+}
+
+// collect returns the mirrored status entries for every watched object owned by
+// the placement identified by key, sorted for a stable patch.
+func (m *StatusMirror) collect(key, placementName string) ([]map[string]interface{}, error) {
+	selector := labels.SelectorFromSet(labels.Set{PlacementLabel: placementName})
+	resources := []map[string]interface{}{}
+	for _, lister := range m.listers {
+		objs, err := lister.List(selector)
+		if err != nil {
+			return nil, fmt.Errorf("listing mirrored objects: %w", err)
+		}
+		for _, obj := range objs {
+			u, ok := obj.(*unstructured.Unstructured)
+			if !ok || placementKeyFor(u) != key {
+				continue
+			}
+			entry := map[string]interface{}{
+				"apiVersion": u.GetAPIVersion(),
+				"kind":       u.GetKind(),
+				"namespace":  u.GetNamespace(),
+				"name":       u.GetName(),
+			}
+			if st, found, _ := unstructured.NestedMap(u.Object, "status"); found {
+				entry["status"] = st
+			}
+			resources = append(resources, entry)
+		}
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		a, b := resources[i], resources[j]
+		return fmt.Sprint(a["kind"], "/", a["namespace"], "/", a["name"]) <
+			fmt.Sprint(b["kind"], "/", b["namespace"], "/", b["name"])
+	})
+	return resources, nil
+}
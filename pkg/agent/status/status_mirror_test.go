@@ -0,0 +1,140 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newMirroredObject(apiVersion, kind, namespace, name, placement string, status map[string]interface{}) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"namespace":   namespace,
+			"name":        name,
+			"labels":      map[string]interface{}{PlacementLabel: placement},
+			"annotations": map[string]interface{}{placementNamespaceAnnotation: "tenant"},
+		},
+	}}
+	if status != nil {
+		u.Object["status"] = status
+	}
+	return u
+}
+
+func newPlacement(namespace, name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": placementGVR.GroupVersion().String(),
+		"kind":       "Placement",
+		"metadata":   map[string]interface{}{"namespace": namespace, "name": name},
+	}}
+}
+
+// newTestMirror builds a StatusMirror over fake hub and edge clients and waits
+// for its informers to sync.
+func newTestMirror(t *testing.T, hubObjs []runtime.Object, edgeObjs ...runtime.Object) (*StatusMirror, *fake.FakeDynamicClient) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	hub := fake.NewSimpleDynamicClientWithCustomListKinds(scheme, map[schema.GroupVersionResource]string{
+		placementGVR: "PlacementList",
+	}, hubObjs...)
+	listKinds := map[schema.GroupVersionResource]string{}
+	for _, gvr := range mirroredResources {
+		listKinds[gvr] = "List"
+	}
+	edge := fake.NewSimpleDynamicClientWithCustomListKinds(scheme, listKinds, edgeObjs...)
+
+	m := NewStatusMirror(hub, edge, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	for _, f := range m.factories {
+		f.Start(ctx.Done())
+	}
+	if !cache.WaitForCacheSync(ctx.Done(), m.synced...) {
+		t.Fatal("informers did not sync")
+	}
+	return m, hub
+}
+
+func TestStatusMirrorCollect(t *testing.T) {
+	m, _ := newTestMirror(t, nil,
+		newMirroredObject("apps/v1", "StatefulSet", "apps", "db", "web", map[string]interface{}{"readyReplicas": int64(1)}),
+		newMirroredObject("apps/v1", "Deployment", "apps", "web", "web", map[string]interface{}{"readyReplicas": int64(2)}),
+		newMirroredObject("batch/v1", "Job", "apps", "migrate", "web", nil),
+		newMirroredObject("apps/v1", "Deployment", "apps", "other", "other", nil),
+	)
+
+	got, err := m.collect("tenant/web", "web")
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	want := []string{"Deployment/web", "Job/migrate", "StatefulSet/db"}
+	if len(got) != len(want) {
+		t.Fatalf("collect returned %d entries, want %d: %v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if k := got[i]["kind"].(string) + "/" + got[i]["name"].(string); k != w {
+			t.Errorf("entry %d = %s, want %s", i, k, w)
+		}
+	}
+	if st, ok := got[0]["status"].(map[string]interface{}); !ok || st["readyReplicas"] != int64(2) {
+		t.Errorf("Deployment status = %v, want readyReplicas 2", got[0]["status"])
+	}
+	if _, ok := got[1]["status"]; ok {
+		t.Errorf("Job without status should carry none, got %v", got[1]["status"])
+	}
+}
+
+func TestStatusMirrorReconcilePatchesPlacement(t *testing.T) {
+	m, hub := newTestMirror(t, []runtime.Object{newPlacement("tenant", "web")},
+		newMirroredObject("apps/v1", "Deployment", "apps", "web", "web", map[string]interface{}{"readyReplicas": int64(2)}),
+	)
+
+	if err := m.reconcile(context.Background(), "tenant/web"); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	p, err := hub.Resource(placementGVR).Namespace("tenant").Get(context.Background(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("getting placement: %v", err)
+	}
+	resources, found, _ := unstructured.NestedSlice(p.Object, "status", "resources")
+	if !found || len(resources) != 1 {
+		t.Fatalf("status.resources = %v, want one entry", resources)
+	}
+	if name := resources[0].(map[string]interface{})["name"]; name != "web" {
+		t.Errorf("mirrored name = %v, want web", name)
+	}
+}
+
+func TestStatusMirrorReconcileDeletedPlacement(t *testing.T) {
+	m, _ := newTestMirror(t, nil,
+		newMirroredObject("apps/v1", "Deployment", "apps", "web", "web", nil),
+	)
+
+	if err := m.reconcile(context.Background(), "tenant/web"); err != nil {
+		t.Errorf("reconcile for a deleted placement = %v, want nil so the key is not retried", err)
+	}
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+
+	"github.com/faroshq/faros-kedge/pkg/agent/metrics"
+)
+
+// minBandwidthBurst is the smallest burst of a bandwidth limiter, so a low
+// limit still moves data in reasonably sized chunks.
+const minBandwidthBurst = 16 * 1024
+
+// ByteRate is a rate in bytes per second, set from a quantity such as
+// "512Ki" or "2M". Zero means unlimited.
+type ByteRate int64
+
+// String formats the rate as a quantity.
+func (r ByteRate) String() string {
+	return resource.NewQuantity(int64(r), resource.BinarySI).String()
+}
+
+// Set parses a quantity such as "512Ki" or "2M".
+func (r *ByteRate) Set(s string) error {
+	q, err := resource.ParseQuantity(s)
+	if err != nil {
+		return fmt.Errorf("invalid bandwidth %q: %w", s, err)
+	}
+	if q.Sign() < 0 {
+		return fmt.Errorf("invalid bandwidth %q: must not be negative", s)
+	}
+	*r = ByteRate(q.Value())
+	return nil
+}
+
+// Type names the flag value type in help output.
+func (r *ByteRate) Type() string {
+	return "bytesPerSecond"
+}
+
+// Bandwidth caps the traffic the agent proxies over its tunnel, across all
+// sessions. Upload is traffic to the hub, Download traffic from it.
+type Bandwidth struct {
+	// Max caps both directions unless Upload or Download is set.
+	Max ByteRate
+	// Upload caps the bytes per second sent to the hub.
+	Upload ByteRate
+	// Download caps the bytes per second received from the hub.
+	Download ByteRate
+}
+
+// Validate reports negative rates.
+func (b Bandwidth) Validate() error {
+	if b.Max < 0 || b.Upload < 0 || b.Download < 0 {
+		return fmt.Errorf("tunnel bandwidth limits must not be negative: %+v", b)
+	}
+	return nil
+}
+
+// limits returns the effective upload and download limits.
+func (b Bandwidth) limits() (upload, download ByteRate) {
+	upload, download = b.Upload, b.Download
+	if upload == 0 {
+		upload = b.Max
+	}
+	if download == 0 {
+		download = b.Max
+	}
+	return upload, download
+}
+
+// TrafficMeter limits and accounts for the traffic of the sessions the hub
+// opens over an edge's tunnel. One meter outlives reconnects, so its totals
+// cover the agent's lifetime.
+type TrafficMeter struct {
+	edgeName         string
+	upload, download *rate.Limiter // nil when unlimited
+	uploadLimit      ByteRate
+	downloadLimit    ByteRate
+	sent, received   atomic.Int64
+	sessions         atomic.Int64
+	sentCounter      prometheus.Counter
+	receivedCounter  prometheus.Counter
+	sessionSentBytes prometheus.Observer
+	sessionRecvBytes prometheus.Observer
+	sessionsCounter  prometheus.Counter
+}
+
+// NewTrafficMeter returns a meter applying bw to the tunnel of edgeName.
+func NewTrafficMeter(edgeName string, bw Bandwidth) *TrafficMeter {
+	up, down := bw.limits()
+	return &TrafficMeter{
+		edgeName:         edgeName,
+		upload:           newBandwidthLimiter(up),
+		download:         newBandwidthLimiter(down),
+		uploadLimit:      up,
+		downloadLimit:    down,
+		sentCounter:      tunnelBytes.WithLabelValues(edgeName, "upload"),
+		receivedCounter:  tunnelBytes.WithLabelValues(edgeName, "download"),
+		sessionSentBytes: tunnelSessionBytes.WithLabelValues(edgeName, "upload"),
+		sessionRecvBytes: tunnelSessionBytes.WithLabelValues(edgeName, "download"),
+		sessionsCounter:  tunnelSessions.WithLabelValues(edgeName),
+	}
+}
+
+func newBandwidthLimiter(r ByteRate) *rate.Limiter {
+	if r <= 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(r), max(int(r), minBandwidthBurst))
+}
+
+// Totals returns the bytes sent to and received from the hub and the number
+// of sessions since the meter was created.
+func (m *TrafficMeter) Totals() (sent, received, sessions int64) {
+	return m.sent.Load(), m.received.Load(), m.sessions.Load()
+}
+
+// Limits returns the upload and download limits in bytes per second, zero
+// when unlimited.
+func (m *TrafficMeter) Limits() (upload, download int64) {
+	return int64(m.uploadLimit), int64(m.downloadLimit)
+}
+
+// listener meters every session accepted from ln. A nil meter returns ln.
+func (m *TrafficMeter) listener(ln net.Listener) net.Listener {
+	if m == nil {
+		return ln
+	}
+	return &meteredListener{Listener: ln, meter: m}
+}
+
+type meteredListener struct {
+	net.Listener
+	meter *TrafficMeter
+}
+
+func (l *meteredListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	l.meter.sessions.Add(1)
+	l.meter.sessionsCounter.Inc()
+	ctx, cancel := context.WithCancel(context.Background())
+	return &meteredConn{Conn: conn, meter: l.meter, ctx: ctx, cancel: cancel, opened: time.Now()}, nil
+}
+
+// meteredConn is one proxied session. Waits on the limiters end when the
+// session is closed.
+type meteredConn struct {
+	net.Conn
+	meter          *TrafficMeter
+	ctx            context.Context
+	cancel         context.CancelFunc
+	opened         time.Time
+	sent, received atomic.Int64
+	closeOnce      sync.Once
+}
+
+func (c *meteredConn) Read(p []byte) (int, error) {
+	if lim := c.meter.download; lim != nil && len(p) > lim.Burst() {
+		p = p[:lim.Burst()]
+	}
+	n, err := c.Conn.Read(p)
+	if n > 0 {
+		c.received.Add(int64(n))
+		c.meter.received.Add(int64(n))
+		c.meter.receivedCounter.Add(float64(n))
+		if lim := c.meter.download; lim != nil {
+			if werr := lim.WaitN(c.ctx, n); werr != nil && err == nil {
+				err = net.ErrClosed
+			}
+		}
+	}
+	return n, err
+}
+
+func (c *meteredConn) Write(p []byte) (int, error) {
+	lim := c.meter.upload
+	written := 0
+	for len(p) > 0 {
+		chunk := p
+		if lim != nil {
+			if len(chunk) > lim.Burst() {
+				chunk = chunk[:lim.Burst()]
+			}
+			if err := lim.WaitN(c.ctx, len(chunk)); err != nil {
+				return written, net.ErrClosed
+			}
+		}
+		n, err := c.Conn.Write(chunk)
+		written += n
+		c.sent.Add(int64(n))
+		c.meter.sent.Add(int64(n))
+		c.meter.sentCounter.Add(float64(n))
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+func (c *meteredConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.cancel()
+		sent, received := c.sent.Load(), c.received.Load()
+		c.meter.sessionSentBytes.Observe(float64(sent))
+		c.meter.sessionRecvBytes.Observe(float64(received))
+		klog.V(2).InfoS("Tunnel session closed", "edge", c.meter.edgeName,
+			"bytesSent", sent, "bytesReceived", received, "duration", time.Since(c.opened).Round(time.Millisecond))
+	})
+	return c.Conn.Close()
+}
+
+var (
+	// tunnelBytes counts the bytes proxied over the edge's tunnel, by
+	// direction ("upload" to the hub, "download" from it).
+	tunnelBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kedge_agent",
+		Name:      "tunnel_bytes_total",
+		Help:      "Bytes proxied over the tunnel to the hub, by direction.",
+	}, []string{"edge", "direction"})
+	// tunnelSessionBytes is the size of the sessions proxied over the
+	// edge's tunnel, by direction, observed when a session closes.
+	tunnelSessionBytes = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "kedge_agent",
+		Name:      "tunnel_session_bytes",
+		Help:      "Bytes proxied per tunnel session, by direction.",
+		Buckets:   prometheus.ExponentialBuckets(1024, 4, 10),
+	}, []string{"edge", "direction"})
+	// tunnelSessions counts the sessions the hub opened over the tunnel.
+	tunnelSessions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kedge_agent",
+		Name:      "tunnel_sessions_total",
+		Help:      "Sessions the hub opened over the tunnel.",
+	}, []string{"edge"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(tunnelBytes, tunnelSessionBytes, tunnelSessions)
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestByteRateSet(t *testing.T) {
+	for in, want := range map[string]ByteRate{"0": 0, "512Ki": 512 * 1024, "2M": 2000000, "1Mi": 1 << 20} {
+		var r ByteRate
+		if err := r.Set(in); err != nil || r != want {
+			t.Errorf("Set(%q) = %d, %v; want %d", in, r, err, want)
+		}
+	}
+	for _, in := range []string{"-1Ki", "fast"} {
+		var r ByteRate
+		if err := r.Set(in); err == nil {
+			t.Errorf("Set(%q) succeeded", in)
+		}
+	}
+}
+
+func TestBandwidthLimits(t *testing.T) {
+	up, down := Bandwidth{Max: 100, Download: 50}.limits()
+	if up != 100 || down != 50 {
+		t.Errorf("limits = %d, %d; want 100, 50", up, down)
+	}
+}
+
+// pipeListener accepts the server end of a single pipe.
+type pipeListener struct {
+	conns chan net.Conn
+}
+
+func (l *pipeListener) Accept() (net.Conn, error) {
+	c, ok := <-l.conns
+	if !ok {
+		return nil, net.ErrClosed
+	}
+	return c, nil
+}
+
+func (l *pipeListener) Close() error   { return nil }
+func (l *pipeListener) Addr() net.Addr { return &net.TCPAddr{} }
+
+// TestTrafficMeter checks that a session's uploads are held to the limit
+// and that both directions are counted.
+func TestTrafficMeter(t *testing.T) {
+	const limit = 64 * 1024
+	meter := NewTrafficMeter("test-meter", Bandwidth{Upload: limit})
+	server, client := net.Pipe()
+	inner := &pipeListener{conns: make(chan net.Conn, 1)}
+	inner.conns <- server
+	conn, err := meter.listener(inner).Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		_, _ = client.Write([]byte("ping"))
+		_, _ = io.Copy(io.Discard, client)
+	}()
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// The limiter starts with a full burst of one second's worth, so sending
+	// two seconds' worth takes about one second.
+	start := time.Now()
+	if _, err := conn.Write(make([]byte, 2*limit)); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 800*time.Millisecond {
+		t.Errorf("sent %d bytes in %s at %d bytes/s", 2*limit, elapsed, limit)
+	}
+	_ = conn.Close()
+
+	sent, received, sessions := meter.Totals()
+	if sent != 2*limit || received != 4 || sessions != 1 {
+		t.Errorf("totals = sent %d, received %d, sessions %d", sent, received, sessions)
+	}
+	if up, down := meter.Limits(); up != limit || down != 0 {
+		t.Errorf("limits = %d, %d", up, down)
+	}
+}
+
+// TestTrafficMeterCloseUnblocks checks that closing a session ends a write
+// waiting on the limiter.
+func TestTrafficMeterCloseUnblocks(t *testing.T) {
+	meter := NewTrafficMeter("test-meter-close", Bandwidth{Max: minBandwidthBurst})
+	server, client := net.Pipe()
+	go func() { _, _ = io.Copy(io.Discard, client) }()
+	inner := &pipeListener{conns: make(chan net.Conn, 1)}
+	inner.conns <- server
+	conn, err := meter.listener(inner).Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := conn.Write(make([]byte, 100*minBandwidthBurst))
+		done <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+	_ = conn.Close()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("write succeeded after close")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("write still blocked after close")
+	}
+}
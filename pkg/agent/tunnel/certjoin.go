@@ -0,0 +1,364 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/faroshq/faros-kedge/pkg/apiurl"
+)
+
+// Certificate join. Instead of presenting its join token or ServiceAccount
+// token on every tunnel connect, the agent generates an ECDSA key, has the
+// hub's agent CA sign a client certificate for it through the edge's
+// certificate subresource, and authenticates tunnels with short-lived
+// assertions signed by that key. The token is only used for the first
+// request; renewals authenticate with the current certificate.
+const (
+	// certTokenPrefix starts a certificate assertion; the hub's format is
+	// prefix + certificate + "." + claims + "." + signature, base64url.
+	certTokenPrefix = "kedge-cert."
+	// certAssertionLifetime is how long an assertion is valid for.
+	certAssertionLifetime = 5 * time.Minute
+	// certRetryInterval is how often a pending or failed certificate request
+	// is retried.
+	certRetryInterval = 30 * time.Second
+
+	certKeyFile     = "agent-cert.key"
+	certFile        = "agent-cert.crt"
+	certPendingFile = "agent-cert.key.pending"
+)
+
+// errCertificateJoinDisabled is returned when the hub has no agent CA.
+var errCertificateJoinDisabled = errors.New("the hub does not sign agent certificates (no agent CA configured)")
+
+// errCertificatePending is returned while a certificate request awaits
+// approval on the hub.
+type errCertificatePending struct{ message string }
+
+func (e *errCertificatePending) Error() string { return e.message }
+
+// CertificateJoin holds the agent's certificate and key, requests and
+// renews them, and turns them into tunnel credentials.
+type CertificateJoin struct {
+	hubURL       string
+	resourceType string
+	cluster      string
+	edgeName     string
+	dir          string
+	client       *http.Client
+
+	mu   sync.Mutex
+	key  *ecdsa.PrivateKey
+	cert *x509.Certificate
+}
+
+// NewCertificateJoin loads the certificate and key the agent stored in dir,
+// if any. hubURL, resourceType and cluster locate the edge as for
+// StartProxyTunnel, and proxy is its proxy selection (nil connects directly).
+func NewCertificateJoin(hubURL, resourceType, cluster, edgeName, dir string, tlsConfig *tls.Config, proxy func(*http.Request) (*url.URL, error)) (*CertificateJoin, error) {
+	c := &CertificateJoin{
+		hubURL:       hubURL,
+		resourceType: resourceType,
+		cluster:      cluster,
+		edgeName:     edgeName,
+		dir:          dir,
+		client: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &http.Transport{Proxy: proxy, TLSClientConfig: tlsConfig},
+		},
+	}
+	key, err := readECKey(filepath.Join(dir, certKeyFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, certFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cert, err := parseCertificatePEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Join(dir, certFile), err)
+	}
+	// A crash between storing a new certificate and its key leaves them
+	// unmatched; request a new one.
+	if pub, ok := cert.PublicKey.(*ecdsa.PublicKey); ok && pub.Equal(key.Public()) {
+		c.key, c.cert = key, cert
+	}
+	return c, nil
+}
+
+// Token wraps a tunnel token source: it returns an assertion while the agent
+// holds a valid certificate and fallback's token otherwise.
+func (c *CertificateJoin) Token(fallback func() string) func() string {
+	return func() string {
+		if token, err := c.assertion(time.Now()); err == nil && token != "" {
+			return token
+		}
+		if fallback == nil {
+			return ""
+		}
+		return fallback()
+	}
+}
+
+// Ensure requests a certificate, authenticating with fallback's token, when
+// the agent holds none or it is due for renewal.
+func (c *CertificateJoin) Ensure(ctx context.Context, fallback func() string) error {
+	if c.renewIn(time.Now()) > 0 {
+		return nil
+	}
+	return c.request(ctx, c.Token(fallback)())
+}
+
+// Run obtains a certificate, authenticating with fallback's token, and
+// renews it once two thirds of its lifetime have passed, until ctx is done
+// or the hub turns out not to sign agent certificates.
+func (c *CertificateJoin) Run(ctx context.Context, fallback func() string) {
+	logger := klog.FromContext(ctx).WithValues("edgeName", c.edgeName)
+	for {
+		wait := c.renewIn(time.Now())
+		if wait <= 0 {
+			err := c.Ensure(ctx, fallback)
+			var pending *errCertificatePending
+			switch {
+			case err == nil:
+				logger.Info("Obtained agent certificate", "serial", c.serial(), "notAfter", c.notAfter())
+				wait = c.renewIn(time.Now())
+			case errors.Is(err, errCertificateJoinDisabled):
+				logger.Error(err, "certificate join disabled; the agent keeps authenticating with its token")
+				return
+			case errors.As(err, &pending):
+				logger.Info("Agent certificate request pending", "message", pending.message)
+				wait = certRetryInterval
+			default:
+				if ctx.Err() != nil {
+					return
+				}
+				logger.Error(err, "agent certificate request failed, retrying", "after", certRetryInterval)
+				wait = certRetryInterval
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// renewIn returns how long until the certificate should be renewed; zero
+// when there is none.
+func (c *CertificateJoin) renewIn(now time.Time) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cert == nil {
+		return 0
+	}
+	lifetime := c.cert.NotAfter.Sub(c.cert.NotBefore)
+	return max(c.cert.NotBefore.Add(lifetime*2/3).Sub(now), 0)
+}
+
+func (c *CertificateJoin) serial() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cert.SerialNumber.Text(16)
+}
+
+func (c *CertificateJoin) notAfter() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cert.NotAfter
+}
+
+// assertion returns a fresh assertion for the current certificate, or ""
+// when the agent has no valid certificate.
+func (c *CertificateJoin) assertion(now time.Time) (string, error) {
+	c.mu.Lock()
+	key, cert := c.key, c.cert
+	c.mu.Unlock()
+	if cert == nil || !now.Before(cert.NotAfter) {
+		return "", nil
+	}
+	claims, err := json.Marshal(struct {
+		IssuedAt  int64 `json:"iat"`
+		ExpiresAt int64 `json:"exp"`
+	}{now.Unix(), now.Add(certAssertionLifetime).Unix()})
+	if err != nil {
+		return "", err
+	}
+	signed := certTokenPrefix + base64.RawURLEncoding.EncodeToString(cert.Raw) + "." +
+		base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// request sends a certificate signing request authenticated with token and
+// stores the certificate the hub returns. The key of a pending request is
+// kept, so retries present the request the operator approves.
+func (c *CertificateJoin) request(ctx context.Context, token string) error {
+	pendingPath := filepath.Join(c.dir, certPendingFile)
+	key, err := readECKey(pendingPath)
+	if errors.Is(err, os.ErrNotExist) {
+		if key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader); err != nil {
+			return err
+		}
+		if err = writeECKey(pendingPath, key); err != nil {
+			return fmt.Errorf("storing the agent key: %w", err)
+		}
+	} else if err != nil {
+		return err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: c.edgeName},
+	}, key)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(map[string]string{
+		"csr": string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr})),
+	})
+	if err != nil {
+		return err
+	}
+
+	base, cluster := tunnelCluster(c.hubURL, c.cluster, token)
+	url := apiurl.ProviderAgentProxyURL(base, c.resourceType, cluster, c.edgeName, "certificate")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+
+	var result struct {
+		Status      string `json:"status"`
+		Certificate string `json:"certificate"`
+		Message     string `json:"message"`
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusAccepted:
+		_ = json.Unmarshal(respBody, &result)
+		return &errCertificatePending{message: result.Message}
+	case http.StatusNotFound:
+		return errCertificateJoinDisabled
+	default:
+		return fmt.Errorf("hub refused the certificate request: %s: %s", resp.Status, bytes.TrimSpace(respBody))
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return fmt.Errorf("decoding the certificate response: %w", err)
+	}
+	cert, err := parseCertificatePEM([]byte(result.Certificate))
+	if err != nil {
+		return err
+	}
+	if pub, ok := cert.PublicKey.(*ecdsa.PublicKey); !ok || !pub.Equal(key.Public()) {
+		return errors.New("the hub returned a certificate for another key")
+	}
+
+	if err := writeFile(filepath.Join(c.dir, certFile), []byte(result.Certificate)); err != nil {
+		return fmt.Errorf("storing the agent certificate: %w", err)
+	}
+	if err := os.Rename(pendingPath, filepath.Join(c.dir, certKeyFile)); err != nil {
+		return fmt.Errorf("storing the agent key: %w", err)
+	}
+	c.mu.Lock()
+	c.key, c.cert = key, cert
+	c.mu.Unlock()
+	return nil
+}
+
+func parseCertificatePEM(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("no PEM certificate")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func readECKey(path string) (*ecdsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM key", path)
+	}
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
+
+func writeECKey(path string, key *ecdsa.PrivateKey) error {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	return writeFile(path, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+}
+
+// writeFile writes data readable by the agent only.
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestCertificateJoin checks that a pending request is retried with the same
+// key, that the issued certificate signs tunnel assertions, and that it is
+// loaded again on restart.
+func TestCertificateJoin(t *testing.T) {
+	caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	caTemplate := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "ca"}}
+
+	var requests []*x509.CertificateRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/root:org/apis/edges.kedge.faros.sh/v1alpha1/linuxservers/store-1/certificate") {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer join-token" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		var body struct{ CSR string }
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		block, _ := pem.Decode([]byte(body.CSR))
+		csr, err := x509.ParseCertificateRequest(block.Bytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		requests = append(requests, csr)
+		if len(requests) == 1 {
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"status":"Pending","message":"awaits approval"}`))
+			return
+		}
+		der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+			SerialNumber: big.NewInt(42),
+			NotBefore:    time.Now().Add(-time.Minute),
+			NotAfter:     time.Now().Add(time.Hour),
+		}, caTemplate, csr.PublicKey, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"status":      "Issued",
+			"certificate": string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		})
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	join, err := NewCertificateJoin(srv.URL, "server", "root:org", "store-1", dir, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fallback := func() string { return "join-token" }
+	if got := join.Token(fallback)(); got != "join-token" {
+		t.Errorf("token without a certificate = %q, want the fallback", got)
+	}
+
+	ctx := context.Background()
+	if err := join.request(ctx, "join-token"); err == nil || err.Error() != "awaits approval" {
+		t.Fatalf("first request: %v, want pending", err)
+	}
+	if err := join.request(ctx, "join-token"); err != nil {
+		t.Fatalf("second request: %v", err)
+	}
+	if !requests[0].PublicKey.(*ecdsa.PublicKey).Equal(requests[1].PublicKey) {
+		t.Error("retried request uses a new key")
+	}
+	if wait := join.renewIn(time.Now()); wait < 35*time.Minute || wait > 40*time.Minute {
+		t.Errorf("renewIn = %s, want two thirds into the lifetime", wait)
+	}
+
+	token := join.Token(fallback)()
+	if !strings.HasPrefix(token, certTokenPrefix) {
+		t.Fatalf("token = %q, want an assertion", token)
+	}
+	parts := strings.Split(strings.TrimPrefix(token, certTokenPrefix), ".")
+	sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	digest := sha256.Sum256([]byte(token[:strings.LastIndex(token, ".")]))
+	if !ecdsa.VerifyASN1(requests[1].PublicKey.(*ecdsa.PublicKey), digest[:], sig) {
+		t.Error("assertion signature does not verify")
+	}
+
+	reloaded, err := NewCertificateJoin(srv.URL, "server", "root:org", "store-1", dir, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.cert == nil || reloaded.cert.SerialNumber.Int64() != 42 {
+		t.Error("certificate not reloaded from disk")
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"k8s.io/client-go/rest"
+)
+
+// downstreamAuth authenticates requests the agent proxies to the edge's API
+// server with whatever credential the agent's kubeconfig holds: a static or
+// file-backed bearer token, basic auth, a client certificate, an exec
+// credential plugin (aws-iam-authenticator, gke-gcloud-auth-plugin) or an
+// auth provider such as oidc. Short-lived tokens are refreshed by client-go
+// as they expire, and again when the API server answers 401.
+type downstreamAuth struct {
+	// wrap adds the credential to requests sent through the returned
+	// RoundTripper.
+	wrap func(http.RoundTripper) (http.RoundTripper, error)
+	// headers is wrap around a RoundTripper that records the request
+	// instead of sending it, for connections the agent writes itself.
+	headers http.RoundTripper
+}
+
+func newDownstreamAuth(config *rest.Config) (*downstreamAuth, error) {
+	wrap := func(rt http.RoundTripper) (http.RoundTripper, error) {
+		return rest.HTTPWrappersForConfig(config, rt)
+	}
+	headers, err := wrap(recordHeaders{})
+	if err != nil {
+		return nil, err
+	}
+	return &downstreamAuth{wrap: wrap, headers: headers}, nil
+}
+
+// apply replaces the caller's credentials on r with the agent's: the
+// Authorization header and any impersonation headers the kubeconfig sets.
+func (a *downstreamAuth) apply(ctx context.Context, r *http.Request) error {
+	stripCredentials(r.Header)
+	probe, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://downstream/", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := a.headers.RoundTrip(probe)
+	if err != nil {
+		return err
+	}
+	for key, values := range resp.Request.Header {
+		if isCredentialHeader(key) {
+			r.Header[key] = values
+		}
+	}
+	return nil
+}
+
+// stripCredentials removes credentials a caller sent, so the wrappers, which
+// leave an existing Authorization header alone, add the agent's.
+func stripCredentials(h http.Header) {
+	for key := range h {
+		if isCredentialHeader(key) {
+			delete(h, key)
+		}
+	}
+}
+
+func isCredentialHeader(key string) bool {
+	key = http.CanonicalHeaderKey(key)
+	return key == "Authorization" || strings.HasPrefix(key, "Impersonate-")
+}
+
+// recordHeaders answers every request with an empty response carrying the
+// request, so its headers can be read after the wrappers ran.
+type recordHeaders struct{}
+
+func (recordHeaders) RoundTrip(r *http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody, Request: r}, nil
+}
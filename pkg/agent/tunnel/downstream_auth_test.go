@@ -0,0 +1,87 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// TestK8sHandlerUsesExecCredentials checks that tunneled requests, plain and
+// upgraded, carry the token of the kubeconfig's exec plugin instead of the
+// caller's Authorization header.
+func TestK8sHandlerUsesExecCredentials(t *testing.T) {
+	apiserver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Header.Get("Authorization")) //nolint:errcheck
+	}))
+	defer apiserver.Close()
+
+	plugin := filepath.Join(t.TempDir(), "credential-plugin")
+	script := `#!/bin/sh
+echo '{"apiVersion":"client.authentication.k8s.io/v1","kind":"ExecCredential","status":{"token":"exec-token"}}'
+`
+	if err := os.WriteFile(plugin, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	config := &rest.Config{
+		Host: apiserver.URL,
+		ExecProvider: &clientcmdapi.ExecConfig{
+			APIVersion:      "client.authentication.k8s.io/v1",
+			Command:         plugin,
+			InteractiveMode: clientcmdapi.NeverExecInteractiveMode,
+		},
+	}
+	agent := httptest.NewServer(k8sHandler(config, ProxyScope{}, nil))
+	defer agent.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, agent.URL+"/k8s/api", nil)
+	req.Header.Set("Authorization", "Bearer caller-token")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if string(body) != "Bearer exec-token" {
+		t.Errorf("proxied Authorization = %q, want the exec plugin's token", body)
+	}
+
+	conn, err := net.Dial("tcp", agent.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()                                                                                                                                      //nolint:errcheck
+	fmt.Fprint(conn, "GET /k8s/api HTTP/1.1\r\nHost: edge-agent\r\nAuthorization: Bearer caller-token\r\nConnection: Upgrade\r\nUpgrade: SPDY/3.1\r\n\r\n") //nolint:errcheck
+	resp, err = http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ = io.ReadAll(io.LimitReader(resp.Body, int64(len("Bearer exec-token"))))
+	if string(body) != "Bearer exec-token" {
+		t.Errorf("upgraded Authorization = %q, want the exec plugin's token", body)
+	}
+}
@@ -0,0 +1,289 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+// EndToEndTLSServerName is the name the agent's generated serving certificate
+// is issued for. Clients set it as the TLS server name (kubeconfig
+// tls-server-name) since they reach the agent through a local relay.
+const EndToEndTLSServerName = "kedge-agent"
+
+// EndToEndTLSUpgrade is the Upgrade protocol of the /k8s-tls endpoint. After
+// the 101 response the connection carries a TLS session terminated by the
+// agent, so the hub only relays ciphertext.
+const EndToEndTLSUpgrade = "kedge-tls"
+
+// EndToEndTLS is the agent's serving identity for end-to-end TLS to the
+// downstream Kubernetes API. When enabled the agent refuses plaintext /k8s
+// requests: every API call must arrive over /k8s-tls, inside a TLS session
+// the hub cannot decrypt. The downstream client credentials never leave the
+// agent either way.
+//
+// Clients pin the certificate the agent publishes in its edge status, but
+// that status reaches them through the hub, which could publish a
+// certificate of its own and terminate the session itself. The pin is
+// therefore only as good as an out-of-band check of Fingerprint: the agent
+// logs it at startup, and `kedge edge tls-proxy --fingerprint` refuses a
+// published certificate that does not match. A generated certificate is
+// persisted, so the fingerprint stays the same across agent restarts and
+// needs checking once.
+type EndToEndTLS struct {
+	config *tls.Config
+
+	// CertificatePEM is the serving certificate, published in the edge's
+	// status so clients can pin it.
+	CertificatePEM string
+	// Fingerprint is the SHA-256 of the certificate's DER, "sha256:<hex>",
+	// for out-of-band verification of the published certificate.
+	Fingerprint string
+}
+
+// Files the generated certificate and key are persisted as, in the agent's
+// state directory next to its SSH host key.
+const (
+	endToEndTLSCertFile = "e2e-tls.crt"
+	endToEndTLSKeyFile  = "e2e-tls.key"
+)
+
+// NewEndToEndTLS loads the serving certificate from certFile/keyFile, or,
+// when both are empty, uses a self-signed one for EndToEndTLSServerName kept
+// in stateDir: generated on first start (and once expired), then reused so
+// its fingerprint stays stable. An empty stateDir generates a certificate
+// that lives as long as the agent process.
+func NewEndToEndTLS(certFile, keyFile, stateDir string) (*EndToEndTLS, error) {
+	var (
+		cert tls.Certificate
+		err  error
+	)
+	switch {
+	case certFile != "" && keyFile != "":
+		cert, err = tls.LoadX509KeyPair(certFile, keyFile)
+	case certFile == "" && keyFile == "":
+		cert, err = loadOrCreateServingCert(stateDir, EndToEndTLSServerName)
+	default:
+		return nil, fmt.Errorf("end-to-end TLS certificate and key files must be set together")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading end-to-end TLS certificate: %w", err)
+	}
+
+	sum := sha256.Sum256(cert.Certificate[0])
+	return &EndToEndTLS{
+		config: &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+			// The inner server speaks HTTP/1.1 only: exec and port-forward
+			// rely on Upgrade, which HTTP/2 does not have.
+			NextProtos: []string{"http/1.1"},
+		},
+		CertificatePEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]})),
+		Fingerprint:    "sha256:" + hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// loadOrCreateServingCert returns the certificate persisted in dir, or
+// generates and persists a new one when there is none or it has expired.
+func loadOrCreateServingCert(dir, name string) (tls.Certificate, error) {
+	if dir == "" {
+		cert, _, _, err := generateServingCert(name)
+		return cert, err
+	}
+	certPath := filepath.Join(dir, endToEndTLSCertFile)
+	keyPath := filepath.Join(dir, endToEndTLSKeyFile)
+
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	switch {
+	case err == nil:
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			return tls.Certificate{}, fmt.Errorf("parsing %s: %w", certPath, err)
+		}
+		if time.Now().Before(leaf.NotAfter) {
+			return cert, nil
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return tls.Certificate{}, err
+	}
+
+	cert, certPEM, keyPEM, err := generateServingCert(name)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return tls.Certificate{}, fmt.Errorf("creating %s: %w", dir, err)
+	}
+	if err := os.WriteFile(keyPath, keyPEM, 0600); err != nil {
+		return tls.Certificate{}, fmt.Errorf("writing %s: %w", keyPath, err)
+	}
+	if err := os.WriteFile(certPath, certPEM, 0644); err != nil {
+		return tls.Certificate{}, fmt.Errorf("writing %s: %w", certPath, err)
+	}
+	return cert, nil
+}
+
+// generateServingCert returns a self-signed serving certificate for name,
+// with its certificate and key PEM-encoded for persisting.
+func generateServingCert(name string) (cert tls.Certificate, certPEM, keyPEM []byte, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, nil, nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, nil, nil, err
+	}
+	now := time.Now()
+	// A plain leaf: clients pin it directly as their only trusted
+	// certificate, so it needs no CA rights and must not be able to
+	// issue certificates for other names.
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: name},
+		DNSNames:              []string{name},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.AddDate(1, 0, 0),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, nil, nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return tls.Certificate{}, nil, nil, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key},
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		nil
+}
+
+// newK8sTLSHandler serves GET /k8s-tls. Like /ssh, the agent answers 101 and
+// hijacks the connection; it then terminates the client's TLS session with
+// its own certificate and serves the downstream Kubernetes API proxy over it.
+// Inner request paths are plain API paths (/api/v1/pods), without /k8s.
+func newK8sTLSHandler(downstream *rest.Config, e2e *EndToEndTLS, scope ProxyScope) http.HandlerFunc {
+	inner := k8sHandler(downstream, scope, nil)
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := klog.Background().WithName("k8s-tls-handler")
+
+		if !strings.EqualFold(r.Header.Get("Upgrade"), EndToEndTLSUpgrade) {
+			http.Error(w, fmt.Sprintf("expected Upgrade: %s", EndToEndTLSUpgrade), http.StatusBadRequest)
+			return
+		}
+		hijacker, ok := w.(http.Hijacker)
+		if !ok {
+			http.Error(w, "hijacking not supported", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Connection", "Upgrade")
+		w.Header().Set("Upgrade", EndToEndTLSUpgrade)
+		w.WriteHeader(http.StatusSwitchingProtocols)
+
+		tunnelConn, brw, err := hijacker.Hijack()
+		if err != nil {
+			logger.Error(err, "failed to hijack connection")
+			return
+		}
+		if err := brw.Flush(); err != nil {
+			logger.Error(err, "failed to flush 101 response")
+			_ = tunnelConn.Close()
+			return
+		}
+
+		tlsConn := tls.Server(tunnelConn, e2e.config)
+		server := &http.Server{
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// k8sHandler expects the tunnel's /k8s/... form.
+				r.URL.Path = "/k8s" + r.URL.Path
+				inner(w, r)
+			}),
+			ReadHeaderTimeout: 30 * time.Second,
+		}
+		ln := newSingleConnListener(tlsConn)
+		// Shut the server down once its only connection is gone, whether it
+		// closed normally or was hijacked by an exec/port-forward upgrade.
+		server.ConnState = func(_ net.Conn, state http.ConnState) {
+			if state == http.StateClosed || state == http.StateHijacked {
+				_ = ln.Close()
+			}
+		}
+		logger.V(4).Info("End-to-end TLS session started", "remote", r.RemoteAddr)
+		_ = server.Serve(ln)
+	}
+}
+
+// singleConnListener is a net.Listener that yields one connection, then
+// blocks until closed.
+type singleConnListener struct {
+	conn   net.Conn
+	once   sync.Once
+	closed chan struct{}
+	mu     sync.Mutex
+	taken  bool
+}
+
+func newSingleConnListener(conn net.Conn) *singleConnListener {
+	return &singleConnListener{conn: conn, closed: make(chan struct{})}
+}
+
+func (l *singleConnListener) Accept() (net.Conn, error) {
+	l.mu.Lock()
+	if !l.taken {
+		l.taken = true
+		l.mu.Unlock()
+		return l.conn, nil
+	}
+	l.mu.Unlock()
+	<-l.closed
+	return nil, net.ErrClosed
+}
+
+func (l *singleConnListener) Close() error {
+	l.once.Do(func() { close(l.closed) })
+	return nil
+}
+
+func (l *singleConnListener) Addr() net.Addr { return l.conn.LocalAddr() }
@@ -0,0 +1,129 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestEndToEndTLS(t *testing.T) {
+	apiserver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s %s", r.URL.Path, r.Header.Get("Authorization")) //nolint:errcheck
+	}))
+	defer apiserver.Close()
+
+	e2e, err := NewEndToEndTLS("", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(e2e.Fingerprint, "sha256:") {
+		t.Fatalf("Fingerprint = %q", e2e.Fingerprint)
+	}
+	router := setupRouter(&rest.Config{Host: apiserver.URL, BearerToken: "agent-token"}, e2e, ProxyScope{}, nil, 0)
+	agent := httptest.NewServer(router)
+	defer agent.Close()
+
+	// Plaintext access is refused.
+	resp, err := http.Get(agent.URL + "/k8s/api/v1/pods")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("plaintext /k8s status = %d, want %d", resp.StatusCode, http.StatusForbidden)
+	}
+
+	// The relay upgrades, then carries TLS terminated by the agent.
+	conn, err := net.Dial("tcp", agent.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close() //nolint:errcheck
+
+	fmt.Fprintf(conn, "GET /k8s-tls HTTP/1.1\r\nHost: edge-agent\r\nConnection: Upgrade\r\nUpgrade: %s\r\n\r\n", EndToEndTLSUpgrade) //nolint:errcheck
+	br := bufio.NewReader(conn)
+	resp, err = http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("upgrade status = %d, want 101", resp.StatusCode)
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM([]byte(e2e.CertificatePEM)) {
+		t.Fatal("CertificatePEM holds no certificate")
+	}
+	tlsConn := tls.Client(conn, &tls.Config{RootCAs: roots, ServerName: EndToEndTLSServerName, MinVersion: tls.VersionTLS12})
+	fmt.Fprint(tlsConn, "GET /api/v1/pods HTTP/1.1\r\nHost: kedge-agent\r\nConnection: close\r\n\r\n") //nolint:errcheck
+	resp, err = http.ReadResponse(bufio.NewReader(tlsConn), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if got, want := string(body), "/api/v1/pods Bearer agent-token"; got != want {
+		t.Fatalf("proxied response = %q, want %q", got, want)
+	}
+}
+
+func TestNewEndToEndTLSRequiresKeyPair(t *testing.T) {
+	if _, err := NewEndToEndTLS("cert.pem", "", ""); err == nil {
+		t.Fatal("NewEndToEndTLS with only a cert file succeeded")
+	}
+}
+
+func TestEndToEndTLSPersistsGeneratedCert(t *testing.T) {
+	dir := t.TempDir()
+	first, err := NewEndToEndTLS("", "", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := NewEndToEndTLS("", "", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Fingerprint != second.Fingerprint {
+		t.Fatalf("fingerprint changed across restarts: %s then %s", first.Fingerprint, second.Fingerprint)
+	}
+	if info, err := os.Stat(filepath.Join(dir, endToEndTLSKeyFile)); err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("persisted key = %v, %v; want mode 0600", info, err)
+	}
+
+	block, _ := pem.Decode([]byte(first.CertificatePEM))
+	leaf, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if leaf.IsCA || leaf.KeyUsage&x509.KeyUsageCertSign != 0 {
+		t.Fatalf("generated certificate can sign certificates (IsCA %v, KeyUsage %v)", leaf.IsCA, leaf.KeyUsage)
+	}
+}
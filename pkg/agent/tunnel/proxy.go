@@ -0,0 +1,59 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ProxyFunc returns the proxy selection for connections to the hub, in the
+// form http.Transport.Proxy takes. An empty proxyURL follows HTTP_PROXY,
+// HTTPS_PROXY and NO_PROXY; otherwise every connection goes through
+// proxyURL (http, https or socks5, optionally with user:password) except
+// those to hosts NO_PROXY exempts. The tunnel's websocket upgrade and the
+// hub's pick-up dials reach an HTTP proxy with CONNECT, so TLS to the hub
+// stays end to end.
+func ProxyFunc(proxyURL string) (func(*http.Request) (*url.URL, error), error) {
+	if proxyURL == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL %q: %w", proxyURL, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("invalid proxy URL %q: scheme must be http, https or socks5", proxyURL)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: no host", proxyURL)
+	}
+	noProxy := os.Getenv("NO_PROXY")
+	if noProxy == "" {
+		noProxy = os.Getenv("no_proxy")
+	}
+	proxy := (&httpproxy.Config{HTTPProxy: proxyURL, HTTPSProxy: proxyURL, NoProxy: noProxy}).ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}, nil
+}
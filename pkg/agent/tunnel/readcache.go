@@ -0,0 +1,286 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"context"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/faroshq/faros-kedge/pkg/agent/metrics"
+)
+
+// cachedResource is a core/v1 resource the read cache serves.
+type cachedResource struct {
+	namespaced bool
+	listKind   string
+}
+
+// cachedResources are the resources hub users browse most. Only these are
+// served from the cache; every other request goes to the API server.
+var cachedResources = map[string]cachedResource{
+	"pods":       {namespaced: true, listKind: "PodList"},
+	"nodes":      {listKind: "NodeList"},
+	"namespaces": {listKind: "NamespaceList"},
+}
+
+// readCacheRequests counts the k8s proxy reads the read cache could serve,
+// by resource and result ("hit" or "miss").
+var readCacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "kedge_agent",
+	Name:      "read_cache_requests_total",
+	Help:      "Cacheable Kubernetes API reads proxied from the hub, by whether the agent's read cache served them.",
+}, []string{"resource", "result"})
+
+func init() {
+	metrics.Registry.MustRegister(readCacheRequests)
+}
+
+// readCache serves GET and LIST requests for cachedResources from informers
+// on the edge cluster, so many hub users browsing the same edge cost the
+// edge's API server one watch per resource instead of a list each. An
+// informer starts on the first read of its resource and stops after ttl
+// without reads; until it has synced, reads go to the API server.
+//
+// Only plain JSON reads are served: requests with any query parameter but
+// labelSelector (paging, field selectors, resourceVersion, watch) or asking
+// for a Table or protobuf-only response pass through.
+type readCache struct {
+	ctx    context.Context
+	client dynamic.Interface
+	ttl    time.Duration
+
+	mu        sync.Mutex
+	informers map[string]*readInformer
+}
+
+// readInformer is the informer of one resource and when it was last read.
+type readInformer struct {
+	informer cache.SharedIndexInformer
+	stop     context.CancelFunc
+	lastRead time.Time
+}
+
+// newReadCache returns a read cache for the cluster config points at, or
+// nil when ttl is zero. Its informers stop when ctx is done.
+func newReadCache(ctx context.Context, config *rest.Config, ttl time.Duration) (*readCache, error) {
+	if config == nil || ttl <= 0 {
+		return nil, nil
+	}
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	c := &readCache{ctx: ctx, client: client, ttl: ttl, informers: map[string]*readInformer{}}
+	go wait.Until(c.expire, ttl/2, ctx.Done())
+	return c, nil
+}
+
+// serve answers r from the cache and reports whether it did; when it did
+// not, the caller proxies r to the API server. k8sPath is r's path on the
+// API server.
+func (c *readCache) serve(w http.ResponseWriter, r *http.Request, k8sPath string) bool {
+	if c == nil || r.Method != http.MethodGet {
+		return false
+	}
+	resource, namespace, name, ok := parseCoreRead(k8sPath)
+	if !ok || !cacheableQuery(r) || !acceptsJSON(r.Header.Get("Accept")) {
+		return false
+	}
+	selector, err := labels.Parse(r.URL.Query().Get("labelSelector"))
+	if err != nil {
+		return false
+	}
+
+	informer := c.informer(resource)
+	if informer == nil {
+		readCacheRequests.WithLabelValues(resource, "miss").Inc()
+		return false
+	}
+
+	var body interface{}
+	if name != "" {
+		key := name
+		if namespace != "" {
+			key = namespace + "/" + name
+		}
+		obj, exists, err := informer.GetIndexer().GetByKey(key)
+		if err != nil || !exists {
+			// Possibly created since the last event: let the API server say.
+			readCacheRequests.WithLabelValues(resource, "miss").Inc()
+			return false
+		}
+		body = obj
+	} else {
+		var objs []interface{}
+		if namespace != "" {
+			objs, _ = informer.GetIndexer().ByIndex(cache.NamespaceIndex, namespace)
+		} else {
+			objs = informer.GetIndexer().List()
+		}
+		items := make([]interface{}, 0, len(objs))
+		for _, obj := range objs {
+			u := obj.(*unstructured.Unstructured)
+			if selector.Matches(labels.Set(u.GetLabels())) {
+				items = append(items, u.Object)
+			}
+		}
+		sort.Slice(items, func(i, j int) bool {
+			a, b := items[i].(map[string]interface{}), items[j].(map[string]interface{})
+			return objectKey(a) < objectKey(b)
+		})
+		body = map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       cachedResources[resource].listKind,
+			"metadata":   map[string]interface{}{"resourceVersion": informer.LastSyncResourceVersion()},
+			"items":      items,
+		}
+	}
+
+	readCacheRequests.WithLabelValues(resource, "hit").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Kedge-Cache", "hit")
+	_ = json.NewEncoder(w).Encode(body)
+	return true
+}
+
+// informer returns the synced informer of resource, starting it on first
+// use. It returns nil while the informer is still syncing.
+func (c *readCache) informer(resource string) cache.SharedIndexInformer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ri := c.informers[resource]
+	if ri == nil {
+		ctx, stop := context.WithCancel(c.ctx)
+		informer := dynamicinformer.NewFilteredDynamicInformer(c.client,
+			schema.GroupVersionResource{Version: "v1", Resource: resource},
+			metav1.NamespaceAll, 0,
+			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, nil).Informer()
+		go informer.RunWithContext(ctx)
+		ri = &readInformer{informer: informer, stop: stop}
+		c.informers[resource] = ri
+	}
+	ri.lastRead = time.Now()
+	if !ri.informer.HasSynced() {
+		return nil
+	}
+	return ri.informer
+}
+
+// expire stops the informers not read for ttl.
+func (c *readCache) expire() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for resource, ri := range c.informers {
+		if time.Since(ri.lastRead) >= c.ttl {
+			ri.stop()
+			delete(c.informers, resource)
+		}
+	}
+}
+
+// parseCoreRead splits a core/v1 read of a cached resource into its
+// resource, namespace and name. Subresources and other paths are not
+// reads the cache serves.
+func parseCoreRead(path string) (resource, namespace, name string, ok bool) {
+	p, found := strings.CutPrefix(path, "/api/v1/")
+	if !found {
+		return "", "", "", false
+	}
+	parts := strings.Split(strings.TrimSuffix(p, "/"), "/")
+	if len(parts) >= 3 && parts[0] == "namespaces" {
+		// /namespaces/{ns}/{resource}[/{name}]
+		if len(parts) > 4 {
+			return "", "", "", false
+		}
+		resource, namespace = parts[2], parts[1]
+		if len(parts) == 4 {
+			name = parts[3]
+		}
+		res, known := cachedResources[resource]
+		return resource, namespace, name, known && res.namespaced
+	}
+	// /{resource}[/{name}]
+	if len(parts) > 2 {
+		return "", "", "", false
+	}
+	resource = parts[0]
+	if len(parts) == 2 {
+		name = parts[1]
+		if cachedResources[resource].namespaced {
+			return "", "", "", false
+		}
+	}
+	_, known := cachedResources[resource]
+	return resource, "", name, known
+}
+
+// cacheableQuery reports whether r's query asks for nothing the cache
+// cannot answer.
+func cacheableQuery(r *http.Request) bool {
+	for key := range r.URL.Query() {
+		if key != "labelSelector" && key != "pretty" {
+			return false
+		}
+	}
+	return true
+}
+
+// acceptsJSON reports whether a client sending accept takes a plain JSON
+// object, and asks for no alternative rendering such as a Table.
+func acceptsJSON(accept string) bool {
+	if accept == "" {
+		return true
+	}
+	plain := false
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			return false
+		}
+		if _, ok := params["as"]; ok {
+			return false
+		}
+		if mediaType == "application/json" || mediaType == "*/*" {
+			plain = true
+		}
+	}
+	return plain
+}
+
+// objectKey orders list items as the API server does: by namespace, then
+// name.
+func objectKey(obj map[string]interface{}) string {
+	u := unstructured.Unstructured{Object: obj}
+	return u.GetNamespace() + "/" + u.GetName()
+}
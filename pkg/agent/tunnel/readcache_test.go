@@ -0,0 +1,132 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func testPod(namespace, name, app string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"name": name, "namespace": namespace,
+			"labels": map[string]interface{}{"app": app},
+		},
+	}}
+}
+
+func TestReadCacheServe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{{Version: "v1", Resource: "pods"}: "PodList"},
+		testPod("shop", "web-1", "web"), testPod("shop", "db-1", "db"), testPod("kube-system", "dns-1", "dns"))
+	c := &readCache{ctx: ctx, client: client, ttl: time.Minute, informers: map[string]*readInformer{}}
+
+	get := func(target, accept string) (*httptest.ResponseRecorder, bool) {
+		r := httptest.NewRequest(http.MethodGet, "/k8s"+target, nil)
+		if accept != "" {
+			r.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+		return rec, c.serve(rec, r, r.URL.Path[len("/k8s"):])
+	}
+
+	// The first read starts the informer; reads pass through until it syncs.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, served := get("/api/v1/pods", ""); served {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("read cache never synced")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	rec, served := get("/api/v1/namespaces/shop/pods?labelSelector=app%3Dweb", "application/json")
+	if !served {
+		t.Fatal("namespaced list with a label selector not served")
+	}
+	var list struct {
+		Kind  string                   `json:"kind"`
+		Items []map[string]interface{} `json:"items"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if list.Kind != "PodList" || len(list.Items) != 1 || (&unstructured.Unstructured{Object: list.Items[0]}).GetName() != "web-1" {
+		t.Errorf("list = %s", rec.Body)
+	}
+
+	if rec, served := get("/api/v1/namespaces/kube-system/pods/dns-1", ""); !served || rec.Header().Get("X-Kedge-Cache") != "hit" {
+		t.Error("get by name not served")
+	}
+	for _, tc := range []struct{ target, accept string }{
+		{"/api/v1/namespaces/shop/pods/missing", ""},
+		{"/api/v1/namespaces/shop/pods/web-1/log", ""},
+		{"/api/v1/pods?limit=500", ""},
+		{"/api/v1/pods", "application/json;as=Table;v=v1;g=meta.k8s.io,application/json"},
+		{"/api/v1/pods", "application/vnd.kubernetes.protobuf"},
+		{"/api/v1/services", ""},
+	} {
+		if _, served := get(tc.target, tc.accept); served {
+			t.Errorf("%s (Accept %q) served from the cache", tc.target, tc.accept)
+		}
+	}
+
+	c.informers["pods"].lastRead = time.Now().Add(-time.Hour)
+	c.expire()
+	if len(c.informers) != 0 {
+		t.Error("idle informer not stopped")
+	}
+}
+
+func TestParseCoreRead(t *testing.T) {
+	for _, tc := range []struct {
+		path                      string
+		resource, namespace, name string
+		ok                        bool
+	}{
+		{"/api/v1/pods", "pods", "", "", true},
+		{"/api/v1/namespaces/shop/pods", "pods", "shop", "", true},
+		{"/api/v1/namespaces/shop/pods/web-1", "pods", "shop", "web-1", true},
+		{"/api/v1/namespaces/shop", "namespaces", "", "shop", true},
+		{"/api/v1/namespaces", "namespaces", "", "", true},
+		{"/api/v1/nodes/n1", "nodes", "", "n1", true},
+		{"/api/v1/pods/web-1", "", "", "", false},
+		{"/api/v1/nodes/n1/proxy", "", "", "", false},
+		{"/apis/apps/v1/deployments", "", "", "", false},
+	} {
+		resource, namespace, name, ok := parseCoreRead(tc.path)
+		if ok != tc.ok || (ok && (resource != tc.resource || namespace != tc.namespace || name != tc.name)) {
+			t.Errorf("parseCoreRead(%s) = %s %s %s %v", tc.path, resource, namespace, name, ok)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/faroshq/faros-kedge/pkg/agent/metrics"
+)
+
+// Reconnect tunes how the agent redials a lost tunnel. Delays grow
+// exponentially from InitialInterval up to MaxInterval, and each wait is
+// drawn uniformly between zero and the current delay ("full jitter"), so
+// agents that lost the hub at the same moment, say on a hub restart, come
+// back spread out instead of all at once.
+type Reconnect struct {
+	// InitialInterval bounds the first wait after a connection is lost.
+	InitialInterval time.Duration
+	// MaxInterval bounds every wait, however many attempts failed.
+	MaxInterval time.Duration
+	// ResetAfter is how long a connection must stay up for the next loss to
+	// start again from InitialInterval. A hub that accepts the tunnel and
+	// drops it at once keeps backing off.
+	ResetAfter time.Duration
+}
+
+// DefaultReconnect returns the reconnect behaviour used for zero fields.
+func DefaultReconnect() Reconnect {
+	return Reconnect{
+		InitialInterval: time.Second,
+		MaxInterval:     30 * time.Second,
+		ResetAfter:      time.Minute,
+	}
+}
+
+// Validate reports negative intervals or an initial interval above the max.
+func (r Reconnect) Validate() error {
+	if r.InitialInterval < 0 || r.MaxInterval < 0 || r.ResetAfter < 0 {
+		return fmt.Errorf("tunnel reconnect intervals must not be negative: %+v", r)
+	}
+	if r.InitialInterval > 0 && r.MaxInterval > 0 && r.InitialInterval > r.MaxInterval {
+		return fmt.Errorf("tunnel reconnect initial interval %s exceeds the max interval %s", r.InitialInterval, r.MaxInterval)
+	}
+	return nil
+}
+
+// withDefaults fills zero fields from DefaultReconnect.
+func (r Reconnect) withDefaults() Reconnect {
+	d := DefaultReconnect()
+	if r.InitialInterval == 0 {
+		r.InitialInterval = d.InitialInterval
+	}
+	if r.MaxInterval == 0 {
+		r.MaxInterval = max(d.MaxInterval, r.InitialInterval)
+	}
+	if r.ResetAfter == 0 {
+		r.ResetAfter = d.ResetAfter
+	}
+	return r
+}
+
+// reconnectBackoff yields the waits between attempts to reach the hub.
+type reconnectBackoff struct {
+	cfg     Reconnect
+	ceiling time.Duration
+	// jitter returns a duration in [0, d]; replaced in tests.
+	jitter func(d time.Duration) time.Duration
+}
+
+func newReconnectBackoff(cfg Reconnect) *reconnectBackoff {
+	cfg = cfg.withDefaults()
+	return &reconnectBackoff{
+		cfg:     cfg,
+		ceiling: cfg.InitialInterval,
+		jitter:  func(d time.Duration) time.Duration { return rand.N(d + 1) },
+	}
+}
+
+// next returns the wait before the next attempt and doubles the ceiling for
+// the one after, up to MaxInterval.
+func (b *reconnectBackoff) next() time.Duration {
+	wait := b.jitter(b.ceiling)
+	b.ceiling = min(2*b.ceiling, b.cfg.MaxInterval)
+	return wait
+}
+
+// connected tells the backoff a connection stayed up for up; a long enough
+// connection starts the next round of waits from InitialInterval.
+func (b *reconnectBackoff) connected(up time.Duration) {
+	if up >= b.cfg.ResetAfter {
+		b.ceiling = b.cfg.InitialInterval
+	}
+}
+
+var (
+	// tunnelConnectAttempts counts attempts to open the tunnel, by edge and
+	// result ("success" or "failure").
+	tunnelConnectAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kedge_agent",
+		Name:      "tunnel_connect_attempts_total",
+		Help:      "Attempts to open the tunnel to the hub, by result.",
+	}, []string{"edge", "result"})
+	// tunnelConnected is 1 while the edge's tunnel is up.
+	tunnelConnected = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "kedge_agent",
+		Name:      "tunnel_connected",
+		Help:      "Whether the tunnel to the hub is connected (1) or not (0).",
+	}, []string{"edge"})
+	// tunnelReconnectDelay is the wait before the edge's next attempt.
+	tunnelReconnectDelay = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "kedge_agent",
+		Name:      "tunnel_reconnect_delay_seconds",
+		Help:      "Wait before the next attempt to reopen the tunnel to the hub.",
+	}, []string{"edge"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(tunnelConnectAttempts, tunnelConnected, tunnelReconnectDelay)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"testing"
+	"time"
+)
+
+// TestReconnectBackoff checks that the wait bound doubles up to MaxInterval,
+// survives a connection that drops at once, and resets after a stable one.
+func TestReconnectBackoff(t *testing.T) {
+	b := newReconnectBackoff(Reconnect{InitialInterval: time.Second, MaxInterval: 5 * time.Second, ResetAfter: time.Minute})
+	b.jitter = func(d time.Duration) time.Duration { return d }
+
+	var got []time.Duration
+	for range 5 {
+		got = append(got, b.next())
+	}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("waits = %v, want %v", got, want)
+		}
+	}
+
+	b.connected(time.Second)
+	if d := b.next(); d != 5*time.Second {
+		t.Errorf("wait after a short-lived connection = %s, want 5s", d)
+	}
+	b.connected(time.Minute)
+	if d := b.next(); d != time.Second {
+		t.Errorf("wait after a stable connection = %s, want 1s", d)
+	}
+}
+
+func TestReconnectJitterBounds(t *testing.T) {
+	b := newReconnectBackoff(Reconnect{InitialInterval: 100 * time.Millisecond, MaxInterval: 100 * time.Millisecond})
+	seen := map[time.Duration]bool{}
+	for range 200 {
+		d := b.next()
+		if d < 0 || d > 100*time.Millisecond {
+			t.Fatalf("wait %s outside [0, 100ms]", d)
+		}
+		seen[d] = true
+	}
+	if len(seen) < 2 {
+		t.Error("waits are not jittered")
+	}
+}
+
+func TestReconnectValidate(t *testing.T) {
+	if err := (Reconnect{}).Validate(); err != nil {
+		t.Errorf("zero Reconnect: %v", err)
+	}
+	if err := (Reconnect{InitialInterval: time.Minute, MaxInterval: time.Second}).Validate(); err == nil {
+		t.Error("initial interval above the max accepted")
+	}
+	if err := (Reconnect{MaxInterval: -time.Second}).Validate(); err == nil {
+		t.Error("negative interval accepted")
+	}
+	if got := (Reconnect{InitialInterval: time.Minute}).withDefaults(); got.MaxInterval != time.Minute {
+		t.Errorf("MaxInterval defaulted to %s below InitialInterval", got.MaxInterval)
+	}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// scopeVerbs are the Kubernetes API verbs ProxyScope.DeniedVerbs may name.
+var scopeVerbs = []string{"get", "list", "watch", "create", "update", "patch", "delete", "deletecollection", "proxy"}
+
+// readVerbs are the verbs allowed on the Namespace objects of
+// ProxyScope.Namespaces.
+var readVerbs = []string{"get", "list", "watch"}
+
+var requestInfoFactory = &apirequest.RequestInfoFactory{
+	APIPrefixes:          sets.NewString("api", "apis"),
+	GrouplessAPIPrefixes: sets.NewString("api"),
+}
+
+// ProxyScope restricts what the hub may reach through the agent's Kubernetes
+// API proxy, for edge owners who expose a slice of their cluster rather than
+// everything the agent's credentials allow. It is enforced by the agent,
+// before a request reaches the read cache or the API server. The zero value
+// restricts nothing.
+//
+// Discovery, /version and the other non-resource paths stay readable so
+// clients keep working; writes to them are refused once any restriction is
+// set.
+type ProxyScope struct {
+	// Namespaces, if set, are the only namespaces reachable. Cluster-scoped
+	// resources and requests across all namespaces are refused; the listed
+	// Namespace objects themselves can be read but not written.
+	Namespaces []string
+	// DeniedVerbs are the API verbs refused: get, list, watch, create,
+	// update, patch, delete, deletecollection or proxy. Exec, attach and
+	// port-forward are creates on pod subresources.
+	DeniedVerbs []string
+	// Resources, if set, are the only resources reachable, as resource or
+	// resource.group, with /subresource for a subresource: "pods",
+	// "deployments.apps", "pods/log". As in RBAC, a resource does not cover
+	// its subresources; "pods/*" matches all of them.
+	Resources []string
+}
+
+// Validate reports unknown verbs and malformed namespaces or resources.
+func (s ProxyScope) Validate() error {
+	for _, ns := range s.Namespaces {
+		if ns == "" || strings.Contains(ns, "/") {
+			return fmt.Errorf("invalid namespace %q in k8s proxy scope", ns)
+		}
+	}
+	for _, verb := range s.DeniedVerbs {
+		if !slices.Contains(scopeVerbs, verb) {
+			return fmt.Errorf("invalid verb %q in k8s proxy scope: must be one of %s", verb, strings.Join(scopeVerbs, ", "))
+		}
+	}
+	for _, res := range s.Resources {
+		name, sub, _ := strings.Cut(res, "/")
+		if name == "" || strings.HasPrefix(name, ".") || strings.Contains(sub, "/") {
+			return fmt.Errorf("invalid resource %q in k8s proxy scope: want resource[.group][/subresource]", res)
+		}
+	}
+	return nil
+}
+
+// IsZero reports whether s restricts nothing.
+func (s ProxyScope) IsZero() bool {
+	return len(s.Namespaces) == 0 && len(s.DeniedVerbs) == 0 && len(s.Resources) == 0
+}
+
+// check returns a Forbidden status error when s does not let r, addressed to
+// k8sPath on the downstream API server, through.
+func (s ProxyScope) check(r *http.Request, k8sPath string) error {
+	if s.IsZero() {
+		return nil
+	}
+	req := r.Clone(r.Context())
+	req.URL.Path = k8sPath
+	info, err := requestInfoFactory.NewRequestInfo(req)
+	if err != nil {
+		return apierrors.NewBadRequest(err.Error())
+	}
+	if !info.IsResourceRequest {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			return apierrors.NewForbidden(schema.GroupResource{}, info.Path, fmt.Errorf("the edge only allows reads of %s through the hub", info.Path))
+		}
+		return nil
+	}
+
+	gr := schema.GroupResource{Group: info.APIGroup, Resource: info.Resource}
+	if info.Subresource != "" {
+		gr.Resource += "/" + info.Subresource
+	}
+	if slices.Contains(s.DeniedVerbs, info.Verb) {
+		return apierrors.NewForbidden(gr, info.Name, fmt.Errorf("the edge does not allow %s through the hub", info.Verb))
+	}
+	if len(s.Namespaces) > 0 && !slices.Contains(s.Namespaces, info.Namespace) {
+		if info.Namespace == "" {
+			return apierrors.NewForbidden(gr, info.Name, fmt.Errorf("the edge only exposes namespaces %s through the hub", strings.Join(s.Namespaces, ", ")))
+		}
+		return apierrors.NewForbidden(gr, info.Name, fmt.Errorf("the edge does not expose namespace %q through the hub", info.Namespace))
+	}
+	// RequestInfo puts a Namespace object's name in its Namespace, so a
+	// listed namespace would otherwise pass for a write to itself.
+	if len(s.Namespaces) > 0 && info.APIGroup == "" && info.Resource == "namespaces" && !slices.Contains(readVerbs, info.Verb) {
+		return apierrors.NewForbidden(gr, info.Name, fmt.Errorf("the edge only allows reads of namespace %q through the hub", info.Namespace))
+	}
+	if len(s.Resources) > 0 && !slices.ContainsFunc(s.Resources, func(res string) bool { return matchScopeResource(res, info) }) {
+		return apierrors.NewForbidden(gr, info.Name, fmt.Errorf("the edge does not expose %s through the hub", gr.String()))
+	}
+	return nil
+}
+
+// matchScopeResource reports whether the ProxyScope.Resources entry res
+// covers info's resource and subresource.
+func matchScopeResource(res string, info *apirequest.RequestInfo) bool {
+	gr, sub, _ := strings.Cut(res, "/")
+	resource, group, _ := strings.Cut(gr, ".")
+	if resource != info.Resource || group != info.APIGroup {
+		return false
+	}
+	if sub == "*" {
+		return info.Subresource != ""
+	}
+	return sub == info.Subresource
+}
+
+// writeStatusError writes err as a Kubernetes Status, so kubectl shows its
+// message as it would the API server's.
+func writeStatusError(w http.ResponseWriter, err error) {
+	status := apierrors.NewInternalError(err).ErrStatus
+	var se apierrors.APIStatus
+	if errors.As(err, &se) {
+		status = se.Status()
+	}
+	status.Kind, status.APIVersion = "Status", "v1"
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(int(status.Code))
+	_ = json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+func TestProxyScopeCheck(t *testing.T) {
+	scope := ProxyScope{
+		Namespaces:  []string{"shop", "web"},
+		DeniedVerbs: []string{"delete", "deletecollection"},
+		Resources:   []string{"pods", "pods/log", "deployments.apps", "services/*", "namespaces"},
+	}
+	for _, tc := range []struct {
+		method, path string
+		allowed      bool
+	}{
+		{http.MethodGet, "/api", true},
+		{http.MethodGet, "/apis/apps/v1", true},
+		{http.MethodGet, "/version", true},
+		{http.MethodPost, "/version", false},
+		{http.MethodGet, "/api/v1/namespaces/shop/pods", true},
+		{http.MethodGet, "/api/v1/namespaces/shop/pods/web-1/log", true},
+		{http.MethodGet, "/api/v1/namespaces/web/pods?watch=true", true},
+		{http.MethodPatch, "/apis/apps/v1/namespaces/web/deployments/web", true},
+		{http.MethodGet, "/api/v1/namespaces/shop", true},
+		{http.MethodGet, "/api/v1/namespaces/shop/services/web/proxy/healthz", true},
+		// Subresources must be listed.
+		{http.MethodPost, "/api/v1/namespaces/shop/pods/web-1/exec", false},
+		{http.MethodGet, "/api/v1/namespaces/shop/services/web", false},
+		// Denied verbs.
+		{http.MethodDelete, "/api/v1/namespaces/shop/pods/web-1", false},
+		{http.MethodDelete, "/api/v1/namespaces/shop/pods", false},
+		// Other namespaces, all namespaces and cluster-scoped resources.
+		{http.MethodGet, "/api/v1/namespaces/kube-system/pods", false},
+		{http.MethodGet, "/api/v1/pods", false},
+		{http.MethodGet, "/api/v1/namespaces", false},
+		{http.MethodGet, "/api/v1/nodes", false},
+		// Resources not listed.
+		{http.MethodGet, "/api/v1/namespaces/shop/secrets", false},
+		{http.MethodGet, "/apis/apps/v1/namespaces/shop/statefulsets", false},
+	} {
+		err := scope.check(httptest.NewRequest(tc.method, "/k8s"+tc.path, nil), strings.SplitN(tc.path, "?", 2)[0])
+		if (err == nil) != tc.allowed {
+			t.Errorf("%s %s: err = %v, want allowed %v", tc.method, tc.path, err, tc.allowed)
+		}
+	}
+
+	// Listed Namespace objects are readable, not writable, even with no
+	// verb denied.
+	nsOnly := ProxyScope{Namespaces: []string{"shop"}}
+	for _, tc := range []struct {
+		method, path string
+		allowed      bool
+	}{
+		{http.MethodGet, "/api/v1/namespaces/shop", true},
+		{http.MethodDelete, "/api/v1/namespaces/shop", false},
+		{http.MethodPatch, "/api/v1/namespaces/shop", false},
+		{http.MethodPut, "/api/v1/namespaces/shop/finalize", false},
+		{http.MethodDelete, "/api/v1/namespaces/shop/pods/web-1", true},
+	} {
+		err := nsOnly.check(httptest.NewRequest(tc.method, "/k8s"+tc.path, nil), tc.path)
+		if (err == nil) != tc.allowed {
+			t.Errorf("namespaces only: %s %s: err = %v, want allowed %v", tc.method, tc.path, err, tc.allowed)
+		}
+	}
+
+	if err := (ProxyScope{}).check(httptest.NewRequest(http.MethodDelete, "/k8s/api/v1/nodes/n1", nil), "/api/v1/nodes/n1"); err != nil {
+		t.Errorf("zero scope refused a request: %v", err)
+	}
+}
+
+func TestProxyScopeValidate(t *testing.T) {
+	if err := (ProxyScope{Namespaces: []string{"shop"}, DeniedVerbs: []string{"delete"}, Resources: []string{"pods/*", "ingresses.networking.k8s.io"}}).Validate(); err != nil {
+		t.Errorf("valid scope: %v", err)
+	}
+	for _, bad := range []ProxyScope{
+		{Namespaces: []string{""}},
+		{DeniedVerbs: []string{"exec"}},
+		{Resources: []string{"/log"}},
+		{Resources: []string{".apps"}},
+		{Resources: []string{"pods/log/x"}},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("%+v validated, want an error", bad)
+		}
+	}
+}
+
+// TestK8sHandlerEnforcesScope checks that a refused request never reaches
+// the API server and that kubectl gets a Forbidden Status.
+func TestK8sHandlerEnforcesScope(t *testing.T) {
+	var reached atomic.Int32
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached.Add(1)
+		_, _ = io.WriteString(w, r.URL.Path)
+	}))
+	defer apiServer.Close()
+
+	handler := k8sHandler(&rest.Config{Host: apiServer.URL}, ProxyScope{Namespaces: []string{"shop"}}, nil)
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/k8s/api/v1/namespaces/kube-system/secrets", nil))
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("code = %d, want 403", rec.Code)
+	}
+	var status metav1.Status
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("decoding Status: %v", err)
+	}
+	if status.Kind != "Status" || status.Reason != metav1.StatusReasonForbidden || !strings.Contains(status.Message, `namespace "kube-system"`) {
+		t.Errorf("status = %+v", status)
+	}
+	if reached.Load() != 0 {
+		t.Error("refused request reached the API server")
+	}
+
+	rec = httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/k8s/api/v1/namespaces/shop/pods", nil))
+	if rec.Code != http.StatusOK || reached.Load() != 1 {
+		t.Errorf("allowed request: code = %d, reached = %d", rec.Code, reached.Load())
+	}
+}
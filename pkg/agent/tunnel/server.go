@@ -18,6 +18,7 @@ limitations under the License.
 package tunnel
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"io"
@@ -30,17 +31,21 @@ import (
 	"github.com/gorilla/mux"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
+
+	"github.com/faroshq/faros-kedge/pkg/util/httpcompress"
 )
 
 // newRemoteServer creates the local HTTP server that is served on the revdial.Listener.
 // It handles requests from the hub that are tunneled back to the agent.
-func newRemoteServer(downstream *rest.Config, sshPort int) (*http.Server, error) {
-	router := setupRouter(downstream, sshPort)
+// scope restricts what the hub may reach through the k8s proxy; reads, if
+// non-nil, serves cacheable k8s reads.
+func newRemoteServer(downstream *rest.Config, e2eTLS *EndToEndTLS, scope ProxyScope, reads *readCache, sshPort int) (*http.Server, error) {
+	router := setupRouter(downstream, e2eTLS, scope, reads, sshPort)
 	return &http.Server{Handler: router}, nil
 }
 
 // setupRouter configures the mux router for the local server.
-func setupRouter(downstream *rest.Config, sshPort int) *mux.Router {
+func setupRouter(downstream *rest.Config, e2eTLS *EndToEndTLS, scope ProxyScope, reads *readCache, sshPort int) *mux.Router {
 	router := mux.NewRouter()
 
 	// SSH handler — proxies the revdial connection to the host sshd on sshPort.
@@ -58,9 +63,17 @@ func setupRouter(downstream *rest.Config, sshPort int) *mux.Router {
 
 	// K8s proxy handler — only registered when a downstream k8s config is present.
 	// In server mode (downstream == nil) k8s proxying is not available.
-	if downstream != nil {
-		router.PathPrefix("/k8s/").HandlerFunc(k8sHandler(downstream))
-	} else {
+	// With end-to-end TLS the plaintext path is refused: API traffic must use
+	// /k8s-tls, where the agent terminates the client's TLS itself.
+	switch {
+	case downstream != nil && e2eTLS != nil:
+		router.HandleFunc("/k8s-tls", newK8sTLSHandler(downstream, e2eTLS, scope)).Methods("GET")
+		router.PathPrefix("/k8s/").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "end-to-end TLS is required by this edge; connect through the k8s-tls subresource", http.StatusForbidden)
+		})
+	case downstream != nil:
+		router.PathPrefix("/k8s/").HandlerFunc(k8sHandler(downstream, scope, reads))
+	default:
 		router.PathPrefix("/k8s/").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "k8s proxy not available in server mode", http.StatusServiceUnavailable)
 		})
@@ -145,20 +158,35 @@ func newSSHHandler(sshPort int) http.HandlerFunc {
 }
 
 // k8sHandler creates an HTTP handler that proxies requests to the local Kubernetes API.
-func k8sHandler(config *rest.Config) http.HandlerFunc {
+// Requests outside scope are refused; reads reads can serve do not reach it.
+func k8sHandler(config *rest.Config, scope ProxyScope, reads *readCache) http.HandlerFunc {
+	auth, authErr := newDownstreamAuth(config)
 	return func(w http.ResponseWriter, r *http.Request) {
 		logger := klog.Background().WithName("k8s-handler")
 		logger.Info("K8s API request received", "path", r.URL.Path)
+		if authErr != nil {
+			logger.Error(authErr, "failed to set up downstream credentials")
+			http.Error(w, "downstream credentials error", http.StatusInternalServerError)
+			return
+		}
 
 		// Strip the /k8s prefix
 		k8sPath := strings.TrimPrefix(r.URL.Path, "/k8s")
 		if k8sPath == "" {
 			k8sPath = "/"
 		}
+		if err := scope.check(r, k8sPath); err != nil {
+			logger.Info("K8s API request refused by the proxy scope", "path", k8sPath, "err", err.Error())
+			writeStatusError(w, err)
+			return
+		}
 
 		// Check if this is an upgrade request (exec, port-forward)
 		if isUpgradeRequest(r) {
-			handleK8sUpgrade(w, r, config, k8sPath)
+			handleK8sUpgrade(w, r, config, auth, k8sPath)
+			return
+		}
+		if reads.serve(w, r, k8sPath) {
 			return
 		}
 
@@ -181,25 +209,33 @@ func k8sHandler(config *rest.Config) http.HandlerFunc {
 			tlsConfig = &tls.Config{} //nolint:gosec
 		}
 
+		// Authenticate every tunneled request with the agent's credential.
+		// NOTE: All requests tunneled to the downstream cluster run at
+		// agent-SA privilege level. Per-user RBAC differentiation in the
+		// downstream cluster is not yet supported. Future improvement:
+		// support impersonation headers if the downstream cluster supports it.
+		transport, err := auth.wrap(&http.Transport{
+			TLSClientConfig: tlsConfig,
+			// Dial is set when the API server is behind a bastion.
+			DialContext: config.Dial,
+		})
+		if err != nil {
+			logger.Error(err, "failed to set up downstream credentials")
+			http.Error(w, "downstream credentials error", http.StatusInternalServerError)
+			return
+		}
+
 		// Create reverse proxy using Rewrite only (Director and Rewrite are mutually exclusive).
 		proxy := &httputil.ReverseProxy{
-			Transport: &http.Transport{
-				TLSClientConfig: tlsConfig,
-			},
+			// Responses cross the tunnel and the hub as they leave here, so
+			// compress them for clients that accept it.
+			Transport: httpcompress.NewTransport(transport),
 			Rewrite: func(pr *httputil.ProxyRequest) {
 				pr.Out.URL.Scheme = target.Scheme
 				pr.Out.URL.Host = target.Host
 				pr.Out.URL.Path = k8sPath
 				pr.Out.Host = target.Host
-
-				// Inject the agent ServiceAccount bearer token for all tunneled requests.
-				// NOTE: All requests tunneled to the downstream cluster run at
-				// agent-SA privilege level. Per-user RBAC differentiation in the
-				// downstream cluster is not yet supported. Future improvement:
-				// support impersonation headers if the downstream cluster supports it.
-				if config.BearerToken != "" {
-					pr.Out.Header.Set("Authorization", "Bearer "+config.BearerToken)
-				}
+				stripCredentials(pr.Out.Header)
 			},
 		}
 
@@ -208,7 +244,7 @@ func k8sHandler(config *rest.Config) http.HandlerFunc {
 }
 
 // handleK8sUpgrade handles protocol upgrade requests (exec, port-forward).
-func handleK8sUpgrade(w http.ResponseWriter, r *http.Request, config *rest.Config, k8sPath string) {
+func handleK8sUpgrade(w http.ResponseWriter, r *http.Request, config *rest.Config, auth *downstreamAuth, k8sPath string) {
 	logger := klog.Background().WithName("k8s-upgrade")
 
 	target, err := url.Parse(config.Host)
@@ -218,6 +254,17 @@ func handleK8sUpgrade(w http.ResponseWriter, r *http.Request, config *rest.Confi
 		return
 	}
 
+	// Inject the agent's credential for all tunneled requests.
+	// NOTE: All requests tunneled to the downstream cluster run at agent-SA
+	// privilege level. Per-user RBAC differentiation in the downstream cluster
+	// is not yet supported. Future improvement: support impersonation headers
+	// if the downstream cluster supports it.
+	if err := auth.apply(r.Context(), r); err != nil {
+		logger.Error(err, "failed to get downstream credentials")
+		http.Error(w, "downstream credentials error", http.StatusBadGateway)
+		return
+	}
+
 	// Dial the K8s API server
 	tlsConfig, err := rest.TLSConfigFor(config)
 	if err != nil {
@@ -225,13 +272,12 @@ func handleK8sUpgrade(w http.ResponseWriter, r *http.Request, config *rest.Confi
 		http.Error(w, "TLS config error", http.StatusInternalServerError)
 		return
 	}
-
-	var backendConn net.Conn
-	if tlsConfig != nil {
-		backendConn, err = tls.Dial("tcp", target.Host, tlsConfig)
-	} else {
-		backendConn, err = net.Dial("tcp", target.Host)
+	if target.Scheme == "http" {
+		// An exec plugin yields a TLS config even for a plain-HTTP server.
+		tlsConfig = nil
 	}
+
+	backendConn, err := dialK8s(r.Context(), config, tlsConfig, target.Host)
 	if err != nil {
 		logger.Error(err, "failed to connect to K8s API")
 		http.Error(w, "upstream error", http.StatusBadGateway)
@@ -255,14 +301,6 @@ func handleK8sUpgrade(w http.ResponseWriter, r *http.Request, config *rest.Confi
 	r.URL.Path = k8sPath
 	r.URL.Host = target.Host
 	r.URL.Scheme = target.Scheme
-	// Inject the agent ServiceAccount bearer token for all tunneled requests.
-	// NOTE: All requests tunneled to the downstream cluster run at agent-SA
-	// privilege level. Per-user RBAC differentiation in the downstream cluster
-	// is not yet supported. Future improvement: support impersonation headers
-	// if the downstream cluster supports it.
-	if config.BearerToken != "" {
-		r.Header.Set("Authorization", "Bearer "+config.BearerToken)
-	}
 
 	if err := r.Write(backendConn); err != nil {
 		logger.Error(err, "failed to write request to backend")
@@ -282,6 +320,29 @@ func handleK8sUpgrade(w http.ResponseWriter, r *http.Request, config *rest.Confi
 	<-errc
 }
 
+// dialK8s connects to the API server at host, through config.Dial when the
+// API server is behind a bastion, with TLS unless tlsConfig is nil.
+func dialK8s(ctx context.Context, config *rest.Config, tlsConfig *tls.Config, host string) (net.Conn, error) {
+	dial := config.Dial
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	conn, err := dial(ctx, "tcp", host)
+	if err != nil || tlsConfig == nil {
+		return conn, err
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig = tlsConfig.Clone()
+		tlsConfig.ServerName, _, _ = net.SplitHostPort(host)
+	}
+	tlsConn := tls.Client(conn, tlsConfig)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return tlsConn, nil
+}
+
 // isUpgradeRequest checks if the request wants a protocol upgrade.
 func isUpgradeRequest(r *http.Request) bool {
 	return strings.EqualFold(r.Header.Get("Connection"), "Upgrade")
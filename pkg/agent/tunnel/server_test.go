@@ -0,0 +1,137 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+// TestK8sHandlerUsesConfigDial checks that proxied API traffic goes through
+// the downstream config's dialer, as it must when the API server is only
+// reachable through a bastion.
+func TestK8sHandlerUsesConfigDial(t *testing.T) {
+	apiServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.URL.Path)
+	}))
+	defer apiServer.Close()
+
+	var dials atomic.Int32
+	config := &rest.Config{
+		// Not resolvable: only the dialer knows where the API server is.
+		Host:            "https://api.behind-bastion.invalid:6443",
+		TLSClientConfig: rest.TLSClientConfig{Insecure: true},
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			dials.Add(1)
+			var d net.Dialer
+			return d.DialContext(ctx, network, apiServer.Listener.Addr().String())
+		},
+	}
+
+	rec := httptest.NewRecorder()
+	k8sHandler(config, ProxyScope{}, nil)(rec, httptest.NewRequest(http.MethodGet, "/k8s/api/v1/pods", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "/api/v1/pods" {
+		t.Fatalf("response = %d %q, want 200 /api/v1/pods", rec.Code, rec.Body.String())
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: true} //nolint:gosec // test server certificate
+	conn, err := dialK8s(context.Background(), config, tlsConfig, "api.behind-bastion.invalid:6443")
+	if err != nil {
+		t.Fatalf("dialK8s: %v", err)
+	}
+	_ = conn.Close()
+	if dials.Load() != 2 {
+		t.Errorf("config.Dial called %d times, want 2", dials.Load())
+	}
+}
+
+// TestDrainWaitsForInflight checks that shutdown stops accepting connections
+// but lets a request already being served finish.
+func TestDrainWaitsForInflight(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	var inflight sync.WaitGroup
+	server := &http.Server{Handler: trackInflight(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		_, _ = io.WriteString(w, "done")
+	}), &inflight)}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = server.Serve(ln) }()
+
+	type result struct {
+		body string
+		err  error
+	}
+	resc := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String())
+		if err != nil {
+			resc <- result{err: err}
+			return
+		}
+		defer resp.Body.Close() //nolint:errcheck
+		b, err := io.ReadAll(resp.Body)
+		resc <- result{string(b), err}
+	}()
+	<-started
+
+	drained := make(chan struct{})
+	go func() {
+		drain(context.Background(), server, &inflight, time.Minute)
+		close(drained)
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		if err != nil {
+			break
+		}
+		_ = conn.Close()
+		if time.Now().After(deadline) {
+			t.Fatal("listener still accepting after drain began")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	select {
+	case <-drained:
+		t.Fatal("drain returned with a request in flight")
+	default:
+	}
+
+	close(release)
+	if res := <-resc; res.err != nil || res.body != "done" {
+		t.Fatalf("in-flight request = %q, %v; want it to finish", res.body, res.err)
+	}
+	select {
+	case <-drained:
+	case <-time.After(5 * time.Second):
+		t.Fatal("drain did not return once the request finished")
+	}
+}
@@ -22,27 +22,37 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"math"
+	"io"
 	"net"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/function61/holepunch-server/pkg/wsconnadapter"
 	"github.com/gorilla/websocket"
 
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 
 	"github.com/faroshq/provider-sdk/revdial"
 
+	"github.com/faroshq/faros-kedge/pkg/agent/health"
 	"github.com/faroshq/faros-kedge/pkg/apiurl"
+	pkgversion "github.com/faroshq/faros-kedge/pkg/version"
 )
 
+// healthSubsystem names the tunnel in the agent's health tracker.
+const healthSubsystem = "tunnel"
+
+// agentVersionHeader carries the agent's build version on the tunnel upgrade
+// request; the hub records it and checks it against its skew policy.
+const agentVersionHeader = "X-Kedge-Agent-Version"
+
 // StartProxyTunnel establishes a reverse tunnel to the hub server.
-// It runs an exponential backoff retry loop to maintain the connection.
+// It redials whenever the connection is lost, waiting between attempts as
+// reconnect directs (see Reconnect).
 // tlsConfig controls TLS verification for the WebSocket connection to the hub.
 // Pass nil to use a default (secure) TLS config; use InsecureSkipVerify only
 // in development environments.
@@ -52,6 +62,12 @@ import (
 // agent dials on the single `edges` provider (kubernetesclusters vs
 // linuxservers) via apiurl.ProviderAgentProxyURL.
 //
+// e2eTLS, if non-nil, enables end-to-end TLS to the downstream Kubernetes API
+// (see EndToEndTLS); it is ignored when downstream is nil.
+//
+// scope restricts what the hub may reach through the downstream Kubernetes
+// API (see ProxyScope); the zero value restricts nothing.
+//
 // cluster is the kcp logical cluster path (e.g., "root:kedge:user-default").
 // If empty, it's extracted from the token (for SA tokens) or defaults to "default".
 //
@@ -64,18 +80,44 @@ import (
 // bearer token. Callers should return the SA token from the saved kubeconfig
 // after token-exchange has succeeded, otherwise the join token is rejected on
 // reconnect once the hub has cleared edge.Status.JoinToken.
-func StartProxyTunnel(ctx context.Context, hubURL string, getToken func() string, edgeName string, resourceType string, downstream *rest.Config, tlsConfig *tls.Config, stateChannel chan bool, sshPort int, cluster string, onAgentToken func(string), extraHeaders http.Header) {
+//
+// tracker, if non-nil, records each failed attempt and is cleared once a
+// connection is established.
+//
+// keepalive tunes dead-peer detection on the tunnel; its TCPUserTimeout is
+// applied to the control and pick-up connections to the hub.
+//
+// proxy, if non-nil, selects the proxy for every connection to the hub (see
+// ProxyFunc); the websocket upgrades go through it with CONNECT.
+//
+// reconnect spaces out the attempts after a lost or failed connection; zero
+// fields take DefaultReconnect's values.
+//
+// meter, if non-nil, limits the bandwidth of the sessions the hub opens and
+// accounts for their traffic.
+//
+// readCacheTTL, if non-zero, serves common reads of the downstream cluster
+// from informers kept for readCacheTTL after their last read (see readCache).
+//
+// When ctx is cancelled the tunnel stops accepting new dials from the hub and
+// waits up to shutdownGrace for in-flight requests and streams (kubectl exec,
+// ssh) to finish before it reports itself disconnected on stateChannel and
+// returns.
+func StartProxyTunnel(ctx context.Context, hubURL string, getToken func() string, edgeName string, resourceType string, downstream *rest.Config, e2eTLS *EndToEndTLS, scope ProxyScope, tlsConfig *tls.Config, proxy func(*http.Request) (*url.URL, error), stateChannel chan bool, sshPort int, cluster string, onAgentToken func(string), extraHeaders http.Header, tracker *health.Tracker, keepalive revdial.Keepalive, reconnect Reconnect, meter *TrafficMeter, readCacheTTL time.Duration, shutdownGrace time.Duration) {
 	logger := klog.FromContext(ctx)
 	logger.Info("Starting proxy tunnel", "hubURL", hubURL, "edgeName", edgeName, "resourceType", resourceType)
 
-	backoff := wait.Backoff{
-		Duration: 1 * time.Second,
-		Factor:   2.0,
-		Jitter:   0.1,
-		Steps:    math.MaxInt32,
-		Cap:      30 * time.Second,
+	// The read cache outlives reconnects, so a flapping tunnel does not
+	// relist the edge.
+	reads, err := newReadCache(ctx, downstream, readCacheTTL)
+	if err != nil {
+		logger.Error(err, "read cache disabled")
 	}
 
+	backoff := newReconnectBackoff(reconnect)
+	defer tunnelConnected.DeleteLabelValues(edgeName)
+	defer tunnelReconnectDelay.DeleteLabelValues(edgeName)
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -83,17 +125,30 @@ func StartProxyTunnel(ctx context.Context, hubURL string, getToken func() string
 		default:
 		}
 
-		err := startTunneler(ctx, hubURL, getToken, edgeName, resourceType, downstream, tlsConfig, stateChannel, sshPort, cluster, onAgentToken, extraHeaders)
+		connectedAt, err := startTunneler(ctx, hubURL, getToken, edgeName, resourceType, downstream, e2eTLS, scope, reads, tlsConfig, proxy, stateChannel, sshPort, cluster, onAgentToken, extraHeaders, tracker, keepalive, meter, shutdownGrace)
+		if connectedAt.IsZero() {
+			tunnelConnectAttempts.WithLabelValues(edgeName, "failure").Inc()
+		} else {
+			backoff.connected(time.Since(connectedAt))
+		}
 		if err != nil {
 			logger.Error(err, "tunnel connection failed, reconnecting")
+			tracker.Observe(healthSubsystem, err)
 		}
 
 		sendTunnelState(stateChannel, false)
+		tracker.SetTunnelConnected(false)
+		tunnelConnected.WithLabelValues(edgeName).Set(0)
 
+		wait := backoff.next()
+		tunnelReconnectDelay.WithLabelValues(edgeName).Set(wait.Seconds())
+		if ctx.Err() == nil {
+			logger.V(2).Info("Reconnecting tunnel", "after", wait)
+		}
 		select {
 		case <-ctx.Done():
 			return
-		case <-time.After(backoff.Step()):
+		case <-time.After(wait):
 		}
 	}
 }
@@ -122,7 +177,10 @@ func sendTunnelState(c chan bool, v bool) {
 	}
 }
 
-func startTunneler(ctx context.Context, hubURL string, getToken func() string, edgeName string, resourceType string, downstream *rest.Config, tlsConfig *tls.Config, stateChannel chan bool, sshPort int, cluster string, onAgentToken func(string), extraHeaders http.Header) error {
+// startTunneler opens one tunnel and serves it until it drops or ctx is
+// cancelled. It returns when the connection was established, zero if it
+// never was.
+func startTunneler(ctx context.Context, hubURL string, getToken func() string, edgeName string, resourceType string, downstream *rest.Config, e2eTLS *EndToEndTLS, scope ProxyScope, reads *readCache, tlsConfig *tls.Config, proxy func(*http.Request) (*url.URL, error), stateChannel chan bool, sshPort int, cluster string, onAgentToken func(string), extraHeaders http.Header, tracker *health.Tracker, keepalive revdial.Keepalive, meter *TrafficMeter, shutdownGrace time.Duration) (time.Time, error) {
 	logger := klog.FromContext(ctx)
 
 	// Resolve the current bearer token for this connect attempt. After
@@ -135,29 +193,16 @@ func startTunneler(ctx context.Context, hubURL string, getToken func() string, e
 	}
 
 	// Connect to hub's tunnel endpoint.
-	// Determine the kcp cluster name and the base hub URL (without /clusters/ path).
-	// Priority: explicit cluster arg > URL-embedded cluster > SA-token claim.
-	baseHubURL, urlCluster := SplitBaseAndCluster(hubURL)
-	clusterName := cluster
-	if clusterName == "" {
-		clusterName = urlCluster
-	}
-	if clusterName == "default" {
-		// Last-resort fallback: extract from SA token JWT claim (works for
-		// kubeconfig-based auth where the bearer token is a kcp ServiceAccount).
-		if sa := extractClusterNameFromToken(token); sa != "default" {
-			clusterName = sa
-		}
-	}
+	baseHubURL, clusterName := tunnelCluster(hubURL, cluster, token)
 
 	// The agent dials the single `edges` provider's agent-ingress path, choosing
 	// the resource (kubernetesclusters vs linuxservers) by type, routed through
 	// the hub backend proxy. resourceType is the agent type ("kubernetes" | "server").
 	edgeProxyURL := apiurl.ProviderAgentProxyURL(baseHubURL, resourceType, clusterName, edgeName, "proxy")
 
-	conn, resp, err := initiateConnection(ctx, edgeProxyURL, token, tlsConfig, extraHeaders)
+	conn, resp, err := initiateConnection(ctx, edgeProxyURL, token, tlsConfig, proxy, extraHeaders, keepalive.TCPUserTimeout)
 	if err != nil {
-		return fmt.Errorf("failed to initiate connection: %w", err)
+		return time.Time{}, fmt.Errorf("failed to initiate connection: %w", err)
 	}
 
 	// Token-exchange flow: if the hub returned an agent kubeconfig in the
@@ -171,18 +216,26 @@ func startTunneler(ctx context.Context, hubURL string, getToken func() string, e
 	}
 
 	logger.Info("Tunnel connection established")
+	connectedAt := time.Now()
 	sendTunnelState(stateChannel, true)
+	tracker.SetTunnelConnected(true)
+	tracker.Observe(healthSubsystem, nil)
+	tunnelConnectAttempts.WithLabelValues(edgeName, "success").Inc()
+	tunnelConnected.WithLabelValues(edgeName).Set(1)
 
 	// Create revdial listener. Pass the token-provider through so each new
-	// sub-connection picked up over the tunnel uses the freshest token.
-	ln := revdial.NewListener(conn, revdialFunc(hubURL, getToken, tlsConfig))
+	// sub-connection picked up over the tunnel uses the freshest token. The
+	// meter limits and counts the traffic of every session.
+	ln := meter.listener(revdial.NewListenerWithKeepalive(conn, revdialFunc(hubURL, getToken, tlsConfig, proxy, keepalive.TCPUserTimeout), keepalive))
 	defer ln.Close() //nolint:errcheck
 
 	// Create and serve local HTTP server
-	server, err := newRemoteServer(downstream, sshPort)
+	server, err := newRemoteServer(downstream, e2eTLS, scope, reads, sshPort)
 	if err != nil {
-		return fmt.Errorf("failed to create remote server: %w", err)
+		return connectedAt, fmt.Errorf("failed to create remote server: %w", err)
 	}
+	var inflight sync.WaitGroup
+	server.Handler = trackInflight(server.Handler, &inflight)
 
 	// Serve on the revdial listener
 	errCh := make(chan error, 1)
@@ -192,17 +245,54 @@ func startTunneler(ctx context.Context, hubURL string, getToken func() string, e
 
 	select {
 	case <-ctx.Done():
-		_ = server.Shutdown(context.Background())
-		return nil
+		drain(ctx, server, &inflight, shutdownGrace)
+		return connectedAt, nil
 	case err := <-errCh:
-		return err
+		return connectedAt, err
+	}
+}
+
+// trackInflight counts the requests next is serving in wg. Upgraded streams
+// are hijacked and so invisible to http.Server.Shutdown, but their handlers
+// return only once the stream ends.
+func trackInflight(next http.Handler, wg *sync.WaitGroup) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wg.Add(1)
+		defer wg.Done()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// drain closes the revdial listener, so the hub can dial no new sessions,
+// and waits up to grace for the requests and streams in inflight to finish.
+// Those still open after grace are cut when the agent exits.
+func drain(ctx context.Context, server *http.Server, inflight *sync.WaitGroup, grace time.Duration) {
+	logger := klog.FromContext(ctx)
+	drainCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), grace)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		inflight.Wait()
+		close(done)
+	}()
+	logger.Info("Draining tunnel sessions", "grace", grace)
+	_ = server.Shutdown(drainCtx)
+	select {
+	case <-done:
+		logger.Info("Tunnel sessions drained")
+	case <-drainCtx.Done():
+		logger.Info("Tunnel sessions still open after the shutdown grace period; closing them", "grace", grace)
+		_ = server.Close()
 	}
 }
 
 // initiateConnection dials the hub via WebSocket and returns the underlying
 // net.Conn together with the HTTP upgrade response. The response headers may
 // contain hub-provided metadata such as X-Kedge-Agent-Token (token-exchange).
-func initiateConnection(ctx context.Context, wsURL string, token string, tlsConfig *tls.Config, extraHeaders http.Header) (net.Conn, *http.Response, error) {
+// A non-zero tcpUserTimeout is set as TCP_USER_TIMEOUT on the dialed socket,
+// which is the proxy's when proxy selects one.
+func initiateConnection(ctx context.Context, wsURL string, token string, tlsConfig *tls.Config, proxy func(*http.Request) (*url.URL, error), extraHeaders http.Header, tcpUserTimeout time.Duration) (net.Conn, *http.Response, error) {
 	u, err := url.Parse(wsURL)
 	if err != nil {
 		return nil, nil, err
@@ -219,9 +309,12 @@ func initiateConnection(ctx context.Context, wsURL string, token string, tlsConf
 	dialer := websocket.Dialer{
 		TLSClientConfig:  tlsConfig,
 		HandshakeTimeout: 30 * time.Second,
+		Proxy:            proxy,
+		NetDialContext:   netDialer(tcpUserTimeout).DialContext,
 	}
 
 	header := http.Header{}
+	header.Set(agentVersionHeader, pkgversion.Get())
 	if token != "" {
 		header.Set("Authorization", "Bearer "+token)
 	}
@@ -233,12 +326,31 @@ func initiateConnection(ctx context.Context, wsURL string, token string, tlsConf
 
 	wsConn, resp, err := dialer.DialContext(ctx, u.String(), header)
 	if err != nil {
+		if resp != nil {
+			// A refused upgrade: keep the status so 401/403 are told apart
+			// from a flaky network, and the hub's reason, e.g. for an
+			// unsupported agent version.
+			if msg := refusalMessage(resp); msg != "" {
+				err = fmt.Errorf("%w: %s", err, msg)
+			}
+			err = &health.HTTPStatusError{Code: resp.StatusCode, Err: err}
+		}
 		return nil, nil, fmt.Errorf("WebSocket dial failed: %w", err)
 	}
 
 	return wsconnadapter.New(wsConn), resp, nil
 }
 
+// refusalMessage returns the first line of the body of a refused upgrade.
+func refusalMessage(resp *http.Response) string {
+	if resp.Body == nil {
+		return ""
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	msg, _, _ := strings.Cut(strings.TrimSpace(string(body)), "\n")
+	return msg
+}
+
 // SplitBaseAndCluster splits a hub URL into the base URL (scheme+host only) and
 // the kcp cluster name embedded in the path.
 //
@@ -260,6 +372,25 @@ func SplitBaseAndCluster(hubURL string) (base, cluster string) {
 	return apiurl.SplitBaseAndCluster(hubURL)
 }
 
+// tunnelCluster determines the base hub URL (without /clusters/ path) and
+// the kcp cluster name of the edge. Priority: explicit cluster arg >
+// URL-embedded cluster > SA-token claim.
+func tunnelCluster(hubURL, cluster, token string) (base, clusterName string) {
+	base, urlCluster := SplitBaseAndCluster(hubURL)
+	clusterName = cluster
+	if clusterName == "" {
+		clusterName = urlCluster
+	}
+	if clusterName == "default" {
+		// Last-resort fallback: extract from SA token JWT claim (works for
+		// kubeconfig-based auth where the bearer token is a kcp ServiceAccount).
+		if sa := extractClusterNameFromToken(token); sa != "default" {
+			clusterName = sa
+		}
+	}
+	return base, clusterName
+}
+
 // extractClusterNameFromToken decodes a kcp ServiceAccount JWT (without
 // signature verification) and returns the clusterName claim. Returns "default"
 // if the token cannot be parsed or lacks the claim.
@@ -288,7 +419,7 @@ func extractClusterNameFromToken(token string) string {
 // pick up new connections from the hub. getToken is invoked on every dial so
 // pick-up connections track the latest bearer token (e.g. the SA token issued
 // via token-exchange) rather than the original join token.
-func revdialFunc(baseURL string, getToken func() string, tlsConfig *tls.Config) func(context.Context, string) (*websocket.Conn, *http.Response, error) {
+func revdialFunc(baseURL string, getToken func() string, tlsConfig *tls.Config, proxy func(*http.Request) (*url.URL, error), tcpUserTimeout time.Duration) func(context.Context, string) (*websocket.Conn, *http.Response, error) {
 	return func(ctx context.Context, path string) (*websocket.Conn, *http.Response, error) {
 		u, err := url.Parse(baseURL)
 		if err != nil {
@@ -314,6 +445,8 @@ func revdialFunc(baseURL string, getToken func() string, tlsConfig *tls.Config)
 		dialer := websocket.Dialer{
 			TLSClientConfig:  tlsConfig,
 			HandshakeTimeout: 30 * time.Second,
+			Proxy:            proxy,
+			NetDialContext:   netDialer(tcpUserTimeout).DialContext,
 		}
 
 		header := http.Header{}
@@ -328,3 +461,13 @@ func revdialFunc(baseURL string, getToken func() string, tlsConfig *tls.Config)
 		return dialer.DialContext(ctx, u.String(), header)
 	}
 }
+
+// netDialer returns the dialer for connections to the hub, setting
+// TCP_USER_TIMEOUT when tcpUserTimeout is non-zero.
+func netDialer(tcpUserTimeout time.Duration) *net.Dialer {
+	return &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   revdial.TCPUserTimeoutControl(tcpUserTimeout),
+	}
+}
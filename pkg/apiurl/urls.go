@@ -44,6 +44,15 @@ const (
 	PathAuthTokenLogin       = "/auth/token-login"
 	PathHealthz              = "/healthz"
 	PathVersion              = "/version"
+	// PathSchedulerPreview is the dry-run placement preview, served by the
+	// edges provider's scheduler through the provider proxy.
+	PathSchedulerPreview = "/services/scheduler/preview"
+	// PathAdminMigrate starts (POST) and reports (GET) a kcp schema
+	// migration on the hub's platform-admin surface.
+	PathAdminMigrate = "/api/admin/migrate"
+	// PathAdminTenants prefixes the per-user tenant export and deletion
+	// endpoints: {user}/export, {user} (DELETE) and {user}/deletion.
+	PathAdminTenants = "/api/admin/tenants"
 )
 
 // SplitBaseAndCluster splits a URL that contains a /clusters/<name> path into
@@ -143,6 +152,15 @@ func ProviderAgentProxyURL(hubBase, edgeType, cluster, edgeName, subresource str
 		ProviderAgentProxyPath(provider, group, resource, cluster, edgeName, subresource)
 }
 
+// ProviderManifestsURL returns the URL an agent fetches the manifest bundle a
+// Placement references from, in the edges provider's manifest store.
+//
+// Pattern: /services/providers/edges/manifests/{cluster}/{namespace}/{placement}/{digest}
+func ProviderManifestsURL(hubBase, cluster, namespace, placement, digest string) string {
+	return fmt.Sprintf("%s%s/edges/manifests/%s/%s/%s/%s",
+		strings.TrimRight(hubBase, "/"), PathPrefixProvidersProxy, cluster, namespace, placement, digest)
+}
+
 // EdgeProxyPath returns the URL path (relative to the hub base) for the
 // edges-proxy virtual workspace endpoint.
 //
@@ -176,6 +194,22 @@ func EdgeServiceProxyURL(hubBase, cluster, name, subresource string) string {
 	return strings.TrimRight(hubBase, "/") + EdgeServiceProxyPath(cluster, name, subresource)
 }
 
+// EdgeFleetCommandPath returns the path of a subresource the edges provider
+// serves on a FleetCommand, routed like EdgeServiceProxyPath.
+//
+// subresource is "requester" (register the caller as the run's requester).
+//
+// Pattern: /services/providers/edges/edgeproxy/clusters/{cluster}/apis/edges.kedge.faros.sh/v1alpha1/fleetcommands/{name}/{subresource}
+func EdgeFleetCommandPath(cluster, name, subresource string) string {
+	return fmt.Sprintf("%s/edges/edgeproxy/clusters/%s/apis/edges.kedge.faros.sh/v1alpha1/fleetcommands/%s/%s",
+		PathPrefixProvidersProxy, cluster, name, subresource)
+}
+
+// EdgeFleetCommandURL returns the full FleetCommand subresource URL.
+func EdgeFleetCommandURL(hubBase, cluster, name, subresource string) string {
+	return strings.TrimRight(hubBase, "/") + EdgeFleetCommandPath(cluster, name, subresource)
+}
+
 // KubernetesMCPPath / KubernetesMCPURL / LinuxMCPPath / LinuxMCPURL
 // were removed when the dedicated per-kind MCP endpoints collapsed
 // into the MCPServer aggregate. Use MCPServerURL below for the single
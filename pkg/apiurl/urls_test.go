@@ -145,6 +145,14 @@ func TestHubServerURL(t *testing.T) {
 	}
 }
 
+func TestProviderManifestsURL(t *testing.T) {
+	got := ProviderManifestsURL("https://hub:9443/", "root:kedge:user-default", "default", "web-edge-1", "sha256:0123")
+	want := "https://hub:9443/services/providers/edges/manifests/root:kedge:user-default/default/web-edge-1/sha256:0123"
+	if got != want {
+		t.Errorf("ProviderManifestsURL = %q, want %q", got, want)
+	}
+}
+
 func TestEdgeAgentProxyPath(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -0,0 +1,309 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/faroshq/faros-kedge/pkg/apiurl"
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
+)
+
+// migrationPollInterval is how often `kedge admin migrate` polls progress.
+const migrationPollInterval = 2 * time.Second
+
+// migrationView mirrors migrate.Status, the /api/admin/migrate response.
+type migrationView struct {
+	Phase      string                   `json:"phase"`
+	DryRun     bool                     `json:"dryRun"`
+	StartedAt  time.Time                `json:"startedAt"`
+	FinishedAt *time.Time               `json:"finishedAt,omitempty"`
+	Message    string                   `json:"message,omitempty"`
+	Workspaces []migrationWorkspaceView `json:"workspaces"`
+}
+
+// migrationWorkspaceView mirrors migrate.WorkspaceStatus.
+type migrationWorkspaceView struct {
+	Path      string `json:"path"`
+	Phase     string `json:"phase"`
+	Objects   int    `json:"objects"`
+	Migrated  int    `json:"migrated"`
+	Converted int    `json:"converted"`
+	Error     string `json:"error,omitempty"`
+}
+
+func (m *migrationView) done() bool {
+	return m.Phase == "Succeeded" || m.Phase == "Failed"
+}
+
+func newAdminCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "admin",
+		Short: "Platform-admin operations on the hub (requires --admin-users on the hub)",
+	}
+	cmd.AddCommand(newAdminMigrateCommand())
+	cmd.AddCommand(newAdminTenantCommand())
+	return cmd
+}
+
+func newAdminMigrateCommand() *cobra.Command {
+	var (
+		dryRun bool
+		noWait bool
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Apply the hub's APIResourceSchemas and migrate stored objects to them",
+		Long: `Apply the platform APIResourceSchemas shipped with the running hub, then
+rewrite every stored kedge object in the system workspaces and in each
+organization and team workspace at its schema's storage version, converting
+objects from older API versions on the way. Progress is reported per
+workspace as it completes.
+
+Run it after upgrading the hub to a release that changes an API version.
+Rewriting is idempotent, so a failed or interrupted migration can be run
+again. --dry-run counts the objects that would be migrated without applying
+schemas or writing anything.`,
+		Example: `  # Preview, then migrate
+  kedge admin migrate --dry-run
+  kedge admin migrate
+
+  # Start without waiting, check on it later
+  kedge admin migrate --no-wait
+  kedge admin migrate status`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != "" && output != "json" {
+				return fmt.Errorf("unsupported output format %q (want json)", output)
+			}
+			hub, err := newHubSession()
+			if err != nil {
+				return err
+			}
+			status, err := startMigration(cmd.Context(), hub, dryRun)
+			if err != nil {
+				return err
+			}
+			if noWait {
+				if output == "json" {
+					return printMigrationJSON(status)
+				}
+				ui.Infof(os.Stdout, "Migration started. Check on it with: kedge admin migrate status\n")
+				return nil
+			}
+			if output != "json" {
+				what := "Migrating"
+				if dryRun {
+					what = "Dry run: counting"
+				}
+				ui.Infof(os.Stdout, "%s stored objects...\n", what)
+			}
+			status, err = waitForMigration(cmd.Context(), hub, output != "json")
+			if err != nil {
+				return err
+			}
+			return reportMigration(status, output)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Count the objects that would be migrated without applying schemas or writing anything")
+	cmd.Flags().BoolVar(&noWait, "no-wait", false, "Start the migration and return without waiting for it to finish")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output format: \"json\"")
+	cmd.AddCommand(newAdminMigrateStatusCommand())
+	return cmd
+}
+
+func newAdminMigrateStatusCommand() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the progress of the current or last migration",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != "" && output != "json" {
+				return fmt.Errorf("unsupported output format %q (want json)", output)
+			}
+			hub, err := newHubSession()
+			if err != nil {
+				return err
+			}
+			var status migrationView
+			if err := doGetJSON(cmd.Context(), hub.client, hub.base+apiurl.PathAdminMigrate, "", &status); err != nil {
+				return fmt.Errorf("reading migration status: %w", err)
+			}
+			if output == "json" {
+				return printMigrationJSON(&status)
+			}
+			if !status.done() {
+				done := 0
+				for _, ws := range status.Workspaces {
+					if ws.Phase == "Succeeded" || ws.Phase == "Failed" {
+						done++
+					}
+				}
+				fmt.Printf("Migration running: %d/%d workspaces done", done, len(status.Workspaces))
+				if status.Message != "" {
+					fmt.Printf(" (%s)", status.Message)
+				}
+				fmt.Println()
+			}
+			return reportMigration(&status, "")
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output format: \"json\"")
+	return cmd
+}
+
+// startMigration asks the hub to start a migration run.
+func startMigration(ctx context.Context, hub *hubSession, dryRun bool) (*migrationView, error) {
+	body, err := json.Marshal(map[string]bool{"dryRun": dryRun})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hub.base+apiurl.PathAdminMigrate, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", hubAccept)
+	resp, err := hub.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("starting migration: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusAccepted {
+		return nil, hubError("starting migration", resp, data)
+	}
+	var out migrationView
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("decoding migration status: %w", err)
+	}
+	return &out, nil
+}
+
+// waitForMigration polls the hub until the run finishes, printing each
+// workspace as it completes when progress is set.
+func waitForMigration(ctx context.Context, hub *hubSession, progress bool) (*migrationView, error) {
+	reported := 0
+	for {
+		var status migrationView
+		if err := doGetJSON(ctx, hub.client, hub.base+apiurl.PathAdminMigrate, "", &status); err != nil {
+			return nil, fmt.Errorf("reading migration status: %w", err)
+		}
+		// Workspaces complete in order, so everything before the first
+		// unfinished one can be reported.
+		for ; reported < len(status.Workspaces); reported++ {
+			ws := status.Workspaces[reported]
+			if ws.Phase != "Succeeded" && ws.Phase != "Failed" {
+				break
+			}
+			if progress {
+				ui.Infof(os.Stdout, "[%d/%d] %s\n", reported+1, len(status.Workspaces), formatMigrationWorkspace(ws))
+			}
+		}
+		if status.done() {
+			return &status, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(migrationPollInterval):
+		}
+	}
+}
+
+// reportMigration prints the outcome of a finished run and fails the
+// command when the run failed.
+func reportMigration(status *migrationView, output string) error {
+	if output == "json" {
+		if err := printMigrationJSON(status); err != nil {
+			return err
+		}
+	} else {
+		t := migrationTable(status.Workspaces)
+		if t.Len() > 0 {
+			if err := t.Render(os.Stdout, false); err != nil {
+				return err
+			}
+		}
+		if status.done() {
+			objects, migrated := 0, 0
+			for _, ws := range status.Workspaces {
+				objects += ws.Objects
+				migrated += ws.Migrated
+			}
+			if status.DryRun {
+				fmt.Printf("Dry run: %d objects in %d workspaces would be migrated.\n", objects, len(status.Workspaces))
+			} else {
+				fmt.Printf("%d of %d objects migrated in %d workspaces.\n", migrated, objects, len(status.Workspaces))
+			}
+		}
+	}
+	if status.Phase == "Failed" {
+		return fmt.Errorf("migration failed: %s — fix the cause and run kedge admin migrate again", status.Message)
+	}
+	return nil
+}
+
+// migrationTable lists the per-workspace progress of a run.
+func migrationTable(workspaces []migrationWorkspaceView) *ui.Table {
+	t := ui.NewTable(
+		ui.Column{Header: "Workspace"},
+		ui.Column{Header: "Phase", Status: true},
+		ui.Column{Header: "Objects"},
+		ui.Column{Header: "Migrated"},
+		ui.Column{Header: "Converted"},
+		ui.Column{Header: "Error"},
+	)
+	for _, ws := range workspaces {
+		t.AddRow(ws.Path, ws.Phase, strconv.Itoa(ws.Objects), strconv.Itoa(ws.Migrated), strconv.Itoa(ws.Converted), ws.Error)
+	}
+	return t
+}
+
+// formatMigrationWorkspace renders one finished workspace as a progress line.
+func formatMigrationWorkspace(ws migrationWorkspaceView) string {
+	if ws.Phase == "Failed" {
+		return fmt.Sprintf("%s: failed: %s", ws.Path, ws.Error)
+	}
+	s := fmt.Sprintf("%s: %d objects, %d migrated", ws.Path, ws.Objects, ws.Migrated)
+	if ws.Converted > 0 {
+		s += fmt.Sprintf(", %d converted", ws.Converted)
+	}
+	return s
+}
+
+func printMigrationJSON(status *migrationView) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(status)
+}
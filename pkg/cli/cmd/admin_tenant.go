@@ -0,0 +1,344 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/faroshq/faros-kedge/pkg/apiurl"
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
+)
+
+// tenantDeletionPollInterval is how often `kedge admin tenant delete` polls
+// the deletion report.
+const tenantDeletionPollInterval = 5 * time.Second
+
+// tenantDeletionView mirrors admin.TenantDeletion.
+type tenantDeletionView struct {
+	User      string             `json:"user"`
+	Orgs      []string           `json:"orgs"`
+	Reason    string             `json:"reason,omitempty"`
+	Message   string             `json:"message,omitempty"`
+	Complete  bool               `json:"complete"`
+	Remaining []tenantObjectView `json:"remaining"`
+}
+
+// tenantObjectView mirrors admin.TenantObject.
+type tenantObjectView struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+func newAdminTenantCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tenant",
+		Short: "Export and delete everything the hub stores for a user",
+		Long: `Offboard a user: export everything kedge stores for them (data
+portability), then delete it and verify nothing is left. A tenant is a
+User and their personal organization; organizations they only belong to
+keep their data, less the user's Membership.`,
+	}
+	cmd.AddCommand(newAdminTenantExportCommand())
+	cmd.AddCommand(newAdminTenantDeleteCommand())
+	cmd.AddCommand(newAdminTenantVerifyCommand())
+	return cmd
+}
+
+func newAdminTenantExportCommand() *cobra.Command {
+	var (
+		file           string
+		includeSecrets bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export <user>",
+		Short: "Export a user's kedge objects as a tar.gz archive",
+		Long: `Write a gzipped tar of everything the hub stores for a user: the User,
+membership index, preferences and personal access tokens, their Memberships,
+and their personal organization with the contents of each of its workspaces
+(kedge objects, API bindings and ConfigMaps). Each object is a YAML file;
+manifest.json, the last entry, summarises the export.
+
+Secrets and personal access token hashes are left out unless
+--include-secrets is set.`,
+		Example: `  kedge admin tenant export alice
+  kedge admin tenant export alice -f - | tar tz`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			user := args[0]
+			if file == "" {
+				file = user + "-export.tar.gz"
+			}
+			hub, err := newHubSession()
+			if err != nil {
+				return err
+			}
+			u := hub.base + tenantPath(user) + "/export"
+			if includeSecrets {
+				u += "?secrets=true"
+			}
+			req, err := http.NewRequestWithContext(cmd.Context(), http.MethodGet, u, nil)
+			if err != nil {
+				return err
+			}
+			req.Header.Set("Accept", "application/gzip, "+hubAccept)
+			resp, err := hub.client.Do(req)
+			if err != nil {
+				return fmt.Errorf("exporting tenant %s: %w", user, err)
+			}
+			defer resp.Body.Close() //nolint:errcheck
+			if resp.StatusCode != http.StatusOK {
+				data, _ := io.ReadAll(resp.Body)
+				return hubError("exporting tenant "+user, resp, data)
+			}
+
+			if file == "-" {
+				_, err := io.Copy(os.Stdout, resp.Body)
+				return err
+			}
+			f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+			if err != nil {
+				return err
+			}
+			n, err := io.Copy(f, resp.Body)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				return fmt.Errorf("writing %s: %w", file, err)
+			}
+			ui.Infof(os.Stderr, "Exported tenant %s to %s (%d bytes)\n", user, file, n)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "File to write the archive to, or - for stdout (default <user>-export.tar.gz)")
+	cmd.Flags().BoolVar(&includeSecrets, "include-secrets", false, "Include Secrets and personal access token hashes")
+	return cmd
+}
+
+func newAdminTenantDeleteCommand() *cobra.Command {
+	var (
+		noWait bool
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "delete <user>",
+		Short: "Delete a user and their personal organization now, and verify it",
+		Long: `Delete a user without waiting out the 30-day soft-delete grace period:
+the hub starts the deletion cascade at once, removing the personal
+organization and its workspaces, the user's Memberships, personal access
+tokens, preferences and membership index, and finally the User. The command
+waits until none of these remain and prints the verification.
+
+The cascade refuses to run while the user is an admin of an organization
+other than their personal one; hand that organization over first. Export
+the tenant beforehand if its data must be kept.`,
+		Example: `  kedge admin tenant export alice
+  kedge admin tenant delete alice
+
+  # Start without waiting, verify later
+  kedge admin tenant delete alice --no-wait
+  kedge admin tenant verify alice`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			user := args[0]
+			if output != "" && output != "json" {
+				return fmt.Errorf("unsupported output format %q (want json)", output)
+			}
+			ok, err := ui.ConfirmName(cmd.InOrStdin(), cmd.ErrOrStderr(),
+				fmt.Sprintf("This will permanently delete user %q and their personal organization.", user), user)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return ui.Aborted("delete")
+			}
+			hub, err := newHubSession()
+			if err != nil {
+				return err
+			}
+			d, err := startTenantDeletion(cmd.Context(), hub, user)
+			if err != nil {
+				return err
+			}
+			if noWait {
+				if output == "json" {
+					return printTenantDeletionJSON(d)
+				}
+				ui.Infof(os.Stdout, "Deletion started. Verify it with: kedge admin tenant verify %s\n", user)
+				return nil
+			}
+			if output != "json" {
+				ui.Infof(os.Stdout, "Deleting tenant %s...\n", user)
+			}
+			if d, err = waitForTenantDeletion(cmd.Context(), hub, d); err != nil {
+				return err
+			}
+			return reportTenantDeletion(d, output)
+		},
+	}
+
+	cmd.Flags().BoolVar(&noWait, "no-wait", false, "Start the deletion and return without waiting for it to finish")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output format: \"json\"")
+	return cmd
+}
+
+func newAdminTenantVerifyCommand() *cobra.Command {
+	var (
+		orgs   []string
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "verify <user>",
+		Short: "Verify that nothing of a deleted user is left",
+		Long: `List what the hub still stores for a user and fail unless nothing is left.
+Once the User is gone its organizations can no longer be looked up; pass
+them with --org (the orgs of kedge admin tenant delete -o json) to check
+their Memberships and workspaces too.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != "" && output != "json" {
+				return fmt.Errorf("unsupported output format %q (want json)", output)
+			}
+			hub, err := newHubSession()
+			if err != nil {
+				return err
+			}
+			d, err := getTenantDeletion(cmd.Context(), hub, args[0], orgs)
+			if err != nil {
+				return err
+			}
+			return reportTenantDeletion(d, output)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&orgs, "org", nil, "Organization to check for the user's Membership or personal workspace (repeatable)")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output format: \"json\"")
+	return cmd
+}
+
+func tenantPath(user string) string {
+	return apiurl.PathAdminTenants + "/" + url.PathEscape(user)
+}
+
+// startTenantDeletion asks the hub to delete the tenant now.
+func startTenantDeletion(ctx context.Context, hub *hubSession, user string) (*tenantDeletionView, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, hub.base+tenantPath(user), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", hubAccept)
+	resp, err := hub.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("deleting tenant %s: %w", user, err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusAccepted {
+		return nil, hubError("deleting tenant "+user, resp, data)
+	}
+	var out tenantDeletionView
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("decoding deletion report: %w", err)
+	}
+	return &out, nil
+}
+
+func getTenantDeletion(ctx context.Context, hub *hubSession, user string, orgs []string) (*tenantDeletionView, error) {
+	q := url.Values{"org": orgs}
+	u := hub.base + tenantPath(user) + "/deletion"
+	if len(orgs) > 0 {
+		u += "?" + q.Encode()
+	}
+	var out tenantDeletionView
+	if err := doGetJSON(ctx, hub.client, u, "", &out); err != nil {
+		return nil, fmt.Errorf("reading deletion report: %w", err)
+	}
+	return &out, nil
+}
+
+// waitForTenantDeletion polls the deletion report until nothing is left or
+// the cascade is blocked. Each poll passes back the orgs first reported, so
+// they are still checked once the User is gone.
+func waitForTenantDeletion(ctx context.Context, hub *hubSession, d *tenantDeletionView) (*tenantDeletionView, error) {
+	orgs := d.Orgs
+	for !d.Complete && d.Reason != "SoleAdminBlocked" {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(tenantDeletionPollInterval):
+		}
+		next, err := getTenantDeletion(ctx, hub, d.User, orgs)
+		if err != nil {
+			return nil, err
+		}
+		d = next
+	}
+	return d, nil
+}
+
+// reportTenantDeletion prints a deletion report and fails the command
+// unless the deletion is complete.
+func reportTenantDeletion(d *tenantDeletionView, output string) error {
+	if output == "json" {
+		if err := printTenantDeletionJSON(d); err != nil {
+			return err
+		}
+	} else if len(d.Remaining) > 0 {
+		t := ui.NewTable(
+			ui.Column{Header: "Kind"},
+			ui.Column{Header: "Name"},
+			ui.Column{Header: "Workspace"},
+		)
+		for _, o := range d.Remaining {
+			t.AddRow(o.Kind, o.Name, o.Path)
+		}
+		if err := t.Render(os.Stdout, false); err != nil {
+			return err
+		}
+	}
+	switch {
+	case d.Complete:
+		if output != "json" {
+			fmt.Printf("Tenant %s deleted: nothing is left.\n", d.User)
+		}
+		return nil
+	case d.Reason == "SoleAdminBlocked":
+		return fmt.Errorf("deletion of %s is blocked: %s", d.User, d.Message)
+	default:
+		return fmt.Errorf("deletion of %s is not complete: %d objects remain", d.User, len(d.Remaining))
+	}
+}
+
+func printTenantDeletionJSON(d *tenantDeletionView) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d)
+}
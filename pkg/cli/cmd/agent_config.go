@@ -0,0 +1,272 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/faroshq/faros-kedge/pkg/agent"
+)
+
+// agentReloadableFlags are the options a changed config file applies to the
+// running agent. Changes to any other option take effect on restart.
+var agentReloadableFlags = []string{"labels", "log-level", "ssh-user", "ssh-password", "ssh-private-key"}
+
+// agentConfigDebounce coalesces the burst of events an editor or a
+// ConfigMap update produces into one reload.
+const agentConfigDebounce = 500 * time.Millisecond
+
+// agentConfig is the --config file of 'kedge agent run': a YAML or TOML map
+// from agent run flag names to values. Flags given on the command line win
+// over the file.
+type agentConfig struct {
+	path string
+	// explicit are the flags set on the command line.
+	explicit map[string]bool
+	// base holds the reloadable settings as first applied; explicit ones
+	// keep these values on reload.
+	base agent.Reloadable
+
+	raw    []byte
+	values map[string]string
+}
+
+// loadAgentConfig reads path and applies it to the flags of cmd that were
+// not set on the command line.
+func loadAgentConfig(cmd *cobra.Command, path string, opts *agent.Options) (*agentConfig, error) {
+	c := &agentConfig{path: path, explicit: map[string]bool{}}
+	cmd.Flags().Visit(func(f *pflag.Flag) { c.explicit[f.Name] = true })
+
+	raw, values, err := readAgentConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.apply(cmd.Flags(), values); err != nil {
+		return nil, err
+	}
+	c.raw, c.values = raw, values
+	c.base = reloadableOf(opts)
+	return c, nil
+}
+
+// apply sets each value in values on its flag in fs, skipping flags set on
+// the command line.
+func (c *agentConfig) apply(fs *pflag.FlagSet, values map[string]string) error {
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		f := fs.Lookup(name)
+		if f == nil || name == "config" {
+			return fmt.Errorf("%s: unknown option %q", c.path, name)
+		}
+		if c.explicit[name] {
+			continue
+		}
+		if err := fs.Set(name, values[name]); err != nil {
+			return fmt.Errorf("%s: option %q: %w", c.path, name, err)
+		}
+	}
+	return nil
+}
+
+// reloadable returns the reloadable settings values configure, with the
+// command line still taking precedence.
+func (c *agentConfig) reloadable(values map[string]string) (agent.Reloadable, error) {
+	tmp := &cobra.Command{}
+	opts := agent.NewOptions()
+	agentRunFlags(tmp, opts)
+	if err := c.apply(tmp.Flags(), values); err != nil {
+		return agent.Reloadable{}, err
+	}
+	r := reloadableOf(opts)
+	if c.explicit["labels"] {
+		r.Labels = c.base.Labels
+	}
+	if c.explicit["log-level"] {
+		r.LogLevel = c.base.LogLevel
+	}
+	if c.explicit["ssh-user"] {
+		r.SSHUser = c.base.SSHUser
+	}
+	if c.explicit["ssh-password"] {
+		r.SSHPassword = c.base.SSHPassword
+	}
+	if c.explicit["ssh-private-key"] {
+		r.SSHPrivateKeyPath = c.base.SSHPrivateKeyPath
+	}
+	return r, nil
+}
+
+func reloadableOf(opts *agent.Options) agent.Reloadable {
+	return agent.Reloadable{
+		Labels:            maps.Clone(opts.Labels),
+		LogLevel:          opts.LogLevel,
+		SSHUser:           opts.SSHUser,
+		SSHPassword:       opts.SSHPassword,
+		SSHPrivateKeyPath: opts.SSHPrivateKeyPath,
+	}
+}
+
+// watch reloads the file whenever it changes until ctx is done, handing the
+// reloadable settings to each of agents. The file's directory is watched so that editors
+// replacing the file and ConfigMap volume updates are seen too.
+func (c *agentConfig) watch(ctx context.Context, agents ...*agent.Agent) {
+	logger := klog.FromContext(ctx).WithValues("config", c.path)
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		logger.Error(err, "Config file changes will not be applied")
+		return
+	}
+	defer watcher.Close() //nolint:errcheck
+	if err := watcher.Add(filepath.Dir(c.path)); err != nil {
+		logger.Error(err, "Config file changes will not be applied")
+		return
+	}
+
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-watcher.Errors:
+			logger.Error(err, "Watching config file")
+		case <-watcher.Events:
+			debounce = time.After(agentConfigDebounce)
+		case <-debounce:
+			c.reload(logger, agents)
+		}
+	}
+}
+
+func (c *agentConfig) reload(logger klog.Logger, agents []*agent.Agent) {
+	raw, values, err := readAgentConfigFile(c.path)
+	if err != nil {
+		logger.Error(err, "Reading changed config file failed; keeping the current settings")
+		return
+	}
+	if bytes.Equal(raw, c.raw) {
+		return
+	}
+	r, err := c.reloadable(values)
+	if err != nil {
+		logger.Error(err, "Changed config file is invalid; keeping the current settings")
+		return
+	}
+	for _, name := range slices.Sorted(maps.Keys(mergeKeys(c.values, values))) {
+		if c.values[name] != values[name] && !slices.Contains(agentReloadableFlags, name) && !c.explicit[name] {
+			logger.Info("Option changed in config file; restart the agent to apply it", "option", name)
+		}
+	}
+	c.raw, c.values = raw, values
+	logger.Info("Config file changed; applying")
+	for _, a := range agents {
+		a.Reload(r)
+	}
+}
+
+func mergeKeys(a, b map[string]string) map[string]string {
+	out := maps.Clone(a)
+	maps.Copy(out, b)
+	return out
+}
+
+// readAgentConfigFile parses path, TOML for a .toml file and YAML (or JSON)
+// otherwise, into flag values.
+func readAgentConfigFile(path string) ([]byte, map[string]string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading agent config: %w", err)
+	}
+	var doc map[string]any
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		err = toml.Unmarshal(raw, &doc)
+	} else {
+		err = yaml.Unmarshal(raw, &doc)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing agent config %s: %w", path, err)
+	}
+	values := make(map[string]string, len(doc))
+	for name, v := range doc {
+		s, err := configFlagValue(v)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: option %q: %w", path, name, err)
+		}
+		values[name] = s
+	}
+	return raw, values, nil
+}
+
+// configFlagValue formats a config value the way its flag parses it: lists
+// comma-separated and maps as comma-separated key=value pairs.
+func configFlagValue(v any) (string, error) {
+	switch v := v.(type) {
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			s, err := configScalar(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, s)
+		}
+		return strings.Join(items, ","), nil
+	case map[string]any:
+		pairs := make([]string, 0, len(v))
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			s, err := configScalar(v[k])
+			if err != nil {
+				return "", err
+			}
+			pairs = append(pairs, k+"="+s)
+		}
+		return strings.Join(pairs, ","), nil
+	default:
+		return configScalar(v)
+	}
+}
+
+func configScalar(v any) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case nil:
+		return "", nil
+	default:
+		return "", fmt.Errorf("unsupported value %v", v)
+	}
+}
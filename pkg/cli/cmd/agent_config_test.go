@@ -0,0 +1,123 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/faroshq/faros-kedge/pkg/agent"
+)
+
+func newTestAgentRunCommand(t *testing.T, args ...string) (*cobra.Command, *agent.Options) {
+	t.Helper()
+	cmd := &cobra.Command{}
+	opts := agent.NewOptions()
+	agentRunFlags(cmd, opts)
+	if err := cmd.Flags().Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return cmd, opts
+}
+
+func TestAgentConfigFile(t *testing.T) {
+	for name, content := range map[string]string{
+		"agent.yaml": `
+edge-name: store-1
+hub-url: https://hub.example.com
+labels:
+  region: eu
+  tier: 1
+status-mirror-namespaces: [apps, pos]
+log-level: 3
+`,
+		"agent.toml": `
+edge-name = "store-1"
+hub-url = "https://hub.example.com"
+status-mirror-namespaces = ["apps", "pos"]
+log-level = 3
+[labels]
+region = "eu"
+tier = "1"
+`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			cmd, opts := newTestAgentRunCommand(t, "--hub-url", "https://override.example.com")
+			if _, err := loadAgentConfig(cmd, path, opts); err != nil {
+				t.Fatal(err)
+			}
+			if opts.EdgeName != "store-1" || opts.LogLevel != 3 {
+				t.Errorf("edge-name %q, log-level %d", opts.EdgeName, opts.LogLevel)
+			}
+			if opts.HubURL != "https://override.example.com" {
+				t.Errorf("hub-url = %q, want the command line value", opts.HubURL)
+			}
+			if want := map[string]string{"region": "eu", "tier": "1"}; !reflect.DeepEqual(opts.Labels, want) {
+				t.Errorf("labels = %v, want %v", opts.Labels, want)
+			}
+			if want := []string{"apps", "pos"}; !reflect.DeepEqual(opts.StatusMirrorNamespaces, want) {
+				t.Errorf("status-mirror-namespaces = %v, want %v", opts.StatusMirrorNamespaces, want)
+			}
+		})
+	}
+}
+
+func TestAgentConfigUnknownOption(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.yaml")
+	if err := os.WriteFile(path, []byte("edge-nmae: x\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cmd, opts := newTestAgentRunCommand(t)
+	if _, err := loadAgentConfig(cmd, path, opts); err == nil {
+		t.Error("unknown option accepted")
+	}
+}
+
+func TestAgentConfigReloadable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.yaml")
+	if err := os.WriteFile(path, []byte("labels: {a: b}\nssh-user: ops\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cmd, opts := newTestAgentRunCommand(t, "--log-level", "4")
+	c, err := loadAgentConfig(cmd, path, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, values, err := readAgentConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values["labels"] = "a=c,d=e"
+	values["log-level"] = "1"
+	r, err := c.reloadable(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := agent.Reloadable{Labels: map[string]string{"a": "c", "d": "e"}, LogLevel: 4, SSHUser: "ops"}
+	if !reflect.DeepEqual(r, want) {
+		t.Errorf("reloadable = %+v, want %+v (command line log-level kept)", r, want)
+	}
+}
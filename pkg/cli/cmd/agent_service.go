@@ -0,0 +1,238 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+
+	"github.com/faroshq/faros-kedge/pkg/agent"
+	"github.com/faroshq/faros-kedge/pkg/agent/service"
+)
+
+// agentServiceFileFlags are the agent flags naming credential files. The
+// service gets its own copies in the service directory, readable by the
+// service account only.
+var agentServiceFileFlags = []string{"hub-kubeconfig", "ssh-private-key", "end-to-end-tls-key-file", "downstream-proxy-ssh-key"}
+
+// agentServicePathFlags are the other agent flags naming files. They are
+// made absolute, as the service does not run in the current directory.
+var agentServicePathFlags = []string{"kubeconfig", "ssh-password-file", "placement-bundle", "placement-bundle-public-key", "end-to-end-tls-cert-file", "downstream-proxy-known-hosts"}
+
+func newAgentInstallServiceCommand() *cobra.Command {
+	opts := agent.NewOptions()
+	var (
+		name string
+		dir  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "install-service",
+		Short: "Install the agent of a server-type edge as a systemd unit (Linux) or Windows service",
+		Long: `Install the agent of a server-type edge as an operating-system service that
+starts at boot and restarts when it exits: a systemd unit on Linux, a service
+running as LocalSystem on Windows.
+
+The agent flags given here are written to a config file in the service
+directory (/etc/kedge/<edge-name> on Linux, %ProgramData%\kedge\<edge-name> on
+Windows) and the service runs "kedge agent run --config <file>", so the join
+token and SSH password never appear in the unit or the service command line.
+--hub-kubeconfig, --ssh-private-key and the other credential files are copied
+into the directory. The directory and its files are readable by root (or
+LocalSystem and Administrators) only.
+
+Run it as root, or from an elevated prompt on Windows. Re-running it replaces
+the config and restarts the service on Linux; on Windows uninstall first.`,
+		Example: `  sudo kedge agent install-service --type server --edge-name store-17 \
+    --hub-url https://hub.example.com --token <join-token> --ssh-user kedge
+
+  kedge agent install-service --type server --edge-name plant-3 --embedded-ssh always ^
+    --hub-url https://hub.example.com --token <join-token>`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(opts.Edges) > 0 {
+				return fmt.Errorf("--edge is not supported for services; install one service per edge with --edge-name")
+			}
+			if opts.EdgeName == "" {
+				return fmt.Errorf("--edge-name is required")
+			}
+			if opts.HubKubeconfig == "" && opts.Token == "" {
+				return fmt.Errorf("--hub-kubeconfig or --token is required")
+			}
+			if name == "" {
+				name = "kedge-agent-" + opts.EdgeName
+			}
+			if dir == "" {
+				dir = service.DefaultDir(opts.EdgeName)
+			}
+			dir, err := filepath.Abs(dir)
+			if err != nil {
+				return err
+			}
+			binaryPath, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("resolving binary path: %w", err)
+			}
+			if binaryPath, err = filepath.EvalSymlinks(binaryPath); err != nil {
+				return fmt.Errorf("resolving symlinks: %w", err)
+			}
+
+			values, err := agentServiceConfig(cmd.Flags(), dir)
+			if err != nil {
+				return err
+			}
+			config, err := yaml.Marshal(values)
+			if err != nil {
+				return err
+			}
+			configPath := filepath.Join(dir, service.ConfigFile)
+			if err := service.WriteSecret(configPath, config); err != nil {
+				return fmt.Errorf("writing %s: %w (are you running as root?)", configPath, err)
+			}
+			fmt.Printf("Agent config written to %s\n", configPath)
+
+			if err := service.Install(service.Config{
+				Name:        name,
+				Description: "Kedge Agent - " + opts.EdgeName,
+				Executable:  binaryPath,
+				Args:        []string{"agent", "run", "--config", configPath},
+			}); err != nil {
+				return err
+			}
+
+			status, logs := service.StatusCommands(name, dir)
+			fmt.Printf("Service %s installed and started.\n", name)
+			fmt.Printf("  Check status:  %s\n", status)
+			fmt.Printf("  View logs:     %s\n", logs)
+			fmt.Printf("  Uninstall:     kedge agent uninstall-service --edge-name %s\n", opts.EdgeName)
+			return nil
+		},
+	}
+
+	agentRunFlags(cmd, opts)
+	// Services are for server-type edges; kubernetes edges run in-cluster
+	// ("kedge agent join --type kubernetes").
+	typeFlag := cmd.Flags().Lookup("type")
+	_ = typeFlag.Value.Set(string(agent.AgentTypeServer))
+	typeFlag.DefValue = string(agent.AgentTypeServer)
+	cmd.Flags().StringVar(&name, "service-name", "", "Service (systemd unit) name (default: kedge-agent-<edge-name>)")
+	cmd.Flags().StringVar(&dir, "service-dir", "", "Directory for the service's config and credentials (default: /etc/kedge/<edge-name>, %ProgramData%\\kedge\\<edge-name> on Windows)")
+	return cmd
+}
+
+// agentServiceConfig returns the --config file contents for the agent flags
+// set in fs, keyed by flag name. Credential files are copied into dir and
+// the config points at the copies.
+func agentServiceConfig(fs *pflag.FlagSet, dir string) (map[string]any, error) {
+	values := map[string]any{}
+	fs.Visit(func(f *pflag.Flag) {
+		if f.Name == "service-name" || f.Name == "service-dir" {
+			return
+		}
+		switch f.Value.Type() {
+		case "stringToString":
+			values[f.Name], _ = fs.GetStringToString(f.Name)
+		case "stringSlice":
+			values[f.Name], _ = fs.GetStringSlice(f.Name)
+		default:
+			values[f.Name] = f.Value.String()
+		}
+	})
+	if _, ok := values["type"]; !ok {
+		values["type"] = fs.Lookup("type").Value.String()
+	}
+
+	for _, flag := range agentServicePathFlags {
+		if p, ok := values[flag].(string); ok && p != "" {
+			abs, err := filepath.Abs(p)
+			if err != nil {
+				return nil, err
+			}
+			values[flag] = abs
+		}
+	}
+	for _, flag := range agentServiceFileFlags {
+		src, ok := values[flag].(string)
+		if !ok || src == "" {
+			continue
+		}
+		data, err := os.ReadFile(src)
+		if err != nil {
+			return nil, fmt.Errorf("reading --%s: %w", flag, err)
+		}
+		dst := filepath.Join(dir, flag)
+		if err := service.WriteSecret(dst, data); err != nil {
+			return nil, fmt.Errorf("copying --%s to %s: %w", flag, dst, err)
+		}
+		values[flag] = dst
+	}
+	return values, nil
+}
+
+func newAgentUninstallServiceCommand() *cobra.Command {
+	var (
+		edgeName string
+		name     string
+		dir      string
+		purge    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "uninstall-service",
+		Short: "Stop and remove the agent service installed by install-service",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if name == "" {
+				if edgeName == "" {
+					return fmt.Errorf("--edge-name or --service-name is required")
+				}
+				name = "kedge-agent-" + edgeName
+			}
+			if err := service.Uninstall(name); err != nil {
+				return err
+			}
+			fmt.Printf("Service %s uninstalled.\n", name)
+
+			if dir == "" && edgeName != "" {
+				dir = service.DefaultDir(edgeName)
+			}
+			if dir == "" {
+				return nil
+			}
+			if !purge {
+				fmt.Printf("Config and credentials kept in %s (--purge removes them).\n", dir)
+				return nil
+			}
+			if err := os.RemoveAll(dir); err != nil {
+				return fmt.Errorf("removing %s: %w", dir, err)
+			}
+			fmt.Printf("Removed %s.\n", dir)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&edgeName, "edge-name", "", "Edge name (used to derive the service name and directory)")
+	cmd.Flags().StringVar(&name, "service-name", "", "Service (systemd unit) name (default: kedge-agent-<edge-name>)")
+	cmd.Flags().StringVar(&dir, "service-dir", "", "Service directory (default: /etc/kedge/<edge-name>, %ProgramData%\\kedge\\<edge-name> on Windows)")
+	cmd.Flags().BoolVar(&purge, "purge", false, "Also remove the service directory with its config and credentials")
+	return cmd
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+)
+
+// TestAgentServiceConfig checks that the config install-service writes
+// gives "agent run --config" the options it was called with, pointing at
+// copies of the credential files.
+func TestAgentServiceConfig(t *testing.T) {
+	src := t.TempDir()
+	key := filepath.Join(src, "id_ed25519")
+	if err := os.WriteFile(key, []byte("private key"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd, _ := newTestAgentRunCommand(t, "--edge-name", "store-1", "--type", "server",
+		"--token", "secret", "--ssh-private-key", key, "--labels", "region=eu,tier=1", "--log-level", "2")
+
+	dir := filepath.Join(t.TempDir(), "store-1")
+	values, err := agentServiceConfig(cmd.Flags(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(configPath, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	run, opts := newTestAgentRunCommand(t)
+	if _, err := loadAgentConfig(run, configPath, opts); err != nil {
+		t.Fatalf("loading the service config: %v", err)
+	}
+	if opts.EdgeName != "store-1" || opts.Token != "secret" || opts.LogLevel != 2 ||
+		!reflect.DeepEqual(opts.Labels, map[string]string{"region": "eu", "tier": "1"}) {
+		t.Errorf("options = edge %q, token %q, log level %d, labels %v", opts.EdgeName, opts.Token, opts.LogLevel, opts.Labels)
+	}
+	if opts.SSHPrivateKeyPath != filepath.Join(dir, "ssh-private-key") {
+		t.Errorf("ssh-private-key = %s, want the copy in %s", opts.SSHPrivateKeyPath, dir)
+	}
+	if fi, err := os.Stat(opts.SSHPrivateKeyPath); err != nil || fi.Mode().Perm() != 0o600 {
+		t.Errorf("copied key: %v, %v", fi, err)
+	}
+}
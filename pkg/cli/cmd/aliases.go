@@ -0,0 +1,151 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// resourceName is a kedge resource and every name the CLI accepts for it.
+type resourceName struct {
+	// Plural is the canonical name, as `kedge get` lists it.
+	Plural   string
+	Singular string
+	// Short are the kubectl-style short names.
+	Short []string
+	// Kinds are the object kinds the resource covers, as events name them.
+	Kinds       []string
+	Description string
+}
+
+// resourceNames is the one table of resource names. The resource commands
+// (kedge edge, kedge vw, kedge placements), kedge get, kedge events --for
+// and shell completion all read it, so a short name works everywhere.
+var resourceNames = []resourceName{
+	{
+		Plural:      "edges",
+		Singular:    "edge",
+		Short:       []string{"ed", "devices"},
+		Kinds:       []string{"KubernetesCluster", "LinuxServer"},
+		Description: "Kubernetes and server edges",
+	},
+	{
+		Plural:      "workloads",
+		Singular:    "workload",
+		Short:       []string{"vw", "wl"},
+		Kinds:       []string{"Workload"},
+		Description: "Workloads scheduled onto edges",
+	},
+	{
+		Plural:      "placements",
+		Singular:    "placement",
+		Short:       []string{"pl"},
+		Kinds:       []string{"Placement"},
+		Description: "Per-edge placements of workloads",
+	},
+}
+
+// verbAliases are the short names of the common subcommands, added to every
+// resource command that has them (kedge ed ls, kedge vw rm).
+var verbAliases = map[string][]string{
+	"list":   {"ls"},
+	"delete": {"rm"},
+}
+
+// names returns every name of r: plural, singular and short names.
+func (r *resourceName) names() []string {
+	return append([]string{r.Plural, r.Singular}, r.Short...)
+}
+
+// aliases returns every name of r but name, for a command whose Use is name.
+func (r *resourceName) aliases(name string) []string {
+	return slices.DeleteFunc(r.names(), func(n string) bool { return n == name })
+}
+
+// lookupResource returns the resource s names, case-insensitively.
+func lookupResource(s string) (*resourceName, bool) {
+	s = strings.ToLower(s)
+	for i := range resourceNames {
+		if slices.Contains(resourceNames[i].names(), s) {
+			return &resourceNames[i], true
+		}
+	}
+	return nil, false
+}
+
+// mustResource returns the resource named plural; it panics on a name not
+// in resourceNames, which is a programming error.
+func mustResource(plural string) *resourceName {
+	r, ok := lookupResource(plural)
+	if !ok {
+		panic("unknown resource " + plural)
+	}
+	return r
+}
+
+// resourceNamesHint lists the resources with their short names, for error
+// messages: "edges (ed), workloads (vw), ...".
+func resourceNamesHint() string {
+	hints := make([]string, 0, len(resourceNames))
+	for _, r := range resourceNames {
+		hints = append(hints, r.Plural+" ("+r.Short[0]+")")
+	}
+	return strings.Join(hints, ", ")
+}
+
+// completeResourceNames completes resource names, each described with its
+// short names. A resource is offered by its plural, or by the first other
+// name that matches what has been typed so far (vw for "v").
+func completeResourceNames(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var out []string
+	for _, r := range resourceNames {
+		i := slices.IndexFunc(r.names(), func(n string) bool { return strings.HasPrefix(n, toComplete) })
+		if i < 0 {
+			continue
+		}
+		out = append(out, r.names()[i]+"\t"+r.Description+" ("+strings.Join(r.Short, ", ")+")")
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// addVerbAliases adds verbAliases to the subcommands of cmd and its
+// descendants, skipping a short name a sibling already uses.
+func addVerbAliases(cmd *cobra.Command) {
+	for _, sub := range cmd.Commands() {
+		for _, alias := range verbAliases[sub.Name()] {
+			if !sub.HasAlias(alias) && !siblingHasName(cmd, sub, alias) {
+				sub.Aliases = append(sub.Aliases, alias)
+			}
+		}
+		addVerbAliases(sub)
+	}
+}
+
+func siblingHasName(parent, self *cobra.Command, name string) bool {
+	for _, c := range parent.Commands() {
+		if c != self && (c.Name() == name || c.HasAlias(name)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestLookupResource(t *testing.T) {
+	for in, want := range map[string]string{
+		"edges":     "edges",
+		"edge":      "edges",
+		"ed":        "edges",
+		"ED":        "edges",
+		"vw":        "workloads",
+		"wl":        "workloads",
+		"workload":  "workloads",
+		"pl":        "placements",
+		"placement": "placements",
+	} {
+		r, ok := lookupResource(in)
+		if !ok || r.Plural != want {
+			t.Errorf("lookupResource(%q) = %v, %v; want %s", in, r, ok, want)
+		}
+	}
+	if _, ok := lookupResource("pods"); ok {
+		t.Errorf("lookupResource(pods) found a resource")
+	}
+}
+
+func TestResourceNamesAreUnique(t *testing.T) {
+	seen := map[string]string{}
+	for _, r := range resourceNames {
+		for _, n := range r.names() {
+			if other, ok := seen[n]; ok {
+				t.Errorf("%q names both %s and %s", n, other, r.Plural)
+			}
+			seen[n] = r.Plural
+		}
+	}
+}
+
+func TestAddVerbAliases(t *testing.T) {
+	root := &cobra.Command{Use: "kedge"}
+	ed := &cobra.Command{Use: "edge"}
+	ed.AddCommand(&cobra.Command{Use: "list"}, &cobra.Command{Use: "delete"})
+	// A top-level list that already owns ls, and a sibling named rm.
+	root.AddCommand(ed, &cobra.Command{Use: "list", Aliases: []string{"ls"}}, &cobra.Command{Use: "rm"}, &cobra.Command{Use: "delete"})
+
+	addVerbAliases(root)
+	addVerbAliases(root) // idempotent
+
+	find := func(parent *cobra.Command, name string) *cobra.Command {
+		for _, c := range parent.Commands() {
+			if c.Name() == name {
+				return c
+			}
+		}
+		t.Fatalf("no %s under %s", name, parent.Name())
+		return nil
+	}
+	if got := find(ed, "list").Aliases; !slices.Equal(got, []string{"ls"}) {
+		t.Errorf("edge list aliases = %v, want [ls]", got)
+	}
+	if got := find(ed, "delete").Aliases; !slices.Equal(got, []string{"rm"}) {
+		t.Errorf("edge delete aliases = %v, want [rm]", got)
+	}
+	if got := find(root, "list").Aliases; !slices.Equal(got, []string{"ls"}) {
+		t.Errorf("list aliases = %v, want [ls]", got)
+	}
+	if got := find(root, "delete").Aliases; len(got) != 0 {
+		t.Errorf("delete aliases = %v, want none: a sibling is named rm", got)
+	}
+}
+
+func TestCompleteResourceNames(t *testing.T) {
+	for _, tc := range []struct {
+		toComplete string
+		want       []string
+	}{
+		{"", []string{"edges", "workloads", "placements"}},
+		{"p", []string{"placements"}},
+		{"v", []string{"vw"}},
+		{"e", []string{"edges"}},
+		{"x", nil},
+	} {
+		got, _ := completeResourceNames(nil, nil, tc.toComplete)
+		var names []string
+		for _, g := range got {
+			name, _, _ := strings.Cut(g, "\t")
+			names = append(names, name)
+		}
+		if !slices.Equal(names, tc.want) {
+			t.Errorf("complete %q = %v, want %v", tc.toComplete, names, tc.want)
+		}
+	}
+}
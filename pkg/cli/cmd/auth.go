@@ -0,0 +1,131 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/faroshq/faros-kedge/pkg/apiurl"
+)
+
+// identityView mirrors restapi.IdentityView, the GET /api/users/me response.
+type identityView struct {
+	User           string     `json:"user"`
+	Email          string     `json:"email,omitempty"`
+	Name           string     `json:"name,omitempty"`
+	RBACIdentity   string     `json:"rbacIdentity,omitempty"`
+	AuthMethod     string     `json:"authMethod"`
+	DefaultCluster string     `json:"defaultCluster,omitempty"`
+	PersonalOrg    string     `json:"personalOrg,omitempty"`
+	ExpiresAt      *time.Time `json:"expiresAt,omitempty"`
+}
+
+// whoamiOutput is what `kedge auth whoami -o json` prints: the hub's view
+// of the caller plus the workspace the kubeconfig currently targets.
+type whoamiOutput struct {
+	identityView
+	Context string `json:"context"`
+	Hub     string `json:"hub"`
+	Cluster string `json:"cluster"`
+}
+
+func newAuthCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Inspect the credentials the CLI uses",
+	}
+	cmd.AddCommand(newAuthWhoamiCommand())
+	return cmd
+}
+
+func newAuthWhoamiCommand() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "whoami",
+		Short: "Print the identity the hub resolves for the current credentials",
+		Long: `Ask the hub who the current kubeconfig credentials belong to and print the
+user, how they authenticated, the workspace the kubeconfig targets and when
+the token expires. Use it to debug unexpected "forbidden" errors: a different
+user, an expired token or a workspace other than the intended one are the
+usual causes.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != "" && output != "json" {
+				return fmt.Errorf("unsupported output format %q (want json)", output)
+			}
+			hub, err := newHubSession()
+			if err != nil {
+				return err
+			}
+			var id identityView
+			if err := doGetJSON(cmd.Context(), hub.client, hub.base+"/api/users/me", "", &id); err != nil {
+				return fmt.Errorf("reading identity: %w", err)
+			}
+			_, cluster := apiurl.SplitBaseAndCluster(hub.cluster.Server)
+			out := whoamiOutput{identityView: id, Context: hub.ctxName, Hub: hub.base, Cluster: cluster}
+			if output == "json" {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(out)
+			}
+			printWhoami(os.Stdout, out, time.Now())
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output format: \"json\"")
+
+	return cmd
+}
+
+// printWhoami renders the whoami result as aligned key/value lines.
+func printWhoami(w io.Writer, out whoamiOutput, now time.Time) {
+	tw := newTabWriter(w)
+	printRow(tw, "User:", out.User)
+	printRow(tw, "Email:", formatStringOrDash(out.Email))
+	if out.Name != "" {
+		printRow(tw, "Name:", out.Name)
+	}
+	printRow(tw, "RBAC identity:", formatStringOrDash(out.RBACIdentity))
+	printRow(tw, "Auth method:", out.AuthMethod)
+	printRow(tw, "Context:", out.Context)
+	printRow(tw, "Hub:", out.Hub)
+	printRow(tw, "Cluster:", out.Cluster)
+	printRow(tw, "Default cluster:", formatStringOrDash(out.DefaultCluster))
+	printRow(tw, "Token expiry:", formatExpiry(out.ExpiresAt, now))
+	_ = tw.Flush()
+}
+
+// formatExpiry renders a token expiry with the time left, or "never" for
+// tokens that do not expire.
+func formatExpiry(expiresAt *time.Time, now time.Time) string {
+	if expiresAt == nil {
+		return "never"
+	}
+	at := expiresAt.Local().Format(time.RFC3339)
+	if !expiresAt.After(now) {
+		return at + " (expired)"
+	}
+	return fmt.Sprintf("%s (in %s)", at, expiresAt.Sub(now).Round(time.Second))
+}
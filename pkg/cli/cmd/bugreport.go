@@ -0,0 +1,332 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/faroshq/faros-kedge/pkg/apiurl"
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
+	pkgversion "github.com/faroshq/faros-kedge/pkg/version"
+)
+
+// bugReportHistoryLines caps how many kedge invocations --include-history
+// takes from the shell history.
+const bugReportHistoryLines = 50
+
+func newBugReportCommand() *cobra.Command {
+	var (
+		output         string
+		includeHistory bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "bug-report",
+		Short: "Collect sanitized diagnostics into an archive for an issue",
+		Long: `Collect diagnostics about this CLI, the hub and the current workspace into
+a .tar.gz archive to attach to an issue:
+
+  version.json     CLI version, commit, build date and platform
+  hub.json         hub URL and version
+  kubeconfig.json  contexts, clusters and the kind of credential each user
+                   has; never the credentials themselves
+  edges.json       name, kind, labels and status of every edge, without
+                   SSH credentials
+  history.txt      with --include-history only: your last kedge commands
+                   from the shell history, with tokens and passwords masked
+  errors.txt       what could not be collected
+
+A part that cannot be collected, e.g. because the hub is unreachable, is
+recorded in errors.txt rather than failing the report. Review the archive
+before attaching it.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output == "" {
+				output = fmt.Sprintf("kedge-bug-report-%s.tar.gz", time.Now().UTC().Format("20060102-150405"))
+			}
+			files := collectBugReport(cmd.Context(), includeHistory)
+			if err := writeBugReport(output, files); err != nil {
+				return err
+			}
+			ui.Infof(cmd.OutOrStdout(), "Bug report written to %s. Review it before attaching it to an issue.\n", output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Archive to write (default kedge-bug-report-<timestamp>.tar.gz)")
+	cmd.Flags().BoolVar(&includeHistory, "include-history", false, "Include your recent kedge commands from the shell history, secrets masked")
+	return cmd
+}
+
+// bugReportFile is one file of the bug report archive.
+type bugReportFile struct {
+	name string
+	data []byte
+}
+
+// collectBugReport gathers the report's files. Failures are recorded in
+// errors.txt instead of aborting the collection.
+func collectBugReport(ctx context.Context, includeHistory bool) []bugReportFile {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var (
+		files []bugReportFile
+		errs  []string
+	)
+	addJSON := func(name string, v interface{}) {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+			return
+		}
+		files = append(files, bugReportFile{name: name, data: append(data, '\n')})
+	}
+
+	addJSON("version.json", map[string]string{
+		"version":   pkgversion.Version,
+		"gitCommit": pkgversion.GitCommit,
+		"buildDate": pkgversion.BuildDate,
+		"goVersion": runtime.Version(),
+		"platform":  runtime.GOOS + "/" + runtime.GOARCH,
+	})
+
+	if raw, err := cliLoadingRules().Load(); err != nil {
+		errs = append(errs, fmt.Sprintf("kubeconfig: %v", err))
+	} else {
+		addJSON("kubeconfig.json", redactKubeconfig(raw))
+	}
+
+	if config, err := loadRestConfig(); err != nil {
+		errs = append(errs, fmt.Sprintf("hub: loading kubeconfig: %v", err))
+	} else {
+		hubURL, cluster := apiurl.SplitBaseAndCluster(config.Host)
+		hub := map[string]string{"url": hubURL, "cluster": cluster}
+		if v, err := fetchHubVersion(ctx, config, hubURL); err != nil {
+			errs = append(errs, fmt.Sprintf("hub version: %v", err))
+		} else {
+			hub["version"] = v
+		}
+		addJSON("hub.json", hub)
+
+		if edges, err := listEdgesForVersionCheck(ctx, config); err != nil {
+			errs = append(errs, fmt.Sprintf("edges: %v", err))
+		} else {
+			addJSON("edges.json", sanitizeEdges(edges))
+		}
+	}
+
+	if includeHistory {
+		if lines, err := kedgeShellHistory(bugReportHistoryLines); err != nil {
+			errs = append(errs, fmt.Sprintf("history: %v", err))
+		} else {
+			files = append(files, bugReportFile{name: "history.txt", data: []byte(strings.Join(lines, "\n") + "\n")})
+		}
+	}
+
+	if len(errs) > 0 {
+		files = append(files, bugReportFile{name: "errors.txt", data: []byte(strings.Join(errs, "\n") + "\n")})
+	}
+	return files
+}
+
+// writeBugReport writes files as a gzipped tarball at path, readable by the
+// owner only.
+func writeBugReport(path string, files []bugReportFile) (err error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", path, err)
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	dir := strings.TrimSuffix(filepath.Base(path), ".tar.gz")
+	now := time.Now()
+	for _, file := range files {
+		hdr := &tar.Header{Name: dir + "/" + file.name, Mode: 0o600, Size: int64(len(file.data)), ModTime: now}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(file.data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// kubeconfigSummary is the part of a kubeconfig a bug report carries.
+type kubeconfigSummary struct {
+	CurrentContext string                       `json:"currentContext"`
+	Contexts       map[string]map[string]string `json:"contexts"`
+	Clusters       map[string]clusterSummary    `json:"clusters"`
+	Users          map[string][]string          `json:"users"`
+}
+
+type clusterSummary struct {
+	Server                string `json:"server"`
+	InsecureSkipTLSVerify bool   `json:"insecureSkipTLSVerify,omitempty"`
+	HasCA                 bool   `json:"hasCA,omitempty"`
+	ProxyURL              bool   `json:"proxyURL,omitempty"`
+}
+
+// redactKubeconfig summarizes config without any credential: a user is
+// reduced to the kinds of credential it has, e.g. "token" or "exec".
+func redactKubeconfig(config *clientcmdapi.Config) kubeconfigSummary {
+	s := kubeconfigSummary{
+		CurrentContext: config.CurrentContext,
+		Contexts:       map[string]map[string]string{},
+		Clusters:       map[string]clusterSummary{},
+		Users:          map[string][]string{},
+	}
+	for name, c := range config.Contexts {
+		s.Contexts[name] = map[string]string{"cluster": c.Cluster, "user": c.AuthInfo, "namespace": c.Namespace}
+	}
+	for name, c := range config.Clusters {
+		s.Clusters[name] = clusterSummary{
+			Server:                c.Server,
+			InsecureSkipTLSVerify: c.InsecureSkipTLSVerify,
+			HasCA:                 c.CertificateAuthority != "" || len(c.CertificateAuthorityData) > 0,
+			ProxyURL:              c.ProxyURL != "",
+		}
+	}
+	for name, u := range config.AuthInfos {
+		methods := []string{}
+		if u.Token != "" || u.TokenFile != "" {
+			methods = append(methods, "token")
+		}
+		if u.ClientCertificate != "" || len(u.ClientCertificateData) > 0 {
+			methods = append(methods, "client-certificate")
+		}
+		if u.Username != "" || u.Password != "" {
+			methods = append(methods, "basic")
+		}
+		if u.Exec != nil {
+			methods = append(methods, "exec:"+filepath.Base(u.Exec.Command))
+		}
+		if u.AuthProvider != nil {
+			methods = append(methods, "auth-provider:"+u.AuthProvider.Name)
+		}
+		s.Users[name] = methods
+	}
+	return s
+}
+
+// edgeSummary is the part of an edge a bug report carries.
+type edgeSummary struct {
+	Kind   string                 `json:"kind"`
+	Name   string                 `json:"name"`
+	Labels map[string]string      `json:"labels,omitempty"`
+	Status map[string]interface{} `json:"status,omitempty"`
+}
+
+// sanitizeEdges summarizes edges, dropping their SSH credentials.
+func sanitizeEdges(edges []unstructured.Unstructured) []edgeSummary {
+	out := make([]edgeSummary, 0, len(edges))
+	for _, edge := range edges {
+		status, _, _ := unstructured.NestedMap(edge.Object, "status")
+		delete(status, "sshCredentials")
+		out = append(out, edgeSummary{Kind: edge.GetKind(), Name: edge.GetName(), Labels: edge.GetLabels(), Status: status})
+	}
+	return out
+}
+
+// historySecrets mask the secret parts of a command line: values of flags
+// and environment variables named like a credential, bearer tokens and URL
+// passwords.
+var historySecrets = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile(`(?i)(--?[\w-]*(?:token|password|secret|key)[\w-]*[= ])\S+`), "${1}***"},
+	{regexp.MustCompile(`(?i)\b([A-Z_]*(?:TOKEN|PASSWORD|SECRET)[A-Z_]*=)\S+`), "${1}***"},
+	{regexp.MustCompile(`(?i)(bearer )\S+`), "${1}***"},
+	{regexp.MustCompile(`(://[^:/@\s]+:)[^@\s]+@`), "${1}***@"},
+}
+
+// redactCommandLine masks the secrets in line.
+func redactCommandLine(line string) string {
+	for _, s := range historySecrets {
+		line = s.re.ReplaceAllString(line, s.repl)
+	}
+	return line
+}
+
+// kedgeShellHistory returns up to max of the most recent kedge commands in
+// the shell history ($HISTFILE, ~/.bash_history or ~/.zsh_history), with
+// secrets masked.
+func kedgeShellHistory(max int) ([]string, error) {
+	var candidates []string
+	if f := os.Getenv("HISTFILE"); f != "" {
+		candidates = append(candidates, f)
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates, filepath.Join(home, ".bash_history"), filepath.Join(home, ".zsh_history"))
+	}
+	for _, path := range candidates {
+		f, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		lines, err := kedgeCommands(bufio.NewScanner(f), max)
+		_ = f.Close()
+		return lines, err
+	}
+	return nil, fmt.Errorf("no shell history found")
+}
+
+// kedgeCommands returns up to max of the last kedge commands scanner yields,
+// redacted. zsh extended-history prefixes (": <time>:<n>;") are stripped.
+func kedgeCommands(scanner *bufio.Scanner, max int) ([]string, error) {
+	var lines []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, ";"); strings.HasPrefix(line, ": ") && i > 0 {
+			line = line[i+1:]
+		}
+		line = strings.TrimSpace(line)
+		if line != "kedge" && !strings.HasPrefix(line, "kedge ") {
+			continue
+		}
+		lines = append(lines, redactCommandLine(line))
+	}
+	if len(lines) > max {
+		lines = lines[len(lines)-max:]
+	}
+	return lines, scanner.Err()
+}
@@ -48,6 +48,38 @@ var (
 
   # Upgrade to a specific chart version
   kedge dev update --chart-version 0.1.0`
+
+	devSnapshotExampleUses = `  # Snapshot the hub and agent kind clusters under a timestamped name
+  kedge dev snapshot
+
+  # Snapshot under a name, e.g. before reproducing a bug
+  kedge dev snapshot two-edges-rolled-out
+
+  # List snapshots
+  kedge dev snapshot --list`
+
+	devRestoreExampleUses = `  # Return the kind clusters to a snapshot
+  kedge dev restore two-edges-rolled-out
+
+  # Restore without the confirmation prompt
+  kedge dev restore two-edges-rolled-out --yes`
+
+	devKCPShellExampleUses = `  # Open a shell with kubectl pointed at the root workspace of the dev kcp
+  kedge dev kcp shell
+
+  # Open it in the providers workspace
+  kedge dev kcp shell root:kedge:providers
+
+  # Point the current shell at kcp instead
+  eval "$(kedge dev kcp env root:kedge:providers)"`
+
+	devKCPUseExampleUses = `  # Print the current workspace
+  kedge dev kcp use
+
+  # Switch to a child workspace, to the parent, or to an absolute path
+  kedge dev kcp use system
+  kedge dev kcp use ..
+  kedge dev kcp use root:kedge:tenants`
 )
 
 // New creates the dev command and all its subcommands.
@@ -58,7 +90,7 @@ func New(streams genericclioptions.IOStreams) (*cobra.Command, error) {
 		Long: `Manage a development environment for kedge using kind clusters.
 
 This command provides subcommands to initialize, update and delete kind
-clusters configured for kedge.`,
+clusters configured for kedge, and to snapshot and restore their state.`,
 		SilenceUsage: true,
 	}
 
@@ -80,6 +112,20 @@ clusters configured for kedge.`,
 	}
 	cmd.AddCommand(deleteCmd)
 
+	snapshotCmd, err := newSnapshotCommand(streams)
+	if err != nil {
+		return nil, err
+	}
+	cmd.AddCommand(snapshotCmd)
+
+	restoreCmd, err := newRestoreCommand(streams)
+	if err != nil {
+		return nil, err
+	}
+	cmd.AddCommand(restoreCmd)
+
+	cmd.AddCommand(newKCPCommand(streams))
+
 	return cmd, nil
 }
 
@@ -161,7 +207,8 @@ func newDeleteCommand(streams genericclioptions.IOStreams) (*cobra.Command, erro
 		Short: "Delete development environment",
 		Long: `Delete the development environment for kedge.
 
-This command will delete the kind cluster created for kedge development.`,
+This command will delete the kind cluster created for kedge development.
+You are asked to confirm; pass --yes to skip the prompt in scripts.`,
 		SilenceUsage: true,
 		Args:         cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -180,3 +227,137 @@ This command will delete the kind cluster created for kedge development.`,
 
 	return cmd, nil
 }
+
+func newSnapshotCommand(streams genericclioptions.IOStreams) (*cobra.Command, error) {
+	opts := plugin.NewSnapshotOptions(streams)
+	cmd := &cobra.Command{
+		Use:   "snapshot [NAME]",
+		Short: "Snapshot the state of the local environment",
+		Long: `Snapshot the state of the kind clusters created by ` + "`kedge dev init`" + `.
+
+The etcd and volume data (including the hub's kcp data) of the hub cluster and
+every agent cluster is copied to ~/.kedge/dev/snapshots/NAME. Each cluster is
+stopped while it is copied and resumed afterwards. Use ` + "`kedge dev restore`" + `
+to return to the snapshot later, instead of recreating edges and workloads by
+hand.`,
+		Example:      devSnapshotExampleUses,
+		SilenceUsage: true,
+		Args:         cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Complete(args); err != nil {
+				return err
+			}
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+			return opts.RunSnapshot(cmd.Context())
+		},
+	}
+	opts.AddCmdFlags(cmd)
+
+	return cmd, nil
+}
+
+func newRestoreCommand(streams genericclioptions.IOStreams) (*cobra.Command, error) {
+	opts := plugin.NewSnapshotOptions(streams)
+	cmd := &cobra.Command{
+		Use:   "restore NAME",
+		Short: "Restore the local environment to a snapshot",
+		Long: `Restore the kind clusters to a snapshot taken with ` + "`kedge dev snapshot`" + `.
+
+The current state of every cluster in the snapshot is replaced. A snapshot
+only restores into the clusters it was taken from: after ` + "`kedge dev delete`" + `
+and a new ` + "`kedge dev init`" + ` its data no longer matches the clusters'
+certificates, and restore refuses it.
+You are asked to confirm; pass --yes to skip the prompt in scripts.`,
+		Example:      devRestoreExampleUses,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Complete(args); err != nil {
+				return err
+			}
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+			return opts.RunRestore(cmd.Context())
+		},
+	}
+	opts.AddCmdFlags(cmd)
+
+	return cmd, nil
+}
+
+func newKCPCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "kcp",
+		Short: "Work with the kcp of an external-kcp environment",
+		Long: `Work with the kcp deployed by ` + "`kedge dev init --with-external-kcp`" + `.
+
+The commands point kubectl at a kcp workspace through copies of the
+kcp-admin.kubeconfig that init writes, kept in ~/.kedge/dev/kcp, so the
+kubeconfig paths and workspace URLs need no assembling by hand.`,
+		SilenceUsage: true,
+	}
+
+	shellOpts := plugin.NewKCPShellOptions(streams)
+	shellCmd := &cobra.Command{
+		Use:   "shell [WORKSPACE]",
+		Short: "Open a shell with KUBECONFIG pointed at a kcp workspace",
+		Long: `Open a shell whose KUBECONFIG points at a kcp workspace, root by default,
+as the kcp admin. Switch workspaces inside it with ` + "`kedge dev kcp use`" + `.
+The shell's kubeconfig is removed when it exits.`,
+		Example:      devKCPShellExampleUses,
+		SilenceUsage: true,
+		Args:         cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := shellOpts.Complete(args); err != nil {
+				return err
+			}
+			return shellOpts.RunShell(cmd.Context())
+		},
+	}
+	shellOpts.AddShellFlags(shellCmd)
+	cmd.AddCommand(shellCmd)
+
+	envOpts := plugin.NewKCPShellOptions(streams)
+	envCmd := &cobra.Command{
+		Use:   "env [WORKSPACE]",
+		Short: "Print the KUBECONFIG export that points a shell at a kcp workspace",
+		Long: `Write a kubeconfig pointed at a kcp workspace, root by default, and print
+the export selecting it, for eval "$(kedge dev kcp env)".`,
+		SilenceUsage: true,
+		Args:         cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := envOpts.Complete(args); err != nil {
+				return err
+			}
+			return envOpts.RunEnv()
+		},
+	}
+	envOpts.AddCmdFlags(envCmd)
+	cmd.AddCommand(envCmd)
+
+	useOpts := plugin.NewKCPShellOptions(streams)
+	useCmd := &cobra.Command{
+		Use:   "use [WORKSPACE]",
+		Short: "Switch the kcp shell to another workspace",
+		Long: `Point the kubeconfig of a kcp shell, or of a shell set up with
+` + "`kedge dev kcp env`" + `, at another workspace: an absolute path (root:…),
+".." for the parent, or a path below the current workspace. Without an
+argument the current workspace is printed.`,
+		Example:      devKCPUseExampleUses,
+		SilenceUsage: true,
+		Args:         cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := useOpts.Complete(args); err != nil {
+				return err
+			}
+			return useOpts.RunUse()
+		},
+	}
+	useOpts.AddCmdFlags(useCmd)
+	cmd.AddCommand(useCmd)
+
+	return cmd
+}
@@ -49,6 +49,8 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"sigs.k8s.io/kind/pkg/cluster"
+
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
 )
 
 // DevOptions contains the options for the dev command
@@ -270,13 +272,14 @@ nodes:
 - role: control-plane
 `
 
-// Color helper functions
+// Color helper functions. Their output goes to stderr, so that is what
+// decides whether colour is enabled.
 func blueCommand(text string) string {
-	return "\033[38;5;67m" + text + "\033[0m"
+	return ui.Colorize(os.Stderr, "38;5;67", text)
 }
 
 func redText(text string) string {
-	return "\033[31m" + text + "\033[0m"
+	return ui.Colorize(os.Stderr, "31", text)
 }
 
 // agentClusterNames returns the list of agent cluster names derived from
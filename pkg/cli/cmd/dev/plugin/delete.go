@@ -24,10 +24,22 @@ import (
 	"strings"
 
 	"sigs.k8s.io/kind/pkg/cluster"
+
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
 )
 
 // RunDelete deletes the development environment
 func (o *DevOptions) RunDelete() error {
+	clusters := append([]string{o.HubClusterName}, o.agentClusterNames()...)
+	ok, err := ui.ConfirmOnTerminal(o.Streams.In, o.Streams.ErrOut,
+		fmt.Sprintf("Delete kind cluster(s) %s and their kubeconfigs?", strings.Join(clusters, ", ")))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ui.Aborted("dev delete")
+	}
+
 	// Delete hub cluster
 	if err := o.deleteCluster(o.HubClusterName); err != nil {
 		return err
@@ -0,0 +1,304 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// The kcp commands work on copies of the admin kubeconfig that
+// `kedge dev init --with-external-kcp` writes, kept in the kcp session
+// directory and pointed at one workspace each. `kedge dev kcp use` only ever
+// rewrites such a copy, never a kubeconfig of the user's own.
+const (
+	// kcpSessionEnv names, in a kcp shell, the session kubeconfig, which is
+	// also its KUBECONFIG; kcpWorkspaceEnv the workspace it was opened in.
+	kcpSessionEnv   = "KEDGE_DEV_KCP_KUBECONFIG"
+	kcpWorkspaceEnv = "KEDGE_DEV_KCP_WORKSPACE"
+
+	// kcpEnvKubeconfigFile is the session kubeconfig `kedge dev kcp env`
+	// writes.
+	kcpEnvKubeconfigFile = "env.kubeconfig"
+)
+
+var kcpWorkspaceSegmentRE = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// KCPShellOptions contains the options for the dev kcp commands.
+type KCPShellOptions struct {
+	Streams genericclioptions.IOStreams
+
+	// Kubeconfig is the kcp admin kubeconfig written by
+	// `kedge dev init --with-external-kcp`.
+	Kubeconfig string
+	// Dir holds the session kubeconfigs.
+	Dir   string
+	Shell string
+
+	// Workspace is the workspace to open or switch to: an absolute path
+	// (root:…), "..", or a path relative to the current workspace.
+	Workspace string
+}
+
+// NewKCPShellOptions creates a new KCPShellOptions.
+func NewKCPShellOptions(streams genericclioptions.IOStreams) *KCPShellOptions {
+	return &KCPShellOptions{
+		Streams:    streams,
+		Kubeconfig: kcpExternalKubeconfigFile,
+	}
+}
+
+// AddCmdFlags adds command line flags.
+func (o *KCPShellOptions) AddCmdFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.Kubeconfig, "kcp-kubeconfig", o.Kubeconfig, "kcp admin kubeconfig written by `kedge dev init --with-external-kcp`")
+	cmd.Flags().StringVar(&o.Dir, "session-dir", "", "Directory holding the session kubeconfigs (default ~/.kedge/dev/kcp)")
+}
+
+// AddShellFlags adds the flags of `kedge dev kcp shell`.
+func (o *KCPShellOptions) AddShellFlags(cmd *cobra.Command) {
+	o.AddCmdFlags(cmd)
+	cmd.Flags().StringVar(&o.Shell, "shell", "", "Shell to start (default $SHELL, else /bin/sh)")
+}
+
+// Complete completes the options.
+func (o *KCPShellOptions) Complete(args []string) error {
+	if len(args) > 0 {
+		o.Workspace = args[0]
+	}
+	if o.Dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("resolving home directory: %w", err)
+		}
+		o.Dir = filepath.Join(home, ".kedge", "dev", "kcp")
+	}
+	if o.Shell == "" {
+		o.Shell = os.Getenv("SHELL")
+	}
+	if o.Shell == "" {
+		o.Shell = "/bin/sh"
+	}
+	abs, err := filepath.Abs(o.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	o.Kubeconfig = abs
+	return nil
+}
+
+// RunShell starts a shell whose KUBECONFIG is a session kubeconfig pointed at
+// o.Workspace ("root" by default). The session kubeconfig is removed when the
+// shell exits.
+func (o *KCPShellOptions) RunShell(ctx context.Context) error {
+	if os.Getenv(kcpSessionEnv) != "" {
+		return fmt.Errorf("already in a kcp shell; switch workspaces with `kedge dev kcp use`")
+	}
+	workspace, err := resolveKCPWorkspace("root", o.Workspace)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(o.Dir, 0o700); err != nil {
+		return fmt.Errorf("creating kcp session directory: %w", err)
+	}
+	session := filepath.Join(o.Dir, fmt.Sprintf("shell-%d.kubeconfig", os.Getpid()))
+	if err := o.writeSession(session, workspace); err != nil {
+		return err
+	}
+	defer os.Remove(session) //nolint:errcheck
+
+	_, _ = fmt.Fprintf(o.Streams.ErrOut, "kcp shell in workspace %s (KUBECONFIG=%s)\n", workspace, session)
+	_, _ = fmt.Fprintf(o.Streams.ErrOut, "Switch workspaces with %s; exit to leave.\n", blueCommand("kedge dev kcp use <workspace>"))
+
+	shell := exec.CommandContext(ctx, o.Shell)
+	shell.Stdin, shell.Stdout, shell.Stderr = o.Streams.In, o.Streams.Out, o.Streams.ErrOut
+	shell.Env = append(os.Environ(),
+		"KUBECONFIG="+session,
+		kcpSessionEnv+"="+session,
+		kcpWorkspaceEnv+"="+workspace,
+	)
+	if err := shell.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			// The shell's last command failed; that is not ours to report.
+			return nil
+		}
+		return fmt.Errorf("running %s: %w", o.Shell, err)
+	}
+	return nil
+}
+
+// RunEnv writes a session kubeconfig pointed at o.Workspace ("root" by
+// default) and prints the export that selects it, for
+// `eval "$(kedge dev kcp env)"`.
+func (o *KCPShellOptions) RunEnv() error {
+	workspace, err := resolveKCPWorkspace("root", o.Workspace)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(o.Dir, 0o700); err != nil {
+		return fmt.Errorf("creating kcp session directory: %w", err)
+	}
+	session := filepath.Join(o.Dir, kcpEnvKubeconfigFile)
+	if err := o.writeSession(session, workspace); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(o.Streams.Out, "export KUBECONFIG=%s\n", shellQuote(session))
+	_, _ = fmt.Fprintf(o.Streams.ErrOut, "# kcp workspace %s\n", workspace)
+	return nil
+}
+
+// RunUse points the session kubeconfig in KUBECONFIG at o.Workspace, resolved
+// against its current workspace, or prints the current workspace when
+// o.Workspace is empty.
+func (o *KCPShellOptions) RunUse() error {
+	session := os.Getenv("KUBECONFIG")
+	if session == "" || filepath.Dir(session) != filepath.Clean(o.Dir) {
+		return fmt.Errorf("KUBECONFIG is not a kcp session kubeconfig; start one with `kedge dev kcp shell` or `eval \"$(kedge dev kcp env)\"`")
+	}
+	config, err := clientcmd.LoadFromFile(session)
+	if err != nil {
+		return fmt.Errorf("loading %s: %w", session, err)
+	}
+	cluster, err := currentCluster(config)
+	if err != nil {
+		return err
+	}
+	current, err := kcpWorkspaceOf(cluster.Server)
+	if err != nil {
+		return err
+	}
+	if o.Workspace == "" {
+		_, _ = fmt.Fprintln(o.Streams.Out, current)
+		return nil
+	}
+	workspace, err := resolveKCPWorkspace(current, o.Workspace)
+	if err != nil {
+		return err
+	}
+	if cluster.Server, err = kcpWorkspaceServer(cluster.Server, workspace); err != nil {
+		return err
+	}
+	if err := clientcmd.WriteToFile(*config, session); err != nil {
+		return fmt.Errorf("writing %s: %w", session, err)
+	}
+	_, _ = fmt.Fprintf(o.Streams.ErrOut, "Current workspace is %s\n", workspace)
+	return nil
+}
+
+// writeSession writes the admin kubeconfig to path, pointed at workspace.
+func (o *KCPShellOptions) writeSession(path, workspace string) error {
+	config, err := clientcmd.LoadFromFile(o.Kubeconfig)
+	if err != nil {
+		return fmt.Errorf("loading the kcp admin kubeconfig (run `kedge dev init --with-external-kcp` first): %w", err)
+	}
+	cluster, err := currentCluster(config)
+	if err != nil {
+		return err
+	}
+	if cluster.Server, err = kcpWorkspaceServer(cluster.Server, workspace); err != nil {
+		return err
+	}
+	if err := clientcmd.WriteToFile(*config, path); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// currentCluster returns the cluster of config's current context.
+func currentCluster(config *clientcmdapi.Config) (*clientcmdapi.Cluster, error) {
+	kctx, ok := config.Contexts[config.CurrentContext]
+	if !ok {
+		return nil, fmt.Errorf("kubeconfig has no current context")
+	}
+	cluster, ok := config.Clusters[kctx.Cluster]
+	if !ok {
+		return nil, fmt.Errorf("kubeconfig has no cluster %q", kctx.Cluster)
+	}
+	return cluster, nil
+}
+
+// resolveKCPWorkspace resolves target against the current workspace: an
+// absolute path starts at root, ".." is the parent, and anything else is
+// below current. An empty target is current.
+func resolveKCPWorkspace(current, target string) (string, error) {
+	var path string
+	switch {
+	case target == "":
+		path = current
+	case target == "..":
+		i := strings.LastIndex(current, ":")
+		if i < 0 {
+			return "", fmt.Errorf("workspace %s has no parent", current)
+		}
+		path = current[:i]
+	case target == "root" || strings.HasPrefix(target, "root:"):
+		path = target
+	default:
+		path = current + ":" + target
+	}
+	segments := strings.Split(path, ":")
+	if segments[0] != "root" {
+		return "", fmt.Errorf("workspace %q is not below root", path)
+	}
+	for _, s := range segments[1:] {
+		if !kcpWorkspaceSegmentRE.MatchString(s) {
+			return "", fmt.Errorf("invalid workspace %q: %q must be lowercase letters, digits and '-'", path, s)
+		}
+	}
+	return path, nil
+}
+
+// kcpWorkspaceServer returns server pointed at workspace.
+func kcpWorkspaceServer(server, workspace string) (string, error) {
+	u, err := url.Parse(server)
+	if err != nil {
+		return "", fmt.Errorf("parsing kcp server %q: %w", server, err)
+	}
+	u.Path = "/clusters/" + workspace
+	u.RawPath = ""
+	return u.String(), nil
+}
+
+// kcpWorkspaceOf returns the workspace server points at.
+func kcpWorkspaceOf(server string) (string, error) {
+	u, err := url.Parse(server)
+	if err != nil {
+		return "", fmt.Errorf("parsing kcp server %q: %w", server, err)
+	}
+	workspace, ok := strings.CutPrefix(u.Path, "/clusters/")
+	if !ok || workspace == "" {
+		return "", fmt.Errorf("kcp server %q names no workspace", server)
+	}
+	return strings.TrimSuffix(workspace, "/"), nil
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,497 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/kind/pkg/cluster"
+
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
+)
+
+// snapshotPaths are the node directories (relative to /) a snapshot holds:
+// the kind cluster's etcd, and the local-path volumes, which hold the hub's
+// data dir with its embedded kcp (and external kcp's etcd with
+// --with-external-kcp). Everything else on a node is recreated from these
+// when the kubelet starts.
+var snapshotPaths = []string{"var/lib/etcd", "var/local-path-provisioner"}
+
+// snapshotManifestFile describes a snapshot inside its directory.
+const snapshotManifestFile = "snapshot.json"
+
+var snapshotNameRE = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// snapshotManifest records what a snapshot holds.
+type snapshotManifest struct {
+	Name     string            `json:"name"`
+	Created  time.Time         `json:"created"`
+	Clusters []snapshotCluster `json:"clusters"`
+}
+
+// snapshotCluster is one kind cluster's part of a snapshot.
+type snapshotCluster struct {
+	Name string `json:"name"`
+	Node string `json:"node"`
+	// CAFingerprint is the SHA-256 of the cluster's CA certificate. A
+	// snapshot only restores into the cluster it was taken from: in a
+	// recreated cluster every credential in the restored etcd is void.
+	CAFingerprint string `json:"caFingerprint"`
+	// Archive is the gzipped tar of snapshotPaths, relative to the snapshot
+	// directory.
+	Archive string `json:"archive"`
+	Size    int64  `json:"size"`
+}
+
+// SnapshotOptions contains the options for the dev snapshot and restore
+// commands.
+type SnapshotOptions struct {
+	Streams genericclioptions.IOStreams
+
+	HubClusterName      string
+	AgentClusterName    string
+	Dir                 string
+	List                bool
+	WaitForReadyTimeout time.Duration
+
+	// Name is the snapshot to take or restore.
+	Name string
+}
+
+// NewSnapshotOptions creates a new SnapshotOptions.
+func NewSnapshotOptions(streams genericclioptions.IOStreams) *SnapshotOptions {
+	return &SnapshotOptions{
+		Streams:          streams,
+		HubClusterName:   "kedge-hub",
+		AgentClusterName: "kedge-agent",
+	}
+}
+
+// AddCmdFlags adds command line flags.
+func (o *SnapshotOptions) AddCmdFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.HubClusterName, "hub-cluster-name", o.HubClusterName, "Name of the hub cluster in dev mode")
+	cmd.Flags().StringVar(&o.AgentClusterName, "agent-cluster-name", o.AgentClusterName, "Name of the agent cluster in dev mode (numbered workers <name>-1, -2, … are included)")
+	cmd.Flags().StringVar(&o.Dir, "snapshot-dir", "", "Directory holding snapshots (default ~/.kedge/dev/snapshots)")
+	cmd.Flags().BoolVar(&o.List, "list", false, "List the snapshots instead")
+	cmd.Flags().DurationVar(&o.WaitForReadyTimeout, "wait-for-ready-timeout", 3*time.Minute, "Timeout for waiting for each cluster's API server after it is resumed")
+}
+
+// Complete completes the options.
+func (o *SnapshotOptions) Complete(args []string) error {
+	if len(args) > 0 {
+		o.Name = args[0]
+	}
+	if o.Dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("resolving home directory: %w", err)
+		}
+		o.Dir = filepath.Join(home, ".kedge", "dev", "snapshots")
+	}
+	return nil
+}
+
+// Validate validates the options.
+func (o *SnapshotOptions) Validate() error {
+	if o.List || o.Name == "" {
+		return nil
+	}
+	if !snapshotNameRE.MatchString(o.Name) {
+		return fmt.Errorf("snapshot name %q must be lowercase letters, digits, '.', '_' or '-'", o.Name)
+	}
+	return nil
+}
+
+// RunSnapshot snapshots every dev kind cluster into a new snapshot named
+// o.Name (a timestamp when empty). Each cluster is stopped while it is
+// copied, so the copy is consistent, and resumed afterwards.
+func (o *SnapshotOptions) RunSnapshot(ctx context.Context) error {
+	if o.List {
+		return o.RunList()
+	}
+	if o.Name == "" {
+		o.Name = time.Now().Format("20060102-150405")
+	}
+	dir := filepath.Join(o.Dir, o.Name)
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("snapshot %q already exists in %s", o.Name, o.Dir)
+	}
+
+	clusters, err := o.devClusters()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("creating snapshot directory: %w", err)
+	}
+	manifest := snapshotManifest{Name: o.Name, Created: time.Now().UTC()}
+	for _, c := range clusters {
+		sc, err := o.snapshotCluster(ctx, dir, c)
+		if err != nil {
+			_ = os.RemoveAll(dir)
+			return err
+		}
+		manifest.Clusters = append(manifest.Clusters, sc)
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, snapshotManifestFile), data, 0o600); err != nil {
+		_ = os.RemoveAll(dir)
+		return fmt.Errorf("writing snapshot manifest: %w", err)
+	}
+	_, _ = fmt.Fprintf(o.Streams.ErrOut, "Snapshot %q saved to %s\n", o.Name, dir)
+	_, _ = fmt.Fprintf(o.Streams.ErrOut, "Restore it with: %s\n", blueCommand("kedge dev restore "+o.Name))
+	return nil
+}
+
+func (o *SnapshotOptions) snapshotCluster(ctx context.Context, dir string, c devCluster) (snapshotCluster, error) {
+	sc := snapshotCluster{Name: c.name, Node: c.node, Archive: c.name + ".tar.gz"}
+	fingerprint, err := caFingerprint(ctx, c.node)
+	if err != nil {
+		return sc, err
+	}
+	sc.CAFingerprint = fingerprint
+
+	_, _ = fmt.Fprintf(o.Streams.ErrOut, "Stopping cluster %s\n", c.name)
+	if err := stopNode(ctx, c.node); err != nil {
+		_ = startNode(context.WithoutCancel(ctx), c.node)
+		return sc, err
+	}
+	// Resume however the copy went.
+	defer func() {
+		_, _ = fmt.Fprintf(o.Streams.ErrOut, "Resuming cluster %s\n", c.name)
+		if err := startNode(context.WithoutCancel(ctx), c.node); err != nil {
+			_, _ = fmt.Fprintf(o.Streams.ErrOut, "Warning: resuming %s failed: %v\n", c.name, err)
+			return
+		}
+		o.waitForAPIServer(ctx, c.name)
+	}()
+
+	_, _ = fmt.Fprintf(o.Streams.ErrOut, "Copying %s state\n", c.name)
+	f, err := os.OpenFile(filepath.Join(dir, sc.Archive), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return sc, fmt.Errorf("creating archive: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	// tar fails on a missing path: a cluster without volumes has no
+	// local-path directory yet.
+	script := "cd / && tar -czf - $(ls -d " + strings.Join(snapshotPaths, " ") + " 2>/dev/null)"
+	if err := dockerExec(ctx, c.node, nil, f, "sh", "-c", script); err != nil {
+		return sc, fmt.Errorf("archiving %s: %w", c.name, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return sc, err
+	}
+	sc.Size = info.Size()
+	return sc, nil
+}
+
+// RunRestore replaces the state of every cluster in snapshot o.Name with
+// the snapshot's. The clusters must be the ones the snapshot was taken from.
+func (o *SnapshotOptions) RunRestore(ctx context.Context) error {
+	if o.List || o.Name == "" {
+		return o.RunList()
+	}
+	dir := filepath.Join(o.Dir, o.Name)
+	manifest, err := readSnapshotManifest(dir)
+	if err != nil {
+		return err
+	}
+
+	// Check every cluster before touching any.
+	existing, err := o.devClusters()
+	if err != nil {
+		return err
+	}
+	nodes := map[string]string{}
+	for _, c := range existing {
+		nodes[c.name] = c.node
+	}
+	names := make([]string, 0, len(manifest.Clusters))
+	for _, sc := range manifest.Clusters {
+		node, ok := nodes[sc.Name]
+		if !ok {
+			return fmt.Errorf("snapshot %q holds kind cluster %s, which does not exist", o.Name, sc.Name)
+		}
+		fingerprint, err := caFingerprint(ctx, node)
+		if err != nil {
+			return err
+		}
+		if fingerprint != sc.CAFingerprint {
+			return fmt.Errorf("kind cluster %s was recreated since snapshot %q was taken; a snapshot only restores into the clusters it was taken from", sc.Name, o.Name)
+		}
+		names = append(names, sc.Name)
+	}
+
+	ok, err := ui.Confirm(o.Streams.In, o.Streams.ErrOut,
+		fmt.Sprintf("Replace the state of kind cluster(s) %s with snapshot %q?", strings.Join(names, ", "), o.Name))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ui.Aborted("dev restore")
+	}
+
+	for _, sc := range manifest.Clusters {
+		if err := o.restoreCluster(ctx, dir, sc, nodes[sc.Name]); err != nil {
+			return err
+		}
+	}
+	_, _ = fmt.Fprintf(o.Streams.ErrOut, "Snapshot %q restored\n", o.Name)
+	return nil
+}
+
+func (o *SnapshotOptions) restoreCluster(ctx context.Context, dir string, sc snapshotCluster, node string) error {
+	f, err := os.Open(filepath.Join(dir, sc.Archive))
+	if err != nil {
+		return fmt.Errorf("opening archive: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	_, _ = fmt.Fprintf(o.Streams.ErrOut, "Stopping cluster %s\n", sc.Name)
+	if err := stopNode(ctx, node); err != nil {
+		_ = startNode(context.WithoutCancel(ctx), node)
+		return err
+	}
+	_, _ = fmt.Fprintf(o.Streams.ErrOut, "Restoring %s state\n", sc.Name)
+	script := "cd / && rm -rf " + strings.Join(snapshotPaths, " ") + " && tar -xzf -"
+	restoreErr := dockerExec(ctx, node, f, io.Discard, "sh", "-c", script)
+
+	_, _ = fmt.Fprintf(o.Streams.ErrOut, "Resuming cluster %s\n", sc.Name)
+	if err := startNode(context.WithoutCancel(ctx), node); err != nil {
+		return fmt.Errorf("resuming %s: %w", sc.Name, err)
+	}
+	if restoreErr != nil {
+		return fmt.Errorf("restoring %s: %w", sc.Name, restoreErr)
+	}
+	o.waitForAPIServer(ctx, sc.Name)
+	return nil
+}
+
+// RunList prints the snapshots in o.Dir.
+func (o *SnapshotOptions) RunList() error {
+	entries, err := os.ReadDir(o.Dir)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading %s: %w", o.Dir, err)
+	}
+	var manifests []snapshotManifest
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		m, err := readSnapshotManifest(filepath.Join(o.Dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		manifests = append(manifests, m)
+	}
+	if len(manifests) == 0 {
+		_, _ = fmt.Fprintf(o.Streams.ErrOut, "No snapshots in %s\n", o.Dir)
+		return nil
+	}
+	sort.Slice(manifests, func(i, j int) bool { return manifests[i].Created.Before(manifests[j].Created) })
+	tw := tabwriter.NewWriter(o.Streams.Out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "NAME\tCREATED\tCLUSTERS\tSIZE")
+	for _, m := range manifests {
+		var names []string
+		var size int64
+		for _, c := range m.Clusters {
+			names = append(names, c.Name)
+			size += c.Size
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", m.Name, m.Created.Local().Format(time.DateTime),
+			strings.Join(names, ","), formatBytes(size))
+	}
+	return tw.Flush()
+}
+
+// devCluster is a running dev kind cluster and its (single) node container.
+type devCluster struct {
+	name string
+	node string
+}
+
+// devClusters returns the hub cluster and every agent cluster that exist,
+// hub first.
+func (o *SnapshotOptions) devClusters() ([]devCluster, error) {
+	provider := cluster.NewProvider()
+	all, err := provider.List()
+	if err != nil {
+		return nil, fmt.Errorf("listing kind clusters: %w", err)
+	}
+	var names []string
+	for _, name := range all {
+		if name == o.HubClusterName || name == o.AgentClusterName || isNumberedAgent(name, o.AgentClusterName) {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == o.HubClusterName) != (names[j] == o.HubClusterName) {
+			return names[i] == o.HubClusterName
+		}
+		return names[i] < names[j]
+	})
+	if len(names) == 0 || names[0] != o.HubClusterName {
+		return nil, fmt.Errorf("kind cluster %s not found (did you run `kedge dev init`?)", o.HubClusterName)
+	}
+
+	clusters := make([]devCluster, 0, len(names))
+	for _, name := range names {
+		nodes, err := provider.ListNodes(name)
+		if err != nil {
+			return nil, fmt.Errorf("listing nodes of %s: %w", name, err)
+		}
+		if len(nodes) != 1 {
+			return nil, fmt.Errorf("kind cluster %s has %d nodes; only single-node dev clusters can be snapshotted", name, len(nodes))
+		}
+		clusters = append(clusters, devCluster{name: name, node: nodes[0].String()})
+	}
+	return clusters, nil
+}
+
+// isNumberedAgent reports whether name is <agent>-<n>, the agent clusters
+// `kedge dev init --worker-count N` creates for N > 1.
+func isNumberedAgent(name, agent string) bool {
+	n, ok := strings.CutPrefix(name, agent+"-")
+	if !ok {
+		return false
+	}
+	_, err := strconv.Atoi(n)
+	return err == nil
+}
+
+func readSnapshotManifest(dir string) (snapshotManifest, error) {
+	var m snapshotManifest
+	data, err := os.ReadFile(filepath.Join(dir, snapshotManifestFile))
+	if err != nil {
+		return m, fmt.Errorf("reading snapshot %s: %w", filepath.Base(dir), err)
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return m, fmt.Errorf("decoding snapshot %s: %w", filepath.Base(dir), err)
+	}
+	return m, nil
+}
+
+// stopNode stops the kubelet and every container on a kind node, so etcd
+// and kcp have flushed and closed their data before it is copied.
+func stopNode(ctx context.Context, node string) error {
+	script := "systemctl stop kubelet && crictl ps -q | xargs -r crictl stop --timeout 30 >/dev/null"
+	if err := dockerExec(ctx, node, nil, io.Discard, "sh", "-c", script); err != nil {
+		return fmt.Errorf("stopping %s: %w", node, err)
+	}
+	return nil
+}
+
+// startNode starts the kubelet again; it restarts the static control-plane
+// pods and, once the API server is up, every other pod.
+func startNode(ctx context.Context, node string) error {
+	return dockerExec(ctx, node, nil, io.Discard, "systemctl", "start", "kubelet")
+}
+
+// caFingerprint returns the SHA-256 of the node's cluster CA certificate.
+func caFingerprint(ctx context.Context, node string) (string, error) {
+	var out bytes.Buffer
+	if err := dockerExec(ctx, node, nil, &out, "sha256sum", "/etc/kubernetes/pki/ca.crt"); err != nil {
+		return "", fmt.Errorf("reading the CA of %s: %w", node, err)
+	}
+	fields := strings.Fields(out.String())
+	if len(fields) == 0 {
+		return "", fmt.Errorf("reading the CA of %s: empty output", node)
+	}
+	return fields[0], nil
+}
+
+// dockerExec runs args in the node container, with stdin (if any) and
+// stdout attached. Stderr is returned in the error.
+func dockerExec(ctx context.Context, node string, stdin io.Reader, stdout io.Writer, args ...string) error {
+	dockerArgs := []string{"exec"}
+	if stdin != nil {
+		dockerArgs = append(dockerArgs, "-i")
+	}
+	dockerArgs = append(dockerArgs, node)
+	cmd := exec.CommandContext(ctx, "docker", append(dockerArgs, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// waitForAPIServer waits until the cluster's API server answers again, using
+// the kubeconfig `kedge dev init` wrote. Without that file, or on timeout, it
+// only warns: the cluster keeps starting in the background.
+func (o *SnapshotOptions) waitForAPIServer(ctx context.Context, clusterName string) {
+	restConfig, err := loadRestConfigFromFile(clusterName + ".kubeconfig")
+	if err != nil {
+		return
+	}
+	restConfig.Timeout = 5 * time.Second
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return
+	}
+	deadline := time.Now().Add(o.WaitForReadyTimeout)
+	for time.Now().Before(deadline) {
+		if _, err := client.Discovery().ServerVersion(); err == nil {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(2 * time.Second):
+		}
+	}
+	_, _ = fmt.Fprintf(o.Streams.ErrOut, "Warning: API server of %s not ready after %s; it is still starting\n", clusterName, o.WaitForReadyTimeout)
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
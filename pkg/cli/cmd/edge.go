@@ -20,19 +20,25 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
 
+	"github.com/faroshq/faros-kedge/pkg/agent"
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
 	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
 )
 
 func newEdgeCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "edge",
-		Aliases: []string{"edges", "devices"},
+		Aliases: mustResource("edges").aliases("edge"),
 		Short:   "Manage edges",
 	}
 
@@ -40,9 +46,23 @@ func newEdgeCommand() *cobra.Command {
 		newEdgeCreateCommand(),
 		newEdgeListCommand(),
 		newEdgeGetCommand(),
+		newEdgeDescribeCommand(),
 		newEdgeDeleteCommand(),
+		newEdgeCordonCommand(),
+		newEdgeUncordonCommand(),
 		newEdgeJoinCommandCommand(),
 		newEdgeUpgradeCommand(),
+		newEdgeRebootCommand(),
+		newEdgeShutdownCommand(),
+		newEdgeSignURLCommand(),
+		newEdgeTLSProxyCommand(),
+		newEdgeCpCommand(),
+		newEdgeWaitSSHCommand(),
+		newEdgeWaitK8sCommand(),
+		newEdgeRequestsCommand(),
+		newEdgeApproveCommand(),
+		newEdgeDenyCommand(),
+		newEdgeApproveCertificateCommand(),
 	)
 
 	return cmd
@@ -51,15 +71,41 @@ func newEdgeCommand() *cobra.Command {
 func newEdgeCreateCommand() *cobra.Command {
 	var labels map[string]string
 	var edgeType string
+	var location agent.Location
+	var registration string
+	onboard := onboardOptions{chart: defaultAgentChart, timeout: 5 * time.Minute}
 
 	cmd := &cobra.Command{
 		Use:   "create <name>",
 		Short: "Create an edge",
-		Args:  cobra.ExactArgs(1),
+		Long: `Create an edge and print the command that connects its agent.
+
+With --from-kubeconfig, the edge is also onboarded in one go: the agent Helm
+chart is installed into the cluster the kubeconfig points at, joined with the
+edge's token, and the command waits until the edge is Ready.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
 			ctx := context.Background()
 
+			if onboard.kubeconfig != "" && edgeType == "server" {
+				return fmt.Errorf("--from-kubeconfig onboards kubernetes edges only")
+			}
+			switch agent.RegistrationMode(registration) {
+			case agent.RegistrationAgent:
+			case agent.RegistrationExternal:
+				if onboard.kubeconfig != "" {
+					return fmt.Errorf("--from-kubeconfig cannot be combined with --registration-mode=external")
+				}
+			default:
+				return fmt.Errorf("invalid --registration-mode %q: must be %q or %q", registration, agent.RegistrationAgent, agent.RegistrationExternal)
+			}
+
+			locationSpec, err := location.Spec()
+			if err != nil {
+				return fmt.Errorf("invalid location: %w", err)
+			}
+
 			dynClient, err := loadDynamicClient()
 			if err != nil {
 				return err
@@ -94,16 +140,29 @@ func newEdgeCreateCommand() *cobra.Command {
 				}
 				edge.Object["metadata"].(map[string]interface{})["labels"] = lbls
 			}
+			if locationSpec != nil {
+				edge.Object["spec"].(map[string]interface{})["location"] = locationSpec
+			}
+			if registration == string(agent.RegistrationExternal) {
+				edge.SetAnnotations(map[string]string{registrationAnnotation: registration})
+			}
 
 			_, err = dynClient.Resource(gvr).Create(ctx, edge, metav1.CreateOptions{})
-			if err != nil {
+			switch {
+			case apierrors.IsAlreadyExists(err) && onboard.kubeconfig != "":
+				// A retried onboarding picks up where the last one stopped.
+				fmt.Printf("Edge %q already exists; continuing onboarding\n", name)
+			case err != nil:
 				return fmt.Errorf("creating edge %q: %w", name, err)
+			default:
+				fmt.Printf("✓ Edge %q created\n", name)
 			}
 
-			fmt.Printf("✓ Edge %q created\n", name)
-
 			// Poll for the join token (set by the hub controller on creation).
 			joinToken, err := pollJoinTokenDynamic(ctx, name, 30*time.Second)
+			if err != nil && onboard.kubeconfig != "" {
+				return fmt.Errorf("retrieving join token for edge %q: %w", name, err)
+			}
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: could not retrieve join token: %v\n", err)
 				fmt.Printf("\nRun 'kedge edge join-command %s' to print the join command once the token is available.\n", name)
@@ -113,13 +172,33 @@ func newEdgeCreateCommand() *cobra.Command {
 			// Get hub URL from the current kubeconfig.
 			hubURL := loadHubURL()
 
+			if onboard.kubeconfig != "" {
+				if err := onboardEdge(ctx, name, hubURL, joinToken, onboard); err != nil {
+					return fmt.Errorf("%w\nThe edge exists; re-run with --from-kubeconfig to retry, or run 'kedge edge join-command %s' to connect it by hand", err, name)
+				}
+				return nil
+			}
+
 			printJoinCommand(name, edgeType, hubURL, joinToken)
+			if registration == string(agent.RegistrationExternal) {
+				fmt.Printf("The agent may only report this edge's status: add --registration-mode=external\n")
+				fmt.Printf("to the agent command (Helm: --set agent.registrationMode=external).\n")
+			}
 			return nil
 		},
 	}
 
 	cmd.Flags().StringToStringVar(&labels, "labels", nil, "Labels for this edge (key=value pairs)")
 	cmd.Flags().StringVar(&edgeType, "type", "kubernetes", "Edge type: kubernetes or server")
+	cmd.Flags().StringVar(&location.Coordinates, "location", "", "Coordinates of the edge as \"<latitude>,<longitude>\", shown on the hub's fleet map")
+	cmd.Flags().StringVar(&location.Address, "location-address", "", "Postal address or site name of the edge")
+	cmd.Flags().StringVar(&location.Region, "location-region", "", "Region of the edge, e.g. \"eu-west\"")
+	cmd.Flags().StringVar(&registration, "registration-mode", string(agent.RegistrationAgent), `Who writes the edge once created: "agent" (its agent keeps labels and location up to date) or "external" (its agent may only read it and report its status)`)
+	cmd.Flags().StringVar(&onboard.kubeconfig, "from-kubeconfig", "", "Kubeconfig of the cluster to onboard: installs the agent chart there and waits for the edge to be Ready")
+	cmd.Flags().StringVar(&onboard.context, "from-context", "", "Context in --from-kubeconfig to use (default: its current context)")
+	cmd.Flags().StringVar(&onboard.chart, "agent-chart", onboard.chart, "Agent Helm chart installed by --from-kubeconfig: an OCI reference or a local path")
+	cmd.Flags().StringVar(&onboard.chartVersion, "agent-chart-version", "", "Version of --agent-chart (default: latest)")
+	cmd.Flags().DurationVar(&onboard.timeout, "wait-timeout", onboard.timeout, "How long --from-kubeconfig waits for the edge to become Ready")
 
 	return cmd
 }
@@ -216,6 +295,11 @@ func printJoinCommand(name, edgeType, hubURL, joinToken string) {
 	fmt.Printf("Run 'kedge edge join-command %s' to print this again.\n", name)
 }
 
+// registrationAnnotation marks an edge provisioned for an agent running with
+// --registration-mode=external; the hub then grants that agent read access
+// to the edge and write access to its status only.
+const registrationAnnotation = "edges.kedge.faros.sh/registration"
+
 // newEdgeJoinCommandCommand returns the 'kedge edge join-command <name>' subcommand.
 func newEdgeJoinCommandCommand() *cobra.Command {
 	cmd := &cobra.Command{
@@ -260,48 +344,32 @@ func newEdgeJoinCommandCommand() *cobra.Command {
 }
 
 func newEdgeListCommand() *cobra.Command {
-	return &cobra.Command{
+	var opts listOptions
+
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all edges",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := context.Background()
+		Example: `  # Edges with their hostname, tunnel holder and labels
+  kedge edge list -o wide
 
+  # Follow edges as they connect and disconnect
+  kedge edge list --watch`,
+		RunE: func(cmd *cobra.Command, args []string) error {
 			dynClient, err := loadDynamicClient()
 			if err != nil {
 				return fmt.Errorf("not logged in — run: kedge login --hub-url <hub-url>\n(original error: %w)", err)
 			}
-
-			items, err := listAllEdges(ctx, dynClient)
-			if err != nil {
-				return fmt.Errorf("listing edges: %w", err)
-			}
-
-			if len(items) == 0 {
-				fmt.Println("No edges found.")
-				return nil
-			}
-
-			tw := newTabWriter(os.Stdout)
-			printRow(tw, "NAME", "TYPE", "PHASE", "CONNECTED", "AGENT VERSION", "AGE")
-
-			for _, item := range items {
-				// The kind is the type: KubernetesCluster → kubernetes, LinuxServer → server.
-				edgeType := "kubernetes"
-				if item.GetKind() == "LinuxServer" {
-					edgeType = "server"
+			return opts.print(cmd.Context(), os.Stdout, "No edges found.", func(ctx context.Context) (*ui.Table, error) {
+				items, err := listAllEdges(ctx, dynClient)
+				if err != nil {
+					return nil, fmt.Errorf("listing edges: %w", err)
 				}
-				phase := getNestedString(item, "status", "phase")
-				connected, _, _ := unstructuredNestedBool(item.Object, "status", "connected")
-				agentVersion := getNestedString(item, "status", "agentVersion")
-				age := formatAge(item.GetCreationTimestamp().Time)
-				printRow(tw, item.GetName(), formatStringOrDash(edgeType), formatStringOrDash(phase),
-					fmt.Sprintf("%v", connected), formatStringOrDash(agentVersion), age)
-			}
-
-			_ = tw.Flush()
-			return nil
+				return edgeTable(items, time.Now()), nil
+			})
 		},
 	}
+	opts.addFlags(cmd)
+	return cmd
 }
 
 func newEdgeGetCommand() *cobra.Command {
@@ -334,6 +402,7 @@ func newEdgeGetCommand() *cobra.Command {
 			fmt.Printf("Phase:         %s\n", formatStringOrDash(phase))
 			fmt.Printf("Connected:     %v\n", connected)
 			fmt.Printf("Hostname:      %s\n", formatStringOrDash(hostname))
+			fmt.Printf("Tunnel:        %s\n", formatEdgeTunnel(*edge, time.Now()))
 			fmt.Printf("WorkspaceURL:  %s\n", formatStringOrDash(workspaceURL))
 			fmt.Printf("Created:       %s\n", edge.GetCreationTimestamp().Format("2006-01-02 15:04:05"))
 
@@ -354,12 +423,19 @@ func newEdgeDeleteCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:   "delete <name>",
 		Short: "Delete an edge",
-		Args:  cobra.ExactArgs(1),
+		Long: `Delete an edge. Its agent loses access to the hub and must be re-registered
+to reconnect. At a terminal you are asked to confirm (--yes skips the prompt);
+with stdin redirected, as in scripts, the edge is deleted without asking.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
 			ctx := context.Background()
 
-			dynClient, err := loadDynamicClient()
+			config, err := loadRestConfig()
+			if err != nil {
+				return fmt.Errorf("loading kubeconfig: %w", err)
+			}
+			dynClient, err := dynamic.NewForConfig(config)
 			if err != nil {
 				return err
 			}
@@ -368,16 +444,57 @@ func newEdgeDeleteCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			if err := dynClient.Resource(gvr).Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+			ok, err := ui.ConfirmOnTerminal(cmd.InOrStdin(), cmd.ErrOrStderr(), fmt.Sprintf("Delete edge %q?", name))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return ui.Aborted("edge delete")
+			}
+			// The hub may require a recent sign-in to delete an edge.
+			err = withStepUp(cmd, config, func(config *rest.Config) error {
+				dynClient, err := dynamic.NewForConfig(config)
+				if err != nil {
+					return err
+				}
+				return dynClient.Resource(gvr).Delete(ctx, name, metav1.DeleteOptions{})
+			})
+			if err != nil {
 				return fmt.Errorf("deleting edge %q: %w", name, err)
 			}
 
-			fmt.Printf("Edge %q deleted.\n", name)
+			ui.Infof(cmd.OutOrStdout(), "Edge %q deleted.\n", name)
 			return nil
 		},
 	}
 }
 
+// formatEdgeTunnel renders an edge's status.tunnel affinity hint as
+// "<holder> (zone <zone>, <endpoint>)". A lease that has run out was left by
+// a provider replica that died without releasing it and is marked stale.
+func formatEdgeTunnel(edge unstructured.Unstructured, now time.Time) string {
+	holder := getNestedString(edge, "status", "tunnel", "holder")
+	if holder == "" {
+		return "-"
+	}
+	var details []string
+	if zone := getNestedString(edge, "status", "tunnel", "zone"); zone != "" {
+		details = append(details, "zone "+zone)
+	}
+	if endpoint := getNestedString(edge, "status", "tunnel", "endpoint"); endpoint != "" {
+		details = append(details, endpoint)
+	}
+	renew, err := time.Parse(time.RFC3339, getNestedString(edge, "status", "tunnel", "renewTime"))
+	lease := time.Duration(getNestedInt(edge, "status", "tunnel", "leaseDurationSeconds")) * time.Second
+	if err == nil && lease > 0 && now.After(renew.Add(lease)) {
+		details = append(details, "stale since "+renew.Add(lease).Local().Format("2006-01-02 15:04:05"))
+	}
+	if len(details) == 0 {
+		return holder
+	}
+	return holder + " (" + strings.Join(details, ", ") + ")"
+}
+
 func unstructuredNestedBool(obj map[string]interface{}, fields ...string) (bool, bool, error) {
 	val, found, err := unstructuredNestedField(obj, fields...)
 	if err != nil || !found {
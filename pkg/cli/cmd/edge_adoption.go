@@ -0,0 +1,226 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
+	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+)
+
+// Adoption request phases (EdgeAdoptionRequest status.phase). Empty means
+// pending.
+const (
+	adoptionApproved = "Approved"
+	adoptionDenied   = "Denied"
+)
+
+func newEdgeRequestsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "requests",
+		Short: "List adoption requests from agents whose edge does not exist yet",
+		Long: `List EdgeAdoptionRequests. An agent files one when it starts before its edge
+exists and may not create it itself (agent flag --adoption=request, the
+default). Approve a request with "kedge edge approve <name>" to create the
+edge, or reject it with "kedge edge deny <name>".`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			dynClient, err := loadDynamicClient()
+			if err != nil {
+				return err
+			}
+			list, err := dynClient.Resource(kedgeclient.EdgeAdoptionRequestGVR).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return fmt.Errorf("listing adoption requests: %w", err)
+			}
+			if len(list.Items) == 0 {
+				fmt.Println("No adoption requests found.")
+				return nil
+			}
+			items := list.Items
+			sort.Slice(items, func(i, j int) bool { return items[i].GetName() < items[j].GetName() })
+
+			tw := newTabWriter(os.Stdout)
+			printRow(tw, "NAME", "TYPE", "HOSTNAME", "LABELS", "PHASE", "AGE")
+			for _, item := range items {
+				labels, _, _ := unstructured.NestedStringMap(item.Object, "spec", "labels")
+				printRow(tw,
+					item.GetName(),
+					getNestedString(item, "spec", "type"),
+					formatStringOrDash(getNestedString(item, "spec", "hostname")),
+					formatStringOrDash(formatLabels(labels)),
+					adoptionPhase(item),
+					formatAge(item.GetCreationTimestamp().Time),
+				)
+			}
+			_ = tw.Flush()
+			return nil
+		},
+	}
+}
+
+func newEdgeApproveCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "approve <name>",
+		Short: "Approve an agent's adoption request and create its edge",
+		Long: `Approve the EdgeAdoptionRequest <name>: create the edge it asks for, with the
+type and labels the agent reported, and mark the request approved. The
+waiting agent then registers and connects as usual.
+
+Check the request's hostname first ("kedge edge requests"): approving hands
+the edge to whichever agent filed it.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			ctx := context.Background()
+
+			dynClient, err := loadDynamicClient()
+			if err != nil {
+				return err
+			}
+			req, err := getAdoptionRequest(ctx, dynClient, name)
+			if err != nil {
+				return err
+			}
+			if adoptionPhase(*req) == adoptionDenied {
+				return fmt.Errorf("adoption request %q was denied; delete it to let the agent ask again", name)
+			}
+
+			edgeType := getNestedString(*req, "spec", "type")
+			kind, gvr := "KubernetesCluster", kedgeclient.KubernetesClusterGVR
+			if edgeType == "server" {
+				kind, gvr = "LinuxServer", kedgeclient.LinuxServerGVR
+			}
+			edge := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": gvr.Group + "/" + gvr.Version,
+				"kind":       kind,
+				"metadata": map[string]interface{}{
+					"name": name,
+				},
+				"spec": map[string]interface{}{},
+			}}
+			if labels, _, _ := unstructured.NestedStringMap(req.Object, "spec", "labels"); len(labels) > 0 {
+				edge.SetLabels(labels)
+			}
+			// An edge created in the meantime (e.g. by hand) is adopted as is.
+			if _, err := dynClient.Resource(gvr).Create(ctx, edge, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+				return fmt.Errorf("creating edge %q: %w", name, err)
+			}
+
+			if err := setAdoptionDecision(ctx, dynClient, req, adoptionApproved, "Edge created by kedge edge approve"); err != nil {
+				return err
+			}
+			ui.Infof(cmd.OutOrStdout(), "Adoption request %q approved; %s %q created.\n", name, kind, name)
+			return nil
+		},
+	}
+}
+
+func newEdgeDenyCommand() *cobra.Command {
+	var message string
+
+	cmd := &cobra.Command{
+		Use:   "deny <name>",
+		Short: "Deny an agent's adoption request",
+		Long: `Deny the EdgeAdoptionRequest <name>. The waiting agent stops with the given
+message. The request is kept so the agent does not simply ask again on
+restart; delete it to allow a new request.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			ctx := context.Background()
+
+			dynClient, err := loadDynamicClient()
+			if err != nil {
+				return err
+			}
+			req, err := getAdoptionRequest(ctx, dynClient, name)
+			if err != nil {
+				return err
+			}
+			if adoptionPhase(*req) == adoptionApproved {
+				return fmt.Errorf("adoption request %q was already approved; delete the edge to revoke it", name)
+			}
+			if err := setAdoptionDecision(ctx, dynClient, req, adoptionDenied, message); err != nil {
+				return err
+			}
+			ui.Infof(cmd.OutOrStdout(), "Adoption request %q denied.\n", name)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&message, "message", "denied by an administrator", "Reason reported to the agent")
+	return cmd
+}
+
+func getAdoptionRequest(ctx context.Context, dynClient dynamic.Interface, name string) (*unstructured.Unstructured, error) {
+	req, err := dynClient.Resource(kedgeclient.EdgeAdoptionRequestGVR).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("no adoption request %q (see kedge edge requests)", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting adoption request %q: %w", name, err)
+	}
+	return req, nil
+}
+
+// setAdoptionDecision records phase and message on the request's status.
+func setAdoptionDecision(ctx context.Context, dynClient dynamic.Interface, req *unstructured.Unstructured, phase, message string) error {
+	status := map[string]interface{}{
+		"phase":        phase,
+		"message":      message,
+		"decisionTime": time.Now().UTC().Format(time.RFC3339),
+	}
+	if err := unstructured.SetNestedMap(req.Object, status, "status"); err != nil {
+		return err
+	}
+	if _, err := dynClient.Resource(kedgeclient.EdgeAdoptionRequestGVR).UpdateStatus(ctx, req, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("updating adoption request %q: %w", req.GetName(), err)
+	}
+	return nil
+}
+
+// adoptionPhase returns the request's phase, "Pending" when undecided.
+func adoptionPhase(req unstructured.Unstructured) string {
+	if phase := getNestedString(req, "status", "phase"); phase != "" {
+		return phase
+	}
+	return "Pending"
+}
+
+// formatLabels renders labels as sorted k=v pairs.
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
+)
+
+// approvedCertificateRequestAnnotation names the public key (hex SHA-256 of
+// its SubjectPublicKeyInfo) whose certificate request the hub may sign when
+// agent certificates need manual approval.
+const approvedCertificateRequestAnnotation = "edges.kedge.faros.sh/approved-certificate-request"
+
+func newEdgeApproveCertificateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "approve-certificate <name>",
+		Short: "Approve the pending agent certificate request of an edge",
+		Long: `Approve the certificate request an agent joining with --certificate-join
+made, when the hub signs agent certificates only after manual approval.
+
+The pending request's key fingerprint (status.certificate.pendingRequest) is
+recorded on the edge, and the hub signs the request when the agent retries,
+within 30 seconds. Renewals by an agent that holds a certificate need no
+approval.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			ctx := context.Background()
+
+			dynClient, err := loadDynamicClient()
+			if err != nil {
+				return err
+			}
+			edge, gvr, err := getEdgeByName(ctx, dynClient, name)
+			if err != nil {
+				return err
+			}
+			pending, _, _ := unstructured.NestedString(edge.Object, "status", "certificate", "pendingRequest")
+			if pending == "" {
+				return fmt.Errorf("edge %q has no pending certificate request", name)
+			}
+			patch, err := json.Marshal(map[string]any{"metadata": map[string]any{
+				"annotations": map[string]string{approvedCertificateRequestAnnotation: pending},
+			}})
+			if err != nil {
+				return err
+			}
+			if _, err := dynClient.Resource(gvr).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+				return fmt.Errorf("updating edge %q: %w", name, err)
+			}
+			ui.Infof(cmd.OutOrStdout(), "Certificate request %s of edge %q approved.\n", pending, name)
+			return nil
+		},
+	}
+}
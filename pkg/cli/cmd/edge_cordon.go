@@ -0,0 +1,80 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
+)
+
+func newEdgeCordonCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "cordon <name>",
+		Short: "Take an edge out of service for maintenance",
+		Long: `Take an edge out of service for maintenance by setting spec.cordoned.
+
+The hub refuses new k8s and ssh sessions to the edge with 503 EdgeDraining.
+Sessions already open may finish within the provider's drain grace period
+(5 minutes by default) and are closed after it. The edge's Draining condition
+shows when the drain started. Run 'kedge edge uncordon' to accept sessions again.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setEdgeCordoned(cmd, args[0], true)
+		},
+	}
+}
+
+func newEdgeUncordonCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "uncordon <name>",
+		Short: "Return a cordoned edge to service",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setEdgeCordoned(cmd, args[0], false)
+		},
+	}
+}
+
+func setEdgeCordoned(cmd *cobra.Command, name string, cordoned bool) error {
+	ctx := context.Background()
+
+	dynClient, err := loadDynamicClient()
+	if err != nil {
+		return err
+	}
+	_, gvr, err := getEdgeByName(ctx, dynClient, name)
+	if err != nil {
+		return err
+	}
+	patch := fmt.Sprintf(`{"spec":{"cordoned":%t}}`, cordoned)
+	if _, err := dynClient.Resource(gvr).Patch(ctx, name, types.MergePatchType, []byte(patch), metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("updating edge %q: %w", name, err)
+	}
+
+	if cordoned {
+		ui.Infof(cmd.OutOrStdout(), "Edge %q cordoned.\n", name)
+	} else {
+		ui.Infof(cmd.OutOrStdout(), "Edge %q uncordoned.\n", name)
+	}
+	return nil
+}
@@ -0,0 +1,377 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+
+	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+)
+
+func newEdgeCpCommand() *cobra.Command {
+	var namespace, container string
+
+	cmd := &cobra.Command{
+		Use:   "cp <edge> <src> <dst>",
+		Short: "Copy files to or from a pod on a Kubernetes edge",
+		Long: `Copy files and directories between your machine and a container on a
+Kubernetes edge, like "kubectl cp" but routed through the hub's edge proxy with
+your current credentials — no edge kubeconfig needed.
+
+Exactly one of <src> and <dst> is a pod path, written [namespace/]pod:path.
+The files travel as a tar stream over exec, so the container must have tar.
+When <dst> is an existing directory the source is copied into it. Symlinks
+and special files are skipped when copying from a pod.`,
+		Example: `  # Copy a local file into a pod in the default namespace
+  kedge edge cp my-cluster ./app.conf web-0:/etc/app/app.conf
+
+  # Copy a directory out of a pod in another namespace
+  kedge edge cp my-cluster monitoring/prometheus-0:/prometheus/wal ./wal
+
+  # Pick the container of a multi-container pod
+  kedge edge cp my-cluster ./dump.sql db-0:/tmp/ -c postgres`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			src, err := parseCopySpec(args[1], namespace)
+			if err != nil {
+				return err
+			}
+			dst, err := parseCopySpec(args[2], namespace)
+			if err != nil {
+				return err
+			}
+			if src.remote() == dst.remote() {
+				return fmt.Errorf("exactly one of <src> and <dst> must be a pod path ([namespace/]pod:path)")
+			}
+
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+
+			config, err := edgeRestConfig(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			client, err := kubernetes.NewForConfig(config)
+			if err != nil {
+				return fmt.Errorf("creating edge client: %w", err)
+			}
+			c := &edgeCopier{config: config, client: client, container: container, warn: cmd.ErrOrStderr()}
+			if dst.remote() {
+				return c.toPod(ctx, src.Path, dst)
+			}
+			return c.fromPod(ctx, src, dst.Path)
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace of pods given without one")
+	cmd.Flags().StringVarP(&container, "container", "c", "", "Container name (default: the pod's default container)")
+	return cmd
+}
+
+// copySpec is one side of a copy: a local path, or a path in a pod.
+type copySpec struct {
+	Namespace string
+	Pod       string
+	Path      string
+}
+
+func (s copySpec) remote() bool { return s.Pod != "" }
+
+// parseCopySpec parses "[namespace/]pod:path" or a local path. Like kubectl,
+// anything whose part before the first colon is not a pod reference (or is a
+// Windows drive letter) is a local path.
+func parseCopySpec(arg, namespace string) (copySpec, error) {
+	i := strings.Index(arg, ":")
+	if i <= 0 || (runtime.GOOS == "windows" && i == 1) {
+		return copySpec{Path: arg}, nil
+	}
+	ref, p := arg[:i], arg[i+1:]
+	parts := strings.Split(ref, "/")
+	switch {
+	case len(parts) > 2:
+		return copySpec{Path: arg}, nil
+	case p == "":
+		return copySpec{}, fmt.Errorf("%q: missing path after the pod name", arg)
+	case len(parts) == 1:
+		return copySpec{Namespace: namespace, Pod: parts[0], Path: p}, nil
+	case parts[0] == "" || parts[1] == "":
+		return copySpec{}, fmt.Errorf("%q: expected namespace/pod:path", arg)
+	default:
+		return copySpec{Namespace: parts[0], Pod: parts[1], Path: p}, nil
+	}
+}
+
+// edgeRestConfig returns a client config for the Kubernetes API of the named
+// edge, reached through its proxy URL on the hub with the current
+// credentials.
+func edgeRestConfig(ctx context.Context, name string) (*rest.Config, error) {
+	config, err := loadRestConfig()
+	if err != nil {
+		return nil, fmt.Errorf("loading kubeconfig: %w", err)
+	}
+	dynClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("creating dynamic client: %w", err)
+	}
+	edge, err := dynClient.Resource(kedgeclient.KubernetesClusterGVR).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("kubernetes edge %q not found (use \"kedge ssh\" for server edges)", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting edge %q: %w", name, err)
+	}
+	edgeURL, _, _ := unstructured.NestedString(edge.Object, "status", "URL")
+	if edgeURL == "" {
+		return nil, fmt.Errorf("edge %q has no proxy URL in status; is the agent running?", name)
+	}
+	externalURL, err := externalizeEdgeURLFromConfig(edgeURL, config)
+	if err != nil {
+		return nil, fmt.Errorf("constructing external edge URL: %w", err)
+	}
+	edgeConfig := rest.CopyConfig(config)
+	edgeConfig.Host = externalURL
+	return edgeConfig, nil
+}
+
+// edgeCopier moves tar streams in and out of pods over exec.
+type edgeCopier struct {
+	config    *rest.Config
+	client    kubernetes.Interface
+	container string
+	warn      io.Writer
+}
+
+// toPod copies the local file or directory src to dst. The archive's single
+// top-level entry is named after the destination, so "a.txt" copied to
+// "pod:/tmp/b.txt" lands as /tmp/b.txt.
+func (c *edgeCopier) toPod(ctx context.Context, src string, dst copySpec) error {
+	if _, err := os.Lstat(src); err != nil {
+		return err
+	}
+	dest := path.Clean(dst.Path)
+	if strings.HasSuffix(dst.Path, "/") || c.exec(ctx, dst, []string{"test", "-d", dest}, nil, nil) == nil {
+		dest = path.Join(dest, filepath.Base(src))
+	}
+
+	pr, pw := io.Pipe()
+	go func() { _ = pw.CloseWithError(writeCopyTar(pw, src, path.Base(dest), c.warn)) }()
+	defer pr.Close() //nolint:errcheck
+	if err := c.exec(ctx, dst, []string{"tar", "-xmf", "-", "-C", path.Dir(dest)}, pr, nil); err != nil {
+		return fmt.Errorf("copying to %s/%s:%s: %w", dst.Namespace, dst.Pod, dest, err)
+	}
+	return nil
+}
+
+// fromPod copies the file or directory src out of its pod to the local path
+// dst, or into dst when it is an existing directory.
+func (c *edgeCopier) fromPod(ctx context.Context, src copySpec, dst string) error {
+	srcPath := path.Clean(src.Path)
+	base := path.Base(srcPath)
+	if base == "/" || base == "." || base == ".." {
+		return fmt.Errorf("cannot copy %q: name a file or directory", src.Path)
+	}
+	if fi, err := os.Stat(dst); err == nil && fi.IsDir() {
+		dst = filepath.Join(dst, base)
+	}
+
+	pr, pw := io.Pipe()
+	untarErr := make(chan error, 1)
+	go func() {
+		err := untarCopy(pr, base, dst, c.warn)
+		// Unblock the exec stream if extraction stopped early.
+		_ = pr.CloseWithError(err)
+		untarErr <- err
+	}()
+	err := c.exec(ctx, src, []string{"tar", "cf", "-", "-C", path.Dir(srcPath), base}, nil, pw)
+	_ = pw.CloseWithError(err)
+	if uerr := <-untarErr; uerr != nil && err == nil {
+		err = uerr
+	}
+	if err != nil {
+		return fmt.Errorf("copying from %s/%s:%s: %w", src.Namespace, src.Pod, srcPath, err)
+	}
+	return nil
+}
+
+// exec runs command in the target pod. It prefers the WebSocket exec
+// protocol and falls back to SPDY for API servers that lack it, as kubectl
+// does. The command's stderr is folded into the returned error.
+func (c *edgeCopier) exec(ctx context.Context, target copySpec, command []string, stdin io.Reader, stdout io.Writer) error {
+	req := c.client.CoreV1().RESTClient().Post().
+		Namespace(target.Namespace).
+		Resource("pods").
+		Name(target.Pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: c.container,
+			Command:   command,
+			Stdin:     stdin != nil,
+			Stdout:    stdout != nil,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	wsExec, err := remotecommand.NewWebSocketExecutor(c.config, "GET", req.URL().String())
+	if err != nil {
+		return err
+	}
+	spdyExec, err := remotecommand.NewSPDYExecutor(c.config, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+	executor, err := remotecommand.NewFallbackExecutor(wsExec, spdyExec, func(err error) bool {
+		return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+	})
+	if err != nil {
+		return err
+	}
+
+	var stderr bytes.Buffer
+	if err := executor.StreamWithContext(ctx, remotecommand.StreamOptions{Stdin: stdin, Stdout: stdout, Stderr: &stderr}); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %w", msg, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// writeCopyTar archives the local file or directory src as name/... to w.
+// Symlinks are archived as links; sockets, devices and pipes are skipped.
+func writeCopyTar(w io.Writer, src, name string, warn io.Writer) error {
+	tw := tar.NewWriter(w)
+	err := filepath.Walk(src, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		entry := name
+		if rel != "." {
+			entry = path.Join(name, filepath.ToSlash(rel))
+		}
+
+		var link string
+		switch mode := fi.Mode(); {
+		case mode&os.ModeSymlink != 0:
+			if link, err = os.Readlink(p); err != nil {
+				return err
+			}
+		case !mode.IsRegular() && !mode.IsDir():
+			fmt.Fprintf(warn, "warning: skipping special file %q\n", p) //nolint:errcheck
+			return nil
+		}
+		hdr, err := tar.FileInfoHeader(fi, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = entry
+		if fi.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close() //nolint:errcheck
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// untarCopy extracts the archive of prefix/... from r to dst, renaming the
+// top-level entry prefix to dst. Entries outside prefix are rejected so a
+// compromised container cannot write elsewhere on this machine; links are
+// skipped with a warning, as kubectl cp does.
+func untarCopy(r io.Reader, prefix, dst string, warn io.Writer) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading archive: %w", err)
+		}
+		name := path.Clean(hdr.Name)
+		if name != prefix && !strings.HasPrefix(name, prefix+"/") {
+			return fmt.Errorf("archive entry %q is outside %q", hdr.Name, prefix)
+		}
+		target := filepath.Join(dst, filepath.FromSlash(strings.TrimPrefix(name, prefix)))
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, hdr.FileInfo().Mode().Perm())
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(f, tr); err != nil {
+				f.Close() //nolint:errcheck
+				return fmt.Errorf("writing %s: %w", target, err)
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+		case tar.TypeSymlink, tar.TypeLink:
+			fmt.Fprintf(warn, "warning: skipping link %q -> %q\n", hdr.Name, hdr.Linkname) //nolint:errcheck
+		default:
+			fmt.Fprintf(warn, "warning: skipping special file %q\n", hdr.Name) //nolint:errcheck
+		}
+	}
+}
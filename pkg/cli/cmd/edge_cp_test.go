@@ -0,0 +1,124 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseCopySpec(t *testing.T) {
+	tests := []struct {
+		arg     string
+		want    copySpec
+		wantErr bool
+	}{
+		{arg: "./local/file", want: copySpec{Path: "./local/file"}},
+		{arg: "web-0:/etc/app", want: copySpec{Namespace: "default", Pod: "web-0", Path: "/etc/app"}},
+		{arg: "monitoring/prom-0:data", want: copySpec{Namespace: "monitoring", Pod: "prom-0", Path: "data"}},
+		{arg: "a/b/c:d", want: copySpec{Path: "a/b/c:d"}},
+		{arg: ":/tmp", want: copySpec{Path: ":/tmp"}},
+		{arg: "web-0:", wantErr: true},
+		{arg: "/web-0:/tmp", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			got, err := parseCopySpec(tt.arg, "default")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCopySpec(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Fatalf("parseCopySpec(%q) = %+v, want %+v", tt.arg, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCopyTarRoundTrip(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "conf")
+	if err := os.MkdirAll(filepath.Join(src, "nested"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "a.txt"), []byte("alpha"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "nested", "b.txt"), []byte("beta"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("a.txt", filepath.Join(src, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	// The archive is named after the destination ("renamed"), as toPod does.
+	var archive, warnings bytes.Buffer
+	if err := writeCopyTar(&archive, src, "renamed", &warnings); err != nil {
+		t.Fatalf("writeCopyTar: %v", err)
+	}
+	dst := filepath.Join(t.TempDir(), "out")
+	if err := untarCopy(&archive, "renamed", dst, &warnings); err != nil {
+		t.Fatalf("untarCopy: %v", err)
+	}
+
+	for file, want := range map[string]string{"a.txt": "alpha", "nested/b.txt": "beta"} {
+		got, err := os.ReadFile(filepath.Join(dst, file))
+		if err != nil {
+			t.Fatalf("reading %s: %v", file, err)
+		}
+		if string(got) != want {
+			t.Fatalf("%s = %q, want %q", file, got, want)
+		}
+	}
+	if fi, err := os.Stat(filepath.Join(dst, "a.txt")); err != nil || fi.Mode().Perm() != 0o600 {
+		t.Fatalf("a.txt mode = %v (%v), want 0600", fi.Mode().Perm(), err)
+	}
+	if _, err := os.Lstat(filepath.Join(dst, "link")); !os.IsNotExist(err) {
+		t.Fatalf("symlink was extracted (err %v)", err)
+	}
+	if !strings.Contains(warnings.String(), "skipping link") {
+		t.Fatalf("no warning for the skipped symlink: %q", warnings.String())
+	}
+}
+
+func TestUntarCopyRejectsEntriesOutsidePrefix(t *testing.T) {
+	for _, name := range []string{"../escape.txt", "data/../../escape.txt", "other/file.txt"} {
+		t.Run(name, func(t *testing.T) {
+			var archive bytes.Buffer
+			tw := tar.NewWriter(&archive)
+			if err := tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o644, Size: 1}); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := tw.Write([]byte("x")); err != nil {
+				t.Fatal(err)
+			}
+			if err := tw.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			root := t.TempDir()
+			if err := untarCopy(&archive, "data", filepath.Join(root, "out"), &bytes.Buffer{}); err == nil {
+				t.Fatalf("entry %q was accepted", name)
+			}
+			if _, err := os.Stat(filepath.Join(root, "escape.txt")); !os.IsNotExist(err) {
+				t.Fatalf("entry %q escaped the destination", name)
+			}
+		})
+	}
+}
@@ -0,0 +1,379 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+)
+
+// edgeDescribeMaxEvents bounds the events shown, newest first.
+const edgeDescribeMaxEvents = 10
+
+// edgeCredentialsNamespace is where the edges provider keeps an edge's
+// credentials in the tenant workspace.
+const edgeCredentialsNamespace = "kedge-system"
+
+var (
+	secretGVR = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	eventGVR  = schema.GroupVersionResource{Version: "v1", Resource: "events"}
+)
+
+// edgeDetail is everything `kedge edge describe` shows, gathered from the
+// edge and the objects around it. Sections that could not be read are noted
+// in warnings rather than failing the command.
+type edgeDetail struct {
+	edge        unstructured.Unstructured
+	placements  []unstructured.Unstructured
+	events      []unstructured.Unstructured
+	credentials []edgeCredential
+	warnings    []string
+}
+
+// edgeCredential is a Secret the edge depends on and whether it is there.
+type edgeCredential struct {
+	ref     string
+	purpose string
+	status  string
+}
+
+func newEdgeDescribeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "describe <name>",
+		Short: "Show edge details, placements, credentials and recent events",
+		Long: `Show everything about an edge in one view: connection and tunnel state,
+labels, workload budget and conditions, the Placements scheduled onto it,
+the status of its credentials Secrets and its most recent events.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dynClient, err := loadDynamicClient()
+			if err != nil {
+				return fmt.Errorf("not logged in — run: kedge login --hub-url <hub-url>\n(original error: %w)", err)
+			}
+			detail, err := gatherEdgeDetail(cmd.Context(), dynClient, args[0])
+			if err != nil {
+				return err
+			}
+			describeEdge(os.Stdout, detail, time.Now())
+			return nil
+		},
+	}
+}
+
+// gatherEdgeDetail reads the edge called name and what surrounds it. Only a
+// failure to read the edge itself is an error.
+func gatherEdgeDetail(ctx context.Context, dyn dynamic.Interface, name string) (*edgeDetail, error) {
+	edge, _, err := getEdgeByName(ctx, dyn, name)
+	if err != nil {
+		return nil, fmt.Errorf("getting edge %q: %w", name, err)
+	}
+	d := &edgeDetail{edge: *edge}
+
+	if list, err := dyn.Resource(kedgeclient.PlacementGVR).List(ctx, metav1.ListOptions{}); err != nil {
+		d.warnings = append(d.warnings, fmt.Sprintf("placements: %v", err))
+	} else {
+		for _, p := range list.Items {
+			if getNestedString(p, "spec", "edgeName") == name {
+				d.placements = append(d.placements, p)
+			}
+		}
+	}
+
+	selector := fields.SelectorFromSet(fields.Set{"involvedObject.name": name, "involvedObject.kind": edge.GetKind()}).String()
+	if list, err := dyn.Resource(eventGVR).List(ctx, metav1.ListOptions{FieldSelector: selector}); err != nil {
+		d.warnings = append(d.warnings, fmt.Sprintf("events: %v", err))
+	} else {
+		for _, e := range list.Items {
+			if getNestedString(e, "involvedObject", "name") == name && getNestedString(e, "involvedObject", "kind") == edge.GetKind() {
+				d.events = append(d.events, e)
+			}
+		}
+		sort.SliceStable(d.events, func(i, j int) bool { return eventTime(d.events[i]).After(eventTime(d.events[j])) })
+		if len(d.events) > edgeDescribeMaxEvents {
+			d.events = d.events[:edgeDescribeMaxEvents]
+		}
+	}
+
+	for _, c := range edgeCredentialRefs(*edge) {
+		ns, secret, _ := strings.Cut(c.ref, "/")
+		_, err := dyn.Resource(secretGVR).Namespace(ns).Get(ctx, secret, metav1.GetOptions{})
+		switch {
+		case err == nil:
+			c.status = "Present"
+		case apierrors.IsNotFound(err):
+			c.status = "Missing"
+		case apierrors.IsForbidden(err):
+			c.status = "Unknown (no access)"
+		default:
+			c.status = "Unknown (" + err.Error() + ")"
+		}
+		d.credentials = append(d.credentials, c)
+	}
+	return d, nil
+}
+
+// edgeCredentialRefs lists the Secrets an edge's kind depends on, as
+// namespace/name: the agent's kubeconfig and token for a KubernetesCluster,
+// the SSH credentials for a LinuxServer.
+func edgeCredentialRefs(edge unstructured.Unstructured) []edgeCredential {
+	if edge.GetKind() != "LinuxServer" {
+		prefix := edgeCredentialsNamespace + "/edge-" + edge.GetName()
+		return []edgeCredential{
+			{ref: prefix + "-kubeconfig", purpose: "agent kubeconfig"},
+			{ref: prefix + "-token", purpose: "agent service account token"},
+		}
+	}
+
+	var creds []edgeCredential
+	seen := map[string]bool{}
+	add := func(purpose string, path ...string) {
+		name := getNestedString(edge, append(path, "name")...)
+		if name == "" {
+			return
+		}
+		ns := getNestedString(edge, append(path, "namespace")...)
+		if ns == "" {
+			ns = edgeCredentialsNamespace
+		}
+		ref := ns + "/" + name
+		if seen[ref] {
+			return
+		}
+		seen[ref] = true
+		creds = append(creds, edgeCredential{ref: ref, purpose: purpose})
+	}
+	add("SSH key", "spec", "sshKeySecretRef")
+	add("SSH credentials", "spec", "sshCredentialsRef")
+	add("SSH password (from agent)", "status", "sshCredentials", "passwordSecretRef")
+	add("SSH private key (from agent)", "status", "sshCredentials", "privateKeySecretRef")
+	return creds
+}
+
+// eventTime is when an event last happened, for either event API shape.
+func eventTime(e unstructured.Unstructured) time.Time {
+	for _, f := range [][]string{{"lastTimestamp"}, {"eventTime"}, {"series", "lastObservedTime"}} {
+		if ts, err := time.Parse(time.RFC3339, getNestedString(e, f...)); err == nil {
+			return ts
+		}
+	}
+	return e.GetCreationTimestamp().Time
+}
+
+// describeEdge prints a human-readable description of an edge.
+func describeEdge(w io.Writer, d *edgeDetail, now time.Time) {
+	e := d.edge
+	connected, _, _ := unstructuredNestedBool(e.Object, "status", "connected")
+
+	fmt.Fprintf(w, "Name:            %s\n", e.GetName())
+	fmt.Fprintf(w, "Kind:            %s\n", e.GetKind())
+	fmt.Fprintf(w, "Phase:           %s\n", formatStringOrDash(getNestedString(e, "status", "phase")))
+	fmt.Fprintf(w, "Connected:       %v\n", connected)
+	cordoned, _, _ := unstructuredNestedBool(e.Object, "spec", "cordoned")
+	fmt.Fprintf(w, "Cordoned:        %v\n", cordoned)
+	fmt.Fprintf(w, "Hostname:        %s\n", formatStringOrDash(getNestedString(e, "status", "hostname")))
+	fmt.Fprintf(w, "Agent version:   %s\n", formatStringOrDash(getNestedString(e, "status", "agentVersion")))
+	heartbeat := "-"
+	if ts, err := time.Parse(time.RFC3339, getNestedString(e, "status", "lastHeartbeatTime")); err == nil {
+		heartbeat = ts.Local().Format("2006-01-02 15:04:05") + " (" + formatAge(ts) + " ago)"
+	}
+	fmt.Fprintf(w, "Last heartbeat:  %s\n", heartbeat)
+	fmt.Fprintf(w, "Workspace:       %s\n", formatStringOrDash(getNestedString(e, "status", "workspacePath")))
+	fmt.Fprintf(w, "Location:        %s\n", formatEdgeLocation(e))
+	fmt.Fprintf(w, "Created:         %s\n", e.GetCreationTimestamp().Format("2006-01-02 15:04:05"))
+
+	fmt.Fprintln(w, "Tunnel:")
+	fmt.Fprintf(w, "  Holder:        %s\n", formatEdgeTunnel(e, now))
+	if ts, err := time.Parse(time.RFC3339, getNestedString(e, "status", "tunnel", "acquireTime")); err == nil {
+		fmt.Fprintf(w, "  Since:         %s\n", ts.Local().Format("2006-01-02 15:04:05"))
+	}
+	fmt.Fprintf(w, "  URL:           %s\n", formatStringOrDash(getNestedString(e, "status", "URL")))
+
+	printLabelMap(w, "Labels:", e.GetLabels())
+	agentLabels, _, _ := unstructured.NestedStringMap(e.Object, "status", "labels")
+	printLabelMap(w, "Agent labels:", agentLabels)
+
+	if e.GetKind() == "LinuxServer" {
+		fmt.Fprintln(w, "Host:")
+		describeHostTelemetry(w, e)
+	} else {
+		fmt.Fprintln(w, "Capacity:")
+		fmt.Fprintf(w, "  Nodes:         %s\n", formatInventoryNodes(e))
+		fmt.Fprintf(w, "  CPU:           %s\n", formatInventory(e, "cpu"))
+		fmt.Fprintf(w, "  Memory:        %s\n", formatInventory(e, "memory"))
+		fmt.Fprintf(w, "  Version:       %s\n", formatInventory(e, "kubernetesVersion"))
+		fmt.Fprintf(w, "  Provider:      %s\n", formatInventory(e, "provider"))
+		fmt.Fprintf(w, "  CNI:           %s\n", formatInventory(e, "cni"))
+		fmt.Fprintf(w, "  Budget CPU:    %s\n", formatBudget(e, "cpu"))
+		fmt.Fprintf(w, "  Budget memory: %s\n", formatBudget(e, "memory"))
+	}
+
+	printConditions(w, e)
+
+	fmt.Fprintln(w, "Placements:")
+	if len(d.placements) == 0 {
+		fmt.Fprintln(w, "  <none>")
+	} else {
+		sort.Slice(d.placements, func(i, j int) bool {
+			a, b := d.placements[i], d.placements[j]
+			if a.GetNamespace() != b.GetNamespace() {
+				return a.GetNamespace() < b.GetNamespace()
+			}
+			return a.GetName() < b.GetName()
+		})
+		tw := newTabWriter(w)
+		printRow(tw, "  NAMESPACE", "NAME", "WORKLOAD", "PHASE", "READY", "REVISION")
+		for _, p := range d.placements {
+			printRow(tw, "  "+p.GetNamespace(), p.GetName(),
+				formatStringOrDash(getNestedString(p, "spec", "workloadRef", "name")),
+				formatStringOrDash(getNestedString(p, "status", "phase")),
+				fmt.Sprintf("%d", getNestedInt(p, "status", "readyReplicas")),
+				placementRevision(p))
+		}
+		_ = tw.Flush()
+	}
+
+	fmt.Fprintln(w, "Credentials:")
+	if len(d.credentials) == 0 {
+		fmt.Fprintln(w, "  <none>")
+	} else {
+		tw := newTabWriter(w)
+		printRow(tw, "  SECRET", "PURPOSE", "STATUS")
+		for _, c := range d.credentials {
+			printRow(tw, "  "+c.ref, c.purpose, c.status)
+		}
+		_ = tw.Flush()
+	}
+
+	fmt.Fprintln(w, "Events:")
+	if len(d.events) == 0 {
+		fmt.Fprintln(w, "  <none>")
+	} else {
+		tw := newTabWriter(w)
+		printRow(tw, "  TYPE", "REASON", "AGE", "FROM", "MESSAGE")
+		for _, ev := range d.events {
+			from := getNestedString(ev, "source", "component")
+			if from == "" {
+				from = getNestedString(ev, "reportingComponent")
+			}
+			printRow(tw, "  "+formatStringOrDash(getNestedString(ev, "type")),
+				formatStringOrDash(getNestedString(ev, "reason")),
+				formatAge(eventTime(ev)),
+				formatStringOrDash(from),
+				getNestedString(ev, "message"))
+		}
+		_ = tw.Flush()
+	}
+
+	for _, warning := range d.warnings {
+		fmt.Fprintf(w, "Warning: could not read %s\n", warning)
+	}
+}
+
+// printLabelMap prints labels sorted by key under heading, or <none>.
+func printLabelMap(w io.Writer, heading string, labels map[string]string) {
+	fmt.Fprintln(w, heading)
+	if len(labels) == 0 {
+		fmt.Fprintln(w, "  <none>")
+		return
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(w, "  %s=%s\n", k, labels[k])
+	}
+}
+
+// formatEdgeLocation renders spec.location as "<region>, <address>".
+func formatEdgeLocation(e unstructured.Unstructured) string {
+	var parts []string
+	for _, f := range []string{"region", "address"} {
+		if v := getNestedString(e, "spec", "location", f); v != "" {
+			parts = append(parts, v)
+		}
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatBudget renders one spec.workloadBudget quantity, which may be
+// serialized as a string or a number; unset means unlimited.
+func formatBudget(e unstructured.Unstructured, resource string) string {
+	q, found, _ := unstructuredNestedField(e.Object, "spec", "workloadBudget", resource)
+	if !found || q == nil {
+		return "unlimited"
+	}
+	return fmt.Sprint(q)
+}
+
+// describeHostTelemetry prints a LinuxServer's status.hostTelemetry, whose
+// quantities may be serialized as strings or numbers.
+func describeHostTelemetry(w io.Writer, e unstructured.Unstructured) {
+	host, found, _ := unstructured.NestedMap(e.Object, "status", "hostTelemetry")
+	if !found {
+		fmt.Fprintln(w, "  <not reported>")
+		return
+	}
+	field := func(m map[string]interface{}, key string) string {
+		if v, ok := m[key]; ok && v != nil {
+			return fmt.Sprint(v)
+		}
+		return "-"
+	}
+	fmt.Fprintf(w, "  CPUs:          %s\n", field(host, "cpus"))
+	fmt.Fprintf(w, "  Load:          %s\n", field(host, "loadAverage"))
+	fmt.Fprintf(w, "  Memory:        %s available of %s\n", field(host, "memoryAvailable"), field(host, "memoryTotal"))
+	filesystems, _, _ := unstructured.NestedSlice(host, "filesystems")
+	for _, item := range filesystems {
+		if fs, ok := item.(map[string]interface{}); ok {
+			fmt.Fprintf(w, "  Disk %-9s %s%% used, %s available of %s\n", field(fs, "path")+":",
+				field(fs, "usedPercent"), field(fs, "available"), field(fs, "size"))
+		}
+	}
+	temperatures, _, _ := unstructured.NestedSlice(host, "temperatures")
+	var readings []string
+	for _, item := range temperatures {
+		if t, ok := item.(map[string]interface{}); ok {
+			readings = append(readings, fmt.Sprintf("%s %s°C", field(t, "sensor"), field(t, "celsius")))
+		}
+	}
+	if len(readings) > 0 {
+		fmt.Fprintf(w, "  Temperatures:  %s\n", strings.Join(readings, ", "))
+	}
+	if ts, err := time.Parse(time.RFC3339, field(host, "lastUpdateTime")); err == nil {
+		fmt.Fprintf(w, "  Updated:       %s ago\n", formatAge(ts))
+	}
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+)
+
+func TestEdgeDescribe(t *testing.T) {
+	edge := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "edges.kedge.faros.sh/v1alpha1",
+		"kind":       "KubernetesCluster",
+		"metadata":   map[string]interface{}{"name": "store-7", "labels": map[string]interface{}{"region": "eu"}},
+		"spec": map[string]interface{}{
+			"workloadBudget": map[string]interface{}{"cpu": "2"},
+			"location":       map[string]interface{}{"region": "eu-west", "address": "Main St 1"},
+		},
+		"status": map[string]interface{}{
+			"phase":        "Ready",
+			"connected":    true,
+			"agentVersion": "v0.9.0",
+			"tunnel":       map[string]interface{}{"holder": "edges-0", "zone": "a"},
+			"inventory": map[string]interface{}{
+				"nodes": int64(3), "readyNodes": int64(2), "cpu": "12", "kubernetesVersion": "v1.31.2+k3s1",
+			},
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "AgentHealthy", "status": "True", "reason": "Healthy"},
+			},
+		},
+	}}
+	placement := func(name, edgeName string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "edges.kedge.faros.sh/v1alpha1",
+			"kind":       "Placement",
+			"metadata":   map[string]interface{}{"name": name, "namespace": "default", "generation": int64(1)},
+			"spec":       map[string]interface{}{"edgeName": edgeName, "workloadRef": map[string]interface{}{"name": "web"}},
+			"status":     map[string]interface{}{"phase": "Running"},
+		}}
+	}
+	event := func(name, kind, reason string, at time.Time) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion":     "v1",
+			"kind":           "Event",
+			"metadata":       map[string]interface{}{"name": name, "namespace": "default"},
+			"involvedObject": map[string]interface{}{"name": "store-7", "kind": kind},
+			"type":           "Normal",
+			"reason":         reason,
+			"lastTimestamp":  at.UTC().Format(time.RFC3339),
+		}}
+	}
+	kubeconfig := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "edge-store-7-kubeconfig", "namespace": "kedge-system"},
+	}}
+
+	now := time.Now()
+	dyn := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		kedgeclient.KubernetesClusterGVR: "KubernetesClusterList",
+		kedgeclient.LinuxServerGVR:       "LinuxServerList",
+		kedgeclient.PlacementGVR:         "PlacementList",
+		eventGVR:                         "EventList",
+		secretGVR:                        "SecretList",
+	},
+		edge,
+		placement("web-store-7", "store-7"),
+		placement("web-store-8", "store-8"),
+		event("older", "KubernetesCluster", "Registered", now.Add(-time.Hour)),
+		event("newer", "KubernetesCluster", "Connected", now.Add(-time.Minute)),
+		event("other-kind", "LinuxServer", "Ignored", now),
+		kubeconfig,
+	)
+
+	d, err := gatherEdgeDetail(context.Background(), dyn, "store-7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.placements) != 1 || d.placements[0].GetName() != "web-store-7" {
+		t.Errorf("placements = %v, want only web-store-7", d.placements)
+	}
+	if len(d.events) != 2 || getNestedString(d.events[0], "reason") != "Connected" {
+		t.Errorf("events = %v, want Connected then Registered", d.events)
+	}
+
+	var buf bytes.Buffer
+	describeEdge(&buf, d, now)
+	out := buf.String()
+	for _, want := range []string{
+		"Kind:            KubernetesCluster",
+		"Agent version:   v0.9.0",
+		"Location:        eu-west, Main St 1",
+		"Holder:        edges-0 (zone a)",
+		"region=eu",
+		"Nodes:         2/3",
+		"CPU:           12",
+		"Version:       v1.31.2+k3s1",
+		"Provider:      -",
+		"Budget CPU:    2",
+		"Budget memory: unlimited",
+		"AgentHealthy",
+		"web-store-7",
+		"kedge-system/edge-store-7-kubeconfig",
+		"Present",
+		"kedge-system/edge-store-7-token",
+		"Missing",
+		"Connected",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	for _, notWant := range []string{"web-store-8", "Ignored", "Warning:"} {
+		if strings.Contains(out, notWant) {
+			t.Errorf("output contains %q:\n%s", notWant, out)
+		}
+	}
+}
+
+func TestEdgeCredentialRefsLinuxServer(t *testing.T) {
+	edge := unstructured.Unstructured{Object: map[string]interface{}{
+		"kind":     "LinuxServer",
+		"metadata": map[string]interface{}{"name": "srv"},
+		"spec": map[string]interface{}{
+			"sshCredentialsRef": map[string]interface{}{"name": "admin-ssh", "namespace": "ops"},
+		},
+		"status": map[string]interface{}{
+			"sshCredentials": map[string]interface{}{
+				"passwordSecretRef":   map[string]interface{}{"name": "srv-ssh-credentials", "namespace": "kedge-system"},
+				"privateKeySecretRef": map[string]interface{}{"name": "srv-ssh-credentials", "namespace": "kedge-system"},
+			},
+		},
+	}}
+	var refs []string
+	for _, c := range edgeCredentialRefs(edge) {
+		refs = append(refs, c.ref)
+	}
+	if got, want := strings.Join(refs, ","), "ops/admin-ssh,kedge-system/srv-ssh-credentials"; got != want {
+		t.Errorf("edgeCredentialRefs() = %s, want %s", got, want)
+	}
+}
+
+func TestDescribeHostTelemetry(t *testing.T) {
+	edge := unstructured.Unstructured{Object: map[string]interface{}{
+		"kind": "LinuxServer",
+		"status": map[string]interface{}{
+			"hostTelemetry": map[string]interface{}{
+				"cpus":            int64(4),
+				"loadAverage":     "0.52 0.41 0.30",
+				"memoryTotal":     "8Gi",
+				"memoryAvailable": "2Gi",
+				"filesystems": []interface{}{
+					map[string]interface{}{"path": "/", "size": "100Gi", "available": "5Gi", "usedPercent": int64(95)},
+				},
+				"temperatures": []interface{}{
+					map[string]interface{}{"sensor": "cpu-thermal", "celsius": int64(61)},
+				},
+			},
+		},
+	}}
+	var out bytes.Buffer
+	describeHostTelemetry(&out, edge)
+	for _, want := range []string{"0.52 0.41 0.30", "2Gi available of 8Gi", "Disk /:", "95% used, 5Gi available of 100Gi", "cpu-thermal 61°C"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output lacks %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	describeHostTelemetry(&out, unstructured.Unstructured{Object: map[string]interface{}{}})
+	if !strings.Contains(out.String(), "<not reported>") {
+		t.Errorf("output without telemetry:\n%s", out.String())
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/registry"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+const (
+	// defaultAgentChart is the agent Helm chart --from-kubeconfig installs.
+	defaultAgentChart = "oci://ghcr.io/faroshq/charts/kedge-agent"
+
+	// agentReleaseName and agentNamespace match the Helm command printed by
+	// 'kedge edge join-command', so either way of installing upgrades the other.
+	agentReleaseName = "kedge-agent"
+	agentNamespace   = "kedge-agent"
+)
+
+// onboardOptions are the 'kedge edge create --from-kubeconfig' settings.
+type onboardOptions struct {
+	kubeconfig   string
+	context      string
+	chart        string
+	chartVersion string
+	timeout      time.Duration
+}
+
+// onboardEdge installs the agent chart into the cluster opts.kubeconfig
+// points at, configured to join edge name with joinToken, and waits until the
+// edge is Ready.
+func onboardEdge(ctx context.Context, name, hubURL, joinToken string, opts onboardOptions) error {
+	fmt.Printf("Installing the kedge agent into %s...\n", describeTargetCluster(opts))
+	if err := installAgentChart(opts, agentChartValues(name, hubURL, joinToken, globalInsecureTLS)); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Agent chart installed (release %s/%s)\n", agentNamespace, agentReleaseName)
+
+	fmt.Printf("Waiting up to %s for edge %q to become Ready...\n", opts.timeout, name)
+	if err := waitForEdgeReady(ctx, name, opts.timeout); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Edge %q is Ready\n", name)
+	return nil
+}
+
+func describeTargetCluster(opts onboardOptions) string {
+	if opts.context != "" {
+		return fmt.Sprintf("context %q of %s", opts.context, opts.kubeconfig)
+	}
+	return opts.kubeconfig
+}
+
+// agentChartValues are the agent chart values joining edge name to the hub at
+// hubURL with joinToken, as in the Helm command printJoinCommand shows.
+func agentChartValues(name, hubURL, joinToken string, insecure bool) map[string]any {
+	hub := map[string]any{
+		"url":   hubURL,
+		"token": joinToken,
+	}
+	if insecure {
+		hub["insecureSkipTLSVerify"] = true
+	}
+	return map[string]any{
+		"agent": map[string]any{
+			"edgeName": name,
+			"hub":      hub,
+		},
+	}
+}
+
+// installAgentChart installs the agent chart, or upgrades the release when
+// it already exists (a re-run after a failed wait).
+func installAgentChart(opts onboardOptions, values map[string]any) error {
+	getter := genericclioptions.NewConfigFlags(false)
+	getter.KubeConfig = &opts.kubeconfig
+	getter.Context = &opts.context
+	namespace := agentNamespace
+	getter.Namespace = &namespace
+
+	actionConfig := new(action.Configuration)
+	if err := actionConfig.Init(getter, agentNamespace, "secret", func(string, ...any) {}); err != nil {
+		return fmt.Errorf("initializing helm for %s: %w", opts.kubeconfig, err)
+	}
+	registryClient, err := registry.NewClient()
+	if err != nil {
+		return fmt.Errorf("creating helm registry client: %w", err)
+	}
+	actionConfig.RegistryClient = registryClient
+
+	locate := action.NewInstall(actionConfig)
+	locate.Version = opts.chartVersion
+	chartPath, err := locate.LocateChart(opts.chart, cli.New())
+	if err != nil {
+		return fmt.Errorf("locating agent chart %s: %w", opts.chart, err)
+	}
+	chartObj, err := loader.Load(chartPath)
+	if err != nil {
+		return fmt.Errorf("loading agent chart: %w", err)
+	}
+
+	history := action.NewHistory(actionConfig)
+	history.Max = 1
+	if _, err := history.Run(agentReleaseName); err == nil {
+		upgrade := action.NewUpgrade(actionConfig)
+		upgrade.Namespace = agentNamespace
+		if _, err := upgrade.Run(agentReleaseName, chartObj, values); err != nil {
+			return fmt.Errorf("upgrading agent release: %w", err)
+		}
+		return nil
+	}
+	install := action.NewInstall(actionConfig)
+	install.ReleaseName = agentReleaseName
+	install.Namespace = agentNamespace
+	install.CreateNamespace = true
+	if _, err := install.Run(chartObj, values); err != nil {
+		return fmt.Errorf("installing agent chart: %w", err)
+	}
+	return nil
+}
+
+// waitForEdgeReady polls edge name until its phase is Ready.
+func waitForEdgeReady(ctx context.Context, name string, timeout time.Duration) error {
+	dynClient, err := loadDynamicClient()
+	if err != nil {
+		return err
+	}
+	deadline := time.Now().Add(timeout)
+	phase := ""
+	for {
+		edge, _, err := getEdgeByName(ctx, dynClient, name)
+		if err != nil {
+			return fmt.Errorf("getting edge: %w", err)
+		}
+		if phase = getNestedString(*edge, "status", "phase"); phase == "Ready" {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("edge %q not Ready after %s (phase %q)", name, timeout, phase)
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
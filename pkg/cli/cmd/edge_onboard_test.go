@@ -0,0 +1,43 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAgentChartValues(t *testing.T) {
+	got := agentChartValues("store-1", "https://hub.example.com/clusters/root:acme", "tok", false)
+	want := map[string]any{
+		"agent": map[string]any{
+			"edgeName": "store-1",
+			"hub": map[string]any{
+				"url":   "https://hub.example.com/clusters/root:acme",
+				"token": "tok",
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("values = %v, want %v", got, want)
+	}
+
+	got = agentChartValues("store-1", "https://hub", "tok", true)
+	if hub := got["agent"].(map[string]any)["hub"].(map[string]any); hub["insecureSkipTLSVerify"] != true {
+		t.Errorf("insecure hub values = %v", hub)
+	}
+}
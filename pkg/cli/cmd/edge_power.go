@@ -0,0 +1,112 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
+	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+)
+
+// edgePowerAction describes one privileged power-management operation run on a
+// server-type edge over the hub SSH exec path.
+type edgePowerAction struct {
+	// verb is the subcommand name, e.g. "reboot".
+	verb string
+	// systemctl is the systemctl action run on the edge.
+	systemctl string
+	// auditAction is the action the hub audit log records for it
+	// (pkg/hub/audit).
+	auditAction string
+}
+
+var (
+	edgeRebootAction   = edgePowerAction{verb: "reboot", systemctl: "reboot", auditAction: "EdgeReboot"}
+	edgeShutdownAction = edgePowerAction{verb: "shutdown", systemctl: "poweroff", auditAction: "EdgeShutdown"}
+)
+
+func newEdgeRebootCommand() *cobra.Command {
+	return newEdgePowerCommand(edgeRebootAction, "Reboot a server-type edge")
+}
+
+func newEdgeShutdownCommand() *cobra.Command {
+	return newEdgePowerCommand(edgeShutdownAction, "Power off a server-type edge")
+}
+
+func newEdgePowerCommand(action edgePowerAction, short string) *cobra.Command {
+	return &cobra.Command{
+		Use:   action.verb + " <name>",
+		Short: short,
+		Long: fmt.Sprintf(`%s.
+
+Runs 'sudo systemctl %s' on the edge over the hub SSH exec path. The SSH user
+must be allowed to run it without a password. Before anything is executed you
+are asked to type the edge name back; pass --yes to skip the prompt in scripts.
+The hub audit log records the exec with action %s.
+
+Only server-type edges (LinuxServer) are supported.`, short, action.systemctl, action.auditAction),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runEdgePower(cmd, action, args[0])
+		},
+	}
+}
+
+func runEdgePower(cmd *cobra.Command, action edgePowerAction, name string) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	dynClient, err := loadDynamicClient()
+	if err != nil {
+		return err
+	}
+
+	edge, gvr, err := getEdgeByName(ctx, dynClient, name)
+	if err != nil {
+		return err
+	}
+	if gvr != kedgeclient.LinuxServerGVR {
+		return fmt.Errorf("edge %q is a %s; %s is only supported for server-type edges", name, edge.GetKind(), action.verb)
+	}
+
+	ok, err := ui.ConfirmName(cmd.InOrStdin(), cmd.ErrOrStderr(), fmt.Sprintf("This will %s edge %q.", action.verb, name), name)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ui.Aborted(action.verb)
+	}
+
+	conn, err := dialEdgeSSH(ctx, name, "sudo systemctl "+action.systemctl)
+	if err != nil {
+		return err
+	}
+	defer conn.Close() //nolint:errcheck
+
+	if err := runSSHCommandStream(ctx, conn); err != nil {
+		return fmt.Errorf("%s of edge %q failed: %w", action.verb, name, err)
+	}
+	ui.Infof(cmd.OutOrStdout(), "Edge %q: %s requested.\n", name, action.verb)
+	return nil
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
+)
+
+func newEdgeSignURLCommand() *cobra.Command {
+	var (
+		ttl         time.Duration
+		readOnly    bool
+		subresource string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "sign-url <name>",
+		Short: "Mint a short-lived signed URL to an edge",
+		Long: `Mint a signed, time-limited URL to an edge's k8s or ssh subresource.
+
+The URL needs no credentials: anyone holding it gets the granted access until
+it expires, which makes it suitable for CI jobs and other integrations that
+should not hold your hub token. It is verified statelessly by the hub and
+cannot be revoked early, so keep the TTL short. Minting requires the same
+"proxy" permission on the edge that using it directly does.
+
+With --read-only the URL only allows GET/HEAD requests to the k8s API (no
+create/update/delete, exec or port-forward).`,
+		Example: `  # 10-minute read-only Kubernetes API URL
+  kedge edge sign-url my-cluster --read-only
+
+  # Use it from a CI job
+  curl "$URL/api/v1/namespaces/default/pods"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			config, err := loadRestConfig()
+			if err != nil {
+				return fmt.Errorf("loading kubeconfig: %w", err)
+			}
+			dynClient, err := dynamic.NewForConfig(config)
+			if err != nil {
+				return fmt.Errorf("creating dynamic client: %w", err)
+			}
+
+			edge, _, err := getEdgeByName(ctx, dynClient, args[0])
+			if err != nil {
+				return err
+			}
+			signed, expiresAt, err := signEdgeURL(ctx, config, edge, subresource, ttl, readOnly)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), signed)
+			ui.Infof(cmd.ErrOrStderr(), "Expires at %s (in %s).\n",
+				expiresAt.Local().Format(time.RFC3339), time.Until(expiresAt).Round(time.Second))
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&ttl, "ttl", 10*time.Minute, "How long the URL stays valid (max 24h)")
+	cmd.Flags().BoolVar(&readOnly, "read-only", false, "Only allow read requests (k8s subresource only)")
+	cmd.Flags().StringVar(&subresource, "subresource", "", "Edge subresource to grant: k8s or ssh (default: the edge type's own)")
+	return cmd
+}
+
+// signEdgeURL asks the edges provider to mint a signed URL for edge and
+// returns it externalized against the hub address in config.
+func signEdgeURL(ctx context.Context, config *rest.Config, edge *unstructured.Unstructured, subresource string, ttl time.Duration, readOnly bool) (string, time.Time, error) {
+	name := edge.GetName()
+	edgeURL, _, _ := unstructured.NestedString(edge.Object, "status", "URL")
+	if edgeURL == "" {
+		return "", time.Time{}, fmt.Errorf("edge %q has no proxy URL in status; is the agent running?", name)
+	}
+	externalURL, err := externalizeEdgeURLFromConfig(edgeURL, config)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("constructing external edge URL: %w", err)
+	}
+	// status.URL points at the edge's default subresource (.../k8s or
+	// .../ssh); the mint endpoint is its sibling .../signurl.
+	mintURL := externalURL[:strings.LastIndex(externalURL, "/")] + "/signurl"
+
+	body, err := json.Marshal(map[string]interface{}{
+		"subresource": subresource,
+		"ttl":         ttl.String(),
+		"readOnly":    readOnly,
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, mintURL, bytes.NewReader(body))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", hubAccept)
+
+	transport, err := rest.TransportFor(config)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("building HTTP transport: %w", err)
+	}
+	resp, err := (&http.Client{Transport: transport, Timeout: 30 * time.Second}).Do(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("signing URL for edge %q: %w", name, err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return "", time.Time{}, hubError(fmt.Sprintf("signing URL for edge %q", name), resp, respBody)
+	}
+
+	var out struct {
+		URL       string    `json:"url"`
+		ExpiresAt time.Time `json:"expiresAt"`
+	}
+	if err := json.Unmarshal(respBody, &out); err != nil {
+		return "", time.Time{}, fmt.Errorf("decoding sign-url response: %w", err)
+	}
+
+	// The provider stamps its own view of the hub address; swap in the one
+	// this kubeconfig reaches, keeping the signed query intact.
+	signed, err := url.Parse(out.URL)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("parsing signed URL: %w", err)
+	}
+	base, err := externalizeEdgeURLFromConfig((&url.URL{Scheme: signed.Scheme, Host: signed.Host, Path: signed.Path}).String(), config)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return base + "?" + signed.RawQuery, out.ExpiresAt, nil
+}
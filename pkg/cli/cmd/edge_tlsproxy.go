@@ -0,0 +1,290 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/faroshq/faros-kedge/pkg/agent/tunnel"
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
+	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+)
+
+func newEdgeTLSProxyCommand() *cobra.Command {
+	var (
+		address     string
+		output      string
+		fingerprint string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "tls-proxy <name>",
+		Short: "Reach an end-to-end TLS edge's Kubernetes API through a local relay",
+		Long: `Open a local relay to the Kubernetes API of an edge whose agent runs with
+--end-to-end-tls, and write a kubeconfig that uses it.
+
+Such an agent terminates TLS for API traffic itself: the hub only forwards
+the encrypted session (the edge's k8s-tls subresource) and plaintext access
+through "kedge kubeconfig edge" is refused. The relay authenticates to the
+hub with your current credentials; requests inside the session run with the
+agent's own credentials on the cluster, which never leave the edge.
+
+The generated kubeconfig pins the agent's serving certificate from the edge
+status. That status comes through the hub, so on its own the pin does not
+protect against the hub itself: pass --fingerprint with the value the agent
+logs at startup (or "openssl x509 -noout -fingerprint -sha256" of
+e2e-tls.crt in the agent's ~/.kedge/agents/<edge> directory) to check the
+certificate out of band. A generated certificate is kept across agent
+restarts, so the fingerprint only changes when it is rotated. The relay runs
+until interrupted.`,
+		Example: `  # Start the relay and write its kubeconfig
+  kedge edge tls-proxy my-cluster -o /tmp/my-cluster.kubeconfig &
+  KUBECONFIG=/tmp/my-cluster.kubeconfig kubectl get pods -A`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+
+			config, err := loadRestConfig()
+			if err != nil {
+				return fmt.Errorf("loading kubeconfig: %w", err)
+			}
+			dynClient, err := dynamic.NewForConfig(config)
+			if err != nil {
+				return fmt.Errorf("creating dynamic client: %w", err)
+			}
+			edge, err := dynClient.Resource(kedgeclient.KubernetesClusterGVR).Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("getting edge %q: %w", name, err)
+			}
+
+			certPEM, _, _ := unstructured.NestedString(edge.Object, "status", "endToEndTLS", "certificate")
+			if certPEM == "" {
+				return fmt.Errorf("edge %q does not offer end-to-end TLS (run its agent with --end-to-end-tls)", name)
+			}
+			got, err := certFingerprint(certPEM)
+			if err != nil {
+				return fmt.Errorf("edge %q: %w", name, err)
+			}
+			if fingerprint == "" {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: pinning the certificate the hub published for edge %q (%s) without checking it;\n"+
+					"pass --fingerprint with the value the agent logs to rule out interception by the hub.\n", name, got) //nolint:errcheck
+			} else if !strings.EqualFold(got, fingerprint) {
+				return fmt.Errorf("edge %q certificate fingerprint is %s, expected %s", name, got, fingerprint)
+			}
+
+			edgeURL, _, _ := unstructured.NestedString(edge.Object, "status", "URL")
+			if edgeURL == "" {
+				return fmt.Errorf("edge %q has no proxy URL in status; is the agent running?", name)
+			}
+			externalURL, err := externalizeEdgeURLFromConfig(edgeURL, config)
+			if err != nil {
+				return fmt.Errorf("constructing external edge URL: %w", err)
+			}
+			// status.URL points at .../k8s; the relay is its sibling .../k8s-tls.
+			relayURL := externalURL[:strings.LastIndex(externalURL, "/")] + "/k8s-tls"
+
+			ln, err := net.Listen("tcp", address)
+			if err != nil {
+				return fmt.Errorf("listening on %s: %w", address, err)
+			}
+			defer ln.Close() //nolint:errcheck
+
+			kubeconfigBytes, err := tlsProxyKubeconfig(name, ln.Addr().String(), certPEM)
+			if err != nil {
+				return err
+			}
+			if output == "" || output == "-" {
+				if _, err := cmd.OutOrStdout().Write(kubeconfigBytes); err != nil {
+					return err
+				}
+			} else {
+				if err := os.WriteFile(output, kubeconfigBytes, 0600); err != nil {
+					return fmt.Errorf("writing kubeconfig to %s: %w", output, err)
+				}
+				ui.Infof(cmd.ErrOrStderr(), "Kubeconfig written to %s\n", output)
+			}
+			ui.Infof(cmd.ErrOrStderr(), "Relaying %s to edge %q; press Ctrl+C to stop.\n", ln.Addr(), name)
+
+			go func() {
+				<-ctx.Done()
+				_ = ln.Close()
+			}()
+			for {
+				conn, err := ln.Accept()
+				if err != nil {
+					if ctx.Err() != nil {
+						return nil
+					}
+					return fmt.Errorf("accepting connection: %w", err)
+				}
+				go func() {
+					if err := relayEdgeTLS(ctx, config, relayURL, conn); err != nil {
+						fmt.Fprintf(cmd.ErrOrStderr(), "relay: %v\n", err) //nolint:errcheck
+					}
+				}()
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&address, "address", "127.0.0.1:0", "Local address the relay listens on")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Kubeconfig output file path (default: stdout)")
+	cmd.Flags().StringVar(&fingerprint, "fingerprint", "", `Expected certificate fingerprint ("sha256:<hex>") as logged by the agent`)
+	return cmd
+}
+
+// tlsProxyKubeconfig builds a kubeconfig for the relay at addr that trusts
+// only the agent's pinned serving certificate. It carries no credentials.
+func tlsProxyKubeconfig(name, addr, certPEM string) ([]byte, error) {
+	contextName := name + "-edge-tls"
+	config := clientcmdapi.NewConfig()
+	config.Clusters[contextName] = &clientcmdapi.Cluster{
+		Server:                   "https://" + addr,
+		CertificateAuthorityData: []byte(certPEM),
+		TLSServerName:            tunnel.EndToEndTLSServerName,
+	}
+	config.AuthInfos[contextName] = &clientcmdapi.AuthInfo{}
+	config.Contexts[contextName] = &clientcmdapi.Context{Cluster: contextName, AuthInfo: contextName}
+	config.CurrentContext = contextName
+	out, err := clientcmd.Write(*config)
+	if err != nil {
+		return nil, fmt.Errorf("serializing kubeconfig: %w", err)
+	}
+	return out, nil
+}
+
+// certFingerprint returns "sha256:<hex>" of the first PEM certificate.
+func certFingerprint(certPEM string) (string, error) {
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return "", errors.New("status.endToEndTLS.certificate is not a PEM certificate")
+	}
+	sum := sha256.Sum256(block.Bytes)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// relayEdgeTLS opens the edge's k8s-tls subresource on the hub and copies
+// bytes between it and local until either side closes.
+func relayEdgeTLS(ctx context.Context, config *rest.Config, relayURL string, local net.Conn) error {
+	defer local.Close() //nolint:errcheck
+
+	remote, br, err := dialEdgeTLSRelay(ctx, config, relayURL)
+	if err != nil {
+		return err
+	}
+	defer remote.Close() //nolint:errcheck
+
+	errc := make(chan error, 2)
+	go func() { _, err := io.Copy(remote, local); errc <- err }()
+	go func() { _, err := io.Copy(local, br); errc <- err }()
+	<-errc
+	return nil
+}
+
+// dialEdgeTLSRelay sends the k8s-tls upgrade request to the hub and returns
+// the connection once the agent has answered 101. Reads must go through the
+// returned reader, which may hold bytes buffered past the response.
+func dialEdgeTLSRelay(ctx context.Context, config *rest.Config, relayURL string) (net.Conn, io.Reader, error) {
+	u, err := url.Parse(relayURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing relay URL %q: %w", relayURL, err)
+	}
+	// Exec-plugin and auth-provider kubeconfigs carry no BearerToken; resolve
+	// the header the way kedge ssh does.
+	authorization, err := authorizationHeader(config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolving credentials: %w", err)
+	}
+	host := u.Host
+	if u.Port() == "" {
+		if u.Scheme == "https" {
+			host = net.JoinHostPort(u.Hostname(), "443")
+		} else {
+			host = net.JoinHostPort(u.Hostname(), "80")
+		}
+	}
+
+	var conn net.Conn
+	if u.Scheme == "https" {
+		tlsConfig, err := rest.TLSConfigFor(config)
+		if err != nil {
+			return nil, nil, fmt.Errorf("building hub TLS config: %w", err)
+		}
+		conn, err = (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", host)
+		if err != nil {
+			return nil, nil, fmt.Errorf("connecting to hub %s: %w", host, err)
+		}
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", host)
+		if err != nil {
+			return nil, nil, fmt.Errorf("connecting to hub %s: %w", host, err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, relayURL, nil)
+	if err != nil {
+		_ = conn.Close()
+		return nil, nil, err
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", tunnel.EndToEndTLSUpgrade)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	if err := req.Write(conn); err != nil {
+		_ = conn.Close()
+		return nil, nil, fmt.Errorf("sending relay request: %w", err)
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		_ = conn.Close()
+		return nil, nil, fmt.Errorf("reading relay response: %w", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		_ = resp.Body.Close()
+		_ = conn.Close()
+		return nil, nil, hubError("opening end-to-end TLS relay", resp, body)
+	}
+	return conn, br, nil
+}
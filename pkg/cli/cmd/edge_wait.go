@@ -0,0 +1,164 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
+)
+
+// edgeWaitProbeTimeout bounds one readiness probe, so a request stuck in a
+// half-open tunnel does not use up the whole wait.
+const edgeWaitProbeTimeout = 15 * time.Second
+
+func newEdgeWaitSSHCommand() *cobra.Command {
+	var timeout, interval time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "wait-ssh <name>",
+		Short: "Wait until SSH to a server edge works through the hub",
+		Long: `Block until an SSH session to the server edge can be opened through the hub
+and runs a command, not just until the edge reports Ready. Exits non-zero when
+the edge does not serve SSH within --timeout, so provisioning scripts can wait
+on it before running "kedge ssh".`,
+		Example: `  kedge edge wait-ssh host1 --timeout 10m && kedge ssh host1 -- sudo apt-get update`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			err := waitEdgeServing(cmd.Context(), timeout, interval, func(ctx context.Context) error {
+				return probeEdgeSSH(ctx, name)
+			})
+			if err != nil {
+				return fmt.Errorf("edge %q does not serve SSH after %s: %w", name, timeout, err)
+			}
+			ui.Infof(cmd.OutOrStdout(), "Edge %q is serving SSH.\n", name)
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&timeout, "timeout", 5*time.Minute, "How long to wait before giving up")
+	cmd.Flags().DurationVar(&interval, "interval", 2*time.Second, "How long to wait between attempts")
+	return cmd
+}
+
+func newEdgeWaitK8sCommand() *cobra.Command {
+	var timeout, interval time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "wait-k8s <name>",
+		Short: "Wait until a Kubernetes edge's API answers through the hub",
+		Long: `Block until the Kubernetes API of the edge answers through the hub (its
+/version endpoint), not just until the edge reports Ready. Exits non-zero when
+the edge does not serve its API within --timeout, so provisioning scripts can
+wait on it before running kubectl against "kedge kubeconfig edge".`,
+		Example: `  kedge edge wait-k8s store-17 && kubectl --kubeconfig store-17.kubeconfig apply -f app.yaml`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			var version string
+			err := waitEdgeServing(cmd.Context(), timeout, interval, func(ctx context.Context) error {
+				var err error
+				version, err = probeEdgeK8s(ctx, name)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("edge %q does not serve its Kubernetes API after %s: %w", name, timeout, err)
+			}
+			ui.Infof(cmd.OutOrStdout(), "Edge %q is serving its Kubernetes API (%s).\n", name, version)
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&timeout, "timeout", 5*time.Minute, "How long to wait before giving up")
+	cmd.Flags().DurationVar(&interval, "interval", 2*time.Second, "How long to wait between attempts")
+	return cmd
+}
+
+// waitEdgeServing runs probe every interval until it succeeds and returns the
+// last probe error once timeout has passed. Errors that need the user to act,
+// such as a step-up login, end the wait at once.
+func waitEdgeServing(ctx context.Context, timeout, interval time.Duration, probe func(context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for {
+		probeCtx, probeCancel := context.WithTimeout(ctx, edgeWaitProbeTimeout)
+		err := probe(probeCtx)
+		probeCancel()
+		if err == nil {
+			return nil
+		}
+		var reason *ui.ReasonError
+		if errors.As(err, &reason) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(interval):
+		}
+	}
+}
+
+// probeEdgeSSH runs "true" on the server edge over the hub's SSH endpoint. A
+// non-zero exit status still means SSH works.
+func probeEdgeSSH(ctx context.Context, name string) error {
+	conn, err := dialEdgeSSH(ctx, name, "true")
+	if err != nil {
+		return err
+	}
+	defer conn.Close() //nolint:errcheck
+	// Unblock the read below when the probe times out.
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	err = streamSSHCommand(ctx, conn, io.Discard)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	var exitErr *remoteExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return err
+	}
+	return nil
+}
+
+// probeEdgeK8s asks the edge's Kubernetes API for its version through the
+// hub.
+func probeEdgeK8s(ctx context.Context, name string) (string, error) {
+	config, err := edgeRestConfig(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	config.Timeout = edgeWaitProbeTimeout
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return "", err
+	}
+	info, err := client.Discovery().ServerVersion()
+	if err != nil {
+		return "", err
+	}
+	return info.GitVersion, nil
+}
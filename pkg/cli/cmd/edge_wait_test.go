@@ -0,0 +1,52 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
+)
+
+func TestWaitEdgeServing(t *testing.T) {
+	ctx := context.Background()
+	notYet := errors.New("no proxy URL")
+
+	attempts := 0
+	err := waitEdgeServing(ctx, time.Second, time.Millisecond, func(context.Context) error {
+		if attempts++; attempts < 3 {
+			return notYet
+		}
+		return nil
+	})
+	if err != nil || attempts != 3 {
+		t.Errorf("waitEdgeServing = %v after %d attempts; want nil after 3", err, attempts)
+	}
+
+	if err := waitEdgeServing(ctx, 50*time.Millisecond, time.Millisecond, func(context.Context) error { return notYet }); !errors.Is(err, notYet) {
+		t.Errorf("waitEdgeServing past the timeout = %v, want the last probe error", err)
+	}
+
+	attempts = 0
+	stepUp := &ui.ReasonError{Err: errors.New("step-up required")}
+	if err := waitEdgeServing(ctx, time.Second, time.Millisecond, func(context.Context) error { attempts++; return stepUp }); err != stepUp || attempts != 1 {
+		t.Errorf("waitEdgeServing = %v after %d attempts; want the step-up error at once", err, attempts)
+	}
+}
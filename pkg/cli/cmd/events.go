@@ -0,0 +1,422 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
+)
+
+// kedgeAPIGroupSuffix marks the API groups whose objects `kedge events`
+// reports on: edges, workloads, placements, fleet commands and the rest.
+const kedgeAPIGroupSuffix = "kedge.faros.sh"
+
+// eventKindAliases maps the short kinds --for accepts to the kinds events
+// name: every name of a resource in resourceNames, plus fleet. "edge"
+// covers both connectable kinds.
+var eventKindAliases = func() map[string][]string {
+	m := map[string][]string{"fleet": {"FleetCommand"}}
+	for _, r := range resourceNames {
+		for _, n := range r.names() {
+			m[n] = r.Kinds
+		}
+	}
+	return m
+}()
+
+// forwardedWorkloadLabel marks an edge cluster event the agent forwarded
+// onto a Placement with the Workload it belongs to, so --for vw/<name>
+// finds it (see status.EventForwarder).
+const forwardedWorkloadLabel = "edges.kedge.faros.sh/workload"
+
+// eventFilter selects the events `kedge events` shows.
+type eventFilter struct {
+	// kinds are the involved object kinds to keep; empty keeps every kind.
+	kinds []string
+	// name is the involved object name to keep; empty keeps every name.
+	name string
+	// eventType is Normal or Warning; empty keeps both.
+	eventType string
+	// since drops events that last happened before it; zero keeps all.
+	since time.Time
+	// allGroups keeps events about objects outside the kedge API groups.
+	allGroups bool
+}
+
+// parseEventObject parses --for: "kind/name", "kind" or "name". A bare word
+// that is a known kind alias selects the kind.
+func parseEventObject(s string) (kinds []string, name string, err error) {
+	if s == "" {
+		return nil, "", nil
+	}
+	kind, name, hasName := strings.Cut(s, "/")
+	if !hasName {
+		if kinds, ok := eventKindAliases[strings.ToLower(s)]; ok {
+			return kinds, "", nil
+		}
+		return nil, s, nil
+	}
+	if kind == "" || name == "" {
+		return nil, "", fmt.Errorf("invalid --for %q (want kind/name, kind or name)", s)
+	}
+	if kinds, ok := eventKindAliases[strings.ToLower(kind)]; ok {
+		return kinds, name, nil
+	}
+	return []string{kind}, name, nil
+}
+
+// match reports whether e passes the filter.
+func (f *eventFilter) match(e unstructured.Unstructured) bool {
+	if !f.allGroups {
+		apiVersion := getNestedString(e, "involvedObject", "apiVersion")
+		group, _, _ := strings.Cut(apiVersion, "/")
+		if !strings.Contains(apiVersion, "/") || !strings.HasSuffix(group, kedgeAPIGroupSuffix) {
+			return false
+		}
+	}
+	if !f.matchObject(e) {
+		return false
+	}
+	if f.eventType != "" && !strings.EqualFold(getNestedString(e, "type"), f.eventType) {
+		return false
+	}
+	if !f.since.IsZero() && eventTime(e).Before(f.since) {
+		return false
+	}
+	return true
+}
+
+// matchObject reports whether e is about the object --for selects. Events
+// forwarded from an edge cluster onto a Placement count as being about the
+// Workload they are labelled with too.
+func (f *eventFilter) matchObject(e unstructured.Unstructured) bool {
+	kind := getNestedString(e, "involvedObject", "kind")
+	name := getNestedString(e, "involvedObject", "name")
+	if f.matchKind(kind) && (f.name == "" || name == f.name) {
+		return true
+	}
+	workload := e.GetLabels()[forwardedWorkloadLabel]
+	return workload != "" && f.matchKind("Workload") && (f.name == "" || workload == f.name)
+}
+
+func (f *eventFilter) matchKind(kind string) bool {
+	if len(f.kinds) == 0 {
+		return true
+	}
+	for _, k := range f.kinds {
+		if strings.EqualFold(k, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+func newEventsCommand() *cobra.Command {
+	var (
+		object    string
+		eventType string
+		since     time.Duration
+		namespace string
+		follow    bool
+		allGroups bool
+		output    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "Show edge, placement and rollout events in the current workspace",
+		Long: `Show the Events recorded about kedge objects in the current workspace:
+edges connecting and disconnecting, placements being scheduled and applied,
+workload rollouts progressing, fleet commands and power operations. Events
+are listed oldest first across all namespaces.
+
+Narrow them to one object with --for, to warnings with --type Warning and to
+a recent window with --since. --follow keeps streaming new events as they
+happen, which is the way to watch a rollout.`,
+		Example: `  # Everything from the last hour
+  kedge events --since 1h
+
+  # Follow a rollout
+  kedge events --for vw/web -f
+
+  # Warnings about one edge
+  kedge events --for edge/store-17 --type Warning`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != "" && output != "wide" && output != "json" {
+				return fmt.Errorf("unsupported --output %q (supported: wide, json)", output)
+			}
+			filter := &eventFilter{allGroups: allGroups}
+			var err error
+			if filter.kinds, filter.name, err = parseEventObject(object); err != nil {
+				return err
+			}
+			switch strings.ToLower(eventType) {
+			case "":
+			case "normal":
+				filter.eventType = "Normal"
+			case "warning":
+				filter.eventType = "Warning"
+			default:
+				return fmt.Errorf("invalid --type %q (want Normal or Warning)", eventType)
+			}
+			if since < 0 {
+				return fmt.Errorf("--since must not be negative")
+			}
+			if since > 0 {
+				filter.since = time.Now().Add(-since)
+			}
+
+			dynClient, err := loadDynamicClient()
+			if err != nil {
+				return fmt.Errorf("not logged in — run: kedge login --hub-url <hub-url>\n(original error: %w)", err)
+			}
+			events := dynClient.Resource(eventGVR).Namespace(namespace)
+			list, err := events.List(cmd.Context(), metav1.ListOptions{})
+			if err != nil {
+				return fmt.Errorf("listing events: %w", err)
+			}
+			matched := filterEvents(list.Items, filter)
+
+			if !follow {
+				if output == "json" {
+					return printEventsJSON(os.Stdout, matched)
+				}
+				t := eventTable(matched)
+				if t.Len() == 0 {
+					fmt.Println("No events found.")
+					return nil
+				}
+				return t.Render(os.Stdout, output == "wide")
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+			p := &eventPrinter{out: os.Stdout, output: output}
+			for _, e := range matched {
+				if err := p.print(e); err != nil {
+					return err
+				}
+			}
+			return followEvents(ctx, events, list.GetResourceVersion(), filter, p.print)
+		},
+	}
+
+	cmd.Flags().StringVar(&object, "for", "", "Only events about this object: kind/name, a kind (edge, vw, placement, fleet or any kind) or a name")
+	cmd.Flags().StringVar(&eventType, "type", "", "Only events of this severity: Normal or Warning")
+	cmd.Flags().DurationVar(&since, "since", 0, "Only events from this long ago until now (e.g. 30m, 2h)")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Only events in this namespace (default: all namespaces)")
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Keep streaming new events until interrupted")
+	cmd.Flags().BoolVar(&allGroups, "all", false, "Include events about objects outside the kedge APIs (pods, namespaces, ...)")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output format: \"wide\" adds more columns, \"json\" prints the events")
+	return cmd
+}
+
+// filterEvents returns the events passing f, oldest first.
+func filterEvents(items []unstructured.Unstructured, f *eventFilter) []unstructured.Unstructured {
+	var out []unstructured.Unstructured
+	for _, e := range items {
+		if f.match(e) {
+			out = append(out, e)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return eventTime(out[i]).Before(eventTime(out[j])) })
+	return out
+}
+
+// followEvents watches events from resourceVersion and hands every new or
+// recurring one passing f to emit until ctx is done. A watch the server
+// closes is resumed; one whose resourceVersion expired starts over from a
+// fresh list, emitting only what happened after the last event seen.
+func followEvents(ctx context.Context, events dynamic.ResourceInterface, resourceVersion string, f *eventFilter, emit func(unstructured.Unstructured) error) error {
+	lastSeen := f.since
+	for {
+		w, err := events.Watch(ctx, metav1.ListOptions{ResourceVersion: resourceVersion, AllowWatchBookmarks: true})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("watching events: %w", err)
+		}
+		expired := false
+		for ev := range w.ResultChan() {
+			if ev.Type == watch.Error {
+				if apierrors.IsResourceExpired(apierrors.FromObject(ev.Object)) || apierrors.IsGone(apierrors.FromObject(ev.Object)) {
+					expired = true
+					break
+				}
+				w.Stop()
+				return fmt.Errorf("watching events: %w", apierrors.FromObject(ev.Object))
+			}
+			u, ok := ev.Object.(*unstructured.Unstructured)
+			if !ok {
+				continue
+			}
+			resourceVersion = u.GetResourceVersion()
+			if ev.Type != watch.Added && ev.Type != watch.Modified {
+				continue
+			}
+			if !f.match(*u) {
+				continue
+			}
+			if t := eventTime(*u); t.After(lastSeen) {
+				lastSeen = t
+			}
+			if err := emit(*u); err != nil {
+				w.Stop()
+				return err
+			}
+		}
+		w.Stop()
+		if ctx.Err() != nil {
+			return nil
+		}
+		if expired {
+			list, err := events.List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return fmt.Errorf("listing events: %w", err)
+			}
+			for _, e := range filterEvents(list.Items, f) {
+				if !eventTime(e).After(lastSeen) {
+					continue
+				}
+				lastSeen = eventTime(e)
+				if err := emit(e); err != nil {
+					return err
+				}
+			}
+			resourceVersion = list.GetResourceVersion()
+		}
+	}
+}
+
+// eventTable lists events in the order given.
+func eventTable(items []unstructured.Unstructured) *ui.Table {
+	t := ui.NewTable(
+		ui.Column{Header: "Last Seen"},
+		ui.Column{Header: "Type", Status: true},
+		ui.Column{Header: "Reason"},
+		ui.Column{Header: "Object"},
+		ui.Column{Header: "Namespace", Wide: true},
+		ui.Column{Header: "From", Wide: true},
+		ui.Column{Header: "Count", Wide: true},
+		ui.Column{Header: "Message"},
+	)
+	for _, e := range items {
+		t.AddRow(eventRow(e)...)
+	}
+	return t
+}
+
+// eventRow is the eventTable row for e.
+func eventRow(e unstructured.Unstructured) []string {
+	from := getNestedString(e, "source", "component")
+	if from == "" {
+		from = getNestedString(e, "reportingComponent")
+	}
+	count := getNestedInt(e, "count")
+	if count == 0 {
+		count = getNestedInt(e, "series", "count")
+	}
+	return []string{
+		formatAge(eventTime(e)),
+		formatStringOrDash(getNestedString(e, "type")),
+		formatStringOrDash(getNestedString(e, "reason")),
+		eventObject(e),
+		formatStringOrDash(e.GetNamespace()),
+		formatStringOrDash(from),
+		strconv.FormatInt(max(count, 1), 10),
+		getNestedString(e, "message"),
+	}
+}
+
+// eventObject names an event's involved object as kind/name.
+func eventObject(e unstructured.Unstructured) string {
+	return strings.ToLower(getNestedString(e, "involvedObject", "kind")) + "/" + getNestedString(e, "involvedObject", "name")
+}
+
+// eventPrinter streams events one line at a time for --follow. Lines are
+// padded to fixed widths since later rows are not known up front.
+type eventPrinter struct {
+	out    io.Writer
+	output string
+	header bool
+}
+
+func (p *eventPrinter) print(e unstructured.Unstructured) error {
+	if p.output == "json" {
+		return json.NewEncoder(p.out).Encode(e.Object)
+	}
+	row := eventRow(e)
+	// Streamed lines show when the event happened rather than an age that
+	// goes stale as soon as it is printed.
+	row[0] = eventTime(e).Local().Format(time.TimeOnly)
+	headers := []string{"TIME", "TYPE", "REASON", "OBJECT", "NAMESPACE", "FROM", "COUNT", "MESSAGE"}
+	if p.output != "wide" {
+		row = append(row[:4], row[7])
+		headers = append(headers[:4], headers[7])
+	}
+	if !p.header {
+		p.header = true
+		if err := p.line(headers); err != nil {
+			return err
+		}
+	}
+	return p.line(row)
+}
+
+// eventColumnWidths are the --follow column widths of everything before
+// the message, for the default and the wide layout.
+var eventColumnWidths = []int{8, 7, 22, 36, 16, 16, 5}
+
+func (p *eventPrinter) line(cols []string) error {
+	var b strings.Builder
+	last := len(cols) - 1
+	for i, c := range cols[:last] {
+		fmt.Fprintf(&b, "%-*s  ", eventColumnWidths[i], c)
+	}
+	b.WriteString(cols[last])
+	_, err := fmt.Fprintln(p.out, strings.TrimRight(b.String(), " "))
+	return err
+}
+
+func printEventsJSON(w io.Writer, items []unstructured.Unstructured) error {
+	objs := make([]map[string]interface{}, 0, len(items))
+	for _, e := range items {
+		objs = append(objs, e.Object)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(objs)
+}
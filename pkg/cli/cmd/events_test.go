@@ -0,0 +1,172 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func testEvent(name, apiVersion, kind, object, eventType, reason string, at time.Time) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Event",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+		"involvedObject": map[string]interface{}{
+			"apiVersion": apiVersion, "kind": kind, "name": object,
+		},
+		"type":          eventType,
+		"reason":        reason,
+		"message":       reason + " happened",
+		"lastTimestamp": at.UTC().Format(time.RFC3339),
+	}}
+}
+
+func TestParseEventObject(t *testing.T) {
+	for _, tc := range []struct {
+		in    string
+		kinds []string
+		name  string
+	}{
+		{"", nil, ""},
+		{"edge/store-7", []string{"KubernetesCluster", "LinuxServer"}, "store-7"},
+		{"vw/web", []string{"Workload"}, "web"},
+		{"Placement/web-1", []string{"Placement"}, "web-1"},
+		{"FleetCommand/upgrade", []string{"FleetCommand"}, "upgrade"},
+		{"placement", []string{"Placement"}, ""},
+		{"store-7", nil, "store-7"},
+	} {
+		kinds, name, err := parseEventObject(tc.in)
+		if err != nil {
+			t.Fatalf("%q: %v", tc.in, err)
+		}
+		if strings.Join(kinds, ",") != strings.Join(tc.kinds, ",") || name != tc.name {
+			t.Errorf("%q = %v, %q; want %v, %q", tc.in, kinds, name, tc.kinds, tc.name)
+		}
+	}
+	for _, bad := range []string{"edge/", "/store-7"} {
+		if _, _, err := parseEventObject(bad); err == nil {
+			t.Errorf("%q parsed, want an error", bad)
+		}
+	}
+}
+
+func TestFilterEvents(t *testing.T) {
+	now := time.Now()
+	items := []unstructured.Unstructured{
+		*testEvent("e1", "edges.kedge.faros.sh/v1alpha1", "Workload", "web", "Normal", "RolloutProgressing", now.Add(-time.Minute)),
+		*testEvent("e2", "edges.kedge.faros.sh/v1alpha1", "KubernetesCluster", "store-7", "Warning", "Disconnected", now.Add(-3*time.Hour)),
+		*testEvent("e3", "v1", "Pod", "web-abc", "Warning", "BackOff", now),
+		*testEvent("e4", "edges.kedge.faros.sh/v1alpha1", "Placement", "web-store-7", "Normal", "Scheduled", now.Add(-2*time.Minute)),
+		*testEvent("e5", "edges.kedge.faros.sh/v1alpha1", "Placement", "web-store-7", "Warning", "BackOff", now.Add(-30*time.Second)),
+	}
+	// e5 was forwarded from the edge cluster by the agent.
+	items[4].SetLabels(map[string]string{forwardedWorkloadLabel: "web"})
+	names := func(f *eventFilter) string {
+		var out []string
+		for _, e := range filterEvents(items, f) {
+			out = append(out, e.GetName())
+		}
+		return strings.Join(out, ",")
+	}
+
+	for _, tc := range []struct {
+		desc   string
+		filter eventFilter
+		want   string
+	}{
+		{"kedge objects, oldest first", eventFilter{}, "e2,e4,e1,e5"},
+		{"all groups", eventFilter{allGroups: true}, "e2,e4,e1,e5,e3"},
+		{"edge kinds", eventFilter{kinds: eventKindAliases["edge"]}, "e2"},
+		{"by name", eventFilter{name: "web"}, "e1,e5"},
+		{"workload", eventFilter{kinds: eventKindAliases["vw"], name: "web"}, "e1,e5"},
+		{"placement", eventFilter{kinds: eventKindAliases["placement"], name: "web-store-7"}, "e4,e5"},
+		{"warnings", eventFilter{eventType: "Warning"}, "e2,e5"},
+		{"since", eventFilter{since: now.Add(-time.Hour)}, "e4,e1,e5"},
+	} {
+		if got := names(&tc.filter); got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.desc, got, tc.want)
+		}
+	}
+}
+
+func TestEventPrinter(t *testing.T) {
+	var buf bytes.Buffer
+	p := &eventPrinter{out: &buf}
+	at := time.Date(2026, 5, 1, 10, 0, 0, 0, time.Local)
+	e := testEvent("e1", "edges.kedge.faros.sh/v1alpha1", "Workload", "web", "Normal", "RolloutProgressing", at)
+	if err := p.print(*e); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.print(*e); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want a header and two events:\n%s", len(lines), buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "TIME") || !strings.HasSuffix(lines[0], "MESSAGE") || strings.Contains(lines[0], "NAMESPACE") {
+		t.Errorf("header = %q", lines[0])
+	}
+	for _, want := range []string{"10:00:00", "Normal", "RolloutProgressing", "workload/web", "RolloutProgressing happened"} {
+		if !strings.Contains(lines[1], want) {
+			t.Errorf("line %q does not contain %q", lines[1], want)
+		}
+	}
+}
+
+func TestFollowEvents(t *testing.T) {
+	now := time.Now()
+	dyn := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{eventGVR: "EventList"})
+	w := watch.NewFake()
+	dyn.PrependWatchReactor("events", clienttesting.DefaultWatchReactor(w, nil))
+
+	go func() {
+		w.Add(testEvent("e1", "v1", "Pod", "web-abc", "Normal", "Pulled", now))
+		w.Add(testEvent("e2", "edges.kedge.faros.sh/v1alpha1", "Workload", "web", "Normal", "RolloutComplete", now))
+		w.Modify(testEvent("e3", "edges.kedge.faros.sh/v1alpha1", "Workload", "other", "Normal", "RolloutComplete", now))
+		w.Add(testEvent("e4", "edges.kedge.faros.sh/v1alpha1", "Workload", "web", "Warning", "RolloutStalled", now))
+	}()
+
+	var got []string
+	errStop := errors.New("stop")
+	err := followEvents(context.Background(), dyn.Resource(eventGVR).Namespace(""), "", &eventFilter{name: "web"}, func(e unstructured.Unstructured) error {
+		got = append(got, e.GetName())
+		if len(got) == 2 {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("followEvents = %v, want the emit error", err)
+	}
+	if strings.Join(got, ",") != "e2,e4" {
+		t.Errorf("emitted %v, want [e2 e4]", got)
+	}
+}
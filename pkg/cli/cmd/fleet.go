@@ -0,0 +1,403 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+
+	"github.com/faroshq/faros-kedge/pkg/apiurl"
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
+	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+)
+
+// fleetPollInterval is how often `kedge fleet run` re-reads a FleetCommand
+// while waiting for it to finish.
+const fleetPollInterval = 2 * time.Second
+
+// fleetRequesterAnnotation is where the edges provider records who a
+// FleetCommand runs as.
+const fleetRequesterAnnotation = "edges.kedge.faros.sh/requester"
+
+func newFleetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fleet",
+		Short: "Run commands across many server edges",
+		Long: `Run one shell command on every server edge matching a label selector and
+collect per-edge exit codes and output.
+
+Each run is a FleetCommand object executed by the hub over the same SSH path
+as "kedge ssh <edge> -- <command>", on behalf of whoever started it: edges you
+could not "kedge ssh" into yourself are reported as denied. Runs are one-shot:
+to run again, start a new one.`,
+	}
+
+	cmd.AddCommand(
+		newFleetRunCommand(),
+		newFleetListCommand(),
+		newFleetGetCommand(),
+	)
+	return cmd
+}
+
+func newFleetRunCommand() *cobra.Command {
+	var (
+		selector    string
+		concurrency int32
+		timeout     time.Duration
+		name        string
+		noWait      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "run [flags] -- <command>",
+		Short: "Run a command on all matching server edges",
+		Example: `  # Check disk usage on every server edge labelled env=prod
+  kedge fleet run -l env=prod -- df -h /
+
+  # Roll a package upgrade 5 edges at a time, 10 minutes per edge
+  kedge fleet run -l role=gateway --concurrency 5 --timeout 10m -- 'sudo apt-get -y upgrade'
+
+  # Start without waiting, check on it later
+  kedge fleet run --no-wait -- uptime
+  kedge fleet get <name>`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			spec := map[string]interface{}{
+				"command": strings.Join(args, " "),
+			}
+			if selector != "" {
+				ls, err := metav1.ParseToLabelSelector(selector)
+				if err != nil {
+					return fmt.Errorf("invalid --selector: %w", err)
+				}
+				sel, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ls)
+				if err != nil {
+					return err
+				}
+				spec["edgeSelector"] = sel
+			}
+			if concurrency > 0 {
+				spec["concurrency"] = int64(concurrency)
+			}
+			if timeout > 0 {
+				spec["timeout"] = timeout.String()
+			}
+
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": kedgeclient.FleetCommandGVR.GroupVersion().String(),
+				"kind":       "FleetCommand",
+				"spec":       spec,
+			}}
+			if name != "" {
+				obj.SetName(name)
+			} else {
+				obj.SetGenerateName("fleet-")
+			}
+
+			config, err := loadRestConfig()
+			if err != nil {
+				return fmt.Errorf("not logged in — run: kedge login --hub-url <hub-url>\n(original error: %w)", err)
+			}
+			dynClient, err := dynamic.NewForConfig(config)
+			if err != nil {
+				return fmt.Errorf("creating dynamic client: %w", err)
+			}
+			created, err := dynClient.Resource(kedgeclient.FleetCommandGVR).Create(ctx, obj, metav1.CreateOptions{})
+			if err != nil {
+				return fmt.Errorf("creating fleet command: %w", err)
+			}
+			// The hub runs the command only on edges the registered
+			// requester may reach; unregistered, it never starts.
+			err = withStepUp(cmd, config, func(config *rest.Config) error {
+				return registerFleetRequester(ctx, config, created.GetName())
+			})
+			if err != nil {
+				_ = dynClient.Resource(kedgeclient.FleetCommandGVR).Delete(ctx, created.GetName(), metav1.DeleteOptions{})
+				return err
+			}
+			ui.Infof(cmd.ErrOrStderr(), "Fleet command %s created.\n", created.GetName())
+			if noWait {
+				fmt.Fprintln(cmd.OutOrStdout(), created.GetName())
+				return nil
+			}
+
+			fc, err := waitForFleetCommand(ctx, dynClient, created.GetName(), cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+			printFleetResults(cmd.OutOrStdout(), *fc)
+			if getNestedString(*fc, "status", "phase") != "Succeeded" {
+				if msg := getNestedString(*fc, "status", "message"); msg != "" {
+					return fmt.Errorf("fleet command %s failed: %s", fc.GetName(), msg)
+				}
+				return fmt.Errorf("fleet command %s failed on %d of %d edges",
+					fc.GetName(), getNestedInt(*fc, "status", "failed"), getNestedInt(*fc, "status", "total"))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", "Label selector over server edges (e.g. env=prod,region in (eu,us)); empty selects all")
+	cmd.Flags().Int32Var(&concurrency, "concurrency", 0, "Max edges running the command at once (default 10)")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Per-edge timeout (default 5m)")
+	cmd.Flags().StringVar(&name, "name", "", "Name of the FleetCommand (default: generated)")
+	cmd.Flags().BoolVar(&noWait, "no-wait", false, "Print the FleetCommand name and return without waiting for results")
+	return cmd
+}
+
+// registerFleetRequester records the caller as the requester of the named
+// FleetCommand on the edges provider, which authorizes the run per edge with
+// the caller's own rights.
+func registerFleetRequester(ctx context.Context, config *rest.Config, name string) error {
+	base, cluster := apiurl.SplitBaseAndCluster(config.Host)
+	if cluster == "default" {
+		return fmt.Errorf("cannot determine cluster name from server URL %q; expected path to contain /clusters/<name>", config.Host)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiurl.EdgeFleetCommandURL(base, cluster, name, "requester"), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", hubAccept)
+
+	transport, err := rest.TransportFor(config)
+	if err != nil {
+		return fmt.Errorf("building HTTP transport: %w", err)
+	}
+	resp, err := (&http.Client{Transport: transport, Timeout: 30 * time.Second}).Do(req)
+	if err != nil {
+		return fmt.Errorf("registering as requester of fleet command %s: %w", name, err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return hubError(fmt.Sprintf("registering as requester of fleet command %s", name), resp, body)
+	}
+	return nil
+}
+
+// fleetRequesterUser returns the user a FleetCommand was registered to run as,
+// for display; the provider verifies the stamp before acting on it.
+func fleetRequesterUser(fc unstructured.Unstructured) string {
+	var requester struct {
+		User string `json:"user"`
+	}
+	_ = json.Unmarshal([]byte(fc.GetAnnotations()[fleetRequesterAnnotation]), &requester)
+	return requester.User
+}
+
+// waitForFleetCommand polls the named FleetCommand until it reaches a terminal
+// phase, reporting progress on progress.
+func waitForFleetCommand(ctx context.Context, dynClient dynamic.Interface, name string, progress io.Writer) (*unstructured.Unstructured, error) {
+	var last string
+	for {
+		fc, err := dynClient.Resource(kedgeclient.FleetCommandGVR).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("getting fleet command %s: %w", name, err)
+		}
+		phase := getNestedString(*fc, "status", "phase")
+		if phase == "Succeeded" || phase == "Failed" {
+			return fc, nil
+		}
+		if phase == "Running" {
+			line := fmt.Sprintf("%d/%d edges done (%d failed)",
+				getNestedInt(*fc, "status", "succeeded")+getNestedInt(*fc, "status", "failed"),
+				getNestedInt(*fc, "status", "total"),
+				getNestedInt(*fc, "status", "failed"))
+			if line != last {
+				ui.Infof(progress, "%s\n", line)
+				last = line
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(fleetPollInterval):
+		}
+	}
+}
+
+func newFleetListCommand() *cobra.Command {
+	var opts listOptions
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List fleet commands",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dynClient, err := loadDynamicClient()
+			if err != nil {
+				return fmt.Errorf("not logged in — run: kedge login --hub-url <hub-url>\n(original error: %w)", err)
+			}
+			return opts.print(cmd.Context(), os.Stdout, "No fleet commands found.", func(ctx context.Context) (*ui.Table, error) {
+				list, err := dynClient.Resource(kedgeclient.FleetCommandGVR).List(ctx, metav1.ListOptions{})
+				if err != nil {
+					return nil, fmt.Errorf("listing fleet commands: %w", err)
+				}
+				return fleetCommandTable(list.Items), nil
+			})
+		},
+	}
+	opts.addFlags(cmd)
+	return cmd
+}
+
+func newFleetGetCommand() *cobra.Command {
+	var showOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "get <name>",
+		Short: "Show a fleet command's per-edge results",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			dynClient, err := loadDynamicClient()
+			if err != nil {
+				return fmt.Errorf("not logged in — run: kedge login --hub-url <hub-url>\n(original error: %w)", err)
+			}
+			fc, err := dynClient.Resource(kedgeclient.FleetCommandGVR).Get(ctx, args[0], metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("getting fleet command %s: %w", args[0], err)
+			}
+
+			w := cmd.OutOrStdout()
+			fmt.Fprintf(w, "Name:       %s\n", fc.GetName())
+			fmt.Fprintf(w, "Command:    %s\n", getNestedString(*fc, "spec", "command"))
+			fmt.Fprintf(w, "Phase:      %s\n", formatStringOrDash(getNestedString(*fc, "status", "phase")))
+			if msg := getNestedString(*fc, "status", "message"); msg != "" {
+				fmt.Fprintf(w, "Message:    %s\n", msg)
+			}
+			fmt.Fprintf(w, "Edges:      %d total, %d succeeded, %d failed\n",
+				getNestedInt(*fc, "status", "total"),
+				getNestedInt(*fc, "status", "succeeded"),
+				getNestedInt(*fc, "status", "failed"))
+			fmt.Fprintln(w)
+			if showOutput {
+				printFleetOutputs(w, *fc)
+				return nil
+			}
+			printFleetResults(w, *fc)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&showOutput, "output-tail", false, "Print each edge's captured output tail instead of the summary table")
+	return cmd
+}
+
+// printFleetResults prints one row per edge: exit code (or "-"), duration and
+// the last line of output, or the error when the command never completed.
+func printFleetResults(w io.Writer, fc unstructured.Unstructured) {
+	results, _, _ := unstructured.NestedSlice(fc.Object, "status", "results")
+	if len(results) == 0 {
+		fmt.Fprintln(w, "No edge results.")
+		return
+	}
+	tw := newTabWriter(w)
+	printRow(tw, "EDGE", "EXIT", "DURATION", "OUTPUT")
+	for _, r := range results {
+		res, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		u := unstructured.Unstructured{Object: res}
+		exit := "-"
+		if code, found, _ := unstructured.NestedInt64(res, "exitCode"); found {
+			exit = fmt.Sprintf("%d", code)
+		}
+		summary := lastLine(getNestedString(u, "output"))
+		if denied, _, _ := unstructured.NestedBool(res, "denied"); denied {
+			summary = "denied: " + getNestedString(u, "error")
+		} else if e := getNestedString(u, "error"); e != "" {
+			summary = "error: " + e
+		} else if getNestedString(u, "completionTime") == "" {
+			summary = "(running)"
+		}
+		printRow(tw, getNestedString(u, "edge"), exit, fleetEdgeDuration(u), shorten(summary, 80))
+	}
+	_ = tw.Flush()
+}
+
+// printFleetOutputs prints each edge's full captured output tail.
+func printFleetOutputs(w io.Writer, fc unstructured.Unstructured) {
+	results, _, _ := unstructured.NestedSlice(fc.Object, "status", "results")
+	for _, r := range results {
+		res, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		u := unstructured.Unstructured{Object: res}
+		header := "==> " + getNestedString(u, "edge")
+		if code, found, _ := unstructured.NestedInt64(res, "exitCode"); found {
+			header += fmt.Sprintf(" (exit %d)", code)
+		}
+		if denied, _, _ := unstructured.NestedBool(res, "denied"); denied {
+			header += " (denied: " + getNestedString(u, "error") + ")"
+		} else if e := getNestedString(u, "error"); e != "" {
+			header += " (error: " + e + ")"
+		}
+		fmt.Fprintln(w, header+" <==")
+		if out := getNestedString(u, "output"); out != "" {
+			fmt.Fprintln(w, strings.TrimRight(out, "\n"))
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+func fleetEdgeDuration(u unstructured.Unstructured) string {
+	start, err1 := time.Parse(time.RFC3339, getNestedString(u, "startTime"))
+	end, err2 := time.Parse(time.RFC3339, getNestedString(u, "completionTime"))
+	if err1 != nil || err2 != nil {
+		return "-"
+	}
+	return end.Sub(start).Round(time.Second).String()
+}
+
+// lastLine returns the last non-empty line of s.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if l := strings.TrimSpace(lines[i]); l != "" {
+			return l
+		}
+	}
+	return ""
+}
+
+// shorten cuts s to at most n runes, marking the cut with "...".
+func shorten(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-3]) + "..."
+}
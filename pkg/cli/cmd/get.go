@@ -20,109 +20,75 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/client-go/dynamic"
 
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
 	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
 )
 
 func newGetCommand() *cobra.Command {
+	var opts listOptions
+
 	cmd := &cobra.Command{
 		Use:   "get [resource]",
 		Short: "Get resources",
-		Args:  cobra.ExactArgs(1),
+		Long: `List resources of the current workspace. Resources can be named by their
+plural, singular or short name: edges (ed), workloads (vw, wl) and
+placements (pl).`,
+		Example: `  kedge get edges
+  kedge get vw -o wide`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeResourceNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			resource := args[0]
-			ctx := context.Background()
+			r, ok := lookupResource(args[0])
+			if !ok {
+				return fmt.Errorf("unknown resource type: %s (try: %s)", args[0], resourceNamesHint())
+			}
 
 			dynClient, err := loadDynamicClient()
 			if err != nil {
 				return err
 			}
 
-			switch resource {
+			switch r.Plural {
 			case "edges":
-				return listEdges(ctx, dynClient)
-			case "workloads", "vw":
-				return listWorkloads(ctx, dynClient)
+				return opts.print(cmd.Context(), os.Stdout, "No edges found.", func(ctx context.Context) (*ui.Table, error) {
+					items, err := listAllEdges(ctx, dynClient)
+					if err != nil {
+						return nil, err
+					}
+					return edgeTable(items, time.Now()), nil
+				})
+			case "workloads":
+				return opts.print(cmd.Context(), os.Stdout, "No workloads found.", func(ctx context.Context) (*ui.Table, error) {
+					list, err := dynClient.Resource(kedgeclient.WorkloadGVR).List(ctx, metav1.ListOptions{})
+					if err != nil {
+						return nil, fmt.Errorf("listing workloads: %w", err)
+					}
+					return workloadTable(list.Items), nil
+				})
 			case "placements":
-				return listPlacements(ctx, dynClient)
+				return opts.print(cmd.Context(), os.Stdout, "No placements found.", func(ctx context.Context) (*ui.Table, error) {
+					list, err := dynClient.Resource(kedgeclient.PlacementGVR).List(ctx, metav1.ListOptions{})
+					if err != nil {
+						return nil, fmt.Errorf("listing placements: %w", err)
+					}
+					return placementTable(list.Items), nil
+				})
 			default:
-				return fmt.Errorf("unknown resource type: %s (try: edges, workloads, placements)", resource)
+				return fmt.Errorf("kedge get does not list %s", r.Plural)
 			}
 		},
 	}
+	opts.addFlags(cmd)
 
 	return cmd
 }
 
-func listEdges(ctx context.Context, dynClient dynamic.Interface) error {
-	items, err := listAllEdges(ctx, dynClient)
-	if err != nil {
-		return err
-	}
-
-	tw := newTabWriter(os.Stdout)
-	printRow(tw, "NAME", "TYPE", "PHASE", "CONNECTED", "AGE")
-
-	for _, item := range items {
-		edgeType := "kubernetes"
-		if item.GetKind() == "LinuxServer" {
-			edgeType = "server"
-		}
-		phase := getNestedString(item, "status", "phase")
-		connected, _, _ := unstructuredNestedBool(item.Object, "status", "connected")
-		age := formatAge(item.GetCreationTimestamp().Time)
-		printRow(tw, item.GetName(), formatStringOrDash(edgeType), formatStringOrDash(phase),
-			fmt.Sprintf("%v", connected), age)
-	}
-
-	_ = tw.Flush()
-	return nil
-}
-
-func listWorkloads(ctx context.Context, dyn dynamic.Interface) error {
-	list, err := dyn.Resource(kedgeclient.WorkloadGVR).List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return fmt.Errorf("listing workloads: %w", err)
-	}
-	tw := newTabWriter(os.Stdout)
-	printRow(tw, "NAME", "IMAGE", "PHASE", "READY", "AGE")
-	for _, item := range list.Items {
-		image := getNestedString(item, "spec", "simple", "image")
-		phase := getNestedString(item, "status", "phase")
-		ready := getNestedInt(item, "status", "readyReplicas")
-		replicas := getNestedInt(item, "spec", "replicas")
-		age := formatAge(item.GetCreationTimestamp().Time)
-		printRow(tw, item.GetName(), formatStringOrDash(image), formatStringOrDash(phase),
-			fmt.Sprintf("%d/%d", ready, replicas), age)
-	}
-	_ = tw.Flush()
-	return nil
-}
-
-func listPlacements(ctx context.Context, dyn dynamic.Interface) error {
-	list, err := dyn.Resource(kedgeclient.PlacementGVR).List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return fmt.Errorf("listing placements: %w", err)
-	}
-	tw := newTabWriter(os.Stdout)
-	printRow(tw, "NAME", "EDGE", "PHASE", "READY", "AGE")
-	for _, item := range list.Items {
-		edge := getNestedString(item, "spec", "edgeName")
-		phase := getNestedString(item, "status", "phase")
-		ready := getNestedInt(item, "status", "readyReplicas")
-		age := formatAge(item.GetCreationTimestamp().Time)
-		printRow(tw, item.GetName(), formatStringOrDash(edge), formatStringOrDash(phase),
-			fmt.Sprintf("%d", ready), age)
-	}
-	_ = tw.Flush()
-	return nil
-}
-
 func getNestedString(u unstructured.Unstructured, fields ...string) string {
 	val, found, err := unstructured.NestedString(u.Object, fields...)
 	if err != nil || !found {
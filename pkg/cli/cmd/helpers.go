@@ -19,15 +19,22 @@ package cmd
 import (
 	"fmt"
 	"io"
+	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"text/tabwriter"
 	"time"
 
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
+	"github.com/faroshq/faros-kedge/pkg/problem"
 )
 
 var (
@@ -48,6 +55,35 @@ func normalizeHubURL(u string) string {
 	return u
 }
 
+// kedgeKubeconfigPath is the kubeconfig file `kedge login` writes by default:
+// a kedge-owned file next to, not inside, the user's ~/.kube/config.
+func kedgeKubeconfigPath() string {
+	return filepath.Join(clientcmd.RecommendedConfigDir, "kedge", "config")
+}
+
+// cliLoadingRules returns the kubeconfig loading rules every command uses:
+// --kubeconfig when set; otherwise the client-go defaults with the file
+// `kedge login` writes by default taking precedence, unless $KUBECONFIG
+// names the files explicitly.
+func cliLoadingRules() *clientcmd.ClientConfigLoadingRules {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if kubeconfig != "" {
+		rules.ExplicitPath = kubeconfig
+		return rules
+	}
+	if os.Getenv(clientcmd.RecommendedConfigPathEnvVar) == "" {
+		if path := kedgeKubeconfigPath(); fileExists(path) {
+			rules.Precedence = append([]string{path}, rules.Precedence...)
+		}
+	}
+	return rules
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
 func loadRestConfig() (*rest.Config, error) {
 	var config *rest.Config
 	var err error
@@ -56,9 +92,8 @@ func loadRestConfig() (*rest.Config, error) {
 	} else {
 		config, err = rest.InClusterConfig()
 		if err != nil {
-			loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
 			configOverrides := &clientcmd.ConfigOverrides{}
-			kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
+			kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(cliLoadingRules(), configOverrides)
 			config, err = kubeConfig.ClientConfig()
 		}
 	}
@@ -110,6 +145,36 @@ func printRow(tw *tabwriter.Writer, cols ...string) {
 	_, _ = fmt.Fprintln(tw, strings.Join(cols, "\t"))
 }
 
+// printConditions prints obj's status.conditions as an indented table under a
+// "Conditions:" heading, for describe output.
+func printConditions(w io.Writer, obj unstructured.Unstructured) {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	fmt.Fprintln(w, "Conditions:")
+	if len(conditions) == 0 {
+		fmt.Fprintln(w, "  <none>")
+		return
+	}
+	tw := newTabWriter(w)
+	printRow(tw, "  TYPE", "STATUS", "REASON", "AGE", "MESSAGE")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		u := unstructured.Unstructured{Object: cond}
+		age := "-"
+		if ts, err := time.Parse(time.RFC3339, getNestedString(u, "lastTransitionTime")); err == nil {
+			age = formatAge(ts)
+		}
+		printRow(tw, "  "+getNestedString(u, "type"),
+			formatStringOrDash(getNestedString(u, "status")),
+			formatStringOrDash(getNestedString(u, "reason")),
+			age,
+			getNestedString(u, "message"))
+	}
+	_ = tw.Flush()
+}
+
 // externalizeEdgeURLFromConfig replaces the host in an edge URL with the hub's
 // external address from a rest.Config. edge.Status.URL may use an internal host
 // (e.g. localhost) for kcp mount resolution; this function swaps in the hub's
@@ -164,3 +229,37 @@ func externalizeEdgeURL(edgeURL string, rawConfig *clientcmdapi.Config) (string,
 	hubBase := hubParsed.Scheme + "://" + hubParsed.Host
 	return hubBase + parsed.Path, nil
 }
+
+// hubAccept is the Accept header for raw hub calls: JSON on success, RFC 7807
+// problem+json (with a stable reason code) on failure.
+const hubAccept = "application/json, " + problem.ContentType
+
+// hubError turns a non-2xx hub response into a user-facing error, keyed off
+// the problem reason code rather than the bare HTTP status. The reason is
+// carried on the error for --error-format=json.
+func hubError(op string, resp *http.Response, body []byte) error {
+	p := problem.Parse(resp.StatusCode, resp.Header.Get("Retry-After"), body)
+	return &ui.ReasonError{Reason: p.Reason, Err: hubErrorMessage(op, resp, p)}
+}
+
+func hubErrorMessage(op string, resp *http.Response, p *problem.Problem) error {
+	switch p.Reason {
+	case problem.ReasonTokenExpired:
+		return fmt.Errorf("%s: your session has expired — run: kedge login", op)
+	case problem.ReasonUnauthorized:
+		return fmt.Errorf("%s: the hub did not accept your credentials — run: kedge login", op)
+	case problem.ReasonAdminRequired:
+		return fmt.Errorf("%s: this requires hub admin rights", op)
+	case problem.ReasonStepUpRequired:
+		return fmt.Errorf("%s: this requires a recent sign-in — run: kedge login --step-up", op)
+	case problem.ReasonForbidden:
+		return fmt.Errorf("%s: permission denied: %s", op, p.Error())
+	case problem.ReasonTooManyRequests:
+		if p.RetryAfter > 0 {
+			return fmt.Errorf("%s: too many requests — retry in %ds", op, p.RetryAfter)
+		}
+		return fmt.Errorf("%s: too many requests — retry shortly", op)
+	default:
+		return fmt.Errorf("%s (%s): %s", op, resp.Status, p.Error())
+	}
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 
@@ -66,88 +67,16 @@ Examples:
   KUBECONFIG=$(kedge kubeconfig edge my-edge) kubectl get pods`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name := args[0]
-			ctx := context.Background()
-
-			// 1. Fetch the Edge resource.
 			dynClient, err := loadDynamicClient()
 			if err != nil {
 				return err
 			}
-
-			edge, err := dynClient.Resource(kedgeclient.KubernetesClusterGVR).Get(ctx, name, metav1.GetOptions{})
-			if err != nil {
-				return fmt.Errorf("getting edge %q: %w", name, err)
-			}
-
-			// 2. Read edge.Status.URL (JSON field name "URL" — note capital U per the API type).
-			// The status URL may be an internal address (for kcp mount resolution).
-			// We extract the path and combine it with the hub's external address.
-			edgeURL, _, _ := unstructuredNestedField(edge.Object, "status", "URL")
-			edgeURLStr, _ := edgeURL.(string)
-			if edgeURLStr == "" {
-				return fmt.Errorf("edge %q has no URL set in status (is the edge Ready and the mount workspace initialised?)", name)
-			}
-
-			// 3. Load the current kubeconfig to reuse credentials from the active context.
-			loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
-			if kubeconfig != "" {
-				loadingRules.ExplicitPath = kubeconfig
-			}
-			rawConfig, err := loadingRules.GetStartingConfig()
-			if err != nil {
-				return fmt.Errorf("loading kubeconfig: %w", err)
-			}
-
-			// 4. Extract the current context's auth info.
-			var authInfo *clientcmdapi.AuthInfo
-			if currentCtx, ok := rawConfig.Contexts[rawConfig.CurrentContext]; ok {
-				if ai, ok := rawConfig.AuthInfos[currentCtx.AuthInfo]; ok {
-					authInfo = ai
-				}
-			}
-
-			// 5. Build the external edge URL by combining the hub server address
-			// with the path from edge.Status.URL (which may use an internal host).
-			externalEdgeURL, err := externalizeEdgeURL(edgeURLStr, rawConfig)
-			if err != nil {
-				return fmt.Errorf("constructing external edge URL: %w", err)
-			}
-
-			contextName := name + "-edge"
-			newConfig := clientcmdapi.NewConfig()
-
-			// Use InsecureSkipTLSVerify by default; inherit CA from existing cluster if available.
-			clusterEntry := &clientcmdapi.Cluster{
-				Server:                externalEdgeURL,
-				InsecureSkipTLSVerify: true,
-			}
-			if currentCtx, ok := rawConfig.Contexts[rawConfig.CurrentContext]; ok {
-				if cl, ok := rawConfig.Clusters[currentCtx.Cluster]; ok && len(cl.CertificateAuthorityData) > 0 {
-					clusterEntry.CertificateAuthorityData = cl.CertificateAuthorityData
-					clusterEntry.InsecureSkipTLSVerify = false
-				}
-			}
-
-			newConfig.Clusters[contextName] = clusterEntry
-			if authInfo != nil {
-				newConfig.AuthInfos[contextName] = authInfo
-			} else {
-				newConfig.AuthInfos[contextName] = &clientcmdapi.AuthInfo{}
-			}
-			newConfig.Contexts[contextName] = &clientcmdapi.Context{
-				Cluster:  contextName,
-				AuthInfo: contextName,
-			}
-			newConfig.CurrentContext = contextName
-
-			// 6. Serialize the kubeconfig to YAML.
-			kubeconfigBytes, err := clientcmd.Write(*newConfig)
+			kubeconfigBytes, err := edgeKubeconfig(context.Background(), dynClient, args[0])
 			if err != nil {
-				return fmt.Errorf("serializing kubeconfig: %w", err)
+				return err
 			}
 
-			// 7. Output to stdout or a file.
+			// Output to stdout or a file.
 			if output == "" || output == "-" {
 				_, err = os.Stdout.Write(kubeconfigBytes)
 				return err
@@ -166,3 +95,78 @@ Examples:
 
 	return cmd
 }
+
+// edgeKubeconfig builds a kubeconfig for the Kubernetes edge name that talks
+// to its API through the hub, reusing the current context's credentials.
+func edgeKubeconfig(ctx context.Context, dynClient dynamic.Interface, name string) ([]byte, error) {
+	// 1. Fetch the Edge resource.
+	edge, err := dynClient.Resource(kedgeclient.KubernetesClusterGVR).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("getting edge %q: %w", name, err)
+	}
+
+	// 2. Read edge.Status.URL (JSON field name "URL" — note capital U per the API type).
+	// The status URL may be an internal address (for kcp mount resolution).
+	// We extract the path and combine it with the hub's external address.
+	edgeURL, _, _ := unstructuredNestedField(edge.Object, "status", "URL")
+	edgeURLStr, _ := edgeURL.(string)
+	if edgeURLStr == "" {
+		return nil, fmt.Errorf("edge %q has no URL set in status (is the edge Ready and the mount workspace initialised?)", name)
+	}
+
+	// 3. Load the current kubeconfig to reuse credentials from the active context.
+	loadingRules := cliLoadingRules()
+	rawConfig, err := loadingRules.GetStartingConfig()
+	if err != nil {
+		return nil, fmt.Errorf("loading kubeconfig: %w", err)
+	}
+
+	// 4. Extract the current context's auth info.
+	var authInfo *clientcmdapi.AuthInfo
+	if currentCtx, ok := rawConfig.Contexts[rawConfig.CurrentContext]; ok {
+		if ai, ok := rawConfig.AuthInfos[currentCtx.AuthInfo]; ok {
+			authInfo = ai
+		}
+	}
+
+	// 5. Build the external edge URL by combining the hub server address
+	// with the path from edge.Status.URL (which may use an internal host).
+	externalEdgeURL, err := externalizeEdgeURL(edgeURLStr, rawConfig)
+	if err != nil {
+		return nil, fmt.Errorf("constructing external edge URL: %w", err)
+	}
+
+	contextName := name + "-edge"
+	newConfig := clientcmdapi.NewConfig()
+
+	// Use InsecureSkipTLSVerify by default; inherit CA from existing cluster if available.
+	clusterEntry := &clientcmdapi.Cluster{
+		Server:                externalEdgeURL,
+		InsecureSkipTLSVerify: true,
+	}
+	if currentCtx, ok := rawConfig.Contexts[rawConfig.CurrentContext]; ok {
+		if cl, ok := rawConfig.Clusters[currentCtx.Cluster]; ok && len(cl.CertificateAuthorityData) > 0 {
+			clusterEntry.CertificateAuthorityData = cl.CertificateAuthorityData
+			clusterEntry.InsecureSkipTLSVerify = false
+		}
+	}
+
+	newConfig.Clusters[contextName] = clusterEntry
+	if authInfo != nil {
+		newConfig.AuthInfos[contextName] = authInfo
+	} else {
+		newConfig.AuthInfos[contextName] = &clientcmdapi.AuthInfo{}
+	}
+	newConfig.Contexts[contextName] = &clientcmdapi.Context{
+		Cluster:  contextName,
+		AuthInfo: contextName,
+	}
+	newConfig.CurrentContext = contextName
+
+	// 6. Serialize the kubeconfig to YAML.
+	kubeconfigBytes, err := clientcmd.Write(*newConfig)
+	if err != nil {
+		return nil, fmt.Errorf("serializing kubeconfig: %w", err)
+	}
+	return kubeconfigBytes, nil
+}
@@ -25,8 +25,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"time"
@@ -50,19 +52,48 @@ func newLoginCommand() *cobra.Command {
 		insecureSkipTLSVerify bool
 		token                 string
 		interactive           bool
+		stepUp                bool
+		idp                   string
+		kubeconfigPath        string
+		force                 bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "login",
 		Short: "Authenticate with the kedge hub via OIDC or static token",
+		Long: `Authenticate with the kedge hub via OIDC or static token.
+
+The hub's kubeconfig context "kedge" is written to ~/.kube/kedge/config
+unless --kubeconfig-path (or --kubeconfig) names another file; other kedge
+commands read that file automatically. Writing into your own kubeconfig
+(~/.kube/config or a file listed in $KUBECONFIG) requires --force.`,
+		Example: `  # Log in and use the context with kubectl
+  kedge login
+  kubectl --kubeconfig ~/.kube/kedge/config get namespaces
+
+  # Merge the kedge context into ~/.kube/config
+  kedge login --kubeconfig-path ~/.kube/config --force
+
+  # Sign in with an identity provider other than the hub's primary
+  kedge login --idp partners`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if hubURL == "" {
 				hubURL = DefaultHubURL
 				fmt.Printf("Using default hub: %s (override with --hub-url)\n", hubURL)
 			}
 			hubURL = normalizeHubURL(hubURL)
+			if stepUp && token != "" {
+				return fmt.Errorf("--step-up re-authenticates with the identity provider and cannot be used with --token")
+			}
+			if idp != "" && token != "" {
+				return fmt.Errorf("--idp signs in with an identity provider and cannot be used with --token")
+			}
+			target, err := loginKubeconfigTarget(kubeconfigPath, force)
+			if err != nil {
+				return err
+			}
 			if token != "" {
-				if err := runStaticTokenLogin(hubURL, token, insecureSkipTLSVerify); err != nil {
+				if err := runStaticTokenLogin(hubURL, token, insecureSkipTLSVerify, target); err != nil {
 					return err
 				}
 			} else {
@@ -76,7 +107,7 @@ func newLoginCommand() *cobra.Command {
 				}
 				ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Minute)
 				defer cancel()
-				if err := runLogin(ctx, hubURL, insecureSkipTLSVerify); err != nil {
+				if _, err := runLogin(ctx, hubURL, idp, insecureSkipTLSVerify, stepUp, target); err != nil {
 					return err
 				}
 			}
@@ -87,6 +118,8 @@ func newLoginCommand() *cobra.Command {
 				if insecureSkipTLSVerify {
 					globalInsecureTLS = true
 				}
+				// Likewise retarget the file just written.
+				kubeconfig = target
 				fmt.Println()
 				return runUse(cmd.Context(), "", "")
 			}
@@ -98,6 +131,10 @@ func newLoginCommand() *cobra.Command {
 	cmd.Flags().BoolVar(&insecureSkipTLSVerify, "insecure-skip-tls-verify", false, "Skip TLS certificate verification")
 	cmd.Flags().StringVar(&token, "token", "", "Static bearer token (skips OIDC browser flow)")
 	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "After login, interactively pick the organization and workspace")
+	cmd.Flags().BoolVar(&stepUp, "step-up", false, "Sign in again at the identity provider even with an active session, for operations that require a recent sign-in")
+	cmd.Flags().StringVar(&idp, "idp", "", "Identity provider to sign in with, by name, when the hub trusts several (default: the hub's primary)")
+	cmd.Flags().StringVar(&kubeconfigPath, "kubeconfig-path", "", "Kubeconfig file to write the kedge context to (default: --kubeconfig, else ~/.kube/kedge/config)")
+	cmd.Flags().BoolVar(&force, "force", false, "Allow writing the kedge context into your own kubeconfig (~/.kube/config or a $KUBECONFIG file)")
 
 	return cmd
 }
@@ -129,7 +166,63 @@ func checkHubAuthMode(hubURL string, insecure bool) (bool, error) {
 	return result.OIDC, nil
 }
 
-func runStaticTokenLogin(hubURL, token string, insecure bool) error {
+// loginKubeconfigTarget returns the kubeconfig file login writes to: path,
+// else --kubeconfig, else the kedge-owned default. An existing file that is
+// the user's own kubeconfig is only modified with force.
+func loginKubeconfigTarget(path string, force bool) (string, error) {
+	if path == "" {
+		path = kubeconfig
+	}
+	if path == "" {
+		return kedgeKubeconfigPath(), nil
+	}
+	if !force && fileExists(path) && isPrimaryKubeconfig(path) {
+		return "", fmt.Errorf("refusing to modify your kubeconfig %s: pass --force to merge the kedge context into it, or omit --kubeconfig-path to use %s", path, kedgeKubeconfigPath())
+	}
+	return path, nil
+}
+
+// isPrimaryKubeconfig reports whether path is ~/.kube/config or one of the
+// files listed in $KUBECONFIG.
+func isPrimaryKubeconfig(path string) bool {
+	primary := []string{clientcmd.RecommendedHomeFile}
+	primary = append(primary, filepath.SplitList(os.Getenv(clientcmd.RecommendedConfigPathEnvVar))...)
+	for _, p := range primary {
+		if p != "" && sameFile(p, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameFile compares paths after resolving symlinks, falling back to the
+// cleaned absolute paths when a file does not exist.
+func sameFile(a, b string) bool {
+	if ai, err := os.Stat(a); err == nil {
+		if bi, err := os.Stat(b); err == nil {
+			return os.SameFile(ai, bi)
+		}
+	}
+	aa, errA := filepath.Abs(a)
+	ba, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && aa == ba
+}
+
+// printKubectlHint tells the user how to reach the context written to path.
+func printKubectlHint(path, resource string) {
+	fmt.Printf("Kubeconfig context \"kedge\" has been set in %s.\n", path)
+	if path == kedgeKubeconfigPath() && os.Getenv(clientcmd.RecommendedConfigPathEnvVar) == "" {
+		// kedge commands find this file by themselves; kubectl does not.
+		fmt.Printf("Run: kubectl --kubeconfig %s get %s\n", path, resource)
+		return
+	}
+	fmt.Printf("Run: kubectl --kubeconfig %s --context=kedge get %s\n", path, resource)
+	if path == kedgeKubeconfigPath() {
+		fmt.Printf("$KUBECONFIG is set, so other kedge commands need --kubeconfig %s as well.\n", path)
+	}
+}
+
+func runStaticTokenLogin(hubURL, token string, insecure bool, kubeconfigPath string) error {
 	// Call the server's token-login endpoint to provision user/workspace
 	// and get a kubeconfig with the correct cluster URL.
 	client := &http.Client{}
@@ -144,6 +237,7 @@ func runStaticTokenLogin(hubURL, token string, insecure bool) error {
 		return fmt.Errorf("creating request: %w", err)
 	}
 	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", hubAccept)
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -157,7 +251,7 @@ func runStaticTokenLogin(hubURL, token string, insecure bool) error {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("token-login failed (status %d): %s", resp.StatusCode, string(body))
+		return hubError("token-login failed", resp, body)
 	}
 
 	var loginResp tenancyv1alpha1.LoginResponse
@@ -165,27 +259,32 @@ func runStaticTokenLogin(hubURL, token string, insecure bool) error {
 		return fmt.Errorf("parsing login response: %w", err)
 	}
 
-	if err := mergeKubeconfig(loginResp.Kubeconfig); err != nil {
+	if err := mergeKubeconfig(loginResp.Kubeconfig, kubeconfigPath); err != nil {
 		return fmt.Errorf("merging kubeconfig: %w", err)
 	}
 
 	fmt.Printf("Login successful! Logged in as %s (user: %s)\n", loginResp.Email, loginResp.UserID)
-	fmt.Printf("Kubeconfig context \"kedge\" has been set.\n")
-	fmt.Printf("Run: kubectl --context=kedge get namespaces\n")
+	printKubectlHint(kubeconfigPath, "namespaces")
 	return nil
 }
 
-func runLogin(ctx context.Context, hubURL string, insecure bool) error {
+// runLogin runs the browser OIDC login and returns the hub's response. idp
+// names the identity provider to sign in with, by name or issuer URL; empty
+// is the hub's primary. With stepUp the identity provider is asked to
+// authenticate the user again even when they have a session (max_age=0), so
+// the new ID token satisfies the hub's step-up policy for sensitive
+// operations. The hub's kubeconfig is merged into kubeconfigPath.
+func runLogin(ctx context.Context, hubURL, idp string, insecure, stepUp bool, kubeconfigPath string) (tenancyv1alpha1.LoginResponse, error) {
 	// 1. Start local callback server on a random port.
 	authenticator := cliauth.NewLocalhostCallbackAuthenticator()
 	if err := authenticator.Start(); err != nil {
-		return fmt.Errorf("starting callback server: %w", err)
+		return tenancyv1alpha1.LoginResponse{}, fmt.Errorf("starting callback server: %w", err)
 	}
 
 	// 2. Generate a random session ID and PKCE code_verifier.
 	sessionBytes := make([]byte, 3)
 	if _, err := rand.Read(sessionBytes); err != nil {
-		return fmt.Errorf("generating session ID: %w", err)
+		return tenancyv1alpha1.LoginResponse{}, fmt.Errorf("generating session ID: %w", err)
 	}
 	sessionID := hex.EncodeToString(sessionBytes)
 
@@ -195,6 +294,12 @@ func runLogin(ctx context.Context, hubURL string, insecure bool) error {
 	//    exchange the auth code without a client secret.
 	authorizeURL := fmt.Sprintf("%s/auth/authorize?p=%d&s=%s&v=%s",
 		hubURL, authenticator.Port(), sessionID, codeVerifier)
+	if idp != "" {
+		authorizeURL += "&idp=" + url.QueryEscape(idp)
+	}
+	if stepUp {
+		authorizeURL += "&max_age=0"
+	}
 
 	// 4. Open browser.
 	fmt.Printf("Opening browser for login...\n")
@@ -207,7 +312,7 @@ func runLogin(ctx context.Context, hubURL string, insecure bool) error {
 	// 6. Wait for the callback response.
 	resp, err := authenticator.WaitForResponse(ctx)
 	if err != nil {
-		return fmt.Errorf("waiting for login response: %w", err)
+		return tenancyv1alpha1.LoginResponse{}, fmt.Errorf("waiting for login response: %w", err)
 	}
 
 	// 7. Save OIDC token cache so the exec credential plugin can use it.
@@ -226,19 +331,23 @@ func runLogin(ctx context.Context, hubURL string, insecure bool) error {
 		}
 	}
 
-	// 8. Merge the received kubeconfig into ~/.kube/config.
-	if err := mergeKubeconfig(resp.Kubeconfig); err != nil {
-		return fmt.Errorf("merging kubeconfig: %w", err)
+	// 8. Merge the received kubeconfig into the target file.
+	if err := mergeKubeconfig(resp.Kubeconfig, kubeconfigPath); err != nil {
+		return tenancyv1alpha1.LoginResponse{}, fmt.Errorf("merging kubeconfig: %w", err)
 	}
 
+	if stepUp {
+		fmt.Printf("Signed in again as %s (user: %s)\n", resp.Email, resp.UserID)
+		return resp, nil
+	}
 	fmt.Printf("Login successful! Logged in as %s (user: %s)\n", resp.Email, resp.UserID)
-	fmt.Printf("Kubeconfig context \"kedge\" has been set.\n")
-	fmt.Printf("Run: kubectl --context=kedge get users\n")
-	return nil
+	printKubectlHint(kubeconfigPath, "users")
+	return resp, nil
 }
 
-// mergeKubeconfig merges the received kubeconfig bytes into the default kubeconfig file.
-func mergeKubeconfig(kubeconfigBytes []byte) error {
+// mergeKubeconfig merges the received kubeconfig bytes into the kubeconfig
+// file at configPath, creating it if needed.
+func mergeKubeconfig(kubeconfigBytes []byte, configPath string) error {
 	// Parse the new kubeconfig.
 	newConfig, err := clientcmd.Load(kubeconfigBytes)
 	if err != nil {
@@ -253,11 +362,11 @@ func mergeKubeconfig(kubeconfigBytes []byte) error {
 	rewriteKedgeExecCommand(newConfig)
 
 	// Load the existing kubeconfig.
-	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
-	existingConfig, err := loadingRules.GetStartingConfig()
-	if err != nil {
-		// If no existing config, just use the new one.
+	existingConfig, err := clientcmd.LoadFromFile(configPath)
+	if os.IsNotExist(err) {
 		existingConfig = clientcmdapi.NewConfig()
+	} else if err != nil {
+		return fmt.Errorf("loading kubeconfig %s: %w", configPath, err)
 	}
 
 	// Merge: overwrite clusters, contexts, and auth infos from the new config.
@@ -285,7 +394,6 @@ func mergeKubeconfig(kubeconfigBytes []byte) error {
 	existingConfig.CurrentContext = newConfig.CurrentContext
 
 	// Write back.
-	configPath := loadingRules.GetDefaultFilename()
 	if err := clientcmd.WriteToFile(*existingConfig, configPath); err != nil {
 		return fmt.Errorf("writing kubeconfig to %s: %w", configPath, err)
 	}
@@ -107,11 +107,10 @@ func TestMergeKubeconfigPreservesWorkspaceSelection(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			path := filepath.Join(t.TempDir(), "config")
-			t.Setenv("KUBECONFIG", path)
 			if tc.existing != "" {
 				writeKubeconfigFile(t, path, tc.existing)
 			}
-			if err := mergeKubeconfig(loginKubeconfig(t, tc.incoming)); err != nil {
+			if err := mergeKubeconfig(loginKubeconfig(t, tc.incoming), path); err != nil {
 				t.Fatalf("mergeKubeconfig: %v", err)
 			}
 			if got := mergedServer(t, path); got != tc.wantServer {
@@ -120,3 +119,47 @@ func TestMergeKubeconfigPreservesWorkspaceSelection(t *testing.T) {
 		})
 	}
 }
+
+func TestLoginKubeconfigTarget(t *testing.T) {
+	dir := t.TempDir()
+	primary := filepath.Join(dir, "config")
+	writeKubeconfigFile(t, primary, "https://other.example.com")
+	missing := filepath.Join(dir, "missing")
+	t.Setenv("KUBECONFIG", primary+string(filepath.ListSeparator)+missing)
+	t.Cleanup(func() { kubeconfig = "" })
+
+	tests := []struct {
+		name      string
+		flag      string // --kubeconfig
+		path      string // --kubeconfig-path
+		force     bool
+		want      string
+		wantError bool
+	}{
+		{name: "default is the kedge-owned file", want: kedgeKubeconfigPath()},
+		{name: "explicit other file", path: filepath.Join(dir, "kedge.yaml"), want: filepath.Join(dir, "kedge.yaml")},
+		{name: "--kubeconfig is the fallback", flag: filepath.Join(dir, "global.yaml"), want: filepath.Join(dir, "global.yaml")},
+		{name: "primary kubeconfig refused", path: primary, wantError: true},
+		{name: "primary kubeconfig via --kubeconfig refused", flag: primary, wantError: true},
+		{name: "primary kubeconfig with --force", path: primary, force: true, want: primary},
+		{name: "missing $KUBECONFIG file is created", path: missing, want: missing},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			kubeconfig = tc.flag
+			got, err := loginKubeconfigTarget(tc.path, tc.force)
+			if tc.wantError {
+				if err == nil {
+					t.Fatalf("loginKubeconfigTarget = %q, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loginKubeconfigTarget: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("loginKubeconfigTarget = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -98,10 +98,7 @@ Usage with Claude Desktop (claude_desktop_config.json):
 
 func runMCPURL(_ *cobra.Command, edgeName, mcpserverName string) error {
 	// Load the current kubeconfig.
-	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
-	if kubeconfig != "" {
-		loadingRules.ExplicitPath = kubeconfig
-	}
+	loadingRules := cliLoadingRules()
 	clientCfg := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
 		loadingRules,
 		&clientcmd.ConfigOverrides{},
@@ -0,0 +1,168 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
+	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+)
+
+func newPlacementCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "placements",
+		Aliases: mustResource("placements").aliases("placements"),
+		Short:   "Inspect workload placements on edges",
+		Long: `Inspect the Placements the scheduler created for your Workloads — one per
+selected edge — to debug why a workload is or isn't running where expected.`,
+	}
+
+	cmd.AddCommand(
+		newPlacementListCommand(),
+		newPlacementDescribeCommand(),
+		newPlacementBundleCommand(),
+	)
+	return cmd
+}
+
+func newPlacementListCommand() *cobra.Command {
+	var (
+		workload string
+		opts     listOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List placements",
+		Example: `  # All placements in the current workspace
+  kedge placements list
+
+  # Only the placements of one workload, refreshed as they roll out
+  kedge placements list --vw my-app --watch`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dynClient, err := loadDynamicClient()
+			if err != nil {
+				return fmt.Errorf("not logged in — run: kedge login --hub-url <hub-url>\n(original error: %w)", err)
+			}
+			return opts.print(cmd.Context(), os.Stdout, "No placements found.", func(ctx context.Context) (*ui.Table, error) {
+				list, err := dynClient.Resource(kedgeclient.PlacementGVR).List(ctx, metav1.ListOptions{})
+				if err != nil {
+					return nil, fmt.Errorf("listing placements: %w", err)
+				}
+				var items []unstructured.Unstructured
+				for _, item := range list.Items {
+					if workload != "" && getNestedString(item, "spec", "workloadRef", "name") != workload {
+						continue
+					}
+					items = append(items, item)
+				}
+				return placementTable(items), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&workload, "vw", "", "Only show placements of this Workload")
+	opts.addFlags(cmd)
+	return cmd
+}
+
+func newPlacementDescribeCommand() *cobra.Command {
+	var namespace string
+
+	cmd := &cobra.Command{
+		Use:   "describe <name>",
+		Short: "Show placement details, conditions and applied resources",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			ctx := context.Background()
+
+			dynClient, err := loadDynamicClient()
+			if err != nil {
+				return fmt.Errorf("not logged in — run: kedge login --hub-url <hub-url>\n(original error: %w)", err)
+			}
+
+			p, err := dynClient.Resource(kedgeclient.PlacementGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("getting placement %s/%s: %w", namespace, name, err)
+			}
+
+			describePlacement(os.Stdout, *p)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace of the placement")
+	return cmd
+}
+
+// describePlacement prints a human-readable description of a Placement.
+func describePlacement(w io.Writer, p unstructured.Unstructured) {
+	workloadNS := getNestedString(p, "spec", "workloadRef", "namespace")
+	if workloadNS == "" {
+		workloadNS = p.GetNamespace()
+	}
+
+	fmt.Fprintf(w, "Name:          %s\n", p.GetName())
+	fmt.Fprintf(w, "Namespace:     %s\n", p.GetNamespace())
+	fmt.Fprintf(w, "Workload:      %s/%s\n", workloadNS, formatStringOrDash(getNestedString(p, "spec", "workloadRef", "name")))
+	fmt.Fprintf(w, "Edge:          %s\n", formatStringOrDash(getNestedString(p, "spec", "edgeName")))
+	fmt.Fprintf(w, "Phase:         %s\n", formatStringOrDash(getNestedString(p, "status", "phase")))
+	fmt.Fprintf(w, "Ready:         %d\n", getNestedInt(p, "status", "readyReplicas"))
+	fmt.Fprintf(w, "Revision:      %s\n", placementRevision(p))
+	manifests, _, _ := unstructured.NestedSlice(p.Object, "spec", "manifests")
+	fmt.Fprintf(w, "Manifests:     %d\n", len(manifests))
+	fmt.Fprintf(w, "Created:       %s\n", p.GetCreationTimestamp().Format("2006-01-02 15:04:05"))
+
+	printConditions(w, p)
+
+	resources, _, _ := unstructured.NestedSlice(p.Object, "status", "resources")
+	if len(resources) > 0 {
+		fmt.Fprintln(w, "Resources:")
+		tw := newTabWriter(w)
+		printRow(tw, "  KIND", "NAMESPACE", "NAME")
+		for _, r := range resources {
+			res, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			u := unstructured.Unstructured{Object: res}
+			printRow(tw, "  "+getNestedString(u, "kind"),
+				formatStringOrDash(getNestedString(u, "namespace")),
+				getNestedString(u, "name"))
+		}
+		_ = tw.Flush()
+	}
+}
+
+// placementRevision renders "<applied>/<desired>" generations; the applied one
+// is what the agent last rolled out on the edge ("-" until the first apply).
+func placementRevision(p unstructured.Unstructured) string {
+	applied := "-"
+	if g := getNestedInt(p, "status", "observedGeneration"); g > 0 {
+		applied = fmt.Sprintf("%d", g)
+	}
+	return fmt.Sprintf("%s/%d", applied, p.GetGeneration())
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/faroshq/faros-kedge/pkg/agent/bundle"
+	agentReconciler "github.com/faroshq/faros-kedge/pkg/agent/reconciler"
+	"github.com/faroshq/faros-kedge/pkg/apiurl"
+	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+)
+
+func newPlacementBundleCommand() *cobra.Command {
+	var (
+		edge       string
+		signingKey string
+		output     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Export an edge's placements as a signed bundle for offline agents",
+		Long: `Export the Placements of a kubernetes edge, with the Workloads they reference
+and the edge itself, into a file signed with an Ed25519 key. Carry the file to
+an edge that cannot reach the hub and run the agent with
+"kedge agent run --placement-bundle <file> --placement-bundle-public-key <key>":
+it applies the placements as if the hub had delivered them.
+
+Create a key pair with:
+
+  openssl genpkey -algorithm ed25519 -out bundle-key.pem
+  openssl pkey -in bundle-key.pem -pubout -out bundle-key.pub.pem`,
+		Example: `  kedge placements bundle --edge store-17 --signing-key bundle-key.pem -o store-17.bundle.json`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if edge == "" || signingKey == "" || output == "" {
+				return fmt.Errorf("--edge, --signing-key and --output are required")
+			}
+			key, err := bundle.ReadPrivateKey(signingKey)
+			if err != nil {
+				return err
+			}
+			config, err := loadRestConfig()
+			if err != nil {
+				return fmt.Errorf("not logged in — run: kedge login --hub-url <hub-url>\n(original error: %w)", err)
+			}
+			dynClient, err := dynamic.NewForConfig(config)
+			if err != nil {
+				return err
+			}
+			_, cluster := apiurl.SplitBaseAndCluster(config.Host)
+			fetcher, err := agentReconciler.NewManifestFetcher(config, cluster)
+			if err != nil {
+				return err
+			}
+
+			b, err := exportPlacementBundle(cmd.Context(), dynClient, fetcher, edge)
+			if err != nil {
+				return err
+			}
+			data, err := bundle.Sign(b, key)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(output, data, 0o600); err != nil {
+				return err
+			}
+			fmt.Printf("Wrote %s: %d objects for edge %s.\n", output, len(b.Objects), edge)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&edge, "edge", "", "Kubernetes edge to export the placements of (required)")
+	cmd.Flags().StringVar(&signingKey, "signing-key", "", "Ed25519 private key (PEM) to sign the bundle with (required)")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "File to write the bundle to (required)")
+	return cmd
+}
+
+// exportPlacementBundle collects what the agent of edge reads from the hub:
+// the edge, its Placements with their manifests inlined, and the Workloads
+// they reference.
+func exportPlacementBundle(ctx context.Context, dyn dynamic.Interface, fetcher *agentReconciler.ManifestFetcher, edge string) (*bundle.Bundle, error) {
+	edgeObj, err := dyn.Resource(kedgeclient.KubernetesClusterGVR).Get(ctx, edge, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("getting kubernetes edge %q: %w", edge, err)
+	}
+	b := &bundle.Bundle{Edge: edge, Created: time.Now().UTC()}
+	b.Objects = append(b.Objects, *trimForBundle(edgeObj))
+
+	placements, err := dyn.Resource(kedgeclient.PlacementGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("listing placements: %w", err)
+	}
+	workloads := map[string]bool{}
+	for i := range placements.Items {
+		p := &placements.Items[i]
+		if getNestedString(*p, "spec", "edgeName") != edge {
+			continue
+		}
+		if err := fetcher.Inline(ctx, p); err != nil {
+			return nil, fmt.Errorf("inlining manifests of placement %s/%s: %w", p.GetNamespace(), p.GetName(), err)
+		}
+		b.Objects = append(b.Objects, *trimForBundle(p))
+
+		name := getNestedString(*p, "spec", "workloadRef", "name")
+		namespace := getNestedString(*p, "spec", "workloadRef", "namespace")
+		if namespace == "" {
+			namespace = p.GetNamespace()
+		}
+		if name == "" || workloads[namespace+"/"+name] {
+			continue
+		}
+		workloads[namespace+"/"+name] = true
+		w, err := dyn.Resource(kedgeclient.WorkloadGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("getting workload %s/%s: %w", namespace, name, err)
+		}
+		b.Objects = append(b.Objects, *trimForBundle(w))
+	}
+	return b, nil
+}
+
+// trimForBundle drops the server bookkeeping a bundle does not need.
+func trimForBundle(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	obj.SetManagedFields(nil)
+	obj.SetResourceVersion("")
+	return obj
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestPlacementRevision(t *testing.T) {
+	tests := []struct {
+		name       string
+		generation int64
+		observed   int64
+		want       string
+	}{
+		{name: "never applied", generation: 1, want: "-/1"},
+		{name: "behind", generation: 3, observed: 2, want: "2/3"},
+		{name: "current", generation: 3, observed: 3, want: "3/3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := unstructured.Unstructured{Object: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "p", "generation": tt.generation},
+			}}
+			if tt.observed != 0 {
+				_ = unstructured.SetNestedField(p.Object, tt.observed, "status", "observedGeneration")
+			}
+			if got := placementRevision(p); got != tt.want {
+				t.Errorf("placementRevision() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribePlacement(t *testing.T) {
+	tests := []struct {
+		name    string
+		object  map[string]interface{}
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "pending placement",
+			object: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "web-edge-1", "namespace": "default", "generation": int64(1)},
+				"spec": map[string]interface{}{
+					"workloadRef": map[string]interface{}{"name": "web"},
+				},
+			},
+			want: []string{
+				"Name:          web-edge-1\n",
+				"Workload:      default/web\n",
+				"Edge:          -\n",
+				"Phase:         -\n",
+				"Revision:      -/1\n",
+				"Manifests:     0\n",
+				"Conditions:\n  <none>\n",
+			},
+			notWant: []string{"Resources:"},
+		},
+		{
+			name: "applied placement",
+			object: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "web-edge-1", "namespace": "default", "generation": int64(2)},
+				"spec": map[string]interface{}{
+					"workloadRef": map[string]interface{}{"name": "web", "namespace": "apps"},
+					"edgeName":    "edge-1",
+					"manifests":   []interface{}{map[string]interface{}{}, map[string]interface{}{}},
+				},
+				"status": map[string]interface{}{
+					"phase":              "Running",
+					"readyReplicas":      int64(2),
+					"observedGeneration": int64(2),
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Ready", "status": "True", "reason": "Available", "message": "all replicas ready"},
+					},
+					"resources": []interface{}{
+						map[string]interface{}{"kind": "Deployment", "namespace": "default", "name": "web"},
+						map[string]interface{}{"kind": "ClusterRole", "name": "web-reader"},
+					},
+				},
+			},
+			want: []string{
+				"Workload:      apps/web\n",
+				"Edge:          edge-1\n",
+				"Phase:         Running\n",
+				"Ready:         2\n",
+				"Revision:      2/2\n",
+				"Manifests:     2\n",
+				"  Ready",
+				"all replicas ready",
+				"Resources:\n",
+				"  Deployment",
+				"  ClusterRole",
+			},
+			notWant: []string{"<none>"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			describePlacement(&buf, unstructured.Unstructured{Object: tt.object})
+			out := buf.String()
+			for _, s := range tt.want {
+				if !strings.Contains(out, s) {
+					t.Errorf("output missing %q:\n%s", s, out)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(out, s) {
+					t.Errorf("output unexpectedly contains %q:\n%s", s, out)
+				}
+			}
+		})
+	}
+}
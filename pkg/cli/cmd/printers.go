@@ -0,0 +1,252 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
+)
+
+// defaultWatchInterval is how often --watch refreshes a list.
+const defaultWatchInterval = 2 * time.Second
+
+// listOptions are the output flags every list command shares.
+type listOptions struct {
+	output   string
+	watch    bool
+	interval time.Duration
+}
+
+func (o *listOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.output, "output", "o", "", "Output format: \"wide\" adds more columns")
+	cmd.Flags().BoolVarP(&o.watch, "watch", "w", false, "Keep the list on screen, refreshing it until interrupted")
+	cmd.Flags().DurationVar(&o.interval, "watch-interval", defaultWatchInterval, "How often --watch refreshes the list")
+}
+
+func (o *listOptions) validate() error {
+	if o.output != "" && o.output != "wide" {
+		return fmt.Errorf("unsupported --output %q (supported: wide)", o.output)
+	}
+	if o.watch && o.interval <= 0 {
+		return fmt.Errorf("--watch-interval must be positive")
+	}
+	return nil
+}
+
+// print renders the table build returns to out, or empty when it has no rows.
+// With --watch it redraws every interval until interrupted: in place on a
+// terminal, as successive tables otherwise.
+func (o *listOptions) print(ctx context.Context, out io.Writer, empty string, build func(context.Context) (*ui.Table, error)) error {
+	if err := o.validate(); err != nil {
+		return err
+	}
+	render := func() error {
+		t, err := build(ctx)
+		if err != nil {
+			return err
+		}
+		if t.Len() == 0 {
+			_, err := fmt.Fprintln(out, empty)
+			return err
+		}
+		return t.Render(out, o.output == "wide")
+	}
+	if !o.watch {
+		return render()
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	redraw := ui.ColorEnabled(out)
+	for first := true; ; first = false {
+		if redraw {
+			// Home the cursor and clear the screen.
+			_, _ = fmt.Fprint(out, "\033[H\033[2J")
+			_, _ = fmt.Fprintf(out, "Every %s: %s\n\n", o.interval, time.Now().Format(time.TimeOnly))
+		} else if !first {
+			_, _ = fmt.Fprintln(out)
+		}
+		if err := render(); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(o.interval):
+		}
+	}
+}
+
+// edgeTable lists edges (KubernetesClusters and LinuxServers) by name.
+func edgeTable(items []unstructured.Unstructured, now time.Time) *ui.Table {
+	t := ui.NewTable(
+		ui.Column{Header: "Name"},
+		ui.Column{Header: "Type"},
+		ui.Column{Header: "Phase", Status: true},
+		ui.Column{Header: "Connected", Status: true},
+		ui.Column{Header: "Agent Version"},
+		ui.Column{Header: "Hostname", Wide: true},
+		ui.Column{Header: "Nodes", Wide: true},
+		ui.Column{Header: "CPU", Wide: true},
+		ui.Column{Header: "Memory", Wide: true},
+		ui.Column{Header: "Version", Wide: true},
+		ui.Column{Header: "Tunnel", Wide: true},
+		ui.Column{Header: "Labels", Wide: true},
+		ui.Column{Header: "Age"},
+	)
+	sort.Slice(items, func(i, j int) bool { return items[i].GetName() < items[j].GetName() })
+	for _, item := range items {
+		// The kind is the type: KubernetesCluster → kubernetes, LinuxServer → server.
+		edgeType := "kubernetes"
+		if item.GetKind() == "LinuxServer" {
+			edgeType = "server"
+		}
+		connected, _, _ := unstructuredNestedBool(item.Object, "status", "connected")
+		t.AddRow(
+			item.GetName(),
+			edgeType,
+			getNestedString(item, "status", "phase"),
+			fmt.Sprintf("%v", connected),
+			getNestedString(item, "status", "agentVersion"),
+			getNestedString(item, "status", "hostname"),
+			formatInventoryNodes(item),
+			formatInventory(item, "cpu"),
+			formatInventory(item, "memory"),
+			formatInventory(item, "kubernetesVersion"),
+			formatEdgeTunnel(item, now),
+			formatLabels(item.GetLabels()),
+			formatAge(item.GetCreationTimestamp().Time),
+		)
+	}
+	return t
+}
+
+// formatInventoryNodes renders status.inventory as "<ready>/<nodes>", or "-"
+// before the agent has reported it.
+func formatInventoryNodes(e unstructured.Unstructured) string {
+	if _, found, _ := unstructuredNestedField(e.Object, "status", "inventory", "nodes"); !found {
+		return "-"
+	}
+	return fmt.Sprintf("%d/%d", getNestedInt(e, "status", "inventory", "readyNodes"),
+		getNestedInt(e, "status", "inventory", "nodes"))
+}
+
+// formatInventory renders one status.inventory field, which for quantities
+// may be serialized as a string or a number, or "-" if unset.
+func formatInventory(e unstructured.Unstructured, field string) string {
+	v, found, _ := unstructuredNestedField(e.Object, "status", "inventory", field)
+	if !found || v == nil {
+		return "-"
+	}
+	return fmt.Sprint(v)
+}
+
+// workloadTable lists VirtualWorkloads.
+func workloadTable(items []unstructured.Unstructured) *ui.Table {
+	t := ui.NewTable(
+		ui.Column{Header: "Name"},
+		ui.Column{Header: "Image"},
+		ui.Column{Header: "Phase", Status: true},
+		ui.Column{Header: "Ready"},
+		ui.Column{Header: "Namespace", Wide: true},
+		ui.Column{Header: "Age"},
+	)
+	for _, item := range items {
+		t.AddRow(
+			item.GetName(),
+			getNestedString(item, "spec", "simple", "image"),
+			getNestedString(item, "status", "phase"),
+			fmt.Sprintf("%d/%d", getNestedInt(item, "status", "readyReplicas"), getNestedInt(item, "spec", "replicas")),
+			item.GetNamespace(),
+			formatAge(item.GetCreationTimestamp().Time),
+		)
+	}
+	return t
+}
+
+// placementTable lists Placements, sorted by namespace and name.
+func placementTable(items []unstructured.Unstructured) *ui.Table {
+	t := ui.NewTable(
+		ui.Column{Header: "Namespace"},
+		ui.Column{Header: "Name"},
+		ui.Column{Header: "Workload"},
+		ui.Column{Header: "Edge"},
+		ui.Column{Header: "Phase", Status: true},
+		ui.Column{Header: "Ready"},
+		ui.Column{Header: "Revision"},
+		ui.Column{Header: "Age"},
+	)
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].GetNamespace() != items[j].GetNamespace() {
+			return items[i].GetNamespace() < items[j].GetNamespace()
+		}
+		return items[i].GetName() < items[j].GetName()
+	})
+	for _, item := range items {
+		t.AddRow(
+			item.GetNamespace(),
+			item.GetName(),
+			getNestedString(item, "spec", "workloadRef", "name"),
+			getNestedString(item, "spec", "edgeName"),
+			getNestedString(item, "status", "phase"),
+			fmt.Sprintf("%d", getNestedInt(item, "status", "readyReplicas")),
+			placementRevision(item),
+			formatAge(item.GetCreationTimestamp().Time),
+		)
+	}
+	return t
+}
+
+// fleetCommandTable lists FleetCommands, newest first.
+func fleetCommandTable(items []unstructured.Unstructured) *ui.Table {
+	t := ui.NewTable(
+		ui.Column{Header: "Name"},
+		ui.Column{Header: "Phase", Status: true},
+		ui.Column{Header: "Total"},
+		ui.Column{Header: "Succeeded"},
+		ui.Column{Header: "Failed"},
+		ui.Column{Header: "Command"},
+		ui.Column{Header: "Requester", Wide: true},
+		ui.Column{Header: "Age"},
+	)
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].GetCreationTimestamp().After(items[j].GetCreationTimestamp().Time)
+	})
+	for _, item := range items {
+		t.AddRow(
+			item.GetName(),
+			getNestedString(item, "status", "phase"),
+			fmt.Sprintf("%d", getNestedInt(item, "status", "total")),
+			fmt.Sprintf("%d", getNestedInt(item, "status", "succeeded")),
+			fmt.Sprintf("%d", getNestedInt(item, "status", "failed")),
+			shorten(getNestedString(item, "spec", "command"), 40),
+			fleetRequesterUser(item),
+			formatAge(item.GetCreationTimestamp().Time),
+		)
+	}
+	return t
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
+)
+
+func TestEdgeTable(t *testing.T) {
+	now := time.Now()
+	edge := func(kind, name, phase string, connected bool) unstructured.Unstructured {
+		u := unstructured.Unstructured{Object: map[string]interface{}{
+			"kind": kind,
+			"metadata": map[string]interface{}{
+				"name":   name,
+				"labels": map[string]interface{}{"env": "prod"},
+			},
+			"status": map[string]interface{}{"phase": phase, "connected": connected},
+		}}
+		return u
+	}
+	items := []unstructured.Unstructured{
+		edge("LinuxServer", "web", "Disconnected", false),
+		edge("KubernetesCluster", "east", "Ready", true),
+	}
+
+	var out bytes.Buffer
+	if err := edgeTable(items, now).Render(&out, false); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines:\n%s", len(lines), out.String())
+	}
+	if strings.Join(strings.Fields(lines[0]), " ") != "NAME TYPE PHASE CONNECTED AGENT VERSION AGE" {
+		t.Errorf("header = %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "east ") || !strings.Contains(lines[1], "kubernetes") {
+		t.Errorf("edges should be sorted by name, got %q", lines[1])
+	}
+	if strings.Contains(out.String(), "env=prod") {
+		t.Error("labels are a wide column")
+	}
+
+	out.Reset()
+	if err := edgeTable(items, now).Render(&out, true); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "LABELS") || !strings.Contains(out.String(), "env=prod") {
+		t.Errorf("wide output lacks labels:\n%s", out.String())
+	}
+}
+
+func TestListOptionsPrint(t *testing.T) {
+	build := func(context.Context) (*ui.Table, error) { return ui.NewTable(ui.Column{Header: "Name"}), nil }
+
+	var out bytes.Buffer
+	if err := (&listOptions{}).print(context.Background(), &out, "No edges found.", build); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "No edges found.\n" {
+		t.Errorf("empty list printed %q", out.String())
+	}
+
+	if err := (&listOptions{output: "json"}).print(context.Background(), &out, "", build); err == nil {
+		t.Error("unsupported --output should fail")
+	}
+	if err := (&listOptions{watch: true}).print(context.Background(), &out, "", build); err == nil {
+		t.Error("--watch without a positive interval should fail")
+	}
+
+	// Watching stops when the context ends, after at least one table.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	out.Reset()
+	if err := (&listOptions{watch: true, interval: 10 * time.Millisecond}).print(ctx, &out, "none", build); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(out.String(), "none") < 2 {
+		t.Errorf("watch should refresh the list, got %q", out.String())
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
 	devcmd "github.com/faroshq/faros-kedge/pkg/cli/cmd/dev/cmd"
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
 )
 
 // NewRootCommand creates the root cobra command for the kedge CLI.
@@ -39,9 +40,18 @@ Remote agents "kedge" (pull) toward the hub via reverse tunnels,
 enabling secure workload deployment across distributed edges.`,
 		SilenceUsage:  true,
 		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return ui.ValidateErrorFormat()
+		},
 	}
 
 	cmd.PersistentFlags().StringVar(&kubeconfig, "kubeconfig", "", "Path to kubeconfig file")
+	// Non-interactive use: these apply to every command so scripts can rely
+	// on them regardless of which command they drive.
+	cmd.PersistentFlags().BoolVarP(&ui.AssumeYes, "yes", "y", false, "Answer yes to all confirmation prompts (required for destructive commands when stdin is not a terminal)")
+	cmd.PersistentFlags().BoolVarP(&ui.Quiet, "quiet", "q", false, "Suppress informational messages; print only results and errors")
+	cmd.PersistentFlags().BoolVar(&ui.NoColor, "no-color", false, "Disable colored output (also honoured via the NO_COLOR environment variable)")
+	cmd.PersistentFlags().StringVar(&ui.ErrorFormat, "error-format", ui.ErrorFormatText, "How to print errors: text or json")
 
 	// Add dev command
 	devCmd, err := devcmd.New(genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
@@ -54,32 +64,38 @@ enabling secure workload deployment across distributed edges.`,
 		newInitCommand(),
 		newLoginCommand(),
 		newGetTokenCommand(),
+		newAuthCommand(),
+		newAdminCommand(),
 		newAgentCommand(),
 		newEdgeCommand(),
 		newListCommand(),
 		newInstallCommand(),
 		newApplyCommand(),
 		newGetCommand(),
+		newPlacementCommand(),
+		newWorkloadCommand(),
+		newFleetCommand(),
+		newSearchCommand(),
+		newEventsCommand(),
 		newWorkspaceCommand(),
 		newUseCommand(),
 		newKubeconfigCommand(),
+		newUICommand(),
 		newVersionCommand(),
+		newBugReportCommand(),
 		newSSHCommand(),
 		newMCPCommand(),
 		devCmd,
 	)
+	addVerbAliases(cmd)
 
 	return cmd
 }
 
 // newListCommand provides a shorthand 'kedge list' → 'kedge edge list'.
 func newListCommand() *cobra.Command {
-	return &cobra.Command{
-		Use:     "list",
-		Short:   "List edges (shorthand for 'kedge edge list')",
-		Aliases: []string{"ls"},
-		RunE: func(cmd *cobra.Command, args []string) error {
-			return newEdgeListCommand().RunE(cmd, args)
-		},
-	}
+	cmd := newEdgeListCommand()
+	cmd.Short = "List edges (shorthand for 'kedge edge list')"
+	cmd.Aliases = []string{"ls"}
+	return cmd
 }
@@ -0,0 +1,186 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/faroshq/faros-kedge/pkg/apiurl"
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
+)
+
+// searchPath is the hub's search API (pkg/hub/search).
+const searchPath = "/services/search"
+
+// searchResponse / searchResult mirror the hub's search.Response and
+// search.Result.
+type searchResponse struct {
+	Total   int            `json:"total"`
+	Results []searchResult `json:"results"`
+}
+
+type searchResult struct {
+	Kind      string            `json:"kind"`
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Labels    map[string]string `json:"labels"`
+	Phase     string            `json:"phase"`
+	Connected *bool             `json:"connected"`
+	Edge      string            `json:"edge"`
+	Workload  string            `json:"workload"`
+}
+
+func newSearchCommand() *cobra.Command {
+	var (
+		opts     listOptions
+		selector string
+		kinds    []string
+		limit    int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "search [terms...]",
+		Short: "Search edges, workloads and placements in the current workspace",
+		Long: `Search the edges, workloads and placements of the current workspace by
+name, namespace, kind, label (key=value), phase, hostname, region, image or
+the edge and workload of a placement. Every term must match; names matching a
+term rank first. The hub answers from an index it keeps in memory, so a
+search does not list every object.`,
+		Example: `  kedge search store-17
+  kedge search nginx --kind placement
+  kedge search --selector region=eu --kind edge`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && selector == "" {
+				return fmt.Errorf("give search terms or --selector")
+			}
+			hub, err := newHubSession()
+			if err != nil {
+				return err
+			}
+			org, workspace := currentWorkspaceIDs(cmd.Context(), hub)
+
+			values := url.Values{}
+			if len(args) > 0 {
+				values.Set("q", strings.Join(args, " "))
+			}
+			if selector != "" {
+				values.Set("labelSelector", selector)
+			}
+			if len(kinds) > 0 {
+				values.Set("kind", strings.Join(kinds, ","))
+			}
+			if limit > 0 {
+				values.Set("limit", strconv.Itoa(limit))
+			}
+			return opts.print(cmd.Context(), os.Stdout, "No matches found.", func(ctx context.Context) (*ui.Table, error) {
+				resp, err := fetchSearch(ctx, hub, org, workspace, values)
+				if err != nil {
+					return nil, err
+				}
+				return searchTable(resp.Results), nil
+			})
+		},
+	}
+
+	opts.addFlags(cmd)
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", "Label selector the matches must satisfy (e.g. region=eu,tier!=dev)")
+	cmd.Flags().StringSliceVar(&kinds, "kind", nil, "Only search these kinds: edge, kubernetescluster, linuxserver, workload, placement (comma-separated or repeat)")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Most matches to show (default 20, at most 200)")
+	return cmd
+}
+
+// currentWorkspaceIDs returns the org and workspace UUIDs of the workspace
+// the kedge context points at, so the hub searches it rather than the
+// caller's personal org. Empty when it cannot be determined.
+func currentWorkspaceIDs(ctx context.Context, hub *hubSession) (org, workspace string) {
+	_, cluster := apiurl.SplitBaseAndCluster(hub.cluster.Server)
+	orgs, err := fetchOrgs(ctx, hub.client, hub.base)
+	if err != nil {
+		return "", ""
+	}
+	for _, o := range orgs {
+		workspaces, err := fetchWorkspaces(ctx, hub.client, hub.base, o.UUID)
+		if err != nil {
+			continue
+		}
+		for _, ws := range workspaces {
+			if ws.ClusterName == cluster {
+				return o.UUID, ws.UUID
+			}
+		}
+	}
+	return "", ""
+}
+
+// fetchSearch runs a search on the hub in the given org and workspace.
+func fetchSearch(ctx context.Context, hub *hubSession, org, workspace string, values url.Values) (*searchResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, hub.base+searchPath+"?"+values.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", hubAccept)
+	if org != "" {
+		req.Header.Set("X-Kedge-Org", org)
+		req.Header.Set("X-Kedge-Workspace", workspace)
+	}
+	resp, err := hub.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("searching: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, hubError("searching", resp, body)
+	}
+	var out searchResponse
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("decoding search results: %w", err)
+	}
+	return &out, nil
+}
+
+// searchTable lists search results in the hub's rank order.
+func searchTable(results []searchResult) *ui.Table {
+	t := ui.NewTable(
+		ui.Column{Header: "Kind"},
+		ui.Column{Header: "Namespace"},
+		ui.Column{Header: "Name"},
+		ui.Column{Header: "Phase", Status: true},
+		ui.Column{Header: "Connected", Status: true, Wide: true},
+		ui.Column{Header: "Edge", Wide: true},
+		ui.Column{Header: "Workload", Wide: true},
+		ui.Column{Header: "Labels", Wide: true},
+	)
+	for _, r := range results {
+		connected := ""
+		if r.Connected != nil {
+			connected = strconv.FormatBool(*r.Connected)
+		}
+		t.AddRow(r.Kind, r.Namespace, r.Name, r.Phase, connected, r.Edge, r.Workload, formatLabels(r.Labels))
+	}
+	return t
+}
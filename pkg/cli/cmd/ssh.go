@@ -21,11 +21,14 @@ import (
 	"crypto/tls"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 
@@ -89,22 +92,54 @@ func runSSH(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("loading kubeconfig: %w", err)
 	}
 
+	// Interactive sessions may require a recent sign-in (step-up); offer it
+	// and redial rather than failing.
+	var conn *websocket.Conn
+	err = withStepUp(cmd, config, func(config *rest.Config) error {
+		var dialErr error
+		conn, dialErr = dialEdgeSSHWithConfig(ctx, config, name, remoteCmd)
+		return dialErr
+	})
+	if err != nil {
+		return err
+	}
+	defer conn.Close() //nolint:errcheck
+
+	if remoteCmd != "" {
+		return runSSHCommandStream(ctx, conn)
+	}
+	return runSSHInteractive(ctx, conn)
+}
+
+// dialEdgeSSH opens the hub SSH WebSocket for the named server-type edge. A
+// non-empty remoteCmd is run non-interactively; the caller owns the returned
+// connection.
+func dialEdgeSSH(ctx context.Context, name, remoteCmd string) (*websocket.Conn, error) {
+	config, err := loadRestConfig()
+	if err != nil {
+		return nil, fmt.Errorf("loading kubeconfig: %w", err)
+	}
+	return dialEdgeSSHWithConfig(ctx, config, name, remoteCmd)
+}
+
+// dialEdgeSSHWithConfig is dialEdgeSSH against an already loaded config.
+func dialEdgeSSHWithConfig(ctx context.Context, config *rest.Config, name, remoteCmd string) (*websocket.Conn, error) {
 	// Fetch the Edge resource to get the proxy URL from status. The Edge type
 	// now lives in the edges-connectivity provider (edges.kedge.faros.sh), so we
 	// read it via the dynamic client and pull status.URL out of the unstructured.
 	client, err := kedgeclient.NewForConfig(config)
 	if err != nil {
-		return fmt.Errorf("creating kedge client: %w", err)
+		return nil, fmt.Errorf("creating kedge client: %w", err)
 	}
 
 	edge, err := client.Dynamic().Resource(kedgeclient.LinuxServerGVR).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		return fmt.Errorf("fetching edge %q: %w", name, err)
+		return nil, fmt.Errorf("fetching edge %q: %w", name, err)
 	}
 
 	edgeURL, _, _ := unstructured.NestedString(edge.Object, "status", "URL")
 	if edgeURL == "" {
-		return fmt.Errorf("edge %q has no proxy URL in status; is the agent running?", name)
+		return nil, fmt.Errorf("edge %q has no proxy URL in status; is the agent running?", name)
 	}
 
 	// Externalize the edge URL: status.URL may use an internal host (for kcp
@@ -112,35 +147,69 @@ func runSSH(cmd *cobra.Command, args []string) error {
 	// the kubeconfig.
 	externalURL, err := externalizeEdgeURLFromConfig(edgeURL, config)
 	if err != nil {
-		return fmt.Errorf("constructing external edge URL: %w", err)
+		return nil, fmt.Errorf("constructing external edge URL: %w", err)
 	}
 
 	wsURL, err := buildSSHWebSocketURL(config, externalURL, remoteCmd)
 	if err != nil {
-		return fmt.Errorf("building SSH endpoint URL: %w", err)
+		return nil, fmt.Errorf("building SSH endpoint URL: %w", err)
 	}
 
+	authorization, err := authorizationHeader(config)
+	if err != nil {
+		return nil, fmt.Errorf("resolving credentials: %w", err)
+	}
 	headers := http.Header{}
-	if config.BearerToken != "" {
-		headers.Set("Authorization", "Bearer "+config.BearerToken)
+	if authorization != "" {
+		headers.Set("Authorization", authorization)
 	}
 
 	dialer := &websocket.Dialer{
 		TLSClientConfig: tlsConfigFromRest(config),
 	}
 
-	conn, _, err := dialer.DialContext(ctx, wsURL, headers)
+	conn, resp, err := dialer.DialContext(ctx, wsURL, headers)
 	if err != nil {
-		return fmt.Errorf("connecting to hub SSH endpoint %s: %w", wsURL, err)
+		if stepUpErr := stepUpErrorFromResponse("ssh "+name, resp); stepUpErr != nil {
+			return nil, stepUpErr
+		}
+		return nil, fmt.Errorf("connecting to hub SSH endpoint %s: %w", wsURL, err)
 	}
-	defer conn.Close() //nolint:errcheck
+	return conn, nil
+}
 
-	if remoteCmd != "" {
-		return runSSHCommandStream(ctx, conn)
+// authorizationHeader returns the Authorization header client-go would send
+// for config. The WebSocket dialer bypasses client-go's transport, so a bare
+// BearerToken check would miss exec-plugin (OIDC) credentials.
+func authorizationHeader(config *rest.Config) (string, error) {
+	if config.BearerToken != "" {
+		return "Bearer " + config.BearerToken, nil
 	}
-	return runSSHInteractive(ctx, conn)
+	var authorization string
+	capture := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		authorization = req.Header.Get("Authorization")
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	})
+	rt, err := rest.HTTPWrappersForConfig(config, capture)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest(http.MethodGet, config.Host, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close() //nolint:errcheck
+	return authorization, nil
 }
 
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
 // buildSSHWebSocketURL constructs the WebSocket URL for the hub SSH subresource
 // using the edge's full proxy URL from status.URL.
 //
@@ -174,11 +243,32 @@ func buildSSHWebSocketURL(_ *rest.Config, edgeURL, remoteCmd string) (string, er
 	return u.String(), nil
 }
 
+// sshExitStatusPrefix starts the WebSocket close reason the hub sends when an
+// exec-mode command ends, followed by the command's exit status (see sshExec
+// in the edges provider). Hubs that predate it close without a reason.
+const sshExitStatusPrefix = "exit-status "
+
+// remoteExitError reports a remote command that exited with a non-zero status.
+type remoteExitError struct {
+	status int
+}
+
+func (e *remoteExitError) Error() string {
+	return fmt.Sprintf("remote command exited with status %d", e.status)
+}
+
 // runSSHCommandStream reads output messages from the WebSocket until the
 // connection is closed by the hub (after the remote command exits).  The
 // command itself was already conveyed to the hub via the "cmd" query
 // parameter in the WebSocket URL; there is nothing to write here.
 func runSSHCommandStream(ctx context.Context, conn *websocket.Conn) error {
+	return streamSSHCommand(ctx, conn, os.Stdout)
+}
+
+// streamSSHCommand copies the command's output to out and returns the error
+// the hub reported in its close frame: a *remoteExitError for a non-zero
+// exit status, or the hub's message when the command could not be run.
+func streamSSHCommand(ctx context.Context, conn *websocket.Conn, out io.Writer) error {
 	for {
 		select {
 		case <-ctx.Done():
@@ -188,14 +278,39 @@ func runSSHCommandStream(ctx context.Context, conn *websocket.Conn) error {
 		_, data, err := conn.ReadMessage()
 		if err != nil {
 			// Normal EOF — remote command finished.
-			return nil //nolint:nilerr
+			return sshCommandResult(err)
 		}
-		if _, err := os.Stdout.Write(data); err != nil {
+		if _, err := out.Write(data); err != nil {
 			return err
 		}
 	}
 }
 
+// sshCommandResult interprets the error that ended the output stream. A close
+// without an exit status (older hubs, or a session that dropped before the
+// command reported one, as a reboot does) counts as success.
+func sshCommandResult(err error) error {
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) {
+		return nil
+	}
+	if closeErr.Code == websocket.CloseInternalServerErr {
+		return fmt.Errorf("running remote command: %s", closeErr.Text)
+	}
+	status, ok := strings.CutPrefix(closeErr.Text, sshExitStatusPrefix)
+	if !ok {
+		return nil
+	}
+	code, convErr := strconv.Atoi(status)
+	if convErr != nil {
+		return nil
+	}
+	if code != 0 {
+		return &remoteExitError{status: code}
+	}
+	return nil
+}
+
 // runSSHInteractive bridges a raw terminal to the hub SSH WebSocket session.
 func runSSHInteractive(ctx context.Context, conn *websocket.Conn) error {
 	fd := int(os.Stdin.Fd())
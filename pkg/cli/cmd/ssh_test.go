@@ -0,0 +1,95 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// serveSSHExec fakes the hub's exec-mode SSH endpoint: it sends output and
+// then closes with closeMsg (nil drops the connection without a close frame).
+func serveSSHExec(t *testing.T, output string, closeMsg []byte) *websocket.Conn {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+		defer c.Close() //nolint:errcheck
+		_ = c.WriteMessage(websocket.BinaryMessage, []byte(output))
+		if closeMsg != nil {
+			_ = c.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(time.Second))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestStreamSSHCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		closeMsg   []byte
+		wantStatus int // non-zero expects a *remoteExitError
+		wantErr    string
+	}{
+		{name: "exit zero", closeMsg: websocket.FormatCloseMessage(websocket.CloseNormalClosure, "exit-status 0")},
+		{name: "exit non-zero", closeMsg: websocket.FormatCloseMessage(websocket.CloseNormalClosure, "exit-status 1"), wantStatus: 1},
+		{name: "command not run", closeMsg: websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "ssh: handshake failed"), wantErr: "ssh: handshake failed"},
+		{name: "no exit status", closeMsg: websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")},
+		{name: "dropped connection"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := serveSSHExec(t, "sudo: a password is required\n", tt.closeMsg)
+			var out bytes.Buffer
+			err := streamSSHCommand(context.Background(), conn, &out)
+
+			if got := out.String(); got != "sudo: a password is required\n" {
+				t.Errorf("output = %q", got)
+			}
+			var exitErr *remoteExitError
+			switch {
+			case tt.wantStatus != 0:
+				if !errors.As(err, &exitErr) || exitErr.status != tt.wantStatus {
+					t.Errorf("error = %v, want exit status %d", err, tt.wantStatus)
+				}
+			case tt.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want it to mention %q", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Errorf("error = %v, want nil", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	"github.com/faroshq/faros-kedge/pkg/apiurl"
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
+	"github.com/faroshq/faros-kedge/pkg/problem"
+)
+
+// stepUpChallenge is the RFC 9470 error code the hub and the edges provider
+// put in WWW-Authenticate when an operation needs a recent sign-in.
+const stepUpChallenge = "insufficient_user_authentication"
+
+// isStepUpRequired reports whether err is the hub or provider refusing an
+// operation until the user signs in again.
+func isStepUpRequired(err error) bool {
+	var reasonErr *ui.ReasonError
+	if errors.As(err, &reasonErr) && reasonErr.Reason == problem.ReasonStepUpRequired {
+		return true
+	}
+	return apierrors.ReasonForError(err) == metav1.StatusReason(problem.ReasonStepUpRequired)
+}
+
+// stepUpErrorFromResponse returns the step-up error for a refused handshake
+// or request, or nil when resp is not a step-up challenge.
+func stepUpErrorFromResponse(op string, resp *http.Response) error {
+	if resp == nil || resp.StatusCode != http.StatusUnauthorized ||
+		!strings.Contains(resp.Header.Get("WWW-Authenticate"), stepUpChallenge) {
+		return nil
+	}
+	return &ui.ReasonError{
+		Reason: problem.ReasonStepUpRequired,
+		Err:    fmt.Errorf("%s: this requires a recent sign-in — run: kedge login --step-up", op),
+	}
+}
+
+// withStepUp runs op with config. When the hub asks for a recent sign-in, it
+// offers to sign in again (--yes accepts) and retries op once with the fresh
+// ID token.
+func withStepUp(cmd *cobra.Command, config *rest.Config, op func(*rest.Config) error) error {
+	err := op(config)
+	if !isStepUpRequired(err) {
+		return err
+	}
+	ok, confirmErr := ui.Confirm(cmd.InOrStdin(), cmd.ErrOrStderr(), "This operation requires a recent sign-in. Sign in again now?")
+	if confirmErr != nil || !ok {
+		return err
+	}
+
+	hubURL, _ := apiurl.SplitBaseAndCluster(config.Host)
+	ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Minute)
+	defer cancel()
+	// Refresh the kubeconfig the failed request was loaded from, signing in
+	// with the identity provider its credentials came from.
+	resp, loginErr := runLogin(ctx, hubURL, execIssuerURL(config), config.Insecure, true, cliLoadingRules().GetDefaultFilename())
+	if loginErr != nil {
+		return loginErr
+	}
+	if resp.IDToken == "" {
+		return err
+	}
+
+	// client-go caches exec plugin credentials for the life of the process,
+	// so the retry carries the new token directly.
+	fresh := rest.CopyConfig(config)
+	fresh.ExecProvider = nil
+	fresh.AuthProvider = nil
+	fresh.BearerTokenFile = ""
+	fresh.BearerToken = resp.IDToken
+	if err := op(fresh); err != nil {
+		if isStepUpRequired(err) {
+			return fmt.Errorf("%w (signing in again did not help: the identity provider must report auth_time or a second factor in amr)", err)
+		}
+		return err
+	}
+	return nil
+}
+
+// execIssuerURL returns the OIDC issuer the kedge get-token exec plugin of
+// config refreshes against, or "" when config has none.
+func execIssuerURL(config *rest.Config) string {
+	if config.ExecProvider == nil {
+		return ""
+	}
+	for _, arg := range config.ExecProvider.Args {
+		if issuer, ok := strings.CutPrefix(arg, "--oidc-issuer-url="); ok {
+			return issuer
+		}
+	}
+	return ""
+}
@@ -0,0 +1,575 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
+	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+)
+
+// tuiView is one of the TUI's top-level lists.
+type tuiView int
+
+const (
+	tuiEdges tuiView = iota
+	tuiWorkloads
+	tuiPlacements
+)
+
+var tuiViewNames = []string{"Edges", "Workloads", "Placements"}
+
+// tuiViewGVRs are the resources each view lists and watches.
+var tuiViewGVRs = map[tuiView][]schema.GroupVersionResource{
+	tuiEdges:      edgeKindGVRs,
+	tuiWorkloads:  {kedgeclient.WorkloadGVR},
+	tuiPlacements: {kedgeclient.PlacementGVR},
+}
+
+const (
+	// tuiWorkloadNamespace is where the agent applies placements on an edge
+	// (pkg/agent/reconciler), and so where logs and exec look.
+	tuiWorkloadNamespace = "default"
+	// tuiPlacementLabel is stamped by the agent on everything it applies
+	// for a placement.
+	tuiPlacementLabel = "edges.kedge.faros.sh/placement"
+	// tuiRefreshInterval coalesces bursts of watch events into one redraw.
+	tuiRefreshInterval = 200 * time.Millisecond
+)
+
+var (
+	tuiTabStyle       = lipgloss.NewStyle().Faint(true)
+	tuiActiveTabStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("12")).Bold(true)
+	tuiCursorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("12")).Bold(true)
+	tuiHelpStyle      = lipgloss.NewStyle().Faint(true)
+	tuiErrorStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+)
+
+func newUICommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "ui",
+		Short: "Browse edges, workloads and placements in an interactive terminal UI",
+		Long: `Browse edges, workloads and placements in an interactive terminal UI.
+
+The lists follow the hub live. Enter drills down: an edge shows its
+description, a workload its placements, a placement its status. Esc goes back.
+
+Shortcuts on the selected row:
+  s  SSH into a server-type edge ('kedge ssh')
+  l  follow the logs of a placement (needs kubectl)
+  x  open a shell in a placement's workload (needs kubectl)`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUI()
+		},
+	}
+}
+
+func runUI() error {
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return fmt.Errorf("kedge ui needs an interactive terminal")
+	}
+	dyn, err := loadDynamicClient()
+	if err != nil {
+		return fmt.Errorf("not logged in — run: kedge login --hub-url <hub-url>\n(original error: %w)", err)
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locating the kedge binary: %w", err)
+	}
+
+	// Informer retries would otherwise print over the UI.
+	klog.LogToStderr(false)
+	klog.SetOutput(io.Discard)
+
+	factory := kedgeclient.NewInformerFactory(dyn, 0)
+	store := &tuiInformerStore{listers: map[tuiView][]cache.GenericLister{}}
+	changed := make(chan struct{}, 1)
+	notify := func(interface{}) {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    notify,
+		UpdateFunc: func(_, obj interface{}) { notify(obj) },
+		DeleteFunc: notify,
+	}
+	for view, gvrs := range tuiViewGVRs {
+		for _, gvr := range gvrs {
+			inf := factory.ForResource(gvr)
+			if _, err := inf.Informer().AddEventHandler(handler); err != nil {
+				return fmt.Errorf("watching %s: %w", gvr.Resource, err)
+			}
+			store.listers[view] = append(store.listers[view], inf.Lister())
+		}
+	}
+
+	prog := tea.NewProgram(newTUIModel(store, dyn, self), tea.WithAltScreen())
+	stop := make(chan struct{})
+	defer close(stop)
+	factory.Start(stop)
+	go func() {
+		factory.WaitForCacheSync(stop)
+		prog.Send(tuiSyncedMsg{})
+		for {
+			select {
+			case <-stop:
+				return
+			case <-changed:
+				prog.Send(tuiRefreshMsg{})
+				time.Sleep(tuiRefreshInterval)
+			}
+		}
+	}()
+
+	_, err = prog.Run()
+	return err
+}
+
+// tuiStore is what the TUI lists from: informer caches when running, fixed
+// lists in tests.
+type tuiStore interface {
+	list(view tuiView) []unstructured.Unstructured
+}
+
+type tuiInformerStore struct {
+	listers map[tuiView][]cache.GenericLister
+}
+
+func (s *tuiInformerStore) list(view tuiView) []unstructured.Unstructured {
+	var out []unstructured.Unstructured
+	for _, l := range s.listers[view] {
+		objs, err := l.List(labels.Everything())
+		if err != nil {
+			continue
+		}
+		for _, o := range objs {
+			if u, ok := o.(*unstructured.Unstructured); ok {
+				out = append(out, *u.DeepCopy())
+			}
+		}
+	}
+	return out
+}
+
+// tuiScreen is one level of drill-down: a list of one view, optionally
+// filtered, or the text description of one object.
+type tuiScreen struct {
+	view  tuiView
+	title string
+	// match filters the list; nil lists everything.
+	match func(unstructured.Unstructured) bool
+	// detail marks a description screen; body is its text.
+	detail bool
+	body   string
+	// cursor is the selected row of a list, offset the first visible line.
+	cursor int
+	offset int
+}
+
+type (
+	// tuiSyncedMsg reports that the informer caches have synced.
+	tuiSyncedMsg struct{}
+	// tuiRefreshMsg reports that a watched resource changed.
+	tuiRefreshMsg struct{}
+	// tuiDetailMsg carries an edge description loaded in the background.
+	tuiDetailMsg struct {
+		title string
+		body  string
+	}
+	// tuiExecMsg carries a prepared shortcut command to hand the terminal to.
+	tuiExecMsg struct {
+		cmd     *exec.Cmd
+		cleanup func()
+	}
+	// tuiStatusMsg sets the status line.
+	tuiStatusMsg string
+)
+
+type tuiModel struct {
+	store tuiStore
+	dyn   dynamic.Interface
+	// self is the kedge executable, run for the ssh shortcut.
+	self string
+
+	stack  []tuiScreen
+	synced bool
+	status string
+	width  int
+	height int
+}
+
+func newTUIModel(store tuiStore, dyn dynamic.Interface, self string) tuiModel {
+	return tuiModel{store: store, dyn: dyn, self: self, stack: []tuiScreen{{view: tuiEdges}}}
+}
+
+func (m tuiModel) Init() tea.Cmd { return nil }
+
+func (m *tuiModel) top() *tuiScreen { return &m.stack[len(m.stack)-1] }
+
+// items returns the rows of a list screen in display order: by namespace,
+// then name, as the list tables sort them.
+func (m tuiModel) items(s *tuiScreen) []unstructured.Unstructured {
+	var out []unstructured.Unstructured
+	for _, u := range m.store.list(s.view) {
+		if s.match == nil || s.match(u) {
+			out = append(out, u)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].GetNamespace() != out[j].GetNamespace() {
+			return out[i].GetNamespace() < out[j].GetNamespace()
+		}
+		return out[i].GetName() < out[j].GetName()
+	})
+	return out
+}
+
+// selected returns the object under the cursor of a list screen.
+func (m tuiModel) selected() (unstructured.Unstructured, bool) {
+	s := m.top()
+	if s.detail {
+		return unstructured.Unstructured{}, false
+	}
+	items := m.items(s)
+	if s.cursor < 0 || s.cursor >= len(items) {
+		return unstructured.Unstructured{}, false
+	}
+	return items[s.cursor], true
+}
+
+// visibleRows is how many list rows or description lines fit on screen.
+func (m tuiModel) visibleRows() int {
+	if m.height == 0 {
+		return 20
+	}
+	return max(m.height-7, 1)
+}
+
+func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case tuiSyncedMsg:
+		m.synced = true
+	case tuiRefreshMsg:
+		// Rows come from the caches on every render; just keep the
+		// cursor on the list after deletions.
+		if s := m.top(); !s.detail {
+			s.cursor = min(s.cursor, max(len(m.items(s))-1, 0))
+		}
+	case tuiDetailMsg:
+		if s := m.top(); s.detail && s.title == msg.title {
+			s.body = msg.body
+		}
+	case tuiExecMsg:
+		return m, tea.ExecProcess(msg.cmd, func(err error) tea.Msg {
+			if msg.cleanup != nil {
+				msg.cleanup()
+			}
+			if err != nil {
+				return tuiStatusMsg(fmt.Sprintf("%s: %v", msg.cmd.Args[0], err))
+			}
+			return tuiStatusMsg("")
+		})
+	case tuiStatusMsg:
+		m.status = string(msg)
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+	return m, nil
+}
+
+func (m tuiModel) handleKey(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := m.top()
+	m.status = ""
+	switch key.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "esc", "backspace":
+		if len(m.stack) > 1 {
+			m.stack = m.stack[:len(m.stack)-1]
+		}
+	case "tab", "shift+tab", "1", "2", "3":
+		view := m.stack[0].view
+		switch key.String() {
+		case "tab":
+			view = (view + 1) % tuiView(len(tuiViewNames))
+		case "shift+tab":
+			view = (view + tuiView(len(tuiViewNames)) - 1) % tuiView(len(tuiViewNames))
+		default:
+			view = tuiView(key.String()[0] - '1')
+		}
+		m.stack = []tuiScreen{{view: view}}
+	case "up", "k":
+		m.move(-1)
+	case "down", "j":
+		m.move(1)
+	case "pgup":
+		m.move(-m.visibleRows())
+	case "pgdown":
+		m.move(m.visibleRows())
+	case "home", "g":
+		m.move(-1 << 30)
+	case "end", "G":
+		m.move(1 << 30)
+	case "enter":
+		if s.detail {
+			break
+		}
+		return m.drillDown()
+	case "s":
+		return m.sshShortcut()
+	case "l", "x":
+		return m.placementShortcut(key.String() == "l")
+	}
+	return m, nil
+}
+
+// move shifts the cursor of a list, or scrolls a description, by delta,
+// keeping the cursor on screen.
+func (m *tuiModel) move(delta int) {
+	s := m.top()
+	rows := m.visibleRows()
+	if s.detail {
+		lines := strings.Count(s.body, "\n")
+		s.offset = min(max(s.offset+delta, 0), max(lines-rows, 0))
+		return
+	}
+	n := len(m.items(s))
+	s.cursor = min(max(s.cursor+delta, 0), max(n-1, 0))
+	if s.cursor < s.offset {
+		s.offset = s.cursor
+	}
+	if s.cursor >= s.offset+rows {
+		s.offset = s.cursor - rows + 1
+	}
+}
+
+// drillDown opens the selected row: an edge's description, a workload's
+// placements or a placement's status.
+func (m tuiModel) drillDown() (tea.Model, tea.Cmd) {
+	obj, ok := m.selected()
+	if !ok {
+		return m, nil
+	}
+	switch m.top().view {
+	case tuiEdges:
+		title := "Edge " + obj.GetName()
+		m.stack = append(m.stack, tuiScreen{title: title, detail: true, body: "Loading…\n"})
+		name, dyn := obj.GetName(), m.dyn
+		return m, func() tea.Msg {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			d, err := gatherEdgeDetail(ctx, dyn, name)
+			if err != nil {
+				return tuiDetailMsg{title: title, body: err.Error() + "\n"}
+			}
+			var b bytes.Buffer
+			describeEdge(&b, d, time.Now())
+			return tuiDetailMsg{title: title, body: b.String()}
+		}
+	case tuiWorkloads:
+		ns, name := obj.GetNamespace(), obj.GetName()
+		m.stack = append(m.stack, tuiScreen{
+			view:  tuiPlacements,
+			title: "Placements of workload " + ns + "/" + name,
+			match: func(p unstructured.Unstructured) bool {
+				wns := getNestedString(p, "spec", "workloadRef", "namespace")
+				if wns == "" {
+					wns = p.GetNamespace()
+				}
+				return wns == ns && getNestedString(p, "spec", "workloadRef", "name") == name
+			},
+		})
+	case tuiPlacements:
+		var b bytes.Buffer
+		describePlacement(&b, obj)
+		m.stack = append(m.stack, tuiScreen{title: "Placement " + obj.GetNamespace() + "/" + obj.GetName(), detail: true, body: b.String()})
+	}
+	return m, nil
+}
+
+// sshShortcut hands the terminal to 'kedge ssh' for the selected server edge.
+func (m tuiModel) sshShortcut() (tea.Model, tea.Cmd) {
+	obj, ok := m.selected()
+	if !ok || m.top().view != tuiEdges {
+		return m, nil
+	}
+	if obj.GetKind() != "LinuxServer" {
+		m.status = "ssh is only available on server-type edges"
+		return m, nil
+	}
+	return m, func() tea.Msg { return tuiExecMsg{cmd: exec.Command(m.self, "ssh", obj.GetName())} }
+}
+
+// placementShortcut hands the terminal to kubectl, following the selected
+// placement's logs or opening a shell in its workload, through a kubeconfig
+// for the placement's edge.
+func (m tuiModel) placementShortcut(logs bool) (tea.Model, tea.Cmd) {
+	obj, ok := m.selected()
+	if !ok || m.top().view != tuiPlacements {
+		return m, nil
+	}
+	edge := getNestedString(obj, "spec", "edgeName")
+	workload := getNestedString(obj, "spec", "workloadRef", "name")
+	dyn := m.dyn
+	return m, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		kubeconfig, err := edgeKubeconfig(ctx, dyn, edge)
+		if err != nil {
+			return tuiStatusMsg(err.Error())
+		}
+		f, err := os.CreateTemp("", "kedge-ui-*.kubeconfig")
+		if err != nil {
+			return tuiStatusMsg(err.Error())
+		}
+		cleanup := func() { _ = os.Remove(f.Name()) }
+		if _, err := f.Write(kubeconfig); err != nil {
+			_ = f.Close()
+			cleanup()
+			return tuiStatusMsg(err.Error())
+		}
+		_ = f.Close()
+		return tuiExecMsg{cmd: exec.Command("kubectl", tuiKubectlArgs(f.Name(), obj.GetName(), workload, logs)...), cleanup: cleanup}
+	}
+}
+
+// tuiKubectlArgs are the kubectl arguments for the logs (logs) or shell
+// shortcut on a placement of workload.
+func tuiKubectlArgs(kubeconfig, placement, workload string, logs bool) []string {
+	args := []string{"--kubeconfig", kubeconfig, "--namespace", tuiWorkloadNamespace}
+	if logs {
+		return append(args, "logs", "--selector", tuiPlacementLabel+"="+placement,
+			"--all-containers", "--prefix", "--tail", "100", "--follow")
+	}
+	return append(args, "exec", "-it", "deployment/"+workload, "--", "sh")
+}
+
+func (m tuiModel) View() string {
+	var b strings.Builder
+	for i, name := range tuiViewNames {
+		label := fmt.Sprintf(" %d %s ", i+1, name)
+		if tuiView(i) == m.stack[0].view {
+			b.WriteString(tuiActiveTabStyle.Render("[" + label + "]"))
+		} else {
+			b.WriteString(tuiTabStyle.Render(" " + label + " "))
+		}
+	}
+	if len(m.stack) > 1 {
+		b.WriteString(tuiHelpStyle.Render("  › " + m.top().title))
+	}
+	b.WriteString("\n\n")
+
+	s := m.top()
+	rows := m.visibleRows()
+	var lines []string
+	switch {
+	case s.detail:
+		lines = strings.Split(strings.TrimSuffix(s.body, "\n"), "\n")
+		lines = lines[min(s.offset, len(lines)):]
+		lines = lines[:min(rows+1, len(lines))]
+	case !m.synced:
+		lines = []string{"Loading…"}
+	default:
+		lines = m.listLines(s, rows)
+	}
+	for _, l := range lines {
+		if m.width > 0 {
+			l = lipgloss.NewStyle().MaxWidth(m.width).Render(l)
+		}
+		b.WriteString(l + "\n")
+	}
+
+	b.WriteString("\n")
+	if m.status != "" {
+		b.WriteString(tuiErrorStyle.Render(m.status) + "\n")
+	}
+	b.WriteString(tuiHelpStyle.Render(m.help()) + "\n")
+	return b.String()
+}
+
+// listLines renders the visible part of a list screen with the selected row
+// marked.
+func (m tuiModel) listLines(s *tuiScreen, rows int) []string {
+	items := m.items(s)
+	if len(items) == 0 {
+		return []string{"No " + strings.ToLower(tuiViewNames[s.view]) + " found."}
+	}
+	var t *ui.Table
+	switch s.view {
+	case tuiEdges:
+		t = edgeTable(items, time.Now())
+	case tuiWorkloads:
+		t = workloadTable(items)
+	case tuiPlacements:
+		t = placementTable(items)
+	}
+	table := t.Lines(false, true)
+	lines := []string{"  " + table[0]}
+	end := min(s.offset+rows, len(items))
+	for i := s.offset; i < end; i++ {
+		if i == s.cursor {
+			lines = append(lines, tuiCursorStyle.Render("›")+" "+table[i+1])
+		} else {
+			lines = append(lines, "  "+table[i+1])
+		}
+	}
+	return lines
+}
+
+// help lists the keys that do something on the current screen.
+func (m tuiModel) help() string {
+	keys := []string{"↑/↓ move"}
+	s := m.top()
+	if !s.detail {
+		keys = append(keys, "enter open")
+		switch s.view {
+		case tuiEdges:
+			keys = append(keys, "s ssh")
+		case tuiPlacements:
+			keys = append(keys, "l logs", "x shell")
+		}
+	}
+	if len(m.stack) > 1 {
+		keys = append(keys, "esc back")
+	}
+	return strings.Join(append(keys, "tab switch", "q quit"), " · ")
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+type fakeTUIStore map[tuiView][]unstructured.Unstructured
+
+func (s fakeTUIStore) list(view tuiView) []unstructured.Unstructured { return s[view] }
+
+func tuiObject(kind, ns, name string, spec map[string]interface{}) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"kind":     kind,
+		"metadata": map[string]interface{}{"name": name, "namespace": ns},
+		"spec":     spec,
+	}}
+}
+
+func tuiPress(t *testing.T, m tuiModel, keys ...string) tuiModel {
+	t.Helper()
+	for _, k := range keys {
+		var msg tea.KeyMsg
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "tab":
+			msg = tea.KeyMsg{Type: tea.KeyTab}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		next, _ := m.Update(msg)
+		m = next.(tuiModel)
+	}
+	return m
+}
+
+func TestTUINavigation(t *testing.T) {
+	placement := func(name, workload, edge string) unstructured.Unstructured {
+		return tuiObject("Placement", "tenant", name, map[string]interface{}{
+			"edgeName":    edge,
+			"workloadRef": map[string]interface{}{"name": workload},
+		})
+	}
+	store := fakeTUIStore{
+		tuiEdges: {
+			tuiObject("LinuxServer", "", "web", nil),
+			tuiObject("KubernetesCluster", "", "east", nil),
+		},
+		tuiWorkloads: {
+			tuiObject("Workload", "tenant", "nginx", nil),
+			tuiObject("Workload", "tenant", "api", nil),
+		},
+		tuiPlacements: {
+			placement("nginx-east", "nginx", "east"),
+			placement("api-east", "api", "east"),
+			placement("nginx-west", "nginx", "west"),
+		},
+	}
+	m := newTUIModel(store, nil, "kedge")
+
+	if view := m.View(); !strings.Contains(view, "Loading") {
+		t.Errorf("view before sync should be loading:\n%s", view)
+	}
+	next, _ := m.Update(tuiSyncedMsg{})
+	m = next.(tuiModel)
+
+	// Rows sort by name; the cursor starts on the first.
+	if obj, _ := m.selected(); obj.GetName() != "east" {
+		t.Errorf("first edge = %q, want east", obj.GetName())
+	}
+	m = tuiPress(t, m, "s")
+	if !strings.Contains(m.status, "server-type") {
+		t.Errorf("ssh on a cluster edge: status = %q", m.status)
+	}
+
+	// A workload drills down to its own placements only.
+	m = tuiPress(t, m, "tab", "down", "enter")
+	if len(m.stack) != 2 || m.top().view != tuiPlacements {
+		t.Fatalf("stack = %+v", m.stack)
+	}
+	var names []string
+	for _, p := range m.items(m.top()) {
+		names = append(names, p.GetName())
+	}
+	if strings.Join(names, ",") != "nginx-east,nginx-west" {
+		t.Errorf("placements of nginx = %v", names)
+	}
+	if view := m.View(); strings.Contains(view, "api-east") || !strings.Contains(view, "Placements of workload tenant/nginx") {
+		t.Errorf("filtered view:\n%s", view)
+	}
+
+	// A placement drills down to its description; esc walks back.
+	m = tuiPress(t, m, "enter")
+	if !m.top().detail || !strings.Contains(m.top().body, "nginx-east") {
+		t.Errorf("placement detail = %+v", m.top())
+	}
+	m = tuiPress(t, m, "esc", "esc")
+	if len(m.stack) != 1 || m.top().view != tuiWorkloads {
+		t.Errorf("after esc: stack = %+v", m.stack)
+	}
+
+	m = tuiPress(t, m, "3")
+	if m.top().view != tuiPlacements {
+		t.Errorf("view = %d, want placements", m.top().view)
+	}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}); cmd == nil {
+		t.Error("q should quit")
+	}
+}
+
+func TestTUIKubectlArgs(t *testing.T) {
+	logs := strings.Join(tuiKubectlArgs("/tmp/kc", "nginx-east", "nginx", true), " ")
+	if want := "--kubeconfig /tmp/kc --namespace default logs --selector edges.kedge.faros.sh/placement=nginx-east"; !strings.HasPrefix(logs, want) {
+		t.Errorf("logs args = %q", logs)
+	}
+	exec := strings.Join(tuiKubectlArgs("/tmp/kc", "nginx-east", "nginx", false), " ")
+	if exec != "--kubeconfig /tmp/kc --namespace default exec -it deployment/nginx -- sh" {
+		t.Errorf("exec args = %q", exec)
+	}
+}
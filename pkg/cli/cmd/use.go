@@ -90,43 +90,10 @@ skip the picker, e.g. for scripts:
 }
 
 func runUse(ctx context.Context, orgFlag, wsFlag string) error {
-	// Load the kubeconfig and locate the kedge context to retarget.
-	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
-	if kubeconfig != "" {
-		loadingRules.ExplicitPath = kubeconfig
-	}
-	raw, err := loadingRules.GetStartingConfig()
-	if err != nil {
-		return fmt.Errorf("loading kubeconfig: %w", err)
-	}
-	ctxName, kctx, err := resolveKedgeContext(raw)
+	hub, err := newHubSession()
 	if err != nil {
 		return err
 	}
-	cluster := raw.Clusters[kctx.Cluster]
-	if cluster == nil {
-		return fmt.Errorf("kubeconfig context %q references missing cluster %q", ctxName, kctx.Cluster)
-	}
-	base, _ := apiurl.SplitBaseAndCluster(cluster.Server)
-
-	// Build an authenticated HTTP client from the context. rest.TransportFor
-	// wires up the exec OIDC credential plugin (or static token) and the TLS
-	// settings, so REST calls carry the same identity kubectl uses.
-	clientConfig := clientcmd.NewNonInteractiveClientConfig(*raw, ctxName, &clientcmd.ConfigOverrides{}, loadingRules)
-	restCfg, err := clientConfig.ClientConfig()
-	if err != nil {
-		return fmt.Errorf("building client config: %w", err)
-	}
-	if globalInsecureTLS {
-		restCfg.Insecure = true
-		restCfg.CAData = nil
-		restCfg.CAFile = ""
-	}
-	transport, err := rest.TransportFor(restCfg)
-	if err != nil {
-		return fmt.Errorf("building HTTP transport: %w", err)
-	}
-	httpClient := &http.Client{Transport: transport, Timeout: 30 * time.Second}
 
 	// Interactive selection needs a TTY; bail early with actionable advice
 	// when one isn't available and a flag is missing.
@@ -135,7 +102,7 @@ func runUse(ctx context.Context, orgFlag, wsFlag string) error {
 	}
 
 	// 1. Pick the organization.
-	orgs, err := fetchOrgs(ctx, httpClient, base)
+	orgs, err := fetchOrgs(ctx, hub.client, hub.base)
 	if err != nil {
 		return err
 	}
@@ -148,7 +115,7 @@ func runUse(ctx context.Context, orgFlag, wsFlag string) error {
 	}
 
 	// 2. Pick the workspace within it.
-	workspaces, err := fetchWorkspaces(ctx, httpClient, base, org.UUID)
+	workspaces, err := fetchWorkspaces(ctx, hub.client, hub.base, org.UUID)
 	if err != nil {
 		return err
 	}
@@ -164,26 +131,83 @@ func runUse(ctx context.Context, orgFlag, wsFlag string) error {
 	}
 
 	// 3. Retarget the kedge cluster server URL and persist.
-	newServer := apiurl.HubServerURL(base, ws.ClusterName)
-	if cluster.Server == newServer {
+	newServer := apiurl.HubServerURL(hub.base, ws.ClusterName)
+	if hub.cluster.Server == newServer {
 		fmt.Printf("Already using organization %q / workspace %q\n", org.DisplayName, displayLabel(ws.DisplayName, ws.UUID))
 		return nil
 	}
-	cluster.Server = newServer
+	hub.cluster.Server = newServer
 
-	destPath := loadingRules.GetDefaultFilename()
+	destPath := hub.loadingRules.GetDefaultFilename()
 	if kubeconfig != "" {
 		destPath = kubeconfig
 	}
-	if err := clientcmd.WriteToFile(*raw, destPath); err != nil {
+	if err := clientcmd.WriteToFile(*hub.raw, destPath); err != nil {
 		return fmt.Errorf("writing kubeconfig to %s: %w", destPath, err)
 	}
 
 	fmt.Printf("Switched to organization %q / workspace %q\n", org.DisplayName, displayLabel(ws.DisplayName, ws.UUID))
-	fmt.Printf("Context %q now points at %s\n", ctxName, newServer)
+	fmt.Printf("Context %q now points at %s\n", hub.ctxName, newServer)
 	return nil
 }
 
+// hubSession is the kedge kubeconfig context resolved for calls to the hub
+// REST API.
+type hubSession struct {
+	loadingRules *clientcmd.ClientConfigLoadingRules
+	raw          *clientcmdapi.Config
+	ctxName      string
+	cluster      *clientcmdapi.Cluster
+	// base is the hub URL without the /clusters/<name> suffix.
+	base   string
+	client *http.Client
+}
+
+// newHubSession loads the kubeconfig, locates the kedge context and builds
+// an HTTP client carrying its credentials.
+func newHubSession() (*hubSession, error) {
+	loadingRules := cliLoadingRules()
+	raw, err := loadingRules.GetStartingConfig()
+	if err != nil {
+		return nil, fmt.Errorf("loading kubeconfig: %w", err)
+	}
+	ctxName, kctx, err := resolveKedgeContext(raw)
+	if err != nil {
+		return nil, err
+	}
+	cluster := raw.Clusters[kctx.Cluster]
+	if cluster == nil {
+		return nil, fmt.Errorf("kubeconfig context %q references missing cluster %q", ctxName, kctx.Cluster)
+	}
+	base, _ := apiurl.SplitBaseAndCluster(cluster.Server)
+
+	// Build an authenticated HTTP client from the context. rest.TransportFor
+	// wires up the exec OIDC credential plugin (or static token) and the TLS
+	// settings, so REST calls carry the same identity kubectl uses.
+	clientConfig := clientcmd.NewNonInteractiveClientConfig(*raw, ctxName, &clientcmd.ConfigOverrides{}, loadingRules)
+	restCfg, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("building client config: %w", err)
+	}
+	if globalInsecureTLS {
+		restCfg.Insecure = true
+		restCfg.CAData = nil
+		restCfg.CAFile = ""
+	}
+	transport, err := rest.TransportFor(restCfg)
+	if err != nil {
+		return nil, fmt.Errorf("building HTTP transport: %w", err)
+	}
+	return &hubSession{
+		loadingRules: loadingRules,
+		raw:          raw,
+		ctxName:      ctxName,
+		cluster:      cluster,
+		base:         base,
+		client:       &http.Client{Transport: transport, Timeout: 30 * time.Second},
+	}, nil
+}
+
 // resolveKedgeContext returns the context to retarget: the "kedge" context if
 // present (what `kedge login` writes), otherwise the current-context.
 func resolveKedgeContext(raw *clientcmdapi.Config) (string, *clientcmdapi.Context, error) {
@@ -232,7 +256,7 @@ func doGetJSON(ctx context.Context, c *http.Client, url, orgHeader string, out a
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept", hubAccept)
 	if orgHeader != "" {
 		req.Header.Set("X-Kedge-Org", orgHeader)
 	}
@@ -243,7 +267,7 @@ func doGetJSON(ctx context.Context, c *http.Client, url, orgHeader string, out a
 	defer resp.Body.Close() //nolint:errcheck
 	body, _ := io.ReadAll(resp.Body)
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("server returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+		return hubError("GET "+req.URL.Path, resp, body)
 	}
 	if err := json.Unmarshal(body, out); err != nil {
 		return fmt.Errorf("decoding response: %w", err)
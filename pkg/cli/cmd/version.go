@@ -17,24 +17,155 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"os"
 	"runtime"
+	"sort"
+	"time"
 
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
 
+	"github.com/faroshq/faros-kedge/pkg/apiurl"
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
 	pkgversion "github.com/faroshq/faros-kedge/pkg/version"
 )
 
+// reasonVersionSkew is the --error-format=json reason of a failed
+// "kedge version --check".
+const reasonVersionSkew = "VersionSkew"
+
 func newVersionCommand() *cobra.Command {
-	return &cobra.Command{
+	var check bool
+
+	cmd := &cobra.Command{
 		Use:   "version",
 		Short: "Print version information",
-		Run: func(cmd *cobra.Command, args []string) {
+		Long: `Print version information for this CLI.
+
+With --check, also query the hub's version and the agent versions reported
+by the edges in the current workspace, and rate each against the skew policy:
+
+  CLI    within ±1 release of the hub
+  agent  up to 2 releases behind the hub, never ahead of it
+
+A release is a minor version (a patch version while on v0.0.z). The command
+exits non-zero when any combination is unsupported.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
 			fmt.Printf("kedge version %s\n", pkgversion.Version)
 			fmt.Printf("  git commit: %s\n", pkgversion.GitCommit)
 			fmt.Printf("  build date: %s\n", pkgversion.BuildDate)
 			fmt.Printf("  go version: %s\n", runtime.Version())
 			fmt.Printf("  platform:   %s/%s\n", runtime.GOOS, runtime.GOARCH)
+			if !check {
+				return nil
+			}
+			fmt.Println()
+			return checkVersionSkew(context.Background(), os.Stdout)
 		},
 	}
+
+	cmd.Flags().BoolVar(&check, "check", false, "Compare CLI, hub and agent versions against the supported skew")
+	return cmd
+}
+
+// checkVersionSkew prints the CLI/hub/agent skew matrix and returns an
+// error if any combination is unsupported.
+func checkVersionSkew(ctx context.Context, out io.Writer) error {
+	config, err := loadRestConfig()
+	if err != nil {
+		return fmt.Errorf("loading kubeconfig: %w", err)
+	}
+	hubURL, _ := apiurl.SplitBaseAndCluster(config.Host)
+	hubVersion, err := fetchHubVersion(ctx, config, hubURL)
+	if err != nil {
+		return err
+	}
+
+	unsupported := 0
+	tw := newTabWriter(out)
+	printRow(tw, "COMPONENT", "NAME", "VERSION", "SKEW")
+	row := func(component, name, version string, skew pkgversion.Skew) {
+		status := string(skew.Status)
+		if skew.Reason != "" {
+			status += ": " + skew.Reason
+		}
+		if skew.Status == pkgversion.SkewUnsupported {
+			unsupported++
+			status = ui.Colorize(out, "31", status)
+		}
+		printRow(tw, component, name, formatStringOrDash(version), status)
+	}
+	row("hub", hubURL, hubVersion, pkgversion.Skew{Status: pkgversion.SkewSupported, Reason: "reference"})
+	row("cli", "-", pkgversion.Get(), pkgversion.CheckCLISkew(pkgversion.Get(), hubVersion))
+
+	edges, err := listEdgesForVersionCheck(ctx, config)
+	if err != nil {
+		_ = tw.Flush()
+		return err
+	}
+	for _, edge := range edges {
+		agentVersion := getNestedString(edge, "status", "agentVersion")
+		row("agent", edge.GetName(), agentVersion, pkgversion.CheckAgentSkew(agentVersion, hubVersion))
+	}
+	_ = tw.Flush()
+
+	if unsupported > 0 {
+		return &ui.ReasonError{
+			Reason: reasonVersionSkew,
+			Err:    fmt.Errorf("%d unsupported version combination(s); see the SKEW column", unsupported),
+		}
+	}
+	return nil
+}
+
+// fetchHubVersion reads the hub's build version from its /version endpoint.
+func fetchHubVersion(ctx context.Context, config *rest.Config, hubURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, hubURL+apiurl.PathVersion, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", hubAccept)
+	transport, err := rest.TransportFor(config)
+	if err != nil {
+		return "", fmt.Errorf("building HTTP transport: %w", err)
+	}
+	resp, err := (&http.Client{Transport: transport, Timeout: 30 * time.Second}).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("querying hub version: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode != http.StatusOK {
+		return "", hubError("querying hub version", resp, body)
+	}
+	var v struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return "", fmt.Errorf("decoding hub version: %w", err)
+	}
+	return v.Version, nil
+}
+
+// listEdgesForVersionCheck lists the current workspace's edges sorted by
+// name.
+func listEdgesForVersionCheck(ctx context.Context, config *rest.Config) ([]unstructured.Unstructured, error) {
+	dynClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("creating dynamic client: %w", err)
+	}
+	items, err := listAllEdges(ctx, dynClient)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].GetName() < items[j].GetName() })
+	return items, nil
 }
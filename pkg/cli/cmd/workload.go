@@ -0,0 +1,387 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+
+	"github.com/faroshq/faros-kedge/pkg/apiurl"
+	"github.com/faroshq/faros-kedge/pkg/cli/ui"
+	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+)
+
+func newWorkloadCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "vw",
+		Aliases: mustResource("workloads").aliases("vw"),
+		Short:   "Manage Workloads",
+		Long: `Manage the Workloads of the current workspace. To create or edit a
+Workload use "kedge apply"; to list them use "kedge vw ls".`,
+	}
+
+	cmd.AddCommand(
+		newWorkloadListCommand(),
+		newWorkloadGetCommand(),
+		newWorkloadScaleCommand(),
+		newWorkloadPreviewCommand(),
+	)
+	return cmd
+}
+
+func newWorkloadListCommand() *cobra.Command {
+	var opts listOptions
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List Workloads",
+		Example: `  kedge vw ls
+  kedge vw ls -o wide --watch`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dynClient, err := loadDynamicClient()
+			if err != nil {
+				return fmt.Errorf("not logged in — run: kedge login --hub-url <hub-url>\n(original error: %w)", err)
+			}
+			return opts.print(cmd.Context(), os.Stdout, "No workloads found.", func(ctx context.Context) (*ui.Table, error) {
+				list, err := dynClient.Resource(kedgeclient.WorkloadGVR).List(ctx, metav1.ListOptions{})
+				if err != nil {
+					return nil, fmt.Errorf("listing workloads: %w", err)
+				}
+				return workloadTable(list.Items), nil
+			})
+		},
+	}
+	opts.addFlags(cmd)
+	return cmd
+}
+
+func newWorkloadGetCommand() *cobra.Command {
+	var (
+		namespace string
+		opts      listOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "get <name>...",
+		Short: "Show Workloads by name",
+		Example: `  kedge vw get nginx
+  kedge vw get nginx api -n shop -o wide`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dynClient, err := loadDynamicClient()
+			if err != nil {
+				return fmt.Errorf("not logged in — run: kedge login --hub-url <hub-url>\n(original error: %w)", err)
+			}
+			client := dynClient.Resource(kedgeclient.WorkloadGVR).Namespace(namespace)
+			return opts.print(cmd.Context(), os.Stdout, "No workloads found.", func(ctx context.Context) (*ui.Table, error) {
+				items := make([]unstructured.Unstructured, 0, len(args))
+				for _, name := range args {
+					w, err := client.Get(ctx, name, metav1.GetOptions{})
+					if err != nil {
+						return nil, fmt.Errorf("getting workload %s/%s: %w", namespace, name, err)
+					}
+					items = append(items, *w)
+				}
+				return workloadTable(items), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace of the Workloads")
+	opts.addFlags(cmd)
+	return cmd
+}
+
+func newWorkloadScaleCommand() *cobra.Command {
+	var (
+		namespace       string
+		replicas        int32
+		currentReplicas int32
+	)
+
+	cmd := &cobra.Command{
+		Use:   "scale <name>...",
+		Short: "Set the number of replicas of Workloads",
+		Long: `Set the number of replicas of one or more Workloads through their scale
+subresource, without editing the Workload. The scheduler passes the new count
+to the Workload's Placements and each edge's agent scales its Deployment.
+
+With --current-replicas the Workload is only scaled when it has that many
+replicas now, so concurrent changes are not overwritten.`,
+		Example: `  kedge vw scale nginx --replicas 3
+  kedge vw scale nginx api --replicas 0 -n shop
+  kedge vw scale nginx --current-replicas 3 --replicas 5`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if replicas < 0 {
+				return fmt.Errorf("--replicas must not be negative")
+			}
+			dynClient, err := loadDynamicClient()
+			if err != nil {
+				return fmt.Errorf("not logged in — run: kedge login --hub-url <hub-url>\n(original error: %w)", err)
+			}
+			client := dynClient.Resource(kedgeclient.WorkloadGVR).Namespace(namespace)
+			for _, name := range args {
+				if err := scaleWorkload(cmd.Context(), client, name, replicas, currentReplicas); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Workload/%s scaled to %d\n", name, replicas)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace of the Workloads")
+	cmd.Flags().Int32Var(&replicas, "replicas", 0, "The new number of replicas")
+	cmd.Flags().Int32Var(&currentReplicas, "current-replicas", -1, "Only scale Workloads that have this many replicas now (-1 skips the check)")
+	_ = cmd.MarkFlagRequired("replicas")
+	return cmd
+}
+
+// scaleWorkload sets the replicas of a Workload through its scale
+// subresource. When current is not negative, the Workload must have that many
+// replicas; the update carries the resourceVersion read, so a change made in
+// between fails it rather than being overwritten.
+func scaleWorkload(ctx context.Context, client dynamic.ResourceInterface, name string, replicas, current int32) error {
+	scale, err := client.Get(ctx, name, metav1.GetOptions{}, "scale")
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("workload %q not found", name)
+	} else if err != nil {
+		return fmt.Errorf("getting scale of workload %s: %w", name, err)
+	}
+	if current >= 0 {
+		have, _, _ := unstructured.NestedInt64(scale.Object, "spec", "replicas")
+		if have != int64(current) {
+			return fmt.Errorf("workload %s has %d replicas, not %d", name, have, current)
+		}
+	} else {
+		scale.SetResourceVersion("")
+	}
+	if err := unstructured.SetNestedField(scale.Object, int64(replicas), "spec", "replicas"); err != nil {
+		return err
+	}
+	if _, err := client.Update(ctx, scale, metav1.UpdateOptions{}, "scale"); err != nil {
+		if apierrors.IsConflict(err) {
+			return fmt.Errorf("workload %s changed while scaling it; check its replicas and retry", name)
+		}
+		return fmt.Errorf("scaling workload %s: %w", name, err)
+	}
+	return nil
+}
+
+// previewResponse / previewEdge mirror the edges provider's
+// scheduler.PreviewResult and scheduler.PreviewEdge.
+type previewResponse struct {
+	Edges         int           `json:"edges"`
+	Matched       int           `json:"matched"`
+	Selected      int           `json:"selected"`
+	ExtenderError string        `json:"extenderError"`
+	Results       []previewEdge `json:"results"`
+}
+
+type previewEdge struct {
+	Name      string            `json:"name"`
+	Labels    map[string]string `json:"labels"`
+	Selected  bool              `json:"selected"`
+	Placed    bool              `json:"placed"`
+	Score     *int64            `json:"score"`
+	Unmatched []string          `json:"unmatched"`
+	Reason    string            `json:"reason"`
+}
+
+func newWorkloadPreviewCommand() *cobra.Command {
+	var (
+		opts      listOptions
+		namespace string
+		filename  string
+		selector  string
+		strategy  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "preview [name]",
+		Short: "Show which edges a Workload would be placed on, and why",
+		Long: `Show which edges the scheduler would place a Workload on without creating
+or changing anything: for every edge, whether it is selected, the edge
+selector requirements it fails, the scheduler extender's score and the
+reason. Preview a Workload that exists by name, or one that does not yet
+with -f; --selector and --strategy try out other placements.
+
+The placement policy is not consulted, so a selected edge may still be
+denied when the Placement is written.`,
+		Example: `  kedge vw preview nginx
+  kedge vw preview -f workload.yaml
+  kedge vw preview nginx --selector region=eu --strategy Singleton`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (len(args) == 1) == (filename != "") {
+				return fmt.Errorf("give either a Workload name or -f")
+			}
+			var vw *unstructured.Unstructured
+			if filename != "" {
+				data, err := os.ReadFile(filename)
+				if err != nil {
+					return fmt.Errorf("reading %s: %w", filename, err)
+				}
+				vw = &unstructured.Unstructured{}
+				if err := yaml.Unmarshal(data, &vw.Object); err != nil {
+					return fmt.Errorf("parsing %s: %w", filename, err)
+				}
+				if vw.GetNamespace() == "" {
+					vw.SetNamespace(namespace)
+				}
+			} else {
+				dynClient, err := loadDynamicClient()
+				if err != nil {
+					return fmt.Errorf("not logged in — run: kedge login --hub-url <hub-url>\n(original error: %w)", err)
+				}
+				vw, err = dynClient.Resource(kedgeclient.WorkloadGVR).Namespace(namespace).Get(cmd.Context(), args[0], metav1.GetOptions{})
+				if apierrors.IsNotFound(err) {
+					return fmt.Errorf("workload %q not found", args[0])
+				} else if err != nil {
+					return fmt.Errorf("getting workload %s: %w", args[0], err)
+				}
+			}
+			if err := overridePlacement(vw, selector, strategy); err != nil {
+				return err
+			}
+			body, err := json.Marshal(vw.Object)
+			if err != nil {
+				return err
+			}
+
+			hub, err := newHubSession()
+			if err != nil {
+				return err
+			}
+			org, workspace := currentWorkspaceIDs(cmd.Context(), hub)
+			resp, err := fetchPreview(cmd.Context(), hub, org, workspace, body)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%d edges, %d matched, %d selected\n", resp.Edges, resp.Matched, resp.Selected)
+			if resp.ExtenderError != "" {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s\n", resp.ExtenderError)
+			}
+			return opts.print(cmd.Context(), cmd.OutOrStdout(), "No edges in this workspace.", func(context.Context) (*ui.Table, error) {
+				return previewTable(resp.Results), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "Output format: \"wide\" adds more columns")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace of the Workload")
+	cmd.Flags().StringVarP(&filename, "filename", "f", "", "Workload manifest to preview (YAML or JSON)")
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", "Edge selector to preview instead of the Workload's (e.g. region=eu,tier!=dev)")
+	cmd.Flags().StringVar(&strategy, "strategy", "", "Placement strategy to preview instead of the Workload's: Spread or Singleton")
+	return cmd
+}
+
+// overridePlacement replaces the edge selector and strategy of vw's
+// placement with the non-empty ones given.
+func overridePlacement(vw *unstructured.Unstructured, selector, strategy string) error {
+	if selector != "" {
+		ls, err := metav1.ParseToLabelSelector(selector)
+		if err != nil {
+			return fmt.Errorf("invalid --selector: %w", err)
+		}
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ls)
+		if err != nil {
+			return err
+		}
+		if err := unstructured.SetNestedMap(vw.Object, obj, "spec", "placement", "edgeSelector"); err != nil {
+			return err
+		}
+	}
+	switch strategy {
+	case "":
+	case "Spread", "Singleton":
+		if err := unstructured.SetNestedField(vw.Object, strategy, "spec", "placement", "strategy"); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported --strategy %q (supported: Spread, Singleton)", strategy)
+	}
+	return nil
+}
+
+// fetchPreview asks the hub's scheduler which edges the Workload in body
+// would be placed on, in the given org and workspace.
+func fetchPreview(ctx context.Context, hub *hubSession, org, workspace string, body []byte) (*previewResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hub.base+apiurl.PathSchedulerPreview, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", hubAccept)
+	if org != "" {
+		req.Header.Set("X-Kedge-Org", org)
+		req.Header.Set("X-Kedge-Workspace", workspace)
+	}
+	resp, err := hub.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("previewing placement: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, hubError("previewing placement", resp, data)
+	}
+	var out previewResponse
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("decoding placement preview: %w", err)
+	}
+	return &out, nil
+}
+
+// previewTable lists the preview's edges, selected ones first.
+func previewTable(results []previewEdge) *ui.Table {
+	t := ui.NewTable(
+		ui.Column{Header: "Edge"},
+		ui.Column{Header: "Selected", Status: true},
+		ui.Column{Header: "Placed"},
+		ui.Column{Header: "Score"},
+		ui.Column{Header: "Reason"},
+		ui.Column{Header: "Unmatched", Wide: true},
+		ui.Column{Header: "Labels", Wide: true},
+	)
+	for _, r := range results {
+		score := ""
+		if r.Score != nil {
+			score = strconv.FormatInt(*r.Score, 10)
+		}
+		t.AddRow(r.Name, strconv.FormatBool(r.Selected), strconv.FormatBool(r.Placed), score, r.Reason,
+			strings.Join(r.Unmatched, ","), formatLabels(r.Labels))
+	}
+	return t
+}
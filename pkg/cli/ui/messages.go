@@ -0,0 +1,92 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ui
+
+import (
+	"os"
+	"strings"
+)
+
+// messages is the text the prompts themselves print and accept. The question
+// a command asks is its own and stays in English; what frames it — the answer
+// hint, the type-to-confirm instruction, the words that mean yes and the
+// errors a declined or impossible prompt returns — follows the user's locale.
+type messages struct {
+	// yesNo follows a yes/no question, e.g. "[y/N]".
+	yesNo string
+	// typeToConfirm asks for a name to be typed back; %q is the name.
+	typeToConfirm string
+	// yes are the answers, besides "y" and "yes", that confirm.
+	yes []string
+	// aborted reports a declined prompt; %s is the operation.
+	aborted string
+	// confirmationRequired reports a prompt that could not be shown.
+	confirmationRequired string
+}
+
+// catalog holds the supported languages, keyed by ISO 639-1 code.
+var catalog = map[string]messages{
+	"en": {
+		yesNo:                "[y/N]",
+		typeToConfirm:        "Type %q to confirm:",
+		aborted:              "%s aborted",
+		confirmationRequired: "confirmation required but stdin is not a terminal; pass --yes to proceed",
+	},
+	"de": {
+		yesNo:                "[j/N]",
+		typeToConfirm:        "Zur Bestätigung %q eingeben:",
+		yes:                  []string{"j", "ja"},
+		aborted:              "%s abgebrochen",
+		confirmationRequired: "Bestätigung erforderlich, aber stdin ist kein Terminal; zum Fortfahren --yes angeben",
+	},
+	"es": {
+		yesNo:                "[s/N]",
+		typeToConfirm:        "Escriba %q para confirmar:",
+		yes:                  []string{"s", "si", "sí"},
+		aborted:              "%s cancelado",
+		confirmationRequired: "se requiere confirmación, pero stdin no es una terminal; pase --yes para continuar",
+	},
+	"fr": {
+		yesNo:                "[o/N]",
+		typeToConfirm:        "Tapez %q pour confirmer :",
+		yes:                  []string{"o", "oui"},
+		aborted:              "%s annulé",
+		confirmationRequired: "confirmation requise, mais stdin n'est pas un terminal ; passez --yes pour continuer",
+	},
+}
+
+// msg is the catalog entry for the user's locale.
+var msg = catalog[localeLanguage(os.Getenv)]
+
+// localeLanguage returns the language of the POSIX locale in effect (LC_ALL,
+// then LC_MESSAGES, then LANG), falling back to English for unset, "C",
+// "POSIX" and unsupported locales.
+func localeLanguage(getenv func(string) string) string {
+	for _, v := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		locale := getenv(v)
+		if locale == "" {
+			continue
+		}
+		lang, _, _ := strings.Cut(locale, "_")
+		lang, _, _ = strings.Cut(lang, ".")
+		if _, ok := catalog[lang]; ok {
+			return lang
+		}
+		return "en"
+	}
+	return "en"
+}
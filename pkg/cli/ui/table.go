@@ -0,0 +1,149 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ui
+
+import (
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// columnGap separates table columns.
+const columnGap = "   "
+
+// Column describes one table column.
+type Column struct {
+	Header string
+	// Wide columns are only rendered in wide output (-o wide).
+	Wide bool
+	// Status cells get an icon and a colour by value (see StatusStyle) when
+	// colour is enabled for the output.
+	Status bool
+}
+
+// Table is a list printed as aligned columns under an upper-case header, the
+// way every kedge list command prints.
+type Table struct {
+	columns []Column
+	rows    [][]string
+}
+
+// NewTable returns an empty table with columns.
+func NewTable(columns ...Column) *Table {
+	return &Table{columns: columns}
+}
+
+// AddRow appends a row with one cell per column; empty cells print as "-".
+func (t *Table) AddRow(cells ...string) {
+	row := make([]string, len(t.columns))
+	for i := range row {
+		row[i] = "-"
+		if i < len(cells) && cells[i] != "" {
+			row[i] = cells[i]
+		}
+	}
+	t.rows = append(t.rows, row)
+}
+
+// Len returns the number of rows.
+func (t *Table) Len() int {
+	return len(t.rows)
+}
+
+// Render writes the table to w, including the Wide columns when wide is set.
+// Alignment is computed on the visible text, so coloured cells line up too.
+func (t *Table) Render(w io.Writer, wide bool) error {
+	return t.render(w, wide, ColorEnabled(w))
+}
+
+// Lines returns the rendered header and rows, one string each, for callers
+// that lay the table out themselves (the kedge ui TUI). color embeds the
+// status colours as SGR sequences.
+func (t *Table) Lines(wide, color bool) []string {
+	var b strings.Builder
+	_ = t.render(&b, wide, color)
+	return strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+}
+
+func (t *Table) render(w io.Writer, wide, color bool) error {
+	var cols []int
+	for i, c := range t.columns {
+		if wide || !c.Wide {
+			cols = append(cols, i)
+		}
+	}
+	cell := func(row []string, i int) string {
+		if t.columns[i].Status && color {
+			if icon, _ := StatusStyle(row[i]); icon != "" {
+				return icon + " " + row[i]
+			}
+		}
+		return row[i]
+	}
+	widths := make(map[int]int, len(cols))
+	for _, i := range cols {
+		widths[i] = utf8.RuneCountInString(t.columns[i].Header)
+		for _, row := range t.rows {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell(row, i)))
+		}
+	}
+
+	var b strings.Builder
+	line := func(texts func(i int) (text, sgr string)) {
+		for n, i := range cols {
+			text, sgr := texts(i)
+			pad := ""
+			if n < len(cols)-1 {
+				pad = strings.Repeat(" ", widths[i]-utf8.RuneCountInString(text)) + columnGap
+			}
+			if sgr != "" {
+				text = "\033[" + sgr + "m" + text + "\033[0m"
+			}
+			b.WriteString(text + pad)
+		}
+		b.WriteString("\n")
+	}
+	line(func(i int) (string, string) { return strings.ToUpper(t.columns[i].Header), "" })
+	for _, row := range t.rows {
+		line(func(i int) (string, string) {
+			text := cell(row, i)
+			if !t.columns[i].Status || !color {
+				return text, ""
+			}
+			_, sgr := StatusStyle(row[i])
+			return text, sgr
+		})
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// StatusStyle returns the icon and SGR colour for a status value: green for
+// healthy (Ready, Running, Succeeded, true), red for broken (Disconnected,
+// Failed, Error, false), yellow for in-between states. Unknown values and "-"
+// are left plain.
+func StatusStyle(value string) (icon, sgr string) {
+	switch strings.ToLower(value) {
+	case "ready", "running", "succeeded", "approved", "connected", "true", "ok":
+		return "●", "32"
+	case "disconnected", "failed", "error", "denied", "unreachable", "false", "notready":
+		return "✗", "31"
+	case "pending", "scheduling", "provisioning", "detected", "unknown", "progressing", "warning":
+		return "◐", "33"
+	}
+	return "", ""
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ui
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func testTable() *Table {
+	t := NewTable(
+		Column{Header: "Name"},
+		Column{Header: "Phase", Status: true},
+		Column{Header: "Hostname", Wide: true},
+		Column{Header: "Age"},
+	)
+	t.AddRow("edge-1", "Ready", "host-1.example.com", "3d")
+	t.AddRow("e2", "Disconnected", "", "5m")
+	return t
+}
+
+func TestTableRender(t *testing.T) {
+	var out bytes.Buffer
+	if err := testTable().render(&out, false, false); err != nil {
+		t.Fatal(err)
+	}
+	want := "" +
+		"NAME     PHASE          AGE\n" +
+		"edge-1   Ready          3d\n" +
+		"e2       Disconnected   5m\n"
+	if out.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	if err := testTable().render(&out, true, false); err != nil {
+		t.Fatal(err)
+	}
+	want = "" +
+		"NAME     PHASE          HOSTNAME             AGE\n" +
+		"edge-1   Ready          host-1.example.com   3d\n" +
+		"e2       Disconnected   -                    5m\n"
+	if out.String() != want {
+		t.Errorf("wide: got\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestTableRenderColor(t *testing.T) {
+	var out bytes.Buffer
+	if err := testTable().render(&out, false, true); err != nil {
+		t.Fatal(err)
+	}
+	want := "" +
+		"NAME     PHASE            AGE\n" +
+		"edge-1   \033[32m● Ready\033[0m          3d\n" +
+		"e2       \033[31m✗ Disconnected\033[0m   5m\n"
+	if out.String() != want {
+		t.Errorf("got\n%q\nwant\n%q", out.String(), want)
+	}
+	// Stripped of colour, the columns still line up.
+	plain := regexp.MustCompile("\033\\[[0-9;]*m").ReplaceAllString(out.String(), "")
+	if plain != "NAME     PHASE            AGE\nedge-1   ● Ready          3d\ne2       ✗ Disconnected   5m\n" {
+		t.Errorf("misaligned:\n%s", plain)
+	}
+}
+
+func TestStatusStyle(t *testing.T) {
+	for value, want := range map[string]string{
+		"Ready": "32", "true": "32", "Disconnected": "31", "false": "31",
+		"Scheduling": "33", "-": "", "Custom": "",
+	} {
+		if _, sgr := StatusStyle(value); sgr != want {
+			t.Errorf("StatusStyle(%q) colour = %q, want %q", value, sgr, want)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ui holds the CLI's global output and prompting behaviour — the
+// --yes, --quiet, --no-color and --error-format flags — so every command
+// confirms, colours and reports errors the same way, interactive or scripted.
+package ui
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Global settings, bound to the root command's persistent flags.
+var (
+	// AssumeYes answers every confirmation prompt with yes.
+	AssumeYes bool
+	// Quiet suppresses informational messages (progress, "created", hints).
+	// Command results and errors are still printed.
+	Quiet bool
+	// NoColor disables ANSI colour. The NO_COLOR environment variable and a
+	// non-terminal output do the same.
+	NoColor bool
+	// ErrorFormat is how the top-level error is printed: "text" or "json".
+	ErrorFormat = ErrorFormatText
+)
+
+// Error formats accepted by --error-format.
+const (
+	ErrorFormatText = "text"
+	ErrorFormatJSON = "json"
+)
+
+// Reason codes for errors raised by the CLI itself; hub errors carry the
+// hub's problem reason instead.
+const (
+	// ReasonAborted: the user declined a confirmation prompt.
+	ReasonAborted = "Aborted"
+	// ReasonConfirmationRequired: a prompt was needed but stdin is not a
+	// terminal and --yes was not given.
+	ReasonConfirmationRequired = "ConfirmationRequired"
+)
+
+// ErrConfirmationRequired is returned instead of prompting when stdin is not a
+// terminal, so scripts fail fast rather than hang or read garbage.
+var ErrConfirmationRequired = &ReasonError{
+	Reason: ReasonConfirmationRequired,
+	Err:    confirmationRequiredError{},
+}
+
+// confirmationRequiredError is ErrConfirmationRequired's localized message.
+type confirmationRequiredError struct{}
+
+func (confirmationRequiredError) Error() string { return msg.confirmationRequired }
+
+// ReasonError attaches a stable machine-readable reason to an error; it is
+// reported as "reason" with --error-format=json.
+type ReasonError struct {
+	Reason string
+	Err    error
+}
+
+func (e *ReasonError) Error() string { return e.Err.Error() }
+func (e *ReasonError) Unwrap() error { return e.Err }
+
+// Aborted returns the error a command reports when a confirmation is declined.
+func Aborted(what string) error {
+	return &ReasonError{Reason: ReasonAborted, Err: fmt.Errorf(msg.aborted, what)}
+}
+
+// Confirm asks a yes/no question on out and reads the answer from in. Only
+// "y"/"yes" (any case), or the locale's own yes, confirm. With --yes it
+// returns true without asking.
+func Confirm(in io.Reader, out io.Writer, prompt string) (bool, error) {
+	if AssumeYes {
+		return true, nil
+	}
+	if !interactive(in) {
+		return false, ErrConfirmationRequired
+	}
+	fmt.Fprintf(out, "%s %s: ", prompt, msg.yesNo) //nolint:errcheck
+	answer, err := readLine(in)
+	if err != nil {
+		return false, err
+	}
+	answer = strings.ToLower(answer)
+	return answer == "y" || answer == "yes" || slices.Contains(msg.yes, answer), nil
+}
+
+// ConfirmOnTerminal is Confirm for commands that ran unprompted before
+// prompts existed (edge delete, dev delete): it only asks a person at a
+// terminal, and proceeds without asking when stdin is not one, so scripts
+// written against those commands keep working without --yes.
+func ConfirmOnTerminal(in io.Reader, out io.Writer, prompt string) (bool, error) {
+	if !interactive(in) {
+		return true, nil
+	}
+	return Confirm(in, out, prompt)
+}
+
+// ConfirmName asks the user to type name back before a destructive action and
+// reports whether it matched. With --yes it returns true without asking. A
+// stdin that is not a terminal is read as a scripted answer (echo NAME |
+// kedge ...); only when it is empty is ErrConfirmationRequired returned.
+func ConfirmName(in io.Reader, out io.Writer, prompt, name string) (bool, error) {
+	if AssumeYes {
+		return true, nil
+	}
+	fmt.Fprintf(out, "%s "+msg.typeToConfirm+" ", prompt, name) //nolint:errcheck
+	answer, err := readLine(in)
+	if err != nil {
+		return false, err
+	}
+	if answer == "" && !interactive(in) {
+		return false, ErrConfirmationRequired
+	}
+	return answer == name, nil
+}
+
+// Infof prints an informational message unless --quiet is set.
+func Infof(w io.Writer, format string, args ...interface{}) {
+	if Quiet {
+		return
+	}
+	fmt.Fprintf(w, format, args...) //nolint:errcheck
+}
+
+// ColorEnabled reports whether ANSI colour may be written to w.
+func ColorEnabled(w io.Writer) bool {
+	if NoColor || os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// Colorize wraps text in the SGR sequence sgr (e.g. "31" for red) when colour
+// is enabled for w.
+func Colorize(w io.Writer, sgr, text string) string {
+	if !ColorEnabled(w) {
+		return text
+	}
+	return "\033[" + sgr + "m" + text + "\033[0m"
+}
+
+// PrintError prints the error a command returned, as "Error: <msg>" or, with
+// --error-format=json, as {"error":{"message":...,"reason":...}}.
+func PrintError(w io.Writer, err error) {
+	if ErrorFormat != ErrorFormatJSON {
+		fmt.Fprintf(w, "Error: %v\n", err) //nolint:errcheck
+		return
+	}
+	out := struct {
+		Error struct {
+			Message string `json:"message"`
+			Reason  string `json:"reason,omitempty"`
+		} `json:"error"`
+	}{}
+	out.Error.Message = err.Error()
+	var re *ReasonError
+	if errors.As(err, &re) {
+		out.Error.Reason = re.Reason
+	}
+	enc := json.NewEncoder(w)
+	enc.Encode(out) //nolint:errcheck
+}
+
+// ValidateErrorFormat rejects unknown --error-format values.
+func ValidateErrorFormat() error {
+	switch ErrorFormat {
+	case ErrorFormatText, ErrorFormatJSON:
+		return nil
+	}
+	return fmt.Errorf("invalid --error-format %q: must be %q or %q", ErrorFormat, ErrorFormatText, ErrorFormatJSON)
+}
+
+// interactive reports whether in can answer a prompt. Non-file readers (tests,
+// piped buffers handed in by callers) are treated as scripted answers.
+func interactive(in io.Reader) bool {
+	f, ok := in.(*os.File)
+	if !ok {
+		return true
+	}
+	return term.IsTerminal(int(f.Fd()))
+}
+
+func readLine(in io.Reader) (string, error) {
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("reading confirmation: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
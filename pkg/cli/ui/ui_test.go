@@ -0,0 +1,188 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// Pin the catalog: the tests match English prompts whatever the
+	// environment's locale.
+	msg = catalog["en"]
+	os.Exit(m.Run())
+}
+
+func TestConfirm(t *testing.T) {
+	cases := []struct {
+		answer string
+		want   bool
+	}{
+		{"y\n", true},
+		{"YES\n", true},
+		{"n\n", false},
+		{"\n", false},
+		{"", false},
+		{"sure\n", false},
+	}
+	for _, tc := range cases {
+		var out bytes.Buffer
+		got, err := Confirm(strings.NewReader(tc.answer), &out, "Delete it?")
+		if err != nil {
+			t.Fatalf("Confirm(%q) error: %v", tc.answer, err)
+		}
+		if got != tc.want {
+			t.Errorf("Confirm(%q) = %v, want %v", tc.answer, got, tc.want)
+		}
+		if !strings.Contains(out.String(), "Delete it? [y/N]") {
+			t.Errorf("prompt not written: %q", out.String())
+		}
+	}
+}
+
+func TestConfirmName(t *testing.T) {
+	ok, err := ConfirmName(strings.NewReader("edge-1\n"), &bytes.Buffer{}, "This will reboot edge-1.", "edge-1")
+	if err != nil || !ok {
+		t.Fatalf("ConfirmName() = %v, %v; want true", ok, err)
+	}
+	ok, err = ConfirmName(strings.NewReader("y\n"), &bytes.Buffer{}, "This will reboot edge-1.", "edge-1")
+	if err != nil || ok {
+		t.Fatalf("ConfirmName() with a y answer = %v, %v; want false", ok, err)
+	}
+}
+
+func TestConfirmAssumeYes(t *testing.T) {
+	AssumeYes = true
+	defer func() { AssumeYes = false }()
+
+	var out bytes.Buffer
+	ok, err := ConfirmName(strings.NewReader(""), &out, "This will reboot edge-1.", "edge-1")
+	if err != nil || !ok {
+		t.Fatalf("ConfirmName() with --yes = %v, %v; want true", ok, err)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("prompt written despite --yes: %q", out.String())
+	}
+}
+
+func TestConfirmNonTerminal(t *testing.T) {
+	stdin := func(content string) *os.File {
+		f, err := os.CreateTemp(t.TempDir(), "stdin")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { f.Close() }) //nolint:errcheck
+		if _, err := f.WriteString(content); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Seek(0, 0); err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+
+	_, err := Confirm(stdin(""), &bytes.Buffer{}, "Delete it?")
+	if !errors.Is(err, ErrConfirmationRequired) {
+		t.Fatalf("Confirm() on a non-terminal error = %v, want ErrConfirmationRequired", err)
+	}
+
+	// Commands that never prompted keep running unattended.
+	var out bytes.Buffer
+	ok, err := ConfirmOnTerminal(stdin(""), &out, "Delete it?")
+	if err != nil || !ok || out.Len() != 0 {
+		t.Fatalf("ConfirmOnTerminal() on a non-terminal = %v, %v (prompt %q); want true without a prompt", ok, err, out.String())
+	}
+
+	// A piped name is a scripted answer; no answer at all is an error.
+	ok, err = ConfirmName(stdin("edge-1\n"), &bytes.Buffer{}, "This will reboot edge-1.", "edge-1")
+	if err != nil || !ok {
+		t.Fatalf("ConfirmName() with a piped name = %v, %v; want true", ok, err)
+	}
+	_, err = ConfirmName(stdin(""), &bytes.Buffer{}, "This will reboot edge-1.", "edge-1")
+	if !errors.Is(err, ErrConfirmationRequired) {
+		t.Fatalf("ConfirmName() on an empty non-terminal error = %v, want ErrConfirmationRequired", err)
+	}
+}
+
+func TestConfirmLocalized(t *testing.T) {
+	msg = catalog["de"]
+	defer func() { msg = catalog["en"] }()
+
+	for answer, want := range map[string]bool{"ja\n": true, "J\n": true, "yes\n": true, "nein\n": false} {
+		var out bytes.Buffer
+		got, err := Confirm(strings.NewReader(answer), &out, "Edge löschen?")
+		if err != nil || got != want {
+			t.Errorf("Confirm(%q) = %v, %v; want %v", answer, got, err, want)
+		}
+		if !strings.Contains(out.String(), "Edge löschen? [j/N]") {
+			t.Errorf("prompt not localized: %q", out.String())
+		}
+	}
+	if got := Aborted("edge delete").Error(); got != "edge delete abgebrochen" {
+		t.Errorf("Aborted() = %q", got)
+	}
+	if got := ErrConfirmationRequired.Error(); !strings.HasPrefix(got, "Bestätigung erforderlich") {
+		t.Errorf("ErrConfirmationRequired = %q", got)
+	}
+}
+
+func TestLocaleLanguage(t *testing.T) {
+	cases := []struct {
+		env  map[string]string
+		want string
+	}{
+		{map[string]string{}, "en"},
+		{map[string]string{"LANG": "C.UTF-8"}, "en"},
+		{map[string]string{"LANG": "de_DE.UTF-8"}, "de"},
+		{map[string]string{"LANG": "fr"}, "fr"},
+		{map[string]string{"LANG": "ja_JP.UTF-8"}, "en"},
+		{map[string]string{"LANG": "de_DE.UTF-8", "LC_MESSAGES": "es_ES.UTF-8"}, "es"},
+		{map[string]string{"LC_ALL": "POSIX", "LANG": "de_DE.UTF-8"}, "en"},
+	}
+	for _, tc := range cases {
+		if got := localeLanguage(func(k string) string { return tc.env[k] }); got != tc.want {
+			t.Errorf("localeLanguage(%v) = %q, want %q", tc.env, got, tc.want)
+		}
+	}
+}
+
+func TestPrintErrorJSON(t *testing.T) {
+	ErrorFormat = ErrorFormatJSON
+	defer func() { ErrorFormat = ErrorFormatText }()
+
+	var out bytes.Buffer
+	PrintError(&out, fmt.Errorf("deleting edge: %w", Aborted("edge delete")))
+
+	var got struct {
+		Error struct {
+			Message string `json:"message"`
+			Reason  string `json:"reason"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("output is not JSON: %q: %v", out.String(), err)
+	}
+	if got.Error.Reason != ReasonAborted || got.Error.Message != "deleting edge: edge delete aborted" {
+		t.Fatalf("PrintError() = %+v", got.Error)
+	}
+}
@@ -74,6 +74,20 @@ var (
 		Version:  "v1alpha1",
 		Resource: "placements",
 	}
+	// FleetCommandGVR addresses the edges provider's FleetCommand kind
+	// (cluster-scoped): one command fanned out to matching server edges.
+	FleetCommandGVR = schema.GroupVersionResource{
+		Group:    "edges.kedge.faros.sh",
+		Version:  "v1alpha1",
+		Resource: "fleetcommands",
+	}
+	// EdgeAdoptionRequestGVR addresses the edges provider's EdgeAdoptionRequest
+	// kind (cluster-scoped): an agent asking an admin to create its edge.
+	EdgeAdoptionRequestGVR = schema.GroupVersionResource{
+		Group:    "edges.kedge.faros.sh",
+		Version:  "v1alpha1",
+		Resource: "edgeadoptionrequests",
+	}
 
 	// UserGVR points at the new tenants.kedge.faros.sh User CRD. PRs
 	// #204-#207 introduced the tenants.kedge.faros.sh group; this GVR
@@ -115,6 +129,16 @@ var (
 		Version:  "v1alpha1",
 		Resource: "userpreferences",
 	}
+
+	// PersonalAccessTokenGVR points at the cluster-scoped PersonalAccessToken
+	// CRD (see apis/tenancy/v1alpha1/types_personal_access_token.go). The hub
+	// proxy looks tokens up by name to verify them; the REST surface issues
+	// and revokes them.
+	PersonalAccessTokenGVR = schema.GroupVersionResource{
+		Group:    "tenants.kedge.faros.sh",
+		Version:  "v1alpha1",
+		Resource: "personalaccesstokens",
+	}
 )
 
 // EdgeGVRForType maps an edge type ("kubernetes" | "server") to the connectable
@@ -180,6 +204,15 @@ func (c *Client) UserPreferences() *TypedResource[tenancyv1alpha1.UserPreference
 	}
 }
 
+// PersonalAccessTokens returns a typed interface for the cluster-scoped
+// PersonalAccessToken CRD.
+func (c *Client) PersonalAccessTokens() *TypedResource[tenancyv1alpha1.PersonalAccessToken, tenancyv1alpha1.PersonalAccessTokenList] {
+	return &TypedResource[tenancyv1alpha1.PersonalAccessToken, tenancyv1alpha1.PersonalAccessTokenList]{
+		client: c.dynamic.Resource(PersonalAccessTokenGVR),
+		gvk:    PersonalAccessTokenGVR.GroupVersion().WithKind("PersonalAccessToken"),
+	}
+}
+
 // Organizations returns a typed interface for the cluster-scoped
 // Organization CRD. Used by the step 10 REST surface.
 func (c *Client) Organizations() *TypedResource[tenancyv1alpha1.Organization, tenancyv1alpha1.OrganizationList] {
@@ -17,16 +17,21 @@ limitations under the License.
 package admin
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"sort"
 	"time"
 
 	"github.com/gorilla/mux"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+	"github.com/faroshq/faros-kedge/pkg/hub/migrate"
 	"github.com/faroshq/faros-kedge/pkg/hub/providers"
+	"github.com/faroshq/faros-kedge/pkg/problem"
 )
 
 // Handler serves the /api/admin/* endpoints.
@@ -34,6 +39,7 @@ type Handler struct {
 	svc        *Service
 	userClient *kedgeclient.Client
 	registry   *providers.Registry
+	migrator   *migrate.Migrator
 }
 
 // NewHandler builds an admin Handler.
@@ -41,6 +47,13 @@ func NewHandler(svc *Service, userClient *kedgeclient.Client, registry *provider
 	return &Handler{svc: svc, userClient: userClient, registry: registry}
 }
 
+// WithMigrator serves the schema migration endpoints from m. Without one
+// they are not registered.
+func (h *Handler) WithMigrator(m *migrate.Migrator) *Handler {
+	h.migrator = m
+	return h
+}
+
 // Register mounts the admin routes on r (already gated by the admin Middleware).
 func (h *Handler) Register(r *mux.Router) {
 	// Cheap probe the portal calls to decide whether to show the /bonkers menu
@@ -59,6 +72,18 @@ func (h *Handler) Register(r *mux.Router) {
 	r.HandleFunc("/providers", h.createProvider).Methods(http.MethodPost)
 	r.HandleFunc("/providers/{name}", h.deleteProvider).Methods(http.MethodDelete)
 	r.HandleFunc("/providers/{name}/kubeconfig", h.providerKubeconfig).Methods(http.MethodGet)
+	// Tenant offboarding (`kedge admin tenant`): export everything kedge
+	// stores for a user, delete it without the soft-delete grace period,
+	// and verify nothing is left.
+	r.HandleFunc("/tenants/{user}/export", h.exportTenant).Methods(http.MethodGet)
+	r.HandleFunc("/tenants/{user}", h.deleteTenant).Methods(http.MethodDelete)
+	r.HandleFunc("/tenants/{user}/deletion", h.tenantDeletion).Methods(http.MethodGet)
+	// Schema migration (`kedge admin migrate`): POST starts a run in the
+	// background, GET reports its per-workspace progress.
+	if h.migrator != nil {
+		r.HandleFunc("/migrate", h.startMigration).Methods(http.MethodPost)
+		r.HandleFunc("/migrate", h.migrationStatus).Methods(http.MethodGet)
+	}
 }
 
 type userDTO struct {
@@ -75,7 +100,7 @@ func (h *Handler) access(w http.ResponseWriter, _ *http.Request) {
 func (h *Handler) listUsers(w http.ResponseWriter, r *http.Request) {
 	list, err := h.userClient.Users().List(r.Context(), metav1.ListOptions{})
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		writeError(w, r, http.StatusInternalServerError, err.Error())
 		return
 	}
 	items := make([]userDTO, 0, len(list.Items))
@@ -109,7 +134,7 @@ type workspaceDTO struct {
 func (h *Handler) listOrganizations(w http.ResponseWriter, r *http.Request) {
 	list, err := h.userClient.Organizations().List(r.Context(), metav1.ListOptions{})
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		writeError(w, r, http.StatusInternalServerError, err.Error())
 		return
 	}
 	items := make([]orgDTO, 0, len(list.Items))
@@ -211,7 +236,7 @@ func (h *Handler) listProviders(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) listIdentities(w http.ResponseWriter, r *http.Request) {
 	ids, err := h.svc.ListRootIdentities(r.Context())
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		writeError(w, r, http.StatusInternalServerError, err.Error())
 		return
 	}
 	writeJSON(w, map[string]any{"items": ids})
@@ -229,15 +254,15 @@ type createProviderRequest struct {
 func (h *Handler) createProvider(w http.ResponseWriter, r *http.Request) {
 	var req createProviderRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid request body")
+		writeError(w, r, http.StatusBadRequest, "invalid request body")
 		return
 	}
 	if req.Name == "" {
-		writeError(w, http.StatusBadRequest, "name is required")
+		writeError(w, r, http.StatusBadRequest, "name is required")
 		return
 	}
 	if err := h.svc.CreateProvider(r.Context(), req.Name, req.DisplayName); err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		writeError(w, r, http.StatusInternalServerError, err.Error())
 		return
 	}
 	w.WriteHeader(http.StatusCreated)
@@ -249,11 +274,11 @@ func (h *Handler) createProvider(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) deleteProvider(w http.ResponseWriter, r *http.Request) {
 	name := mux.Vars(r)["name"]
 	if name == "" {
-		writeError(w, http.StatusBadRequest, "provider name is required")
+		writeError(w, r, http.StatusBadRequest, "provider name is required")
 		return
 	}
 	if err := h.svc.DeleteProvider(r.Context(), name); err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		writeError(w, r, http.StatusInternalServerError, err.Error())
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -265,16 +290,16 @@ func (h *Handler) deleteProvider(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) providerKubeconfig(w http.ResponseWriter, r *http.Request) {
 	name := mux.Vars(r)["name"]
 	if name == "" {
-		writeError(w, http.StatusBadRequest, "provider name is required")
+		writeError(w, r, http.StatusBadRequest, "provider name is required")
 		return
 	}
 	kc, err := h.svc.GetProviderKubeconfig(r.Context(), name)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		writeError(w, r, http.StatusInternalServerError, err.Error())
 		return
 	}
 	if len(kc) == 0 {
-		writeError(w, http.StatusNotFound, "kubeconfig not available yet — provider not provisioned")
+		writeError(w, r, http.StatusNotFound, "kubeconfig not available yet — provider not provisioned")
 		return
 	}
 	w.Header().Set("Content-Type", "application/yaml")
@@ -282,13 +307,102 @@ func (h *Handler) providerKubeconfig(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write(kc)
 }
 
+// startMigrationRequest is the body of POST /api/admin/migrate. An empty
+// body starts a real run.
+type startMigrationRequest struct {
+	DryRun bool `json:"dryRun"`
+}
+
+// startMigration starts a schema migration and answers 202 with its initial
+// status, or 409 while one is already running.
+func (h *Handler) startMigration(w http.ResponseWriter, r *http.Request) {
+	var req startMigrationRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, r, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+	status, err := h.migrator.Start(r.Context(), req.DryRun)
+	if errors.Is(err, migrate.ErrRunning) {
+		writeError(w, r, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(status)
+}
+
+// migrationStatus reports the current or last migration run. Progress is
+// kept in memory by the hub replica that ran it; 404 if it has run none.
+func (h *Handler) migrationStatus(w http.ResponseWriter, r *http.Request) {
+	status := h.migrator.Status()
+	if status == nil {
+		writeError(w, r, http.StatusNotFound, "no migration has run since the hub started")
+		return
+	}
+	writeJSON(w, status)
+}
+
+// exportTenant streams a tenant export archive (see Service.ExportTenant).
+// ?secrets=true includes Secrets and token hashes. The archive is built in
+// memory so a failure part way surfaces as an error, not a truncated file.
+func (h *Handler) exportTenant(w http.ResponseWriter, r *http.Request) {
+	user := mux.Vars(r)["user"]
+	includeSecrets := r.URL.Query().Get("secrets") == "true"
+	var buf bytes.Buffer
+	if err := h.svc.ExportTenant(r.Context(), h.userClient.Dynamic(), user, includeSecrets, &buf); err != nil {
+		writeTenantError(w, r, user, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+user+`-export.tar.gz"`)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// deleteTenant starts an immediate tenant deletion and answers 202 with the
+// first deletion report.
+func (h *Handler) deleteTenant(w http.ResponseWriter, r *http.Request) {
+	user := mux.Vars(r)["user"]
+	d, err := h.svc.DeleteTenant(r.Context(), h.userClient, user)
+	if err != nil {
+		writeTenantError(w, r, user, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(d)
+}
+
+// tenantDeletion reports what is left of a tenant. Repeated ?org= values
+// add organizations to check, typically the orgs of the report
+// deleteTenant returned.
+func (h *Handler) tenantDeletion(w http.ResponseWriter, r *http.Request) {
+	d, err := h.svc.TenantDeletionStatus(r.Context(), h.userClient, mux.Vars(r)["user"], r.URL.Query()["org"])
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, d)
+}
+
+func writeTenantError(w http.ResponseWriter, r *http.Request, user string, err error) {
+	if apierrors.IsNotFound(err) {
+		writeError(w, r, http.StatusNotFound, "user "+user+" not found")
+		return
+	}
+	writeError(w, r, http.StatusInternalServerError, err.Error())
+}
+
 func writeJSON(w http.ResponseWriter, v any) {
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(v)
 }
 
-func writeError(w http.ResponseWriter, code int, msg string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
-	_ = json.NewEncoder(w).Encode(map[string]string{"error": msg})
+func writeError(w http.ResponseWriter, r *http.Request, code int, msg string) {
+	problem.Write(w, r, code, problem.ReasonForStatus(code), msg)
 }
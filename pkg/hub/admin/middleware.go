@@ -19,6 +19,8 @@ package admin
 import (
 	"context"
 	"net/http"
+
+	"github.com/faroshq/faros-kedge/pkg/problem"
 )
 
 // UserResolver maps an inbound request to the caller's User CR name, or returns
@@ -56,11 +58,11 @@ func Middleware(resolver UserResolver, checker AdminChecker) func(http.Handler)
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			name, err := resolver.ResolveUser(r)
 			if err != nil || name == "" {
-				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				problem.Write(w, r, http.StatusUnauthorized, problem.ReasonUnauthorized, "unauthorized")
 				return
 			}
 			if !checker.IsAdmin(r.Context(), name) {
-				http.Error(w, "forbidden: admin access required", http.StatusForbidden)
+				problem.Write(w, r, http.StatusForbidden, problem.ReasonAdminRequired, "forbidden: admin access required")
 				return
 			}
 			ctx := context.WithValue(r.Context(), adminCtxKey{}, name)
@@ -0,0 +1,428 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"sort"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/yaml"
+
+	tenancyv1alpha1 "github.com/faroshq/faros-kedge/apis/tenancy/v1alpha1"
+	"github.com/faroshq/faros-kedge/pkg/apiurl"
+	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+	"github.com/faroshq/faros-kedge/pkg/hub/controllers/softdelete"
+	"github.com/faroshq/faros-kedge/pkg/hub/migrate"
+	"github.com/faroshq/faros-kedge/pkg/kcppaths"
+)
+
+var (
+	configMapGVR  = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	secretGVR     = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	apiBindingGVR = schema.GroupVersionResource{Group: "apis.kcp.io", Version: "v1alpha2", Resource: "apibindings"}
+	membershipGVR = schema.GroupVersionResource{Group: "tenants.kedge.faros.sh", Version: "v1alpha1", Resource: "memberships"}
+)
+
+// TenantManifest is manifest.json, the last entry of a tenant export
+// archive.
+type TenantManifest struct {
+	User       string      `json:"user"`
+	ExportedAt metav1.Time `json:"exportedAt"`
+	// IncludesSecrets reports whether Secrets and personal access token
+	// hashes were exported; they are left out by default.
+	IncludesSecrets bool   `json:"includesSecrets"`
+	PersonalOrg     string `json:"personalOrg,omitempty"`
+	// Workspaces are the kcp paths of the personal org's workspaces whose
+	// contents the archive holds.
+	Workspaces []string `json:"workspaces"`
+	Objects    int      `json:"objects"`
+}
+
+// ExportTenant writes a gzipped tar of everything kedge stores for the user
+// to w: the User, UserMembershipIndex, UserPreferences and personal access
+// tokens; the user's Memberships in other orgs; and the personal org with
+// its Memberships and the contents of each of its workspaces (platform
+// resources, APIBindings and ConfigMaps, plus Secrets with includeSecrets).
+// Objects are YAML, one file each, without managedFields. Workspaces of
+// orgs the user only belongs to are the team's data and are not exported.
+// Returns a NotFound error, before writing anything, if the user does not
+// exist.
+func (s *Service) ExportTenant(ctx context.Context, users dynamic.Interface, userName string, includeSecrets bool, w io.Writer) error {
+	user, err := users.Resource(kedgeclient.UserGVR).Get(ctx, userName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	resources, err := migrate.PlatformResources()
+	if err != nil {
+		return fmt.Errorf("reading platform resources: %w", err)
+	}
+
+	zw := gzip.NewWriter(w)
+	a := &tenantArchive{tw: tar.NewWriter(zw), now: time.Now()}
+	m := TenantManifest{
+		User:            userName,
+		ExportedAt:      metav1.NewTime(a.now),
+		IncludesSecrets: includeSecrets,
+		Workspaces:      []string{},
+	}
+
+	if err := a.add("user/user.yaml", user); err != nil {
+		return err
+	}
+	umi, err := getOptional(ctx, users.Resource(kedgeclient.UserMembershipIndexGVR), userName)
+	if err != nil {
+		return err
+	}
+	if umi != nil {
+		if err := a.add("user/usermembershipindex.yaml", umi); err != nil {
+			return err
+		}
+	}
+	prefs, err := getOptional(ctx, users.Resource(kedgeclient.UserPreferencesGVR), userName)
+	if err != nil {
+		return err
+	}
+	if prefs != nil {
+		if err := a.add("user/userpreferences.yaml", prefs); err != nil {
+			return err
+		}
+	}
+	pats, err := users.Resource(kedgeclient.PersonalAccessTokenGVR).List(ctx, metav1.ListOptions{
+		LabelSelector: tenancyv1alpha1.PersonalAccessTokenUserLabel + "=" + userName,
+	})
+	if err != nil {
+		return fmt.Errorf("listing personal access tokens: %w", err)
+	}
+	for i := range pats.Items {
+		pat := &pats.Items[i]
+		if !includeSecrets {
+			unstructured.RemoveNestedField(pat.Object, "spec", "tokenHash")
+		}
+		if err := a.add(path.Join("user/personalaccesstokens", pat.GetName()+".yaml"), pat); err != nil {
+			return err
+		}
+	}
+
+	for _, org := range memberOrgs(umi) {
+		orgClient, err := s.clientForPath(kcppaths.OrgPath(org))
+		if err != nil {
+			return err
+		}
+		mb, err := getOptional(ctx, orgClient.Resource(membershipGVR), userName)
+		if err != nil {
+			return err
+		}
+		if mb != nil {
+			if err := a.add(path.Join("orgs", org, "memberships", userName+".yaml"), mb); err != nil {
+				return err
+			}
+		}
+	}
+
+	m.PersonalOrg, _, _ = unstructured.NestedString(user.Object, "status", "personalOrg")
+	if org := m.PersonalOrg; org != "" {
+		orgObj, err := getOptional(ctx, users.Resource(kedgeclient.OrganizationGVR), org)
+		if err != nil {
+			return err
+		}
+		if orgObj != nil {
+			if err := a.add(path.Join("orgs", org, "organization.yaml"), orgObj); err != nil {
+				return err
+			}
+		}
+		if err := s.exportWorkspace(ctx, a, path.Join("orgs", org), kcppaths.OrgPath(org), []schema.GroupVersionResource{membershipGVR}); err != nil {
+			return err
+		}
+		wss, err := s.bootstrapper.ListChildWorkspaces(ctx, org)
+		if err != nil {
+			return fmt.Errorf("listing workspaces of org %s: %w", org, err)
+		}
+		gvrs := []schema.GroupVersionResource{apiBindingGVR, configMapGVR}
+		if includeSecrets {
+			gvrs = append(gvrs, secretGVR)
+		}
+		for _, r := range resources {
+			gvrs = append(gvrs, r.GVR())
+		}
+		for _, ws := range wss {
+			wsPath := kcppaths.WorkspacePath(org, ws)
+			if err := s.exportWorkspace(ctx, a, path.Join("orgs", org, "workspaces", ws), wsPath, gvrs); err != nil {
+				return err
+			}
+			m.Workspaces = append(m.Workspaces, wsPath)
+		}
+	}
+
+	m.Objects = a.objects
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := a.write("manifest.json", data); err != nil {
+		return err
+	}
+	if err := a.tw.Close(); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// exportWorkspace adds every object of gvrs stored in the workspace at
+// wsPath to a under dir, as dir/<resource.group>/[<namespace>/]<name>.yaml.
+// Resources the workspace does not serve are skipped.
+func (s *Service) exportWorkspace(ctx context.Context, a *tenantArchive, dir, wsPath string, gvrs []schema.GroupVersionResource) error {
+	client, err := s.clientForPath(wsPath)
+	if err != nil {
+		return err
+	}
+	for _, gvr := range gvrs {
+		list, err := client.Resource(gvr).List(ctx, metav1.ListOptions{})
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("listing %s in %s: %w", gvr.GroupResource(), wsPath, err)
+		}
+		for i := range list.Items {
+			obj := &list.Items[i]
+			name := path.Join(dir, gvr.GroupResource().String(), obj.GetNamespace(), obj.GetName()+".yaml")
+			if err := a.add(name, obj); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// TenantObject is an object a tenant deletion has not removed yet.
+type TenantObject struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Path is the kcp workspace the object lives in.
+	Path string `json:"path"`
+}
+
+// TenantDeletion reports how far a tenant deletion has got. It is complete
+// once none of the user's objects remain.
+type TenantDeletion struct {
+	User string `json:"user"`
+	// Orgs are the organizations checked for the user's personal org and
+	// Memberships. Once the User and its UserMembershipIndex are gone they
+	// are no longer discoverable, so callers pass back the Orgs of an
+	// earlier report.
+	Orgs []string `json:"orgs"`
+	// Reason and Message are the User's DeletionInProgress condition, e.g.
+	// SoleAdminBlocked when the cascade cannot proceed.
+	Reason    string         `json:"reason,omitempty"`
+	Message   string         `json:"message,omitempty"`
+	Complete  bool           `json:"complete"`
+	Remaining []TenantObject `json:"remaining"`
+}
+
+// DeleteTenant deletes the user without waiting out the soft-delete grace
+// period: it sets status.deletionRequestedAt a grace period in the past, so
+// the soft-delete cascade starts at once, and returns the first deletion
+// report. Returns a NotFound error if the user does not exist.
+func (s *Service) DeleteTenant(ctx context.Context, users *kedgeclient.Client, userName string) (*TenantDeletion, error) {
+	user, err := users.Users().Get(ctx, userName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	due := metav1.NewTime(time.Now().Add(-softdelete.GracePeriod))
+	if at := user.Status.DeletionRequestedAt; at == nil || at.After(due.Time) {
+		user.Status.DeletionRequestedAt = &due
+		if _, err := users.Users().UpdateStatus(ctx, user, metav1.UpdateOptions{}); err != nil {
+			return nil, fmt.Errorf("requesting deletion of user %q: %w", userName, err)
+		}
+	}
+	return s.TenantDeletionStatus(ctx, users, userName, nil)
+}
+
+// TenantDeletionStatus lists what is left of the user: the User,
+// UserMembershipIndex, UserPreferences and personal access tokens, the
+// personal Organization and its workspace, and the user's Memberships in
+// orgs. orgs adds organizations to check to those the User and
+// UserMembershipIndex still name.
+func (s *Service) TenantDeletionStatus(ctx context.Context, users *kedgeclient.Client, userName string, orgs []string) (*TenantDeletion, error) {
+	d := &TenantDeletion{User: userName, Remaining: []TenantObject{}}
+	remain := func(kind, name, path string) {
+		d.Remaining = append(d.Remaining, TenantObject{Kind: kind, Name: name, Path: path})
+	}
+	orgs = slices.Clone(orgs)
+
+	user, err := users.Users().Get(ctx, userName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return nil, fmt.Errorf("getting user %q: %w", userName, err)
+	default:
+		remain("User", userName, kcppaths.SystemTenants)
+		if c := meta.FindStatusCondition(user.Status.Conditions, tenancyv1alpha1.UserConditionDeletionInProgress); c != nil {
+			d.Reason, d.Message = c.Reason, c.Message
+		}
+		if user.Status.PersonalOrg != "" {
+			orgs = append(orgs, user.Status.PersonalOrg)
+		}
+	}
+
+	umi, err := getOptional(ctx, users.Dynamic().Resource(kedgeclient.UserMembershipIndexGVR), userName)
+	if err != nil {
+		return nil, err
+	}
+	if umi != nil {
+		remain("UserMembershipIndex", userName, kcppaths.SystemTenants)
+		orgs = append(orgs, memberOrgs(umi)...)
+	}
+	if _, err := users.UserPreferences().Get(ctx, userName, metav1.GetOptions{}); err == nil {
+		remain("UserPreferences", userName, kcppaths.SystemTenants)
+	} else if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("getting preferences of user %q: %w", userName, err)
+	}
+	pats, err := users.PersonalAccessTokens().List(ctx, metav1.ListOptions{
+		LabelSelector: tenancyv1alpha1.PersonalAccessTokenUserLabel + "=" + userName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing personal access tokens: %w", err)
+	}
+	for i := range pats.Items {
+		remain("PersonalAccessToken", pats.Items[i].Name, kcppaths.SystemTenants)
+	}
+
+	sort.Strings(orgs)
+	d.Orgs = slices.Compact(orgs)
+	var orgWorkspaces []string
+	for _, org := range d.Orgs {
+		o, err := users.Organizations().Get(ctx, org, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			// The org is gone; its workspace may not be yet.
+			if orgWorkspaces == nil {
+				if orgWorkspaces, err = s.bootstrapper.ListOrgWorkspaces(ctx); err != nil {
+					return nil, fmt.Errorf("listing org workspaces: %w", err)
+				}
+			}
+			if slices.Contains(orgWorkspaces, org) {
+				remain("Workspace", org, kcppaths.TenantsParent)
+			}
+		case err != nil:
+			return nil, fmt.Errorf("getting organization %q: %w", org, err)
+		case o.Spec.Personal:
+			remain("Organization", org, kcppaths.SystemTenants)
+		default:
+			members, err := s.bootstrapper.ListOrgMemberships(ctx, org)
+			if err != nil {
+				return nil, err
+			}
+			if slices.Contains(members, userName) {
+				remain("Membership", userName, kcppaths.OrgPath(org))
+			}
+		}
+	}
+	d.Complete = len(d.Remaining) == 0
+	return d, nil
+}
+
+// memberOrgs returns the non-personal orgs a UserMembershipIndex lists the
+// user as a member of. umi may be nil.
+func memberOrgs(umi *unstructured.Unstructured) []string {
+	if umi == nil {
+		return nil
+	}
+	entries, _, _ := unstructured.NestedSlice(umi.Object, "spec", "entries")
+	var out []string
+	for _, e := range entries {
+		em, ok := e.(map[string]any)
+		if !ok {
+			continue
+		}
+		org, _ := em["orgUUID"].(string)
+		ws, _ := em["workspaceUUID"].(string)
+		personal, _ := em["personal"].(bool)
+		if org != "" && ws == "" && !personal {
+			out = append(out, org)
+		}
+	}
+	return out
+}
+
+func (s *Service) clientForPath(clusterPath string) (dynamic.Interface, error) {
+	cfg := rest.CopyConfig(s.kcpConfig)
+	cfg.Host = apiurl.KCPClusterURL(cfg.Host, clusterPath)
+	cl, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("dynamic client for %s: %w", clusterPath, err)
+	}
+	return cl, nil
+}
+
+// getOptional gets name from ri, returning nil without error if it does
+// not exist.
+func getOptional(ctx context.Context, ri dynamic.ResourceInterface, name string) (*unstructured.Unstructured, error) {
+	obj, err := ri.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting %s: %w", name, err)
+	}
+	return obj, nil
+}
+
+// tenantArchive writes objects as YAML files into a tar.
+type tenantArchive struct {
+	tw      *tar.Writer
+	now     time.Time
+	objects int
+}
+
+func (a *tenantArchive) add(name string, obj *unstructured.Unstructured) error {
+	unstructured.RemoveNestedField(obj.Object, "metadata", "managedFields")
+	data, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", name, err)
+	}
+	a.objects++
+	return a.write(name, data)
+}
+
+func (a *tenantArchive) write(name string, data []byte) error {
+	if err := a.tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o600,
+		Size:    int64(len(data)),
+		ModTime: a.now,
+	}); err != nil {
+		return err
+	}
+	_, err := a.tw.Write(data)
+	return err
+}
@@ -0,0 +1,433 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit records who did what through the hub: every request to the
+// kcp API proxy and the provider backend proxy, which carries edge cluster
+// access (kubectl, exec, ssh), becomes one Event with the caller's identity,
+// the target workspace and edge, the verb, the path, the response code and
+// the latency.
+//
+// Events are written to one or more sinks (see ParseSink): JSON lines on
+// stdout or in a file, or batches POSTed to a webhook. Writing never delays a
+// request: events are queued and the queue is drained in the background; when
+// it is full, events are dropped and counted in
+// kedge_hub_audit_events_dropped_total.
+//
+// An event is recorded when its request completes, so a watch, exec or ssh
+// session is recorded when it ends, with its full duration as latency. The
+// proxies name the caller with SetUser once they have authenticated it;
+// requests refused before that are recorded without a user.
+package audit
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+
+	"github.com/faroshq/faros-kedge/pkg/hub/metrics"
+)
+
+const (
+	// queueSize bounds the events waiting to be written.
+	queueSize = 4096
+	// maxBatch bounds the events handed to a sink in one Write.
+	maxBatch = 256
+	// writeTimeout bounds one sink Write.
+	writeTimeout = 10 * time.Second
+)
+
+// Authentication methods of Event.AuthMethod.
+const (
+	AuthMethodOIDC                = "oidc"
+	AuthMethodStaticToken         = "static-token"
+	AuthMethodPersonalAccessToken = "personal-access-token"
+	AuthMethodServiceAccount      = "service-account"
+)
+
+// Actions of Event.Action.
+const (
+	// ActionEdgeReboot and ActionEdgeShutdown mark an SSH exec on an edge
+	// whose command reboots or powers off the host (kedge edge reboot,
+	// kedge edge shutdown).
+	ActionEdgeReboot   = "EdgeReboot"
+	ActionEdgeShutdown = "EdgeShutdown"
+)
+
+// auditedPrefixes are the request paths audited: the kcp API and the hub's
+// services, provider backends included. Health probes, the portal and its
+// assets are not.
+var auditedPrefixes = []string{"/clusters/", "/api/", "/apis/", "/services/"}
+
+// redactedQueryParams are query parameters carrying credentials: a signed
+// edge proxy URL's signature and expiry, and bearer tokens some clients (a
+// browser's WebSocket, say) pass in the query. Their values are not recorded.
+var redactedQueryParams = sets.New("kedge-signature", "kedge-expires", "token", "access_token")
+
+// edgeGroups and edgeResources identify requests addressing an edge, whose
+// name is then recorded as the event's Edge.
+var (
+	edgeGroups    = sets.New("edges.kedge.faros.sh", "kedge.faros.sh")
+	edgeResources = sets.New("kubernetesclusters", "linuxservers", "edges")
+)
+
+var (
+	eventsDropped = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "kedge_hub",
+		Name:      "audit_events_dropped_total",
+		Help:      "Audit events dropped because the audit queue was full.",
+	})
+	sinkErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kedge_hub",
+		Name:      "audit_sink_errors_total",
+		Help:      "Failed writes of audit event batches, by sink.",
+	}, []string{"sink"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(eventsDropped, sinkErrors)
+}
+
+// Event is one audited request.
+type Event struct {
+	Time time.Time `json:"time"`
+	// User is the hub User the request was authenticated as, or the kcp
+	// ServiceAccount, and AuthMethod how, one of the AuthMethod constants.
+	// Both are empty for requests refused before authentication.
+	User       string `json:"user,omitempty"`
+	AuthMethod string `json:"authMethod,omitempty"`
+	// SourceIP is the address the request came from; ForwardedFor the
+	// X-Forwarded-For header it carried, if any.
+	SourceIP     string `json:"sourceIP"`
+	ForwardedFor string `json:"forwardedFor,omitempty"`
+	UserAgent    string `json:"userAgent,omitempty"`
+	// Cluster is the logical cluster addressed, Edge the edge, if any.
+	Cluster string `json:"cluster,omitempty"`
+	Edge    string `json:"edge,omitempty"`
+	// Verb is the Kubernetes verb of API requests ("list", "create",
+	// "watch"...) and the lower-cased HTTP method of others.
+	Verb   string `json:"verb"`
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// Query is the raw query string, with the values of credential
+	// parameters (redactedQueryParams) replaced by REDACTED.
+	Query string `json:"query,omitempty"`
+	Code  int    `json:"code"`
+	// Action names the privileged edge operation the request performs,
+	// one of the Action constants, if any.
+	Action string `json:"action,omitempty"`
+	// LatencyMs is the time the hub took to serve the request, streaming
+	// responses included.
+	LatencyMs float64 `json:"latencyMs"`
+}
+
+// Logger records Events to its sinks.
+type Logger struct {
+	sinks []Sink
+	queue chan Event
+	done  chan struct{}
+	now   func() time.Time
+}
+
+// NewLogger returns a Logger writing to sinks. Call Run to start writing.
+func NewLogger(sinks ...Sink) *Logger {
+	return &Logger{
+		sinks: sinks,
+		queue: make(chan Event, queueSize),
+		done:  make(chan struct{}),
+		now:   time.Now,
+	}
+}
+
+// Run writes queued events until ctx is done, then writes the events still
+// queued and closes the sinks.
+func (l *Logger) Run(ctx context.Context) {
+	defer close(l.done)
+	logger := klog.FromContext(ctx).WithName("audit")
+	for {
+		select {
+		case ev := <-l.queue:
+			l.write(logger, l.batch(ev))
+		case <-ctx.Done():
+			for len(l.queue) > 0 {
+				l.write(logger, l.batch(<-l.queue))
+			}
+			for _, s := range l.sinks {
+				if err := s.Close(); err != nil {
+					logger.Error(err, "Closing audit sink", "sink", s.Name())
+				}
+			}
+			return
+		}
+	}
+}
+
+// Done is closed when Run has returned.
+func (l *Logger) Done() <-chan struct{} {
+	return l.done
+}
+
+// batch returns first and the events queued behind it, up to maxBatch.
+func (l *Logger) batch(first Event) []Event {
+	events := []Event{first}
+	for len(events) < maxBatch {
+		select {
+		case ev := <-l.queue:
+			events = append(events, ev)
+		default:
+			return events
+		}
+	}
+	return events
+}
+
+func (l *Logger) write(logger klog.Logger, events []Event) {
+	for _, s := range l.sinks {
+		ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+		err := s.Write(ctx, events)
+		cancel()
+		if err != nil {
+			sinkErrors.WithLabelValues(s.Name()).Inc()
+			logger.Error(err, "Writing audit events", "sink", s.Name(), "events", len(events))
+		}
+	}
+}
+
+// Record queues ev, dropping it if the queue is full.
+func (l *Logger) Record(ev Event) {
+	select {
+	case l.queue <- ev:
+	default:
+		eventsDropped.Inc()
+	}
+}
+
+// Handler returns next with its API and service requests audited.
+func (l *Logger) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !audited(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		start := l.now()
+		ev := newEvent(r, start)
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), eventKey{}, ev)))
+		ev.Code = rec.status()
+		ev.LatencyMs = float64(l.now().Sub(start)) / float64(time.Millisecond)
+		l.Record(*ev)
+	})
+}
+
+// audited reports whether requests to path are audited.
+func audited(path string) bool {
+	for _, prefix := range auditedPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// newEvent returns the event of r, with its target and verb taken from the
+// path.
+func newEvent(r *http.Request, now time.Time) *Event {
+	ev := &Event{
+		Time:         now.UTC(),
+		SourceIP:     r.RemoteAddr,
+		ForwardedFor: r.Header.Get("X-Forwarded-For"),
+		UserAgent:    r.UserAgent(),
+		Method:       r.Method,
+		Path:         r.URL.Path,
+		Query:        redactQuery(r.URL.RawQuery),
+		Verb:         strings.ToLower(r.Method),
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		ev.SourceIP = host
+	}
+	ev.setTarget(r.Method, r.URL.Path, r.URL.RawQuery)
+	return ev
+}
+
+// redactQuery returns rawQuery with the values of redactedQueryParams
+// replaced, the rest kept as sent.
+func redactQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	params := strings.Split(rawQuery, "&")
+	for i, param := range params {
+		key, _, _ := strings.Cut(param, "=")
+		name, err := url.QueryUnescape(key)
+		if err != nil {
+			name = key
+		}
+		if redactedQueryParams.Has(strings.ToLower(name)) {
+			params[i] = key + "=REDACTED"
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// setTarget fills in the cluster, edge and verb of a request for apiPath,
+// which addresses a logical cluster as /clusters/{cluster}[:{edge}]/...,
+// either at the start or, for provider backends, further down the path.
+func (ev *Event) setTarget(method, apiPath, rawQuery string) {
+	i := strings.Index(apiPath, "/clusters/")
+	if i < 0 {
+		if strings.HasPrefix(apiPath, "/api/") || strings.HasPrefix(apiPath, "/apis/") {
+			ev.setVerb(method, apiPath, rawQuery)
+		}
+		return
+	}
+	rest := apiPath[i+len("/clusters/"):]
+	seg, rest, _ := strings.Cut(rest, "/")
+	ev.Cluster, ev.Edge, _ = strings.Cut(seg, ":")
+	ev.setVerb(method, "/"+rest, rawQuery)
+}
+
+// setVerb sets the Kubernetes verb of a request for apiPath, a path without
+// a cluster segment, the edge it addresses if it is an edge or one of its
+// subresources, and the action of an SSH exec on it.
+func (ev *Event) setVerb(method, apiPath, rawQuery string) {
+	factory := &request.RequestInfoFactory{
+		APIPrefixes:          sets.NewString("api", "apis"),
+		GrouplessAPIPrefixes: sets.NewString("api"),
+	}
+	req, err := http.NewRequest(method, apiPath+"?"+rawQuery, nil)
+	if err != nil {
+		return
+	}
+	info, err := factory.NewRequestInfo(req)
+	if err != nil || !info.IsResourceRequest {
+		return
+	}
+	ev.Verb = info.Verb
+	if edgeGroups.Has(info.APIGroup) && edgeResources.Has(info.Resource) && info.Name != "" {
+		ev.Edge = info.Name
+		if info.Subresource == "ssh" {
+			ev.Action = powerAction(req.URL.Query().Get("cmd"))
+		}
+	}
+}
+
+// powerAction returns the action of the SSH exec command cmd if it reboots or
+// powers off the host, or "". It looks past sudo and its flags at the program
+// run, so the action follows what the edge is asked to do, not which client
+// asked; a command wrapped in a shell is recorded in Query only.
+func powerAction(cmd string) string {
+	fields := strings.Fields(cmd)
+	for len(fields) > 0 && (path.Base(fields[0]) == "sudo" || strings.HasPrefix(fields[0], "-")) {
+		fields = fields[1:]
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+	prog, args := path.Base(fields[0]), fields[1:]
+	if prog == "systemctl" {
+		prog, args = "", nil
+		for i, arg := range fields[1:] {
+			if !strings.HasPrefix(arg, "-") {
+				prog, args = arg, fields[i+2:]
+				break
+			}
+		}
+	}
+	switch prog {
+	case "reboot":
+		return ActionEdgeReboot
+	case "poweroff", "halt":
+		return ActionEdgeShutdown
+	case "shutdown":
+		if slices.Contains(args, "-r") || slices.Contains(args, "--reboot") {
+			return ActionEdgeReboot
+		}
+		return ActionEdgeShutdown
+	}
+	return ""
+}
+
+type eventKey struct{}
+
+// SetUser records on the event of the request ctx belongs to the User it was
+// authenticated as and how. It does nothing for unaudited requests.
+func SetUser(ctx context.Context, user, authMethod string) {
+	if ev, ok := ctx.Value(eventKey{}).(*Event); ok {
+		ev.User = user
+		if authMethod != "" {
+			ev.AuthMethod = authMethod
+		}
+	}
+}
+
+// SetCluster records on the event of the request ctx belongs to the logical
+// cluster it was forwarded to, for requests whose path does not name it.
+func SetCluster(ctx context.Context, cluster string) {
+	if ev, ok := ctx.Value(eventKey{}).(*Event); ok {
+		ev.Cluster, ev.Edge, _ = strings.Cut(cluster, ":")
+	}
+}
+
+// statusRecorder passes a response through, noting its status code.
+type statusRecorder struct {
+	http.ResponseWriter
+	code int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if r.code == 0 {
+		r.code = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	if r.code == 0 {
+		r.code = http.StatusOK
+	}
+	return r.ResponseWriter.Write(p)
+}
+
+func (r *statusRecorder) status() int {
+	if r.code == 0 {
+		return http.StatusOK
+	}
+	return r.code
+}
+
+// Hijack records a protocol switch, as for exec and port-forward, before
+// handing the connection over.
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if r.code == 0 {
+		r.code = http.StatusSwitchingProtocols
+	}
+	return http.NewResponseController(r.ResponseWriter).Hijack()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer (flushes).
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHandlerRecordsEvents(t *testing.T) {
+	l := NewLogger()
+	h := l.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		SetUser(r.Context(), "alice", AuthMethodOIDC)
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	for _, tc := range []struct {
+		method, target string
+		want           Event
+	}{{
+		method: http.MethodGet,
+		target: "/clusters/abc/apis/apps/v1/namespaces/default/deployments?watch=true",
+		want:   Event{User: "alice", AuthMethod: AuthMethodOIDC, Cluster: "abc", Verb: "watch", Code: http.StatusCreated},
+	}, {
+		method: http.MethodPost,
+		target: "/clusters/abc/apis/edges.kedge.faros.sh/v1alpha1/kubernetesclusters/site-1/proxy/api/v1/pods",
+		want:   Event{User: "alice", AuthMethod: AuthMethodOIDC, Cluster: "abc", Edge: "site-1", Verb: "create", Code: http.StatusCreated},
+	}, {
+		method: http.MethodGet,
+		target: "/services/providers/edges/edgeproxy/clusters/abc/apis/edges.kedge.faros.sh/v1alpha1/linuxservers/box/ssh",
+		want:   Event{User: "alice", AuthMethod: AuthMethodOIDC, Cluster: "abc", Edge: "box", Verb: "get", Code: http.StatusCreated},
+	}, {
+		method: http.MethodGet,
+		target: "/services/providers/edges/edgeproxy/clusters/abc/apis/edges.kedge.faros.sh/v1alpha1/linuxservers/box/ssh?cmd=sudo+systemctl+reboot",
+		want: Event{User: "alice", AuthMethod: AuthMethodOIDC, Cluster: "abc", Edge: "box", Verb: "get", Code: http.StatusCreated,
+			Action: ActionEdgeReboot},
+	}, {
+		method: http.MethodDelete,
+		target: "/clusters/abc:site-1/api/v1/namespaces/default/pods/web",
+		want:   Event{User: "alice", AuthMethod: AuthMethodOIDC, Cluster: "abc", Edge: "site-1", Verb: "delete", Code: http.StatusCreated},
+	}} {
+		req := httptest.NewRequest(tc.method, tc.target, nil)
+		h.ServeHTTP(httptest.NewRecorder(), req)
+		got := <-l.queue
+		if got.User != tc.want.User || got.AuthMethod != tc.want.AuthMethod || got.Cluster != tc.want.Cluster ||
+			got.Edge != tc.want.Edge || got.Verb != tc.want.Verb || got.Code != tc.want.Code || got.Action != tc.want.Action {
+			t.Errorf("%s %s: event = %+v, want %+v", tc.method, tc.target, got, tc.want)
+		}
+		if got.SourceIP != "192.0.2.1" || got.Method != tc.method || got.Path != req.URL.Path {
+			t.Errorf("%s %s: request fields = %q %q %q", tc.method, tc.target, got.SourceIP, got.Method, got.Path)
+		}
+	}
+
+	// Health probes and the portal are not audited.
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if len(l.queue) != 0 {
+		t.Error("/healthz was audited")
+	}
+}
+
+func TestPowerAction(t *testing.T) {
+	for _, tc := range []struct{ cmd, want string }{
+		{"sudo systemctl reboot", ActionEdgeReboot},
+		{"sudo -n systemctl poweroff", ActionEdgeShutdown},
+		{"/usr/bin/sudo /sbin/reboot", ActionEdgeReboot},
+		{"systemctl --no-wall halt", ActionEdgeShutdown},
+		{"shutdown -h now", ActionEdgeShutdown},
+		{"sudo shutdown -r +1", ActionEdgeReboot},
+		{"systemctl status sshd", ""},
+		{"uptime", ""},
+		{"", ""},
+	} {
+		if got := powerAction(tc.cmd); got != tc.want {
+			t.Errorf("powerAction(%q) = %q, want %q", tc.cmd, got, tc.want)
+		}
+	}
+}
+
+func TestHandlerRedactsCredentials(t *testing.T) {
+	l := NewLogger()
+	h := l.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for _, tc := range []struct{ query, want string }{
+		{"kedge-expires=1767225600&kedge-signature=c2lnbmF0dXJl&command=ls", "kedge-expires=REDACTED&kedge-signature=REDACTED&command=ls"},
+		{"watch=true&access_token=eyJhbGciOi&Token=secret", "watch=true&access_token=REDACTED&Token=REDACTED"},
+		{"kedge%2Dsignature=c2ln", "kedge%2Dsignature=REDACTED"},
+		{"labelSelector=app%3Dweb", "labelSelector=app%3Dweb"},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/services/providers/edges/edgeproxy/clusters/abc/apis/edges.kedge.faros.sh/v1alpha1/linuxservers/box/ssh?"+tc.query, nil)
+		h.ServeHTTP(httptest.NewRecorder(), req)
+		if got := (<-l.queue).Query; got != tc.want {
+			t.Errorf("query %q recorded as %q, want %q", tc.query, got, tc.want)
+		}
+	}
+}
+
+func TestRecordDropsWhenFull(t *testing.T) {
+	l := NewLogger()
+	for range queueSize + 1 {
+		l.Record(Event{})
+	}
+	if len(l.queue) != queueSize {
+		t.Errorf("queued %d events, want %d", len(l.queue), queueSize)
+	}
+}
+
+func TestSinks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	file, err := ParseSink("file:" + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	batches := make(chan []Event, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var events []Event
+		if err := json.NewDecoder(r.Body).Decode(&events); err != nil {
+			t.Errorf("decoding webhook batch: %v", err)
+		}
+		batches <- events
+	}))
+	defer srv.Close()
+	webhook, err := ParseSink("webhook:" + srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewLogger(file, webhook)
+	ctx, cancel := context.WithCancel(context.Background())
+	go l.Run(ctx)
+	l.Record(Event{User: "alice", Verb: "get"})
+	l.Record(Event{User: "bob", Verb: "delete"})
+
+	var got []Event
+	for len(got) < 2 {
+		select {
+		case batch := <-batches:
+			got = append(got, batch...)
+		case <-time.After(5 * time.Second):
+			t.Fatal("webhook received no events")
+		}
+	}
+	cancel()
+	<-l.Done()
+	if got[0].User != "alice" || got[1].User != "bob" {
+		t.Errorf("webhook events = %+v", got)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close() //nolint:errcheck
+	var users []string
+	for scanner := bufio.NewScanner(f); scanner.Scan(); {
+		var ev Event
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			t.Fatalf("line %q: %v", scanner.Text(), err)
+		}
+		users = append(users, ev.User)
+	}
+	if len(users) != 2 || users[0] != "alice" || users[1] != "bob" {
+		t.Errorf("file events = %q", users)
+	}
+
+	for _, spec := range []string{"", "syslog", "stdout:x", "file:", "webhook:ftp://x", "webhook:/relative"} {
+		if _, err := ParseSink(spec); err == nil {
+			t.Errorf("ParseSink(%q) succeeded", spec)
+		}
+	}
+}
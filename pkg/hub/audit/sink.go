@@ -0,0 +1,136 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Sink is where a Logger writes events.
+type Sink interface {
+	// Name identifies the sink in logs and metrics.
+	Name() string
+	// Write writes a batch of events.
+	Write(ctx context.Context, events []Event) error
+	// Close flushes and releases the sink.
+	Close() error
+}
+
+// ParseSink returns the sink spec describes:
+//
+//	stdout             JSON lines on standard output
+//	file:<path>        JSON lines appended to <path>, created mode 0600
+//	webhook:<url>      each batch POSTed to <url> as a JSON array
+func ParseSink(spec string) (Sink, error) {
+	kind, arg, _ := strings.Cut(spec, ":")
+	switch kind {
+	case "stdout":
+		if arg != "" {
+			return nil, fmt.Errorf("audit sink %q: stdout takes no argument", spec)
+		}
+		return &jsonLinesSink{name: "stdout", w: os.Stdout}, nil
+	case "file":
+		if arg == "" {
+			return nil, fmt.Errorf("audit sink %q: file needs a path", spec)
+		}
+		f, err := os.OpenFile(arg, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("audit sink %q: %w", spec, err)
+		}
+		return &jsonLinesSink{name: "file", w: f, closer: f}, nil
+	case "webhook":
+		u, err := url.Parse(arg)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("audit sink %q: webhook needs an absolute http(s) URL", spec)
+		}
+		return &webhookSink{url: u.String(), client: &http.Client{}}, nil
+	}
+	return nil, fmt.Errorf("audit sink %q: want stdout, file:<path> or webhook:<url>", spec)
+}
+
+// jsonLinesSink writes one JSON object per event and line.
+type jsonLinesSink struct {
+	name   string
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+}
+
+func (s *jsonLinesSink) Name() string { return s.name }
+
+func (s *jsonLinesSink) Write(_ context.Context, events []Event) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, ev := range events {
+		if err := enc.Encode(ev); err != nil {
+			return err
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.w.Write(buf.Bytes())
+	return err
+}
+
+func (s *jsonLinesSink) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+// webhookSink POSTs each batch as a JSON array. A batch the webhook does not
+// accept with a 2xx is dropped; the error is logged and counted.
+type webhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func (s *webhookSink) Name() string { return "webhook" }
+
+func (s *webhookSink) Write(ctx context.Context, events []Event) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
+
+func (s *webhookSink) Close() error { return nil }
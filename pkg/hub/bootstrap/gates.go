@@ -0,0 +1,101 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+
+	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+)
+
+// GateTimeout bounds how long a readiness gate waits for what it checks. A
+// gate that times out fails bootstrap, and the hub restarts.
+const GateTimeout = 3 * time.Minute
+
+// gateInterval is how often a gate checks again.
+const gateInterval = time.Second
+
+// PollGate calls check until it returns nil, for at most GateTimeout. The
+// error of the last check is returned on timeout and logged, at V(2), while
+// waiting.
+func PollGate(ctx context.Context, name string, check func(context.Context) error) error {
+	logger := klog.FromContext(ctx)
+	var last error
+	err := wait.PollUntilContextTimeout(ctx, gateInterval, GateTimeout, true, func(ctx context.Context) (bool, error) {
+		if last = check(ctx); last != nil {
+			logger.V(2).Info("Waiting for readiness gate", "gate", name, "reason", last.Error())
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil && last != nil {
+		return fmt.Errorf("%w (last: %w)", err, last)
+	}
+	return err
+}
+
+// WaitForServerReady waits until the API server config points at answers its
+// /readyz with ok. For embedded kcp that includes its post-start hooks, which
+// bootstrap the root workspace and the batteries. A workspace path in the
+// config's host (/clusters/root) is dropped: /readyz is served at the root.
+func WaitForServerReady(ctx context.Context, config *rest.Config) error {
+	serverConfig := rest.CopyConfig(config)
+	u, err := url.Parse(serverConfig.Host)
+	if err != nil {
+		return fmt.Errorf("parsing server %q: %w", serverConfig.Host, err)
+	}
+	u.Path, u.RawPath = "", ""
+	serverConfig.Host = u.String()
+	client, err := kubernetes.NewForConfig(serverConfig)
+	if err != nil {
+		return fmt.Errorf("creating kubernetes client: %w", err)
+	}
+	return PollGate(ctx, "server", func(ctx context.Context) error {
+		body, err := client.Discovery().RESTClient().Get().AbsPath("/readyz").DoRaw(ctx)
+		if err != nil {
+			return fmt.Errorf("/readyz: %w: %s", err, body)
+		}
+		return nil
+	})
+}
+
+// WaitForUserAPI waits until Users can be listed in the cluster config points
+// at: the users.tenants.kedge.faros.sh CRD is established or, with kcp, the
+// tenants.kedge.faros.sh APIExport is bound and served. Until then the first
+// sign-ups fail.
+func WaitForUserAPI(ctx context.Context, config *rest.Config) error {
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("creating dynamic client: %w", err)
+	}
+	return PollGate(ctx, "users", func(ctx context.Context) error {
+		if _, err := client.Resource(kedgeclient.UserGVR).List(ctx, metav1.ListOptions{Limit: 1}); err != nil {
+			return fmt.Errorf("listing %s: %w", kedgeclient.UserGVR.GroupResource(), err)
+		}
+		return nil
+	})
+}
@@ -93,6 +93,7 @@ func InstallCRDs(ctx context.Context, config *rest.Config) error {
 		"memberships.tenants.kedge.faros.sh",
 		"usermembershipindices.tenants.kedge.faros.sh",
 		"userpreferences.tenants.kedge.faros.sh",
+		"personalaccesstokens.tenants.kedge.faros.sh",
 		"catalogentries.providers.kedge.faros.sh",
 	}
 
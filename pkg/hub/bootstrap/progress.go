@@ -0,0 +1,304 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+// StatusConfigMapName is the ConfigMap recording bootstrap progress, in the
+// default namespace of the cluster the hub bootstraps (the kcp root workspace
+// when kcp is configured). It lives next to what it describes, so wiping
+// kcp's data wipes the record too.
+const StatusConfigMapName = "kedge-hub-bootstrap"
+
+const (
+	statusNamespace = "default"
+	statusKey       = "status"
+)
+
+// ConditionReady is the condition that turns True once every step has
+// succeeded. Each step adds a condition of its own, typed by its name.
+const ConditionReady = "Ready"
+
+// Condition reasons.
+const (
+	ReasonPending    = "Pending"
+	ReasonInProgress = "InProgress"
+	ReasonSucceeded  = "Succeeded"
+	ReasonFailed     = "Failed"
+)
+
+// saveTimeout bounds recording progress, which also happens after the
+// bootstrap context has been cancelled.
+const saveTimeout = 10 * time.Second
+
+// Step is one tracked stage of hub bootstrap. Run must be idempotent: a step
+// that failed, or whose success did not get recorded, runs again on restart.
+type Step struct {
+	// Name is the type of the step's condition, e.g. "Workspaces".
+	Name string
+	Run  func(context.Context) error
+	// Gate marks a step that checks live state instead of changing it, such
+	// as an APIExport becoming available. A gate runs on every start, even
+	// when a previous run recorded it as succeeded.
+	Gate bool
+}
+
+// Status is the recorded bootstrap progress.
+type Status struct {
+	// Fingerprint identifies the hub build and configuration that made the
+	// progress. Progress recorded under another fingerprint is discarded, so
+	// an upgrade runs every step against the new schemas.
+	Fingerprint string             `json:"fingerprint"`
+	Conditions  []metav1.Condition `json:"conditions"`
+}
+
+// StatusStore persists Status between hub restarts.
+type StatusStore interface {
+	// Load returns the recorded status, or nil if there is none.
+	Load(ctx context.Context) (*Status, error)
+	Save(ctx context.Context, status *Status) error
+}
+
+// Fingerprint digests values and every file in trees, for Status.Fingerprint.
+func Fingerprint(values []string, trees ...fs.FS) string {
+	h := sha256.New()
+	for _, v := range values {
+		_, _ = fmt.Fprintf(h, "%d:%s\n", len(v), v)
+	}
+	for _, tree := range trees {
+		_ = fs.WalkDir(tree, ".", func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			data, err := fs.ReadFile(tree, path)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(h, "%s:%d\n", path, len(data))
+			_, _ = h.Write(data)
+			return nil
+		})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// CRDsFingerprint digests the embedded CRDs InstallCRDs applies.
+func CRDsFingerprint() string {
+	return Fingerprint(nil, crdFS)
+}
+
+// Progress runs the bootstrap steps in order, recording each one's outcome
+// as a condition, and serves the conditions on /readyz.
+type Progress struct {
+	store       StatusStore
+	fingerprint string
+	steps       []Step
+
+	mu     sync.RWMutex
+	status Status
+}
+
+// NewProgress returns the progress of steps, all pending. store may be nil,
+// in which case progress is kept in memory only and every restart runs
+// every step.
+func NewProgress(store StatusStore, fingerprint string, steps []Step) *Progress {
+	p := &Progress{store: store, fingerprint: fingerprint, steps: steps}
+	p.status.Fingerprint = fingerprint
+	for _, step := range steps {
+		meta.SetStatusCondition(&p.status.Conditions, metav1.Condition{
+			Type: step.Name, Status: metav1.ConditionFalse, Reason: ReasonPending,
+		})
+	}
+	meta.SetStatusCondition(&p.status.Conditions, metav1.Condition{
+		Type: ConditionReady, Status: metav1.ConditionFalse, Reason: ReasonPending,
+	})
+	return p
+}
+
+// Run runs the steps in order, skipping those other than gates that a
+// previous run under the same fingerprint recorded as succeeded, and stops at
+// the first that fails.
+func (p *Progress) Run(ctx context.Context) error {
+	logger := klog.FromContext(ctx)
+
+	var recorded []metav1.Condition
+	if p.store != nil {
+		status, err := p.store.Load(ctx)
+		switch {
+		case err != nil:
+			logger.Error(err, "Reading bootstrap progress failed; running every step")
+		case status != nil && status.Fingerprint != p.fingerprint:
+			logger.Info("Bootstrap progress was recorded by another hub build or configuration; running every step")
+		case status != nil:
+			recorded = status.Conditions
+		}
+	}
+
+	for _, step := range p.steps {
+		if c := meta.FindStatusCondition(recorded, step.Name); !step.Gate && c != nil && c.Status == metav1.ConditionTrue {
+			logger.Info("Bootstrap step already complete, skipping", "step", step.Name)
+			p.update(ctx, *c)
+			continue
+		}
+
+		logger.Info("Running bootstrap step", "step", step.Name)
+		p.update(ctx, metav1.Condition{Type: step.Name, Status: metav1.ConditionFalse, Reason: ReasonInProgress})
+		if err := step.Run(ctx); err != nil {
+			p.update(ctx,
+				metav1.Condition{Type: step.Name, Status: metav1.ConditionFalse, Reason: ReasonFailed, Message: err.Error()},
+				metav1.Condition{Type: ConditionReady, Status: metav1.ConditionFalse, Reason: ReasonFailed,
+					Message: fmt.Sprintf("Step %s failed; the next start resumes there.", step.Name)},
+			)
+			return fmt.Errorf("bootstrap step %s: %w", step.Name, err)
+		}
+		p.update(ctx, metav1.Condition{Type: step.Name, Status: metav1.ConditionTrue, Reason: ReasonSucceeded})
+	}
+
+	p.update(ctx, metav1.Condition{Type: ConditionReady, Status: metav1.ConditionTrue, Reason: ReasonSucceeded})
+	logger.Info("Hub bootstrap complete")
+	return nil
+}
+
+// update sets conditions and records the result. A failure to record is
+// logged rather than returned: the steps are idempotent, so the worst a lost
+// record costs is re-running them on the next start.
+func (p *Progress) update(ctx context.Context, conditions ...metav1.Condition) {
+	p.mu.Lock()
+	for _, c := range conditions {
+		meta.SetStatusCondition(&p.status.Conditions, c)
+	}
+	status := p.snapshotLocked()
+	p.mu.Unlock()
+
+	if p.store == nil {
+		return
+	}
+	saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), saveTimeout)
+	defer cancel()
+	if err := p.store.Save(saveCtx, status); err != nil {
+		klog.FromContext(ctx).Error(err, "Recording bootstrap progress failed")
+	}
+}
+
+// Status returns a copy of the current progress.
+func (p *Progress) Status() *Status {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.snapshotLocked()
+}
+
+func (p *Progress) snapshotLocked() *Status {
+	return &Status{
+		Fingerprint: p.status.Fingerprint,
+		Conditions:  append([]metav1.Condition(nil), p.status.Conditions...),
+	}
+}
+
+// ServeHTTP answers /readyz: 200 once every step has succeeded and 503
+// before, with the step conditions as JSON either way.
+func (p *Progress) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	status := p.Status()
+	ready := meta.IsStatusConditionTrue(status.Conditions, ConditionReady)
+	w.Header().Set("Content-Type", "application/json")
+	if ready {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(struct {
+		Ready      bool               `json:"ready"`
+		Conditions []metav1.Condition `json:"conditions"`
+	}{ready, status.Conditions})
+}
+
+// configMapStore keeps Status as JSON in the StatusConfigMapName ConfigMap.
+type configMapStore struct {
+	client kubernetes.Interface
+}
+
+// NewConfigMapStore returns a StatusStore backed by a ConfigMap in the
+// cluster config points at.
+func NewConfigMapStore(config *rest.Config) (StatusStore, error) {
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("creating kubernetes client: %w", err)
+	}
+	return &configMapStore{client: client}, nil
+}
+
+func (s *configMapStore) Load(ctx context.Context) (*Status, error) {
+	cm, err := s.client.CoreV1().ConfigMaps(statusNamespace).Get(ctx, StatusConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting ConfigMap %s/%s: %w", statusNamespace, StatusConfigMapName, err)
+	}
+	var status Status
+	if err := json.Unmarshal([]byte(cm.Data[statusKey]), &status); err != nil {
+		return nil, fmt.Errorf("decoding ConfigMap %s/%s: %w", statusNamespace, StatusConfigMapName, err)
+	}
+	return &status, nil
+}
+
+func (s *configMapStore) Save(ctx context.Context, status *Status) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	cms := s.client.CoreV1().ConfigMaps(statusNamespace)
+	cm, err := cms.Get(ctx, StatusConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: StatusConfigMapName, Namespace: statusNamespace},
+			Data:       map[string]string{statusKey: string(data)},
+		}
+		if _, err := cms.Create(ctx, cm, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("creating ConfigMap %s/%s: %w", statusNamespace, StatusConfigMapName, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("getting ConfigMap %s/%s: %w", statusNamespace, StatusConfigMapName, err)
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[statusKey] = string(data)
+	if _, err := cms.Update(ctx, cm, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("updating ConfigMap %s/%s: %w", statusNamespace, StatusConfigMapName, err)
+	}
+	return nil
+}
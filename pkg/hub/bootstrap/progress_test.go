@@ -0,0 +1,135 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestProgressResumesAtFailedStep(t *testing.T) {
+	store := &configMapStore{client: fake.NewClientset()}
+	var ran []string
+	failSchemas := true
+	steps := func() []Step {
+		step := func(name string) Step {
+			return Step{Name: name, Run: func(context.Context) error {
+				ran = append(ran, name)
+				if name == "Schemas" && failSchemas {
+					return errors.New("apiexport not ready")
+				}
+				return nil
+			}}
+		}
+		return []Step{step("Workspaces"), step("Schemas"), step("Exports")}
+	}
+	readyz := func(p *Progress) (int, string) {
+		rec := httptest.NewRecorder()
+		p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		return rec.Code, rec.Body.String()
+	}
+
+	p := NewProgress(store, "v1", steps())
+	if code, body := readyz(p); code != http.StatusServiceUnavailable || !strings.Contains(body, `"reason":"Pending"`) {
+		t.Errorf("readyz before run = %d %s", code, body)
+	}
+	if err := p.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "Schemas") {
+		t.Fatalf("run = %v, want Schemas failure", err)
+	}
+	if strings.Join(ran, ",") != "Workspaces,Schemas" {
+		t.Errorf("first run ran %v", ran)
+	}
+	if c := meta.FindStatusCondition(p.Status().Conditions, "Schemas"); c == nil || c.Reason != ReasonFailed || c.Message != "apiexport not ready" {
+		t.Errorf("Schemas condition = %+v", c)
+	}
+
+	// A restart resumes at the failed step.
+	ran, failSchemas = nil, false
+	p = NewProgress(store, "v1", steps())
+	if err := p.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(ran, ",") != "Schemas,Exports" {
+		t.Errorf("resumed run ran %v", ran)
+	}
+	if code, body := readyz(p); code != http.StatusOK || !strings.Contains(body, `"ready":true`) {
+		t.Errorf("readyz after run = %d %s", code, body)
+	}
+	recorded, err := store.Load(context.Background())
+	if err != nil || recorded == nil {
+		t.Fatalf("load = %v, %v", recorded, err)
+	}
+	for _, typ := range []string{"Workspaces", "Schemas", "Exports", ConditionReady} {
+		if !meta.IsStatusConditionTrue(recorded.Conditions, typ) {
+			t.Errorf("recorded %s not True: %+v", typ, recorded.Conditions)
+		}
+	}
+
+	// Another build or configuration runs every step again.
+	ran = nil
+	if err := NewProgress(store, "v2", steps()).Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(ran) != 3 {
+		t.Errorf("run under a new fingerprint ran %v", ran)
+	}
+}
+
+func TestProgressRerunsGates(t *testing.T) {
+	store := &configMapStore{client: fake.NewClientset()}
+	var ran []string
+	steps := []Step{
+		{Name: "Exports", Run: func(context.Context) error { ran = append(ran, "Exports"); return nil }},
+		{Name: "UserAPI", Gate: true, Run: func(context.Context) error { ran = append(ran, "UserAPI"); return nil }},
+	}
+	for range 2 {
+		if err := NewProgress(store, "v1", steps).Run(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The recorded step is skipped on restart; the gate checks again.
+	if strings.Join(ran, ",") != "Exports,UserAPI,UserAPI" {
+		t.Errorf("ran %v", ran)
+	}
+}
+
+func TestProgressWithoutStore(t *testing.T) {
+	p := NewProgress(nil, "", []Step{{Name: "CRDs", Run: func(context.Context) error { return nil }}})
+	if err := p.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if c := meta.FindStatusCondition(p.Status().Conditions, "CRDs"); c == nil || c.Status != metav1.ConditionTrue {
+		t.Errorf("CRDs condition = %+v", c)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	if Fingerprint([]string{"a", "b"}) == Fingerprint([]string{"ab"}) {
+		t.Error("values are not delimited")
+	}
+	if CRDsFingerprint() != CRDsFingerprint() || CRDsFingerprint() == Fingerprint(nil) {
+		t.Error("CRDs fingerprint is not a stable digest of the CRDs")
+	}
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package directory implements the directory sync: it maps the IdP
+// groups recorded on each User (status.groups, from the OIDC groups
+// claim) onto the organizations → teams → users hierarchy through
+// Organization.spec.groupBindings. See docs/organizations.md
+// §Directory sync.
+//
+// One controller, two reconcilers:
+//
+//   - User: computes the (Org, Workspace, role) grants the User's
+//     groups earn, writes the org-scope Membership CRs and the
+//     UserMembershipIndex rows for them, and revokes the grants it
+//     made earlier that no longer apply. Rows it writes carry
+//     source=directory; rows without a source are explicit grants and
+//     are never touched.
+//
+//   - Organization: binds each bound group as a kcp RBAC group in
+//     every child team Workspace it applies to, and enables the Org's
+//     spec.inheritedProviders in every child Workspace.
+//
+// Child Workspaces are not in our scheme, so both reconcilers also
+// requeue every resyncInterval to pick up Workspaces created since.
+package directory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	tenancyv1alpha1 "github.com/faroshq/faros-kedge/apis/tenancy/v1alpha1"
+)
+
+const (
+	// resyncInterval is how often both reconcilers re-run without a
+	// watch event, so Workspaces created since the last pass get their
+	// group grants and inherited providers.
+	resyncInterval = 5 * time.Minute
+
+	userControllerName = "directory-user"
+	orgControllerName  = "directory-organization"
+)
+
+// Reconciler is the shared receiver for the two directory-sync
+// reconcile entry points.
+type Reconciler struct {
+	client      client.Client
+	provisioner Provisioner
+	// providers resolves spec.inheritedProviders; nil disables
+	// provider inheritance.
+	providers ProviderLookup
+}
+
+// SetupWithManager registers the User and Organization reconcilers
+// with mgr. The User reconciler also watches Organization so editing
+// spec.groupBindings re-syncs every User that has groups.
+func SetupWithManager(mgr manager.Manager, provisioner Provisioner, lookup ProviderLookup) error {
+	r := &Reconciler{
+		client:      mgr.GetClient(),
+		provisioner: provisioner,
+		providers:   lookup,
+	}
+	klog.Info("Registering directory sync controller")
+
+	if err := builder.ControllerManagedBy(mgr).
+		Named(userControllerName).
+		For(&tenancyv1alpha1.User{}).
+		Watches(
+			&tenancyv1alpha1.Organization{},
+			handler.EnqueueRequestsFromMapFunc(r.mapOrganizationToUsers),
+		).
+		Complete(reconcile.Func(r.reconcileUser)); err != nil {
+		return fmt.Errorf("setting up user directory sync controller: %w", err)
+	}
+
+	if err := builder.ControllerManagedBy(mgr).
+		Named(orgControllerName).
+		For(&tenancyv1alpha1.Organization{}).
+		Complete(reconcile.Func(r.reconcileOrganization)); err != nil {
+		return fmt.Errorf("setting up organization directory sync controller: %w", err)
+	}
+	return nil
+}
+
+// NewManager constructs a controller-runtime manager bound to the
+// users workspace's rest.Config. Separate from the bootstrap and
+// soft-delete managers so a sync failure doesn't take their
+// workqueues down.
+func NewManager(cfg *rest.Config, scheme *runtime.Scheme) (manager.Manager, error) {
+	return manager.New(cfg, manager.Options{
+		Scheme: scheme,
+		Metrics: server.Options{
+			BindAddress: "0",
+		},
+		HealthProbeBindAddress: "0",
+	})
+}
+
+// mapOrganizationToUsers enqueues every User with directory groups.
+// Which of them the Organization's bindings affect depends on the old
+// bindings as well as the new ones, so all of them are re-synced; the
+// set is bounded by the users who logged in with a groups claim.
+func (r *Reconciler) mapOrganizationToUsers(ctx context.Context, _ client.Object) []reconcile.Request {
+	var users tenancyv1alpha1.UserList
+	if err := r.client.List(ctx, &users); err != nil {
+		klog.FromContext(ctx).Error(err, "Listing users for directory sync")
+		return nil
+	}
+	var reqs []reconcile.Request
+	for _, u := range users.Items {
+		if len(u.Status.Groups) == 0 {
+			continue
+		}
+		reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: u.Name}})
+	}
+	return reqs
+}
+
+// fetchUser returns (nil, nil) on NotFound.
+func (r *Reconciler) fetchUser(ctx context.Context, name string) (*tenancyv1alpha1.User, error) {
+	var user tenancyv1alpha1.User
+	if err := r.client.Get(ctx, types.NamespacedName{Name: name}, &user); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("getting User %q: %w", name, err)
+	}
+	return &user, nil
+}
+
+// activeChildWorkspaces lists the Org's child Workspaces that are not
+// in their soft-delete window. deleting holds the ones that are.
+func (r *Reconciler) activeChildWorkspaces(ctx context.Context, orgUUID string) (active []string, deleting map[string]bool, err error) {
+	children, err := r.provisioner.ListChildWorkspaces(ctx, orgUUID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing child workspaces of org %q: %w", orgUUID, err)
+	}
+	deleting = map[string]bool{}
+	for _, ws := range children {
+		_, present, err := r.provisioner.GetWorkspaceDeletionRequestedAt(ctx, orgUUID, ws)
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading deletion annotation of workspace %q in org %q: %w", ws, orgUUID, err)
+		}
+		if present {
+			deleting[ws] = true
+			continue
+		}
+		active = append(active, ws)
+	}
+	return active, deleting, nil
+}
+
+// bindingAppliesTo reports whether b covers child Workspace ws.
+func bindingAppliesTo(b tenancyv1alpha1.OrganizationGroupBinding, ws string) bool {
+	if len(b.Workspaces) == 0 {
+		return true
+	}
+	for _, w := range b.Workspaces {
+		if w == ws {
+			return true
+		}
+	}
+	return false
+}
+
+// syncsOrg reports whether the directory sync manages org: personal
+// Orgs have no group bindings and Orgs in their soft-delete window
+// belong to the soft-delete reconciler.
+func syncsOrg(org *tenancyv1alpha1.Organization) bool {
+	return !org.Spec.Personal && org.Status.DeletionRequestedAt == nil && org.DeletionTimestamp == nil
+}
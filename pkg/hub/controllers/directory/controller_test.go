@@ -0,0 +1,445 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package directory
+
+import (
+	"context"
+	"maps"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	tenancyv1alpha1 "github.com/faroshq/faros-kedge/apis/tenancy/v1alpha1"
+	"github.com/faroshq/faros-kedge/pkg/hub/kcp"
+	"github.com/faroshq/faros-kedge/pkg/hub/providers"
+)
+
+// ===== test doubles =====
+
+type workspaceKey struct{ Org, WS string }
+
+type fakeProvisioner struct {
+	mu sync.Mutex
+
+	// canned state
+	childWorkspaces map[string][]string                // orgUUID → list
+	deleting        map[workspaceKey]bool              // soft-deleted child workspaces
+	memberships     map[workspaceKey]string            // (org, user) → role
+	bound           map[workspaceKey][]string          // (org, ws) → bound providers
+	groupRoles      map[workspaceKey]map[string]string // last SyncChildWorkspaceGroupRoles
+	claims          map[string][]kcp.ProviderClaim     // binding name → claims
+
+	// call recording
+	deletedMemberships []workspaceKey
+	edgeProxyGrants    []string
+}
+
+func newFakeProvisioner() *fakeProvisioner {
+	return &fakeProvisioner{
+		childWorkspaces: map[string][]string{},
+		deleting:        map[workspaceKey]bool{},
+		memberships:     map[workspaceKey]string{},
+		bound:           map[workspaceKey][]string{},
+		groupRoles:      map[workspaceKey]map[string]string{},
+		claims:          map[string][]kcp.ProviderClaim{},
+	}
+}
+
+func (f *fakeProvisioner) EnsureOrgMembership(_ context.Context, orgUUID, userName, role string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.memberships[workspaceKey{orgUUID, userName}]; !ok {
+		f.memberships[workspaceKey{orgUUID, userName}] = role
+	}
+	return nil
+}
+
+func (f *fakeProvisioner) PatchOrgMembershipRole(_ context.Context, orgUUID, userName, role string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.memberships[workspaceKey{orgUUID, userName}] = role
+	return nil
+}
+
+func (f *fakeProvisioner) DeleteOrgMembership(_ context.Context, orgUUID, userName string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.memberships, workspaceKey{orgUUID, userName})
+	f.deletedMemberships = append(f.deletedMemberships, workspaceKey{orgUUID, userName})
+	return nil
+}
+
+func (f *fakeProvisioner) ListChildWorkspaces(_ context.Context, orgUUID string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.childWorkspaces[orgUUID]...), nil
+}
+
+func (f *fakeProvisioner) GetWorkspaceDisplayName(_ context.Context, _, wsUUID string) (string, error) {
+	return wsUUID + "-dn", nil
+}
+
+func (f *fakeProvisioner) GetWorkspaceDeletionRequestedAt(_ context.Context, orgUUID, wsUUID string) (*time.Time, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.deleting[workspaceKey{orgUUID, wsUUID}] {
+		return nil, false, nil
+	}
+	t := time.Now()
+	return &t, true, nil
+}
+
+func (f *fakeProvisioner) SyncChildWorkspaceGroupRoles(_ context.Context, orgUUID, wsUUID string, roles map[string]string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.groupRoles[workspaceKey{orgUUID, wsUUID}] = maps.Clone(roles)
+	return nil
+}
+
+func (f *fakeProvisioner) ListProviderAPIBindings(_ context.Context, orgUUID, wsUUID string) (map[string]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := map[string]string{}
+	for _, name := range f.bound[workspaceKey{orgUUID, wsUUID}] {
+		out[name] = name
+	}
+	return out, nil
+}
+
+func (f *fakeProvisioner) EnsureProviderAPIBinding(_ context.Context, orgUUID, wsUUID, bindingName, _, _ string, claims []kcp.ProviderClaim) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := workspaceKey{orgUUID, wsUUID}
+	f.bound[key] = append(f.bound[key], bindingName)
+	f.claims[bindingName] = claims
+	return nil
+}
+
+func (f *fakeProvisioner) EnsureProviderEdgeProxyGrant(_ context.Context, _, wsUUID, providerName, subject string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.edgeProxyGrants = append(f.edgeProxyGrants, wsUUID+"/"+providerName+"/"+subject)
+	return nil
+}
+
+type fakeLookup map[string]providers.Provider
+
+func (l fakeLookup) Get(name string) (providers.Provider, bool) {
+	p, ok := l[name]
+	return p, ok
+}
+
+// ===== test fixtures =====
+
+func newReconciler(t *testing.T, prov Provisioner, lookup ProviderLookup, objects ...client.Object) (*Reconciler, client.Client) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := tenancyv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding tenancy scheme: %v", err)
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&tenancyv1alpha1.User{}, &tenancyv1alpha1.Organization{}).
+		Build()
+	return &Reconciler{client: c, provisioner: prov, providers: lookup}, c
+}
+
+func newUser(name string, groups ...string) *tenancyv1alpha1.User {
+	u := &tenancyv1alpha1.User{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	u.Status.Groups = groups
+	return u
+}
+
+func newOrg(name string, bindings ...tenancyv1alpha1.OrganizationGroupBinding) *tenancyv1alpha1.Organization {
+	return &tenancyv1alpha1.Organization{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: tenancyv1alpha1.OrganizationSpec{
+			DisplayName:   name + "-dn",
+			GroupBindings: bindings,
+		},
+	}
+}
+
+func getUMI(t *testing.T, c client.Client, user string) []tenancyv1alpha1.MembershipIndexEntry {
+	t.Helper()
+	var idx tenancyv1alpha1.UserMembershipIndex
+	if err := c.Get(context.Background(), types.NamespacedName{Name: user}, &idx); err != nil {
+		t.Fatalf("get UMI: %v", err)
+	}
+	return idx.Spec.Entries
+}
+
+func findEntry(entries []tenancyv1alpha1.MembershipIndexEntry, org, ws string) *tenancyv1alpha1.MembershipIndexEntry {
+	for i := range entries {
+		if entries[i].OrgUUID == org && entries[i].WorkspaceUUID == ws {
+			return &entries[i]
+		}
+	}
+	return nil
+}
+
+func reconcileUser(t *testing.T, r *Reconciler, name string) {
+	t.Helper()
+	res, err := r.reconcileUser(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: name}})
+	if err != nil {
+		t.Fatalf("reconcileUser: %v", err)
+	}
+	if res.RequeueAfter != resyncInterval {
+		t.Errorf("RequeueAfter: got %v, want %v", res.RequeueAfter, resyncInterval)
+	}
+}
+
+// ===== User branch tests =====
+
+func TestUserSync_OrgWideBindingGrantsOrgAndEveryTeam(t *testing.T) {
+	org := newOrg("acme", tenancyv1alpha1.OrganizationGroupBinding{Group: "eng", Role: tenancyv1alpha1.MembershipRoleMember})
+	prov := newFakeProvisioner()
+	prov.childWorkspaces["acme"] = []string{"team-a", "team-b"}
+	r, c := newReconciler(t, prov, nil, newUser("alice", "eng"), org)
+
+	reconcileUser(t, r, "alice")
+
+	if got := prov.memberships[workspaceKey{"acme", "alice"}]; got != tenancyv1alpha1.MembershipRoleMember {
+		t.Errorf("org Membership role: got %q, want member", got)
+	}
+	entries := getUMI(t, c, "alice")
+	if len(entries) != 3 {
+		t.Fatalf("UMI entries: got %d, want 3: %#v", len(entries), entries)
+	}
+	for _, ws := range []string{"", "team-a", "team-b"} {
+		e := findEntry(entries, "acme", ws)
+		if e == nil {
+			t.Fatalf("missing UMI row for workspace %q", ws)
+		}
+		if e.Source != tenancyv1alpha1.MembershipSourceDirectory || e.OrgDisplayName != "acme-dn" {
+			t.Errorf("row %q: got %#v", ws, e)
+		}
+	}
+	if e := findEntry(entries, "acme", "team-a"); e.WorkspaceDisplayName != "team-a-dn" {
+		t.Errorf("WorkspaceDisplayName: got %q", e.WorkspaceDisplayName)
+	}
+}
+
+func TestUserSync_TeamBindingAndAdminWins(t *testing.T) {
+	org := newOrg("acme",
+		tenancyv1alpha1.OrganizationGroupBinding{Group: "eng", Role: tenancyv1alpha1.MembershipRoleMember},
+		tenancyv1alpha1.OrganizationGroupBinding{Group: "team-a-leads", Role: tenancyv1alpha1.MembershipRoleAdmin, Workspaces: []string{"team-a"}},
+	)
+	prov := newFakeProvisioner()
+	prov.childWorkspaces["acme"] = []string{"team-a", "team-b"}
+	r, c := newReconciler(t, prov, nil, newUser("alice", "eng", "team-a-leads"), org)
+
+	reconcileUser(t, r, "alice")
+
+	entries := getUMI(t, c, "alice")
+	if e := findEntry(entries, "acme", "team-a"); e == nil || e.Role != tenancyv1alpha1.MembershipRoleAdmin {
+		t.Errorf("team-a row: got %#v, want admin", e)
+	}
+	if e := findEntry(entries, "acme", "team-b"); e == nil || e.Role != tenancyv1alpha1.MembershipRoleMember {
+		t.Errorf("team-b row: got %#v, want member", e)
+	}
+	if e := findEntry(entries, "acme", ""); e == nil || e.Role != tenancyv1alpha1.MembershipRoleMember {
+		t.Errorf("org row: got %#v, want member", e)
+	}
+}
+
+func TestUserSync_LeavingGroupRevokesOnlyDirectoryRows(t *testing.T) {
+	org := newOrg("acme", tenancyv1alpha1.OrganizationGroupBinding{Group: "eng", Role: tenancyv1alpha1.MembershipRoleMember})
+	umi := &tenancyv1alpha1.UserMembershipIndex{
+		ObjectMeta: metav1.ObjectMeta{Name: "alice"},
+		Spec: tenancyv1alpha1.UserMembershipIndexSpec{Entries: []tenancyv1alpha1.MembershipIndexEntry{
+			{OrgUUID: "acme", Role: tenancyv1alpha1.MembershipRoleMember, Source: tenancyv1alpha1.MembershipSourceDirectory},
+			{OrgUUID: "acme", WorkspaceUUID: "team-a", Role: tenancyv1alpha1.MembershipRoleMember, Source: tenancyv1alpha1.MembershipSourceDirectory},
+			{OrgUUID: "acme", WorkspaceUUID: "team-b", Role: tenancyv1alpha1.MembershipRoleAdmin},
+		}},
+	}
+	prov := newFakeProvisioner()
+	prov.childWorkspaces["acme"] = []string{"team-a", "team-b"}
+	prov.memberships[workspaceKey{"acme", "alice"}] = tenancyv1alpha1.MembershipRoleMember
+	r, c := newReconciler(t, prov, nil, newUser("alice" /* no longer in eng */), org, umi)
+
+	reconcileUser(t, r, "alice")
+
+	entries := getUMI(t, c, "alice")
+	if len(entries) != 1 || entries[0].WorkspaceUUID != "team-b" {
+		t.Fatalf("UMI entries: got %#v, want only the explicit team-b row", entries)
+	}
+	if _, ok := prov.memberships[workspaceKey{"acme", "alice"}]; ok {
+		t.Errorf("org Membership not revoked")
+	}
+}
+
+func TestUserSync_ExplicitGrantIsNotTakenOver(t *testing.T) {
+	org := newOrg("acme", tenancyv1alpha1.OrganizationGroupBinding{Group: "eng", Role: tenancyv1alpha1.MembershipRoleMember})
+	umi := &tenancyv1alpha1.UserMembershipIndex{
+		ObjectMeta: metav1.ObjectMeta{Name: "alice"},
+		Spec: tenancyv1alpha1.UserMembershipIndexSpec{Entries: []tenancyv1alpha1.MembershipIndexEntry{
+			{OrgUUID: "acme", Role: tenancyv1alpha1.MembershipRoleAdmin},
+		}},
+	}
+	prov := newFakeProvisioner()
+	prov.memberships[workspaceKey{"acme", "alice"}] = tenancyv1alpha1.MembershipRoleAdmin
+	r, c := newReconciler(t, prov, nil, newUser("alice", "eng"), org, umi)
+
+	reconcileUser(t, r, "alice")
+
+	entries := getUMI(t, c, "alice")
+	if len(entries) != 1 || entries[0].Role != tenancyv1alpha1.MembershipRoleAdmin || entries[0].Source != "" {
+		t.Fatalf("UMI entries: got %#v, want the explicit admin row untouched", entries)
+	}
+	if got := prov.memberships[workspaceKey{"acme", "alice"}]; got != tenancyv1alpha1.MembershipRoleAdmin {
+		t.Errorf("Membership role: got %q, want admin", got)
+	}
+}
+
+func TestUserSync_SoftDeletedScopesAreFrozen(t *testing.T) {
+	deletedOrg := newOrg("gone", tenancyv1alpha1.OrganizationGroupBinding{Group: "eng", Role: tenancyv1alpha1.MembershipRoleMember})
+	now := metav1.Now()
+	deletedOrg.Status.DeletionRequestedAt = &now
+	org := newOrg("acme", tenancyv1alpha1.OrganizationGroupBinding{Group: "eng", Role: tenancyv1alpha1.MembershipRoleMember})
+	personal := newOrg("alice-personal", tenancyv1alpha1.OrganizationGroupBinding{Group: "eng", Role: tenancyv1alpha1.MembershipRoleMember})
+	personal.Spec.Personal = true
+	umi := &tenancyv1alpha1.UserMembershipIndex{
+		ObjectMeta: metav1.ObjectMeta{Name: "alice"},
+		Spec: tenancyv1alpha1.UserMembershipIndexSpec{Entries: []tenancyv1alpha1.MembershipIndexEntry{
+			{OrgUUID: "gone", Role: tenancyv1alpha1.MembershipRoleMember, Source: tenancyv1alpha1.MembershipSourceDirectory},
+			{OrgUUID: "acme", WorkspaceUUID: "team-a", Role: tenancyv1alpha1.MembershipRoleMember, Source: tenancyv1alpha1.MembershipSourceDirectory},
+		}},
+	}
+	prov := newFakeProvisioner()
+	prov.childWorkspaces["acme"] = []string{"team-a"}
+	prov.deleting[workspaceKey{"acme", "team-a"}] = true
+	r, c := newReconciler(t, prov, nil, newUser("alice", "eng"), deletedOrg, org, personal, umi)
+
+	reconcileUser(t, r, "alice")
+
+	entries := getUMI(t, c, "alice")
+	if findEntry(entries, "gone", "") == nil {
+		t.Errorf("row of soft-deleted org was revoked")
+	}
+	if findEntry(entries, "acme", "team-a") == nil {
+		t.Errorf("row of soft-deleted workspace was revoked")
+	}
+	if findEntry(entries, "acme", "") == nil {
+		t.Errorf("org row for acme not granted")
+	}
+	if findEntry(entries, "alice-personal", "") != nil {
+		t.Errorf("binding on a personal org was honoured")
+	}
+	if len(prov.deletedMemberships) != 0 {
+		t.Errorf("DeleteOrgMembership called: %v", prov.deletedMemberships)
+	}
+}
+
+// ===== Organization branch tests =====
+
+func TestOrgSync_BindsGroupsPerWorkspace(t *testing.T) {
+	org := newOrg("acme",
+		tenancyv1alpha1.OrganizationGroupBinding{Group: "eng", Role: tenancyv1alpha1.MembershipRoleMember},
+		tenancyv1alpha1.OrganizationGroupBinding{Group: "team-a-leads", Role: tenancyv1alpha1.MembershipRoleAdmin, Workspaces: []string{"team-a"}},
+	)
+	prov := newFakeProvisioner()
+	prov.childWorkspaces["acme"] = []string{"team-a", "team-b"}
+	r, _ := newReconciler(t, prov, nil, org)
+
+	if _, err := r.reconcileOrganization(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "acme"}}); err != nil {
+		t.Fatalf("reconcileOrganization: %v", err)
+	}
+	admin := tenancyv1alpha1.WorkspaceRoleAdmin
+	if got, want := prov.groupRoles[workspaceKey{"acme", "team-a"}], map[string]string{"eng": admin, "team-a-leads": admin}; !reflect.DeepEqual(got, want) {
+		t.Errorf("team-a groups: got %v, want %v", got, want)
+	}
+	if got, want := prov.groupRoles[workspaceKey{"acme", "team-b"}], map[string]string{"eng": admin}; !reflect.DeepEqual(got, want) {
+		t.Errorf("team-b groups: got %v, want %v", got, want)
+	}
+}
+
+func TestOrgSync_BindsBroadestWorkspaceRole(t *testing.T) {
+	org := newOrg("acme",
+		tenancyv1alpha1.OrganizationGroupBinding{Group: "eng", Role: tenancyv1alpha1.MembershipRoleMember, WorkspaceRole: tenancyv1alpha1.WorkspaceRoleViewer},
+		tenancyv1alpha1.OrganizationGroupBinding{Group: "eng", Role: tenancyv1alpha1.MembershipRoleMember, WorkspaceRole: tenancyv1alpha1.WorkspaceRoleEditor, Workspaces: []string{"team-a"}},
+		tenancyv1alpha1.OrganizationGroupBinding{Group: "auditors", Role: tenancyv1alpha1.MembershipRoleMember, WorkspaceRole: tenancyv1alpha1.WorkspaceRoleViewer},
+	)
+	prov := newFakeProvisioner()
+	prov.childWorkspaces["acme"] = []string{"team-a", "team-b"}
+	r, _ := newReconciler(t, prov, nil, org)
+
+	if _, err := r.reconcileOrganization(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "acme"}}); err != nil {
+		t.Fatalf("reconcileOrganization: %v", err)
+	}
+	viewer, editor := tenancyv1alpha1.WorkspaceRoleViewer, tenancyv1alpha1.WorkspaceRoleEditor
+	if got, want := prov.groupRoles[workspaceKey{"acme", "team-a"}], map[string]string{"eng": editor, "auditors": viewer}; !reflect.DeepEqual(got, want) {
+		t.Errorf("team-a roles: got %v, want %v", got, want)
+	}
+	if got, want := prov.groupRoles[workspaceKey{"acme", "team-b"}], map[string]string{"eng": viewer, "auditors": viewer}; !reflect.DeepEqual(got, want) {
+		t.Errorf("team-b roles: got %v, want %v", got, want)
+	}
+}
+
+func TestOrgSync_EnablesInheritedProviders(t *testing.T) {
+	org := newOrg("acme")
+	org.Spec.InheritedProviders = []string{"backups", "needs-dep", "unknown"}
+	lookup := fakeLookup{
+		"backups": {
+			Name:             "backups",
+			APIExportPath:    "root:kedge:providers:backups",
+			APIExportName:    "backups.kedge.faros.sh",
+			PermissionClaims: []providers.PermissionClaim{{Group: "", Resource: "secrets", Verbs: []string{"get"}}},
+			EdgeProxyAccess:  true,
+			WorkspaceCluster: "abc123",
+		},
+		"needs-dep": {
+			Name:          "needs-dep",
+			APIExportPath: "root:kedge:providers:needs-dep",
+			APIExportName: "needs-dep.kedge.faros.sh",
+			Dependencies:  []providers.Dependency{{Name: "other"}},
+		},
+	}
+	prov := newFakeProvisioner()
+	prov.childWorkspaces["acme"] = []string{"team-a"}
+	r, _ := newReconciler(t, prov, lookup, org)
+
+	if _, err := r.reconcileOrganization(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "acme"}}); err != nil {
+		t.Fatalf("reconcileOrganization: %v", err)
+	}
+	if got, want := prov.bound[workspaceKey{"acme", "team-a"}], []string{"backups"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("bound providers: got %v, want %v", got, want)
+	}
+	if claims := prov.claims["backups"]; len(claims) != 1 || !claims[0].Accepted {
+		t.Errorf("claims: got %#v, want the declared claim accepted", claims)
+	}
+	if len(prov.edgeProxyGrants) != 1 {
+		t.Errorf("edge proxy grants: got %v, want one", prov.edgeProxyGrants)
+	}
+
+	// A second pass sees the binding and does not re-enable it.
+	if _, err := r.reconcileOrganization(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "acme"}}); err != nil {
+		t.Fatalf("reconcileOrganization: %v", err)
+	}
+	if got := len(prov.bound[workspaceKey{"acme", "team-a"}]); got != 1 {
+		t.Errorf("bound providers after resync: got %d, want 1", got)
+	}
+}
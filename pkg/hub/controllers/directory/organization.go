@@ -0,0 +1,170 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package directory
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	tenancyv1alpha1 "github.com/faroshq/faros-kedge/apis/tenancy/v1alpha1"
+	"github.com/faroshq/faros-kedge/pkg/hub/kcp"
+	"github.com/faroshq/faros-kedge/pkg/hub/providers"
+	"github.com/faroshq/faros-kedge/pkg/util/identity"
+)
+
+// reconcileOrganization pushes the Org's group bindings and inherited
+// providers down into each of its child team Workspaces. Personal Orgs
+// only inherit providers; they have no group bindings.
+func (r *Reconciler) reconcileOrganization(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := klog.FromContext(ctx).WithName(orgControllerName).WithValues("org", req.Name)
+
+	var org tenancyv1alpha1.Organization
+	if err := r.client.Get(ctx, types.NamespacedName{Name: req.Name}, &org); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("getting Organization %q: %w", req.Name, err)
+	}
+	if org.Status.DeletionRequestedAt != nil || org.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	children, _, err := r.activeChildWorkspaces(ctx, org.Name)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	for _, ws := range children {
+		if !org.Spec.Personal {
+			roles := workspaceGroupRoles(&org, ws)
+			if err := r.provisioner.SyncChildWorkspaceGroupRoles(ctx, org.Name, ws, roles); err != nil {
+				return ctrl.Result{}, fmt.Errorf("syncing group bindings in workspace %q: %w", ws, err)
+			}
+		}
+		if err := r.enableInheritedProviders(ctx, logger, &org, ws); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: resyncInterval}, nil
+}
+
+// workspaceGroupRoles returns the workspace role of each group whose
+// bindings cover child Workspace ws. When several bindings cover it with
+// different roles, the broadest wins.
+func workspaceGroupRoles(org *tenancyv1alpha1.Organization, ws string) map[string]string {
+	roles := map[string]string{}
+	for _, b := range org.Spec.GroupBindings {
+		if !bindingAppliesTo(b, ws) {
+			continue
+		}
+		role := b.WorkspaceRole
+		if role == "" {
+			role = tenancyv1alpha1.WorkspaceRoleAdmin
+		}
+		if workspaceRoleRank[role] > workspaceRoleRank[roles[b.Group]] {
+			roles[b.Group] = role
+		}
+	}
+	return roles
+}
+
+// workspaceRoleRank orders workspace roles from narrowest to broadest.
+var workspaceRoleRank = map[string]int{
+	tenancyv1alpha1.WorkspaceRoleViewer: 1,
+	tenancyv1alpha1.WorkspaceRoleEditor: 2,
+	tenancyv1alpha1.WorkspaceRoleAdmin:  3,
+}
+
+// enableInheritedProviders binds every provider in
+// spec.inheritedProviders that ws does not have yet, the way the
+// portal's Enable action does, with every declared permission claim
+// accepted: listing a provider on the Org is the consent. Providers
+// that cannot be enabled yet (unknown, no APIExport, missing
+// dependencies, workspace not provisioned) are skipped and retried on
+// the next resync.
+func (r *Reconciler) enableInheritedProviders(ctx context.Context, logger klog.Logger, org *tenancyv1alpha1.Organization, ws string) error {
+	if r.providers == nil || len(org.Spec.InheritedProviders) == 0 {
+		return nil
+	}
+	bound, err := r.provisioner.ListProviderAPIBindings(ctx, org.Name, ws)
+	if err != nil {
+		return fmt.Errorf("listing provider bindings in workspace %q: %w", ws, err)
+	}
+	for _, name := range org.Spec.InheritedProviders {
+		if _, ok := bound[name]; ok {
+			continue
+		}
+		prov, found := r.providers.Get(name)
+		if !found || prov.APIExportPath == "" || prov.APIExportName == "" {
+			logger.V(2).Info("Skipping inherited provider without an APIExport", "provider", name, "workspace", ws)
+			continue
+		}
+		if missing := r.missingDependencies(prov, bound); len(missing) > 0 {
+			logger.V(2).Info("Inherited provider waits for its dependencies", "provider", name, "workspace", ws, "missing", missing)
+			continue
+		}
+		if prov.EdgeProxyAccess && prov.WorkspaceCluster == "" {
+			logger.V(2).Info("Inherited provider workspace not provisioned yet", "provider", name)
+			continue
+		}
+
+		claims := make([]kcp.ProviderClaim, 0, len(prov.PermissionClaims))
+		for _, declared := range prov.PermissionClaims {
+			claims = append(claims, kcp.ProviderClaim{
+				Group:    declared.Group,
+				Resource: declared.Resource,
+				Verbs:    declared.Verbs,
+				Accepted: true,
+			})
+		}
+		if err := r.provisioner.EnsureProviderAPIBinding(ctx, org.Name, ws, name, prov.APIExportPath, prov.APIExportName, claims); err != nil {
+			return fmt.Errorf("enabling provider %q in workspace %q: %w", name, ws, err)
+		}
+		if prov.EdgeProxyAccess {
+			subject := identity.QualifiedServiceAccount(prov.WorkspaceCluster, providers.ProviderSANamespace, providers.ProviderSAName)
+			if err := r.provisioner.EnsureProviderEdgeProxyGrant(ctx, org.Name, ws, name, subject); err != nil {
+				return fmt.Errorf("granting provider %q edge proxy access in workspace %q: %w", name, ws, err)
+			}
+		}
+		logger.Info("Enabled inherited provider", "provider", name, "workspace", ws)
+	}
+	return nil
+}
+
+// missingDependencies mirrors the REST API's Enable precheck: a
+// dependency is met when it is bound in the Workspace, or when it is a
+// ready provider without an APIExport (always implicitly enabled).
+func (r *Reconciler) missingDependencies(prov providers.Provider, bound map[string]string) []string {
+	var missing []string
+	for _, dep := range prov.Dependencies {
+		if dep.Name == "" {
+			continue
+		}
+		if _, ok := bound[dep.Name]; ok {
+			continue
+		}
+		if depProv, found := r.providers.Get(dep.Name); found && depProv.Ready() && depProv.APIExportName == "" {
+			continue
+		}
+		missing = append(missing, dep.Name)
+	}
+	return missing
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package directory
+
+import (
+	"context"
+	"time"
+
+	"github.com/faroshq/faros-kedge/pkg/hub/kcp"
+	"github.com/faroshq/faros-kedge/pkg/hub/providers"
+)
+
+// Provisioner is the slice of the kcp Bootstrapper the directory sync
+// needs to write Memberships, RBAC and provider bindings. Pulled out as
+// an interface so unit tests use a fake without standing up embedded
+// kcp; mirrors the Provisioner in pkg/hub/controllers/softdelete.
+//
+// Implemented by *pkg/hub/kcp.Bootstrapper.
+type Provisioner interface {
+	// EnsureOrgMembership creates the org-scope Membership CR named
+	// userName in the Org workspace. No-op if it exists, whatever its
+	// role.
+	EnsureOrgMembership(ctx context.Context, orgUUID, userName, role string) error
+
+	// PatchOrgMembershipRole sets the role of an existing Membership.
+	PatchOrgMembershipRole(ctx context.Context, orgUUID, userName, role string) error
+
+	// DeleteOrgMembership removes the Membership CR. Idempotent on
+	// NotFound.
+	DeleteOrgMembership(ctx context.Context, orgUUID, userName string) error
+
+	// ListChildWorkspaces returns the UUIDs of the Org's child
+	// Workspaces (its teams).
+	ListChildWorkspaces(ctx context.Context, orgUUID string) ([]string, error)
+
+	// GetWorkspaceDisplayName reads the child Workspace's display name
+	// for the UMI projection.
+	GetWorkspaceDisplayName(ctx context.Context, orgUUID, wsUUID string) (string, error)
+
+	// GetWorkspaceDeletionRequestedAt reports whether the child
+	// Workspace is in its soft-delete window. The directory sync leaves
+	// such Workspaces to the soft-delete reconciler.
+	GetWorkspaceDeletionRequestedAt(ctx context.Context, orgUUID, wsUUID string) (*time.Time, bool, error)
+
+	// SyncChildWorkspaceGroupRoles makes the directory groups bound in
+	// the child Workspace exactly the keys of roles, each with its
+	// workspace role.
+	SyncChildWorkspaceGroupRoles(ctx context.Context, orgUUID, wsUUID string, roles map[string]string) error
+
+	// ListProviderAPIBindings returns the Bound provider APIBindings in
+	// the child Workspace, keyed by provider name.
+	ListProviderAPIBindings(ctx context.Context, orgUUID, wsUUID string) (map[string]string, error)
+
+	// EnsureProviderAPIBinding binds a provider's APIExport in the child
+	// Workspace. No-op on AlreadyExists.
+	EnsureProviderAPIBinding(ctx context.Context, orgUUID, wsUUID, bindingName, exportPath, exportName string, claims []kcp.ProviderClaim) error
+
+	// EnsureProviderEdgeProxyGrant grants the provider's service account
+	// "proxy" on edges in the child Workspace.
+	EnsureProviderEdgeProxyGrant(ctx context.Context, orgUUID, wsUUID, providerName, subject string) error
+}
+
+// ProviderLookup resolves provider names against the catalog.
+// Implemented by *pkg/hub/providers.Registry.
+type ProviderLookup interface {
+	Get(name string) (providers.Provider, bool)
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package directory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	tenancyv1alpha1 "github.com/faroshq/faros-kedge/apis/tenancy/v1alpha1"
+)
+
+// grantKey identifies a UMI row: wsUUID "" is the org-scope row.
+type grantKey struct{ Org, WS string }
+
+// reconcileUser brings the User's directory-sourced memberships in line
+// with their groups. Explicit grants (rows without a source) win over
+// the directory: they are left alone and not duplicated. Orgs and
+// Workspaces in their soft-delete window are frozen — neither granted
+// nor revoked — so an undelete restores the rows as they were.
+func (r *Reconciler) reconcileUser(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := klog.FromContext(ctx).WithName(userControllerName).WithValues("user", req.Name)
+
+	user, err := r.fetchUser(ctx, req.Name)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if user == nil || user.Status.DeletionRequestedAt != nil {
+		return ctrl.Result{}, nil
+	}
+
+	var orgs tenancyv1alpha1.OrganizationList
+	if err := r.client.List(ctx, &orgs); err != nil {
+		return ctrl.Result{}, fmt.Errorf("listing organizations: %w", err)
+	}
+
+	groups := make(map[string]bool, len(user.Status.Groups))
+	for _, g := range user.Status.Groups {
+		groups[g] = true
+	}
+
+	want := map[grantKey]tenancyv1alpha1.MembershipIndexEntry{}
+	known := map[string]bool{}
+	frozenOrgs := map[string]bool{}
+	frozenWorkspaces := map[grantKey]bool{}
+	for i := range orgs.Items {
+		org := &orgs.Items[i]
+		known[org.Name] = true
+		if !syncsOrg(org) {
+			frozenOrgs[org.Name] = true
+			continue
+		}
+		var matched []tenancyv1alpha1.OrganizationGroupBinding
+		for _, b := range org.Spec.GroupBindings {
+			if groups[b.Group] {
+				matched = append(matched, b)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+		children, deleting, err := r.activeChildWorkspaces(ctx, org.Name)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		for ws := range deleting {
+			frozenWorkspaces[grantKey{org.Name, ws}] = true
+		}
+		for _, b := range matched {
+			if len(b.Workspaces) == 0 {
+				addGrant(want, org, "", b.Role)
+			}
+			for _, ws := range children {
+				if bindingAppliesTo(b, ws) {
+					addGrant(want, org, ws, b.Role)
+				}
+			}
+		}
+	}
+
+	var idx tenancyv1alpha1.UserMembershipIndex
+	if err := r.client.Get(ctx, types.NamespacedName{Name: user.Name}, &idx); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, fmt.Errorf("getting UserMembershipIndex %q: %w", user.Name, err)
+		}
+		idx = tenancyv1alpha1.UserMembershipIndex{ObjectMeta: metav1.ObjectMeta{Name: user.Name}}
+	}
+	have := map[grantKey]*tenancyv1alpha1.MembershipIndexEntry{}
+	for i := range idx.Spec.Entries {
+		e := &idx.Spec.Entries[i]
+		have[grantKey{e.OrgUUID, e.WorkspaceUUID}] = e
+	}
+
+	changed := false
+	var added []tenancyv1alpha1.MembershipIndexEntry
+	for key, w := range want {
+		e, ok := have[key]
+		if ok && e.Source != tenancyv1alpha1.MembershipSourceDirectory {
+			continue
+		}
+		if ok && e.Role == w.Role {
+			continue
+		}
+		if key.WS == "" {
+			if err := r.provisioner.EnsureOrgMembership(ctx, key.Org, user.Name, w.Role); err != nil {
+				return ctrl.Result{}, fmt.Errorf("granting membership in org %q: %w", key.Org, err)
+			}
+			// Ensure keeps an existing Membership's role; a changed
+			// binding role has to be patched in.
+			if err := r.provisioner.PatchOrgMembershipRole(ctx, key.Org, user.Name, w.Role); err != nil {
+				return ctrl.Result{}, fmt.Errorf("setting membership role in org %q: %w", key.Org, err)
+			}
+		}
+		if key.WS != "" && !ok {
+			w.WorkspaceDisplayName, _ = r.provisioner.GetWorkspaceDisplayName(ctx, key.Org, key.WS)
+		}
+		if ok {
+			e.Role = w.Role
+		} else {
+			added = append(added, w)
+		}
+		changed = true
+		logger.V(2).Info("Granted directory membership", "org", key.Org, "workspace", key.WS, "role", w.Role)
+	}
+	// Appended only now: have points into the current backing array.
+	sort.Slice(added, func(i, j int) bool {
+		if added[i].OrgUUID != added[j].OrgUUID {
+			return added[i].OrgUUID < added[j].OrgUUID
+		}
+		return added[i].WorkspaceUUID < added[j].WorkspaceUUID
+	})
+
+	next := idx.Spec.Entries[:0]
+	for _, e := range idx.Spec.Entries {
+		key := grantKey{e.OrgUUID, e.WorkspaceUUID}
+		_, wanted := want[key]
+		if e.Source != tenancyv1alpha1.MembershipSourceDirectory || wanted || frozenOrgs[e.OrgUUID] || frozenWorkspaces[key] {
+			next = append(next, e)
+			continue
+		}
+		// The Org CR is gone once the soft-delete cascade finished; its
+		// Membership CRs went with the Org workspace.
+		if key.WS == "" && known[key.Org] {
+			if err := r.provisioner.DeleteOrgMembership(ctx, key.Org, user.Name); err != nil {
+				return ctrl.Result{}, fmt.Errorf("revoking membership in org %q: %w", key.Org, err)
+			}
+		}
+		changed = true
+		logger.V(2).Info("Revoked directory membership", "org", key.Org, "workspace", key.WS)
+	}
+	idx.Spec.Entries = append(next, added...)
+
+	if changed {
+		if err := r.persistUMI(ctx, &idx); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: resyncInterval}, nil
+}
+
+// addGrant records role for (org, ws) in want. admin outranks member
+// when several bindings cover the same scope.
+func addGrant(want map[grantKey]tenancyv1alpha1.MembershipIndexEntry, org *tenancyv1alpha1.Organization, ws, role string) {
+	key := grantKey{org.Name, ws}
+	if existing, ok := want[key]; ok && existing.Role == tenancyv1alpha1.MembershipRoleAdmin {
+		return
+	}
+	want[key] = tenancyv1alpha1.MembershipIndexEntry{
+		OrgUUID:        org.Name,
+		OrgDisplayName: org.Spec.DisplayName,
+		OrgCreatedAt:   org.CreationTimestamp,
+		WorkspaceUUID:  ws,
+		Role:           role,
+		Source:         tenancyv1alpha1.MembershipSourceDirectory,
+	}
+}
+
+// persistUMI creates or updates idx. A conflict is returned so the
+// request is retried against the fresh copy: the bootstrap controller
+// and the REST API write UMIs too.
+func (r *Reconciler) persistUMI(ctx context.Context, idx *tenancyv1alpha1.UserMembershipIndex) error {
+	if idx.ResourceVersion == "" {
+		if err := r.client.Create(ctx, idx); err != nil {
+			return fmt.Errorf("creating UserMembershipIndex %q: %w", idx.Name, err)
+		}
+		return nil
+	}
+	if err := r.client.Update(ctx, idx); err != nil {
+		return fmt.Errorf("updating UserMembershipIndex %q: %w", idx.Name, err)
+	}
+	return nil
+}
@@ -53,6 +53,7 @@ type fakeProvisioner struct {
 	deleteOrgCalls    []string
 	deleteChildCalls  []workspaceKey
 	deleteOrgMembers  []string
+	deleteMemberCalls []workspaceKey // {orgUUID, userName}
 	listChildrenCalls []string
 	listOrgsCalls     int
 
@@ -142,6 +143,13 @@ func (f *fakeProvisioner) DeleteOrgMemberships(_ context.Context, orgUUID string
 	return nil
 }
 
+func (f *fakeProvisioner) DeleteOrgMembership(_ context.Context, orgUUID, userName string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deleteMemberCalls = append(f.deleteMemberCalls, workspaceKey{orgUUID, userName})
+	return nil
+}
+
 func (f *fakeProvisioner) ListOrgMemberships(_ context.Context, orgUUID string) ([]string, error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
@@ -379,6 +387,48 @@ func TestUserSoftDelete_AfterGrace_OrgGone_DeletesUserAndUMI(t *testing.T) {
 	}
 }
 
+func TestUserSoftDelete_AfterGrace_DeletesTokensPreferencesAndMemberships(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	requestedAt := now.Add(-31 * 24 * time.Hour)
+	user := newUserWithDeletion("alice", "alice-org", "alice-ws", requestedAt)
+	umi := newUMI("alice", "alice-org", "alice-ws", true, tenancyv1alpha1.MembershipRoleAdmin)
+	umi.Spec.Entries = append(umi.Spec.Entries, tenancyv1alpha1.MembershipIndexEntry{
+		OrgUUID: "team-org",
+		Role:    tenancyv1alpha1.MembershipRoleMember,
+	})
+	pat := func(name, owner string) *tenancyv1alpha1.PersonalAccessToken {
+		return &tenancyv1alpha1.PersonalAccessToken{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{tenancyv1alpha1.PersonalAccessTokenUserLabel: owner},
+			},
+			Spec: tenancyv1alpha1.PersonalAccessTokenSpec{User: owner},
+		}
+	}
+	prefs := &tenancyv1alpha1.UserPreferences{ObjectMeta: metav1.ObjectMeta{Name: "alice"}}
+
+	prov := newFakeProvisioner()
+	r, c := newReconciler(t, prov, user, umi, prefs, pat("alice-ci", "alice"), pat("bob-ci", "bob"))
+	r.now = func() time.Time { return now }
+
+	if _, err := r.reconcileUser(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "alice"}}); err != nil {
+		t.Fatalf("reconcileUser: %v", err)
+	}
+
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "alice-ci"}, &tenancyv1alpha1.PersonalAccessToken{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected alice's token to be deleted; got err=%v", err)
+	}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "bob-ci"}, &tenancyv1alpha1.PersonalAccessToken{}); err != nil {
+		t.Errorf("expected bob's token to survive; got err=%v", err)
+	}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "alice"}, &tenancyv1alpha1.UserPreferences{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected UserPreferences to be deleted; got err=%v", err)
+	}
+	if want := []workspaceKey{{"team-org", "alice"}}; len(prov.deleteMemberCalls) != 1 || prov.deleteMemberCalls[0] != want[0] {
+		t.Errorf("DeleteOrgMembership calls = %v, want %v", prov.deleteMemberCalls, want)
+	}
+}
+
 func TestUserSoftDelete_SoleAdminBlocked(t *testing.T) {
 	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
 	requestedAt := now.Add(-31 * 24 * time.Hour)
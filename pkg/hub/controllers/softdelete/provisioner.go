@@ -64,4 +64,10 @@ type Provisioner interface {
 	// grace window to mark every member's UMI rows, and during cascade
 	// to know which UMIs to strip.
 	ListOrgMemberships(ctx context.Context, orgUUID string) ([]string, error)
+
+	// DeleteOrgMembership removes one user's Membership CR from the
+	// Organization workspace. Run during User cascade for every
+	// non-personal Org the user still belongs to. Idempotent on
+	// NotFound.
+	DeleteOrgMembership(ctx context.Context, orgUUID, userName string) error
 }
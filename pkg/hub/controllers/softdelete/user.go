@@ -26,6 +26,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	tenancyv1alpha1 "github.com/faroshq/faros-kedge/apis/tenancy/v1alpha1"
 )
@@ -48,8 +49,12 @@ import (
 //	     reconciler picks up the cascade from there.
 //	  3. Wait for the personal Org CR to disappear
 //	     (AwaitingDependents requeue).
-//	  4. Once the Org is gone, delete the UserMembershipIndex.
-//	  5. Delete the User CR itself.
+//	  4. Once the Org is gone, remove the user's Membership from every
+//	     non-personal Org they still belong to, and delete their
+//	     PersonalAccessTokens and UserPreferences, so nothing personal
+//	     outlives the User.
+//	  5. Delete the UserMembershipIndex.
+//	  6. Delete the User CR itself.
 func (r *Reconciler) reconcileUser(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := klog.FromContext(ctx).WithValues("user", req.Name, "branch", "user")
 
@@ -223,13 +228,30 @@ func (r *Reconciler) reconcileUserCascade(ctx context.Context, user *tenancyv1al
 		// Org was already gone (or just disappeared) — fall through.
 	}
 
-	// Step 2: delete the UMI.
+	// Step 2: remove what else references the user. The UMI is the
+	// only record of which non-personal Orgs they joined, so this runs
+	// before it is deleted.
+	for _, e := range umi.Spec.Entries {
+		if e.WorkspaceUUID != "" || e.Personal {
+			continue
+		}
+		if err := r.provisioner.DeleteOrgMembership(ctx, e.OrgUUID, user.Name); err != nil {
+			logger.Error(err, "Deleting Membership failed; will retry", "org", e.OrgUUID)
+			return ctrl.Result{}, err
+		}
+	}
+	if err := r.deleteUserCredentials(ctx, user.Name); err != nil {
+		logger.Error(err, "Deleting user tokens and preferences failed; will retry")
+		return ctrl.Result{}, err
+	}
+
+	// Step 3: delete the UMI.
 	if err := r.deleteUMI(ctx, user.Name); err != nil {
 		logger.Error(err, "Deleting UserMembershipIndex failed; will retry")
 		return ctrl.Result{}, err
 	}
 
-	// Step 3: delete the User CR.
+	// Step 4: delete the User CR.
 	if err := r.client.Delete(ctx, user); err != nil && !apierrors.IsNotFound(err) {
 		logger.Error(err, "Deleting User failed; will retry")
 		return ctrl.Result{}, err
@@ -238,3 +260,22 @@ func (r *Reconciler) reconcileUserCascade(ctx context.Context, user *tenancyv1al
 	logger.Info("User cascade complete")
 	return ctrl.Result{}, nil
 }
+
+// deleteUserCredentials deletes the user's PersonalAccessTokens (found
+// by owner label) and their UserPreferences. Idempotent.
+func (r *Reconciler) deleteUserCredentials(ctx context.Context, userName string) error {
+	var pats tenancyv1alpha1.PersonalAccessTokenList
+	if err := r.client.List(ctx, &pats, client.MatchingLabels{tenancyv1alpha1.PersonalAccessTokenUserLabel: userName}); err != nil {
+		return fmt.Errorf("listing PersonalAccessTokens of %q: %w", userName, err)
+	}
+	for i := range pats.Items {
+		if err := r.client.Delete(ctx, &pats.Items[i]); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("deleting PersonalAccessToken %q: %w", pats.Items[i].Name, err)
+		}
+	}
+	prefs := &tenancyv1alpha1.UserPreferences{ObjectMeta: metav1.ObjectMeta{Name: userName}}
+	if err := r.client.Delete(ctx, prefs); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("deleting UserPreferences %q: %w", userName, err)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package drain lets a hub replica shut down without cutting the fleet's
+// sessions.
+//
+// Upgraded connections, kubectl exec and port-forward, ssh, and the agents'
+// tunnels, are hijacked from the HTTP server, so http.Server.Shutdown neither
+// waits for nor closes them. The Drainer tracks them instead. When the hub
+// starts draining it refuses new agent tunnels and closes the ones it holds,
+// so their agents reconnect through another replica; sessions already open
+// run over their own connections and are given the grace period to finish.
+package drain
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/klog/v2"
+
+	"github.com/faroshq/faros-kedge/pkg/apiurl"
+	"github.com/faroshq/faros-kedge/pkg/problem"
+)
+
+// pickupParam marks a revdial pickup, the connection an agent dials for one
+// session, as opposed to its tunnel.
+const pickupParam = "revdial.dialer"
+
+// Drainer tracks the upgraded connections served by a hub replica and drains
+// them on shutdown.
+type Drainer struct {
+	mu       sync.Mutex
+	draining bool
+	// tunnels are the hijacked connections of agent tunnels, sessions those
+	// of everything else upgraded.
+	tunnels  map[net.Conn]struct{}
+	sessions map[net.Conn]struct{}
+	inflight sync.WaitGroup
+}
+
+// New returns a Drainer that is not draining.
+func New() *Drainer {
+	return &Drainer{tunnels: map[net.Conn]struct{}{}, sessions: map[net.Conn]struct{}{}}
+}
+
+// Handler tracks the upgraded requests next serves. While draining, new agent
+// tunnels are refused with 503 and a Retry-After, for the agent to redial.
+func (d *Drainer) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !httpstream.IsUpgradeRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		tunnel := isAgentTunnel(r)
+		d.mu.Lock()
+		if d.draining && tunnel {
+			d.mu.Unlock()
+			w.Header().Set("Retry-After", "1")
+			w.Header().Set("Connection", "close")
+			problem.Write(w, r, http.StatusServiceUnavailable, problem.ReasonServiceUnavailable, "hub replica is shutting down; reconnect")
+			return
+		}
+		d.inflight.Add(1)
+		d.mu.Unlock()
+		defer d.inflight.Done()
+
+		next.ServeHTTP(&hijackRecorder{ResponseWriter: w, drainer: d, tunnel: tunnel}, r)
+	})
+}
+
+// isAgentTunnel reports whether r opens an agent's tunnel: an upgrade on a
+// provider's agent ingress, or the legacy agent-proxy, that is not a pickup.
+func isAgentTunnel(r *http.Request) bool {
+	if r.URL.Query().Has(pickupParam) {
+		return false
+	}
+	if strings.HasPrefix(r.URL.Path, apiurl.PathPrefixAgentProxy+"/") {
+		return true
+	}
+	rest, ok := strings.CutPrefix(r.URL.Path, apiurl.PathPrefixProvidersProxy+"/")
+	if !ok {
+		return false
+	}
+	_, rest, _ = strings.Cut(rest, "/")
+	return strings.HasPrefix(rest, "agent/")
+}
+
+// Drain refuses new agent tunnels, closes the held ones and waits for the
+// sessions to finish. Sessions still open when ctx is done are closed.
+func (d *Drainer) Drain(ctx context.Context) {
+	logger := klog.FromContext(ctx)
+	d.mu.Lock()
+	d.draining = true
+	tunnels, sessions := len(d.tunnels), len(d.sessions)
+	for c := range d.tunnels {
+		_ = c.Close()
+	}
+	d.mu.Unlock()
+	logger.Info("Draining upgraded connections", "tunnels", tunnels, "sessions", sessions)
+
+	done := make(chan struct{})
+	go func() {
+		d.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		logger.Info("Sessions drained")
+	case <-ctx.Done():
+		d.mu.Lock()
+		logger.Info("Sessions still open after the shutdown grace period; closing them", "sessions", len(d.sessions))
+		for c := range d.sessions {
+			_ = c.Close()
+		}
+		d.mu.Unlock()
+	}
+}
+
+// conns returns the set a hijacked connection is tracked in. The caller
+// holds mu.
+func (d *Drainer) conns(tunnel bool) map[net.Conn]struct{} {
+	if tunnel {
+		return d.tunnels
+	}
+	return d.sessions
+}
+
+// track records c until it is closed, by its handler or by Drain. A tunnel
+// hijacked after Drain closed the others is closed at once.
+func (d *Drainer) track(c net.Conn, tunnel bool) net.Conn {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.draining && tunnel {
+		_ = c.Close()
+	}
+	d.conns(tunnel)[c] = struct{}{}
+	return &trackedConn{Conn: c, untrack: func() {
+		d.mu.Lock()
+		delete(d.conns(tunnel), c)
+		d.mu.Unlock()
+	}}
+}
+
+// hijackRecorder hands the connection its handler hijacks to the Drainer.
+type hijackRecorder struct {
+	http.ResponseWriter
+	drainer *Drainer
+	tunnel  bool
+}
+
+// Hijack hijacks the connection and tracks it.
+func (r *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	c, rw, err := http.NewResponseController(r.ResponseWriter).Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	return r.drainer.track(c, r.tunnel), rw, nil
+}
+
+// Unwrap lets http.ResponseController reach the wrapped writer.
+func (r *hijackRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// trackedConn stops tracking its connection once closed.
+type trackedConn struct {
+	net.Conn
+	once    sync.Once
+	untrack func()
+}
+
+func (c *trackedConn) Close() error {
+	c.once.Do(c.untrack)
+	return c.Conn.Close()
+}
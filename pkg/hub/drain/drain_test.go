@@ -0,0 +1,160 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drain
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faroshq/faros-kedge/pkg/problem"
+)
+
+// echo hijacks upgraded requests and echoes their stream until it ends, as
+// the hub's proxies hold exec sessions and agent tunnels.
+var echo = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	conn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		return
+	}
+	defer conn.Close() //nolint:errcheck
+	_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: test\r\n\r\n")
+	_ = rw.Flush()
+	_, _ = io.Copy(conn, rw)
+})
+
+// upgrade opens an upgraded connection to path on srv and returns it, with
+// the response.
+func upgrade(t *testing.T, srv *httptest.Server, path string) (net.Conn, *http.Response) {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "test")
+	if err := req.Write(conn); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn, resp
+}
+
+// alive reports whether conn still echoes.
+func alive(conn net.Conn) bool {
+	_ = conn.SetDeadline(time.Now().Add(2 * time.Second))
+	if _, err := conn.Write([]byte("x")); err != nil {
+		return false
+	}
+	buf := make([]byte, 1)
+	_, err := conn.Read(buf)
+	return err == nil
+}
+
+func TestDrain(t *testing.T) {
+	d := New()
+	srv := httptest.NewServer(d.Handler(echo))
+	defer srv.Close()
+
+	tunnelPath := "/services/providers/edges/agent/tenant-a/apis/edges.kedge.faros.sh/v1alpha1/kubernetesclusters/store-1/proxy"
+	tunnel, resp := upgrade(t, srv, tunnelPath)
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("tunnel upgrade = %d", resp.StatusCode)
+	}
+	exec, _ := upgrade(t, srv, "/services/providers/edges/edgeproxy/clusters/tenant-a/k8s/api/v1/namespaces/default/pods/web/exec")
+	pickup, _ := upgrade(t, srv, "/services/providers/edges/agent/proxy?revdial.dialer=abc")
+	for name, conn := range map[string]net.Conn{"tunnel": tunnel, "exec": exec, "pickup": pickup} {
+		if !alive(conn) {
+			t.Fatalf("%s not established", name)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	drained := make(chan struct{})
+	go func() {
+		d.Drain(ctx)
+		close(drained)
+	}()
+
+	// The tunnel is closed for its agent to reconnect elsewhere, and a new
+	// one is refused; sessions go on.
+	deadline := time.Now().Add(5 * time.Second)
+	for alive(tunnel) {
+		if time.Now().After(deadline) {
+			t.Fatal("tunnel still open while draining")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	_, resp = upgrade(t, srv, tunnelPath)
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("new tunnel while draining = %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+	if resp.Header.Get("Retry-After") != "1" || resp.Header.Get("Content-Type") != problem.ContentType {
+		t.Errorf("refused tunnel: Retry-After %q, Content-Type %q", resp.Header.Get("Retry-After"), resp.Header.Get("Content-Type"))
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if p := problem.Parse(resp.StatusCode, resp.Header.Get("Retry-After"), body); p.Reason != problem.ReasonServiceUnavailable {
+		t.Errorf("refused tunnel reason = %q, want %q", p.Reason, problem.ReasonServiceUnavailable)
+	}
+	if !alive(exec) || !alive(pickup) {
+		t.Fatal("session cut while draining")
+	}
+
+	// A session that ends is no longer waited for; one still open when the
+	// grace period is over is closed.
+	_ = pickup.Close()
+	select {
+	case <-drained:
+		t.Fatal("drained with a session open")
+	case <-time.After(100 * time.Millisecond):
+	}
+	cancel()
+	select {
+	case <-drained:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Drain did not return after the grace period")
+	}
+	if alive(exec) {
+		t.Fatal("session still open after the grace period")
+	}
+}
+
+func TestIsAgentTunnel(t *testing.T) {
+	for path, want := range map[string]bool{
+		"/services/providers/edges/agent/tenant-a/apis/edges.kedge.faros.sh/v1alpha1/linuxservers/box/proxy": true,
+		"/services/agent-proxy/tenant-a/apis/kedge.faros.sh/v1alpha1/edges/box/proxy":                        true,
+		"/services/providers/edges/agent/proxy?revdial.dialer=abc":                                           false,
+		"/services/providers/edges/edgeproxy/clusters/tenant-a/ssh":                                          false,
+		"/clusters/tenant-a/api/v1/namespaces/default/pods/web/exec":                                         false,
+	} {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		if got := isAgentTunnel(r); got != want {
+			t.Errorf("isAgentTunnel(%s) = %v, want %v", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package explorer serves the hub's interactive API explorer at /explorer:
+// an OpenAPI document generated from the APIResourceSchemas behind the kedge
+// and provider APIExports, and a same-origin page that browses it and sends
+// requests with the portal's session token.
+package explorer
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/gorilla/mux"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+
+	"github.com/faroshq/faros-kedge/pkg/apiurl"
+	"github.com/faroshq/faros-kedge/pkg/hub/providers"
+	"github.com/faroshq/faros-kedge/pkg/kcppaths"
+	"github.com/faroshq/faros-kedge/pkg/problem"
+)
+
+// PathPrefix is the URL prefix the explorer is served under.
+const PathPrefix = "/explorer"
+
+// specTTL is how long a generated OpenAPI document is reused. Schemas only
+// change when a provider is upgraded, so a short cache is enough to keep
+// page loads off kcp.
+const specTTL = time.Minute
+
+// coreExport is the tenant-facing kedge APIExport in the controllers
+// workspace; the tenancy and admin exports are platform-internal.
+const coreExport = "kedge.faros.sh"
+
+var (
+	apiExportGVR = schema.GroupVersionResource{
+		Group: "apis.kcp.io", Version: "v1alpha2", Resource: "apiexports",
+	}
+	apiResourceSchemaGVR = schema.GroupVersionResource{
+		Group: "apis.kcp.io", Version: "v1alpha1", Resource: "apiresourceschemas",
+	}
+)
+
+// contentSecurityPolicy keeps the explorer to its own embedded assets and
+// same-origin requests: no inline script, no third-party CDN.
+const contentSecurityPolicy = "default-src 'self'; script-src 'self'; style-src 'self'; img-src 'self' data:; " +
+	"connect-src 'self'; frame-ancestors 'none'; base-uri 'none'; form-action 'none'"
+
+//go:embed static/*
+var staticFS embed.FS
+
+// UserResolver maps an inbound request to the caller's User CR name, or
+// returns an error if no identity can be resolved.
+type UserResolver interface {
+	ResolveUser(r *http.Request) (string, error)
+}
+
+// UserResolverFunc adapts a function to UserResolver.
+type UserResolverFunc func(r *http.Request) (string, error)
+
+// ResolveUser implements UserResolver.
+func (f UserResolverFunc) ResolveUser(r *http.Request) (string, error) { return f(r) }
+
+// Handler serves the explorer page and its OpenAPI document.
+type Handler struct {
+	kcpConfig *rest.Config
+	registry  *providers.Registry
+	resolver  UserResolver
+	version   string
+	log       logr.Logger
+
+	mu       sync.Mutex
+	spec     []byte
+	specTime time.Time
+}
+
+// NewHandler returns a Handler reading schemas with the hub's kcp admin
+// config. version is reported as the document's info.version.
+func NewHandler(kcpConfig *rest.Config, registry *providers.Registry, resolver UserResolver, version string, log logr.Logger) *Handler {
+	return &Handler{
+		kcpConfig: kcpConfig,
+		registry:  registry,
+		resolver:  resolver,
+		version:   version,
+		log:       log,
+	}
+}
+
+// Register mounts the explorer on router. The page itself is public (it
+// holds no data); the OpenAPI document requires an authenticated caller.
+func (h *Handler) Register(router *mux.Router) {
+	assets, err := fs.Sub(staticFS, "static")
+	if err != nil {
+		// The embed pattern guarantees the directory exists.
+		panic(err)
+	}
+	fileServer := http.StripPrefix(PathPrefix+"/", http.FileServer(http.FS(assets)))
+
+	router.HandleFunc(PathPrefix, func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, PathPrefix+"/", http.StatusMovedPermanently)
+	})
+	router.HandleFunc(PathPrefix+"/openapi.json", h.serveSpec).Methods("GET")
+	router.PathPrefix(PathPrefix+"/").Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", contentSecurityPolicy)
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Referrer-Policy", "no-referrer")
+		fileServer.ServeHTTP(w, r)
+	})).Methods("GET", "HEAD")
+}
+
+func (h *Handler) serveSpec(w http.ResponseWriter, r *http.Request) {
+	if name, err := h.resolver.ResolveUser(r); err != nil || name == "" {
+		problem.Write(w, r, http.StatusUnauthorized, problem.ReasonUnauthorized, "unauthorized")
+		return
+	}
+	spec, err := h.document(r.Context())
+	if err != nil {
+		h.log.Error(err, "Building API explorer document")
+		problem.Write(w, r, http.StatusInternalServerError, problem.ReasonForStatus(http.StatusInternalServerError), "building API document failed")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "private, max-age=60")
+	_, _ = w.Write(spec)
+}
+
+// document returns the cached OpenAPI document, rebuilding it when older
+// than specTTL.
+func (h *Handler) document(ctx context.Context) ([]byte, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.spec != nil && time.Since(h.specTime) < specTTL {
+		return h.spec, nil
+	}
+	spec, err := json.Marshal(buildSpec(h.version, h.collect(ctx)))
+	if err != nil {
+		return nil, fmt.Errorf("encoding OpenAPI document: %w", err)
+	}
+	h.spec, h.specTime = spec, time.Now()
+	return spec, nil
+}
+
+// collect reads the schemas of the core kedge export and of every ready
+// provider's export. An export that cannot be read is logged and left out
+// so one broken provider does not hide the others.
+func (h *Handler) collect(ctx context.Context) []APIGroup {
+	var groups []APIGroup
+	if schemas, err := h.exportSchemas(ctx, kcppaths.SystemControllers, coreExport); err != nil {
+		h.log.Error(err, "Reading kedge APIExport for the API explorer")
+	} else {
+		groups = append(groups, APIGroup{Name: "kedge", Schemas: schemas})
+	}
+	for _, p := range h.registry.List() {
+		if p.APIExportPath == "" || p.APIExportName == "" || !p.Ready() {
+			continue
+		}
+		schemas, err := h.exportSchemas(ctx, p.APIExportPath, p.APIExportName)
+		if err != nil {
+			h.log.Error(err, "Reading provider APIExport for the API explorer", "provider", p.Name)
+			continue
+		}
+		name := p.DisplayName
+		if name == "" {
+			name = p.Name
+		}
+		groups = append(groups, APIGroup{Name: name, Schemas: schemas})
+	}
+	return groups
+}
+
+// exportSchemas returns the APIResourceSchemas the named APIExport in
+// workspace path serves.
+func (h *Handler) exportSchemas(ctx context.Context, path, exportName string) ([]*unstructured.Unstructured, error) {
+	cfg := rest.CopyConfig(h.kcpConfig)
+	cfg.Host = apiurl.KCPClusterURL(cfg.Host, path)
+	cl, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("dynamic client for %s: %w", path, err)
+	}
+	export, err := cl.Resource(apiExportGVR).Get(ctx, exportName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("getting APIExport %s|%s: %w", path, exportName, err)
+	}
+	resources, _, _ := unstructured.NestedSlice(export.Object, "spec", "resources")
+	var out []*unstructured.Unstructured
+	for _, raw := range resources {
+		res, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := res["schema"].(string)
+		if name == "" {
+			continue
+		}
+		ars, err := cl.Resource(apiResourceSchemaGVR).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("getting APIResourceSchema %s|%s: %w", path, name, err)
+		}
+		out = append(out, ars)
+	}
+	return out, nil
+}
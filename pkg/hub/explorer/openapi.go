@@ -0,0 +1,274 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package explorer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// APIGroup is the set of APIResourceSchemas one provider exports, the unit
+// the explorer groups its navigation by.
+type APIGroup struct {
+	// Name is the provider's display name.
+	Name string
+	// Schemas are the provider APIExport's APIResourceSchemas.
+	Schemas []*unstructured.Unstructured
+}
+
+// resourceSchema is the subset of an APIResourceSchema the OpenAPI
+// document is built from.
+type resourceSchema struct {
+	group, kind, listKind, plural string
+	namespaced                    bool
+	versions                      []resourceVersion
+}
+
+type resourceVersion struct {
+	name   string
+	status bool
+	schema map[string]interface{}
+}
+
+// parseResourceSchema reads an APIResourceSchema (apis.kcp.io/v1alpha1).
+// Versions that are not served are dropped.
+func parseResourceSchema(u *unstructured.Unstructured) (resourceSchema, error) {
+	var rs resourceSchema
+	rs.group, _, _ = unstructured.NestedString(u.Object, "spec", "group")
+	rs.kind, _, _ = unstructured.NestedString(u.Object, "spec", "names", "kind")
+	rs.listKind, _, _ = unstructured.NestedString(u.Object, "spec", "names", "listKind")
+	rs.plural, _, _ = unstructured.NestedString(u.Object, "spec", "names", "plural")
+	scope, _, _ := unstructured.NestedString(u.Object, "spec", "scope")
+	rs.namespaced = scope == "Namespaced"
+	if rs.group == "" || rs.kind == "" || rs.plural == "" {
+		return rs, fmt.Errorf("APIResourceSchema %q lacks group or names", u.GetName())
+	}
+	if rs.listKind == "" {
+		rs.listKind = rs.kind + "List"
+	}
+
+	versions, _, _ := unstructured.NestedSlice(u.Object, "spec", "versions")
+	for _, raw := range versions {
+		v, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if served, _, _ := unstructured.NestedBool(v, "served"); !served {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(v, "name")
+		schema, _, _ := unstructured.NestedMap(v, "schema")
+		_, hasStatus, _ := unstructured.NestedMap(v, "subresources", "status")
+		rs.versions = append(rs.versions, resourceVersion{name: name, status: hasStatus, schema: schema})
+	}
+	return rs, nil
+}
+
+// buildSpec renders groups as an OpenAPI 3.0 document. Every path is
+// workspace-relative (/clusters/{cluster}/apis/...), the way clients reach
+// kcp through the hub. Each kind becomes a tag; each provider a tag group.
+func buildSpec(version string, groups []APIGroup) map[string]interface{} {
+	paths := map[string]interface{}{}
+	schemas := map[string]interface{}{
+		"Status": map[string]interface{}{
+			"type":        "object",
+			"description": "Status is the Kubernetes response envelope for deletions and errors.",
+			"properties": map[string]interface{}{
+				"status":  map[string]interface{}{"type": "string"},
+				"reason":  map[string]interface{}{"type": "string"},
+				"message": map[string]interface{}{"type": "string"},
+				"code":    map[string]interface{}{"type": "integer"},
+			},
+		},
+	}
+	var tags, tagGroups []interface{}
+
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	for _, g := range groups {
+		var groupTags []string
+		var resources []resourceSchema
+		for _, u := range g.Schemas {
+			rs, err := parseResourceSchema(u)
+			if err != nil || len(rs.versions) == 0 {
+				continue
+			}
+			resources = append(resources, rs)
+		}
+		sort.Slice(resources, func(i, j int) bool { return resources[i].kind < resources[j].kind })
+
+		for _, rs := range resources {
+			tag := rs.kind
+			groupTags = append(groupTags, tag)
+			tags = append(tags, map[string]interface{}{
+				"name":        tag,
+				"description": fmt.Sprintf("%s (%s.%s, %s)", rs.kind, rs.plural, rs.group, scopeName(rs.namespaced)),
+			})
+			for _, v := range rs.versions {
+				addResourcePaths(paths, schemas, tag, rs, v)
+			}
+		}
+		if len(groupTags) > 0 {
+			tagGroups = append(tagGroups, map[string]interface{}{"name": g.Name, "tags": groupTags})
+		}
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "kedge API",
+			"version": version,
+			"description": "The kedge resource APIs, generated from the APIResourceSchemas of the enabled providers. " +
+				"Requests go to a workspace's logical cluster through the hub and are authorized as the caller.",
+		},
+		"servers":     []interface{}{map[string]interface{}{"url": "/"}},
+		"tags":        tags,
+		"x-tagGroups": tagGroups,
+		"paths":       paths,
+		"components": map[string]interface{}{
+			"schemas": schemas,
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{"type": "http", "scheme": "bearer"},
+			},
+		},
+		"security": []interface{}{map[string]interface{}{"bearerAuth": []interface{}{}}},
+	}
+}
+
+// addResourcePaths adds the collection, item and status paths of one served
+// version, plus its object and list schemas.
+func addResourcePaths(paths, schemas map[string]interface{}, tag string, rs resourceSchema, v resourceVersion) {
+	id := rs.group + "." + v.name + "." + rs.kind
+	listID := rs.group + "." + v.name + "." + rs.listKind
+	objSchema := v.schema
+	if objSchema == nil {
+		objSchema = map[string]interface{}{"type": "object"}
+	}
+	schemas[id] = objSchema
+	schemas[listID] = map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"apiVersion": map[string]interface{}{"type": "string"},
+			"kind":       map[string]interface{}{"type": "string"},
+			"metadata":   map[string]interface{}{"type": "object"},
+			"items":      map[string]interface{}{"type": "array", "items": ref(id)},
+		},
+	}
+
+	base := "/clusters/{cluster}/apis/" + rs.group + "/" + v.name
+	params := []interface{}{pathParam("cluster", "Logical cluster (workspace) ID, as shown by `kedge ws current`.")}
+	collection := base + "/" + rs.plural
+	if rs.namespaced {
+		// Cross-namespace list, then the namespaced collection.
+		paths[collection] = map[string]interface{}{
+			"parameters": params,
+			"get":        operation(tag, "list"+rs.kind+"ForAllNamespaces", "List "+rs.kind+" objects across namespaces", listQueryParams(), nil, "200", listID),
+		}
+		params = append(params, pathParam("namespace", "Namespace of the object."))
+		collection = base + "/namespaces/{namespace}/" + rs.plural
+	}
+	paths[collection] = map[string]interface{}{
+		"parameters": params,
+		"get":        operation(tag, "list"+rs.kind, "List "+rs.kind+" objects", listQueryParams(), nil, "200", listID),
+		"post":       operation(tag, "create"+rs.kind, "Create a "+rs.kind, nil, body("application/json", ref(id)), "201", id),
+	}
+
+	itemParams := append(append([]interface{}{}, params...), pathParam("name", "Name of the "+rs.kind+"."))
+	item := collection + "/{name}"
+	paths[item] = map[string]interface{}{
+		"parameters": itemParams,
+		"get":        operation(tag, "read"+rs.kind, "Read a "+rs.kind, nil, nil, "200", id),
+		"put":        operation(tag, "replace"+rs.kind, "Replace a "+rs.kind, nil, body("application/json", ref(id)), "200", id),
+		"patch":      operation(tag, "patch"+rs.kind, "Patch a "+rs.kind+" with a JSON merge patch", nil, body("application/merge-patch+json", map[string]interface{}{"type": "object"}), "200", id),
+		"delete":     operation(tag, "delete"+rs.kind, "Delete a "+rs.kind, nil, nil, "200", "Status"),
+	}
+	if v.status {
+		paths[item+"/status"] = map[string]interface{}{
+			"parameters": itemParams,
+			"get":        operation(tag, "read"+rs.kind+"Status", "Read the status of a "+rs.kind, nil, nil, "200", id),
+			"patch":      operation(tag, "patch"+rs.kind+"Status", "Patch the status of a "+rs.kind, nil, body("application/merge-patch+json", map[string]interface{}{"type": "object"}), "200", id),
+		}
+	}
+}
+
+func operation(tag, id, summary string, params []interface{}, requestBody map[string]interface{}, code, responseSchema string) map[string]interface{} {
+	op := map[string]interface{}{
+		"tags":        []interface{}{tag},
+		"operationId": id,
+		"summary":     summary,
+		"responses": map[string]interface{}{
+			code: map[string]interface{}{
+				"description": "OK",
+				"content":     map[string]interface{}{"application/json": map[string]interface{}{"schema": ref(responseSchema)}},
+			},
+			"default": map[string]interface{}{
+				"description": "Error",
+				"content":     map[string]interface{}{"application/json": map[string]interface{}{"schema": ref("Status")}},
+			},
+		},
+	}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+	if requestBody != nil {
+		op["requestBody"] = requestBody
+	}
+	return op
+}
+
+func listQueryParams() []interface{} {
+	return []interface{}{
+		queryParam("labelSelector", "Restrict the list to objects matching this label selector."),
+		queryParam("fieldSelector", "Restrict the list to objects matching this field selector."),
+		queryParam("limit", "Maximum number of objects to return; continue with the returned continue token."),
+		queryParam("continue", "Continue token from a previous limited list."),
+	}
+}
+
+func pathParam(name, description string) map[string]interface{} {
+	return map[string]interface{}{
+		"name": name, "in": "path", "required": true, "description": description,
+		"schema": map[string]interface{}{"type": "string"},
+	}
+}
+
+func queryParam(name, description string) map[string]interface{} {
+	return map[string]interface{}{
+		"name": name, "in": "query", "description": description,
+		"schema": map[string]interface{}{"type": "string"},
+	}
+}
+
+func body(contentType string, schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"required": true,
+		"content":  map[string]interface{}{contentType: map[string]interface{}{"schema": schema}},
+	}
+}
+
+func ref(id string) map[string]interface{} {
+	return map[string]interface{}{"$ref": "#/components/schemas/" + strings.ReplaceAll(id, "/", "~1")}
+}
+
+func scopeName(namespaced bool) string {
+	if namespaced {
+		return "namespaced"
+	}
+	return "cluster-scoped"
+}
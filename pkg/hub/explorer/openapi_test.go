@@ -0,0 +1,108 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package explorer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	kcpconfig "github.com/faroshq/faros-kedge/config/kcp"
+)
+
+func TestBuildSpec(t *testing.T) {
+	raw, err := kcpconfig.ProvidersFS.ReadFile("apiresourceschema-mcpservers.kedge.faros.sh.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mcp := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(raw, &mcp.Object); err != nil {
+		t.Fatal(err)
+	}
+	placements := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apis.kcp.io/v1alpha1",
+		"kind":       "APIResourceSchema",
+		"metadata":   map[string]interface{}{"name": "v1.placements.example.faros.sh"},
+		"spec": map[string]interface{}{
+			"group": "example.faros.sh",
+			"names": map[string]interface{}{"kind": "Placement", "plural": "placements"},
+			"scope": "Namespaced",
+			"versions": []interface{}{
+				map[string]interface{}{"name": "v1alpha1", "served": true, "schema": map[string]interface{}{"type": "object"}},
+				map[string]interface{}{"name": "v1alpha0", "served": false},
+			},
+		},
+	}}
+
+	spec := buildSpec("v0.0.0", []APIGroup{
+		{Name: "Workloads", Schemas: []*unstructured.Unstructured{placements}},
+		{Name: "kedge", Schemas: []*unstructured.Unstructured{mcp}},
+	})
+	// Round-trip through JSON the way the handler serves it.
+	b, err := json.Marshal(spec)
+	if err != nil {
+		t.Fatalf("encoding spec: %v", err)
+	}
+	var doc struct {
+		Paths      map[string]map[string]interface{} `json:"paths"`
+		Components struct {
+			Schemas map[string]interface{} `json:"schemas"`
+		} `json:"components"`
+		TagGroups []struct {
+			Name string   `json:"name"`
+			Tags []string `json:"tags"`
+		} `json:"x-tagGroups"`
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatalf("decoding spec: %v", err)
+	}
+
+	for path, methods := range map[string][]string{
+		"/clusters/{cluster}/apis/kedge.faros.sh/v1alpha1/mcpservers":                                 {"get", "post"},
+		"/clusters/{cluster}/apis/kedge.faros.sh/v1alpha1/mcpservers/{name}":                          {"get", "put", "patch", "delete"},
+		"/clusters/{cluster}/apis/kedge.faros.sh/v1alpha1/mcpservers/{name}/status":                   {"get", "patch"},
+		"/clusters/{cluster}/apis/example.faros.sh/v1alpha1/placements":                               {"get"},
+		"/clusters/{cluster}/apis/example.faros.sh/v1alpha1/namespaces/{namespace}/placements/{name}": {"get", "delete"},
+	} {
+		item, ok := doc.Paths[path]
+		if !ok {
+			t.Errorf("missing path %s", path)
+			continue
+		}
+		for _, m := range methods {
+			if _, ok := item[m]; !ok {
+				t.Errorf("%s: missing %s", path, m)
+			}
+		}
+	}
+	if _, ok := doc.Paths["/clusters/{cluster}/apis/example.faros.sh/v1alpha1/placements/{name}/status"]; ok {
+		t.Error("status path for a kind without the status subresource")
+	}
+	if _, ok := doc.Paths["/clusters/{cluster}/apis/example.faros.sh/v1alpha0/placements"]; ok {
+		t.Error("path for an unserved version")
+	}
+	for _, id := range []string{"kedge.faros.sh.v1alpha1.MCPServer", "kedge.faros.sh.v1alpha1.MCPServerList", "example.faros.sh.v1alpha1.PlacementList"} {
+		if _, ok := doc.Components.Schemas[id]; !ok {
+			t.Errorf("missing schema %s", id)
+		}
+	}
+	if len(doc.TagGroups) != 2 || doc.TagGroups[0].Name != "Workloads" || doc.TagGroups[1].Tags[0] != "MCPServer" {
+		t.Errorf("tag groups = %+v", doc.TagGroups)
+	}
+}
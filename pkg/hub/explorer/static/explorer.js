@@ -0,0 +1,265 @@
+// kedge API explorer. Renders the hub's /explorer/openapi.json and sends
+// "Try it" requests with the portal's session token. Everything is built
+// with DOM APIs (no innerHTML): schema descriptions come from providers.
+'use strict';
+
+(function () {
+  var METHODS = ['get', 'post', 'put', 'patch', 'delete'];
+  var auth = loadAuth();
+  var spec = null;
+  var active = null;
+
+  function loadAuth() {
+    try {
+      var a = JSON.parse(localStorage.getItem('kedge-auth') || 'null');
+      if (!a || !a.idToken) return null;
+      if (a.expiresAt && Date.now() / 1000 > a.expiresAt) return null;
+      return a;
+    } catch (e) {
+      return null;
+    }
+  }
+
+  function el(tag, attrs) {
+    var node = document.createElement(tag);
+    Object.keys(attrs || {}).forEach(function (k) {
+      if (k === 'text') node.textContent = attrs[k];
+      else if (k === 'class') node.className = attrs[k];
+      else if (k === 'onclick') node.addEventListener('click', attrs[k]);
+      else node.setAttribute(k, attrs[k]);
+    });
+    for (var i = 2; i < arguments.length; i++) {
+      var c = arguments[i];
+      if (c == null) continue;
+      node.appendChild(typeof c === 'string' ? document.createTextNode(c) : c);
+    }
+    return node;
+  }
+
+  function clear(node) {
+    while (node.firstChild) node.removeChild(node.firstChild);
+    return node;
+  }
+
+  function signIn(message) {
+    clear(document.getElementById('main')).appendChild(el('div', { class: 'notice' },
+      message + ' ', el('a', { href: '/ui/' }, 'Sign in to the portal'),
+      ', then reload this page.'));
+  }
+
+  function resolveRef(schema) {
+    var seen = 0;
+    while (schema && schema.$ref && seen++ < 8) {
+      var name = schema.$ref.replace('#/components/schemas/', '').replace(/~1/g, '/');
+      schema = spec.components.schemas[name];
+    }
+    return schema || {};
+  }
+
+  function typeOf(s) {
+    if (s['x-kubernetes-int-or-string']) return 'int-or-string';
+    if (s['x-kubernetes-preserve-unknown-fields'] && !s.properties) return 'object (free-form)';
+    if (s.type === 'array') return (resolveRef(s.items).type || 'object') + '[]';
+    if (s.type === 'object' && s.additionalProperties) return 'map[string]' + (resolveRef(s.additionalProperties).type || 'object');
+    return (s.type || 'object') + (s.format ? ' (' + s.format + ')' : '') + (s.enum ? ' ∈ {' + s.enum.join(', ') + '}' : '');
+  }
+
+  function children(s) {
+    if (s.properties) return s;
+    if (s.type === 'array' && s.items) return children(resolveRef(s.items));
+    if (s.additionalProperties && typeof s.additionalProperties === 'object') return children(resolveRef(s.additionalProperties));
+    return null;
+  }
+
+  function renderSchema(schema, depth) {
+    schema = resolveRef(schema);
+    var box = el('div', { class: 'schema' });
+    var obj = children(schema);
+    if (!obj) {
+      box.appendChild(el('span', { class: 'type', text: typeOf(schema) }));
+      return box;
+    }
+    var required = obj.required || [];
+    Object.keys(obj.properties).sort().forEach(function (name) {
+      var p = resolveRef(obj.properties[name]);
+      var line = [
+        el('span', { class: 'name', text: name }),
+        el('span', { class: 'type', text: typeOf(p) }),
+        required.indexOf(name) >= 0 ? el('span', { class: 'req', text: 'required' }) : null,
+        p.description ? el('span', { class: 'desc', text: p.description }) : null,
+      ];
+      if (children(p)) {
+        var d = el('details', depth < 1 ? { open: '' } : {});
+        d.appendChild(el.apply(null, ['summary', {}].concat(line)));
+        d.addEventListener('toggle', function once() {
+          d.removeEventListener('toggle', once);
+          d.appendChild(renderSchema(p, depth + 1));
+        });
+        if (depth < 1) d.appendChild(renderSchema(p, depth + 1));
+        box.appendChild(d);
+      } else {
+        box.appendChild(el.apply(null, ['div', { class: 'prop' }].concat(line)));
+      }
+    });
+    return box;
+  }
+
+  // exampleBody is the skeleton a create or replace starts from.
+  function exampleBody(path, kind, schema) {
+    var m = path.match(/\/apis\/([^/]+)\/([^/]+)\//);
+    var body = { apiVersion: m ? m[1] + '/' + m[2] : '', kind: kind, metadata: { name: '' } };
+    if ((resolveRef(schema).properties || {}).spec) body.spec = {};
+    return body;
+  }
+
+  function renderOperation(path, method, op, pathItem) {
+    var main = clear(document.getElementById('main'));
+    var params = (pathItem.parameters || []).concat(op.parameters || []);
+    main.appendChild(el('h2', {}, el('span', { class: 'method ' + method, text: method }), ' ', el('code', { text: path })));
+    main.appendChild(el('p', { class: 'muted', text: op.summary + ' · operationId ' + op.operationId }));
+
+    var inputs = {};
+    if (params.length) {
+      main.appendChild(el('h3', { text: 'Parameters' }));
+      var table = el('table');
+      params.forEach(function (p) {
+        var input = el('input', { type: 'text', placeholder: p.in + (p.required ? ', required' : '') });
+        if (p.name === 'cluster' && auth && auth.clusterName) input.value = auth.clusterName;
+        if (p.name === 'namespace') input.value = 'default';
+        inputs[p.in + ':' + p.name] = input;
+        table.appendChild(el('tr', {}, el('td', {}, el('code', { text: p.name })), el('td', {}, input),
+          el('td', { class: 'muted', text: p.description || '' })));
+      });
+      main.appendChild(table);
+    }
+
+    var bodyInput = null;
+    if (op.requestBody) {
+      var ct = Object.keys(op.requestBody.content)[0];
+      var bodySchema = op.requestBody.content[ct].schema;
+      main.appendChild(el('h3', { text: 'Request body (' + ct + ')' }));
+      if (bodySchema.$ref) main.appendChild(renderSchema(bodySchema, 0));
+      var sample = bodySchema.$ref ? exampleBody(path, (op.tags || [''])[0], bodySchema) : {};
+      bodyInput = el('textarea', { spellcheck: 'false' });
+      bodyInput.value = JSON.stringify(sample, null, 2);
+      main.appendChild(bodyInput);
+    }
+
+    var responses = op.responses || {};
+    Object.keys(responses).filter(function (c) { return c !== 'default'; }).forEach(function (code) {
+      var content = responses[code].content && responses[code].content['application/json'];
+      if (!content) return;
+      main.appendChild(el('h3', { text: 'Response ' + code }));
+      main.appendChild(renderSchema(content.schema, 0));
+    });
+
+    var out = el('pre', { hidden: '' });
+    main.appendChild(el('h3', { text: 'Try it' }));
+    main.appendChild(el('p', { class: 'muted', text: 'Sends the request to the hub as you. Mutating requests change real objects.' }));
+    main.appendChild(el('button', { class: 'run', onclick: function () {
+      send(path, method, params, inputs, bodyInput, op, out);
+    } }, 'Send request'));
+    main.appendChild(out);
+  }
+
+  function send(path, method, params, inputs, bodyInput, op, out) {
+    var url = path;
+    var query = [];
+    var missing = [];
+    params.forEach(function (p) {
+      var v = inputs[p.in + ':' + p.name].value.trim();
+      if (!v) {
+        if (p.required) missing.push(p.name);
+        return;
+      }
+      if (p.in === 'path') url = url.replace('{' + p.name + '}', encodeURIComponent(v));
+      else query.push(encodeURIComponent(p.name) + '=' + encodeURIComponent(v));
+    });
+    out.hidden = false;
+    if (missing.length) {
+      out.textContent = 'Missing required parameters: ' + missing.join(', ');
+      return;
+    }
+    if (query.length) url += '?' + query.join('&');
+    var init = { method: method.toUpperCase(), headers: { Authorization: 'Bearer ' + auth.idToken, Accept: 'application/json' } };
+    if (bodyInput) {
+      init.headers['Content-Type'] = Object.keys(op.requestBody.content)[0];
+      init.body = bodyInput.value;
+    }
+    out.textContent = init.method + ' ' + url + ' …';
+    fetch(url, init).then(function (resp) {
+      return resp.text().then(function (text) {
+        try { text = JSON.stringify(JSON.parse(text), null, 2); } catch (e) { /* not JSON */ }
+        out.textContent = init.method + ' ' + url + '\n' + resp.status + ' ' + resp.statusText + '\n\n' + text;
+      });
+    }, function (err) {
+      out.textContent = init.method + ' ' + url + '\n' + err;
+    });
+  }
+
+  function renderNav(filter) {
+    var nav = clear(document.getElementById('nav'));
+    var byTag = {};
+    Object.keys(spec.paths).sort().forEach(function (path) {
+      var item = spec.paths[path];
+      METHODS.forEach(function (m) {
+        if (!item[m]) return;
+        var tag = (item[m].tags || ['other'])[0];
+        (byTag[tag] = byTag[tag] || []).push({ path: path, method: m, op: item[m], item: item });
+      });
+    });
+    var f = (filter || '').toLowerCase();
+    (spec['x-tagGroups'] || []).forEach(function (group) {
+      var groupNode = el('div', { class: 'group', text: group.name });
+      var shown = false;
+      group.tags.forEach(function (tag) {
+        var ops = (byTag[tag] || []).filter(function (o) {
+          return !f || tag.toLowerCase().indexOf(f) >= 0 || o.path.toLowerCase().indexOf(f) >= 0;
+        });
+        if (!ops.length) return;
+        if (!shown) { nav.appendChild(groupNode); shown = true; }
+        nav.appendChild(el('div', { class: 'tag', text: tag }));
+        ops.forEach(function (o) {
+          var key = o.method + ' ' + o.path;
+          var btn = el('button', { class: 'op' + (key === active ? ' active' : ''), onclick: function () {
+            active = key;
+            renderNav(document.getElementById('filter').value);
+            renderOperation(o.path, o.method, o.op, o.item);
+          } }, el('span', { class: 'method ' + o.method, text: o.method }), el('span', { class: 'path', text: o.path }));
+          nav.appendChild(btn);
+        });
+      });
+    });
+  }
+
+  function start() {
+    if (!auth) {
+      signIn('The explorer uses your portal session.');
+      return;
+    }
+    fetch('openapi.json', { headers: { Authorization: 'Bearer ' + auth.idToken } }).then(function (resp) {
+      if (resp.status === 401) {
+        signIn('Your portal session has expired.');
+        return null;
+      }
+      if (!resp.ok) throw new Error(resp.status + ' ' + resp.statusText);
+      return resp.json();
+    }).then(function (doc) {
+      if (!doc) return;
+      spec = doc;
+      var link = document.getElementById('download');
+      link.href = URL.createObjectURL(new Blob([JSON.stringify(doc, null, 2)], { type: 'application/json' }));
+      link.hidden = false;
+      document.getElementById('filter').addEventListener('input', function (e) { renderNav(e.target.value); });
+      renderNav('');
+      var main = clear(document.getElementById('main'));
+      main.appendChild(el('h2', { text: doc.info.title + ' ' + doc.info.version }));
+      main.appendChild(el('p', { text: doc.info.description }));
+      main.appendChild(el('p', { class: 'muted', text: 'Pick an operation on the left. The cluster parameter defaults to your home workspace.' }));
+    }).catch(function (err) {
+      clear(document.getElementById('main')).appendChild(el('div', { class: 'notice', text: 'Loading the API document failed: ' + err.message }));
+    });
+  }
+
+  document.addEventListener('DOMContentLoaded', start);
+})();
@@ -0,0 +1,225 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fleetmap serves /services/fleetmap: every edge in the caller's
+// workspace as a GeoJSON FeatureCollection, positioned by the edge's
+// spec.location and carrying its connection status, for the dashboard's
+// fleet map.
+package fleetmap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/go-logr/logr"
+	"github.com/gorilla/mux"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+
+	"github.com/faroshq/faros-kedge/pkg/apiurl"
+	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+	"github.com/faroshq/faros-kedge/pkg/hub/providers"
+	"github.com/faroshq/faros-kedge/pkg/problem"
+)
+
+// PathPrefix is the URL path the fleet map is served at.
+const PathPrefix = "/services/fleetmap"
+
+// edgeKinds are the edge kinds plotted on the map.
+var edgeKinds = []struct {
+	kind string
+	gvr  schema.GroupVersionResource
+}{
+	{"KubernetesCluster", kedgeclient.KubernetesClusterGVR},
+	{"LinuxServer", kedgeclient.LinuxServerGVR},
+}
+
+// Handler serves the fleet map.
+type Handler struct {
+	kcpConfig *rest.Config
+	resolver  providers.TenantResolver
+	log       logr.Logger
+}
+
+// NewHandler returns a Handler listing edges with the hub's kcp admin
+// config, in the workspace resolver picks for the caller (which honors the
+// portal's X-Kedge-Org / X-Kedge-Workspace selection after checking
+// membership).
+func NewHandler(kcpConfig *rest.Config, resolver providers.TenantResolver, log logr.Logger) *Handler {
+	return &Handler{kcpConfig: kcpConfig, resolver: resolver, log: log}
+}
+
+// Register mounts the fleet map on router.
+func (h *Handler) Register(router *mux.Router) {
+	router.HandleFunc(PathPrefix, h.serve).Methods("GET")
+}
+
+func (h *Handler) serve(w http.ResponseWriter, r *http.Request) {
+	user, tenantPath, err := h.resolver.Resolve(r)
+	if err != nil || user == "" {
+		problem.Write(w, r, http.StatusUnauthorized, problem.ReasonUnauthorized, "unauthorized")
+		return
+	}
+	if tenantPath == "" {
+		problem.Write(w, r, http.StatusNotFound, problem.ReasonNotFound, "no workspace found for the caller")
+		return
+	}
+
+	edges, err := h.listEdges(r.Context(), tenantPath)
+	if err != nil {
+		h.log.Error(err, "Listing edges for the fleet map", "user", user, "workspace", tenantPath)
+		problem.Write(w, r, http.StatusBadGateway, problem.ReasonForStatus(http.StatusBadGateway), "listing edges failed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/geo+json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(buildFeatureCollection(edges))
+}
+
+// edge is one listed edge together with its kind.
+type edge struct {
+	kind string
+	obj  *unstructured.Unstructured
+}
+
+// listEdges lists every edge kind in the workspace at tenantPath. A kind
+// whose API is not bound there (the edges provider is not enabled) has no
+// edges rather than being an error.
+func (h *Handler) listEdges(ctx context.Context, tenantPath string) ([]edge, error) {
+	cfg := rest.CopyConfig(h.kcpConfig)
+	cfg.Host = apiurl.KCPClusterURL(cfg.Host, tenantPath)
+	cl, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("dynamic client for %s: %w", tenantPath, err)
+	}
+
+	var edges []edge
+	for _, k := range edgeKinds {
+		list, err := cl.Resource(k.gvr).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				continue
+			}
+			return nil, fmt.Errorf("listing %s: %w", k.gvr.Resource, err)
+		}
+		for i := range list.Items {
+			edges = append(edges, edge{kind: k.kind, obj: &list.Items[i]})
+		}
+	}
+	return edges, nil
+}
+
+// FeatureCollection is a GeoJSON (RFC 7946) feature collection.
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+// Feature is one edge. Geometry is null for an edge without coordinates,
+// which RFC 7946 allows for an unlocated feature; the dashboard lists those
+// beside the map.
+type Feature struct {
+	Type       string     `json:"type"`
+	ID         string     `json:"id"`
+	Geometry   *Point     `json:"geometry"`
+	Properties Properties `json:"properties"`
+}
+
+// Point is a GeoJSON point. Coordinates are [longitude, latitude].
+type Point struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// Properties is an edge's identity, location details and status.
+type Properties struct {
+	Name              string `json:"name"`
+	Kind              string `json:"kind"`
+	Phase             string `json:"phase,omitempty"`
+	Connected         bool   `json:"connected"`
+	Region            string `json:"region,omitempty"`
+	Address           string `json:"address,omitempty"`
+	AgentVersion      string `json:"agentVersion,omitempty"`
+	LastHeartbeatTime string `json:"lastHeartbeatTime,omitempty"`
+}
+
+// buildFeatureCollection renders edges as GeoJSON, ordered by kind and name
+// so the response is stable.
+func buildFeatureCollection(edges []edge) FeatureCollection {
+	fc := FeatureCollection{Type: "FeatureCollection", Features: []Feature{}}
+	for _, e := range edges {
+		o := e.obj.Object
+		f := Feature{
+			Type: "Feature",
+			ID:   e.kind + "/" + e.obj.GetName(),
+			Properties: Properties{
+				Name: e.obj.GetName(),
+				Kind: e.kind,
+			},
+		}
+		f.Properties.Phase, _, _ = unstructured.NestedString(o, "status", "phase")
+		f.Properties.Connected, _, _ = unstructured.NestedBool(o, "status", "connected")
+		f.Properties.AgentVersion, _, _ = unstructured.NestedString(o, "status", "agentVersion")
+		f.Properties.LastHeartbeatTime, _, _ = unstructured.NestedString(o, "status", "lastHeartbeatTime")
+		f.Properties.Region, _, _ = unstructured.NestedString(o, "spec", "location", "region")
+		f.Properties.Address, _, _ = unstructured.NestedString(o, "spec", "location", "address")
+		if lat, lon, ok := coordinates(o); ok {
+			f.Geometry = &Point{Type: "Point", Coordinates: [2]float64{lon, lat}}
+		}
+		fc.Features = append(fc.Features, f)
+	}
+	sort.SliceStable(fc.Features, func(i, j int) bool {
+		a, b := fc.Features[i].Properties, fc.Features[j].Properties
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+	return fc
+}
+
+// coordinates reads spec.location's latitude and longitude. JSON decoding
+// yields int64 for whole numbers, so both number types are accepted.
+func coordinates(obj map[string]interface{}) (lat, lon float64, ok bool) {
+	rawLat, _, _ := unstructured.NestedFieldNoCopy(obj, "spec", "location", "latitude")
+	rawLon, _, _ := unstructured.NestedFieldNoCopy(obj, "spec", "location", "longitude")
+	lat, okLat := number(rawLat)
+	lon, okLon := number(rawLon)
+	return lat, lon, okLat && okLon
+}
+
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
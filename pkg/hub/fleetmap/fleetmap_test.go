@@ -0,0 +1,123 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fleetmap
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/faroshq/faros-kedge/pkg/hub/providers"
+)
+
+func mustEdge(t *testing.T, kind, doc string) edge {
+	t.Helper()
+	obj := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal([]byte(doc), &obj.Object); err != nil {
+		t.Fatal(err)
+	}
+	return edge{kind: kind, obj: obj}
+}
+
+func TestBuildFeatureCollection(t *testing.T) {
+	fc := buildFeatureCollection([]edge{
+		mustEdge(t, "LinuxServer", `
+metadata: {name: pos-terminal}
+status: {phase: Disconnected, connected: false}
+`),
+		mustEdge(t, "KubernetesCluster", `
+metadata: {name: store-berlin}
+spec:
+  location: {latitude: 52.52, longitude: 13.405, address: Alexanderplatz 1, region: eu-central}
+status: {phase: Ready, connected: true, agentVersion: v0.4.0, lastHeartbeatTime: "2026-10-16T09:00:00Z"}
+`),
+		mustEdge(t, "KubernetesCluster", `
+metadata: {name: store-accra}
+spec:
+  location: {latitude: 5, longitude: 0, region: af-west}
+`),
+	})
+
+	// Round-trip through JSON the way the handler serves it.
+	b, err := json.Marshal(fc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Type     string `json:"type"`
+		Features []struct {
+			ID       string `json:"id"`
+			Geometry *struct {
+				Type        string    `json:"type"`
+				Coordinates []float64 `json:"coordinates"`
+			} `json:"geometry"`
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Type != "FeatureCollection" || len(doc.Features) != 3 {
+		t.Fatalf("got %s with %d features", doc.Type, len(doc.Features))
+	}
+
+	for i, id := range []string{"KubernetesCluster/store-accra", "KubernetesCluster/store-berlin", "LinuxServer/pos-terminal"} {
+		if doc.Features[i].ID != id {
+			t.Errorf("feature %d = %s, want %s", i, doc.Features[i].ID, id)
+		}
+	}
+	accra, berlin, pos := doc.Features[0], doc.Features[1], doc.Features[2]
+	if g := accra.Geometry; g == nil || g.Coordinates[0] != 0 || g.Coordinates[1] != 5 {
+		t.Errorf("whole-number coordinates: geometry = %+v", g)
+	}
+	if g := berlin.Geometry; g == nil || g.Type != "Point" || g.Coordinates[0] != 13.405 || g.Coordinates[1] != 52.52 {
+		t.Errorf("geometry = %+v, want Point [13.405, 52.52]", g)
+	}
+	for key, want := range map[string]interface{}{
+		"name": "store-berlin", "kind": "KubernetesCluster", "phase": "Ready", "connected": true,
+		"region": "eu-central", "address": "Alexanderplatz 1", "agentVersion": "v0.4.0",
+		"lastHeartbeatTime": "2026-10-16T09:00:00Z",
+	} {
+		if got := berlin.Properties[key]; got != want {
+			t.Errorf("properties.%s = %v, want %v", key, got, want)
+		}
+	}
+	if pos.Geometry != nil {
+		t.Errorf("edge without a location has geometry %+v", pos.Geometry)
+	}
+	if pos.Properties["connected"] != false {
+		t.Errorf("connected = %v, want false", pos.Properties["connected"])
+	}
+}
+
+func TestServeRequiresCaller(t *testing.T) {
+	resolver := providers.TenantResolverFunc(func(*http.Request) (string, string, error) {
+		return "", "", errors.New("anonymous caller")
+	})
+	h := NewHandler(nil, resolver, logr.Discard())
+	rec := httptest.NewRecorder()
+	h.serve(rec, httptest.NewRequest(http.MethodGet, PathPrefix, nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("anonymous fleet map request = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
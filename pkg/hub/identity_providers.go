@@ -0,0 +1,142 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hub
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultIdentityProviderName names the primary identity provider when
+// --idp-name does not.
+const DefaultIdentityProviderName = "default"
+
+// IdentityProvider is an OIDC identity provider users sign in with.
+type IdentityProvider struct {
+	// Name identifies the provider: kedge login --idp <name> signs in with
+	// it.
+	Name        string `json:"name"`
+	IssuerURL   string `json:"issuerURL"`
+	ClientID    string `json:"clientID"`
+	CAFile      string `json:"caFile,omitempty"`
+	GroupsClaim string `json:"groupsClaim,omitempty"`
+	// Namespace keeps the provider's users and groups apart from other
+	// providers': their kcp usernames are "kedge:<namespace>:<email>" and
+	// their groups "kedge:<namespace>:<group>". It is the Name of every
+	// provider but the primary, whose users keep "kedge:<email>".
+	Namespace string `json:"-"`
+}
+
+// IdentityProviderConfig is the --idp-config file:
+//
+//	identityProviders:
+//	- name: partners
+//	  issuerURL: https://login.partner.example.com
+//	  clientID: kedge
+//	  caFile: /etc/kedge/partners-ca.pem   # optional
+//	  groupsClaim: groups                  # optional, --idp-groups-claim
+type IdentityProviderConfig struct {
+	IdentityProviders []IdentityProvider `json:"identityProviders"`
+}
+
+// IdentityProviders returns the identity providers of --idp-issuer-url,
+// followed by those of --idp-config, in the order the hub tries them. The
+// first is the primary: it is named DefaultIdentityProviderName unless
+// --idp-name names it, and its users keep the unqualified "kedge:<email>"
+// identity of a hub with a single provider. Every other provider must be
+// named, and its name namespaces its users and groups. It returns nil when
+// no provider is configured.
+func (o *Options) IdentityProviders() ([]IdentityProvider, error) {
+	n := len(o.IDPIssuerURLs)
+	if len(o.IDPClientIDs) > 1 && len(o.IDPClientIDs) != n {
+		return nil, fmt.Errorf("--idp-client-id is given %d times for %d --idp-issuer-url: give it once, or once per issuer", len(o.IDPClientIDs), n)
+	}
+	if len(o.IDPNames) > n {
+		return nil, fmt.Errorf("--idp-name is given %d times for %d --idp-issuer-url", len(o.IDPNames), n)
+	}
+
+	var idps []IdentityProvider
+	for i, issuer := range o.IDPIssuerURLs {
+		idp := IdentityProvider{
+			IssuerURL:   issuer,
+			CAFile:      o.IDPCAFile,
+			GroupsClaim: o.IDPGroupsClaim,
+		}
+		if i < len(o.IDPNames) {
+			idp.Name = o.IDPNames[i]
+		}
+		switch len(o.IDPClientIDs) {
+		case 0:
+		case 1:
+			idp.ClientID = o.IDPClientIDs[0]
+		default:
+			idp.ClientID = o.IDPClientIDs[i]
+		}
+		idps = append(idps, idp)
+	}
+
+	if o.IDPConfigFile != "" {
+		data, err := os.ReadFile(o.IDPConfigFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading --idp-config: %w", err)
+		}
+		var config IdentityProviderConfig
+		if err := yaml.UnmarshalStrict(data, &config); err != nil {
+			return nil, fmt.Errorf("parsing --idp-config %s: %w", o.IDPConfigFile, err)
+		}
+		for _, idp := range config.IdentityProviders {
+			if idp.GroupsClaim == "" {
+				idp.GroupsClaim = o.IDPGroupsClaim
+			}
+			idps = append(idps, idp)
+		}
+	}
+
+	names := map[string]bool{}
+	issuers := map[string]bool{}
+	for i := range idps {
+		idp := &idps[i]
+		if i == 0 && idp.Name == "" {
+			idp.Name = DefaultIdentityProviderName
+		}
+		if i > 0 {
+			idp.Namespace = idp.Name
+		}
+		switch {
+		case idp.IssuerURL == "":
+			return nil, fmt.Errorf("identity provider %d has no issuer URL", i+1)
+		case idp.ClientID == "":
+			return nil, fmt.Errorf("identity provider %s has no client ID", idp.IssuerURL)
+		case idp.Name == "":
+			return nil, fmt.Errorf("identity provider %s needs a name (--idp-name, or name in --idp-config): every provider but the first is named to keep its users apart", idp.IssuerURL)
+		case names[idp.Name]:
+			return nil, fmt.Errorf("identity provider name %q is used twice", idp.Name)
+		case issuers[idp.IssuerURL]:
+			return nil, fmt.Errorf("identity provider %s is configured twice", idp.IssuerURL)
+		}
+		if errs := validation.IsDNS1123Label(idp.Name); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid identity provider name %q: %s", idp.Name, strings.Join(errs, "; "))
+		}
+		names[idp.Name] = true
+		issuers[idp.IssuerURL] = true
+	}
+	return idps, nil
+}
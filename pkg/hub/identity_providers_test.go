@@ -0,0 +1,100 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hub
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIdentityProviders(t *testing.T) {
+	opts := &Options{
+		IDPIssuerURLs:  []string{"https://dex.example.com", "https://login.partner.example.com"},
+		IDPClientIDs:   []string{"kedge"},
+		IDPNames:       []string{"", "partners"},
+		IDPCAFile:      "/ca.pem",
+		IDPGroupsClaim: "groups",
+	}
+	configFile := filepath.Join(t.TempDir(), "idps.yaml")
+	if err := os.WriteFile(configFile, []byte(`identityProviders:
+- name: contractors
+  issuerURL: https://contractors.example.com
+  clientID: kedge-contractors
+  groupsClaim: teams
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	opts.IDPConfigFile = configFile
+
+	got, err := opts.IdentityProviders()
+	if err != nil {
+		t.Fatalf("IdentityProviders: %v", err)
+	}
+	want := []IdentityProvider{
+		{Name: "default", IssuerURL: "https://dex.example.com", ClientID: "kedge", CAFile: "/ca.pem", GroupsClaim: "groups"},
+		{Name: "partners", Namespace: "partners", IssuerURL: "https://login.partner.example.com", ClientID: "kedge", CAFile: "/ca.pem", GroupsClaim: "groups"},
+		{Name: "contractors", Namespace: "contractors", IssuerURL: "https://contractors.example.com", ClientID: "kedge-contractors", GroupsClaim: "teams"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("IdentityProviders:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestIdentityProvidersNone(t *testing.T) {
+	idps, err := NewOptions().IdentityProviders()
+	if err != nil || idps != nil {
+		t.Errorf("IdentityProviders() = %v, %v; want none", idps, err)
+	}
+}
+
+func TestIdentityProvidersInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		opts    Options
+		wantErr string
+	}{{
+		name:    "unnamed second issuer",
+		opts:    Options{IDPIssuerURLs: []string{"https://a", "https://b"}, IDPClientIDs: []string{"kedge"}},
+		wantErr: "needs a name",
+	}, {
+		name:    "client IDs not per issuer",
+		opts:    Options{IDPIssuerURLs: []string{"https://a", "https://b", "https://c"}, IDPClientIDs: []string{"x", "y"}, IDPNames: []string{"a", "b", "c"}},
+		wantErr: "--idp-client-id is given 2 times",
+	}, {
+		name:    "duplicate name",
+		opts:    Options{IDPIssuerURLs: []string{"https://a", "https://b"}, IDPClientIDs: []string{"kedge"}, IDPNames: []string{"corp", "corp"}},
+		wantErr: "used twice",
+	}, {
+		name:    "duplicate issuer",
+		opts:    Options{IDPIssuerURLs: []string{"https://a", "https://a"}, IDPClientIDs: []string{"kedge"}, IDPNames: []string{"a", "b"}},
+		wantErr: "configured twice",
+	}, {
+		name:    "invalid name",
+		opts:    Options{IDPIssuerURLs: []string{"https://a", "https://b"}, IDPClientIDs: []string{"kedge"}, IDPNames: []string{"a", "Corp:EU"}},
+		wantErr: "invalid identity provider name",
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.opts.IdentityProviders()
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -19,9 +19,13 @@ package kcp
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	apisv1alpha2 "github.com/kcp-dev/sdk/apis/apis/v1alpha2"
@@ -37,8 +41,10 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 
+	tenancyv1alpha1 "github.com/faroshq/faros-kedge/apis/tenancy/v1alpha1"
 	"github.com/faroshq/faros-kedge/config/kcp"
 	"github.com/faroshq/faros-kedge/pkg/apiurl"
+	hubbootstrap "github.com/faroshq/faros-kedge/pkg/hub/bootstrap"
 	"github.com/faroshq/faros-kedge/pkg/hub/providers"
 	"github.com/faroshq/faros-kedge/pkg/kcppaths"
 	"github.com/faroshq/faros-kedge/pkg/util/confighelpers"
@@ -66,6 +72,8 @@ type Bootstrapper struct {
 	config *rest.Config
 	// workspaceIdentityHash is the identity hash of the tenancy.kcp.io APIExport
 	// from the root workspace. Needed for permission claims on workspaces.
+	// Read through tenancyIdentityHash.
+	identityMu            sync.Mutex
 	workspaceIdentityHash string
 	// enabledProviders is the value of `--providers`, controlling which
 	// first-party CatalogEntries get materialized. nil/empty means "all
@@ -99,6 +107,13 @@ func (b *Bootstrapper) WithEnabledProviders(names []string) *Bootstrapper {
 	return b
 }
 
+// Fingerprint digests what Bootstrap applies: the embedded workspace,
+// schema and export manifests and the enabled providers.
+func (b *Bootstrapper) Fingerprint() string {
+	return hubbootstrap.Fingerprint(b.enabledProviders,
+		kcp.RootWorkspaceFS, kcp.KedgeWorkspaceFS, kcp.SystemWorkspaceFS, kcp.ProvidersFS, kcp.PostProvidersFS)
+}
+
 // Bootstrap creates the workspace hierarchy:
 //
 //	root:kedge                          - Root kedge workspace
@@ -108,7 +123,23 @@ func (b *Bootstrapper) WithEnabledProviders(names []string) *Bootstrapper {
 //	root:kedge:system:controllers       - ALL platform APIExports + schemas
 //	root:kedge:system:providers         - Provider + CatalogEntry objects
 //	root:kedge:system:tenants           - User/Organization/Membership objects
+//
+// It runs BootstrapWorkspaces, BootstrapSchemas and BootstrapExports in
+// order. The hub runs them as separate tracked steps instead, so a restart
+// resumes at the one that failed; each is idempotent.
 func (b *Bootstrapper) Bootstrap(ctx context.Context) error {
+	for _, step := range []func(context.Context) error{b.BootstrapWorkspaces, b.BootstrapSchemas, b.BootstrapExports} {
+		if err := step(ctx); err != nil {
+			return err
+		}
+	}
+	klog.FromContext(ctx).Info("kcp bootstrap complete")
+	return nil
+}
+
+// BootstrapWorkspaces creates root:kedge, its providers, tenants and system
+// children, and the system sub-workspaces, waiting for each to be ready.
+func (b *Bootstrapper) BootstrapWorkspaces(ctx context.Context) error {
 	logger := klog.FromContext(ctx)
 	logger.Info("Bootstrapping kcp workspace hierarchy")
 
@@ -128,8 +159,7 @@ func (b *Bootstrapper) Bootstrap(ctx context.Context) error {
 	}
 
 	// 3. Bootstrap child workspaces: providers, tenants, users.
-	kedgeConfig := configForPath(b.config, "root:kedge")
-	kedgeDynamic, kedgeDiscovery, err := newClients(kedgeConfig)
+	kedgeDynamic, kedgeDiscovery, err := newClients(configForPath(b.config, "root:kedge"))
 	if err != nil {
 		return fmt.Errorf("creating kedge clients: %w", err)
 	}
@@ -161,37 +191,24 @@ func (b *Bootstrapper) Bootstrap(ctx context.Context) error {
 			return fmt.Errorf("waiting for system:%s workspace: %w", name, err)
 		}
 	}
+	return nil
+}
 
-	// 4. Fetch tenancy.kcp.io identity hash from root workspace.
-	// The identity hash is set asynchronously by kcp after startup, so we
-	// poll until it is available rather than failing immediately.
-	logger.Info("Fetching tenancy.kcp.io identity hash from root workspace")
-	var identityHash string
-	if err := wait.PollUntilContextTimeout(ctx, 2*time.Second, 3*time.Minute, true, func(ctx context.Context) (bool, error) {
-		tenancyExport, getErr := rootDynamic.Resource(apiExportGVR).Get(ctx, "tenancy.kcp.io", metav1.GetOptions{})
-		if getErr != nil {
-			logger.V(4).Info("tenancy.kcp.io APIExport not yet available, retrying", "err", getErr)
-			return false, nil
-		}
-		h, _, _ := unstructured.NestedString(tenancyExport.Object, "status", "identityHash")
-		if h == "" {
-			logger.V(4).Info("tenancy.kcp.io APIExport has no identity hash yet, retrying")
-			return false, nil
-		}
-		identityHash = h
-		return true, nil
-	}); err != nil {
-		return fmt.Errorf("waiting for tenancy.kcp.io identity hash: %w", err)
+// BootstrapSchemas applies every platform APIResourceSchema and APIExport in
+// root:kedge:system:controllers, the single home for platform exports.
+// Requires BootstrapWorkspaces.
+func (b *Bootstrapper) BootstrapSchemas(ctx context.Context) error {
+	logger := klog.FromContext(ctx)
+
+	// 4. The __TENANCY_IDENTITY_HASH__ placeholder in the APIExport YAML is
+	//    replaced with the identity hash of tenancy.kcp.io.
+	identityHash, err := b.tenancyIdentityHash(ctx)
+	if err != nil {
+		return err
 	}
-	b.workspaceIdentityHash = identityHash
-	logger.Info("Got tenancy.kcp.io identity hash", "hash", identityHash)
 
-	// 5. Bootstrap ALL platform APIResourceSchemas + APIExports in
-	//    root:kedge:system:controllers — the single home for platform exports.
-	//    The __TENANCY_IDENTITY_HASH__ placeholder in the APIExport YAML is
-	//    replaced with the actual identity hash from step 4.
-	controllersConfig := configForPath(b.config, kcppaths.SystemControllers)
-	controllersDynamic, controllersDiscovery, err := newClients(controllersConfig)
+	// 5. Bootstrap ALL platform APIResourceSchemas + APIExports.
+	controllersDynamic, controllersDiscovery, err := newClients(configForPath(b.config, kcppaths.SystemControllers))
 	if err != nil {
 		return fmt.Errorf("creating system:controllers clients: %w", err)
 	}
@@ -207,6 +224,14 @@ func (b *Bootstrapper) Bootstrap(ctx context.Context) error {
 	); err != nil {
 		return fmt.Errorf("bootstrapping platform exports: %w", err)
 	}
+	return nil
+}
+
+// BootstrapExports binds the platform exports into the system workspaces
+// that hold their objects, creates the first-party CatalogEntries and applies
+// the artefacts that need the exports to exist. Requires BootstrapSchemas.
+func (b *Bootstrapper) BootstrapExports(ctx context.Context) error {
+	logger := klog.FromContext(ctx)
 
 	// 5b. Bind the platform exports into the workspaces that hold their
 	//     objects: system:providers binds providers.kedge.faros.sh (CatalogEntry)
@@ -240,6 +265,10 @@ func (b *Bootstrapper) Bootstrap(ctx context.Context) error {
 	//     admission resolves the binding's LogicalCluster and checks bind
 	//     RBAC at apply time, so the APIExport (created in step 5) must
 	//     exist beforehand or the apply fails with a 403 forbidden.
+	kedgeDynamic, kedgeDiscovery, err := newClients(configForPath(b.config, "root:kedge"))
+	if err != nil {
+		return fmt.Errorf("creating kedge clients: %w", err)
+	}
 	logger.Info("Bootstrapping post-providers workspace artefacts (organization WorkspaceType)")
 	if err := confighelpers.Bootstrap(ctx, kedgeDiscovery, kedgeDynamic, kcp.PostProvidersFS); err != nil {
 		return fmt.Errorf("bootstrapping post-providers artefacts: %w", err)
@@ -255,11 +284,49 @@ func (b *Bootstrapper) Bootstrap(ctx context.Context) error {
 	if err := b.ensureTenancyObjectsBinding(ctx); err != nil {
 		return fmt.Errorf("binding tenants.kedge.faros.sh in system:tenants: %w", err)
 	}
-
-	logger.Info("kcp bootstrap complete")
 	return nil
 }
 
+// tenancyIdentityHash returns the identity hash of the tenancy.kcp.io
+// APIExport in the root workspace, fetching it on first use. kcp sets the
+// hash asynchronously after startup, so the first fetch polls until it is
+// there. Cached rather than taken from BootstrapSchemas so a resumed
+// bootstrap that skips that step still has it.
+func (b *Bootstrapper) tenancyIdentityHash(ctx context.Context) (string, error) {
+	b.identityMu.Lock()
+	defer b.identityMu.Unlock()
+	if b.workspaceIdentityHash != "" {
+		return b.workspaceIdentityHash, nil
+	}
+
+	logger := klog.FromContext(ctx)
+	rootDynamic, err := dynamic.NewForConfig(b.config)
+	if err != nil {
+		return "", fmt.Errorf("creating root client: %w", err)
+	}
+	logger.Info("Fetching tenancy.kcp.io identity hash from root workspace")
+	var identityHash string
+	if err := wait.PollUntilContextTimeout(ctx, 2*time.Second, 3*time.Minute, true, func(ctx context.Context) (bool, error) {
+		tenancyExport, getErr := rootDynamic.Resource(apiExportGVR).Get(ctx, "tenancy.kcp.io", metav1.GetOptions{})
+		if getErr != nil {
+			logger.V(4).Info("tenancy.kcp.io APIExport not yet available, retrying", "err", getErr)
+			return false, nil
+		}
+		h, _, _ := unstructured.NestedString(tenancyExport.Object, "status", "identityHash")
+		if h == "" {
+			logger.V(4).Info("tenancy.kcp.io APIExport has no identity hash yet, retrying")
+			return false, nil
+		}
+		identityHash = h
+		return true, nil
+	}); err != nil {
+		return "", fmt.Errorf("waiting for tenancy.kcp.io identity hash: %w", err)
+	}
+	b.workspaceIdentityHash = identityHash
+	logger.Info("Got tenancy.kcp.io identity hash", "hash", identityHash)
+	return identityHash, nil
+}
+
 // ensureTenancyObjectsBinding creates an APIBinding to the
 // tenants.kedge.faros.sh APIExport (in root:kedge:system:controllers) inside
 // root:kedge:system:tenants. Idempotent. Without this binding the organization
@@ -273,6 +340,72 @@ func (b *Bootstrapper) ensureTenancyObjectsBinding(ctx context.Context) error {
 	return ensureExportBinding(ctx, tenancyDynamic, kcppaths.SystemControllers, "tenants.kedge.faros.sh")
 }
 
+// systemBindings are the APIBindings BootstrapExports creates, by the
+// workspace holding them.
+var systemBindings = map[string][]string{
+	kcppaths.SystemProviders: {"providers.kedge.faros.sh", "admin.kedge.faros.sh"},
+	kcppaths.SystemTenants:   {"tenants.kedge.faros.sh"},
+}
+
+// WaitForExports waits until the platform APIExports in
+// root:kedge:system:controllers are available, each with its identity hash,
+// and the system workspaces' APIBindings to them are Bound. Bootstrap only
+// creates them; until kcp has processed them, binding a tenant workspace or
+// writing a User fails. Requires BootstrapExports.
+func (b *Bootstrapper) WaitForExports(ctx context.Context) error {
+	controllersDynamic, err := dynamic.NewForConfig(configForPath(b.config, kcppaths.SystemControllers))
+	if err != nil {
+		return fmt.Errorf("creating system:controllers client: %w", err)
+	}
+	bindingClients := map[string]dynamic.Interface{}
+	for path := range systemBindings {
+		if bindingClients[path], err = dynamic.NewForConfig(configForPath(b.config, path)); err != nil {
+			return fmt.Errorf("creating %s client: %w", path, err)
+		}
+	}
+
+	return hubbootstrap.PollGate(ctx, "exports", func(ctx context.Context) error {
+		exports, err := controllersDynamic.Resource(apiExportGVR).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("listing APIExports in %s: %w", kcppaths.SystemControllers, err)
+		}
+		if len(exports.Items) == 0 {
+			return fmt.Errorf("no APIExports in %s yet", kcppaths.SystemControllers)
+		}
+		for _, export := range exports.Items {
+			if h, _, _ := unstructured.NestedString(export.Object, "status", "identityHash"); h == "" {
+				return fmt.Errorf("APIExport %s has no identity hash yet", export.GetName())
+			}
+		}
+		for path, names := range systemBindings {
+			bindings, err := bindingClients[path].Resource(apiBindingGVR).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return fmt.Errorf("listing APIBindings in %s: %w", path, err)
+			}
+			// Bindings are matched by the export they reference, as
+			// ensureExportBinding does, not by name.
+			phases := map[string]string{}
+			for _, binding := range bindings.Items {
+				exportPath, _, _ := unstructured.NestedString(binding.Object, "spec", "reference", "export", "path")
+				exportName, _, _ := unstructured.NestedString(binding.Object, "spec", "reference", "export", "name")
+				if exportPath == kcppaths.SystemControllers {
+					phases[exportName], _, _ = unstructured.NestedString(binding.Object, "status", "phase")
+				}
+			}
+			for _, name := range names {
+				phase, ok := phases[name]
+				if !ok {
+					return fmt.Errorf("no APIBinding to %s in %s", name, path)
+				}
+				if phase != string(apisv1alpha2.APIBindingPhaseBound) {
+					return fmt.Errorf("APIBinding to %s in %s is %q, not Bound", name, path, phase)
+				}
+			}
+		}
+		return nil
+	})
+}
+
 // UsersConfig returns a rest.Config targeting root:kedge:system:tenants, where
 // the User / Organization / Membership CR OBJECTS are stored (this replaces the
 // former root:kedge:users). The org *fleet* lives separately under
@@ -328,13 +461,14 @@ func (b *Bootstrapper) EnsureOrgWorkspace(ctx context.Context, orgUUID string) e
 		},
 	}
 
-	if _, err := orgsClient.Resource(workspaceGVR).Create(ctx, ws, metav1.CreateOptions{}); err != nil {
-		if !errors.IsAlreadyExists(err) {
-			return fmt.Errorf("creating Organization workspace %s: %w", orgUUID, err)
-		}
-		logger.V(4).Info("Organization workspace already exists")
-	} else {
+	created, err := createOrAdoptWorkspace(ctx, orgsClient, ws)
+	if err != nil {
+		return fmt.Errorf("ensuring Organization workspace %s: %w", orgUUID, err)
+	}
+	if created {
 		logger.Info("Created Organization workspace")
+	} else {
+		logger.V(4).Info("Organization workspace already exists")
 	}
 
 	if err := waitForWorkspaceReady(ctx, orgsClient, orgUUID); err != nil {
@@ -455,13 +589,14 @@ func (b *Bootstrapper) EnsureChildWorkspace(ctx context.Context, orgUUID, wsUUID
 		},
 	}
 
-	if _, err := orgClient.Resource(workspaceGVR).Create(ctx, ws, metav1.CreateOptions{}); err != nil {
-		if !errors.IsAlreadyExists(err) {
-			return fmt.Errorf("creating child Workspace %s in org %s: %w", wsUUID, orgUUID, err)
-		}
-		logger.V(4).Info("Child Workspace already exists")
-	} else {
+	created, err := createOrAdoptWorkspace(ctx, orgClient, ws)
+	if err != nil {
+		return fmt.Errorf("ensuring child Workspace %s in org %s: %w", wsUUID, orgUUID, err)
+	}
+	if created {
 		logger.Info("Created child Workspace")
+	} else {
+		logger.V(4).Info("Child Workspace already exists")
 	}
 
 	if err := waitForWorkspaceReady(ctx, orgClient, wsUUID); err != nil {
@@ -550,6 +685,11 @@ func (b *Bootstrapper) EnsureChildWorkspaceKedgeBinding(ctx context.Context, org
 		return fmt.Errorf("creating child workspace client: %w", err)
 	}
 
+	identityHash, err := b.tenancyIdentityHash(ctx)
+	if err != nil {
+		return err
+	}
+
 	allVerbs := []string{"get", "list", "watch", "create", "update", "delete"}
 	binding := &apisv1alpha2.APIBinding{
 		TypeMeta: metav1.TypeMeta{
@@ -576,7 +716,7 @@ func (b *Bootstrapper) EnsureChildWorkspaceKedgeBinding(ctx context.Context, org
 				// declares. The edge mount reconciler creates/deletes and
 				// Owns(&Workspace{}) the per-edge mount workspaces, so it needs
 				// the full verb set the export offers.
-				acceptedClaim("tenancy.kcp.io", "workspaces", b.workspaceIdentityHash, allVerbs),
+				acceptedClaim("tenancy.kcp.io", "workspaces", identityHash, allVerbs),
 				// apibindings (apis.kcp.io): accepted so kcp labels EVERY
 				// APIBinding in this workspace with core.faros.sh's claim label,
 				// making them visible through the core.faros.sh APIExport virtual
@@ -1441,6 +1581,236 @@ func ensureWorkspaceAdmin(ctx context.Context, tenantClient dynamic.Interface, r
 	return nil
 }
 
+// Labels and annotations on the ClusterRoleBindings
+// SyncChildWorkspaceGroupRoles manages.
+const (
+	labelDirectoryGroup      = "tenants.kedge.faros.sh/directory-group"
+	annotationDirectoryGroup = "tenants.kedge.faros.sh/group"
+)
+
+// ClusterRoles SyncChildWorkspaceGroupRoles maintains in a child Workspace
+// for the viewer and editor workspace roles. admin binds cluster-admin.
+const (
+	workspaceViewerClusterRole = "kedge:workspace:viewer"
+	workspaceEditorClusterRole = "kedge:workspace:editor"
+)
+
+// directoryGroupBindingName derives a stable, name-safe ClusterRoleBinding
+// name from a directory group, which may hold characters (spaces, slashes)
+// object names cannot.
+func directoryGroupBindingName(group string) string {
+	sum := sha256.Sum256([]byte(group))
+	return "kedge-group-" + hex.EncodeToString(sum[:])[:16]
+}
+
+// workspaceRoleClusterRole returns the ClusterRole a group with the given
+// workspace role is bound to. Unknown and empty roles are admin, the
+// default of OrganizationGroupBinding.WorkspaceRole.
+func workspaceRoleClusterRole(role string) string {
+	switch role {
+	case tenancyv1alpha1.WorkspaceRoleViewer:
+		return workspaceViewerClusterRole
+	case tenancyv1alpha1.WorkspaceRoleEditor:
+		return workspaceEditorClusterRole
+	default:
+		return "cluster-admin"
+	}
+}
+
+// SyncChildWorkspaceGroupRoles makes the directory groups bound in the
+// child team Workspace exactly the keys of roles: one ClusterRoleBinding per
+// group, for the kcp group DefaultOIDCGroupsPrefix + group, to the
+// ClusterRole of its workspace role (see workspaceRoleClusterRole), and none
+// for groups no longer listed. Only bindings carrying the directory-group
+// label are pruned, so per-user grants (EnsureChildWorkspaceAdmin) are never
+// touched.
+//
+// Binding the group rather than each member means kcp stops authorizing a
+// user the moment the IdP drops them from the group, without waiting for
+// the directory sync to catch up.
+func (b *Bootstrapper) SyncChildWorkspaceGroupRoles(ctx context.Context, orgUUID, wsUUID string, roles map[string]string) error {
+	if orgUUID == "" || wsUUID == "" {
+		return fmt.Errorf("SyncChildWorkspaceGroupRoles: orgUUID and wsUUID are required")
+	}
+	wsClient, err := dynamic.NewForConfig(configForPath(b.config, childWorkspacePath(orgUUID, wsUUID)))
+	if err != nil {
+		return fmt.Errorf("creating child workspace client: %w", err)
+	}
+	if err := syncGroupRoleBindings(ctx, wsClient, roles); err != nil {
+		return fmt.Errorf("syncing directory group bindings in %s: %w", childWorkspacePath(orgUUID, wsUUID), err)
+	}
+	return nil
+}
+
+// syncGroupRoleBindings is SyncChildWorkspaceGroupRoles against the
+// Workspace wsClient targets.
+func syncGroupRoleBindings(ctx context.Context, wsClient dynamic.Interface, roles map[string]string) error {
+	needsRoles := false
+	for _, role := range roles {
+		if workspaceRoleClusterRole(role) != "cluster-admin" {
+			needsRoles = true
+		}
+	}
+	if needsRoles {
+		if err := ensureWorkspaceRoleClusterRoles(ctx, wsClient); err != nil {
+			return err
+		}
+	}
+
+	crbs := wsClient.Resource(clusterRoleBindingGVR)
+	type wanted struct{ group, clusterRole string }
+	want := make(map[string]wanted, len(roles))
+	for group, role := range roles {
+		want[directoryGroupBindingName(group)] = wanted{group, workspaceRoleClusterRole(role)}
+	}
+	existing, err := crbs.List(ctx, metav1.ListOptions{LabelSelector: labelDirectoryGroup + "=true"})
+	if err != nil {
+		return fmt.Errorf("listing directory group bindings: %w", err)
+	}
+	have := make(map[string]bool, len(existing.Items))
+	for i := range existing.Items {
+		name := existing.Items[i].GetName()
+		// roleRef is immutable: a group whose workspace role changed gets
+		// its binding deleted here and created afresh below.
+		roleRef, _, _ := unstructured.NestedString(existing.Items[i].Object, "roleRef", "name")
+		if w, ok := want[name]; ok && roleRef == w.clusterRole {
+			have[name] = true
+			continue
+		}
+		if err := crbs.Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("deleting directory group binding %q: %w", name, err)
+		}
+	}
+
+	for name, w := range want {
+		if have[name] {
+			continue
+		}
+		crb := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "rbac.authorization.k8s.io/v1",
+			"kind":       "ClusterRoleBinding",
+			"metadata": map[string]interface{}{
+				"name":        name,
+				"labels":      map[string]interface{}{labelDirectoryGroup: "true"},
+				"annotations": map[string]interface{}{annotationDirectoryGroup: w.group},
+			},
+			"roleRef": map[string]interface{}{
+				"apiGroup": "rbac.authorization.k8s.io",
+				"kind":     "ClusterRole",
+				"name":     w.clusterRole,
+			},
+			"subjects": []interface{}{
+				map[string]interface{}{
+					"apiGroup": "rbac.authorization.k8s.io",
+					"kind":     "Group",
+					"name":     DefaultOIDCGroupsPrefix + w.group,
+				},
+			},
+		}}
+		if _, err := crbs.Create(ctx, crb, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("creating directory group binding for %q: %w", w.group, err)
+		}
+	}
+	return nil
+}
+
+// workspaceCoreResources are the core resources of a child Workspace the
+// viewer and editor roles cover. Secrets are editor-only.
+var workspaceCoreResources = []interface{}{"configmaps", "events", "namespaces", "serviceaccounts", "services"}
+
+// ensureWorkspaceRoleClusterRoles creates or updates the viewer and editor
+// ClusterRoles. Besides the core resources they cover every resource of
+// the APIs bound in the Workspace, kedge.faros.sh and enabled providers
+// alike, so they are rewritten as providers are enabled: the directory
+// sync calls this on every pass.
+func ensureWorkspaceRoleClusterRoles(ctx context.Context, wsClient dynamic.Interface) error {
+	bindings, err := wsClient.Resource(apiBindingGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("listing APIBindings: %w", err)
+	}
+	seen := map[string]bool{}
+	var groups []string
+	for i := range bindings.Items {
+		bound, _, _ := unstructured.NestedSlice(bindings.Items[i].Object, "status", "boundResources")
+		for _, r := range bound {
+			group, _, _ := unstructured.NestedString(r.(map[string]interface{}), "group")
+			if group == "" || seen[group] {
+				continue
+			}
+			seen[group] = true
+			groups = append(groups, group)
+		}
+	}
+	sort.Strings(groups)
+	boundGroups := make([]interface{}, 0, len(groups))
+	for _, g := range groups {
+		boundGroups = append(boundGroups, g)
+	}
+
+	read := []interface{}{"get", "list", "watch"}
+	write := []interface{}{"get", "list", "watch", "create", "update", "patch", "delete", "deletecollection"}
+	rule := func(apiGroups, resources, verbs []interface{}) interface{} {
+		return map[string]interface{}{"apiGroups": apiGroups, "resources": resources, "verbs": verbs}
+	}
+	viewer := []interface{}{
+		rule([]interface{}{""}, workspaceCoreResources, read),
+		rule([]interface{}{"apis.kcp.io"}, []interface{}{"apibindings"}, read),
+	}
+	editor := []interface{}{
+		rule([]interface{}{""}, append(append([]interface{}{}, workspaceCoreResources...), "secrets"), write),
+		rule([]interface{}{"apis.kcp.io"}, []interface{}{"apibindings"}, read),
+	}
+	if len(boundGroups) > 0 {
+		viewer = append(viewer, rule(boundGroups, []interface{}{"*"}, read))
+		// Every verb, so editors also reach subresources such as an edge's
+		// proxy and ssh.
+		editor = append(editor, rule(boundGroups, []interface{}{"*"}, []interface{}{"*"}))
+	}
+
+	for name, rules := range map[string][]interface{}{
+		workspaceViewerClusterRole: viewer,
+		workspaceEditorClusterRole: editor,
+	} {
+		if err := ensureClusterRoleRules(ctx, wsClient, name, rules); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureClusterRoleRules creates ClusterRole name with rules, or rewrites an
+// existing one's rules when they differ.
+func ensureClusterRoleRules(ctx context.Context, wsClient dynamic.Interface, name string, rules []interface{}) error {
+	crs := wsClient.Resource(clusterRoleGVR)
+	existing, err := crs.Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		cr := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "rbac.authorization.k8s.io/v1",
+			"kind":       "ClusterRole",
+			"metadata":   map[string]interface{}{"name": name},
+			"rules":      rules,
+		}}
+		if _, err := crs.Create(ctx, cr, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("creating ClusterRole %q: %w", name, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("getting ClusterRole %q: %w", name, err)
+	}
+	got, _, _ := unstructured.NestedSlice(existing.Object, "rules")
+	if reflect.DeepEqual(got, rules) {
+		return nil
+	}
+	if err := unstructured.SetNestedSlice(existing.Object, rules, "rules"); err != nil {
+		return fmt.Errorf("setting ClusterRole %q rules: %w", name, err)
+	}
+	if _, err := crs.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("updating ClusterRole %q: %w", name, err)
+	}
+	return nil
+}
+
 // newClients creates dynamic and discovery clients from a rest.Config.
 func newClients(cfg *rest.Config) (dynamic.Interface, discovery.DiscoveryInterface, error) {
 	dynClient, err := dynamic.NewForConfig(cfg)
@@ -1461,6 +1831,39 @@ func configForPath(base *rest.Config, clusterPath string) *rest.Config {
 	return cfg
 }
 
+// createOrAdoptWorkspace creates ws, or adopts an existing Workspace of the
+// same name when a concurrent or earlier reconcile already created it. It
+// reports whether this call created the object.
+//
+// Adoption is refused for half-created state the caller cannot converge on
+// by waiting: a Workspace that is being deleted (a retry must wait for it to
+// go away and create it fresh) or one of a different WorkspaceType (a name
+// collision, not a previous attempt of ours). Both surface as errors so the
+// controller requeues instead of blocking in waitForWorkspaceReady until
+// timeout.
+func createOrAdoptWorkspace(ctx context.Context, client dynamic.Interface, ws *unstructured.Unstructured) (bool, error) {
+	name := ws.GetName()
+	if _, err := client.Resource(workspaceGVR).Create(ctx, ws, metav1.CreateOptions{}); err == nil {
+		return true, nil
+	} else if !errors.IsAlreadyExists(err) {
+		return false, fmt.Errorf("creating workspace %s: %w", name, err)
+	}
+
+	existing, err := client.Resource(workspaceGVR).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return false, fmt.Errorf("getting existing workspace %s: %w", name, err)
+	}
+	if existing.GetDeletionTimestamp() != nil {
+		return false, fmt.Errorf("workspace %s is being deleted; retry once it is gone", name)
+	}
+	wantType, _, _ := unstructured.NestedString(ws.Object, "spec", "type", "name")
+	gotType, _, _ := unstructured.NestedString(existing.Object, "spec", "type", "name")
+	if wantType != "" && gotType != "" && gotType != wantType {
+		return false, fmt.Errorf("workspace %s exists with type %q, want %q", name, gotType, wantType)
+	}
+	return false, nil
+}
+
 // waitForWorkspaceReady polls until a workspace has phase "Ready".
 // Uses a 3-minute timeout to accommodate slower CI environments where kcp
 // workspaces may take longer to become ready after initial deployment.
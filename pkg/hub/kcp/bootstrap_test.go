@@ -16,6 +16,7 @@ package kcp
 
 import (
 	"context"
+	"reflect"
 	"testing"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -91,3 +92,113 @@ func TestEnsureBuiltinCatalogEntries_DoesNotTouchChartOwnedEntry(t *testing.T) {
 		t.Fatalf("displayName = %q, want chart-owned value", displayName)
 	}
 }
+
+func TestCreateOrAdoptWorkspace(t *testing.T) {
+	newWorkspace := func(name, typeName string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "tenancy.kcp.io/v1alpha1",
+			"kind":       "Workspace",
+			"metadata":   map[string]interface{}{"name": name},
+			"spec": map[string]interface{}{
+				"type": map[string]interface{}{"name": typeName, "path": "root:kedge"},
+			},
+		}}
+	}
+	deleting := newWorkspace("deleting", "workspace")
+	now := metav1.Now()
+	deleting.SetDeletionTimestamp(&now)
+	deleting.SetFinalizers([]string{"core.kcp.io/logicalcluster"})
+
+	scheme := runtime.NewScheme()
+	dyn := fake.NewSimpleDynamicClientWithCustomListKinds(scheme, map[schema.GroupVersionResource]string{
+		workspaceGVR: "WorkspaceList",
+	}, newWorkspace("existing", "workspace"), newWorkspace("other-type", "organization"), deleting)
+
+	cases := []struct {
+		name        string
+		typeName    string
+		wantCreated bool
+		wantErr     bool
+	}{
+		{name: "fresh", typeName: "workspace", wantCreated: true},
+		{name: "existing", typeName: "workspace"},
+		{name: "other-type", typeName: "workspace", wantErr: true},
+		{name: "deleting", typeName: "workspace", wantErr: true},
+	}
+	for _, tc := range cases {
+		created, err := createOrAdoptWorkspace(context.Background(), dyn, newWorkspace(tc.name, tc.typeName))
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tc.name, err, tc.wantErr)
+		}
+		if created != tc.wantCreated {
+			t.Errorf("%s: created = %v, want %v", tc.name, created, tc.wantCreated)
+		}
+	}
+}
+
+func TestSyncGroupRoleBindings(t *testing.T) {
+	binding := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apis.kcp.io/v1alpha2",
+		"kind":       "APIBinding",
+		"metadata":   map[string]interface{}{"name": "kedge"},
+		"status": map[string]interface{}{
+			"boundResources": []interface{}{
+				map[string]interface{}{"group": "kedge.faros.sh", "resource": "virtualworkloads"},
+				map[string]interface{}{"group": "kedge.faros.sh", "resource": "placements"},
+			},
+		},
+	}}
+	scheme := runtime.NewScheme()
+	dyn := fake.NewSimpleDynamicClientWithCustomListKinds(scheme, map[schema.GroupVersionResource]string{
+		apiBindingGVR:         "APIBindingList",
+		clusterRoleGVR:        "ClusterRoleList",
+		clusterRoleBindingGVR: "ClusterRoleBindingList",
+	}, binding)
+	ctx := context.Background()
+
+	roleRefs := func() map[string]string {
+		t.Helper()
+		list, err := dyn.Resource(clusterRoleBindingGVR).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			t.Fatalf("listing bindings: %v", err)
+		}
+		out := map[string]string{}
+		for _, crb := range list.Items {
+			group := crb.GetAnnotations()[annotationDirectoryGroup]
+			out[group], _, _ = unstructured.NestedString(crb.Object, "roleRef", "name")
+		}
+		return out
+	}
+
+	if err := syncGroupRoleBindings(ctx, dyn, map[string]string{"eng": "editor", "auditors": "viewer", "leads": "admin"}); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	want := map[string]string{"eng": workspaceEditorClusterRole, "auditors": workspaceViewerClusterRole, "leads": "cluster-admin"}
+	if got := roleRefs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("bindings: got %v, want %v", got, want)
+	}
+	viewer, err := dyn.Resource(clusterRoleGVR).Get(ctx, workspaceViewerClusterRole, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("getting viewer ClusterRole: %v", err)
+	}
+	rules, _, _ := unstructured.NestedSlice(viewer.Object, "rules")
+	last := rules[len(rules)-1].(map[string]interface{})
+	if got := last["apiGroups"]; !reflect.DeepEqual(got, []interface{}{"kedge.faros.sh"}) {
+		t.Errorf("viewer bound API groups: got %v", got)
+	}
+	for _, r := range rules {
+		for _, res := range r.(map[string]interface{})["resources"].([]interface{}) {
+			if res == "secrets" {
+				t.Errorf("viewer may read secrets")
+			}
+		}
+	}
+
+	// A role change replaces the binding; a dropped group loses it.
+	if err := syncGroupRoleBindings(ctx, dyn, map[string]string{"eng": "viewer"}); err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	if got, want := roleRefs(), map[string]string{"eng": workspaceViewerClusterRole}; !reflect.DeepEqual(got, want) {
+		t.Errorf("bindings after change: got %v, want %v", got, want)
+	}
+}
@@ -27,11 +27,14 @@ import (
 	"strings"
 
 	"github.com/kcp-dev/embeddedetcd"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apiserverv1 "k8s.io/apiserver/pkg/apis/apiserver/v1"
 	genericapiserver "k8s.io/apiserver/pkg/server"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
 
 	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
 	"github.com/kcp-dev/kcp/pkg/server"
@@ -93,8 +96,31 @@ type EmbeddedKCPOptions struct {
 	OIDCUsernamePrefix string
 	OIDCGroupsClaim    string
 	OIDCGroupsPrefix   string
+
+	// OIDCAdditionalIssuers are further identity providers kcp trusts
+	// besides OIDCIssuerURL, each with its own username and groups prefix
+	// so their users cannot collide. kcp's --oidc-* flags take a single
+	// issuer, so when any is set every issuer is written to a structured
+	// authentication configuration instead (--authentication-config).
+	OIDCAdditionalIssuers []OIDCIssuer
+}
+
+// OIDCIssuer is an identity provider whose ID tokens kcp authenticates.
+type OIDCIssuer struct {
+	IssuerURL string
+	ClientID  string
+	CAFile    string
+	// Prefix is put on the usernames and groups kcp reads from the
+	// provider's ID tokens.
+	Prefix      string
+	GroupsClaim string
 }
 
+// DefaultOIDCGroupsPrefix is the prefix kcp puts on OIDC groups claim values
+// unless EmbeddedKCPOptions.OIDCGroupsPrefix overrides it. RBAC bindings
+// for directory groups name the group with it.
+const DefaultOIDCGroupsPrefix = "kedge:"
+
 // EmbeddedKCP wraps a kcp server that runs in-process.
 type EmbeddedKCP struct {
 	opts   EmbeddedKCPOptions
@@ -203,7 +229,17 @@ func (e *EmbeddedKCP) Run(ctx context.Context) error {
 	// Configure OIDC authentication if provided. This lets kcp verify bearer
 	// tokens issued by the IdP natively, so the hub proxy can forward user
 	// tokens unchanged and have kcp enforce per-user RBAC.
-	if e.opts.OIDCIssuerURL != "" && e.opts.OIDCClientID != "" {
+	if e.opts.OIDCIssuerURL != "" && e.opts.OIDCClientID != "" && len(e.opts.OIDCAdditionalIssuers) > 0 {
+		configPath, err := e.writeAuthenticationConfig()
+		if err != nil {
+			return err
+		}
+		kcpOpts.GenericControlPlane.Authentication.AuthenticationConfigFile = configPath
+		logger.Info("OIDC authentication configured for kcp",
+			"issuer", e.opts.OIDCIssuerURL,
+			"additionalIssuers", len(e.opts.OIDCAdditionalIssuers),
+			"authenticationConfig", configPath)
+	} else if e.opts.OIDCIssuerURL != "" && e.opts.OIDCClientID != "" {
 		oidcOpts := kcpOpts.GenericControlPlane.Authentication.OIDC
 		oidcOpts.IssuerURL = e.opts.OIDCIssuerURL
 		oidcOpts.ClientID = e.opts.OIDCClientID
@@ -225,7 +261,7 @@ func (e *EmbeddedKCP) Run(ctx context.Context) error {
 		if e.opts.OIDCGroupsPrefix != "" {
 			oidcOpts.GroupsPrefix = e.opts.OIDCGroupsPrefix
 		} else {
-			oidcOpts.GroupsPrefix = "kedge:"
+			oidcOpts.GroupsPrefix = DefaultOIDCGroupsPrefix
 		}
 		if e.opts.OIDCCAFile != "" {
 			oidcOpts.CAFile = e.opts.OIDCCAFile
@@ -345,3 +381,94 @@ func (e *EmbeddedKCP) AdminConfig() *rest.Config {
 func (e *EmbeddedKCP) AdminKubeconfigPath() string {
 	return filepath.Join(e.opts.RootDir, "admin.kubeconfig")
 }
+
+// writeAuthenticationConfig writes the structured authentication
+// configuration for the OIDC issuers into RootDir and returns its path.
+func (e *EmbeddedKCP) writeAuthenticationConfig() (string, error) {
+	config, err := e.authenticationConfig()
+	if err != nil {
+		return "", err
+	}
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("encoding authentication configuration: %w", err)
+	}
+	if err := os.MkdirAll(e.opts.RootDir, 0700); err != nil {
+		return "", fmt.Errorf("creating kcp root directory: %w", err)
+	}
+	path := filepath.Join(e.opts.RootDir, "authentication-config.yaml")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", fmt.Errorf("writing authentication configuration: %w", err)
+	}
+	return path, nil
+}
+
+// authenticationConfig returns a structured authentication configuration
+// trusting OIDCIssuerURL, mapped as the --oidc-* flags would map it, and
+// each of OIDCAdditionalIssuers under its own prefix.
+func (e *EmbeddedKCP) authenticationConfig() (*apiserverv1.AuthenticationConfiguration, error) {
+	usernamePrefix := e.opts.OIDCUsernamePrefix
+	if usernamePrefix == "" {
+		usernamePrefix = "kedge:"
+	}
+	groupsPrefix := e.opts.OIDCGroupsPrefix
+	if groupsPrefix == "" {
+		groupsPrefix = DefaultOIDCGroupsPrefix
+	}
+	primary, err := jwtAuthenticator(OIDCIssuer{
+		IssuerURL:   e.opts.OIDCIssuerURL,
+		ClientID:    e.opts.OIDCClientID,
+		CAFile:      e.opts.OIDCCAFile,
+		GroupsClaim: e.opts.OIDCGroupsClaim,
+	}, e.opts.OIDCUsernameClaim, usernamePrefix, groupsPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &apiserverv1.AuthenticationConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiserverv1.SchemeGroupVersion.String(),
+			Kind:       "AuthenticationConfiguration",
+		},
+		JWT: []apiserverv1.JWTAuthenticator{primary},
+	}
+	for _, issuer := range e.opts.OIDCAdditionalIssuers {
+		jwt, err := jwtAuthenticator(issuer, e.opts.OIDCUsernameClaim, issuer.Prefix, issuer.Prefix)
+		if err != nil {
+			return nil, err
+		}
+		config.JWT = append(config.JWT, jwt)
+	}
+	return config, nil
+}
+
+// jwtAuthenticator returns the JWT authenticator for issuer. The username
+// claim defaults to email and the groups claim to groups, as for the
+// --oidc-* flags.
+func jwtAuthenticator(issuer OIDCIssuer, usernameClaim, usernamePrefix, groupsPrefix string) (apiserverv1.JWTAuthenticator, error) {
+	if usernameClaim == "" {
+		usernameClaim = "email"
+	}
+	groupsClaim := issuer.GroupsClaim
+	if groupsClaim == "" {
+		groupsClaim = "groups"
+	}
+	jwt := apiserverv1.JWTAuthenticator{
+		Issuer: apiserverv1.Issuer{
+			URL:       issuer.IssuerURL,
+			Audiences: []string{issuer.ClientID},
+		},
+		ClaimMappings: apiserverv1.ClaimMappings{
+			Username: apiserverv1.PrefixedClaimOrExpression{Claim: usernameClaim, Prefix: &usernamePrefix},
+			Groups:   apiserverv1.PrefixedClaimOrExpression{Claim: groupsClaim, Prefix: &groupsPrefix},
+		},
+	}
+	if issuer.CAFile != "" {
+		ca, err := os.ReadFile(issuer.CAFile)
+		if err != nil {
+			return jwt, fmt.Errorf("reading CA file of OIDC issuer %s: %w", issuer.IssuerURL, err)
+		}
+		jwt.Issuer.CertificateAuthority = string(ca)
+	}
+	return jwt, nil
+}
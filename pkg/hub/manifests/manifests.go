@@ -0,0 +1,377 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package manifests applies the hub's declarative bootstrap manifests: a
+// platform team's baseline configuration (policies, quotas, shared
+// VirtualWorkloads, ...) kept as YAML in a directory or behind a URL and
+// seeded into kcp workspaces when the hub starts.
+//
+// Every object names the workspace it belongs in with the
+// kedge.faros.sh/workspace annotation, e.g.
+//
+//	metadata:
+//	  annotations:
+//	    kedge.faros.sh/workspace: root:kedge:tenants:acme
+//
+// The Applier re-reads the sources and server-side applies every object
+// again on each resync, so edits to the manifests roll out and hand edits to
+// the fields they set are reverted. Objects removed from the manifests are
+// left in place.
+package manifests
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/klog/v2"
+
+	"github.com/faroshq/faros-kedge/pkg/hub/kcp"
+)
+
+const (
+	// WorkspaceAnnotation names the kcp workspace path an object is applied
+	// into. It is required on every object and not applied itself.
+	WorkspaceAnnotation = "kedge.faros.sh/workspace"
+
+	// DefaultResyncInterval is how often the manifests are re-applied.
+	DefaultResyncInterval = time.Minute
+
+	// fieldManager identifies the hub's server-side-apply writes.
+	fieldManager = "kedge-hub-bootstrap"
+
+	// maxManifestBytes bounds a single file or URL response.
+	maxManifestBytes = 16 << 20
+)
+
+// Object is one decoded manifest and the workspace it is applied into.
+type Object struct {
+	Workspace string
+	Object    *unstructured.Unstructured
+	// Source is the file or URL the object came from, for error messages.
+	Source string
+}
+
+// Load reads and decodes every source. A source is a directory (its *.yaml,
+// *.yml and *.json files, in name order, not recursing), a single file or an
+// http(s) URL. Objects are returned in source, file and document order.
+func Load(ctx context.Context, client *http.Client, sources []string) ([]Object, error) {
+	var objs []Object
+	for _, src := range sources {
+		files, err := read(ctx, client, src)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			decoded, err := decode(f.name, f.data)
+			if err != nil {
+				return nil, err
+			}
+			objs = append(objs, decoded...)
+		}
+	}
+	return objs, nil
+}
+
+type file struct {
+	name string
+	data []byte
+}
+
+func read(ctx context.Context, client *http.Client, src string) ([]file, error) {
+	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+		data, err := fetch(ctx, client, src)
+		if err != nil {
+			return nil, err
+		}
+		return []file{{name: src, data: data}}, nil
+	}
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return nil, fmt.Errorf("reading bootstrap manifests: %w", err)
+	}
+	if !info.IsDir() {
+		data, err := readFile(src)
+		if err != nil {
+			return nil, err
+		}
+		return []file{{name: src, data: data}}, nil
+	}
+
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return nil, fmt.Errorf("reading bootstrap manifests: %w", err)
+	}
+	var files []file
+	for _, e := range entries {
+		if e.IsDir() || !slices.Contains([]string{".yaml", ".yml", ".json"}, filepath.Ext(e.Name())) {
+			continue
+		}
+		name := filepath.Join(src, e.Name())
+		data, err := readFile(name)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file{name: name, data: data})
+	}
+	return files, nil
+}
+
+func readFile(name string) ([]byte, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("reading bootstrap manifests: %w", err)
+	}
+	defer f.Close() //nolint:errcheck
+	data, err := io.ReadAll(io.LimitReader(f, maxManifestBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", name, err)
+	}
+	if len(data) > maxManifestBytes {
+		return nil, fmt.Errorf("%s exceeds %d bytes", name, maxManifestBytes)
+	}
+	return data, nil
+}
+
+func fetch(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", url, err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", url, err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", url, err)
+	}
+	if len(data) > maxManifestBytes {
+		return nil, fmt.Errorf("%s exceeds %d bytes", url, maxManifestBytes)
+	}
+	return data, nil
+}
+
+// decode splits a YAML or JSON stream into objects, expanding v1 Lists and
+// skipping empty documents.
+func decode(source string, data []byte) ([]Object, error) {
+	var objs []Object
+	dec := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for i := 0; ; i++ {
+		var raw map[string]any
+		if err := dec.Decode(&raw); errors.Is(err, io.EOF) {
+			return objs, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: document %d: %w", source, i, err)
+		}
+		if len(raw) == 0 {
+			continue
+		}
+		u := &unstructured.Unstructured{Object: raw}
+		items := []unstructured.Unstructured{*u}
+		if u.IsList() {
+			list, err := u.ToList()
+			if err != nil {
+				return nil, fmt.Errorf("%s: document %d: %w", source, i, err)
+			}
+			items = list.Items
+		}
+		for j := range items {
+			obj, err := object(source, &items[j])
+			if err != nil {
+				return nil, fmt.Errorf("%s: document %d: %w", source, i, err)
+			}
+			objs = append(objs, obj)
+		}
+	}
+}
+
+func object(source string, u *unstructured.Unstructured) (Object, error) {
+	if u.GetAPIVersion() == "" || u.GetKind() == "" {
+		return Object{}, errors.New("apiVersion and kind are required")
+	}
+	if u.GetName() == "" {
+		return Object{}, fmt.Errorf("%s has no metadata.name", u.GetKind())
+	}
+	ws := u.GetAnnotations()[WorkspaceAnnotation]
+	if ws != "root" && !strings.HasPrefix(ws, "root:") {
+		return Object{}, fmt.Errorf("%s %q: annotation %s must name an absolute workspace path (root:...), got %q",
+			u.GetKind(), u.GetName(), WorkspaceAnnotation, ws)
+	}
+	annotations := u.GetAnnotations()
+	delete(annotations, WorkspaceAnnotation)
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	u.SetAnnotations(annotations)
+	return Object{Workspace: ws, Object: u, Source: source}, nil
+}
+
+// Applier keeps the bootstrap manifests applied.
+type Applier struct {
+	config     *rest.Config
+	sources    []string
+	interval   time.Duration
+	httpClient *http.Client
+
+	// clients caches a dynamic client and REST mapper per workspace.
+	clients map[string]*workspaceClient
+}
+
+type workspaceClient struct {
+	dynamic dynamic.Interface
+	mapper  meta.ResettableRESTMapper
+}
+
+// NewApplier returns an Applier for sources against the kcp admin config.
+// interval <= 0 means DefaultResyncInterval.
+func NewApplier(kcpConfig *rest.Config, sources []string, interval time.Duration) *Applier {
+	if interval <= 0 {
+		interval = DefaultResyncInterval
+	}
+	return &Applier{
+		config:     kcpConfig,
+		sources:    sources,
+		interval:   interval,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		clients:    map[string]*workspaceClient{},
+	}
+}
+
+// Run applies the manifests now and again every resync interval until ctx is
+// done. Failures are logged and retried on the next resync: a manifest may
+// target a workspace or an API that does not exist yet.
+func (a *Applier) Run(ctx context.Context) {
+	logger := klog.FromContext(ctx).WithName("bootstrap-manifests")
+	ctx = klog.NewContext(ctx, logger)
+
+	var lastDigest string
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		digest, err := a.Apply(ctx)
+		if err != nil {
+			logger.Error(err, "Applying bootstrap manifests failed")
+		} else if digest != lastDigest {
+			logger.Info("Applied bootstrap manifests", "digest", digest)
+			lastDigest = digest
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Apply loads the manifests and server-side applies each object into its
+// workspace, returning a digest of what was applied. Every object is
+// attempted; the errors are joined.
+func (a *Applier) Apply(ctx context.Context) (string, error) {
+	objs, err := Load(ctx, a.httpClient, a.sources)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	var errs []error
+	for _, o := range objs {
+		data, err := o.Object.MarshalJSON()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		h.Write([]byte(o.Workspace))
+		h.Write(data)
+		if err := a.apply(ctx, o); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s %q in %s: %w", o.Source, o.Object.GetKind(), o.Object.GetName(), o.Workspace, err))
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12], errors.Join(errs...)
+}
+
+func (a *Applier) apply(ctx context.Context, o Object) error {
+	c, err := a.clientFor(o.Workspace)
+	if err != nil {
+		return err
+	}
+	gvk := o.Object.GroupVersionKind()
+	mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		// The API may have been bound since discovery was cached.
+		c.mapper.Reset()
+		mapping, err = c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("no REST mapping for %s: %w", gvk, err)
+	}
+
+	var ri dynamic.ResourceInterface = c.dynamic.Resource(mapping.Resource)
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		ns := o.Object.GetNamespace()
+		if ns == "" {
+			ns = metav1.NamespaceDefault
+		}
+		ri = c.dynamic.Resource(mapping.Resource).Namespace(ns)
+	}
+	_, err = ri.Apply(ctx, o.Object.GetName(), o.Object, metav1.ApplyOptions{FieldManager: fieldManager, Force: true})
+	return err
+}
+
+func (a *Applier) clientFor(workspace string) (*workspaceClient, error) {
+	if c, ok := a.clients[workspace]; ok {
+		return c, nil
+	}
+	cfg := rest.CopyConfig(a.config)
+	cfg.Host = kcp.AppendClusterPath(cfg.Host, workspace)
+	dyn, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("creating dynamic client for %s: %w", workspace, err)
+	}
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("creating discovery client for %s: %w", workspace, err)
+	}
+	c := &workspaceClient{
+		dynamic: dyn,
+		mapper:  restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc)),
+	}
+	a.clients[workspace] = c
+	return c, nil
+}
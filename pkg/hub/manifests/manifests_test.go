@@ -0,0 +1,128 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const quota = `apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: baseline
+  namespace: default
+  annotations:
+    kedge.faros.sh/workspace: root:kedge:tenants:acme
+    team: platform
+spec:
+  hard:
+    pods: "10"
+`
+
+const list = `---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: a
+    annotations:
+      kedge.faros.sh/workspace: root:kedge
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: b
+    annotations:
+      kedge.faros.sh/workspace: root:kedge
+---
+`
+
+func TestLoadDirectoryAndURL(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{
+		"10-quota.yaml": quota,
+		"20-list.yml":   list,
+		"README.md":     "not a manifest",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"shared","annotations":{"kedge.faros.sh/workspace":"root"}}}`))
+	}))
+	defer srv.Close()
+
+	objs, err := Load(context.Background(), srv.Client(), []string{dir, srv.URL})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	var got []string
+	for _, o := range objs {
+		got = append(got, o.Workspace+"/"+o.Object.GetKind()+"/"+o.Object.GetName())
+	}
+	want := "root:kedge:tenants:acme/ResourceQuota/baseline root:kedge/ConfigMap/a root:kedge/ConfigMap/b root/Namespace/shared"
+	if strings.Join(got, " ") != want {
+		t.Errorf("objects = %v, want %s", got, want)
+	}
+
+	q := objs[0].Object
+	if _, ok := q.GetAnnotations()[WorkspaceAnnotation]; ok {
+		t.Error("workspace annotation should not be applied")
+	}
+	if q.GetAnnotations()["team"] != "platform" {
+		t.Errorf("other annotations should be kept, got %v", q.GetAnnotations())
+	}
+	if objs[1].Object.GetAnnotations() != nil {
+		t.Errorf("annotations = %v, want none", objs[1].Object.GetAnnotations())
+	}
+}
+
+func TestLoadRejectsInvalidManifests(t *testing.T) {
+	for name, data := range map[string]string{
+		"no workspace":       "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n",
+		"relative workspace": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n  annotations:\n    kedge.faros.sh/workspace: kedge:tenants\n",
+		"no name":            "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  annotations:\n    kedge.faros.sh/workspace: root\n",
+		"no kind":            "apiVersion: v1\nmetadata:\n  name: a\n",
+		"malformed":          "apiVersion: [v1\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "m.yaml")
+			if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Load(context.Background(), http.DefaultClient, []string{path}); err == nil {
+				t.Fatal("Load succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestLoadURLError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+	if _, err := Load(context.Background(), srv.Client(), []string{srv.URL}); err == nil {
+		t.Fatal("Load succeeded, want error for 404")
+	}
+}
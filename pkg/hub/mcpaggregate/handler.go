@@ -39,6 +39,7 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	"github.com/faroshq/faros-kedge/pkg/apiurl"
+	"github.com/faroshq/faros-kedge/pkg/problem"
 )
 
 // impl is the MCP Implementation advertised on `initialize`.
@@ -67,12 +68,12 @@ func New(opts Options) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		cluster, name, ok := parseMCPServerPath(r.URL.Path)
 		if !ok {
-			http.Error(w, "invalid path: expected /{cluster}/apis/kedge.faros.sh/v1alpha1/mcpservers/{name}/mcp", http.StatusBadRequest)
+			problem.Write(w, r, http.StatusBadRequest, problem.ReasonBadRequest, "invalid path: expected /{cluster}/apis/kedge.faros.sh/v1alpha1/mcpservers/{name}/mcp")
 			return
 		}
 		token := extractBearer(r)
 		if token == "" {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			problem.Write(w, r, http.StatusUnauthorized, problem.ReasonUnauthorized, "Unauthorized")
 			return
 		}
 
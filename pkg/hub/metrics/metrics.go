@@ -0,0 +1,118 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics holds the hub's Prometheus registry, served at /metrics
+// on the debug server (--debug-addr) together with controller-runtime's
+// reconcile and workqueue metrics of the hub's controllers.
+package metrics
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Registry is the registry hub components register their metrics with.
+var Registry = prometheus.NewRegistry()
+
+var (
+	proxyRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kedge_hub",
+		Name:      "proxy_requests_total",
+		Help:      "Requests the hub proxied, by upstream (kcp, or provider/<name> for provider backends such as the edges proxy) and response code.",
+	}, []string{"upstream", "code"})
+	proxyRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "kedge_hub",
+		Name:      "proxy_request_duration_seconds",
+		Help:      "Time the hub took to serve proxied requests, until the response headers were written, by upstream.",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 2, 12),
+	}, []string{"upstream"})
+)
+
+func init() {
+	Registry.MustRegister(proxyRequests, proxyRequestDuration)
+}
+
+// Handler serves the metrics in Registry and controller-runtime's registry.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(prometheus.Gatherers{Registry, ctrlmetrics.Registry}, promhttp.HandlerOpts{})
+}
+
+// UpstreamKCP is the upstream label of requests proxied to kcp.
+const UpstreamKCP = "kcp"
+
+// UpstreamProvider returns the upstream label of requests proxied to the
+// backend of provider name.
+func UpstreamProvider(name string) string {
+	return "provider/" + name
+}
+
+// InstrumentProxy returns next with its requests counted and timed under
+// upstream. Time is taken until the response headers are written, so a
+// watch or a tunnelled session is not charged for its whole life.
+func InstrumentProxy(upstream string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &headerRecorder{ResponseWriter: w, start: time.Now(), upstream: upstream}
+		next.ServeHTTP(rec, r)
+		rec.observe(http.StatusOK)
+	})
+}
+
+// headerRecorder observes a proxied request when its response headers are
+// written.
+type headerRecorder struct {
+	http.ResponseWriter
+	start    time.Time
+	upstream string
+	observed bool
+}
+
+func (r *headerRecorder) observe(code int) {
+	if r.observed {
+		return
+	}
+	r.observed = true
+	proxyRequests.WithLabelValues(r.upstream, strconv.Itoa(code)).Inc()
+	proxyRequestDuration.WithLabelValues(r.upstream).Observe(time.Since(r.start).Seconds())
+}
+
+func (r *headerRecorder) WriteHeader(code int) {
+	r.observe(code)
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *headerRecorder) Write(p []byte) (int, error) {
+	r.observe(http.StatusOK)
+	return r.ResponseWriter.Write(p)
+}
+
+// Hijack observes a protocol switch, as for exec and port-forward, before
+// handing the connection over.
+func (r *headerRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	r.observe(http.StatusSwitchingProtocols)
+	return http.NewResponseController(r.ResponseWriter).Hijack()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer (flushes).
+func (r *headerRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestInstrumentProxy(t *testing.T) {
+	upstream := UpstreamProvider("test")
+	h := InstrumentProxy(upstream, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("not found"))
+		case "/exec":
+			conn, _, err := http.NewResponseController(w).Hijack()
+			if err != nil {
+				t.Errorf("hijack: %v", err)
+				return
+			}
+			_, _ = conn.Write([]byte("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: test\r\n\r\n"))
+			_ = conn.Close()
+		default:
+			_, _ = w.Write([]byte("ok"))
+		}
+	}))
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	for _, path := range []string{"/ok", "/ok", "/missing", "/exec"} {
+		resp, err := http.Get(srv.URL + path)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	}
+
+	for code, want := range map[string]float64{"200": 2, "404": 1, "101": 1} {
+		if got := testutil.ToFloat64(proxyRequests.WithLabelValues(upstream, code)); got != want {
+			t.Errorf("requests with code %s = %v, want %v", code, got, want)
+		}
+	}
+	if got := testutil.CollectAndCount(proxyRequestDuration); got != 1 {
+		t.Errorf("duration series = %d, want 1", got)
+	}
+}
@@ -0,0 +1,439 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package migrate brings the objects kcp has stored up to the platform
+// APIResourceSchemas this hub build ships. A run applies the schemas, then
+// walks the system workspaces and every tenant workspace and rewrites each
+// stored kedge object at its schema's storage version, converting it first
+// when a Conversion is registered for it. It backs `kedge admin migrate`.
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/faroshq/faros-kedge/config/kcp"
+	"github.com/faroshq/faros-kedge/pkg/apiurl"
+	"github.com/faroshq/faros-kedge/pkg/kcppaths"
+)
+
+// Phase is the state of a migration run or of one workspace within it.
+type Phase string
+
+const (
+	PhasePending   Phase = "Pending"
+	PhaseRunning   Phase = "Running"
+	PhaseSucceeded Phase = "Succeeded"
+	PhaseFailed    Phase = "Failed"
+)
+
+// ErrRunning is returned by Start while a previous run is still in progress.
+var ErrRunning = errors.New("a migration is already running")
+
+// Conversion rewrites a stored object from one API version of a platform
+// resource to the schema's storage version. Register one in conversions when
+// a version bump changes a resource's shape in a way kcp cannot convert
+// itself; objects are read at From and written back at the storage version.
+type Conversion struct {
+	Resource schema.GroupResource
+	From     string
+	// Convert edits obj, read at From, into the storage version's shape.
+	// The migrator sets the apiVersion afterwards.
+	Convert func(obj *unstructured.Unstructured) error
+}
+
+// conversions are the registered stored-object conversions. There are none
+// while every platform API is at v1alpha1; the first v1alpha2 adds one per
+// resource whose shape changed, e.g.
+//
+//	{Resource: schema.GroupResource{Group: "tenants.kedge.faros.sh", Resource: "users"},
+//	 From: "v1alpha1", Convert: convertUserV1alpha1}
+var conversions []Conversion
+
+// Resource is a platform resource the migrator rewrites.
+type Resource struct {
+	schema.GroupResource
+	// StorageVersion is the version the schema stores objects at.
+	StorageVersion string
+}
+
+// GVR returns the resource at its storage version.
+func (r Resource) GVR() schema.GroupVersionResource {
+	return r.WithVersion(r.StorageVersion)
+}
+
+// WorkspaceStatus is the migration progress of one workspace.
+type WorkspaceStatus struct {
+	Path  string `json:"path"`
+	Phase Phase  `json:"phase"`
+	// Objects counts the stored objects found, Migrated those written back
+	// and Converted those a Conversion rewrote on the way.
+	Objects   int    `json:"objects"`
+	Migrated  int    `json:"migrated"`
+	Converted int    `json:"converted"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Status is the progress of the current or last migration run.
+type Status struct {
+	Phase Phase `json:"phase"`
+	// DryRun runs report what would be migrated without applying the
+	// schemas or writing any object.
+	DryRun     bool              `json:"dryRun"`
+	StartedAt  metav1.Time       `json:"startedAt"`
+	FinishedAt *metav1.Time      `json:"finishedAt,omitempty"`
+	Message    string            `json:"message,omitempty"`
+	Workspaces []WorkspaceStatus `json:"workspaces"`
+}
+
+// Workspaces is what the migrator needs from the hub's kcp bootstrapper:
+// applying the embedded schemas and enumerating the tenant workspaces.
+type Workspaces interface {
+	BootstrapSchemas(ctx context.Context) error
+	ListOrgWorkspaces(ctx context.Context) ([]string, error)
+	ListChildWorkspaces(ctx context.Context, orgUUID string) ([]string, error)
+}
+
+// Migrator runs migrations one at a time and keeps the progress of the
+// current or last run in memory.
+type Migrator struct {
+	workspaces  Workspaces
+	resources   []Resource
+	conversions []Conversion
+	// clientFor returns a dynamic client for the workspace at path.
+	clientFor func(path string) (dynamic.Interface, error)
+
+	mu      sync.RWMutex
+	running bool
+	status  *Status
+}
+
+// New returns a Migrator for the platform schemas embedded in this build,
+// talking to kcp with kcpConfig's (admin) credentials.
+func New(kcpConfig *rest.Config, workspaces Workspaces) (*Migrator, error) {
+	resources, err := PlatformResources()
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{
+		workspaces:  workspaces,
+		resources:   resources,
+		conversions: conversions,
+		clientFor: func(path string) (dynamic.Interface, error) {
+			cfg := rest.CopyConfig(kcpConfig)
+			cfg.Host = apiurl.KCPClusterURL(cfg.Host, path)
+			return dynamic.NewForConfig(cfg)
+		},
+	}, nil
+}
+
+// Status returns a copy of the current or last run's progress, or nil if
+// no migration has run since the hub started.
+func (m *Migrator) Status() *Status {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.status == nil {
+		return nil
+	}
+	s := *m.status
+	s.Workspaces = append([]WorkspaceStatus(nil), m.status.Workspaces...)
+	return &s
+}
+
+// Start begins a run in the background and returns its initial status, or
+// ErrRunning if one is in progress. The run outlives ctx's cancellation, so
+// callers can start it from a request handler.
+func (m *Migrator) Start(ctx context.Context, dryRun bool) (*Status, error) {
+	if !m.begin(dryRun) {
+		return nil, ErrRunning
+	}
+	// The outcome is recorded in Status, which is what callers poll.
+	go func() { _ = m.run(context.WithoutCancel(ctx), dryRun) }()
+	return m.Status(), nil
+}
+
+// Run migrates synchronously, returning an error if any step or workspace
+// failed. The per-workspace outcome is in Status.
+func (m *Migrator) Run(ctx context.Context, dryRun bool) error {
+	if !m.begin(dryRun) {
+		return ErrRunning
+	}
+	return m.run(ctx, dryRun)
+}
+
+func (m *Migrator) begin(dryRun bool) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.running {
+		return false
+	}
+	m.running = true
+	m.status = &Status{Phase: PhaseRunning, DryRun: dryRun, StartedAt: metav1.Now(), Workspaces: []WorkspaceStatus{}}
+	return true
+}
+
+func (m *Migrator) run(ctx context.Context, dryRun bool) (err error) {
+	logger := klog.FromContext(ctx).WithValues("dryRun", dryRun)
+	defer func() {
+		m.update(func(s *Status) {
+			now := metav1.Now()
+			s.FinishedAt = &now
+			s.Phase = PhaseSucceeded
+			s.Message = ""
+			if err != nil {
+				s.Phase = PhaseFailed
+				s.Message = err.Error()
+			}
+		})
+		m.mu.Lock()
+		m.running = false
+		m.mu.Unlock()
+		if err != nil {
+			logger.Error(err, "Schema migration failed")
+		} else {
+			logger.Info("Schema migration complete")
+		}
+	}()
+
+	if !dryRun {
+		logger.Info("Applying platform APIResourceSchemas")
+		m.update(func(s *Status) { s.Message = "Applying platform APIResourceSchemas" })
+		if err := m.workspaces.BootstrapSchemas(ctx); err != nil {
+			return fmt.Errorf("applying schemas: %w", err)
+		}
+	}
+
+	m.update(func(s *Status) { s.Message = "Listing workspaces" })
+	paths, err := m.listWorkspaces(ctx)
+	if err != nil {
+		return err
+	}
+	m.update(func(s *Status) {
+		s.Message = ""
+		for _, p := range paths {
+			s.Workspaces = append(s.Workspaces, WorkspaceStatus{Path: p, Phase: PhasePending})
+		}
+	})
+
+	failed := 0
+	for i, path := range paths {
+		m.update(func(s *Status) { s.Workspaces[i].Phase = PhaseRunning })
+		ws := WorkspaceStatus{Path: path, Phase: PhaseSucceeded}
+		if err := m.migrateWorkspace(ctx, path, dryRun, &ws); err != nil {
+			logger.Error(err, "Migrating workspace failed", "workspace", path)
+			ws.Phase = PhaseFailed
+			ws.Error = err.Error()
+			failed++
+		}
+		m.update(func(s *Status) { s.Workspaces[i] = ws })
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d workspaces failed to migrate", failed, len(paths))
+	}
+	return nil
+}
+
+func (m *Migrator) update(f func(*Status)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f(m.status)
+}
+
+// listWorkspaces returns the workspaces that hold platform objects: the
+// system workspaces, then every org and team workspace under
+// root:kedge:tenants.
+func (m *Migrator) listWorkspaces(ctx context.Context) ([]string, error) {
+	paths := []string{kcppaths.SystemTenants, kcppaths.SystemProviders}
+	orgs, err := m.workspaces.ListOrgWorkspaces(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing organizations: %w", err)
+	}
+	sort.Strings(orgs)
+	for _, org := range orgs {
+		paths = append(paths, kcppaths.OrgPath(org))
+		children, err := m.workspaces.ListChildWorkspaces(ctx, org)
+		if err != nil {
+			return nil, fmt.Errorf("listing workspaces of organization %s: %w", org, err)
+		}
+		sort.Strings(children)
+		for _, ws := range children {
+			paths = append(paths, kcppaths.WorkspacePath(org, ws))
+		}
+	}
+	return paths, nil
+}
+
+// migrateWorkspace rewrites every platform object stored in the workspace at
+// path. Writing an object back unchanged is enough to move it to the storage
+// version: the apiserver skips the write when the stored bytes would not
+// change, so objects already at the storage version cost a round trip only.
+// Resources the workspace does not bind, and objects deleted while the run
+// is in progress, are skipped.
+func (m *Migrator) migrateWorkspace(ctx context.Context, path string, dryRun bool, ws *WorkspaceStatus) error {
+	client, err := m.clientFor(path)
+	if err != nil {
+		return fmt.Errorf("creating client: %w", err)
+	}
+	for _, r := range m.resources {
+		conv := m.conversionFor(r)
+		list, err := client.Resource(readGVR(r, conv)).List(ctx, metav1.ListOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("listing %s: %w", r.GroupResource, err)
+		}
+		for i := range list.Items {
+			ws.Objects++
+			if dryRun {
+				continue
+			}
+			err := m.migrateObject(ctx, client, r, conv, &list.Items[i])
+			switch {
+			case apierrors.IsNotFound(err):
+				// Deleted since it was listed; nothing left to migrate.
+			case err != nil:
+				return err
+			default:
+				ws.Migrated++
+				if conv != nil {
+					ws.Converted++
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// migrateObject writes obj back at r's storage version, converting it first
+// when conv is set. A conflict re-reads the object and tries again.
+func (m *Migrator) migrateObject(ctx context.Context, client dynamic.Interface, r Resource, conv *Conversion, obj *unstructured.Unstructured) error {
+	read := client.Resource(readGVR(r, conv)).Namespace(obj.GetNamespace())
+	write := client.Resource(r.GVR()).Namespace(obj.GetNamespace())
+	first := true
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if !first {
+			latest, err := read.Get(ctx, obj.GetName(), metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			obj = latest
+		}
+		first = false
+		if conv != nil {
+			if err := conv.Convert(obj); err != nil {
+				return fmt.Errorf("converting from %s: %w", conv.From, err)
+			}
+		}
+		obj.SetAPIVersion(r.GVR().GroupVersion().String())
+		_, err := write.Update(ctx, obj, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("migrating %s %s: %w", r.GroupResource, objectKey(obj), err)
+	}
+	return nil
+}
+
+// readGVR is the version r's objects are read at: the conversion's source
+// version when one applies, the storage version otherwise.
+func readGVR(r Resource, conv *Conversion) schema.GroupVersionResource {
+	if conv != nil {
+		return r.WithVersion(conv.From)
+	}
+	return r.GVR()
+}
+
+func (m *Migrator) conversionFor(r Resource) *Conversion {
+	for i := range m.conversions {
+		c := &m.conversions[i]
+		if c.Resource == r.GroupResource && c.From != r.StorageVersion {
+			return c
+		}
+	}
+	return nil
+}
+
+func objectKey(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() != "" {
+		return obj.GetNamespace() + "/" + obj.GetName()
+	}
+	return obj.GetName()
+}
+
+// PlatformResources returns the platform resources served to tenant
+// workspaces by the schemas embedded in this build, at their storage
+// versions.
+func PlatformResources() ([]Resource, error) {
+	return platformResources(kcp.ProvidersFS)
+}
+
+// platformResources reads the resources and storage versions of the
+// APIResourceSchemas in fsys.
+func platformResources(fsys fs.FS) ([]Resource, error) {
+	files, err := fs.Glob(fsys, "apiresourceschema-*.yaml")
+	if err != nil {
+		return nil, err
+	}
+	var out []Resource
+	for _, name := range files {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		var s struct {
+			Spec struct {
+				Group string `json:"group"`
+				Names struct {
+					Plural string `json:"plural"`
+				} `json:"names"`
+				Versions []struct {
+					Name    string `json:"name"`
+					Storage bool   `json:"storage"`
+				} `json:"versions"`
+			} `json:"spec"`
+		}
+		if err := yaml.Unmarshal(data, &s); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", name, err)
+		}
+		r := Resource{GroupResource: schema.GroupResource{Group: s.Spec.Group, Resource: s.Spec.Names.Plural}}
+		for _, v := range s.Spec.Versions {
+			if v.Storage {
+				r.StorageVersion = v.Name
+			}
+		}
+		if r.StorageVersion == "" {
+			return nil, fmt.Errorf("%s: no storage version", name)
+		}
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].String() < out[j].String() })
+	return out, nil
+}
@@ -0,0 +1,264 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrate
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/faroshq/faros-kedge/config/kcp"
+	"github.com/faroshq/faros-kedge/pkg/kcppaths"
+)
+
+var (
+	usersGR   = schema.GroupResource{Group: "tenants.kedge.faros.sh", Resource: "users"}
+	mcpGR     = schema.GroupResource{Group: "kedge.faros.sh", Resource: "mcpservers"}
+	usersV2   = usersGR.WithVersion("v1alpha2")
+	usersV1   = usersGR.WithVersion("v1alpha1")
+	mcpV1     = mcpGR.WithVersion("v1alpha1")
+	testLists = map[schema.GroupVersionResource]string{
+		usersV1: "UserList",
+		usersV2: "UserList",
+		mcpV1:   "MCPServerList",
+	}
+)
+
+type fakeWorkspaces struct {
+	orgs     []string
+	children map[string][]string
+	applied  bool
+}
+
+func (f *fakeWorkspaces) BootstrapSchemas(context.Context) error { f.applied = true; return nil }
+func (f *fakeWorkspaces) ListOrgWorkspaces(context.Context) ([]string, error) {
+	return f.orgs, nil
+}
+func (f *fakeWorkspaces) ListChildWorkspaces(_ context.Context, org string) ([]string, error) {
+	return f.children[org], nil
+}
+
+func object(gvr schema.GroupVersionResource, kind, name string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(gvr.GroupVersion().String())
+	u.SetKind(kind)
+	u.SetName(name)
+	return u
+}
+
+// newTestMigrator serves users from system:tenants and mcpservers from the
+// team workspace; every other workspace binds neither.
+func newTestMigrator(t *testing.T, ws *fakeWorkspaces, resources []Resource, convs []Conversion, objs map[string][]runtime.Object) (*Migrator, map[string]*dynamicfake.FakeDynamicClient) {
+	t.Helper()
+	clients := map[string]*dynamicfake.FakeDynamicClient{}
+	m := &Migrator{
+		workspaces:  ws,
+		resources:   resources,
+		conversions: convs,
+		clientFor: func(path string) (dynamic.Interface, error) {
+			if c, ok := clients[path]; ok {
+				return c, nil
+			}
+			c := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), testLists, objs[path]...)
+			bound := map[string]bool{}
+			for _, o := range objs[path] {
+				bound[o.(*unstructured.Unstructured).GetKind()] = true
+			}
+			c.PrependReactor("list", "*", func(a clienttesting.Action) (bool, runtime.Object, error) {
+				r := a.GetResource()
+				if (r.Resource == "users" && !bound["User"]) || (r.Resource == "mcpservers" && !bound["MCPServer"]) {
+					return true, nil, apierrors.NewNotFound(r.GroupResource(), "")
+				}
+				return false, nil, nil
+			})
+			clients[path] = c
+			return c, nil
+		},
+	}
+	return m, clients
+}
+
+func updates(c *dynamicfake.FakeDynamicClient) []clienttesting.UpdateAction {
+	var out []clienttesting.UpdateAction
+	for _, a := range c.Actions() {
+		if u, ok := a.(clienttesting.UpdateAction); ok {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+func TestRunRewritesEveryWorkspace(t *testing.T) {
+	ws := &fakeWorkspaces{orgs: []string{"org1"}, children: map[string][]string{"org1": {"team"}}}
+	team := kcppaths.WorkspacePath("org1", "team")
+	m, clients := newTestMigrator(t, ws,
+		[]Resource{{GroupResource: mcpGR, StorageVersion: "v1alpha1"}, {GroupResource: usersGR, StorageVersion: "v1alpha1"}},
+		nil,
+		map[string][]runtime.Object{
+			kcppaths.SystemTenants: {object(usersV1, "User", "alice"), object(usersV1, "User", "bob")},
+			team:                   {object(mcpV1, "MCPServer", "default")},
+		})
+
+	if err := m.Run(context.Background(), false); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !ws.applied {
+		t.Error("schemas were not applied")
+	}
+	st := m.Status()
+	if st.Phase != PhaseSucceeded || st.FinishedAt == nil {
+		t.Fatalf("status = %s (finished %v), want Succeeded", st.Phase, st.FinishedAt)
+	}
+	want := []WorkspaceStatus{
+		{Path: kcppaths.SystemTenants, Phase: PhaseSucceeded, Objects: 2, Migrated: 2},
+		{Path: kcppaths.SystemProviders, Phase: PhaseSucceeded},
+		{Path: kcppaths.OrgPath("org1"), Phase: PhaseSucceeded},
+		{Path: team, Phase: PhaseSucceeded, Objects: 1, Migrated: 1},
+	}
+	if len(st.Workspaces) != len(want) {
+		t.Fatalf("workspaces = %+v, want %+v", st.Workspaces, want)
+	}
+	for i := range want {
+		if st.Workspaces[i] != want[i] {
+			t.Errorf("workspace %d = %+v, want %+v", i, st.Workspaces[i], want[i])
+		}
+	}
+	if n := len(updates(clients[kcppaths.SystemTenants])); n != 2 {
+		t.Errorf("system:tenants updates = %d, want 2", n)
+	}
+}
+
+func TestRunDryRunWritesNothing(t *testing.T) {
+	ws := &fakeWorkspaces{}
+	m, clients := newTestMigrator(t, ws,
+		[]Resource{{GroupResource: usersGR, StorageVersion: "v1alpha1"}},
+		nil,
+		map[string][]runtime.Object{kcppaths.SystemTenants: {object(usersV1, "User", "alice")}})
+
+	if err := m.Run(context.Background(), true); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if ws.applied {
+		t.Error("dry run applied schemas")
+	}
+	if got := m.Status().Workspaces[0]; got.Objects != 1 || got.Migrated != 0 {
+		t.Errorf("system:tenants = %+v, want 1 object, 0 migrated", got)
+	}
+	if n := len(updates(clients[kcppaths.SystemTenants])); n != 0 {
+		t.Errorf("dry run made %d updates", n)
+	}
+}
+
+func TestRunConvertsFromOlderVersion(t *testing.T) {
+	conv := Conversion{Resource: usersGR, From: "v1alpha1", Convert: func(obj *unstructured.Unstructured) error {
+		email, _, _ := unstructured.NestedString(obj.Object, "spec", "email")
+		return unstructured.SetNestedField(obj.Object, email, "spec", "primaryEmail")
+	}}
+	alice := object(usersV1, "User", "alice")
+	_ = unstructured.SetNestedField(alice.Object, "alice@example.com", "spec", "email")
+	m, clients := newTestMigrator(t, &fakeWorkspaces{},
+		[]Resource{{GroupResource: usersGR, StorageVersion: "v1alpha2"}},
+		[]Conversion{conv},
+		map[string][]runtime.Object{kcppaths.SystemTenants: {alice}})
+	// The fake tracker keeps each version apart, so accept the v1alpha2
+	// write of the object it holds at v1alpha1.
+	c, _ := m.clientFor(kcppaths.SystemTenants)
+	c.(*dynamicfake.FakeDynamicClient).PrependReactor("update", "users", func(a clienttesting.Action) (bool, runtime.Object, error) {
+		return true, a.(clienttesting.UpdateAction).GetObject(), nil
+	})
+
+	if err := m.Run(context.Background(), false); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if got := m.Status().Workspaces[0]; got.Converted != 1 {
+		t.Errorf("system:tenants = %+v, want 1 converted", got)
+	}
+	ups := updates(clients[kcppaths.SystemTenants])
+	if len(ups) != 1 {
+		t.Fatalf("updates = %d, want 1", len(ups))
+	}
+	if ups[0].GetResource() != usersV2 {
+		t.Errorf("written at %s, want %s", ups[0].GetResource(), usersV2)
+	}
+	obj := ups[0].GetObject().(*unstructured.Unstructured)
+	if obj.GetAPIVersion() != "tenants.kedge.faros.sh/v1alpha2" {
+		t.Errorf("apiVersion = %s", obj.GetAPIVersion())
+	}
+	if v, _, _ := unstructured.NestedString(obj.Object, "spec", "primaryEmail"); v != "alice@example.com" {
+		t.Errorf("spec.primaryEmail = %q", v)
+	}
+}
+
+func TestRunReportsFailedWorkspace(t *testing.T) {
+	m, _ := newTestMigrator(t, &fakeWorkspaces{},
+		[]Resource{{GroupResource: usersGR, StorageVersion: "v1alpha1"}},
+		nil,
+		map[string][]runtime.Object{kcppaths.SystemTenants: {object(usersV1, "User", "alice")}})
+	c, _ := m.clientFor(kcppaths.SystemTenants)
+	c.(*dynamicfake.FakeDynamicClient).PrependReactor("update", "users", func(clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("etcd unavailable")
+	})
+
+	if err := m.Run(context.Background(), false); err == nil {
+		t.Fatal("Run succeeded, want an error")
+	}
+	st := m.Status()
+	if st.Phase != PhaseFailed {
+		t.Errorf("phase = %s, want Failed", st.Phase)
+	}
+	if got := st.Workspaces[0]; got.Phase != PhaseFailed || got.Error == "" {
+		t.Errorf("system:tenants = %+v, want Failed with an error", got)
+	}
+	// A failed workspace does not stop the others.
+	if got := st.Workspaces[1]; got.Phase != PhaseSucceeded {
+		t.Errorf("system:providers = %+v, want Succeeded", got)
+	}
+}
+
+func TestStartRefusesConcurrentRun(t *testing.T) {
+	m := &Migrator{}
+	if !m.begin(false) {
+		t.Fatal("first begin refused")
+	}
+	if _, err := m.Start(context.Background(), false); !errors.Is(err, ErrRunning) {
+		t.Errorf("Start = %v, want ErrRunning", err)
+	}
+}
+
+func TestPlatformResources(t *testing.T) {
+	resources, err := platformResources(kcp.ProvidersFS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := map[schema.GroupResource]string{}
+	for _, r := range resources {
+		found[r.GroupResource] = r.StorageVersion
+	}
+	for _, gr := range []schema.GroupResource{usersGR, mcpGR, {Group: "admin.kedge.faros.sh", Resource: "providers"}} {
+		if found[gr] != "v1alpha1" {
+			t.Errorf("%s storage version = %q, want v1alpha1", gr, found[gr])
+		}
+	}
+}
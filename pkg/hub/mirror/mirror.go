@@ -0,0 +1,324 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mirror implements the hub's shadow-traffic mode: a sampled share of
+// read-only kcp API requests is replayed against a canary hub (or kcp) and
+// the canary's status code and latency are compared with the primary's. It
+// exists to validate proxy rewrites on production traffic before cutting
+// over; clients only ever see the primary's response.
+//
+// Only reads are mirrored (see readonly.IsRead), and not watches, which never
+// complete. The mirrored request carries the caller's headers, credentials
+// included, so the canary must trust the same identity provider; it is marked
+// with MirrorHeader. Mirroring never delays the primary: a request is simply
+// not mirrored when MaxInFlight mirrors are already outstanding.
+//
+// Requests whose status codes differ are logged as they happen; counts and
+// latencies are summarised in the log every SummaryInterval.
+package mirror
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/faroshq/faros-kedge/pkg/hub/readonly"
+)
+
+const (
+	// DefaultTimeout bounds one mirrored request.
+	DefaultTimeout = 30 * time.Second
+	// DefaultMaxInFlight bounds concurrently outstanding mirrored requests.
+	DefaultMaxInFlight = 64
+	// SummaryInterval is how often the comparison summary is logged.
+	SummaryInterval = time.Minute
+
+	// MirrorHeader marks requests sent to the canary, so it can tell shadow
+	// traffic apart.
+	MirrorHeader = "X-Kedge-Mirror"
+
+	// maxDrainBytes bounds how much of a canary response body is read; the
+	// latency compared is time to the end of the body or this limit.
+	maxDrainBytes = 64 << 20
+)
+
+// hopHeaders are connection-scoped and not copied onto the mirrored request.
+var hopHeaders = []string{
+	"Connection", "Keep-Alive", "Proxy-Connection", "Proxy-Authorization",
+	"Te", "Trailer", "Transfer-Encoding", "Upgrade",
+}
+
+// Options configure the mirroring handler.
+type Options struct {
+	// Target is the canary's base URL, e.g. https://hub-canary.example.com.
+	// Request paths and queries are appended unchanged.
+	Target string
+	// Percent of eligible requests to mirror, in (0, 100].
+	Percent float64
+	// InsecureSkipTLSVerify skips verification of the canary's serving
+	// certificate.
+	InsecureSkipTLSVerify bool
+	// Timeout and MaxInFlight bound the mirrored requests; 0 means the
+	// defaults.
+	Timeout     time.Duration
+	MaxInFlight int
+}
+
+// NewHandler returns next with the sampled share of its reads mirrored to the
+// canary in opts.
+func NewHandler(ctx context.Context, next http.Handler, opts Options) (http.Handler, error) {
+	target, err := url.Parse(opts.Target)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, fmt.Errorf("mirror target %q must be an absolute http(s) URL", opts.Target)
+	}
+	if opts.Percent <= 0 || opts.Percent > 100 {
+		return nil, fmt.Errorf("mirror percent must be in (0, 100], got %v", opts.Percent)
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.MaxInFlight <= 0 {
+		opts.MaxInFlight = DefaultMaxInFlight
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if opts.InsecureSkipTLSVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec // opt-in for canaries with self-signed certs
+	}
+	return &handler{
+		ctx:      ctx,
+		next:     next,
+		target:   target,
+		percent:  opts.Percent,
+		timeout:  opts.Timeout,
+		inFlight: make(chan struct{}, opts.MaxInFlight),
+		client: &http.Client{
+			Transport: transport,
+			// Redirects are part of the response being compared.
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		sample: func() bool { return rand.Float64()*100 < opts.Percent },
+		now:    time.Now,
+	}, nil
+}
+
+type handler struct {
+	ctx      context.Context
+	next     http.Handler
+	target   *url.URL
+	percent  float64
+	timeout  time.Duration
+	inFlight chan struct{}
+	client   *http.Client
+	sample   func() bool
+	now      func() time.Time
+
+	mu    sync.Mutex
+	stats stats
+}
+
+// result is one side of a mirrored request.
+type result struct {
+	status  int
+	latency time.Duration
+	err     error
+}
+
+// stats accumulate comparisons between summaries.
+type stats struct {
+	since            time.Time
+	mirrored         int
+	statusMismatches int
+	canaryErrors     int
+	primaryLatency   time.Duration
+	canaryLatency    time.Duration
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !eligible(r) || !h.sample() {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+	select {
+	case h.inFlight <- struct{}{}:
+	default:
+		h.next.ServeHTTP(w, r)
+		return
+	}
+
+	// Build the copy before next runs: the proxy may rewrite r.
+	mreq, cancel, err := h.mirrorRequest(r)
+	if err != nil {
+		<-h.inFlight
+		klog.FromContext(h.ctx).V(2).Info("Not mirroring request", "path", r.URL.Path, "err", err)
+		h.next.ServeHTTP(w, r)
+		return
+	}
+	canary := make(chan result, 1)
+	go func() {
+		defer func() { <-h.inFlight }()
+		defer cancel()
+		canary <- h.send(mreq)
+	}()
+
+	start := h.now()
+	rec := &statusRecorder{ResponseWriter: w}
+	h.next.ServeHTTP(rec, r)
+	primary := result{status: rec.status(), latency: h.now().Sub(start)}
+
+	method, path := r.Method, r.URL.Path
+	go h.compare(method, path, primary, canary)
+}
+
+// eligible reports whether r may be mirrored: a read that completes.
+func eligible(r *http.Request) bool {
+	if !readonly.IsRead(r) {
+		return false
+	}
+	q := r.URL.Query()
+	if w := q.Get("watch"); w == "true" || w == "1" {
+		return false
+	}
+	// Log streams follow until the container exits.
+	if f := q.Get("follow"); f == "true" || f == "1" {
+		return false
+	}
+	return true
+}
+
+// mirrorRequest copies r for the canary, detached from r's lifetime. cancel
+// releases the request's timeout.
+func (h *handler) mirrorRequest(r *http.Request) (*http.Request, context.CancelFunc, error) {
+	u := *h.target
+	u.Path = strings.TrimSuffix(h.target.Path, "/") + r.URL.Path
+	u.RawPath = ""
+	u.RawQuery = r.URL.RawQuery
+
+	ctx, cancel := context.WithTimeout(h.ctx, h.timeout)
+	req, err := http.NewRequestWithContext(ctx, r.Method, u.String(), nil)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	req.Header = r.Header.Clone()
+	for _, k := range hopHeaders {
+		req.Header.Del(k)
+	}
+	req.Header.Set(MirrorHeader, "true")
+	return req, cancel, nil
+}
+
+// send performs the mirrored request and drains its body.
+func (h *handler) send(req *http.Request) result {
+	start := h.now()
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return result{err: err}
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainBytes))
+	return result{status: resp.StatusCode, latency: h.now().Sub(start)}
+}
+
+// compare waits for the canary and records how it differed from the primary.
+func (h *handler) compare(method, path string, primary result, canary <-chan result) {
+	logger := klog.FromContext(h.ctx).WithName("mirror")
+	c := <-canary
+
+	switch {
+	case c.err != nil:
+		logger.V(2).Info("Mirrored request failed", "method", method, "path", path, "err", c.err)
+	case c.status != primary.status:
+		logger.Info("Mirrored request status differs", "method", method, "path", path,
+			"primaryStatus", primary.status, "canaryStatus", c.status,
+			"primaryLatency", primary.latency.String(), "canaryLatency", c.latency.String())
+	default:
+		logger.V(4).Info("Mirrored request matched", "method", method, "path", path, "status", c.status,
+			"primaryLatency", primary.latency.String(), "canaryLatency", c.latency.String())
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := h.now()
+	if h.stats.since.IsZero() {
+		h.stats.since = now
+	}
+	h.stats.mirrored++
+	switch {
+	case c.err != nil:
+		h.stats.canaryErrors++
+	default:
+		if c.status != primary.status {
+			h.stats.statusMismatches++
+		}
+		h.stats.primaryLatency += primary.latency
+		h.stats.canaryLatency += c.latency
+	}
+	if now.Sub(h.stats.since) >= SummaryInterval {
+		s := h.stats
+		h.stats = stats{since: now}
+		completed := s.mirrored - s.canaryErrors
+		var meanPrimary, meanCanary time.Duration
+		if completed > 0 {
+			meanPrimary = s.primaryLatency / time.Duration(completed)
+			meanCanary = s.canaryLatency / time.Duration(completed)
+		}
+		logger.Info("Mirroring summary", "target", h.target.Redacted(), "percent", h.percent,
+			"mirrored", s.mirrored, "statusMismatches", s.statusMismatches, "canaryErrors", s.canaryErrors,
+			"meanPrimaryLatency", meanPrimary.String(), "meanCanaryLatency", meanCanary.String())
+	}
+}
+
+// statusRecorder passes a response through, noting its status code.
+type statusRecorder struct {
+	http.ResponseWriter
+	code int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if r.code == 0 {
+		r.code = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	if r.code == 0 {
+		r.code = http.StatusOK
+	}
+	return r.ResponseWriter.Write(p)
+}
+
+func (r *statusRecorder) status() int {
+	if r.code == 0 {
+		return http.StatusOK
+	}
+	return r.code
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer (flushes).
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mirror
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewHandlerValidates(t *testing.T) {
+	next := http.NotFoundHandler()
+	for _, opts := range []Options{
+		{Target: "", Percent: 10},
+		{Target: "canary:8443", Percent: 10},
+		{Target: "ftp://canary", Percent: 10},
+		{Target: "https://canary", Percent: 0},
+		{Target: "https://canary", Percent: 101},
+	} {
+		if _, err := NewHandler(context.Background(), next, opts); err == nil {
+			t.Errorf("NewHandler(%+v) succeeded, want error", opts)
+		}
+	}
+}
+
+func TestMirrorsReads(t *testing.T) {
+	got := make(chan *http.Request, 1)
+	canary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got <- r
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer canary.Close()
+
+	h, err := NewHandler(context.Background(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("primary"))
+	}), Options{Target: canary.URL + "/base/", Percent: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/clusters/c/api/v1/configmaps?limit=5", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("Connection", "keep-alive")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || rec.Body.String() != "primary" {
+		t.Fatalf("client got %d %q, want the primary's response", rec.Code, rec.Body.String())
+	}
+
+	select {
+	case m := <-got:
+		if m.URL.Path != "/base/clusters/c/api/v1/configmaps" || m.URL.RawQuery != "limit=5" {
+			t.Errorf("canary got %s, want /base/clusters/c/api/v1/configmaps?limit=5", m.URL)
+		}
+		if m.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("canary Authorization = %q, want the caller's", m.Header.Get("Authorization"))
+		}
+		if m.Header.Get(MirrorHeader) != "true" {
+			t.Errorf("canary request lacks %s", MirrorHeader)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("request was not mirrored")
+	}
+}
+
+func TestSkipsIneligible(t *testing.T) {
+	mirrored := make(chan struct{}, 10)
+	canary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mirrored <- struct{}{}
+	}))
+	defer canary.Close()
+
+	served := 0
+	h, err := NewHandler(context.Background(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served++
+	}), Options{Target: canary.URL, Percent: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct{ method, path string }{
+		{http.MethodPost, "/clusters/c/api/v1/namespaces/default/configmaps"},
+		{http.MethodGet, "/clusters/c/api/v1/configmaps?watch=true"},
+		{http.MethodGet, "/clusters/c/api/v1/namespaces/default/pods/p/log?follow=true"},
+		{http.MethodGet, "/clusters/c/apis/edges.kedge.faros.sh/v1alpha1/kubernetesclusters/e/proxy/api/v1/pods"},
+	} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tc.method, tc.path, nil))
+	}
+	if served != 4 {
+		t.Errorf("primary served %d requests, want 4", served)
+	}
+	select {
+	case <-mirrored:
+		t.Error("ineligible request was mirrored")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestSkipsWhenSaturated(t *testing.T) {
+	release := make(chan struct{})
+	canary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer canary.Close()
+	defer close(release)
+
+	h, err := NewHandler(context.Background(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		Options{Target: canary.URL, Percent: 100, MaxInFlight: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hh := h.(*handler)
+
+	hh.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/namespaces", nil))
+	if len(hh.inFlight) != 1 {
+		t.Fatalf("in flight = %d, want 1", len(hh.inFlight))
+	}
+	// The slot is taken: the second request is served without mirroring.
+	hh.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/namespaces", nil))
+	if len(hh.inFlight) != 1 {
+		t.Errorf("in flight = %d, want 1", len(hh.inFlight))
+	}
+}
@@ -16,7 +16,15 @@ limitations under the License.
 
 package hub
 
-import "github.com/faroshq/faros-kedge/pkg/kcppaths"
+import (
+	"time"
+
+	"github.com/faroshq/faros-kedge/pkg/hub/manifests"
+	"github.com/faroshq/faros-kedge/pkg/hub/search"
+	"github.com/faroshq/faros-kedge/pkg/kcppaths"
+	"github.com/faroshq/faros-kedge/pkg/server/auth"
+	"github.com/faroshq/faros-kedge/pkg/server/proxy"
+)
 
 // Options holds configuration for the hub server.
 type Options struct {
@@ -24,12 +32,38 @@ type Options struct {
 	ListenAddr            string
 	Kubeconfig            string
 	ExternalKCPKubeconfig string
-	IDPIssuerURL          string
-	IDPClientID           string
+	// IDPIssuerURLs are the OIDC identity providers users sign in with,
+	// tried in this order. The first is the primary; see
+	// IdentityProviders for how each is named and namespaced.
+	IDPIssuerURLs []string
+	// IDPClientIDs are the client IDs of IDPIssuerURLs, by position. A
+	// single client ID applies to every issuer.
+	IDPClientIDs []string
+	// IDPNames name IDPIssuerURLs, by position. Every issuer after the
+	// first needs one.
+	IDPNames []string
+	// IDPConfigFile is a YAML file listing further identity providers,
+	// after those of IDPIssuerURLs. See IdentityProviderConfig.
+	IDPConfigFile string
 	// IDPCAFile is a path to a PEM-encoded CA bundle used to verify the IdP's
-	// TLS certificate. Required when IDPIssuerURL is https and uses a cert
+	// TLS certificate. Required when IDPIssuerURLs are https and use a cert
 	// not signed by a system trust anchor (e.g. the dev Dex deployment).
-	IDPCAFile       string
+	IDPCAFile string
+	// IDPGroupsClaim names the ID token claim holding the user's IdP
+	// groups. Embedded kcp reads RBAC groups from it and the directory sync
+	// group memberships.
+	IDPGroupsClaim string
+	// DirectorySync maps IdP groups (the IDPGroupsClaim claim) onto
+	// organization and workspace memberships, and workspace RBAC roles,
+	// through the organizations' spec.groupBindings. See
+	// docs/organizations.md.
+	DirectorySync bool
+	// StepUpMaxAge, when non-zero, makes interactive SSH sessions and edge
+	// deletion require an OIDC sign-in no older than this, or a second
+	// factor listed in StepUpAMR. See auth.StepUpPolicy.
+	StepUpMaxAge time.Duration
+	// StepUpAMR are the ID token amr values accepted as a second factor.
+	StepUpAMR       []string
 	ServingCertFile string
 	ServingKeyFile  string
 	HubExternalURL  string
@@ -42,6 +76,27 @@ type Options struct {
 	ProviderInternalURL string
 	DevMode             bool
 	StaticAuthTokens    []string
+	// DebugAddr, if non-empty, is the bind address for the hub's debug HTTP
+	// server exposing /metrics and the standard /debug/pprof/* endpoints. Keep
+	// it on loopback; the soak e2e suite reads goroutine and heap snapshots
+	// from it.
+	DebugAddr string
+
+	// AuthLockoutThreshold failed authentication attempts from one client
+	// IP, or with one static-token prefix, lock it out for
+	// AuthLockoutDuration, doubled on each repeat. Zero disables the
+	// lockout, as does DevMode. See auth.Lockout.
+	AuthLockoutThreshold int
+	AuthLockoutDuration  time.Duration
+	// AuthTrustedProxies are the CIDRs of load balancers whose
+	// X-Forwarded-For names the client IP for the lockout. Without them the
+	// connection's peer address is used.
+	AuthTrustedProxies []string
+
+	// ProxyRequestLimits throttle the requests the kcp API proxy forwards,
+	// per user and per workspace, with 429s, so one tenant's control loop
+	// cannot starve everyone else's access to kcp. See proxy.RequestLimits.
+	ProxyRequestLimits proxy.RequestLimits
 
 	// AdminUsers is the allowlist of platform-admin identities permitted to
 	// reach the /api/admin/* surface and the portal's /bonkers area. Each entry
@@ -57,6 +112,52 @@ type Options struct {
 	// pkg/hub/kcp.builtinEntries[].Requires.
 	Providers []string
 
+	// BootstrapManifests are directories, files or http(s) URLs of YAML
+	// manifests the hub applies into the kcp workspaces they name at
+	// startup and re-applies every BootstrapManifestsInterval, reverting
+	// drift. See pkg/hub/manifests.
+	BootstrapManifests         []string
+	BootstrapManifestsInterval time.Duration
+
+	// ReadOnlyListenAddr, when set, serves the kcp API proxy restricted to
+	// reads on a second listener, so heavy list/watch traffic can be routed
+	// and scaled apart from writes and tunnels. ReadOnlyCacheTTL > 0 caches
+	// list responses there where resourceVersion semantics allow. See
+	// pkg/hub/readonly.
+	ReadOnlyListenAddr string
+	ReadOnlyCacheTTL   time.Duration
+
+	// MirrorURL, when set, replays MirrorPercent of read-only kcp API
+	// requests against the canary hub or kcp at that URL and logs how its
+	// status codes and latency compare, to validate proxy changes on real
+	// traffic. See pkg/hub/mirror.
+	MirrorURL                   string
+	MirrorPercent               float64
+	MirrorInsecureSkipTLSVerify bool
+
+	// AuditSinks, when set, records every kcp API and provider backend
+	// request, edge cluster access included, to each sink: "stdout",
+	// "file:<path>" or "webhook:<url>". See pkg/hub/audit.
+	AuditSinks []string
+
+	// ControllerShards > 1 divides the tenant workspaces reconciled by the
+	// hub's multicluster controllers among that many hub replicas, each
+	// claiming one shard through a Lease. Replicas must share an external
+	// kcp. See pkg/hub/sharding.
+	ControllerShards int
+
+	// ControllerLeaderElection has a single controller shard claimed through
+	// its Lease too, so that with several hub replicas one runs the
+	// controllers and the others wait as standbys. Implied by
+	// ControllerShards > 1.
+	ControllerLeaderElection bool
+
+	// ShutdownGracePeriod is how long the hub, on SIGTERM, waits for
+	// in-flight requests and upgraded sessions (kubectl exec, ssh) to finish
+	// after refusing new agent tunnels and closing the ones it holds, so
+	// their agents reconnect through another replica. See pkg/hub/drain.
+	ShutdownGracePeriod time.Duration
+
 	// GraphQLAddr is the address of an external GraphQL gateway to proxy /graphql/ requests to.
 	// If empty and EmbeddedGraphQL is false, the graphql proxy is disabled.
 	GraphQLAddr string
@@ -80,6 +181,13 @@ type Options struct {
 	// by the portal. Keep this narrow; provider UIs are still same-origin through
 	// the hub, while platform-owned preview hosts can be added explicitly.
 	PortalFrameSources []string
+	// APIExplorer serves the interactive API explorer at /explorer, built from
+	// the APIResourceSchemas of the kedge and enabled provider APIExports.
+	APIExplorer bool
+	// SearchIndexTTL is how long the hub keeps a workspace's search index
+	// (/services/search) after its last search. Zero uses
+	// search.DefaultIdleTTL.
+	SearchIndexTTL time.Duration
 
 	// Embedded kcp options
 	EmbeddedKCP         bool   // Enable embedded kcp server
@@ -106,6 +214,10 @@ type Options struct {
 	KCPShardVirtualWorkspaceURL string
 }
 
+// DefaultShutdownGracePeriod leaves room within Kubernetes' default
+// terminationGracePeriodSeconds of 30s.
+const DefaultShutdownGracePeriod = 20 * time.Second
+
 // NewOptions returns default Options.
 func NewOptions() *Options {
 	return &Options{
@@ -117,10 +229,21 @@ func NewOptions() *Options {
 		KCPSecurePort:       6443,
 		KCPBindAddress:      "127.0.0.1",
 		KCPBatteriesInclude: "admin,user",
+		IDPClientIDs:        []string{"kedge"},
+		IDPGroupsClaim:      "groups",
 
 		GraphQLAPIExportSliceName:      "core.faros.sh",
 		GraphQLAPIExportLogicalCluster: kcppaths.SystemControllers,
 		GraphQLGRPCAddr:                "localhost:50051",
 		GraphQLPlayground:              true,
+		APIExplorer:                    true,
+		SearchIndexTTL:                 search.DefaultIdleTTL,
+		AuthLockoutThreshold:           auth.DefaultLockoutThreshold,
+		AuthLockoutDuration:            auth.DefaultLockoutDuration,
+		ProxyRequestLimits:             proxy.DefaultRequestLimits(),
+
+		BootstrapManifestsInterval: manifests.DefaultResyncInterval,
+		ControllerShards:           1,
+		ShutdownGracePeriod:        DefaultShutdownGracePeriod,
 	}
 }
@@ -20,6 +20,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"sort"
+
+	"github.com/faroshq/faros-kedge/pkg/problem"
 )
 
 // PathListProviders is the portal-facing list endpoint. It returns the names,
@@ -120,7 +122,7 @@ type navChildDTO struct {
 func NewListHandler(reg *Registry) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
-			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			problem.Write(w, r, http.StatusMethodNotAllowed, problem.ReasonMethodNotAllowed, "method not allowed")
 			return
 		}
 
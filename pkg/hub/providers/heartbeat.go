@@ -24,6 +24,8 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+
+	"github.com/faroshq/faros-kedge/pkg/problem"
 )
 
 // PathProviderHeartbeat is the prefix for the heartbeat endpoint. The handler
@@ -47,7 +49,7 @@ func NewHeartbeatHandler(reg *Registry, log logr.Logger) http.Handler {
 	logger := log.WithName("heartbeat")
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
-			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			problem.Write(w, r, http.StatusMethodNotAllowed, problem.ReasonMethodNotAllowed, "method not allowed")
 			return
 		}
 		name, ok := parseHeartbeatPath(r.URL.Path)
@@ -58,12 +60,12 @@ func NewHeartbeatHandler(reg *Registry, log logr.Logger) http.Handler {
 		var body heartbeatRequest
 		if r.ContentLength > 0 {
 			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&body); err != nil {
-				http.Error(w, "invalid body: "+err.Error(), http.StatusBadRequest)
+				problem.Write(w, r, http.StatusBadRequest, problem.ReasonBadRequest, "invalid body: "+err.Error())
 				return
 			}
 		}
 		if !reg.Heartbeat(name, body.Version, time.Now()) {
-			http.Error(w, "provider not found: "+name, http.StatusNotFound)
+			problem.Write(w, r, http.StatusNotFound, problem.ReasonNotFound, "provider not found: "+name)
 			return
 		}
 		logger.V(2).Info("heartbeat received", "provider", name, "version", body.Version)
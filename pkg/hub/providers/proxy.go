@@ -34,6 +34,9 @@ import (
 	"github.com/go-logr/logr"
 
 	"github.com/faroshq/faros-kedge/pkg/apiurl"
+	"github.com/faroshq/faros-kedge/pkg/hub/audit"
+	"github.com/faroshq/faros-kedge/pkg/hub/metrics"
+	"github.com/faroshq/faros-kedge/pkg/problem"
 )
 
 // NewUIProxy returns an http.Handler serving /ui/providers/{name}/* by reverse
@@ -123,6 +126,9 @@ func NewBackendProxy(reg *Registry, log logr.Logger) *ProviderProxy {
 			return
 		}
 		user, tenantPath, err := p.tenantResolver.Resolve(req)
+		if user != "" {
+			audit.SetUser(req.Context(), user, "")
+		}
 		if err != nil {
 			// Anonymous (no bearer) is common on /healthz probes
 			// and isn't worth screaming about — keep at V(2). Real
@@ -256,11 +262,11 @@ func (p *ProviderProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	prov, found := p.reg.Get(name)
 	if !found {
-		http.Error(w, "provider not found: "+name, http.StatusNotFound)
+		problem.Write(w, r, http.StatusNotFound, problem.ReasonNotFound, "provider not found: "+name)
 		return
 	}
 	if !prov.Ready() {
-		http.Error(w, "provider not ready: "+name, http.StatusServiceUnavailable)
+		problem.Write(w, r, http.StatusServiceUnavailable, problem.ReasonServiceUnavailable, "provider not ready: "+name)
 		return
 	}
 
@@ -276,7 +282,7 @@ func (p *ProviderProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	target := p.pick(prov)
 	if target == nil {
-		http.Error(w, "provider has no endpoint for this route: "+name, http.StatusNotFound)
+		problem.Write(w, r, http.StatusNotFound, problem.ReasonNotFound, "provider has no endpoint for this route: "+name)
 		return
 	}
 
@@ -304,10 +310,14 @@ func (p *ProviderProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		},
 		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
 			p.log.Error(err, "upstream error", "provider", name, "target", target.String())
-			http.Error(w, "provider upstream error", http.StatusBadGateway)
+			problem.Write(w, r, http.StatusBadGateway, problem.ReasonServiceUnavailable, "provider upstream error")
 		},
 	}
-	rp.ServeHTTP(w, r)
+	if p.fallbackForSPA {
+		rp.ServeHTTP(w, r)
+		return
+	}
+	metrics.InstrumentProxy(metrics.UpstreamProvider(name), rp).ServeHTTP(w, r)
 }
 
 // localAssetCacheControl is what we serve on embedded provider assets.
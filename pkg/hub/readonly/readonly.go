@@ -0,0 +1,284 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package readonly serves the hub's read-only endpoint: the kcp API proxy
+// restricted to reads, on a listener of its own (--read-only-listen-addr) so
+// heavy list/watch traffic — dashboards, inventory exporters, GitOps diffing —
+// can be routed, scaled and rate-limited apart from the mutating and tunnel
+// traffic on the main listener.
+//
+// Reads are GET and HEAD requests that are neither upgrades nor connect-style
+// subresources (proxy, exec, attach, portforward, ssh), which would open a
+// tunnel to an edge. Everything else is refused with 405.
+//
+// With a cache TTL set, successful responses are cached per caller where the
+// API's resourceVersion semantics allow a served-from-cache answer:
+//
+//   - resourceVersion=0 ("any"): the API server itself may answer from its
+//     watch cache, arbitrarily stale;
+//   - resourceVersionMatch=Exact: the answer for a given resourceVersion never
+//     changes.
+//
+// Requests without a resourceVersion ask for a consistent, most-recent read
+// and are never cached, and neither are watches.
+package readonly
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/faroshq/faros-kedge/pkg/problem"
+)
+
+const (
+	// DefaultMaxEntries bounds the number of cached responses.
+	DefaultMaxEntries = 1024
+	// DefaultMaxEntryBytes bounds the size of a single cached response;
+	// larger responses are served but not cached.
+	DefaultMaxEntryBytes = 4 << 20
+
+	// CacheHeader reports whether a response came from the cache ("hit") or
+	// was fetched and stored ("miss").
+	CacheHeader = "X-Kedge-Cache"
+)
+
+// connectSubresources open a stream to an edge and are never served here.
+var connectSubresources = map[string]bool{
+	"proxy":       true,
+	"exec":        true,
+	"attach":      true,
+	"portforward": true,
+	"ssh":         true,
+}
+
+// Options configure the read-only handler.
+type Options struct {
+	// CacheTTL is how long a cacheable response is reused; 0 disables the
+	// cache.
+	CacheTTL time.Duration
+	// MaxEntries and MaxEntryBytes bound the cache; 0 means the defaults.
+	MaxEntries    int
+	MaxEntryBytes int
+}
+
+// NewHandler returns next restricted to reads, with the response cache from
+// opts in front of it.
+func NewHandler(next http.Handler, opts Options) http.Handler {
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = DefaultMaxEntries
+	}
+	if opts.MaxEntryBytes <= 0 {
+		opts.MaxEntryBytes = DefaultMaxEntryBytes
+	}
+	return &handler{
+		next:    next,
+		opts:    opts,
+		now:     time.Now,
+		entries: map[string]*entry{},
+	}
+}
+
+type handler struct {
+	next http.Handler
+	opts Options
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+type entry struct {
+	status  int
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !IsRead(r) {
+		w.Header().Set("Allow", "GET, HEAD")
+		problem.Write(w, r, http.StatusMethodNotAllowed, problem.ReasonMethodNotAllowed,
+			"the read-only endpoint serves reads only; send writes, upgrades and connect subresources to the main hub endpoint")
+		return
+	}
+	if h.opts.CacheTTL <= 0 || !cacheable(r) {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+
+	key := cacheKey(r)
+	if e := h.lookup(key); e != nil {
+		for k, v := range e.header {
+			w.Header()[k] = v
+		}
+		w.Header().Set(CacheHeader, "hit")
+		w.WriteHeader(e.status)
+		if r.Method != http.MethodHead {
+			_, _ = w.Write(e.body)
+		}
+		return
+	}
+
+	w.Header().Set(CacheHeader, "miss")
+	rec := &recorder{ResponseWriter: w, limit: h.opts.MaxEntryBytes}
+	h.next.ServeHTTP(rec, r)
+	if rec.status() == http.StatusOK && !rec.overflow && r.Method == http.MethodGet {
+		header := w.Header().Clone()
+		header.Del(CacheHeader)
+		h.store(key, &entry{status: http.StatusOK, header: header, body: rec.buf.Bytes(), expires: h.now().Add(h.opts.CacheTTL)})
+	}
+}
+
+// IsRead reports whether r is a read the endpoint serves: a GET or HEAD that
+// is neither an upgrade nor a connect subresource.
+func IsRead(r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	return !IsConnect(r)
+}
+
+// IsConnect reports whether r opens a stream: an upgrade or a connect
+// subresource.
+func IsConnect(r *http.Request) bool {
+	if r.Header.Get("Upgrade") != "" {
+		return true
+	}
+	for _, seg := range strings.Split(r.URL.Path, "/") {
+		if connectSubresources[seg] {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheable reports whether the API allows r to be answered from a cache
+// (see the package comment).
+func cacheable(r *http.Request) bool {
+	q := r.URL.Query()
+	if w := q.Get("watch"); w == "true" || w == "1" {
+		return false
+	}
+	rv, match := q.Get("resourceVersion"), q.Get("resourceVersionMatch")
+	switch {
+	case rv == "0" && (match == "" || match == "NotOlderThan"):
+		return true
+	case rv != "" && match == "Exact":
+		return true
+	}
+	return false
+}
+
+// cacheKey scopes a response to the caller's credentials, the request URI and
+// the representation asked for, content coding included: the proxy passes
+// gzip through to clients that accept it (httpcompress), and a compressed
+// body must not be replayed to one that does not.
+func cacheKey(r *http.Request) string {
+	sum := sha256.New()
+	for _, part := range []string{
+		r.Header.Get("Authorization"), r.Header.Get("Cookie"), r.URL.RequestURI(), r.Header.Get("Accept"),
+		r.Header.Get("Accept-Encoding"),
+	} {
+		sum.Write([]byte(part))
+		sum.Write([]byte{0})
+	}
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+func (h *handler) lookup(key string) *entry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	e, ok := h.entries[key]
+	if !ok {
+		return nil
+	}
+	if !h.now().Before(e.expires) {
+		delete(h.entries, key)
+		return nil
+	}
+	return e
+}
+
+func (h *handler) store(key string, e *entry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.entries) >= h.opts.MaxEntries {
+		now := h.now()
+		var oldest string
+		for k, v := range h.entries {
+			if !now.Before(v.expires) {
+				delete(h.entries, k)
+				continue
+			}
+			if oldest == "" || v.expires.Before(h.entries[oldest].expires) {
+				oldest = k
+			}
+		}
+		if len(h.entries) >= h.opts.MaxEntries && oldest != "" {
+			delete(h.entries, oldest)
+		}
+	}
+	h.entries[key] = e
+}
+
+// recorder passes a response through while keeping a copy of up to limit
+// bytes of its body.
+type recorder struct {
+	http.ResponseWriter
+	code     int
+	buf      bytes.Buffer
+	limit    int
+	overflow bool
+}
+
+func (r *recorder) WriteHeader(code int) {
+	if r.code == 0 {
+		r.code = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	if r.code == 0 {
+		r.code = http.StatusOK
+	}
+	if !r.overflow {
+		if r.buf.Len()+len(p) > r.limit {
+			r.overflow = true
+			r.buf = bytes.Buffer{}
+		} else {
+			r.buf.Write(p)
+		}
+	}
+	return r.ResponseWriter.Write(p)
+}
+
+func (r *recorder) status() int {
+	if r.code == 0 {
+		return http.StatusOK
+	}
+	return r.code
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer (flushes).
+func (r *recorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
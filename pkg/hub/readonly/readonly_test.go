@@ -0,0 +1,191 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readonly
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// counter answers every request with the number of requests it has seen.
+type counter struct{ n int }
+
+func (c *counter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.n++
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = fmt.Fprintf(w, `{"n":%d}`, c.n)
+}
+
+func TestRefusesNonReads(t *testing.T) {
+	h := NewHandler(&counter{}, Options{})
+	for _, tc := range []struct {
+		method, path, upgrade string
+	}{
+		{http.MethodPost, "/clusters/c/api/v1/namespaces/default/configmaps", ""},
+		{http.MethodDelete, "/clusters/c/apis/edges.kedge.faros.sh/v1alpha1/kubernetesclusters/e", ""},
+		{http.MethodGet, "/clusters/c/apis/edges.kedge.faros.sh/v1alpha1/kubernetesclusters/e/proxy/api/v1/pods", ""},
+		{http.MethodGet, "/clusters/c/apis/edges.kedge.faros.sh/v1alpha1/linuxservers/e/ssh", ""},
+		{http.MethodGet, "/clusters/c/api/v1/namespaces/default/pods/p/exec", "websocket"},
+	} {
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		if tc.upgrade != "" {
+			req.Header.Set("Upgrade", tc.upgrade)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("%s %s = %d, want 405", tc.method, tc.path, rec.Code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/clusters/c/api/v1/configmaps?watch=true", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("watch = %d, want 200", rec.Code)
+	}
+}
+
+func TestCacheHonoursResourceVersion(t *testing.T) {
+	next := &counter{}
+	h := NewHandler(next, Options{CacheTTL: time.Minute}).(*handler)
+	now := time.Unix(1000, 0)
+	h.now = func() time.Time { return now }
+
+	get := func(uri, auth string) (string, string) {
+		req := httptest.NewRequest(http.MethodGet, uri, nil)
+		req.Header.Set("Authorization", auth)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Body.String(), rec.Header().Get(CacheHeader)
+	}
+	const list = "/clusters/c/api/v1/configmaps"
+
+	// A consistent read is always fetched.
+	if body, _ := get(list, "Bearer a"); body != `{"n":1}` {
+		t.Fatalf("body = %s", body)
+	}
+	if body, _ := get(list, "Bearer a"); body != `{"n":2}` {
+		t.Errorf("consistent read was cached: %s", body)
+	}
+
+	// resourceVersion=0 may be served from the cache.
+	if body, cache := get(list+"?resourceVersion=0", "Bearer a"); body != `{"n":3}` || cache != "miss" {
+		t.Errorf("first rv=0 = %s (%s)", body, cache)
+	}
+	if body, cache := get(list+"?resourceVersion=0", "Bearer a"); body != `{"n":3}` || cache != "hit" {
+		t.Errorf("second rv=0 = %s (%s), want cached", body, cache)
+	}
+	// ... but never across callers.
+	if body, _ := get(list+"?resourceVersion=0", "Bearer b"); body != `{"n":4}` {
+		t.Errorf("another caller got %s", body)
+	}
+	// Exact matches are cacheable, NotOlderThan a specific version is not.
+	get(list+"?resourceVersion=42&resourceVersionMatch=Exact", "Bearer a")
+	if _, cache := get(list+"?resourceVersion=42&resourceVersionMatch=Exact", "Bearer a"); cache != "hit" {
+		t.Errorf("exact match not cached")
+	}
+	if _, cache := get(list+"?resourceVersion=42", "Bearer a"); cache != "" {
+		t.Errorf("rv=42 NotOlderThan went through the cache (%s)", cache)
+	}
+
+	// Entries expire.
+	now = now.Add(2 * time.Minute)
+	if _, cache := get(list+"?resourceVersion=0", "Bearer a"); cache != "miss" {
+		t.Errorf("expired entry served (%s)", cache)
+	}
+}
+
+// encoder answers with a gzip-encoded body to clients that accept it and an
+// identity one to the rest, as the proxy does through httpcompress.
+type encoder struct{}
+
+func (encoder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		_, _ = zw.Write([]byte(`{"kind":"List"}`))
+		_ = zw.Close()
+		return
+	}
+	_, _ = w.Write([]byte(`{"kind":"List"}`))
+}
+
+func TestCacheKeepsEncodingsApart(t *testing.T) {
+	h := NewHandler(encoder{}, Options{CacheTTL: time.Minute})
+	get := func(acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/clusters/c/api/v1/configmaps?resourceVersion=0", nil)
+		req.Header.Set("Authorization", "Bearer a")
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, round := range []string{"miss", "hit"} {
+		gz := get("gzip")
+		if cache := gz.Header().Get(CacheHeader); cache != round {
+			t.Errorf("gzip: cache %q, want %q", cache, round)
+		}
+		if gz.Header().Get("Content-Encoding") != "gzip" {
+			t.Errorf("gzip %s: Content-Encoding %q", round, gz.Header().Get("Content-Encoding"))
+		}
+		zr, err := gzip.NewReader(gz.Body)
+		if err != nil {
+			t.Fatalf("gzip %s: %v", round, err)
+		}
+		if body, _ := io.ReadAll(zr); string(body) != `{"kind":"List"}` {
+			t.Errorf("gzip %s: body %s", round, body)
+		}
+
+		plain := get("")
+		if cache := plain.Header().Get(CacheHeader); cache != round {
+			t.Errorf("identity: cache %q, want %q", cache, round)
+		}
+		if plain.Header().Get("Content-Encoding") != "" || plain.Body.String() != `{"kind":"List"}` {
+			t.Errorf("identity %s: Content-Encoding %q, body %q", round, plain.Header().Get("Content-Encoding"), plain.Body.String())
+		}
+	}
+}
+
+func TestCacheBounds(t *testing.T) {
+	next := &counter{}
+	h := NewHandler(next, Options{CacheTTL: time.Minute, MaxEntries: 2, MaxEntryBytes: 4}).(*handler)
+	for i := range 3 {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/pods?resourceVersion=0&i=%d", i), nil))
+	}
+	if len(h.entries) != 0 {
+		t.Errorf("responses over MaxEntryBytes were cached: %d entries", len(h.entries))
+	}
+
+	h = NewHandler(next, Options{CacheTTL: time.Minute, MaxEntries: 2}).(*handler)
+	for i := range 3 {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/pods?resourceVersion=0&i=%d", i), nil))
+	}
+	if len(h.entries) != 2 {
+		t.Errorf("cache holds %d entries, want 2", len(h.entries))
+	}
+}
@@ -88,7 +88,7 @@ func (h *Handler) downloadKubeconfig(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	cfg, err := h.mgr.buildWorkspaceKubeconfig(tc.User, clusterName, staticToken, execCommand)
+	cfg, err := h.mgr.buildWorkspaceKubeconfig(tc.User, clusterName, staticToken, execCommand, bearerTokenIssuer(r))
 	if err != nil {
 		writeError(w, err)
 		return
@@ -123,7 +123,10 @@ func kubeconfigFilename(displayName, uuid string) string {
 
 var filenameSafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
 
-func (m *Manager) buildWorkspaceKubeconfig(userID, clusterName, staticToken, execCommand string) ([]byte, error) {
+// buildWorkspaceKubeconfig builds the kubeconfig of a workspace. In OIDC
+// mode, issuer is the caller's ID token issuer, picking the identity
+// provider the exec plugin refreshes against.
+func (m *Manager) buildWorkspaceKubeconfig(userID, clusterName, staticToken, execCommand, issuer string) ([]byte, error) {
 	cfg := clientcmdapi.NewConfig()
 	serverURL := apiurl.HubServerURL(m.kubeconfig.HubExternalURL, clusterName)
 
@@ -139,10 +142,11 @@ func (m *Manager) buildWorkspaceKubeconfig(userID, clusterName, staticToken, exe
 		}
 		// PKCE public client — no --oidc-client-secret. Matches the args
 		// pkg/server/auth.Handler.generateKubeconfig writes on first login.
+		issuerURL, clientID := m.kubeconfig.oidcClient(issuer)
 		args := []string{
 			"get-token",
-			"--oidc-issuer-url=" + m.kubeconfig.OIDCIssuerURL,
-			"--oidc-client-id=" + m.kubeconfig.OIDCClientID,
+			"--oidc-issuer-url=" + issuerURL,
+			"--oidc-client-id=" + clientID,
 		}
 		if m.kubeconfig.DevMode {
 			args = append(args, "--insecure-skip-tls-verify")
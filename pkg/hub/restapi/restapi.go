@@ -150,6 +150,19 @@ type KubeconfigConfig struct {
 	DevMode        bool
 	OIDCIssuerURL  string
 	OIDCClientID   string
+	// OIDCClientIDs maps the issuer URL of every identity provider to its
+	// client ID. A caller signed in with one of them gets a kubeconfig
+	// refreshing against it; other callers get OIDCIssuerURL's.
+	OIDCClientIDs map[string]string
+}
+
+// oidcClient returns the issuer URL and client ID the kubeconfig of a
+// caller whose ID token issuer signed refreshes against.
+func (c KubeconfigConfig) oidcClient(issuer string) (issuerURL, clientID string) {
+	if clientID, ok := c.OIDCClientIDs[issuer]; ok {
+		return issuer, clientID
+	}
+	return c.OIDCIssuerURL, c.OIDCClientID
 }
 
 // ProviderLookup is the slice of pkg/hub/providers.Registry the
@@ -160,6 +173,14 @@ type ProviderLookup interface {
 	Get(name string) (providers.Provider, bool)
 }
 
+// StepUpGuard is the slice of *proxy.KCPProxy the token handlers need
+// to hold sensitive operations to the hub's step-up policy.
+type StepUpGuard interface {
+	// RequireStepUp reports whether r may proceed; when it may not, it
+	// has already written the refusal.
+	RequireStepUp(w http.ResponseWriter, r *http.Request, operation string) bool
+}
+
 // Manager holds the dependencies every handler needs: the kedge
 // typed client (for Org / User / UMI CR access in root:kedge:users)
 // and the WorkspaceOps (kcp Bootstrapper in production; fake in tests).
@@ -168,6 +189,7 @@ type Manager struct {
 	bootstrapper WorkspaceOps
 	kubeconfig   KubeconfigConfig
 	providers    ProviderLookup // optional; nil = enableProvider returns 501
+	stepUp       StepUpGuard    // optional; nil = no step-up for admin tokens
 }
 
 // NewManager builds a Manager from the userClient (typed kedge client
@@ -195,6 +217,13 @@ func (m *Manager) WithProviderRegistry(p ProviderLookup) *Manager {
 	return m
 }
 
+// WithStepUp installs the guard that issuing an admin-scoped personal
+// access token must pass. Wired from the hub's KCPProxy.
+func (m *Manager) WithStepUp(g StepUpGuard) *Manager {
+	m.stepUp = g
+	return m
+}
+
 // Handler is the HTTP surface. One handler instance registers all
 // /api/* endpoints across the two middlewares.
 type Handler struct {
@@ -212,13 +241,21 @@ func NewHandler(mgr *Manager) *Handler { return &Handler{mgr: mgr} }
 //
 //	GET    /api/orgs                       list orgs the caller is in
 //	POST   /api/orgs                       create a new Org
+//	GET    /api/users/me                   caller identity, auth method and token expiry
 //	DELETE /api/users/me                   soft-delete self (O-8)
 //	POST   /api/users/me/undelete          undelete self (O-8)
+//	GET    /api/users/me/tokens            list own personal access tokens
+//	POST   /api/users/me/tokens            issue a personal access token
+//	DELETE /api/users/me/tokens/{id}       revoke a personal access token
 func (h *Handler) RegisterUserOnly(r *mux.Router) {
 	r.HandleFunc("/orgs", h.listOrgs).Methods(http.MethodGet)
 	r.HandleFunc("/orgs", h.createOrg).Methods(http.MethodPost)
+	r.HandleFunc("/users/me", h.getSelfUser).Methods(http.MethodGet)
 	r.HandleFunc("/users/me", h.deleteSelfUser).Methods(http.MethodDelete)
 	r.HandleFunc("/users/me/undelete", h.undeleteSelfUser).Methods(http.MethodPost)
+	r.HandleFunc("/users/me/tokens", h.listTokens).Methods(http.MethodGet)
+	r.HandleFunc("/users/me/tokens", h.createToken).Methods(http.MethodPost)
+	r.HandleFunc("/users/me/tokens/{id}", h.deleteToken).Methods(http.MethodDelete)
 }
 
 // RegisterTenantScoped attaches the routes that require an active Org
@@ -460,16 +497,19 @@ func (m *Manager) mutateUMI(ctx context.Context, userName string, mutator func(*
 
 // upsertUMIEntry adds or updates a (orgUUID, wsUUID) row in the user's
 // UMI. wsUUID="" means org-scope. The fields argument carries the
-// metadata the row should reflect.
+// metadata the row should reflect. Its Source replaces the row's, so an
+// explicit grant takes over a row the directory sync created and the
+// sync no longer revokes it.
 func (m *Manager) upsertUMIEntry(ctx context.Context, userName string, want tenancyv1alpha1.MembershipIndexEntry) error {
 	return m.mutateUMI(ctx, userName, func(idx *tenancyv1alpha1.UserMembershipIndex) bool {
 		for i := range idx.Spec.Entries {
 			e := &idx.Spec.Entries[i]
 			if e.OrgUUID == want.OrgUUID && e.WorkspaceUUID == want.WorkspaceUUID {
-				if e.Role == want.Role && e.OrgDisplayName == want.OrgDisplayName && e.WorkspaceDisplayName == want.WorkspaceDisplayName {
+				if e.Role == want.Role && e.OrgDisplayName == want.OrgDisplayName && e.WorkspaceDisplayName == want.WorkspaceDisplayName && e.Source == want.Source {
 					return false
 				}
 				e.Role = want.Role
+				e.Source = want.Source
 				if want.OrgDisplayName != "" {
 					e.OrgDisplayName = want.OrgDisplayName
 				}
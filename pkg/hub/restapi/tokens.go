@@ -0,0 +1,229 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package restapi
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	tenancyv1alpha1 "github.com/faroshq/faros-kedge/apis/tenancy/v1alpha1"
+	"github.com/faroshq/faros-kedge/pkg/server/auth"
+)
+
+const (
+	// defaultTokenLifetimeDays applies when a token request leaves
+	// expiresInDays unset.
+	defaultTokenLifetimeDays = 90
+	// maxTokenLifetimeDays caps how long a personal access token lives;
+	// tokens are meant to be rotated, not issued forever.
+	maxTokenLifetimeDays = 365
+)
+
+// CreateTokenRequest is the POST /api/users/me/tokens body.
+type CreateTokenRequest struct {
+	Description   string   `json:"description,omitempty"`
+	Scopes        []string `json:"scopes"`                  // read | write | ssh | admin
+	Cluster       string   `json:"cluster,omitempty"`       // restrict to one workspace's logical cluster
+	ExpiresInDays int      `json:"expiresInDays,omitempty"` // default 90, max 365
+}
+
+// TokenView is the REST projection of a PersonalAccessToken. Token is
+// set only in the response that issues it; the hub keeps a hash.
+type TokenView struct {
+	ID          string     `json:"id"`
+	Description string     `json:"description,omitempty"`
+	Scopes      []string   `json:"scopes"`
+	Cluster     string     `json:"cluster,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt  *time.Time `json:"lastUsedAt,omitempty"`
+	Token       string     `json:"token,omitempty"`
+}
+
+func projectToken(t *tenancyv1alpha1.PersonalAccessToken) TokenView {
+	out := TokenView{
+		ID:          t.Name,
+		Description: t.Spec.Description,
+		Scopes:      make([]string, 0, len(t.Spec.Scopes)),
+		Cluster:     t.Spec.Cluster,
+		CreatedAt:   t.CreationTimestamp.Time,
+	}
+	for _, s := range t.Spec.Scopes {
+		out.Scopes = append(out.Scopes, string(s))
+	}
+	if t.Spec.ExpirationTimestamp != nil {
+		e := t.Spec.ExpirationTimestamp.Time
+		out.ExpiresAt = &e
+	}
+	if t.Status.LastUsedTime != nil {
+		u := t.Status.LastUsedTime.Time
+		out.LastUsedAt = &u
+	}
+	return out
+}
+
+// requireTokenManager is requireUser for the token endpoints: a
+// personal access token cannot manage tokens, so it is refused.
+func (h *Handler) requireTokenManager(w http.ResponseWriter, r *http.Request) (string, bool) {
+	if method, _, _ := bearerTokenInfo(r); method == AuthMethodPersonalAccessToken {
+		writeStatus(w, http.StatusForbidden, "Forbidden", "personal access tokens cannot manage tokens — sign in interactively")
+		return "", false
+	}
+	return h.requireUser(w, r)
+}
+
+// listTokens returns the caller's personal access tokens, newest
+// first. The tokens themselves are never returned again.
+func (h *Handler) listTokens(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.requireTokenManager(w, r)
+	if !ok {
+		return
+	}
+	list, err := h.mgr.client.PersonalAccessTokens().List(r.Context(), metav1.ListOptions{
+		LabelSelector: tenancyv1alpha1.PersonalAccessTokenUserLabel + "=" + user,
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	out := make([]TokenView, 0, len(list.Items))
+	for i := range list.Items {
+		// The label is a lookup aid; spec.user is authoritative.
+		if list.Items[i].Spec.User != user {
+			continue
+		}
+		out = append(out, projectToken(&list.Items[i]))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	writeJSON(w, http.StatusOK, ListResponse[TokenView]{Items: out})
+}
+
+// createToken issues a personal access token for the caller. The
+// response is the only time the token is shown. An admin-scoped token
+// deletes edges without step-up, so issuing one requires it.
+func (h *Handler) createToken(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.requireTokenManager(w, r)
+	if !ok {
+		return
+	}
+	var req CreateTokenRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	scopes, err := parseTokenScopes(req.Scopes)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if slices.Contains(scopes, tenancyv1alpha1.PersonalAccessTokenScopeAdmin) && h.mgr.stepUp != nil &&
+		!h.mgr.stepUp.RequireStepUp(w, r, "issuing an admin-scoped personal access token") {
+		return
+	}
+	days := req.ExpiresInDays
+	switch {
+	case days == 0:
+		days = defaultTokenLifetimeDays
+	case days < 0 || days > maxTokenLifetimeDays:
+		writeError(w, newValidationError(fmt.Sprintf("expiresInDays must be between 1 and %d", maxTokenLifetimeDays)))
+		return
+	}
+
+	id, token, hash, err := auth.NewPersonalAccessToken()
+	if err != nil {
+		writeStatus(w, http.StatusInternalServerError, "InternalError", err.Error())
+		return
+	}
+	expires := metav1.NewTime(time.Now().UTC().AddDate(0, 0, days))
+	pat := &tenancyv1alpha1.PersonalAccessToken{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   id,
+			Labels: map[string]string{tenancyv1alpha1.PersonalAccessTokenUserLabel: user},
+		},
+		Spec: tenancyv1alpha1.PersonalAccessTokenSpec{
+			User:                user,
+			Description:         strings.TrimSpace(req.Description),
+			Scopes:              scopes,
+			Cluster:             strings.TrimSpace(req.Cluster),
+			TokenHash:           hash,
+			ExpirationTimestamp: &expires,
+		},
+	}
+	created, err := h.mgr.client.PersonalAccessTokens().Create(r.Context(), pat, metav1.CreateOptions{})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	view := projectToken(created)
+	view.Token = token
+	writeJSON(w, http.StatusCreated, view)
+}
+
+// deleteToken revokes one of the caller's personal access tokens.
+// Another user's token is reported as not found, so token IDs cannot
+// be probed.
+func (h *Handler) deleteToken(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.requireTokenManager(w, r)
+	if !ok {
+		return
+	}
+	id := mux.Vars(r)["id"]
+	pat, err := h.mgr.client.PersonalAccessTokens().Get(r.Context(), id, metav1.GetOptions{})
+	if err == nil && pat.Spec.User != user {
+		err = apierrors.NewNotFound(schema.GroupResource{Group: "tenants.kedge.faros.sh", Resource: "personalaccesstokens"}, id)
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := h.mgr.client.PersonalAccessTokens().Delete(r.Context(), id, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseTokenScopes validates and de-duplicates requested scopes.
+func parseTokenScopes(in []string) ([]tenancyv1alpha1.PersonalAccessTokenScope, error) {
+	if len(in) == 0 {
+		return nil, newValidationError("at least one scope is required (read, write, admin)")
+	}
+	seen := map[tenancyv1alpha1.PersonalAccessTokenScope]bool{}
+	var out []tenancyv1alpha1.PersonalAccessTokenScope
+	for _, s := range in {
+		scope := tenancyv1alpha1.PersonalAccessTokenScope(strings.ToLower(strings.TrimSpace(s)))
+		switch scope {
+		case tenancyv1alpha1.PersonalAccessTokenScopeRead, tenancyv1alpha1.PersonalAccessTokenScopeWrite,
+			tenancyv1alpha1.PersonalAccessTokenScopeAdmin:
+		default:
+			return nil, newValidationError(fmt.Sprintf("unknown scope %q (want read, write or admin)", s))
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			out = append(out, scope)
+		}
+	}
+	return out, nil
+}
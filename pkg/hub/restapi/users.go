@@ -17,15 +17,122 @@ limitations under the License.
 package restapi
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"net/http"
+	"strings"
 	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	tenancyv1alpha1 "github.com/faroshq/faros-kedge/apis/tenancy/v1alpha1"
+	"github.com/faroshq/faros-kedge/pkg/server/auth"
 )
 
+// Authentication methods reported by GET /api/users/me.
+const (
+	AuthMethodOIDC                = "oidc"
+	AuthMethodStaticToken         = "static-token"
+	AuthMethodPersonalAccessToken = "personal-access-token"
+)
+
+// IdentityView is the GET /api/users/me response: who the hub thinks
+// the caller is, for debugging unexpected 403s.
+type IdentityView struct {
+	User           string     `json:"user"`
+	Email          string     `json:"email,omitempty"`
+	Name           string     `json:"name,omitempty"`
+	RBACIdentity   string     `json:"rbacIdentity,omitempty"`
+	AuthMethod     string     `json:"authMethod"`
+	DefaultCluster string     `json:"defaultCluster,omitempty"`
+	PersonalOrg    string     `json:"personalOrg,omitempty"`
+	ExpiresAt      *time.Time `json:"expiresAt,omitempty"`
+}
+
+// getSelfUser reports the caller's identity as resolved from their
+// bearer token, along with how they authenticated and when the token
+// expires.
+func (h *Handler) getSelfUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.requireUser(w, r)
+	if !ok {
+		return
+	}
+	u, err := h.mgr.client.Users().Get(r.Context(), user, metav1.GetOptions{})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	method, patID, expiresAt := bearerTokenInfo(r)
+	if patID != "" {
+		pat, err := h.mgr.client.PersonalAccessTokens().Get(r.Context(), patID, metav1.GetOptions{})
+		if err == nil && pat.Spec.ExpirationTimestamp != nil {
+			e := pat.Spec.ExpirationTimestamp.Time
+			expiresAt = &e
+		}
+	}
+	writeJSON(w, http.StatusOK, IdentityView{
+		User:           u.Name,
+		Email:          u.Spec.Email,
+		Name:           u.Spec.Name,
+		RBACIdentity:   u.Spec.RBACIdentity,
+		AuthMethod:     method,
+		DefaultCluster: u.Spec.DefaultCluster,
+		PersonalOrg:    u.Status.PersonalOrg,
+		ExpiresAt:      expiresAt,
+	})
+}
+
+// bearerTokenInfo classifies the request's bearer token. It returns the
+// token ID for a personal access token and the exp claim for an OIDC ID
+// token. The middleware has already verified the token, so the claims
+// are decoded without re-checking the signature.
+func bearerTokenInfo(r *http.Request) (method, patID string, expiresAt *time.Time) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if id, ok := auth.ParsePersonalAccessToken(token); ok {
+		return AuthMethodPersonalAccessToken, id, nil
+	}
+	if strings.Count(token, ".") != 2 {
+		return AuthMethodStaticToken, "", nil
+	}
+	claims, ok := idTokenClaims(token)
+	if !ok || claims.Exp == 0 {
+		return AuthMethodOIDC, "", nil
+	}
+	exp := time.Unix(claims.Exp, 0).UTC()
+	return AuthMethodOIDC, "", &exp
+}
+
+// bearerTokenIssuer returns the iss claim of the request's bearer token, or
+// "" when it is not an OIDC ID token. As for bearerTokenInfo, the token is
+// already verified.
+func bearerTokenIssuer(r *http.Request) string {
+	claims, _ := idTokenClaims(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+	return claims.Iss
+}
+
+type jwtClaims struct {
+	Iss string `json:"iss"`
+	Exp int64  `json:"exp"`
+}
+
+// idTokenClaims decodes the claims of a JWT without checking its signature.
+func idTokenClaims(token string) (jwtClaims, bool) {
+	var claims jwtClaims
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return claims, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return claims, false
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return claims, false
+	}
+	return claims, true
+}
+
 // deleteSelfUser soft-deletes the caller's User CR by stamping
 // status.deletionRequestedAt. The soft-delete reconciler (PR #212)
 // drives the 30-day grace + cascade per O-8.
@@ -0,0 +1,528 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package search serves /services/search: free-text and label queries over
+// the edges, workloads and placements in the caller's workspace, answered
+// from an in-memory index the hub keeps current with informers. It powers
+// "kedge search" and UI type-ahead without a list call per keystroke.
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/faroshq/faros-kedge/pkg/apiurl"
+	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+	"github.com/faroshq/faros-kedge/pkg/hub/metrics"
+	"github.com/faroshq/faros-kedge/pkg/hub/providers"
+	"github.com/faroshq/faros-kedge/pkg/problem"
+)
+
+// PathPrefix is the URL path the search API is served at.
+const PathPrefix = "/services/search"
+
+const (
+	// DefaultIdleTTL is how long a workspace's index is kept after its last
+	// search.
+	DefaultIdleTTL = 10 * time.Minute
+	// defaultLimit and maxLimit bound the results of one search.
+	defaultLimit = 20
+	maxLimit     = 200
+	// syncTimeout bounds how long a search waits for a new index to load.
+	syncTimeout = 10 * time.Second
+	// maxIndexes bounds the workspaces indexed at once; the least recently
+	// searched index is dropped to make room.
+	maxIndexes = 512
+)
+
+// searchKind is an indexed kind. Edges have status.connected; placements
+// name their edge and workload.
+type searchKind struct {
+	kind string
+	gvr  schema.GroupVersionResource
+	edge bool
+}
+
+// searchKinds are the indexed kinds, in the order results of equal rank are
+// listed.
+var searchKinds = []searchKind{
+	{kind: "KubernetesCluster", gvr: kedgeclient.KubernetesClusterGVR, edge: true},
+	{kind: "LinuxServer", gvr: kedgeclient.LinuxServerGVR, edge: true},
+	{kind: "Workload", gvr: kedgeclient.WorkloadGVR},
+	{kind: "Placement", gvr: kedgeclient.PlacementGVR},
+}
+
+// indexedFields are the fields an indexed object keeps besides its
+// metadata; the rest (manifests, pod templates, conditions) is dropped so
+// the index stays small.
+var indexedFields = [][]string{
+	{"status", "phase"},
+	{"status", "connected"},
+	{"status", "hostname"},
+	{"spec", "location", "region"},
+	{"spec", "location", "address"},
+	{"spec", "edgeName"},
+	{"spec", "workloadRef", "name"},
+	{"spec", "simple", "image"},
+	{"spec", "helm", "chart"},
+}
+
+// searchIndexes is the number of workspaces indexed.
+var searchIndexes = prometheus.NewGauge(prometheus.GaugeOpts{
+	Namespace: "kedge_hub",
+	Name:      "search_indexed_workspaces",
+	Help:      "Workspaces the hub keeps a search index of.",
+})
+
+func init() {
+	metrics.Registry.MustRegister(searchIndexes)
+}
+
+// Handler serves the search API.
+type Handler struct {
+	ctx       context.Context
+	kcpConfig *rest.Config
+	resolver  providers.TenantResolver
+	ttl       time.Duration
+	log       logr.Logger
+	// clientFor returns a client of the workspace at a tenant path;
+	// replaced in tests.
+	clientFor func(tenantPath string) (dynamic.Interface, error)
+
+	mu      sync.Mutex
+	indexes map[string]*workspaceIndex
+}
+
+// NewHandler returns a Handler indexing, with the hub's kcp admin config,
+// the workspace resolver picks for the caller (which honors the portal's
+// X-Kedge-Org / X-Kedge-Workspace selection after checking membership). A
+// workspace is indexed on its first search and dropped ttl after its last;
+// every index stops when ctx is done.
+func NewHandler(ctx context.Context, kcpConfig *rest.Config, resolver providers.TenantResolver, ttl time.Duration, log logr.Logger) *Handler {
+	if ttl <= 0 {
+		ttl = DefaultIdleTTL
+	}
+	h := &Handler{
+		ctx:       ctx,
+		kcpConfig: kcpConfig,
+		resolver:  resolver,
+		ttl:       ttl,
+		log:       log,
+		indexes:   map[string]*workspaceIndex{},
+	}
+	h.clientFor = h.workspaceClient
+	go wait.Until(h.expire, ttl/2, ctx.Done())
+	return h
+}
+
+// workspaceClient returns a dynamic client of the workspace at tenantPath.
+func (h *Handler) workspaceClient(tenantPath string) (dynamic.Interface, error) {
+	cfg := rest.CopyConfig(h.kcpConfig)
+	cfg.Host = apiurl.KCPClusterURL(cfg.Host, tenantPath)
+	client, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("dynamic client for %s: %w", tenantPath, err)
+	}
+	return client, nil
+}
+
+// Register mounts the search API on router.
+func (h *Handler) Register(router *mux.Router) {
+	router.HandleFunc(PathPrefix, h.serve).Methods("GET")
+}
+
+// Response is the result of a search. Total counts every match, of which
+// Results holds the best ranked up to the limit.
+type Response struct {
+	Total   int      `json:"total"`
+	Results []Result `json:"results"`
+}
+
+// Result is one matching object.
+type Result struct {
+	Kind      string            `json:"kind"`
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Phase     string            `json:"phase,omitempty"`
+	// Connected is set for edges.
+	Connected *bool `json:"connected,omitempty"`
+	// Edge and Workload are set for placements.
+	Edge     string `json:"edge,omitempty"`
+	Workload string `json:"workload,omitempty"`
+}
+
+// query is a parsed search request.
+type query struct {
+	terms    []string
+	selector labels.Selector
+	kinds    map[string]bool // nil matches every kind
+	limit    int
+}
+
+// parseQuery reads q (whitespace-separated terms, all of which must match),
+// labelSelector, kind (comma-separated kinds, "edge" for both edge kinds)
+// and limit.
+func parseQuery(r *http.Request) (query, error) {
+	v := r.URL.Query()
+	q := query{terms: strings.Fields(strings.ToLower(v.Get("q"))), selector: labels.Everything(), limit: defaultLimit}
+	if s := v.Get("labelSelector"); s != "" {
+		sel, err := labels.Parse(s)
+		if err != nil {
+			return q, fmt.Errorf("invalid labelSelector: %w", err)
+		}
+		q.selector = sel
+	}
+	if s := v.Get("kind"); s != "" {
+		q.kinds = map[string]bool{}
+		for _, k := range strings.Split(s, ",") {
+			k = strings.ToLower(strings.TrimSpace(k))
+			found := false
+			for _, sk := range searchKinds {
+				if k == strings.ToLower(sk.kind) || k == sk.gvr.Resource || (sk.edge && (k == "edge" || k == "edges")) {
+					q.kinds[sk.kind] = true
+					found = true
+				}
+			}
+			if !found {
+				return q, fmt.Errorf("unknown kind %q", k)
+			}
+		}
+	}
+	if s := v.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			return q, fmt.Errorf("invalid limit %q", s)
+		}
+		q.limit = min(n, maxLimit)
+	}
+	return q, nil
+}
+
+func (h *Handler) serve(w http.ResponseWriter, r *http.Request) {
+	user, tenantPath, err := h.resolver.Resolve(r)
+	if err != nil || user == "" {
+		problem.Write(w, r, http.StatusUnauthorized, problem.ReasonUnauthorized, "unauthorized")
+		return
+	}
+	if tenantPath == "" {
+		problem.Write(w, r, http.StatusNotFound, problem.ReasonNotFound, "no workspace found for the caller")
+		return
+	}
+	q, err := parseQuery(r)
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, problem.ReasonBadRequest, err.Error())
+		return
+	}
+
+	idx := h.index(tenantPath)
+	ctx, cancel := context.WithTimeout(r.Context(), syncTimeout)
+	defer cancel()
+	if err := idx.wait(ctx); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			problem.Write(w, r, http.StatusServiceUnavailable, problem.ReasonServiceUnavailable, "the search index of the workspace is still loading; retry shortly")
+			return
+		}
+		h.log.Error(err, "Indexing workspace for search", "user", user, "workspace", tenantPath)
+		problem.Write(w, r, http.StatusBadGateway, problem.ReasonForStatus(http.StatusBadGateway), "indexing the workspace failed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(idx.search(q))
+}
+
+// index returns the index of the workspace at tenantPath, starting it if
+// there is none, and marks it read.
+func (h *Handler) index(tenantPath string) *workspaceIndex {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if idx, ok := h.indexes[tenantPath]; ok && !idx.failed() {
+		idx.lastRead = time.Now()
+		return idx
+	}
+	if len(h.indexes) >= maxIndexes {
+		h.evictOldestLocked()
+	}
+	ctx, cancel := context.WithCancel(h.ctx)
+	idx := &workspaceIndex{stop: cancel, lastRead: time.Now(), ready: make(chan struct{})}
+	if old, ok := h.indexes[tenantPath]; ok {
+		old.stop()
+	}
+	h.indexes[tenantPath] = idx
+	searchIndexes.Set(float64(len(h.indexes)))
+	go func() {
+		client, err := h.clientFor(tenantPath)
+		if err != nil {
+			idx.err = err
+			close(idx.ready)
+			return
+		}
+		idx.start(ctx, client)
+	}()
+	return idx
+}
+
+// evictOldestLocked drops the least recently searched index. h.mu is held.
+func (h *Handler) evictOldestLocked() {
+	var oldest string
+	for path, idx := range h.indexes {
+		if oldest == "" || idx.lastRead.Before(h.indexes[oldest].lastRead) {
+			oldest = path
+		}
+	}
+	if oldest != "" {
+		h.indexes[oldest].stop()
+		delete(h.indexes, oldest)
+	}
+}
+
+// expire drops the indexes not searched within the TTL.
+func (h *Handler) expire() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for path, idx := range h.indexes {
+		if time.Since(idx.lastRead) > h.ttl {
+			idx.stop()
+			delete(h.indexes, path)
+			h.log.V(2).Info("Dropped idle search index", "workspace", path)
+		}
+	}
+	searchIndexes.Set(float64(len(h.indexes)))
+}
+
+// workspaceIndex holds the informers of one workspace's indexed kinds.
+type workspaceIndex struct {
+	stop     context.CancelFunc
+	lastRead time.Time // guarded by Handler.mu
+
+	// ready is closed once the informers have synced or starting them
+	// failed with err.
+	ready     chan struct{}
+	err       error
+	informers []kindInformer
+}
+
+type kindInformer struct {
+	kind     searchKind
+	informer cache.SharedIndexInformer
+}
+
+// start starts an informer for each indexed kind bound in the workspace
+// client addresses and waits for them to sync. A kind whose API is not bound
+// (the edges provider is not enabled) is skipped rather than being an error.
+func (idx *workspaceIndex) start(ctx context.Context, client dynamic.Interface) {
+	defer close(idx.ready)
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(client, 0)
+	for _, k := range searchKinds {
+		if _, err := client.Resource(k.gvr).List(ctx, metav1.ListOptions{Limit: 1}); err != nil {
+			if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				continue
+			}
+			idx.err = fmt.Errorf("listing %s: %w", k.gvr.Resource, err)
+			return
+		}
+		informer := factory.ForResource(k.gvr).Informer()
+		if err := informer.SetTransform(trim); err != nil {
+			idx.err = err
+			return
+		}
+		idx.informers = append(idx.informers, kindInformer{kind: k, informer: informer})
+	}
+	factory.Start(ctx.Done())
+	for k, synced := range factory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			idx.err = fmt.Errorf("%s did not sync", k.Resource)
+		}
+	}
+}
+
+// wait blocks until the index has loaded or ctx is done.
+func (idx *workspaceIndex) wait(ctx context.Context) error {
+	select {
+	case <-idx.ready:
+		return idx.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// failed reports whether the index failed to load, so the next search
+// starts a new one.
+func (idx *workspaceIndex) failed() bool {
+	select {
+	case <-idx.ready:
+		return idx.err != nil
+	default:
+		return false
+	}
+}
+
+// trim keeps the metadata and indexedFields of an object.
+func trim(obj interface{}) (interface{}, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return obj, nil
+	}
+	out := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	out.SetAPIVersion(u.GetAPIVersion())
+	out.SetKind(u.GetKind())
+	out.SetName(u.GetName())
+	out.SetNamespace(u.GetNamespace())
+	out.SetUID(u.GetUID())
+	out.SetResourceVersion(u.GetResourceVersion())
+	out.SetLabels(u.GetLabels())
+	for _, path := range indexedFields {
+		if v, found, _ := unstructured.NestedFieldNoCopy(u.Object, path...); found {
+			_ = unstructured.SetNestedField(out.Object, v, path...)
+		}
+	}
+	return out, nil
+}
+
+// ranked is a match and its rank.
+type ranked struct {
+	result Result
+	order  int
+	score  int
+}
+
+// search returns the objects matching q, best ranked first.
+func (idx *workspaceIndex) search(q query) Response {
+	var matches []ranked
+	for order, ki := range idx.informers {
+		if q.kinds != nil && !q.kinds[ki.kind.kind] {
+			continue
+		}
+		for _, obj := range ki.informer.GetStore().List() {
+			u, ok := obj.(*unstructured.Unstructured)
+			if !ok || !q.selector.Matches(labels.Set(u.GetLabels())) {
+				continue
+			}
+			result := toResult(ki.kind, u)
+			score, ok := match(q.terms, result, u)
+			if !ok {
+				continue
+			}
+			matches = append(matches, ranked{result: result, order: order, score: score})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		if a.order != b.order {
+			return a.order < b.order
+		}
+		if a.result.Namespace != b.result.Namespace {
+			return a.result.Namespace < b.result.Namespace
+		}
+		return a.result.Name < b.result.Name
+	})
+
+	resp := Response{Total: len(matches), Results: []Result{}}
+	for i := 0; i < len(matches) && i < q.limit; i++ {
+		resp.Results = append(resp.Results, matches[i].result)
+	}
+	return resp
+}
+
+func toResult(k searchKind, u *unstructured.Unstructured) Result {
+	r := Result{Kind: k.kind, Name: u.GetName(), Namespace: u.GetNamespace(), Labels: u.GetLabels()}
+	r.Phase, _, _ = unstructured.NestedString(u.Object, "status", "phase")
+	if k.edge {
+		connected, _, _ := unstructured.NestedBool(u.Object, "status", "connected")
+		r.Connected = &connected
+	}
+	r.Edge, _, _ = unstructured.NestedString(u.Object, "spec", "edgeName")
+	r.Workload, _, _ = unstructured.NestedString(u.Object, "spec", "workloadRef", "name")
+	return r
+}
+
+// match reports whether every term matches the object and ranks it. A term
+// matching the name counts most (exact, then prefix, then substring); one
+// matching the kind, namespace, a label ("key=value") or an indexed field
+// counts least. No terms match everything.
+func match(terms []string, r Result, u *unstructured.Unstructured) (int, bool) {
+	name := strings.ToLower(r.Name)
+	var fields []string
+	score := 0
+	for _, term := range terms {
+		switch {
+		case name == term:
+			score += 8
+		case strings.HasPrefix(name, term):
+			score += 4
+		case strings.Contains(name, term):
+			score += 2
+		default:
+			if fields == nil {
+				fields = searchFields(r, u)
+			}
+			found := false
+			for _, f := range fields {
+				if strings.Contains(f, term) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return 0, false
+			}
+			score++
+		}
+	}
+	return score, true
+}
+
+// searchFields are the lowercased values a term may match besides the name.
+func searchFields(r Result, u *unstructured.Unstructured) []string {
+	fields := []string{strings.ToLower(r.Kind), r.Namespace}
+	for k, v := range r.Labels {
+		fields = append(fields, strings.ToLower(k+"="+v))
+	}
+	for _, path := range indexedFields {
+		if s, found, _ := unstructured.NestedString(u.Object, path...); found && s != "" {
+			fields = append(fields, strings.ToLower(s))
+		}
+	}
+	return fields
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/faroshq/faros-kedge/pkg/hub/providers"
+)
+
+func object(kind, namespace, name string, labels map[string]string, fields map[string]interface{}) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: fields}
+	if u.Object == nil {
+		u.Object = map[string]interface{}{}
+	}
+	u.SetAPIVersion("edges.kedge.faros.sh/v1alpha1")
+	u.SetKind(kind)
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	u.SetLabels(labels)
+	return u
+}
+
+func newTestHandler(t *testing.T) *Handler {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	objects := []runtime.Object{
+		object("KubernetesCluster", "", "store-17", map[string]string{"region": "eu"}, map[string]interface{}{
+			"status": map[string]interface{}{"phase": "Ready", "connected": true},
+		}),
+		object("LinuxServer", "", "store-17-pos", nil, map[string]interface{}{
+			"status": map[string]interface{}{"phase": "Ready", "hostname": "pos-1.example.com"},
+		}),
+		object("Workload", "default", "nginx", nil, map[string]interface{}{
+			"spec": map[string]interface{}{"simple": map[string]interface{}{"image": "nginx:1.27"}},
+		}),
+		object("Placement", "default", "nginx-store-17", nil, map[string]interface{}{
+			"spec": map[string]interface{}{
+				"edgeName":    "store-17",
+				"workloadRef": map[string]interface{}{"name": "nginx"},
+				"manifests":   []interface{}{map[string]interface{}{"kind": "Deployment"}},
+			},
+		}),
+	}
+	listKinds := map[schema.GroupVersionResource]string{}
+	for _, k := range searchKinds {
+		listKinds[k.gvr] = k.kind + "List"
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...)
+
+	h := NewHandler(ctx, nil, providers.TenantResolverFunc(func(*http.Request) (string, string, error) {
+		return "alice", "root:acme", nil
+	}), 0, logr.Discard())
+	h.clientFor = func(string) (dynamic.Interface, error) { return client, nil }
+	return h
+}
+
+func search(t *testing.T, h *Handler, query string) (int, Response) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.serve(rec, httptest.NewRequest(http.MethodGet, PathPrefix+"?"+query, nil))
+	var resp Response
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return rec.Code, resp
+}
+
+func names(resp Response) []string {
+	var out []string
+	for _, r := range resp.Results {
+		out = append(out, r.Kind+"/"+r.Name)
+	}
+	return out
+}
+
+func TestSearch(t *testing.T) {
+	h := newTestHandler(t)
+
+	for _, tc := range []struct {
+		query string
+		want  []string
+	}{
+		// Exact name first, then prefix, then substring.
+		{"q=store-17", []string{"KubernetesCluster/store-17", "LinuxServer/store-17-pos", "Placement/nginx-store-17"}},
+		{"q=STORE-17+pos", []string{"LinuxServer/store-17-pos"}},
+		{"q=nginx&kind=placement", []string{"Placement/nginx-store-17"}},
+		{"q=store&kind=edge", []string{"KubernetesCluster/store-17", "LinuxServer/store-17-pos"}},
+		{"labelSelector=region%3Deu", []string{"KubernetesCluster/store-17"}},
+		{"q=region%3Deu", []string{"KubernetesCluster/store-17"}},
+		{"q=pos-1.example", []string{"LinuxServer/store-17-pos"}},
+		{"q=nginx:1.27", []string{"Workload/nginx"}},
+		{"q=workload", []string{"Workload/nginx"}},
+		{"q=store-17&limit=1", []string{"KubernetesCluster/store-17"}},
+		{"q=nothing-matches", nil},
+	} {
+		code, resp := search(t, h, tc.query)
+		if code != http.StatusOK {
+			t.Errorf("%s: status %d", tc.query, code)
+			continue
+		}
+		if got := names(resp); !slices.Equal(got, tc.want) {
+			t.Errorf("%s: results %v, want %v", tc.query, got, tc.want)
+		}
+	}
+
+	_, resp := search(t, h, "q=store-17&limit=1")
+	if resp.Total != 3 {
+		t.Errorf("total = %d, want 3", resp.Total)
+	}
+	if c := resp.Results[0].Connected; c == nil || !*c {
+		t.Errorf("connected = %v, want true", c)
+	}
+
+	for _, query := range []string{"kind=bogus", "limit=0", "labelSelector=%3D%3D"} {
+		if code, _ := search(t, h, query); code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, want 400", query, code)
+		}
+	}
+}
+
+func TestTrim(t *testing.T) {
+	u := object("Placement", "default", "p", map[string]string{"a": "b"}, map[string]interface{}{
+		"spec": map[string]interface{}{
+			"edgeName":  "store-17",
+			"manifests": []interface{}{map[string]interface{}{"kind": "Deployment"}},
+		},
+	})
+	obj, err := trim(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := obj.(*unstructured.Unstructured)
+	if _, found, _ := unstructured.NestedFieldNoCopy(out.Object, "spec", "manifests"); found {
+		t.Error("manifests kept in the index")
+	}
+	if edge, _, _ := unstructured.NestedString(out.Object, "spec", "edgeName"); edge != "store-17" || out.GetLabels()["a"] != "b" {
+		t.Errorf("trimmed object lost indexed fields: %v", out.Object)
+	}
+}
@@ -34,6 +34,7 @@ import (
 	oidc "github.com/coreos/go-oidc"
 	"github.com/gorilla/mux"
 	"github.com/kcp-dev/multicluster-provider/apiexport"
+	"github.com/kcp-dev/multicluster-provider/pkg/provider"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
@@ -47,17 +48,30 @@ import (
 	"github.com/faroshq/faros-kedge/pkg/apiurl"
 	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
 	"github.com/faroshq/faros-kedge/pkg/hub/admin"
+	"github.com/faroshq/faros-kedge/pkg/hub/audit"
 	"github.com/faroshq/faros-kedge/pkg/hub/bootstrap"
+	"github.com/faroshq/faros-kedge/pkg/hub/controllers/directory"
 	"github.com/faroshq/faros-kedge/pkg/hub/controllers/mcpserver"
 	"github.com/faroshq/faros-kedge/pkg/hub/controllers/organization"
 	"github.com/faroshq/faros-kedge/pkg/hub/controllers/softdelete"
+	"github.com/faroshq/faros-kedge/pkg/hub/drain"
+	"github.com/faroshq/faros-kedge/pkg/hub/explorer"
+	"github.com/faroshq/faros-kedge/pkg/hub/fleetmap"
 	"github.com/faroshq/faros-kedge/pkg/hub/kcp"
+	"github.com/faroshq/faros-kedge/pkg/hub/manifests"
 	"github.com/faroshq/faros-kedge/pkg/hub/mcpaggregate"
+	"github.com/faroshq/faros-kedge/pkg/hub/metrics"
+	"github.com/faroshq/faros-kedge/pkg/hub/migrate"
+	"github.com/faroshq/faros-kedge/pkg/hub/mirror"
 	"github.com/faroshq/faros-kedge/pkg/hub/providers"
+	"github.com/faroshq/faros-kedge/pkg/hub/readonly"
 	"github.com/faroshq/faros-kedge/pkg/hub/restapi"
+	"github.com/faroshq/faros-kedge/pkg/hub/search"
 	"github.com/faroshq/faros-kedge/pkg/hub/serviceaccounts"
+	"github.com/faroshq/faros-kedge/pkg/hub/sharding"
 	"github.com/faroshq/faros-kedge/pkg/hub/tenant"
 	"github.com/faroshq/faros-kedge/pkg/kcppaths"
+	"github.com/faroshq/faros-kedge/pkg/problem"
 	"github.com/faroshq/faros-kedge/pkg/server/auth"
 	"github.com/faroshq/faros-kedge/pkg/server/proxy"
 	pkgversion "github.com/faroshq/faros-kedge/pkg/version"
@@ -92,6 +106,24 @@ func (s *Server) Run(ctx context.Context) error {
 	if err := kcp.ValidateProviders(s.opts.Providers); err != nil {
 		return err
 	}
+	if s.opts.ControllerShards > 1 && s.opts.EmbeddedKCP {
+		return fmt.Errorf("--controller-shards > 1 needs replicas sharing an external kcp (--external-kcp-kubeconfig)")
+	}
+	if s.opts.ControllerLeaderElection && s.opts.EmbeddedKCP {
+		return fmt.Errorf("--controller-leader-election needs replicas sharing an external kcp (--external-kcp-kubeconfig)")
+	}
+	idps, err := s.opts.IdentityProviders()
+	if err != nil {
+		return err
+	}
+	trustedProxies, err := auth.ParseTrustedProxies(s.opts.AuthTrustedProxies)
+	if err != nil {
+		return fmt.Errorf("--auth-trusted-proxies: %w", err)
+	}
+
+	if s.opts.DebugAddr != "" {
+		go runDebugServer(ctx, logger, s.opts.DebugAddr)
+	}
 
 	var kcpConfig *rest.Config
 	var bootstrapper *kcp.Bootstrapper
@@ -112,7 +144,7 @@ func (s *Server) Run(ctx context.Context) error {
 			batteries = strings.Split(s.opts.KCPBatteriesInclude, ",")
 		}
 
-		embeddedKCP = kcp.NewEmbeddedKCP(kcp.EmbeddedKCPOptions{
+		kcpOpts := kcp.EmbeddedKCPOptions{
 			RootDir:                  kcpRootDir,
 			SecurePort:               s.opts.KCPSecurePort,
 			BindAddress:              s.opts.KCPBindAddress,
@@ -122,14 +154,30 @@ func (s *Server) Run(ctx context.Context) error {
 			ShardExternalURL:         s.opts.KCPShardExternalURL,
 			ShardVirtualWorkspaceURL: s.opts.KCPShardVirtualWorkspaceURL,
 			StaticAuthTokens:         s.opts.StaticAuthTokens,
-			// Wire OIDC into kcp so it can authenticate user tokens forwarded
-			// by the proxy natively. The default username mapping (sub →
-			// "kedge:<sub>") matches User.Spec.RBACIdentity issued by the auth
-			// handler, so existing workspace RBAC bindings keep working.
-			OIDCIssuerURL: s.opts.IDPIssuerURL,
-			OIDCClientID:  s.opts.IDPClientID,
-			OIDCCAFile:    s.opts.IDPCAFile,
-		})
+		}
+		// Wire OIDC into kcp so it can authenticate user tokens forwarded
+		// by the proxy natively. The default username mapping (email →
+		// "kedge:<email>") matches User.Spec.RBACIdentity issued by the auth
+		// handler, so existing workspace RBAC bindings keep working; other
+		// identity providers' users get their namespace in the prefix.
+		for i, idp := range idps {
+			if i == 0 {
+				kcpOpts.OIDCIssuerURL = idp.IssuerURL
+				kcpOpts.OIDCClientID = idp.ClientID
+				kcpOpts.OIDCCAFile = idp.CAFile
+				// The directory sync binds groups from the same claim.
+				kcpOpts.OIDCGroupsClaim = idp.GroupsClaim
+				continue
+			}
+			kcpOpts.OIDCAdditionalIssuers = append(kcpOpts.OIDCAdditionalIssuers, kcp.OIDCIssuer{
+				IssuerURL:   idp.IssuerURL,
+				ClientID:    idp.ClientID,
+				CAFile:      idp.CAFile,
+				Prefix:      oidcConfig(idp).UsernamePrefix(),
+				GroupsClaim: idp.GroupsClaim,
+			})
+		}
+		embeddedKCP = kcp.NewEmbeddedKCP(kcpOpts)
 
 		// Start kcp in a goroutine. It will block until context is cancelled
 		// or an error occurs.
@@ -184,6 +232,55 @@ func (s *Server) Run(ctx context.Context) error {
 		}
 	}
 
+	// Bootstrap runs as tracked steps: CRDs, then with kcp the workspace
+	// hierarchy, platform schemas and exports. Each records its outcome as a
+	// condition in the bootstrap status ConfigMap, so a restart resumes at the
+	// step that failed; /readyz reports the conditions. Gates, checked on
+	// every start, hold readiness until embedded kcp and its batteries are
+	// up, the exports are available and Users can be written.
+	var steps []bootstrap.Step
+	if embeddedKCP != nil {
+		steps = append(steps, bootstrap.Step{Name: "KCP", Gate: true, Run: func(ctx context.Context) error {
+			return bootstrap.WaitForServerReady(ctx, kcpConfig)
+		}})
+	}
+	steps = append(steps, bootstrap.Step{
+		Name: "CRDs",
+		Run: func(ctx context.Context) error {
+			return runStartupStepWithRetry(ctx, startupRetryPolicy{
+				Name:      "install CRDs",
+				Interval:  5 * time.Second,
+				Timeout:   10 * time.Minute,
+				Retryable: isRetriableKCPBootstrapError,
+			}, func(ctx context.Context) error {
+				return bootstrap.InstallCRDs(ctx, config)
+			})
+		},
+	})
+	fingerprint := []string{pkgversion.Version, pkgversion.GitCommit, bootstrap.CRDsFingerprint()}
+	if kcpConfig != nil {
+		bootstrapper = kcp.NewBootstrapper(kcpConfig).WithEnabledProviders(s.opts.Providers)
+		steps = append(steps,
+			bootstrap.Step{Name: "Workspaces", Run: bootstrapper.BootstrapWorkspaces},
+			bootstrap.Step{Name: "Schemas", Run: bootstrapper.BootstrapSchemas},
+			bootstrap.Step{Name: "Exports", Run: bootstrapper.BootstrapExports},
+			bootstrap.Step{Name: "APIExportsAvailable", Gate: true, Run: bootstrapper.WaitForExports},
+		)
+		fingerprint = append(fingerprint, bootstrapper.Fingerprint())
+	}
+	usersConfig := config
+	if bootstrapper != nil {
+		usersConfig = bootstrapper.UsersConfig()
+	}
+	steps = append(steps, bootstrap.Step{Name: "UserAPI", Gate: true, Run: func(ctx context.Context) error {
+		return bootstrap.WaitForUserAPI(ctx, usersConfig)
+	}})
+	statusStore, err := bootstrap.NewConfigMapStore(config)
+	if err != nil {
+		return fmt.Errorf("creating bootstrap status store: %w", err)
+	}
+	progress := bootstrap.NewProgress(statusStore, bootstrap.Fingerprint(fingerprint), steps)
+
 	// Start the HTTP server early so that the liveness probe (/healthz) can
 	// succeed during CRD and kcp bootstrap. We use a delegating handler that
 	// initially serves only the health endpoints; once full initialization is
@@ -195,38 +292,50 @@ func (s *Server) Run(ctx context.Context) error {
 		w.WriteHeader(http.StatusOK)
 		_, _ = fmt.Fprint(w, `{"status":"ok","bootstrapping":true}`)
 	})
-	earlyMux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
-		// Return 503 until bootstrap completes so the readiness gate works
-		// correctly, while the liveness gate remains satisfied.
-		w.WriteHeader(http.StatusServiceUnavailable)
-		_, _ = fmt.Fprint(w, "bootstrapping")
-	})
+	// /readyz returns 503 with the step conditions until bootstrap
+	// completes, so the readiness gate works correctly while the liveness
+	// gate remains satisfied.
+	earlyMux.Handle("/readyz", progress)
 	delegate.set(earlyMux)
 
+	// The drainer tracks the upgraded connections, exec, ssh and agent
+	// tunnels, that http.Server.Shutdown does not wait for.
+	drainer := drain.New()
 	earlyHTTPServer := &http.Server{
 		Addr:              s.opts.ListenAddr,
-		Handler:           delegate,
+		Handler:           drainer.Handler(delegate),
 		ReadHeaderTimeout: 10 * time.Second,
 	}
 
 	// Channel to receive HTTP server errors.
 	httpErrCh := make(chan error, 1)
+	// drained is closed once the shutdown handler is done.
+	drained := make(chan struct{})
 
 	// Shutdown handler - triggered by context cancellation or kcp failure.
 	// We capture earlyHTTPServer in the closure; once the server object is
 	// replaced below the same pointer is used because we never reassign it.
+	// The listener closes at once; agents are sent to other replicas while
+	// requests and sessions in flight get the grace period to finish.
 	go func() {
+		defer close(drained)
 		select {
 		case <-ctx.Done():
-			logger.Info("Shutting down HTTP server (context cancelled)")
+			logger.Info("Shutting down HTTP server (context cancelled)", "grace", s.opts.ShutdownGracePeriod)
 		case err := <-kcpErrCh:
 			logger.Error(err, "Embedded kcp server failed, shutting down hub")
 		}
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.opts.ShutdownGracePeriod)
 		defer cancel()
+		drainDone := make(chan struct{})
+		go func() {
+			drainer.Drain(shutdownCtx)
+			close(drainDone)
+		}()
 		if err := earlyHTTPServer.Shutdown(shutdownCtx); err != nil {
 			logger.Error(err, "HTTP server shutdown error")
 		}
+		<-drainDone
 	}()
 
 	// Start HTTP server in a goroutine.
@@ -245,17 +354,9 @@ func (s *Server) Run(ctx context.Context) error {
 		close(httpErrCh)
 	}()
 
-	// 2. Bootstrap CRDs
-	logger.Info("Installing CRDs")
-	if err := runStartupStepWithRetry(ctx, startupRetryPolicy{
-		Name:      "install CRDs",
-		Interval:  5 * time.Second,
-		Timeout:   10 * time.Minute,
-		Retryable: isRetriableKCPBootstrapError,
-	}, func(ctx context.Context) error {
-		return bootstrap.InstallCRDs(ctx, config)
-	}); err != nil {
-		return fmt.Errorf("installing CRDs: %w", err)
+	// 2. Bootstrap CRDs and, with kcp, the workspace hierarchy.
+	if err := progress.Run(ctx); err != nil {
+		return fmt.Errorf("bootstrapping hub: %w", err)
 	}
 
 	// 3. Create dynamic client (used by controllers for kedge resources)
@@ -271,16 +372,21 @@ func (s *Server) Run(ctx context.Context) error {
 
 	kedgeClient := kedgeclient.NewFromDynamic(dynamicClient)
 
-	// 4. kcp bootstrap (if kcp is configured - either embedded or external)
+	// 4. kcp wiring (if kcp is configured - either embedded or external)
 	// userClient is a kedge client targeting the workspace where User CRDs live.
 	// Defaults to the base kedgeClient; overridden to root:kedge:users when kcp is configured.
 	userClient := kedgeClient
 	if kcpConfig != nil {
-		bootstrapper = kcp.NewBootstrapper(kcpConfig).WithEnabledProviders(s.opts.Providers)
-		if err := bootstrapper.Bootstrap(ctx); err != nil {
-			return fmt.Errorf("bootstrapping kcp: %w", err)
+		// Bootstrap manifests: the platform team's baseline config. A
+		// source that cannot be read or parsed fails startup; apply errors
+		// (e.g. a workspace that does not exist yet) are retried on resync.
+		if len(s.opts.BootstrapManifests) > 0 {
+			if _, err := manifests.Load(ctx, &http.Client{Timeout: 30 * time.Second}, s.opts.BootstrapManifests); err != nil {
+				return fmt.Errorf("loading bootstrap manifests: %w", err)
+			}
+			applier := manifests.NewApplier(kcpConfig, s.opts.BootstrapManifests, s.opts.BootstrapManifestsInterval)
+			go applier.Run(ctx)
 		}
-		logger.Info("kcp bootstrap complete")
 
 		// The legacy per-tenant BackfillDefaultMCPs walk (which iterated
 		// root:kedge:tenants) was removed when the new multi-org model
@@ -300,23 +406,47 @@ func (s *Server) Run(ctx context.Context) error {
 	// Create HTTP mux
 	router := mux.NewRouter()
 
+	// Authentication lockout for the OIDC callback and the kcp proxy. Dev
+	// mode shares one client IP (localhost) across many test and CLI
+	// invocations, so, as for token-login rate limiting, it is off there.
+	var lockout *auth.Lockout
+	if !s.opts.DevMode {
+		lockout = auth.NewLockout(s.opts.AuthLockoutThreshold, s.opts.AuthLockoutDuration, trustedProxies)
+	}
+
 	// Auth routes (OIDC)
 	var authHandler *auth.Handler
-	if s.opts.IDPIssuerURL != "" {
-		oidcConfig := auth.DefaultOIDCConfig()
-		oidcConfig.IssuerURL = s.opts.IDPIssuerURL
-		oidcConfig.ClientID = s.opts.IDPClientID
-		oidcConfig.RedirectURL = s.opts.HubExternalURL + apiurl.PathAuthCallback
+	if len(idps) > 0 {
+		var stepUp *auth.StepUpPolicy
+		if s.opts.StepUpMaxAge > 0 {
+			stepUp = &auth.StepUpPolicy{MaxAge: s.opts.StepUpMaxAge, AMR: s.opts.StepUpAMR}
+		}
+		oidcConfigs := make([]*auth.OIDCConfig, 0, len(idps))
+		for _, idp := range idps {
+			c := oidcConfig(idp)
+			c.RedirectURL = s.opts.HubExternalURL + apiurl.PathAuthCallback
+			if s.opts.DirectorySync {
+				// Same claim embedded kcp reads groups from, so the sync's
+				// group bindings and kcp's RBAC groups agree.
+				c.GroupsClaim = idp.GroupsClaim
+				c.Scopes = append(c.Scopes, "groups")
+			}
+			c.StepUp = stepUp
+			oidcConfigs = append(oidcConfigs, c)
+		}
 
-		authHandler, err = auth.NewHandler(ctx, oidcConfig, userClient, bootstrapper, s.opts.HubExternalURL, s.opts.DevMode)
+		authHandler, err = auth.NewHandler(ctx, oidcConfigs, userClient, bootstrapper, s.opts.HubExternalURL, s.opts.DevMode)
 		if err != nil {
 			return fmt.Errorf("creating auth handler: %w", err)
 		}
+		authHandler.SetLockout(lockout)
 		// Auth routes registered below on the main router with /api/ prefix.
 		router.HandleFunc(apiurl.PathAuthAuthorize, authHandler.HandleAuthorize).Methods("GET")
 		router.HandleFunc(apiurl.PathAuthCallback, authHandler.HandleCallback).Methods("GET")
 		router.HandleFunc(apiurl.PathAuthRefresh, authHandler.HandleRefresh).Methods("POST")
-		logger.Info("OIDC auth routes registered", "issuer", s.opts.IDPIssuerURL)
+		for _, idp := range idps {
+			logger.Info("OIDC auth routes registered", "idp", idp.Name, "issuer", idp.IssuerURL)
+		}
 	}
 
 	// NOTE: edge connectivity (agent-proxy / edges-proxy tunnel termination, the
@@ -344,6 +474,14 @@ func (s *Server) Run(ctx context.Context) error {
 	// X-Kedge-Tenant, which is the Phase 1A behaviour.
 	backendProxy := providers.NewBackendProxy(providerRegistry, logger)
 	router.PathPrefix(apiurl.PathPrefixProvidersProxy + "/").Handler(backendProxy)
+	// The Workload scheduler runs in the edges provider; its placement
+	// preview is also served at a provider-independent path.
+	router.Handle(apiurl.PathSchedulerPreview, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.Clone(r.Context())
+		r.URL.Path = apiurl.PathPrefixProvidersProxy + "/edges/scheduler/preview"
+		r.URL.RawPath = ""
+		backendProxy.ServeHTTP(w, r)
+	})).Methods("POST")
 	router.Handle(providers.PathListProviders, providers.NewListHandler(providerRegistry)).Methods("GET")
 	// Heartbeat endpoint matches /api/providers/{name}/heartbeat. The
 	// parsing happens inside the handler; gorilla/mux just needs the prefix.
@@ -422,15 +560,12 @@ func (s *Server) Run(ctx context.Context) error {
 		w.WriteHeader(http.StatusOK)
 		oidcEnabled := authHandler != nil
 		if oidcEnabled {
-			_, _ = fmt.Fprintf(w, `{"status":"ok","oidc":true,"issuerUrl":%q,"clientId":%q}`, s.opts.IDPIssuerURL, s.opts.IDPClientID)
+			_, _ = fmt.Fprintf(w, `{"status":"ok","oidc":true,"issuerUrl":%q,"clientId":%q}`, idps[0].IssuerURL, idps[0].ClientID)
 		} else {
 			_, _ = fmt.Fprint(w, `{"status":"ok","oidc":false}`)
 		}
 	})
-	router.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		_, _ = fmt.Fprint(w, "ok")
-	})
+	router.Handle("/readyz", progress)
 
 	// Version endpoint — used by the portal to detect when an edge agent is
 	// running an older build than the hub and to render upgrade instructions.
@@ -444,16 +579,27 @@ func (s *Server) Run(ctx context.Context) error {
 	// kcp API proxy: catch-all that forwards authenticated kubectl requests to kcp.
 	var kcpProxy *proxy.KCPProxy
 	if kcpConfig != nil && (authHandler != nil || len(s.opts.StaticAuthTokens) > 0) {
-		var verifier *oidc.IDTokenVerifier
+		var verifiers []*oidc.IDTokenVerifier
 		if authHandler != nil {
-			verifier = authHandler.Verifier()
+			verifiers = authHandler.Verifiers()
 		}
 		var err error
-		kcpProxy, err = proxy.NewKCPProxy(kcpConfig, verifier, userClient, bootstrapper, s.opts.StaticAuthTokens, s.opts.HubExternalURL, s.opts.DevMode)
+		kcpProxy, err = proxy.NewKCPProxy(kcpConfig, verifiers, userClient, bootstrapper, s.opts.StaticAuthTokens, s.opts.HubExternalURL, s.opts.DevMode)
 		if err != nil {
 			return fmt.Errorf("creating kcp proxy: %w", err)
 		}
-		logger.Info("kcp API proxy enabled")
+		if authHandler != nil && authHandler.StepUpPolicy().Enabled() {
+			kcpProxy.SetStepUpPolicy(authHandler.StepUpPolicy())
+			logger.Info("Step-up authentication required for edge deletion", "maxAge", s.opts.StepUpMaxAge.String())
+		}
+		if lockout != nil {
+			kcpProxy.SetLockout(lockout)
+			logger.Info("Authentication lockout enabled", "threshold", s.opts.AuthLockoutThreshold, "duration", s.opts.AuthLockoutDuration.String())
+		}
+		kcpProxy.SetRequestLimits(s.opts.ProxyRequestLimits)
+		logger.Info("kcp API proxy enabled",
+			"userQPS", s.opts.ProxyRequestLimits.UserQPS, "userBurst", s.opts.ProxyRequestLimits.UserBurst,
+			"workspaceQPS", s.opts.ProxyRequestLimits.WorkspaceQPS, "workspaceBurst", s.opts.ProxyRequestLimits.WorkspaceBurst)
 
 		// Register static token login endpoint if static tokens are configured.
 		// Use HandleTokenLoginRateLimited to protect against brute force attacks.
@@ -473,7 +619,7 @@ func (s *Server) Run(ctx context.Context) error {
 			userResolver := tenant.UserResolverFunc(func(r *http.Request) (string, error) {
 				name, err := kcpProxy.IdentifyUser(r)
 				if err != nil {
-					if errors.Is(err, proxy.ErrIdentifyNoBearer) {
+					if errors.Is(err, proxy.ErrIdentifyNoBearer) || errors.Is(err, proxy.ErrIdentifyRejected) {
 						return "", tenant.ErrUserNotResolved
 					}
 					return "", err
@@ -493,7 +639,8 @@ func (s *Server) Run(ctx context.Context) error {
 			// See pkg/hub/provider_tenant_resolver.go for the concrete
 			// resolver (lives here to avoid a providers→proxy→kcp→providers
 			// import cycle).
-			backendProxy.SetTenantResolver(newKCPTenantResolver(kcpProxy, userClient))
+			tenantResolver := newKCPTenantResolver(kcpProxy, userClient)
+			backendProxy.SetTenantResolver(tenantResolver)
 			// Inject X-Kedge-Cluster (the resolved tenant's logical-cluster
 			// ID) so providers can address per-workspace surfaces that key on
 			// the ID — notably the GraphQL gateway at /graphql/clusters/{id}.
@@ -504,6 +651,9 @@ func (s *Server) Run(ctx context.Context) error {
 			// Provider registry powers POST /api/orgs/{org}/workspaces/{ws}/providers/{name}/enable
 			// (server-side APIBinding create — see pkg/hub/restapi/providers_enable.go).
 			apiMgr.WithProviderRegistry(providerRegistry)
+			// Issuing an admin-scoped personal access token needs the same
+			// recent sign-in as the edge deletion it then allows.
+			apiMgr.WithStepUp(kcpProxy)
 			// Per-workspace kubeconfig download — OIDC mode emits an exec
 			// credential plugin entry (kedge get-token), static-token mode
 			// embeds the caller's bearer token. Either way the cluster URL
@@ -513,8 +663,12 @@ func (s *Server) Run(ctx context.Context) error {
 				DevMode:        s.opts.DevMode,
 			}
 			if authHandler != nil {
-				kcCfg.OIDCIssuerURL = s.opts.IDPIssuerURL
-				kcCfg.OIDCClientID = s.opts.IDPClientID
+				kcCfg.OIDCIssuerURL = idps[0].IssuerURL
+				kcCfg.OIDCClientID = idps[0].ClientID
+				kcCfg.OIDCClientIDs = map[string]string{}
+				for _, idp := range idps {
+					kcCfg.OIDCClientIDs[idp.IssuerURL] = idp.ClientID
+				}
 			}
 			apiMgr.WithKubeconfig(kcCfg)
 			apiHandler := restapi.NewHandler(apiMgr)
@@ -547,6 +701,11 @@ func (s *Server) Run(ctx context.Context) error {
 					adminSet[strings.ToLower(strings.TrimSpace(a))] = struct{}{}
 				}
 				adminResolver := admin.UserResolverFunc(func(r *http.Request) (string, error) {
+					// The platform-admin surface needs an interactive
+					// sign-in; personal access tokens do not reach it.
+					if _, ok := auth.ParsePersonalAccessToken(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")); ok {
+						return "", proxy.ErrIdentifyRejected
+					}
 					return kcpProxy.IdentifyUser(r)
 				})
 				adminChecker := admin.AdminCheckerFunc(func(ctx context.Context, userName string) bool {
@@ -568,13 +727,53 @@ func (s *Server) Run(ctx context.Context) error {
 				adminSvc := admin.NewService(kcpConfig, s.opts.HubExternalURL, s.opts.ProviderInternalURL)
 				adminSub := router.PathPrefix("/api/admin").Subrouter()
 				adminSub.Use(admin.Middleware(adminResolver, adminChecker))
-				admin.NewHandler(adminSvc, userClient, providerRegistry).Register(adminSub)
+				adminHandler := admin.NewHandler(adminSvc, userClient, providerRegistry)
+				if migrator, err := migrate.New(kcpConfig, bootstrapper); err != nil {
+					logger.Error(err, "Schema migration unavailable")
+				} else {
+					adminHandler.WithMigrator(migrator)
+				}
+				adminHandler.Register(adminSub)
 				logger.Info("Admin routes registered at /api/admin/* (gated by --admin-users)")
 			}
+
+			// API explorer (/explorer): an OpenAPI document generated from
+			// the kedge and provider APIResourceSchemas, and a same-origin
+			// page that browses it with the portal session.
+			if s.opts.APIExplorer {
+				explorerResolver := explorer.UserResolverFunc(func(r *http.Request) (string, error) {
+					return kcpProxy.IdentifyUser(r)
+				})
+				explorer.NewHandler(kcpConfig, providerRegistry, explorerResolver, pkgversion.Version, logger).Register(router)
+				logger.Info("API explorer registered at " + explorer.PathPrefix)
+			}
+
+			// Fleet map (/services/fleetmap): the caller's edges as GeoJSON,
+			// positioned by spec.location, for the dashboard's map view. Uses
+			// the provider-proxy tenant resolver so the portal's workspace
+			// selection applies here too.
+			fleetmap.NewHandler(kcpConfig, tenantResolver, logger).Register(router)
+			logger.Info("Fleet map registered at " + fleetmap.PathPrefix)
+
+			// Search (/services/search): free-text and label queries over
+			// the caller's edges, workloads and placements, answered from
+			// per-workspace informers started on first use, for "kedge
+			// search" and UI type-ahead.
+			search.NewHandler(ctx, kcpConfig, tenantResolver, s.opts.SearchIndexTTL, logger).Register(router)
+			logger.Info("Search registered at " + search.PathPrefix)
+
+			// kcp latency report (/services/latency): the time kcp took to
+			// answer the caller's workspaces' proxied requests, per
+			// workspace and resource. The same observations are exported as
+			// kedge_hub_kcp_request_duration_seconds on --debug-addr.
+			router.HandleFunc(proxy.LatencyPath, kcpProxy.ServeLatency).Methods("GET")
+			logger.Info("kcp latency report registered at " + proxy.LatencyPath)
 		}
 	}
 
 	// 7. Create and start multicluster controllers (when kcp is configured)
+	shard := sharding.New(s.opts.ControllerShards, s.opts.ControllerLeaderElection)
+	shardErrCh := make(chan error, 1)
 	if kcpConfig != nil {
 		// Initialize controller-runtime logger (bridges to klog).
 		ctrl.SetLogger(klog.NewKlogr())
@@ -631,12 +830,30 @@ func (s *Server) Run(ctx context.Context) error {
 			}
 		}()
 
+		// With --controller-shards, hub replicas divide the tenant workspaces
+		// between them: each claims a shard through a Lease in
+		// root:kedge:system:controllers and the core.faros.sh manager engages
+		// only that shard's workspaces. --controller-leader-election makes
+		// even a single shard claimed by one replica, the others standing by. The catalog manager above keeps
+		// running everywhere, since it fills each replica's provider
+		// registry; the managers reconciling a single workspace run on the
+		// replica holding shard 0 only.
+		go func() {
+			if err := shard.Claim(ctx, providersConfig); err != nil && ctx.Err() == nil {
+				shardErrCh <- err
+			}
+		}()
+
 		// MCPServer reconciler: MCPServer is a built-in, core-hosted provider —
 		// its CRD is distributed to tenants via core.faros.sh, so we re-introduce
 		// a core.faros.sh multicluster manager (removed in the edge extraction)
 		// to run it. It provisions each server's identity across all tenant
 		// workspaces. The aggregate serving lives in pkg/hub/mcpaggregate.
-		coreExportProvider, err := apiexport.New(providersConfig, "core.faros.sh", apiexport.Options{Scheme: scheme})
+		coreExportProvider, err := apiexport.New(providersConfig, "core.faros.sh", apiexport.Options{
+			Scheme:       scheme,
+			AddFilter:    shard.Filter(provider.ConditionReadyFunc("Ready")),
+			UpdateFilter: shard.Filter(provider.ConditionReadyFunc("Ready")),
+		})
 		if err != nil {
 			return fmt.Errorf("creating core.faros.sh multicluster provider: %w", err)
 		}
@@ -651,7 +868,10 @@ func (s *Server) Run(ctx context.Context) error {
 			return fmt.Errorf("setting up mcpserver controller: %w", err)
 		}
 		go func() {
-			logger.Info("Starting core multicluster manager (mcpserver)")
+			if !shard.Wait(ctx) {
+				return
+			}
+			logger.Info("Starting core multicluster manager (mcpserver)", "shard", shard.Index(), "shards", shard.Count())
 			if err := coreMgr.Start(ctx); err != nil {
 				logger.Error(err, "Core multicluster manager failed")
 			}
@@ -683,6 +903,9 @@ func (s *Server) Run(ctx context.Context) error {
 			return fmt.Errorf("setting up provider provisioning controller: %w", err)
 		}
 		go func() {
+			if !shard.Wait(ctx) || shard.Index() != 0 {
+				return
+			}
 			logger.Info("Starting admin multicluster manager")
 			if err := adminMgr.Start(ctx); err != nil {
 				logger.Error(err, "Admin multicluster manager failed")
@@ -701,6 +924,9 @@ func (s *Server) Run(ctx context.Context) error {
 			return fmt.Errorf("setting up organization bootstrap controller: %w", err)
 		}
 		go func() {
+			if !shard.Wait(ctx) || shard.Index() != 0 {
+				return
+			}
 			logger.Info("Starting organization bootstrap manager")
 			if err := orgMgr.Start(ctx); err != nil {
 				logger.Error(err, "Organization bootstrap manager failed")
@@ -718,11 +944,37 @@ func (s *Server) Run(ctx context.Context) error {
 			return fmt.Errorf("setting up soft-delete reconciler: %w", err)
 		}
 		go func() {
+			if !shard.Wait(ctx) || shard.Index() != 0 {
+				return
+			}
 			logger.Info("Starting soft-delete manager")
 			if err := softdeleteMgr.Start(ctx); err != nil {
 				logger.Error(err, "Soft-delete manager failed")
 			}
 		}()
+
+		// Directory sync — maps IdP groups onto Org and team Workspace
+		// memberships per Organization.spec.groupBindings. Opt-in: the
+		// IdP must issue the groups claim (docs/organizations.md
+		// §Directory sync).
+		if s.opts.DirectorySync {
+			directoryMgr, err := directory.NewManager(bootstrapper.UsersConfig(), scheme)
+			if err != nil {
+				return fmt.Errorf("creating directory sync manager: %w", err)
+			}
+			if err := directory.SetupWithManager(directoryMgr, bootstrapper, providerRegistry); err != nil {
+				return fmt.Errorf("setting up directory sync controller: %w", err)
+			}
+			go func() {
+				if !shard.Wait(ctx) || shard.Index() != 0 {
+					return
+				}
+				logger.Info("Starting directory sync manager")
+				if err := directoryMgr.Start(ctx); err != nil {
+					logger.Error(err, "Directory sync manager failed")
+				}
+			}()
+		}
 	}
 
 	// Portal: serve Vue.js SPA under /ui. Two modes:
@@ -770,6 +1022,41 @@ func (s *Server) Run(ctx context.Context) error {
 		uiProxy.SetFallback(portalSPA)
 	}
 
+	// Shadow traffic: a share of kcp API reads is replayed against a canary.
+	var kcpHandler http.Handler
+	if kcpProxy != nil {
+		kcpHandler = metrics.InstrumentProxy(metrics.UpstreamKCP, kcpProxy)
+		if s.opts.MirrorURL != "" {
+			kcpHandler, err = mirror.NewHandler(ctx, kcpHandler, mirror.Options{
+				Target:                s.opts.MirrorURL,
+				Percent:               s.opts.MirrorPercent,
+				InsecureSkipTLSVerify: s.opts.MirrorInsecureSkipTLSVerify,
+			})
+			if err != nil {
+				return err
+			}
+			logger.Info("Mirroring kcp API reads", "target", s.opts.MirrorURL, "percent", s.opts.MirrorPercent)
+		}
+	} else if s.opts.MirrorURL != "" {
+		return fmt.Errorf("--mirror-url requires kcp and an authentication method")
+	}
+
+	// Audit log: every kcp API and service request, on every listener.
+	var auditLogger *audit.Logger
+	if len(s.opts.AuditSinks) > 0 {
+		sinks := make([]audit.Sink, 0, len(s.opts.AuditSinks))
+		for _, spec := range s.opts.AuditSinks {
+			sink, err := audit.ParseSink(spec)
+			if err != nil {
+				return err
+			}
+			sinks = append(sinks, sink)
+		}
+		auditLogger = audit.NewLogger(sinks...)
+		go auditLogger.Run(ctx)
+		logger.Info("Audit logging enabled", "sinks", s.opts.AuditSinks)
+	}
+
 	// 8. Swap the HTTP server handler from the early bootstrap mux to the full
 	// router now that initialisation is complete.
 	// Routing order:
@@ -789,11 +1076,11 @@ func (s *Server) Run(ctx context.Context) error {
 		//  - /clusters/<cluster>/...          user kubeconfig / kubectl-ws
 		//  - /apis/<group>/... or /api/v1/... agent's bare kcp calls
 		//    (serveServiceAccount prepends /clusters/<name> from SA token claim)
-		if kcpProxy != nil {
+		if kcpHandler != nil {
 			if strings.HasPrefix(r.URL.Path, "/clusters/") ||
 				strings.HasPrefix(r.URL.Path, "/apis/") ||
 				strings.HasPrefix(r.URL.Path, "/api/") {
-				kcpProxy.ServeHTTP(w, r)
+				kcpHandler.ServeHTTP(w, r)
 				return
 			}
 		}
@@ -805,20 +1092,77 @@ func (s *Server) Run(ctx context.Context) error {
 		// 4. Nothing matched.
 		http.NotFound(w, r)
 	})
-	delegate.set(fullHandler)
+	if auditLogger != nil {
+		delegate.set(auditLogger.Handler(fullHandler))
+	} else {
+		delegate.set(fullHandler)
+	}
 	logger.Info("Full HTTP handler installed; server is ready")
 
+	// Read-only endpoint: the kcp API proxy restricted to reads on its own
+	// listener (same serving cert), for heavy list/watch clients.
+	var readOnlyErrCh chan error
+	if s.opts.ReadOnlyListenAddr != "" {
+		if kcpProxy == nil {
+			return fmt.Errorf("--read-only-listen-addr requires kcp and an authentication method")
+		}
+		readOnlyErrCh = make(chan error, 1)
+		readOnlyMux := http.NewServeMux()
+		readOnlyMux.Handle("/healthz", fullHandler)
+		readOnlyMux.Handle("/readyz", fullHandler)
+		var readOnlyKCP http.Handler = readonly.NewHandler(kcpHandler, readonly.Options{CacheTTL: s.opts.ReadOnlyCacheTTL})
+		if auditLogger != nil {
+			readOnlyKCP = auditLogger.Handler(readOnlyKCP)
+		}
+		for _, prefix := range []string{"/clusters/", "/apis/", "/api/"} {
+			readOnlyMux.Handle(prefix, readOnlyKCP)
+		}
+		readOnlyServer := &http.Server{
+			Addr:              s.opts.ReadOnlyListenAddr,
+			Handler:           readOnlyMux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), s.opts.ShutdownGracePeriod)
+			defer cancel()
+			_ = readOnlyServer.Shutdown(shutdownCtx)
+		}()
+		go func() {
+			logger.Info("Read-only endpoint starting", "addr", s.opts.ReadOnlyListenAddr, "cacheTTL", s.opts.ReadOnlyCacheTTL.String())
+			var err error
+			if s.opts.ServingCertFile != "" && s.opts.ServingKeyFile != "" {
+				err = readOnlyServer.ListenAndServeTLS(s.opts.ServingCertFile, s.opts.ServingKeyFile)
+			} else {
+				err = readOnlyServer.ListenAndServe()
+			}
+			if err != nil && err != http.ErrServerClosed {
+				readOnlyErrCh <- err
+			}
+		}()
+	}
+
 	// Wait for either HTTP server error, kcp error, or context cancellation.
 	select {
 	case err := <-httpErrCh:
 		if err != nil {
 			return fmt.Errorf("HTTP server error: %w", err)
 		}
+		// Closed by a shutdown: wait for the sessions to drain.
+		<-drained
+	case err := <-readOnlyErrCh:
+		return fmt.Errorf("read-only endpoint error: %w", err)
 	case err := <-kcpErrCh:
 		return fmt.Errorf("embedded kcp server failed: %w", err)
+	case err := <-shardErrCh:
+		return fmt.Errorf("claiming a controller shard: %w", err)
+	case <-shard.Lost():
+		return fmt.Errorf("lost the controller shard lease")
 	case <-ctx.Done():
-		// Wait for HTTP server to finish shutting down.
+		// Wait for HTTP server to finish shutting down and its sessions to
+		// drain.
 		<-httpErrCh
+		<-drained
 	}
 
 	// If embedded GraphQL was started, wait for its goroutines to finish.
@@ -870,7 +1214,7 @@ func (d *delegatingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	h := d.current
 	d.mu.RUnlock()
 	if h == nil {
-		http.Error(w, "server initialising", http.StatusServiceUnavailable)
+		problem.Write(w, r, http.StatusServiceUnavailable, problem.ReasonServiceUnavailable, "server initialising")
 		return
 	}
 	h.ServeHTTP(w, r)
@@ -880,3 +1224,14 @@ func (d *delegatingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 // the dedicated per-kind CRDs were collapsed into the MCPServer
 // aggregate. Per-tenant default MCPServer creation lives in
 // pkg/hub/kcp/bootstrap.go (EnsureDefaultMCPServer).
+
+// oidcConfig returns the auth handler configuration of idp, before the
+// hub-wide settings (redirect URL, groups, step-up) are applied.
+func oidcConfig(idp IdentityProvider) *auth.OIDCConfig {
+	c := auth.DefaultOIDCConfig()
+	c.Name = idp.Name
+	c.Namespace = idp.Namespace
+	c.IssuerURL = idp.IssuerURL
+	c.ClientID = idp.ClientID
+	return c
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sharding divides the tenant workspaces reconciled by the hub's
+// multicluster controllers among hub replicas.
+//
+// Workspaces are partitioned into a fixed number of shards by a hash of their
+// logical cluster name. Each replica claims one shard by holding its Lease,
+// kedge-hub-controllers-shard-<n>, in root:kedge:system:controllers, and
+// engages only the workspaces of that shard. Replicas beyond the shard count
+// wait as standbys and take over a shard whose holder stops renewing it.
+//
+// With a single shard there is no Lease and every replica reconciles every
+// workspace, unless leader election is on: the shard is then claimed like any
+// other, by one replica, and the others wait as standbys.
+//
+// A replica that loses its Lease must not keep reconciling: Lost is closed and
+// the hub exits, to be restarted and claim a shard anew.
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// leasePrefix and leaseNamespace name the shard Leases.
+	leasePrefix    = "kedge-hub-controllers-shard-"
+	leaseNamespace = "default"
+
+	leaseDuration = 15 * time.Second
+	renewDeadline = 10 * time.Second
+	retryPeriod   = 2 * time.Second
+)
+
+// Shard is the share of workspaces this replica reconciles.
+type Shard struct {
+	count   int
+	elect   bool
+	index   atomic.Int32
+	claimed chan struct{}
+	lost    chan struct{}
+	lostOne sync.Once
+}
+
+// New returns the shard of a replica among count. With count <= 1 there is a
+// single shard, owned without a Lease unless elect is set: every workspace is
+// reconciled here.
+func New(count int, elect bool) *Shard {
+	s := &Shard{count: max(count, 1), elect: elect, claimed: make(chan struct{}), lost: make(chan struct{})}
+	s.index.Store(-1)
+	if s.count == 1 && !elect {
+		s.index.Store(0)
+		close(s.claimed)
+	}
+	return s
+}
+
+// Count returns the number of shards.
+func (s *Shard) Count() int {
+	return s.count
+}
+
+// Index returns the shard this replica claimed, or -1 before Claim returned.
+func (s *Shard) Index() int {
+	return int(s.index.Load())
+}
+
+// Lost is closed when this replica lost the Lease of its shard.
+func (s *Shard) Lost() <-chan struct{} {
+	return s.lost
+}
+
+// Wait blocks until this replica claimed its shard and reports whether it
+// did, false meaning ctx is done.
+func (s *Shard) Wait(ctx context.Context) bool {
+	select {
+	case <-s.claimed:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Owns reports whether the logical cluster is in this replica's shard.
+func (s *Shard) Owns(cluster string) bool {
+	if s.count == 1 {
+		return true
+	}
+	return ShardOf(cluster, s.count) == s.Index()
+}
+
+// ShardOf returns the shard among count the logical cluster belongs to.
+func ShardOf(cluster string, count int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(cluster))
+	return int(h.Sum32() % uint32(count))
+}
+
+// Filter returns a multicluster provider AddFilter/UpdateFilter accepting the
+// objects next accepts whose logical cluster is in this replica's shard.
+func (s *Shard) Filter(next func(client.Object) (bool, error)) func(client.Object) (bool, error) {
+	return func(obj client.Object) (bool, error) {
+		if !s.Owns(logicalcluster.From(obj).String()) {
+			return false, nil
+		}
+		if next == nil {
+			return true, nil
+		}
+		return next(obj)
+	}
+}
+
+// Claim blocks until this replica holds the Lease of a shard, or ctx is done.
+// config addresses the workspace holding the Leases. The Lease is renewed
+// until ctx is done, then released; if renewal fails, Lost is closed.
+func (s *Shard) Claim(ctx context.Context, config *rest.Config) error {
+	if s.count == 1 && !s.elect {
+		return nil
+	}
+	logger := klog.FromContext(ctx).WithName("sharding")
+	cs, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("creating lease client: %w", err)
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("getting hostname: %w", err)
+	}
+	identity := hostname + "_" + utilrand.String(8)
+
+	// Compete for every shard; the first one won is kept and the others
+	// released, so replicas spread over the free shards.
+	cancels := make([]context.CancelFunc, s.count)
+	for i := range s.count {
+		shardCtx, cancel := context.WithCancel(ctx)
+		cancels[i] = cancel
+		le, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+			Lock: &resourcelock.LeaseLock{
+				LeaseMeta:  metav1.ObjectMeta{Name: fmt.Sprintf("%s%d", leasePrefix, i), Namespace: leaseNamespace},
+				Client:     cs.CoordinationV1(),
+				LockConfig: resourcelock.ResourceLockConfig{Identity: identity},
+			},
+			LeaseDuration:   leaseDuration,
+			RenewDeadline:   renewDeadline,
+			RetryPeriod:     retryPeriod,
+			ReleaseOnCancel: true,
+			Name:            fmt.Sprintf("%s%d", leasePrefix, i),
+			Callbacks: leaderelection.LeaderCallbacks{
+				OnStartedLeading: func(context.Context) {
+					if s.index.CompareAndSwap(-1, int32(i)) {
+						close(s.claimed)
+						return
+					}
+					cancel()
+				},
+				OnStoppedLeading: func() {
+					if s.Index() == i && ctx.Err() == nil {
+						logger.Error(nil, "Lost controller shard lease", "shard", i)
+						s.lostOne.Do(func() { close(s.lost) })
+					}
+				},
+			},
+		})
+		if err != nil {
+			for _, cancel := range cancels[:i+1] {
+				cancel()
+			}
+			return fmt.Errorf("creating shard %d elector: %w", i, err)
+		}
+		go le.Run(shardCtx)
+	}
+
+	logger.Info("Waiting for a controller shard", "shards", s.count, "identity", identity)
+	select {
+	case <-s.claimed:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	for i, cancel := range cancels {
+		if i != s.Index() {
+			cancel()
+		}
+	}
+	logger.Info("Claimed controller shard", "shard", s.Index(), "shards", s.count)
+	return nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestShardOf(t *testing.T) {
+	const count = 4
+	perShard := make([]int, count)
+	for i := range 1000 {
+		cluster := fmt.Sprintf("cluster-%d", i)
+		shard := ShardOf(cluster, count)
+		if shard != ShardOf(cluster, count) {
+			t.Fatalf("ShardOf(%q) is not stable", cluster)
+		}
+		perShard[shard]++
+	}
+	for shard, n := range perShard {
+		if n < 150 {
+			t.Errorf("shard %d got %d of 1000 clusters", shard, n)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	binding := func(cluster string) client.Object {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:        "b",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: cluster},
+		}}
+	}
+	ready := func(client.Object) (bool, error) { return true, nil }
+
+	// A single shard owns everything, without a Lease.
+	single := New(1, false)
+	if err := single.Claim(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := single.Filter(ready)(binding("abc")); !ok {
+		t.Error("single shard rejected a cluster")
+	}
+
+	// An elected single shard owns nothing until claimed.
+	elected := New(1, true)
+	if elected.Index() != -1 {
+		t.Error("elected shard owned before its Lease was claimed")
+	}
+
+	s := New(3, false)
+	if ok, _ := s.Filter(ready)(binding("abc")); ok {
+		t.Error("unclaimed shard accepted a cluster")
+	}
+	s.index.Store(int32(ShardOf("abc", 3)))
+	if ok, _ := s.Filter(ready)(binding("abc")); !ok {
+		t.Error("shard rejected its own cluster")
+	}
+	if ok, _ := s.Filter(func(client.Object) (bool, error) { return false, nil })(binding("abc")); ok {
+		t.Error("shard accepted a cluster next rejected")
+	}
+	for i := range 100 {
+		cluster := fmt.Sprintf("cluster-%d", i)
+		if ok, _ := s.Filter(nil)(binding(cluster)); ok != (ShardOf(cluster, 3) == s.Index()) {
+			t.Errorf("Filter(%q) = %v", cluster, ok)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package problem is the single error-response format for hub HTTP handlers:
+// RFC 7807 application/problem+json bodies carrying a stable, machine-readable
+// reason code that the CLI (and any other client) can switch on.
+//
+// API-style paths (/clusters, /api, /apis, /openapi) are also consumed by
+// kubectl and client-go, which only understand the Kubernetes Status envelope.
+// On those paths Write keeps emitting a Status unless the client explicitly
+// asks for application/problem+json. Both shapes carry the same reason code.
+package problem
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContentType is the RFC 7807 media type.
+const ContentType = "application/problem+json"
+
+// typePrefix namespaces problem type URIs; the reason code is appended.
+const typePrefix = "urn:kedge:problem:"
+
+// Stable reason codes. They match the Kubernetes StatusReason spelling where
+// one exists so Status and problem bodies agree. Never rename a published
+// value — clients key behaviour off them.
+const (
+	// ReasonUnauthorized: no credentials, or credentials the hub does not
+	// recognise. Re-authenticating is the fix.
+	ReasonUnauthorized = "Unauthorized"
+	// ReasonTokenExpired: a recognised token that is past its expiry.
+	ReasonTokenExpired = "TokenExpired"
+	// ReasonStepUpRequired: a recognised token, but the operation needs a
+	// more recent sign-in or a second factor; see the WWW-Authenticate
+	// challenge for the max_age. `kedge login --step-up` is the fix.
+	ReasonStepUpRequired = "StepUpRequired"
+	// ReasonForbidden: authenticated, but not allowed to do this.
+	ReasonForbidden = "Forbidden"
+	// ReasonAdminRequired: the endpoint is restricted to hub admins.
+	ReasonAdminRequired = "AdminRequired"
+	// ReasonOrgWorkspaceNotDirectlyAccessible: the request addressed an
+	// Organization workspace, which is only reachable through the hub REST
+	// endpoints under /api/orgs/{org}.
+	ReasonOrgWorkspaceNotDirectlyAccessible = "OrgWorkspaceNotDirectlyAccessible"
+	// ReasonTooManyRequests: rate limited; see Retry-After.
+	ReasonTooManyRequests = "TooManyRequests"
+	// ReasonBadRequest: the request itself is malformed.
+	ReasonBadRequest = "BadRequest"
+	// ReasonNotFound: the addressed object or route does not exist.
+	ReasonNotFound = "NotFound"
+	// ReasonMethodNotAllowed: wrong HTTP method for the route.
+	ReasonMethodNotAllowed = "MethodNotAllowed"
+	// ReasonNotImplemented: the route exists but is not implemented.
+	ReasonNotImplemented = "NotImplemented"
+	// ReasonServiceUnavailable: a dependency (kcp, a provider) is not ready.
+	ReasonServiceUnavailable = "ServiceUnavailable"
+	// ReasonInternalError: anything else on the hub side.
+	ReasonInternalError = "InternalError"
+)
+
+// Problem is an RFC 7807 problem details object with kedge extension members.
+type Problem struct {
+	// Type is a URI identifying the problem type ("urn:kedge:problem:<reason>").
+	Type string `json:"type"`
+	// Title is the short, reason-level summary.
+	Title string `json:"title"`
+	// Status is the HTTP status code.
+	Status int `json:"status"`
+	// Detail is the occurrence-specific human-readable explanation.
+	Detail string `json:"detail,omitempty"`
+	// Instance is the request path that produced the problem.
+	Instance string `json:"instance,omitempty"`
+	// Reason is the stable machine-readable code (one of the Reason* constants).
+	Reason string `json:"reason"`
+	// RetryAfter mirrors the Retry-After header, in seconds, when set.
+	RetryAfter int `json:"retryAfter,omitempty"`
+}
+
+// Error implements error so a parsed Problem can be returned directly.
+func (p *Problem) Error() string {
+	if p.Detail != "" {
+		return p.Detail
+	}
+	return p.Title
+}
+
+// New builds a Problem for status/reason with the given detail.
+func New(status int, reason, detail string) *Problem {
+	return &Problem{
+		Type:   typePrefix + reason,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Reason: reason,
+	}
+}
+
+// Write writes an error response for r, as problem+json or — on API-style
+// paths for clients that did not ask for problem+json — as a Kubernetes Status.
+func Write(w http.ResponseWriter, r *http.Request, status int, reason, detail string) {
+	p := New(status, reason, detail)
+	if r != nil {
+		p.Instance = r.URL.Path
+	}
+	WriteProblem(w, r, p)
+}
+
+// WriteTooManyRequests writes a 429 with a Retry-After header.
+func WriteTooManyRequests(w http.ResponseWriter, r *http.Request, retryAfter time.Duration) {
+	secs := int(retryAfter.Round(time.Second) / time.Second)
+	if secs < 1 {
+		secs = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(secs))
+	p := New(http.StatusTooManyRequests, ReasonTooManyRequests, "rate limit exceeded - too many requests")
+	p.RetryAfter = secs
+	if r != nil {
+		p.Instance = r.URL.Path
+	}
+	WriteProblem(w, r, p)
+}
+
+// WriteProblem writes p, negotiating the body shape as Write does.
+func WriteProblem(w http.ResponseWriter, r *http.Request, p *Problem) {
+	if r != nil && isAPIPath(r.URL.Path) && !acceptsProblem(r) {
+		writeStatus(w, p)
+		return
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// writeStatus emits the Kubernetes Status envelope kubectl renders.
+func writeStatus(w http.ResponseWriter, p *Problem) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(p.Status)
+	message := p.Detail
+	if message == "" {
+		message = p.Title
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"kind":       "Status",
+		"apiVersion": "v1",
+		"metadata":   map[string]any{},
+		"status":     "Failure",
+		"message":    message,
+		"reason":     p.Reason,
+		"code":       p.Status,
+	})
+}
+
+// isAPIPath reports whether path is served to Kubernetes-style clients. The
+// hub's own REST endpoints (/api/orgs, /api/admin, ...) share the /api prefix
+// with the core group, so only /api and its versioned /api/v* paths count.
+func isAPIPath(path string) bool {
+	for _, prefix := range []string{"/clusters/", "/api/v", "/apis/", "/openapi/"} {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return path == "/api" || path == "/apis"
+}
+
+// acceptsProblem reports whether the client listed application/problem+json
+// in its Accept header.
+func acceptsProblem(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		if mt, _, err := mime.ParseMediaType(strings.TrimSpace(part)); err == nil && mt == ContentType {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse decodes an error response from the hub into a Problem. It understands
+// problem+json, the Kubernetes Status envelope, the legacy {"error": "..."}
+// body and plain text, so callers get a reason code regardless of which
+// handler (or hub version) answered. retryAfter is the Retry-After header.
+func Parse(status int, retryAfter string, body []byte) *Problem {
+	var raw struct {
+		Reason     string `json:"reason"`     // problem+json and Status
+		Detail     string `json:"detail"`     // problem+json
+		Message    string `json:"message"`    // Status
+		Error      string `json:"error"`      // legacy
+		RetryAfter int    `json:"retryAfter"` // problem+json
+	}
+	p := New(status, ReasonForStatus(status), "")
+	if err := json.Unmarshal(body, &raw); err == nil {
+		if raw.Reason != "" {
+			p.Reason = raw.Reason
+			p.Type = typePrefix + raw.Reason
+		}
+		switch {
+		case raw.Detail != "":
+			p.Detail = raw.Detail
+		case raw.Message != "":
+			p.Detail = raw.Message
+		case raw.Error != "":
+			p.Detail = raw.Error
+		}
+		p.RetryAfter = raw.RetryAfter
+	} else {
+		p.Detail = strings.TrimSpace(string(body))
+	}
+	if secs, err := strconv.Atoi(retryAfter); err == nil && p.RetryAfter == 0 {
+		p.RetryAfter = secs
+	}
+	return p
+}
+
+// ReasonForStatus is the default reason for an HTTP status, used when a
+// handler has nothing more specific and when a response body carries none.
+func ReasonForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return ReasonBadRequest
+	case http.StatusUnauthorized:
+		return ReasonUnauthorized
+	case http.StatusForbidden:
+		return ReasonForbidden
+	case http.StatusNotFound:
+		return ReasonNotFound
+	case http.StatusMethodNotAllowed:
+		return ReasonMethodNotAllowed
+	case http.StatusTooManyRequests:
+		return ReasonTooManyRequests
+	case http.StatusNotImplemented:
+		return ReasonNotImplemented
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return ReasonServiceUnavailable
+	default:
+		return ReasonInternalError
+	}
+}
+
+// String renders p for logs: "<status> <reason>: <detail>".
+func (p *Problem) String() string {
+	return fmt.Sprintf("%d %s: %s", p.Status, p.Reason, p.Error())
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWriteNegotiatesShape(t *testing.T) {
+	cases := []struct {
+		name        string
+		path        string
+		accept      string
+		wantProblem bool
+	}{
+		{"auth endpoint", "/auth/token-login", "", true},
+		{"cluster path", "/clusters/abc/api/v1/pods", "", false},
+		{"core API", "/api/v1/pods", "", false},
+		{"core API discovery", "/api", "", false},
+		{"core API asking for problem", "/api/v1/pods", "application/json, application/problem+json", true},
+		{"hub REST", "/api/orgs", "application/json", true},
+		{"hub admin REST", "/api/admin/users", "", true},
+		{"provider proxy", "/services/providers/edges/x", "", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.accept != "" {
+				r.Header.Set("Accept", tc.accept)
+			}
+			w := httptest.NewRecorder()
+			Write(w, r, http.StatusForbidden, ReasonForbidden, "nope")
+
+			if w.Code != http.StatusForbidden {
+				t.Fatalf("code = %d, want 403", w.Code)
+			}
+			var body map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decoding body: %v", err)
+			}
+			if body["reason"] != ReasonForbidden {
+				t.Errorf("reason = %v, want %s", body["reason"], ReasonForbidden)
+			}
+			gotProblem := w.Header().Get("Content-Type") == ContentType
+			if gotProblem != tc.wantProblem {
+				t.Errorf("problem+json = %v, want %v (body %s)", gotProblem, tc.wantProblem, w.Body.String())
+			}
+			if tc.wantProblem && body["type"] != "urn:kedge:problem:Forbidden" {
+				t.Errorf("type = %v", body["type"])
+			}
+			if !tc.wantProblem && body["kind"] != "Status" {
+				t.Errorf("kind = %v, want Status", body["kind"])
+			}
+		})
+	}
+}
+
+func TestWriteTooManyRequests(t *testing.T) {
+	w := httptest.NewRecorder()
+	WriteTooManyRequests(w, httptest.NewRequest(http.MethodPost, "/auth/token-login", nil), time.Minute)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("code = %d, want 429", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "60" {
+		t.Errorf("Retry-After = %q, want 60", got)
+	}
+	p := Parse(w.Code, w.Header().Get("Retry-After"), w.Body.Bytes())
+	if p.Reason != ReasonTooManyRequests || p.RetryAfter != 60 {
+		t.Errorf("parsed = %+v", p)
+	}
+}
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		name       string
+		status     int
+		body       string
+		wantReason string
+		wantDetail string
+	}{
+		{"problem", 401, `{"type":"urn:kedge:problem:TokenExpired","status":401,"reason":"TokenExpired","detail":"expired"}`, ReasonTokenExpired, "expired"},
+		{"status envelope", 403, `{"kind":"Status","reason":"AdminRequired","message":"admins only","code":403}`, ReasonAdminRequired, "admins only"},
+		{"legacy error", 400, `{"error":"bad"}`, ReasonBadRequest, "bad"},
+		{"plain text", 503, "server initialising\n", ReasonServiceUnavailable, "server initialising"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := Parse(tc.status, "", []byte(tc.body))
+			if p.Reason != tc.wantReason || p.Detail != tc.wantDetail || p.Status != tc.status {
+				t.Errorf("Parse() = %+v, want reason %q detail %q", p, tc.wantReason, tc.wantDetail)
+			}
+		})
+	}
+}
@@ -43,6 +43,7 @@ import (
 	"github.com/faroshq/faros-kedge/pkg/apiurl"
 	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
 	"github.com/faroshq/faros-kedge/pkg/hub/kcp"
+	"github.com/faroshq/faros-kedge/pkg/problem"
 )
 
 // defaultRateLimit is the default number of requests allowed per minute per IP.
@@ -53,9 +54,9 @@ const defaultBurstDuration = time.Minute
 
 // Handler provides OAuth2/OIDC authentication endpoints.
 type Handler struct {
-	oidcProvider   *oidc.Provider
-	oauth2Config   *oauth2.Config
-	oidcConfig     *OIDCConfig
+	// providers are the identity providers users sign in with; the first
+	// is the primary.
+	providers      []*provider
 	kedgeClient    *kedgeclient.Client
 	bootstrapper   *kcp.Bootstrapper
 	hubExternalURL string
@@ -63,12 +64,23 @@ type Handler struct {
 	logger         klog.Logger
 	// rateLimiter protects auth endpoints against brute force attacks
 	rateLimiter *rateLimiter
+	// lockout refuses clients whose callbacks keep failing; nil disables it.
+	lockout *Lockout
 }
 
-// NewHandler creates a new OIDC auth handler.
-func NewHandler(ctx context.Context, config *OIDCConfig, kedgeClient *kedgeclient.Client, bootstrapper *kcp.Bootstrapper, hubExternalURL string, devMode bool) (*Handler, error) {
-	if config.IssuerURL == "" {
-		return nil, fmt.Errorf("OIDC issuer URL is required")
+// provider is one identity provider of a Handler.
+type provider struct {
+	config   *OIDCConfig
+	oauth2   *oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+// NewHandler creates a new OIDC auth handler for one or more identity
+// providers. The first is the primary: /auth/authorize signs in with it
+// unless the request names another.
+func NewHandler(ctx context.Context, configs []*OIDCConfig, kedgeClient *kedgeclient.Client, bootstrapper *kcp.Bootstrapper, hubExternalURL string, devMode bool) (*Handler, error) {
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("at least one OIDC identity provider is required")
 	}
 
 	// In dev mode, skip TLS verification for OIDC discovery (self-signed certs).
@@ -81,24 +93,31 @@ func NewHandler(ctx context.Context, config *OIDCConfig, kedgeClient *kedgeclien
 		providerCtx = oidc.ClientContext(ctx, httpClient)
 	}
 
-	provider, err := oidc.NewProvider(providerCtx, config.IssuerURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create OIDC provider: %w", err)
-	}
-
-	// No ClientSecret: kedge uses PKCE (public client). Dex must be configured
-	// with public: true for this client ID.
-	oauth2Config := &oauth2.Config{
-		ClientID:    config.ClientID,
-		RedirectURL: config.RedirectURL,
-		Endpoint:    provider.Endpoint(),
-		Scopes:      config.Scopes,
+	providers := make([]*provider, 0, len(configs))
+	for _, config := range configs {
+		if config.IssuerURL == "" {
+			return nil, fmt.Errorf("OIDC issuer URL is required")
+		}
+		oidcProvider, err := oidc.NewProvider(providerCtx, config.IssuerURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OIDC provider %s: %w", config.IssuerURL, err)
+		}
+		providers = append(providers, &provider{
+			config: config,
+			// No ClientSecret: kedge uses PKCE (public client). Dex must be
+			// configured with public: true for this client ID.
+			oauth2: &oauth2.Config{
+				ClientID:    config.ClientID,
+				RedirectURL: config.RedirectURL,
+				Endpoint:    oidcProvider.Endpoint(),
+				Scopes:      config.Scopes,
+			},
+			verifier: oidcProvider.Verifier(&oidc.Config{ClientID: config.ClientID}),
+		})
 	}
 
 	handler := &Handler{
-		oidcProvider:   provider,
-		oauth2Config:   oauth2Config,
-		oidcConfig:     config,
+		providers:      providers,
 		kedgeClient:    kedgeClient,
 		bootstrapper:   bootstrapper,
 		hubExternalURL: hubExternalURL,
@@ -116,6 +135,14 @@ func NewHandler(ctx context.Context, config *OIDCConfig, kedgeClient *kedgeclien
 // CLI mode:    GET /auth/authorize?p=<port>&s=<sessionID>&v=<codeVerifier>
 // Portal mode: GET /auth/authorize?redirect_uri=<url>&s=<sessionID>&v=<codeVerifier>
 //
+// Either mode may add idp=<name> to sign in with an identity provider other
+// than the primary; the issuer URL is accepted as well as the name.
+//
+// Either mode may add max_age=<seconds> to step up: it is passed on to the
+// OIDC provider (max_age=0 also sends prompt=login), which makes the user
+// sign in again — with a second factor if the IdP requires one — so the
+// issued ID token carries a fresh auth_time. See StepUpPolicy.
+//
 // The CLI generates a PKCE code_verifier and passes it as "v". The hub stores
 // it in the OAuth2 state and sends the corresponding S256 code_challenge to
 // the OIDC provider. The verifier is recovered from state in HandleCallback
@@ -131,11 +158,16 @@ func (h *Handler) HandleAuthorize(w http.ResponseWriter, r *http.Request) {
 	port := r.URL.Query().Get("p")
 
 	if sessionID == "" {
-		http.Error(w, "missing s (session) parameter", http.StatusBadRequest)
+		problem.Write(w, r, http.StatusBadRequest, problem.ReasonBadRequest, "missing s (session) parameter")
 		return
 	}
 	if codeVerifier == "" {
-		http.Error(w, "missing v (PKCE code_verifier) parameter", http.StatusBadRequest)
+		problem.Write(w, r, http.StatusBadRequest, problem.ReasonBadRequest, "missing v (PKCE code_verifier) parameter")
+		return
+	}
+	p := h.provider(r.URL.Query().Get("idp"))
+	if p == nil {
+		problem.Write(w, r, http.StatusBadRequest, problem.ReasonBadRequest, "unknown identity provider "+r.URL.Query().Get("idp"))
 		return
 	}
 
@@ -143,7 +175,7 @@ func (h *Handler) HandleAuthorize(w http.ResponseWriter, r *http.Request) {
 	if redirectURI != "" {
 		// Portal flow: validate redirect_uri against the hub's external URL.
 		if err := h.validateRedirectURI(redirectURI); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			problem.Write(w, r, http.StatusBadRequest, problem.ReasonBadRequest, err.Error())
 			return
 		}
 		callbackURL = redirectURI
@@ -151,30 +183,47 @@ func (h *Handler) HandleAuthorize(w http.ResponseWriter, r *http.Request) {
 		// CLI flow: build localhost callback URL from port.
 		portNum, err := strconv.Atoi(port)
 		if err != nil || portNum < 1 || portNum > 65535 {
-			http.Error(w, "invalid port parameter: must be a number between 1 and 65535", http.StatusBadRequest)
+			problem.Write(w, r, http.StatusBadRequest, problem.ReasonBadRequest, "invalid port parameter: must be a number between 1 and 65535")
 			return
 		}
 		callbackURL = fmt.Sprintf("http://127.0.0.1:%d/callback", portNum)
 	} else {
-		http.Error(w, "missing p (port) or redirect_uri parameter", http.StatusBadRequest)
+		problem.Write(w, r, http.StatusBadRequest, problem.ReasonBadRequest, "missing p (port) or redirect_uri parameter")
 		return
 	}
 
+	// Include S256 code_challenge derived from the verifier in the auth URL.
+	authOpts := []oauth2.AuthCodeOption{oauth2.S256ChallengeOption(codeVerifier)}
+	stepUp := false
+	if maxAge := r.URL.Query().Get("max_age"); maxAge != "" {
+		secs, err := strconv.Atoi(maxAge)
+		if err != nil || secs < 0 {
+			problem.Write(w, r, http.StatusBadRequest, problem.ReasonBadRequest, "invalid max_age parameter: must be a non-negative number of seconds")
+			return
+		}
+		stepUp = true
+		authOpts = append(authOpts, oauth2.SetAuthURLParam("max_age", strconv.Itoa(secs)))
+		if secs == 0 {
+			authOpts = append(authOpts, oauth2.SetAuthURLParam("prompt", "login"))
+		}
+	}
+
 	authCode := tenancyv1alpha1.AuthCode{
 		RedirectURL:  callbackURL,
 		SessionID:    sessionID,
 		CodeVerifier: codeVerifier,
+		StepUp:       stepUp,
+		IDP:          p.config.Name,
 	}
 
 	stateJSON, err := json.Marshal(authCode)
 	if err != nil {
-		http.Error(w, "failed to encode state", http.StatusInternalServerError)
+		problem.Write(w, r, http.StatusInternalServerError, problem.ReasonInternalError, "failed to encode state")
 		return
 	}
 	state := base64.URLEncoding.EncodeToString(stateJSON)
 
-	// Include S256 code_challenge derived from the verifier in the auth URL.
-	authURL := h.oauth2Config.AuthCodeURL(state, oauth2.S256ChallengeOption(codeVerifier))
+	authURL := p.oauth2.AuthCodeURL(state, authOpts...)
 	http.Redirect(w, r, authURL, http.StatusFound)
 }
 
@@ -213,23 +262,35 @@ func (h *Handler) validateRedirectURI(redirectURI string) error {
 // GET /auth/callback?code=<code>&state=<state>
 func (h *Handler) HandleCallback(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	if wait, ok := h.lockout.Check(r, "", lockoutEndpointCallback); !ok {
+		problem.WriteTooManyRequests(w, r, wait)
+		return
+	}
 
 	code := r.URL.Query().Get("code")
 	stateParam := r.URL.Query().Get("state")
 	if code == "" || stateParam == "" {
-		http.Error(w, "missing code or state parameter", http.StatusBadRequest)
+		problem.Write(w, r, http.StatusBadRequest, problem.ReasonBadRequest, "missing code or state parameter")
 		return
 	}
 
 	// Decode the state to get the CLI callback URL.
 	stateJSON, err := base64.URLEncoding.DecodeString(stateParam)
 	if err != nil {
-		http.Error(w, "invalid state parameter", http.StatusBadRequest)
+		h.lockout.Fail(r, "", lockoutEndpointCallback)
+		problem.Write(w, r, http.StatusBadRequest, problem.ReasonBadRequest, "invalid state parameter")
 		return
 	}
 	var authCode tenancyv1alpha1.AuthCode
 	if err := json.Unmarshal(stateJSON, &authCode); err != nil {
-		http.Error(w, "invalid state payload", http.StatusBadRequest)
+		h.lockout.Fail(r, "", lockoutEndpointCallback)
+		problem.Write(w, r, http.StatusBadRequest, problem.ReasonBadRequest, "invalid state payload")
+		return
+	}
+	p := h.provider(authCode.IDP)
+	if p == nil {
+		h.lockout.Fail(r, "", lockoutEndpointCallback)
+		problem.Write(w, r, http.StatusBadRequest, problem.ReasonBadRequest, "invalid state payload")
 		return
 	}
 
@@ -243,27 +304,36 @@ func (h *Handler) HandleCallback(w http.ResponseWriter, r *http.Request) {
 		exchangeCtx = context.WithValue(ctx, oauth2.HTTPClient, httpClient)
 	}
 
-	token, err := h.oauth2Config.Exchange(exchangeCtx, code, oauth2.VerifierOption(authCode.CodeVerifier))
+	token, err := p.oauth2.Exchange(exchangeCtx, code, oauth2.VerifierOption(authCode.CodeVerifier))
 	if err != nil {
 		h.logger.Error(err, "failed to exchange code for token")
-		http.Error(w, "token exchange failed", http.StatusInternalServerError)
+		h.lockout.Fail(r, "", lockoutEndpointCallback)
+		problem.Write(w, r, http.StatusInternalServerError, problem.ReasonInternalError, "token exchange failed")
 		return
 	}
 
 	rawIDToken, ok := token.Extra("id_token").(string)
 	if !ok {
-		http.Error(w, "missing id_token", http.StatusInternalServerError)
+		problem.Write(w, r, http.StatusInternalServerError, problem.ReasonInternalError, "missing id_token")
 		return
 	}
 
-	verifier := h.oidcProvider.Verifier(&oidc.Config{ClientID: h.oidcConfig.ClientID})
-	idToken, err := verifier.Verify(ctx, rawIDToken)
+	idToken, err := VerifyIDToken(ctx, p.verifier, rawIDToken, lockoutEndpointCallback)
 	if err != nil {
 		h.logger.Error(err, "failed to verify ID token")
-		http.Error(w, "token verification failed", http.StatusInternalServerError)
+		h.lockout.Fail(r, "", lockoutEndpointCallback)
+		problem.Write(w, r, http.StatusInternalServerError, problem.ReasonInternalError, "token verification failed")
 		return
 	}
 
+	// An IdP that ignores max_age, or reports neither auth_time nor amr,
+	// completes the login but cannot satisfy the step-up policy; say so
+	// instead of leaving the user in a re-login loop with no hint.
+	if authCode.StepUp && !p.config.StepUp.Satisfied(idToken, time.Now()) {
+		h.logger.Info("Step-up login did not satisfy the step-up policy: the identity provider reported no recent auth_time or accepted amr",
+			"subject", idToken.Subject)
+	}
+
 	var claims struct {
 		Email string `json:"email"`
 		Name  string `json:"name"`
@@ -271,7 +341,7 @@ func (h *Handler) HandleCallback(w http.ResponseWriter, r *http.Request) {
 	}
 	if err := idToken.Claims(&claims); err != nil {
 		h.logger.Error(err, "failed to parse ID token claims")
-		http.Error(w, "failed to parse claims", http.StatusInternalServerError)
+		problem.Write(w, r, http.StatusInternalServerError, problem.ReasonInternalError, "failed to parse claims")
 		return
 	}
 
@@ -283,10 +353,19 @@ func (h *Handler) HandleCallback(w http.ResponseWriter, r *http.Request) {
 	// patches User.spec.DefaultCluster itself once the Workspace is
 	// Ready. The auth handler just needs to write the User CR; the
 	// controller does the rest asynchronously.
-	userID, err := h.seedUser(ctx, claims.Email, claims.Name, claims.Sub, h.oidcConfig.IssuerURL)
+	var groups []string
+	if p.config.GroupsClaim != "" {
+		groups, err = groupsFromClaims(idToken, p.config.GroupsClaim)
+		if err != nil {
+			h.logger.Error(err, "failed to parse groups claim", "claim", p.config.GroupsClaim)
+			problem.Write(w, r, http.StatusInternalServerError, problem.ReasonInternalError, "failed to parse claims")
+			return
+		}
+	}
+	userID, err := h.seedUser(ctx, p.config, claims.Email, claims.Name, claims.Sub, p.config.qualifyGroups(groups))
 	if err != nil {
 		h.logger.Error(err, "failed to seed user")
-		http.Error(w, "failed to create user", http.StatusInternalServerError)
+		problem.Write(w, r, http.StatusInternalServerError, problem.ReasonInternalError, "failed to create user")
 		return
 	}
 
@@ -297,10 +376,10 @@ func (h *Handler) HandleCallback(w http.ResponseWriter, r *http.Request) {
 	clusterName := h.lookupDefaultCluster(ctx, userID)
 
 	// Generate kubeconfig using exec credential plugin for automatic token refresh.
-	kubeconfigBytes, err := h.generateKubeconfig(userID, clusterName, claims.Email)
+	kubeconfigBytes, err := h.generateKubeconfig(p.config, userID, clusterName, claims.Email)
 	if err != nil {
 		h.logger.Error(err, "failed to generate kubeconfig")
-		http.Error(w, "failed to generate kubeconfig", http.StatusInternalServerError)
+		problem.Write(w, r, http.StatusInternalServerError, problem.ReasonInternalError, "failed to generate kubeconfig")
 		return
 	}
 
@@ -313,12 +392,12 @@ func (h *Handler) HandleCallback(w http.ResponseWriter, r *http.Request) {
 		UserID:       userID,
 		IDToken:      rawIDToken,
 		RefreshToken: token.RefreshToken,
-		IssuerURL:    h.oidcConfig.IssuerURL,
-		ClientID:     h.oidcConfig.ClientID,
+		IssuerURL:    p.config.IssuerURL,
+		ClientID:     p.config.ClientID,
 	}
 	respJSON, err := json.Marshal(resp)
 	if err != nil {
-		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		problem.Write(w, r, http.StatusInternalServerError, problem.ReasonInternalError, "failed to encode response")
 		return
 	}
 	encoded := base64.URLEncoding.EncodeToString(respJSON)
@@ -328,12 +407,44 @@ func (h *Handler) HandleCallback(w http.ResponseWriter, r *http.Request) {
 
 // HandleRefresh handles token refresh requests.
 func (h *Handler) HandleRefresh(w http.ResponseWriter, r *http.Request) {
-	http.Error(w, "not implemented", http.StatusNotImplemented)
+	problem.Write(w, r, http.StatusNotImplemented, problem.ReasonNotImplemented, "not implemented")
+}
+
+// SetLockout makes the callback endpoint refuse client IPs whose callbacks
+// keep failing (bad state, code or ID token). Call before serving; nil (the
+// default) disables the lockout.
+func (h *Handler) SetLockout(lockout *Lockout) {
+	h.lockout = lockout
+}
+
+// StepUpPolicy returns the policy guarding SSH and edge deletion; nil when
+// step-up is disabled.
+func (h *Handler) StepUpPolicy() *StepUpPolicy {
+	return h.providers[0].config.StepUp
+}
+
+// Verifiers returns the OIDC token verifiers of the identity providers, in
+// order, for use by other components (e.g., API proxy).
+func (h *Handler) Verifiers() []*oidc.IDTokenVerifier {
+	verifiers := make([]*oidc.IDTokenVerifier, len(h.providers))
+	for i, p := range h.providers {
+		verifiers[i] = p.verifier
+	}
+	return verifiers
 }
 
-// Verifier returns the OIDC token verifier for use by other components (e.g., API proxy).
-func (h *Handler) Verifier() *oidc.IDTokenVerifier {
-	return h.oidcProvider.Verifier(&oidc.Config{ClientID: h.oidcConfig.ClientID})
+// provider returns the identity provider named idp, by name or issuer URL;
+// empty is the primary. It returns nil for an unknown provider.
+func (h *Handler) provider(idp string) *provider {
+	if idp == "" {
+		return h.providers[0]
+	}
+	for _, p := range h.providers {
+		if p.config.Name == idp || p.config.IssuerURL == idp {
+			return p
+		}
+	}
+	return nil
 }
 
 // RegisterRoutes registers auth routes on the given gorilla/mux router.
@@ -344,10 +455,30 @@ func (h *Handler) RegisterRoutes(router *mux.Router) {
 	router.HandleFunc(apiurl.PathAuthRefresh, h.rateLimiter.middleware(h.HandleRefresh)).Methods("POST")
 }
 
-// seedUser creates or updates a User CRD based on OIDC claims.
-func (h *Handler) seedUser(ctx context.Context, email, name, sub, issuer string) (string, error) {
-	// Hash issuer+sub for a label-safe lookup key.
-	hash := sha256.Sum256([]byte(issuer + "/" + sub))
+// groupsFromClaims returns the string list under claim in the ID token.
+// A missing claim yields an empty, non-nil list: the IdP reports no groups.
+func groupsFromClaims(idToken *oidc.IDToken, claim string) ([]string, error) {
+	var all map[string]json.RawMessage
+	if err := idToken.Claims(&all); err != nil {
+		return nil, err
+	}
+	groups := []string{}
+	raw, ok := all[claim]
+	if !ok || string(raw) == "null" {
+		return groups, nil
+	}
+	if err := json.Unmarshal(raw, &groups); err != nil {
+		return nil, fmt.Errorf("claim %q is not a list of strings: %w", claim, err)
+	}
+	return groups, nil
+}
+
+// seedUser creates or updates a User CRD based on OIDC claims. A non-nil
+// groups replaces status.groups; nil leaves it as is.
+func (h *Handler) seedUser(ctx context.Context, config *OIDCConfig, email, name, sub string, groups []string) (string, error) {
+	// Hash issuer+sub for a label-safe lookup key. The issuer keeps users
+	// of different identity providers apart even when their subs collide.
+	hash := sha256.Sum256([]byte(config.IssuerURL + "/" + sub))
 	subHash := hex.EncodeToString(hash[:])[:63]
 
 	labelSelector := fmt.Sprintf("tenants.kedge.faros.sh/sub=%s", subHash)
@@ -365,7 +496,7 @@ func (h *Handler) seedUser(ctx context.Context, email, name, sub, issuer string)
 		// before the sub→email RBAC switch. Without this, an old User CRD keeps
 		// kedge:<sub> in RBACIdentity forever, which no longer matches the
 		// kcp-extracted username (now email-based) and locks the user out.
-		wantRBAC := fmt.Sprintf("kedge:%s", email)
+		wantRBAC := config.UsernamePrefix() + email
 		if user.Spec.RBACIdentity != wantRBAC || user.Spec.Email != email || user.Spec.Name != name {
 			user.Spec.RBACIdentity = wantRBAC
 			user.Spec.Email = email
@@ -380,6 +511,9 @@ func (h *Handler) seedUser(ctx context.Context, email, name, sub, issuer string)
 		// Update status with last login.
 		user.Status.Active = true
 		user.Status.LastLogin = &now
+		if groups != nil {
+			user.Status.Groups = groups
+		}
 		updated, err := h.kedgeClient.Users().UpdateStatus(ctx, user, metav1.UpdateOptions{})
 		if err != nil {
 			return "", fmt.Errorf("updating user status: %w", err)
@@ -398,10 +532,10 @@ func (h *Handler) seedUser(ctx context.Context, email, name, sub, issuer string)
 		Spec: tenancyv1alpha1.UserSpec{
 			Email:        email,
 			Name:         name,
-			RBACIdentity: fmt.Sprintf("kedge:%s", email),
+			RBACIdentity: config.UsernamePrefix() + email,
 			OIDCProviders: []tenancyv1alpha1.OIDCProvider{
 				{
-					Name:       "dex",
+					Name:       config.Name,
 					ProviderID: sub,
 					Email:      email,
 				},
@@ -420,6 +554,9 @@ func (h *Handler) seedUser(ctx context.Context, email, name, sub, issuer string)
 	// Update status.
 	created.Status.Active = true
 	created.Status.LastLogin = &now
+	if groups != nil {
+		created.Status.Groups = groups
+	}
 	if _, err := h.kedgeClient.Users().UpdateStatus(ctx, created, metav1.UpdateOptions{}); err != nil {
 		h.logger.Error(err, "failed to update new user status", "user", created.Name)
 	}
@@ -472,8 +609,9 @@ func (h *Handler) lookupDefaultCluster(ctx context.Context, userID string) strin
 // generateKubeconfig builds a kubeconfig pointing to the hub using an exec
 // credential plugin (kedge get-token) for automatic OIDC token refresh.
 // When clusterName is set, the server URL includes /clusters/{clusterName}
-// for kcp-syntax compatibility.
-func (h *Handler) generateKubeconfig(userID, clusterName, email string) ([]byte, error) {
+// for kcp-syntax compatibility. The plugin refreshes against idp, the
+// identity provider the user signed in with.
+func (h *Handler) generateKubeconfig(idp *OIDCConfig, userID, clusterName, email string) ([]byte, error) {
 	config := clientcmdapi.NewConfig()
 
 	serverURL := h.hubExternalURL
@@ -491,8 +629,8 @@ func (h *Handler) generateKubeconfig(userID, clusterName, email string) ([]byte,
 	// issuer URL and client ID. The refresh token is stored in the token cache.
 	execArgs := []string{
 		"get-token",
-		"--oidc-issuer-url=" + h.oidcConfig.IssuerURL,
-		"--oidc-client-id=" + h.oidcConfig.ClientID,
+		"--oidc-issuer-url=" + idp.IssuerURL,
+		"--oidc-client-id=" + idp.ClientID,
 	}
 	if h.devMode {
 		execArgs = append(execArgs, "--insecure-skip-tls-verify")
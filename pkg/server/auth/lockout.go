@@ -0,0 +1,315 @@
+// Copyright 2026 The Faros Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/klog/v2"
+
+	"github.com/faroshq/faros-kedge/pkg/hub/metrics"
+)
+
+const (
+	// DefaultLockoutThreshold is the number of failed authentication
+	// attempts within lockoutWindow that locks a client IP or credential
+	// prefix out.
+	DefaultLockoutThreshold = 10
+	// DefaultLockoutDuration is the length of a first lockout.
+	DefaultLockoutDuration = time.Minute
+
+	// lockoutWindow is the period failures are counted over.
+	lockoutWindow = 5 * time.Minute
+	// maxLockoutDuration caps the doubling of repeated lockouts. A key that
+	// has not failed for this long starts again from the base duration.
+	maxLockoutDuration = time.Hour
+	// maxLockoutKeys bounds the client IPs and credential prefixes tracked;
+	// once reached, failures for further keys are counted in the metrics only.
+	maxLockoutKeys = 100000
+	// credentialPrefixLen is how much of an opaque token is counted against.
+	credentialPrefixLen = 8
+
+	// lockoutEndpointCallback labels the metrics of OIDC callback failures.
+	lockoutEndpointCallback = "callback"
+)
+
+var (
+	authFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kedge_hub",
+		Name:      "auth_failures_total",
+		Help:      "Failed authentication attempts, by endpoint.",
+	}, []string{"endpoint"})
+	authLockouts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kedge_hub",
+		Name:      "auth_lockouts_total",
+		Help:      "Lockouts started after repeated authentication failures, by endpoint and scope (ip or token).",
+	}, []string{"endpoint", "scope"})
+	authLockoutRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kedge_hub",
+		Name:      "auth_lockout_rejections_total",
+		Help:      "Authentication attempts refused because their client IP or credential prefix was locked out, by endpoint and scope (ip or token).",
+	}, []string{"endpoint", "scope"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(authFailures, authLockouts, authLockoutRejections)
+}
+
+// Lockout throttles authentication attempts that keep failing. Failures are
+// counted per client IP — the connection's peer address, or, when that peer
+// is a trusted proxy, the nearest X-Forwarded-For hop that is not — and per
+// credential prefix (see credentialPrefix), so guessing at one token from many
+// addresses is throttled as well as guessing many tokens from one address. A
+// key that reaches the threshold within lockoutWindow is refused for the
+// lockout duration, doubled for each repeat up to maxLockoutDuration.
+//
+// A nil *Lockout allows every attempt.
+type Lockout struct {
+	threshold      int
+	duration       time.Duration
+	trustedProxies []*net.IPNet
+	logger         klog.Logger
+	now            func() time.Time
+
+	mu   sync.Mutex
+	keys map[string]*lockoutState
+}
+
+// lockoutState is the failure history of one client IP or credential prefix.
+type lockoutState struct {
+	failures    int       // failures since windowStart
+	windowStart time.Time // start of the counting window
+	last        time.Time // latest failure
+	until       time.Time // refused until then
+	lockouts    int       // lockouts since the key was last clean for maxLockoutDuration
+}
+
+// lockoutKey is one key an attempt is counted against.
+type lockoutKey struct {
+	scope string // "ip" or "token", the metrics label
+	key   string
+}
+
+// NewLockout returns a Lockout that refuses a client IP or credential prefix
+// for duration after threshold failures. X-Forwarded-For is only read from peers within
+// trustedProxies. It returns nil, allowing everything, when threshold or
+// duration is not positive.
+func NewLockout(threshold int, duration time.Duration, trustedProxies []*net.IPNet) *Lockout {
+	if threshold <= 0 || duration <= 0 {
+		return nil
+	}
+	return &Lockout{
+		threshold:      threshold,
+		duration:       duration,
+		trustedProxies: trustedProxies,
+		logger:         klog.Background().WithName("auth-lockout"),
+		now:            time.Now,
+		keys:           make(map[string]*lockoutState),
+	}
+}
+
+// ParseTrustedProxies parses CIDRs, or single addresses, of the proxies whose
+// X-Forwarded-For the lockout believes.
+func ParseTrustedProxies(values []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if !strings.Contains(v, "/") {
+			ip := net.ParseIP(v)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", v)
+			}
+			bits := 8 * len(ip.To16())
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", v, err)
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+// Check reports whether an attempt from r's client with credential (empty if
+// the request carries none) may proceed, and if not, how long until it may.
+// endpoint labels the metrics.
+func (l *Lockout) Check(r *http.Request, credential, endpoint string) (time.Duration, bool) {
+	if l == nil {
+		return 0, true
+	}
+	now := l.now()
+	keys := l.lockoutKeys(r, credential)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, k := range keys {
+		if s := l.keys[k.key]; s != nil && now.Before(s.until) {
+			authLockoutRejections.WithLabelValues(endpoint, k.scope).Inc()
+			return s.until.Sub(now), false
+		}
+	}
+	return 0, true
+}
+
+// Fail records a failed attempt from r's client with credential, locking
+// either out once it reaches the threshold.
+func (l *Lockout) Fail(r *http.Request, credential, endpoint string) {
+	if l == nil {
+		return
+	}
+	authFailures.WithLabelValues(endpoint).Inc()
+	now := l.now()
+	keys := l.lockoutKeys(r, credential)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, k := range keys {
+		s := l.keys[k.key]
+		if s == nil {
+			if len(l.keys) >= maxLockoutKeys {
+				l.prune(now)
+				if len(l.keys) >= maxLockoutKeys {
+					continue
+				}
+			}
+			s = &lockoutState{}
+			l.keys[k.key] = s
+		}
+		if now.Sub(s.last) > maxLockoutDuration {
+			s.lockouts = 0
+		}
+		if now.Sub(s.windowStart) > lockoutWindow {
+			s.failures, s.windowStart = 0, now
+		}
+		s.failures++
+		s.last = now
+		if s.failures < l.threshold || now.Before(s.until) {
+			continue
+		}
+
+		d := l.lockoutDuration(s.lockouts)
+		s.until = now.Add(d)
+		s.lockouts++
+		s.failures, s.windowStart = 0, now
+		authLockouts.WithLabelValues(endpoint, k.scope).Inc()
+		l.logger.Info("authentication locked out after repeated failures", "endpoint", endpoint, "scope", k.scope, "key", k.key, "duration", d.String())
+	}
+}
+
+// lockoutDuration is the length of a key's lockout after it was already
+// locked out n times.
+func (l *Lockout) lockoutDuration(n int) time.Duration {
+	ceiling := max(l.duration, maxLockoutDuration)
+	d := l.duration
+	for i := 0; i < n && d < ceiling; i++ {
+		d *= 2
+	}
+	return min(d, ceiling)
+}
+
+// prune forgets keys that are not locked out and have been clean long enough
+// to start again from the base duration. Called with l.mu held.
+func (l *Lockout) prune(now time.Time) {
+	for k, s := range l.keys {
+		if !now.Before(s.until) && now.Sub(s.last) > maxLockoutDuration {
+			delete(l.keys, k)
+		}
+	}
+}
+
+// lockoutKeys returns the keys an attempt is counted against: the client IP
+// and, when credential has one, its prefix. The prefix is hashed so no token
+// material is kept or logged.
+func (l *Lockout) lockoutKeys(r *http.Request, credential string) []lockoutKey {
+	keys := []lockoutKey{{scope: "ip", key: "ip/" + l.clientIP(r)}}
+	if prefix := credentialPrefix(credential); prefix != "" {
+		sum := sha256.Sum256([]byte(prefix))
+		keys = append(keys, lockoutKey{scope: "token", key: "token/" + hex.EncodeToString(sum[:8])})
+	}
+	return keys
+}
+
+// credentialPrefix is the part of a bearer credential failures are counted
+// against besides the client IP: the start of an opaque token such as a
+// static token, which may be short enough to guess, so guessing at it is
+// throttled however many addresses the attempts come from. The prefix is
+// itself secret, so outsiders cannot aim a lockout at a valid token.
+//
+// Personal access tokens have none: their ID is not secret, so counting
+// against it would let anyone lock a token's owner out, and their secret is
+// too long to guess. Nor do JWTs, which all begin with the same encoded
+// header and are checked by signature.
+func credentialPrefix(credential string) string {
+	if _, ok := ParsePersonalAccessToken(credential); ok {
+		return ""
+	}
+	if credential == "" || strings.Count(credential, ".") == 2 {
+		return ""
+	}
+	if len(credential) > credentialPrefixLen {
+		return credential[:credentialPrefixLen]
+	}
+	return credential
+}
+
+// clientIP is the address failures from r are counted against. Headers a
+// client sets itself are ignored unless the connection comes from a trusted
+// proxy; then X-Forwarded-For is walked from the right, past further trusted
+// proxies, to the first hop none of them vouches for.
+func (l *Lockout) clientIP(r *http.Request) string {
+	peer, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		peer = r.RemoteAddr
+	}
+	if !l.trusted(peer) {
+		return peer
+	}
+	hops := splitAndTrim(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		if !l.trusted(hops[i]) {
+			return hops[i]
+		}
+		peer = hops[i]
+	}
+	return peer
+}
+
+// trusted reports whether addr is one of the trusted proxies.
+func (l *Lockout) trusted(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, n := range l.trustedProxies {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+// Copyright 2026 The Faros Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func requestFrom(ip string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/clusters/x/api", nil)
+	r.RemoteAddr = ip + ":40000"
+	return r
+}
+
+func TestLockout(t *testing.T) {
+	now := time.Now()
+	l := NewLockout(3, time.Minute, nil)
+	l.now = func() time.Time { return now }
+	attacker, other := requestFrom("203.0.113.7"), requestFrom("198.51.100.2")
+
+	for i := range 3 {
+		if _, ok := l.Check(attacker, "", "test"); !ok {
+			t.Fatalf("attempt %d refused before the threshold", i+1)
+		}
+		l.Fail(attacker, "", "test")
+	}
+	if wait, ok := l.Check(attacker, "", "test"); ok || wait != time.Minute {
+		t.Errorf("after 3 failures: Check = %v, %v; want refused for 1m", wait, ok)
+	}
+	if _, ok := l.Check(other, "", "test"); !ok {
+		t.Error("another client was refused")
+	}
+
+	// Guessing at one static token from many addresses locks its prefix
+	// out; a personal access token's public ID is never locked out.
+	for i := range 3 {
+		l.Fail(requestFrom(fmt.Sprintf("192.0.2.%d", i+1)), fmt.Sprintf("s3cr3t-t%d", i), "test")
+		l.Fail(requestFrom(fmt.Sprintf("192.0.2.%d", i+1)), fmt.Sprintf("kedgepat_pat-0123_guess%d", i), "test")
+	}
+	if _, ok := l.Check(other, "s3cr3t-token", "test"); ok {
+		t.Error("locked-out static token prefix was allowed from another client")
+	}
+	if _, ok := l.Check(other, "kedgepat_pat-0123_secret", "test"); !ok {
+		t.Error("a personal access token was locked out by its ID")
+	}
+
+	// Once the lockout expires, the next one lasts twice as long.
+	now = now.Add(time.Minute)
+	if _, ok := l.Check(attacker, "", "test"); !ok {
+		t.Fatal("still refused after the lockout expired")
+	}
+	for range 3 {
+		l.Fail(attacker, "", "test")
+	}
+	if wait, ok := l.Check(attacker, "", "test"); ok || wait != 2*time.Minute {
+		t.Errorf("second lockout: Check = %v, %v; want refused for 2m", wait, ok)
+	}
+
+	// A clean hour resets the backoff.
+	now = now.Add(maxLockoutDuration + time.Minute)
+	for range 3 {
+		l.Fail(attacker, "", "test")
+	}
+	if wait, _ := l.Check(attacker, "", "test"); wait != time.Minute {
+		t.Errorf("lockout after a clean hour = %v, want 1m", wait)
+	}
+}
+
+func TestLockoutFailuresExpire(t *testing.T) {
+	now := time.Now()
+	l := NewLockout(2, time.Minute, nil)
+	l.now = func() time.Time { return now }
+	r := requestFrom("203.0.113.7")
+
+	l.Fail(r, "", "test")
+	now = now.Add(lockoutWindow + time.Second)
+	l.Fail(r, "", "test")
+	if _, ok := l.Check(r, "", "test"); !ok {
+		t.Error("failures further apart than the window locked the client out")
+	}
+}
+
+func TestLockoutForwardedFor(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.0.2.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := NewLockout(2, time.Minute, proxies)
+	forwarded := func(peer, xff string) *http.Request {
+		r := requestFrom(peer)
+		r.Header.Set("X-Forwarded-For", xff)
+		return r
+	}
+
+	// A client connecting directly cannot name a victim's address: the
+	// failures count against its own, and the victim is not locked out.
+	for range 2 {
+		l.Fail(forwarded("203.0.113.7", "198.51.100.2"), "", "test")
+	}
+	if _, ok := l.Check(requestFrom("198.51.100.2"), "", "test"); !ok {
+		t.Error("a forged X-Forwarded-For locked out the address it named")
+	}
+	// Nor dodge the lockout by rotating the header.
+	if _, ok := l.Check(forwarded("203.0.113.7", "198.51.100.99"), "", "test"); ok {
+		t.Error("rotating X-Forwarded-For evaded the lockout")
+	}
+
+	// Behind trusted proxies the nearest untrusted hop is the client; what
+	// the client prepended itself is ignored.
+	for range 2 {
+		l.Fail(forwarded("10.1.2.3", "198.51.100.50, 198.51.100.7, 192.0.2.1"), "", "test")
+	}
+	if _, ok := l.Check(forwarded("10.9.9.9", "198.51.100.7"), "", "test"); ok {
+		t.Error("the forwarded client was not locked out")
+	}
+	if _, ok := l.Check(forwarded("10.9.9.9", "198.51.100.50"), "", "test"); !ok {
+		t.Error("the client-supplied first hop was locked out")
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	nets, err := ParseTrustedProxies([]string{"10.0.0.0/8", " 192.0.2.1 ", "2001:db8::1", ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(nets); got != 3 {
+		t.Fatalf("parsed %d proxies, want 3", got)
+	}
+	if nets[1].String() != "192.0.2.1/32" || nets[2].String() != "2001:db8::1/128" {
+		t.Errorf("single addresses parsed as %v, %v", nets[1], nets[2])
+	}
+	if _, err := ParseTrustedProxies([]string{"not-an-ip"}); err == nil {
+		t.Error("an invalid proxy was accepted")
+	}
+}
+
+func TestNilLockout(t *testing.T) {
+	l := NewLockout(0, time.Minute, nil)
+	if l != nil {
+		t.Fatal("threshold 0 should disable the lockout")
+	}
+	r := requestFrom("203.0.113.7")
+	for range 100 {
+		l.Fail(r, "", "test")
+	}
+	if _, ok := l.Check(r, "", "test"); !ok {
+		t.Error("a nil Lockout refused an attempt")
+	}
+}
+
+func TestCredentialPrefix(t *testing.T) {
+	for _, tc := range []struct{ in, want string }{
+		{"", ""},
+		{"kedgepat_pat-1a2b3c_c2VjcmV0", ""},
+		{"eyJhbGciOiJSUzI1NiJ9.eyJzdWIiOiJ4In0.c2ln", ""},
+		{"short", "short"},
+		{"0123456789abcdef", "01234567"},
+	} {
+		if got := credentialPrefix(tc.in); got != tc.want {
+			t.Errorf("credentialPrefix(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
@@ -16,14 +16,32 @@ limitations under the License.
 
 package auth
 
+import "github.com/faroshq/faros-kedge/pkg/hub/kcp"
+
 // OIDCConfig holds OIDC provider configuration.
 // ClientSecret is intentionally absent: kedge uses PKCE (public client flow)
 // so no client secret is required on the hub side.
 type OIDCConfig struct {
+	// Name identifies the provider: /auth/authorize?idp=<name> signs in
+	// with it, and it is recorded in User.spec.oidcProviders.
+	Name string
+	// Namespace keeps the provider's users and groups apart from other
+	// providers': their RBAC identity is "kedge:<namespace>:<email>" and
+	// their groups "<namespace>:<group>". Empty, for the primary provider,
+	// keeps the "kedge:<email>" of a single-provider hub.
+	Namespace   string
 	IssuerURL   string
 	ClientID    string
 	RedirectURL string
 	Scopes      []string
+	// GroupsClaim names the ID token claim holding the user's IdP groups.
+	// When set, each login records them in User.status.groups for the
+	// directory sync. Empty leaves status.groups untouched.
+	GroupsClaim string
+	// StepUp guards interactive SSH and edge deletion behind a recent
+	// sign-in or a second factor. Nil disables it. The hub applies one
+	// policy to every provider; Handler.StepUpPolicy reads the first's.
+	StepUp *StepUpPolicy
 }
 
 // DefaultOIDCConfig returns default OIDC configuration.
@@ -32,3 +50,26 @@ func DefaultOIDCConfig() *OIDCConfig {
 		Scopes: []string{"openid", "profile", "email", "offline_access"},
 	}
 }
+
+// UsernamePrefix is the prefix of the provider's RBAC identities, and of
+// the usernames and groups kcp derives from its ID tokens.
+func (c *OIDCConfig) UsernamePrefix() string {
+	if c.Namespace == "" {
+		return kcp.DefaultOIDCGroupsPrefix
+	}
+	return kcp.DefaultOIDCGroupsPrefix + c.Namespace + ":"
+}
+
+// qualifyGroups puts the provider's namespace on IdP groups, as recorded in
+// User.status.groups: kcp names the group DefaultOIDCGroupsPrefix plus the
+// qualified name, so group bindings and personal access tokens agree.
+func (c *OIDCConfig) qualifyGroups(groups []string) []string {
+	if c.Namespace == "" || groups == nil {
+		return groups
+	}
+	out := make([]string, len(groups))
+	for i, g := range groups {
+		out[i] = c.Namespace + ":" + g
+	}
+	return out
+}
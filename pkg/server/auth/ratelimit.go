@@ -23,6 +23,8 @@ import (
 
 	"golang.org/x/time/rate"
 	"k8s.io/klog/v2"
+
+	"github.com/faroshq/faros-kedge/pkg/problem"
 )
 
 // rateLimiter implements a per-IP rate limiter for authentication endpoints.
@@ -93,8 +95,7 @@ func (rl *rateLimiter) middleware(next http.HandlerFunc) http.HandlerFunc {
 
 		if !rl.isAllowed(clientIP) {
 			rl.logger.V(2).Info("rate limit exceeded", "clientIP", clientIP, "path", r.URL.Path)
-			w.Header().Set("Retry-After", "60")
-			http.Error(w, "rate limit exceeded - too many requests", http.StatusTooManyRequests)
+			problem.WriteTooManyRequests(w, r, rl.burstDuration/time.Duration(rl.bursts))
 			return
 		}
 
@@ -0,0 +1,99 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	oidc "github.com/coreos/go-oidc"
+
+	"github.com/faroshq/faros-kedge/pkg/problem"
+)
+
+// DefaultStepUpAMR are the RFC 8176 authentication method references accepted
+// as a second factor: multiple factors, a hardware or software key (WebAuthn
+// authenticators report hwk or swk), a one-time password, a smart card.
+var DefaultStepUpAMR = []string{"mfa", "hwk", "swk", "otp", "sc"}
+
+// StepUpPolicy guards sensitive operations — interactive SSH sessions and
+// edge deletion — behind a recent sign-in or a second factor. An ID token
+// satisfies it when its auth_time is within MaxAge, or when its amr claim
+// names one of AMR. Refreshing a token keeps its auth_time, so a stolen
+// refresh token cannot satisfy the policy; only signing in again can.
+//
+// The zero value, and a nil policy, are disabled.
+type StepUpPolicy struct {
+	// MaxAge is how long after signing in a user may perform guarded
+	// operations. Zero disables step-up.
+	MaxAge time.Duration
+	// AMR are the amr values that satisfy the policy regardless of age.
+	AMR []string
+}
+
+// Enabled reports whether the policy guards anything.
+func (p *StepUpPolicy) Enabled() bool {
+	return p != nil && p.MaxAge > 0
+}
+
+// stepUpClaims are the ID token claims the policy reads. auth_time is a
+// NumericDate, which may carry a fraction.
+type stepUpClaims struct {
+	AuthTime float64  `json:"auth_time"`
+	AMR      []string `json:"amr"`
+}
+
+// Satisfied reports whether idToken meets the policy at now. A disabled
+// policy is always satisfied.
+func (p *StepUpPolicy) Satisfied(idToken *oidc.IDToken, now time.Time) bool {
+	if !p.Enabled() {
+		return true
+	}
+	var claims stepUpClaims
+	if err := idToken.Claims(&claims); err != nil {
+		return false
+	}
+	return p.satisfiedBy(claims, now)
+}
+
+func (p *StepUpPolicy) satisfiedBy(claims stepUpClaims, now time.Time) bool {
+	for _, method := range claims.AMR {
+		if slices.Contains(p.AMR, method) {
+			return true
+		}
+	}
+	if claims.AuthTime <= 0 {
+		// The IdP does not report when the user signed in.
+		return false
+	}
+	authTime := time.Unix(int64(claims.AuthTime), 0)
+	return now.Sub(authTime) <= p.MaxAge
+}
+
+// WriteStepUpRequired refuses a request that needs step-up with 401 and the
+// RFC 9470 challenge, so clients know to re-authenticate with max_age rather
+// than retry or refresh.
+func (p *StepUpPolicy) WriteStepUpRequired(w http.ResponseWriter, r *http.Request, operation string) {
+	maxAge := int(p.MaxAge / time.Second)
+	w.Header().Set("WWW-Authenticate", fmt.Sprintf(
+		`Bearer error="insufficient_user_authentication", error_description="%s requires a recent sign-in", max_age=%d`,
+		operation, maxAge))
+	problem.Write(w, r, http.StatusUnauthorized, problem.ReasonStepUpRequired,
+		fmt.Sprintf("%s requires signing in within the last %s or a second factor — run 'kedge login --step-up'", operation, p.MaxAge))
+}
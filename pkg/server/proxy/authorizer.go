@@ -106,6 +106,30 @@ func (a *clusterAuthorizer) authorize(ctx context.Context, userName, clusterID s
 	return false
 }
 
+// reachable returns the clusterIDs userName may reach, in input order. The
+// membership index is read once and the caller's workspaces are resolved at
+// most once, so filtering many clusters costs about as much as authorizing one.
+func (a *clusterAuthorizer) reachable(ctx context.Context, userName string, clusterIDs []string) []string {
+	idx, err := a.members(ctx, userName)
+	if err != nil || idx == nil {
+		return nil
+	}
+	populated := false
+	var out []string
+	for _, id := range clusterIDs {
+		owner, ok := a.reverseGet(id)
+		if !ok && !populated {
+			a.populateForUser(ctx, idx)
+			populated = true
+			owner, ok = a.reverseGet(id)
+		}
+		if ok && membershipCovers(idx, owner) {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
 // membershipCovers reports whether the index grants access to (owner.org,
 // owner.ws): a workspace-scope entry for that workspace, or an org-scope entry
 // (empty WorkspaceUUID) for its org.
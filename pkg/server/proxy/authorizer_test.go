@@ -186,9 +186,13 @@ func TestAuthorizeKCPPath(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			gotPath, gotStatus, gotBody := p.authorizeKCPPath(context.Background(), "user", tc.urlPath)
+			gotPath, denial := p.authorizeKCPPath(context.Background(), "user", tc.urlPath)
+			gotStatus := 0
+			if denial != nil {
+				gotStatus = denial.Status
+			}
 			if gotStatus != tc.wantStatus {
-				t.Fatalf("status = %d (body %q), want %d", gotStatus, gotBody, tc.wantStatus)
+				t.Fatalf("status = %d (denial %v), want %d", gotStatus, denial, tc.wantStatus)
 			}
 			if tc.wantStatus == 0 && gotPath != tc.wantPath {
 				t.Errorf("path = %q, want %q", gotPath, tc.wantPath)
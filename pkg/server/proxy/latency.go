@@ -0,0 +1,346 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/faroshq/faros-kedge/pkg/hub/metrics"
+	"github.com/faroshq/faros-kedge/pkg/problem"
+)
+
+// LatencyPath is the URL path ServeLatency is mounted at.
+const LatencyPath = "/services/latency"
+
+const (
+	// latencySamples is how many recent request durations are kept per
+	// workspace for the report's percentiles.
+	latencySamples = 256
+	// maxLatencyWorkspaces bounds the workspaces tracked; requests for
+	// further workspaces still reach kcp but are not attributed.
+	maxLatencyWorkspaces = 4096
+	// maxLatencyResources bounds the per-workspace resource breakdown;
+	// further verb/resource pairs are summed under resource "other".
+	maxLatencyResources = 64
+)
+
+// kcpRequestDuration is the time kcp took to answer proxied requests.
+var kcpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "kedge_hub",
+	Name:      "kcp_request_duration_seconds",
+	Help:      "Time kcp took to answer requests proxied by the hub, until the response headers arrived, by workspace (logical cluster) and verb.",
+	Buckets:   prometheus.ExponentialBuckets(0.005, 2, 12),
+}, []string{"workspace", "verb"})
+
+func init() {
+	metrics.Registry.MustRegister(kcpRequestDuration)
+}
+
+// latencyTransport measures the time kcp takes to answer each request sent
+// through it and attributes it to the request's workspace. Time is taken
+// until the response headers arrive, so a long watch or a slow client reading
+// the body is not charged to kcp.
+type latencyTransport struct {
+	next    http.RoundTripper
+	tracker *latencyTracker
+}
+
+func (t *latencyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	// A 401 is kcp rejecting the credentials; the cluster in the path (taken
+	// from an unverified ServiceAccount token, say) may not exist, so do not
+	// attribute it.
+	if err == nil && resp.StatusCode == http.StatusUnauthorized {
+		return resp, err
+	}
+	failed := err != nil || resp.StatusCode >= http.StatusInternalServerError
+	t.tracker.observe(req.Method, req.URL, time.Since(start), failed)
+	return resp, err
+}
+
+// latencyTracker aggregates kcp request latency per workspace for the
+// /services/latency report. The Prometheus histogram carries the same
+// observations for every workspace.
+type latencyTracker struct {
+	mu         sync.Mutex
+	workspaces map[string]*workspaceLatency
+	total      time.Duration
+}
+
+type workspaceLatency struct {
+	requests  int64
+	errors    int64
+	total     time.Duration
+	max       time.Duration
+	samples   []time.Duration // ring of the latest latencySamples durations
+	next      int
+	resources map[resourceKey]*resourceLatency
+}
+
+type resourceKey struct {
+	verb     string
+	resource string
+}
+
+type resourceLatency struct {
+	requests int64
+	total    time.Duration
+}
+
+func newLatencyTracker() *latencyTracker {
+	return &latencyTracker{workspaces: map[string]*workspaceLatency{}}
+}
+
+// observe records one kcp request for the workspace addressed by u.
+func (t *latencyTracker) observe(method string, u *url.URL, d time.Duration, failed bool) {
+	workspace, rest := latencyWorkspace(u.Path)
+	if workspace == "" {
+		return
+	}
+	verb := requestVerb(method, u)
+	key := resourceKey{verb: verb, resource: requestResource(rest)}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ws, ok := t.workspaces[workspace]
+	if !ok {
+		if len(t.workspaces) >= maxLatencyWorkspaces {
+			return
+		}
+		ws = &workspaceLatency{resources: map[resourceKey]*resourceLatency{}}
+		t.workspaces[workspace] = ws
+	}
+	kcpRequestDuration.WithLabelValues(workspace, verb).Observe(d.Seconds())
+
+	t.total += d
+	ws.requests++
+	if failed {
+		ws.errors++
+	}
+	ws.total += d
+	ws.max = max(ws.max, d)
+	if len(ws.samples) < latencySamples {
+		ws.samples = append(ws.samples, d)
+	} else {
+		ws.samples[ws.next] = d
+		ws.next = (ws.next + 1) % latencySamples
+	}
+	res, ok := ws.resources[key]
+	if !ok {
+		if len(ws.resources) >= maxLatencyResources {
+			key.resource = "other"
+			res = ws.resources[key]
+		}
+		if res == nil {
+			res = &resourceLatency{}
+			ws.resources[key] = res
+		}
+	}
+	res.requests++
+	res.total += d
+}
+
+// workspaceIDs returns the tracked workspaces, sorted.
+func (t *latencyTracker) workspaceIDs() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ids := make([]string, 0, len(t.workspaces))
+	for id := range t.workspaces {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// LatencyReport is the /services/latency response.
+type LatencyReport struct {
+	Workspaces []WorkspaceLatency `json:"workspaces"`
+}
+
+// WorkspaceLatency is the kcp time spent on one workspace's requests since
+// the hub started. Share is its fraction of all kcp time the hub has
+// attributed, which tells a noisy tenant apart from a kcp that is slow for
+// everyone. The percentiles cover the latest requests only.
+type WorkspaceLatency struct {
+	Workspace  string            `json:"workspace"`
+	Requests   int64             `json:"requests"`
+	Errors     int64             `json:"errors"`
+	KCPSeconds float64           `json:"kcpSeconds"`
+	Share      float64           `json:"share"`
+	P50Ms      float64           `json:"p50Ms"`
+	P95Ms      float64           `json:"p95Ms"`
+	P99Ms      float64           `json:"p99Ms"`
+	MaxMs      float64           `json:"maxMs"`
+	Resources  []ResourceLatency `json:"resources"`
+}
+
+// ResourceLatency is the kcp time spent on one verb and resource in a
+// workspace, so the requests behind a hotspot can be found.
+type ResourceLatency struct {
+	Verb       string  `json:"verb"`
+	Resource   string  `json:"resource"`
+	Requests   int64   `json:"requests"`
+	KCPSeconds float64 `json:"kcpSeconds"`
+}
+
+// report summarizes the given workspaces, in order. Resources are sorted by
+// kcp time, highest first.
+func (t *latencyTracker) report(ids []string) LatencyReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := LatencyReport{Workspaces: []WorkspaceLatency{}}
+	for _, id := range ids {
+		ws, ok := t.workspaces[id]
+		if !ok {
+			continue
+		}
+		sorted := slices.Clone(ws.samples)
+		slices.Sort(sorted)
+		wl := WorkspaceLatency{
+			Workspace:  id,
+			Requests:   ws.requests,
+			Errors:     ws.errors,
+			KCPSeconds: ws.total.Seconds(),
+			P50Ms:      milliseconds(percentile(sorted, 0.50)),
+			P95Ms:      milliseconds(percentile(sorted, 0.95)),
+			P99Ms:      milliseconds(percentile(sorted, 0.99)),
+			MaxMs:      milliseconds(ws.max),
+			Resources:  make([]ResourceLatency, 0, len(ws.resources)),
+		}
+		if t.total > 0 {
+			wl.Share = float64(ws.total) / float64(t.total)
+		}
+		for key, res := range ws.resources {
+			wl.Resources = append(wl.Resources, ResourceLatency{
+				Verb:       key.verb,
+				Resource:   key.resource,
+				Requests:   res.requests,
+				KCPSeconds: res.total.Seconds(),
+			})
+		}
+		slices.SortFunc(wl.Resources, func(a, b ResourceLatency) int {
+			if a.KCPSeconds != b.KCPSeconds {
+				if a.KCPSeconds > b.KCPSeconds {
+					return -1
+				}
+				return 1
+			}
+			if c := strings.Compare(a.Resource, b.Resource); c != 0 {
+				return c
+			}
+			return strings.Compare(a.Verb, b.Verb)
+		})
+		out.Workspaces = append(out.Workspaces, wl)
+	}
+	return out
+}
+
+// ServeLatency serves the kcp latency report for every workspace the caller
+// is a member of and the hub has proxied requests for.
+func (p *KCPProxy) ServeLatency(w http.ResponseWriter, r *http.Request) {
+	user, err := p.IdentifyUser(r)
+	if err != nil || user == "" {
+		problem.Write(w, r, http.StatusUnauthorized, problem.ReasonUnauthorized, "unauthorized")
+		return
+	}
+	p.writeLatencyReport(w, r, user)
+}
+
+func (p *KCPProxy) writeLatencyReport(w http.ResponseWriter, r *http.Request, user string) {
+	ids := p.authorizer.reachable(r.Context(), user, p.latency.workspaceIDs())
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(p.latency.report(ids))
+}
+
+// latencyWorkspace splits a /clusters/{id}[:{edge}]/... kcp path into the
+// workspace the request is attributed to (edges count towards their parent)
+// and the API path after the cluster segment.
+func latencyWorkspace(kcpPath string) (string, string) {
+	seg := extractClusterPathFromKCPPath(kcpPath)
+	if seg == "" {
+		return "", ""
+	}
+	rest := strings.TrimPrefix(kcpPath, "/clusters/"+seg)
+	workspace, _, _ := strings.Cut(seg, ":")
+	return workspace, rest
+}
+
+// requestVerb is the Kubernetes-style verb of a request: "watch" for watch
+// requests, otherwise the lower-cased HTTP method.
+func requestVerb(method string, u *url.URL) string {
+	if method == http.MethodGet {
+		if w := u.Query().Get("watch"); w == "true" || w == "1" {
+			return "watch"
+		}
+	}
+	return strings.ToLower(method)
+}
+
+// requestResource names the resource an API path addresses, as "pods" or
+// "kubernetesclusters.edges.kedge.faros.sh". Discovery paths are
+// "discovery"; anything else, such as /version, is "other".
+func requestResource(apiPath string) string {
+	parts := strings.Split(strings.Trim(apiPath, "/"), "/")
+	var group string
+	switch {
+	case len(parts) >= 1 && parts[0] == "api":
+		parts = parts[min(2, len(parts)):]
+	case len(parts) >= 1 && parts[0] == "apis":
+		if len(parts) < 4 {
+			return "discovery"
+		}
+		group = parts[1]
+		parts = parts[3:]
+	default:
+		return "other"
+	}
+	if len(parts) == 0 || parts[0] == "" {
+		return "discovery"
+	}
+	resource := parts[0]
+	if resource == "namespaces" && len(parts) >= 3 {
+		resource = parts[2]
+	}
+	if group != "" {
+		resource += "." + group
+	}
+	return resource
+}
+
+// percentile returns the q-quantile of sorted by the nearest-rank method.
+func percentile(sorted []time.Duration, q float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(math.Ceil(q*float64(len(sorted)))) - 1
+	return sorted[max(i, 0)]
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	tenancyv1alpha1 "github.com/faroshq/faros-kedge/apis/tenancy/v1alpha1"
+)
+
+func TestRequestResource(t *testing.T) {
+	tests := map[string]string{
+		"/api/v1/pods":                                           "pods",
+		"/api/v1/namespaces/default/pods/p1":                     "pods",
+		"/api/v1/namespaces/default":                             "namespaces",
+		"/api/v1/namespaces":                                     "namespaces",
+		"/apis/edges.kedge.faros.sh/v1alpha1/kubernetesclusters": "kubernetesclusters.edges.kedge.faros.sh",
+		"/apis/edges.kedge.faros.sh/v1alpha1/namespaces/ns/placements/p1/status": "placements.edges.kedge.faros.sh",
+		"/apis/edges.kedge.faros.sh/v1alpha1":                                    "discovery",
+		"/api":                                                                   "discovery",
+		"/api/v1":                                                                "discovery",
+		"/version":                                                               "other",
+	}
+	for path, want := range tests {
+		if got := requestResource(path); got != want {
+			t.Errorf("requestResource(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestLatencyWorkspace(t *testing.T) {
+	tests := []struct {
+		path, workspace, rest string
+	}{
+		{"/clusters/abc/api/v1/pods", "abc", "/api/v1/pods"},
+		{"/clusters/abc:edge-1/api/v1/pods", "abc", "/api/v1/pods"},
+		{"/clusters/abc", "abc", ""},
+		{"/api/v1/pods", "", ""},
+	}
+	for _, tt := range tests {
+		ws, rest := latencyWorkspace(tt.path)
+		if ws != tt.workspace || rest != tt.rest {
+			t.Errorf("latencyWorkspace(%q) = (%q, %q), want (%q, %q)", tt.path, ws, rest, tt.workspace, tt.rest)
+		}
+	}
+}
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+type statusTransport int
+
+func (s statusTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: int(s), Body: http.NoBody, Request: req}, nil
+}
+
+func TestLatencyTransportAttributesByWorkspace(t *testing.T) {
+	tracker := newLatencyTracker()
+	send := func(status int, method, target string) {
+		t.Helper()
+		req := httptest.NewRequest(method, "https://kcp"+target, nil)
+		tr := &latencyTransport{next: statusTransport(status), tracker: tracker}
+		if _, err := tr.RoundTrip(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	send(http.StatusOK, http.MethodGet, "/clusters/a/api/v1/pods")
+	send(http.StatusOK, http.MethodGet, "/clusters/a:edge/api/v1/pods?watch=true")
+	send(http.StatusServiceUnavailable, http.MethodPost, "/clusters/b/api/v1/namespaces/x/configmaps")
+	send(http.StatusUnauthorized, http.MethodGet, "/clusters/forged/api/v1/pods")
+
+	if got, want := tracker.workspaceIDs(), []string{"a", "b"}; len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("workspaces = %v, want %v", got, want)
+	}
+	report := tracker.report([]string{"a", "b", "unknown"})
+	if len(report.Workspaces) != 2 {
+		t.Fatalf("report has %d workspaces, want 2", len(report.Workspaces))
+	}
+	a, b := report.Workspaces[0], report.Workspaces[1]
+	if a.Requests != 2 || a.Errors != 0 || len(a.Resources) != 2 {
+		t.Errorf("workspace a = %+v, want 2 requests over get and watch pods", a)
+	}
+	if b.Requests != 1 || b.Errors != 1 || b.Resources[0].Verb != "post" || b.Resources[0].Resource != "configmaps" {
+		t.Errorf("workspace b = %+v, want one failed configmaps post", b)
+	}
+}
+
+func TestLatencyTrackerReport(t *testing.T) {
+	tracker := newLatencyTracker()
+	pods := mustParseURL(t, "/clusters/a/api/v1/pods")
+	for i := 1; i <= 100; i++ {
+		tracker.observe(http.MethodGet, pods, time.Duration(i)*time.Millisecond, false)
+	}
+	tracker.observe(http.MethodGet, mustParseURL(t, "/clusters/b/api/v1/pods"), 5050*time.Millisecond, false)
+
+	report := tracker.report([]string{"a", "b"})
+	a := report.Workspaces[0]
+	if a.P50Ms != 50 || a.P95Ms != 95 || a.P99Ms != 99 || a.MaxMs != 100 {
+		t.Errorf("percentiles = p50 %v p95 %v p99 %v max %v, want 50/95/99/100", a.P50Ms, a.P95Ms, a.P99Ms, a.MaxMs)
+	}
+	if a.KCPSeconds != 5.05 || a.Share != 0.5 || report.Workspaces[1].Share != 0.5 {
+		t.Errorf("a = %v s (share %v), b share %v; want 5.05 s and an even split", a.KCPSeconds, a.Share, report.Workspaces[1].Share)
+	}
+}
+
+func TestLatencyTrackerKeepsLatestSamples(t *testing.T) {
+	tracker := newLatencyTracker()
+	u := mustParseURL(t, "/clusters/a/api/v1/pods")
+	for range latencySamples {
+		tracker.observe(http.MethodGet, u, time.Second, false)
+	}
+	for range latencySamples {
+		tracker.observe(http.MethodGet, u, time.Millisecond, false)
+	}
+	a := tracker.report([]string{"a"}).Workspaces[0]
+	if a.P99Ms != 1 || a.MaxMs != 1000 {
+		t.Errorf("p99 = %v ms, max = %v ms; want 1 ms over the latest samples and 1000 ms overall", a.P99Ms, a.MaxMs)
+	}
+}
+
+func TestWriteLatencyReportOnlyCallerWorkspaces(t *testing.T) {
+	p := &KCPProxy{
+		authorizer: fakeAuthorizer(
+			[]tenancyv1alpha1.MembershipIndexEntry{wsEntry("o1", "w1")},
+			map[string]string{"o1/w1": "cidA", "o2/w2": "cidB"},
+			nil,
+		),
+		latency: newLatencyTracker(),
+	}
+	p.latency.observe(http.MethodGet, mustParseURL(t, "/clusters/cidA/api/v1/pods"), time.Millisecond, false)
+	p.latency.observe(http.MethodGet, mustParseURL(t, "/clusters/cidB/api/v1/pods"), time.Millisecond, false)
+
+	rec := httptest.NewRecorder()
+	p.writeLatencyReport(rec, httptest.NewRequest(http.MethodGet, LatencyPath, nil), "alice")
+	var report LatencyReport
+	if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Workspaces) != 1 || report.Workspaces[0].Workspace != "cidA" {
+		t.Errorf("report = %+v, want only cidA", report)
+	}
+}
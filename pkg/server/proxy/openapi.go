@@ -0,0 +1,477 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	configkcp "github.com/faroshq/faros-kedge/config/kcp"
+	"github.com/faroshq/faros-kedge/pkg/problem"
+)
+
+// openAPIV3Segment is the kcp (and kube-apiserver) OpenAPI v3 discovery root,
+// relative to a /clusters/{cluster} prefix.
+const openAPIV3Segment = "/openapi/v3"
+
+// coreAPIExportFile is the merged APIExport whose resources tenants bind. Its
+// schemas are what the proxy guarantees to be present in every workspace's
+// OpenAPI document, bound or not, so the portal can render forms for them
+// before the first APIBinding lands.
+const coreAPIExportFile = "apiexport-core.faros.sh.yaml"
+
+// defaultOpenAPICacheTTL bounds how long an upstream OpenAPI document is
+// reused. kcp regenerates the per-workspace spec whenever an APIBinding
+// changes, so keep it short; the win is absorbing the portal's burst of
+// identical fetches on page load.
+const defaultOpenAPICacheTTL = 30 * time.Second
+
+// defaultOpenAPICacheSize bounds how many documents the aggregator keeps. The
+// key space is every workspace times every group-version, so without a bound
+// a multi-tenant hub would grow the cache without limit.
+const defaultOpenAPICacheSize = 1024
+
+// openAPIAggregator serves /clusters/{cluster}/openapi/v3[/...] by fetching
+// kcp's per-workspace document with the caller's credentials and merging the
+// kedge APIExport schemas into it. Only successful upstream responses are
+// cached; auth failures and upstream errors pass through unchanged.
+//
+// The cache holds at most maxEntries documents. Storing into a full cache
+// first drops expired entries and then, if none expired, the entry closest to
+// expiry.
+type openAPIAggregator struct {
+	target    *url.URL
+	transport http.RoundTripper
+	logger    klog.Logger
+
+	// kedge maps "apis/{group}/{version}" to the synthesized OpenAPI v3
+	// document for that group-version, built once from the embedded schemas.
+	kedge map[string]map[string]interface{}
+
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+	mu         sync.Mutex
+	cache      map[string]openAPICacheEntry
+}
+
+type openAPICacheEntry struct {
+	body []byte
+	exp  time.Time
+}
+
+func newOpenAPIAggregator(target *url.URL, transport http.RoundTripper, logger klog.Logger) (*openAPIAggregator, error) {
+	docs, err := kedgeOpenAPIDocuments(configkcp.ProvidersFS)
+	if err != nil {
+		return nil, fmt.Errorf("building kedge OpenAPI documents: %w", err)
+	}
+	return &openAPIAggregator{
+		target:     target,
+		transport:  transport,
+		logger:     logger,
+		kedge:      docs,
+		ttl:        defaultOpenAPICacheTTL,
+		maxEntries: defaultOpenAPICacheSize,
+		now:        time.Now,
+		cache:      map[string]openAPICacheEntry{},
+	}, nil
+}
+
+// isOpenAPIV3Path reports whether kcpPath addresses a workspace's OpenAPI v3
+// index (/clusters/{c}/openapi/v3) or one of its group-version documents.
+func isOpenAPIV3Path(kcpPath string) bool {
+	cluster := extractClusterPathFromKCPPath(kcpPath)
+	if cluster == "" {
+		return false
+	}
+	rest := strings.TrimPrefix(kcpPath, "/clusters/"+cluster)
+	return rest == openAPIV3Segment || strings.HasPrefix(rest, openAPIV3Segment+"/")
+}
+
+// serve answers an OpenAPI v3 request for kcpPath. The caller has already
+// authorized the request against the cluster in kcpPath.
+func (a *openAPIAggregator) serve(w http.ResponseWriter, r *http.Request, kcpPath string) {
+	a.serveDocument(w, r, kcpPath, true)
+}
+
+// serveUncached answers an OpenAPI v3 request for a caller the hub has not
+// authorized itself (a kcp ServiceAccount token, which only kcp verifies):
+// every request goes upstream so kcp decides, and nothing is cached for it.
+func (a *openAPIAggregator) serveUncached(w http.ResponseWriter, r *http.Request, kcpPath string) {
+	a.serveDocument(w, r, kcpPath, false)
+}
+
+func (a *openAPIAggregator) serveDocument(w http.ResponseWriter, r *http.Request, kcpPath string, useCache bool) {
+	cluster := extractClusterPathFromKCPPath(kcpPath)
+	gv := strings.TrimPrefix(strings.TrimPrefix(kcpPath, "/clusters/"+cluster+openAPIV3Segment), "/")
+
+	key := kcpPath + "?" + r.URL.RawQuery
+	if useCache {
+		if body, ok := a.cached(key); ok {
+			writeOpenAPI(w, body)
+			return
+		}
+	}
+
+	status, upstream, err := a.fetch(r, kcpPath)
+	if err != nil {
+		a.logger.Error(err, "openapi upstream error", "path", kcpPath)
+		problem.Write(w, r, http.StatusBadGateway, problem.ReasonServiceUnavailable, "upstream error")
+		return
+	}
+
+	var merged []byte
+	switch {
+	case gv == "" && status == http.StatusOK:
+		merged, err = a.mergeIndex(upstream, cluster)
+	case gv != "" && a.kedge[gv] != nil && status == http.StatusOK:
+		merged, err = mergeGroupVersion(upstream, a.kedge[gv])
+	case gv != "" && a.kedge[gv] != nil && status == http.StatusNotFound:
+		// The workspace has not bound the kedge export (yet) — serve the
+		// embedded schema so clients can still introspect it.
+		merged, err = json.Marshal(a.kedge[gv])
+	case status == http.StatusOK:
+		merged = upstream
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write(upstream)
+		return
+	}
+	if err != nil {
+		a.logger.Error(err, "merging openapi document", "path", kcpPath)
+		merged = upstream
+	}
+
+	if useCache {
+		a.store(key, merged)
+	}
+	writeOpenAPI(w, merged)
+}
+
+// fetch issues the GET against kcp with the caller's Authorization header so
+// kcp enforces the caller's access to the workspace's discovery.
+func (a *openAPIAggregator) fetch(r *http.Request, kcpPath string) (int, []byte, error) {
+	u := *a.target
+	u.Path = kcpPath
+	u.RawQuery = r.URL.RawQuery
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, u.String(), nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	resp, err := a.transport.RoundTrip(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, body, nil
+}
+
+// mergeIndex adds an entry for every kedge group-version missing from kcp's
+// OpenAPI v3 index. Entries kcp already serves are left untouched.
+func (a *openAPIAggregator) mergeIndex(upstream []byte, cluster string) ([]byte, error) {
+	var index map[string]interface{}
+	if err := json.Unmarshal(upstream, &index); err != nil {
+		return nil, err
+	}
+	paths, _ := index["paths"].(map[string]interface{})
+	if paths == nil {
+		paths = map[string]interface{}{}
+	}
+	for gv, doc := range a.kedge {
+		if _, ok := paths[gv]; ok {
+			continue
+		}
+		raw, err := json.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(raw)
+		paths[gv] = map[string]interface{}{
+			"serverRelativeURL": "/clusters/" + cluster + openAPIV3Segment + "/" + gv + "?hash=" + strings.ToUpper(hex.EncodeToString(sum[:])),
+		}
+	}
+	index["paths"] = paths
+	return json.Marshal(index)
+}
+
+// mergeGroupVersion adds the kedge paths and component schemas that kcp's
+// group-version document does not already define.
+func mergeGroupVersion(upstream []byte, kedgeDoc map[string]interface{}) ([]byte, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(upstream, &doc); err != nil {
+		return nil, err
+	}
+	paths, _ := doc["paths"].(map[string]interface{})
+	if paths == nil {
+		paths = map[string]interface{}{}
+	}
+	for p, v := range kedgeDoc["paths"].(map[string]interface{}) {
+		if _, ok := paths[p]; !ok {
+			paths[p] = v
+		}
+	}
+	doc["paths"] = paths
+
+	components, _ := doc["components"].(map[string]interface{})
+	if components == nil {
+		components = map[string]interface{}{}
+	}
+	schemas, _ := components["schemas"].(map[string]interface{})
+	if schemas == nil {
+		schemas = map[string]interface{}{}
+	}
+	kedgeSchemas := kedgeDoc["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	for name, s := range kedgeSchemas {
+		if _, ok := schemas[name]; !ok {
+			schemas[name] = s
+		}
+	}
+	components["schemas"] = schemas
+	doc["components"] = components
+	return json.Marshal(doc)
+}
+
+func (a *openAPIAggregator) cached(key string) ([]byte, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	e, ok := a.cache[key]
+	if !ok || !a.now().Before(e.exp) {
+		delete(a.cache, key)
+		return nil, false
+	}
+	return e.body, true
+}
+
+func (a *openAPIAggregator) store(key string, body []byte) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := a.now()
+	if _, ok := a.cache[key]; !ok && len(a.cache) >= a.maxEntries {
+		a.evictLocked(now)
+	}
+	a.cache[key] = openAPICacheEntry{body: body, exp: now.Add(a.ttl)}
+}
+
+// evictLocked makes room for one entry: it drops every expired entry, or the
+// one closest to expiry when none has expired. a.mu must be held.
+func (a *openAPIAggregator) evictLocked(now time.Time) {
+	var oldest string
+	var oldestExp time.Time
+	for k, e := range a.cache {
+		if !now.Before(e.exp) {
+			delete(a.cache, k)
+			continue
+		}
+		if oldest == "" || e.exp.Before(oldestExp) {
+			oldest, oldestExp = k, e.exp
+		}
+	}
+	if len(a.cache) >= a.maxEntries {
+		delete(a.cache, oldest)
+	}
+}
+
+func writeOpenAPI(w http.ResponseWriter, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// kedgeOpenAPIDocuments builds one OpenAPI v3 document per group-version from
+// the APIResourceSchemas referenced by the core.faros.sh APIExport.
+func kedgeOpenAPIDocuments(fsys fs.FS) (map[string]map[string]interface{}, error) {
+	raw, err := fs.ReadFile(fsys, coreAPIExportFile)
+	if err != nil {
+		return nil, err
+	}
+	var export struct {
+		Spec struct {
+			Resources []struct {
+				Schema string `json:"schema"`
+			} `json:"resources"`
+		} `json:"spec"`
+	}
+	if err := yaml.Unmarshal(raw, &export); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", coreAPIExportFile, err)
+	}
+	wanted := map[string]bool{}
+	for _, res := range export.Spec.Resources {
+		wanted[res.Schema] = true
+	}
+
+	files, err := fs.Glob(fsys, "apiresourceschema-*.yaml")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	docs := map[string]map[string]interface{}{}
+	for _, f := range files {
+		raw, err := fs.ReadFile(fsys, f)
+		if err != nil {
+			return nil, err
+		}
+		var ars apiResourceSchema
+		if err := yaml.Unmarshal(raw, &ars); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", f, err)
+		}
+		if !wanted[ars.Metadata.Name] {
+			continue
+		}
+		for _, v := range ars.Spec.Versions {
+			if !v.Served {
+				continue
+			}
+			gv := "apis/" + ars.Spec.Group + "/" + v.Name
+			doc, ok := docs[gv]
+			if !ok {
+				doc = map[string]interface{}{
+					"openapi":    "3.0.0",
+					"info":       map[string]interface{}{"title": "Kubernetes", "version": "unversioned"},
+					"paths":      map[string]interface{}{},
+					"components": map[string]interface{}{"schemas": map[string]interface{}{}},
+				}
+				docs[gv] = doc
+			}
+			addResourceToDocument(doc, ars, v)
+		}
+	}
+	return docs, nil
+}
+
+// apiResourceSchema is the subset of apis.kcp.io/v1alpha1 APIResourceSchema
+// the aggregator reads.
+type apiResourceSchema struct {
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Spec struct {
+		Group string `json:"group"`
+		Names struct {
+			Kind     string `json:"kind"`
+			ListKind string `json:"listKind"`
+			Plural   string `json:"plural"`
+		} `json:"names"`
+		Scope    string                     `json:"scope"`
+		Versions []apiResourceSchemaVersion `json:"versions"`
+	} `json:"spec"`
+}
+
+type apiResourceSchemaVersion struct {
+	Name   string                 `json:"name"`
+	Served bool                   `json:"served"`
+	Schema map[string]interface{} `json:"schema"`
+}
+
+// addResourceToDocument adds the kind + list schemas and the read paths for
+// one resource version, using the same reversed-group component naming the
+// kube-apiserver uses for CRDs (e.g. sh.faros.kedge.v1alpha1.MCPServer).
+func addResourceToDocument(doc map[string]interface{}, ars apiResourceSchema, v apiResourceSchemaVersion) {
+	group := ars.Spec.Group
+	kind := ars.Spec.Names.Kind
+	listKind := ars.Spec.Names.ListKind
+	if listKind == "" {
+		listKind = kind + "List"
+	}
+	prefix := reverseGroup(group) + "." + v.Name + "."
+	kindRef := "#/components/schemas/" + prefix + kind
+	listRef := "#/components/schemas/" + prefix + listKind
+
+	schema := map[string]interface{}{}
+	for k, val := range v.Schema {
+		schema[k] = val
+	}
+	schema["x-kubernetes-group-version-kind"] = []interface{}{
+		map[string]interface{}{"group": group, "version": v.Name, "kind": kind},
+	}
+	list := map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"items"},
+		"properties": map[string]interface{}{
+			"apiVersion": map[string]interface{}{"type": "string"},
+			"kind":       map[string]interface{}{"type": "string"},
+			"metadata":   map[string]interface{}{"type": "object"},
+			"items": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"$ref": kindRef},
+			},
+		},
+		"x-kubernetes-group-version-kind": []interface{}{
+			map[string]interface{}{"group": group, "version": v.Name, "kind": listKind},
+		},
+	}
+	schemas := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	schemas[prefix+kind] = schema
+	schemas[prefix+listKind] = list
+
+	base := "/apis/" + group + "/" + v.Name
+	if ars.Spec.Scope == "Namespaced" {
+		base += "/namespaces/{namespace}"
+	}
+	collection := base + "/" + ars.Spec.Names.Plural
+	paths := doc["paths"].(map[string]interface{})
+	paths[collection] = map[string]interface{}{"get": openAPIGetOperation("list", kind, listRef)}
+	paths[collection+"/{name}"] = map[string]interface{}{"get": openAPIGetOperation("read", kind, kindRef)}
+}
+
+func openAPIGetOperation(verb, kind, ref string) map[string]interface{} {
+	return map[string]interface{}{
+		"description": verb + " objects of kind " + kind,
+		"responses": map[string]interface{}{
+			"200": map[string]interface{}{
+				"description": "OK",
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": map[string]interface{}{"$ref": ref},
+					},
+				},
+			},
+		},
+	}
+}
+
+// reverseGroup turns an API group into the reverse-DNS form used in OpenAPI
+// component names (kedge.faros.sh → sh.faros.kedge).
+func reverseGroup(group string) string {
+	parts := strings.Split(group, ".")
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/faroshq/faros-kedge/pkg/problem"
+)
+
+const kedgeGV = "apis/kedge.faros.sh/v1alpha1"
+
+func TestIsOpenAPIV3Path(t *testing.T) {
+	cases := []struct {
+		in   string
+		want bool
+	}{
+		{"/clusters/abc/openapi/v3", true},
+		{"/clusters/abc/openapi/v3/apis/kedge.faros.sh/v1alpha1", true},
+		{"/clusters/abc/openapi/v2", false},
+		{"/clusters/abc/openapi/v3x", false},
+		{"/clusters/abc/api/v1/pods", false},
+		{"/openapi/v3", false},
+	}
+	for _, tc := range cases {
+		if got := isOpenAPIV3Path(tc.in); got != tc.want {
+			t.Errorf("isOpenAPIV3Path(%q) = %v, want %v", tc.in, got, tc.want)
+		}
+	}
+}
+
+// newTestAggregator wires an aggregator against a fake kcp that serves the
+// given index and counts requests.
+func newTestAggregator(t *testing.T, handler http.HandlerFunc) (*openAPIAggregator, *int32) {
+	t.Helper()
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	target, _ := url.Parse(srv.URL)
+	a, err := newOpenAPIAggregator(target, http.DefaultTransport, klog.Background())
+	if err != nil {
+		t.Fatalf("newOpenAPIAggregator: %v", err)
+	}
+	return a, &hits
+}
+
+func TestOpenAPIAggregatorIndexMergesKedgeGroupVersions(t *testing.T) {
+	a, hits := newTestAggregator(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer user" {
+			t.Errorf("caller credentials not forwarded: %q", r.Header.Get("Authorization"))
+		}
+		_, _ = w.Write([]byte(`{"paths":{"api/v1":{"serverRelativeURL":"/clusters/abc/openapi/v3/api/v1?hash=X"}}}`))
+	})
+
+	for range 2 {
+		r := httptest.NewRequest(http.MethodGet, "/clusters/abc/openapi/v3", nil)
+		r.Header.Set("Authorization", "Bearer user")
+		w := httptest.NewRecorder()
+		a.serve(w, r, "/clusters/abc/openapi/v3")
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200", w.Code)
+		}
+		var index struct {
+			Paths map[string]struct {
+				ServerRelativeURL string `json:"serverRelativeURL"`
+			} `json:"paths"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &index); err != nil {
+			t.Fatalf("decoding index: %v", err)
+		}
+		if _, ok := index.Paths["api/v1"]; !ok {
+			t.Errorf("upstream entry dropped: %v", index.Paths)
+		}
+		got, ok := index.Paths[kedgeGV]
+		if !ok {
+			t.Fatalf("kedge group-version missing from index: %v", index.Paths)
+		}
+		if !strings.HasPrefix(got.ServerRelativeURL, "/clusters/abc/openapi/v3/"+kedgeGV+"?hash=") {
+			t.Errorf("serverRelativeURL = %q", got.ServerRelativeURL)
+		}
+	}
+	if n := atomic.LoadInt32(hits); n != 1 {
+		t.Errorf("upstream hits = %d, want 1 (second request should be cached)", n)
+	}
+}
+
+func TestOpenAPIAggregatorServesEmbeddedSchemaWhenUnbound(t *testing.T) {
+	a, _ := newTestAggregator(t, func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+
+	path := "/clusters/abc/openapi/v3/" + kedgeGV
+	w := httptest.NewRecorder()
+	a.serve(w, httptest.NewRequest(http.MethodGet, path, nil), path)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	var doc struct {
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("decoding document: %v", err)
+	}
+	if _, ok := doc.Components.Schemas["sh.faros.kedge.v1alpha1.MCPServer"]; !ok {
+		t.Errorf("MCPServer schema missing; got %d schemas", len(doc.Components.Schemas))
+	}
+}
+
+func TestOpenAPIAggregatorPassesThroughErrors(t *testing.T) {
+	a, hits := newTestAggregator(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"kind":"Status","code":403}`))
+	})
+
+	for range 2 {
+		w := httptest.NewRecorder()
+		a.serve(w, httptest.NewRequest(http.MethodGet, "/clusters/abc/openapi/v3", nil), "/clusters/abc/openapi/v3")
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("status = %d, want 403", w.Code)
+		}
+	}
+	if n := atomic.LoadInt32(hits); n != 2 {
+		t.Errorf("upstream hits = %d, want 2 (errors must not be cached)", n)
+	}
+}
+
+func TestOpenAPIAggregatorUncachedAlwaysGoesUpstream(t *testing.T) {
+	a, hits := newTestAggregator(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"paths":{}}`))
+	})
+
+	for range 2 {
+		w := httptest.NewRecorder()
+		a.serveUncached(w, httptest.NewRequest(http.MethodGet, "/clusters/abc/openapi/v3", nil), "/clusters/abc/openapi/v3")
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200", w.Code)
+		}
+	}
+	if n := atomic.LoadInt32(hits); n != 2 {
+		t.Errorf("upstream hits = %d, want 2 (uncached requests must reach kcp)", n)
+	}
+	if n := len(a.cache); n != 0 {
+		t.Errorf("cache entries = %d, want 0", n)
+	}
+}
+
+func TestOpenAPIAggregatorCacheIsBounded(t *testing.T) {
+	a, _ := newTestAggregator(t, func(w http.ResponseWriter, r *http.Request) {})
+	a.maxEntries = 2
+	now := time.Unix(0, 0)
+	a.now = func() time.Time { return now }
+
+	a.store("a", []byte("a"))
+	now = now.Add(time.Second)
+	a.store("b", []byte("b"))
+	now = now.Add(time.Second)
+	a.store("c", []byte("c"))
+	if _, ok := a.cached("a"); ok {
+		t.Error("entry closest to expiry should have been evicted")
+	}
+	for _, k := range []string{"b", "c"} {
+		if _, ok := a.cached(k); !ok {
+			t.Errorf("entry %q evicted, want kept", k)
+		}
+	}
+
+	// Once everything has expired, a store sweeps it all.
+	now = now.Add(a.ttl)
+	a.store("d", []byte("d"))
+	if n := len(a.cache); n != 1 {
+		t.Errorf("cache entries = %d, want 1 after sweeping expired entries", n)
+	}
+}
+
+func TestOpenAPIAggregatorUpstreamErrorIsProblem(t *testing.T) {
+	target, _ := url.Parse("http://127.0.0.1:1")
+	a, err := newOpenAPIAggregator(target, http.DefaultTransport, klog.Background())
+	if err != nil {
+		t.Fatalf("newOpenAPIAggregator: %v", err)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/clusters/abc/openapi/v3", nil)
+	r.Header.Set("Accept", problem.ContentType)
+	w := httptest.NewRecorder()
+	a.serve(w, r, "/clusters/abc/openapi/v3")
+	if w.Code != http.StatusBadGateway {
+		t.Fatalf("status = %d, want 502", w.Code)
+	}
+	p := problem.Parse(w.Code, "", w.Body.Bytes())
+	if p.Reason != problem.ReasonServiceUnavailable {
+		t.Errorf("reason = %q, want %q", p.Reason, problem.ReasonServiceUnavailable)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != problem.ContentType {
+		t.Errorf("Content-Type = %q, want %q", ct, problem.ContentType)
+	}
+}
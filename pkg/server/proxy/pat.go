@@ -0,0 +1,228 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tenancyv1alpha1 "github.com/faroshq/faros-kedge/apis/tenancy/v1alpha1"
+	"github.com/faroshq/faros-kedge/pkg/hub/audit"
+	"github.com/faroshq/faros-kedge/pkg/hub/kcp"
+	"github.com/faroshq/faros-kedge/pkg/hub/readonly"
+	"github.com/faroshq/faros-kedge/pkg/problem"
+	"github.com/faroshq/faros-kedge/pkg/server/auth"
+)
+
+// patLastUsedInterval throttles PersonalAccessToken status.lastUsedTime
+// writes, so a busy CI job does not turn every request into an update.
+const patLastUsedInterval = time.Minute
+
+// servePersonalAccessToken handles requests authenticated with a personal
+// access token. kcp cannot verify these tokens itself, so the hub checks the
+// hash, expiry, workspace restriction and scopes, then forwards the request
+// with admin credentials impersonating the owning User.
+func (p *KCPProxy) servePersonalAccessToken(w http.ResponseWriter, r *http.Request, token, id string) {
+	ctx := r.Context()
+
+	now := time.Now()
+	pat, user, err := p.verifyPersonalAccessToken(ctx, token, id, now)
+	switch {
+	case errors.Is(err, errPATUnknown):
+		p.rejectCredential(w, r, token, lockoutEndpointProxy)
+		return
+	case errors.Is(err, errPATExpired):
+		problem.Write(w, r, http.StatusUnauthorized, problem.ReasonTokenExpired, "personal access token expired — issue a new one")
+		return
+	case err != nil:
+		writeUnauthorized(w, r)
+		return
+	}
+	audit.SetUser(ctx, user.Name, audit.AuthMethodPersonalAccessToken)
+	if user.Spec.RBACIdentity == "" {
+		problem.Write(w, r, http.StatusForbidden, problem.ReasonForbidden, "user has no RBAC identity yet")
+		return
+	}
+
+	kcpPath, denial := p.authorizeKCPPath(ctx, user.Name, r.URL.Path)
+	if denial != nil {
+		p.logger.Info("cluster access denied", "user", user.Name, "path", r.URL.Path, "status", denial.Status)
+		denial.Instance = r.URL.Path
+		problem.WriteProblem(w, r, denial)
+		return
+	}
+	if !patClusterAllowed(pat.Spec.Cluster, extractClusterPathFromKCPPath(kcpPath)) {
+		problem.Write(w, r, http.StatusForbidden, problem.ReasonForbidden,
+			fmt.Sprintf("personal access token is restricted to cluster %s", pat.Spec.Cluster))
+		return
+	}
+	if readonly.IsConnect(r) {
+		// Edge sessions terminate at the edges provider, which verifies
+		// callers with a kcp TokenReview that cannot resolve these tokens.
+		problem.Write(w, r, http.StatusForbidden, problem.ReasonForbidden,
+			"personal access tokens cannot open edge sessions — sign in with 'kedge login'")
+		return
+	}
+	if scope := patRequiredScope(r, kcpPath); !patGrants(&pat.Spec, scope) {
+		p.logger.Info("personal access token scope denied", "id", id, "user", user.Name, "path", kcpPath, "scope", scope)
+		problem.Write(w, r, http.StatusForbidden, problem.ReasonForbidden,
+			fmt.Sprintf("personal access token lacks the %q scope", scope))
+		return
+	}
+
+	if p.throttle(w, r, user.Name, extractClusterPathFromKCPPath(kcpPath)) {
+		return
+	}
+
+	if last := pat.Status.LastUsedTime; last == nil || now.Sub(last.Time) >= patLastUsedInterval {
+		go p.touchPersonalAccessToken(pat, now)
+	}
+
+	target := *p.kcpTarget
+	logger := p.logger
+	identity := user.Spec.RBACIdentity
+	groups := patImpersonationGroups(user)
+
+	proxy := &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			req.URL.Scheme = target.Scheme
+			req.URL.Host = target.Host
+			req.URL.Path = kcpPath
+			req.Host = target.Host
+
+			// The token means nothing to kcp: drop it so the admin
+			// transport sets its own credentials, drop any
+			// caller-supplied impersonation, and act as the owner.
+			req.Header.Del("Authorization")
+			for k := range req.Header {
+				if strings.HasPrefix(k, "Impersonate-") {
+					req.Header.Del(k)
+				}
+			}
+			req.Header.Set("Impersonate-User", identity)
+			for _, g := range groups {
+				req.Header.Add("Impersonate-Group", g)
+			}
+		},
+		Transport: p.adminTransport,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			logger.Error(err, "proxy upstream error (personal access token)", "method", r.Method, "path", r.URL.Path)
+			problem.Write(w, r, http.StatusBadGateway, problem.ReasonServiceUnavailable, "upstream error")
+		},
+	}
+
+	proxy.ServeHTTP(w, r)
+}
+
+var (
+	// errPATUnknown means no token matches: the ID is unknown or the
+	// secret does not match its hash.
+	errPATUnknown = errors.New("unknown personal access token")
+	// errPATExpired means the token is past its expiration time.
+	errPATExpired = errors.New("personal access token expired")
+)
+
+// verifyPersonalAccessToken checks token (whose embedded ID is id) against
+// the stored hash and expiry at now, and returns it with its owner, who
+// must exist and not be pending deletion.
+func (p *KCPProxy) verifyPersonalAccessToken(ctx context.Context, token, id string, now time.Time) (*tenancyv1alpha1.PersonalAccessToken, *tenancyv1alpha1.User, error) {
+	pat, err := p.kedgeClient.PersonalAccessTokens().Get(ctx, id, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			p.logger.Error(err, "failed to get personal access token", "id", id)
+			return nil, nil, fmt.Errorf("getting personal access token: %w", err)
+		}
+		return nil, nil, errPATUnknown
+	}
+	if subtle.ConstantTimeCompare([]byte(auth.HashPersonalAccessToken(token)), []byte(pat.Spec.TokenHash)) != 1 {
+		p.logger.Info("proxy auth: personal access token hash mismatch", "id", id)
+		return nil, nil, errPATUnknown
+	}
+	if exp := pat.Spec.ExpirationTimestamp; exp != nil && !now.Before(exp.Time) {
+		return nil, nil, errPATExpired
+	}
+	user, err := p.kedgeClient.Users().Get(ctx, pat.Spec.User, metav1.GetOptions{})
+	if err != nil || user.Status.DeletionRequestedAt != nil {
+		p.logger.Info("proxy auth: personal access token owner unavailable", "id", id, "user", pat.Spec.User)
+		return nil, nil, fmt.Errorf("personal access token owner %s unavailable", pat.Spec.User)
+	}
+	return pat, user, nil
+}
+
+// patImpersonationGroups returns the groups a personal access token request
+// impersonates along with its owner: system:authenticated, and the owner's
+// directory groups as embedded kcp names them from the OIDC groups claim, so
+// RBAC granted to those groups (team workspace roles) applies to the token
+// as it does to the owner's sign-in. The directory groups are those of the
+// owner's last sign-in.
+func patImpersonationGroups(user *tenancyv1alpha1.User) []string {
+	groups := make([]string, 0, 1+len(user.Status.Groups))
+	groups = append(groups, "system:authenticated")
+	for _, g := range user.Status.Groups {
+		groups = append(groups, kcp.DefaultOIDCGroupsPrefix+g)
+	}
+	return groups
+}
+
+// touchPersonalAccessToken records that pat was used at now. Best effort: a
+// failed write only leaves lastUsedTime stale.
+func (p *KCPProxy) touchPersonalAccessToken(pat *tenancyv1alpha1.PersonalAccessToken, now time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	pat = pat.DeepCopy()
+	pat.Status.LastUsedTime = &metav1.Time{Time: now}
+	if _, err := p.kedgeClient.PersonalAccessTokens().UpdateStatus(ctx, pat, metav1.UpdateOptions{}); err != nil {
+		p.logger.V(4).Info("failed to record personal access token use", "id", pat.Name, "err", err)
+	}
+}
+
+// patClusterAllowed reports whether a token restricted to cluster (empty for
+// unrestricted) may address seg: the cluster itself or one of its edges.
+func patClusterAllowed(cluster, seg string) bool {
+	return cluster == "" || seg == cluster || strings.HasPrefix(seg, cluster+":")
+}
+
+// patRequiredScope returns the scope a request needs: read for reads, admin
+// for deleting edges and write for everything else.
+func patRequiredScope(r *http.Request, kcpPath string) tenancyv1alpha1.PersonalAccessTokenScope {
+	switch {
+	case readonly.IsRead(r):
+		return tenancyv1alpha1.PersonalAccessTokenScopeRead
+	case isEdgeDeletion(r.Method, kcpPath):
+		return tenancyv1alpha1.PersonalAccessTokenScopeAdmin
+	default:
+		return tenancyv1alpha1.PersonalAccessTokenScopeWrite
+	}
+}
+
+// patGrants reports whether spec's scopes cover scope: admin covers
+// everything and write covers read.
+func patGrants(spec *tenancyv1alpha1.PersonalAccessTokenSpec, scope tenancyv1alpha1.PersonalAccessTokenScope) bool {
+	if spec.HasScope(scope) || spec.HasScope(tenancyv1alpha1.PersonalAccessTokenScopeAdmin) {
+		return true
+	}
+	return scope == tenancyv1alpha1.PersonalAccessTokenScopeRead && spec.HasScope(tenancyv1alpha1.PersonalAccessTokenScopeWrite)
+}
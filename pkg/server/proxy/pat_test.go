@@ -0,0 +1,174 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/faroshq/faros-kedge/apis/tenancy/v1alpha1"
+	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+	"github.com/faroshq/faros-kedge/pkg/server/auth"
+)
+
+func TestPatRequiredScope(t *testing.T) {
+	const edges = "/clusters/c/apis/edges.kedge.faros.sh/v1alpha1/kubernetesclusters"
+	for _, tc := range []struct {
+		method, path, upgrade string
+		want                  tenancyv1alpha1.PersonalAccessTokenScope
+	}{
+		{http.MethodGet, "/clusters/c/api/v1/configmaps", "", tenancyv1alpha1.PersonalAccessTokenScopeRead},
+		{http.MethodPost, "/clusters/c/api/v1/namespaces/default/configmaps", "", tenancyv1alpha1.PersonalAccessTokenScopeWrite},
+		{http.MethodDelete, "/clusters/c/api/v1/namespaces/default/configmaps/x", "", tenancyv1alpha1.PersonalAccessTokenScopeWrite},
+		{http.MethodDelete, edges + "/store-7", "", tenancyv1alpha1.PersonalAccessTokenScopeAdmin},
+	} {
+		r := httptest.NewRequest(tc.method, tc.path, nil)
+		if tc.upgrade != "" {
+			r.Header.Set("Upgrade", tc.upgrade)
+		}
+		if got := patRequiredScope(r, tc.path); got != tc.want {
+			t.Errorf("%s %s: scope = %q, want %q", tc.method, tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestPatGrants(t *testing.T) {
+	spec := func(scopes ...tenancyv1alpha1.PersonalAccessTokenScope) *tenancyv1alpha1.PersonalAccessTokenSpec {
+		return &tenancyv1alpha1.PersonalAccessTokenSpec{Scopes: scopes}
+	}
+	read, write, admin := tenancyv1alpha1.PersonalAccessTokenScopeRead, tenancyv1alpha1.PersonalAccessTokenScopeWrite,
+		tenancyv1alpha1.PersonalAccessTokenScopeAdmin
+	for _, tc := range []struct {
+		spec  *tenancyv1alpha1.PersonalAccessTokenSpec
+		scope tenancyv1alpha1.PersonalAccessTokenScope
+		want  bool
+	}{
+		{spec(read), read, true},
+		{spec(read), write, false},
+		{spec(write), read, true},
+		{spec(write), admin, false},
+		{spec(admin), read, true},
+		{spec(admin), admin, true},
+	} {
+		if got := patGrants(tc.spec, tc.scope); got != tc.want {
+			t.Errorf("scopes %v grant %q = %v, want %v", tc.spec.Scopes, tc.scope, got, tc.want)
+		}
+	}
+}
+
+func TestPatImpersonationGroups(t *testing.T) {
+	user := &tenancyv1alpha1.User{}
+	if got, want := patImpersonationGroups(user), []string{"system:authenticated"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("without directory groups: got %v, want %v", got, want)
+	}
+	user.Status.Groups = []string{"eng", "auditors"}
+	if got, want := patImpersonationGroups(user), []string{"system:authenticated", "kedge:eng", "kedge:auditors"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("with directory groups: got %v, want %v", got, want)
+	}
+}
+
+func TestPatClusterAllowed(t *testing.T) {
+	for _, tc := range []struct {
+		cluster, seg string
+		want         bool
+	}{
+		{"", "abc", true},
+		{"abc", "abc", true},
+		{"abc", "abc:store-7", true},
+		{"abc", "abcd", false},
+		{"abc", "xyz", false},
+	} {
+		if got := patClusterAllowed(tc.cluster, tc.seg); got != tc.want {
+			t.Errorf("patClusterAllowed(%q, %q) = %v, want %v", tc.cluster, tc.seg, got, tc.want)
+		}
+	}
+}
+
+// TestServePersonalAccessTokenRejects checks the failures decided before
+// the request reaches kcp.
+func TestServePersonalAccessTokenRejects(t *testing.T) {
+	id, token, hash, err := auth.NewPersonalAccessToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	past := metav1.NewTime(time.Now().Add(-time.Hour))
+	pat := &tenancyv1alpha1.PersonalAccessToken{
+		ObjectMeta: metav1.ObjectMeta{Name: id},
+		Spec: tenancyv1alpha1.PersonalAccessTokenSpec{
+			User:      "alice",
+			Scopes:    []tenancyv1alpha1.PersonalAccessTokenScope{tenancyv1alpha1.PersonalAccessTokenScopeRead},
+			TokenHash: hash,
+		},
+	}
+	// Stores the same hash, so token passes the hash check against it.
+	expired := pat.DeepCopy()
+	expired.Name = "pat-expired"
+	expired.Spec.ExpirationTimestamp = &past
+
+	scheme := runtime.NewScheme()
+	if err := tenancyv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	dyn := fake.NewSimpleDynamicClientWithCustomListKinds(scheme, map[schema.GroupVersionResource]string{
+		kedgeclient.PersonalAccessTokenGVR: "PersonalAccessTokenList",
+		kedgeclient.UserGVR:                "UserList",
+	}, pat, expired)
+	p := &KCPProxy{
+		kedgeClient: kedgeclient.NewFromDynamic(dyn),
+		logger:      klog.Background(),
+	}
+
+	for _, tc := range []struct {
+		name, token, id string
+		want            int
+	}{
+		{"unknown id", auth.PersonalAccessTokenPrefix + "pat-0000_secret", "pat-0000", http.StatusUnauthorized},
+		{"wrong secret", auth.PersonalAccessTokenPrefix + id + "_wrong", id, http.StatusUnauthorized},
+		{"expired", token, "pat-expired", http.StatusUnauthorized},
+		{"owner missing", token, id, http.StatusUnauthorized},
+	} {
+		rec := httptest.NewRecorder()
+		p.servePersonalAccessToken(rec, httptest.NewRequest(http.MethodGet, "/clusters/c/api/v1/configmaps", nil), tc.token, tc.id)
+		if rec.Code != tc.want {
+			t.Errorf("%s: status = %d, want %d", tc.name, rec.Code, tc.want)
+		}
+	}
+}
+
+func TestParsePersonalAccessToken(t *testing.T) {
+	id, token, _, err := auth.NewPersonalAccessToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := auth.ParsePersonalAccessToken(token); !ok || got != id {
+		t.Errorf("ParsePersonalAccessToken(%q) = %q, %v; want %q", token, got, ok, id)
+	}
+	for _, bad := range []string{"", "eyJhbGciOi.x.y", "kedgepat_", "kedgepat_pat-1", "kedgepat_nope_secret", "kedgepat_pat-1_"} {
+		if _, ok := auth.ParsePersonalAccessToken(bad); ok {
+			t.Errorf("ParsePersonalAccessToken(%q) accepted", bad)
+		}
+	}
+}
@@ -38,8 +38,10 @@ import (
 
 	oidc "github.com/coreos/go-oidc"
 	"golang.org/x/oauth2"
+	"golang.org/x/sync/singleflight"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
@@ -48,7 +50,12 @@ import (
 	tenancyv1alpha1 "github.com/faroshq/faros-kedge/apis/tenancy/v1alpha1"
 	"github.com/faroshq/faros-kedge/pkg/apiurl"
 	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+	"github.com/faroshq/faros-kedge/pkg/hub/audit"
 	"github.com/faroshq/faros-kedge/pkg/hub/kcp"
+	"github.com/faroshq/faros-kedge/pkg/hub/readonly"
+	"github.com/faroshq/faros-kedge/pkg/problem"
+	"github.com/faroshq/faros-kedge/pkg/server/auth"
+	"github.com/faroshq/faros-kedge/pkg/util/httpcompress"
 )
 
 // defaultStaticTokenRateLimit is the default number of token-login requests allowed per minute per IP.
@@ -57,13 +64,24 @@ const defaultStaticTokenRateLimit = 10
 // defaultStaticTokenBurstDuration is the default time window for static token rate limiting.
 const defaultStaticTokenBurstDuration = time.Minute
 
+// Endpoint labels of the authentication lockout and OIDC verification
+// metrics.
+const (
+	lockoutEndpointProxy      = "kcp-proxy"
+	lockoutEndpointTokenLogin = "token-login"
+	// verifyEndpointIdentify labels the verifications of IdentifyUser, for
+	// the hub REST API and provider backends.
+	verifyEndpointIdentify = "identify"
+)
+
 // KCPProxy is a reverse proxy that authenticates requests via OIDC
 // and forwards them to the user's dedicated kcp tenant workspace.
 type KCPProxy struct {
 	kcpTarget            *url.URL
-	passthroughTransport http.RoundTripper // TLS-only transport; no credentials injected
-	verifier             *oidc.IDTokenVerifier
-	verifyCtx            context.Context // context with HTTP client for OIDC key fetches
+	passthroughTransport http.RoundTripper       // TLS-only transport; no credentials injected
+	adminTransport       http.RoundTripper       // kcp admin credentials; used to impersonate personal access token owners
+	verifiers            []*oidc.IDTokenVerifier // one per identity provider, tried in order
+	verifyCtx            context.Context         // context with HTTP client for OIDC key fetches
 	kedgeClient          *kedgeclient.Client
 	bootstrapper         *kcp.Bootstrapper
 	staticAuthTokens     []string
@@ -75,6 +93,25 @@ type KCPProxy struct {
 	authorizer *clusterAuthorizer
 	// staticTokenRateLimiter protects the token-login endpoint against brute force attacks
 	staticTokenRateLimiter *tokenRateLimiter
+	// lockout refuses clients and static-token prefixes after repeated
+	// authentication failures; nil disables it. See SetLockout.
+	lockout *auth.Lockout
+	// openapi serves /clusters/{id}/openapi/v3 with the kedge APIExport
+	// schemas merged into kcp's per-workspace document.
+	openapi *openAPIAggregator
+	// staticUserGroup collapses concurrent first logins for the same static
+	// token (keyed by sub hash) into a single user bootstrap.
+	staticUserGroup singleflight.Group
+	// stepUp, when enabled, refuses edge deletion with an OIDC token that
+	// is neither recent nor multi-factor. Static and ServiceAccount tokens
+	// are not interactive sign-ins and are not subject to it.
+	stepUp *auth.StepUpPolicy
+	// latency attributes the time kcp takes to answer forwarded requests to
+	// their workspace; see ServeLatency.
+	latency *latencyTracker
+	// limiter throttles forwarded requests per user and per workspace; nil
+	// disables it. See SetRequestLimits.
+	limiter *requestLimiter
 }
 
 // tokenRateLimiter wraps the auth rate limiter for static token endpoints.
@@ -143,8 +180,7 @@ func (rl *rateLimiter) middleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		clientIP := getClientIP(r)
 		if !rl.isAllowed(clientIP) {
-			w.Header().Set("Retry-After", "60")
-			http.Error(w, "rate limit exceeded - too many requests", http.StatusTooManyRequests)
+			problem.WriteTooManyRequests(w, r, rl.interval)
 			return
 		}
 		next(w, r)
@@ -177,9 +213,10 @@ func getClientIP(r *http.Request) string {
 }
 
 // NewKCPProxy creates a reverse proxy to kcp.
-// It validates bearer tokens as OIDC id_tokens before proxying.
-// verifier may be nil when only static token auth is used.
-func NewKCPProxy(kcpConfig *rest.Config, verifier *oidc.IDTokenVerifier, kedgeClient *kedgeclient.Client, bootstrapper *kcp.Bootstrapper, staticAuthTokens []string, hubExternalURL string, devMode bool) (*KCPProxy, error) {
+// It validates bearer tokens as OIDC id_tokens before proxying, trying the
+// verifiers of the identity providers in order. verifiers may be empty when
+// only static token auth is used.
+func NewKCPProxy(kcpConfig *rest.Config, verifiers []*oidc.IDTokenVerifier, kedgeClient *kedgeclient.Client, bootstrapper *kcp.Bootstrapper, staticAuthTokens []string, hubExternalURL string, devMode bool) (*KCPProxy, error) {
 	target, err := url.Parse(kcpConfig.Host)
 	if err != nil {
 		return nil, err
@@ -211,6 +248,13 @@ func NewKCPProxy(kcpConfig *rest.Config, verifier *oidc.IDTokenVerifier, kedgeCl
 		return nil, fmt.Errorf("building passthrough transport: %w", err)
 	}
 
+	// Personal access tokens are verified by the hub, not kcp; their
+	// requests are forwarded with admin credentials and impersonation.
+	adminTransport, err := rest.TransportFor(transportConfig)
+	if err != nil {
+		return nil, fmt.Errorf("building admin transport: %w", err)
+	}
+
 	// Build a context with an insecure HTTP client for OIDC key fetches.
 	verifyCtx := context.Background()
 	if devMode {
@@ -230,18 +274,38 @@ func NewKCPProxy(kcpConfig *rest.Config, verifier *oidc.IDTokenVerifier, kedgeCl
 		bootstrapper.ListChildWorkspaces,
 	)
 
+	logger := klog.Background().WithName("kcp-proxy")
+	openapi, err := newOpenAPIAggregator(target, passthroughTransport, logger.WithName("openapi"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Forwarded requests are timed per workspace. The OpenAPI aggregator
+	// keeps the bare transport: its cached fetches serve every caller.
+	latency := newLatencyTracker()
+	passthroughTransport = &latencyTransport{next: passthroughTransport, tracker: latency}
+	adminTransport = &latencyTransport{next: adminTransport, tracker: latency}
+
+	// Compress forwarded responses for clients that accept it: kcp itself
+	// compresses only responses over 128KiB, and only with gzip.
+	passthroughTransport = httpcompress.NewTransport(passthroughTransport)
+	adminTransport = httpcompress.NewTransport(adminTransport)
+
 	return &KCPProxy{
 		kcpTarget:            target,
 		passthroughTransport: passthroughTransport,
-		verifier:             verifier,
+		adminTransport:       adminTransport,
+		verifiers:            verifiers,
 		verifyCtx:            verifyCtx,
 		kedgeClient:          kedgeClient,
 		bootstrapper:         bootstrapper,
 		staticAuthTokens:     staticAuthTokens,
 		hubExternalURL:       hubExternalURL,
 		devMode:              devMode,
-		logger:               klog.Background().WithName("kcp-proxy"),
+		logger:               logger,
 		authorizer:           authorizer,
+		openapi:              openapi,
+		latency:              latency,
 		// Initialize rate limiter for token-login endpoint (10 requests per minute)
 		staticTokenRateLimiter: &tokenRateLimiter{
 			limiter:   newRateLimiter(defaultStaticTokenBurstDuration, defaultStaticTokenRateLimit),
@@ -251,20 +315,69 @@ func NewKCPProxy(kcpConfig *rest.Config, verifier *oidc.IDTokenVerifier, kedgeCl
 	}, nil
 }
 
+// SetStepUpPolicy makes edge deletion require a recent sign-in or a second
+// factor. Call before serving; nil (the default) disables step-up.
+func (p *KCPProxy) SetStepUpPolicy(policy *auth.StepUpPolicy) {
+	p.stepUp = policy
+}
+
+// SetLockout makes the proxy and the token-login endpoint refuse client IPs
+// and token prefixes with repeated authentication failures. Call before
+// serving; nil (the default) disables the lockout.
+func (p *KCPProxy) SetLockout(lockout *auth.Lockout) {
+	p.lockout = lockout
+}
+
+// SetRequestLimits makes the proxy refuse requests over limits with 429.
+// Call before serving; without it (the default) requests are not limited.
+func (p *KCPProxy) SetRequestLimits(limits RequestLimits) {
+	p.limiter = newRequestLimiter(limits)
+}
+
+// isEdgeDeletion reports whether a DELETE on kcpPath deletes edges: one
+// KubernetesCluster or LinuxServer, or a collection of them.
+func isEdgeDeletion(method, kcpPath string) bool {
+	if method != http.MethodDelete {
+		return false
+	}
+	_, rest, ok := strings.Cut(kcpPath, "/apis/")
+	if !ok {
+		return false
+	}
+	parts := strings.Split(strings.Trim(rest, "/"), "/")
+	if len(parts) < 3 || len(parts) > 4 {
+		return false
+	}
+	for _, gvr := range []schema.GroupVersionResource{kedgeclient.KubernetesClusterGVR, kedgeclient.LinuxServerGVR} {
+		if parts[0] == gvr.Group && parts[2] == gvr.Resource {
+			return true
+		}
+	}
+	return false
+}
+
 // ServeHTTP validates the bearer token and proxies the request to kcp.
-// Two token types are supported:
+// Four token types are supported:
+//   - static tokens: resolved to a bootstrapped User, forwarded unchanged.
+//   - personal access tokens (kedgepat_…): verified against their
+//     PersonalAccessToken CR and scopes, forwarded with admin credentials
+//     impersonating the owning User.
 //   - OIDC id_tokens (from Dex): resolved to a tenant workspace via User CRD lookup,
-//     forwarded with admin credentials.
+//     forwarded unchanged.
 //   - kcp ServiceAccount tokens: the clusterName claim identifies the workspace,
 //     forwarded with the original SA token so kcp handles authn/authz natively.
 func (p *KCPProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Extract bearer token.
 	authHeader := r.Header.Get("Authorization")
 	if !strings.HasPrefix(authHeader, "Bearer ") {
-		writeUnauthorized(w)
+		writeUnauthorized(w, r)
 		return
 	}
 	token := strings.TrimPrefix(authHeader, "Bearer ")
+	if wait, ok := p.lockout.Check(r, token, lockoutEndpointProxy); !ok {
+		problem.WriteTooManyRequests(w, r, wait)
+		return
+	}
 
 	// Static token: create user/workspace if needed and proxy to user's workspace.
 	// Use constant-time comparison to prevent timing side-channel attacks.
@@ -276,32 +389,47 @@ func (p *KCPProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if id, ok := auth.ParsePersonalAccessToken(token); ok {
+		p.logger.V(4).Info("proxy auth: personal access token", "path", r.URL.Path, "id", id)
+		p.servePersonalAccessToken(w, r, token, id)
+		return
+	}
+
 	// Check for kcp ServiceAccount tokens BEFORE OIDC verification.
 	// SA tokens have iss="kubernetes/serviceaccount"; the OIDC verifier would
 	// correctly reject them, but running the check first saves a JWKS fetch and
 	// makes the auth branch unambiguous in logs.
 	if saClaims, ok := parseServiceAccountToken(token); ok {
 		p.logger.Info("proxy auth: SA token", "path", r.URL.Path, "clusterName", saClaims.ClusterName())
+		// The subject is unverified here; kcp verifies the token, and a
+		// forged one is recorded with the 401 kcp answers it with.
+		audit.SetUser(r.Context(), saClaims.Subject, audit.AuthMethodServiceAccount)
 		p.serveServiceAccount(w, r, token, saClaims.ClusterName())
 		return
 	}
 
 	// Try OIDC verification (user tokens from Dex).
-	if p.verifier != nil {
-		idToken, err := p.verifier.Verify(p.verifyCtx, token)
+	if len(p.verifiers) > 0 {
+		idToken, err := auth.VerifyIDTokenWithAny(p.verifyCtx, p.verifiers, token, lockoutEndpointProxy)
 		if err == nil {
 			p.logger.Info("proxy auth: OIDC verified", "path", r.URL.Path)
 			p.serveOIDC(w, r, token, idToken)
 			return
 		}
 		p.logger.Info("proxy auth: OIDC verify failed", "path", r.URL.Path, "err", err.Error())
+		// An expired id_token is still ours; tell the client so it can
+		// refresh or re-login instead of treating the token as unknown.
+		if isTokenExpiredError(err) {
+			problem.Write(w, r, http.StatusUnauthorized, problem.ReasonTokenExpired, "token expired — run 'kedge login' to refresh credentials")
+			return
+		}
 	}
 
 	// Log only SHA-256 hash prefix to prevent token information disclosure
 	// while still allowing correlation for debugging
 	tokenHash := sha256.Sum256([]byte(token))
 	p.logger.Info("proxy auth: no match — returning 401", "path", r.URL.Path, "tokenHash", hex.EncodeToString(tokenHash[:])[:16])
-	writeUnauthorized(w)
+	p.rejectCredential(w, r, token, lockoutEndpointProxy)
 }
 
 // serveOIDC handles OIDC-authenticated requests by resolving the user's tenant
@@ -317,32 +445,40 @@ func (p *KCPProxy) serveOIDC(w http.ResponseWriter, r *http.Request, token strin
 		Sub string `json:"sub"`
 	}
 	if err := idToken.Claims(&claims); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		_, _ = fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","metadata":{},"status":"Failure","message":"failed to parse token claims","reason":"InternalError","code":500}`)
+		problem.Write(w, r, http.StatusInternalServerError, problem.ReasonInternalError, "failed to parse token claims")
 		return
 	}
 
 	user, err := p.resolveUser(r.Context(), idToken.Issuer, claims.Sub)
 	if err != nil {
 		p.logger.Error(err, "failed to resolve user workspace", "sub", claims.Sub)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusForbidden)
-		_, _ = fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","metadata":{},"status":"Failure","message":"user workspace not found","reason":"Forbidden","code":403}`)
+		problem.Write(w, r, http.StatusForbidden, problem.ReasonForbidden, "user workspace not found")
 		return
 	}
 	// Wait for the bootstrap controller to finish provisioning the user's
 	// personal org/workspace (and its membership index) on the very first
 	// request after sign-up. Warm-path requests short-circuit immediately.
 	user = p.waitForDefaultCluster(r.Context(), user)
+	audit.SetUser(r.Context(), user.Name, audit.AuthMethodOIDC)
 
 	// Authorize the requested cluster against the caller's membership (A-1/A-3).
-	kcpPath, errStatus, errBody := p.authorizeKCPPath(r.Context(), user.Name, r.URL.Path)
-	if errStatus != 0 {
-		p.logger.Info("cluster access denied", "user", user.Name, "path", r.URL.Path, "status", errStatus)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(errStatus)
-		_, _ = fmt.Fprint(w, errBody)
+	kcpPath, denial := p.authorizeKCPPath(r.Context(), user.Name, r.URL.Path)
+	if denial != nil {
+		p.logger.Info("cluster access denied", "user", user.Name, "path", r.URL.Path, "status", denial.Status)
+		denial.Instance = r.URL.Path
+		problem.WriteProblem(w, r, denial)
+		return
+	}
+	if r.Method == http.MethodGet && isOpenAPIV3Path(kcpPath) {
+		p.openapi.serve(w, r, kcpPath)
+		return
+	}
+	if p.throttle(w, r, user.Name, extractClusterPathFromKCPPath(kcpPath)) {
+		return
+	}
+	if isEdgeDeletion(r.Method, kcpPath) && !p.stepUp.Satisfied(idToken, time.Now()) {
+		p.logger.Info("edge deletion refused: step-up required", "user", user.Name, "path", kcpPath)
+		p.stepUp.WriteStepUpRequired(w, r, "deleting an edge")
 		return
 	}
 
@@ -365,9 +501,7 @@ func (p *KCPProxy) serveOIDC(w http.ResponseWriter, r *http.Request, token strin
 		Transport: p.passthroughTransport,
 		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
 			logger.Error(err, "proxy upstream error", "method", r.Method, "path", r.URL.Path)
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadGateway)
-			_, _ = fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","metadata":{},"status":"Failure","message":"upstream error","reason":"ServiceUnavailable","code":502}`)
+			problem.Write(w, r, http.StatusBadGateway, problem.ReasonServiceUnavailable, "upstream error")
 		},
 	}
 
@@ -387,22 +521,27 @@ func (p *KCPProxy) serveStaticToken(w http.ResponseWriter, r *http.Request, toke
 	user, err := p.ensureStaticTokenUser(ctx, token, subHash)
 	if err != nil {
 		p.logger.Error(err, "failed to ensure static token user")
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		_, _ = fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","metadata":{},"status":"Failure","message":"failed to create user","reason":"InternalError","code":500}`)
+		problem.Write(w, r, http.StatusInternalServerError, problem.ReasonInternalError, "failed to create user")
 		return
 	}
 	// Wait for the bootstrap controller to finish provisioning the user's
 	// personal org/workspace (and its membership index) on first request.
 	user = p.waitForDefaultCluster(ctx, user)
+	audit.SetUser(ctx, user.Name, audit.AuthMethodStaticToken)
 
 	// Authorize the requested cluster against the caller's membership (A-1/A-3).
-	kcpPath, errStatus, errBody := p.authorizeKCPPath(ctx, user.Name, r.URL.Path)
-	if errStatus != 0 {
-		p.logger.Info("cluster access denied", "user", user.Name, "path", r.URL.Path, "status", errStatus)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(errStatus)
-		_, _ = fmt.Fprint(w, errBody)
+	kcpPath, denial := p.authorizeKCPPath(ctx, user.Name, r.URL.Path)
+	if denial != nil {
+		p.logger.Info("cluster access denied", "user", user.Name, "path", r.URL.Path, "status", denial.Status)
+		denial.Instance = r.URL.Path
+		problem.WriteProblem(w, r, denial)
+		return
+	}
+	if r.Method == http.MethodGet && isOpenAPIV3Path(kcpPath) {
+		p.openapi.serve(w, r, kcpPath)
+		return
+	}
+	if p.throttle(w, r, user.Name, extractClusterPathFromKCPPath(kcpPath)) {
 		return
 	}
 
@@ -425,18 +564,44 @@ func (p *KCPProxy) serveStaticToken(w http.ResponseWriter, r *http.Request, toke
 		Transport: p.passthroughTransport,
 		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
 			logger.Error(err, "proxy upstream error (static token)", "method", r.Method, "path", r.URL.Path)
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadGateway)
-			_, _ = fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","metadata":{},"status":"Failure","message":"upstream error","reason":"ServiceUnavailable","code":502}`)
+			problem.Write(w, r, http.StatusBadGateway, problem.ReasonServiceUnavailable, "upstream error")
 		},
 	}
 
 	proxy.ServeHTTP(w, r)
 }
 
+// staticUserBootstrapTimeout bounds the shared static-token user bootstrap.
+// It is detached from every caller's context, so without a deadline one hung
+// kcp call would block all requests for that token indefinitely.
+const staticUserBootstrapTimeout = 30 * time.Second
+
 // ensureStaticTokenUser creates or retrieves a User for a static token.
-// It uses retry logic to handle conflicts from concurrent updates.
+// Concurrent calls for the same token share one in-flight bootstrap, so a
+// burst of first requests (CLI + portal + agent logging in together) issues a
+// single List/Create instead of racing each other into conflicts. The shared
+// call is detached from the caller's cancellation so one client hanging up
+// does not fail the others waiting on it, and bounded by
+// staticUserBootstrapTimeout instead.
 func (p *KCPProxy) ensureStaticTokenUser(ctx context.Context, token, subHash string) (*tenancyv1alpha1.User, error) {
+	v, err, shared := p.staticUserGroup.Do(subHash, func() (interface{}, error) {
+		bootstrapCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), staticUserBootstrapTimeout)
+		defer cancel()
+		return p.ensureStaticTokenUserWithRetry(bootstrapCtx, token, subHash)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if shared {
+		p.logger.V(4).Info("Shared in-flight static token user bootstrap", "subHash", subHash[:16])
+	}
+	// Every caller gets its own copy; the result is shared across goroutines.
+	return v.(*tenancyv1alpha1.User).DeepCopy(), nil
+}
+
+// ensureStaticTokenUserWithRetry runs ensureStaticTokenUserOnce, retrying on
+// conflicts from concurrent updates (e.g. another hub replica).
+func (p *KCPProxy) ensureStaticTokenUserWithRetry(ctx context.Context, token, subHash string) (*tenancyv1alpha1.User, error) {
 	const maxRetries = 5
 	var lastErr error
 
@@ -580,7 +745,7 @@ func (p *KCPProxy) serveServiceAccount(w http.ResponseWriter, r *http.Request, t
 	matched, _ := regexp.MatchString(`^[a-z0-9]+(?:[:-][a-z0-9]+)*$`, clusterName)
 	if !matched {
 		p.logger.Info("SA: clusterName regex rejected — 401", "clusterName", clusterName)
-		writeUnauthorized(w)
+		p.rejectCredential(w, r, token, lockoutEndpointProxy)
 		return
 	}
 
@@ -591,7 +756,31 @@ func (p *KCPProxy) serveServiceAccount(w http.ResponseWriter, r *http.Request, t
 	// reaching the Org workspace's API server.
 	if isOrgWorkspacePath(clusterName) {
 		p.logger.Info("SA: org workspace access denied (O-10)", "cluster", clusterName)
-		writeOrgWorkspaceForbidden(w)
+		writeOrgWorkspaceForbidden(w, r)
+		return
+	}
+
+	// The agent kubeconfig may already include /clusters/{name} in its
+	// server URL, so the incoming path can be
+	//   /clusters/{name}/api/...
+	// Strip the prefix to avoid doubling it when we prepend below.
+	clusterPrefix := "/clusters/" + clusterName
+	reqPath := r.URL.Path
+	if strings.HasPrefix(reqPath, clusterPrefix+"/") || reqPath == clusterPrefix {
+		reqPath = strings.TrimPrefix(reqPath, clusterPrefix)
+		if reqPath == "" {
+			reqPath = "/"
+		}
+	}
+	kcpPath := clusterPrefix + reqPath
+	audit.SetCluster(r.Context(), clusterName)
+	// Only kcp verifies SA tokens, so the aggregated document is fetched with
+	// the SA token on every request instead of coming from the shared cache.
+	if r.Method == http.MethodGet && isOpenAPIV3Path(kcpPath) {
+		p.openapi.serveUncached(w, r, kcpPath)
+		return
+	}
+	if p.throttle(w, r, serviceAccountLimitKey(token), "") {
 		return
 	}
 
@@ -602,20 +791,7 @@ func (p *KCPProxy) serveServiceAccount(w http.ResponseWriter, r *http.Request, t
 		Director: func(req *http.Request) {
 			req.URL.Scheme = target.Scheme
 			req.URL.Host = target.Host
-
-			// The agent kubeconfig may already include /clusters/{name} in its
-			// server URL, so the incoming path can be
-			//   /clusters/{name}/api/...
-			// Strip the prefix to avoid doubling it when we prepend below.
-			clusterPrefix := "/clusters/" + clusterName
-			reqPath := req.URL.Path
-			if strings.HasPrefix(reqPath, clusterPrefix+"/") || reqPath == clusterPrefix {
-				reqPath = strings.TrimPrefix(reqPath, clusterPrefix)
-				if reqPath == "" {
-					reqPath = "/"
-				}
-			}
-			req.URL.Path = clusterPrefix + reqPath
+			req.URL.Path = kcpPath
 			req.Host = target.Host
 
 			// Keep the SA token — kcp authenticates it natively.
@@ -625,9 +801,7 @@ func (p *KCPProxy) serveServiceAccount(w http.ResponseWriter, r *http.Request, t
 		Transport: p.passthroughTransport,
 		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
 			logger.Error(err, "proxy upstream error (SA)", "method", r.Method, "path", r.URL.Path)
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadGateway)
-			_, _ = fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","metadata":{},"status":"Failure","message":"upstream error","reason":"ServiceUnavailable","code":502}`)
+			problem.Write(w, r, http.StatusBadGateway, problem.ReasonServiceUnavailable, "upstream error")
 		},
 	}
 
@@ -641,6 +815,7 @@ func (p *KCPProxy) serveServiceAccount(w http.ResponseWriter, r *http.Request, t
 // WithInClusterServiceAccountRequestRewrite (pkg/server/filters/serviceaccounts.go).
 type saTokenClaims struct {
 	Issuer            string `json:"iss"`
+	Subject           string `json:"sub"`
 	ClusterNameLegacy string `json:"kubernetes.io/serviceaccount/clusterName"`
 	Kubernetes        struct {
 		ClusterName string `json:"clusterName"`
@@ -682,10 +857,21 @@ func parseServiceAccountToken(token string) (saTokenClaims, bool) {
 	return claims, true
 }
 
-func writeUnauthorized(w http.ResponseWriter) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusUnauthorized)
-	_, _ = fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","metadata":{},"status":"Failure","message":"Unauthorized","reason":"Unauthorized","code":401}`)
+// isTokenExpiredError reports whether err is go-oidc's expiry rejection. The
+// v2 library does not export a typed error for it, so match on the message.
+func isTokenExpiredError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "token is expired")
+}
+
+func writeUnauthorized(w http.ResponseWriter, r *http.Request) {
+	problem.Write(w, r, http.StatusUnauthorized, problem.ReasonUnauthorized, "Unauthorized")
+}
+
+// rejectCredential counts a failed authentication with token towards the
+// lockout and writes a 401.
+func (p *KCPProxy) rejectCredential(w http.ResponseWriter, r *http.Request, token, endpoint string) {
+	p.lockout.Fail(r, token, endpoint)
+	writeUnauthorized(w, r)
 }
 
 // orgWorkspacePathPrefix is the kcp logical-cluster path under which every
@@ -695,13 +881,13 @@ func writeUnauthorized(w http.ResponseWriter) {
 // requested target is an Org workspace.
 const orgWorkspacePathPrefix = "root:kedge:tenants:"
 
-// orgWorkspaceForbiddenBody is the JSON the proxy returns when refusing a
-// direct request to an Organization workspace per docs/organizations.md
-// decision O-10 ("Org workspaces are hub-mediated only"). The body uses
-// the standard Kubernetes Status envelope so kubectl renders the message
-// nicely while also carrying a kedge-specific reason + a pointer at the
-// hub REST surface so CLI tooling can suggest the right endpoint.
-const orgWorkspaceForbiddenBody = `{"kind":"Status","apiVersion":"v1","metadata":{},"status":"Failure","message":"Organization workspaces are hub-mediated and not directly accessible — use the hub REST endpoints at /api/orgs/{org-uuid}/... instead.","reason":"OrgWorkspaceNotDirectlyAccessible","code":403,"details":{"kind":"OrganizationWorkspace","group":"tenants.kedge.faros.sh"}}`
+// orgWorkspaceForbiddenDetail is the message the proxy returns when refusing
+// a direct request to an Organization workspace per docs/organizations.md
+// decision O-10 ("Org workspaces are hub-mediated only"). It goes out with
+// problem.ReasonOrgWorkspaceNotDirectlyAccessible, as a Kubernetes Status for
+// kubectl or problem+json for clients that ask, and points at the hub REST
+// surface so CLI tooling can suggest the right endpoint.
+const orgWorkspaceForbiddenDetail = "Organization workspaces are hub-mediated and not directly accessible — use the hub REST endpoints at /api/orgs/{org-uuid}/... instead."
 
 // isOrgWorkspacePath reports whether clusterPath addresses a kcp
 // Organization workspace (path root:kedge:orgs:{single-segment}). Child
@@ -745,22 +931,20 @@ func extractClusterPathFromKCPPath(kcpPath string) string {
 }
 
 // writeOrgWorkspaceForbidden writes the O-10 403 response.
-func writeOrgWorkspaceForbidden(w http.ResponseWriter) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusForbidden)
-	_, _ = fmt.Fprint(w, orgWorkspaceForbiddenBody)
+func writeOrgWorkspaceForbidden(w http.ResponseWriter, r *http.Request) {
+	problem.Write(w, r, http.StatusForbidden, problem.ReasonOrgWorkspaceNotDirectlyAccessible, orgWorkspaceForbiddenDetail)
 }
 
-// Response bodies for the membership-gated cluster authorization (Option A).
+// Denial messages for the membership-gated cluster authorization (Option A).
 const (
-	bareNoClusterBody       = `{"kind":"Status","apiVersion":"v1","metadata":{},"status":"Failure","message":"no workspace selected — address /clusters/{id} (resolve the id via the hub REST endpoints, e.g. /api/orgs/{org}/workspaces)","reason":"BadRequest","code":400}`
-	addressByIDBody         = `{"kind":"Status","apiVersion":"v1","metadata":{},"status":"Failure","message":"address workspaces by cluster ID (/clusters/{id}), not by path — resolve the id via /api/orgs/{org}/workspaces/{ws}","reason":"Forbidden","code":403}`
-	clusterAccessDeniedBody = `{"kind":"Status","apiVersion":"v1","metadata":{},"status":"Failure","message":"cluster access denied","reason":"Forbidden","code":403}`
+	bareNoClusterDetail       = "no workspace selected — address /clusters/{id} (resolve the id via the hub REST endpoints, e.g. /api/orgs/{org}/workspaces)"
+	addressByIDDetail         = "address workspaces by cluster ID (/clusters/{id}), not by path — resolve the id via /api/orgs/{org}/workspaces/{ws}"
+	clusterAccessDeniedDetail = "cluster access denied"
 )
 
 // authorizeKCPPath authorizes userName's request URL against their membership
-// and returns the kcp path to forward (unchanged for /clusters/{id}) or an
-// error (status, body). Implements docs/hub-proxy-workspace-access.md:
+// and returns the kcp path to forward (unchanged for /clusters/{id}) or the
+// problem to answer with. Implements docs/hub-proxy-workspace-access.md:
 //
 //   - bare /api|/apis (no cluster segment) → rejected; there is no
 //     DefaultCluster default (A-1).
@@ -770,24 +954,24 @@ const (
 //   - /clusters/{id}[:{edge}] → allowed iff the caller is a member of the
 //     workspace the id (or the id of an edge's parent) belongs to (A-3).
 //
-// Returns (kcpPath, 0, "") on success, or ("", status, body) on denial.
-func (p *KCPProxy) authorizeKCPPath(ctx context.Context, userName, urlPath string) (string, int, string) {
+// Returns (kcpPath, nil) on success, or ("", problem) on denial.
+func (p *KCPProxy) authorizeKCPPath(ctx context.Context, userName, urlPath string) (string, *problem.Problem) {
 	if !strings.HasPrefix(urlPath, "/clusters/") {
-		return "", http.StatusBadRequest, bareNoClusterBody
+		return "", problem.New(http.StatusBadRequest, problem.ReasonBadRequest, bareNoClusterDetail)
 	}
 	seg := extractClusterPathFromKCPPath(urlPath)
 	switch {
 	case seg == "":
-		return "", http.StatusBadRequest, bareNoClusterBody
+		return "", problem.New(http.StatusBadRequest, problem.ReasonBadRequest, bareNoClusterDetail)
 	case isOrgWorkspacePath(seg):
-		return "", http.StatusForbidden, orgWorkspaceForbiddenBody
+		return "", problem.New(http.StatusForbidden, problem.ReasonOrgWorkspaceNotDirectlyAccessible, orgWorkspaceForbiddenDetail)
 	case strings.HasPrefix(seg, "root:"):
-		return "", http.StatusForbidden, addressByIDBody
+		return "", problem.New(http.StatusForbidden, problem.ReasonForbidden, addressByIDDetail)
 	}
 	if !p.authorizer.authorize(ctx, userName, seg) {
-		return "", http.StatusForbidden, clusterAccessDeniedBody
+		return "", problem.New(http.StatusForbidden, problem.ReasonForbidden, clusterAccessDeniedDetail)
 	}
-	return urlPath, 0, ""
+	return urlPath, nil
 }
 
 // ErrIdentifyNoBearer is returned by IdentifyUser when the request
@@ -795,17 +979,26 @@ func (p *KCPProxy) authorizeKCPPath(ctx context.Context, userName, urlPath strin
 // middleware) translate this into a 401.
 var ErrIdentifyNoBearer = errors.New("no Authorization: Bearer token")
 
+// ErrIdentifyRejected is returned by IdentifyUser for a personal access
+// token it does not accept: unknown, expired, owned by an unavailable
+// user, or presented for anything but a read. Callers translate this
+// into a 401, as for a missing token.
+var ErrIdentifyRejected = errors.New("personal access token rejected")
+
 // IdentifyUser extracts the caller's User CR name from r's bearer
 // token, using the same auth schemes as ServeHTTP (static token,
-// OIDC). Used by hub REST endpoints behind the tenant middleware so
-// they can identify the caller without duplicating the proxy's auth
-// dispatch.
+// OIDC, personal access token). Used by hub REST endpoints behind the
+// tenant middleware so they can identify the caller without duplicating
+// the proxy's auth dispatch.
 //
 // Returns ErrIdentifyNoBearer for missing/unparseable Authorization
-// headers, and other errors for verification failures. kcp
-// ServiceAccount tokens are intentionally not accepted here — REST
-// endpoints are addressed by humans (or by their portal session) and
-// not by edge-side bots.
+// headers, ErrIdentifyRejected for personal access tokens it refuses,
+// and other errors for verification failures. kcp ServiceAccount
+// tokens are intentionally not accepted here — REST endpoints are
+// addressed by humans (or by their portal session) and not by
+// edge-side bots. Personal access tokens identify their owner on reads
+// only, so a leaked token can neither change the account nor mint
+// further tokens.
 func (p *KCPProxy) IdentifyUser(r *http.Request) (string, error) {
 	authHeader := r.Header.Get("Authorization")
 	if !strings.HasPrefix(authHeader, "Bearer ") {
@@ -826,9 +1019,20 @@ func (p *KCPProxy) IdentifyUser(r *http.Request) (string, error) {
 		}
 	}
 
+	if id, ok := auth.ParsePersonalAccessToken(token); ok {
+		if !readonly.IsRead(r) {
+			return "", fmt.Errorf("%w: read-only here", ErrIdentifyRejected)
+		}
+		_, user, err := p.verifyPersonalAccessToken(r.Context(), token, id, time.Now())
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrIdentifyRejected, err)
+		}
+		return user.Name, nil
+	}
+
 	// OIDC branch.
-	if p.verifier != nil {
-		idToken, err := p.verifier.Verify(p.verifyCtx, token)
+	if len(p.verifiers) > 0 {
+		idToken, err := auth.VerifyIDTokenWithAny(p.verifyCtx, p.verifiers, token, verifyEndpointIdentify)
 		if err != nil {
 			return "", fmt.Errorf("verifying OIDC token: %w", err)
 		}
@@ -848,6 +1052,30 @@ func (p *KCPProxy) IdentifyUser(r *http.Request) (string, error) {
 	return "", ErrIdentifyNoBearer
 }
 
+// RequireStepUp holds r to the step-up policy and reports whether it may
+// proceed; when it may not, the refusal has been written. As for edge
+// deletion, only OIDC ID tokens are subject to step-up: static tokens pass.
+func (p *KCPProxy) RequireStepUp(w http.ResponseWriter, r *http.Request, operation string) bool {
+	if !p.stepUp.Enabled() {
+		return true
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	for _, staticToken := range p.staticAuthTokens {
+		if staticToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(staticToken)) == 1 {
+			return true
+		}
+	}
+	if len(p.verifiers) > 0 {
+		idToken, err := auth.VerifyIDTokenWithAny(p.verifyCtx, p.verifiers, token, verifyEndpointIdentify)
+		if err == nil && p.stepUp.Satisfied(idToken, time.Now()) {
+			return true
+		}
+	}
+	p.logger.Info("refused: step-up required", "operation", operation)
+	p.stepUp.WriteStepUpRequired(w, r, operation)
+	return false
+}
+
 // resolveUser looks up the User CRD by OIDC issuer+sub hash and returns the full User object.
 func (p *KCPProxy) resolveUser(ctx context.Context, issuer, sub string) (*tenancyv1alpha1.User, error) {
 	hash := sha256.Sum256([]byte(issuer + "/" + sub))
@@ -921,19 +1149,21 @@ func (p *KCPProxy) HandleTokenLoginRateLimited(w http.ResponseWriter, r *http.Re
 // when registering routes to prevent brute force attacks.
 func (p *KCPProxy) HandleTokenLogin(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusMethodNotAllowed)
-		_, _ = fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","metadata":{},"status":"Failure","message":"Method not allowed","reason":"MethodNotAllowed","code":405}`)
+		problem.Write(w, r, http.StatusMethodNotAllowed, problem.ReasonMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Extract bearer token.
 	authHeader := r.Header.Get("Authorization")
 	if !strings.HasPrefix(authHeader, "Bearer ") {
-		writeUnauthorized(w)
+		writeUnauthorized(w, r)
 		return
 	}
 	token := strings.TrimPrefix(authHeader, "Bearer ")
+	if wait, ok := p.lockout.Check(r, token, lockoutEndpointTokenLogin); !ok {
+		problem.WriteTooManyRequests(w, r, wait)
+		return
+	}
 
 	// Validate token against static tokens.
 	// Use constant-time comparison to prevent timing side-channel attacks.
@@ -945,7 +1175,7 @@ func (p *KCPProxy) HandleTokenLogin(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 	if !validToken {
-		writeUnauthorized(w)
+		p.rejectCredential(w, r, token, lockoutEndpointTokenLogin)
 		return
 	}
 
@@ -959,9 +1189,7 @@ func (p *KCPProxy) HandleTokenLogin(w http.ResponseWriter, r *http.Request) {
 	user, err := p.ensureStaticTokenUser(ctx, token, subHash)
 	if err != nil {
 		p.logger.Error(err, "failed to ensure static token user")
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		_, _ = fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","metadata":{},"status":"Failure","message":"failed to create user","reason":"InternalError","code":500}`)
+		problem.Write(w, r, http.StatusInternalServerError, problem.ReasonInternalError, "failed to create user")
 		return
 	}
 	// Wait for the bootstrap controller to populate DefaultCluster so
@@ -974,9 +1202,7 @@ func (p *KCPProxy) HandleTokenLogin(w http.ResponseWriter, r *http.Request) {
 	kubeconfigBytes, err := p.generateStaticTokenKubeconfig(user, token)
 	if err != nil {
 		p.logger.Error(err, "failed to generate kubeconfig")
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		_, _ = fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","metadata":{},"status":"Failure","message":"failed to generate kubeconfig","reason":"InternalError","code":500}`)
+		problem.Write(w, r, http.StatusInternalServerError, problem.ReasonInternalError, "failed to generate kubeconfig")
 		return
 	}
 
@@ -83,7 +83,7 @@ func TestExtractClusterPathFromKCPPath(t *testing.T) {
 // kedge-specific reason + a pointer at the hub REST surface for CLI tooling.
 func TestWriteOrgWorkspaceForbidden(t *testing.T) {
 	w := httptest.NewRecorder()
-	writeOrgWorkspaceForbidden(w)
+	writeOrgWorkspaceForbidden(w, httptest.NewRequest(http.MethodGet, "/clusters/root:kedge:tenants:org1/api/v1/pods", nil))
 
 	if w.Code != http.StatusForbidden {
 		t.Errorf("status: got %d, want %d", w.Code, http.StatusForbidden)
@@ -0,0 +1,202 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+
+	"github.com/faroshq/faros-kedge/pkg/hub/metrics"
+	"github.com/faroshq/faros-kedge/pkg/problem"
+)
+
+// Default request limits of the kcp proxy. They leave room for kubectl and
+// controller-runtime clients, which default to 20 QPS, to run side by side.
+const (
+	DefaultUserQPS        = 50
+	DefaultUserBurst      = 100
+	DefaultWorkspaceQPS   = 100
+	DefaultWorkspaceBurst = 200
+)
+
+const (
+	// Limit labels of the throttled requests metric.
+	limitUser      = "user"
+	limitWorkspace = "workspace"
+
+	// limiterSweepInterval is how often idle limiters are dropped; a limiter
+	// whose bucket has refilled is no different from a new one.
+	limiterSweepInterval = time.Minute
+)
+
+var throttledRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "kedge_hub",
+	Name:      "kcp_proxy_throttled_requests_total",
+	Help:      "Requests the kcp proxy refused with 429 for exceeding a request limit, by limit (user or workspace).",
+}, []string{"limit"})
+
+func init() {
+	metrics.Registry.MustRegister(throttledRequests)
+}
+
+// RequestLimits are the rates at which the kcp proxy forwards requests to kcp,
+// so one tenant running a tight control loop against the hub cannot starve
+// everyone else's access. QPS is the sustained rate and Burst the requests
+// allowed at once; a QPS of zero disables the limit. A watch counts as one
+// request however long it runs.
+type RequestLimits struct {
+	// UserQPS and UserBurst limit each caller: a User, whichever token it
+	// authenticates with, or a ServiceAccount token.
+	UserQPS   float64
+	UserBurst int
+	// WorkspaceQPS and WorkspaceBurst limit each workspace (logical
+	// cluster), across its callers. Requests with ServiceAccount tokens,
+	// which only kcp verifies, are not charged to the workspace their token
+	// claims, so a forged token cannot use up a workspace's budget.
+	WorkspaceQPS   float64
+	WorkspaceBurst int
+}
+
+// DefaultRequestLimits returns the default RequestLimits.
+func DefaultRequestLimits() RequestLimits {
+	return RequestLimits{
+		UserQPS:        DefaultUserQPS,
+		UserBurst:      DefaultUserBurst,
+		WorkspaceQPS:   DefaultWorkspaceQPS,
+		WorkspaceBurst: DefaultWorkspaceBurst,
+	}
+}
+
+// requestLimiter enforces RequestLimits.
+type requestLimiter struct {
+	user      *keyedLimiter
+	workspace *keyedLimiter
+}
+
+func newRequestLimiter(limits RequestLimits) *requestLimiter {
+	return &requestLimiter{
+		user:      newKeyedLimiter(limits.UserQPS, limits.UserBurst),
+		workspace: newKeyedLimiter(limits.WorkspaceQPS, limits.WorkspaceBurst),
+	}
+}
+
+// allow takes a request of user against workspace, either of which may be
+// empty to skip its limit, at now. When refused it returns the limit hit and
+// how long until a retry would be allowed; a refused request is charged to
+// neither limit.
+func (l *requestLimiter) allow(user, workspace string, now time.Time) (string, time.Duration, bool) {
+	if l == nil {
+		return "", 0, true
+	}
+	userRes, wait := l.user.reserve(user, now)
+	if wait > 0 {
+		return limitUser, wait, false
+	}
+	if _, wait := l.workspace.reserve(workspace, now); wait > 0 {
+		if userRes != nil {
+			userRes.CancelAt(now)
+		}
+		return limitWorkspace, wait, false
+	}
+	return "", 0, true
+}
+
+// keyedLimiter holds a token bucket per key.
+type keyedLimiter struct {
+	limit rate.Limit
+	burst int
+
+	mu        sync.Mutex
+	limiters  map[string]*rate.Limiter
+	lastSweep time.Time
+}
+
+// newKeyedLimiter returns a keyedLimiter allowing qps requests per second per
+// key with bursts of burst, or nil, allowing everything, when qps is zero.
+func newKeyedLimiter(qps float64, burst int) *keyedLimiter {
+	if qps <= 0 {
+		return nil
+	}
+	return &keyedLimiter{
+		limit:    rate.Limit(qps),
+		burst:    max(burst, 1),
+		limiters: map[string]*rate.Limiter{},
+	}
+}
+
+// reserve takes a request of key at now. It returns the reservation, to cancel
+// should the request be refused after all, or, when key is out of requests,
+// how long until it has one again. A nil limiter or an empty key allows the
+// request without a reservation.
+func (k *keyedLimiter) reserve(key string, now time.Time) (*rate.Reservation, time.Duration) {
+	if k == nil || key == "" {
+		return nil, 0
+	}
+	k.mu.Lock()
+	if now.Sub(k.lastSweep) >= limiterSweepInterval {
+		for key, l := range k.limiters {
+			if l.TokensAt(now) >= float64(k.burst) {
+				delete(k.limiters, key)
+			}
+		}
+		k.lastSweep = now
+	}
+	l, ok := k.limiters[key]
+	if !ok {
+		l = rate.NewLimiter(k.limit, k.burst)
+		k.limiters[key] = l
+	}
+	k.mu.Unlock()
+
+	res := l.ReserveN(now, 1)
+	if wait := res.DelayFrom(now); wait > 0 {
+		res.CancelAt(now)
+		return nil, wait
+	}
+	return res, 0
+}
+
+// serviceAccountLimitKey is the user limit key of a ServiceAccount token. The
+// hub cannot verify the token, so it is limited by itself rather than by the
+// subject it claims.
+func serviceAccountLimitKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "serviceaccount/" + hex.EncodeToString(sum[:8])
+}
+
+// throttle charges a request of user to workspace, the cluster segment of
+// the forwarded path, and, when it is over a limit, answers it with 429 and
+// returns true. Requests to an edge ({id}:{edge}) are charged to its
+// workspace. An empty user or workspace skips that limit.
+func (p *KCPProxy) throttle(w http.ResponseWriter, r *http.Request, user, workspace string) bool {
+	workspace, _, _ = strings.Cut(workspace, ":")
+	limit, wait, ok := p.limiter.allow(user, workspace, time.Now())
+	if ok {
+		return false
+	}
+	throttledRequests.WithLabelValues(limit).Inc()
+	p.logger.V(2).Info("request limit exceeded", "limit", limit, "user", user, "workspace", workspace, "path", r.URL.Path, "retryAfter", wait.String())
+	problem.WriteTooManyRequests(w, r, wait)
+	return true
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRequestLimiter(t *testing.T) {
+	l := newRequestLimiter(RequestLimits{UserQPS: 1, UserBurst: 2, WorkspaceQPS: 1, WorkspaceBurst: 3})
+	now := time.Now()
+
+	// alice spends her burst; bob still gets in until the workspace's
+	// burst is spent.
+	for i := range 2 {
+		if _, _, ok := l.allow("alice", "ws-a", now); !ok {
+			t.Fatalf("alice request %d refused within burst", i)
+		}
+	}
+	limit, wait, ok := l.allow("alice", "ws-a", now)
+	if ok || limit != limitUser || wait <= 0 || wait > time.Second {
+		t.Fatalf("alice over burst: limit=%q wait=%s ok=%v", limit, wait, ok)
+	}
+	if _, _, ok := l.allow("bob", "ws-a", now); !ok {
+		t.Fatal("bob refused with workspace budget left")
+	}
+	if limit, _, ok := l.allow("bob", "ws-a", now); ok || limit != limitWorkspace {
+		t.Fatalf("bob over workspace burst: limit=%q ok=%v", limit, ok)
+	}
+
+	// A request refused by the workspace limit is not charged to its user:
+	// bob has one request left for another workspace.
+	if _, _, ok := l.allow("bob", "ws-b", now); !ok {
+		t.Fatal("bob charged for a refused request")
+	}
+	if _, _, ok := l.allow("bob", "ws-b", now); ok {
+		t.Fatal("bob allowed over the user burst")
+	}
+
+	// Budgets refill at QPS, and an empty workspace skips its limit.
+	now = now.Add(time.Second)
+	if _, _, ok := l.allow("alice", "ws-a", now); !ok {
+		t.Fatal("alice refused after refill")
+	}
+	if _, _, ok := l.allow("carol", "", now); !ok {
+		t.Fatal("carol refused without a workspace")
+	}
+}
+
+func TestRequestLimiterDisabled(t *testing.T) {
+	var nilLimiter *requestLimiter
+	if _, _, ok := nilLimiter.allow("alice", "ws-a", time.Now()); !ok {
+		t.Fatal("nil limiter refused a request")
+	}
+	l := newRequestLimiter(RequestLimits{UserQPS: 0, WorkspaceQPS: 1, WorkspaceBurst: 1})
+	now := time.Now()
+	for i := range 5 {
+		if _, _, ok := l.allow("alice", "", now); !ok {
+			t.Fatalf("request %d refused with the user limit disabled", i)
+		}
+	}
+}
+
+func TestKeyedLimiterSweep(t *testing.T) {
+	k := newKeyedLimiter(10, 1)
+	now := time.Now()
+	k.reserve("a", now)
+	k.reserve("b", now)
+	// After a sweep interval both buckets are full again and dropped; only
+	// the key taking the request remains.
+	k.reserve("c", now.Add(limiterSweepInterval))
+	if len(k.limiters) != 1 {
+		t.Fatalf("limiters after sweep = %d, want 1", len(k.limiters))
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/klog/v2"
+
+	kedgeclient "github.com/faroshq/faros-kedge/pkg/client"
+)
+
+// TestEnsureStaticTokenUserSharesConcurrentBootstrap checks that a burst of
+// first logins with the same static token issues a single List/Create
+// against kcp, with every caller receiving the same User.
+func TestEnsureStaticTokenUserSharesConcurrentBootstrap(t *testing.T) {
+	dyn := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		kedgeclient.UserGVR: "UserList",
+	})
+
+	var lists, creates atomic.Int32
+	listing := make(chan struct{})
+	release := make(chan struct{})
+	dyn.PrependReactor("list", "users", func(clienttesting.Action) (bool, runtime.Object, error) {
+		if lists.Add(1) == 1 {
+			close(listing)
+		}
+		<-release
+		return false, nil, nil
+	})
+	dyn.PrependReactor("create", "users", func(clienttesting.Action) (bool, runtime.Object, error) {
+		creates.Add(1)
+		return false, nil, nil
+	})
+
+	p := &KCPProxy{
+		kedgeClient: kedgeclient.NewFromDynamic(dyn),
+		logger:      klog.Background(),
+	}
+
+	const callers = 8
+	const subHash = "0123456789abcdef0123456789abcdef"
+	names := make([]string, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	call := func(i int) {
+		defer wg.Done()
+		user, err := p.ensureStaticTokenUser(context.Background(), "dev-token", subHash)
+		errs[i] = err
+		if user != nil {
+			names[i] = user.Name
+		}
+	}
+
+	wg.Add(callers)
+	go call(0)
+	<-listing
+	for i := 1; i < callers; i++ {
+		go call(i)
+	}
+	// Give the remaining callers time to join the in-flight bootstrap.
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for i := range callers {
+		if errs[i] != nil {
+			t.Fatalf("caller %d: %v", i, errs[i])
+		}
+		if names[i] != "static-user-0123456789abcdef" {
+			t.Errorf("caller %d got user %q", i, names[i])
+		}
+	}
+	if got := lists.Load(); got != 1 {
+		t.Errorf("lists = %d, want 1", got)
+	}
+	if got := creates.Load(); got != 1 {
+		t.Errorf("creates = %d, want 1", got)
+	}
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package httpcompress negotiates response compression for the hub's and the
+// agent's reverse proxies. Wrapping a proxy's transport, it passes an
+// upstream gzip or deflate response through when the client accepts that
+// encoding, decodes it when the client does not, and compresses uncompressed
+// API responses (JSON, YAML, protobuf, text) for clients that accept gzip or
+// deflate, so large lists do not cross the WAN uncompressed.
+package httpcompress
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Content encodings the transport produces and decodes.
+const (
+	Gzip    = "gzip"
+	Deflate = "deflate"
+)
+
+// MinSize is the smallest response, by Content-Length, that is compressed.
+// Responses of unknown length, like watches and chunked lists, always are.
+const MinSize = 1024
+
+// compressibleTypes are the media types worth compressing.
+var compressibleTypes = []string{
+	"application/json",
+	"application/yaml",
+	"application/vnd.kubernetes.protobuf",
+	"application/apply-patch+yaml",
+}
+
+// NewTransport wraps next with content-encoding negotiation against the
+// Accept-Encoding header of each request sent through it. next must not
+// decode responses itself for requests that carry Accept-Encoding, which
+// holds for http.Transport and the client-go transports built on it.
+func NewTransport(next http.RoundTripper) http.RoundTripper {
+	return &transport{next: next}
+}
+
+type transport struct {
+	next http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || req.Method == http.MethodHead || !hasBody(resp) {
+		return resp, err
+	}
+	accept := parseAcceptEncoding(req.Header.Get("Accept-Encoding"))
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	switch {
+	case encoding == "" || encoding == "identity":
+		if preferred := accept.preferred(); preferred != "" && compressible(resp) {
+			resp.Body = encode(resp.Body, preferred)
+			resp.Header.Set("Content-Encoding", preferred)
+			setStreamed(resp)
+			if !strings.Contains(strings.Join(resp.Header.Values("Vary"), ","), "Accept-Encoding") {
+				resp.Header.Add("Vary", "Accept-Encoding")
+			}
+		}
+	case accept.allows(encoding):
+		// Pass the upstream encoding through.
+	case encoding == Gzip || encoding == Deflate:
+		body, err := decode(resp.Body, encoding)
+		if err != nil {
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("decoding %s response: %w", encoding, err)
+		}
+		resp.Body = body
+		resp.Header.Del("Content-Encoding")
+		setStreamed(resp)
+		resp.Uncompressed = true
+	}
+	return resp, nil
+}
+
+// hasBody reports whether resp may carry a body to re-encode.
+func hasBody(resp *http.Response) bool {
+	switch {
+	case resp.StatusCode < http.StatusOK,
+		resp.StatusCode == http.StatusNoContent,
+		resp.StatusCode == http.StatusNotModified,
+		resp.Header.Get("Content-Range") != "":
+		return false
+	}
+	return true
+}
+
+// compressible reports whether resp is an uncompressed API response worth
+// compressing.
+func compressible(resp *http.Response) bool {
+	if resp.ContentLength >= 0 && resp.ContentLength < MinSize {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	if strings.HasPrefix(mediaType, "text/") && mediaType != "text/event-stream" {
+		return true
+	}
+	for _, t := range compressibleTypes {
+		if mediaType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// setStreamed drops the length of a response whose body was re-encoded.
+func setStreamed(resp *http.Response) {
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+}
+
+// acceptEncoding is a parsed Accept-Encoding header: the quality of each
+// listed coding, with "*" covering the unlisted ones.
+type acceptEncoding map[string]float64
+
+func parseAcceptEncoding(header string) acceptEncoding {
+	accept := acceptEncoding{}
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding == "" {
+			continue
+		}
+		q := 1.0
+		if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(name) == "q" {
+			if v, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				q = v
+			}
+		}
+		accept[coding] = q
+	}
+	return accept
+}
+
+func (a acceptEncoding) allows(coding string) bool {
+	if q, ok := a[coding]; ok {
+		return q > 0
+	}
+	return a["*"] > 0
+}
+
+// preferred returns the encoding to compress with: gzip unless the client
+// ranks deflate higher, or "" when it accepts neither.
+func (a acceptEncoding) preferred() string {
+	quality := func(coding string) float64 {
+		if q, ok := a[coding]; ok {
+			return q
+		}
+		return a["*"]
+	}
+	gz, fl := quality(Gzip), quality(Deflate)
+	switch {
+	case gz > 0 && gz >= fl:
+		return Gzip
+	case fl > 0:
+		return Deflate
+	}
+	return ""
+}
+
+// flushWriter is a compressor that can flush what it has buffered.
+type flushWriter interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// encode returns body compressed with encoding. Each chunk read from body is
+// flushed, so watch events reach the client as they arrive.
+func encode(body io.ReadCloser, encoding string) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		var zw flushWriter
+		if encoding == Deflate {
+			zw = zlib.NewWriter(pw)
+		} else {
+			zw = gzip.NewWriter(pw)
+		}
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := body.Read(buf)
+			if n > 0 {
+				if _, werr := zw.Write(buf[:n]); werr != nil {
+					err = werr
+				} else if ferr := zw.Flush(); ferr != nil {
+					err = ferr
+				}
+			}
+			if err != nil {
+				if err == io.EOF {
+					err = zw.Close()
+				}
+				_ = body.Close()
+				_ = pw.CloseWithError(err)
+				return
+			}
+		}
+	}()
+	return &encodedBody{PipeReader: pr, upstream: body}
+}
+
+// encodedBody is the read side of encode. Closing it also closes the upstream
+// body, which unblocks a compressor waiting on an idle watch.
+type encodedBody struct {
+	*io.PipeReader
+	upstream io.Closer
+}
+
+func (b *encodedBody) Close() error {
+	_ = b.upstream.Close()
+	return b.PipeReader.Close()
+}
+
+// decode returns body decompressed from encoding.
+func decode(body io.ReadCloser, encoding string) (io.ReadCloser, error) {
+	var r io.ReadCloser
+	var err error
+	if encoding == Deflate {
+		r, err = zlib.NewReader(body)
+	} else {
+		r, err = gzip.NewReader(body)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &decodedBody{ReadCloser: r, upstream: body}, nil
+}
+
+// decodedBody closes the decompressor and the upstream body.
+type decodedBody struct {
+	io.ReadCloser
+	upstream io.Closer
+}
+
+func (b *decodedBody) Close() error {
+	_ = b.ReadCloser.Close()
+	return b.upstream.Close()
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpcompress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func upstream(contentType, encoding string, body []byte) http.RoundTripper {
+	return roundTripFunc(func(*http.Request) (*http.Response, error) {
+		h := http.Header{"Content-Type": {contentType}}
+		if encoding != "" {
+			h.Set("Content-Encoding", encoding)
+		}
+		return &http.Response{StatusCode: http.StatusOK, Header: h,
+			Body: io.NopCloser(bytes.NewReader(body)), ContentLength: int64(len(body))}, nil
+	})
+}
+
+func roundTrip(t *testing.T, rt http.RoundTripper, acceptEncoding string) (*http.Response, []byte) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, "http://kcp/api/v1/pods", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	resp, err := NewTransport(rt).RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, body
+}
+
+func gzipped(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, _ = zw.Write(data)
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestTransport(t *testing.T) {
+	list := []byte(`{"kind":"PodList","items":[` + strings.Repeat(`{"kind":"Pod"},`, 200) + `{}]}`)
+
+	t.Run("compresses for gzip clients", func(t *testing.T) {
+		resp, body := roundTrip(t, upstream("application/json", "", list), "gzip, deflate")
+		if resp.Header.Get("Content-Encoding") != Gzip || resp.ContentLength != -1 {
+			t.Fatalf("Content-Encoding = %q, length %d", resp.Header.Get("Content-Encoding"), resp.ContentLength)
+		}
+		zr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := io.ReadAll(zr); !bytes.Equal(got, list) {
+			t.Fatalf("decompressed body differs")
+		}
+		if len(body) >= len(list) {
+			t.Errorf("compressed %d bytes to %d", len(list), len(body))
+		}
+	})
+
+	t.Run("compresses with deflate when preferred", func(t *testing.T) {
+		resp, body := roundTrip(t, upstream("application/json", "", list), "gzip;q=0.5, deflate")
+		if resp.Header.Get("Content-Encoding") != Deflate {
+			t.Fatalf("Content-Encoding = %q", resp.Header.Get("Content-Encoding"))
+		}
+		zr, err := zlib.NewReader(bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := io.ReadAll(zr); !bytes.Equal(got, list) {
+			t.Fatalf("decompressed body differs")
+		}
+	})
+
+	t.Run("leaves small, binary and unrequested responses alone", func(t *testing.T) {
+		for _, tt := range []struct {
+			contentType, accept string
+			body                []byte
+		}{
+			{"application/json", "gzip", []byte(`{"kind":"Status"}`)},
+			{"application/octet-stream", "gzip", list},
+			{"application/json", "", list},
+			{"application/json", "gzip;q=0", list},
+		} {
+			resp, body := roundTrip(t, upstream(tt.contentType, "", tt.body), tt.accept)
+			if resp.Header.Get("Content-Encoding") != "" || !bytes.Equal(body, tt.body) {
+				t.Errorf("%s with Accept-Encoding %q was re-encoded", tt.contentType, tt.accept)
+			}
+		}
+	})
+
+	t.Run("passes upstream gzip through", func(t *testing.T) {
+		compressed := gzipped(t, list)
+		resp, body := roundTrip(t, upstream("application/json", Gzip, compressed), "gzip")
+		if resp.Header.Get("Content-Encoding") != Gzip || !bytes.Equal(body, compressed) {
+			t.Fatalf("upstream gzip not passed through")
+		}
+	})
+
+	t.Run("decodes gzip for clients that do not accept it", func(t *testing.T) {
+		resp, body := roundTrip(t, upstream("application/json", Gzip, gzipped(t, list)), "identity")
+		if resp.Header.Get("Content-Encoding") != "" || !bytes.Equal(body, list) {
+			t.Fatalf("Content-Encoding = %q, body decoded %v", resp.Header.Get("Content-Encoding"), bytes.Equal(body, list))
+		}
+	})
+}
+
+// TestTransportStreams checks that each chunk of a watch reaches the client
+// before the next one is written upstream.
+func TestTransportStreams(t *testing.T) {
+	pr, pw := io.Pipe()
+	rt := roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, ContentLength: -1,
+			Header: http.Header{"Content-Type": {"application/json;stream=watch"}}, Body: pr}, nil
+	})
+	req, _ := http.NewRequest(http.MethodGet, "http://kcp/api/v1/pods?watch=true", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := NewTransport(rt).RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	event := `{"type":"ADDED","object":{"kind":"Pod"}}` + "\n"
+	go func() { _, _ = pw.Write([]byte(event)) }()
+	zr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]byte, len(event))
+	done := make(chan error, 1)
+	go func() {
+		_, err := io.ReadFull(zr, got)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil || string(got) != event {
+			t.Fatalf("read %q, %v", got, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch event not flushed")
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version skew policy. The hub is the reference: CLI and agent versions are
+// measured against it in releases. A release is a minor version, or a patch
+// version while the project is still on v0.0.z, where every patch may change
+// the wire contract.
+const (
+	// MaxCLISkew is how many releases the CLI may be ahead of or behind the
+	// hub.
+	MaxCLISkew = 1
+	// MaxAgentLag is how many releases an agent may be behind the hub.
+	// Agents newer than the hub are never supported: the hub must roll out
+	// first.
+	MaxAgentLag = 2
+)
+
+// SkewStatus classifies a component version against the hub's.
+type SkewStatus string
+
+const (
+	// SkewSupported: the combination is within policy.
+	SkewSupported SkewStatus = "ok"
+	// SkewUnsupported: the combination is outside policy.
+	SkewUnsupported SkewStatus = "unsupported"
+	// SkewUnknown: one side is a dev build or did not report a version.
+	SkewUnknown SkewStatus = "unknown"
+)
+
+// Skew is the outcome of a skew check with a human-readable reason.
+type Skew struct {
+	Status SkewStatus
+	Reason string
+}
+
+// CheckCLISkew checks a CLI version against the hub version.
+func CheckCLISkew(cli, hub string) Skew {
+	d, s, ok := releaseDistance(cli, hub)
+	if !ok {
+		return s
+	}
+	switch {
+	case d > MaxCLISkew:
+		return Skew{SkewUnsupported, fmt.Sprintf("CLI is %d releases behind the hub (supported: %d); upgrade the CLI", d, MaxCLISkew)}
+	case -d > MaxCLISkew:
+		return Skew{SkewUnsupported, fmt.Sprintf("CLI is %d releases ahead of the hub (supported: %d)", -d, MaxCLISkew)}
+	}
+	return Skew{Status: SkewSupported}
+}
+
+// CheckAgentSkew checks an agent version against the hub version.
+func CheckAgentSkew(agent, hub string) Skew {
+	d, s, ok := releaseDistance(agent, hub)
+	if !ok {
+		return s
+	}
+	switch {
+	case d < 0:
+		return Skew{SkewUnsupported, "agent is newer than the hub; upgrade the hub first"}
+	case d > MaxAgentLag:
+		return Skew{SkewUnsupported, fmt.Sprintf("agent is %d releases behind the hub (supported: %d); upgrade the agent", d, MaxAgentLag)}
+	}
+	return Skew{Status: SkewSupported}
+}
+
+// releaseDistance returns how many releases v is behind ref (negative when
+// ahead). When the two cannot be compared it returns ok=false and the Skew to
+// report instead.
+func releaseDistance(v, ref string) (d int, s Skew, ok bool) {
+	if v == "" {
+		return 0, Skew{SkewUnknown, "version not reported"}, false
+	}
+	rv, okV := parseRelease(v)
+	rr, okRef := parseRelease(ref)
+	if !okV || !okRef {
+		return 0, Skew{SkewUnknown, "development build; skew not checked"}, false
+	}
+	if rv[0] != rr[0] {
+		return 0, Skew{SkewUnsupported, fmt.Sprintf("major version v%d differs from the hub's v%d", rv[0], rr[0])}, false
+	}
+	if rv[0] == 0 && rv[1] == 0 && rr[1] == 0 {
+		return rr[2] - rv[2], Skew{}, true
+	}
+	return rr[1] - rv[1], Skew{}, true
+}
+
+// parseRelease parses "v1.2.3", ignoring any "-pre" or "+build" suffix.
+func parseRelease(v string) ([3]int, bool) {
+	var out [3]int
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) != 3 {
+		return out, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return out, false
+		}
+		out[i] = n
+	}
+	return out, true
+}
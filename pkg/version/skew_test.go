@@ -0,0 +1,64 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version
+
+import "testing"
+
+func TestCheckCLISkew(t *testing.T) {
+	tests := []struct {
+		cli, hub string
+		want     SkewStatus
+	}{
+		{"v0.0.28", "v0.0.28", SkewSupported},
+		{"v0.0.27", "v0.0.28", SkewSupported},
+		{"v0.0.29", "v0.0.28", SkewSupported},
+		{"v0.0.26", "v0.0.28", SkewUnsupported},
+		{"v0.0.30", "v0.0.28", SkewUnsupported},
+		{"v1.4.9", "v1.5.0", SkewSupported},
+		{"v1.3.0", "v1.5.2", SkewUnsupported},
+		{"v2.0.0", "v1.9.0", SkewUnsupported},
+		{"v0.0.28-rc.1", "v0.0.28", SkewSupported},
+		{"dev", "v0.0.28", SkewUnknown},
+		{"v0.0.28", "dev", SkewUnknown},
+	}
+	for _, tt := range tests {
+		if got := CheckCLISkew(tt.cli, tt.hub); got.Status != tt.want {
+			t.Errorf("CheckCLISkew(%q, %q) = %+v, want %s", tt.cli, tt.hub, got, tt.want)
+		}
+	}
+}
+
+func TestCheckAgentSkew(t *testing.T) {
+	tests := []struct {
+		agent, hub string
+		want       SkewStatus
+	}{
+		{"v0.0.28", "v0.0.28", SkewSupported},
+		{"v0.0.26", "v0.0.28", SkewSupported},
+		{"v0.0.25", "v0.0.28", SkewUnsupported},
+		{"v0.0.29", "v0.0.28", SkewUnsupported},
+		{"v0.0.28", "v0.1.0", SkewSupported},
+		{"v1.1.0", "v1.3.5", SkewSupported},
+		{"v1.0.0", "v1.3.0", SkewUnsupported},
+		{"", "v0.0.28", SkewUnknown},
+	}
+	for _, tt := range tests {
+		if got := CheckAgentSkew(tt.agent, tt.hub); got.Status != tt.want {
+			t.Errorf("CheckAgentSkew(%q, %q) = %+v, want %s", tt.agent, tt.hub, got, tt.want)
+		}
+	}
+}
@@ -30,14 +30,15 @@ import { authFetch } from '@/auth/session'
 
 const STORAGE_KEY = 'kedge:portal:tenant'
 
-// statusMessage pulls the human-readable `.message` out of the hub's
-// kube-style Status envelope (see restapi.writeStatus) so callers can
+// statusMessage pulls the human-readable text out of the hub's error body —
+// `.message` of a kube-style Status envelope (see restapi.writeStatus) or
+// `.detail` of a problem+json body (see pkg/problem) — so callers can
 // surface the real reason instead of a bare HTTP code. Returns '' when
-// the body isn't a Status envelope or can't be parsed.
+// the body is neither or can't be parsed.
 async function statusMessage(resp: Response): Promise<string> {
   try {
-    const data = (await resp.clone().json()) as { message?: string }
-    return data?.message ?? ''
+    const data = (await resp.clone().json()) as { message?: string; detail?: string }
+    return data?.message ?? data?.detail ?? ''
   } catch {
     return ''
   }
@@ -55,14 +55,48 @@ import (
 // containing the Dialer's random unique ID.
 const dialerUniqParam = "revdial.dialer"
 
-// dialerPingInterval is used to ensure we are sending constant pings
-const dialerPingInterval = 18 * time.Second
+// DefaultKeepaliveInterval is how often each side pings the other when
+// Keepalive.Interval is zero.
+const DefaultKeepaliveInterval = 18 * time.Second
+
+// DefaultKeepaliveTimeout is how long each side waits for a control message
+// before considering the tunnel dead when Keepalive.Timeout is zero. It must
+// be comfortably larger than the interval to tolerate network jitter and
+// Cloudflare proxy buffering.
+const DefaultKeepaliveTimeout = 60 * time.Second
+
+// Keepalive tunes dead-peer detection on a tunnel's control connection. The
+// Dialer sends "keep-alive" and the Listener "ping" every Interval; the peer
+// answers each with "pong". A side that hears nothing for Timeout closes the
+// tunnel, so a half-open connection (e.g. a NAT mapping that expired) is
+// detected within Timeout instead of on the next failed dial.
+//
+// A Listener only applies a Timeout shorter than DefaultKeepaliveTimeout once
+// the Dialer has answered a ping: older Dialers do not answer pings and only
+// send keep-alives every DefaultKeepaliveInterval.
+type Keepalive struct {
+	// Interval between pings. Zero uses DefaultKeepaliveInterval.
+	Interval time.Duration
+	// Timeout without any control message after which the peer is
+	// considered dead. Zero uses DefaultKeepaliveTimeout.
+	Timeout time.Duration
+	// TCPUserTimeout bounds how long data written to the tunnel's TCP
+	// connection may stay unacknowledged before the kernel drops it (Linux
+	// TCP_USER_TIMEOUT). revdial only sees the WebSocket, so callers apply it
+	// to the connection they dial or accept with TCPUserTimeoutControl or
+	// SetTCPUserTimeout. Zero leaves the system default.
+	TCPUserTimeout time.Duration
+}
 
-// listenerReadTimeout is how long the hub-side Listener waits for a control
-// message (keep-alive or conn-ready) before considering the tunnel dead.
-// It must be comfortably larger than dialerPingInterval to tolerate network
-// jitter and Cloudflare proxy buffering.
-const listenerReadTimeout = 60 * time.Second
+func (k Keepalive) withDefaults() Keepalive {
+	if k.Interval <= 0 {
+		k.Interval = DefaultKeepaliveInterval
+	}
+	if k.Timeout <= 0 {
+		k.Timeout = DefaultKeepaliveTimeout
+	}
+	return k
+}
 
 // The Dialer can create new connections.
 type Dialer struct {
@@ -76,11 +110,16 @@ type Dialer struct {
 	connReady    chan bool
 	donec        chan struct{}
 	closeOnce    sync.Once
+	keepalive    Keepalive
+
+	// writeMu serializes control messages: the read loop answers pings while
+	// serve sends keep-alives and conn-ready requests.
+	writeMu sync.Mutex
 
 	// lastPongMu guards lastPong, which records the time of the most recent
-	// "pong" received from the peer. Callers (e.g. hub heartbeat reporters)
-	// use this as a positive liveness signal: a recent pong means the tunnel
-	// was end-to-end healthy at that moment.
+	// "pong" (or "ping") received from the peer. Callers (e.g. hub heartbeat
+	// reporters) use this as a positive liveness signal: a recent pong means
+	// the tunnel was end-to-end healthy at that moment.
 	lastPongMu sync.RWMutex
 	lastPong   time.Time
 }
@@ -97,6 +136,11 @@ var (
 // without scheme or host) on the dialer where the ConnHandler is
 // mounted.
 func NewDialer(c net.Conn, connPath string) *Dialer {
+	return NewDialerWithKeepalive(c, connPath, Keepalive{})
+}
+
+// NewDialerWithKeepalive is NewDialer with tuned dead-peer detection.
+func NewDialerWithKeepalive(c net.Conn, connPath string, keepalive Keepalive) *Dialer {
 	d := &Dialer{
 		path:         connPath,
 		uniqID:       newUniqID(),
@@ -105,6 +149,7 @@ func NewDialer(c net.Conn, connPath string) *Dialer {
 		connReady:    make(chan bool),
 		incomingConn: make(chan net.Conn),
 		pickupFailed: make(chan error),
+		keepalive:    keepalive.withDefaults(),
 		// Seed lastPong with creation time: we only enter NewDialer after a
 		// successful WebSocket upgrade, so the peer was alive a moment ago.
 		lastPong: time.Now(),
@@ -217,7 +262,7 @@ func (d *Dialer) serve() error {
 			// Apply a read deadline so the agent detects a dead hub-side
 			// connection (e.g. Cloudflare silently dropped it). We expect
 			// at least a "pong" response within this window.
-			_ = d.conn.SetReadDeadline(time.Now().Add(listenerReadTimeout))
+			_ = d.conn.SetReadDeadline(time.Now().Add(d.keepalive.Timeout))
 			line, err := br.ReadSlice('\n')
 			if err != nil {
 				log.Printf("revdial.Dialer: read error (tunnel dead?): %v", err)
@@ -232,9 +277,15 @@ func (d *Dialer) serve() error {
 			case "pong":
 				// Peer confirmed it is alive — record the timestamp so callers
 				// can use it as a positive liveness signal.
-				d.lastPongMu.Lock()
-				d.lastPong = time.Now()
-				d.lastPongMu.Unlock()
+				d.markAlive()
+			case "ping":
+				// The Listener checks on us; answering lets it detect a dead
+				// tunnel within its own timeout.
+				d.markAlive()
+				if err := d.sendMessage(controlMsg{Command: "pong"}); err != nil {
+					log.Printf("revdial.Dialer: write error answering ping: %v", err)
+					return
+				}
 			case "pickup-failed":
 				err := fmt.Errorf("revdial listener failed to pick up connection: %v", msg.Err)
 				select {
@@ -250,7 +301,7 @@ func (d *Dialer) serve() error {
 			return err
 		}
 
-		t := time.NewTimer(dialerPingInterval)
+		t := time.NewTimer(d.keepalive.Interval)
 		select {
 		case <-t.C:
 			continue
@@ -269,11 +320,19 @@ func (d *Dialer) serve() error {
 	}
 }
 
+func (d *Dialer) markAlive() {
+	d.lastPongMu.Lock()
+	d.lastPong = time.Now()
+	d.lastPongMu.Unlock()
+}
+
 func (d *Dialer) sendMessage(m controlMsg) error {
 	j, err := json.Marshal(m)
 	if err != nil {
 		return err
 	}
+	d.writeMu.Lock()
+	defer d.writeMu.Unlock()
 	_ = d.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
 	j = append(j, '\n')
 	_, err = d.conn.Write(j)
@@ -288,11 +347,18 @@ func (d *Dialer) sendMessage(m controlMsg) error {
 // The provided dialServer func is responsible for connecting back to
 // the server and doing TLS setup.
 func NewListener(serverConn net.Conn, dialServer func(context.Context, string) (*websocket.Conn, *http.Response, error)) *Listener {
+	return NewListenerWithKeepalive(serverConn, dialServer, Keepalive{})
+}
+
+// NewListenerWithKeepalive is NewListener with tuned dead-peer detection: it
+// also pings the Dialer every keepalive.Interval.
+func NewListenerWithKeepalive(serverConn net.Conn, dialServer func(context.Context, string) (*websocket.Conn, *http.Response, error), keepalive Keepalive) *Listener {
 	ln := &Listener{
-		sc:    serverConn,
-		dial:  dialServer,
-		connc: make(chan net.Conn, 8), // arbitrary
-		donec: make(chan struct{}),
+		sc:        serverConn,
+		dial:      dialServer,
+		connc:     make(chan net.Conn, 8), // arbitrary
+		donec:     make(chan struct{}),
+		keepalive: keepalive.withDefaults(),
 	}
 	go ln.run()
 	return ln
@@ -309,13 +375,15 @@ type Listener struct {
 	dial   func(context.Context, string) (*websocket.Conn, *http.Response, error)
 	writec chan<- []byte
 
+	keepalive Keepalive
+
 	mu      sync.Mutex // guards below, closing connc, and writing to rw
 	readErr error
 	closed  bool
 }
 
 type controlMsg struct {
-	Command  string `json:"command,omitempty"`  // "keep-alive", "pong", "conn-ready", "pickup-failed"
+	Command  string `json:"command,omitempty"`  // "keep-alive", "ping", "pong", "conn-ready", "pickup-failed"
 	ConnPath string `json:"connPath,omitempty"` // conn pick-up URL path for "conn-url", "pickup-failed"
 	Err      string `json:"err,omitempty"`
 }
@@ -343,11 +411,33 @@ func (ln *Listener) run() {
 		}
 	}()
 
-	// Read loop — apply a read deadline so the hub detects dead tunnels even
-	// when the TCP connection is silently dropped (e.g. by Cloudflare).
+	// Ping loop — the Dialer's keep-alives alone leave a half-open tunnel
+	// undetected for up to DefaultKeepaliveTimeout.
+	go func() {
+		t := time.NewTicker(ln.keepalive.Interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-ln.donec:
+				return
+			case <-t.C:
+				ln.sendMessage(controlMsg{Command: "ping"})
+			}
+		}
+	}()
+
+	// Read loop — apply a read deadline so the agent detects dead tunnels even
+	// when the TCP connection is silently dropped (e.g. by Cloudflare). Until
+	// the Dialer answers a ping it may be one that never does, so keep the
+	// default timeout its keep-alives are paced for.
 	br := bufio.NewReader(ln.sc)
+	answersPings := false
 	for {
-		_ = ln.sc.SetReadDeadline(time.Now().Add(listenerReadTimeout))
+		timeout := ln.keepalive.Timeout
+		if !answersPings {
+			timeout = max(timeout, DefaultKeepaliveTimeout)
+		}
+		_ = ln.sc.SetReadDeadline(time.Now().Add(timeout))
 		line, err := br.ReadSlice('\n')
 		if err != nil {
 			log.Printf("revdial.Listener: read error (tunnel dead?): %v", err)
@@ -363,6 +453,8 @@ func (ln *Listener) run() {
 			// Agent is alive — send pong so the agent can also verify the
 			// connection is bidirectionally healthy.
 			ln.sendMessage(controlMsg{Command: "pong"})
+		case "pong":
+			answersPings = true
 		case "conn-ready":
 			go ln.grabConn(msg.ConnPath)
 		default:
@@ -377,7 +469,10 @@ func (ln *Listener) sendMessage(m controlMsg) {
 		return // Just return on error, can't send invalid json
 	}
 	j = append(j, '\n')
-	ln.writec <- j
+	select {
+	case ln.writec <- j:
+	case <-ln.donec:
+	}
 }
 
 func (ln *Listener) grabConn(path string) {
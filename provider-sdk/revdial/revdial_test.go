@@ -0,0 +1,134 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revdial
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+)
+
+// readControl reads control messages from c and calls handle for each until c
+// is closed.
+func readControl(c net.Conn, handle func(controlMsg)) {
+	br := bufio.NewReader(c)
+	for {
+		line, err := br.ReadSlice('\n')
+		if err != nil {
+			return
+		}
+		var msg controlMsg
+		if json.Unmarshal(line, &msg) == nil {
+			handle(msg)
+		}
+	}
+}
+
+func writeControl(t *testing.T, c net.Conn, m controlMsg) {
+	t.Helper()
+	j, _ := json.Marshal(m)
+	if _, err := c.Write(append(j, '\n')); err != nil {
+		t.Errorf("writing %q: %v", m.Command, err)
+	}
+}
+
+func TestDialerDetectsSilentPeer(t *testing.T) {
+	hubSide, agentSide := net.Pipe()
+	defer agentSide.Close() //nolint:errcheck
+
+	// The peer drains keep-alives but never answers, like the far end of a
+	// half-open connection.
+	go readControl(agentSide, func(controlMsg) {})
+
+	d := NewDialerWithKeepalive(hubSide, "/proxy", Keepalive{Interval: 10 * time.Millisecond, Timeout: 100 * time.Millisecond})
+	select {
+	case <-d.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Dialer did not close after its peer went silent")
+	}
+}
+
+func TestDialerAnswersPing(t *testing.T) {
+	hubSide, agentSide := net.Pipe()
+	defer agentSide.Close() //nolint:errcheck
+
+	d := NewDialerWithKeepalive(hubSide, "/proxy", Keepalive{})
+	defer d.Close() //nolint:errcheck
+
+	pongs := make(chan struct{}, 1)
+	go readControl(agentSide, func(m controlMsg) {
+		if m.Command == "pong" {
+			pongs <- struct{}{}
+		}
+	})
+	writeControl(t, agentSide, controlMsg{Command: "ping"})
+
+	select {
+	case <-pongs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Dialer did not answer ping")
+	}
+}
+
+func TestListenerDetectsSilentPeer(t *testing.T) {
+	agentSide, hubSide := net.Pipe()
+	defer hubSide.Close() //nolint:errcheck
+
+	// The peer answers the first ping, proving it understands pings, and is
+	// silent from then on.
+	answered := false
+	go readControl(hubSide, func(m controlMsg) {
+		if m.Command == "ping" && !answered {
+			answered = true
+			writeControl(t, hubSide, controlMsg{Command: "pong"})
+		}
+	})
+
+	ln := NewListenerWithKeepalive(agentSide, nil, Keepalive{Interval: 10 * time.Millisecond, Timeout: 100 * time.Millisecond})
+	accepted := make(chan error, 1)
+	go func() {
+		_, err := ln.Accept()
+		accepted <- err
+	}()
+
+	select {
+	case err := <-accepted:
+		if err == nil {
+			t.Fatal("Accept returned a connection, want an error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Listener did not close after its peer went silent")
+	}
+}
+
+func TestListenerKeepsDefaultTimeoutForPeerWithoutPings(t *testing.T) {
+	agentSide, hubSide := net.Pipe()
+	defer hubSide.Close() //nolint:errcheck
+
+	// An old Dialer ignores pings; the short timeout must not apply to it.
+	go readControl(hubSide, func(controlMsg) {})
+
+	ln := NewListenerWithKeepalive(agentSide, nil, Keepalive{Interval: 10 * time.Millisecond, Timeout: 20 * time.Millisecond})
+	defer ln.Close() //nolint:errcheck
+
+	time.Sleep(200 * time.Millisecond)
+	if ln.Closed() {
+		t.Fatal("Listener closed before DefaultKeepaliveTimeout for a peer that does not answer pings")
+	}
+}
@@ -0,0 +1,72 @@
+//go:build linux
+
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revdial
+
+import (
+	"net"
+	"syscall"
+	"time"
+)
+
+// tcpUserTimeout is TCP_USER_TIMEOUT from linux/tcp.h. The syscall package
+// does not define it on every architecture.
+const tcpUserTimeout = 0x12
+
+// TCPUserTimeoutControl returns a net.Dialer / net.ListenConfig Control func
+// that sets TCP_USER_TIMEOUT on every socket it creates. It returns nil when
+// timeout is zero so callers can assign it unconditionally.
+func TCPUserTimeoutControl(timeout time.Duration) func(network, address string, c syscall.RawConn) error {
+	if timeout <= 0 {
+		return nil
+	}
+	return func(_, _ string, c syscall.RawConn) error {
+		var sockErr error
+		if err := c.Control(func(fd uintptr) {
+			sockErr = setTCPUserTimeout(fd, timeout)
+		}); err != nil {
+			return err
+		}
+		return sockErr
+	}
+}
+
+// SetTCPUserTimeout sets TCP_USER_TIMEOUT on an already established
+// connection, e.g. one hijacked from an HTTP server. Connections that are not
+// TCP and a zero timeout are left untouched.
+func SetTCPUserTimeout(conn net.Conn, timeout time.Duration) error {
+	tc, ok := conn.(*net.TCPConn)
+	if !ok || timeout <= 0 {
+		return nil
+	}
+	rc, err := tc.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var sockErr error
+	if err := rc.Control(func(fd uintptr) {
+		sockErr = setTCPUserTimeout(fd, timeout)
+	}); err != nil {
+		return err
+	}
+	return sockErr
+}
+
+func setTCPUserTimeout(fd uintptr, timeout time.Duration) error {
+	return syscall.SetsockoptInt(int(fd), syscall.IPPROTO_TCP, tcpUserTimeout, int(timeout.Milliseconds()))
+}
@@ -0,0 +1,89 @@
+//go:build linux
+
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revdial
+
+import (
+	"net"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestTCPUserTimeoutControl(t *testing.T) {
+	if TCPUserTimeoutControl(0) != nil {
+		t.Error("TCPUserTimeoutControl(0) should be nil")
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close() //nolint:errcheck
+
+	d := net.Dialer{Control: TCPUserTimeoutControl(7 * time.Second)}
+	c, err := d.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close() //nolint:errcheck
+
+	if got := userTimeout(t, c); got != 7000 {
+		t.Errorf("TCP_USER_TIMEOUT = %dms, want 7000ms", got)
+	}
+}
+
+func TestSetTCPUserTimeout(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close() //nolint:errcheck
+
+	c, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close() //nolint:errcheck
+
+	if err := SetTCPUserTimeout(c, 3*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if got := userTimeout(t, c); got != 3000 {
+		t.Errorf("TCP_USER_TIMEOUT = %dms, want 3000ms", got)
+	}
+}
+
+func userTimeout(t *testing.T, c net.Conn) int {
+	t.Helper()
+	rc, err := c.(*net.TCPConn).SyscallConn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v int
+	var sockErr error
+	if err := rc.Control(func(fd uintptr) {
+		v, sockErr = syscall.GetsockoptInt(int(fd), syscall.IPPROTO_TCP, tcpUserTimeout)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if sockErr != nil {
+		t.Fatal(sockErr)
+	}
+	return v
+}
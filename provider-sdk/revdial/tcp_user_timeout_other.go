@@ -0,0 +1,36 @@
+//go:build !linux
+
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revdial
+
+import (
+	"net"
+	"syscall"
+	"time"
+)
+
+// TCPUserTimeoutControl returns nil: TCP_USER_TIMEOUT is Linux-only, so the
+// application-level keepalive is the only dead-peer detection elsewhere.
+func TCPUserTimeoutControl(time.Duration) func(network, address string, c syscall.RawConn) error {
+	return nil
+}
+
+// SetTCPUserTimeout is a no-op outside Linux.
+func SetTCPUserTimeout(net.Conn, time.Duration) error {
+	return nil
+}
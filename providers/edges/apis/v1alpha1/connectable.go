@@ -22,22 +22,26 @@ import (
 
 // Resource / URL path segments for the group's kinds.
 const (
-	KubernetesClusterResource = "kubernetesclusters"
-	LinuxServerResource       = "linuxservers"
-	WorkloadResource          = "workloads"
-	PlacementResource         = "placements"
-	ServiceResource           = "services"
+	KubernetesClusterResource   = "kubernetesclusters"
+	LinuxServerResource         = "linuxservers"
+	WorkloadResource            = "workloads"
+	PlacementResource           = "placements"
+	ServiceResource             = "services"
+	FleetCommandResource        = "fleetcommands"
+	EdgeAdoptionRequestResource = "edgeadoptionrequests"
 )
 
 // GVRs of the group's kinds (all in edges.kedge.faros.sh). The two connectable
 // kinds terminate agent tunnels; Workload/Placement drive workload
 // scheduling across KubernetesCluster edges.
 var (
-	KubernetesClusterGVR = SchemeGroupVersion.WithResource(KubernetesClusterResource)
-	LinuxServerGVR       = SchemeGroupVersion.WithResource(LinuxServerResource)
-	WorkloadGVR          = SchemeGroupVersion.WithResource(WorkloadResource)
-	PlacementGVR         = SchemeGroupVersion.WithResource(PlacementResource)
-	ServiceGVR           = SchemeGroupVersion.WithResource(ServiceResource)
+	KubernetesClusterGVR   = SchemeGroupVersion.WithResource(KubernetesClusterResource)
+	LinuxServerGVR         = SchemeGroupVersion.WithResource(LinuxServerResource)
+	WorkloadGVR            = SchemeGroupVersion.WithResource(WorkloadResource)
+	PlacementGVR           = SchemeGroupVersion.WithResource(PlacementResource)
+	ServiceGVR             = SchemeGroupVersion.WithResource(ServiceResource)
+	FleetCommandGVR        = SchemeGroupVersion.WithResource(FleetCommandResource)
+	EdgeAdoptionRequestGVR = SchemeGroupVersion.WithResource(EdgeAdoptionRequestResource)
 )
 
 // Correlation labels the scheduler stamps on Placements; the status aggregator
@@ -66,6 +70,12 @@ func (s *LinuxServer) GetConnectionStatus() *edgeapi.ConnectionStatus {
 	return &s.Status.ConnectionStatus
 }
 
+// IsCordoned reports spec.cordoned for the drain reconciler.
+func (c *KubernetesCluster) IsCordoned() bool { return c.Spec.Cordoned }
+
+// IsCordoned reports spec.cordoned.
+func (s *LinuxServer) IsCordoned() bool { return s.Spec.Cordoned }
+
 // NewKubernetesCluster / NewLinuxServer yield fresh instances as
 // edgeapi.Connectable, for edgectrl.SetupControllers (called once per kind).
 func NewKubernetesCluster() edgeapi.Connectable { return &KubernetesCluster{} }
@@ -52,6 +52,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&PlacementList{},
 		&Service{},
 		&ServiceList{},
+		&FleetCommand{},
+		&FleetCommandList{},
+		&EdgeAdoptionRequest{},
+		&EdgeAdoptionRequestList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -0,0 +1,100 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EdgeAdoptionPhase is the decision state of an EdgeAdoptionRequest.
+type EdgeAdoptionPhase string
+
+const (
+	// EdgeAdoptionPhasePending: awaiting an admin decision. An empty phase
+	// means the same.
+	EdgeAdoptionPhasePending EdgeAdoptionPhase = "Pending"
+	// EdgeAdoptionPhaseApproved: the edge was created; the agent registers.
+	EdgeAdoptionPhaseApproved EdgeAdoptionPhase = "Approved"
+	// EdgeAdoptionPhaseDenied: the agent gives up. Delete the request to let
+	// it ask again.
+	EdgeAdoptionPhaseDenied EdgeAdoptionPhase = "Denied"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=ear
+// +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="Hostname",type="string",JSONPath=".spec.hostname"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// EdgeAdoptionRequest is filed by an agent that starts before its edge exists
+// in a workspace where it may not create edges. An admin approves it with
+// `kedge edge approve <name>`, which creates the edge, or rejects it with
+// `kedge edge deny <name>`; the agent waits for the decision. This mirrors
+// kubelet TLS bootstrapping: agent identities only need create/get on
+// edgeadoptionrequests, not on the edge kinds themselves.
+//
+// The request is named after the edge it asks for.
+type EdgeAdoptionRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              EdgeAdoptionRequestSpec   `json:"spec,omitempty"`
+	Status            EdgeAdoptionRequestStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// EdgeAdoptionRequestList is a list of EdgeAdoptionRequest resources.
+type EdgeAdoptionRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EdgeAdoptionRequest `json:"items"`
+}
+
+// EdgeAdoptionRequestSpec describes the agent asking to be adopted.
+type EdgeAdoptionRequestSpec struct {
+	// Type is the agent type: "kubernetes" asks for a KubernetesCluster,
+	// "server" for a LinuxServer.
+	// +kubebuilder:validation:Enum=kubernetes;server
+	Type string `json:"type"`
+	// Labels the agent was started with; copied onto the edge on approval.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Hostname of the machine the agent runs on, to help the admin tell
+	// requests apart.
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
+	// AgentVersion is the version of the requesting kedge binary.
+	// +optional
+	AgentVersion string `json:"agentVersion,omitempty"`
+}
+
+// EdgeAdoptionRequestStatus records the admin's decision.
+type EdgeAdoptionRequestStatus struct {
+	// Phase is the decision state.
+	// +optional
+	Phase EdgeAdoptionPhase `json:"phase,omitempty"`
+	// Message explains the decision, e.g. why the request was denied.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// DecisionTime is when the request was approved or denied.
+	// +optional
+	DecisionTime *metav1.Time `json:"decisionTime,omitempty"`
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FleetCommandPhase is the lifecycle phase of a FleetCommand.
+type FleetCommandPhase string
+
+const (
+	// FleetCommandPhasePending: accepted, edges not yet selected.
+	FleetCommandPhasePending FleetCommandPhase = "Pending"
+	// FleetCommandPhaseRunning: the command is executing on the selected edges.
+	FleetCommandPhaseRunning FleetCommandPhase = "Running"
+	// FleetCommandPhaseSucceeded: every selected edge exited 0.
+	FleetCommandPhaseSucceeded FleetCommandPhase = "Succeeded"
+	// FleetCommandPhaseFailed: at least one edge failed, or the run was
+	// interrupted.
+	FleetCommandPhaseFailed FleetCommandPhase = "Failed"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=fc
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Total",type="integer",JSONPath=".status.total"
+// +kubebuilder:printcolumn:name="Succeeded",type="integer",JSONPath=".status.succeeded"
+// +kubebuilder:printcolumn:name="Failed",type="integer",JSONPath=".status.failed"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// FleetCommand runs one shell command on every LinuxServer edge matching a
+// selector, over the same SSH exec path as `kedge ssh <edge> -- <cmd>`. It is
+// one-shot, like a Job: the edge set is fixed when the run starts, spec changes
+// afterwards are ignored, and a FleetCommand is never re-run — create a new one.
+type FleetCommand struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              FleetCommandSpec   `json:"spec,omitempty"`
+	Status            FleetCommandStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// FleetCommandList is a list of FleetCommand resources.
+type FleetCommandList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FleetCommand `json:"items"`
+}
+
+// FleetCommandSpec defines what to run and where.
+type FleetCommandSpec struct {
+	// EdgeSelector selects the LinuxServer edges to run on. Empty selects
+	// every LinuxServer in the workspace.
+	// +optional
+	EdgeSelector *metav1.LabelSelector `json:"edgeSelector,omitempty"`
+	// Command is run by the edge's SSH server as the edge's SSH user, exactly
+	// as `kedge ssh <edge> -- <command>` would.
+	// +kubebuilder:validation:MinLength=1
+	Command string `json:"command"`
+	// Concurrency caps how many edges run the command at once. Defaults to 10.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Concurrency int32 `json:"concurrency,omitempty"`
+	// Timeout bounds the run on each edge. Defaults to 5m.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// FleetCommandStatus is the observed state of a FleetCommand.
+type FleetCommandStatus struct {
+	// Phase is one of Pending, Running, Succeeded, Failed.
+	// +optional
+	Phase FleetCommandPhase `json:"phase,omitempty"`
+	// StartTime is when edges were selected and execution began.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is when the last edge finished.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Total is the number of edges selected.
+	// +optional
+	Total int32 `json:"total,omitempty"`
+	// Succeeded is the number of edges whose command exited 0.
+	// +optional
+	Succeeded int32 `json:"succeeded,omitempty"`
+	// Failed is the number of edges that exited non-zero, timed out or could
+	// not be reached.
+	// +optional
+	Failed int32 `json:"failed,omitempty"`
+	// Message explains a Failed phase that is not down to individual edges,
+	// or what a Pending one is waiting for.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Results holds one entry per selected edge.
+	// +optional
+	Results []FleetCommandEdgeResult `json:"results,omitempty"`
+}
+
+// FleetCommandEdgeResult is the outcome of the command on one edge.
+type FleetCommandEdgeResult struct {
+	// Edge is the LinuxServer's name.
+	Edge string `json:"edge"`
+	// ExitCode is the command's exit status; unset while running or when the
+	// command never ran (see Error).
+	// +optional
+	ExitCode *int32 `json:"exitCode,omitempty"`
+	// Error explains why the command did not complete (edge not connected,
+	// SSH failure, timeout, denied).
+	// +optional
+	Error string `json:"error,omitempty"`
+	// Denied is set when the FleetCommand's requester may not reach this
+	// edge; the command was not run there.
+	// +optional
+	Denied bool `json:"denied,omitempty"`
+	// Output is the tail of the combined stdout and stderr, truncated to the
+	// last 1 KiB.
+	// +optional
+	Output string `json:"output,omitempty"`
+	// StartTime is when the command started on this edge.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is when the command finished on this edge.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	edgeapi "github.com/faroshq/provider-edges/internal/edgeapi"
@@ -31,6 +32,9 @@ import (
 // +kubebuilder:printcolumn:name="Last Heartbeat",type="date",JSONPath=".status.lastHeartbeatTime"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="Agent Version",type="string",JSONPath=".status.agentVersion",priority=1
+// +kubebuilder:printcolumn:name="Tunnel",type="string",JSONPath=".status.tunnel.holder",priority=1
+// +kubebuilder:printcolumn:name="Nodes",type="integer",JSONPath=".status.inventory.nodes",priority=1
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.inventory.kubernetesVersion",priority=1
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // KubernetesCluster is a managed Kubernetes cluster reachable through the hub
@@ -61,13 +65,161 @@ type KubernetesClusterList struct {
 
 // KubernetesClusterSpec defines the desired state of a KubernetesCluster.
 type KubernetesClusterSpec struct {
+	// Cordoned takes the cluster out of service for maintenance: the hub refuses
+	// new k8s and ssh sessions to it, and sessions already open are closed
+	// once the drain grace period has passed. The Draining condition reports
+	// progress.
+	// +optional
+	Cordoned bool `json:"cordoned,omitempty"`
+
 	// Labels for scheduling hints (region, provider, etc.)
 	// +optional
 	Labels map[string]string `json:"labels,omitempty"`
+
+	// RegistryCache, when set and enabled, has the agent run a pull-through
+	// image cache on the cluster so nodes at one site pull each image over the
+	// uplink once.
+	// +optional
+	RegistryCache *RegistryCacheSpec `json:"registryCache,omitempty"`
+
+	// WorkloadBudget caps the total CPU and memory that hub-scheduled
+	// Placements may request on this cluster, protecting site-local
+	// workloads sharing it. The agent refuses to apply a Placement that would
+	// exceed it and flags the Placement's WithinBudget condition instead.
+	// +optional
+	WorkloadBudget *WorkloadBudget `json:"workloadBudget,omitempty"`
+
+	// NamespaceQuota is resource policy the agent materializes in every
+	// namespace it applies Placements to. A Workload's own namespaceQuota is
+	// materialized next to it.
+	// +optional
+	NamespaceQuota *NamespaceQuota `json:"namespaceQuota,omitempty"`
+
+	// Location places the cluster on the hub's fleet map.
+	// +optional
+	Location *edgeapi.Location `json:"location,omitempty"`
+}
+
+// WorkloadBudget is the share of an edge cluster's capacity the hub may
+// schedule onto. Unset resources are not limited.
+type WorkloadBudget struct {
+	// CPU is the total CPU Placements may request, e.g. "4" or "2500m".
+	// +optional
+	CPU *resource.Quantity `json:"cpu,omitempty"`
+
+	// Memory is the total memory Placements may request, e.g. "8Gi".
+	// +optional
+	Memory *resource.Quantity `json:"memory,omitempty"`
+}
+
+// RegistryCacheSpec configures the edge-local pull-through registry cache.
+type RegistryCacheSpec struct {
+	// Enabled turns the cache on. Disabling it removes the cache workload.
+	Enabled bool `json:"enabled"`
+
+	// Upstream is the registry the cache proxies, e.g. "https://ghcr.io".
+	// +optional
+	// +kubebuilder:default="https://registry-1.docker.io"
+	Upstream string `json:"upstream,omitempty"`
+
+	// Image overrides the registry image the agent deploys.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// StorageSize is the size of the cache volume. Empty uses an emptyDir,
+	// which is lost when the cache pod is rescheduled.
+	// +optional
+	StorageSize string `json:"storageSize,omitempty"`
 }
 
 // KubernetesClusterStatus defines the observed state of a KubernetesCluster.
 type KubernetesClusterStatus struct {
 	// ConnectionStatus holds the shared tunnel/connection state (SDK-owned).
 	edgeapi.ConnectionStatus `json:",inline"`
+
+	// RegistryCache reports the state of the edge-local registry cache.
+	// +optional
+	RegistryCache *RegistryCacheStatus `json:"registryCache,omitempty"`
+
+	// EndToEndTLS is set when the agent terminates TLS for API traffic itself
+	// (agent flag --end-to-end-tls). The hub then only relays ciphertext over
+	// the k8s-tls subresource, and plaintext k8s access is refused.
+	// +optional
+	EndToEndTLS *EndToEndTLSStatus `json:"endToEndTLS,omitempty"`
+
+	// Inventory is the size and make-up of the cluster, reported by the
+	// agent, so the scheduler and CLI see capacity without querying the
+	// cluster.
+	// +optional
+	Inventory *ClusterInventory `json:"inventory,omitempty"`
+}
+
+// ClusterInventory summarizes the cluster's nodes and software.
+type ClusterInventory struct {
+	// Nodes is the number of nodes in the cluster.
+	Nodes int32 `json:"nodes"`
+
+	// ReadyNodes is the number of nodes whose Ready condition is True.
+	ReadyNodes int32 `json:"readyNodes"`
+
+	// CPU is the summed CPU capacity of all nodes.
+	// +optional
+	CPU *resource.Quantity `json:"cpu,omitempty"`
+
+	// Memory is the summed memory capacity of all nodes.
+	// +optional
+	Memory *resource.Quantity `json:"memory,omitempty"`
+
+	// KubernetesVersion is the API server's version, e.g. "v1.31.2+k3s1".
+	// +optional
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+
+	// Provider is the infrastructure or distribution the cluster runs on,
+	// from the nodes' providerID (e.g. "aws", "gce", "kind") or else the
+	// version string (e.g. "k3s", "eks").
+	// +optional
+	Provider string `json:"provider,omitempty"`
+
+	// CNI is the network plugin detected from the cluster's DaemonSets,
+	// e.g. "cilium" or "calico".
+	// +optional
+	CNI string `json:"cni,omitempty"`
+
+	// LastUpdateTime is when the agent last collected the inventory.
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// EndToEndTLSStatus is the agent's end-to-end TLS serving identity.
+type EndToEndTLSStatus struct {
+	// Certificate is the agent's PEM serving certificate, which clients pin
+	// as their CA bundle.
+	Certificate string `json:"certificate"`
+
+	// Fingerprint is the certificate's SHA-256, "sha256:<hex>", also logged
+	// by the agent so it can be checked out of band.
+	Fingerprint string `json:"fingerprint"`
+}
+
+// RegistryCacheStatus is the agent-reported state of the registry cache.
+type RegistryCacheStatus struct {
+	// Ready is true once the cache Deployment has an available replica.
+	Ready bool `json:"ready"`
+
+	// Endpoint is the in-cluster address nodes configure as a registry mirror.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Requests, Hits and Misses are the cache's cumulative blob counters since
+	// the cache pod started.
+	// +optional
+	Requests int64 `json:"requests,omitempty"`
+	// +optional
+	Hits int64 `json:"hits,omitempty"`
+	// +optional
+	Misses int64 `json:"misses,omitempty"`
+
+	// LastScrapeTime is when the counters were last read from the cache.
+	// +optional
+	LastScrapeTime *metav1.Time `json:"lastScrapeTime,omitempty"`
 }
@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	edgeapi "github.com/faroshq/provider-edges/internal/edgeapi"
@@ -32,6 +33,7 @@ import (
 // +kubebuilder:printcolumn:name="Last Heartbeat",type="date",JSONPath=".status.lastHeartbeatTime"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="Agent Version",type="string",JSONPath=".status.agentVersion",priority=1
+// +kubebuilder:printcolumn:name="Tunnel",type="string",JSONPath=".status.tunnel.holder",priority=1
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // LinuxServer is a managed bare-metal/VM Linux host reachable through the hub
@@ -62,6 +64,13 @@ type LinuxServerList struct {
 
 // LinuxServerSpec defines the desired state of a LinuxServer.
 type LinuxServerSpec struct {
+	// Cordoned takes the server out of service for maintenance: the hub refuses
+	// new k8s and ssh sessions to it, and sessions already open are closed
+	// once the drain grace period has passed. The Draining condition reports
+	// progress.
+	// +optional
+	Cordoned bool `json:"cordoned,omitempty"`
+
 	// SSHPort is the port sshd listens on inside the remote host (default: 22).
 	// +optional
 	// +kubebuilder:default=22
@@ -80,6 +89,10 @@ type LinuxServerSpec struct {
 	// SSHCredentialsRef references a Secret with admin-configured SSH credentials.
 	// +optional
 	SSHCredentialsRef *corev1.SecretReference `json:"sshCredentialsRef,omitempty"`
+
+	// Location places the server on the hub's fleet map.
+	// +optional
+	Location *edgeapi.Location `json:"location,omitempty"`
 }
 
 // LinuxServerStatus defines the observed state of a LinuxServer.
@@ -94,4 +107,71 @@ type LinuxServerStatus struct {
 	// SSHHostKey is the SSH host public key reported by the agent (authorized_keys format).
 	// +optional
 	SSHHostKey string `json:"sshHostKey,omitempty"`
+
+	// HostTelemetry is the server's load, memory, filesystem usage and
+	// temperatures, reported by the agent with its heartbeats, so full disks
+	// and overheating are seen from the hub before they take the site down.
+	// +optional
+	HostTelemetry *HostTelemetry `json:"hostTelemetry,omitempty"`
+}
+
+// HostTelemetry is a snapshot of the server's resource usage.
+type HostTelemetry struct {
+	// CPUs is the number of CPUs the server has online.
+	CPUs int32 `json:"cpus"`
+
+	// LoadAverage is the 1, 5 and 15 minute load averages as the kernel
+	// reports them, e.g. "0.52 0.41 0.30".
+	// +optional
+	LoadAverage string `json:"loadAverage,omitempty"`
+
+	// MemoryTotal is the server's usable physical memory.
+	// +optional
+	MemoryTotal *resource.Quantity `json:"memoryTotal,omitempty"`
+
+	// MemoryAvailable is the memory available to new processes without
+	// swapping.
+	// +optional
+	MemoryAvailable *resource.Quantity `json:"memoryAvailable,omitempty"`
+
+	// Filesystems is the usage of the filesystems the agent watches
+	// (agent flag --host-telemetry-paths, default "/").
+	// +optional
+	Filesystems []FilesystemUsage `json:"filesystems,omitempty"`
+
+	// Temperatures are the readings of the server's thermal zones; empty
+	// when it exposes none, as most VMs do.
+	// +optional
+	Temperatures []TemperatureReading `json:"temperatures,omitempty"`
+
+	// LastUpdateTime is when the agent collected the telemetry.
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// FilesystemUsage is the usage of the filesystem mounted at a path.
+type FilesystemUsage struct {
+	// Path is the path the agent was asked to watch.
+	Path string `json:"path"`
+
+	// Size is the filesystem's size.
+	// +optional
+	Size *resource.Quantity `json:"size,omitempty"`
+
+	// Available is the space available to unprivileged users.
+	// +optional
+	Available *resource.Quantity `json:"available,omitempty"`
+
+	// UsedPercent is the share of the space usable by unprivileged users
+	// that is used, rounded up, as df reports it.
+	UsedPercent int32 `json:"usedPercent"`
+}
+
+// TemperatureReading is the temperature of one thermal zone.
+type TemperatureReading struct {
+	// Sensor names the zone, e.g. "x86_pkg_temp" or "cpu-thermal".
+	Sensor string `json:"sensor"`
+
+	// Celsius is the temperature in whole degrees Celsius.
+	Celsius int32 `json:"celsius"`
 }
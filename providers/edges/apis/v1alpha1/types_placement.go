@@ -64,6 +64,24 @@ type PlacementObjSpec struct {
 	// +optional
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Manifests []runtime.RawExtension `json:"manifests,omitempty"`
+	// ManifestsRef points at the rendered bundle in the provider's
+	// content-addressed manifest store, in place of inline Manifests. The
+	// scheduler sets it when the store is enabled, so identical workloads
+	// across placements and tenants share one stored copy; the agent fetches
+	// the bundle by digest, verifies it and applies it like Manifests.
+	// +optional
+	ManifestsRef *ManifestsRef `json:"manifestsRef,omitempty"`
+}
+
+// ManifestsRef identifies a stored manifest bundle by content.
+type ManifestsRef struct {
+	// Digest is "sha256:<hex>" of the bundle's canonical JSON encoding.
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	Digest string `json:"digest"`
+	// Size is the length in bytes of the canonical encoding.
+	Size int64 `json:"size"`
+	// Count is the number of objects in the bundle.
+	Count int32 `json:"count"`
 }
 
 // PlacementObjStatus defines the observed state of a Placement.
@@ -73,4 +91,27 @@ type PlacementObjStatus struct {
 	ReadyReplicas int32  `json:"readyReplicas"`
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the metadata.generation of this Placement whose
+	// manifests the agent last applied on the edge — the last applied revision.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Resources mirrors the status of the Deployments, StatefulSets and Jobs
+	// the agent applied for this placement, so hub users see replica and
+	// readiness counts without proxying to the edge.
+	// +optional
+	Resources []PlacementResourceStatus `json:"resources,omitempty"`
+}
+
+// PlacementResourceStatus is the mirrored status of one object applied on the
+// edge.
+type PlacementResourceStatus struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// Status is the object's .status, copied verbatim.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Status *runtime.RawExtension `json:"status,omitempty"`
 }
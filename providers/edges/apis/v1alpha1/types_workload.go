@@ -43,6 +43,7 @@ const (
 // +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.readyReplicas
 // +kubebuilder:resource:path=workloads,singular=workload,shortName=wl
 // +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
 // +kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.readyReplicas"
@@ -88,6 +89,23 @@ type WorkloadSpec struct {
 	Placement PlacementSpec `json:"placement"`
 	// +optional
 	Access *AccessSpec `json:"access,omitempty"`
+	// NamespaceQuota is resource policy the edge agents materialize in the
+	// namespaces this workload is applied to, next to the edge's own.
+	// +optional
+	NamespaceQuota *NamespaceQuota `json:"namespaceQuota,omitempty"`
+}
+
+// NamespaceQuota is fleet-wide resource policy kept on the hub. The agent of
+// each edge it applies to writes it as a ResourceQuota and a LimitRange into
+// the edge namespaces it manages, and removes them when it is unset.
+type NamespaceQuota struct {
+	// ResourceQuota caps the aggregate resources of the namespace.
+	// +optional
+	ResourceQuota *corev1.ResourceQuotaSpec `json:"resourceQuota,omitempty"`
+	// LimitRange bounds, and defaults, the resources of each pod and
+	// container in the namespace.
+	// +optional
+	LimitRange *corev1.LimitRangeSpec `json:"limitRange,omitempty"`
 }
 
 // HelmWorkloadSpec deploys a workload from a Helm chart, rendered by the
@@ -26,6 +26,135 @@ func (in *AccessSpec) DeepCopy() *AccessSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterInventory) DeepCopyInto(out *ClusterInventory) {
+	*out = *in
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterInventory.
+func (in *ClusterInventory) DeepCopy() *ClusterInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EdgeAdoptionRequest) DeepCopyInto(out *EdgeAdoptionRequest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EdgeAdoptionRequest.
+func (in *EdgeAdoptionRequest) DeepCopy() *EdgeAdoptionRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(EdgeAdoptionRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EdgeAdoptionRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EdgeAdoptionRequestList) DeepCopyInto(out *EdgeAdoptionRequestList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]EdgeAdoptionRequest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EdgeAdoptionRequestList.
+func (in *EdgeAdoptionRequestList) DeepCopy() *EdgeAdoptionRequestList {
+	if in == nil {
+		return nil
+	}
+	out := new(EdgeAdoptionRequestList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EdgeAdoptionRequestList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EdgeAdoptionRequestSpec) DeepCopyInto(out *EdgeAdoptionRequestSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EdgeAdoptionRequestSpec.
+func (in *EdgeAdoptionRequestSpec) DeepCopy() *EdgeAdoptionRequestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EdgeAdoptionRequestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EdgeAdoptionRequestStatus) DeepCopyInto(out *EdgeAdoptionRequestStatus) {
+	*out = *in
+	if in.DecisionTime != nil {
+		in, out := &in.DecisionTime, &out.DecisionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EdgeAdoptionRequestStatus.
+func (in *EdgeAdoptionRequestStatus) DeepCopy() *EdgeAdoptionRequestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EdgeAdoptionRequestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EdgeWorkloadStatus) DeepCopyInto(out *EdgeWorkloadStatus) {
 	*out = *in
@@ -41,6 +170,188 @@ func (in *EdgeWorkloadStatus) DeepCopy() *EdgeWorkloadStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EndToEndTLSStatus) DeepCopyInto(out *EndToEndTLSStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndToEndTLSStatus.
+func (in *EndToEndTLSStatus) DeepCopy() *EndToEndTLSStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EndToEndTLSStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemUsage) DeepCopyInto(out *FilesystemUsage) {
+	*out = *in
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Available != nil {
+		in, out := &in.Available, &out.Available
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemUsage.
+func (in *FilesystemUsage) DeepCopy() *FilesystemUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FleetCommand) DeepCopyInto(out *FleetCommand) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetCommand.
+func (in *FleetCommand) DeepCopy() *FleetCommand {
+	if in == nil {
+		return nil
+	}
+	out := new(FleetCommand)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FleetCommand) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FleetCommandEdgeResult) DeepCopyInto(out *FleetCommandEdgeResult) {
+	*out = *in
+	if in.ExitCode != nil {
+		in, out := &in.ExitCode, &out.ExitCode
+		*out = new(int32)
+		**out = **in
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetCommandEdgeResult.
+func (in *FleetCommandEdgeResult) DeepCopy() *FleetCommandEdgeResult {
+	if in == nil {
+		return nil
+	}
+	out := new(FleetCommandEdgeResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FleetCommandList) DeepCopyInto(out *FleetCommandList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FleetCommand, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetCommandList.
+func (in *FleetCommandList) DeepCopy() *FleetCommandList {
+	if in == nil {
+		return nil
+	}
+	out := new(FleetCommandList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FleetCommandList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FleetCommandSpec) DeepCopyInto(out *FleetCommandSpec) {
+	*out = *in
+	if in.EdgeSelector != nil {
+		in, out := &in.EdgeSelector, &out.EdgeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetCommandSpec.
+func (in *FleetCommandSpec) DeepCopy() *FleetCommandSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FleetCommandSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FleetCommandStatus) DeepCopyInto(out *FleetCommandStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Results != nil {
+		in, out := &in.Results, &out.Results
+		*out = make([]FleetCommandEdgeResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetCommandStatus.
+func (in *FleetCommandStatus) DeepCopy() *FleetCommandStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FleetCommandStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HelmWorkloadSpec) DeepCopyInto(out *HelmWorkloadSpec) {
 	*out = *in
@@ -61,6 +372,47 @@ func (in *HelmWorkloadSpec) DeepCopy() *HelmWorkloadSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostTelemetry) DeepCopyInto(out *HostTelemetry) {
+	*out = *in
+	if in.MemoryTotal != nil {
+		in, out := &in.MemoryTotal, &out.MemoryTotal
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MemoryAvailable != nil {
+		in, out := &in.MemoryAvailable, &out.MemoryAvailable
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Filesystems != nil {
+		in, out := &in.Filesystems, &out.Filesystems
+		*out = make([]FilesystemUsage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Temperatures != nil {
+		in, out := &in.Temperatures, &out.Temperatures
+		*out = make([]TemperatureReading, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostTelemetry.
+func (in *HostTelemetry) DeepCopy() *HostTelemetry {
+	if in == nil {
+		return nil
+	}
+	out := new(HostTelemetry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubeServiceRef) DeepCopyInto(out *KubeServiceRef) {
 	*out = *in
@@ -145,6 +497,26 @@ func (in *KubernetesClusterSpec) DeepCopyInto(out *KubernetesClusterSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.RegistryCache != nil {
+		in, out := &in.RegistryCache, &out.RegistryCache
+		*out = new(RegistryCacheSpec)
+		**out = **in
+	}
+	if in.WorkloadBudget != nil {
+		in, out := &in.WorkloadBudget, &out.WorkloadBudget
+		*out = new(WorkloadBudget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NamespaceQuota != nil {
+		in, out := &in.NamespaceQuota, &out.NamespaceQuota
+		*out = new(NamespaceQuota)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Location != nil {
+		in, out := &in.Location, &out.Location
+		*out = new(edgeapi.Location)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesClusterSpec.
@@ -161,6 +533,21 @@ func (in *KubernetesClusterSpec) DeepCopy() *KubernetesClusterSpec {
 func (in *KubernetesClusterStatus) DeepCopyInto(out *KubernetesClusterStatus) {
 	*out = *in
 	in.ConnectionStatus.DeepCopyInto(&out.ConnectionStatus)
+	if in.RegistryCache != nil {
+		in, out := &in.RegistryCache, &out.RegistryCache
+		*out = new(RegistryCacheStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EndToEndTLS != nil {
+		in, out := &in.EndToEndTLS, &out.EndToEndTLS
+		*out = new(EndToEndTLSStatus)
+		**out = **in
+	}
+	if in.Inventory != nil {
+		in, out := &in.Inventory, &out.Inventory
+		*out = new(ClusterInventory)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesClusterStatus.
@@ -245,6 +632,11 @@ func (in *LinuxServerSpec) DeepCopyInto(out *LinuxServerSpec) {
 		*out = new(v1.SecretReference)
 		**out = **in
 	}
+	if in.Location != nil {
+		in, out := &in.Location, &out.Location
+		*out = new(edgeapi.Location)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LinuxServerSpec.
@@ -266,6 +658,11 @@ func (in *LinuxServerStatus) DeepCopyInto(out *LinuxServerStatus) {
 		*out = new(edgeapi.SSHCredentials)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HostTelemetry != nil {
+		in, out := &in.HostTelemetry, &out.HostTelemetry
+		*out = new(HostTelemetry)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LinuxServerStatus.
@@ -278,6 +675,46 @@ func (in *LinuxServerStatus) DeepCopy() *LinuxServerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManifestsRef) DeepCopyInto(out *ManifestsRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestsRef.
+func (in *ManifestsRef) DeepCopy() *ManifestsRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ManifestsRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceQuota) DeepCopyInto(out *NamespaceQuota) {
+	*out = *in
+	if in.ResourceQuota != nil {
+		in, out := &in.ResourceQuota, &out.ResourceQuota
+		*out = new(v1.ResourceQuotaSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LimitRange != nil {
+		in, out := &in.LimitRange, &out.LimitRange
+		*out = new(v1.LimitRangeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceQuota.
+func (in *NamespaceQuota) DeepCopy() *NamespaceQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Placement) DeepCopyInto(out *Placement) {
 	*out = *in
@@ -353,6 +790,11 @@ func (in *PlacementObjSpec) DeepCopyInto(out *PlacementObjSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ManifestsRef != nil {
+		in, out := &in.ManifestsRef, &out.ManifestsRef
+		*out = new(ManifestsRef)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementObjSpec.
@@ -375,6 +817,13 @@ func (in *PlacementObjStatus) DeepCopyInto(out *PlacementObjStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]PlacementResourceStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementObjStatus.
@@ -387,6 +836,26 @@ func (in *PlacementObjStatus) DeepCopy() *PlacementObjStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementResourceStatus) DeepCopyInto(out *PlacementResourceStatus) {
+	*out = *in
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementResourceStatus.
+func (in *PlacementResourceStatus) DeepCopy() *PlacementResourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementResourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PlacementSpec) DeepCopyInto(out *PlacementSpec) {
 	*out = *in
@@ -407,6 +876,40 @@ func (in *PlacementSpec) DeepCopy() *PlacementSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryCacheSpec) DeepCopyInto(out *RegistryCacheSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryCacheSpec.
+func (in *RegistryCacheSpec) DeepCopy() *RegistryCacheSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryCacheSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryCacheStatus) DeepCopyInto(out *RegistryCacheStatus) {
+	*out = *in
+	if in.LastScrapeTime != nil {
+		in, out := &in.LastScrapeTime, &out.LastScrapeTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryCacheStatus.
+func (in *RegistryCacheStatus) DeepCopy() *RegistryCacheStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryCacheStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Service) DeepCopyInto(out *Service) {
 	*out = *in
@@ -572,6 +1075,21 @@ func (in *SimpleWorkloadSpec) DeepCopy() *SimpleWorkloadSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemperatureReading) DeepCopyInto(out *TemperatureReading) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemperatureReading.
+func (in *TemperatureReading) DeepCopy() *TemperatureReading {
+	if in == nil {
+		return nil
+	}
+	out := new(TemperatureReading)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Workload) DeepCopyInto(out *Workload) {
 	*out = *in
@@ -599,6 +1117,31 @@ func (in *Workload) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadBudget) DeepCopyInto(out *WorkloadBudget) {
+	*out = *in
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadBudget.
+func (in *WorkloadBudget) DeepCopy() *WorkloadBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkloadList) DeepCopyInto(out *WorkloadList) {
 	*out = *in
@@ -660,6 +1203,11 @@ func (in *WorkloadSpec) DeepCopyInto(out *WorkloadSpec) {
 		*out = new(AccessSpec)
 		**out = **in
 	}
+	if in.NamespaceQuota != nil {
+		in, out := &in.NamespaceQuota, &out.NamespaceQuota
+		*out = new(NamespaceQuota)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadSpec.
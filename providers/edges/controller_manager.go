@@ -24,6 +24,7 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
@@ -31,8 +32,12 @@ import (
 	mcmanager "sigs.k8s.io/multicluster-runtime/pkg/manager"
 	mcmulticluster "sigs.k8s.io/multicluster-runtime/pkg/multicluster"
 
+	"github.com/faroshq/provider-edges/internal/claims"
+	"github.com/faroshq/provider-edges/internal/costs"
 	edgectrl "github.com/faroshq/provider-edges/internal/edgectrl"
 	"github.com/faroshq/provider-edges/internal/events"
+	"github.com/faroshq/provider-edges/internal/fleet"
+	"github.com/faroshq/provider-edges/internal/manifeststore"
 	"github.com/faroshq/provider-edges/internal/scheduler"
 	"github.com/faroshq/provider-edges/internal/servicectrl"
 	"github.com/faroshq/provider-edges/internal/status"
@@ -52,16 +57,30 @@ var errControllerDisabled = errors.New("no kubeconfig available; edge controller
 // name — see sdkinstall.Bootstrap / EnsureAPIExportEndpointSlice.
 const endpointSliceName = apiExportName
 
+// leaderElectionID names the Lease, in the provider workspace's default
+// namespace, that elects the replica running the provider's controllers.
+const leaderElectionID = "edges-provider-controllers"
+
 // eventsMaxAge bounds how long an edge event is retained in the in-memory store
 // (on top of the per-service count cap), so the "recent events" a tool returns
 // stay recent even for a quiet camera.
 const eventsMaxAge = 6 * time.Hour
 
 // startEdgeControllerManager builds the multicluster manager and starts the
-// edge token / RBAC / lifecycle reconcilers. connManager wires the lifecycle
-// reconciler's tunnel-liveness cross-check to the provider's live ConnManager.
-// A nil config means "skip the manager" (healthz-only / dev).
-func startEdgeControllerManager(ctx context.Context, config *rest.Config, tsrv *sdktunnel.Server, hubExternalURL string, hubCAData []byte, devMode bool) error {
+// edge token / RBAC / drain / lifecycle reconcilers. connManager wires the
+// lifecycle reconciler's tunnel-liveness cross-check to the provider's live
+// ConnManager, and drainGrace is how long a drain leaves sessions open.
+// manifestStore, when non-nil, has the scheduler reference stored bundles from
+// Placements, extender, when non-nil, filters and scores the edges it
+// schedules onto, and policy, when non-nil, admits each Placement it writes.
+// preview reads tenant workspaces through the manager once it is built.
+// costIndex, when non-nil, names the Workload labels the
+// scheduler copies onto Placements and is kept up to date with them.
+// claimAllowlist names the resources Workloads may have the APIExport claim. With
+// leaderElection, as when several replicas run, only the elected replica runs
+// the controllers that do not need a tunnel. A nil config means "skip the
+// manager" (healthz-only / dev).
+func startEdgeControllerManager(ctx context.Context, config *rest.Config, tsrv *sdktunnel.Server, manifestStore *manifeststore.Store, extender *scheduler.Extender, policy *scheduler.Policy, preview *scheduler.Preview, costIndex *costs.Index, claimAllowlist claims.Allowlist, hubExternalURL string, hubCAData []byte, devMode bool, drainGrace time.Duration, leaderElection bool) error {
 	if config == nil {
 		return errControllerDisabled
 	}
@@ -87,13 +106,30 @@ func startEdgeControllerManager(ctx context.Context, config *rest.Config, tsrv *
 		return fmt.Errorf("creating apiexport multicluster provider: %w", err)
 	}
 
-	mgr, err := mcmanager.New(config, provider, manager.Options{
-		Scheme:  s,
-		Metrics: metricsserver.Options{BindAddress: "0"}, // provider serves its own HTTP
+	// Every replica engages the tenant workspaces, elected or not: its
+	// tunnels read and write them (SetTenantConfigGetter below). mcmanager
+	// would run a ProviderRunnable under leader election, so with election on
+	// the provider is hidden behind its Provider interface and run here.
+	var mcProvider mcmulticluster.Provider = provider
+	if leaderElection {
+		mcProvider = unelectedProvider{provider}
+	}
+	mgr, err := mcmanager.New(config, mcProvider, manager.Options{
+		Scheme:                        s,
+		Metrics:                       metricsserver.Options{BindAddress: "0"}, // provider serves its own HTTP
+		LeaderElection:                leaderElection,
+		LeaderElectionID:              leaderElectionID,
+		LeaderElectionNamespace:       "default",
+		LeaderElectionReleaseOnCancel: true,
 	})
 	if err != nil {
 		return fmt.Errorf("creating multicluster manager: %w", err)
 	}
+	if leaderElection {
+		if err := mgr.GetLocalManager().Add(providerRunnable{provider: provider, mgr: mgr}); err != nil {
+			return fmt.Errorf("adding multicluster provider: %w", err)
+		}
+	}
 
 	// Wire the tunnel plane's cross-workspace tenant reads/writes to this
 	// manager's APIExport virtual workspace. The provider's own SA credential is
@@ -109,13 +145,19 @@ func startEdgeControllerManager(ctx context.Context, config *rest.Config, tsrv *
 		return cl.GetConfig(), nil
 	})
 
-	opts := edgectrl.Options{HubExternalURL: hubExternalURL, HubCAData: hubCAData, DevMode: devMode}
-	// Drive the UpgradeAvailable condition off the hub's /version endpoint. A
-	// single cache is shared across both kinds' version reconcilers so many edges
+	// The tunnel Server refuses and closes sessions to the edges the drain
+	// reconcilers mark Draining.
+	opts := edgectrl.Options{HubExternalURL: hubExternalURL, HubCAData: hubCAData, DevMode: devMode,
+		Drainer: tsrv, DrainGracePeriod: drainGrace, Instance: tsrv.InstanceName()}
+	// Drive the UpgradeAvailable and VersionSupported conditions off the hub's
+	// /version endpoint. A single cache is shared across both kinds' version
+	// reconcilers and the tunnel's skew check on agent connect, so many edges
 	// cost one periodic hub lookup, not one per edge. Skipped without a hub URL
-	// (dev/healthz-only), leaving the condition untouched.
+	// (dev/healthz-only), leaving the conditions untouched.
 	if hubExternalURL != "" {
-		opts.LatestAgentVersion = edgectrl.NewHubVersionCache(hubExternalURL, hubCAData, 10*time.Minute).Get
+		hubVersion := edgectrl.NewHubVersionCache(hubExternalURL, hubCAData, 10*time.Minute)
+		opts.LatestAgentVersion = hubVersion.Get
+		tsrv.SetHubVersion(hubVersion.Get)
 	}
 	// One set of token/RBAC/lifecycle controllers per kind, on the shared
 	// multicluster manager. Both kinds share the single tunnel ConnManager (keyed
@@ -138,20 +180,52 @@ func startEdgeControllerManager(ctx context.Context, config *rest.Config, tsrv *
 	// Workload out into one Placement per matching edge; the status
 	// aggregator rolls per-edge Placement statuses back up. Each edge's agent
 	// applies the derived Deployment locally and reports Placement status.
-	if err := scheduler.SetupWithManager(mgr); err != nil {
+	var costLabels []string
+	if costIndex != nil {
+		costLabels = costIndex.Labels()
+	}
+	if err := scheduler.SetupWithManager(mgr, manifestStore, extender, policy, costLabels); err != nil {
 		return fmt.Errorf("Workload scheduler: %w", err)
 	}
+	preview.SetClusterClient(func(ctx context.Context, clusterName string) (client.Client, error) {
+		cl, err := mgr.GetCluster(ctx, mcmulticluster.ClusterName(clusterName))
+		if err != nil {
+			return nil, fmt.Errorf("engaging tenant cluster %q: %w", clusterName, err)
+		}
+		return cl.GetClient(), nil
+	})
 	if err := status.SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("Workload status aggregator: %w", err)
 	}
+	// Permission claims: a Workload carrying kinds kcp serves in the tenant
+	// workspace gets them claimed on the APIExport when allowlisted, and a
+	// PermissionClaims condition naming those still missing.
+	if err := claims.SetupWithManager(mgr, config, apiExportName, catalogEntryName, claimAllowlist); err != nil {
+		return fmt.Errorf("Workload permission claims: %w", err)
+	}
+	// Cost attribution: index Placements by the cost labels the scheduler
+	// copied onto them, for /costs and the cost metrics.
+	if costIndex != nil {
+		if err := costs.SetupWithManager(mgr, costIndex); err != nil {
+			return fmt.Errorf("Placement cost index: %w", err)
+		}
+	}
+
+	// Fleet commands (LinuxServer edges): fan one command out over the ssh
+	// subresource's exec path to every matching edge. Runs execute in-process
+	// through the tunnel Server, so with several replicas they reach only the
+	// edges whose tunnel the elected replica holds.
+	if err := fleet.SetupWithManager(ctx, mgr, tsrv, tsrv); err != nil {
+		return fmt.Errorf("FleetCommand controller: %w", err)
+	}
 
 	// Edge event subscribers (currently UniFi Protect): a per-tenant, per-service
 	// event store the validation reconciler feeds via WebSocket subscribers, and
 	// the MCP `events` tool reads. The in-memory store is bounded per service and
-	// sits behind an interface so it can be swapped for Redis once the provider
-	// scales past the revdial single-replica invariant. Both the writer (manager)
-	// and reader (tunnel Server) share the one store; subscriber goroutines live
-	// under ctx, so they stop on shutdown.
+	// sits behind an interface so it can be swapped for Redis: with several
+	// replicas, a service's events are kept by the replica holding its edge's
+	// tunnel. Both the writer (manager) and reader (tunnel Server) share the one
+	// store; subscriber goroutines live under ctx, so they stop on shutdown.
 	eventStore := events.NewMemoryStore(events.DefaultPerServiceCap, eventsMaxAge)
 	eventsMgr := events.NewManager(ctx, eventStore, ctrl.Log.WithName("edge-events"))
 	tsrv.SetEventStore(eventStore)
@@ -164,6 +238,7 @@ func startEdgeControllerManager(ctx context.Context, config *rest.Config, tsrv *
 	if err := servicectrl.SetupWithManager(mgr, connManager, servicectrl.Options{
 		EdgeProxyPublicPath: edgeProxyPublicPath,
 		Events:              eventsMgr,
+		Instance:            tsrv.InstanceName(),
 	}); err != nil {
 		return fmt.Errorf("EdgeService controllers: %w", err)
 	}
@@ -176,3 +251,22 @@ func startEdgeControllerManager(ctx context.Context, config *rest.Config, tsrv *
 	}()
 	return nil
 }
+
+// unelectedProvider exposes only the multicluster Provider interface of a
+// provider, so mcmanager does not start it as a leader-elected runnable.
+type unelectedProvider struct {
+	mcmulticluster.Provider
+}
+
+// providerRunnable runs the multicluster provider on every replica.
+type providerRunnable struct {
+	provider mcmulticluster.ProviderRunnable
+	mgr      mcmanager.Manager
+}
+
+func (r providerRunnable) Start(ctx context.Context) error {
+	return r.provider.Start(ctx, r.mgr)
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (providerRunnable) NeedLeaderElection() bool { return false }
@@ -23,6 +23,8 @@ import (
 const (
 	apiExportName        = "edges.providers.kedge.faros.sh"
 	defaultWorkspacePath = "root:kedge:providers:edges"
+	// catalogEntryName is the CatalogEntry of manifest.yaml.
+	catalogEntryName = "edges"
 )
 
 // runInitCmd bootstraps the provider's APIExport into its workspace: it applies
@@ -0,0 +1,205 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package claims keeps the edges APIExport's permission claims in step with
+// what Workloads carry.
+//
+// A Workload's rendered bundle can hold kinds kcp also serves in the tenant
+// workspace — ConfigMaps, Secrets, ServiceAccounts, RBAC — which the provider
+// reaches there only through a claim the tenant's APIBinding accepted. The
+// reconciler renders each Workload and, for every such kind the export does
+// not claim yet, adds the claim to the APIExport and the CatalogEntry if the
+// kind is on the operator's allowlist (KEDGE_CLAIM_ALLOWLIST). The Workload's
+// PermissionClaims condition names the kinds still missing: those off the
+// allowlist, and those claimed but not accepted by the workspace.
+//
+// Kinds of the edges group come with the export, and kinds kcp does not serve
+// (Deployments, Services...) only ever exist on the edge; neither needs a
+// claim.
+package claims
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	edgesv1alpha1 "github.com/faroshq/provider-edges/apis/v1alpha1"
+)
+
+// ConditionPermissionClaims reports on a Workload whether the edges export
+// claims, and the workspace accepted, every kind of its bundle that needs it.
+const ConditionPermissionClaims = "PermissionClaims"
+
+// Reasons of the PermissionClaims condition.
+const (
+	ReasonClaimed          = "Claimed"
+	ReasonClaimNotAllowed  = "ClaimNotAllowed"
+	ReasonClaimNotAccepted = "ClaimNotAccepted"
+)
+
+// claimVerbs are the verbs of an added claim, those of the export's other
+// tenant-object claims (see init_cmd.go).
+var claimVerbs = []string{"get", "list", "watch", "create", "update", "patch", "delete"}
+
+// Allowlist is the set of resources the provider may add claims on.
+type Allowlist map[schema.GroupResource]bool
+
+// ParseAllowlist parses resources written resource.group, or resource for the
+// core group, e.g. "configmaps" or "leases.coordination.k8s.io".
+func ParseAllowlist(entries []string) (Allowlist, error) {
+	allow := Allowlist{}
+	for _, e := range entries {
+		gr := schema.ParseGroupResource(strings.TrimSpace(e))
+		if gr.Resource == "" || strings.ContainsAny(gr.Resource, "/ ") {
+			return nil, fmt.Errorf("invalid resource %q (want resource.group)", e)
+		}
+		if gr.Group == edgesv1alpha1.SchemeGroupVersion.Group {
+			return nil, fmt.Errorf("%q is served by the edges export, not claimed", e)
+		}
+		allow[gr] = true
+	}
+	return allow, nil
+}
+
+// claim is a resource of a Workload's bundle that needs a permission claim,
+// with a kind of it to probe the workspace with.
+type claim struct {
+	resource schema.GroupResource
+	kind     schema.GroupVersionKind
+}
+
+// requiredClaims returns the resources of objs that kcp serves, by mapper's
+// account, sorted. Kinds mapper does not know are not served by kcp.
+func requiredClaims(objs []*unstructured.Unstructured, mapper meta.RESTMapper) ([]claim, error) {
+	seen := map[schema.GroupResource]bool{}
+	var out []claim
+	for _, obj := range objs {
+		gvk := obj.GroupVersionKind()
+		if gvk.Group == edgesv1alpha1.SchemeGroupVersion.Group {
+			continue
+		}
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if meta.IsNoMatchError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("mapping %s: %w", gvk, err)
+		}
+		gr := mapping.Resource.GroupResource()
+		if seen[gr] {
+			continue
+		}
+		seen[gr] = true
+		out = append(out, claim{resource: gr, kind: mapping.GroupVersionKind})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].resource.String() < out[j].resource.String() })
+	return out, nil
+}
+
+// claimedResources returns the resources of the permission claims at path in
+// obj: an APIExport's spec.permissionClaims or a CatalogEntry's
+// spec.apiExport.permissionClaims.
+func claimedResources(obj *unstructured.Unstructured, path ...string) sets.Set[schema.GroupResource] {
+	out := sets.New[schema.GroupResource]()
+	pcs, _, _ := unstructured.NestedSlice(obj.Object, path...)
+	for _, pc := range pcs {
+		m, ok := pc.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		group, _ := m["group"].(string)
+		resource, _ := m["resource"].(string)
+		out.Insert(schema.GroupResource{Group: group, Resource: resource})
+	}
+	return out
+}
+
+// addClaims appends to the permission claims at path in obj a claim on each
+// of grs it lacks, with the fields of extra, and reports whether obj changed.
+func addClaims(obj *unstructured.Unstructured, grs []schema.GroupResource, extra map[string]interface{}, path ...string) (bool, error) {
+	have := claimedResources(obj, path...)
+	pcs, _, err := unstructured.NestedSlice(obj.Object, path...)
+	if err != nil {
+		return false, err
+	}
+	changed := false
+	for _, gr := range grs {
+		if have.Has(gr) {
+			continue
+		}
+		verbs := make([]interface{}, 0, len(claimVerbs))
+		for _, v := range claimVerbs {
+			verbs = append(verbs, v)
+		}
+		pc := map[string]interface{}{"resource": gr.Resource, "verbs": verbs}
+		if gr.Group != "" {
+			pc["group"] = gr.Group
+		}
+		for k, v := range extra {
+			pc[k] = v
+		}
+		pcs = append(pcs, pc)
+		have.Insert(gr)
+		changed = true
+	}
+	if !changed {
+		return false, nil
+	}
+	return true, unstructured.SetNestedSlice(obj.Object, pcs, path...)
+}
+
+// claimsCondition returns the PermissionClaims condition for a Workload whose
+// bundle needs claims on notAllowed, which the provider may not add, and on
+// notAccepted, which the workspace has not accepted.
+func claimsCondition(notAllowed, notAccepted []schema.GroupResource, generation int64) metav1.Condition {
+	cond := metav1.Condition{
+		Type:               ConditionPermissionClaims,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonClaimed,
+		Message:            "The edges provider claims every kind of the workload the workspace serves",
+		ObservedGeneration: generation,
+	}
+	var msgs []string
+	if len(notAllowed) > 0 {
+		cond.Reason = ReasonClaimNotAllowed
+		msgs = append(msgs, fmt.Sprintf("not on the edges provider's claim allowlist: %s", joinResources(notAllowed)))
+	}
+	if len(notAccepted) > 0 {
+		if cond.Reason == ReasonClaimed {
+			cond.Reason = ReasonClaimNotAccepted
+		}
+		msgs = append(msgs, fmt.Sprintf("claimed by the edges provider but not accepted on this workspace's APIBinding: %s", joinResources(notAccepted)))
+	}
+	if len(msgs) > 0 {
+		cond.Status = metav1.ConditionFalse
+		cond.Message = "Missing permission claims — " + strings.Join(msgs, "; ")
+	}
+	return cond
+}
+
+func joinResources(grs []schema.GroupResource) string {
+	names := make([]string, len(grs))
+	for i, gr := range grs {
+		names[i] = gr.String()
+	}
+	return strings.Join(names, ", ")
+}
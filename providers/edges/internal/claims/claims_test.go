@@ -0,0 +1,136 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package claims
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	configmaps = schema.GroupResource{Resource: "configmaps"}
+	leases     = schema.GroupResource{Group: "coordination.k8s.io", Resource: "leases"}
+	secrets    = schema.GroupResource{Resource: "secrets"}
+)
+
+func TestParseAllowlist(t *testing.T) {
+	got, err := ParseAllowlist([]string{"configmaps", " leases.coordination.k8s.io"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Allowlist{configmaps: true, leases: true}); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseAllowlist = %v, want %v", got, want)
+	}
+	for _, bad := range []string{"", "apps/deployments", "kubernetesclusters.edges.kedge.faros.sh"} {
+		if _, err := ParseAllowlist([]string{bad}); err == nil {
+			t.Errorf("ParseAllowlist(%q) accepted", bad)
+		}
+	}
+}
+
+func object(apiVersion, kind string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(apiVersion)
+	u.SetKind(kind)
+	return u
+}
+
+func TestRequiredClaims(t *testing.T) {
+	// The mapper knows what kcp serves: no Deployments or Services.
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Secret"}, meta.RESTScopeNamespace)
+
+	got, err := requiredClaims([]*unstructured.Unstructured{
+		object("apps/v1", "Deployment"),
+		object("v1", "Service"),
+		object("v1", "Secret"),
+		object("v1", "ConfigMap"),
+		object("v1", "ConfigMap"),
+		object("edges.kedge.faros.sh/v1alpha1", "Service"),
+	}, mapper)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []claim{
+		{resource: configmaps, kind: schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}},
+		{resource: secrets, kind: schema.GroupVersionKind{Version: "v1", Kind: "Secret"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("requiredClaims = %v, want %v", got, want)
+	}
+}
+
+func TestAddClaims(t *testing.T) {
+	export := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"permissionClaims": []interface{}{
+				map[string]interface{}{"resource": "secrets", "verbs": []interface{}{"get"}},
+			},
+		},
+	}}
+	path := []string{"spec", "permissionClaims"}
+
+	changed, err := addClaims(export, []schema.GroupResource{secrets, leases}, map[string]interface{}{"tenantScoped": true}, path...)
+	if err != nil || !changed {
+		t.Fatalf("addClaims = %v, %v; want a change", changed, err)
+	}
+	if got := claimedResources(export, path...); !got.Has(secrets) || !got.Has(leases) || got.Len() != 2 {
+		t.Errorf("claimed after adding = %v, want secrets and leases", got.UnsortedList())
+	}
+	pcs, _, _ := unstructured.NestedSlice(export.Object, path...)
+	added := pcs[1].(map[string]interface{})
+	if added["group"] != "coordination.k8s.io" || added["tenantScoped"] != true || len(added["verbs"].([]interface{})) != len(claimVerbs) {
+		t.Errorf("added claim = %v", added)
+	}
+
+	if changed, err := addClaims(export, []schema.GroupResource{leases}, nil, path...); err != nil || changed {
+		t.Errorf("re-adding a claim = %v, %v; want no change", changed, err)
+	}
+}
+
+func TestClaimsCondition(t *testing.T) {
+	for _, tc := range []struct {
+		name                    string
+		notAllowed, notAccepted []schema.GroupResource
+		status                  metav1.ConditionStatus
+		reason                  string
+		mentions                []string
+	}{
+		{"satisfied", nil, nil, metav1.ConditionTrue, ReasonClaimed, nil},
+		{"not allowed", []schema.GroupResource{leases}, nil, metav1.ConditionFalse, ReasonClaimNotAllowed, []string{"leases.coordination.k8s.io"}},
+		{"not accepted", nil, []schema.GroupResource{configmaps}, metav1.ConditionFalse, ReasonClaimNotAccepted, []string{"configmaps"}},
+		{"both", []schema.GroupResource{leases}, []schema.GroupResource{configmaps}, metav1.ConditionFalse, ReasonClaimNotAllowed,
+			[]string{"leases.coordination.k8s.io", "configmaps"}},
+	} {
+		cond := claimsCondition(tc.notAllowed, tc.notAccepted, 3)
+		if cond.Status != tc.status || cond.Reason != tc.reason || cond.ObservedGeneration != 3 {
+			t.Errorf("%s: condition = %+v, want %s/%s", tc.name, cond, tc.status, tc.reason)
+		}
+		for _, m := range tc.mentions {
+			if !strings.Contains(cond.Message, m) {
+				t.Errorf("%s: message %q does not name %s", tc.name, cond.Message, m)
+			}
+		}
+	}
+}
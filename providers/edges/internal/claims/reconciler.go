@@ -0,0 +1,222 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package claims
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	edgesv1alpha1 "github.com/faroshq/provider-edges/apis/v1alpha1"
+	"github.com/faroshq/provider-edges/internal/render"
+
+	mcbuilder "sigs.k8s.io/multicluster-runtime/pkg/builder"
+	mcmanager "sigs.k8s.io/multicluster-runtime/pkg/manager"
+	mcreconcile "sigs.k8s.io/multicluster-runtime/pkg/reconcile"
+)
+
+const controllerName = "workload-claims"
+
+// acceptanceRecheck is how often a Workload waiting on the workspace to
+// accept a claim is looked at again; APIBindings are not watched.
+const acceptanceRecheck = time.Minute
+
+var (
+	apiExportGVR    = schema.GroupVersionResource{Group: "apis.kcp.io", Version: "v1alpha2", Resource: "apiexports"}
+	catalogEntryGVR = schema.GroupVersionResource{Group: "providers.kedge.faros.sh", Version: "v1alpha1", Resource: "catalogentries"}
+)
+
+// Reconciler matches the export's permission claims to the Workloads of every
+// tenant workspace.
+type Reconciler struct {
+	mgr mcmanager.Manager
+
+	// provider reads and writes the APIExport and CatalogEntry in the
+	// provider workspace; mapper tells which kinds kcp serves there, as in
+	// any workspace.
+	provider     dynamic.Interface
+	mapper       meta.RESTMapper
+	exportName   string
+	catalogEntry string
+	allow        Allowlist
+}
+
+// SetupWithManager registers the claims reconciler. config targets the
+// provider workspace holding the APIExport exportName and the CatalogEntry
+// catalogEntry; allow lists the resources the reconciler may add claims on.
+func SetupWithManager(mgr mcmanager.Manager, config *rest.Config, exportName, catalogEntry string, allow Allowlist) error {
+	dyn, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("dynamic client: %w", err)
+	}
+	disco, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return fmt.Errorf("discovery client: %w", err)
+	}
+	r := &Reconciler{
+		mgr:          mgr,
+		provider:     dyn,
+		mapper:       restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(disco)),
+		exportName:   exportName,
+		catalogEntry: catalogEntry,
+		allow:        allow,
+	}
+	klog.Info("Registering Workload permission claims controller")
+	return mcbuilder.ControllerManagedBy(mgr).
+		Named(controllerName).
+		For(&edgesv1alpha1.Workload{}, mcbuilder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
+
+// Reconcile renders a Workload, claims what its bundle needs and may be
+// claimed, and reports what is still missing.
+func (r *Reconciler) Reconcile(ctx context.Context, req mcreconcile.Request) (ctrl.Result, error) {
+	logger := klog.FromContext(ctx).WithValues("key", req.NamespacedName, "cluster", req.ClusterName)
+
+	cl, err := r.mgr.GetCluster(ctx, req.ClusterName)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("getting cluster %s: %w", req.ClusterName, err)
+	}
+	c := cl.GetClient()
+
+	var vw edgesv1alpha1.Workload
+	if err := c.Get(ctx, req.NamespacedName, &vw); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// The scheduler renders the same bundle and reports its failures.
+	objs, err := render.Render(ctx, &vw)
+	if err != nil {
+		logger.V(2).Info("Cannot render workload yet", "err", err)
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+	required, err := requiredClaims(objs, r.mapper)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	export, err := r.provider.Resource(apiExportGVR).Get(ctx, r.exportName, metav1.GetOptions{})
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("getting APIExport %s: %w", r.exportName, err)
+	}
+	claimed := claimedResources(export, "spec", "permissionClaims")
+	var expand, notAllowed, notAccepted []schema.GroupResource
+	for _, rc := range required {
+		switch {
+		case claimed.Has(rc.resource):
+		case r.allow[rc.resource]:
+			expand = append(expand, rc.resource)
+		default:
+			notAllowed = append(notAllowed, rc.resource)
+		}
+	}
+	if len(expand) > 0 {
+		if err := r.expand(ctx, export, expand); err != nil {
+			return ctrl.Result{}, err
+		}
+		logger.Info("Added permission claims to the edges APIExport", "resources", joinResources(expand))
+	}
+
+	for _, rc := range required {
+		if !claimed.Has(rc.resource) && !r.allow[rc.resource] {
+			continue
+		}
+		ok, err := accepted(ctx, c, rc.kind)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("probing %s: %w", rc.resource, err)
+		}
+		if !ok {
+			notAccepted = append(notAccepted, rc.resource)
+		}
+	}
+
+	if meta.SetStatusCondition(&vw.Status.Conditions, claimsCondition(notAllowed, notAccepted, vw.Generation)) {
+		if err := c.Status().Update(ctx, &vw); err != nil {
+			return ctrl.Result{}, fmt.Errorf("updating Workload status: %w", err)
+		}
+	}
+	if len(notAccepted) > 0 {
+		return ctrl.Result{RequeueAfter: acceptanceRecheck}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// expand adds claims on grs to the APIExport and, so the Enable dialog offers
+// them, to the CatalogEntry when the provider registers it itself. Both are
+// rewritten from the chart's claims when the provider's init runs again; the
+// claims are added back as Workloads are reconciled on start.
+func (r *Reconciler) expand(ctx context.Context, export *unstructured.Unstructured, grs []schema.GroupResource) error {
+	if _, err := addClaims(export, grs, nil, "spec", "permissionClaims"); err != nil {
+		return fmt.Errorf("adding claims to APIExport %s: %w", r.exportName, err)
+	}
+	if _, err := r.provider.Resource(apiExportGVR).Update(ctx, export, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("updating APIExport %s: %w", r.exportName, err)
+	}
+
+	entry, err := r.provider.Resource(catalogEntryGVR).Get(ctx, r.catalogEntry, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("getting CatalogEntry %s: %w", r.catalogEntry, err)
+	}
+	changed, err := addClaims(entry, grs, map[string]interface{}{"tenantScoped": true}, "spec", "apiExport", "permissionClaims")
+	if err != nil {
+		return fmt.Errorf("adding claims to CatalogEntry %s: %w", r.catalogEntry, err)
+	}
+	if !changed {
+		return nil
+	}
+	if _, err := r.provider.Resource(catalogEntryGVR).Update(ctx, entry, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("updating CatalogEntry %s: %w", r.catalogEntry, err)
+	}
+	return nil
+}
+
+// accepted reports whether the workspace c reaches through the export's
+// virtual workspace lets the provider list kind, that is whether its
+// APIBinding accepted the claim on it.
+func accepted(ctx context.Context, c client.Client, kind schema.GroupVersionKind) (bool, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(kind.GroupVersion().WithKind(kind.Kind + "List"))
+	err := c.List(ctx, list, client.Limit(1))
+	switch {
+	case err == nil:
+		return true, nil
+	case meta.IsNoMatchError(err) || apierrors.IsForbidden(err) || apierrors.IsNotFound(err):
+		return false, nil
+	}
+	return false, err
+}
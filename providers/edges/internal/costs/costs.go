@@ -0,0 +1,200 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package costs attributes placements to cost centers. The scheduler copies
+// the configured cost labels from each Workload onto its Placements; this
+// package indexes the Placements by those labels and reports placement
+// counts and replicas per label value, as Prometheus metrics and as a
+// per-workspace summary at /costs.
+package costs
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+
+	edgesv1alpha1 "github.com/faroshq/provider-edges/apis/v1alpha1"
+)
+
+// Group is the usage of every placement carrying one value of a cost label.
+// Placements without the label are grouped under the empty Value, so the
+// groups of one label add up to the workspace's total.
+type Group struct {
+	Label         string `json:"label"`
+	Value         string `json:"value"`
+	Placements    int    `json:"placements"`
+	Replicas      int64  `json:"replicas"`
+	ReadyReplicas int64  `json:"readyReplicas"`
+}
+
+type placementKey struct {
+	cluster string
+	types.NamespacedName
+}
+
+type usage struct {
+	values   map[string]string
+	replicas int32
+	ready    int32
+}
+
+// Index tracks the cost labels and replicas of every Placement across the
+// provider's workspaces. It is safe for concurrent use.
+type Index struct {
+	labels []string
+
+	mu         sync.RWMutex
+	placements map[placementKey]usage
+}
+
+// NewIndex returns an empty Index attributing placements by labels.
+func NewIndex(labels []string) *Index {
+	return &Index{labels: labels, placements: map[placementKey]usage{}}
+}
+
+// Labels returns the cost label keys the index attributes by.
+func (ix *Index) Labels() []string {
+	return ix.labels
+}
+
+// Set records p, in the workspace cluster, replacing what was recorded for
+// it before. A Placement without spec.replicas counts as one replica, as the
+// agent runs it.
+func (ix *Index) Set(cluster string, p *edgesv1alpha1.Placement) {
+	u := usage{values: map[string]string{}, replicas: 1, ready: p.Status.ReadyReplicas}
+	if p.Spec.Replicas != nil {
+		u.replicas = *p.Spec.Replicas
+	}
+	for _, l := range ix.labels {
+		u.values[l] = p.Labels[l]
+	}
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.placements[placementKey{cluster, types.NamespacedName{Namespace: p.Namespace, Name: p.Name}}] = u
+}
+
+// Delete forgets the Placement name in the workspace cluster.
+func (ix *Index) Delete(cluster string, name types.NamespacedName) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	delete(ix.placements, placementKey{cluster, name})
+}
+
+// Summary returns the usage per cost label value in the workspace cluster,
+// sorted by label and value.
+func (ix *Index) Summary(cluster string) []Group {
+	groups := ix.groups(func(c string) bool { return c == cluster })
+	out := make([]Group, 0, len(groups))
+	for k, g := range groups {
+		g.Label, g.Value = k.label, k.value
+		out = append(out, *g)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Label != out[j].Label {
+			return out[i].Label < out[j].Label
+		}
+		return out[i].Value < out[j].Value
+	})
+	return out
+}
+
+type groupKey struct {
+	cluster, label, value string
+}
+
+// groups sums the placements of the workspaces match accepts.
+func (ix *Index) groups(match func(cluster string) bool) map[groupKey]*Group {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	groups := map[groupKey]*Group{}
+	for k, u := range ix.placements {
+		if !match(k.cluster) {
+			continue
+		}
+		for _, l := range ix.labels {
+			gk := groupKey{k.cluster, l, u.values[l]}
+			g := groups[gk]
+			if g == nil {
+				g = &Group{}
+				groups[gk] = g
+			}
+			g.Placements++
+			g.Replicas += int64(u.replicas)
+			g.ReadyReplicas += int64(u.ready)
+		}
+	}
+	return groups
+}
+
+// ServeHTTP serves the caller's workspace summary as {"groups": [...]},
+// optionally narrowed to one cost label with ?label=. The workspace is the
+// X-Kedge-Cluster header, which the hub's backend proxy sets after
+// authenticating the caller.
+func (ix *Index) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	cluster := r.Header.Get("X-Kedge-Cluster")
+	if cluster == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	groups := ix.Summary(cluster)
+	if label := r.URL.Query().Get("label"); label != "" {
+		filtered := groups[:0]
+		for _, g := range groups {
+			if g.Label == label {
+				filtered = append(filtered, g)
+			}
+		}
+		groups = filtered
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(struct {
+		Groups []Group `json:"groups"`
+	}{groups})
+}
+
+var (
+	placementsDesc = prometheus.NewDesc("kedge_edges_cost_placements",
+		"Placements per cost label value.", []string{"workspace", "label", "value"}, nil)
+	replicasDesc = prometheus.NewDesc("kedge_edges_cost_replicas",
+		"Desired replicas of the placements per cost label value.", []string{"workspace", "label", "value"}, nil)
+	readyReplicasDesc = prometheus.NewDesc("kedge_edges_cost_ready_replicas",
+		"Ready replicas of the placements per cost label value.", []string{"workspace", "label", "value"}, nil)
+)
+
+// Describe implements prometheus.Collector.
+func (ix *Index) Describe(ch chan<- *prometheus.Desc) {
+	ch <- placementsDesc
+	ch <- replicasDesc
+	ch <- readyReplicasDesc
+}
+
+// Collect implements prometheus.Collector, reporting every workspace.
+func (ix *Index) Collect(ch chan<- prometheus.Metric) {
+	for k, g := range ix.groups(func(string) bool { return true }) {
+		ch <- prometheus.MustNewConstMetric(placementsDesc, prometheus.GaugeValue, float64(g.Placements), k.cluster, k.label, k.value)
+		ch <- prometheus.MustNewConstMetric(replicasDesc, prometheus.GaugeValue, float64(g.Replicas), k.cluster, k.label, k.value)
+		ch <- prometheus.MustNewConstMetric(readyReplicasDesc, prometheus.GaugeValue, float64(g.ReadyReplicas), k.cluster, k.label, k.value)
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package costs
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	edgesv1alpha1 "github.com/faroshq/provider-edges/apis/v1alpha1"
+)
+
+func testPlacement(name, team string, replicas *int32, ready int32) *edgesv1alpha1.Placement {
+	p := &edgesv1alpha1.Placement{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name}}
+	if team != "" {
+		p.Labels = map[string]string{"team": team}
+	}
+	p.Spec.Replicas = replicas
+	p.Status.ReadyReplicas = ready
+	return p
+}
+
+func TestIndexSummary(t *testing.T) {
+	ix := NewIndex([]string{"team"})
+	ix.Set("ws1", testPlacement("a", "pos", ptr.To[int32](3), 2))
+	ix.Set("ws1", testPlacement("b", "pos", nil, 1))
+	ix.Set("ws1", testPlacement("c", "", ptr.To[int32](2), 0))
+	ix.Set("ws2", testPlacement("a", "pos", ptr.To[int32](5), 5))
+
+	got := ix.Summary("ws1")
+	want := []Group{
+		{Label: "team", Value: "", Placements: 1, Replicas: 2},
+		{Label: "team", Value: "pos", Placements: 2, Replicas: 4, ReadyReplicas: 3},
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("summary = %+v, want %+v", got, want)
+	}
+
+	// A relabeled placement moves groups; a deleted one drops out.
+	ix.Set("ws1", testPlacement("b", "kiosk", nil, 1))
+	ix.Delete("ws1", types.NamespacedName{Namespace: "default", Name: "c"})
+	got = ix.Summary("ws1")
+	if len(got) != 2 || got[0].Value != "kiosk" || got[1].Value != "pos" || got[1].Placements != 1 {
+		t.Errorf("summary after changes = %+v", got)
+	}
+}
+
+func TestIndexServeHTTP(t *testing.T) {
+	ix := NewIndex([]string{"team", "cost-center"})
+	ix.Set("ws1", testPlacement("a", "pos", nil, 1))
+
+	rec := httptest.NewRecorder()
+	ix.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/costs", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("without a workspace = %d, want 401", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/costs?label=team", nil)
+	req.Header.Set("X-Kedge-Cluster", "ws1")
+	rec = httptest.NewRecorder()
+	ix.ServeHTTP(rec, req)
+	var body struct {
+		Groups []Group `json:"groups"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Groups) != 1 || body.Groups[0] != (Group{Label: "team", Value: "pos", Placements: 1, Replicas: 1, ReadyReplicas: 1}) {
+		t.Errorf("groups = %+v", body.Groups)
+	}
+}
+
+func TestIndexCollect(t *testing.T) {
+	ix := NewIndex([]string{"team"})
+	ix.Set("ws1", testPlacement("a", "pos", ptr.To[int32](3), 2))
+	ix.Set("ws2", testPlacement("a", "pos", nil, 0))
+
+	want := `
+# HELP kedge_edges_cost_replicas Desired replicas of the placements per cost label value.
+# TYPE kedge_edges_cost_replicas gauge
+kedge_edges_cost_replicas{label="team",value="pos",workspace="ws1"} 3
+kedge_edges_cost_replicas{label="team",value="pos",workspace="ws2"} 1
+`
+	if err := testutil.CollectAndCompare(ix, strings.NewReader(want), "kedge_edges_cost_replicas"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package costs
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	edgesv1alpha1 "github.com/faroshq/provider-edges/apis/v1alpha1"
+
+	mcbuilder "sigs.k8s.io/multicluster-runtime/pkg/builder"
+	mcmanager "sigs.k8s.io/multicluster-runtime/pkg/manager"
+	mcreconcile "sigs.k8s.io/multicluster-runtime/pkg/reconcile"
+)
+
+const controllerName = "cost-index"
+
+// Reconciler keeps an Index in step with the Placements of every workspace.
+type Reconciler struct {
+	mgr   mcmanager.Manager
+	index *Index
+}
+
+// SetupWithManager registers the Placement indexer with the multicluster
+// manager.
+func SetupWithManager(mgr mcmanager.Manager, index *Index) error {
+	r := &Reconciler{mgr: mgr, index: index}
+	return mcbuilder.ControllerManagedBy(mgr).
+		Named(controllerName).
+		For(&edgesv1alpha1.Placement{}).
+		Complete(r)
+}
+
+// Reconcile records one Placement in the index, or forgets a deleted one.
+func (r *Reconciler) Reconcile(ctx context.Context, req mcreconcile.Request) (ctrl.Result, error) {
+	cl, err := r.mgr.GetCluster(ctx, req.ClusterName)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("getting cluster %s: %w", req.ClusterName, err)
+	}
+
+	var p edgesv1alpha1.Placement
+	if err := cl.GetClient().Get(ctx, req.NamespacedName, &p); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.index.Delete(string(req.ClusterName), req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	r.index.Set(string(req.ClusterName), &p)
+	return ctrl.Result{}, nil
+}
@@ -22,6 +22,8 @@ limitations under the License.
 package edgeapi
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -47,10 +49,64 @@ const ConnectionConditionRegistered = "Registered"
 // separate lookup.
 const ConnectionConditionUpgradeAvailable = "UpgradeAvailable"
 
+// ConnectionConditionVersionSupported is set by the version reconciler from
+// the agent-vs-hub version skew policy: True while the agent is at most
+// versionskew.MaxAgentLag releases behind the hub and not ahead of it, False
+// (reason UnsupportedVersion) outside that window, Unknown for dev builds or
+// an unreported version. With KEDGE_AGENT_VERSION_SKEW=reject the hub also
+// refuses the tunnel of an agent outside the window.
+const ConnectionConditionVersionSupported = "VersionSupported"
+
+// ConnectionConditionAgentHealthy is written by the agent itself: True while
+// none of its subsystems (tunnel, heartbeat, workload reconciler, status
+// reporters, edge cluster credential) is failing, otherwise False with the
+// most severe failure. Fatal reasons (Unauthorized, Forbidden,
+// TLSVerificationFailed, UnsupportedVersion, DownstreamUnauthorized) need an
+// operator; transient ones (HubUnreachable, RateLimited, HubError, Error) are
+// retried.
+const ConnectionConditionAgentHealthy = "AgentHealthy"
+
+// ConnectionConditionDraining is True while the edge is cordoned or being
+// deleted: the hub refuses new k8s and ssh sessions to it and closes the open
+// ones once the drain grace period, counted from the condition's
+// lastTransitionTime, has passed. The reason says why (DrainReasonCordoned,
+// DrainReasonDeleting). It turns False with DrainReasonUncordoned when the
+// cordon is lifted. The agent sets it itself, with DrainReasonAgentShutdown,
+// while it shuts down, and removes that once it runs again.
+const ConnectionConditionDraining = "Draining"
+
+// Draining condition reasons.
+const (
+	DrainReasonCordoned      = "Cordoned"
+	DrainReasonDeleting      = "Deleting"
+	DrainReasonUncordoned    = "Uncordoned"
+	DrainReasonAgentShutdown = "AgentShutdown"
+)
+
+// FinalizerDrain holds a connectable's deletion until its sessions have been
+// drained.
+const FinalizerDrain = "edges.kedge.faros.sh/drain"
+
 // AnnotationRegenerateJoinToken, set on a connectable resource, instructs the
 // token reconciler to mint a fresh bootstrap join token.
 const AnnotationRegenerateJoinToken = "edges.kedge.faros.sh/regenerate-join-token"
 
+// AnnotationApprovedCertificateRequest, set on a connectable resource to the
+// public-key fingerprint in its status.certificate.pendingRequest, approves
+// the agent's certificate signing request when the hub requires manual
+// approval of certificate joins.
+const AnnotationApprovedCertificateRequest = "edges.kedge.faros.sh/approved-certificate-request"
+
+// AnnotationRegistration, set on a connectable resource to
+// RegistrationExternal, marks it as provisioned by an admin for an agent
+// running with --registration-mode=external. The agent's ServiceAccount is
+// then bound to a role that may read the resource and patch only its status.
+const AnnotationRegistration = "edges.kedge.faros.sh/registration"
+
+// RegistrationExternal is the AnnotationRegistration value for admin-provisioned
+// edges.
+const RegistrationExternal = "external"
+
 // ConnectionStatus is the tunnel/connection state shared by every connectable
 // kind. Providers embed it (inline) into their kind's Status.
 type ConnectionStatus struct {
@@ -78,11 +134,133 @@ type ConnectionStatus struct {
 	// LastHeartbeatTime is the most recent agent heartbeat.
 	// +optional
 	LastHeartbeatTime *metav1.Time `json:"lastHeartbeatTime,omitempty"`
+	// HeartbeatIntervalSeconds is how often the agent heartbeats. The edge
+	// is marked Disconnected once LastHeartbeatTime is three intervals old.
+	// +optional
+	HeartbeatIntervalSeconds int32 `json:"heartbeatIntervalSeconds,omitempty"`
+	// Tunnel identifies the provider replica holding the agent's tunnel.
+	// Unset while disconnected.
+	// +optional
+	Tunnel *TunnelAffinity `json:"tunnel,omitempty"`
+	// TunnelTraffic accounts for the traffic the agent proxied over its
+	// tunnel and the bandwidth limits it applies.
+	// +optional
+	TunnelTraffic *TunnelTraffic `json:"tunnelTraffic,omitempty"`
+	// Certificate describes the client certificate the hub issued to the
+	// agent through a certificate join.
+	// +optional
+	Certificate *AgentCertificate `json:"certificate,omitempty"`
 	// Conditions represent the latest observations of state.
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// AgentCertificate tracks the per-edge client certificate of an agent that
+// joined with a certificate signing request.
+type AgentCertificate struct {
+	// SerialNumber is the hex serial of the current certificate. Only it
+	// authenticates the agent: issuing a new one revokes the previous.
+	// +optional
+	SerialNumber string `json:"serialNumber,omitempty"`
+	// NotAfter is when the current certificate expires.
+	// +optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+	// PendingRequest is the SHA-256 fingerprint of the public key of a
+	// certificate signing request awaiting approval.
+	// +optional
+	PendingRequest string `json:"pendingRequest,omitempty"`
+}
+
+// TunnelTraffic is what the agent proxied over its tunnel since it started.
+type TunnelTraffic struct {
+	// BytesSent is the number of bytes the agent sent to the hub.
+	// +optional
+	BytesSent int64 `json:"bytesSent,omitempty"`
+	// BytesReceived is the number of bytes the agent received from the hub.
+	// +optional
+	BytesReceived int64 `json:"bytesReceived,omitempty"`
+	// Sessions is the number of proxied sessions the hub opened.
+	// +optional
+	Sessions int64 `json:"sessions,omitempty"`
+	// UploadLimitBytesPerSecond caps the bytes sent to the hub. Unset means
+	// unlimited.
+	// +optional
+	UploadLimitBytesPerSecond int64 `json:"uploadLimitBytesPerSecond,omitempty"`
+	// DownloadLimitBytesPerSecond caps the bytes received from the hub.
+	// Unset means unlimited.
+	// +optional
+	DownloadLimitBytesPerSecond int64 `json:"downloadLimitBytesPerSecond,omitempty"`
+}
+
+// TunnelAffinity is a lease-style hint naming the provider replica that
+// terminates an agent's tunnel, so load balancers and the CLI can route proxy
+// traffic for the edge to that replica. The holder renews RenewTime on every
+// heartbeat; a hint whose lease has run out was left behind by a replica that
+// died and must be ignored.
+type TunnelAffinity struct {
+	// Holder is the replica's identity (its pod name).
+	Holder string `json:"holder"`
+	// Zone is the holder's topology zone.
+	// +optional
+	Zone string `json:"zone,omitempty"`
+	// Endpoint is the holder's directly reachable address (host:port).
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+	// AcquireTime is when the holder accepted the current tunnel.
+	// +optional
+	AcquireTime *metav1.Time `json:"acquireTime,omitempty"`
+	// RenewTime is when the holder last renewed the lease.
+	// +optional
+	RenewTime *metav1.Time `json:"renewTime,omitempty"`
+	// LeaseDurationSeconds is how long after RenewTime the hint stays valid.
+	// +optional
+	LeaseDurationSeconds int32 `json:"leaseDurationSeconds,omitempty"`
+}
+
+// Expired reports whether the lease has run out at now. A lease without a
+// RenewTime or duration never expires.
+func (t *TunnelAffinity) Expired(now time.Time) bool {
+	if t.RenewTime == nil || t.LeaseDurationSeconds <= 0 {
+		return false
+	}
+	return now.After(t.RenewTime.Add(time.Duration(t.LeaseDurationSeconds) * time.Second))
+}
+
+// HeldByPeer reports whether a live lease names a replica other than self,
+// which then owns the tunnel and whatever needs it. Nil means no lease.
+func (t *TunnelAffinity) HeldByPeer(self string, now time.Time) bool {
+	return t != nil && t.Holder != "" && t.Holder != self && !t.Expired(now)
+}
+
+// Location is where an edge physically sits. It is set when the edge is
+// created or, if left empty, filled in once by the agent from its
+// --location-* flags, and is what the hub's fleet map plots.
+//
+// +kubebuilder:validation:XValidation:rule="has(self.latitude) == has(self.longitude)",message="latitude and longitude must be set together"
+type Location struct {
+	// Latitude in decimal degrees (WGS 84).
+	// +kubebuilder:validation:Minimum=-90
+	// +kubebuilder:validation:Maximum=90
+	// +optional
+	Latitude *float64 `json:"latitude,omitempty"`
+	// Longitude in decimal degrees (WGS 84).
+	// +kubebuilder:validation:Minimum=-180
+	// +kubebuilder:validation:Maximum=180
+	// +optional
+	Longitude *float64 `json:"longitude,omitempty"`
+	// Address is a free-form postal address or site name.
+	// +optional
+	Address string `json:"address,omitempty"`
+	// Region is a coarse grouping such as "eu-west" or "store-cluster-7".
+	// +optional
+	Region string `json:"region,omitempty"`
+}
+
+// HasCoordinates reports whether l can be placed on a map.
+func (l *Location) HasCoordinates() bool {
+	return l != nil && l.Latitude != nil && l.Longitude != nil
+}
+
 // Connectable is implemented by every connectable kind. It exposes the shared
 // ConnectionStatus so the SDK's token/rbac/lifecycle reconcilers operate on all
 // kinds with one code path.
@@ -92,6 +270,8 @@ type Connectable interface {
 	client.Object
 	// GetConnectionStatus returns a pointer to the embedded ConnectionStatus.
 	GetConnectionStatus() *ConnectionStatus
+	// IsCordoned reports whether the spec takes the edge out of service.
+	IsCordoned() bool
 }
 
 // SSHUserMappingMode controls SSH username selection for SSH-server kinds.
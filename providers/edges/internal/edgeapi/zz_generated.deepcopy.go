@@ -9,6 +9,25 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentCertificate) DeepCopyInto(out *AgentCertificate) {
+	*out = *in
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentCertificate.
+func (in *AgentCertificate) DeepCopy() *AgentCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConnectionStatus) DeepCopyInto(out *ConnectionStatus) {
 	*out = *in
@@ -23,6 +42,21 @@ func (in *ConnectionStatus) DeepCopyInto(out *ConnectionStatus) {
 		in, out := &in.LastHeartbeatTime, &out.LastHeartbeatTime
 		*out = (*in).DeepCopy()
 	}
+	if in.Tunnel != nil {
+		in, out := &in.Tunnel, &out.Tunnel
+		*out = new(TunnelAffinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TunnelTraffic != nil {
+		in, out := &in.TunnelTraffic, &out.TunnelTraffic
+		*out = new(TunnelTraffic)
+		**out = **in
+	}
+	if in.Certificate != nil {
+		in, out := &in.Certificate, &out.Certificate
+		*out = new(AgentCertificate)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -42,6 +76,31 @@ func (in *ConnectionStatus) DeepCopy() *ConnectionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Location) DeepCopyInto(out *Location) {
+	*out = *in
+	if in.Latitude != nil {
+		in, out := &in.Latitude, &out.Latitude
+		*out = new(float64)
+		**out = **in
+	}
+	if in.Longitude != nil {
+		in, out := &in.Longitude, &out.Longitude
+		*out = new(float64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Location.
+func (in *Location) DeepCopy() *Location {
+	if in == nil {
+		return nil
+	}
+	out := new(Location)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SSHCredentials) DeepCopyInto(out *SSHCredentials) {
 	*out = *in
@@ -66,3 +125,41 @@ func (in *SSHCredentials) DeepCopy() *SSHCredentials {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TunnelAffinity) DeepCopyInto(out *TunnelAffinity) {
+	*out = *in
+	if in.AcquireTime != nil {
+		in, out := &in.AcquireTime, &out.AcquireTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RenewTime != nil {
+		in, out := &in.RenewTime, &out.RenewTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TunnelAffinity.
+func (in *TunnelAffinity) DeepCopy() *TunnelAffinity {
+	if in == nil {
+		return nil
+	}
+	out := new(TunnelAffinity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TunnelTraffic) DeepCopyInto(out *TunnelTraffic) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TunnelTraffic.
+func (in *TunnelTraffic) DeepCopy() *TunnelTraffic {
+	if in == nil {
+		return nil
+	}
+	out := new(TunnelTraffic)
+	in.DeepCopyInto(out)
+	return out
+}
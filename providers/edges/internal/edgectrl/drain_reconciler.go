@@ -0,0 +1,189 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edgectrl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	edgeapi "github.com/faroshq/provider-edges/internal/edgeapi"
+
+	mcbuilder "sigs.k8s.io/multicluster-runtime/pkg/builder"
+	mccontroller "sigs.k8s.io/multicluster-runtime/pkg/controller"
+	mcmanager "sigs.k8s.io/multicluster-runtime/pkg/manager"
+	mcreconcile "sigs.k8s.io/multicluster-runtime/pkg/reconcile"
+)
+
+// DefaultDrainGracePeriod is how long sessions open when an edge starts
+// draining may run before they are closed.
+const DefaultDrainGracePeriod = 5 * time.Minute
+
+// Drainer is the tunnel plane's side of a drain: it refuses new sessions to
+// a draining edge and closes the open ones at the deadline.
+type Drainer interface {
+	// Drain marks key as draining for reason, closing its sessions at deadline.
+	Drain(key, reason string, deadline time.Time)
+	// Undrain lifts a drain; sessions are accepted again.
+	Undrain(key string)
+}
+
+// DrainReconciler maintains the Draining condition of cordoned and deleting
+// edges and mirrors it into the tunnel plane. The condition is the record:
+// its lastTransitionTime fixes the deadline, so a provider restart resumes a
+// drain where it was rather than granting a fresh grace period.
+type DrainReconciler struct {
+	mgr         mcmanager.Manager
+	connManager ConnManager
+	drainer     Drainer
+	newObj      func() edgeapi.Connectable
+	resource    string
+	grace       time.Duration
+	// instance is this replica's name in edges' status.tunnel.
+	instance string
+}
+
+// SetupDrainWithManager registers the drain controller for one connectable
+// kind. drainer may be nil, in which case only the condition and the
+// deletion finalizer are maintained. Every replica drains its own sessions,
+// so it runs on every replica, leader election or not.
+func SetupDrainWithManager(mgr mcmanager.Manager, gvr schema.GroupVersionResource, newObj func() edgeapi.Connectable, connManager ConnManager, drainer Drainer, grace time.Duration, instance string) error {
+	if grace <= 0 {
+		grace = DefaultDrainGracePeriod
+	}
+	r := &DrainReconciler{mgr: mgr, connManager: connManager, drainer: drainer, newObj: newObj, resource: gvr.Resource, grace: grace, instance: instance}
+	return mcbuilder.ControllerManagedBy(mgr).
+		Named("drain-" + gvr.Resource).
+		For(newObj()).
+		WithOptions(mccontroller.Options{NeedLeaderElection: ptr.To(false)}).
+		Complete(r)
+}
+
+// Reconcile sets Draining=True while the edge is cordoned or being deleted
+// and False once a cordon is lifted. A deleting edge keeps the drain
+// finalizer until its grace period is over or it has no tunnel left to
+// carry sessions.
+func (r *DrainReconciler) Reconcile(ctx context.Context, req mcreconcile.Request) (ctrl.Result, error) {
+	logger := klog.FromContext(ctx).WithValues("edge", req.Name, "cluster", req.ClusterName)
+
+	cl, err := r.mgr.GetCluster(ctx, req.ClusterName)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("getting cluster %s: %w", req.ClusterName, err)
+	}
+	c := cl.GetClient()
+
+	key := connKey(r.resource, string(req.ClusterName), req.Name)
+	edge := r.newObj()
+	if err := c.Get(ctx, req.NamespacedName, edge); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.undrain(key)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	deleting := !edge.GetDeletionTimestamp().IsZero()
+	if !deleting && controllerutil.AddFinalizer(edge, edgeapi.FinalizerDrain) {
+		if err := c.Update(ctx, edge); err != nil {
+			return ctrl.Result{}, fmt.Errorf("adding drain finalizer: %w", err)
+		}
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	cs := edge.GetConnectionStatus()
+	desired, draining := drainCondition(deleting, edge.IsCordoned(), meta.FindStatusCondition(cs.Conditions, edgeapi.ConnectionConditionDraining))
+	if desired != nil && meta.SetStatusCondition(&cs.Conditions, *desired) {
+		if err := c.Status().Update(ctx, edge); err != nil {
+			return ctrl.Result{}, fmt.Errorf("updating draining condition: %w", err)
+		}
+		logger.Info("Updated draining condition", "status", desired.Status, "reason", desired.Reason)
+	}
+	if !draining {
+		r.undrain(key)
+		return ctrl.Result{}, nil
+	}
+
+	since := meta.FindStatusCondition(cs.Conditions, edgeapi.ConnectionConditionDraining).LastTransitionTime.Time
+	deadline := since.Add(r.grace)
+	if r.drainer != nil {
+		r.drainer.Drain(key, desired.Reason, deadline)
+	}
+	if !deleting {
+		return ctrl.Result{}, nil
+	}
+
+	remaining := time.Until(deadline)
+	tunnelUp := r.connManager.HasConnection(key) || cs.Tunnel.HeldByPeer(r.instance, time.Now())
+	if remaining > 0 && tunnelUp {
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+	if controllerutil.RemoveFinalizer(edge, edgeapi.FinalizerDrain) {
+		if err := c.Update(ctx, edge); err != nil && !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, fmt.Errorf("removing drain finalizer: %w", err)
+		}
+		logger.Info("Edge drained, releasing deletion")
+	}
+	r.undrain(key)
+	return ctrl.Result{}, nil
+}
+
+func (r *DrainReconciler) undrain(key string) {
+	if r.drainer != nil {
+		r.drainer.Undrain(key)
+	}
+}
+
+// drainCondition returns the Draining condition an edge should carry given
+// its deletion and cordon state and the current condition, and whether the
+// edge is draining. A nil condition means leave it as it is: an edge that
+// was never cordoned carries none, and a drain its agent reported while
+// shutting down is the agent's to lift.
+func drainCondition(deleting, cordoned bool, existing *metav1.Condition) (*metav1.Condition, bool) {
+	switch {
+	case deleting:
+		return &metav1.Condition{
+			Type:    edgeapi.ConnectionConditionDraining,
+			Status:  metav1.ConditionTrue,
+			Reason:  edgeapi.DrainReasonDeleting,
+			Message: "Edge is being deleted; new sessions are refused.",
+		}, true
+	case cordoned:
+		return &metav1.Condition{
+			Type:    edgeapi.ConnectionConditionDraining,
+			Status:  metav1.ConditionTrue,
+			Reason:  edgeapi.DrainReasonCordoned,
+			Message: "Edge is cordoned; new sessions are refused.",
+		}, true
+	case existing != nil && existing.Status == metav1.ConditionTrue && existing.Reason != edgeapi.DrainReasonAgentShutdown:
+		return &metav1.Condition{
+			Type:    edgeapi.ConnectionConditionDraining,
+			Status:  metav1.ConditionFalse,
+			Reason:  edgeapi.DrainReasonUncordoned,
+			Message: "Edge accepts sessions.",
+		}, false
+	}
+	return nil, false
+}
@@ -24,12 +24,14 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	edgesv1alpha1 "github.com/faroshq/provider-edges/apis/v1alpha1"
 	edgeapi "github.com/faroshq/provider-edges/internal/edgeapi"
 
 	mcbuilder "sigs.k8s.io/multicluster-runtime/pkg/builder"
+	mccontroller "sigs.k8s.io/multicluster-runtime/pkg/controller"
 	mcmanager "sigs.k8s.io/multicluster-runtime/pkg/manager"
 	mcreconcile "sigs.k8s.io/multicluster-runtime/pkg/reconcile"
 )
@@ -51,24 +53,45 @@ type LifecycleReconciler struct {
 	connManager ConnManager
 	newObj      func() edgeapi.Connectable
 	resource    string
+	// instance is this replica's name in edges' status.tunnel.
+	instance string
 }
 
 // SetupLifecycleWithManager registers the lifecycle controller for every
-// connectable kind on the multicluster manager.
-func SetupLifecycleWithManager(mgr mcmanager.Manager, gvr schema.GroupVersionResource, newObj func() edgeapi.Connectable, connManager ConnManager) error {
-	r := &LifecycleReconciler{mgr: mgr, connManager: connManager, newObj: newObj, resource: gvr.Resource}
+// connectable kind on the multicluster manager. Tunnels are per replica, so
+// it runs on every replica, leader election or not.
+func SetupLifecycleWithManager(mgr mcmanager.Manager, gvr schema.GroupVersionResource, newObj func() edgeapi.Connectable, connManager ConnManager, instance string) error {
+	r := &LifecycleReconciler{mgr: mgr, connManager: connManager, newObj: newObj, resource: gvr.Resource, instance: instance}
 	return mcbuilder.ControllerManagedBy(mgr).
 		Named("lifecycle-" + gvr.Resource).
 		For(newObj()).
+		WithOptions(mccontroller.Options{NeedLeaderElection: ptr.To(false)}).
 		Complete(r)
 }
 
-// staleHeartbeatThreshold is how long an Edge can go without a hub-stamped
-// heartbeat before this reconciler considers the tunnel stale even when
-// ConnManager still reports a live connection. Sized as 3× the agent-proxy
-// heartbeat interval (30s) — see edgeHeartbeatInterval in pkg/virtual/builder.
+// staleHeartbeatThreshold is how long an Edge can go without a heartbeat
+// before this reconciler considers the tunnel stale even when ConnManager
+// still reports a live connection. Sized as 3× the agent-proxy heartbeat
+// interval (30s) — see edgeHeartbeatInterval in internal/tunnel. It is the
+// floor: the hub stamps lastHeartbeatTime that often whatever the agent's own
+// interval.
 const staleHeartbeatThreshold = 90 * time.Second
 
+// missedHeartbeats is how many agent heartbeat intervals may pass without a
+// heartbeat before the edge is stale.
+const missedHeartbeats = 3
+
+// lifecycleResync is how often connected edges are re-checked.
+const lifecycleResync = 30 * time.Second
+
+// heartbeatThreshold is how old lastHeartbeatTime may get before the edge is
+// stale: three of the agent's reported heartbeat intervals, but no less than
+// staleHeartbeatThreshold.
+func heartbeatThreshold(cs *edgeapi.ConnectionStatus) time.Duration {
+	threshold := time.Duration(cs.HeartbeatIntervalSeconds) * time.Second * missedHeartbeats
+	return max(threshold, staleHeartbeatThreshold)
+}
+
 // Reconcile reconciles status.connected/phase against the in-process tunnel registry.
 //
 // status.connected is only flipped to true by the agent-proxy handler when a
@@ -114,8 +137,14 @@ func (r *LifecycleReconciler) Reconcile(ctx context.Context, req mcreconcile.Req
 	cs := edge.GetConnectionStatus()
 
 	hasTunnel := r.connManager.HasConnection(connKey(r.resource, string(req.ClusterName), req.Name))
+	if !hasTunnel && cs.Tunnel.HeldByPeer(r.instance, time.Now()) {
+		// Another replica holds the tunnel and tracks its liveness. Should
+		// it die, its lease runs out and the edge is checked here again.
+		return ctrl.Result{RequeueAfter: lifecycleResync}, nil
+	}
+	threshold := heartbeatThreshold(cs)
 	heartbeatStale := cs.LastHeartbeatTime != nil &&
-		time.Since(cs.LastHeartbeatTime.Time) > staleHeartbeatThreshold
+		time.Since(cs.LastHeartbeatTime.Time) > threshold
 
 	switch {
 	case cs.Connected && !hasTunnel:
@@ -126,13 +155,16 @@ func (r *LifecycleReconciler) Reconcile(ctx context.Context, req mcreconcile.Req
 			return ctrl.Result{}, fmt.Errorf("updating edge status: %w", err)
 		}
 	case cs.Connected && heartbeatStale:
-		// connManager still reports a tunnel, but the hub-side heartbeat
-		// goroutine hasn't stamped lastHeartbeatTime within the threshold.
-		// That means revdial pongs have stopped flowing — treat the edge as
-		// disconnected even though the dialer entry hasn't been evicted yet.
+		// connManager still reports a tunnel, but neither the hub-side
+		// heartbeat goroutine nor the agent has stamped lastHeartbeatTime
+		// within the threshold. That means revdial pongs have stopped flowing
+		// and the agent is not getting through either (a half-open
+		// connection) — treat the edge as disconnected even though the
+		// dialer entry hasn't been evicted yet.
 		logger.Info("Edge heartbeat stale, marking Disconnected",
 			"lastHeartbeat", cs.LastHeartbeatTime.Time,
-			"age", time.Since(cs.LastHeartbeatTime.Time).Round(time.Second))
+			"age", time.Since(cs.LastHeartbeatTime.Time).Round(time.Second),
+			"threshold", threshold)
 		cs.Connected = false
 		cs.Phase = edgeapi.ConnectionPhaseDisconnected
 		if err := c.Status().Update(ctx, edge); err != nil {
@@ -146,5 +178,13 @@ func (r *LifecycleReconciler) Reconcile(ctx context.Context, req mcreconcile.Req
 		}
 	}
 
-	return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	// Re-check a connected edge no later than the moment its heartbeat
+	// would go stale.
+	requeue := lifecycleResync
+	if cs.Connected && cs.LastHeartbeatTime != nil {
+		if untilStale := time.Until(cs.LastHeartbeatTime.Add(threshold)) + time.Second; untilStale > 0 && untilStale < requeue {
+			requeue = untilStale
+		}
+	}
+	return ctrl.Result{RequeueAfter: requeue}, nil
 }
@@ -22,4 +22,7 @@ const (
 	edgeNamespace = "kedge-system"
 	// edgeAgentClusterRole is the ClusterRole name for edge agents.
 	edgeAgentClusterRole = "kedge-edge-agent"
+	// edgeAgentStatusClusterRole is the ClusterRole name for agents of edges
+	// provisioned for external registration.
+	edgeAgentStatusClusterRole = "kedge-edge-agent-status"
 )
@@ -114,13 +114,20 @@ func (r *RBACReconciler) Reconcile(ctx context.Context, req mcreconcile.Request)
 	// 3. Ensure ClusterRole for edge agents.
 	// NOTE: the ClusterRole is a shared cluster-wide resource, not owned by
 	// individual edges.  Attaching per-edge ownerRefs would cause GC races.
-	if err := ensureClusterRole(ctx, c); err != nil {
+	// Edges an admin provisioned for external registration get the
+	// status-only role instead: their agents never create or update them.
+	roleName, rules := edgeAgentClusterRole, desiredAgentRules()
+	if edge.GetAnnotations()[edgeapi.AnnotationRegistration] == edgeapi.RegistrationExternal {
+		roleName, rules = edgeAgentStatusClusterRole, desiredStatusAgentRules()
+	}
+	if err := ensureClusterRole(ctx, c, roleName, rules); err != nil {
 		return ctrl.Result{}, fmt.Errorf("ensuring cluster role: %w", err)
 	}
 
-	// 4. Ensure ClusterRoleBinding for this edge's SA (shared operational role:
-	// get/list/watch/update on edges, placements, workloads).
-	if err := ensureClusterRoleBinding(ctx, c, saName, ownerRef); err != nil {
+	// 4. Ensure ClusterRoleBinding for this edge's SA to the role chosen
+	// above (the shared operational role: get/list/watch/update on edges,
+	// placements, workloads; or its status-only counterpart).
+	if err := ensureClusterRoleBinding(ctx, c, saName, roleName, ownerRef); err != nil {
 		return ctrl.Result{}, fmt.Errorf("ensuring cluster role binding: %w", err)
 	}
 
@@ -256,6 +263,13 @@ func desiredAgentRules() []rbacv1.PolicyRule {
 			Resources: []string{"workloads", "workloads/status"},
 			Verbs:     []string{"get", "list", "watch"},
 		},
+		// The event forwarder (--forward-events) mirrors edge cluster Events
+		// onto Placements, refreshing them when they recur.
+		{
+			APIGroups: []string{""},
+			Resources: []string{"events"},
+			Verbs:     []string{"create", "patch"},
+		},
 		// Namespaces and secrets are needed for SSH credential setup (server-type edges).
 		{
 			APIGroups: []string{""},
@@ -270,13 +284,53 @@ func desiredAgentRules() []rbacv1.PolicyRule {
 	}
 }
 
-// ensureClusterRole creates or updates the shared kedge-edge-agent ClusterRole.
+// desiredStatusAgentRules returns the PolicyRules of the ClusterRole shared
+// by agents of edges provisioned for external registration. Such an agent
+// reads its edge and writes only the /status subresource of it and of its
+// Placements, plus the Events it forwards: no create or update on edges, and
+// no Secrets, since the hub stores a server's SSH credentials from the
+// agent's tunnel headers.
+func desiredStatusAgentRules() []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{"edges.kedge.faros.sh"},
+			Resources: []string{"kubernetesclusters", "linuxservers"},
+			Verbs:     []string{"get", "list", "watch"},
+		},
+		{
+			APIGroups: []string{"edges.kedge.faros.sh"},
+			Resources: []string{"kubernetesclusters/status", "linuxservers/status"},
+			Verbs:     []string{"get", "patch"},
+		},
+		{
+			APIGroups: []string{"edges.kedge.faros.sh"},
+			Resources: []string{"placements"},
+			Verbs:     []string{"get", "list", "watch"},
+		},
+		{
+			APIGroups: []string{"edges.kedge.faros.sh"},
+			Resources: []string{"placements/status"},
+			Verbs:     []string{"get", "patch"},
+		},
+		{
+			APIGroups: []string{"edges.kedge.faros.sh"},
+			Resources: []string{"workloads", "workloads/status"},
+			Verbs:     []string{"get", "list", "watch"},
+		},
+		{
+			APIGroups: []string{""},
+			Resources: []string{"events"},
+			Verbs:     []string{"create", "patch"},
+		},
+	}
+}
+
+// ensureClusterRole creates or updates a ClusterRole shared by edge agents.
 // It intentionally carries no owner reference so that it is never garbage-collected
 // when an individual edge is deleted; the role is a cluster-wide shared resource.
-func ensureClusterRole(ctx context.Context, c client.Client) error {
-	desired := desiredAgentRules()
+func ensureClusterRole(ctx context.Context, c client.Client, name string, desired []rbacv1.PolicyRule) error {
 	cr := &rbacv1.ClusterRole{}
-	if err := c.Get(ctx, client.ObjectKey{Name: edgeAgentClusterRole}, cr); err == nil {
+	if err := c.Get(ctx, client.ObjectKey{Name: name}, cr); err == nil {
 		if !rulesEqual(cr.Rules, desired) {
 			cr.Rules = desired
 			return c.Update(ctx, cr)
@@ -287,7 +341,7 @@ func ensureClusterRole(ctx context.Context, c client.Client) error {
 	}
 	if err := c.Create(ctx, &rbacv1.ClusterRole{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: edgeAgentClusterRole,
+			Name: name,
 		},
 		Rules: desired,
 	}); err != nil && !apierrors.IsAlreadyExists(err) {
@@ -329,11 +383,19 @@ func slicesEqual(a, b []string) bool {
 	return true
 }
 
-func ensureClusterRoleBinding(ctx context.Context, c client.Client, saName string, ownerRef metav1.OwnerReference) error {
+// ensureClusterRoleBinding binds the edge's agent SA to roleName. A binding
+// to another role, left by a change of the edge's registration, is replaced:
+// a binding's roleRef cannot be updated.
+func ensureClusterRoleBinding(ctx context.Context, c client.Client, saName, roleName string, ownerRef metav1.OwnerReference) error {
 	crbName := "kedge-edge-" + saName
 	crb := &rbacv1.ClusterRoleBinding{}
 	if err := c.Get(ctx, client.ObjectKey{Name: crbName}, crb); err == nil {
-		return ensureOwnerRef(ctx, c, crb, ownerRef)
+		if crb.RoleRef.Name == roleName {
+			return ensureOwnerRef(ctx, c, crb, ownerRef)
+		}
+		if err := c.Delete(ctx, crb); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("deleting binding to %s: %w", crb.RoleRef.Name, err)
+		}
 	} else if !apierrors.IsNotFound(err) {
 		return err
 	}
@@ -345,7 +407,7 @@ func ensureClusterRoleBinding(ctx context.Context, c client.Client, saName strin
 		RoleRef: rbacv1.RoleRef{
 			APIGroup: "rbac.authorization.k8s.io",
 			Kind:     "ClusterRole",
-			Name:     edgeAgentClusterRole,
+			Name:     roleName,
 		},
 		Subjects: []rbacv1.Subject{
 			{
@@ -18,6 +18,7 @@ package edgectrl
 
 import (
 	"context"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
@@ -25,7 +26,8 @@ import (
 	mcmanager "sigs.k8s.io/multicluster-runtime/pkg/manager"
 )
 
-// Options configures the RBAC reconciler's agent-kubeconfig generation.
+// Options configures the edge controllers: the RBAC reconciler's
+// agent-kubeconfig generation, the version check and session draining.
 type Options struct {
 	HubExternalURL string
 	HubCAData      []byte
@@ -34,9 +36,19 @@ type Options struct {
 	// version reconciler maintains the UpgradeAvailable condition by comparing it
 	// against each edge's reported status.agentVersion. Nil disables the check.
 	LatestAgentVersion func(context.Context) (string, error)
+	// Drainer is told which edges are draining so it can refuse and close
+	// their sessions. Nil leaves the drain to the Draining condition alone.
+	Drainer Drainer
+	// DrainGracePeriod is how long open sessions survive a drain; zero uses
+	// DefaultDrainGracePeriod.
+	DrainGracePeriod time.Duration
+	// Instance is this replica's name in edges' status.tunnel. The drain and
+	// lifecycle reconcilers leave an edge whose tunnel another live replica
+	// holds to that replica.
+	Instance string
 }
 
-// SetupControllers registers the token, RBAC, and lifecycle reconcilers for one
+// SetupControllers registers the token, RBAC, drain and lifecycle reconcilers for one
 // connectable kind on the multicluster manager. An edge-type provider calls this
 // once with its kind's GVR + Kind + a factory that yields its concrete type
 // (which must implement edgeapi.Connectable), plus the tunnel's ConnManager so
@@ -60,5 +72,8 @@ func SetupControllers(
 			return err
 		}
 	}
-	return SetupLifecycleWithManager(mgr, gvr, newObj, connManager)
+	if err := SetupDrainWithManager(mgr, gvr, newObj, connManager, opts.Drainer, opts.DrainGracePeriod, opts.Instance); err != nil {
+		return err
+	}
+	return SetupLifecycleWithManager(mgr, gvr, newObj, connManager, opts.Instance)
 }
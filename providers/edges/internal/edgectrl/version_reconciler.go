@@ -36,6 +36,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	edgeapi "github.com/faroshq/provider-edges/internal/edgeapi"
+	"github.com/faroshq/provider-edges/internal/versionskew"
 
 	mcbuilder "sigs.k8s.io/multicluster-runtime/pkg/builder"
 	mcmanager "sigs.k8s.io/multicluster-runtime/pkg/manager"
@@ -123,7 +124,8 @@ func (h *HubVersionCache) Get(ctx context.Context) (string, error) {
 }
 
 // VersionReconciler compares each connectable's reported agent version against
-// the hub release and maintains the UpgradeAvailable condition.
+// the hub release and maintains the UpgradeAvailable and VersionSupported
+// conditions.
 type VersionReconciler struct {
 	mgr    mcmanager.Manager
 	newObj func() edgeapi.Connectable
@@ -141,9 +143,10 @@ func SetupVersionWithManager(mgr mcmanager.Manager, gvr schema.GroupVersionResou
 		Complete(r)
 }
 
-// Reconcile keeps the UpgradeAvailable condition in sync with the agent-vs-hub
-// version delta. It only writes status when the condition actually changes, so a
-// steady state costs one periodic Get (usually cache-served) and no API writes.
+// Reconcile keeps the UpgradeAvailable and VersionSupported conditions in sync
+// with the agent-vs-hub version delta. It only writes status when a condition
+// actually changes, so a steady state costs one periodic Get (usually
+// cache-served) and no API writes.
 func (r *VersionReconciler) Reconcile(ctx context.Context, req mcreconcile.Request) (ctrl.Result, error) {
 	logger := klog.FromContext(ctx).WithValues("edge", req.Name, "cluster", req.ClusterName)
 
@@ -175,23 +178,57 @@ func (r *VersionReconciler) Reconcile(ctx context.Context, req mcreconcile.Reque
 		return ctrl.Result{RequeueAfter: versionRetryInterval}, nil
 	}
 
-	desired := upgradeCondition(cs.AgentVersion, latest)
-	existing := meta.FindStatusCondition(cs.Conditions, edgeapi.ConnectionConditionUpgradeAvailable)
-	if existing != nil &&
-		existing.Status == desired.Status &&
-		existing.Reason == desired.Reason &&
-		existing.Message == desired.Message {
+	upgrade := upgradeCondition(cs.AgentVersion, latest)
+	supported := versionSupportedCondition(cs.AgentVersion, latest)
+	if !conditionChanged(cs.Conditions, upgrade) && !conditionChanged(cs.Conditions, supported) {
 		return ctrl.Result{RequeueAfter: versionCheckInterval}, nil
 	}
 
-	meta.SetStatusCondition(&cs.Conditions, desired)
+	meta.SetStatusCondition(&cs.Conditions, upgrade)
+	meta.SetStatusCondition(&cs.Conditions, supported)
 	if err := c.Status().Update(ctx, edge); err != nil {
-		return ctrl.Result{}, fmt.Errorf("updating upgrade condition: %w", err)
+		return ctrl.Result{}, fmt.Errorf("updating version conditions: %w", err)
 	}
-	logger.Info("Updated upgrade condition", "status", desired.Status, "agentVersion", cs.AgentVersion, "hubVersion", latest)
+	logger.Info("Updated version conditions", "upgradeAvailable", upgrade.Status, "versionSupported", supported.Status,
+		"agentVersion", cs.AgentVersion, "hubVersion", latest)
 	return ctrl.Result{RequeueAfter: versionCheckInterval}, nil
 }
 
+// conditionChanged reports whether desired differs from the condition of its
+// type in conditions, ignoring the transition time.
+func conditionChanged(conditions []metav1.Condition, desired metav1.Condition) bool {
+	existing := meta.FindStatusCondition(conditions, desired.Type)
+	return existing == nil ||
+		existing.Status != desired.Status ||
+		existing.Reason != desired.Reason ||
+		existing.Message != desired.Message
+}
+
+// versionSupportedCondition builds the desired VersionSupported condition
+// from the agent-vs-hub skew policy.
+func versionSupportedCondition(agentVersion, hubVersion string) metav1.Condition {
+	cond := metav1.Condition{
+		Type:               edgeapi.ConnectionConditionVersionSupported,
+		LastTransitionTime: metav1.NewTime(time.Now()),
+	}
+	skew := versionskew.CheckAgent(agentVersion, hubVersion)
+	switch skew.Status {
+	case versionskew.Supported:
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "SupportedVersion"
+		cond.Message = fmt.Sprintf("Agent %s is supported by hub %s.", agentVersion, hubVersion)
+	case versionskew.Unsupported:
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "UnsupportedVersion"
+		cond.Message = skew.Reason + "."
+	default:
+		cond.Status = metav1.ConditionUnknown
+		cond.Reason = "UnknownVersion"
+		cond.Message = skew.Reason + "."
+	}
+	return cond
+}
+
 // upgradeCondition builds the desired UpgradeAvailable condition. The True
 // message embeds the target version in a "upgrade available to <version>."
 // suffix the portal parses to render upgrade commands.
@@ -0,0 +1,417 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fleet runs FleetCommands: one shell command fanned out over the SSH
+// exec path to every LinuxServer edge matching a selector, with per-edge exit
+// codes and output tails recorded in status.
+package fleet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcbuilder "sigs.k8s.io/multicluster-runtime/pkg/builder"
+	mcmanager "sigs.k8s.io/multicluster-runtime/pkg/manager"
+	mcreconcile "sigs.k8s.io/multicluster-runtime/pkg/reconcile"
+
+	edgesv1alpha1 "github.com/faroshq/provider-edges/apis/v1alpha1"
+)
+
+const (
+	controllerName = "fleetcommand"
+
+	defaultConcurrency = 10
+	defaultTimeout     = 5 * time.Minute
+
+	// maxOutputBytes bounds the output tail kept per edge, so a chatty
+	// command over a large fleet cannot blow the object past etcd's size limit.
+	maxOutputBytes = 1024
+
+	// requesterWait is how long a new FleetCommand waits for its requester
+	// to be recorded (kedge fleet run does it right after creating it), and
+	// requesterPoll how often it looks.
+	requesterWait = time.Minute
+	requesterPoll = 2 * time.Second
+)
+
+// Executor runs a command on one connected server edge and returns its exit
+// code. The tunnel Server implements it over the ssh subresource's exec path.
+type Executor interface {
+	ExecSSH(ctx context.Context, cluster, name, command string, output io.Writer) (int, error)
+}
+
+// Authorizer decides whether a FleetCommand may run on an edge. The tunnel
+// Server implements it over the signed requester the edges proxy records on
+// the object when `kedge fleet run` registers it.
+type Authorizer interface {
+	// FleetRequester returns the verified user fc runs on behalf of, or ""
+	// when none is recorded yet. An error means the record is not valid.
+	FleetRequester(cluster string, fc metav1.Object) (string, error)
+	// AuthorizeFleetExec checks that fc's requester may reach edge.
+	AuthorizeFleetExec(ctx context.Context, cluster string, fc metav1.Object, edge string) error
+}
+
+// Reconciler starts FleetCommands and records their per-edge results. Runs are
+// in-process goroutines rooted at the manager's context: a FleetCommand found
+// Running that this process never started (the provider restarted mid-run) is
+// failed rather than re-run, since the command may already have had effects.
+type Reconciler struct {
+	mgr   mcmanager.Manager
+	exec  Executor
+	authz Authorizer
+	// ctx outlives individual reconciles; runs are cancelled when it is.
+	ctx context.Context
+
+	// started holds the runs this process launched, until their terminal
+	// phase (or deletion) is observed. Keeping finished runs until then stops
+	// a stale cached Running from being mistaken for an orphan.
+	mu      sync.Mutex
+	started map[string]bool
+}
+
+// SetupWithManager registers the FleetCommand controller. ctx bounds the
+// lifetime of in-flight runs.
+func SetupWithManager(ctx context.Context, mgr mcmanager.Manager, exec Executor, authz Authorizer) error {
+	r := &Reconciler{mgr: mgr, exec: exec, authz: authz, ctx: ctx, started: map[string]bool{}}
+	klog.Info("Registering FleetCommand controller")
+	return mcbuilder.ControllerManagedBy(mgr).
+		Named(controllerName).
+		For(&edgesv1alpha1.FleetCommand{}).
+		Complete(r)
+}
+
+// Reconcile starts a new FleetCommand or fails an orphaned one.
+func (r *Reconciler) Reconcile(ctx context.Context, req mcreconcile.Request) (ctrl.Result, error) {
+	logger := klog.FromContext(ctx).WithValues("fleetcommand", req.Name, "cluster", req.ClusterName)
+
+	cl, err := r.mgr.GetCluster(ctx, req.ClusterName)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("getting cluster %s: %w", req.ClusterName, err)
+	}
+	c := cl.GetClient()
+
+	runKey := string(req.ClusterName) + "/" + req.Name
+	var fc edgesv1alpha1.FleetCommand
+	if err := c.Get(ctx, req.NamespacedName, &fc); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.forget(runKey)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	switch fc.Status.Phase {
+	case edgesv1alpha1.FleetCommandPhaseSucceeded, edgesv1alpha1.FleetCommandPhaseFailed:
+		r.forget(runKey)
+		return ctrl.Result{}, nil
+	}
+
+	r.mu.Lock()
+	started := r.started[runKey]
+	r.mu.Unlock()
+	if started {
+		return ctrl.Result{}, nil
+	}
+
+	if fc.Status.Phase == edgesv1alpha1.FleetCommandPhaseRunning {
+		logger.Info("FleetCommand was running when the provider restarted; marking it failed")
+		return ctrl.Result{}, r.failInterrupted(ctx, c, &fc)
+	}
+
+	// The command runs with the rights of whoever registered as its
+	// requester, checked per edge once running; nobody registered, nothing
+	// runs.
+	requester, err := r.authz.FleetRequester(string(req.ClusterName), &fc)
+	switch {
+	case err != nil:
+		return ctrl.Result{}, r.fail(ctx, c, &fc, fmt.Sprintf("requester not verified: %v", err))
+	case requester == "" && time.Since(fc.CreationTimestamp.Time) >= requesterWait:
+		return ctrl.Result{}, r.fail(ctx, c, &fc, "no requester was recorded; start fleet commands with 'kedge fleet run'")
+	case requester == "":
+		if fc.Status.Phase != edgesv1alpha1.FleetCommandPhasePending {
+			fc.Status.Phase = edgesv1alpha1.FleetCommandPhasePending
+			fc.Status.Message = "waiting for the requester to be recorded"
+			if err := c.Status().Update(ctx, &fc); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{RequeueAfter: requesterPoll}, nil
+	}
+
+	edges, err := r.selectEdges(ctx, c, fc.Spec.EdgeSelector)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	now := metav1.Now()
+	fc.Status.Message = ""
+	fc.Status.StartTime = &now
+	fc.Status.Total = int32(len(edges))
+	fc.Status.Results = make([]edgesv1alpha1.FleetCommandEdgeResult, len(edges))
+	for i, name := range edges {
+		fc.Status.Results[i] = edgesv1alpha1.FleetCommandEdgeResult{Edge: name}
+	}
+	if len(edges) == 0 {
+		fc.Status.Phase = edgesv1alpha1.FleetCommandPhaseFailed
+		fc.Status.Message = "no LinuxServer edges match edgeSelector"
+		fc.Status.CompletionTime = &now
+		return ctrl.Result{}, c.Status().Update(ctx, &fc)
+	}
+	fc.Status.Phase = edgesv1alpha1.FleetCommandPhaseRunning
+	// The Running write doubles as the start claim: a conflict here means
+	// another reconcile already moved the object on, so nothing is launched.
+	if err := c.Status().Update(ctx, &fc); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	r.mu.Lock()
+	r.started[runKey] = true
+	r.mu.Unlock()
+	logger.Info("Starting FleetCommand", "requester", requester, "edges", len(edges), "concurrency", concurrencyOf(&fc), "timeout", timeoutOf(&fc))
+	go r.run(klog.NewContext(r.ctx, logger), c, string(req.ClusterName), fc.DeepCopy())
+	return ctrl.Result{}, nil
+}
+
+func (r *Reconciler) forget(runKey string) {
+	r.mu.Lock()
+	delete(r.started, runKey)
+	r.mu.Unlock()
+}
+
+// selectEdges returns the sorted names of the LinuxServers matching selector;
+// a nil selector matches all of them.
+func (r *Reconciler) selectEdges(ctx context.Context, c client.Client, selector *metav1.LabelSelector) ([]string, error) {
+	sel := labels.Everything()
+	if selector != nil {
+		var err error
+		if sel, err = metav1.LabelSelectorAsSelector(selector); err != nil {
+			return nil, fmt.Errorf("parsing edgeSelector: %w", err)
+		}
+	}
+	var list edgesv1alpha1.LinuxServerList
+	if err := c.List(ctx, &list, client.MatchingLabelsSelector{Selector: sel}); err != nil {
+		return nil, fmt.Errorf("listing LinuxServer edges: %w", err)
+	}
+	names := make([]string, 0, len(list.Items))
+	for _, e := range list.Items {
+		names = append(names, e.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// run executes fc's command on every selected edge, at most Concurrency at a
+// time, recording each result as it lands and the final phase at the end.
+func (r *Reconciler) run(ctx context.Context, c client.Client, cluster string, fc *edgesv1alpha1.FleetCommand) {
+	logger := klog.FromContext(ctx)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	key := types.NamespacedName{Name: fc.Name}
+	timeout := timeoutOf(fc)
+	sem := make(chan struct{}, concurrencyOf(fc))
+	var wg sync.WaitGroup
+	for i := range fc.Status.Results {
+		edge := fc.Status.Results[i].Edge
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+
+			result := r.execOne(ctx, cluster, fc, edge, timeout)
+			err := updateStatus(ctx, c, key, func(st *edgesv1alpha1.FleetCommandStatus) {
+				setResult(st, result)
+			})
+			if apierrors.IsNotFound(err) {
+				// Deleted mid-run: stop starting edges nobody will read about.
+				cancel()
+				return
+			}
+			if err != nil {
+				logger.Error(err, "Failed to record FleetCommand result", "edge", edge)
+			}
+		}()
+	}
+	wg.Wait()
+
+	err := updateStatus(r.ctx, c, key, func(st *edgesv1alpha1.FleetCommandStatus) {
+		now := metav1.Now()
+		for i := range st.Results {
+			if st.Results[i].CompletionTime == nil {
+				st.Results[i].Error = "not run"
+				st.Results[i].CompletionTime = &now
+			}
+		}
+		finish(st, now)
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		logger.Error(err, "Failed to record FleetCommand completion")
+		return
+	}
+	logger.Info("FleetCommand finished")
+}
+
+// execOne runs fc's command on a single edge under the per-edge timeout, once
+// its requester is authorized for the edge. An edge it is not authorized for
+// is marked Denied and never contacted.
+func (r *Reconciler) execOne(ctx context.Context, cluster string, fc *edgesv1alpha1.FleetCommand, edge string, timeout time.Duration) edgesv1alpha1.FleetCommandEdgeResult {
+	start := metav1.Now()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := r.authz.AuthorizeFleetExec(ctx, cluster, fc, edge); err != nil {
+		end := metav1.Now()
+		return edgesv1alpha1.FleetCommandEdgeResult{
+			Edge:           edge,
+			Denied:         true,
+			Error:          err.Error(),
+			StartTime:      &start,
+			CompletionTime: &end,
+		}
+	}
+
+	out := newTailBuffer(maxOutputBytes)
+	code, err := r.exec.ExecSSH(ctx, cluster, edge, fc.Spec.Command, out)
+	end := metav1.Now()
+
+	result := edgesv1alpha1.FleetCommandEdgeResult{
+		Edge:           edge,
+		Output:         out.String(),
+		StartTime:      &start,
+		CompletionTime: &end,
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		result.Error = fmt.Sprintf("timed out after %s", timeout)
+	case err != nil:
+		result.Error = err.Error()
+	default:
+		exitCode := int32(code)
+		result.ExitCode = &exitCode
+	}
+	return result
+}
+
+// fail fails fc before any edge was selected.
+func (r *Reconciler) fail(ctx context.Context, c client.Client, fc *edgesv1alpha1.FleetCommand, message string) error {
+	now := metav1.Now()
+	fc.Status.Phase = edgesv1alpha1.FleetCommandPhaseFailed
+	fc.Status.Message = message
+	fc.Status.CompletionTime = &now
+	return c.Status().Update(ctx, fc)
+}
+
+// failInterrupted fails a run orphaned by a provider restart. Edges that had
+// finished keep their results; the rest are marked interrupted.
+func (r *Reconciler) failInterrupted(ctx context.Context, c client.Client, fc *edgesv1alpha1.FleetCommand) error {
+	return updateStatus(ctx, c, types.NamespacedName{Name: fc.Name}, func(st *edgesv1alpha1.FleetCommandStatus) {
+		now := metav1.Now()
+		for i := range st.Results {
+			if st.Results[i].CompletionTime == nil {
+				st.Results[i].Error = "interrupted: provider restarted before the edge reported a result"
+				st.Results[i].CompletionTime = &now
+			}
+		}
+		finish(st, now)
+		st.Phase = edgesv1alpha1.FleetCommandPhaseFailed
+		st.Message = "run interrupted by a provider restart"
+	})
+}
+
+// updateStatus applies mutate to the latest FleetCommand status and writes it,
+// retrying on conflicts with concurrent per-edge writers.
+func updateStatus(ctx context.Context, c client.Client, key types.NamespacedName, mutate func(*edgesv1alpha1.FleetCommandStatus)) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		var fc edgesv1alpha1.FleetCommand
+		if err := c.Get(ctx, key, &fc); err != nil {
+			return err
+		}
+		mutate(&fc.Status)
+		return c.Status().Update(ctx, &fc)
+	})
+}
+
+// setResult stores result in the entry for its edge and refreshes the
+// succeeded/failed tallies.
+func setResult(st *edgesv1alpha1.FleetCommandStatus, result edgesv1alpha1.FleetCommandEdgeResult) {
+	for i := range st.Results {
+		if st.Results[i].Edge == result.Edge {
+			st.Results[i] = result
+			break
+		}
+	}
+	st.Succeeded, st.Failed = tally(st.Results)
+}
+
+// finish stamps completion and the terminal phase: Succeeded only when every
+// edge exited 0.
+func finish(st *edgesv1alpha1.FleetCommandStatus, now metav1.Time) {
+	st.Succeeded, st.Failed = tally(st.Results)
+	st.CompletionTime = &now
+	if st.Failed == 0 && st.Succeeded == st.Total {
+		st.Phase = edgesv1alpha1.FleetCommandPhaseSucceeded
+	} else {
+		st.Phase = edgesv1alpha1.FleetCommandPhaseFailed
+	}
+}
+
+// tally counts finished edges: succeeded exited 0, failed is everything else
+// that completed (non-zero exit or an error).
+func tally(results []edgesv1alpha1.FleetCommandEdgeResult) (succeeded, failed int32) {
+	for _, res := range results {
+		switch {
+		case res.CompletionTime == nil:
+		case res.ExitCode != nil && *res.ExitCode == 0:
+			succeeded++
+		default:
+			failed++
+		}
+	}
+	return succeeded, failed
+}
+
+func concurrencyOf(fc *edgesv1alpha1.FleetCommand) int {
+	if fc.Spec.Concurrency > 0 {
+		return int(fc.Spec.Concurrency)
+	}
+	return defaultConcurrency
+}
+
+func timeoutOf(fc *edgesv1alpha1.FleetCommand) time.Duration {
+	if fc.Spec.Timeout != nil && fc.Spec.Timeout.Duration > 0 {
+		return fc.Spec.Timeout.Duration
+	}
+	return defaultTimeout
+}
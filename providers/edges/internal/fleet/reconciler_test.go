@@ -0,0 +1,146 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fleet
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	edgesv1alpha1 "github.com/faroshq/provider-edges/apis/v1alpha1"
+)
+
+func TestTailBuffer(t *testing.T) {
+	b := newTailBuffer(8)
+	b.Write([]byte("abc")) //nolint:errcheck
+	if got := b.String(); got != "abc" {
+		t.Fatalf("String() = %q, want %q", got, "abc")
+	}
+	b.Write([]byte("defghijk")) //nolint:errcheck
+	if got, want := b.String(), truncatedMarker+"defghijk"; got != want {
+		t.Fatalf("String() = %q, want %q", got, want)
+	}
+
+	// A cut through a multi-byte rune must not leave invalid UTF-8 behind.
+	u := newTailBuffer(4)
+	u.Write([]byte("xé€")) //nolint:errcheck
+	if got := strings.TrimPrefix(u.String(), truncatedMarker); got != "€" {
+		t.Fatalf("String() = %q, want %q", got, "€")
+	}
+}
+
+func TestFinish(t *testing.T) {
+	now := metav1.Now()
+	exit := func(code int32) *int32 { return &code }
+	done := func(edge string, code *int32, errMsg string) edgesv1alpha1.FleetCommandEdgeResult {
+		return edgesv1alpha1.FleetCommandEdgeResult{Edge: edge, ExitCode: code, Error: errMsg, CompletionTime: &now}
+	}
+
+	cases := []struct {
+		name          string
+		results       []edgesv1alpha1.FleetCommandEdgeResult
+		wantPhase     edgesv1alpha1.FleetCommandPhase
+		wantSucceeded int32
+		wantFailed    int32
+	}{
+		{
+			name:          "all exit 0",
+			results:       []edgesv1alpha1.FleetCommandEdgeResult{done("a", exit(0), ""), done("b", exit(0), "")},
+			wantPhase:     edgesv1alpha1.FleetCommandPhaseSucceeded,
+			wantSucceeded: 2,
+		},
+		{
+			name:          "non-zero exit",
+			results:       []edgesv1alpha1.FleetCommandEdgeResult{done("a", exit(0), ""), done("b", exit(2), "")},
+			wantPhase:     edgesv1alpha1.FleetCommandPhaseFailed,
+			wantSucceeded: 1,
+			wantFailed:    1,
+		},
+		{
+			name:       "unreachable edge",
+			results:    []edgesv1alpha1.FleetCommandEdgeResult{done("a", nil, "edge is not connected")},
+			wantPhase:  edgesv1alpha1.FleetCommandPhaseFailed,
+			wantFailed: 1,
+		},
+		{
+			name:          "edge never finished",
+			results:       []edgesv1alpha1.FleetCommandEdgeResult{done("a", exit(0), ""), {Edge: "b"}},
+			wantPhase:     edgesv1alpha1.FleetCommandPhaseFailed,
+			wantSucceeded: 1,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			st := &edgesv1alpha1.FleetCommandStatus{Total: int32(len(tc.results)), Results: tc.results}
+			finish(st, now)
+			if st.Phase != tc.wantPhase || st.Succeeded != tc.wantSucceeded || st.Failed != tc.wantFailed {
+				t.Fatalf("finish() = phase %s succeeded %d failed %d, want %s %d %d",
+					st.Phase, st.Succeeded, st.Failed, tc.wantPhase, tc.wantSucceeded, tc.wantFailed)
+			}
+		})
+	}
+}
+
+type fakeAuthorizer struct{ denied map[string]bool }
+
+func (f fakeAuthorizer) FleetRequester(string, metav1.Object) (string, error) { return "alice", nil }
+
+func (f fakeAuthorizer) AuthorizeFleetExec(_ context.Context, _ string, _ metav1.Object, edge string) error {
+	if f.denied[edge] {
+		return errors.New("access denied")
+	}
+	return nil
+}
+
+type fakeExecutor struct{ ran []string }
+
+func (f *fakeExecutor) ExecSSH(_ context.Context, _, name, _ string, output io.Writer) (int, error) {
+	f.ran = append(f.ran, name)
+	io.WriteString(output, "ok\n") //nolint:errcheck
+	return 0, nil
+}
+
+// TestExecOneDenied pins that an edge the requester may not reach is marked
+// Denied, counted as failed and never contacted.
+func TestExecOneDenied(t *testing.T) {
+	exec := &fakeExecutor{}
+	r := &Reconciler{exec: exec, authz: fakeAuthorizer{denied: map[string]bool{"locked": true}}}
+	fc := &edgesv1alpha1.FleetCommand{Spec: edgesv1alpha1.FleetCommandSpec{Command: "uptime"}}
+
+	allowed := r.execOne(context.Background(), "abc", fc, "box", time.Minute)
+	if allowed.Denied || allowed.ExitCode == nil || *allowed.ExitCode != 0 {
+		t.Fatalf("execOne(box) = %+v, want exit 0", allowed)
+	}
+	denied := r.execOne(context.Background(), "abc", fc, "locked", time.Minute)
+	if !denied.Denied || denied.ExitCode != nil || denied.Error == "" || denied.CompletionTime == nil {
+		t.Fatalf("execOne(locked) = %+v, want a completed denied result", denied)
+	}
+	if len(exec.ran) != 1 || exec.ran[0] != "box" {
+		t.Fatalf("executed on %q, want only box", exec.ran)
+	}
+
+	st := &edgesv1alpha1.FleetCommandStatus{Total: 2, Results: []edgesv1alpha1.FleetCommandEdgeResult{allowed, denied}}
+	finish(st, metav1.Now())
+	if st.Phase != edgesv1alpha1.FleetCommandPhaseFailed || st.Succeeded != 1 || st.Failed != 1 {
+		t.Fatalf("finish() = phase %s succeeded %d failed %d, want Failed 1 1", st.Phase, st.Succeeded, st.Failed)
+	}
+}
@@ -0,0 +1,397 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package manifeststore is a content-addressed store for the manifest
+// bundles the scheduler renders for Placements. A bundle is kept once per
+// digest, as a gzipped ConfigMap in the provider's own workspace, however
+// many placements and tenants run it; Placements carry only a ManifestsRef
+// and the edge agent fetches the bundle by digest through the manifests
+// endpoint. That keeps large rendered charts out of every Placement object
+// in kcp and lets an agent download a bundle its other placements already
+// use exactly once.
+//
+// Bundles are immutable. Each Put refreshes the bundle's last-used stamp (at
+// most once per touchInterval), and Run deletes bundles no Put has touched
+// for the retention period, so bundles of deleted or re-rendered workloads
+// age out without reference counting across tenants.
+package manifeststore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+
+	edgesv1alpha1 "github.com/faroshq/provider-edges/apis/v1alpha1"
+)
+
+const (
+	// labelBundle marks the ConfigMaps holding bundles, so collection lists
+	// nothing else.
+	labelBundle = edgesv1alpha1.GroupName + "/manifest-bundle"
+	// annLastUsed is the RFC 3339 time a Put last referenced the bundle.
+	annLastUsed = edgesv1alpha1.GroupName + "/last-used"
+	// dataKey is the BinaryData key holding the gzipped encoding.
+	dataKey = "bundle.json.gz"
+
+	// maxBlobSize keeps a gzipped bundle inside the 1 MiB object limit, with
+	// room for the ConfigMap's metadata.
+	maxBlobSize = 900 << 10
+	// maxCacheBytes bounds the in-memory copy of recently used blobs.
+	maxCacheBytes = 64 << 20
+
+	// touchInterval is how often a bundle's last-used stamp is refreshed
+	// while placements keep referencing it.
+	touchInterval = time.Hour
+	// collectInterval is how often Run looks for expired bundles.
+	collectInterval = time.Hour
+
+	// DefaultRetention is how long a bundle outlives its last reference.
+	DefaultRetention = 7 * 24 * time.Hour
+	// DefaultNamespace is the provider-workspace namespace bundles live in.
+	DefaultNamespace = "default"
+)
+
+// ErrTooLarge is returned by Put for a bundle that does not fit in a single
+// stored object even compressed; the caller keeps it inline instead.
+var ErrTooLarge = errors.New("manifest bundle too large for the manifest store")
+
+// ErrNotFound is returned by Get for a digest the store does not hold.
+var ErrNotFound = errors.New("manifest bundle not found")
+
+var digestRE = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// Options configures a Store.
+type Options struct {
+	// Namespace holds the bundle ConfigMaps. Defaults to DefaultNamespace.
+	Namespace string
+	// Retention is how long an unreferenced bundle is kept. Defaults to
+	// DefaultRetention.
+	Retention time.Duration
+}
+
+// Store keeps manifest bundles in the provider's workspace. It is safe for
+// concurrent use.
+type Store struct {
+	client    kubernetes.Interface
+	namespace string
+	retention time.Duration
+	now       func() time.Time
+
+	mu sync.Mutex
+	// blobs caches gzipped bundles by digest; they never change, so the
+	// cache needs no invalidation, only a size bound.
+	blobs     map[string][]byte
+	blobBytes int
+	// touched is when this process last stamped each bundle as used.
+	touched map[string]time.Time
+}
+
+// New returns a Store writing to the workspace config addresses (the
+// provider's own).
+func New(config *rest.Config, opts Options) (*Store, error) {
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("creating manifest store client: %w", err)
+	}
+	return newStore(client, opts), nil
+}
+
+func newStore(client kubernetes.Interface, opts Options) *Store {
+	if opts.Namespace == "" {
+		opts.Namespace = DefaultNamespace
+	}
+	if opts.Retention <= 0 {
+		opts.Retention = DefaultRetention
+	}
+	return &Store{
+		client:    client,
+		namespace: opts.Namespace,
+		retention: opts.Retention,
+		now:       time.Now,
+		blobs:     map[string][]byte{},
+		touched:   map[string]time.Time{},
+	}
+}
+
+// Encode returns the canonical encoding of manifests — a JSON array of the
+// objects with map keys sorted and insignificant whitespace dropped — and
+// the reference to it. Equal bundles therefore share a digest however their
+// renderer ordered keys.
+func Encode(manifests []runtime.RawExtension) ([]byte, edgesv1alpha1.ManifestsRef, error) {
+	objs := make([]interface{}, 0, len(manifests))
+	for i, raw := range manifests {
+		dec := json.NewDecoder(bytes.NewReader(raw.Raw))
+		// Numbers stay verbatim rather than round-tripping through float64.
+		dec.UseNumber()
+		var obj interface{}
+		if err := dec.Decode(&obj); err != nil {
+			return nil, edgesv1alpha1.ManifestsRef{}, fmt.Errorf("decoding manifest[%d]: %w", i, err)
+		}
+		objs = append(objs, obj)
+	}
+	data, err := json.Marshal(objs)
+	if err != nil {
+		return nil, edgesv1alpha1.ManifestsRef{}, fmt.Errorf("encoding manifest bundle: %w", err)
+	}
+	return data, edgesv1alpha1.ManifestsRef{
+		Digest: Digest(data),
+		Size:   int64(len(data)),
+		Count:  int32(len(manifests)),
+	}, nil
+}
+
+// Decode splits a canonical encoding back into one object per manifest.
+func Decode(data []byte) ([]runtime.RawExtension, error) {
+	var objs []json.RawMessage
+	if err := json.Unmarshal(data, &objs); err != nil {
+		return nil, fmt.Errorf("decoding manifest bundle: %w", err)
+	}
+	manifests := make([]runtime.RawExtension, 0, len(objs))
+	for _, obj := range objs {
+		manifests = append(manifests, runtime.RawExtension{Raw: obj})
+	}
+	return manifests, nil
+}
+
+// Digest returns the "sha256:<hex>" digest of data.
+func Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// ValidDigest reports whether digest is a well-formed "sha256:<hex>".
+func ValidDigest(digest string) bool {
+	return digestRE.MatchString(digest)
+}
+
+// objectName is the ConfigMap holding digest.
+func objectName(digest string) string {
+	return strings.Replace(digest, ":", "-", 1)
+}
+
+// Put stores manifests, if not already stored, and returns the reference
+// Placements carry instead of the bundle itself.
+func (s *Store) Put(ctx context.Context, manifests []runtime.RawExtension) (*edgesv1alpha1.ManifestsRef, error) {
+	data, ref, err := Encode(manifests)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+
+	s.mu.Lock()
+	_, cached := s.blobs[ref.Digest]
+	fresh := now.Sub(s.touched[ref.Digest]) < touchInterval
+	s.mu.Unlock()
+	if cached && fresh {
+		return &ref, nil
+	}
+
+	blob, err := compress(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(blob) > maxBlobSize {
+		return nil, fmt.Errorf("%w: %d bytes compressed", ErrTooLarge, len(blob))
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        objectName(ref.Digest),
+			Namespace:   s.namespace,
+			Labels:      map[string]string{labelBundle: "true"},
+			Annotations: map[string]string{annLastUsed: now.UTC().Format(time.RFC3339)},
+		},
+		BinaryData: map[string][]byte{dataKey: blob},
+	}
+	immutable := true
+	cm.Immutable = &immutable
+	_, err = s.client.CoreV1().ConfigMaps(s.namespace).Create(ctx, cm, metav1.CreateOptions{})
+	switch {
+	case apierrors.IsAlreadyExists(err):
+		// Stored earlier, by this or a previous run: only the stamp moves.
+		if err := s.touch(ctx, ref.Digest, now); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, fmt.Errorf("storing manifest bundle %s: %w", ref.Digest, err)
+	}
+
+	s.mu.Lock()
+	s.touched[ref.Digest] = now
+	s.cacheLocked(ref.Digest, blob)
+	s.mu.Unlock()
+	return &ref, nil
+}
+
+// touch refreshes the last-used stamp of a stored bundle. Annotations stay
+// writable on an immutable ConfigMap.
+func (s *Store) touch(ctx context.Context, digest string, now time.Time) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{annLastUsed: now.UTC().Format(time.RFC3339)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := s.client.CoreV1().ConfigMaps(s.namespace).Patch(
+		ctx, objectName(digest), types.MergePatchType, patch, metav1.PatchOptions{},
+	); err != nil {
+		return fmt.Errorf("touching manifest bundle %s: %w", digest, err)
+	}
+	return nil
+}
+
+// Get returns the gzipped canonical encoding of the bundle with digest.
+// The content is verified against the digest before it is returned.
+func (s *Store) Get(ctx context.Context, digest string) ([]byte, error) {
+	if !ValidDigest(digest) {
+		return nil, fmt.Errorf("%w: malformed digest %q", ErrNotFound, digest)
+	}
+	s.mu.Lock()
+	blob, ok := s.blobs[digest]
+	s.mu.Unlock()
+	if ok {
+		return blob, nil
+	}
+
+	cm, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(ctx, objectName(digest), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, digest)
+	} else if err != nil {
+		return nil, fmt.Errorf("reading manifest bundle %s: %w", digest, err)
+	}
+	blob = cm.BinaryData[dataKey]
+	data, err := Decompress(blob)
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest bundle %s: %w", digest, err)
+	}
+	if got := Digest(data); got != digest {
+		return nil, fmt.Errorf("manifest bundle %s is corrupt: content digest is %s", digest, got)
+	}
+
+	s.mu.Lock()
+	s.cacheLocked(digest, blob)
+	s.mu.Unlock()
+	return blob, nil
+}
+
+// cacheLocked adds blob to the cache, emptying it first when the bound
+// would be exceeded. Evicted blobs are re-read from kcp on the next Get.
+func (s *Store) cacheLocked(digest string, blob []byte) {
+	if _, ok := s.blobs[digest]; ok {
+		return
+	}
+	if s.blobBytes+len(blob) > maxCacheBytes {
+		s.blobs = map[string][]byte{}
+		s.blobBytes = 0
+	}
+	s.blobs[digest] = blob
+	s.blobBytes += len(blob)
+}
+
+// Run deletes expired bundles every collectInterval until ctx is done.
+func (s *Store) Run(ctx context.Context) {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := s.collect(ctx); err != nil {
+			klog.FromContext(ctx).Error(err, "Collecting expired manifest bundles")
+		}
+	}, collectInterval)
+}
+
+// collect deletes the bundles no Put has referenced within the retention
+// period. The delete is conditional on the listed resourceVersion, so a
+// bundle touched in the meantime survives.
+func (s *Store) collect(ctx context.Context) error {
+	list, err := s.client.CoreV1().ConfigMaps(s.namespace).List(ctx, metav1.ListOptions{LabelSelector: labelBundle + "=true"})
+	if err != nil {
+		return fmt.Errorf("listing manifest bundles: %w", err)
+	}
+	cutoff := s.now().Add(-s.retention)
+	for i := range list.Items {
+		cm := &list.Items[i]
+		lastUsed, err := time.Parse(time.RFC3339, cm.Annotations[annLastUsed])
+		if err != nil {
+			lastUsed = cm.CreationTimestamp.Time
+		}
+		if lastUsed.After(cutoff) {
+			continue
+		}
+		rv := cm.ResourceVersion
+		err = s.client.CoreV1().ConfigMaps(s.namespace).Delete(ctx, cm.Name, metav1.DeleteOptions{
+			Preconditions: &metav1.Preconditions{ResourceVersion: &rv},
+		})
+		if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+			return fmt.Errorf("deleting manifest bundle %s: %w", cm.Name, err)
+		}
+		digest := strings.Replace(cm.Name, "-", ":", 1)
+		s.mu.Lock()
+		if blob, ok := s.blobs[digest]; ok {
+			delete(s.blobs, digest)
+			s.blobBytes -= len(blob)
+		}
+		delete(s.touched, digest)
+		s.mu.Unlock()
+		klog.FromContext(ctx).V(2).Info("Deleted expired manifest bundle", "digest", digest, "lastUsed", lastUsed)
+	}
+	return nil
+}
+
+func compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, fmt.Errorf("compressing manifest bundle: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("compressing manifest bundle: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress returns the canonical encoding inside a gzipped blob.
+func Decompress(blob []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(blob))
+	if err != nil {
+		return nil, fmt.Errorf("decompressing manifest bundle: %w", err)
+	}
+	defer zr.Close()
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("decompressing manifest bundle: %w", err)
+	}
+	return data, nil
+}
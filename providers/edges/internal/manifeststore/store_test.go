@@ -0,0 +1,132 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifeststore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func raws(docs ...string) []runtime.RawExtension {
+	out := make([]runtime.RawExtension, 0, len(docs))
+	for _, d := range docs {
+		out = append(out, runtime.RawExtension{Raw: []byte(d)})
+	}
+	return out
+}
+
+func TestEncodeIsCanonical(t *testing.T) {
+	a := raws(`{"kind":"Deployment","apiVersion":"apps/v1","spec":{"replicas":3}}`, `{"kind":"Service"}`)
+	b := raws(`{ "apiVersion": "apps/v1", "spec": {"replicas": 3}, "kind": "Deployment" }`, `{"kind":"Service"}`)
+	dataA, refA, err := Encode(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, refB, err := Encode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refA != refB {
+		t.Fatalf("equal bundles got refs %+v and %+v", refA, refB)
+	}
+	if refA.Count != 2 || refA.Size != int64(len(dataA)) || !ValidDigest(refA.Digest) {
+		t.Fatalf("ref = %+v", refA)
+	}
+
+	// Order is significant: it is the apply order.
+	_, reversed, err := Encode(raws(`{"kind":"Service"}`, `{"kind":"Deployment","apiVersion":"apps/v1","spec":{"replicas":3}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reversed.Digest == refA.Digest {
+		t.Fatal("reordered bundle shares a digest")
+	}
+
+	decoded, err := Decode(dataA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 2 || string(decoded[1].Raw) != `{"kind":"Service"}` {
+		t.Fatalf("decoded = %s", decoded)
+	}
+}
+
+func TestPutGetCollect(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	s := newStore(client, Options{Retention: 24 * time.Hour})
+	s.now = func() time.Time { return now }
+
+	bundle := raws(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"web"}}`)
+	ref, err := s.Put(ctx, bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A second tenant rendering the same workload shares the stored copy.
+	if again, err := s.Put(ctx, bundle); err != nil || *again != *ref {
+		t.Fatalf("second Put = %+v, %v", again, err)
+	}
+	list, _ := client.CoreV1().ConfigMaps(DefaultNamespace).List(ctx, metav1.ListOptions{})
+	if len(list.Items) != 1 {
+		t.Fatalf("stored %d objects for one bundle", len(list.Items))
+	}
+
+	// A fresh process reads the bundle back from kcp and verifies it.
+	reader := newStore(client, Options{})
+	blob, err := reader.Get(ctx, ref.Digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := Decompress(blob)
+	if err != nil || Digest(data) != ref.Digest {
+		t.Fatalf("Get returned content with digest %s, %v", Digest(data), err)
+	}
+	if _, err := reader.Get(ctx, Digest([]byte("other"))); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get of an unknown digest = %v, want ErrNotFound", err)
+	}
+
+	// Within retention the bundle survives collection; past it, it goes.
+	now = now.Add(23 * time.Hour)
+	if err := s.collect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if list, _ := client.CoreV1().ConfigMaps(DefaultNamespace).List(ctx, metav1.ListOptions{}); len(list.Items) != 1 {
+		t.Fatal("bundle collected within its retention")
+	}
+	now = now.Add(2 * time.Hour)
+	if err := s.collect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if list, _ := client.CoreV1().ConfigMaps(DefaultNamespace).List(ctx, metav1.ListOptions{}); len(list.Items) != 0 {
+		t.Fatal("expired bundle not collected")
+	}
+
+	// Put after collection stores the bundle again.
+	if _, err := s.Put(ctx, bundle); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newStore(client, Options{}).Get(ctx, ref.Digest); err != nil {
+		t.Fatalf("bundle not restored after collection: %v", err)
+	}
+}
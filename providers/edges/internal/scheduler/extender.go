@@ -0,0 +1,151 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	edgesv1alpha1 "github.com/faroshq/provider-edges/apis/v1alpha1"
+)
+
+// DefaultExtenderTimeout bounds one extender call when no timeout is set.
+const DefaultExtenderTimeout = 5 * time.Second
+
+// maxExtenderResponseBytes bounds the extender's response body.
+const maxExtenderResponseBytes = 1 << 20
+
+// Extender is an out-of-tree scheduling step, modelled on kube-scheduler
+// extenders: an HTTP webhook the scheduler POSTs each Workload and the edges
+// matching its selector to, before applying the placement strategy. The
+// webhook filters (edges it leaves out are not scheduled to) and scores
+// (higher scores are preferred, so a Singleton lands on the best edge), which
+// lets cost models or network planners steer placements without changes to
+// the scheduler.
+type Extender struct {
+	url string
+	// failOpen schedules onto the unfiltered edges when the webhook fails;
+	// otherwise the Workload's placements are left as they are until it
+	// answers.
+	failOpen bool
+	client   *http.Client
+}
+
+// NewExtender returns an Extender calling url. A zero timeout means
+// DefaultExtenderTimeout.
+func NewExtender(url string, timeout time.Duration, failOpen bool) *Extender {
+	if timeout <= 0 {
+		timeout = DefaultExtenderTimeout
+	}
+	return &Extender{url: url, failOpen: failOpen, client: &http.Client{Timeout: timeout}}
+}
+
+// ExtenderArgs is the body POSTed to the extender.
+type ExtenderArgs struct {
+	// Cluster is the tenant workspace (kcp logical cluster) of the Workload.
+	Cluster  string                  `json:"cluster"`
+	Workload *edgesv1alpha1.Workload `json:"workload"`
+	// Edges are the candidate edges with their status, including the
+	// agent-reported inventory (node count, capacity, version).
+	Edges []edgesv1alpha1.KubernetesCluster `json:"edges"`
+}
+
+// ExtenderResult is the extender's answer.
+type ExtenderResult struct {
+	// Edges are the edges to keep, by name; any other candidate is filtered
+	// out. Names that were not candidates are ignored.
+	Edges []ExtenderEdgeScore `json:"edges"`
+	// FailedEdges optionally explains, per edge name, why an edge was
+	// filtered out. It is logged.
+	FailedEdges map[string]string `json:"failedEdges,omitempty"`
+	// Error fails the call, like a non-2xx status.
+	Error string `json:"error,omitempty"`
+}
+
+// ExtenderEdgeScore is one kept edge and its score.
+type ExtenderEdgeScore struct {
+	Name  string `json:"name"`
+	Score int64  `json:"score,omitempty"`
+}
+
+// Extend asks the extender to filter and score edges for vw and returns the
+// kept edges, highest score first; edges with equal scores keep their order.
+func (e *Extender) Extend(ctx context.Context, cluster string, vw *edgesv1alpha1.Workload, edges []edgesv1alpha1.KubernetesCluster) ([]edgesv1alpha1.KubernetesCluster, map[string]string, error) {
+	scores, failed, err := e.score(ctx, cluster, vw, edges)
+	if err != nil {
+		return nil, nil, err
+	}
+	return keepScored(edges, scores), failed, nil
+}
+
+// keepScored returns the edges that have a score, highest score first; edges
+// with equal scores keep their order.
+func keepScored(edges []edgesv1alpha1.KubernetesCluster, scores map[string]int64) []edgesv1alpha1.KubernetesCluster {
+	var kept []edgesv1alpha1.KubernetesCluster
+	for _, edge := range edges {
+		if _, ok := scores[edge.Name]; ok {
+			kept = append(kept, edge)
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return scores[kept[i].Name] > scores[kept[j].Name] })
+	return kept
+}
+
+// score calls the extender and returns the score of each edge it kept and
+// the reasons it gave for the edges it filtered out.
+func (e *Extender) score(ctx context.Context, cluster string, vw *edgesv1alpha1.Workload, edges []edgesv1alpha1.KubernetesCluster) (map[string]int64, map[string]string, error) {
+	body, err := json.Marshal(ExtenderArgs{Cluster: cluster, Workload: vw, Edges: edges})
+	if err != nil {
+		return nil, nil, fmt.Errorf("encoding extender request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, fmt.Errorf("building extender request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("calling scheduler extender: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxExtenderResponseBytes))
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading extender response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, nil, fmt.Errorf("scheduler extender returned %s: %s", resp.Status, bytes.TrimSpace(data))
+	}
+	var result ExtenderResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, nil, fmt.Errorf("decoding extender response: %w", err)
+	}
+	if result.Error != "" {
+		return nil, nil, fmt.Errorf("scheduler extender: %s", result.Error)
+	}
+
+	scores := make(map[string]int64, len(result.Edges))
+	for _, s := range result.Edges {
+		scores[s.Name] = s.Score
+	}
+	return scores, result.FailedEdges, nil
+}
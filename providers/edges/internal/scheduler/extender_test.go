@@ -0,0 +1,97 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	edgesv1alpha1 "github.com/faroshq/provider-edges/apis/v1alpha1"
+)
+
+func testEdges(names ...string) []edgesv1alpha1.KubernetesCluster {
+	var edges []edgesv1alpha1.KubernetesCluster
+	for _, n := range names {
+		edges = append(edges, edgesv1alpha1.KubernetesCluster{ObjectMeta: metav1.ObjectMeta{Name: n}})
+	}
+	return edges
+}
+
+func TestExtenderFiltersAndScores(t *testing.T) {
+	var got ExtenderArgs
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		_ = json.NewEncoder(w).Encode(ExtenderResult{
+			Edges: []ExtenderEdgeScore{
+				{Name: "c", Score: 10},
+				{Name: "a", Score: 1},
+				{Name: "d", Score: 1},
+				{Name: "unknown", Score: 100},
+			},
+			FailedEdges: map[string]string{"b": "too expensive"},
+		})
+	}))
+	defer srv.Close()
+
+	vw := &edgesv1alpha1.Workload{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}}
+	kept, failed, err := NewExtender(srv.URL, 0, false).Extend(context.Background(), "tenant1", vw, testEdges("a", "b", "c", "d"))
+	if err != nil {
+		t.Fatalf("Extend: %v", err)
+	}
+	if got.Cluster != "tenant1" || got.Workload == nil || got.Workload.Name != "web" || len(got.Edges) != 4 {
+		t.Errorf("request = cluster %q, workload %v, %d edges", got.Cluster, got.Workload, len(got.Edges))
+	}
+	var names []string
+	for _, e := range kept {
+		names = append(names, e.Name)
+	}
+	if want := []string{"c", "a", "d"}; len(names) != len(want) || names[0] != want[0] || names[1] != want[1] || names[2] != want[2] {
+		t.Errorf("kept = %v, want %v", names, want)
+	}
+	if failed["b"] != "too expensive" {
+		t.Errorf("failed = %v", failed)
+	}
+}
+
+func TestExtenderErrors(t *testing.T) {
+	for name, handler := range map[string]http.HandlerFunc{
+		"status": func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "boom", http.StatusInternalServerError)
+		},
+		"error field": func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(ExtenderResult{Error: "model unavailable"})
+		},
+		"malformed": func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("{"))
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(handler)
+			defer srv.Close()
+			if _, _, err := NewExtender(srv.URL, 0, false).Extend(context.Background(), "c", &edgesv1alpha1.Workload{}, testEdges("a")); err == nil {
+				t.Fatal("Extend succeeded, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/uuid"
+
+	edgesv1alpha1 "github.com/faroshq/provider-edges/apis/v1alpha1"
+)
+
+// DefaultPolicyTimeout bounds one policy call when no timeout is set.
+const DefaultPolicyTimeout = 5 * time.Second
+
+// maxPolicyResponseBytes bounds the policy service's response body.
+const maxPolicyResponseBytes = 1 << 20
+
+// policyUsername is the user a policy review names as the requester.
+const policyUsername = "system:kedge:scheduler"
+
+// clusterAnnotation carries the tenant workspace of the reviewed Placement,
+// as kcp sets it on objects it serves.
+const clusterAnnotation = "kcp.io/cluster"
+
+// Policy is an external admission step for Placements: before the scheduler
+// creates or updates a Placement it POSTs an admission.k8s.io/v1
+// AdmissionReview to the policy service, which may deny the write or mutate
+// the Placement with a JSONPatch, as a mutating admission webhook would. It
+// lets a central governance engine (OPA/Gatekeeper, Kyverno and the like)
+// gate what lands on edges without running inside every tenant workspace.
+type Policy struct {
+	url string
+	// failOpen writes the Placement unreviewed when the service fails;
+	// otherwise the write is skipped until it answers.
+	failOpen bool
+	client   *http.Client
+}
+
+// NewPolicy returns a Policy calling url. A zero timeout means
+// DefaultPolicyTimeout.
+func NewPolicy(url string, timeout time.Duration, failOpen bool) *Policy {
+	if timeout <= 0 {
+		timeout = DefaultPolicyTimeout
+	}
+	return &Policy{url: url, failOpen: failOpen, client: &http.Client{Timeout: timeout}}
+}
+
+// PolicyDeniedError is returned by Review when the policy service denies a
+// Placement.
+type PolicyDeniedError struct {
+	Message string
+}
+
+func (e *PolicyDeniedError) Error() string {
+	if e.Message == "" {
+		return "denied by placement policy"
+	}
+	return "denied by placement policy: " + e.Message
+}
+
+// Review asks the policy service to admit placement, a new Placement when old
+// is nil or an update of old otherwise, in the tenant workspace cluster. It
+// returns the Placement to write: placement itself, or a patched copy when
+// the service mutated it. A denial is a *PolicyDeniedError.
+//
+// Patches may not touch what ties the Placement to its Workload and edge:
+// the name, namespace, scheduler labels, owner references, workloadRef and
+// edgeName.
+func (p *Policy) Review(ctx context.Context, cluster string, placement, old *edgesv1alpha1.Placement) (*edgesv1alpha1.Placement, error) {
+	object, err := reviewObject(cluster, placement)
+	if err != nil {
+		return nil, err
+	}
+	req := &admissionv1.AdmissionRequest{
+		UID: uuid.NewUUID(),
+		Kind: metav1.GroupVersionKind{
+			Group: edgesv1alpha1.SchemeGroupVersion.Group, Version: edgesv1alpha1.SchemeGroupVersion.Version, Kind: "Placement",
+		},
+		Resource: metav1.GroupVersionResource{
+			Group: edgesv1alpha1.SchemeGroupVersion.Group, Version: edgesv1alpha1.SchemeGroupVersion.Version, Resource: "placements",
+		},
+		Name:      placement.Name,
+		Namespace: placement.Namespace,
+		Operation: admissionv1.Create,
+		UserInfo:  authenticationv1.UserInfo{Username: policyUsername},
+		Object:    runtime.RawExtension{Raw: object},
+	}
+	if old != nil {
+		req.Operation = admissionv1.Update
+		if req.OldObject.Raw, err = reviewObject(cluster, old); err != nil {
+			return nil, err
+		}
+	}
+	body, err := json.Marshal(admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: admissionv1.SchemeGroupVersion.String(), Kind: "AdmissionReview"},
+		Request:  req,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encoding policy review: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("building policy request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("calling placement policy: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPolicyResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("reading policy response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("placement policy returned %s: %s", resp.Status, bytes.TrimSpace(data))
+	}
+	var review admissionv1.AdmissionReview
+	if err := json.Unmarshal(data, &review); err != nil {
+		return nil, fmt.Errorf("decoding policy response: %w", err)
+	}
+	result := review.Response
+	if result == nil {
+		return nil, fmt.Errorf("placement policy returned no response")
+	}
+	if result.UID != req.UID {
+		return nil, fmt.Errorf("placement policy answered review %q, want %q", result.UID, req.UID)
+	}
+	if !result.Allowed {
+		denied := &PolicyDeniedError{}
+		if result.Result != nil {
+			denied.Message = result.Result.Message
+		}
+		return nil, denied
+	}
+	if len(result.Patch) == 0 {
+		return placement, nil
+	}
+	if result.PatchType == nil || *result.PatchType != admissionv1.PatchTypeJSONPatch {
+		return nil, fmt.Errorf("placement policy returned a patch that is not a JSONPatch")
+	}
+	return applyPolicyPatch(placement, object, result.Patch)
+}
+
+// reviewObject encodes p as the policy service sees it: with its kind set and
+// the tenant workspace in the kcp.io/cluster annotation.
+func reviewObject(cluster string, p *edgesv1alpha1.Placement) ([]byte, error) {
+	p = p.DeepCopy()
+	p.APIVersion = edgesv1alpha1.SchemeGroupVersion.String()
+	p.Kind = "Placement"
+	if cluster != "" {
+		if p.Annotations == nil {
+			p.Annotations = map[string]string{}
+		}
+		p.Annotations[clusterAnnotation] = cluster
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("encoding placement for policy review: %w", err)
+	}
+	return data, nil
+}
+
+// applyPolicyPatch applies a policy's JSONPatch to object, the reviewed
+// encoding of placement, and returns the patched Placement.
+func applyPolicyPatch(placement *edgesv1alpha1.Placement, object, patch []byte) (*edgesv1alpha1.Placement, error) {
+	ops, err := jsonpatch.DecodePatch(patch)
+	if err != nil {
+		return nil, fmt.Errorf("decoding policy patch: %w", err)
+	}
+	patched, err := ops.Apply(object)
+	if err != nil {
+		return nil, fmt.Errorf("applying policy patch: %w", err)
+	}
+	out := &edgesv1alpha1.Placement{}
+	if err := json.Unmarshal(patched, out); err != nil {
+		return nil, fmt.Errorf("decoding patched placement: %w", err)
+	}
+	out.TypeMeta = placement.TypeMeta
+	if _, ok := placement.Annotations[clusterAnnotation]; !ok {
+		delete(out.Annotations, clusterAnnotation)
+		if len(out.Annotations) == 0 {
+			out.Annotations = nil
+		}
+	}
+
+	switch {
+	case out.Name != placement.Name || out.Namespace != placement.Namespace:
+		return nil, fmt.Errorf("placement policy may not rename the placement")
+	case out.Labels[labelWorkload] != placement.Labels[labelWorkload] || out.Labels[labelEdge] != placement.Labels[labelEdge]:
+		return nil, fmt.Errorf("placement policy may not change the %s or %s labels", labelWorkload, labelEdge)
+	case !equality.Semantic.DeepEqual(out.OwnerReferences, placement.OwnerReferences):
+		return nil, fmt.Errorf("placement policy may not change owner references")
+	case out.Spec.EdgeName != placement.Spec.EdgeName || out.Spec.WorkloadRef != placement.Spec.WorkloadRef:
+		return nil, fmt.Errorf("placement policy may not change the edge or workload of a placement")
+	}
+	return out, nil
+}
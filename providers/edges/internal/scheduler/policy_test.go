@@ -0,0 +1,145 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	edgesv1alpha1 "github.com/faroshq/provider-edges/apis/v1alpha1"
+)
+
+// policyServer answers each review with respond's response, echoing the
+// request UID, and records the last request.
+func policyServer(t *testing.T, respond func(*admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse) (*httptest.Server, *admissionv1.AdmissionRequest) {
+	t.Helper()
+	got := &admissionv1.AdmissionRequest{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var review admissionv1.AdmissionReview
+		if err := json.NewDecoder(r.Body).Decode(&review); err != nil || review.Request == nil {
+			t.Errorf("decoding review: %v", err)
+			return
+		}
+		*got = *review.Request
+		resp := respond(review.Request)
+		resp.UID = review.Request.UID
+		review.Request, review.Response = nil, resp
+		_ = json.NewEncoder(w).Encode(review)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, got
+}
+
+func testPlacement() *edgesv1alpha1.Placement {
+	vw := &edgesv1alpha1.Workload{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: "uid-web"}}
+	return newPlacement(vw, "site-a", nil, nil)
+}
+
+func TestPolicyReview(t *testing.T) {
+	jsonPatch := admissionv1.PatchTypeJSONPatch
+	srv, got := policyServer(t, func(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+		return &admissionv1.AdmissionResponse{
+			Allowed:   true,
+			PatchType: &jsonPatch,
+			Patch:     []byte(`[{"op":"add","path":"/metadata/labels/team","value":"payments"}]`),
+		}
+	})
+
+	placement := testPlacement()
+	admitted, err := NewPolicy(srv.URL, 0, false).Review(context.Background(), "tenant1", placement, nil)
+	if err != nil {
+		t.Fatalf("Review: %v", err)
+	}
+	if got.Operation != admissionv1.Create || got.Resource.Resource != "placements" || got.Name != placement.Name || len(got.OldObject.Raw) != 0 {
+		t.Errorf("request = %s %s %s, old object %q", got.Operation, got.Resource.Resource, got.Name, got.OldObject.Raw)
+	}
+	var sent edgesv1alpha1.Placement
+	if err := json.Unmarshal(got.Object.Raw, &sent); err != nil {
+		t.Fatal(err)
+	}
+	if sent.Kind != "Placement" || sent.Annotations[clusterAnnotation] != "tenant1" || sent.Spec.EdgeName != "site-a" {
+		t.Errorf("sent object = %+v", sent)
+	}
+	if admitted.Labels["team"] != "payments" || admitted.Labels[labelEdge] != "site-a" {
+		t.Errorf("admitted labels = %v", admitted.Labels)
+	}
+	if admitted.Annotations != nil {
+		t.Errorf("admitted annotations = %v, want the cluster annotation dropped", admitted.Annotations)
+	}
+	if placement.Labels["team"] != "" {
+		t.Error("Review mutated its argument")
+	}
+
+	if _, err := NewPolicy(srv.URL, 0, false).Review(context.Background(), "tenant1", placement, testPlacement()); err != nil {
+		t.Fatalf("Review of an update: %v", err)
+	}
+	if got.Operation != admissionv1.Update || len(got.OldObject.Raw) == 0 {
+		t.Errorf("update request = %s, old object %q", got.Operation, got.OldObject.Raw)
+	}
+}
+
+func TestPolicyReviewDenies(t *testing.T) {
+	srv, _ := policyServer(t, func(*admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+		return &admissionv1.AdmissionResponse{Result: &metav1.Status{Message: "site-a is frozen"}}
+	})
+	_, err := NewPolicy(srv.URL, 0, true).Review(context.Background(), "tenant1", testPlacement(), nil)
+	var denied *PolicyDeniedError
+	if !errors.As(err, &denied) || denied.Message != "site-a is frozen" {
+		t.Errorf("Review = %v, want a denial", err)
+	}
+}
+
+func TestPolicyReviewErrors(t *testing.T) {
+	jsonPatch := admissionv1.PatchTypeJSONPatch
+	patch := func(p string) func(*admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+		return func(*admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+			return &admissionv1.AdmissionResponse{Allowed: true, PatchType: &jsonPatch, Patch: []byte(p)}
+		}
+	}
+	for name, respond := range map[string]func(*admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse{
+		"edge changed":  patch(`[{"op":"replace","path":"/spec/edgeName","value":"site-b"}]`),
+		"renamed":       patch(`[{"op":"replace","path":"/metadata/name","value":"other"}]`),
+		"label removed": patch(`[{"op":"remove","path":"/metadata/labels/edges.kedge.faros.sh~1edge"}]`),
+		"owner removed": patch(`[{"op":"remove","path":"/metadata/ownerReferences"}]`),
+		"bad patch":     patch(`[{"op":"remove","path":"/nope"}]`),
+		"not a json patch": func(*admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+			return &admissionv1.AdmissionResponse{Allowed: true, Patch: []byte(`{}`)}
+		},
+	} {
+		srv, _ := policyServer(t, respond)
+		_, err := NewPolicy(srv.URL, 0, false).Review(context.Background(), "tenant1", testPlacement(), nil)
+		var denied *PolicyDeniedError
+		if err == nil || errors.As(err, &denied) {
+			t.Errorf("%s: Review = %v, want a non-denial error", name, err)
+		}
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	if _, err := NewPolicy(srv.URL, 0, false).Review(context.Background(), "tenant1", testPlacement(), nil); err == nil {
+		t.Error("Review succeeded against a failing service")
+	}
+}
@@ -0,0 +1,297 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	edgesv1alpha1 "github.com/faroshq/provider-edges/apis/v1alpha1"
+)
+
+// maxPreviewRequestBytes bounds the Workload POSTed to the preview.
+const maxPreviewRequestBytes = 1 << 20
+
+// Preview answers which edges the scheduler would place a Workload on, and
+// why, without writing anything: the same edge selector, extender and
+// strategy steps the Reconciler runs, reported per edge. The placement policy
+// is not consulted, as it reviews the rendered Placements rather than the
+// choice of edges.
+type Preview struct {
+	extender *Extender
+
+	mu            sync.RWMutex
+	clusterClient func(ctx context.Context, cluster string) (client.Client, error)
+}
+
+// NewPreview returns a Preview scoring edges with extender, which may be nil.
+// It answers 503 until SetClusterClient is called.
+func NewPreview(extender *Extender) *Preview {
+	return &Preview{extender: extender}
+}
+
+// SetClusterClient sets how the preview reads a tenant workspace's edges and
+// Placements, once the multicluster manager is up.
+func (p *Preview) SetClusterClient(f func(ctx context.Context, cluster string) (client.Client, error)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clusterClient = f
+}
+
+// PreviewResult is the preview of one Workload.
+type PreviewResult struct {
+	// Edges, Matched and Selected count the workspace's edges, those
+	// matching the edge selector and kept by the extender, and those the
+	// strategy selects.
+	Edges    int `json:"edges"`
+	Matched  int `json:"matched"`
+	Selected int `json:"selected"`
+	// ExtenderError is set when the extender failed. Fail-open, every
+	// matched edge stays a candidate; fail-closed, the Workload keeps its
+	// current placements.
+	ExtenderError string `json:"extenderError,omitempty"`
+	// Results has one entry per edge: selected edges first, in the order the
+	// strategy picks them, then the others by name.
+	Results []PreviewEdge `json:"results"`
+}
+
+// PreviewEdge is the outcome for one edge.
+type PreviewEdge struct {
+	Name     string            `json:"name"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Selected bool              `json:"selected"`
+	// Placed is true when the Workload has a Placement on the edge now.
+	Placed bool `json:"placed"`
+	// Score is the extender's score, unset without an extender or for edges
+	// it did not keep.
+	Score *int64 `json:"score,omitempty"`
+	// Unmatched lists the edge selector requirements the edge fails.
+	Unmatched []string `json:"unmatched,omitempty"`
+	// Reason says why the edge is or is not selected.
+	Reason string `json:"reason"`
+}
+
+// ServeHTTP previews the Workload POSTed as JSON in the caller's workspace,
+// the X-Kedge-Cluster header the hub's backend proxy sets after
+// authenticating the caller. An unset namespace means "default".
+func (p *Preview) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	cluster := r.Header.Get("X-Kedge-Cluster")
+	if cluster == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	p.mu.RLock()
+	clusterClient := p.clusterClient
+	p.mu.RUnlock()
+	if clusterClient == nil {
+		http.Error(w, "scheduler is not running", http.StatusServiceUnavailable)
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxPreviewRequestBytes+1))
+	if err != nil {
+		http.Error(w, "reading request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(data) > maxPreviewRequestBytes {
+		http.Error(w, "workload too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	var vw edgesv1alpha1.Workload
+	if err := json.Unmarshal(data, &vw); err != nil {
+		http.Error(w, "decoding workload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if vw.Namespace == "" {
+		vw.Namespace = "default"
+	}
+
+	ctx := r.Context()
+	c, err := clusterClient(ctx, cluster)
+	if err != nil {
+		klog.FromContext(ctx).Error(err, "Engaging tenant cluster for placement preview", "cluster", cluster)
+		http.Error(w, "workspace unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	var edgeList edgesv1alpha1.KubernetesClusterList
+	if err := c.List(ctx, &edgeList); err != nil {
+		http.Error(w, "listing edges: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	placed := map[string]bool{}
+	if vw.Name != "" {
+		var placementList edgesv1alpha1.PlacementList
+		if err := c.List(ctx, &placementList,
+			client.InNamespace(vw.Namespace),
+			client.MatchingLabels{labelWorkload: vw.Name}); err != nil {
+			http.Error(w, "listing placements: "+err.Error(), http.StatusBadGateway)
+			return
+		}
+		for _, pl := range placementList.Items {
+			placed[pl.Spec.EdgeName] = true
+		}
+	}
+
+	result, err := p.preview(ctx, cluster, &vw, edgeList.Items, placed)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+// preview runs the scheduling steps for vw over edges. placed names the
+// edges the Workload has a Placement on now.
+func (p *Preview) preview(ctx context.Context, cluster string, vw *edgesv1alpha1.Workload, edges []edgesv1alpha1.KubernetesCluster, placed map[string]bool) (*PreviewResult, error) {
+	results := make(map[string]*PreviewEdge, len(edges))
+	for _, edge := range edges {
+		results[edge.Name] = &PreviewEdge{Name: edge.Name, Labels: edge.Labels, Placed: placed[edge.Name]}
+	}
+
+	var matched []edgesv1alpha1.KubernetesCluster
+	if sel := vw.Spec.Placement.EdgeSelector; sel != nil {
+		selector, err := metav1.LabelSelectorAsSelector(sel)
+		if err != nil {
+			return nil, fmt.Errorf("invalid edge selector: %w", err)
+		}
+		requirements, _ := selector.Requirements()
+		for _, edge := range edges {
+			set := labels.Set(edge.Labels)
+			for _, req := range requirements {
+				if !req.Matches(set) {
+					results[edge.Name].Unmatched = append(results[edge.Name].Unmatched, req.String())
+				}
+			}
+			if len(results[edge.Name].Unmatched) == 0 {
+				matched = append(matched, edge)
+			} else {
+				results[edge.Name].Reason = "does not match the edge selector"
+			}
+		}
+	} else {
+		matched = edges
+	}
+
+	result := &PreviewResult{Edges: len(edges)}
+	var selected []edgesv1alpha1.KubernetesCluster
+	keepCurrent := false
+	if p.extender != nil && len(matched) > 0 {
+		scores, failed, err := p.extender.score(ctx, cluster, vw, matched)
+		switch {
+		case err != nil:
+			result.ExtenderError = err.Error()
+			keepCurrent = !p.extender.failOpen
+		default:
+			for _, edge := range matched {
+				score, ok := scores[edge.Name]
+				if !ok {
+					reason := "filtered out by the scheduler extender"
+					if failed[edge.Name] != "" {
+						reason += ": " + failed[edge.Name]
+					}
+					results[edge.Name].Reason = reason
+					continue
+				}
+				results[edge.Name].Score = &score
+			}
+			matched = keepScored(matched, scores)
+		}
+	}
+	result.Matched = len(matched)
+
+	if keepCurrent {
+		for _, edge := range matched {
+			if placed[edge.Name] {
+				selected = append(selected, edge)
+			}
+		}
+		for _, edge := range edges {
+			r := results[edge.Name]
+			switch {
+			case r.Reason != "":
+			case placed[edge.Name]:
+				r.Reason = "current placement kept while the scheduler extender fails"
+			default:
+				r.Reason = "not placed while the scheduler extender fails"
+			}
+		}
+	} else {
+		selected = SelectEdges(matched, vw.Spec.Placement.Strategy)
+		for _, edge := range matched {
+			results[edge.Name].Reason = fmt.Sprintf("not selected: the %s strategy places on one edge", edgesv1alpha1.PlacementStrategySingleton)
+		}
+		for _, edge := range selected {
+			results[edge.Name].Reason = selectedReason(vw, results[edge.Name], result.ExtenderError != "")
+		}
+	}
+	result.Selected = len(selected)
+
+	for _, edge := range selected {
+		results[edge.Name].Selected = true
+		result.Results = append(result.Results, *results[edge.Name])
+	}
+	var rest []PreviewEdge
+	for _, r := range results {
+		if !r.Selected {
+			rest = append(rest, *r)
+		}
+	}
+	sort.Slice(rest, func(i, j int) bool { return rest[i].Name < rest[j].Name })
+	result.Results = append(result.Results, rest...)
+	return result, nil
+}
+
+// selectedReason explains why a selected edge was chosen.
+func selectedReason(vw *edgesv1alpha1.Workload, r *PreviewEdge, extenderFailed bool) string {
+	var parts []string
+	if vw.Spec.Placement.EdgeSelector != nil {
+		parts = append(parts, "matches the edge selector")
+	} else {
+		parts = append(parts, "no edge selector")
+	}
+	switch {
+	case extenderFailed:
+		parts = append(parts, "scheduler extender failed open")
+	case r.Score != nil:
+		parts = append(parts, fmt.Sprintf("extender score %d", *r.Score))
+	}
+	if vw.Spec.Placement.Strategy == edgesv1alpha1.PlacementStrategySingleton {
+		if r.Score != nil {
+			parts = append(parts, "highest score for the Singleton strategy")
+		} else {
+			parts = append(parts, "first candidate for the Singleton strategy")
+		}
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	edgesv1alpha1 "github.com/faroshq/provider-edges/apis/v1alpha1"
+)
+
+func previewWorkload(strategy edgesv1alpha1.PlacementStrategy) *edgesv1alpha1.Workload {
+	return &edgesv1alpha1.Workload{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: edgesv1alpha1.WorkloadSpec{Placement: edgesv1alpha1.PlacementSpec{
+			EdgeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"region": "eu"}},
+			Strategy:     strategy,
+		}},
+	}
+}
+
+func labeledEdges() []edgesv1alpha1.KubernetesCluster {
+	edges := testEdges("a", "b", "c", "d")
+	edges[0].Labels = map[string]string{"region": "eu"}
+	edges[1].Labels = map[string]string{"region": "us"}
+	edges[2].Labels = map[string]string{"region": "eu"}
+	edges[3].Labels = map[string]string{"region": "eu"}
+	return edges
+}
+
+func previewNames(r *PreviewResult) (selected, rest []string) {
+	for _, e := range r.Results {
+		if e.Selected {
+			selected = append(selected, e.Name)
+		} else {
+			rest = append(rest, e.Name)
+		}
+	}
+	return selected, rest
+}
+
+func TestPreviewScoresAndExplains(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(ExtenderResult{
+			Edges:       []ExtenderEdgeScore{{Name: "a", Score: 1}, {Name: "c", Score: 7}},
+			FailedEdges: map[string]string{"d": "too expensive"},
+		})
+	}))
+	defer srv.Close()
+
+	p := NewPreview(NewExtender(srv.URL, 0, false))
+	r, err := p.preview(context.Background(), "tenant1", previewWorkload(edgesv1alpha1.PlacementStrategySingleton), labeledEdges(), map[string]bool{"a": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Edges != 4 || r.Matched != 2 || r.Selected != 1 {
+		t.Errorf("counts = %d/%d/%d, want 4/2/1", r.Edges, r.Matched, r.Selected)
+	}
+	selected, rest := previewNames(r)
+	if strings.Join(selected, ",") != "c" || strings.Join(rest, ",") != "a,b,d" {
+		t.Errorf("selected %v, rest %v", selected, rest)
+	}
+	byName := map[string]PreviewEdge{}
+	for _, e := range r.Results {
+		byName[e.Name] = e
+	}
+	if e := byName["c"]; e.Score == nil || *e.Score != 7 || !strings.Contains(e.Reason, "highest score") {
+		t.Errorf("c = %+v", e)
+	}
+	if e := byName["a"]; !e.Placed || !strings.Contains(e.Reason, "Singleton") {
+		t.Errorf("a = %+v", e)
+	}
+	if e := byName["b"]; len(e.Unmatched) != 1 || e.Unmatched[0] != "region=eu" {
+		t.Errorf("b = %+v", e)
+	}
+	if e := byName["d"]; e.Score != nil || !strings.Contains(e.Reason, "too expensive") {
+		t.Errorf("d = %+v", e)
+	}
+}
+
+func TestPreviewExtenderFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	vw := previewWorkload(edgesv1alpha1.PlacementStrategySpread)
+
+	// Fail-closed keeps the current placements.
+	r, err := NewPreview(NewExtender(srv.URL, 0, false)).preview(context.Background(), "tenant1", vw, labeledEdges(), map[string]bool{"d": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if selected, _ := previewNames(r); r.ExtenderError == "" || strings.Join(selected, ",") != "d" {
+		t.Errorf("fail-closed: selected %v, extender error %q", selected, r.ExtenderError)
+	}
+
+	// Fail-open spreads over every matched edge.
+	r, err = NewPreview(NewExtender(srv.URL, 0, true)).preview(context.Background(), "tenant1", vw, labeledEdges(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if selected, _ := previewNames(r); strings.Join(selected, ",") != "a,c,d" {
+		t.Errorf("fail-open: selected %v", selected)
+	}
+}
+
+func TestPreviewServeHTTPRequiresCluster(t *testing.T) {
+	p := NewPreview(nil)
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/scheduler/preview", strings.NewReader("{}")))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("no cluster: status %d, want 401", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/scheduler/preview", strings.NewReader("{}"))
+	req.Header.Set("X-Kedge-Cluster", "tenant1")
+	p.ServeHTTP(rec, req)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("no cluster client: status %d, want 503", rec.Code)
+	}
+}
@@ -18,6 +18,7 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -25,6 +26,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -32,6 +34,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	edgesv1alpha1 "github.com/faroshq/provider-edges/apis/v1alpha1"
+	"github.com/faroshq/provider-edges/internal/manifeststore"
 	"github.com/faroshq/provider-edges/internal/render"
 
 	mcbuilder "sigs.k8s.io/multicluster-runtime/pkg/builder"
@@ -46,13 +49,31 @@ import (
 // matching KubernetesCluster edges.
 type Reconciler struct {
 	mgr mcmanager.Manager
+
+	// store, when set, holds rendered bundles so Placements carry a
+	// ManifestsRef instead of the manifests themselves.
+	store *manifeststore.Store
+
+	// extender, when set, filters and scores the matched edges.
+	extender *Extender
+
+	// policy, when set, admits (and may mutate) each Placement before it is
+	// written.
+	policy *Policy
+
+	// costLabels are the Workload label keys copied onto its Placements for
+	// cost attribution.
+	costLabels []string
 }
 
 // SetupWithManager registers the Workload scheduler with the multicluster
 // manager. It watches Workload and re-enqueues on KubernetesCluster changes
-// so newly connected / relabeled edges are (re)scheduled.
-func SetupWithManager(mgr mcmanager.Manager) error {
-	r := &Reconciler{mgr: mgr}
+// so newly connected / relabeled edges are (re)scheduled. A nil store keeps
+// manifests inline on every Placement; a nil extender schedules onto every
+// matched edge; a nil policy writes Placements unreviewed. costLabels name
+// the Workload labels each Placement carries along for cost attribution.
+func SetupWithManager(mgr mcmanager.Manager, store *manifeststore.Store, extender *Extender, policy *Policy, costLabels []string) error {
+	r := &Reconciler{mgr: mgr, store: store, extender: extender, policy: policy, costLabels: costLabels}
 	klog.Info("Registering Workload scheduler controller")
 	return mcbuilder.ControllerManagedBy(mgr).
 		Named(controllerName).
@@ -92,13 +113,31 @@ func (r *Reconciler) Reconcile(ctx context.Context, req mcreconcile.Request) (ct
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("matching edges: %w", err)
 	}
+	if r.extender != nil && len(matched) > 0 {
+		extended, failed, err := r.extender.Extend(ctx, string(req.ClusterName), &vw, matched)
+		switch {
+		case err != nil && r.extender.failOpen:
+			logger.Error(err, "Scheduler extender failed; scheduling onto all matched edges")
+		case err != nil:
+			// Leave the placements as they are rather than act on a
+			// partial view; the periodic requeue retries.
+			logger.Error(err, "Scheduler extender failed; keeping current placements")
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		default:
+			if len(failed) > 0 {
+				logger.V(2).Info("Scheduler extender filtered edges", "edges", failed)
+			}
+			matched = extended
+		}
+	}
 	selected := SelectEdges(matched, vw.Spec.Placement.Strategy)
 	logger.V(4).Info("Scheduling", "edges", len(edgeList.Items), "matched", len(matched), "selected", len(selected))
 
 	// Render the workload into a manifest bundle once (Helm charts are fetched
-	// + templated here, hub-side). The same bundle is stored on every
-	// Placement; the agent stamps per-placement labels at apply time. A render
-	// failure (e.g. chart fetch) requeues rather than creating empty placements.
+	// + templated here, hub-side). The same bundle goes on every Placement —
+	// inline, or as a reference into the manifest store; the agent stamps
+	// per-placement labels at apply time. A render failure (e.g. chart fetch)
+	// requeues rather than creating empty placements.
 	objs, err := render.Render(ctx, &vw)
 	if err != nil {
 		logger.Error(err, "Failed to render workload")
@@ -108,6 +147,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req mcreconcile.Request) (ct
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("encoding rendered manifests: %w", err)
 	}
+	manifests, manifestsRef := r.storeManifests(ctx, manifests)
 
 	// List existing placements for this VW.
 	var placementList edgesv1alpha1.PlacementList
@@ -143,60 +183,142 @@ func (r *Reconciler) Reconcile(ctx context.Context, req mcreconcile.Request) (ct
 
 	// Create or refresh a placement per selected edge.
 	for _, edge := range selected {
-		if existing, ok := existingByEdge[edge.Name]; ok {
-			if equality.Semantic.DeepEqual(existing.Spec.Manifests, manifests) &&
+		existing, ok := existingByEdge[edge.Name]
+		var placement *edgesv1alpha1.Placement
+		if ok {
+			placement = existing.DeepCopy()
+			labelsChanged := syncCostLabels(placement, vw.Labels, r.costLabels)
+			if !labelsChanged &&
+				equality.Semantic.DeepEqual(existing.Spec.Manifests, manifests) &&
+				equality.Semantic.DeepEqual(existing.Spec.ManifestsRef, manifestsRef) &&
 				equalReplicas(existing.Spec.Replicas, vw.Spec.Replicas) {
 				continue
 			}
-			existing.Spec.Manifests = manifests
-			existing.Spec.Replicas = vw.Spec.Replicas
-			logger.Info("Refreshing placement manifests", "placement", existing.Name, "edge", edge.Name)
-			if err := c.Update(ctx, existing); err != nil && !apierrors.IsConflict(err) {
-				logger.Error(err, "Failed to update placement", "name", existing.Name)
+			placement.Spec.Manifests = manifests
+			placement.Spec.ManifestsRef = manifestsRef
+			placement.Spec.Replicas = vw.Spec.Replicas
+		} else {
+			placement = newPlacement(&vw, edge.Name, manifests, manifestsRef)
+			syncCostLabels(placement, vw.Labels, r.costLabels)
+		}
+
+		if r.policy != nil {
+			var old *edgesv1alpha1.Placement
+			if ok {
+				old = existing
+			}
+			admitted, err := r.policy.Review(ctx, string(req.ClusterName), placement, old)
+			var denied *PolicyDeniedError
+			switch {
+			case errors.As(err, &denied):
+				logger.Info("Placement policy denied placement", "placement", placement.Name, "edge", edge.Name, "reason", denied.Message)
+				continue
+			case err != nil && r.policy.failOpen:
+				logger.Error(err, "Placement policy failed; writing placement unreviewed", "placement", placement.Name)
+			case err != nil:
+				logger.Error(err, "Placement policy failed; skipping placement until it answers", "placement", placement.Name)
+				continue
+			default:
+				placement = admitted
+			}
+			// A policy that mutates the placement the same way every time
+			// leaves nothing to write.
+			if ok && equality.Semantic.DeepEqual(existing.Labels, placement.Labels) &&
+				equality.Semantic.DeepEqual(existing.Annotations, placement.Annotations) &&
+				equality.Semantic.DeepEqual(existing.Spec, placement.Spec) {
+				continue
+			}
+		}
+
+		if ok {
+			logger.Info("Refreshing placement", "placement", placement.Name, "edge", edge.Name)
+			if err := c.Update(ctx, placement); err != nil && !apierrors.IsConflict(err) {
+				logger.Error(err, "Failed to update placement", "name", placement.Name)
 			}
 			continue
 		}
+		logger.Info("Creating placement", "placement", placement.Name, "edge", edge.Name)
+		if err := c.Create(ctx, placement); err != nil && !apierrors.IsAlreadyExists(err) {
+			logger.Error(err, "Failed to create placement", "name", placement.Name)
+		}
+	}
 
-		placement := &edgesv1alpha1.Placement{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      fmt.Sprintf("%s-%s", vw.Name, edge.Name),
-				Namespace: vw.Namespace,
-				Labels: map[string]string{
-					labelWorkload: vw.Name,
-					labelEdge:     edge.Name,
-				},
-				OwnerReferences: []metav1.OwnerReference{
-					{
-						APIVersion: edgesv1alpha1.SchemeGroupVersion.String(),
-						Kind:       "Workload",
-						Name:       vw.Name,
-						UID:        vw.UID,
-					},
-				},
+	// Requeue periodically so edge reconnects are picked up even if a watch
+	// event was missed (status-only changes may not always fire the mapper).
+	return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+}
+
+// newPlacement returns the Placement of vw on edge, owned by vw.
+func newPlacement(vw *edgesv1alpha1.Workload, edge string, manifests []runtime.RawExtension, manifestsRef *edgesv1alpha1.ManifestsRef) *edgesv1alpha1.Placement {
+	return &edgesv1alpha1.Placement{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", vw.Name, edge),
+			Namespace: vw.Namespace,
+			Labels: map[string]string{
+				labelWorkload: vw.Name,
+				labelEdge:     edge,
 			},
-			Spec: edgesv1alpha1.PlacementObjSpec{
-				WorkloadRef: corev1.ObjectReference{
+			OwnerReferences: []metav1.OwnerReference{
+				{
 					APIVersion: edgesv1alpha1.SchemeGroupVersion.String(),
 					Kind:       "Workload",
 					Name:       vw.Name,
-					Namespace:  vw.Namespace,
 					UID:        vw.UID,
 				},
-				EdgeName:  edge.Name,
-				Replicas:  vw.Spec.Replicas,
-				Manifests: manifests,
 			},
-		}
+		},
+		Spec: edgesv1alpha1.PlacementObjSpec{
+			WorkloadRef: corev1.ObjectReference{
+				APIVersion: edgesv1alpha1.SchemeGroupVersion.String(),
+				Kind:       "Workload",
+				Name:       vw.Name,
+				Namespace:  vw.Namespace,
+				UID:        vw.UID,
+			},
+			EdgeName:     edge,
+			Replicas:     vw.Spec.Replicas,
+			Manifests:    manifests,
+			ManifestsRef: manifestsRef,
+		},
+	}
+}
 
-		logger.Info("Creating placement", "placement", placement.Name, "edge", edge.Name)
-		if err := c.Create(ctx, placement); err != nil && !apierrors.IsAlreadyExists(err) {
-			logger.Error(err, "Failed to create placement", "name", placement.Name)
-		}
+// storeManifests moves a rendered bundle into the manifest store when one is
+// configured, returning what the Placements carry: either the manifests
+// inline or a reference to the stored copy. A bundle the store cannot take
+// (too large, or kcp unavailable) stays inline, which every agent applies.
+func (r *Reconciler) storeManifests(ctx context.Context, manifests []runtime.RawExtension) ([]runtime.RawExtension, *edgesv1alpha1.ManifestsRef) {
+	if r.store == nil || len(manifests) == 0 {
+		return manifests, nil
+	}
+	ref, err := r.store.Put(ctx, manifests)
+	if err != nil {
+		klog.FromContext(ctx).Error(err, "Keeping rendered manifests inline")
+		return manifests, nil
 	}
+	return nil, ref
+}
 
-	// Requeue periodically so edge reconnects are picked up even if a watch
-	// event was missed (status-only changes may not always fire the mapper).
-	return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+// syncCostLabels copies the labels named by keys from workloadLabels onto p,
+// removing those the Workload no longer has, and reports whether p changed.
+func syncCostLabels(p *edgesv1alpha1.Placement, workloadLabels map[string]string, keys []string) bool {
+	changed := false
+	for _, k := range keys {
+		want, ok := workloadLabels[k]
+		have, had := p.Labels[k]
+		switch {
+		case ok && (!had || have != want):
+			if p.Labels == nil {
+				p.Labels = map[string]string{}
+			}
+			p.Labels[k] = want
+			changed = true
+		case !ok && had:
+			delete(p.Labels, k)
+			changed = true
+		}
+	}
+	return changed
 }
 
 func equalReplicas(a, b *int32) bool {
@@ -0,0 +1,46 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	edgesv1alpha1 "github.com/faroshq/provider-edges/apis/v1alpha1"
+)
+
+func TestSyncCostLabels(t *testing.T) {
+	keys := []string{"team", "cost-center"}
+	p := &edgesv1alpha1.Placement{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+		edgesv1alpha1.LabelWorkload: "pos", "cost-center": "cc-1",
+	}}}
+
+	if !syncCostLabels(p, map[string]string{"team": "retail", "other": "x"}, keys) {
+		t.Fatal("labels changed but reported unchanged")
+	}
+	want := map[string]string{edgesv1alpha1.LabelWorkload: "pos", "team": "retail"}
+	if len(p.Labels) != len(want) || p.Labels["team"] != "retail" || p.Labels[edgesv1alpha1.LabelWorkload] != "pos" {
+		t.Errorf("labels = %v, want %v", p.Labels, want)
+	}
+	if syncCostLabels(p, map[string]string{"team": "retail"}, keys) {
+		t.Error("in-sync labels reported changed")
+	}
+	if syncCostLabels(p, map[string]string{"team": "ops"}, nil) {
+		t.Error("no cost labels configured but reported changed")
+	}
+}
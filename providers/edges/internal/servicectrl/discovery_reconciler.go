@@ -25,10 +25,12 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	mcbuilder "sigs.k8s.io/multicluster-runtime/pkg/builder"
+	mccontroller "sigs.k8s.io/multicluster-runtime/pkg/controller"
 	mcmanager "sigs.k8s.io/multicluster-runtime/pkg/manager"
 	mcreconcile "sigs.k8s.io/multicluster-runtime/pkg/reconcile"
 
@@ -47,11 +49,13 @@ type DiscoveryReconciler struct {
 }
 
 // SetupDiscoveryWithManager registers the discovery reconciler (For LinuxServer).
+// It runs on every replica: only the one holding an edge's tunnel scans it.
 func SetupDiscoveryWithManager(mgr mcmanager.Manager, connManager ConnManager) error {
 	r := &DiscoveryReconciler{mgr: mgr, connManager: connManager}
 	return mcbuilder.ControllerManagedBy(mgr).
 		Named("service-discovery").
 		For(&edgesv1alpha1.LinuxServer{}).
+		WithOptions(mccontroller.Options{NeedLeaderElection: ptr.To(false)}).
 		Complete(r)
 }
 
@@ -36,13 +36,17 @@ type Options struct {
 	// Ready and stops it when the Service is deleted or goes NotReady. Nil
 	// disables event subscriptions.
 	Events *events.Manager
+	// Instance is this replica's name in edges' status.tunnel. A Service
+	// whose edge tunnel another live replica holds is left to that replica.
+	Instance string
 }
 
 // SetupWithManager registers both Service controllers on the multicluster
-// manager, sharing the tunnel ConnManager for agent dials.
+// manager, sharing the tunnel ConnManager for agent dials. Both need the
+// edge's tunnel, so they run on every replica, leader election or not.
 func SetupWithManager(mgr mcmanager.Manager, connManager ConnManager, opts Options) error {
 	if err := SetupDiscoveryWithManager(mgr, connManager); err != nil {
 		return err
 	}
-	return SetupValidationWithManager(mgr, connManager, opts.EdgeProxyPublicPath, opts.Events)
+	return SetupValidationWithManager(mgr, connManager, opts.EdgeProxyPublicPath, opts.Events, opts.Instance)
 }
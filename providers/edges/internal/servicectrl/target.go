@@ -17,7 +17,13 @@ limitations under the License.
 package servicectrl
 
 import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
 	edgesv1alpha1 "github.com/faroshq/provider-edges/apis/v1alpha1"
+	edgeapi "github.com/faroshq/provider-edges/internal/edgeapi"
 )
 
 // kubernetesClusterKind is the edgeRef.kind value for a KubernetesCluster edge.
@@ -37,6 +43,19 @@ func connResource(es *edgesv1alpha1.Service) string {
 	return edgesv1alpha1.LinuxServerResource
 }
 
+// edgeHeldByPeer reports whether a replica other than self holds the tunnel
+// of the edge es references. That replica reconciles the Service.
+func edgeHeldByPeer(ctx context.Context, c client.Client, es *edgesv1alpha1.Service, self string) bool {
+	var edge edgeapi.Connectable = &edgesv1alpha1.LinuxServer{}
+	if isKube(es) {
+		edge = &edgesv1alpha1.KubernetesCluster{}
+	}
+	if err := c.Get(ctx, client.ObjectKey{Name: es.Spec.EdgeRef.Name}, edge); err != nil {
+		return false
+	}
+	return edge.GetConnectionStatus().Tunnel.HeldByPeer(self, time.Now())
+}
+
 // targetHost is the agent-side address of the service. It must stay in lockstep
 // with the tunnel's serviceView.targetHost (service_proxy.go) so the validation
 // probe reaches the same host the proxy does:
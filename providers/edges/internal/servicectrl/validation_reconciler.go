@@ -31,12 +31,14 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	mcbuilder "sigs.k8s.io/multicluster-runtime/pkg/builder"
 	mccontext "sigs.k8s.io/multicluster-runtime/pkg/context"
+	mccontroller "sigs.k8s.io/multicluster-runtime/pkg/controller"
 	mchandler "sigs.k8s.io/multicluster-runtime/pkg/handler"
 	mcmanager "sigs.k8s.io/multicluster-runtime/pkg/manager"
 	"sigs.k8s.io/multicluster-runtime/pkg/multicluster"
@@ -59,17 +61,21 @@ type ValidationReconciler struct {
 	edgeProxyPublicPath string
 	// events, when non-nil, runs a per-Service event subscriber (UniFi Protect).
 	events *events.Manager
+	// instance is this replica's name in edges' status.tunnel.
+	instance string
 }
 
 // SetupValidationWithManager registers the validation reconciler (For Service).
 // It also watches Secrets so an edited auth token is re-validated immediately,
-// rather than waiting up to validationResyncInterval for the next resync.
-func SetupValidationWithManager(mgr mcmanager.Manager, connManager ConnManager, edgeProxyPublicPath string, eventsMgr *events.Manager) error {
-	r := &ValidationReconciler{mgr: mgr, connManager: connManager, edgeProxyPublicPath: edgeProxyPublicPath, events: eventsMgr}
+// rather than waiting up to validationResyncInterval for the next resync. It
+// runs on every replica: the one holding the edge's tunnel probes the Service.
+func SetupValidationWithManager(mgr mcmanager.Manager, connManager ConnManager, edgeProxyPublicPath string, eventsMgr *events.Manager, instance string) error {
+	r := &ValidationReconciler{mgr: mgr, connManager: connManager, edgeProxyPublicPath: edgeProxyPublicPath, events: eventsMgr, instance: instance}
 	return mcbuilder.ControllerManagedBy(mgr).
 		Named("service-validation").
 		For(&edgesv1alpha1.Service{}).
 		Watches(&corev1.Secret{}, mchandler.EnqueueRequestsFromMapFunc(r.mapSecretToServices)).
+		WithOptions(mccontroller.Options{NeedLeaderElection: ptr.To(false)}).
 		Complete(r)
 }
 
@@ -189,6 +195,9 @@ func (r *ValidationReconciler) Reconcile(ctx context.Context, req mcreconcile.Re
 	key := connKey(connResource(es), string(req.ClusterName), es.Spec.EdgeRef.Name)
 	dialer, ok := r.connManager.Load(key)
 	if !ok {
+		if edgeHeldByPeer(ctx, c, es, r.instance) {
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
 		setCondition(&es.Status.Conditions, "Ready", metav1.ConditionFalse, "EdgeDisconnected", "no live tunnel to the edge")
 		setNotProbed(es, "the edge is disconnected, so the service was never reached")
 		return r.commit(ctx, c, orig, es, 30*time.Second)
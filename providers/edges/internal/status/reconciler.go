@@ -75,7 +75,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req mcreconcile.Request) (ct
 		return ctrl.Result{}, fmt.Errorf("listing placements: %w", err)
 	}
 
+	// Conditions are set by other controllers (permission claims).
+	conditions := vw.Status.Conditions
 	vw.Status = AggregateStatus(placementList.Items)
+	vw.Status.Conditions = conditions
 	logger.V(4).Info("Updating Workload status", "readyReplicas", vw.Status.ReadyReplicas, "phase", vw.Status.Phase)
 	if err := c.Status().Update(ctx, &vw); err != nil {
 		return ctrl.Result{}, fmt.Errorf("updating Workload status: %w", err)
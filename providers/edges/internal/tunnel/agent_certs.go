@@ -0,0 +1,509 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	edgeapi "github.com/faroshq/provider-edges/internal/edgeapi"
+)
+
+// Certificate join. An agent generates a key pair, POSTs a certificate
+// signing request to the certificate subresource, authenticated with its
+// join token, and the hub signs it with the agent CA: at once, or after an
+// operator approves it when CSRApproval is manual. The agent then opens its
+// tunnel with a short-lived assertion signed by that key instead of a bearer
+// token, and requests a new certificate, authenticated by the current one,
+// before it expires.
+//
+// The assertion is
+//
+//	kedge-cert.<certificate>.<claims>.<signature>
+//
+// with the DER certificate, the JSON claims {"iat", "exp"} (Unix seconds) and
+// the signature over everything before the last dot, each base64url-encoded
+// without padding. ECDSA signatures are ASN.1 over the SHA-256 digest.
+const (
+	// AgentCertTokenPrefix starts an agent certificate assertion.
+	AgentCertTokenPrefix = "kedge-cert."
+	// AgentCertURIScheme is the scheme of the URI SAN naming the edge an
+	// agent certificate was issued to: kedge-edge:///{cluster}/{resource}/{name}.
+	// The cluster is part of the path, as kcp workspace paths hold colons.
+	AgentCertURIScheme = "kedge-edge"
+
+	// DefaultAgentCertValidity is how long issued agent certificates last.
+	DefaultAgentCertValidity = 30 * 24 * time.Hour
+	// maxAssertionLifetime bounds exp-iat of an assertion; clockLeeway is the
+	// skew tolerated on iat.
+	maxAssertionLifetime = 10 * time.Minute
+	clockLeeway          = time.Minute
+	// maxCSRBytes bounds a certificate request body.
+	maxCSRBytes = 16 << 10
+)
+
+// CSRApproval says how an edge's first certificate request, authenticated by
+// its join token or ServiceAccount token, is approved. Renewals authenticated
+// by a current certificate, and requests with a static token, are always
+// signed at once.
+type CSRApproval string
+
+const (
+	// CSRApprovalAuto signs every authenticated request.
+	CSRApprovalAuto CSRApproval = "auto"
+	// CSRApprovalManual signs a first request only once the edge's
+	// AnnotationApprovedCertificateRequest names the request's public key.
+	CSRApprovalManual CSRApproval = "manual"
+)
+
+// AgentCA signs per-edge agent client certificates.
+type AgentCA struct {
+	cert     *x509.Certificate
+	certPEM  []byte
+	key      crypto.Signer
+	pool     *x509.CertPool
+	validity time.Duration
+	approval CSRApproval
+}
+
+// LoadAgentCA reads the agent CA certificate and its private key (PKCS#8,
+// PKCS#1 or SEC 1 PEM). A zero validity applies DefaultAgentCertValidity and
+// an empty approval CSRApprovalAuto.
+func LoadAgentCA(certFile, keyFile string, validity time.Duration, approval CSRApproval) (*AgentCA, error) {
+	if approval == "" {
+		approval = CSRApprovalAuto
+	}
+	if approval != CSRApprovalAuto && approval != CSRApprovalManual {
+		return nil, fmt.Errorf("unknown certificate approval %q (want %q or %q)", approval, CSRApprovalAuto, CSRApprovalManual)
+	}
+	if validity == 0 {
+		validity = DefaultAgentCertValidity
+	}
+	if validity < time.Hour {
+		return nil, fmt.Errorf("agent certificate validity %s is below one hour", validity)
+	}
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("%s: no PEM certificate", certFile)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", certFile, err)
+	}
+	if !cert.IsCA {
+		return nil, fmt.Errorf("%s is not a CA certificate", certFile)
+	}
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	key, err := parsePrivateKey(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", keyFile, err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return &AgentCA{
+		cert:     cert,
+		certPEM:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}),
+		key:      key,
+		pool:     pool,
+		validity: validity,
+		approval: approval,
+	}, nil
+}
+
+func parsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM private key")
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, errors.New("unsupported private key encoding")
+}
+
+// agentCertURI names the edge an agent certificate is issued to.
+func agentCertURI(cluster, resource, name string) *url.URL {
+	return &url.URL{Scheme: AgentCertURIScheme, Path: "/" + cluster + "/" + resource + "/" + name}
+}
+
+// publicKeyFingerprint is the hex SHA-256 of a DER SubjectPublicKeyInfo.
+func publicKeyFingerprint(spki []byte) string {
+	sum := sha256.Sum256(spki)
+	return hex.EncodeToString(sum[:])
+}
+
+// sign issues a client certificate for the edge to the key in csr.
+func (ca *AgentCA) sign(csr *x509.CertificateRequest, cluster, resource, name string, now time.Time) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	notAfter := now.Add(ca.validity)
+	if notAfter.After(ca.cert.NotAfter) {
+		notAfter = ca.cert.NotAfter
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "kedge-edge:" + name, Organization: []string{cluster}},
+		URIs:         []*url.URL{agentCertURI(cluster, resource, name)},
+		NotBefore:    now.Add(-5 * time.Minute),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, csr.PublicKey, ca.key)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}
+
+// agentCertClaims are the claims of an agent certificate assertion.
+type agentCertClaims struct {
+	IssuedAt  int64 `json:"iat"`
+	ExpiresAt int64 `json:"exp"`
+}
+
+// verifyAssertion checks an agent certificate assertion and returns its
+// certificate, which must chain to the CA and name the given edge.
+func (ca *AgentCA) verifyAssertion(token, cluster, resource, name string, now time.Time) (*x509.Certificate, error) {
+	parts := strings.Split(strings.TrimPrefix(token, AgentCertTokenPrefix), ".")
+	if !strings.HasPrefix(token, AgentCertTokenPrefix) || len(parts) != 3 {
+		return nil, errors.New("malformed certificate assertion")
+	}
+	var raw [3][]byte
+	for i, part := range parts {
+		b, err := base64.RawURLEncoding.DecodeString(part)
+		if err != nil {
+			return nil, fmt.Errorf("malformed certificate assertion: %w", err)
+		}
+		raw[i] = b
+	}
+	cert, err := x509.ParseCertificate(raw[0])
+	if err != nil {
+		return nil, err
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:       ca.pool,
+		CurrentTime: now,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		return nil, err
+	}
+	want := agentCertURI(cluster, resource, name).String()
+	if len(cert.URIs) != 1 || cert.URIs[0].String() != want {
+		return nil, fmt.Errorf("certificate was not issued to %s", want)
+	}
+
+	signed := token[:strings.LastIndex(token, ".")]
+	digest := sha256.Sum256([]byte(signed))
+	switch key := cert.PublicKey.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest[:], raw[2]) {
+			return nil, errors.New("invalid assertion signature")
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, []byte(signed), raw[2]) {
+			return nil, errors.New("invalid assertion signature")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported certificate key type %T", key)
+	}
+
+	var claims agentCertClaims
+	if err := json.Unmarshal(raw[1], &claims); err != nil {
+		return nil, fmt.Errorf("malformed assertion claims: %w", err)
+	}
+	iat, exp := time.Unix(claims.IssuedAt, 0), time.Unix(claims.ExpiresAt, 0)
+	switch {
+	case !now.Before(exp):
+		return nil, errors.New("assertion expired")
+	case iat.After(now.Add(clockLeeway)):
+		return nil, errors.New("assertion issued in the future")
+	case exp.Sub(iat) > maxAssertionLifetime:
+		return nil, fmt.Errorf("assertion lifetime exceeds %s", maxAssertionLifetime)
+	}
+	return cert, nil
+}
+
+// checkCSR parses a PEM certificate request and checks its signature and key.
+func checkCSR(data []byte) (*x509.CertificateRequest, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, errors.New("no PEM certificate request")
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, err
+	}
+	switch key := csr.PublicKey.(type) {
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() {
+			return nil, errors.New("ECDSA keys must use P-256")
+		}
+	case ed25519.PublicKey:
+	default:
+		return nil, fmt.Errorf("unsupported key type %T (want ECDSA P-256 or Ed25519)", key)
+	}
+	return csr, nil
+}
+
+// agentCertificateRequest and agentCertificateResponse are the bodies of the
+// certificate subresource.
+type agentCertificateRequest struct {
+	// CSR is the PEM certificate signing request.
+	CSR string `json:"csr"`
+}
+
+type agentCertificateResponse struct {
+	// Status is "Issued" or "Pending".
+	Status string `json:"status"`
+	// Certificate and CA are PEM, set when issued.
+	Certificate string `json:"certificate,omitempty"`
+	CA          string `json:"ca,omitempty"`
+	// Message explains a pending request.
+	Message string `json:"message,omitempty"`
+}
+
+// agentCertAuth is how a certificate request was authenticated.
+type agentCertAuth int
+
+const (
+	agentCertAuthToken       agentCertAuth = iota // join or ServiceAccount token
+	agentCertAuthCertificate                      // renewal
+	agentCertAuthStatic
+)
+
+// serveAgentCertificate handles POST .../{resource}/{name}/certificate: it
+// signs the agent's certificate request, or records it for approval and
+// answers 202 until the request is approved.
+func (p *Server) serveAgentCertificate(w http.ResponseWriter, r *http.Request, cluster, resource, name string) {
+	if p.agentCA == nil {
+		http.Error(w, "certificate join is not enabled on this hub", http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	token := extractBearerToken(r)
+	if token == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	gvr, _, _ := p.gvrForResource(resource)
+	ctx := r.Context()
+	now := time.Now()
+
+	var auth agentCertAuth
+	_, isStaticToken := p.staticTokens[token]
+	switch {
+	case isStaticToken:
+		auth = agentCertAuthStatic
+	case strings.HasPrefix(token, AgentCertTokenPrefix):
+		if _, err := p.authorizeByCertificate(ctx, gvr, token, cluster, resource, name, now); err != nil {
+			p.logger.Info("Rejected agent certificate request: invalid certificate assertion",
+				"cluster", cluster, "name", name, "err", err)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		auth = agentCertAuthCertificate
+	default:
+		if _, ok := parseServiceAccountToken(token); ok {
+			if err := p.authorizeByIssuedToken(ctx, gvr, cluster, name, token); err != nil {
+				p.logger.Info("Rejected agent certificate request: SA token failed delegated authorization",
+					"cluster", cluster, "name", name, "err", err)
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+		} else {
+			if p.kcpConfig == nil {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			if err := p.authorizeByJoinToken(ctx, gvr, token, cluster, name); err != nil {
+				p.logger.Info("Rejected agent certificate request: invalid join token",
+					"cluster", cluster, "name", name, "err", err)
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+		auth = agentCertAuthToken
+	}
+
+	var req agentCertificateRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxCSRBytes)).Decode(&req); err != nil {
+		http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	csr, err := checkCSR([]byte(req.CSR))
+	if err != nil {
+		http.Error(w, "invalid certificate request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	dynClient, err := p.tenantDynamicClient(ctx, cluster)
+	if err != nil {
+		p.logger.Error(err, "agent certificate request: failed to reach tenant", "cluster", cluster, "name", name)
+		http.Error(w, "failed to reach the edge", http.StatusBadGateway)
+		return
+	}
+	fingerprint := publicKeyFingerprint(csr.RawSubjectPublicKeyInfo)
+	if auth == agentCertAuthToken && p.agentCA.approval == CSRApprovalManual {
+		edge, err := dynClient.Resource(gvr).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			p.logger.Error(err, "agent certificate request: failed to get edge", "cluster", cluster, "name", name)
+			http.Error(w, "failed to read the edge", http.StatusBadGateway)
+			return
+		}
+		if edge.GetAnnotations()[edgeapi.AnnotationApprovedCertificateRequest] != fingerprint {
+			if err := patchAgentCertificate(ctx, dynClient, gvr, name, map[string]any{"pendingRequest": fingerprint}); err != nil {
+				p.logger.Error(err, "agent certificate request: failed to record pending request", "cluster", cluster, "name", name)
+				http.Error(w, "failed to record the request", http.StatusBadGateway)
+				return
+			}
+			p.logger.Info("Agent certificate request awaits approval", "cluster", cluster, "name", name, "fingerprint", fingerprint)
+			writeAgentCertificateResponse(w, http.StatusAccepted, agentCertificateResponse{
+				Status:  "Pending",
+				Message: fmt.Sprintf("certificate request %s awaits approval: kedge edge approve-certificate %s", fingerprint[:16], name),
+			})
+			return
+		}
+	}
+
+	cert, err := p.agentCA.sign(csr, cluster, resource, name, now)
+	if err != nil {
+		p.logger.Error(err, "agent certificate request: signing failed", "cluster", cluster, "name", name)
+		http.Error(w, "signing failed", http.StatusInternalServerError)
+		return
+	}
+	// Recording the serial revokes the edge's previous certificate.
+	if err := patchAgentCertificate(ctx, dynClient, gvr, name, map[string]any{
+		"serialNumber":   cert.SerialNumber.Text(16),
+		"notAfter":       cert.NotAfter.UTC().Format(time.RFC3339),
+		"pendingRequest": nil,
+	}); err != nil {
+		p.logger.Error(err, "agent certificate request: failed to record certificate", "cluster", cluster, "name", name)
+		http.Error(w, "failed to record the certificate", http.StatusBadGateway)
+		return
+	}
+	p.logger.Info("Issued agent certificate", "cluster", cluster, "name", name,
+		"serial", cert.SerialNumber.Text(16), "notAfter", cert.NotAfter)
+	writeAgentCertificateResponse(w, http.StatusOK, agentCertificateResponse{
+		Status:      "Issued",
+		Certificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})),
+		CA:          string(p.agentCA.certPEM),
+	})
+}
+
+func writeAgentCertificateResponse(w http.ResponseWriter, code int, resp agentCertificateResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// authorizeByCertificate verifies an agent certificate assertion for the
+// edge and checks that its certificate is the one last issued to it. It
+// reports whether the edge still has a join token, i.e. the agent has not
+// received its hub kubeconfig yet.
+func (p *Server) authorizeByCertificate(ctx context.Context, gvr schema.GroupVersionResource, token, cluster, resource, name string, now time.Time) (registering bool, err error) {
+	if p.agentCA == nil {
+		return false, errors.New("certificate join is not enabled")
+	}
+	cert, err := p.agentCA.verifyAssertion(token, cluster, resource, name, now)
+	if err != nil {
+		return false, err
+	}
+	dynClient, err := p.tenantDynamicClient(ctx, cluster)
+	if err != nil {
+		return false, err
+	}
+	edge, err := dynClient.Resource(gvr).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return false, fmt.Errorf("getting %s %s/%s: %w", gvr.Resource, cluster, name, err)
+	}
+	serial, _, _ := unstructured.NestedString(edge.Object, "status", "certificate", "serialNumber")
+	if serial != cert.SerialNumber.Text(16) {
+		return false, fmt.Errorf("certificate %s has been replaced or revoked", cert.SerialNumber.Text(16))
+	}
+	joinToken, _, _ := unstructured.NestedString(edge.Object, "status", "joinToken")
+	return joinToken != "", nil
+}
+
+// tenantDynamicClient returns a dynamic client for a tenant cluster.
+func (p *Server) tenantDynamicClient(ctx context.Context, cluster string) (dynamic.Interface, error) {
+	cfg, err := p.tenantConfigFor(ctx, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("resolving tenant config: %w", err)
+	}
+	return dynamic.NewForConfig(cfg)
+}
+
+// patchAgentCertificate merges fields into the edge's status.certificate.
+func patchAgentCertificate(ctx context.Context, dynClient dynamic.Interface, gvr schema.GroupVersionResource, name string, fields map[string]any) error {
+	patch, err := json.Marshal(map[string]any{"status": map[string]any{"certificate": fields}})
+	if err != nil {
+		return err
+	}
+	_, err = dynClient.Resource(gvr).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}, "status")
+	return err
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestAgentCA writes a self-signed CA to disk and loads it.
+func newTestAgentCA(t *testing.T) *AgentCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "kedge agent CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "kedge agent CA"}}, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key")
+	_ = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	_ = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600)
+	ca, err := LoadAgentCA(certFile, keyFile, 0, "")
+	if err != nil {
+		t.Fatalf("LoadAgentCA: %v", err)
+	}
+	return ca
+}
+
+func newTestCSR(t *testing.T, key crypto.Signer) []byte {
+	t.Helper()
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: "store-1"},
+	}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})
+}
+
+// testAssertion builds an assertion the way the agent does.
+func testAssertion(t *testing.T, cert *x509.Certificate, key *ecdsa.PrivateKey, iat, exp time.Time) string {
+	t.Helper()
+	claims, _ := json.Marshal(agentCertClaims{IssuedAt: iat.Unix(), ExpiresAt: exp.Unix()})
+	signed := AgentCertTokenPrefix + base64.RawURLEncoding.EncodeToString(cert.Raw) + "." +
+		base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestAgentCertificateAssertion(t *testing.T) {
+	ca := newTestAgentCA(t)
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	csr, err := checkCSR(newTestCSR(t, key))
+	if err != nil {
+		t.Fatalf("checkCSR: %v", err)
+	}
+	now := time.Now()
+	cert, err := ca.sign(csr, "root:org", "linuxservers", "store-1", now)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if got := cert.NotAfter.Sub(now).Round(time.Hour); got != DefaultAgentCertValidity {
+		t.Errorf("validity = %s, want %s", got, DefaultAgentCertValidity)
+	}
+
+	valid := testAssertion(t, cert, key, now, now.Add(5*time.Minute))
+	if got, err := ca.verifyAssertion(valid, "root:org", "linuxservers", "store-1", now); err != nil || got.SerialNumber.Cmp(cert.SerialNumber) != 0 {
+		t.Fatalf("verifyAssertion = %v, %v", got, err)
+	}
+
+	otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tampered := valid[:strings.LastIndex(valid, ".")] + "." +
+		strings.Split(testAssertion(t, cert, otherKey, now, now.Add(5*time.Minute)), ".")[3]
+	for name, tc := range map[string]struct {
+		token, cluster, name string
+		now                  time.Time
+	}{
+		"other edge":        {valid, "root:org", "store-2", now},
+		"other cluster":     {valid, "root:other", "store-1", now},
+		"expired":           {valid, "root:org", "store-1", now.Add(6 * time.Minute)},
+		"wrong signature":   {tampered, "root:org", "store-1", now},
+		"long lifetime":     {testAssertion(t, cert, key, now, now.Add(time.Hour)), "root:org", "store-1", now},
+		"issued in future":  {testAssertion(t, cert, key, now.Add(5*time.Minute), now.Add(10*time.Minute)), "root:org", "store-1", now},
+		"certificate after": {testAssertion(t, cert, key, now.Add(31*24*time.Hour), now.Add(31*24*time.Hour+time.Minute)), "root:org", "store-1", now.Add(31 * 24 * time.Hour)},
+		"malformed":         {AgentCertTokenPrefix + "x.y", "root:org", "store-1", now},
+	} {
+		if _, err := ca.verifyAssertion(tc.token, tc.cluster, "linuxservers", tc.name, tc.now); err == nil {
+			t.Errorf("%s: assertion accepted", name)
+		}
+	}
+}
+
+func TestCheckCSR(t *testing.T) {
+	p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	for name, data := range map[string][]byte{
+		"P-384":   newTestCSR(t, p384),
+		"RSA":     newTestCSR(t, rsaKey),
+		"not PEM": []byte("csr"),
+	} {
+		if _, err := checkCSR(data); err == nil {
+			t.Errorf("%s: CSR accepted", name)
+		}
+	}
+}
+
+func TestLoadAgentCARejectsBadSettings(t *testing.T) {
+	if _, err := LoadAgentCA("ca.crt", "ca.key", 0, "sometimes"); err == nil {
+		t.Error("unknown approval accepted")
+	}
+	if _, err := LoadAgentCA("ca.crt", "ca.key", time.Minute, CSRApprovalAuto); err == nil {
+		t.Error("validity below one hour accepted")
+	}
+}
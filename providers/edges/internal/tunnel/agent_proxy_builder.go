@@ -19,11 +19,14 @@ package tunnel
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/websocket"
 	gossh "golang.org/x/crypto/ssh"
@@ -66,14 +69,59 @@ func (p *Server) sshExec(ctx context.Context, wsConn *websocket.Conn, sshClient
 		}
 	}()
 
-	// Run the remote command (blocks until it exits).
-	if err := sshSession.Run(remoteCmd); err != nil {
-		logger.V(4).Info("SSH exec command finished", "cmd", remoteCmd, "err", err)
+	// Run the remote command (blocks until it exits). Closing the client
+	// when ctx ends (a drain deadline) unblocks it early.
+	runDone := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			sshClient.Close() //nolint:errcheck
+		case <-runDone:
+		}
+	}()
+	runErr := sshSession.Run(remoteCmd)
+	close(runDone)
+	if runErr != nil {
+		logger.V(4).Info("SSH exec command finished", "cmd", remoteCmd, "err", runErr)
 	}
 
 	// Close the write end of the pipe so the forwarder goroutine sees EOF.
 	pw.Close() //nolint:errcheck
 	<-fwdDone  // wait for all output to be forwarded before closing the WebSocket
+
+	// Tell the caller how the command ended so the CLI can fail on a
+	// non-zero exit (e.g. sudo asking for a password).
+	_ = wsConn.WriteControl(websocket.CloseMessage, sshExecCloseMessage(runErr), time.Now().Add(time.Second))
+}
+
+// sshExitStatusPrefix starts the close reason carrying an exec command's exit
+// status; the kedge CLI parses it (runSSHCommandStream).
+const sshExitStatusPrefix = "exit-status "
+
+// sshExecCloseMessage encodes the outcome of an exec command as a WebSocket
+// close frame: a normal closure with "exit-status N" when the command exited,
+// a normal closure without a status when the session ended before the command
+// reported one (a reboot), and an internal error carrying the message when
+// the command could not be run.
+func sshExecCloseMessage(runErr error) []byte {
+	var exitErr *gossh.ExitError
+	var missingErr *gossh.ExitMissingError
+	switch {
+	case runErr == nil:
+		return websocket.FormatCloseMessage(websocket.CloseNormalClosure, sshExitStatusPrefix+"0")
+	case errors.As(runErr, &exitErr):
+		return websocket.FormatCloseMessage(websocket.CloseNormalClosure, sshExitStatusPrefix+strconv.Itoa(exitErr.ExitStatus()))
+	case errors.As(runErr, &missingErr):
+		return websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	default:
+		// A control frame payload is limited to 125 bytes, two of which
+		// carry the code.
+		msg := runErr.Error()
+		if len(msg) > 123 {
+			msg = msg[:123]
+		}
+		return websocket.FormatCloseMessage(websocket.CloseInternalServerErr, msg)
+	}
 }
 
 // openAgentSSHTunnel sends an HTTP upgrade request to the agent's /ssh endpoint
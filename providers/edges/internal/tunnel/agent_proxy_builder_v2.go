@@ -61,6 +61,10 @@ var secretGVR = schema.GroupVersionResource{Group: "", Version: "v1", Resource:
 // pick-up connections initiated by the agent side.
 func (p *Server) buildEdgeAgentProxyHandler() http.Handler {
 	upgrader := websocket.Upgrader{
+		// A non-zero read buffer makes gorilla read through the hijacked
+		// connection, which quotaResponseWriter wraps to enforce the tunnel
+		// quota, instead of the server's bufio.Reader.
+		ReadBufferSize: 4096,
 		CheckOrigin: func(r *http.Request) bool {
 			return utilhttp.CheckSameOrAllowedOrigin(r, []url.URL{})
 		},
@@ -71,9 +75,10 @@ func (p *Server) buildEdgeAgentProxyHandler() http.Handler {
 	// /proxy — revdial pick-up endpoint.
 	// When the hub dials the agent (Dialer.Dial), it sends a "conn-ready"
 	// message to the agent telling it to open a new WebSocket to this path.
-	// The path passed to revdial.NewDialer below must match the absolute URL
-	// path where this handler is mounted.
-	mux.Handle("/proxy", revdial.ConnHandler(upgrader))
+	// The path passed to revdial.NewDialerWithKeepalive below must match the absolute URL
+	// path where this handler is mounted. Each pickup is charged to the quota
+	// of the tunnel named in its path (pickupQuotaHandler).
+	mux.Handle("/proxy", p.pickupQuotaHandler(revdial.ConnHandler(upgrader)))
 
 	// / — initial agent connection handler.
 	// Path (after mount-prefix stripping):
@@ -89,6 +94,17 @@ func (p *Server) buildEdgeAgentProxyHandler() http.Handler {
 			p.buildMCPHandler(cluster, resource, name).ServeHTTP(w, r)
 			return
 		}
+		// Certificate requests authenticate like tunnels but are plain
+		// POSTs (agent_certs.go).
+		if strings.HasSuffix(strings.TrimRight(r.URL.Path, "/"), "/certificate") {
+			cluster, resource, name, ok := p.parseEdgeSubresourcePath(r.URL.Path, "certificate")
+			if !ok {
+				http.Error(w, "invalid path: expected /{cluster}/apis/"+p.group+"/"+p.version+"/{resource}/{name}/certificate", http.StatusBadRequest)
+				return
+			}
+			p.serveAgentCertificate(w, r, cluster, resource, name)
+			return
+		}
 
 		// 1. Authenticate: require a valid bearer token.
 		token := extractBearerToken(r)
@@ -109,6 +125,7 @@ func (p *Server) buildEdgeAgentProxyHandler() http.Handler {
 		// 3. Authentication: static tokens bypass JWT SA requirement.
 		//    SA tokens go through kcp delegated authorization.
 		//    Bootstrap join tokens are accepted if they match edge.Status.JoinToken.
+		//    Certificate assertions are verified against the agent CA.
 		_, isStaticToken := p.staticTokens[token]
 		// authenticatedByJoinToken tracks whether the agent was authenticated via a
 		// bootstrap join token. When true, the hub echoes the token back in the
@@ -116,7 +133,25 @@ func (p *Server) buildEdgeAgentProxyHandler() http.Handler {
 		// as its durable credential (token-exchange flow).
 		authenticatedByJoinToken := false
 		if !isStaticToken {
-			if _, ok := parseServiceAccountToken(token); !ok {
+			isCertificate := strings.HasPrefix(token, AgentCertTokenPrefix)
+			if !isCertificate && p.requireAgentCertificate {
+				p.logger.Info("Rejected edge agent tunnel: a client certificate is required",
+					"cluster", cluster, "name", name)
+				http.Error(w, "Unauthorized: this hub requires agent certificates (--certificate-join)", http.StatusUnauthorized)
+				return
+			}
+			if isCertificate {
+				registering, err := p.authorizeByCertificate(r.Context(), gvr, token, cluster, resource, name, time.Now())
+				if err != nil {
+					p.logger.Info("Rejected edge agent tunnel: invalid certificate assertion",
+						"cluster", cluster, "name", name, "err", err)
+					http.Error(w, "Unauthorized", http.StatusUnauthorized)
+					return
+				}
+				// An agent that joined by certificate still needs its hub
+				// kubeconfig, delivered as in the join-token flow.
+				authenticatedByJoinToken = registering
+			} else if _, ok := parseServiceAccountToken(token); !ok {
 				// Not a SA token — check if it's a valid bootstrap join token for this edge.
 				if p.kcpConfig == nil {
 					p.logger.Info("Rejected edge agent tunnel: invalid or missing SA token (no kcp configured)",
@@ -147,7 +182,17 @@ func (p *Server) buildEdgeAgentProxyHandler() http.Handler {
 			}
 		}
 
-		// 4. Upgrade to WebSocket.
+		// 4. Check the agent's version against the hub's. An agent refused
+		// here still has its version recorded, so the edge shows why it does
+		// not connect.
+		agentVersion := r.Header.Get(AgentVersionHeader)
+		if reason := p.checkAgentVersion(r.Context(), cluster, name, agentVersion); reason != "" {
+			go p.recordAgentVersion(context.Background(), gvr, cluster, name, agentVersion)
+			http.Error(w, "unsupported agent version: "+reason, http.StatusUpgradeRequired)
+			return
+		}
+
+		// 5. Upgrade to WebSocket.
 		// When the agent authenticated via a bootstrap join token, build a minimal
 		// kubeconfig and include it in the upgrade response so the agent can save it
 		// as its durable credential and reconnect without the join token on restart.
@@ -161,21 +206,26 @@ func (p *Server) buildEdgeAgentProxyHandler() http.Handler {
 				kubeconfigDelivered = true
 			}
 		}
-		wsConn, err := upgrader.Upgrade(w, r, upgradeHeaders)
+		// The tunnel's quota covers the control connection upgraded here and
+		// every pickup connection it requests.
+		limiter := newTunnelLimiter(p.quota)
+		limiterID := p.tunnelLimits.add(limiter)
+		defer p.tunnelLimits.remove(limiterID)
+		wsConn, err := upgrader.Upgrade(&quotaResponseWriter{ResponseWriter: w, limiter: limiter}, r, upgradeHeaders)
 		if err != nil {
 			p.logger.Error(err, "failed to upgrade WebSocket connection",
 				"cluster", cluster, "name", name)
 			return
 		}
 
-		// 5. Register the revdial tunnel.
+		// 6. Register the revdial tunnel.
 		// The pick-up path must match the absolute path at which the /proxy
 		// endpoint is reachable (i.e. the mount point + /proxy).
 		key := edgeConnKey(resource, cluster, name)
 		p.logger.Info("Edge agent connecting", "key", key)
 
-		conn := wsconnadapter.New(wsConn)
-		dialer := revdial.NewDialer(conn, p.agentPickupPath)
+		conn := &messageConn{Conn: wsconnadapter.New(wsConn), limiter: limiter}
+		dialer := revdial.NewDialerWithKeepalive(conn, p.pickupPath(limiterID), p.keepalive)
 		p.edgeConnManager.Store(key, dialer)
 		p.logger.Info("Edge agent tunnel established", "key", key)
 
@@ -195,11 +245,11 @@ func (p *Server) buildEdgeAgentProxyHandler() http.Handler {
 		// and needs the join token to remain valid for the next reconnect attempt.
 		clearJoinToken := !authenticatedByJoinToken || kubeconfigDelivered
 		sshCreds := extractSSHCredsFromHeaders(r)
-		go p.markEdgeConnected(context.Background(), gvr, cluster, name, sshCreds, clearJoinToken)
+		go p.markEdgeConnected(context.Background(), gvr, cluster, name, agentVersion, sshCreds, clearJoinToken)
 
 		// Stamp status.lastHeartbeatTime from the dialer's LastPong while the
 		// tunnel is alive. revdial's keep-alive/pong loop already detects dead
-		// tunnels within ~60s; LastPong gives us a positive liveness signal
+		// tunnels within the keepalive timeout; LastPong gives us a positive liveness signal
 		// that we can surface on the Edge resource so the LifecycleReconciler
 		// (and CLI/UI) can spot a stalled connection.
 		heartbeatCtx, cancelHeartbeat := context.WithCancel(context.Background())
@@ -210,7 +260,11 @@ func (p *Server) buildEdgeAgentProxyHandler() http.Handler {
 		<-dialer.Done()
 		cancelHeartbeat()
 		p.edgeConnManager.Delete(key)
-		p.logger.Info("Edge agent tunnel closed", "key", key)
+		if violation := limiter.Violation(); violation != "" {
+			p.logger.Info("Edge agent tunnel disconnected for exceeding its quota", "key", key, "violation", violation)
+		} else {
+			p.logger.Info("Edge agent tunnel closed", "key", key)
+		}
 
 		// Proactively mark the Edge as Disconnected in the hub.  Agents may die
 		// without sending a clean disconnect heartbeat (e.g. SIGKILL), so the
@@ -251,6 +305,13 @@ func (p *Server) parseEdgeAgentPath(path string) (cluster, resource, name string
 // parseEdgeMCPPath extracts {cluster} and {name} for per-edge MCP requests.
 // Format: /{cluster}/apis/{group}/{version}/{resource}/{name}/mcp
 func (p *Server) parseEdgeMCPPath(path string) (cluster, resource, name string, ok bool) {
+	return p.parseEdgeSubresourcePath(path, "mcp")
+}
+
+// parseEdgeSubresourcePath extracts {cluster}, {resource} and {name} for a
+// per-edge subresource.
+// Format: /{cluster}/apis/{group}/{version}/{resource}/{name}/{subresource}
+func (p *Server) parseEdgeSubresourcePath(path, subresource string) (cluster, resource, name string, ok bool) {
 	parts := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 8)
 	if len(parts) < 7 {
 		return "", "", "", false
@@ -259,7 +320,7 @@ func (p *Server) parseEdgeMCPPath(path string) (cluster, resource, name string,
 		return "", "", "", false
 	}
 	if parts[1] != "apis" || parts[2] != p.group || parts[3] != p.version ||
-		parts[6] != "mcp" {
+		strings.TrimRight(parts[6], "/") != subresource {
 		return "", "", "", false
 	}
 	return parts[0], parts[4], parts[5], true
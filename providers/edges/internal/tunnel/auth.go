@@ -114,6 +114,24 @@ func extractBearerToken(r *http.Request) string {
 // old approach), which the production hub proxy rejects with an opaque 404 —
 // the failure kcp#4279 documents. It goes through the VW instead.
 func authorize(ctx context.Context, tenantCfg, kcpConfig *rest.Config, token, clusterName, verb, group, resource, name string) error {
+	caller, err := reviewToken(ctx, tenantCfg, kcpConfig, token, clusterName)
+	if err != nil {
+		return err
+	}
+	return reviewAccess(ctx, tenantCfg, caller, verb, group, resource, name)
+}
+
+// Requester is an authenticated caller identity, as resolved by reviewToken
+// and authorized by reviewAccess.
+type Requester struct {
+	User   string   `json:"user"`
+	Groups []string `json:"groups,omitempty"`
+}
+
+// reviewToken authenticates token in the workspace that issued it and returns
+// the identity to authorize in the consumer workspace clusterName — step 1 of
+// authorize.
+func reviewToken(ctx context.Context, tenantCfg, kcpConfig *rest.Config, token, clusterName string) (Requester, error) {
 	saClaims, isForeignSA := parseServiceAccountToken(token)
 	if isForeignSA && saClaims.ClusterName == clusterName {
 		// SA minted in the consumer workspace (agent credentials): it
@@ -121,7 +139,6 @@ func authorize(ctx context.Context, tenantCfg, kcpConfig *rest.Config, token, cl
 		isForeignSA = false
 	}
 
-	// 1. Authenticate the token in its issuing workspace.
 	var trCfg *rest.Config
 	if isForeignSA {
 		trCfg = rest.CopyConfig(kcpConfig)
@@ -131,44 +148,45 @@ func authorize(ctx context.Context, tenantCfg, kcpConfig *rest.Config, token, cl
 	}
 	trClient, err := kubernetes.NewForConfig(trCfg)
 	if err != nil {
-		return fmt.Errorf("creating token-review client: %w", err)
+		return Requester{}, fmt.Errorf("creating token-review client: %w", err)
 	}
 	tr, err := trClient.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
 		Spec: authenticationv1.TokenReviewSpec{Token: token},
 	}, metav1.CreateOptions{})
 	if err != nil {
-		return fmt.Errorf("token review: %w", err)
+		return Requester{}, fmt.Errorf("token review: %w", err)
 	}
 	if !tr.Status.Authenticated {
-		return fmt.Errorf("token not authenticated")
+		return Requester{}, fmt.Errorf("token not authenticated")
 	}
 
-	sarUser := tr.Status.User.Username
-	sarGroups := tr.Status.User.Groups
-	if isForeignSA {
-		qualified, ok := identity.QualifyServiceAccount(saClaims.ClusterName, tr.Status.User.Username)
-		if !ok {
-			// Claimed to be an SA token but the home cluster resolved it to a
-			// non-SA identity — refuse rather than authorize an identity we
-			// can't encode unambiguously.
-			return fmt.Errorf("token review: expected ServiceAccount identity, got %q", tr.Status.User.Username)
-		}
-		sarUser = qualified
-		// Drop groups: system:serviceaccounts et al. would match group-targeted
-		// bindings the tenant wrote for their OWN SAs.
-		sarGroups = nil
-	}
-
-	// 2. Authorize the resolved identity against the consumer workspace's RBAC,
-	// via the APIExport virtual workspace.
+	if !isForeignSA {
+		return Requester{User: tr.Status.User.Username, Groups: tr.Status.User.Groups}, nil
+	}
+	qualified, ok := identity.QualifyServiceAccount(saClaims.ClusterName, tr.Status.User.Username)
+	if !ok {
+		// Claimed to be an SA token but the home cluster resolved it to a
+		// non-SA identity — refuse rather than authorize an identity we
+		// can't encode unambiguously.
+		return Requester{}, fmt.Errorf("token review: expected ServiceAccount identity, got %q", tr.Status.User.Username)
+	}
+	// Drop groups: system:serviceaccounts et al. would match group-targeted
+	// bindings the tenant wrote for their OWN SAs.
+	return Requester{User: qualified}, nil
+}
+
+// reviewAccess authorizes caller for verb on the resource against the
+// consumer workspace's RBAC, via the APIExport virtual workspace tenantCfg
+// targets — step 2 of authorize.
+func reviewAccess(ctx context.Context, tenantCfg *rest.Config, caller Requester, verb, group, resource, name string) error {
 	sarClient, err := kubernetes.NewForConfig(tenantCfg)
 	if err != nil {
 		return fmt.Errorf("creating subject-access-review client: %w", err)
 	}
 	sar, err := sarClient.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
 		Spec: authorizationv1.SubjectAccessReviewSpec{
-			User:   sarUser,
-			Groups: sarGroups,
+			User:   caller.User,
+			Groups: caller.Groups,
 			ResourceAttributes: &authorizationv1.ResourceAttributes{
 				Verb:     verb,
 				Group:    group,
@@ -0,0 +1,238 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// reasonEdgeBusy is the problem reason for a request refused because its
+// edge already has as many requests in flight and queued as it may.
+const reasonEdgeBusy = "EdgeBusy"
+
+// Concurrency caps the proxied requests in flight to one edge, so hub-side
+// automation cannot overwhelm a low-powered edge cluster. Requests beyond
+// MaxInFlight wait in a queue that serves callers in turn, one request each,
+// so a single busy caller cannot starve the others. A request that finds the
+// queue full, or waits longer than QueueTimeout, is refused with 429.
+//
+// Long-running requests (watches, followed logs, exec, port-forward, SSH)
+// are not counted: they would hold a slot for their whole lifetime.
+type Concurrency struct {
+	// MaxInFlight is how many requests to one edge are served at once. Zero
+	// disables the limit.
+	MaxInFlight int
+	// MaxQueued is how many requests to one edge may wait for a slot.
+	MaxQueued int
+	// QueueTimeout is the longest a request waits for a slot.
+	QueueTimeout time.Duration
+}
+
+// DefaultConcurrency returns the limit applied when Config.Concurrency is
+// nil: well above what interactive use needs, low enough that a runaway
+// script queues instead of piling onto the edge's API server.
+func DefaultConcurrency() Concurrency {
+	return Concurrency{
+		MaxInFlight:  32,
+		MaxQueued:    128,
+		QueueTimeout: 15 * time.Second,
+	}
+}
+
+// Validate reports negative limits or a limit without a queue timeout.
+func (c Concurrency) Validate() error {
+	if c.MaxInFlight < 0 || c.MaxQueued < 0 || c.QueueTimeout < 0 {
+		return fmt.Errorf("edge concurrency limits must not be negative: %+v", c)
+	}
+	if c.MaxInFlight > 0 && c.MaxQueued > 0 && c.QueueTimeout == 0 {
+		return fmt.Errorf("edge concurrency: QueueTimeout must be set with MaxQueued")
+	}
+	return nil
+}
+
+// edgeLimiters holds the concurrency state of each edge with requests in
+// flight, keyed like edgeConnManager.
+type edgeLimiters struct {
+	limit Concurrency
+	mu    sync.Mutex
+	edges map[string]*edgeLimiter
+}
+
+// edgeLimiter is one edge's requests in flight and the callers waiting.
+type edgeLimiter struct {
+	inflight int
+	queued   int
+	// waiting holds each caller's queued requests, oldest first; turns lists
+	// the callers with queued requests in the order they are served.
+	waiting map[string][]*waiter
+	turns   []string
+}
+
+// waiter is a queued request. ready is closed when it is granted a slot.
+type waiter struct {
+	ready chan struct{}
+}
+
+func newEdgeLimiters(limit Concurrency) *edgeLimiters {
+	return &edgeLimiters{limit: limit, edges: make(map[string]*edgeLimiter)}
+}
+
+// acquire waits for a slot to serve a request from caller to the edge with
+// connection key key. It returns the function releasing the slot, or false
+// when the request must be refused.
+func (l *edgeLimiters) acquire(ctx context.Context, key, caller string) (func(), bool) {
+	if l.limit.MaxInFlight == 0 {
+		return func() {}, true
+	}
+	l.mu.Lock()
+	e := l.edges[key]
+	if e == nil {
+		e = &edgeLimiter{waiting: make(map[string][]*waiter)}
+		l.edges[key] = e
+	}
+	if e.inflight < l.limit.MaxInFlight {
+		e.inflight++
+		l.mu.Unlock()
+		return l.releaser(key), true
+	}
+	if e.queued >= l.limit.MaxQueued {
+		l.mu.Unlock()
+		return nil, false
+	}
+	w := &waiter{ready: make(chan struct{})}
+	if len(e.waiting[caller]) == 0 {
+		e.turns = append(e.turns, caller)
+	}
+	e.waiting[caller] = append(e.waiting[caller], w)
+	e.queued++
+	l.mu.Unlock()
+
+	timer := time.NewTimer(l.limit.QueueTimeout)
+	defer timer.Stop()
+	select {
+	case <-w.ready:
+		return l.releaser(key), true
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	select {
+	case <-w.ready:
+		// Granted while giving up: pass the slot on.
+		l.release(key)
+	default:
+		e.dequeue(caller, w)
+	}
+	return nil, false
+}
+
+// releaser returns the function releasing one slot of key, at most once.
+func (l *edgeLimiters) releaser(key string) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.release(key)
+		})
+	}
+}
+
+// release hands the slot to the next caller in turn, or frees it. Callers
+// hold l.mu.
+func (l *edgeLimiters) release(key string) {
+	e := l.edges[key]
+	if len(e.turns) == 0 {
+		e.inflight--
+		if e.inflight == 0 {
+			delete(l.edges, key)
+		}
+		return
+	}
+	caller := e.turns[0]
+	w := e.waiting[caller][0]
+	e.dequeue(caller, w)
+	if len(e.waiting[caller]) > 0 {
+		// The caller has more queued: back of the line.
+		e.turns = append(e.turns, caller)
+	}
+	close(w.ready)
+}
+
+// dequeue removes w from caller's queue and caller from turns, the caller
+// re-entering at the back when it still has requests queued.
+func (e *edgeLimiter) dequeue(caller string, w *waiter) {
+	q := e.waiting[caller]
+	for i := range q {
+		if q[i] == w {
+			q = append(q[:i], q[i+1:]...)
+			break
+		}
+	}
+	if len(q) == 0 {
+		delete(e.waiting, caller)
+	} else {
+		e.waiting[caller] = q
+	}
+	for i, c := range e.turns {
+		if c == caller {
+			e.turns = append(e.turns[:i], e.turns[i+1:]...)
+			break
+		}
+	}
+	e.queued--
+}
+
+// isLongRunning reports whether a proxied request stays open for as long as
+// the client wants, and so is not subject to the concurrency limit.
+func isLongRunning(r *http.Request, subresource string) bool {
+	if subresource == "ssh" || subresource == k8sTLSSubresource || isUpgradeRequest(r) {
+		return true
+	}
+	q := r.URL.Query()
+	if watch, _ := strconv.ParseBool(q.Get("watch")); watch {
+		return true
+	}
+	if follow, _ := strconv.ParseBool(q.Get("follow")); follow {
+		return true
+	}
+	return strings.Contains(r.URL.Path, "/watch/")
+}
+
+// callerKey identifies the caller of a proxied request for fair queuing,
+// without keeping its token.
+func callerKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:8])
+}
+
+// refuseBusy writes the 429 for a request its edge has no room for.
+func refuseBusy(w http.ResponseWriter, limit Concurrency) {
+	w.Header().Set("Retry-After", "1")
+	writeProblem(w, http.StatusTooManyRequests, reasonEdgeBusy,
+		fmt.Sprintf("edge has %d requests in flight and %d queued; retry later", limit.MaxInFlight, limit.MaxQueued))
+}
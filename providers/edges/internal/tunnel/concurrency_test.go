@@ -0,0 +1,133 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestEdgeLimitersFairQueue checks that a full edge queues requests, serves
+// callers in turn rather than in arrival order, and refuses requests beyond
+// the queue.
+func TestEdgeLimitersFairQueue(t *testing.T) {
+	l := newEdgeLimiters(Concurrency{MaxInFlight: 1, MaxQueued: 3, QueueTimeout: time.Minute})
+	ctx := context.Background()
+
+	release, ok := l.acquire(ctx, "edge", "bot")
+	if !ok {
+		t.Fatal("first request refused")
+	}
+
+	// The bot queues two requests before a person queues one.
+	served := make(chan string, 3)
+	start := func(caller string) {
+		l.mu.Lock()
+		queued := l.edges["edge"].queued
+		l.mu.Unlock()
+		go func() {
+			rel, ok := l.acquire(ctx, "edge", caller)
+			if !ok {
+				served <- "refused " + caller
+				return
+			}
+			served <- caller
+			rel()
+		}()
+		waitQueued(t, l, "edge", queued+1)
+	}
+	start("bot")
+	start("bot")
+	start("person")
+
+	if _, ok := l.acquire(ctx, "edge", "other"); ok {
+		t.Fatal("request beyond the queue was admitted")
+	}
+
+	release()
+	release() // releasing twice frees one slot
+	var order []string
+	for range 3 {
+		order = append(order, <-served)
+	}
+	if want := []string{"bot", "person", "bot"}; order[0] != want[0] || order[1] != want[1] || order[2] != want[2] {
+		t.Errorf("served %v, want %v", order, want)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.edges) != 0 {
+		t.Errorf("edge state left after all requests finished: %+v", l.edges["edge"])
+	}
+}
+
+// TestEdgeLimitersQueueTimeout checks that a queued request gives up after
+// QueueTimeout and leaves the queue.
+func TestEdgeLimitersQueueTimeout(t *testing.T) {
+	l := newEdgeLimiters(Concurrency{MaxInFlight: 1, MaxQueued: 1, QueueTimeout: 20 * time.Millisecond})
+	release, _ := l.acquire(context.Background(), "edge", "a")
+	if _, ok := l.acquire(context.Background(), "edge", "b"); ok {
+		t.Fatal("queued request admitted while the slot is held")
+	}
+	l.mu.Lock()
+	queued := l.edges["edge"].queued
+	l.mu.Unlock()
+	if queued != 0 {
+		t.Errorf("timed-out request still queued (%d)", queued)
+	}
+	release()
+	if rel, ok := l.acquire(context.Background(), "edge", "b"); !ok {
+		t.Error("request refused with a free slot")
+	} else {
+		rel()
+	}
+}
+
+func TestIsLongRunning(t *testing.T) {
+	for _, tc := range []struct {
+		target, subresource string
+		want                bool
+	}{
+		{"/k8s/api/v1/pods", "k8s", false},
+		{"/k8s/api/v1/pods?watch=true", "k8s", true},
+		{"/k8s/api/v1/watch/pods", "k8s", true},
+		{"/k8s/api/v1/namespaces/a/pods/p/log?follow=1", "k8s", true},
+		{"/ssh?cmd=uptime", "ssh", true},
+	} {
+		r := httptest.NewRequest("GET", tc.target, nil)
+		if got := isLongRunning(r, tc.subresource); got != tc.want {
+			t.Errorf("isLongRunning(%s) = %v, want %v", tc.target, got, tc.want)
+		}
+	}
+}
+
+// waitQueued blocks until want requests are queued on key.
+func waitQueued(t *testing.T, l *edgeLimiters, key string, want int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		l.mu.Lock()
+		n := l.edges[key].queued
+		l.mu.Unlock()
+		if n >= want {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("request was not queued")
+}
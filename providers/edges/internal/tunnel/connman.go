@@ -22,14 +22,18 @@ limitations under the License.
 // provider imports from the monorepo module.
 //
 // IMPORTANT: the ConnManager holds live revdial dialers in an in-process map,
-// so this provider MUST run as a single replica — an agent's control
-// connection and every later pickup connection must reach the same process.
+// so an agent's control connection and every later pickup connection must
+// reach the same process. Several replicas can only serve behind one load
+// balancer with peer forwarding on (peer.go), which routes pickups and
+// consumer requests to the replica holding the tunnel.
 package tunnel
 
 import (
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/klog/v2"
 
 	"github.com/faroshq/provider-sdk/revdial"
@@ -44,7 +48,7 @@ const connManagerSweepInterval = 30 * time.Second
 // ConnManager manages revdial.Dialer connections keyed by "edges/cluster/name".
 // It is shared between the agent-ingress handler (writes) and the edgeproxy
 // handler (reads) so that tunnel registrations are visible to user-facing
-// requests within this single provider process.
+// requests within this provider process.
 type ConnManager struct {
 	mu    sync.RWMutex
 	dials map[string]*revdial.Dialer
@@ -149,3 +153,31 @@ func (c *ConnManager) Keys() []string {
 // agent_proxy_builder_v2.go), used by consumers (controllers, edgeproxy) to
 // check whether an edge has a live tunnel.
 func EdgeConnKey(resource, cluster, name string) string { return edgeConnKey(resource, cluster, name) }
+
+var tunnelsDesc = prometheus.NewDesc("kedge_edges_tunnels",
+	"Open agent tunnels by edge resource and workspace (logical cluster).", []string{"resource", "workspace"}, nil)
+
+// Describe implements prometheus.Collector.
+func (c *ConnManager) Describe(ch chan<- *prometheus.Desc) {
+	ch <- tunnelsDesc
+}
+
+// Collect implements prometheus.Collector, counting the open tunnels per
+// edge resource and workspace. Closed dialers not swept yet are not counted.
+func (c *ConnManager) Collect(ch chan<- prometheus.Metric) {
+	type group struct{ resource, workspace string }
+	counts := map[group]int{}
+	c.mu.RLock()
+	for key, d := range c.dials {
+		if d == nil || d.IsClosed() {
+			continue
+		}
+		resource, rest, _ := strings.Cut(key, "/")
+		workspace, _, _ := strings.Cut(rest, "/")
+		counts[group{resource, workspace}]++
+	}
+	c.mu.RUnlock()
+	for g, n := range counts {
+		ch <- prometheus.MustNewConstMetric(tunnelsDesc, prometheus.GaugeValue, float64(n), g.resource, g.workspace)
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	edgeapi "github.com/faroshq/provider-edges/internal/edgeapi"
+)
+
+// reasonEdgeDraining is the problem reason for a session refused because its
+// edge is cordoned or being deleted.
+const reasonEdgeDraining = "EdgeDraining"
+
+// drainRegistry tracks the edges being drained and the proxied sessions open
+// to each, keyed like edgeConnManager. The drain reconciler feeds it from the
+// edges' Draining condition through Drain and Undrain.
+type drainRegistry struct {
+	mu       sync.Mutex
+	edges    map[string]*edgeDrain
+	sessions map[string]map[*session]struct{}
+}
+
+// edgeDrain is one edge's drain: why, and when its open sessions close.
+type edgeDrain struct {
+	reason   string
+	deadline time.Time
+	timer    *time.Timer
+}
+
+// session is an open proxied session that a drain can cut short.
+type session struct {
+	cancel context.CancelFunc
+}
+
+func newDrainRegistry() *drainRegistry {
+	return &drainRegistry{
+		edges:    make(map[string]*edgeDrain),
+		sessions: make(map[string]map[*session]struct{}),
+	}
+}
+
+// Drain refuses new sessions to the edge with connection key key and closes
+// those already open at deadline, immediately when it has passed. Calling it
+// again with the same deadline is a no-op, so the reconciler can repeat it.
+func (s *Server) Drain(key, reason string, deadline time.Time) {
+	d := s.drains
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if cur, ok := d.edges[key]; ok {
+		cur.reason = reason
+		if cur.deadline.Equal(deadline) {
+			return
+		}
+		cur.timer.Stop()
+	} else {
+		s.logger.Info("Draining edge", "key", key, "reason", reason, "deadline", deadline)
+	}
+	d.edges[key] = &edgeDrain{
+		reason:   reason,
+		deadline: deadline,
+		timer:    time.AfterFunc(time.Until(deadline), func() { s.closeSessions(key) }),
+	}
+}
+
+// Undrain accepts sessions to the edge again.
+func (s *Server) Undrain(key string) {
+	d := s.drains
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if cur, ok := d.edges[key]; ok {
+		cur.timer.Stop()
+		delete(d.edges, key)
+		s.logger.Info("Edge no longer draining", "key", key)
+	}
+}
+
+// draining returns the drain of the edge with connection key key, if any.
+func (s *Server) draining(key string) (edgeDrain, bool) {
+	d := s.drains
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	cur, ok := d.edges[key]
+	if !ok {
+		return edgeDrain{}, false
+	}
+	return *cur, true
+}
+
+// trackSession registers a proxied session to the edge with connection key
+// key. The returned context is cancelled when a drain of the edge reaches its
+// deadline; handlers must stop serving when it is. Call done when the
+// session ends.
+func (s *Server) trackSession(ctx context.Context, key string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	sess := &session{cancel: cancel}
+	d := s.drains
+	d.mu.Lock()
+	if d.sessions[key] == nil {
+		d.sessions[key] = make(map[*session]struct{})
+	}
+	d.sessions[key][sess] = struct{}{}
+	d.mu.Unlock()
+	return ctx, func() {
+		cancel()
+		d.mu.Lock()
+		delete(d.sessions[key], sess)
+		if len(d.sessions[key]) == 0 {
+			delete(d.sessions, key)
+		}
+		d.mu.Unlock()
+	}
+}
+
+// closeSessions ends every session open to the edge with connection key key.
+func (s *Server) closeSessions(key string) {
+	d := s.drains
+	d.mu.Lock()
+	sessions := d.sessions[key]
+	delete(d.sessions, key)
+	d.mu.Unlock()
+	if len(sessions) > 0 {
+		s.logger.Info("Drain grace period over, closing sessions", "key", key, "sessions", len(sessions))
+	}
+	for sess := range sessions {
+		sess.cancel()
+	}
+}
+
+// refuseDraining writes the 503 for a new session to a draining edge.
+func refuseDraining(w http.ResponseWriter, drain edgeDrain) {
+	what := "cordoned"
+	if drain.reason == edgeapi.DrainReasonDeleting {
+		what = "being deleted"
+	}
+	writeProblem(w, http.StatusServiceUnavailable, reasonEdgeDraining,
+		fmt.Sprintf("edge is %s and accepts no new sessions", what))
+}
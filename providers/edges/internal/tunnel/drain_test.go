@@ -0,0 +1,107 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	edgeapi "github.com/faroshq/provider-edges/internal/edgeapi"
+)
+
+// TestEdgesProxyRefusesDrainingEdge pins that a new session to a draining
+// edge is refused with 503 EdgeDraining before the tunnel is looked up, and
+// accepted again once the drain is lifted.
+func TestEdgesProxyRefusesDrainingEdge(t *testing.T) {
+	s := testServer("")
+	s.edgeConnManager = NewConnManager()
+	s.staticTokens = map[string]struct{}{"static-token": {}}
+	s.logger = klog.Background()
+	h := s.buildEdgesProxyHandler()
+
+	key := edgeConnKey("linuxservers", "abc", "box")
+	serve := func() *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/clusters/abc/apis/edges.kedge.faros.sh/v1alpha1/linuxservers/box/ssh?cmd=uptime", nil)
+		r.Header.Set("Authorization", "Bearer static-token")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	s.Drain(key, edgeapi.DrainReasonCordoned, time.Now().Add(time.Hour))
+	w := serve()
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503 (body %q)", w.Code, w.Body.String())
+	}
+	var body problem
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Reason != reasonEdgeDraining {
+		t.Errorf("reason = %q, want %q", body.Reason, reasonEdgeDraining)
+	}
+
+	s.Undrain(key)
+	if w := serve(); w.Code != http.StatusBadGateway {
+		t.Errorf("after undrain: status = %d, want 502 (no tunnel)", w.Code)
+	}
+}
+
+// TestDrainClosesSessionsAtDeadline checks that open sessions survive until
+// the drain deadline, and only those of the drained edge are closed.
+func TestDrainClosesSessionsAtDeadline(t *testing.T) {
+	s := testServer("")
+	s.logger = klog.Background()
+	drained, other := edgeConnKey("linuxservers", "abc", "box"), edgeConnKey("linuxservers", "abc", "other")
+
+	ctx, done := s.trackSession(context.Background(), drained)
+	defer done()
+	otherCtx, otherDone := s.trackSession(context.Background(), other)
+	defer otherDone()
+
+	deadline := time.Now().Add(50 * time.Millisecond)
+	s.Drain(drained, edgeapi.DrainReasonDeleting, deadline)
+	// Repeating the drain, as the reconciler does, must not reset it.
+	s.Drain(drained, edgeapi.DrainReasonDeleting, deadline)
+	select {
+	case <-ctx.Done():
+		t.Fatal("session closed before the deadline")
+	case <-time.After(10 * time.Millisecond):
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("session still open after the deadline")
+	}
+	if otherCtx.Err() != nil {
+		t.Error("session to another edge was closed")
+	}
+
+	// A drain whose deadline has passed closes sessions at once.
+	s.Drain(other, edgeapi.DrainReasonCordoned, time.Now().Add(-time.Second))
+	select {
+	case <-otherCtx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("session still open under an expired drain")
+	}
+}
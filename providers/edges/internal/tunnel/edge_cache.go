@@ -0,0 +1,161 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+// edgeCacheTTL is how long the edge cache keeps watching a tenant's edges of
+// one kind, or its Secrets in one namespace, after they were last read.
+const edgeCacheTTL = 30 * time.Minute
+
+// tenantClientFunc returns a dynamic client for a tenant logical cluster.
+type tenantClientFunc func(ctx context.Context, cluster string) (dynamic.Interface, error)
+
+// edgeCache mirrors the edge objects and Secrets the data plane reads from
+// tenant workspaces on every SSH dial, so a session to a warm tenant costs no
+// kcp round trip. Informers, one per tenant cluster, resource and namespace,
+// start on the first read and stop after ttl without reads; their watch
+// events keep the mirror current, so an edited edge or a rotated credential
+// Secret is seen by the next dial. Until an informer has synced, and for
+// objects it does not hold, reads go to kcp.
+type edgeCache struct {
+	clientFor tenantClientFunc
+	ttl       time.Duration
+
+	mu        sync.Mutex
+	informers map[edgeCacheKey]*edgeInformer
+	closed    bool
+}
+
+// edgeCacheKey identifies the objects one informer mirrors. namespace is
+// empty for cluster-scoped resources.
+type edgeCacheKey struct {
+	cluster   string
+	gvr       schema.GroupVersionResource
+	namespace string
+}
+
+// edgeInformer is one informer of the cache and when it was last read.
+type edgeInformer struct {
+	informer cache.SharedIndexInformer
+	stop     context.CancelFunc
+	lastRead time.Time
+}
+
+func newEdgeCache(clientFor tenantClientFunc, ttl time.Duration) *edgeCache {
+	return &edgeCache{clientFor: clientFor, ttl: ttl, informers: map[edgeCacheKey]*edgeInformer{}}
+}
+
+// run stops the informers not read for ttl until stop is closed, then stops
+// them all.
+func (c *edgeCache) run(stop <-chan struct{}) {
+	wait.Until(c.expire, c.ttl/2, stop)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	for key, ei := range c.informers {
+		ei.stop()
+		delete(c.informers, key)
+	}
+}
+
+// get returns the object name of gvr in namespace (empty for cluster-scoped
+// resources) of cluster, from the mirror when it holds it and from kcp
+// otherwise. The object returned is the caller's to modify.
+func (c *edgeCache) get(ctx context.Context, cluster string, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+	key := edgeCacheKey{cluster: cluster, gvr: gvr, namespace: namespace}
+	client, err := c.clientFor(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+	if informer := c.informer(key, client); informer != nil {
+		storeKey := name
+		if namespace != "" {
+			storeKey = namespace + "/" + name
+		}
+		// A miss may be an object created since the last event: let kcp say.
+		if obj, exists, err := informer.GetIndexer().GetByKey(storeKey); err == nil && exists {
+			return obj.(*unstructured.Unstructured).DeepCopy(), nil
+		}
+	}
+	return client.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// getSecret returns the Secret name in namespace of cluster, as get does.
+func (c *edgeCache) getSecret(ctx context.Context, cluster, namespace, name string) (*corev1.Secret, error) {
+	u, err := c.get(ctx, cluster, secretGVR, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	secret := &corev1.Secret{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, secret); err != nil {
+		return nil, fmt.Errorf("decoding secret %s/%s: %w", namespace, name, err)
+	}
+	return secret, nil
+}
+
+// informer returns the synced informer for key, starting it with client on
+// first use. It returns nil while the informer is still syncing.
+func (c *edgeCache) informer(key edgeCacheKey, client dynamic.Interface) cache.SharedIndexInformer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil
+	}
+	ei := c.informers[key]
+	if ei == nil {
+		ctx, stop := context.WithCancel(context.Background())
+		informer := dynamicinformer.NewFilteredDynamicInformer(client, key.gvr, key.namespace, 0,
+			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, nil).Informer()
+		go informer.RunWithContext(ctx)
+		ei = &edgeInformer{informer: informer, stop: stop}
+		c.informers[key] = ei
+	}
+	ei.lastRead = time.Now()
+	if !ei.informer.HasSynced() {
+		return nil
+	}
+	return ei.informer
+}
+
+// expire stops the informers not read for ttl.
+func (c *edgeCache) expire() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, ei := range c.informers {
+		if time.Since(ei.lastRead) >= c.ttl {
+			ei.stop()
+			delete(c.informers, key)
+		}
+	}
+}
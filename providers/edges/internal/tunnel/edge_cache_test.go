@@ -0,0 +1,175 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+var linuxServerGVR = schema.GroupVersionResource{Group: "edges.kedge.faros.sh", Version: "v1alpha1", Resource: "linuxservers"}
+
+// countGets returns the number of get actions client has recorded.
+func countGets(client *fake.FakeDynamicClient) int {
+	n := 0
+	for _, a := range client.Actions() {
+		if a.GetVerb() == "get" {
+			n++
+		}
+	}
+	return n
+}
+
+// TestEdgeCacheServesFromMirror pins that reads go to kcp until the tenant's
+// informer has synced, are then served from memory, and follow changes.
+func TestEdgeCacheServesFromMirror(t *testing.T) {
+	edge := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "edges.kedge.faros.sh/v1alpha1",
+		"kind":       "LinuxServer",
+		"metadata":   map[string]interface{}{"name": "box"},
+		"status":     map[string]interface{}{"sshHostKey": "key-1"},
+	}}
+	secret := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"namespace": "kedge-system", "name": "box-ssh"},
+		"data":       map[string]interface{}{"password": "aHVudGVyMg=="},
+	}}
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		linuxServerGVR: "LinuxServerList",
+		secretGVR:      "SecretList",
+	}, edge, secret)
+
+	c := newEdgeCache(func(_ context.Context, cluster string) (dynamic.Interface, error) {
+		if cluster != "tenant-a" {
+			t.Errorf("client for cluster %q, want tenant-a", cluster)
+		}
+		return client, nil
+	}, time.Hour)
+	stop := make(chan struct{})
+	defer close(stop)
+	go c.run(stop)
+	ctx := context.Background()
+
+	// Cold: the first read starts the informer and goes to kcp.
+	u, err := c.get(ctx, "tenant-a", linuxServerGVR, "", "box")
+	if err != nil {
+		t.Fatalf("cold get: %v", err)
+	}
+	if key, _, _ := unstructured.NestedString(u.Object, "status", "sshHostKey"); key != "key-1" {
+		t.Errorf("sshHostKey = %q, want key-1", key)
+	}
+	if countGets(client) != 1 {
+		t.Fatalf("cold read made %d kcp gets, want 1", countGets(client))
+	}
+
+	waitSynced := func(gvr schema.GroupVersionResource, namespace string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for c.informer(edgeCacheKey{cluster: "tenant-a", gvr: gvr, namespace: namespace}, client) == nil {
+			if time.Now().After(deadline) {
+				t.Fatalf("%s informer did not sync", gvr.Resource)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	waitSynced(linuxServerGVR, "")
+
+	// Warm: served from the mirror, and the caller's copy is its own.
+	client.ClearActions()
+	u, err = c.get(ctx, "tenant-a", linuxServerGVR, "", "box")
+	if err != nil {
+		t.Fatalf("warm get: %v", err)
+	}
+	u.Object["status"] = nil
+	if countGets(client) != 0 {
+		t.Errorf("warm read made %d kcp gets, want none", countGets(client))
+	}
+
+	// A changed edge reaches the mirror through its watch.
+	edge.Object["status"] = map[string]interface{}{"sshHostKey": "key-2"}
+	if _, err := client.Resource(linuxServerGVR).Update(ctx, edge, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		u, err := c.get(ctx, "tenant-a", linuxServerGVR, "", "box")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key, _, _ := unstructured.NestedString(u.Object, "status", "sshHostKey"); key == "key-2" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("updated edge never reached the mirror")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Secrets are decoded, from kcp and then from the mirror alike.
+	for range 2 {
+		s, err := c.getSecret(ctx, "tenant-a", "kedge-system", "box-ssh")
+		if err != nil {
+			t.Fatalf("getSecret: %v", err)
+		}
+		if string(s.Data["password"]) != "hunter2" {
+			t.Errorf("password = %q, want hunter2", s.Data["password"])
+		}
+		waitSynced(secretGVR, "kedge-system")
+	}
+
+	// An object the mirror lacks is looked up in kcp.
+	client.ClearActions()
+	if _, err := c.get(ctx, "tenant-a", linuxServerGVR, "", "gone"); err == nil {
+		t.Error("get of a missing edge succeeded")
+	}
+	if countGets(client) != 1 {
+		t.Errorf("miss made %d kcp gets, want 1", countGets(client))
+	}
+}
+
+// TestEdgeCacheExpiresIdleInformers pins that informers not read for the
+// TTL are stopped.
+func TestEdgeCacheExpiresIdleInformers(t *testing.T) {
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		linuxServerGVR: "LinuxServerList",
+	})
+	c := newEdgeCache(func(context.Context, string) (dynamic.Interface, error) { return client, nil }, time.Minute)
+	_, _ = c.get(context.Background(), "tenant-a", linuxServerGVR, "", "box")
+	if len(c.informers) != 1 {
+		t.Fatalf("informers = %d, want 1", len(c.informers))
+	}
+	c.expire()
+	if len(c.informers) != 1 {
+		t.Fatal("a recently read informer was stopped")
+	}
+	for _, ei := range c.informers {
+		ei.lastRead = time.Now().Add(-time.Minute)
+	}
+	c.expire()
+	if len(c.informers) != 0 {
+		t.Error("an idle informer was kept")
+	}
+}
@@ -34,6 +34,7 @@ func testServer(edgeProxyPublicPath string) *Server {
 		group:               "edges.kedge.faros.sh",
 		version:             "v1alpha1",
 		edgeProxyPublicPath: edgeProxyPublicPath,
+		drains:              newDrainRegistry(),
 	}
 }
 
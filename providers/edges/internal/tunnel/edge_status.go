@@ -38,13 +38,14 @@ import (
 )
 
 // markEdgeConnected updates an Edge's status to Connected=true, Phase=Ready,
-// and sets the Registered condition to True.
+// and sets the Registered condition to True. A non-empty agentVersion, sent by
+// the agent when it dialed, is recorded in status.agentVersion.
 // When clearJoinToken is true, the bootstrap JoinToken is also cleared from status.
 // clearJoinToken should only be true when the agent has received a durable credential
 // (kubeconfig) — otherwise the agent would be unable to reconnect after a restart.
 // It is called by the agent-proxy handler when a tunnel is established.
 // Best-effort: errors are logged but not propagated.
-func (p *Server) markEdgeConnected(ctx context.Context, gvr schema.GroupVersionResource, cluster, name string, sshCreds *sshCredsFromAgent, clearJoinToken bool) {
+func (p *Server) markEdgeConnected(ctx context.Context, gvr schema.GroupVersionResource, cluster, name, agentVersion string, sshCreds *sshCredsFromAgent, clearJoinToken bool) {
 	cfg, err := p.tenantConfigFor(ctx, cluster)
 	if err != nil {
 		p.logger.Error(err, "markEdgeConnected: failed to resolve tenant config",
@@ -99,6 +100,9 @@ func (p *Server) markEdgeConnected(ctx context.Context, gvr schema.GroupVersionR
 		if clearJoinToken {
 			delete(status, "joinToken")
 		}
+		if agentVersion != "" {
+			status["agentVersion"] = agentVersion
+		}
 
 		// Stamp the public proxy URL so `kedge kubeconfig edge` / `kedge ssh`
 		// have an address to externalize. This was previously set by the hub's
@@ -111,6 +115,10 @@ func (p *Server) markEdgeConnected(ctx context.Context, gvr schema.GroupVersionR
 
 		// Set the Registered condition to True.
 		now := metav1.NewTime(time.Now())
+
+		// Take the tunnel affinity lease: this replica now terminates the
+		// agent's tunnel. runEdgeHeartbeatLoop renews it.
+		status["tunnel"] = p.tunnelAffinity(now.Time, now.Time)
 		registeredCondition := metav1.Condition{
 			Type:               edgeapi.ConnectionConditionRegistered,
 			Status:             metav1.ConditionTrue,
@@ -269,8 +277,8 @@ func (p *Server) runEdgeHeartbeatLoop(ctx context.Context, gvr schema.GroupVersi
 	}
 }
 
-// stampEdgeHeartbeat patches an Edge's status.lastHeartbeatTime to t.  It is
-// used by the agent-proxy-v2 handler to surface revdial-level liveness (the
+// stampEdgeHeartbeat patches an Edge's status.lastHeartbeatTime to t and
+// renews this replica's status.tunnel affinity lease.  It is used by the agent-proxy-v2 handler to surface revdial-level liveness (the
 // last successful "pong" from the agent) on the Edge resource.  Agents
 // connected via join token can't write their own kcp status, so the hub does
 // it for them.
@@ -292,9 +300,18 @@ func (p *Server) stampEdgeHeartbeat(ctx context.Context, gvr schema.GroupVersion
 		return
 	}
 
-	// MergePatch with RFC3339-formatted timestamp; the field is typed as
-	// metav1.Time (date-time) in the APIResourceSchema.
-	patch := []byte(`{"status":{"lastHeartbeatTime":"` + t.UTC().Format(time.RFC3339) + `"}}`)
+	// MergePatch with RFC3339-formatted timestamps; the fields are typed as
+	// metav1.Time (date-time) in the APIResourceSchema. The lease renewal
+	// merges into status.tunnel, keeping its acquireTime.
+	patch, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"lastHeartbeatTime": t.UTC().Format(time.RFC3339),
+			"tunnel":            p.tunnelAffinity(time.Time{}, time.Now()),
+		},
+	})
+	if err != nil {
+		return
+	}
 	_, err = dynClient.Resource(gvr).Patch(ctx, name,
 		types.MergePatchType, patch, metav1.PatchOptions{}, "status")
 	if err != nil {
@@ -304,7 +321,8 @@ func (p *Server) stampEdgeHeartbeat(ctx context.Context, gvr schema.GroupVersion
 }
 
 // markEdgeDisconnected patches an Edge's status to Connected=false,
-// Phase=Disconnected on the hub.  It is called by the agent-proxy-v2 handler
+// Phase=Disconnected on the hub and releases this replica's status.tunnel
+// lease.  It is called by the agent-proxy-v2 handler
 // when the agent's revdial tunnel closes so that the hub's view of edge
 // connectivity is accurate even when the agent process dies without sending a
 // clean disconnect heartbeat.
@@ -333,6 +351,7 @@ func (p *Server) markEdgeDisconnected(ctx context.Context, gvr schema.GroupVersi
 			"cluster", cluster, "edge", name)
 		return
 	}
+	p.releaseTunnelAffinity(ctx, dynClient, gvr, cluster, name)
 
 	p.logger.Info("Edge marked Disconnected on tunnel close",
 		"cluster", cluster, "edge", name)
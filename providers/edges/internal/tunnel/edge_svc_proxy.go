@@ -0,0 +1,127 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+)
+
+// edgeSvcSubresource proxies HTTP(S) to a Service on a kubernetes edge, the
+// way kubectl proxy's service path does:
+//
+//	.../kubernetesclusters/{name}/svc/{namespace}/{service}/{port}[/path]
+//
+// {port} is a port number, optionally prefixed with the scheme to speak to
+// the Service ("https:8443"; plain HTTP by default). The request goes to the
+// agent's /svc endpoint with the Service's cluster-DNS name as the target, so
+// it is the agent that dials it — the edge apiserver's own service proxy is
+// not involved and the caller needs no RBAC on the edge cluster.
+const edgeSvcSubresource = "svc"
+
+// parseEdgeSvcPath parses what follows the svc subresource into the agent's
+// X-Kedge-Svc-Target value and the path on the Service.
+func parseEdgeSvcPath(rest string) (target, path string, err error) {
+	parts := strings.SplitN(rest, "/", 4)
+	if len(parts) < 3 {
+		return "", "", fmt.Errorf("expected svc/{namespace}/{service}/{port}[/path]")
+	}
+	namespace, service, port := parts[0], parts[1], parts[2]
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return "", "", fmt.Errorf("invalid namespace %q: %s", namespace, strings.Join(errs, "; "))
+	}
+	if errs := validation.IsDNS1035Label(service); len(errs) > 0 {
+		return "", "", fmt.Errorf("invalid service name %q: %s", service, strings.Join(errs, "; "))
+	}
+
+	scheme := "http"
+	if s, p, ok := strings.Cut(port, ":"); ok {
+		if s != "http" && s != "https" {
+			return "", "", fmt.Errorf("invalid scheme %q: must be http or https", s)
+		}
+		scheme, port = s, p
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return "", "", fmt.Errorf("invalid port %q: must be a number between 1 and 65535", port)
+	}
+
+	path = "/"
+	if len(parts) == 4 {
+		path += parts[3]
+	}
+	return fmt.Sprintf("%s://%s.%s.svc:%s", scheme, service, namespace, port), path, nil
+}
+
+// edgeSubresourceRest returns the part of an edges-proxy path after the
+// subresource segment (see parseEdgesProxyPath), without a leading slash.
+func edgeSubresourceRest(path string) string {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 9)
+	if len(parts) < 9 {
+		return ""
+	}
+	return parts[8]
+}
+
+// edgesSvcHandler reverse-proxies a request to a Service on the edge cluster
+// through the agent's /svc endpoint. The caller's hub credentials are
+// dropped: they authorize the request here and mean nothing to the Service.
+func (p *Server) edgesSvcHandler(ctx context.Context, w http.ResponseWriter, r *http.Request, key string, dialer interface {
+	Dial(context.Context) (net.Conn, error)
+}) {
+	logger := klog.FromContext(ctx)
+
+	target, path, err := parseEdgeSvcPath(edgeSubresourceRest(r.URL.Path))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	svcPath := "/svc" + path
+
+	deviceConn, err := dialer.Dial(ctx)
+	if err != nil {
+		logger.Error(err, "failed to dial edge agent for svc", "key", key)
+		http.Error(w, "failed to connect to edge agent", http.StatusBadGateway)
+		return
+	}
+
+	if isUpgradeRequest(r) {
+		p.serviceHandleUpgrade(ctx, w, r, deviceConn, target, svcPath, "")
+		return
+	}
+
+	transport := &edgeDeviceConnTransport{conn: deviceConn}
+	proxy := &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			req.URL.Scheme = "http"
+			req.URL.Host = "edge-agent"
+			req.URL.Path = svcPath
+			req.URL.RawPath = ""
+			req.Header.Set(svcTargetHeader, target)
+			req.Header.Del("Authorization")
+		},
+		Transport: transport,
+	}
+	proxy.ServeHTTP(w, r)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import "testing"
+
+func TestParseEdgeSvcPath(t *testing.T) {
+	cases := []struct {
+		rest       string
+		wantTarget string
+		wantPath   string
+	}{
+		{"monitoring/grafana/3000", "http://grafana.monitoring.svc:3000", "/"},
+		{"monitoring/grafana/3000/", "http://grafana.monitoring.svc:3000", "/"},
+		{"monitoring/grafana/3000/d/abc/home", "http://grafana.monitoring.svc:3000", "/d/abc/home"},
+		{"default/dash/https:8443/api", "https://dash.default.svc:8443", "/api"},
+		{"default/dash/http:80", "http://dash.default.svc:80", "/"},
+	}
+	for _, tc := range cases {
+		target, path, err := parseEdgeSvcPath(tc.rest)
+		if err != nil {
+			t.Errorf("parseEdgeSvcPath(%q): %v", tc.rest, err)
+			continue
+		}
+		if target != tc.wantTarget || path != tc.wantPath {
+			t.Errorf("parseEdgeSvcPath(%q) = %q, %q; want %q, %q", tc.rest, target, path, tc.wantTarget, tc.wantPath)
+		}
+	}
+}
+
+func TestParseEdgeSvcPathRejects(t *testing.T) {
+	for _, rest := range []string{
+		"",
+		"monitoring/grafana",
+		"monitoring/grafana/http",
+		"monitoring/grafana/0",
+		"monitoring/grafana/70000",
+		"monitoring/grafana/ftp:21",
+		"monitoring/evil.example.com/80",
+		"kube-system:x/grafana/80",
+		"Monitoring/grafana/80",
+	} {
+		if target, _, err := parseEdgeSvcPath(rest); err == nil {
+			t.Errorf("parseEdgeSvcPath(%q) = %q, want error", rest, target)
+		}
+	}
+}
+
+func TestEdgeSubresourceRest(t *testing.T) {
+	const edge = "/clusters/abc/apis/edges.kedge.faros.sh/v1alpha1/kubernetesclusters/site"
+	if got := edgeSubresourceRest(edge + "/svc/monitoring/grafana/3000/login"); got != "monitoring/grafana/3000/login" {
+		t.Errorf("rest = %q", got)
+	}
+	if got := edgeSubresourceRest(edge + "/svc"); got != "" {
+		t.Errorf("rest = %q, want empty", got)
+	}
+}
@@ -34,7 +34,6 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
@@ -52,74 +51,173 @@ import (
 //	/clusters/{cluster}/apis/edges.kedge.faros.sh/v1alpha1/edges/{name}/{subresource}[/...]
 //
 // Supported subresources:
-//   - k8s  — reverse-proxy to the Kubernetes API of a type=kubernetes edge
-//   - ssh  — WebSocket SSH terminal session on a type=server edge
+//   - k8s     — reverse-proxy to the Kubernetes API of a type=kubernetes edge
+//   - k8s-tls — opaque relay of a TLS session the agent terminates itself
+//     (agents run with --end-to-end-tls); the hub never sees plaintext
+//   - ssh     — WebSocket SSH terminal session on a type=server edge; an
+//     interactive one may require step-up (stepup.go)
+//   - svc     — reverse-proxy HTTP(S) to a Service on a type=kubernetes edge
+//     (edge_svc_proxy.go)
+//   - signurl — POST: mint a signed, time-limited URL to k8s/ssh (signed_url.go)
+//
+// It also serves POST .../fleetcommands/{name}/requester (fleet_requester.go).
 func (p *Server) buildEdgesProxyHandler() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// 1. Authenticate: require a valid bearer token.
+		// 1. Authenticate: require a valid bearer token, or a signed URL
+		// (verified in step 3 once the path is parsed). The query is kept as
+		// received for a peer the request may be forwarded to: verifying a
+		// signed URL strips its parameters.
+		rawQuery := r.URL.RawQuery
 		token := extractBearerToken(r)
-		if token == "" {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		signed := token == "" && isSignedRequest(r)
+		if token == "" && !signed {
+			writeProblem(w, http.StatusUnauthorized, reasonUnauthorized, "missing bearer token or signed URL")
 			return
 		}
 
 		// 1b. Service subresources (proxy/mcp) are branched here BEFORE
 		// parseEdgesProxyPath: "services" is not a tunnel Kind, so that
 		// parser (which validates against gvrForResource) would reject it.
+		// Signed URLs are never valid for services.
 		if esCluster, esName, esSub, esRest, ok := p.parseServicePath(r.URL.Path); ok {
+			if signed {
+				writeProblem(w, http.StatusUnauthorized, reasonUnauthorized, "signed URLs are not valid for edge services")
+				return
+			}
 			p.serveService(w, r, token, esCluster, esName, esSub, esRest)
 			return
 		}
+		// Likewise FleetCommand requester registration (fleet_requester.go).
+		if fcCluster, fcName, ok := p.parseFleetRequesterPath(r.URL.Path); ok {
+			if signed {
+				writeProblem(w, http.StatusUnauthorized, reasonUnauthorized, "signed URLs are not valid for fleet command requesters")
+				return
+			}
+			p.serveFleetRequester(w, r, token, fcCluster, fcName)
+			return
+		}
 
 		// 2. Parse cluster, resource (kind), name, and subresource from the URL path.
 		cluster, resource, name, subresource, ok := p.parseEdgesProxyPath(r.URL.Path)
 		if !ok {
-			http.Error(w, "invalid path: expected /clusters/{cluster}/apis/edges.kedge.faros.sh/v1alpha1/{kubernetesclusters|linuxservers}/{name}/{subresource}[/...]", http.StatusBadRequest)
+			writeProblem(w, http.StatusBadRequest, reasonBadRequest, "invalid path: expected /clusters/{cluster}/apis/edges.kedge.faros.sh/v1alpha1/{kubernetesclusters|linuxservers}/{name}/{subresource}[/...]")
 			return
 		}
 
 		// 3. Delegated authorization via kcp (if configured).
+		// Signed URLs carry their own authorization (scope + expiry under the
+		// provider's HMAC key) and are checked statelessly instead; they can
+		// never be used to mint further URLs.
 		// Static tokens bypass authorizeFn entirely — they are pre-authenticated
 		// server-side credentials that do not go through kcp SubjectAccessReview.
+		// Any other token is refused when there is no kcp to authorize it
+		// against: failing open here would let an arbitrary bearer string
+		// reach the edge and mint signed URLs for it.
 		_, isStaticToken := p.staticTokens[token]
-		if !isStaticToken && p.kcpConfig != nil {
+		if signed {
+			if subresource == signURLSubresource {
+				writeProblem(w, http.StatusForbidden, reasonForbidden, "signed URLs cannot mint further signed URLs")
+				return
+			}
+			if err := verifySignedRequest(p.urlSigningKey, r, cluster, resource, name, subresource, time.Now()); err != nil {
+				p.logger.Info("edges proxy signed URL rejected", "cluster", cluster, "name", name,
+					"subresource", subresource, "reason", err.Error())
+				writeProblem(w, http.StatusForbidden, reasonForbidden, "invalid or expired signed URL")
+				return
+			}
+		} else if !isStaticToken {
+			if p.kcpConfig == nil {
+				p.logger.Info("edges proxy authorization unavailable: no kcp config",
+					"cluster", cluster, "name", name, "subresource", subresource)
+				writeProblem(w, http.StatusForbidden, reasonForbidden, "not allowed to proxy to this edge")
+				return
+			}
 			tenantCfg, err := p.tenantConfigFor(r.Context(), cluster)
 			if err != nil {
 				p.logger.Error(err, "edges proxy authorization: resolving tenant config failed",
 					"cluster", cluster, "name", name, "subresource", subresource)
-				http.Error(w, "Forbidden", http.StatusForbidden)
+				writeProblem(w, http.StatusForbidden, reasonForbidden, "not allowed to proxy to this edge")
 				return
 			}
 			if err := p.authorizeFn(r.Context(), tenantCfg, p.kcpConfig, token, cluster, "proxy", p.group, resource, name); err != nil {
 				p.logger.Error(err, "edges proxy authorization failed",
 					"cluster", cluster, "name", name, "subresource", subresource)
-				http.Error(w, "Forbidden", http.StatusForbidden)
+				writeProblem(w, http.StatusForbidden, reasonForbidden, "not allowed to proxy to this edge")
 				return
 			}
 		}
 
-		// 4. Look up the dialer registered by the agent-proxy-v2 handler.
+		// 3b. Minting a signed URL needs no tunnel.
+		if subresource == signURLSubresource {
+			p.serveSignURL(w, r, cluster, resource, name)
+			return
+		}
+
+		// 4. Refuse new sessions to a cordoned or deleting edge (drain.go),
+		// then look up the dialer registered by the agent-proxy-v2 handler.
 		key := edgeConnKey(resource, cluster, name)
+		if drain, ok := p.draining(key); ok {
+			p.logger.Info("edges proxy session refused: edge draining", "cluster", cluster, "name", name,
+				"subresource", subresource, "reason", drain.reason)
+			refuseDraining(w, drain)
+			return
+		}
 		dialer, found := p.edgeConnManager.Load(key)
 		if !found {
+			// Another replica may hold the tunnel (peer.go).
+			if peer := p.tunnelPeer(r.Context(), resource, cluster, name); peer != "" &&
+				p.forwardToPeer(w, r, peer, EdgeProxyMount, rawQuery) {
+				return
+			}
 			p.logger.Info("no active tunnel found for edge", "cluster", cluster, "name", name)
-			http.Error(w, "upstream unavailable", http.StatusBadGateway)
+			writeProblem(w, http.StatusBadGateway, reasonServiceUnavailable, "upstream unavailable")
 			return
 		}
 
-		// 5. Route to the appropriate subresource handler.
+		// 4b. Wait for one of the edge's request slots, callers taking turns
+		// (concurrency.go). Long-running sessions are not counted.
+		if !isLongRunning(r, subresource) {
+			release, ok := p.concurrency.acquire(r.Context(), key, callerKey(token))
+			if !ok {
+				p.logger.Info("edges proxy request refused: edge busy", "cluster", cluster, "name", name,
+					"subresource", subresource)
+				refuseBusy(w, p.concurrency.limit)
+				return
+			}
+			defer release()
+		}
+
+		// 5. Route to the appropriate subresource handler. The session ends
+		// early if a drain of the edge reaches its deadline.
+		ctx, done := p.trackSession(r.Context(), key)
+		defer done()
+		r = r.WithContext(ctx)
 		switch subresource {
 		case "k8s":
-			p.edgesK8sHandler(r.Context(), w, r, key, dialer)
+			p.edgesK8sHandler(ctx, w, r, key, dialer)
+		case k8sTLSSubresource:
+			p.edgesK8sTLSHandler(ctx, w, r, key, dialer)
+		case edgeSvcSubresource:
+			if resource == "linuxservers" {
+				writeProblem(w, http.StatusBadRequest, reasonBadRequest, "svc is only available on kubernetes edges")
+				return
+			}
+			p.edgesSvcHandler(ctx, w, r, key, dialer)
 		case "ssh":
+			// Interactive sessions need a recent sign-in when step-up is
+			// on; signed URLs were stepped up when minted.
+			if !signed && r.URL.Query().Get("cmd") == "" && !p.requireStepUp(w, token, "an interactive SSH session") {
+				p.logger.Info("edges proxy SSH refused: step-up required", "cluster", cluster, "name", name)
+				return
+			}
 			// Resolve caller identity for identity-mode SSH mapping.
 			// Best-effort: empty string is fine for inherited/provided modes.
-			callerIdentity := resolveCallerIdentity(r.Context(), p.kcpConfig, token, p.logger)
+			callerIdentity := resolveCallerIdentity(ctx, p.kcpConfig, token, p.logger)
 			gvr, _, _ := p.gvrForResource(resource)
-			p.edgesSSHHandler(r.Context(), w, r, key, dialer, callerIdentity, gvr)
+			p.edgesSSHHandler(ctx, w, r, key, dialer, callerIdentity, gvr)
 		default:
 			p.logger.Info("unknown subresource requested", "subresource", subresource, "cluster", cluster, "name", name)
-			http.Error(w, "unknown subresource", http.StatusNotFound)
+			writeProblem(w, http.StatusNotFound, reasonNotFound, "unknown subresource")
 		}
 	})
 }
@@ -134,7 +232,7 @@ func (p *Server) edgesK8sHandler(ctx context.Context, w http.ResponseWriter, r *
 	deviceConn, err := dialer.Dial(ctx)
 	if err != nil {
 		logger.Error(err, "failed to dial edge agent for k8s", "key", key)
-		http.Error(w, "failed to connect to edge agent", http.StatusBadGateway)
+		writeProblem(w, http.StatusBadGateway, reasonServiceUnavailable, "failed to connect to edge agent")
 		return
 	}
 
@@ -158,6 +256,39 @@ func (p *Server) edgesK8sHandler(ctx context.Context, w http.ResponseWriter, r *
 	proxy.ServeHTTP(w, r)
 }
 
+// k8sTLSSubresource relays an end-to-end TLS session to the agent's /k8s-tls
+// endpoint. The client opens it with "Upgrade: kedge-tls" and, after the
+// agent's 101, starts a TLS handshake with the agent's pinned certificate.
+const k8sTLSSubresource = "k8s-tls"
+
+// edgesK8sTLSHandler forwards the upgrade request to the agent and then only
+// copies bytes: the session is encrypted between client and agent, so unlike
+// edgesK8sHandler nothing here can read or rewrite the API requests.
+func (p *Server) edgesK8sTLSHandler(ctx context.Context, w http.ResponseWriter, r *http.Request, key string, dialer interface {
+	Dial(context.Context) (net.Conn, error)
+}) {
+	logger := klog.FromContext(ctx)
+
+	if !isUpgradeRequest(r) {
+		writeProblem(w, http.StatusBadRequest, reasonBadRequest, "k8s-tls requires a connection upgrade")
+		return
+	}
+
+	deviceConn, err := dialer.Dial(ctx)
+	if err != nil {
+		logger.Error(err, "failed to dial edge agent for k8s-tls", "key", key)
+		writeProblem(w, http.StatusBadGateway, reasonServiceUnavailable, "failed to connect to edge agent")
+		return
+	}
+
+	// The agent serves the relay at a fixed path; anything after the
+	// subresource is meaningless outside the TLS session.
+	r.URL.Path = "/k8s-tls"
+	r.URL.RawQuery = ""
+	r.RequestURI = r.URL.RequestURI()
+	p.edgesRelayUpgrade(ctx, w, r, deviceConn)
+}
+
 // edgesSSHHandler establishes a WebSocket SSH session to the edge agent.
 // It dials the agent via the revdial.Dialer, opens the agent-side SSH tunnel,
 // and then bridges the caller's WebSocket to the SSH session.
@@ -185,7 +316,7 @@ func (p *Server) edgesSSHHandler(ctx context.Context, w http.ResponseWriter, r *
 	deviceConn, err := dialer.Dial(ctx)
 	if err != nil {
 		logger.Error(err, "failed to dial edge agent for SSH", "key", key)
-		http.Error(w, "failed to connect to edge agent", http.StatusBadGateway)
+		writeProblem(w, http.StatusBadGateway, reasonServiceUnavailable, "failed to connect to edge agent")
 		return
 	}
 
@@ -193,7 +324,7 @@ func (p *Server) edgesSSHHandler(ctx context.Context, w http.ResponseWriter, r *
 	sshConn, err := openAgentSSHTunnel(ctx, deviceConn)
 	if err != nil {
 		logger.Error(err, "failed to open SSH tunnel to edge agent", "key", key)
-		http.Error(w, "failed to open SSH tunnel", http.StatusBadGateway)
+		writeProblem(w, http.StatusBadGateway, reasonServiceUnavailable, "failed to open SSH tunnel")
 		return
 	}
 
@@ -267,29 +398,16 @@ func (p *Server) fetchSSHCredentials(ctx context.Context, cluster, edgeName, cal
 		return nil, nil
 	}
 
-	// Create cluster-scoped clients via the APIExport virtual workspace (the
-	// provider SA cannot read tenant Edge/Secret objects by re-rooting its own
-	// workspace-scoped config).
-	clusterConfig, err := p.tenantConfigFor(ctx, cluster)
-	if err != nil {
-		return nil, fmt.Errorf("resolving tenant config: %w", err)
-	}
-
-	dynClient, err := dynamic.NewForConfig(clusterConfig)
-	if err != nil {
-		return nil, fmt.Errorf("creating cluster-scoped dynamic client: %w", err)
-	}
-
-	k8sClient, err := kubernetes.NewForConfig(clusterConfig)
-	if err != nil {
-		return nil, fmt.Errorf("creating cluster-scoped k8s client: %w", err)
-	}
-
+	// Read through the edge cache (edge_cache.go), which mirrors tenants'
+	// edges and Secrets through the APIExport virtual workspace (the
+	// provider SA cannot read tenant Edge/Secret objects by re-rooting its
+	// own workspace-scoped config).
+	//
 	// SSH is a server-kind concern; fetch this Server's configured kind (the
 	// edges-servers provider configures it with the LinuxServer GVR) and decode
 	// only the ssh-relevant fields into a local view — the SDK stays independent
 	// of any provider's concrete type.
-	u, err := dynClient.Resource(gvr).Get(ctx, edgeName, metav1.GetOptions{})
+	u, err := p.edgeCache.get(ctx, cluster, gvr, "", edgeName)
 	if err != nil {
 		return nil, fmt.Errorf("fetching edge %s: %w", edgeName, err)
 	}
@@ -308,7 +426,7 @@ func (p *Server) fetchSSHCredentials(ctx context.Context, cluster, edgeName, cal
 		if ref == nil {
 			return nil, fmt.Errorf("sshUserMapping=provided but spec.sshCredentialsRef is not set for linuxserver %s", edgeName)
 		}
-		creds, err := p.readSSHCredsFromSecret(ctx, k8sClient, ref, "", logger)
+		creds, err := p.readSSHCredsFromSecret(ctx, cluster, ref, "", logger)
 		if err != nil {
 			return nil, err
 		}
@@ -323,7 +441,7 @@ func (p *Server) fetchSSHCredentials(ctx context.Context, cluster, edgeName, cal
 			return nil, fmt.Errorf("sshUserMapping=identity but caller identity is empty for edge %s", edgeName)
 		}
 		if ref := edge.Spec.SSHCredentialsRef; ref != nil {
-			creds, err := p.readSSHCredsFromSecret(ctx, k8sClient, ref, callerIdentity, logger)
+			creds, err := p.readSSHCredsFromSecret(ctx, cluster, ref, callerIdentity, logger)
 			if err != nil {
 				return nil, err
 			}
@@ -333,7 +451,7 @@ func (p *Server) fetchSSHCredentials(ctx context.Context, cluster, edgeName, cal
 			return creds, nil
 		}
 		// Fall back to status credentials but override the username.
-		creds, err := p.readStatusSSHCreds(ctx, k8sClient, edge, logger)
+		creds, err := p.readStatusSSHCreds(ctx, cluster, edge, logger)
 		if err != nil {
 			return nil, err
 		}
@@ -346,7 +464,7 @@ func (p *Server) fetchSSHCredentials(ctx context.Context, cluster, edgeName, cal
 
 	default:
 		// "inherited" (or empty default) → existing behavior: use agent-reported creds.
-		creds, err := p.readStatusSSHCreds(ctx, k8sClient, edge, logger)
+		creds, err := p.readStatusSSHCreds(ctx, cluster, edge, logger)
 		if err != nil {
 			return nil, err
 		}
@@ -395,7 +513,7 @@ type sshEdgeView struct {
 
 // readStatusSSHCreds reads SSH credentials from status.sshCredentials
 // and dereferences the referenced secrets.
-func (p *Server) readStatusSSHCreds(ctx context.Context, k8sClient kubernetes.Interface, edge *sshEdgeView, logger klog.Logger) (*SSHClientCredentials, error) {
+func (p *Server) readStatusSSHCreds(ctx context.Context, cluster string, edge *sshEdgeView, logger klog.Logger) (*SSHClientCredentials, error) {
 	if edge.Status.SSHCredentials == nil {
 		logger.V(4).Info("No SSH credentials in edge status", "edge", edge.Name)
 		return nil, nil
@@ -406,7 +524,7 @@ func (p *Server) readStatusSSHCreds(ctx context.Context, k8sClient kubernetes.In
 	}
 
 	if ref := edge.Status.SSHCredentials.PasswordSecretRef; ref != nil {
-		secret, err := k8sClient.CoreV1().Secrets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		secret, err := p.edgeCache.getSecret(ctx, cluster, ref.Namespace, ref.Name)
 		if err != nil {
 			return nil, fmt.Errorf("fetching password secret %s/%s: %w", ref.Namespace, ref.Name, err)
 		}
@@ -416,7 +534,7 @@ func (p *Server) readStatusSSHCreds(ctx context.Context, k8sClient kubernetes.In
 	}
 
 	if ref := edge.Status.SSHCredentials.PrivateKeySecretRef; ref != nil {
-		secret, err := k8sClient.CoreV1().Secrets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		secret, err := p.edgeCache.getSecret(ctx, cluster, ref.Namespace, ref.Name)
 		if err != nil {
 			return nil, fmt.Errorf("fetching private key secret %s/%s: %w", ref.Namespace, ref.Name, err)
 		}
@@ -430,8 +548,8 @@ func (p *Server) readStatusSSHCreds(ctx context.Context, k8sClient kubernetes.In
 	return creds, nil
 }
 
-func (p *Server) readSSHCredsFromSecret(ctx context.Context, k8sClient kubernetes.Interface, ref *corev1.SecretReference, usernameOverride string, logger klog.Logger) (*SSHClientCredentials, error) {
-	secret, err := k8sClient.CoreV1().Secrets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+func (p *Server) readSSHCredsFromSecret(ctx context.Context, cluster string, ref *corev1.SecretReference, usernameOverride string, logger klog.Logger) (*SSHClientCredentials, error) {
+	secret, err := p.edgeCache.getSecret(ctx, cluster, ref.Namespace, ref.Name)
 	if err != nil {
 		return nil, fmt.Errorf("fetching SSH credentials secret %s/%s: %w", ref.Namespace, ref.Name, err)
 	}
@@ -489,26 +607,33 @@ func resolveCallerIdentity(ctx context.Context, kcpConfig *rest.Config, token st
 // edgesHandleK8sUpgrade handles upgrade requests (exec, port-forward) to an
 // edge agent by hijacking the client connection and doing a bidirectional copy.
 func (p *Server) edgesHandleK8sUpgrade(ctx context.Context, w http.ResponseWriter, r *http.Request, deviceConn net.Conn) {
+	// Rewrite the URL path to the /k8s/... form the agent's mux expects.
+	// Without this the agent router sees the full hub path and returns 404.
+	r.URL.Path = extractEdgeK8sPath(r.URL.Path)
+	r.RequestURI = r.URL.RequestURI()
+	p.edgesRelayUpgrade(ctx, w, r, deviceConn)
+}
+
+// edgesRelayUpgrade writes the already-rewritten upgrade request r to the
+// agent, hijacks the client connection and copies bytes both ways until
+// either side closes. The agent's response, 101 or error, reaches the client
+// verbatim.
+func (p *Server) edgesRelayUpgrade(ctx context.Context, w http.ResponseWriter, r *http.Request, deviceConn net.Conn) {
 	logger := klog.FromContext(ctx)
+	defer deviceConn.Close() //nolint:errcheck
 
 	hijacker, ok := w.(http.Hijacker)
 	if !ok {
-		http.Error(w, "hijacking not supported", http.StatusInternalServerError)
+		writeProblem(w, http.StatusInternalServerError, reasonInternalError, "hijacking not supported")
 		return
 	}
 
 	clientConn, _, err := hijacker.Hijack()
 	if err != nil {
-		logger.Error(err, "failed to hijack client connection for edge k8s upgrade")
+		logger.Error(err, "failed to hijack client connection for edge upgrade")
 		return
 	}
 	defer clientConn.Close() //nolint:errcheck
-	defer deviceConn.Close() //nolint:errcheck
-
-	// Rewrite the URL path to the /k8s/... form the agent's mux expects.
-	// Without this the agent router sees the full hub path and returns 404.
-	r.URL.Path = extractEdgeK8sPath(r.URL.Path)
-	r.RequestURI = r.URL.RequestURI()
 
 	// Strip user credentials before forwarding to the edge agent to prevent
 	// the user's OIDC token from unnecessarily transiting the reverse tunnel.
@@ -519,11 +644,15 @@ func (p *Server) edgesHandleK8sUpgrade(ctx context.Context, w http.ResponseWrite
 		return
 	}
 
-	// Bidirectional pipe.
+	// Bidirectional pipe, until either side closes or ctx ends (a drain
+	// deadline); the deferred closes unblock the copies.
 	errc := make(chan error, 2)
 	go func() { _, err := io.Copy(deviceConn, clientConn); errc <- err }()
 	go func() { _, err := io.Copy(clientConn, deviceConn); errc <- err }()
-	<-errc
+	select {
+	case <-errc:
+	case <-ctx.Done():
+	}
 }
 
 // edgeDeviceConnTransport implements http.RoundTripper using an already-opened
@@ -580,15 +709,24 @@ func (p *Server) edgeProxyStatusURL(gvr schema.GroupVersionResource, cluster, na
 	if p.edgeProxyPublicPath == "" {
 		return ""
 	}
-	subresource := "k8s"
-	if gvr.Resource == "linuxservers" {
-		subresource = "ssh"
-	}
+	return p.edgeProxyPath(gvr, cluster, name, defaultSubresource(gvr.Resource))
+}
+
+// edgeProxyPath builds the public consumer-egress path of one edge subresource.
+func (p *Server) edgeProxyPath(gvr schema.GroupVersionResource, cluster, name, subresource string) string {
 	return fmt.Sprintf("%s/clusters/%s/apis/%s/%s/%s/%s/%s",
 		strings.TrimRight(p.edgeProxyPublicPath, "/"),
 		cluster, gvr.Group, gvr.Version, gvr.Resource, name, subresource)
 }
 
+// defaultSubresource is the subresource a kind is normally reached over.
+func defaultSubresource(resource string) string {
+	if resource == "linuxservers" {
+		return "ssh"
+	}
+	return "k8s"
+}
+
 // extractEdgeK8sPath strips the edges-proxy prefix from the request path,
 // keeping the /k8s/ prefix that the agent expects.
 //
@@ -0,0 +1,118 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"k8s.io/klog/v2"
+)
+
+// TestEdgesProxyFailsClosedWithoutKCP pins that, with no kcp to authorize
+// against, only static tokens get past authorization: any other bearer
+// string is refused before it can reach an edge or mint a signed URL.
+func TestEdgesProxyFailsClosedWithoutKCP(t *testing.T) {
+	s := testServer("")
+	s.edgeConnManager = NewConnManager()
+	s.staticTokens = map[string]struct{}{"static-token": {}}
+	s.logger = klog.Background()
+	h := s.buildEdgesProxyHandler()
+
+	const edge = "/clusters/abc/apis/edges.kedge.faros.sh/v1alpha1/linuxservers/box"
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		want   int
+	}{
+		{"ssh with unknown token", http.MethodGet, edge + "/ssh", "whatever", http.StatusForbidden},
+		{"signurl with unknown token", http.MethodPost, edge + "/signurl", "whatever", http.StatusForbidden},
+		{"service with unknown token", http.MethodGet, "/clusters/abc/apis/edges.kedge.faros.sh/v1alpha1/services/ha/proxy", "whatever", http.StatusForbidden},
+		{"fleet requester with unknown token", http.MethodPost, "/clusters/abc/apis/edges.kedge.faros.sh/v1alpha1/fleetcommands/run/requester", "whatever", http.StatusForbidden},
+		// Authorized, then fails on the missing tunnel.
+		{"ssh with static token", http.MethodGet, edge + "/ssh", "static-token", http.StatusBadGateway},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(tc.method, tc.path, nil)
+			r.Header.Set("Authorization", "Bearer "+tc.token)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != tc.want {
+				t.Errorf("status = %d, want %d (body %q)", w.Code, tc.want, w.Body.String())
+			}
+		})
+	}
+}
+
+// TestEdgesProxyErrorsAreProblems pins that refusals reach the hub's clients
+// as problem+json with the hub's stable reasons, not as text/plain.
+func TestEdgesProxyErrorsAreProblems(t *testing.T) {
+	s := testServer("")
+	s.edgeConnManager = NewConnManager()
+	s.staticTokens = map[string]struct{}{"static-token": {}}
+	s.logger = klog.Background()
+	h := s.buildEdgesProxyHandler()
+
+	const edge = "/clusters/abc/apis/edges.kedge.faros.sh/v1alpha1/linuxservers/box"
+	cases := []struct {
+		name, method, target, token, body string
+		status                            int
+		reason                            string
+	}{
+		{"no credentials", http.MethodGet, edge + "/ssh", "", "", http.StatusUnauthorized, reasonUnauthorized},
+		{"signed URL for a service", http.MethodGet, "/clusters/abc/apis/edges.kedge.faros.sh/v1alpha1/services/ha/proxy?kedge-signature=x", "", "", http.StatusUnauthorized, reasonUnauthorized},
+		{"signed URL minting a URL", http.MethodPost, edge + "/signurl?kedge-signature=x", "", "", http.StatusForbidden, reasonForbidden},
+		{"invalid signature", http.MethodGet, edge + "/ssh?kedge-signature=x&kedge-expires=1", "", "", http.StatusForbidden, reasonForbidden},
+		{"unknown token", http.MethodGet, edge + "/ssh", "whatever", "", http.StatusForbidden, reasonForbidden},
+		{"invalid path", http.MethodGet, "/clusters/abc/apis/edges.kedge.faros.sh/v1alpha1/linuxservers", "static-token", "", http.StatusBadRequest, reasonBadRequest},
+		{"sign with GET", http.MethodGet, edge + "/signurl", "static-token", "", http.StatusMethodNotAllowed, reasonMethodNotAllowed},
+		{"sign with a bad ttl", http.MethodPost, edge + "/signurl", "static-token", `{"subresource":"k8s","ttl":"soon"}`, http.StatusBadRequest, reasonBadRequest},
+		{"no tunnel", http.MethodGet, edge + "/ssh", "static-token", "", http.StatusBadGateway, reasonServiceUnavailable},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			if tc.body == "" {
+				r.ContentLength = 0
+			}
+			if tc.token != "" {
+				r.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != tc.status {
+				t.Fatalf("status = %d, want %d (body %q)", w.Code, tc.status, w.Body.String())
+			}
+			if got := w.Header().Get("Content-Type"); got != problemContentType {
+				t.Fatalf("Content-Type = %q, want %q", got, problemContentType)
+			}
+			var body problem
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("body is not JSON: %q: %v", w.Body.String(), err)
+			}
+			if body.Reason != tc.reason || body.Status != tc.status || body.Detail == "" {
+				t.Errorf("body = %+v, want reason %s", body, tc.reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
+)
+
+// A FleetCommand runs its command on every selected edge from the provider,
+// so the object alone says nothing about who may reach those edges: anyone
+// who can create one would otherwise get SSH to the whole fleet. Whoever runs
+// it therefore registers as its requester through
+//
+//	POST /clusters/{cluster}/apis/edges.kedge.faros.sh/v1alpha1/fleetcommands/{name}/requester
+//
+// on the edges proxy, which authenticates the caller, applies the step-up
+// check an interactive SSH session would, and records the identity on the
+// object under an HMAC the provider alone can produce. The fleet controller
+// then authorizes that identity for "proxy" on each edge before running the
+// command there (AuthorizeFleetExec). Annotations a client writes itself are
+// never trusted.
+
+const (
+	// FleetRequesterAnnotation holds the JSON Requester a FleetCommand runs on
+	// behalf of; FleetRequesterSignatureAnnotation its HMAC.
+	FleetRequesterAnnotation          = "edges.kedge.faros.sh/requester"
+	FleetRequesterSignatureAnnotation = "edges.kedge.faros.sh/requester-signature"
+
+	fleetCommandsResource     = "fleetcommands"
+	fleetRequesterSubresource = "requester"
+
+	// staticTokenRequester stands in for a static-token caller, which is
+	// pre-authorized everywhere on the edges proxy and so on every edge.
+	staticTokenRequester = "kedge:static-token"
+)
+
+// errNoFleetRequester marks a FleetCommand nobody has registered as the
+// requester of yet.
+var errNoFleetRequester = errors.New("no requester recorded")
+
+// parseFleetRequesterPath extracts {cluster} and {name} from a requester
+// registration path (see the file comment).
+func (p *Server) parseFleetRequesterPath(path string) (cluster, name string, ok bool) {
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(parts) != 8 || parts[0] != "clusters" || parts[2] != "apis" || parts[3] != p.group ||
+		parts[4] != p.version || parts[5] != fleetCommandsResource || parts[7] != fleetRequesterSubresource ||
+		parts[1] == "" || parts[6] == "" {
+		return "", "", false
+	}
+	return parts[1], parts[6], true
+}
+
+// serveFleetRequester records the caller as the requester of a FleetCommand
+// that has not started yet.
+func (p *Server) serveFleetRequester(w http.ResponseWriter, r *http.Request, token, cluster, name string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx := r.Context()
+
+	tenantCfg, err := p.tenantConfigFor(ctx, cluster)
+	if err != nil {
+		p.logger.Error(err, "fleet requester: resolving tenant config failed", "cluster", cluster, "name", name)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	requester := Requester{User: staticTokenRequester}
+	if _, isStaticToken := p.staticTokens[token]; !isStaticToken {
+		if p.kcpConfig == nil {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		if requester, err = p.reviewTokenFn(ctx, tenantCfg, p.kcpConfig, token, cluster); err != nil {
+			p.logger.Error(err, "fleet requester: token review failed", "cluster", cluster, "name", name)
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		// Registering binds the run to the caller, so it takes the same
+		// right as editing the FleetCommand.
+		if err := p.reviewAccessFn(ctx, tenantCfg, requester, "update", p.group, fleetCommandsResource, name); err != nil {
+			p.logger.Error(err, "fleet requester authorization failed", "cluster", cluster, "name", name, "user", requester.User)
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+	}
+	if !p.requireStepUp(w, token, "running a fleet command") {
+		p.logger.Info("fleet requester refused: step-up required", "cluster", cluster, "name", name)
+		return
+	}
+
+	raw, err := json.Marshal(requester)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	dynClient, err := dynamic.NewForConfig(tenantCfg)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	gvr := schema.GroupVersionResource{Group: p.group, Version: p.version, Resource: fleetCommandsResource}
+	var started bool
+	err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		fc, err := dynClient.Resource(gvr).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if phase, _, _ := unstructured.NestedString(fc.Object, "status", "phase"); phase != "" && phase != "Pending" {
+			started = true
+			return nil
+		}
+		// The resourceVersion precondition pins the generation the
+		// signature covers: a concurrent spec edit fails this patch.
+		patch, err := json.Marshal(map[string]any{
+			"metadata": map[string]any{
+				"resourceVersion": fc.GetResourceVersion(),
+				"annotations": map[string]string{
+					FleetRequesterAnnotation:          string(raw),
+					FleetRequesterSignatureAnnotation: fleetRequesterMAC(p.urlSigningKey, cluster, fc, string(raw)),
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		_, err = dynClient.Resource(gvr).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+		return err
+	})
+	switch {
+	case apierrors.IsNotFound(err):
+		http.Error(w, fmt.Sprintf("fleet command %q not found", name), http.StatusNotFound)
+		return
+	case err != nil:
+		p.logger.Error(err, "fleet requester: recording requester failed", "cluster", cluster, "name", name)
+		http.Error(w, "upstream unavailable", http.StatusBadGateway)
+		return
+	case started:
+		http.Error(w, fmt.Sprintf("fleet command %q has already started", name), http.StatusConflict)
+		return
+	}
+
+	p.logger.Info("recorded fleet command requester", "cluster", cluster, "name", name, "user", requester.User)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// fleetRequesterMAC signs requester for fc. The UID and generation are part of
+// the MAC, so a stamp cannot be copied onto another FleetCommand nor survive
+// a change to the command or selector.
+func fleetRequesterMAC(key []byte, cluster string, fc metav1.Object, requester string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strings.Join([]string{
+		cluster, fc.GetName(), string(fc.GetUID()), strconv.FormatInt(fc.GetGeneration(), 10), requester,
+	}, "\n")))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// FleetRequester returns the verified user recorded as fc's requester, or ""
+// when none is recorded yet. An error means the stamp is not one this
+// provider made for fc as it stands.
+func (p *Server) FleetRequester(cluster string, fc metav1.Object) (string, error) {
+	requester, err := p.fleetRequester(cluster, fc)
+	if errors.Is(err, errNoFleetRequester) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return requester.User, nil
+}
+
+func (p *Server) fleetRequester(cluster string, fc metav1.Object) (Requester, error) {
+	raw, ok := fc.GetAnnotations()[FleetRequesterAnnotation]
+	if !ok {
+		return Requester{}, errNoFleetRequester
+	}
+	sig := fc.GetAnnotations()[FleetRequesterSignatureAnnotation]
+	if !hmac.Equal([]byte(sig), []byte(fleetRequesterMAC(p.urlSigningKey, cluster, fc, raw))) {
+		return Requester{}, errors.New("requester signature does not match this fleet command")
+	}
+	var requester Requester
+	if err := json.Unmarshal([]byte(raw), &requester); err != nil || requester.User == "" {
+		return Requester{}, errors.New("malformed requester")
+	}
+	return requester, nil
+}
+
+// AuthorizeFleetExec checks that fc's verified requester may reach the
+// LinuxServer edge: "proxy" on it, exactly what `kedge ssh <edge>` needs.
+func (p *Server) AuthorizeFleetExec(ctx context.Context, cluster string, fc metav1.Object, edge string) error {
+	requester, err := p.fleetRequester(cluster, fc)
+	if err != nil {
+		return err
+	}
+	if requester.User == staticTokenRequester {
+		return nil
+	}
+	if p.kcpConfig == nil {
+		return errors.New("no kcp to authorize the requester against")
+	}
+	tenantCfg, err := p.tenantConfigFor(ctx, cluster)
+	if err != nil {
+		return fmt.Errorf("resolving tenant config: %w", err)
+	}
+	return p.reviewAccessFn(ctx, tenantCfg, requester, "proxy", p.group, "linuxservers", edge)
+}
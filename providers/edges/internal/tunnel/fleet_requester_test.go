@@ -0,0 +1,141 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+func TestParseFleetRequesterPath(t *testing.T) {
+	s := testServer("")
+	cases := []struct {
+		path        string
+		wantCluster string
+		wantName    string
+		wantOK      bool
+	}{
+		{"/clusters/abc/apis/edges.kedge.faros.sh/v1alpha1/fleetcommands/run-1/requester", "abc", "run-1", true},
+		{"/clusters/abc/apis/edges.kedge.faros.sh/v1alpha1/fleetcommands/run-1/requester/x", "", "", false},
+		{"/clusters/abc/apis/edges.kedge.faros.sh/v1alpha1/fleetcommands/run-1/status", "", "", false},
+		{"/clusters/abc/apis/edges.kedge.faros.sh/v1alpha1/linuxservers/box/requester", "", "", false},
+		{"/clusters/abc/apis/other.group/v1alpha1/fleetcommands/run-1/requester", "", "", false},
+	}
+	for _, tc := range cases {
+		cluster, name, ok := s.parseFleetRequesterPath(tc.path)
+		if cluster != tc.wantCluster || name != tc.wantName || ok != tc.wantOK {
+			t.Errorf("parseFleetRequesterPath(%q) = (%q, %q, %v), want (%q, %q, %v)",
+				tc.path, cluster, name, ok, tc.wantCluster, tc.wantName, tc.wantOK)
+		}
+	}
+}
+
+// TestFleetRequester pins that only a stamp made by this provider for the
+// FleetCommand as it stands is trusted.
+func TestFleetRequester(t *testing.T) {
+	s := testServer("")
+	s.urlSigningKey = []byte("key")
+	const requester = `{"user":"alice","groups":["ops"]}`
+
+	stamped := func(cluster string, key []byte) *metav1.ObjectMeta {
+		fc := &metav1.ObjectMeta{Name: "run-1", UID: "uid-1", Generation: 1}
+		fc.Annotations = map[string]string{
+			FleetRequesterAnnotation:          requester,
+			FleetRequesterSignatureAnnotation: fleetRequesterMAC(key, cluster, fc, requester),
+		}
+		return fc
+	}
+
+	cases := []struct {
+		name    string
+		fc      *metav1.ObjectMeta
+		want    string
+		wantErr bool
+	}{
+		{name: "valid", fc: stamped("abc", s.urlSigningKey), want: "alice"},
+		{name: "unstamped", fc: &metav1.ObjectMeta{Name: "run-1", UID: "uid-1", Generation: 1}},
+		{name: "signed by another key", fc: stamped("abc", []byte("other")), wantErr: true},
+		{name: "copied from another workspace", fc: stamped("xyz", s.urlSigningKey), wantErr: true},
+		{name: "spec changed", fc: func() *metav1.ObjectMeta { fc := stamped("abc", s.urlSigningKey); fc.Generation = 2; return fc }(), wantErr: true},
+		{name: "requester rewritten", fc: func() *metav1.ObjectMeta {
+			fc := stamped("abc", s.urlSigningKey)
+			fc.Annotations[FleetRequesterAnnotation] = `{"user":"admin"}`
+			return fc
+		}(), wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := s.FleetRequester("abc", tc.fc)
+			if (err != nil) != tc.wantErr || got != tc.want {
+				t.Fatalf("FleetRequester() = (%q, %v), want (%q, error %v)", got, err, tc.want, tc.wantErr)
+			}
+		})
+	}
+}
+
+// TestAuthorizeFleetExec pins that the requester, not the provider, is
+// authorized for "proxy" on each edge.
+func TestAuthorizeFleetExec(t *testing.T) {
+	s := testServer("")
+	s.urlSigningKey = []byte("key")
+	s.kcpConfig = &rest.Config{Host: "https://kcp"}
+	s.tenantConfig = func(context.Context, string) (*rest.Config, error) { return &rest.Config{}, nil }
+	var got []string
+	s.reviewAccessFn = func(_ context.Context, _ *rest.Config, caller Requester, verb, group, resource, name string) error {
+		got = append(got, caller.User+" "+verb+" "+resource+"/"+name)
+		if name == "locked" {
+			return errors.New("access denied")
+		}
+		return nil
+	}
+
+	stamp := func(requester string) *metav1.ObjectMeta {
+		fc := &metav1.ObjectMeta{Name: "run-1", UID: "uid-1", Generation: 1}
+		fc.Annotations = map[string]string{
+			FleetRequesterAnnotation:          requester,
+			FleetRequesterSignatureAnnotation: fleetRequesterMAC(s.urlSigningKey, "abc", fc, requester),
+		}
+		return fc
+	}
+
+	fc := stamp(`{"user":"alice"}`)
+	if err := s.AuthorizeFleetExec(context.Background(), "abc", fc, "box"); err != nil {
+		t.Fatalf("AuthorizeFleetExec(box) = %v, want nil", err)
+	}
+	if err := s.AuthorizeFleetExec(context.Background(), "abc", fc, "locked"); err == nil {
+		t.Fatal("AuthorizeFleetExec(locked) = nil, want denied")
+	}
+	if want := []string{"alice proxy linuxservers/box", "alice proxy linuxservers/locked"}; len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("access reviews = %q, want %q", got, want)
+	}
+
+	if err := s.AuthorizeFleetExec(context.Background(), "abc", &metav1.ObjectMeta{Name: "run-1"}, "box"); err == nil {
+		t.Fatal("AuthorizeFleetExec without a requester = nil, want error")
+	}
+
+	got = nil
+	if err := s.AuthorizeFleetExec(context.Background(), "abc", stamp(`{"user":"`+staticTokenRequester+`"}`), "locked"); err != nil {
+		t.Fatalf("AuthorizeFleetExec for a static-token requester = %v, want nil", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("static-token requester was access-reviewed: %q", got)
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/faroshq/provider-edges/internal/manifeststore"
+)
+
+// ManifestSource is the read side of the manifest store: the gzipped
+// canonical encoding of a bundle by digest.
+type ManifestSource interface {
+	Get(ctx context.Context, digest string) ([]byte, error)
+}
+
+// ManifestsHandler serves stored manifest bundles to edge agents. Mounted
+// (behind the hub backend proxy) at /services/providers/edges/manifests/.
+// Path after StripPrefix: /{cluster}/{namespace}/{placement}/{digest}.
+//
+// A bundle is served only to a caller allowed to get the Placement, and
+// only when that Placement references the digest, so holding a digest from
+// another tenant reveals nothing. The response is immutable; gzip-capable
+// clients get the stored compressed bytes as-is.
+func (p *Server) ManifestsHandler(source ManifestSource) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		if len(parts) != 4 || parts[0] == "" || parts[1] == "" || parts[2] == "" || !manifeststore.ValidDigest(parts[3]) {
+			http.Error(w, "invalid path: expected /{cluster}/{namespace}/{placement}/sha256:{hex}", http.StatusBadRequest)
+			return
+		}
+		cluster, namespace, name, digest := parts[0], parts[1], parts[2], parts[3]
+
+		token := extractBearerToken(r)
+		if token == "" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if p.kcpConfig == nil {
+			http.Error(w, "manifest store unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		tenantCfg, err := p.tenantConfigFor(r.Context(), cluster)
+		if err != nil {
+			p.logger.Error(err, "manifests: resolving tenant config failed", "cluster", cluster)
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		if _, isStaticToken := p.staticTokens[token]; !isStaticToken {
+			if err := p.authorizeFn(r.Context(), tenantCfg, p.kcpConfig, token, cluster, "get", p.group, "placements", name); err != nil {
+				p.logger.Error(err, "manifests authorization failed", "cluster", cluster, "placement", namespace+"/"+name)
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+		}
+
+		// The Placement must reference the digest.
+		dynClient, err := dynamic.NewForConfig(tenantCfg)
+		if err != nil {
+			p.logger.Error(err, "manifests: creating tenant client failed", "cluster", cluster)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		placementGVR := schema.GroupVersionResource{Group: p.group, Version: p.version, Resource: "placements"}
+		placement, err := dynClient.Resource(placementGVR).Namespace(namespace).Get(r.Context(), name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			p.logger.Error(err, "manifests: reading placement failed", "cluster", cluster, "placement", namespace+"/"+name)
+			http.Error(w, "reading placement failed", http.StatusBadGateway)
+			return
+		}
+		if ref, _, _ := unstructured.NestedString(placement.Object, "spec", "manifestsRef", "digest"); ref != digest {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+
+		blob, err := source.Get(r.Context(), digest)
+		if errors.Is(err, manifeststore.ErrNotFound) {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			p.logger.Error(err, "manifests: reading bundle failed", "digest", digest)
+			http.Error(w, "reading manifest bundle failed", http.StatusBadGateway)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"`+digest+`"`)
+		w.Header().Set("Cache-Control", "private, max-age=31536000, immutable")
+		w.Header().Set("Vary", "Accept-Encoding")
+		if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			w.Header().Set("Content-Encoding", "gzip")
+			_, _ = w.Write(blob)
+			return
+		}
+		data, err := manifeststore.Decompress(blob)
+		if err != nil {
+			p.logger.Error(err, "manifests: decompressing bundle failed", "digest", digest)
+			http.Error(w, "reading manifest bundle failed", http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write(data)
+	})
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"slices"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	edgeapi "github.com/faroshq/provider-edges/internal/edgeapi"
+)
+
+// Peer forwarding lets several provider replicas serve behind one load
+// balancer. A tunnel lives in the replica its agent connected to, which
+// publishes itself in the edge's status.tunnel (tunnel_affinity.go). A
+// replica asked for an edge whose tunnel it does not hold forwards the
+// request, as received, to the holder's endpoint; the holder authenticates
+// and authorizes it again. Revdial pickups name the replica that asked for
+// them (tunnelPeerParam) and are forwarded to it the same way.
+//
+// Forwarding is enabled by a peer Service, a headless Service resolving to
+// every replica. Only addresses it resolves to are forwarded to: agents may
+// patch their edge's status, so status.tunnel alone must not be able to
+// point a replica, and the caller's credentials, at an arbitrary host.
+
+const (
+	// AgentIngressMount and EdgeProxyMount are the paths AgentIngressHandler
+	// and EdgeProxyHandler are mounted at, with the prefix stripped. A
+	// forwarded request is sent to the same mount on the peer.
+	AgentIngressMount = "/agent"
+	EdgeProxyMount    = "/edgeproxy"
+
+	// peerForwardedHeader marks a request forwarded by a peer, with its
+	// name. A forwarded request is never forwarded again, so replicas that
+	// disagree about the holder cannot bounce a request between them.
+	peerForwardedHeader = "X-Kedge-Forwarded-By"
+
+	// tunnelPeerParam is the query parameter naming, in a pickup path, the
+	// endpoint of the replica holding the tunnel.
+	tunnelPeerParam = "kedge.peer"
+
+	// peerDialTimeout bounds connecting to a peer.
+	peerDialTimeout = 5 * time.Second
+)
+
+// peersEnabled reports whether requests may be forwarded to other replicas.
+func (p *Server) peersEnabled() bool {
+	return p.peerService != "" && p.instance.Endpoint != ""
+}
+
+// isPeer reports whether endpoint is another replica's: its host is one of
+// the addresses the peer Service resolves to.
+func (p *Server) isPeer(ctx context.Context, endpoint string) bool {
+	if !p.peersEnabled() || endpoint == "" || endpoint == p.instance.Endpoint {
+		return false
+	}
+	host, _, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return false
+	}
+	addrs, err := p.lookupPeers(ctx, p.peerService)
+	if err != nil {
+		p.logger.V(2).Info("resolving peer service failed", "service", p.peerService, "err", err)
+		return false
+	}
+	return slices.Contains(addrs, host)
+}
+
+// tunnelPeer returns the endpoint of the replica holding the tunnel of the
+// edge, or "" when there is none to forward to: no live lease, a lease held
+// by this replica, or an endpoint that is not a peer's.
+func (p *Server) tunnelPeer(ctx context.Context, resource, cluster, name string) string {
+	if !p.peersEnabled() {
+		return ""
+	}
+	gvr, _, ok := p.gvrForResource(resource)
+	if !ok {
+		return ""
+	}
+	edge, err := p.edgeCache.get(ctx, cluster, gvr, "", name)
+	if err != nil {
+		return ""
+	}
+	status, ok := edge.Object["status"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	raw, ok := status["tunnel"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	var lease edgeapi.TunnelAffinity
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &lease); err != nil {
+		return ""
+	}
+	if lease.Holder == p.instance.Name || lease.Expired(time.Now()) || !p.isPeer(ctx, lease.Endpoint) {
+		return ""
+	}
+	return lease.Endpoint
+}
+
+// forwardToPeer serves r by proxying it, upgrades included, to mount on the
+// peer at endpoint. rawQuery is the query r arrived with, in case a handler
+// already consumed parameters the peer must see again. It reports false,
+// having written nothing, when r was itself forwarded.
+func (p *Server) forwardToPeer(w http.ResponseWriter, r *http.Request, endpoint, mount, rawQuery string) bool {
+	if r.Header.Get(peerForwardedHeader) != "" {
+		return false
+	}
+	p.logger.V(4).Info("forwarding to tunnel holder", "peer", endpoint, "path", r.URL.Path)
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.Out.URL.Scheme = "http"
+			pr.Out.URL.Host = endpoint
+			pr.Out.URL.Path = mount + pr.In.URL.Path
+			pr.Out.URL.RawPath = ""
+			if pr.In.URL.RawPath != "" {
+				pr.Out.URL.RawPath = mount + pr.In.URL.RawPath
+			}
+			pr.Out.URL.RawQuery = rawQuery
+			pr.Out.Host = endpoint
+			pr.Out.Header.Set(peerForwardedHeader, p.instance.Name)
+		},
+		Transport:     p.peerTransport,
+		FlushInterval: -1,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			p.logger.Info("forwarding to tunnel holder failed", "peer", endpoint, "err", err)
+			http.Error(w, "upstream unavailable", http.StatusBadGateway)
+		},
+	}
+	proxy.ServeHTTP(w, r)
+	return true
+}
+
+// newPeerTransport returns the transport forwarded requests use. Peers are
+// reached directly over the pod network, never through a proxy.
+func newPeerTransport() *http.Transport {
+	return &http.Transport{
+		DialContext:         (&net.Dialer{Timeout: peerDialTimeout}).DialContext,
+		MaxIdleConnsPerHost: 16,
+		IdleConnTimeout:     90 * time.Second,
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+// peerTestServer returns a replica at 10.0.0.1 whose peer Service resolves
+// to itself and to 127.0.0.1, where the test's peers listen. The edges it
+// reads come from edges.
+func peerTestServer(edges ...runtime.Object) *Server {
+	s := testServer("")
+	s.instance = Instance{Name: "edges-a", Endpoint: "10.0.0.1:8084"}
+	s.peerService = "edges-peers"
+	s.lookupPeers = func(context.Context, string) ([]string, error) {
+		return []string{"10.0.0.1", "127.0.0.1"}, nil
+	}
+	s.peerTransport = newPeerTransport()
+	s.tunnelLimits = newTunnelLimiters()
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		linuxServerGVR: "LinuxServerList",
+	}, edges...)
+	s.edgeCache = newEdgeCache(func(context.Context, string) (dynamic.Interface, error) { return client, nil }, time.Hour)
+	return s
+}
+
+// heldEdge returns a LinuxServer whose tunnel holder renewed its lease at
+// renew.
+func heldEdge(name, holder, endpoint string, renew time.Time) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "edges.kedge.faros.sh/v1alpha1",
+		"kind":       "LinuxServer",
+		"metadata":   map[string]interface{}{"name": name},
+		"status": map[string]interface{}{
+			"tunnel": map[string]interface{}{
+				"holder":               holder,
+				"endpoint":             endpoint,
+				"renewTime":            renew.UTC().Format(time.RFC3339),
+				"leaseDurationSeconds": int64(90),
+			},
+		},
+	}}
+}
+
+func TestTunnelPeer(t *testing.T) {
+	now := time.Now()
+	s := peerTestServer(
+		heldEdge("held", "edges-b", "127.0.0.1:8084", now),
+		heldEdge("mine", "edges-a", "10.0.0.1:8084", now),
+		heldEdge("expired", "edges-b", "127.0.0.1:8084", now.Add(-time.Hour)),
+		heldEdge("foreign", "edges-b", "192.0.2.1:8084", now),
+		&unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "edges.kedge.faros.sh/v1alpha1",
+			"kind":       "LinuxServer",
+			"metadata":   map[string]interface{}{"name": "idle"},
+		}},
+	)
+	ctx := context.Background()
+
+	for name, want := range map[string]string{
+		"held":    "127.0.0.1:8084",
+		"mine":    "",
+		"expired": "",
+		"foreign": "", // not an address of the peer Service
+		"idle":    "",
+		"missing": "",
+	} {
+		if got := s.tunnelPeer(ctx, "linuxservers", "tenant-a", name); got != want {
+			t.Errorf("tunnelPeer(%s) = %q, want %q", name, got, want)
+		}
+	}
+
+	// Without a peer Service nothing is forwarded.
+	s.peerService = ""
+	if got := s.tunnelPeer(ctx, "linuxservers", "tenant-a", "held"); got != "" {
+		t.Errorf("tunnelPeer without peer service = %q", got)
+	}
+}
+
+func TestForwardToPeer(t *testing.T) {
+	var gotPath, gotQuery, gotFrom string
+	peer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery, gotFrom = r.URL.Path, r.URL.RawQuery, r.Header.Get(peerForwardedHeader)
+		_, _ = w.Write([]byte("held"))
+	}))
+	defer peer.Close()
+	endpoint := strings.TrimPrefix(peer.URL, "http://")
+	s := peerTestServer()
+
+	// A consumer request is sent to the peer's edgeproxy mount, with the
+	// query it arrived with.
+	path := "/clusters/tenant-a/apis/edges.kedge.faros.sh/v1alpha1/linuxservers/box/ssh"
+	rec := httptest.NewRecorder()
+	if !s.forwardToPeer(rec, httptest.NewRequest(http.MethodGet, path, nil), endpoint, EdgeProxyMount, "sig=abc") {
+		t.Fatal("request not forwarded")
+	}
+	if rec.Code != http.StatusOK || rec.Body.String() != "held" {
+		t.Fatalf("forwarded response = %d %q", rec.Code, rec.Body.String())
+	}
+	if gotPath != EdgeProxyMount+path || gotQuery != "sig=abc" || gotFrom != "edges-a" {
+		t.Fatalf("peer got %s?%s from %q", gotPath, gotQuery, gotFrom)
+	}
+
+	// A forwarded request is not forwarded again.
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set(peerForwardedHeader, "edges-b")
+	if s.forwardToPeer(httptest.NewRecorder(), req, endpoint, EdgeProxyMount, "") {
+		t.Fatal("forwarded request forwarded again")
+	}
+
+	// A pickup for a tunnel this replica does not know goes to the replica
+	// its path names, when that is a peer.
+	h := s.pickupQuotaHandler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Error("unknown tunnel served locally")
+	}))
+	pickup := func(peer string) int {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+			"/proxy?"+tunnelQuotaParam+"=unknown&"+tunnelPeerParam+"="+url.QueryEscape(peer)+"&revdial.dialer=abc", nil))
+		return rec.Code
+	}
+	gotPath = ""
+	if code := pickup(endpoint); code != http.StatusOK || gotPath != AgentIngressMount+"/proxy" {
+		t.Fatalf("pickup for a peer's tunnel = %d, peer got %q", code, gotPath)
+	}
+	if code := pickup("192.0.2.1:8084"); code != http.StatusNotFound {
+		t.Fatalf("pickup naming a non-peer = %d, want %d", code, http.StatusNotFound)
+	}
+	if !strings.Contains(s.pickupPath("id"), tunnelPeerParam+"="+url.QueryEscape(s.instance.Endpoint)) {
+		t.Fatalf("pickup path %q does not name this replica", s.pickupPath("id"))
+	}
+}
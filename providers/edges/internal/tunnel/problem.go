@@ -0,0 +1,66 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// problemContentType and problemTypePrefix match the hub's RFC 7807 error
+// bodies (pkg/problem), so the CLI and portal read a provider refusal the
+// same way as one from the hub itself.
+const (
+	problemContentType = "application/problem+json"
+	problemTypePrefix  = "urn:kedge:problem:"
+
+	// reasonStepUpRequired is the hub's StepUpRequired reason: a recognised
+	// token, but the operation needs a more recent sign-in.
+	reasonStepUpRequired = "StepUpRequired"
+
+	// The hub's generic reasons (problem.ReasonForStatus).
+	reasonUnauthorized       = "Unauthorized"
+	reasonForbidden          = "Forbidden"
+	reasonBadRequest         = "BadRequest"
+	reasonNotFound           = "NotFound"
+	reasonMethodNotAllowed   = "MethodNotAllowed"
+	reasonServiceUnavailable = "ServiceUnavailable"
+	reasonInternalError      = "InternalError"
+)
+
+// problem is the hub's problem+json body.
+type problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// writeProblem writes a problem+json error response.
+func writeProblem(w http.ResponseWriter, status int, reason, detail string) {
+	w.Header().Set("Content-Type", problemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(problem{
+		Type:   problemTypePrefix + reason,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Reason: reason,
+	})
+}
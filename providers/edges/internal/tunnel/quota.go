@@ -0,0 +1,392 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// tunnelQuotaParam is the query parameter added to a tunnel's revdial pickup
+// path. It names the tunnel's limiter so pickup connections are charged to
+// the same quota as the control connection that requested them.
+const tunnelQuotaParam = "kedge.tunnel"
+
+// errQuotaExceeded is returned by reads and writes on a tunnel that was
+// disconnected for exceeding its quota.
+var errQuotaExceeded = errors.New("tunnel quota exceeded")
+
+// Quota bounds what one agent tunnel — its control connection plus every
+// pickup connection — may push through this replica, so a slow or
+// misbehaving agent cannot saturate the provider's network or memory. A zero
+// field disables that limit.
+type Quota struct {
+	// ReceiveBytesPerSecond caps agent→provider traffic. Reads beyond it are
+	// throttled, which stops reading from the agent's sockets.
+	ReceiveBytesPerSecond int64
+	// SendBytesPerSecond caps provider→agent traffic. Writes beyond it are
+	// throttled.
+	SendBytesPerSecond int64
+	// MessagesPerSecond caps the control messages and pickup connections the
+	// agent sends, after a burst of MessageBurst. A well-behaved agent sends a
+	// pong per keep-alive and one pickup per proxied request; exceeding the
+	// rate disconnects the tunnel.
+	MessagesPerSecond float64
+	MessageBurst      int
+	// MaxThrottle is the longest a single read or write may be held back. An
+	// agent whose backlog would take longer to drain is disconnected rather
+	// than left holding connections open.
+	MaxThrottle time.Duration
+}
+
+// DefaultQuota returns the quota applied when Config.Quota is nil: generous
+// enough for kubectl, log streaming and SSH, tight enough that one edge cannot
+// starve the others.
+func DefaultQuota() Quota {
+	return Quota{
+		ReceiveBytesPerSecond: 16 << 20,
+		SendBytesPerSecond:    16 << 20,
+		MessagesPerSecond:     50,
+		MessageBurst:          200,
+		MaxThrottle:           30 * time.Second,
+	}
+}
+
+// Validate reports a quota with negative limits or a message rate without
+// a burst.
+func (q Quota) Validate() error {
+	if q.ReceiveBytesPerSecond < 0 || q.SendBytesPerSecond < 0 || q.MessagesPerSecond < 0 || q.MessageBurst < 0 || q.MaxThrottle < 0 {
+		return fmt.Errorf("tunnel quota limits must not be negative: %+v", q)
+	}
+	if q.MessagesPerSecond > 0 && q.MessageBurst == 0 {
+		return fmt.Errorf("tunnel quota: MessageBurst must be set with MessagesPerSecond")
+	}
+	return nil
+}
+
+// tunnelLimiter enforces a Quota across all connections of one tunnel. Once
+// the tunnel is found abusive every connection is closed, which also ends the
+// revdial dialer reading the control connection.
+type tunnelLimiter struct {
+	quota         Quota
+	recv, send    *rate.Limiter // nil when the limit is disabled
+	msgs          *rate.Limiter
+	recvChunk     int
+	sendChunk     int
+	done          chan struct{}
+	mu            sync.Mutex
+	conns         map[*quotaConn]struct{}
+	violation     string
+	violationOnce sync.Once
+}
+
+func newTunnelLimiter(q Quota) *tunnelLimiter {
+	l := &tunnelLimiter{
+		quota: q,
+		done:  make(chan struct{}),
+		conns: make(map[*quotaConn]struct{}),
+	}
+	if q.ReceiveBytesPerSecond > 0 {
+		l.recvChunk = byteBurst(q.ReceiveBytesPerSecond)
+		l.recv = rate.NewLimiter(rate.Limit(q.ReceiveBytesPerSecond), l.recvChunk)
+	}
+	if q.SendBytesPerSecond > 0 {
+		l.sendChunk = byteBurst(q.SendBytesPerSecond)
+		l.send = rate.NewLimiter(rate.Limit(q.SendBytesPerSecond), l.sendChunk)
+	}
+	if q.MessagesPerSecond > 0 {
+		l.msgs = rate.NewLimiter(rate.Limit(q.MessagesPerSecond), q.MessageBurst)
+	}
+	return l
+}
+
+// byteBurst is one second's worth of a byte rate, which is also the largest
+// single read or write charged against it.
+func byteBurst(perSecond int64) int {
+	const maxBurst = 1 << 30
+	if perSecond > maxBurst {
+		return maxBurst
+	}
+	return int(perSecond)
+}
+
+// Violation returns why the tunnel was disconnected, or "" if it was not.
+func (l *tunnelLimiter) Violation() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.violation
+}
+
+// disconnect records the first violation and closes every connection of the
+// tunnel.
+func (l *tunnelLimiter) disconnect(reason string) {
+	l.violationOnce.Do(func() {
+		l.mu.Lock()
+		l.violation = reason
+		conns := make([]*quotaConn, 0, len(l.conns))
+		for c := range l.conns {
+			conns = append(conns, c)
+		}
+		l.mu.Unlock()
+		close(l.done)
+		for _, c := range conns {
+			_ = c.Conn.Close()
+		}
+	})
+}
+
+func (l *tunnelLimiter) disconnected() bool {
+	select {
+	case <-l.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// message charges n agent messages and disconnects the tunnel if they exceed
+// the message rate.
+func (l *tunnelLimiter) message(n int) bool {
+	if l.msgs == nil || n == 0 {
+		return !l.disconnected()
+	}
+	if !l.msgs.AllowN(time.Now(), n) {
+		l.disconnect(fmt.Sprintf("more than %g messages per second (burst %d)", l.quota.MessagesPerSecond, l.quota.MessageBurst))
+		return false
+	}
+	return !l.disconnected()
+}
+
+// wait blocks until n bytes fit the limiter, or disconnects the tunnel when
+// that would take longer than MaxThrottle.
+func (l *tunnelLimiter) wait(lim *rate.Limiter, n int, direction string) error {
+	r := lim.ReserveN(time.Now(), n)
+	if !r.OK() {
+		// n is bounded by the chunk size, which is the burst.
+		return fmt.Errorf("tunnel quota: %d bytes exceed the %s burst", n, direction)
+	}
+	delay := r.Delay()
+	if delay == 0 {
+		return nil
+	}
+	if l.quota.MaxThrottle > 0 && delay > l.quota.MaxThrottle {
+		r.Cancel()
+		l.disconnect(fmt.Sprintf("%s backlog would be throttled for %s (limit %s)",
+			direction, delay.Round(time.Millisecond), l.quota.MaxThrottle))
+		return errQuotaExceeded
+	}
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-l.done:
+		return errQuotaExceeded
+	}
+}
+
+// wrap returns c charged against the tunnel's byte quotas. Closing the tunnel
+// for a violation closes c.
+func (l *tunnelLimiter) wrap(c net.Conn) net.Conn {
+	qc := &quotaConn{Conn: c, limiter: l}
+	l.mu.Lock()
+	l.conns[qc] = struct{}{}
+	l.mu.Unlock()
+	if l.disconnected() {
+		_ = c.Close()
+	}
+	return qc
+}
+
+// quotaConn is one raw (pre-WebSocket) connection of a tunnel. Bytes are
+// counted on the wire, framing included.
+type quotaConn struct {
+	net.Conn
+	limiter *tunnelLimiter
+}
+
+func (c *quotaConn) Read(b []byte) (int, error) {
+	l := c.limiter
+	if l.disconnected() {
+		return 0, errQuotaExceeded
+	}
+	if l.recv != nil && len(b) > l.recvChunk {
+		b = b[:l.recvChunk]
+	}
+	n, err := c.Conn.Read(b)
+	if n > 0 && l.recv != nil {
+		// Charge after the read: the bytes are already buffered, but the
+		// next read waits, so the agent's socket backs up instead of ours.
+		if werr := l.wait(l.recv, n, "receive"); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+func (c *quotaConn) Write(b []byte) (int, error) {
+	l := c.limiter
+	if l.send == nil {
+		if l.disconnected() {
+			return 0, errQuotaExceeded
+		}
+		return c.Conn.Write(b)
+	}
+	var written int
+	for len(b) > 0 {
+		chunk := b
+		if len(chunk) > l.sendChunk {
+			chunk = chunk[:l.sendChunk]
+		}
+		if err := l.wait(l.send, len(chunk), "send"); err != nil {
+			return written, err
+		}
+		n, err := c.Conn.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		b = b[n:]
+	}
+	return written, nil
+}
+
+func (c *quotaConn) Close() error {
+	c.limiter.mu.Lock()
+	delete(c.limiter.conns, c)
+	c.limiter.mu.Unlock()
+	return c.Conn.Close()
+}
+
+// messageConn counts the newline-delimited revdial control messages read from
+// a tunnel's (decoded) control connection against the message rate.
+type messageConn struct {
+	net.Conn
+	limiter *tunnelLimiter
+}
+
+func (c *messageConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if !c.limiter.message(bytes.Count(b[:n], []byte{'\n'})) {
+		return n, errQuotaExceeded
+	}
+	return n, err
+}
+
+// quotaResponseWriter hands the WebSocket upgrader a quota-wrapped connection
+// when it hijacks the request. The upgrader must have a non-zero
+// ReadBufferSize; otherwise gorilla reads through the hijacked bufio.Reader,
+// which bypasses the wrapper.
+type quotaResponseWriter struct {
+	http.ResponseWriter
+	limiter *tunnelLimiter
+}
+
+func (w *quotaResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	c, brw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	return w.limiter.wrap(c), brw, nil
+}
+
+func (w *quotaResponseWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+// tunnelLimiters indexes the live tunnels' limiters by the opaque ID carried
+// in their pickup path.
+type tunnelLimiters struct {
+	mu sync.Mutex
+	m  map[string]*tunnelLimiter
+}
+
+func newTunnelLimiters() *tunnelLimiters {
+	return &tunnelLimiters{m: make(map[string]*tunnelLimiter)}
+}
+
+// add registers a limiter for a new tunnel and returns its ID. The ID is
+// random so an agent cannot charge its pickups to another edge's tunnel.
+func (t *tunnelLimiters) add(l *tunnelLimiter) string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	id := hex.EncodeToString(buf)
+	t.mu.Lock()
+	t.m[id] = l
+	t.mu.Unlock()
+	return id
+}
+
+func (t *tunnelLimiters) get(id string) *tunnelLimiter {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.m[id]
+}
+
+func (t *tunnelLimiters) remove(id string) {
+	t.mu.Lock()
+	delete(t.m, id)
+	t.mu.Unlock()
+}
+
+// pickupPath returns the revdial pickup path for the tunnel with the given
+// limiter ID. With peer forwarding on, it also names this replica, so a
+// pickup the load balancer sends elsewhere is forwarded here (peer.go).
+func (p *Server) pickupPath(limiterID string) string {
+	join := "?"
+	if strings.Contains(p.agentPickupPath, "?") {
+		join = "&"
+	}
+	path := p.agentPickupPath + join + tunnelQuotaParam + "=" + limiterID
+	if p.peersEnabled() {
+		path += "&" + tunnelPeerParam + "=" + url.QueryEscape(p.instance.Endpoint)
+	}
+	return path
+}
+
+// pickupQuotaHandler charges each revdial pickup connection to its tunnel's
+// quota before handing it to next. Pickups for another replica's tunnel are
+// forwarded to it; pickups for unknown tunnels are refused outright: revdial
+// would otherwise hold them open until their dialer closes.
+func (p *Server) pickupQuotaHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l := p.tunnelLimits.get(r.URL.Query().Get(tunnelQuotaParam))
+		if l == nil {
+			if peer := r.URL.Query().Get(tunnelPeerParam); p.isPeer(r.Context(), peer) &&
+				p.forwardToPeer(w, r, peer, AgentIngressMount, r.URL.RawQuery) {
+				return
+			}
+			http.Error(w, "unknown tunnel", http.StatusNotFound)
+			return
+		}
+		if !l.message(1) {
+			http.Error(w, "tunnel quota exceeded", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(&quotaResponseWriter{ResponseWriter: w, limiter: l}, r)
+	})
+}
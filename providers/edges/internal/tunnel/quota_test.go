@@ -0,0 +1,131 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQuotaThrottlesReceive(t *testing.T) {
+	l := newTunnelLimiter(Quota{ReceiveBytesPerSecond: 1000})
+	agent, hub := net.Pipe()
+	defer agent.Close() //nolint:errcheck
+	conn := l.wrap(hub)
+	defer conn.Close() //nolint:errcheck
+
+	go func() { _, _ = agent.Write(make([]byte, 1500)) }()
+
+	start := time.Now()
+	if _, err := io.ReadFull(conn, make([]byte, 1500)); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	// The first 1000 bytes are the burst; the other 500 take half a second.
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Fatalf("read 1500 bytes at 1000 B/s in %s, want throttling", elapsed)
+	}
+	if v := l.Violation(); v != "" {
+		t.Fatalf("throttled tunnel recorded a violation: %s", v)
+	}
+}
+
+func TestQuotaDisconnectsOnSendBacklog(t *testing.T) {
+	l := newTunnelLimiter(Quota{SendBytesPerSecond: 100, MaxThrottle: 100 * time.Millisecond})
+	agent, hub := net.Pipe()
+	defer agent.Close() //nolint:errcheck
+	go func() { _, _ = io.Copy(io.Discard, agent) }()
+	conn := l.wrap(hub)
+
+	if _, err := conn.Write(make([]byte, 100)); err != nil {
+		t.Fatalf("write within burst: %v", err)
+	}
+	// The next 100 bytes would be held back for a second.
+	if _, err := conn.Write(make([]byte, 100)); !errors.Is(err, errQuotaExceeded) {
+		t.Fatalf("write over backlog = %v, want %v", err, errQuotaExceeded)
+	}
+	if v := l.Violation(); !strings.Contains(v, "send") {
+		t.Fatalf("violation = %q, want a send backlog", v)
+	}
+	if _, err := hub.Write([]byte("x")); err == nil {
+		t.Fatal("underlying connection still open after the violation")
+	}
+}
+
+func TestQuotaDisconnectsOnMessageFlood(t *testing.T) {
+	l := newTunnelLimiter(Quota{MessagesPerSecond: 1, MessageBurst: 2})
+	agent, hub := net.Pipe()
+	defer agent.Close() //nolint:errcheck
+	control := &messageConn{Conn: l.wrap(hub), limiter: l}
+
+	go func() { _, _ = agent.Write([]byte("{\"command\":\"pong\"}\n{\"command\":\"pong\"}\n")) }()
+	if _, err := io.ReadFull(control, make([]byte, 38)); err != nil {
+		t.Fatalf("reading messages within burst: %v", err)
+	}
+
+	go func() { _, _ = agent.Write([]byte("{\"command\":\"pong\"}\n")) }()
+	if _, err := control.Read(make([]byte, 19)); !errors.Is(err, errQuotaExceeded) {
+		t.Fatalf("reading a message over the rate = %v, want %v", err, errQuotaExceeded)
+	}
+	if v := l.Violation(); !strings.Contains(v, "messages per second") {
+		t.Fatalf("violation = %q, want a message rate", v)
+	}
+}
+
+func TestPickupQuotaHandler(t *testing.T) {
+	s := testServer("")
+	s.agentPickupPath = "/services/providers/edges/agent/proxy"
+	s.tunnelLimits = newTunnelLimiters()
+	l := newTunnelLimiter(Quota{MessagesPerSecond: 1, MessageBurst: 1})
+	id := s.tunnelLimits.add(l)
+
+	var served int
+	h := s.pickupQuotaHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := w.(*quotaResponseWriter); !ok {
+			t.Errorf("pickup served with %T, want *quotaResponseWriter", w)
+		}
+		served++
+	}))
+	pickup := func(path string) int {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path+"&revdial.dialer=abc", nil))
+		return rec.Code
+	}
+
+	if code := pickup("/proxy?" + tunnelQuotaParam + "=unknown"); code != http.StatusNotFound {
+		t.Fatalf("pickup for unknown tunnel = %d, want %d", code, http.StatusNotFound)
+	}
+	path := s.pickupPath(id)
+	if !strings.HasPrefix(path, s.agentPickupPath+"?"+tunnelQuotaParam+"=") {
+		t.Fatalf("pickup path = %q", path)
+	}
+	query := path[strings.Index(path, "?"):]
+	if code := pickup("/proxy" + query); code != http.StatusOK || served != 1 {
+		t.Fatalf("first pickup = %d (served %d), want it served", code, served)
+	}
+	if code := pickup("/proxy" + query); code != http.StatusTooManyRequests || served != 1 {
+		t.Fatalf("pickup over the message rate = %d (served %d), want %d", code, served, http.StatusTooManyRequests)
+	}
+	if l.Violation() == "" {
+		t.Fatal("pickup flood recorded no violation")
+	}
+}
@@ -19,14 +19,18 @@ package tunnel
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
+	"os"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 
 	"github.com/faroshq/provider-edges/internal/events"
 	"github.com/faroshq/provider-edges/internal/kcpurl"
+	"github.com/faroshq/provider-sdk/revdial"
 )
 
 // KindConfig declares one connectable kind the tunnel serves. All kinds a
@@ -45,6 +49,13 @@ type KindConfig struct {
 // package-level authorize (auth.go).
 type authorizeFnType func(ctx context.Context, tenantCfg, kcpConfig *rest.Config, token, clusterName, verb, group, resource, name string) error
 
+// reviewTokenFnType and reviewAccessFnType are authorize's two halves, used
+// apart where the identity outlives the request (fleet_requester.go).
+type (
+	reviewTokenFnType  func(ctx context.Context, tenantCfg, kcpConfig *rest.Config, token, clusterName string) (Requester, error)
+	reviewAccessFnType func(ctx context.Context, tenantCfg *rest.Config, caller Requester, verb, group, resource, name string) error
+)
+
 // TenantConfigGetter returns a *rest.Config scoped to the given kcp tenant
 // logical cluster, able to read/write the Edge resources (and their
 // kedge-system Secrets) the provider owns in that workspace.
@@ -78,9 +89,17 @@ type Server struct {
 	version string
 
 	// edgeConnManager is the tunnel registry: agent-ingress writes, edgeproxy
-	// reads. Single-replica invariant applies (see connman.go).
+	// reads. It holds this replica's tunnels only (see connman.go).
 	edgeConnManager *ConnManager
 
+	// drains refuses and closes proxied sessions to cordoned and deleting
+	// edges (drain.go).
+	drains *drainRegistry
+
+	// edgeCache mirrors the edges and Secrets SSH dials read from tenant
+	// workspaces (edge_cache.go).
+	edgeCache *edgeCache
+
 	// kcpConfig is the provider's kcp credential. Used for delegated agent-token
 	// authorization (TokenReview/SAR via a tenant-workspace RBAC grant) and, as a
 	// fallback when tenantConfig is unset, for direct tenant reads/writes.
@@ -114,8 +133,51 @@ type Server struct {
 	// through the hub. Empty disables URL stamping.
 	edgeProxyPublicPath string
 
+	// urlSigningKey is the HMAC key for signed edge URLs (signed_url.go).
+	urlSigningKey []byte
+
+	// instance identifies this replica in the status.tunnel affinity lease of
+	// every edge whose tunnel it terminates (tunnel_affinity.go).
+	instance Instance
+
+	// peerService resolves to the other replicas' addresses; empty disables
+	// forwarding to them (peer.go). lookupPeers resolves it and
+	// peerTransport carries forwarded requests.
+	peerService   string
+	lookupPeers   func(ctx context.Context, host string) ([]string, error)
+	peerTransport http.RoundTripper
+
+	// quota bounds each agent tunnel's traffic; tunnelLimits holds the live
+	// tunnels' limiters, keyed by the ID in their pickup path (quota.go).
+	quota        Quota
+	tunnelLimits *tunnelLimiters
+
+	// keepalive tunes dead-peer detection on agent tunnels.
+	keepalive revdial.Keepalive
+
+	// concurrency caps the proxied requests in flight to each edge
+	// (concurrency.go).
+	concurrency *edgeLimiters
+
+	// stepUp guards interactive SSH behind a recent sign-in (stepup.go).
+	stepUp StepUp
+
+	// hubVersion yields the hub release agent versions are checked against;
+	// agentSkew says what to do with an agent outside the skew policy
+	// (version_skew.go).
+	hubVersion func(context.Context) (string, error)
+	agentSkew  AgentSkewPolicy
+
+	// agentCA signs agent certificates; with requireAgentCertificate, agent
+	// tunnels must authenticate with one (agent_certs.go).
+	agentCA                 *AgentCA
+	requireAgentCertificate bool
+
 	// authorizeFn performs delegated authn/authz against kcp; injectable for tests.
 	authorizeFn authorizeFnType
+	// reviewTokenFn and reviewAccessFn are its halves; injectable for tests.
+	reviewTokenFn  reviewTokenFnType
+	reviewAccessFn reviewAccessFnType
 
 	// eventStore, when set, backs the read side of edge event tools (the UniFi
 	// Protect `events` MCP tool). The write side (the WebSocket subscribers) is
@@ -149,7 +211,41 @@ type Config struct {
 	StaticTokens        []string
 	HubExternalURL      string
 	HubInternalURL      string
-	Logger              klog.Logger
+	// URLSigningKey is the HMAC key for signed edge URLs. Every replica must
+	// share it. When empty a random key is generated, so signed URLs do not
+	// survive a restart and only verify on the replica that minted them.
+	URLSigningKey []byte
+	// Instance identifies this replica in edges' status.tunnel. An empty
+	// Name defaults to the hostname (the pod name in Kubernetes).
+	Instance Instance
+	// PeerService is the DNS name of a headless Service resolving to every
+	// replica. When set, with Instance.Endpoint, requests for a tunnel
+	// another replica holds are forwarded to it (peer.go). Empty means a
+	// single replica.
+	PeerService string
+	// Quota bounds each agent tunnel's bytes and message rate. Nil applies
+	// DefaultQuota; a zero Quota disables enforcement.
+	Quota *Quota
+	// Concurrency caps the proxied requests in flight to each edge. Nil
+	// applies DefaultConcurrency; a zero MaxInFlight disables the cap.
+	Concurrency *Concurrency
+	// StepUp requires a recent sign-in or a second factor for interactive
+	// SSH. The zero value disables it.
+	StepUp StepUp
+	// AgentSkew says what to do with an agent whose version is outside the
+	// skew policy. Empty means AgentSkewWarn.
+	AgentSkew AgentSkewPolicy
+	// AgentCA signs agent certificates requested through the certificate
+	// subresource. Nil disables certificate join.
+	AgentCA *AgentCA
+	// RequireAgentCertificate refuses agent tunnels that do not authenticate
+	// with an agent certificate, except for static tokens. It needs AgentCA.
+	RequireAgentCertificate bool
+	// Keepalive tunes dead-peer detection on agent tunnels. The zero value
+	// keeps revdial's defaults. Its TCPUserTimeout is not applied here: set
+	// it on the listener serving AgentIngressHandler.
+	Keepalive revdial.Keepalive
+	Logger    klog.Logger
 }
 
 // New constructs the tunnel Server for one or more connectable kinds.
@@ -168,24 +264,77 @@ func New(cfg Config) (*Server, error) {
 		}
 		kinds[k.GVR.Resource] = k
 	}
+	signingKey := cfg.URLSigningKey
+	if len(signingKey) == 0 {
+		var err error
+		if signingKey, err = newSigningKey(); err != nil {
+			return nil, err
+		}
+	}
+	instance := cfg.Instance
+	if instance.Name == "" {
+		instance.Name, _ = os.Hostname()
+	}
+	quota := DefaultQuota()
+	if cfg.Quota != nil {
+		quota = *cfg.Quota
+	}
+	if err := quota.Validate(); err != nil {
+		return nil, err
+	}
+	concurrency := DefaultConcurrency()
+	if cfg.Concurrency != nil {
+		concurrency = *cfg.Concurrency
+	}
+	if err := concurrency.Validate(); err != nil {
+		return nil, err
+	}
+	agentSkew := cfg.AgentSkew
+	if agentSkew == "" {
+		agentSkew = AgentSkewWarn
+	}
+	if err := agentSkew.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.RequireAgentCertificate && cfg.AgentCA == nil {
+		return nil, fmt.Errorf("tunnel: requiring agent certificates needs an agent CA")
+	}
 	tokenSet := make(map[string]struct{}, len(cfg.StaticTokens))
 	for _, t := range cfg.StaticTokens {
 		tokenSet[t] = struct{}{}
 	}
-	return &Server{
-		kinds:               kinds,
-		group:               group,
-		version:             version,
-		edgeConnManager:     NewConnManager(),
-		kcpConfig:           cfg.KCPConfig,
-		staticTokens:        tokenSet,
-		hubExternalURL:      cfg.HubExternalURL,
-		hubInternalURL:      cfg.HubInternalURL,
-		agentPickupPath:     cfg.AgentPickupPath,
-		edgeProxyPublicPath: cfg.EdgeProxyPublicPath,
-		authorizeFn:         authorize,
-		logger:              cfg.Logger.WithName("edge-tunnel"),
-	}, nil
+	s := &Server{
+		kinds:                   kinds,
+		group:                   group,
+		version:                 version,
+		edgeConnManager:         NewConnManager(),
+		drains:                  newDrainRegistry(),
+		kcpConfig:               cfg.KCPConfig,
+		staticTokens:            tokenSet,
+		hubExternalURL:          cfg.HubExternalURL,
+		hubInternalURL:          cfg.HubInternalURL,
+		agentPickupPath:         cfg.AgentPickupPath,
+		edgeProxyPublicPath:     cfg.EdgeProxyPublicPath,
+		urlSigningKey:           signingKey,
+		instance:                instance,
+		peerService:             cfg.PeerService,
+		lookupPeers:             net.DefaultResolver.LookupHost,
+		peerTransport:           newPeerTransport(),
+		quota:                   quota,
+		tunnelLimits:            newTunnelLimiters(),
+		stepUp:                  cfg.StepUp,
+		agentSkew:               agentSkew,
+		agentCA:                 cfg.AgentCA,
+		requireAgentCertificate: cfg.RequireAgentCertificate,
+		keepalive:               cfg.Keepalive,
+		concurrency:             newEdgeLimiters(concurrency),
+		authorizeFn:             authorize,
+		reviewTokenFn:           reviewToken,
+		reviewAccessFn:          reviewAccess,
+		logger:                  cfg.Logger.WithName("edge-tunnel"),
+	}
+	s.edgeCache = newEdgeCache(s.tenantClient, edgeCacheTTL)
+	return s, nil
 }
 
 // SetTenantConfigGetter wires the cross-workspace tenant config source (the
@@ -211,6 +360,16 @@ func (p *Server) tenantConfigFor(ctx context.Context, cluster string) (*rest.Con
 	return cfg, nil
 }
 
+// tenantClient returns a dynamic client for the given tenant logical cluster
+// (see tenantConfigFor).
+func (p *Server) tenantClient(ctx context.Context, cluster string) (dynamic.Interface, error) {
+	cfg, err := p.tenantConfigFor(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+	return dynamic.NewForConfig(cfg)
+}
+
 // gvrForResource resolves a URL resource segment to its GVR + Kind. ok is false
 // when the resource is not one of the kinds this Server serves.
 func (p *Server) gvrForResource(resource string) (gvr schema.GroupVersionResource, kind string, ok bool) {
@@ -221,16 +380,20 @@ func (p *Server) gvrForResource(resource string) (gvr schema.GroupVersionResourc
 	return k.GVR, k.Kind, true
 }
 
-// Start launches background maintenance (the stale-tunnel sweeper). Call once;
-// the goroutine exits when stop is closed.
+// Start launches background maintenance (the stale-tunnel sweeper and the
+// edge cache's expiry). Call once; the goroutines exit when stop is closed.
 func (s *Server) Start(stop <-chan struct{}) {
 	s.edgeConnManager.StartSweeper(stop)
+	go s.edgeCache.run(stop)
 }
 
 // ConnManager exposes the shared tunnel registry so the provider's edge
 // controllers can check whether a given edge tunnel is live.
 func (s *Server) ConnManager() *ConnManager { return s.edgeConnManager }
 
+// InstanceName returns this replica's name as published in status.tunnel.
+func (s *Server) InstanceName() string { return s.instance.Name }
+
 // AgentIngressHandler terminates agent reverse tunnels. Mounted (behind the hub
 // backend proxy) at /services/providers/edges/agent/. Path after
 // StripPrefix: /{cluster}/apis/edges.kedge.faros.sh/v1alpha1/{kubernetesclusters|linuxservers}/{name}/proxy
@@ -241,7 +404,7 @@ func (s *Server) AgentIngressHandler() http.Handler {
 
 // EdgeProxyHandler serves the consumer data-plane subresources. Mounted (behind
 // the hub backend proxy) at /services/providers/edges/edgeproxy/.
-// Path after StripPrefix: /clusters/{cluster}/apis/edges.kedge.faros.sh/v1alpha1/{kubernetesclusters|linuxservers}/{name}/{k8s|ssh}.
+// Path after StripPrefix: /clusters/{cluster}/apis/edges.kedge.faros.sh/v1alpha1/{kubernetesclusters|linuxservers}/{name}/{k8s|ssh|signurl}.
 func (s *Server) EdgeProxyHandler() http.Handler {
 	return s.buildEdgesProxyHandler()
 }
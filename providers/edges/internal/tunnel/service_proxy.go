@@ -142,9 +142,15 @@ func (p *Server) serveService(w http.ResponseWriter, r *http.Request, token, clu
 	ctx := r.Context()
 	logger := klog.FromContext(ctx).WithName("edgeservice-proxy")
 
-	// Delegated authorization (static tokens bypass, as in buildEdgesProxyHandler).
+	// Delegated authorization (static tokens bypass, and everything else fails
+	// closed without kcp, as in buildEdgesProxyHandler).
 	_, isStaticToken := p.staticTokens[token]
-	if !isStaticToken && p.kcpConfig != nil {
+	if !isStaticToken {
+		if p.kcpConfig == nil {
+			logger.Info("edgeservice authorization unavailable: no kcp config", "cluster", cluster, "name", name)
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
 		tenantCfg, err := p.tenantConfigFor(ctx, cluster)
 		if err != nil {
 			logger.Error(err, "edgeservice authorization: resolving tenant config failed", "cluster", cluster, "name", name)
@@ -169,6 +175,10 @@ func (p *Server) serveService(w http.ResponseWriter, r *http.Request, token, clu
 	key := edgeConnKey(svc.connResource(), cluster, svc.Spec.EdgeRef.Name)
 	dialer, found := p.edgeConnManager.Load(key)
 	if !found {
+		if peer := p.tunnelPeer(ctx, svc.connResource(), cluster, svc.Spec.EdgeRef.Name); peer != "" &&
+			p.forwardToPeer(w, r, peer, EdgeProxyMount, r.URL.RawQuery) {
+			return
+		}
 		logger.Info("no active tunnel for edge", "cluster", cluster, "edge", svc.Spec.EdgeRef.Name)
 		http.Error(w, "upstream unavailable", http.StatusBadGateway)
 		return
@@ -275,7 +285,10 @@ func (p *Server) serviceHandleUpgrade(ctx context.Context, w http.ResponseWriter
 	errc := make(chan error, 2)
 	go func() { _, e := io.Copy(deviceConn, clientConn); errc <- e }()
 	go func() { _, e := io.Copy(clientConn, deviceConn); errc <- e }()
-	<-errc
+	select {
+	case <-errc:
+	case <-ctx.Done():
+	}
 }
 
 // userClusterConfig returns a rest.Config scoped to a tenant workspace that
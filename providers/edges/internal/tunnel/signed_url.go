@@ -0,0 +1,222 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Signed URLs grant bearer-less, time-limited access to one subresource of one
+// edge — e.g. a 10-minute read-only k8s URL handed to a CI job that should not
+// hold the user's credentials. They are minted by an authorized caller through
+// the signurl subresource and verified statelessly: the URL carries its own
+// scope and expiry, authenticated with an HMAC over the provider's signing key.
+// Nothing is stored, so a URL cannot be revoked before it expires other than
+// by rotating the key.
+
+const (
+	// signURLSubresource is the mint endpoint on an edge:
+	// POST .../{kubernetesclusters|linuxservers}/{name}/signurl.
+	signURLSubresource = "signurl"
+
+	// Query parameters carried by a signed URL. Prefixed so they cannot collide
+	// with Kubernetes API query parameters on the k8s pass-through.
+	signedURLExpiresParam   = "kedge-expires"
+	signedURLAccessParam    = "kedge-access"
+	signedURLSignatureParam = "kedge-signature"
+
+	// signedURLAccessRead allows only non-mutating requests (GET/HEAD, no
+	// exec/attach/port-forward upgrades). signedURLAccessWrite allows anything
+	// the subresource serves.
+	signedURLAccessRead  = "read"
+	signedURLAccessWrite = "write"
+
+	// defaultSignedURLTTL is used when the mint request names no TTL.
+	defaultSignedURLTTL = 10 * time.Minute
+	// maxSignedURLTTL caps how long a minted URL may live.
+	maxSignedURLTTL = 24 * time.Hour
+)
+
+// signURLRequest is the JSON body of a mint request.
+type signURLRequest struct {
+	// Subresource is the edge subresource the URL grants ("k8s" or "ssh").
+	// Defaults to the kind's natural subresource.
+	Subresource string `json:"subresource,omitempty"`
+	// TTL is a Go duration string ("10m"); defaults to defaultSignedURLTTL.
+	TTL string `json:"ttl,omitempty"`
+	// ReadOnly restricts the URL to non-mutating requests. Only valid for k8s.
+	ReadOnly bool `json:"readOnly,omitempty"`
+}
+
+// signURLResponse is returned by the mint endpoint.
+type signURLResponse struct {
+	// URL is the signed URL. Absolute when the provider knows the hub's
+	// external URL, otherwise a path relative to the hub.
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// newSigningKey returns a random 32-byte HMAC key, used when no key is
+// configured. URLs signed with it do not survive a provider restart.
+func newSigningKey() ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generating URL signing key: %w", err)
+	}
+	return key, nil
+}
+
+// signEdgeProxyURL returns the query parameters that authorize access to the
+// given edge subresource until expires.
+func signEdgeProxyURL(key []byte, cluster, resource, name, subresource, access string, expires time.Time) url.Values {
+	exp := strconv.FormatInt(expires.Unix(), 10)
+	v := url.Values{}
+	v.Set(signedURLExpiresParam, exp)
+	v.Set(signedURLAccessParam, access)
+	v.Set(signedURLSignatureParam, signedURLMAC(key, cluster, resource, name, subresource, access, exp))
+	return v
+}
+
+// signedURLMAC computes the URL signature. Every field is part of the MAC, so a
+// URL cannot be re-pointed at another edge, subresource or workspace, nor have
+// its access widened or its expiry extended.
+func signedURLMAC(key []byte, cluster, resource, name, subresource, access, expires string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strings.Join([]string{cluster, resource, name, subresource, access, expires}, "\n")))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// isSignedRequest reports whether r carries a signed-URL signature.
+func isSignedRequest(r *http.Request) bool {
+	return r.URL.Query().Get(signedURLSignatureParam) != ""
+}
+
+// verifySignedRequest checks r's signature against the addressed edge
+// subresource, its expiry against now, and its method against the granted
+// access. On success the signed-URL parameters are removed from r.URL so they
+// are not forwarded to the edge.
+func verifySignedRequest(key []byte, r *http.Request, cluster, resource, name, subresource string, now time.Time) error {
+	q := r.URL.Query()
+	exp := q.Get(signedURLExpiresParam)
+	access := q.Get(signedURLAccessParam)
+	sig := q.Get(signedURLSignatureParam)
+
+	expUnix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s", signedURLExpiresParam)
+	}
+	want := signedURLMAC(key, cluster, resource, name, subresource, access, exp)
+	if !hmac.Equal([]byte(sig), []byte(want)) {
+		return fmt.Errorf("signature mismatch")
+	}
+	if now.After(time.Unix(expUnix, 0)) {
+		return fmt.Errorf("signed URL expired at %s", time.Unix(expUnix, 0).UTC().Format(time.RFC3339))
+	}
+	switch access {
+	case signedURLAccessWrite:
+	case signedURLAccessRead:
+		if (r.Method != http.MethodGet && r.Method != http.MethodHead) || isUpgradeRequest(r) {
+			return fmt.Errorf("signed URL is read-only; %s not allowed", r.Method)
+		}
+	default:
+		return fmt.Errorf("unknown access %q", access)
+	}
+
+	q.Del(signedURLExpiresParam)
+	q.Del(signedURLAccessParam)
+	q.Del(signedURLSignatureParam)
+	r.URL.RawQuery = q.Encode()
+	return nil
+}
+
+// serveSignURL mints a signed URL for an edge subresource. The caller has
+// already been authorized for "proxy" on the edge, which is exactly what the
+// URL grants (or less, when read-only).
+func (p *Server) serveSignURL(w http.ResponseWriter, r *http.Request, cluster, resource, name string) {
+	if r.Method != http.MethodPost {
+		writeProblem(w, http.StatusMethodNotAllowed, reasonMethodNotAllowed, "method not allowed")
+		return
+	}
+	gvr, _, _ := p.gvrForResource(resource)
+
+	var req signURLRequest
+	if r.Body != nil && r.ContentLength != 0 {
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+			writeProblem(w, http.StatusBadRequest, reasonBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+	}
+	if req.Subresource == "" {
+		req.Subresource = defaultSubresource(gvr.Resource)
+	}
+	switch req.Subresource {
+	case "k8s", "ssh":
+	default:
+		writeProblem(w, http.StatusBadRequest, reasonBadRequest, fmt.Sprintf("cannot sign subresource %q (want k8s or ssh)", req.Subresource))
+		return
+	}
+	if req.ReadOnly && req.Subresource == "ssh" {
+		writeProblem(w, http.StatusBadRequest, reasonBadRequest, "read-only URLs are only supported for the k8s subresource")
+		return
+	}
+	// A signed SSH URL opens interactive sessions without a token, so the
+	// step-up check happens here instead.
+	if req.Subresource == "ssh" && !p.requireStepUp(w, extractBearerToken(r), "signing an SSH URL") {
+		return
+	}
+	ttl := defaultSignedURLTTL
+	if req.TTL != "" {
+		d, err := time.ParseDuration(req.TTL)
+		if err != nil || d <= 0 {
+			writeProblem(w, http.StatusBadRequest, reasonBadRequest, fmt.Sprintf("invalid ttl %q", req.TTL))
+			return
+		}
+		ttl = d
+	}
+	if ttl > maxSignedURLTTL {
+		writeProblem(w, http.StatusBadRequest, reasonBadRequest, fmt.Sprintf("ttl %s exceeds the maximum of %s", ttl, maxSignedURLTTL))
+		return
+	}
+
+	access := signedURLAccessWrite
+	if req.ReadOnly {
+		access = signedURLAccessRead
+	}
+	expires := time.Now().Add(ttl).Truncate(time.Second)
+	query := signEdgeProxyURL(p.urlSigningKey, cluster, resource, name, req.Subresource, access, expires)
+
+	u := p.edgeProxyPath(gvr, cluster, name, req.Subresource) + "?" + query.Encode()
+	if p.hubExternalURL != "" {
+		u = strings.TrimRight(p.hubExternalURL, "/") + u
+	}
+
+	p.logger.Info("minted signed edge URL", "cluster", cluster, "resource", resource, "name", name,
+		"subresource", req.Subresource, "access", access, "expiresAt", expires)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(signURLResponse{URL: u, ExpiresAt: expires.UTC()})
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestVerifySignedRequest(t *testing.T) {
+	key := []byte("test-signing-key")
+	now := time.Unix(1_800_000_000, 0)
+	const (
+		cluster  = "11tcw27t4rdtnacy"
+		resource = "kubernetesclusters"
+		name     = "edge-1"
+	)
+	sign := func(subresource, access string, expires time.Time) string {
+		return signEdgeProxyURL(key, cluster, resource, name, subresource, access, expires).Encode()
+	}
+
+	cases := []struct {
+		name        string
+		method      string
+		query       string
+		upgrade     bool
+		edge        string
+		subresource string
+		wantErr     bool
+	}{
+		{
+			name:        "read URL allows GET",
+			method:      http.MethodGet,
+			query:       sign("k8s", signedURLAccessRead, now.Add(10*time.Minute)),
+			edge:        name,
+			subresource: "k8s",
+		},
+		{
+			name:        "read URL rejects POST",
+			method:      http.MethodPost,
+			query:       sign("k8s", signedURLAccessRead, now.Add(10*time.Minute)),
+			edge:        name,
+			subresource: "k8s",
+			wantErr:     true,
+		},
+		{
+			name:        "read URL rejects upgrades",
+			method:      http.MethodGet,
+			query:       sign("k8s", signedURLAccessRead, now.Add(10*time.Minute)),
+			upgrade:     true,
+			edge:        name,
+			subresource: "k8s",
+			wantErr:     true,
+		},
+		{
+			name:        "write URL allows POST",
+			method:      http.MethodPost,
+			query:       sign("k8s", signedURLAccessWrite, now.Add(10*time.Minute)),
+			edge:        name,
+			subresource: "k8s",
+		},
+		{
+			name:        "expired",
+			method:      http.MethodGet,
+			query:       sign("k8s", signedURLAccessRead, now.Add(-time.Second)),
+			edge:        name,
+			subresource: "k8s",
+			wantErr:     true,
+		},
+		{
+			name:        "other edge",
+			method:      http.MethodGet,
+			query:       sign("k8s", signedURLAccessRead, now.Add(10*time.Minute)),
+			edge:        "edge-2",
+			subresource: "k8s",
+			wantErr:     true,
+		},
+		{
+			name:        "other subresource",
+			method:      http.MethodGet,
+			query:       sign("k8s", signedURLAccessWrite, now.Add(10*time.Minute)),
+			edge:        name,
+			subresource: "ssh",
+			wantErr:     true,
+		},
+		{
+			name:        "access widened",
+			method:      http.MethodPost,
+			query:       sign("k8s", signedURLAccessRead, now.Add(10*time.Minute)) + "&" + signedURLAccessParam + "=" + signedURLAccessWrite,
+			edge:        name,
+			subresource: "k8s",
+			wantErr:     true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(tc.method, "/clusters/"+cluster+"/apis/edges.kedge.faros.sh/v1alpha1/"+resource+"/"+tc.edge+"/"+tc.subresource+"/api/v1/pods?limit=5&"+tc.query, nil)
+			if tc.upgrade {
+				r.Header.Set("Connection", "Upgrade")
+				r.Header.Set("Upgrade", "SPDY/3.1")
+			}
+			if !isSignedRequest(r) {
+				t.Fatal("isSignedRequest() = false for a signed URL")
+			}
+			err := verifySignedRequest(key, r, cluster, resource, tc.edge, tc.subresource, now)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("verifySignedRequest() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if err == nil && r.URL.RawQuery != "limit=5" {
+				t.Fatalf("signed-URL params not stripped: %q", r.URL.RawQuery)
+			}
+		})
+	}
+}
+
+func TestVerifySignedRequestWrongKey(t *testing.T) {
+	now := time.Unix(1_800_000_000, 0)
+	q := signEdgeProxyURL([]byte("key-a"), "c", "linuxservers", "n", "ssh", signedURLAccessWrite, now.Add(time.Minute))
+	r := httptest.NewRequest(http.MethodGet, "/x?"+q.Encode(), nil)
+	if err := verifySignedRequest([]byte("key-b"), r, "c", "linuxservers", "n", "ssh", now); err == nil {
+		t.Fatal("expected a URL signed with another key to be rejected")
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	gossh "golang.org/x/crypto/ssh"
+	"k8s.io/klog/v2"
+)
+
+// ExecSSH runs command on the connected server edge name in cluster over the
+// same path as the ssh subresource's exec mode (reverse tunnel → agent /ssh →
+// the edge's sshd), writing combined stdout+stderr to output. It returns the
+// command's exit code; err is non-nil only when the command could not be run
+// to completion (edge not connected or draining, SSH failure, ctx done).
+//
+// Credentials are resolved as for a caller without an identity, so edges with
+// sshUserMapping=identity are refused.
+func (p *Server) ExecSSH(ctx context.Context, cluster, name, command string, output io.Writer) (int, error) {
+	logger := klog.FromContext(ctx).WithValues("cluster", cluster, "edge", name)
+
+	const resource = "linuxservers"
+	gvr, _, ok := p.gvrForResource(resource)
+	if !ok {
+		return -1, fmt.Errorf("this server does not serve %s", resource)
+	}
+	key := edgeConnKey(resource, cluster, name)
+	if drain, ok := p.draining(key); ok {
+		return -1, fmt.Errorf("edge is draining (%s)", drain.reason)
+	}
+	dialer, ok := p.edgeConnManager.Load(key)
+	if !ok {
+		// Commands run where the controller does; they are not forwarded.
+		if peer := p.tunnelPeer(ctx, resource, cluster, name); peer != "" {
+			return -1, fmt.Errorf("edge tunnel is held by another replica (%s)", peer)
+		}
+		return -1, errors.New("edge is not connected")
+	}
+	ctx, untrack := p.trackSession(ctx, key)
+	defer untrack()
+
+	creds, err := p.fetchSSHCredentials(ctx, cluster, name, "", gvr, logger)
+	if err != nil {
+		return -1, fmt.Errorf("fetching SSH credentials: %w", err)
+	}
+	var hostKey string
+	if creds != nil {
+		hostKey = creds.SSHHostKey
+	}
+
+	deviceConn, err := dialer.Dial(ctx)
+	if err != nil {
+		return -1, fmt.Errorf("dialing edge agent: %w", err)
+	}
+	sshConn, err := openAgentSSHTunnel(ctx, deviceConn)
+	if err != nil {
+		deviceConn.Close() //nolint:errcheck
+		return -1, fmt.Errorf("opening SSH tunnel: %w", err)
+	}
+	sshClient, err := newSSHClient(ctx, sshConn, creds, hostKey, logger)
+	if err != nil {
+		sshConn.Close() //nolint:errcheck
+		return -1, err
+	}
+	defer sshClient.Close() //nolint:errcheck
+
+	session, err := sshClient.NewSession()
+	if err != nil {
+		return -1, fmt.Errorf("creating SSH session: %w", err)
+	}
+	defer session.Close() //nolint:errcheck
+	session.Stdout = output
+	session.Stderr = output
+
+	// Run blocks until the command exits; closing the client on ctx expiry is
+	// what unblocks it on a timeout.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			sshClient.Close() //nolint:errcheck
+		case <-done:
+		}
+	}()
+
+	err = session.Run(command)
+	if err == nil {
+		return 0, nil
+	}
+	if ctx.Err() != nil {
+		return -1, ctx.Err()
+	}
+	var exitErr *gossh.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitStatus(), nil
+	}
+	return -1, fmt.Errorf("running command: %w", err)
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultStepUpAMR are the RFC 8176 amr values accepted as a second factor
+// when StepUp.AMR is empty. Mirrors the hub's auth.DefaultStepUpAMR.
+var DefaultStepUpAMR = []string{"mfa", "hwk", "swk", "otp", "sc"}
+
+// StepUp guards interactive SSH sessions (and minting signed SSH URLs, which
+// would otherwise sidestep it) behind a recent sign-in or a second factor —
+// the provider half of the hub's step-up policy, which guards edge deletion.
+// A user's OIDC ID token satisfies it when its auth_time is within MaxAge or
+// its amr claim names one of AMR. A zero MaxAge disables it.
+//
+// Only OIDC sign-ins are subject to it: static tokens and kcp ServiceAccount
+// tokens are not interactive users and cannot re-authenticate.
+type StepUp struct {
+	MaxAge time.Duration
+	AMR    []string
+}
+
+// stepUpClaims are the ID token claims StepUp reads. auth_time is a
+// NumericDate, which may carry a fraction.
+type stepUpClaims struct {
+	AuthTime float64  `json:"auth_time"`
+	AMR      []string `json:"amr"`
+}
+
+// satisfied reports whether token meets the policy at now. The token must
+// already be authenticated (authorizeFn): its claims are read, not verified.
+func (s StepUp) satisfied(token string, now time.Time) bool {
+	if s.MaxAge <= 0 {
+		return true
+	}
+	if _, isSA := parseServiceAccountToken(token); isSA {
+		return true
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return false
+	}
+	var claims stepUpClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return false
+	}
+	amr := s.AMR
+	if len(amr) == 0 {
+		amr = DefaultStepUpAMR
+	}
+	for _, method := range claims.AMR {
+		if slices.Contains(amr, method) {
+			return true
+		}
+	}
+	if claims.AuthTime <= 0 {
+		return false
+	}
+	return now.Sub(time.Unix(int64(claims.AuthTime), 0)) <= s.MaxAge
+}
+
+// requireStepUp reports whether the caller holding token may perform
+// operation, writing the RFC 9470 step-up challenge when not: the
+// WWW-Authenticate header plus a StepUpRequired problem+json body, as the hub
+// answers for edge deletion. Static tokens are exempt.
+func (p *Server) requireStepUp(w http.ResponseWriter, token, operation string) bool {
+	if _, isStaticToken := p.staticTokens[token]; isStaticToken {
+		return true
+	}
+	if p.stepUp.satisfied(token, time.Now()) {
+		return true
+	}
+	w.Header().Set("WWW-Authenticate", fmt.Sprintf(
+		`Bearer error="insufficient_user_authentication", error_description="%s requires a recent sign-in", max_age=%d`,
+		operation, int(p.stepUp.MaxAge/time.Second)))
+	writeProblem(w, http.StatusUnauthorized, reasonStepUpRequired,
+		fmt.Sprintf("%s requires signing in within the last %s or a second factor — run 'kedge login --step-up'",
+			operation, p.stepUp.MaxAge))
+	return false
+}
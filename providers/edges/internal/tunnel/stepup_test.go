@@ -0,0 +1,99 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testJWT(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "eyJhbGciOiJSUzI1NiJ9." + base64.RawURLEncoding.EncodeToString(payload) + ".sig"
+}
+
+func TestStepUpSatisfied(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	policy := StepUp{MaxAge: 15 * time.Minute}
+
+	cases := []struct {
+		name  string
+		token string
+		want  bool
+	}{
+		{"recent sign-in", testJWT(t, map[string]any{"auth_time": now.Add(-5 * time.Minute).Unix()}), true},
+		{"stale sign-in", testJWT(t, map[string]any{"auth_time": now.Add(-time.Hour).Unix()}), false},
+		{"no auth_time", testJWT(t, map[string]any{"iat": now.Unix()}), false},
+		{"second factor", testJWT(t, map[string]any{"auth_time": now.Add(-time.Hour).Unix(), "amr": []string{"pwd", "hwk"}}), true},
+		{"password only", testJWT(t, map[string]any{"amr": []string{"pwd"}}), false},
+		{"service account", testJWT(t, map[string]any{"iss": "kubernetes/serviceaccount", "kubernetes.io/serviceaccount/clusterName": "abc"}), true},
+		{"opaque token", "not-a-jwt", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := policy.satisfied(tc.token, now); got != tc.want {
+				t.Fatalf("satisfied = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	if !(StepUp{}).satisfied("not-a-jwt", now) {
+		t.Fatal("disabled policy refused a token")
+	}
+}
+
+func TestRequireStepUpChallenge(t *testing.T) {
+	p := &Server{
+		staticTokens: map[string]struct{}{"dev-token": {}},
+		stepUp:       StepUp{MaxAge: 10 * time.Minute},
+	}
+	stale := testJWT(t, map[string]any{"auth_time": time.Now().Add(-time.Hour).Unix()})
+
+	w := httptest.NewRecorder()
+	if p.requireStepUp(w, stale, "an interactive SSH session") {
+		t.Fatal("stale sign-in allowed")
+	}
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", w.Code)
+	}
+	if got := w.Header().Get("WWW-Authenticate"); !strings.Contains(got, `error="insufficient_user_authentication"`) || !strings.Contains(got, "max_age=600") {
+		t.Fatalf("WWW-Authenticate = %q", got)
+	}
+	if got := w.Header().Get("Content-Type"); got != problemContentType {
+		t.Fatalf("Content-Type = %q, want %q", got, problemContentType)
+	}
+	var body problem
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("body is not JSON: %q: %v", w.Body.String(), err)
+	}
+	if body.Reason != reasonStepUpRequired || body.Status != http.StatusUnauthorized || !strings.Contains(body.Detail, "kedge login --step-up") {
+		t.Fatalf("body = %+v", body)
+	}
+
+	if !p.requireStepUp(httptest.NewRecorder(), "dev-token", "an interactive SSH session") {
+		t.Fatal("static token refused")
+	}
+}
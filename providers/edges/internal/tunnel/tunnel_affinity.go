@@ -0,0 +1,89 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// edgeTunnelLeaseDuration is how long a status.tunnel hint stays valid
+// without renewal. Three missed heartbeats: long enough to ride out a slow
+// status write, short enough that a crashed replica's hint ages out before
+// the LifecycleReconciler would flag the edge anyway.
+const edgeTunnelLeaseDuration = 3 * edgeHeartbeatInterval
+
+// Instance identifies one provider replica. It is published in the
+// status.tunnel affinity lease (edgeapi.TunnelAffinity) of each edge whose
+// tunnel the replica terminates.
+type Instance struct {
+	// Name is the replica identity, normally the pod name.
+	Name string
+	// Zone is the replica's topology zone. Optional.
+	Zone string
+	// Endpoint is the replica's directly reachable host:port, for load
+	// balancers that route by address rather than by pod name. Optional.
+	Endpoint string
+}
+
+// tunnelAffinity returns the status.tunnel lease this replica holds, renewed
+// at renew. acquire is omitted when zero so heartbeat renewals (a merge
+// patch) leave the original acquireTime in place.
+func (p *Server) tunnelAffinity(acquire, renew time.Time) map[string]interface{} {
+	lease := map[string]interface{}{
+		"holder":               p.instance.Name,
+		"renewTime":            renew.UTC().Format(time.RFC3339),
+		"leaseDurationSeconds": int64(edgeTunnelLeaseDuration / time.Second),
+	}
+	if p.instance.Zone != "" {
+		lease["zone"] = p.instance.Zone
+	}
+	if p.instance.Endpoint != "" {
+		lease["endpoint"] = p.instance.Endpoint
+	}
+	if !acquire.IsZero() {
+		lease["acquireTime"] = acquire.UTC().Format(time.RFC3339)
+	}
+	return lease
+}
+
+// releaseTunnelAffinity removes status.tunnel if this replica still holds it.
+// The JSON patch's test op makes the removal conditional: when the agent has
+// already reconnected through another replica, that replica's hint is kept.
+//
+// Best-effort: a failed test (another holder, or no lease at all) is the
+// expected outcome in that case, so errors are logged at V(4) only.
+func (p *Server) releaseTunnelAffinity(ctx context.Context, dynClient dynamic.Interface, gvr schema.GroupVersionResource, cluster, name string) {
+	patch, err := json.Marshal([]map[string]interface{}{
+		{"op": "test", "path": "/status/tunnel/holder", "value": p.instance.Name},
+		{"op": "remove", "path": "/status/tunnel"},
+	})
+	if err != nil {
+		return
+	}
+	if _, err := dynClient.Resource(gvr).Patch(ctx, name,
+		types.JSONPatchType, patch, metav1.PatchOptions{}, "status"); err != nil {
+		p.logger.V(4).Info("releaseTunnelAffinity: lease not released",
+			"cluster", cluster, "edge", name, "holder", p.instance.Name, "err", err)
+	}
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	edgeapi "github.com/faroshq/provider-edges/internal/edgeapi"
+)
+
+func TestTunnelAffinityLease(t *testing.T) {
+	s := testServer("")
+	s.instance = Instance{Name: "edges-7d9f-abcde", Zone: "eu-west-1a", Endpoint: "10.0.3.7:8084"}
+	acquired := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	renewed := acquired.Add(edgeHeartbeatInterval)
+
+	// The lease is written into unstructured status; it must decode into the
+	// typed status.tunnel field.
+	var lease edgeapi.TunnelAffinity
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(s.tunnelAffinity(acquired, renewed), &lease); err != nil {
+		t.Fatalf("decoding lease: %v", err)
+	}
+	if lease.Holder != s.instance.Name || lease.Zone != s.instance.Zone || lease.Endpoint != s.instance.Endpoint {
+		t.Fatalf("lease identity = %+v, want %+v", lease, s.instance)
+	}
+	if lease.AcquireTime == nil || !lease.AcquireTime.Time.Equal(acquired) {
+		t.Fatalf("acquireTime = %v, want %v", lease.AcquireTime, acquired)
+	}
+	if lease.Expired(renewed.Add(edgeTunnelLeaseDuration)) {
+		t.Fatal("lease expired at the end of its duration")
+	}
+	if !lease.Expired(renewed.Add(edgeTunnelLeaseDuration + time.Second)) {
+		t.Fatal("lease not expired past its duration")
+	}
+
+	// Heartbeat renewals merge into the existing lease and must not reset
+	// acquireTime.
+	if _, ok := s.tunnelAffinity(time.Time{}, renewed)["acquireTime"]; ok {
+		t.Fatal("renewal carries acquireTime")
+	}
+}
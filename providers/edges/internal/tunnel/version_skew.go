@@ -0,0 +1,121 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/faroshq/provider-edges/internal/versionskew"
+)
+
+// AgentVersionHeader carries the agent's build version on the tunnel upgrade
+// request.
+const AgentVersionHeader = "X-Kedge-Agent-Version"
+
+// hubVersionTimeout bounds the hub version lookup on tunnel open; the lookup
+// is usually served from the HubVersionCache.
+const hubVersionTimeout = 5 * time.Second
+
+// AgentSkewPolicy says what the tunnel does with an agent whose version is
+// outside the skew policy (versionskew.CheckAgent). Either way the agent's
+// version is recorded in the edge's status.agentVersion and the version
+// reconciler sets its VersionSupported condition False.
+type AgentSkewPolicy string
+
+const (
+	// AgentSkewWarn accepts the tunnel and logs the skew.
+	AgentSkewWarn AgentSkewPolicy = "warn"
+	// AgentSkewReject refuses the tunnel with 426 Upgrade Required.
+	AgentSkewReject AgentSkewPolicy = "reject"
+)
+
+// Validate reports an unknown policy.
+func (p AgentSkewPolicy) Validate() error {
+	switch p {
+	case "", AgentSkewWarn, AgentSkewReject:
+		return nil
+	}
+	return fmt.Errorf("unknown agent version skew policy %q (want %q or %q)", p, AgentSkewWarn, AgentSkewReject)
+}
+
+// SetHubVersion wires the hub release lookup agent versions are checked
+// against, typically a shared HubVersionCache.Get. When never set, agent
+// versions are recorded but not checked.
+func (p *Server) SetHubVersion(fn func(context.Context) (string, error)) { p.hubVersion = fn }
+
+// checkAgentVersion checks the version an agent dialed in with against the
+// hub's and returns why its tunnel must be refused, or "" to accept it. An
+// unknown hub version never refuses a tunnel.
+func (p *Server) checkAgentVersion(ctx context.Context, cluster, name, agentVersion string) string {
+	if p.hubVersion == nil {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(ctx, hubVersionTimeout)
+	defer cancel()
+	hubVersion, err := p.hubVersion(ctx)
+	if err != nil {
+		p.logger.V(2).Info("Could not determine hub version, skipping agent version check",
+			"cluster", cluster, "edge", name, "err", err)
+		return ""
+	}
+	skew := versionskew.CheckAgent(agentVersion, hubVersion)
+	if skew.Status != versionskew.Unsupported {
+		return ""
+	}
+	p.logger.Info("Edge agent version outside the supported skew",
+		"cluster", cluster, "edge", name, "agentVersion", agentVersion, "hubVersion", hubVersion,
+		"reason", skew.Reason, "policy", p.agentSkew)
+	if p.agentSkew == AgentSkewReject {
+		return skew.Reason
+	}
+	return ""
+}
+
+// recordAgentVersion stores the version of an agent whose tunnel was refused
+// in the edge's status.agentVersion, so the version reconciler flags the edge
+// even though it never connects. Best-effort: errors are logged.
+func (p *Server) recordAgentVersion(ctx context.Context, gvr schema.GroupVersionResource, cluster, name, agentVersion string) {
+	cfg, err := p.tenantConfigFor(ctx, cluster)
+	if err != nil {
+		p.logger.Error(err, "recordAgentVersion: failed to resolve tenant config",
+			"cluster", cluster, "edge", name)
+		return
+	}
+	dynClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		p.logger.Error(err, "recordAgentVersion: failed to create dynamic client",
+			"cluster", cluster, "edge", name)
+		return
+	}
+	patch, err := json.Marshal(map[string]any{"status": map[string]any{"agentVersion": agentVersion}})
+	if err != nil {
+		return
+	}
+	if _, err := dynClient.Resource(gvr).Patch(ctx, name,
+		types.MergePatchType, patch, metav1.PatchOptions{}, "status"); err != nil {
+		p.logger.Error(err, "recordAgentVersion: failed to patch status",
+			"cluster", cluster, "edge", name)
+	}
+}
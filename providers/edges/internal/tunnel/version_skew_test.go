@@ -0,0 +1,53 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"k8s.io/klog/v2"
+)
+
+func TestCheckAgentVersion(t *testing.T) {
+	hub := func(context.Context) (string, error) { return "v0.0.28", nil }
+	tests := []struct {
+		name       string
+		policy     AgentSkewPolicy
+		hubVersion func(context.Context) (string, error)
+		agent      string
+		refused    bool
+	}{
+		{"supported", AgentSkewReject, hub, "v0.0.27", false},
+		{"too old, rejected", AgentSkewReject, hub, "v0.0.25", true},
+		{"newer than hub, rejected", AgentSkewReject, hub, "v0.0.29", true},
+		{"too old, warned", AgentSkewWarn, hub, "v0.0.25", false},
+		{"dev build", AgentSkewReject, hub, "dev", false},
+		{"hub version unknown", AgentSkewReject, func(context.Context) (string, error) { return "", errors.New("unreachable") }, "v0.0.25", false},
+		{"no hub version lookup", AgentSkewReject, nil, "v0.0.25", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Server{hubVersion: tt.hubVersion, agentSkew: tt.policy, logger: klog.Background()}
+			reason := p.checkAgentVersion(context.Background(), "c1", "edge-1", tt.agent)
+			if (reason != "") != tt.refused {
+				t.Fatalf("checkAgentVersion(%q) = %q, refused want %v", tt.agent, reason, tt.refused)
+			}
+		})
+	}
+}
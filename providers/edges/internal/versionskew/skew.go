@@ -0,0 +1,97 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package versionskew checks an agent's version against the hub's. It mirrors
+// the agent half of the kedge module's pkg/version skew policy, which this
+// module cannot import; keep the two in step.
+package versionskew
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxAgentLag is how many releases an agent may be behind the hub. A release
+// is a minor version, or a patch version while the project is still on
+// v0.0.z. Agents newer than the hub are never supported: the hub must roll
+// out first.
+const MaxAgentLag = 2
+
+// Status classifies an agent version against the hub's.
+type Status string
+
+const (
+	// Supported: the combination is within policy.
+	Supported Status = "ok"
+	// Unsupported: the combination is outside policy.
+	Unsupported Status = "unsupported"
+	// Unknown: one side is a dev build or did not report a version.
+	Unknown Status = "unknown"
+)
+
+// Skew is the outcome of a skew check with a human-readable reason.
+type Skew struct {
+	Status Status
+	Reason string
+}
+
+// CheckAgent checks an agent version against the hub version.
+func CheckAgent(agent, hub string) Skew {
+	if agent == "" {
+		return Skew{Unknown, "agent did not report a version"}
+	}
+	ra, okAgent := parseRelease(agent)
+	rh, okHub := parseRelease(hub)
+	if !okAgent || !okHub {
+		return Skew{Unknown, "development build; skew not checked"}
+	}
+	if ra[0] != rh[0] {
+		return Skew{Unsupported, fmt.Sprintf("agent major version v%d differs from the hub's v%d", ra[0], rh[0])}
+	}
+	d := rh[1] - ra[1]
+	if ra[0] == 0 && ra[1] == 0 && rh[1] == 0 {
+		d = rh[2] - ra[2]
+	}
+	switch {
+	case d < 0:
+		return Skew{Unsupported, fmt.Sprintf("agent %s is newer than the hub (%s); upgrade the hub first", agent, hub)}
+	case d > MaxAgentLag:
+		return Skew{Unsupported, fmt.Sprintf("agent %s is %d releases behind the hub (%s, supported: %d); upgrade the agent", agent, d, hub, MaxAgentLag)}
+	}
+	return Skew{Status: Supported}
+}
+
+// parseRelease parses "v1.2.3", ignoring any "-pre" or "+build" suffix.
+func parseRelease(v string) ([3]int, bool) {
+	var out [3]int
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) != 3 {
+		return out, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return out, false
+		}
+		out[i] = n
+	}
+	return out, true
+}
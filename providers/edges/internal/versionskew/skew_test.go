@@ -0,0 +1,44 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package versionskew
+
+import "testing"
+
+func TestCheckAgent(t *testing.T) {
+	tests := []struct {
+		agent, hub string
+		want       Status
+	}{
+		{"v0.0.28", "v0.0.28", Supported},
+		{"v0.0.26", "v0.0.28", Supported},
+		{"v0.0.25", "v0.0.28", Unsupported},
+		{"v0.0.29", "v0.0.28", Unsupported},
+		{"v0.0.28", "v0.1.0", Supported},
+		{"v1.1.0", "v1.3.5", Supported},
+		{"v1.0.0", "v1.3.0", Unsupported},
+		{"v1.3.0", "v2.0.0", Unsupported},
+		{"v0.0.28-rc.1", "v0.0.28", Supported},
+		{"", "v0.0.28", Unknown},
+		{"dev", "v0.0.28", Unknown},
+		{"v0.0.28", "dev", Unknown},
+	}
+	for _, tt := range tests {
+		if got := CheckAgent(tt.agent, tt.hub); got.Status != tt.want {
+			t.Errorf("CheckAgent(%q, %q) = %+v, want %s", tt.agent, tt.hub, got, tt.want)
+		}
+	}
+}
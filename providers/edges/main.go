@@ -18,8 +18,9 @@
 //
 //   - /healthz                                          liveness/readiness gate
 //   - /agent/{cluster}/apis/edges.kedge.faros.sh/v1alpha1/{kubernetesclusters|linuxservers}/{name}/proxy  agent control-tunnel ingress
-//   - /agent/proxy?revdial.dialer=<id>                  agent revdial pickup ingress
+//   - /agent/proxy?kedge.tunnel=<id>&revdial.dialer=<id> agent revdial pickup ingress
 //   - /edgeproxy/clusters/{cluster}/.../{name}/{k8s|ssh|mcp}  consumer egress
+//   - /manifests/{cluster}/{namespace}/{placement}/{digest}   stored manifest bundles (agent pull)
 //
 // IMPORTANT: this provider MUST run as a single replica — revdial registers
 // dialers in a process-global map, so an agent's control connection and every
@@ -31,21 +32,31 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
 
 	edgesv1alpha1 "github.com/faroshq/provider-edges/apis/v1alpha1"
+	"github.com/faroshq/provider-edges/internal/claims"
+	"github.com/faroshq/provider-edges/internal/costs"
+	"github.com/faroshq/provider-edges/internal/manifeststore"
+	"github.com/faroshq/provider-edges/internal/scheduler"
 	sdktunnel "github.com/faroshq/provider-edges/internal/tunnel"
 	"github.com/faroshq/provider-edges/internal/svccatalog"
+	"github.com/faroshq/provider-sdk/revdial"
 )
 
 // providerPublicBase is the path prefix (behind the hub backend proxy) this
@@ -110,10 +121,36 @@ func runServe() error {
 	// manager (Edge reconcilers across tenant workspaces).
 	kcpConfig := loadKCPConfig(log)
 	hubExternalURL := os.Getenv("KEDGE_HUB_EXTERNAL_URL")
+	quota, err := tunnelQuotaFromEnv()
+	if err != nil {
+		return err
+	}
+	concurrency, err := edgeConcurrencyFromEnv()
+	if err != nil {
+		return err
+	}
+	stepUp, err := stepUpFromEnv()
+	if err != nil {
+		return err
+	}
+	keepalive, err := tunnelKeepaliveFromEnv()
+	if err != nil {
+		return err
+	}
+	agentCA, err := agentCAFromEnv()
+	if err != nil {
+		return err
+	}
+
+	urlSigningKey := []byte(os.Getenv("KEDGE_URL_SIGNING_KEY"))
+	if len(urlSigningKey) == 0 {
+		log.Info("KEDGE_URL_SIGNING_KEY not set; signed edge URLs use a per-process key and break on restart or across replicas")
+	}
 
 	// Tunnel plane. The provider owns the ConnManager and terminates agent
-	// reverse tunnels in-process (single-replica). Both prefixes sit behind the
-	// hub backend proxy at /services/providers/edges/*.
+	// reverse tunnels in-process; with several replicas, requests for another
+	// replica's tunnel are forwarded to it (KEDGE_PEER_SERVICE). Both prefixes
+	// sit behind the hub backend proxy at /services/providers/edges/*.
 	tsrv, err := sdktunnel.New(sdktunnel.Config{
 		Kinds: []sdktunnel.KindConfig{
 			{GVR: edgesv1alpha1.KubernetesClusterGVR, Kind: "KubernetesCluster"},
@@ -125,18 +162,63 @@ func runServe() error {
 		StaticTokens:        splitEnv(os.Getenv("KEDGE_STATIC_TOKENS")),
 		HubExternalURL:      hubExternalURL,
 		HubInternalURL:      os.Getenv("KEDGE_HUB_INTERNAL_URL"),
-		Logger:              log,
+		URLSigningKey:       urlSigningKey,
+		// Published in each connected edge's status.tunnel so load balancers
+		// and the CLI can route an edge's proxy traffic to this replica.
+		Instance: sdktunnel.Instance{
+			Name:     os.Getenv("KEDGE_INSTANCE_NAME"),
+			Zone:     os.Getenv("KEDGE_INSTANCE_ZONE"),
+			Endpoint: os.Getenv("KEDGE_INSTANCE_ENDPOINT"),
+		},
+		PeerService: os.Getenv("KEDGE_PEER_SERVICE"),
+		Quota:       quota,
+		Concurrency: concurrency,
+		StepUp:      stepUp,
+		AgentSkew:   sdktunnel.AgentSkewPolicy(os.Getenv("KEDGE_AGENT_VERSION_SKEW")),
+		AgentCA:     agentCA,
+		// Agents must then join with --certificate-join.
+		RequireAgentCertificate: os.Getenv("KEDGE_AGENT_REQUIRE_CERTIFICATE") == "true",
+		Keepalive:               keepalive,
+		Logger:                  log,
 	})
 	if err != nil {
 		return fmt.Errorf("build tunnel server: %w", err)
 	}
 	tsrv.Start(ctx.Done())
 
+	manifestStore, err := manifestStoreFromEnv(kcpConfig)
+	if err != nil {
+		return err
+	}
+	extender, err := schedulerExtenderFromEnv()
+	if err != nil {
+		return err
+	}
+	policy, err := placementPolicyFromEnv()
+	if err != nil {
+		return err
+	}
+	drainGrace, err := drainGracePeriodFromEnv()
+	if err != nil {
+		return err
+	}
+	costIndex, err := costIndexFromEnv()
+	if err != nil {
+		return err
+	}
+	claimAllowlist, err := claims.ParseAllowlist(splitEnv(os.Getenv("KEDGE_CLAIM_ALLOWLIST")))
+	if err != nil {
+		return fmt.Errorf("KEDGE_CLAIM_ALLOWLIST: %w", err)
+	}
+
+	preview := scheduler.NewPreview(extender)
+
 	// Edge controllers (token / RBAC / lifecycle) on the provider's own
 	// APIExportEndpointSlice multicluster manager. Best-effort: a missing
 	// kubeconfig just disables the manager (healthz + tunnel still serve).
-	if cerr := startEdgeControllerManager(ctx, kcpConfig, tsrv,
-		hubExternalURL, hubCAData(log), os.Getenv("KEDGE_DEV_MODE") == "true"); cerr != nil {
+	if cerr := startEdgeControllerManager(ctx, kcpConfig, tsrv, manifestStore, extender, policy, preview, costIndex, claimAllowlist,
+		hubExternalURL, hubCAData(log), os.Getenv("KEDGE_DEV_MODE") == "true", drainGrace,
+		os.Getenv("KEDGE_LEADER_ELECTION") == "true"); cerr != nil {
 		if errors.Is(cerr, errControllerDisabled) {
 			log.Info("edge controller manager disabled (no kcp kubeconfig)")
 		} else {
@@ -146,15 +228,34 @@ func runServe() error {
 
 	// Agent ingress: control tunnel + revdial pickup. StripPrefix so the
 	// handler sees /{cluster}/.../edges/{name}/proxy and /proxy.
-	mux.Handle("/agent/", http.StripPrefix("/agent", tsrv.AgentIngressHandler()))
+	mux.Handle(sdktunnel.AgentIngressMount+"/", http.StripPrefix(sdktunnel.AgentIngressMount, tsrv.AgentIngressHandler()))
 	// Consumer egress: k8s/ssh/mcp subresources on the Edge CR.
-	mux.Handle("/edgeproxy/", http.StripPrefix("/edgeproxy", tsrv.EdgeProxyHandler()))
+	mux.Handle(sdktunnel.EdgeProxyMount+"/", http.StripPrefix(sdktunnel.EdgeProxyMount, tsrv.EdgeProxyHandler()))
+	// Manifest store pull: agents fetch the bundles their Placements reference
+	// by digest. Only mounted with the store enabled.
+	if manifestStore != nil {
+		mux.Handle("/manifests/", http.StripPrefix("/manifests", tsrv.ManifestsHandler(manifestStore)))
+		go manifestStore.Run(ctx)
+		log.Info("manifest store enabled; placements reference stored bundles")
+	}
 	// Provider aggregate MCP: the hub's MCP aggregate federates this endpoint
 	// (POST tools/list with the caller's token + X-Kedge-Cluster). Exposes kube
 	// tools across the tenant's connected KubernetesCluster edges AND the Home
 	// Assistant tools of every Ready home-assistant EdgeService.
 	mux.Handle("/mcp", tsrv.RootMCPHandler())
 
+	// Cost attribution: the caller's placements summed per cost label value.
+	// Only mounted with cost labels configured.
+	if costIndex != nil {
+		mux.Handle("/costs", costIndex)
+		log.Info("cost attribution enabled", "labels", costIndex.Labels())
+	}
+
+	// Placement preview: which edges the scheduler would place a POSTed
+	// Workload on, and why, without writing anything. The hub also serves it
+	// at /services/scheduler/preview. Answers 503 until the manager is up.
+	mux.Handle("/scheduler/preview", preview)
+
 	// Service catalog: the UI-facing form schema for every service type
 	// (svccatalog.All() — connection defaults, auth model + credential fields,
 	// scheme-lock/host-required hints). The portal fetches this at
@@ -198,21 +299,32 @@ func runServe() error {
 
 	// NOTE: no WriteTimeout / IdleTimeout — the agent control tunnel and
 	// consumer streams are long-lived (revdial pings every 18s, 60s read
-	// deadline). ReadHeaderTimeout only bounds the header phase.
+	// deadline by default). ReadHeaderTimeout only bounds the header phase.
 	srv := &http.Server{
 		Addr:              ":" + port,
 		Handler:           mux,
 		ReadHeaderTimeout: 10 * time.Second,
 	}
+	// TCP_USER_TIMEOUT is set on every accepted socket: the agent tunnels
+	// arrive on the same listener as everything else.
+	lc := net.ListenConfig{Control: revdial.TCPUserTimeoutControl(keepalive.TCPUserTimeout)}
+	ln, err := lc.Listen(ctx, "tcp", srv.Addr)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", srv.Addr, err)
+	}
 
 	errCh := make(chan error, 1)
 	go func() {
 		log.Info("edges provider listening", "port", port)
-		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			errCh <- err
 		}
 	}()
 
+	if addr := os.Getenv("KEDGE_METRICS_ADDR"); addr != "" {
+		go serveMetrics(ctx, log, addr, tsrv.ConnManager(), costIndex)
+	}
+
 	go runHeartbeat(ctx, log)
 
 	select {
@@ -283,3 +395,263 @@ func splitEnv(v string) []string {
 	}
 	return out
 }
+
+// manifestStoreFromEnv returns the content-addressed manifest store when
+// KEDGE_MANIFEST_STORE is "true", else nil (manifests stay inline on every
+// Placement). It is opt-in because agents older than the store cannot apply
+// a Placement that only references its bundle. KEDGE_MANIFEST_STORE_RETENTION
+// overrides how long an unreferenced bundle is kept.
+func manifestStoreFromEnv(kcpConfig *rest.Config) (*manifeststore.Store, error) {
+	if os.Getenv("KEDGE_MANIFEST_STORE") != "true" || kcpConfig == nil {
+		return nil, nil
+	}
+	opts := manifeststore.Options{Namespace: os.Getenv("KEDGE_MANIFEST_STORE_NAMESPACE")}
+	if s := os.Getenv("KEDGE_MANIFEST_STORE_RETENTION"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("parsing KEDGE_MANIFEST_STORE_RETENTION: %w", err)
+		}
+		opts.Retention = d
+	}
+	return manifeststore.New(kcpConfig, opts)
+}
+
+// schedulerExtenderFromEnv returns the scheduler extender webhook at
+// KEDGE_SCHEDULER_EXTENDER_URL, or nil when unset.
+// KEDGE_SCHEDULER_EXTENDER_TIMEOUT overrides the per-call timeout and
+// KEDGE_SCHEDULER_EXTENDER_FAIL_OPEN=true schedules onto every matched edge
+// while the webhook fails.
+func schedulerExtenderFromEnv() (*scheduler.Extender, error) {
+	url := os.Getenv("KEDGE_SCHEDULER_EXTENDER_URL")
+	if url == "" {
+		return nil, nil
+	}
+	var timeout time.Duration
+	if s := os.Getenv("KEDGE_SCHEDULER_EXTENDER_TIMEOUT"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("parsing KEDGE_SCHEDULER_EXTENDER_TIMEOUT: %w", err)
+		}
+		timeout = d
+	}
+	return scheduler.NewExtender(url, timeout, os.Getenv("KEDGE_SCHEDULER_EXTENDER_FAIL_OPEN") == "true"), nil
+}
+
+// placementPolicyFromEnv returns the placement policy service at
+// KEDGE_PLACEMENT_POLICY_URL, or nil when unset.
+// KEDGE_PLACEMENT_POLICY_TIMEOUT overrides the per-call timeout and
+// KEDGE_PLACEMENT_POLICY_FAIL_OPEN=true writes placements unreviewed while
+// the service fails.
+func placementPolicyFromEnv() (*scheduler.Policy, error) {
+	url := os.Getenv("KEDGE_PLACEMENT_POLICY_URL")
+	if url == "" {
+		return nil, nil
+	}
+	var timeout time.Duration
+	if s := os.Getenv("KEDGE_PLACEMENT_POLICY_TIMEOUT"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("parsing KEDGE_PLACEMENT_POLICY_TIMEOUT: %w", err)
+		}
+		timeout = d
+	}
+	return scheduler.NewPolicy(url, timeout, os.Getenv("KEDGE_PLACEMENT_POLICY_FAIL_OPEN") == "true"), nil
+}
+
+// costIndexFromEnv returns the cost index for the Workload labels in
+// KEDGE_COST_LABELS (comma-separated keys), or nil when unset.
+func costIndexFromEnv() (*costs.Index, error) {
+	labels := splitEnv(os.Getenv("KEDGE_COST_LABELS"))
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	for _, l := range labels {
+		if errs := validation.IsQualifiedName(l); len(errs) > 0 {
+			return nil, fmt.Errorf("KEDGE_COST_LABELS: invalid label key %q: %s", l, strings.Join(errs, "; "))
+		}
+		if l == edgesv1alpha1.LabelWorkload || l == edgesv1alpha1.LabelEdge {
+			return nil, fmt.Errorf("KEDGE_COST_LABELS: %q is set by the scheduler", l)
+		}
+	}
+	return costs.NewIndex(labels), nil
+}
+
+// serveMetrics serves Prometheus metrics at /metrics on addr until ctx is
+// done: the open agent tunnels and, with cost attribution, the cost index.
+// It listens apart from the main mux, which the hub proxies to tenants,
+// because the metrics cover every workspace.
+func serveMetrics(ctx context.Context, log logr.Logger, addr string, tunnels *sdktunnel.ConnManager, costIndex *costs.Index) {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(tunnels)
+	if costIndex != nil {
+		reg.MustRegister(costIndex)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+	log.Info("metrics listening", "addr", addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Error(err, "metrics server failed")
+	}
+}
+
+// drainGracePeriodFromEnv returns KEDGE_DRAIN_GRACE_PERIOD, how long
+// sessions open to a cordoned or deleting edge may run before they are
+// closed. Zero (unset) uses edgectrl.DefaultDrainGracePeriod.
+func drainGracePeriodFromEnv() (time.Duration, error) {
+	s := os.Getenv("KEDGE_DRAIN_GRACE_PERIOD")
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("parsing KEDGE_DRAIN_GRACE_PERIOD: %w", err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("KEDGE_DRAIN_GRACE_PERIOD must not be negative, got %s", d)
+	}
+	return d, nil
+}
+
+// agentCAFromEnv loads the CA signing agent certificates from
+// KEDGE_AGENT_CA_CERT_FILE and KEDGE_AGENT_CA_KEY_FILE, with the certificate
+// lifetime in KEDGE_AGENT_CERT_VALIDITY and the approval mode ("auto" or
+// "manual") in KEDGE_AGENT_CSR_APPROVAL. Nil (unset) disables certificate
+// join.
+func agentCAFromEnv() (*sdktunnel.AgentCA, error) {
+	certFile, keyFile := os.Getenv("KEDGE_AGENT_CA_CERT_FILE"), os.Getenv("KEDGE_AGENT_CA_KEY_FILE")
+	if certFile == "" && keyFile == "" {
+		return nil, nil
+	}
+	if certFile == "" || keyFile == "" {
+		return nil, fmt.Errorf("KEDGE_AGENT_CA_CERT_FILE and KEDGE_AGENT_CA_KEY_FILE must be set together")
+	}
+	var validity time.Duration
+	if v := os.Getenv("KEDGE_AGENT_CERT_VALIDITY"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("parsing KEDGE_AGENT_CERT_VALIDITY: %w", err)
+		}
+		validity = d
+	}
+	ca, err := sdktunnel.LoadAgentCA(certFile, keyFile, validity, sdktunnel.CSRApproval(os.Getenv("KEDGE_AGENT_CSR_APPROVAL")))
+	if err != nil {
+		return nil, fmt.Errorf("loading agent CA: %w", err)
+	}
+	return ca, nil
+}
+
+// stepUpFromEnv returns the step-up policy for interactive SSH:
+// KEDGE_STEP_UP_MAX_AGE (a duration; unset disables it) and the accepted
+// second factors in KEDGE_STEP_UP_AMR (comma-separated amr values).
+func stepUpFromEnv() (sdktunnel.StepUp, error) {
+	var s sdktunnel.StepUp
+	if v := os.Getenv("KEDGE_STEP_UP_MAX_AGE"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return s, fmt.Errorf("parsing KEDGE_STEP_UP_MAX_AGE: %w", err)
+		}
+		s.MaxAge = d
+	}
+	s.AMR = splitEnv(os.Getenv("KEDGE_STEP_UP_AMR"))
+	return s, nil
+}
+
+// tunnelKeepaliveFromEnv returns the agent tunnels' dead-peer detection
+// settings from KEDGE_TUNNEL_KEEPALIVE_INTERVAL, KEDGE_TUNNEL_KEEPALIVE_TIMEOUT
+// and KEDGE_TUNNEL_TCP_USER_TIMEOUT (durations; unset keeps the default).
+func tunnelKeepaliveFromEnv() (revdial.Keepalive, error) {
+	var k revdial.Keepalive
+	for _, v := range []struct {
+		env string
+		d   *time.Duration
+	}{
+		{"KEDGE_TUNNEL_KEEPALIVE_INTERVAL", &k.Interval},
+		{"KEDGE_TUNNEL_KEEPALIVE_TIMEOUT", &k.Timeout},
+		{"KEDGE_TUNNEL_TCP_USER_TIMEOUT", &k.TCPUserTimeout},
+	} {
+		if s := os.Getenv(v.env); s != "" {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return k, fmt.Errorf("parsing %s: %w", v.env, err)
+			}
+			*v.d = d
+		}
+	}
+	return k, nil
+}
+
+// tunnelQuotaFromEnv returns the per-tunnel quota: sdktunnel.DefaultQuota
+// with each KEDGE_TUNNEL_* variable that is set overriding its limit. "0"
+// disables a limit.
+func tunnelQuotaFromEnv() (*sdktunnel.Quota, error) {
+	q := sdktunnel.DefaultQuota()
+	for _, v := range []struct {
+		env   string
+		parse func(string) error
+	}{
+		{"KEDGE_TUNNEL_RECEIVE_BYTES_PER_SECOND", func(s string) (err error) {
+			q.ReceiveBytesPerSecond, err = strconv.ParseInt(s, 10, 64)
+			return err
+		}},
+		{"KEDGE_TUNNEL_SEND_BYTES_PER_SECOND", func(s string) (err error) {
+			q.SendBytesPerSecond, err = strconv.ParseInt(s, 10, 64)
+			return err
+		}},
+		{"KEDGE_TUNNEL_MESSAGES_PER_SECOND", func(s string) (err error) {
+			q.MessagesPerSecond, err = strconv.ParseFloat(s, 64)
+			return err
+		}},
+		{"KEDGE_TUNNEL_MESSAGE_BURST", func(s string) (err error) {
+			q.MessageBurst, err = strconv.Atoi(s)
+			return err
+		}},
+		{"KEDGE_TUNNEL_MAX_THROTTLE", func(s string) (err error) {
+			q.MaxThrottle, err = time.ParseDuration(s)
+			return err
+		}},
+	} {
+		if s := os.Getenv(v.env); s != "" {
+			if err := v.parse(s); err != nil {
+				return nil, fmt.Errorf("parsing %s: %w", v.env, err)
+			}
+		}
+	}
+	return &q, nil
+}
+
+// edgeConcurrencyFromEnv returns the per-edge proxied request limit:
+// sdktunnel.DefaultConcurrency with KEDGE_EDGE_MAX_INFLIGHT,
+// KEDGE_EDGE_MAX_QUEUED and KEDGE_EDGE_QUEUE_TIMEOUT overriding it when set.
+// KEDGE_EDGE_MAX_INFLIGHT=0 disables the limit.
+func edgeConcurrencyFromEnv() (*sdktunnel.Concurrency, error) {
+	c := sdktunnel.DefaultConcurrency()
+	for _, v := range []struct {
+		env   string
+		parse func(string) error
+	}{
+		{"KEDGE_EDGE_MAX_INFLIGHT", func(s string) (err error) {
+			c.MaxInFlight, err = strconv.Atoi(s)
+			return err
+		}},
+		{"KEDGE_EDGE_MAX_QUEUED", func(s string) (err error) {
+			c.MaxQueued, err = strconv.Atoi(s)
+			return err
+		}},
+		{"KEDGE_EDGE_QUEUE_TIMEOUT", func(s string) (err error) {
+			c.QueueTimeout, err = time.ParseDuration(s)
+			return err
+		}},
+	} {
+		if s := os.Getenv(v.env); s != "" {
+			if err := v.parse(s); err != nil {
+				return nil, fmt.Errorf("parsing %s: %w", v.env, err)
+			}
+		}
+	}
+	return &c, nil
+}
@@ -88,14 +88,19 @@ func (k *KedgeClient) Run(ctx context.Context, args ...string) (string, error) {
 	return k.run(ctx, args...)
 }
 
-// Login authenticates to the hub using a static token.
+// Login authenticates to the hub using a static token. The kedge context is
+// written to the client's kubeconfig, which the suites own.
 func (k *KedgeClient) Login(ctx context.Context, token string) error {
-	_, err := k.run(ctx,
+	args := []string{
 		"login",
 		"--hub-url", k.hubURL,
 		"--insecure-skip-tls-verify",
 		"--token", token,
-	)
+	}
+	if k.kubeconfig != "" {
+		args = append(args, "--kubeconfig-path", k.kubeconfig, "--force")
+	}
+	_, err := k.run(ctx, args...)
 	return err
 }
 
@@ -488,7 +488,7 @@ func TeardownClusters(workDir string) env.Func {
 		kedge := filepath.Join(workDir, KedgeBin)
 
 		args := []string{
-			"dev", "delete",
+			"dev", "delete", "--yes",
 			"--hub-cluster-name", DefaultHubClusterName,
 			"--agent-cluster-name", DefaultAgentClusterName,
 			"--worker-count", fmt.Sprintf("%d", DefaultAgentCount),
@@ -833,7 +833,7 @@ func TeardownClustersWithAgentCount(workDir string, agentCount int) env.Func {
 		}
 		kedge := filepath.Join(workDir, KedgeBin)
 		args := []string{
-			"dev", "delete",
+			"dev", "delete", "--yes",
 			"--hub-cluster-name", DefaultHubClusterName,
 			"--agent-cluster-name", DefaultAgentClusterName,
 			"--worker-count", fmt.Sprintf("%d", agentCount),
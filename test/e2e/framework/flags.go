@@ -42,11 +42,31 @@ var (
 
 	// DevToken is the static auth token used in standalone (non-OIDC) test suites.
 	DevToken string
+
+	// SoakDuration is how long the soak suite keeps churning edges, workloads
+	// and agents. Zero (the default) skips the soak suite, so it only runs
+	// when asked for: `make e2e-soak E2E_SOAK_DURATION=2h`.
+	SoakDuration time.Duration
+
+	// SoakInterval is the length of one soak churn cycle.
+	SoakInterval time.Duration
+
+	// SoakMaxGoroutineGrowth is how many more goroutines the hub may run at
+	// the end of a soak than at its warmed-up baseline before the run fails.
+	SoakMaxGoroutineGrowth int
+
+	// SoakMaxHeapGrowth is how much the hub's in-use heap may grow over its
+	// warmed-up baseline, as a fraction (0.5 = 50%), before the run fails.
+	SoakMaxHeapGrowth float64
 )
 
 func init() {
 	flag.BoolVar(&KeepClusters, "keep-clusters", false, "Keep kind clusters after test run (useful for debugging failures)")
 	flag.StringVar(&KedgeBin, "kedge-bin", "bin/kedge", "Path to the kedge CLI binary")
 	flag.StringVar(&DevToken, "dev-token", "dev-token", "Static auth token for non-OIDC test suites")
+	flag.DurationVar(&SoakDuration, "soak-duration", 0, "How long the soak suite churns edges, workloads and agents; 0 skips it")
+	flag.DurationVar(&SoakInterval, "soak-interval", 30*time.Second, "Length of one soak churn cycle")
+	flag.IntVar(&SoakMaxGoroutineGrowth, "soak-max-goroutine-growth", 50, "Goroutines the hub may gain over its soak baseline before the soak fails")
+	flag.Float64Var(&SoakMaxHeapGrowth, "soak-max-heap-growth", 0.5, "Fraction the hub's in-use heap may grow over its soak baseline before the soak fails")
 	flag.DurationVar(&SSHKeepaliveDuration, "ssh-keepalive-duration", 60*time.Second, "How long to hold the long-lived SSH session open in the keepalive test (minimum 60s so the 30s ticker fires at least once)")
 }
@@ -0,0 +1,131 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProcessSnapshot is one sample of a Go process's resource use, read from
+// its /debug/pprof endpoints.
+type ProcessSnapshot struct {
+	Time       time.Time
+	Goroutines int
+	// HeapInuse is runtime.MemStats.HeapInuse after a forced GC, in bytes.
+	HeapInuse uint64
+}
+
+func (s ProcessSnapshot) String() string {
+	return fmt.Sprintf("goroutines=%d heapInuse=%.1fMiB", s.Goroutines, float64(s.HeapInuse)/(1<<20))
+}
+
+// TakeProcessSnapshot samples the process serving pprof at debugURL (e.g.
+// http://127.0.0.1:6061). The heap profile is requested with gc=1 so
+// garbage waiting for collection is not counted as a leak.
+func TakeProcessSnapshot(ctx context.Context, debugURL string) (ProcessSnapshot, error) {
+	snap := ProcessSnapshot{Time: time.Now()}
+
+	goroutines, err := fetchProfile(ctx, debugURL+"/debug/pprof/goroutine?debug=1")
+	if err != nil {
+		return snap, err
+	}
+	// First line: "goroutine profile: total N".
+	line, _, _ := bytes.Cut(goroutines, []byte("\n"))
+	total := strings.TrimPrefix(string(line), "goroutine profile: total ")
+	if snap.Goroutines, err = strconv.Atoi(strings.TrimSpace(total)); err != nil {
+		return snap, fmt.Errorf("parsing goroutine profile header %q: %w", line, err)
+	}
+
+	heap, err := fetchProfile(ctx, debugURL+"/debug/pprof/heap?debug=1&gc=1")
+	if err != nil {
+		return snap, err
+	}
+	// The debug=1 heap profile ends with the runtime.MemStats as comments:
+	// "# HeapInuse = 12345".
+	sc := bufio.NewScanner(bytes.NewReader(heap))
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		if v, ok := strings.CutPrefix(sc.Text(), "# HeapInuse = "); ok {
+			if snap.HeapInuse, err = strconv.ParseUint(strings.TrimSpace(v), 10, 64); err != nil {
+				return snap, fmt.Errorf("parsing HeapInuse %q: %w", v, err)
+			}
+			return snap, nil
+		}
+	}
+	return snap, fmt.Errorf("no HeapInuse in heap profile from %s", debugURL)
+}
+
+// CheckProcessGrowth compares a snapshot against a baseline and returns an
+// error describing every bound it exceeds: more than maxGoroutines extra
+// goroutines, or an in-use heap grown by more than maxHeapGrowth (a
+// fraction of the baseline).
+func CheckProcessGrowth(baseline, current ProcessSnapshot, maxGoroutines int, maxHeapGrowth float64) error {
+	var problems []string
+	if grown := current.Goroutines - baseline.Goroutines; grown > maxGoroutines {
+		problems = append(problems, fmt.Sprintf("goroutines grew by %d (%d → %d, allowed %d)",
+			grown, baseline.Goroutines, current.Goroutines, maxGoroutines))
+	}
+	if limit := float64(baseline.HeapInuse) * (1 + maxHeapGrowth); float64(current.HeapInuse) > limit {
+		problems = append(problems, fmt.Sprintf("in-use heap grew %.0f%% (%s → %s, allowed %.0f%%)",
+			100*(float64(current.HeapInuse)/float64(baseline.HeapInuse)-1), baseline, current, 100*maxHeapGrowth))
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s", strings.Join(problems, "; "))
+}
+
+// DumpGoroutines writes the full goroutine dump (debug=2) of the process at
+// debugURL to path, for diffing a leak against the baseline dump.
+func DumpGoroutines(ctx context.Context, debugURL, path string) error {
+	b, err := fetchProfile(ctx, debugURL+"/debug/pprof/goroutine?debug=2")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
+
+func fetchProfile(ctx context.Context, url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", url, err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", url, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: HTTP %d", url, resp.StatusCode)
+	}
+	return b, nil
+}
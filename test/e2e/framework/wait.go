@@ -71,16 +71,21 @@ var insecureHTTPClient = &http.Client{
 	},
 }
 
-// WaitForTenantAPI logs in with a static token and polls the hub's
-// token-login endpoint until the tenant/users APIBinding has finished
-// bootstrapping. Until then the hub can 500 ("failed to create user") on the
-// first tenant operations, so suites gate startup on this.
+// WaitForTenantAPI waits for the hub's /readyz, which passes once the
+// tenant/users APIBinding is bound and Users can be listed, then logs in with
+// a static token and polls the hub's token-login endpoint until it succeeds.
+// Until then the hub can 500 ("failed to create user") on the first tenant
+// operations, so suites gate startup on this.
 //
 // This replaces the pre-decouple WaitForEdgeAPI gate: edges are now an
 // optional out-of-process provider (group edges.kedge.faros.sh) that these
 // suites do not bootstrap, so "edge list works" is no longer a valid
 // readiness signal. Edge connectivity has its own dedicated suite.
 func WaitForTenantAPI(ctx context.Context, client *KedgeClient, hubURL, token string) error {
+	if err := WaitForHubReadyz(ctx, hubURL); err != nil {
+		return err
+	}
+
 	// Login (retryable) so the kedge context is written to the default
 	// kubeconfig — some non-edge tests drive kubectl via that context.
 	loginCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
@@ -101,6 +106,32 @@ func WaitForTenantAPI(ctx context.Context, client *KedgeClient, hubURL, token st
 	})
 }
 
+// WaitForHubReadyz polls the hub's /readyz until it answers 200: bootstrap
+// is complete and its readiness gates (embedded kcp, APIExport availability,
+// the User API) have passed. WaitForHubReady only waits for /healthz.
+func WaitForHubReadyz(ctx context.Context, hubURL string) error {
+	attempt := 0
+	return Poll(ctx, 3*time.Second, 5*time.Minute, func(ctx context.Context) (bool, error) {
+		attempt++
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, hubURL+"/readyz", nil)
+		if err != nil {
+			return false, err
+		}
+		resp, err := insecureHTTPClient.Do(req)
+		if err != nil {
+			fmt.Printf("[WaitForHubReadyz] attempt %d: %v\n", attempt, err)
+			return false, nil
+		}
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode == http.StatusOK {
+			return true, nil
+		}
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		fmt.Printf("[WaitForHubReadyz] attempt %d: status %d body=%s\n", attempt, resp.StatusCode, b)
+		return false, nil
+	})
+}
+
 // postTokenLogin POSTs the hub's static-token login endpoint with the given
 // bearer token and returns the status code and (truncated) body.
 func postTokenLogin(ctx context.Context, hubURL, token string) (int, string) {
@@ -227,8 +227,8 @@ func TestMain(m *testing.M) {
 // applyEdgesManifests applies provider.yaml (kind Provider) + manifest.yaml
 // (kind CatalogEntry) into root:kedge:system:providers, mirroring `make
 // install-provider-edges`. The CatalogEntry's ui/backend url is overridden to
-// the test provider port. Retried until the API answers (the hub reports
-// /readyz before those APIs are fully servable).
+// the test provider port. Retried until the API answers, in case discovery
+// lags behind the bindings the hub's /readyz waits for.
 func applyEdgesManifests() error {
 	cl, err := kcpDynamicRaw("root:kedge:system:providers", adminToken)
 	if err != nil {
@@ -67,7 +67,8 @@ func TestKubectlThroughTunnel(t *testing.T) {
 
 	// 1. Log in as the static tenant user; the CLI writes a workspace-scoped
 	// context we drive `kedge`/`kubectl` against.
-	runCLI(t, kubeconfig, kedgeBin, "login", "--hub-url", hubURL, "--insecure-skip-tls-verify", "--token", staticToken)
+	runCLI(t, kubeconfig, kedgeBin, "login", "--hub-url", hubURL, "--insecure-skip-tls-verify", "--token", staticToken,
+		"--kubeconfig-path", kubeconfig, "--force")
 	tenantWS := clusterFromKubeconfig(t, kubeconfig)
 	t.Logf("tenant workspace = %s", tenantWS)
 
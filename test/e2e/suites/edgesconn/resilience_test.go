@@ -35,7 +35,8 @@ func TestInvalidJoinTokenRejected(t *testing.T) {
 
 	workDir := t.TempDir()
 	kubeconfig := filepath.Join(workDir, "kedge.kubeconfig")
-	runCLI(t, kubeconfig, kedgeBin, "login", "--hub-url", hubURL, "--insecure-skip-tls-verify", "--token", staticToken)
+	runCLI(t, kubeconfig, kedgeBin, "login", "--hub-url", hubURL, "--insecure-skip-tls-verify", "--token", staticToken,
+		"--kubeconfig-path", kubeconfig, "--force")
 	tenantWS := clusterFromKubeconfig(t, kubeconfig)
 	tenantAdmin := kcpDynamic(t, tenantWS, adminToken)
 
@@ -50,7 +50,8 @@ func TestSSHThroughTunnel(t *testing.T) {
 	kubeconfig := filepath.Join(workDir, "kedge.kubeconfig")
 
 	// 1. Log in + resolve the tenant workspace.
-	runCLI(t, kubeconfig, kedgeBin, "login", "--hub-url", hubURL, "--insecure-skip-tls-verify", "--token", staticToken)
+	runCLI(t, kubeconfig, kedgeBin, "login", "--hub-url", hubURL, "--insecure-skip-tls-verify", "--token", staticToken,
+		"--kubeconfig-path", kubeconfig, "--force")
 	tenantWS := clusterFromKubeconfig(t, kubeconfig)
 	t.Logf("tenant workspace = %s", tenantWS)
 	tenantAdmin := kcpDynamic(t, tenantWS, adminToken)
@@ -106,7 +107,8 @@ func TestSSHUserMappingInherited(t *testing.T) {
 	workDir := t.TempDir()
 	kubeconfig := filepath.Join(workDir, "kedge.kubeconfig")
 
-	runCLI(t, kubeconfig, kedgeBin, "login", "--hub-url", hubURL, "--insecure-skip-tls-verify", "--token", staticToken)
+	runCLI(t, kubeconfig, kedgeBin, "login", "--hub-url", hubURL, "--insecure-skip-tls-verify", "--token", staticToken,
+		"--kubeconfig-path", kubeconfig, "--force")
 	tenantWS := clusterFromKubeconfig(t, kubeconfig)
 	tenantAdmin := kcpDynamic(t, tenantWS, adminToken)
 	enableEdges(t, tenantAdmin)
@@ -121,10 +121,11 @@ func applyProviderManifests() error {
 					return fmt.Errorf("%s: override spec.backend.url: %w", file, err)
 				}
 			}
-			// The hub reports /readyz before the admin/catalog APIs in
-			// root:kedge:system:providers are fully servable, so the first
-			// Create on a slow runner can 404 ("could not find the requested
-			// resource"). Retry until the API is up.
+			// The hub's /readyz waits for the admin/catalog bindings in
+			// root:kedge:system:providers, but on a slow runner the first
+			// Create can still 404 ("could not find the requested
+			// resource") while discovery catches up. Retry until the API is
+			// up.
 			deadline := time.Now().Add(90 * time.Second)
 			for {
 				ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
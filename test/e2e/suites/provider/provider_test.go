@@ -92,8 +92,8 @@ func kcpDynamic(t *testing.T, clusterPath, token string) dynamic.Interface {
 // applyQuickstartManifests applies provider.yaml (kind Provider) +
 // manifest.yaml (kind CatalogEntry) into root:kedge:system:providers,
 // mirroring `make install-provider-quickstart`. Called from TestMain. The
-// hub reports /readyz before those APIs are fully servable, so creates are
-// retried until the API answers.
+// hub's /readyz waits for the bindings serving those APIs; creates are still
+// retried until the API answers, in case discovery lags behind.
 func applyQuickstartManifests() error {
 	cl, err := kcpDynamicRaw("root:kedge:system:providers", adminToken)
 	if err != nil {
@@ -574,11 +574,11 @@ func httpGetJSON(t *testing.T, url, token string) map[string]any {
 // returned kubeconfig.
 func loginStaticTokenAndGetCluster(t *testing.T) string {
 	t.Helper()
-	// The hub reports /readyz before the users APIBinding in
-	// root:kedge:users is fully usable, so the first logins after startup
-	// can 500 with "failed to create user" (the handler's user list hits
-	// "the server could not find the requested resource"). Retry until the
-	// binding settles rather than failing the suite on the race.
+	// The hub's /readyz waits until Users can be listed, but the first
+	// logins after startup could once 500 with "failed to create user"
+	// (the handler's user list hit "the server could not find the
+	// requested resource"). Keep retrying rather than failing the suite
+	// should the binding still be settling.
 	var (
 		b    []byte
 		code int
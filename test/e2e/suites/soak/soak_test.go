@@ -0,0 +1,488 @@
+/*
+Copyright 2026 The Faros Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package soak
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/faroshq/faros-kedge/pkg/util/identity"
+	"github.com/faroshq/faros-kedge/test/e2e/framework"
+)
+
+var (
+	linuxServerGVR        = schema.GroupVersionResource{Group: "edges.kedge.faros.sh", Version: "v1alpha1", Resource: "linuxservers"}
+	workloadGVR           = schema.GroupVersionResource{Group: "edges.kedge.faros.sh", Version: "v1alpha1", Resource: "workloads"}
+	placementGVR          = schema.GroupVersionResource{Group: "edges.kedge.faros.sh", Version: "v1alpha1", Resource: "placements"}
+	apiBindingGVR         = schema.GroupVersionResource{Group: "apis.kcp.io", Version: "v1alpha2", Resource: "apibindings"}
+	clusterRoleGVR        = schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}
+	clusterRoleBindingGVR = schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterrolebindings"}
+	workspaceGVR          = schema.GroupVersionResource{Group: "tenancy.kcp.io", Version: "v1alpha1", Resource: "workspaces"}
+)
+
+const (
+	// soakLabel marks every object the soak creates; its value is the edge
+	// the object belongs to, which is also what the workload selects.
+	soakLabel = "kedge.faros.sh/soak"
+	// liveCycles is how many cycles' edges and workloads are alive at once.
+	// Each cycle creates one of each and retires the oldest.
+	liveCycles = 3
+	sshPort    = 22083
+)
+
+// soakEdge is one churned LinuxServer, its workload, and the agent serving it.
+type soakEdge struct {
+	name     string
+	workload string
+	agent    *exec.Cmd
+}
+
+// TestSoak churns edges, workloads and agents for --soak-duration, then
+// deletes everything and asserts nothing leaked: no soak object is left
+// behind, and the hub's goroutines and in-use heap are back within
+// --soak-max-goroutine-growth / --soak-max-heap-growth of the baseline taken
+// after the first (warm-up) cycle.
+func TestSoak(t *testing.T) {
+	if framework.SoakDuration == 0 {
+		t.Skip("--soak-duration not set")
+	}
+
+	workDir := t.TempDir()
+	kubeconfig := filepath.Join(workDir, "kedge.kubeconfig")
+	runCLI(t, kubeconfig, kedgeBin, "login", "--hub-url", hubURL, "--insecure-skip-tls-verify", "--token", staticToken,
+		"--kubeconfig-path", kubeconfig, "--force")
+	tenantWS := clusterFromKubeconfig(t, kubeconfig)
+	t.Logf("tenant workspace = %s", tenantWS)
+	tenant := kcpDynamic(t, tenantWS, adminToken)
+	enableEdges(t, tenant)
+	grantEdgeProxy(t, tenant)
+
+	sshCtx, cancelSSH := context.WithCancel(context.Background())
+	t.Cleanup(cancelSSH)
+	sshSrv := framework.NewTestSSHServer(sshPort)
+	if err := sshSrv.Start(sshCtx); err != nil {
+		t.Fatalf("start embedded SSH server: %v", err)
+	}
+	t.Cleanup(sshSrv.Stop)
+
+	debugURL := "http://" + hubDebugAddr
+	csv, err := os.Create(filepath.Join(dataDir, "soak-snapshots.csv"))
+	if err != nil {
+		t.Fatalf("create snapshot log: %v", err)
+	}
+	defer csv.Close() //nolint:errcheck
+	_, _ = fmt.Fprintln(csv, "elapsed_seconds,cycle,goroutines,heap_inuse_bytes")
+
+	var live []*soakEdge
+	t.Cleanup(func() {
+		for _, e := range live {
+			killGroup(e.agent)
+		}
+	})
+
+	var baseline framework.ProcessSnapshot
+	start := time.Now()
+	cycle := 0
+	for ; cycle == 0 || time.Since(start) < framework.SoakDuration; cycle++ {
+		cycleStart := time.Now()
+
+		// 1. A new edge with its agent and a workload selecting it.
+		e := &soakEdge{name: fmt.Sprintf("soak-%d", cycle), workload: fmt.Sprintf("soak-wl-%d", cycle)}
+		createEdge(t, tenant, e.name)
+		token := waitForJoinToken(t, tenant, e.name)
+		e.agent = startAgent(t, e.name, token, tenantWS)
+		waitForTunnel(t, tenant, e.name, time.Time{})
+		createWorkload(t, tenant, e.workload, e.name)
+		live = append(live, e)
+
+		// 2. Restart the previous cycle's agent: it must come back on its
+		// saved kubeconfig and re-acquire the tunnel.
+		if len(live) > 1 {
+			prev := live[len(live)-2]
+			killGroup(prev.agent)
+			restarted := time.Now()
+			prev.agent = startAgent(t, prev.name, "", tenantWS)
+			waitForTunnel(t, tenant, prev.name, restarted)
+		}
+
+		// 3. Retire the oldest edge and workload.
+		if len(live) > liveCycles {
+			retire(t, tenant, live[0])
+			live = live[1:]
+		}
+
+		snap, err := framework.TakeProcessSnapshot(context.Background(), debugURL)
+		if err != nil {
+			t.Fatalf("cycle %d: hub snapshot: %v", cycle, err)
+		}
+		_, _ = fmt.Fprintf(csv, "%d,%d,%d,%d\n", int(time.Since(start).Seconds()), cycle, snap.Goroutines, snap.HeapInuse)
+		if cycle == 0 {
+			// The first cycle warms informers, caches and connection pools;
+			// growth is measured from here.
+			baseline = snap
+			if err := framework.DumpGoroutines(context.Background(), debugURL, filepath.Join(dataDir, "goroutines-baseline.txt")); err != nil {
+				t.Logf("baseline goroutine dump: %v", err)
+			}
+		}
+		t.Logf("cycle %d (%s elapsed): hub %s, baseline %s", cycle, time.Since(start).Round(time.Second), snap, baseline)
+
+		if rest := framework.SoakInterval - time.Since(cycleStart); rest > 0 {
+			time.Sleep(rest)
+		}
+	}
+
+	// Drain: delete everything the soak created, then the hub must settle
+	// back near its baseline.
+	for _, e := range live {
+		retire(t, tenant, e)
+	}
+	live = nil
+	assertNoSoakObjects(t, tenant)
+
+	var final framework.ProcessSnapshot
+	var growth error
+	waitFor(t, 3*time.Minute, func() (bool, string) {
+		final, err = framework.TakeProcessSnapshot(context.Background(), debugURL)
+		if err != nil {
+			return false, err.Error()
+		}
+		growth = framework.CheckProcessGrowth(baseline, final, framework.SoakMaxGoroutineGrowth, framework.SoakMaxHeapGrowth)
+		return growth == nil, fmt.Sprint(growth)
+	})
+	t.Logf("soak ran %d cycles over %s: hub baseline %s, final %s", cycle, time.Since(start).Round(time.Second), baseline, final)
+	if growth != nil {
+		dump := filepath.Join(dataDir, "goroutines-final.txt")
+		if err := framework.DumpGoroutines(context.Background(), debugURL, dump); err != nil {
+			t.Logf("final goroutine dump: %v", err)
+		}
+		t.Fatalf("hub leaked over the soak: %v (compare %s with goroutines-baseline.txt)", growth, dump)
+	}
+}
+
+// --- churn steps ---
+
+func createEdge(t *testing.T, tenant dynamic.Interface, name string) {
+	t.Helper()
+	edge := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "edges.kedge.faros.sh/v1alpha1",
+		"kind":       "LinuxServer",
+		"metadata":   map[string]any{"name": name, "labels": map[string]any{soakLabel: name}},
+		"spec":       map[string]any{},
+	}}
+	if _, err := tenant.Resource(linuxServerGVR).Create(ctxWithTimeout(t, 10*time.Second), edge, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("create LinuxServer %s: %v", name, err)
+	}
+}
+
+func createWorkload(t *testing.T, tenant dynamic.Interface, name, edgeName string) {
+	t.Helper()
+	wl := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "edges.kedge.faros.sh/v1alpha1",
+		"kind":       "Workload",
+		"metadata":   map[string]any{"name": name, "namespace": "default", "labels": map[string]any{soakLabel: edgeName}},
+		"spec": map[string]any{
+			"simple":   map[string]any{"image": "registry.k8s.io/pause:3.9"},
+			"replicas": int64(1),
+			"placement": map[string]any{
+				"edgeSelector": map[string]any{"matchLabels": map[string]any{soakLabel: edgeName}},
+			},
+		},
+	}}
+	if _, err := tenant.Resource(workloadGVR).Namespace("default").Create(ctxWithTimeout(t, 10*time.Second), wl, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("create Workload %s: %v", name, err)
+	}
+}
+
+// retire stops an edge's agent and deletes its workload and the edge. The
+// agent's saved kubeconfig goes too, so ~/.kedge does not grow with the soak.
+func retire(t *testing.T, tenant dynamic.Interface, e *soakEdge) {
+	t.Helper()
+	killGroup(e.agent)
+	if err := tenant.Resource(workloadGVR).Namespace("default").Delete(ctxWithTimeout(t, 10*time.Second), e.workload, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		t.Fatalf("delete Workload %s: %v", e.workload, err)
+	}
+	if err := tenant.Resource(linuxServerGVR).Delete(ctxWithTimeout(t, 10*time.Second), e.name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		t.Fatalf("delete LinuxServer %s: %v", e.name, err)
+	}
+	if path, err := framework.AgentSavedKubeconfigPath(e.name); err == nil {
+		_ = os.Remove(path)
+	}
+}
+
+// assertNoSoakObjects waits for every soak edge and workload, and every
+// Placement of a soak workload, to be gone — finalizers included.
+func assertNoSoakObjects(t *testing.T, tenant dynamic.Interface) {
+	t.Helper()
+	if !waitFor(t, 3*time.Minute, func() (bool, string) {
+		var left []string
+		edges, err := tenant.Resource(linuxServerGVR).List(ctxWithTimeout(t, 10*time.Second), metav1.ListOptions{LabelSelector: soakLabel})
+		if err != nil {
+			return false, err.Error()
+		}
+		for _, e := range edges.Items {
+			left = append(left, "LinuxServer/"+e.GetName())
+		}
+		workloads, err := tenant.Resource(workloadGVR).Namespace("default").List(ctxWithTimeout(t, 10*time.Second), metav1.ListOptions{LabelSelector: soakLabel})
+		if err != nil {
+			return false, err.Error()
+		}
+		for _, w := range workloads.Items {
+			left = append(left, "Workload/"+w.GetName())
+		}
+		placements, err := tenant.Resource(placementGVR).Namespace("").List(ctxWithTimeout(t, 10*time.Second), metav1.ListOptions{})
+		if err != nil {
+			return false, err.Error()
+		}
+		for _, p := range placements.Items {
+			if ref, _, _ := unstructured.NestedString(p.Object, "spec", "workloadRef", "name"); strings.HasPrefix(ref, "soak-wl-") {
+				left = append(left, "Placement/"+p.GetNamespace()+"/"+p.GetName())
+			}
+		}
+		return len(left) == 0, "left behind: " + strings.Join(left, ", ")
+	}) {
+		t.Fatal("soak objects were not cleaned up")
+	}
+}
+
+// waitForTunnel waits until the edge is connected with a tunnel lease
+// acquired after since, so an agent restart is only accepted once the new
+// process re-established the tunnel rather than on the old lease.
+func waitForTunnel(t *testing.T, tenant dynamic.Interface, edgeName string, since time.Time) {
+	t.Helper()
+	if !waitFor(t, 2*time.Minute, func() (bool, string) {
+		got, err := tenant.Resource(linuxServerGVR).Get(ctxWithTimeout(t, 5*time.Second), edgeName, metav1.GetOptions{})
+		if err != nil {
+			return false, err.Error()
+		}
+		conn, _, _ := unstructured.NestedBool(got.Object, "status", "connected")
+		acquired, _, _ := unstructured.NestedString(got.Object, "status", "tunnel", "acquireTime")
+		at, _ := time.Parse(time.RFC3339, acquired)
+		// acquireTime has second precision.
+		return conn && !at.Before(since.Truncate(time.Second)), fmt.Sprintf("connected=%v acquireTime=%s", conn, acquired)
+	}) {
+		t.Fatalf("edge %s never (re)acquired its tunnel", edgeName)
+	}
+}
+
+// --- steps shared with the edgesconn suite ---
+
+func enableEdges(t *testing.T, tenant dynamic.Interface) {
+	t.Helper()
+	claim := func(group, resource string) map[string]any {
+		return map[string]any{
+			"group": group, "resource": resource,
+			"verbs":    []any{"get", "list", "watch", "create", "update", "patch", "delete"},
+			"selector": map[string]any{"matchAll": true},
+			"state":    "Accepted",
+		}
+	}
+	binding := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apis.kcp.io/v1alpha2",
+		"kind":       "APIBinding",
+		"metadata":   map[string]any{"name": "edges"},
+		"spec": map[string]any{
+			"reference": map[string]any{"export": map[string]any{"path": edgesWorkspacePath, "name": edgesAPIExportName}},
+			"permissionClaims": []any{
+				claim("", "namespaces"), claim("", "serviceaccounts"), claim("", "secrets"),
+				claim("rbac.authorization.k8s.io", "clusterroles"), claim("rbac.authorization.k8s.io", "clusterrolebindings"),
+			},
+		},
+	}}
+	if _, err := tenant.Resource(apiBindingGVR).Create(ctxWithTimeout(t, 10*time.Second), binding, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		t.Fatalf("create APIBinding: %v", err)
+	}
+	if !waitFor(t, 30*time.Second, func() (bool, string) {
+		got, err := tenant.Resource(apiBindingGVR).Get(ctxWithTimeout(t, 2*time.Second), "edges", metav1.GetOptions{})
+		if err != nil {
+			return false, err.Error()
+		}
+		phase, _, _ := unstructured.NestedString(got.Object, "status", "phase")
+		return phase == "Bound", "phase=" + phase
+	}) {
+		t.Fatal("edges APIBinding never reached Bound")
+	}
+}
+
+func grantEdgeProxy(t *testing.T, tenant dynamic.Interface) {
+	t.Helper()
+	providersWS := kcpDynamic(t, "root:kedge:providers", adminToken)
+	ws, err := providersWS.Resource(workspaceGVR).Get(ctxWithTimeout(t, 10*time.Second), "edges", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get provider workspace: %v", err)
+	}
+	providerCluster, _, _ := unstructured.NestedString(ws.Object, "spec", "cluster")
+	if providerCluster == "" {
+		t.Fatal("provider workspace has no spec.cluster")
+	}
+	qualified := identity.QualifiedServiceAccount(providerCluster, "default", "provider")
+
+	name := "kedge:provider:edges:edgeproxy"
+	rules := []any{
+		map[string]any{"nonResourceURLs": []any{"/"}, "verbs": []any{"access"}},
+		map[string]any{"apiGroups": []any{"edges.kedge.faros.sh"}, "resources": []any{"kubernetesclusters", "linuxservers"}, "verbs": []any{"get", "list", "watch", "proxy"}},
+		map[string]any{"apiGroups": []any{"edges.kedge.faros.sh"}, "resources": []any{"kubernetesclusters/status", "linuxservers/status"}, "verbs": []any{"get", "update", "patch"}},
+		map[string]any{"apiGroups": []any{""}, "resources": []any{"secrets"}, "verbs": []any{"get", "list", "watch", "create", "update"}},
+		map[string]any{"apiGroups": []any{""}, "resources": []any{"namespaces"}, "verbs": []any{"get", "create"}},
+		map[string]any{"apiGroups": []any{"authentication.k8s.io"}, "resources": []any{"tokenreviews"}, "verbs": []any{"create"}},
+		map[string]any{"apiGroups": []any{"authorization.k8s.io"}, "resources": []any{"subjectaccessreviews"}, "verbs": []any{"create"}},
+	}
+	role := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "rbac.authorization.k8s.io/v1", "kind": "ClusterRole",
+		"metadata": map[string]any{"name": name}, "rules": rules,
+	}}
+	if _, err := tenant.Resource(clusterRoleGVR).Create(ctxWithTimeout(t, 10*time.Second), role, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		t.Fatalf("create ClusterRole: %v", err)
+	}
+	crb := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "rbac.authorization.k8s.io/v1", "kind": "ClusterRoleBinding",
+		"metadata": map[string]any{"name": name},
+		"roleRef":  map[string]any{"apiGroup": "rbac.authorization.k8s.io", "kind": "ClusterRole", "name": name},
+		"subjects": []any{
+			map[string]any{"apiGroup": "rbac.authorization.k8s.io", "kind": "User", "name": qualified},
+			map[string]any{"apiGroup": "rbac.authorization.k8s.io", "kind": "User", "name": "system:serviceaccount:default:provider"},
+		},
+	}}
+	if _, err := tenant.Resource(clusterRoleBindingGVR).Create(ctxWithTimeout(t, 10*time.Second), crb, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		t.Fatalf("create ClusterRoleBinding: %v", err)
+	}
+}
+
+func waitForJoinToken(t *testing.T, tenant dynamic.Interface, edgeName string) string {
+	t.Helper()
+	var token string
+	if !waitFor(t, 60*time.Second, func() (bool, string) {
+		got, err := tenant.Resource(linuxServerGVR).Get(ctxWithTimeout(t, 5*time.Second), edgeName, metav1.GetOptions{})
+		if err != nil {
+			return false, err.Error()
+		}
+		token, _, _ = unstructured.NestedString(got.Object, "status", "joinToken")
+		return token != "", "joinToken empty"
+	}) {
+		t.Fatalf("join token for %s never issued", edgeName)
+	}
+	return token
+}
+
+// startAgent runs a server-mode agent for edgeName. An empty joinToken
+// restarts it on the kubeconfig it saved when it first joined. Logs go under
+// the suite's data dir so a failed soak keeps them.
+func startAgent(t *testing.T, edgeName, joinToken, tenantWS string) *exec.Cmd {
+	t.Helper()
+	logf, err := os.OpenFile(filepath.Join(dataDir, "agent-"+edgeName+".log"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("agent log: %v", err)
+	}
+	args := []string{
+		"agent", "run",
+		"--hub-url", hubURL,
+		"--hub-insecure-skip-tls-verify",
+		"--tunnel-url", hubURL,
+		"--edge-name", edgeName,
+		"--cluster", tenantWS,
+		"--type", "server",
+		"--ssh-proxy-port", strconv.Itoa(sshPort),
+	}
+	if joinToken != "" {
+		args = append(args, "--token", joinToken)
+	}
+	cmd := exec.Command(kedgeBin, args...)
+	cmd.Stdout = logf
+	cmd.Stderr = logf
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("start agent for %s: %v", edgeName, err)
+	}
+	return cmd
+}
+
+// --- CLI + kubeconfig helpers ---
+
+// runCLI runs a kedge/kubectl command with an isolated KUBECONFIG.
+func runCLI(t *testing.T, kubeconfig string, name string, args ...string) string {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = append(os.Environ(), "KUBECONFIG="+kubeconfig)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("%s %s failed: %v\n%s", filepath.Base(name), strings.Join(args, " "), err, string(out))
+	}
+	return string(out)
+}
+
+// clusterFromKubeconfig extracts the logical cluster name from the server URL
+// (https://.../clusters/<cluster>) of the kedge login context.
+func clusterFromKubeconfig(t *testing.T, kubeconfig string) string {
+	t.Helper()
+	b, err := os.ReadFile(kubeconfig)
+	if err != nil {
+		t.Fatalf("read kubeconfig: %v", err)
+	}
+	for _, line := range strings.Split(string(b), "\n") {
+		if i := strings.Index(line, "/clusters/"); i >= 0 {
+			rest := line[i+len("/clusters/"):]
+			for j, r := range rest {
+				if r == ' ' || r == '\n' || r == '/' || r == '"' {
+					return strings.TrimSpace(rest[:j])
+				}
+			}
+			return strings.TrimSpace(rest)
+		}
+	}
+	t.Fatalf("no /clusters/ in kubeconfig:\n%s", string(b))
+	return ""
+}
+
+func insecureClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}, //nolint:gosec // test-only
+	}
+}
+
+func waitFor(t *testing.T, timeout time.Duration, cond func() (bool, string)) bool {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	var last string
+	for time.Now().Before(deadline) {
+		if ok, msg := cond(); ok {
+			return true
+		} else {
+			last = msg
+		}
+		time.Sleep(2 * time.Second)
+	}
+	t.Logf("wait timeout after %s; last: %s", timeout, last)
+	return false
+}